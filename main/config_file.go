@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+// configFileVariable is the environment variable holding the path to an optional config file. The file is re-read for
+// every run, so that a long-running deployment picks up changes without restarting.
+const configFileVariable = "CLOUDCONCIERGE_CONFIGFILE"
+
+// loadJobConfig builds the JobConfig from, in increasing order of precedence, the environment, the config file and
+// the passed per-run overrides. Config file and override keys may omit the CLOUDCONCIERGE_ prefix.
+func loadJobConfig(overrides map[string]string) (JobConfig, error) {
+	values := map[string]string{}
+
+	if path := os.Getenv(configFileVariable); path != "" {
+		fileValues, err := readConfigFile(path)
+		if err != nil {
+			return JobConfig{}, err
+		}
+		for key, value := range fileValues {
+			values[configVariableName(key)] = value
+		}
+	}
+
+	for key, value := range overrides {
+		values[configVariableName(key)] = value
+	}
+
+	var jobConfig JobConfig
+	err := withEnvironment(values, func() error {
+		return envconfig.Process("CLOUDCONCIERGE", &jobConfig)
+	})
+	if err != nil {
+		return JobConfig{}, fmt.Errorf("[load_job_config]%w", err)
+	}
+
+	return jobConfig, nil
+}
+
+// readConfigFile parses a config file of KEY=VALUE lines, in the same format as the example environment files.
+// Blank lines and lines starting with # are ignored, and values may be wrapped in matching quotes.
+func readConfigFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("[read_config_file][os.Open]%w", err)
+	}
+	defer file.Close()
+
+	values := map[string]string{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			return nil, fmt.Errorf("[read_config_file][line %d is not of the form KEY=VALUE]", lineNumber)
+		}
+
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		values[strings.TrimSpace(key)] = value
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("[read_config_file][scanner]%w", err)
+	}
+	return values, nil
+}
+
+// configVariableName returns the environment variable name of a config key, adding the CLOUDCONCIERGE_ prefix
+// when omitted.
+func configVariableName(key string) string {
+	key = strings.ToUpper(key)
+	if strings.HasPrefix(key, "CLOUDCONCIERGE_") {
+		return key
+	}
+	return "CLOUDCONCIERGE_" + key
+}
+
+// withEnvironment sets the passed environment variables while running fn, restoring their previous values after.
+func withEnvironment(values map[string]string, fn func() error) error {
+	previous := map[string]*string{}
+	for key, value := range values {
+		if current, ok := os.LookupEnv(key); ok {
+			previous[key] = &current
+		} else {
+			previous[key] = nil
+		}
+
+		err := os.Setenv(key, value)
+		if err != nil {
+			return fmt.Errorf("[with_environment][error setting %v]%w", key, err)
+		}
+	}
+
+	defer func() {
+		for key, value := range previous {
+			if value == nil {
+				_ = os.Unsetenv(key)
+			} else {
+				_ = os.Setenv(key, *value)
+			}
+		}
+	}()
+
+	return fn()
+}
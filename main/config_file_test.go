@@ -0,0 +1,119 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadConfigFile(t *testing.T) {
+	// Given
+	path := filepath.Join(t.TempDir(), "cloud-concierge.env")
+	content := `# Comment
+CLOUDCONCIERGE_JOBNAME="My Job"
+
+RESOURCESBLACKLIST=["aws_lb"]
+CLOUDCONCIERGE_DIVISIONCLOUDCREDENTIALS=prod:{"ambient": "aws"}
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+	// When
+	values, err := readConfigFile(path)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"CLOUDCONCIERGE_JOBNAME":                  "My Job",
+		"RESOURCESBLACKLIST":                      `["aws_lb"]`,
+		"CLOUDCONCIERGE_DIVISIONCLOUDCREDENTIALS": `prod:{"ambient": "aws"}`,
+	}, values)
+}
+
+func TestReadConfigFile_InvalidLine(t *testing.T) {
+	// Given
+	path := filepath.Join(t.TempDir(), "cloud-concierge.env")
+	require.NoError(t, os.WriteFile(path, []byte("not a setting\n"), 0600))
+
+	// When
+	_, err := readConfigFile(path)
+
+	// Then
+	assert.Error(t, err)
+}
+
+func TestWithEnvironment(t *testing.T) {
+	// Given
+	t.Setenv("CLOUDCONCIERGE_JOBNAME", "original")
+
+	// When
+	var during string
+	err := withEnvironment(map[string]string{"CLOUDCONCIERGE_JOBNAME": "override", "CLOUDCONCIERGE_JOBID": "123"}, func() error {
+		during = os.Getenv("CLOUDCONCIERGE_JOBNAME")
+		return nil
+	})
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, "override", during)
+	assert.Equal(t, "original", os.Getenv("CLOUDCONCIERGE_JOBNAME"))
+	_, ok := os.LookupEnv("CLOUDCONCIERGE_JOBID")
+	assert.False(t, ok)
+}
+
+func TestConfigVariableName(t *testing.T) {
+	assert.Equal(t, "CLOUDCONCIERGE_JOBNAME", configVariableName("jobName"))
+	assert.Equal(t, "CLOUDCONCIERGE_JOBNAME", configVariableName("CLOUDCONCIERGE_JOBNAME"))
+}
+
+func TestLoadJobConfig(t *testing.T) {
+	// Given
+	path := filepath.Join(t.TempDir(), "cloud-concierge.env")
+	content := `CLOUDCONCIERGE_DIVISIONCLOUDCREDENTIALS=prod:{"ambient": "aws"}
+CLOUDCONCIERGE_INFRACOSTAPITOKEN=infracost-token
+CLOUDCONCIERGE_ORGTOKEN=org-token
+CLOUDCONCIERGE_TERRAFORMVERSION=1.5.0
+CLOUDCONCIERGE_STATEBACKEND=s3
+CLOUDCONCIERGE_WORKSPACEDIRECTORIES=/terraform/
+CLOUDCONCIERGE_PROVIDERS=aws:~>4.57.0
+CLOUDCONCIERGE_VCSBASEBRANCH=main
+CLOUDCONCIERGE_VCSTOKEN=vcs-token
+CLOUDCONCIERGE_VCSUSER=vcs-user
+CLOUDCONCIERGE_VCSREPO=https://github.com/my-org/my-repo
+CLOUDCONCIERGE_VCSSYSTEM=github
+CLOUDCONCIERGE_UNIQUERUNDIRECTORIES=true
+JOBNAME=From File
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	t.Setenv(configFileVariable, path)
+
+	// When
+	jobConfig, err := loadJobConfig(map[string]string{"jobName": "From Override"})
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, "From Override", jobConfig.JobName)
+	assert.Equal(t, "s3", jobConfig.StateBackend)
+	_, ok := os.LookupEnv("CLOUDCONCIERGE_STATEBACKEND")
+	assert.False(t, ok)
+
+	// When
+	jobConfig, err = loadJobConfig(nil)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, "From File", jobConfig.JobName)
+
+	// When
+	scheduledAt := time.Date(2023, time.March, 15, 6, 0, 0, 0, time.UTC)
+	jobConfig, err = loadJobConfig(scheduledRunOverrides("/data", scheduledAt, map[string]string{"jobName": "From Trigger", "workingDirectory": "/elsewhere"}))
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, "From Trigger", jobConfig.JobName)
+	assert.Equal(t, filepath.Join("/data", "runs", "20230315T060000"), jobConfig.WorkingDirectory)
+	assert.False(t, jobConfig.UniqueRunDirectories)
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/joberrors"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/schedule"
+)
+
+// scheduledRunLayout is the time layout of the names of scheduled run directories.
+const scheduledRunLayout = "20060102T150405"
+
+// runDaemon runs the job whenever the cron schedule of config fires, and whenever a run is triggered through the
+// trigger endpoint, until the process is interrupted or terminated, which also cancels a run in progress. Runs never
+// overlap. Each run works within its own directory, of which only the most recent are retained, and its failure is
+// logged rather than stopping the daemon.
+func runDaemon(env string, config JobConfig) error {
+	if config.TriggerListenAddress != "" && config.TriggerToken == "" {
+		return fmt.Errorf("[run_daemon][trigger token is required when listening for triggered runs]")
+	}
+
+	var cronSchedule *schedule.Schedule
+	if config.Schedule != "" {
+		parsed, err := schedule.Parse(config.Schedule)
+		if err != nil {
+			return fmt.Errorf("[run_daemon]%w", err)
+		}
+		cronSchedule = &parsed
+	}
+
+	location, err := time.LoadLocation(config.ScheduleTimeZone)
+	if err != nil {
+		return fmt.Errorf("[run_daemon][time.LoadLocation %v]%w", config.ScheduleTimeZone, err)
+	}
+
+	baseDirectory := config.WorkingDirectory
+	if baseDirectory == "" {
+		baseDirectory, err = os.Getwd()
+		if err != nil {
+			return fmt.Errorf("[run_daemon][os.Getwd]%w", err)
+		}
+	}
+	baseDirectory, err = filepath.Abs(baseDirectory)
+	if err != nil {
+		return fmt.Errorf("[run_daemon][filepath.Abs %v]%w", baseDirectory, err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	triggers := make(chan map[string]string, 1)
+	if config.TriggerListenAddress != "" {
+		closeServer := serveTriggers(config.TriggerListenAddress, config.TriggerToken, triggers, stop)
+		defer closeServer()
+	}
+
+	for {
+		// Without a schedule, the nil timer channel never fires, leaving only triggered runs.
+		var timer *time.Timer
+		var timerChannel <-chan time.Time
+		var next time.Time
+		if cronSchedule != nil {
+			next = cronSchedule.Next(time.Now().In(location))
+			if next.IsZero() {
+				return fmt.Errorf("[run_daemon][schedule %q never fires]", config.Schedule)
+			}
+			log.Infof("Next scheduled job run at %v", next)
+
+			timer = time.NewTimer(time.Until(next))
+			timerChannel = timer.C
+		}
+
+		var overrides map[string]string
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			log.Info("Stopping scheduled job runs")
+			return nil
+		case <-timerChannel:
+		case overrides = <-triggers:
+			next = time.Now()
+			log.Info("Running triggered job run")
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+
+		runScheduledJob(ctx, env, baseDirectory, next, overrides)
+
+		err = pruneScheduledRunDirectories(baseDirectory, config.ScheduleRetainedRuns)
+		if err != nil {
+			log.Warnf("Error pruning scheduled run directories: %s", err.Error())
+		}
+	}
+}
+
+// runScheduledJob runs a single scheduled or triggered job, with the passed per-run config overrides, within its own
+// run directory, writing its run summary there.
+func runScheduledJob(ctx context.Context, env string, baseDirectory string, scheduledAt time.Time, overrides map[string]string) {
+	startedAt := time.Now()
+	err := runJob(ctx, env, scheduledRunOverrides(baseDirectory, scheduledAt, overrides))
+	writeRunSummary(startedAt, err)
+	if err != nil {
+		log.Errorf("Scheduled job run failed with error code %s", joberrors.CodeOf(err))
+	} else {
+		log.Info("Done executing scheduled job run")
+	}
+
+	err = os.Chdir(baseDirectory)
+	if err != nil {
+		log.Errorf("Error returning to the working directory: %s", err.Error())
+	}
+}
+
+// scheduledRunOverrides returns the passed per-run config overrides, extended so that the job run scheduled at the
+// passed time works within its own run directory, whatever the passed overrides.
+func scheduledRunOverrides(baseDirectory string, scheduledAt time.Time, overrides map[string]string) map[string]string {
+	runOverrides := map[string]string{}
+	for key, value := range overrides {
+		runOverrides[configVariableName(key)] = value
+	}
+	runOverrides[configVariableName("WORKINGDIRECTORY")] = scheduledRunDirectory(baseDirectory, scheduledAt)
+	runOverrides[configVariableName("UNIQUERUNDIRECTORIES")] = "false"
+	return runOverrides
+}
+
+// scheduledRunDirectory returns the directory, runs/<scheduled time>/ within the base directory, in which the job run
+// scheduled at the passed time works.
+func scheduledRunDirectory(baseDirectory string, scheduledAt time.Time) string {
+	return filepath.Join(baseDirectory, "runs", scheduledAt.UTC().Format(scheduledRunLayout))
+}
+
+// pruneScheduledRunDirectories removes all but the retained most recent scheduled run directories within
+// runs/ of the base directory. Other directories within runs/, such as those named after a job id, are kept. All
+// run directories are kept when retained is not positive.
+func pruneScheduledRunDirectories(baseDirectory string, retained int) error {
+	if retained <= 0 {
+		return nil
+	}
+
+	runsDirectory := filepath.Join(baseDirectory, "runs")
+	entries, err := os.ReadDir(runsDirectory)
+	if err != nil {
+		return fmt.Errorf("[prune_scheduled_run_directories][os.ReadDir %v]%w", runsDirectory, err)
+	}
+
+	// The run directory layout sorts chronologically, as do the entries returned by os.ReadDir.
+	runDirectories := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if _, err := time.Parse(scheduledRunLayout, entry.Name()); entry.IsDir() && err == nil {
+			runDirectories = append(runDirectories, entry.Name())
+		}
+	}
+
+	for i := 0; i < len(runDirectories)-retained; i++ {
+		err = os.RemoveAll(filepath.Join(runsDirectory, runDirectories[i]))
+		if err != nil {
+			return fmt.Errorf("[prune_scheduled_run_directories][os.RemoveAll %v]%w", runDirectories[i], err)
+		}
+	}
+	return nil
+}
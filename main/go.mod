@@ -9,6 +9,7 @@ require (
 	github.com/aws/aws-sdk-go v1.44.290
 	github.com/go-git/go-git/v5 v5.4.2
 	github.com/google/go-github/v45 v45.1.0
+	github.com/hashicorp/go-version v1.6.0
 	github.com/hashicorp/hcl/v2 v2.13.0
 	github.com/kelseyhightower/envconfig v1.4.0
 	github.com/sirupsen/logrus v1.9.0
@@ -16,6 +17,7 @@ require (
 	github.com/zclconf/go-cty v1.10.0
 	golang.org/x/oauth2 v0.6.0
 	google.golang.org/api v0.114.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -62,5 +64,4 @@ require (
 	google.golang.org/grpc v1.53.0 // indirect
 	google.golang.org/protobuf v1.29.1 // indirect
 	gopkg.in/warnings.v0 v0.1.2 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,177 @@
+package artifacts
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Store holds the artifacts that pipeline stages pass to one another, such as the json mappings within mappings/,
+// keyed by their path relative to the working directory. Only artifacts produced by the job itself are held; the
+// outputs of external tools, such as terraformer's current_cloud/ and the cloned repo/, remain on disk, so the
+// working directory must be writable. A Store is created for each job run and passed to each pipeline stage.
+type Store interface {
+	// ReadFile returns the content of the named artifact, falling back to disk for artifacts produced by external
+	// tools. An error wrapping os.ErrNotExist is returned if the artifact does not exist.
+	ReadFile(name string) ([]byte, error)
+
+	// WriteFile stores the content of the named artifact, replacing any previous content.
+	WriteFile(name string, content []byte, perm os.FileMode) error
+
+	// Remove removes the named artifact.
+	Remove(name string) error
+
+	// Exists returns true if the named artifact exists.
+	Exists(name string) bool
+
+	// Flush writes every artifact held only in memory to disk, for external tools such as the python scripts.
+	Flush() error
+}
+
+// artifact is the content of an artifact and the file mode with which it is written to disk.
+type artifact struct {
+	content []byte
+	perm    os.FileMode
+	onDisk  bool
+}
+
+// MemoryStore is a Store holding artifacts in memory, which optionally spills each artifact to disk as it is written.
+type MemoryStore struct {
+	mu        sync.RWMutex
+	artifacts map[string]artifact
+	spill     bool
+}
+
+// NewMemoryStore creates a new MemoryStore. If spill is true, each artifact is also written to disk as it is written
+// to the store.
+func NewMemoryStore(spill bool) *MemoryStore {
+	return &MemoryStore{
+		artifacts: map[string]artifact{},
+		spill:     spill,
+	}
+}
+
+// ReadFile returns the content of the named artifact, falling back to disk for artifacts produced by external tools.
+func (s *MemoryStore) ReadFile(name string) ([]byte, error) {
+	path, err := filepath.Abs(name)
+	if err != nil {
+		return nil, fmt.Errorf("[read_file][filepath.Abs %v]%w", name, err)
+	}
+
+	s.mu.RLock()
+	stored, ok := s.artifacts[path]
+	s.mu.RUnlock()
+	if ok {
+		return append([]byte{}, stored.content...), nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("[read_file][os.ReadFile %v]%w", name, err)
+	}
+	return content, nil
+}
+
+// WriteFile stores the content of the named artifact, writing it to disk as well if the store spills.
+func (s *MemoryStore) WriteFile(name string, content []byte, perm os.FileMode) error {
+	path, err := filepath.Abs(name)
+	if err != nil {
+		return fmt.Errorf("[write_file][filepath.Abs %v]%w", name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := artifact{content: append([]byte{}, content...), perm: perm}
+	if s.spill {
+		err = writeToDisk(path, stored)
+		if err != nil {
+			return fmt.Errorf("[write_file]%w", err)
+		}
+		stored.onDisk = true
+	}
+	s.artifacts[path] = stored
+	return nil
+}
+
+// Remove removes the named artifact from memory and disk.
+func (s *MemoryStore) Remove(name string) error {
+	path, err := filepath.Abs(name)
+	if err != nil {
+		return fmt.Errorf("[remove][filepath.Abs %v]%w", name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, inMemory := s.artifacts[path]
+	delete(s.artifacts, path)
+
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) && inMemory {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("[remove][os.Remove %v]%w", name, err)
+	}
+	return nil
+}
+
+// Exists returns true if the named artifact exists in memory or on disk.
+func (s *MemoryStore) Exists(name string) bool {
+	path, err := filepath.Abs(name)
+	if err != nil {
+		return false
+	}
+
+	s.mu.RLock()
+	_, ok := s.artifacts[path]
+	s.mu.RUnlock()
+	if ok {
+		return true
+	}
+
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// Flush writes every artifact held only in memory to disk.
+func (s *MemoryStore) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for path, stored := range s.artifacts {
+		if stored.onDisk {
+			continue
+		}
+
+		err := writeToDisk(path, stored)
+		if err != nil {
+			return fmt.Errorf("[flush]%w", err)
+		}
+		stored.onDisk = true
+		s.artifacts[path] = stored
+	}
+	return nil
+}
+
+// writeToDisk writes an artifact to path, replacing any previous file, which may be read-only.
+func writeToDisk(path string, stored artifact) error {
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return fmt.Errorf("[write_to_disk][os.MkdirAll %v]%w", filepath.Dir(path), err)
+	}
+
+	err = os.Remove(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("[write_to_disk][os.Remove %v]%w", path, err)
+	}
+
+	err = os.WriteFile(path, stored.content, stored.perm)
+	if err != nil {
+		return fmt.Errorf("[write_to_disk][os.WriteFile %v]%w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package artifacts
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore_WithoutSpill(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	store := NewMemoryStore(false)
+
+	// When
+	err := store.WriteFile("mappings/new-resources-to-workspace.json", []byte(`{"a": "b"}`), 0400)
+
+	// Then
+	require.NoError(t, err)
+	content, err := store.ReadFile("mappings/new-resources-to-workspace.json")
+	require.NoError(t, err)
+	assert.Equal(t, `{"a": "b"}`, string(content))
+	assert.True(t, store.Exists("mappings/new-resources-to-workspace.json"))
+
+	_, err = os.Stat("mappings/new-resources-to-workspace.json")
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+
+	require.NoError(t, store.Flush())
+	content, err = os.ReadFile("mappings/new-resources-to-workspace.json")
+	require.NoError(t, err)
+	assert.Equal(t, `{"a": "b"}`, string(content))
+}
+
+func TestMemoryStore_WithSpill(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	store := NewMemoryStore(true)
+	require.NoError(t, store.WriteFile("mappings/new-resources-to-workspace.json", []byte(`{}`), 0400))
+
+	// When
+	err := store.WriteFile("mappings/new-resources-to-workspace.json", []byte(`{"a": "b"}`), 0400)
+
+	// Then
+	require.NoError(t, err)
+	content, err := os.ReadFile("mappings/new-resources-to-workspace.json")
+	require.NoError(t, err)
+	assert.Equal(t, `{"a": "b"}`, string(content))
+
+	require.NoError(t, store.Remove("mappings/new-resources-to-workspace.json"))
+	assert.False(t, store.Exists("mappings/new-resources-to-workspace.json"))
+	_, err = os.Stat("mappings/new-resources-to-workspace.json")
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestMemoryStore_ReadFile_FallsBackToDisk(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	require.NoError(t, os.MkdirAll("mappings", 0700))
+	require.NoError(t, os.WriteFile("mappings/provider-schemas.json", []byte(`{}`), 0600))
+	store := NewMemoryStore(false)
+
+	// When
+	content, err := store.ReadFile("mappings/provider-schemas.json")
+	_, missingErr := store.ReadFile("mappings/missing.json")
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, `{}`, string(content))
+	assert.True(t, errors.Is(missingErr, os.ErrNotExist))
+	assert.Error(t, store.Remove("mappings/missing.json"))
+}
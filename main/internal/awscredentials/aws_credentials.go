@@ -0,0 +1,242 @@
+package awscredentials
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/defaults"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+// ErrMissingBaseCredentials is returned when a role is to be assumed without static keys, a web identity token or
+// ambient credentials.
+var ErrMissingBaseCredentials = errors.New("[assuming a role requires static access keys, a web identity token file or ambient credentials]")
+
+// defaultRoleSessionName is the role session name used when the credential does not specify one.
+const defaultRoleSessionName = "cloud-concierge"
+
+// defaultSTSRegion is the region of the STS endpoint used to assume roles.
+const defaultSTSRegion = "us-east-1"
+
+// Credential is the json structure of an AWS division credential. Static access keys may be used directly, or
+// with an optional session token. When RoleARN is set, the role is assumed either with the static access keys
+// (optionally passing ExternalID), with the web identity token within WebIdentityTokenFile, or with the ambient
+// credentials of the container. When SourceRoleARN is also set, that role is assumed first in the same way, and
+// RoleARN is then assumed with its session, e.g. a member account role assumed from an organization management role.
+type Credential struct {
+	AWSAccessKeyID       string `json:"awsAccessKeyID"`
+	AWSSecretAccessKey   string `json:"awsSecretAccessKey"`
+	Token                string `json:"token"`
+	RoleARN              string `json:"roleARN"`
+	ExternalID           string `json:"externalID"`
+	RoleSessionName      string `json:"roleSessionName"`
+	WebIdentityTokenFile string `json:"webIdentityTokenFile"`
+	SourceRoleARN        string `json:"sourceRoleARN"`
+	SourceExternalID     string `json:"sourceExternalID"`
+
+	// ambient flags that the credentials available within the container's environment are used as the base credentials.
+	ambient bool
+}
+
+// newSTSClient returns the client used to assume roles with the passed base credentials.
+var newSTSClient = func(baseCredentials *credentials.Credentials) (stscreds.AssumeRoler, error) {
+	newSession, err := session.NewSession(aws.NewConfig().WithRegion(defaultSTSRegion).WithCredentials(baseCredentials))
+	if err != nil {
+		return nil, err
+	}
+	return sts.New(newSession), nil
+}
+
+// Parse unmarshals an AWS division credential.
+func Parse(credential terraformValueObjects.Credential) (Credential, error) {
+	awsCredential := Credential{}
+	err := json.Unmarshal([]byte(credential), &awsCredential)
+	if err != nil {
+		return Credential{}, fmt.Errorf("[awscredentials][json.Unmarshal]%w", err)
+	}
+	awsCredential.ambient = credential.IsAmbient()
+	return awsCredential, nil
+}
+
+// IsAWSCredential returns true if the passed credential fields describe either static AWS access keys or a role
+// to assume.
+func IsAWSCredential(credentialMapped map[string]string) bool {
+	hasStaticKeys := strings.Trim(credentialMapped["awsAccessKeyID"], " ") != "" &&
+		strings.Trim(credentialMapped["awsSecretAccessKey"], " ") != ""
+
+	return hasStaticKeys || strings.Trim(credentialMapped["roleARN"], " ") != ""
+}
+
+// Resolve returns the credentials to use for the division, assuming the configured roles if any. Roles are assumed
+// anew on each call, so that the returned session is fresh whenever the credential is used.
+func (c Credential) Resolve() (credentials.Value, error) {
+	if c.SourceRoleARN != "" {
+		return c.resolveChained()
+	}
+
+	if c.RoleARN == "" && c.ambient {
+		value, err := ambientCredentials().Get()
+		if err != nil {
+			return credentials.Value{}, fmt.Errorf("[awscredentials][error retrieving ambient credentials]%w", err)
+		}
+		return value, nil
+	}
+
+	if c.RoleARN == "" {
+		return credentials.Value{
+			AccessKeyID:     c.AWSAccessKeyID,
+			SecretAccessKey: c.AWSSecretAccessKey,
+			SessionToken:    c.Token,
+		}, nil
+	}
+
+	roleSessionName := c.RoleSessionName
+	if roleSessionName == "" {
+		roleSessionName = defaultRoleSessionName
+	}
+
+	tokenFile := c.WebIdentityTokenFile
+	if tokenFile == "" && !c.ambient {
+		tokenFile = os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE")
+	}
+
+	var roleCredentials *credentials.Credentials
+	switch {
+	case c.AWSAccessKeyID != "" && c.AWSSecretAccessKey != "" || c.ambient && tokenFile == "":
+		baseCredentials := credentials.NewStaticCredentials(c.AWSAccessKeyID, c.AWSSecretAccessKey, c.Token)
+		if c.AWSAccessKeyID == "" {
+			baseCredentials = ambientCredentials()
+		}
+
+		stsClient, err := newSTSClient(baseCredentials)
+		if err != nil {
+			return credentials.Value{}, fmt.Errorf("[awscredentials][error creating sts client]%w", err)
+		}
+
+		roleCredentials = stscreds.NewCredentialsWithClient(stsClient, c.RoleARN, func(p *stscreds.AssumeRoleProvider) {
+			p.RoleSessionName = roleSessionName
+			if c.ExternalID != "" {
+				p.ExternalID = aws.String(c.ExternalID)
+			}
+		})
+	case tokenFile != "":
+		newSession, err := session.NewSession(aws.NewConfig().WithRegion(defaultSTSRegion).WithCredentials(credentials.AnonymousCredentials))
+		if err != nil {
+			return credentials.Value{}, fmt.Errorf("[awscredentials][error creating sts session]%w", err)
+		}
+		roleCredentials = stscreds.NewWebIdentityCredentials(newSession, c.RoleARN, roleSessionName, tokenFile)
+	default:
+		return credentials.Value{}, fmt.Errorf("[awscredentials][role %v]%w", c.RoleARN, ErrMissingBaseCredentials)
+	}
+
+	value, err := roleCredentials.Get()
+	if err != nil {
+		return credentials.Value{}, fmt.Errorf("[awscredentials][error assuming role %v]%w", c.RoleARN, err)
+	}
+
+	return value, nil
+}
+
+// resolveChained assumes the source role with the base credentials, and then the role with the source role's session.
+func (c Credential) resolveChained() (credentials.Value, error) {
+	source := c
+	source.RoleARN = c.SourceRoleARN
+	source.ExternalID = c.SourceExternalID
+	source.SourceRoleARN = ""
+	source.SourceExternalID = ""
+
+	value, err := source.Resolve()
+	if err != nil {
+		return credentials.Value{}, err
+	}
+
+	chained := Credential{
+		AWSAccessKeyID:     value.AccessKeyID,
+		AWSSecretAccessKey: value.SecretAccessKey,
+		Token:              value.SessionToken,
+		RoleARN:            c.RoleARN,
+		ExternalID:         c.ExternalID,
+		RoleSessionName:    c.RoleSessionName,
+	}
+	return chained.Resolve()
+}
+
+// SetEnvironment resolves an AWS division credential and exports it as the standard AWS environment variables, for
+// use by terraformer and the AWS CLI.
+func SetEnvironment(credential terraformValueObjects.Credential) error {
+	awsCredential, err := Parse(credential)
+	if err != nil {
+		return err
+	}
+
+	if awsCredential.ambient && awsCredential.RoleARN == "" {
+		return unsetEnvironment()
+	}
+
+	value, err := awsCredential.Resolve()
+	if err != nil {
+		return err
+	}
+
+	err = os.Setenv("AWS_ACCESS_KEY_ID", value.AccessKeyID)
+	if err != nil {
+		return fmt.Errorf("[awscredentials][error setting AWS_ACCESS_KEY_ID]%w", err)
+	}
+
+	err = os.Setenv("AWS_SECRET_ACCESS_KEY", value.SecretAccessKey)
+	if err != nil {
+		return fmt.Errorf("[awscredentials][error setting AWS_SECRET_ACCESS_KEY]%w", err)
+	}
+
+	if value.SessionToken == "" {
+		err = os.Unsetenv("AWS_SESSION_TOKEN")
+	} else {
+		err = os.Setenv("AWS_SESSION_TOKEN", value.SessionToken)
+	}
+	if err != nil {
+		return fmt.Errorf("[awscredentials][error setting AWS_SESSION_TOKEN]%w", err)
+	}
+
+	return nil
+}
+
+// unsetEnvironment removes static AWS credentials from the environment, so that terraformer and the AWS CLI fall back
+// to the default credential chain, e.g. an instance profile or an EKS service account role.
+func unsetEnvironment() error {
+	for _, variable := range []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN"} {
+		err := os.Unsetenv(variable)
+		if err != nil {
+			return fmt.Errorf("[awscredentials][error unsetting %v]%w", variable, err)
+		}
+	}
+	return nil
+}
+
+// ambientCredentials returns the credentials available within the container's environment, ignoring static
+// credentials within environment variables which may belong to another division. Checked in order are a web identity
+// token (e.g. an EKS service account role), the shared credentials file, and the ECS task or EC2 instance role.
+func ambientCredentials() *credentials.Credentials {
+	providers := make([]credentials.Provider, 0)
+
+	roleARN := os.Getenv("AWS_ROLE_ARN")
+	tokenFile := os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE")
+	if roleARN != "" && tokenFile != "" {
+		newSession, err := session.NewSession(aws.NewConfig().WithRegion(defaultSTSRegion).WithCredentials(credentials.AnonymousCredentials))
+		if err == nil {
+			providers = append(providers, stscreds.NewWebIdentityRoleProvider(sts.New(newSession), roleARN, defaultRoleSessionName, tokenFile))
+		}
+	}
+
+	providers = append(providers, &credentials.SharedCredentialsProvider{}, defaults.RemoteCredProvider(*defaults.Config(), defaults.Handlers()))
+
+	return credentials.NewChainCredentials(providers)
+}
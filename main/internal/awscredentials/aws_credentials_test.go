@@ -0,0 +1,144 @@
+package awscredentials
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAssumeRoler struct {
+	input *sts.AssumeRoleInput
+}
+
+func (m *mockAssumeRoler) AssumeRole(input *sts.AssumeRoleInput) (*sts.AssumeRoleOutput, error) {
+	m.input = input
+	return &sts.AssumeRoleOutput{Credentials: &sts.Credentials{
+		AccessKeyId:     aws.String("temporary-key"),
+		SecretAccessKey: aws.String("temporary-secret"),
+		SessionToken:    aws.String("temporary-token"),
+		Expiration:      aws.Time(time.Now().Add(time.Hour)),
+	}}, nil
+}
+
+func TestIsAWSCredential(t *testing.T) {
+	assert.True(t, IsAWSCredential(map[string]string{"awsAccessKeyID": "key", "awsSecretAccessKey": "secret"}))
+	assert.True(t, IsAWSCredential(map[string]string{"roleARN": "arn:aws:iam::123456789012:role/scanner"}))
+	assert.False(t, IsAWSCredential(map[string]string{"awsAccessKeyID": "key"}))
+}
+
+func TestSetEnvironment_SessionToken(t *testing.T) {
+	// Given
+	for _, variable := range []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN"} {
+		t.Setenv(variable, "")
+	}
+
+	// When
+	err := SetEnvironment(`{"awsAccessKeyID": "key", "awsSecretAccessKey": "secret", "token": "session"}`)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, "key", os.Getenv("AWS_ACCESS_KEY_ID"))
+	assert.Equal(t, "secret", os.Getenv("AWS_SECRET_ACCESS_KEY"))
+	assert.Equal(t, "session", os.Getenv("AWS_SESSION_TOKEN"))
+
+	// When
+	err = SetEnvironment(`{"awsAccessKeyID": "key", "awsSecretAccessKey": "secret"}`)
+
+	// Then
+	require.NoError(t, err)
+	_, ok := os.LookupEnv("AWS_SESSION_TOKEN")
+	assert.False(t, ok)
+}
+
+func TestCredential_Resolve_AssumeRole(t *testing.T) {
+	// Given
+	mock := &mockAssumeRoler{}
+	originalNewSTSClient := newSTSClient
+	newSTSClient = func(baseCredentials *credentials.Credentials) (stscreds.AssumeRoler, error) {
+		return mock, nil
+	}
+	defer func() { newSTSClient = originalNewSTSClient }()
+
+	credential := Credential{
+		AWSAccessKeyID:     "key",
+		AWSSecretAccessKey: "secret",
+		RoleARN:            "arn:aws:iam::123456789012:role/scanner",
+		ExternalID:         "external",
+	}
+
+	// When
+	value, err := credential.Resolve()
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, "temporary-key", value.AccessKeyID)
+	assert.Equal(t, "temporary-token", value.SessionToken)
+	assert.Equal(t, "arn:aws:iam::123456789012:role/scanner", *mock.input.RoleArn)
+	assert.Equal(t, "external", *mock.input.ExternalId)
+	assert.Equal(t, defaultRoleSessionName, *mock.input.RoleSessionName)
+}
+
+func TestCredential_Resolve_MissingBaseCredentials(t *testing.T) {
+	// Given
+	t.Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", "")
+	credential := Credential{RoleARN: "arn:aws:iam::123456789012:role/scanner"}
+
+	// When
+	_, err := credential.Resolve()
+
+	// Then
+	assert.True(t, errors.Is(err, ErrMissingBaseCredentials))
+}
+
+func TestSetEnvironment_Ambient(t *testing.T) {
+	// Given
+	t.Setenv("AWS_ACCESS_KEY_ID", "previous-division-key")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "previous-division-secret")
+	t.Setenv("AWS_SESSION_TOKEN", "previous-division-token")
+
+	// When
+	err := SetEnvironment(`{"ambient": "aws"}`)
+
+	// Then
+	require.NoError(t, err)
+	for _, variable := range []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN"} {
+		_, ok := os.LookupEnv(variable)
+		assert.False(t, ok)
+	}
+}
+
+func TestCredential_Resolve_SourceRole(t *testing.T) {
+	// Given
+	baseAccessKeyIDs := make([]string, 0)
+	originalNewSTSClient := newSTSClient
+	newSTSClient = func(baseCredentials *credentials.Credentials) (stscreds.AssumeRoler, error) {
+		value, err := baseCredentials.Get()
+		require.NoError(t, err)
+		baseAccessKeyIDs = append(baseAccessKeyIDs, value.AccessKeyID)
+		return &mockAssumeRoler{}, nil
+	}
+	defer func() { newSTSClient = originalNewSTSClient }()
+
+	credential := Credential{
+		AWSAccessKeyID:     "key",
+		AWSSecretAccessKey: "secret",
+		RoleARN:            "arn:aws:iam::222222222222:role/scanner",
+		SourceRoleARN:      "arn:aws:iam::111111111111:role/management",
+	}
+
+	// When
+	value, err := credential.Resolve()
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, "temporary-key", value.AccessKeyID)
+	assert.Equal(t, []string{"key", "temporary-key"}, baseAccessKeyIDs)
+}
@@ -0,0 +1,107 @@
+package awscredentials
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+// newOrganizationsClient returns the Organizations API client used with the management account's credentials.
+var newOrganizationsClient = func(value credentials.Value) (organizationsiface.OrganizationsAPI, error) {
+	newSession, err := session.NewSession(aws.NewConfig().WithRegion(defaultSTSRegion).WithCredentials(credentials.NewStaticCredentialsFromCreds(value)))
+	if err != nil {
+		return nil, err
+	}
+	return organizations.New(newSession), nil
+}
+
+// DiscoverOrganizationDivisions enumerates the active member accounts of the AWS organization that the management
+// credential belongs to, and returns a division per account, named by account id, whose credential assumes
+// memberRoleName within that account. The management account itself and excluded account ids are skipped.
+func DiscoverOrganizationDivisions(management terraformValueObjects.Credential, memberRoleName string, excludedAccounts []string) (map[terraformValueObjects.Division]terraformValueObjects.Credential, error) {
+	managementCredential, err := Parse(management)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := managementCredential.Resolve()
+	if err != nil {
+		return nil, fmt.Errorf("[awscredentials][discover_organization_divisions]%w", err)
+	}
+
+	client, err := newOrganizationsClient(value)
+	if err != nil {
+		return nil, fmt.Errorf("[awscredentials][discover_organization_divisions][error creating organizations client]%w", err)
+	}
+
+	organization, err := client.DescribeOrganization(&organizations.DescribeOrganizationInput{})
+	if err != nil {
+		return nil, fmt.Errorf("[awscredentials][discover_organization_divisions][error describing organization]%w", err)
+	}
+
+	excluded := map[string]bool{aws.StringValue(organization.Organization.MasterAccountId): true}
+	for _, accountID := range excludedAccounts {
+		excluded[accountID] = true
+	}
+
+	divisions := map[terraformValueObjects.Division]terraformValueObjects.Credential{}
+	var credentialErr error
+	err = client.ListAccountsPages(&organizations.ListAccountsInput{}, func(output *organizations.ListAccountsOutput, lastPage bool) bool {
+		for _, account := range output.Accounts {
+			accountID := aws.StringValue(account.Id)
+			if excluded[accountID] || aws.StringValue(account.Status) != organizations.AccountStatusActive {
+				continue
+			}
+
+			roleARN := fmt.Sprintf("arn:aws:iam::%v:role/%v", accountID, memberRoleName)
+			credential, err := memberCredential(managementCredential, roleARN)
+			if err != nil {
+				credentialErr = err
+				return false
+			}
+			divisions[terraformValueObjects.Division(accountID)] = credential
+		}
+		return true
+	})
+	if credentialErr != nil {
+		return nil, credentialErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[awscredentials][discover_organization_divisions][error listing accounts]%w", err)
+	}
+
+	return divisions, nil
+}
+
+// memberCredential returns the division credential assuming roleARN within a member account with the management
+// credential's base credentials. When the management credential itself assumes a role, that role is kept as the
+// source of a role chain, assumed anew whenever the member credential is used, rather than embedding its temporary
+// session, which could expire before the member account is scanned.
+func memberCredential(management Credential, roleARN string) (terraformValueObjects.Credential, error) {
+	member := map[string]string{
+		"roleARN":              roleARN,
+		"roleSessionName":      management.RoleSessionName,
+		"awsAccessKeyID":       management.AWSAccessKeyID,
+		"awsSecretAccessKey":   management.AWSSecretAccessKey,
+		"token":                management.Token,
+		"webIdentityTokenFile": management.WebIdentityTokenFile,
+		"sourceRoleARN":        management.RoleARN,
+		"sourceExternalID":     management.ExternalID,
+	}
+	if management.ambient {
+		member[terraformValueObjects.AmbientCredentialField] = "aws"
+	}
+
+	credential, err := json.Marshal(member)
+	if err != nil {
+		return "", fmt.Errorf("[awscredentials][member_credential][json.Marshal]%w", err)
+	}
+	return terraformValueObjects.Credential(credential), nil
+}
@@ -0,0 +1,111 @@
+package awscredentials
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+type mockOrganizationsClient struct {
+	organizationsiface.OrganizationsAPI
+}
+
+func (m *mockOrganizationsClient) DescribeOrganization(*organizations.DescribeOrganizationInput) (*organizations.DescribeOrganizationOutput, error) {
+	return &organizations.DescribeOrganizationOutput{
+		Organization: &organizations.Organization{MasterAccountId: aws.String("111111111111")},
+	}, nil
+}
+
+func (m *mockOrganizationsClient) ListAccountsPages(_ *organizations.ListAccountsInput, fn func(*organizations.ListAccountsOutput, bool) bool) error {
+	pages := []*organizations.ListAccountsOutput{
+		{Accounts: []*organizations.Account{
+			{Id: aws.String("111111111111"), Status: aws.String(organizations.AccountStatusActive)},
+			{Id: aws.String("222222222222"), Status: aws.String(organizations.AccountStatusActive)},
+		}},
+		{Accounts: []*organizations.Account{
+			{Id: aws.String("333333333333"), Status: aws.String(organizations.AccountStatusSuspended)},
+			{Id: aws.String("444444444444"), Status: aws.String(organizations.AccountStatusActive)},
+			{Id: aws.String("555555555555"), Status: aws.String(organizations.AccountStatusActive)},
+		}},
+	}
+	for i, page := range pages {
+		if !fn(page, i == len(pages)-1) {
+			break
+		}
+	}
+	return nil
+}
+
+func TestDiscoverOrganizationDivisions(t *testing.T) {
+	// Given
+	originalNewOrganizationsClient := newOrganizationsClient
+	newOrganizationsClient = func(value credentials.Value) (organizationsiface.OrganizationsAPI, error) {
+		assert.Equal(t, "key", value.AccessKeyID)
+		return &mockOrganizationsClient{}, nil
+	}
+	defer func() { newOrganizationsClient = originalNewOrganizationsClient }()
+
+	management := terraformValueObjects.Credential(`{"awsAccessKeyID": "key", "awsSecretAccessKey": "secret"}`)
+
+	// When
+	divisions, err := DiscoverOrganizationDivisions(management, "OrganizationAccountAccessRole", []string{"555555555555"})
+
+	// Then
+	require.NoError(t, err)
+	assert.Len(t, divisions, 2)
+
+	member, err := Parse(divisions["222222222222"])
+	require.NoError(t, err)
+	assert.Equal(t, "arn:aws:iam::222222222222:role/OrganizationAccountAccessRole", member.RoleARN)
+	assert.Equal(t, "key", member.AWSAccessKeyID)
+	assert.Equal(t, "secret", member.AWSSecretAccessKey)
+
+	_, ok := divisions["444444444444"]
+	assert.True(t, ok)
+}
+
+func TestMemberCredential_Ambient(t *testing.T) {
+	// Given
+	management, err := Parse(`{"ambient": "aws"}`)
+	require.NoError(t, err)
+
+	// When
+	credential, err := memberCredential(management, "arn:aws:iam::222222222222:role/scanner")
+
+	// Then
+	require.NoError(t, err)
+	assert.True(t, credential.IsAmbient())
+
+	member, err := Parse(credential)
+	require.NoError(t, err)
+	assert.Equal(t, "arn:aws:iam::222222222222:role/scanner", member.RoleARN)
+	assert.Empty(t, member.AWSAccessKeyID)
+}
+
+func TestMemberCredential_ManagementRole(t *testing.T) {
+	// Given
+	management, err := Parse(`{"awsAccessKeyID": "key", "awsSecretAccessKey": "secret", "roleARN": "arn:aws:iam::111111111111:role/management", "externalID": "external"}`)
+	require.NoError(t, err)
+
+	// When
+	credential, err := memberCredential(management, "arn:aws:iam::222222222222:role/scanner")
+
+	// Then
+	require.NoError(t, err)
+	member, err := Parse(credential)
+	require.NoError(t, err)
+	assert.Equal(t, "key", member.AWSAccessKeyID)
+	assert.Empty(t, member.Token)
+	assert.Equal(t, "arn:aws:iam::111111111111:role/management", member.SourceRoleARN)
+	assert.Equal(t, "external", member.SourceExternalID)
+	assert.Equal(t, "arn:aws:iam::222222222222:role/scanner", member.RoleARN)
+	assert.Empty(t, member.ExternalID)
+	assert.False(t, credential.IsAmbient())
+}
@@ -0,0 +1,239 @@
+package azuremanagementgroups
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+// managementEndpoint is the Azure Resource Manager endpoint.
+var managementEndpoint = "https://management.azure.com"
+
+// loginEndpoint is the Microsoft identity platform endpoint from which service principal tokens are requested.
+var loginEndpoint = "https://login.microsoftonline.com"
+
+// managedIdentityEndpoint is the instance metadata service endpoint from which managed identity tokens are requested.
+var managedIdentityEndpoint = "http://169.254.169.254/metadata/identity/oauth2/token"
+
+// credential is the json structure of an Azure division credential.
+type credential struct {
+	ClientID       string `json:"client_id"`
+	ClientSecret   string `json:"client_secret"`
+	TenantID       string `json:"tenant_id"`
+	SubscriptionID string `json:"subscription_id"`
+}
+
+// listResponse is a page of an Azure Resource Manager list response.
+type listResponse struct {
+	Value []struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	} `json:"value"`
+	NextLink string `json:"nextLink"`
+}
+
+// ResourceGroupDivision returns the division of a resource group discovered beneath a management group, keyed by
+// both its subscription and its name, e.g. 00000000-0000-0000-0000-000000000000_networking, as resource group names
+// are only unique within a subscription.
+func ResourceGroupDivision(subscriptionID string, resourceGroup string) terraformValueObjects.Division {
+	return terraformValueObjects.Division(fmt.Sprintf("%v_%v", subscriptionID, resourceGroup))
+}
+
+// ResourceGroupName returns the name of the resource group of an Azure division accessed within subscriptionID,
+// which is the division itself unless the division was discovered beneath a management group.
+func ResourceGroupName(division terraformValueObjects.Division, subscriptionID string) string {
+	return strings.TrimPrefix(string(division), subscriptionID+"_")
+}
+
+// DiscoverResourceGroupDivisions enumerates every subscription beneath the management group, and every resource
+// group within those subscriptions. Each resource group is returned as a division, named by ResourceGroupDivision,
+// accessed with the passed service principal or managed identity credential scoped to its subscription. Excluded
+// subscription ids are skipped.
+func DiscoverResourceGroupDivisions(ctx context.Context, managementCredential terraformValueObjects.Credential, managementGroupID string, excludedSubscriptions []string) (map[terraformValueObjects.Division]terraformValueObjects.Credential, error) {
+	azureCredential := credential{}
+	err := json.Unmarshal([]byte(managementCredential), &azureCredential)
+	if err != nil {
+		return nil, fmt.Errorf("[azuremanagementgroups][json.Unmarshal]%w", err)
+	}
+
+	token, err := getAccessToken(ctx, azureCredential, managementCredential.IsAmbient())
+	if err != nil {
+		return nil, fmt.Errorf("[azuremanagementgroups][error getting access token]%w", err)
+	}
+
+	subscriptionIDs, err := listSubscriptions(ctx, token, managementGroupID)
+	if err != nil {
+		return nil, fmt.Errorf("[azuremanagementgroups][error listing subscriptions of %v]%w", managementGroupID, err)
+	}
+
+	excluded := map[string]bool{}
+	for _, subscriptionID := range excludedSubscriptions {
+		excluded[subscriptionID] = true
+	}
+
+	divisions := map[terraformValueObjects.Division]terraformValueObjects.Credential{}
+	for _, subscriptionID := range subscriptionIDs {
+		if excluded[subscriptionID] {
+			continue
+		}
+
+		resourceGroups, err := listResourceGroups(ctx, token, subscriptionID)
+		if err != nil {
+			return nil, fmt.Errorf("[azuremanagementgroups][error listing resource groups of %v]%w", subscriptionID, err)
+		}
+
+		subscriptionCredential, err := withSubscription(managementCredential, subscriptionID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, resourceGroup := range resourceGroups {
+			divisions[ResourceGroupDivision(subscriptionID, resourceGroup)] = subscriptionCredential
+		}
+	}
+
+	return divisions, nil
+}
+
+// withSubscription returns the credential with its subscription_id replaced, keeping all other fields.
+func withSubscription(managementCredential terraformValueObjects.Credential, subscriptionID string) (terraformValueObjects.Credential, error) {
+	fields := map[string]interface{}{}
+	err := json.Unmarshal([]byte(managementCredential), &fields)
+	if err != nil {
+		return "", fmt.Errorf("[azuremanagementgroups][with_subscription][json.Unmarshal]%w", err)
+	}
+	fields["subscription_id"] = subscriptionID
+
+	subscriptionCredential, err := json.Marshal(fields)
+	if err != nil {
+		return "", fmt.Errorf("[azuremanagementgroups][with_subscription][json.Marshal]%w", err)
+	}
+	return terraformValueObjects.Credential(subscriptionCredential), nil
+}
+
+// getAccessToken requests an Azure Resource Manager access token for the service principal, or for the container's
+// managed identity when the credential is ambient.
+func getAccessToken(ctx context.Context, azureCredential credential, ambient bool) (string, error) {
+	var request *http.Request
+	var err error
+	if ambient {
+		query := url.Values{"api-version": {"2018-02-01"}, "resource": {managementEndpoint + "/"}}
+		if azureCredential.ClientID != "" {
+			query.Set("client_id", azureCredential.ClientID)
+		}
+		request, err = http.NewRequestWithContext(ctx, http.MethodGet, managedIdentityEndpoint+"?"+query.Encode(), nil)
+		if err != nil {
+			return "", err
+		}
+		request.Header.Set("Metadata", "true")
+	} else {
+		form := url.Values{
+			"grant_type":    {"client_credentials"},
+			"client_id":     {azureCredential.ClientID},
+			"client_secret": {azureCredential.ClientSecret},
+			"scope":         {managementEndpoint + "/.default"},
+		}
+		tokenURL := fmt.Sprintf("%v/%v/oauth2/v2.0/token", loginEndpoint, azureCredential.TenantID)
+		request, err = http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+		if err != nil {
+			return "", err
+		}
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	body, err := doRequest(request)
+	if err != nil {
+		return "", err
+	}
+
+	token := struct {
+		AccessToken string `json:"access_token"`
+	}{}
+	err = json.Unmarshal(body, &token)
+	if err != nil {
+		return "", fmt.Errorf("[json.Unmarshal]%w", err)
+	}
+	return token.AccessToken, nil
+}
+
+// listSubscriptions returns the ids of all subscriptions beneath the management group, including those within
+// nested management groups.
+func listSubscriptions(ctx context.Context, token string, managementGroupID string) ([]string, error) {
+	listURL := fmt.Sprintf("%v/providers/Microsoft.Management/managementGroups/%v/descendants?api-version=2020-05-01", managementEndpoint, managementGroupID)
+
+	subscriptionIDs := make([]string, 0)
+	err := listAll(ctx, token, listURL, func(page listResponse) {
+		for _, descendant := range page.Value {
+			if strings.HasSuffix(descendant.Type, "/subscriptions") {
+				subscriptionIDs = append(subscriptionIDs, descendant.Name)
+			}
+		}
+	})
+	sort.Strings(subscriptionIDs)
+	return subscriptionIDs, err
+}
+
+// listResourceGroups returns the names of the resource groups within the subscription.
+func listResourceGroups(ctx context.Context, token string, subscriptionID string) ([]string, error) {
+	listURL := fmt.Sprintf("%v/subscriptions/%v/resourcegroups?api-version=2021-04-01", managementEndpoint, subscriptionID)
+
+	resourceGroups := make([]string, 0)
+	err := listAll(ctx, token, listURL, func(page listResponse) {
+		for _, resourceGroup := range page.Value {
+			resourceGroups = append(resourceGroups, resourceGroup.Name)
+		}
+	})
+	return resourceGroups, err
+}
+
+// listAll requests every page of an Azure Resource Manager list operation.
+func listAll(ctx context.Context, token string, listURL string, handlePage func(page listResponse)) error {
+	for listURL != "" {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, listURL, nil)
+		if err != nil {
+			return err
+		}
+		request.Header.Set("Authorization", fmt.Sprintf("Bearer %v", token))
+
+		body, err := doRequest(request)
+		if err != nil {
+			return err
+		}
+
+		page := listResponse{}
+		err = json.Unmarshal(body, &page)
+		if err != nil {
+			return fmt.Errorf("[json.Unmarshal]%w", err)
+		}
+
+		handlePage(page)
+		listURL = page.NextLink
+	}
+	return nil
+}
+
+// doRequest sends the request and returns the response body, erroring on a non 200 response.
+func doRequest(request *http.Request) ([]byte, error) {
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("[http.Do]%w", err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("[io.ReadAll]%w", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("[unexpected status code %d from %v]%s", response.StatusCode, request.URL.Path, string(body))
+	}
+	return body, nil
+}
@@ -0,0 +1,93 @@
+package azuremanagementgroups
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+func TestDiscoverResourceGroupDivisions(t *testing.T) {
+	// Given
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/my-tenant/oauth2/v2.0/token" {
+			_, _ = w.Write([]byte(`{"access_token": "my-token"}`))
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer my-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch {
+		case r.URL.Path == "/providers/Microsoft.Management/managementGroups/my-group/descendants" && r.URL.Query().Get("page") == "":
+			_, _ = fmt.Fprintf(w, `{"value": [
+				{"name": "child-group", "type": "Microsoft.Management/managementGroups"},
+				{"name": "sub-1", "type": "Microsoft.Management/managementGroups/subscriptions"}
+			], "nextLink": "%v/providers/Microsoft.Management/managementGroups/my-group/descendants?page=2"}`, server.URL)
+		case r.URL.Path == "/providers/Microsoft.Management/managementGroups/my-group/descendants":
+			_, _ = w.Write([]byte(`{"value": [
+				{"name": "sub-2", "type": "Microsoft.Management/managementGroups/subscriptions"},
+				{"name": "sub-3", "type": "Microsoft.Management/managementGroups/subscriptions"}
+			]}`))
+		case r.URL.Path == "/subscriptions/sub-1/resourcegroups":
+			_, _ = w.Write([]byte(`{"value": [{"name": "networking"}, {"name": "payments"}]}`))
+		case r.URL.Path == "/subscriptions/sub-2/resourcegroups":
+			_, _ = w.Write([]byte(`{"value": [{"name": "networking"}, {"name": "analytics"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	originalManagementEndpoint, originalLoginEndpoint := managementEndpoint, loginEndpoint
+	managementEndpoint, loginEndpoint = server.URL, server.URL
+	defer func() { managementEndpoint, loginEndpoint = originalManagementEndpoint, originalLoginEndpoint }()
+
+	management := terraformValueObjects.Credential(`{"client_id":"id","client_secret":"secret","tenant_id":"my-tenant","subscription_id":"sub-0"}`)
+
+	// When
+	divisions, err := DiscoverResourceGroupDivisions(context.Background(), management, "my-group", []string{"sub-3"})
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, map[terraformValueObjects.Division]terraformValueObjects.Credential{
+		"sub-1_networking": `{"client_id":"id","client_secret":"secret","subscription_id":"sub-1","tenant_id":"my-tenant"}`,
+		"sub-1_payments":   `{"client_id":"id","client_secret":"secret","subscription_id":"sub-1","tenant_id":"my-tenant"}`,
+		"sub-2_networking": `{"client_id":"id","client_secret":"secret","subscription_id":"sub-2","tenant_id":"my-tenant"}`,
+		"sub-2_analytics":  `{"client_id":"id","client_secret":"secret","subscription_id":"sub-2","tenant_id":"my-tenant"}`,
+	}, divisions)
+	assert.Equal(t, "networking", ResourceGroupName("sub-2_networking", "sub-2"))
+	assert.Equal(t, "networking", ResourceGroupName("networking", "sub-2"))
+}
+
+func TestGetAccessToken_ManagedIdentity(t *testing.T) {
+	// Given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata") != "true" || r.URL.Query().Get("client_id") != "my-identity" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token": "identity-token"}`))
+	}))
+	defer server.Close()
+
+	originalManagedIdentityEndpoint := managedIdentityEndpoint
+	managedIdentityEndpoint = server.URL
+	defer func() { managedIdentityEndpoint = originalManagedIdentityEndpoint }()
+
+	// When
+	token, err := getAccessToken(context.Background(), credential{ClientID: "my-identity"}, true)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, "identity-token", token)
+}
@@ -0,0 +1,225 @@
+package baseline
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/clonedrepo"
+)
+
+// RepositoryPath is the path, relative to the root of the scanned repository, of the baseline file.
+const RepositoryPath = ".cloud-concierge/baseline.yaml"
+
+// Baseline records the drift and unmanaged resources accepted by the owners of the scanned repository. Findings
+// matching the baseline are suppressed, so that each pull request only surfaces new deltas.
+type Baseline struct {
+	// Drift are the accepted drifted attributes of managed resources.
+	Drift []AcceptedDrift `yaml:"drift"`
+
+	// Deleted are the accepted managed resources deleted outside of Terraform.
+	Deleted []AcceptedDeleted `yaml:"deleted"`
+
+	// Unmanaged are the accepted resources outside of Terraform control.
+	Unmanaged []AcceptedUnmanaged `yaml:"unmanaged"`
+
+	// Security are the accepted security scan findings.
+	Security []AcceptedSecurityFinding `yaml:"security"`
+}
+
+// AcceptedDrift is the accepted drift of a managed resource. Empty fields match any value.
+type AcceptedDrift struct {
+	// StateFile is the workspace state file of the resource.
+	StateFile string `yaml:"state_file"`
+
+	// Address is the address of the resource within its state file, e.g. module.app.aws_instance.web.
+	Address string `yaml:"address"`
+
+	// InstanceID is the cloud id of the resource instance.
+	InstanceID string `yaml:"instance_id"`
+
+	// Attribute is the drifted attribute path, e.g. tags.env. All attributes of the resource are accepted when empty.
+	Attribute string `yaml:"attribute"`
+
+	// Value is the accepted cloud value of Attribute. Drift to any other value is surfaced again. Any value is accepted
+	// when omitted.
+	Value *string `yaml:"value"`
+}
+
+// AcceptedDeleted is an accepted managed resource deleted outside of Terraform. Empty fields match any value.
+type AcceptedDeleted struct {
+	// StateFile is the workspace state file of the resource.
+	StateFile string `yaml:"state_file"`
+
+	// Address is the address of the resource instance within its state file.
+	Address string `yaml:"address"`
+}
+
+// AcceptedUnmanaged is an accepted resource outside of Terraform control. Empty fields match any value.
+type AcceptedUnmanaged struct {
+	// Division is the cloud division of the resource, e.g. my-aws-account.
+	Division string `yaml:"division"`
+
+	// Type is the Terraform resource type, e.g. aws_s3_bucket.
+	Type string `yaml:"type"`
+
+	// ID is the cloud id of the resource.
+	ID string `yaml:"id"`
+}
+
+// expiryLayout is the layout of the expiry date of an accepted security finding.
+const expiryLayout = "2006-01-02"
+
+// AcceptedSecurityFinding is an accepted security scan finding. Empty fields match any value, though an entry must
+// name at least a rule or a resource, and is otherwise ignored.
+type AcceptedSecurityFinding struct {
+	// RuleID is the scanner's id of the rule, e.g. AVD-AWS-0086, aws-s3-block-public-acls or CKV_AWS_18.
+	RuleID string `yaml:"rule_id"`
+
+	// Resource is the address of the resource, e.g. aws_s3_bucket.tfer--public-assets.
+	Resource string `yaml:"resource"`
+
+	// Division is the cloud division of the resource, e.g. my-aws-account.
+	Division string `yaml:"division"`
+
+	// Expires is the date, formatted as YYYY-MM-DD, after which the finding is surfaced again. The finding is accepted
+	// indefinitely when empty.
+	Expires string `yaml:"expires"`
+
+	// Reason documents why the finding is accepted.
+	Reason string `yaml:"reason"`
+}
+
+// LoadFromRepository loads the baseline within the cloned scanned repository, returning an empty baseline when the
+// repository does not contain one.
+func LoadFromRepository() (*Baseline, error) {
+	return Load(filepath.Join(clonedrepo.Directory, RepositoryPath))
+}
+
+// Load reads the baseline at path, returning an empty baseline when the file does not exist.
+func Load(path string) (*Baseline, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &Baseline{}, nil
+		}
+		return nil, fmt.Errorf("[baseline][load][error reading %v]%w", path, err)
+	}
+
+	baseline := &Baseline{}
+	err = yaml.Unmarshal(content, baseline)
+	if err != nil {
+		return nil, fmt.Errorf("[baseline][load][error parsing %v]%w", path, err)
+	}
+
+	for _, accepted := range baseline.Security {
+		if accepted.Expires == "" {
+			continue
+		}
+		_, err = time.Parse(expiryLayout, accepted.Expires)
+		if err != nil {
+			return nil, fmt.Errorf("[baseline][load][invalid expiry of accepted security finding %v in %v]%w", accepted.RuleID, path, err)
+		}
+	}
+
+	return baseline, nil
+}
+
+// IsEmpty returns true if the baseline accepts no findings.
+func (b *Baseline) IsEmpty() bool {
+	return b == nil || (len(b.Drift) == 0 && len(b.Deleted) == 0 && len(b.Unmanaged) == 0 && len(b.Security) == 0)
+}
+
+// AcceptsDrift returns true if the drift of attribute to cloudValue, for the resource instance at address within
+// stateFile, is accepted.
+func (b *Baseline) AcceptsDrift(stateFile string, address string, instanceID string, attribute string, cloudValue string) bool {
+	if b == nil {
+		return false
+	}
+
+	for _, accepted := range b.Drift {
+		if matches(accepted.StateFile, stateFile) &&
+			matches(accepted.Address, address) &&
+			matches(accepted.InstanceID, instanceID) &&
+			matches(accepted.Attribute, attribute) &&
+			(accepted.Value == nil || *accepted.Value == cloudValue) {
+			return true
+		}
+	}
+	return false
+}
+
+// AcceptsDeleted returns true if the deletion of the resource instance at address within stateFile is accepted.
+func (b *Baseline) AcceptsDeleted(stateFile string, address string) bool {
+	if b == nil {
+		return false
+	}
+
+	for _, accepted := range b.Deleted {
+		if matches(accepted.StateFile, stateFile) && matches(accepted.Address, address) {
+			return true
+		}
+	}
+	return false
+}
+
+// AcceptsUnmanaged returns true if the resource outside of Terraform control is accepted. division is prefixed by the
+// division's provider, e.g. aws-my-aws-account, and is accepted by either its prefixed or its own exact name.
+func (b *Baseline) AcceptsUnmanaged(division string, resourceType string, id string) bool {
+	if b == nil {
+		return false
+	}
+
+	_, divisionName, _ := strings.Cut(division, "-")
+	for _, accepted := range b.Unmanaged {
+		divisionMatches := matches(accepted.Division, division) || accepted.Division == divisionName
+		if divisionMatches && matches(accepted.Type, resourceType) && matches(accepted.ID, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// AcceptsSecurityFinding returns true if the finding of the rule, identified by either ruleID or longID, for the
+// resource at address within division is accepted and the acceptance has not expired by now.
+func (b *Baseline) AcceptsSecurityFinding(division string, ruleID string, longID string, address string, now time.Time) bool {
+	if b == nil {
+		return false
+	}
+
+	for _, accepted := range b.Security {
+		if accepted.isEmpty() {
+			continue
+		}
+
+		ruleMatches := matches(accepted.RuleID, ruleID) || (longID != "" && accepted.RuleID == longID)
+		if !ruleMatches || !matches(accepted.Resource, address) || !matches(accepted.Division, division) {
+			continue
+		}
+
+		if accepted.Expires == "" {
+			return true
+		}
+		expires, err := time.Parse(expiryLayout, accepted.Expires)
+		if err == nil && now.Before(expires.AddDate(0, 0, 1)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmpty returns true if the accepted finding names neither a rule nor a resource, and so would otherwise accept
+// every finding.
+func (a AcceptedSecurityFinding) isEmpty() bool {
+	return strings.TrimSpace(a.RuleID) == "" && strings.TrimSpace(a.Resource) == ""
+}
+
+// matches returns true if the accepted value is empty, or equal to value.
+func matches(accepted string, value string) bool {
+	return accepted == "" || accepted == value
+}
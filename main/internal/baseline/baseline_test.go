@@ -0,0 +1,125 @@
+package baseline
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	// Given
+	path := filepath.Join(t.TempDir(), "baseline.yaml")
+	content := `
+drift:
+  - state_file: compute
+    address: aws_autoscaling_group.web
+    attribute: desired_capacity
+  - address: aws_instance.bastion
+    attribute: instance_type
+    value: t3.large
+deleted:
+  - state_file: storage
+    address: aws_s3_bucket.logs
+unmanaged:
+  - division: my-aws-account
+    type: aws_s3_bucket
+    id: scratch-bucket
+  - division: prod
+    type: aws_sqs_queue
+  - type: aws_cloudwatch_log_group
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+	// When
+	baseline, err := Load(path)
+
+	// Then
+	require.NoError(t, err)
+	assert.False(t, baseline.IsEmpty())
+
+	assert.True(t, baseline.AcceptsDrift("compute", "aws_autoscaling_group.web", "web-asg", "desired_capacity", "4"))
+	assert.False(t, baseline.AcceptsDrift("compute", "aws_autoscaling_group.web", "web-asg", "max_size", "4"))
+	assert.True(t, baseline.AcceptsDrift("bastion", "aws_instance.bastion", "i-1", "instance_type", "t3.large"))
+	assert.False(t, baseline.AcceptsDrift("bastion", "aws_instance.bastion", "i-1", "instance_type", "t3.xlarge"))
+
+	assert.True(t, baseline.AcceptsDeleted("storage", "aws_s3_bucket.logs"))
+	assert.False(t, baseline.AcceptsDeleted("network", "aws_s3_bucket.logs"))
+
+	assert.True(t, baseline.AcceptsUnmanaged("aws-my-aws-account", "aws_s3_bucket", "scratch-bucket"))
+	assert.False(t, baseline.AcceptsUnmanaged("aws-other-account", "aws_s3_bucket", "scratch-bucket"))
+	assert.True(t, baseline.AcceptsUnmanaged("aws-prod", "aws_sqs_queue", "orders"))
+	assert.False(t, baseline.AcceptsUnmanaged("aws-staging-prod", "aws_sqs_queue", "orders"))
+	assert.True(t, baseline.AcceptsUnmanaged("aws-other-account", "aws_cloudwatch_log_group", "/app/logs"))
+}
+
+func TestLoad_Missing(t *testing.T) {
+	// Given
+	path := filepath.Join(t.TempDir(), "baseline.yaml")
+
+	// When
+	baseline, err := Load(path)
+
+	// Then
+	require.NoError(t, err)
+	assert.True(t, baseline.IsEmpty())
+	assert.False(t, baseline.AcceptsDeleted("storage", "aws_s3_bucket.logs"))
+}
+
+func TestLoad_Security(t *testing.T) {
+	// Given
+	path := filepath.Join(t.TempDir(), "baseline.yaml")
+	content := `
+security:
+  - rule_id: AVD-AWS-0086
+    resource: aws_s3_bucket.tfer--public-assets
+    reason: Hosts the public website.
+  - rule_id: aws-s3-enable-bucket-logging
+    division: my-aws-account
+    expires: 2026-10-31
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	now := time.Date(2026, 10, 31, 18, 0, 0, 0, time.UTC)
+
+	// When
+	baseline, err := Load(path)
+
+	// Then
+	require.NoError(t, err)
+	assert.False(t, baseline.IsEmpty())
+
+	assert.True(t, baseline.AcceptsSecurityFinding("my-aws-account", "AVD-AWS-0086", "aws-s3-block-public-acls", "aws_s3_bucket.tfer--public-assets", now))
+	assert.False(t, baseline.AcceptsSecurityFinding("my-aws-account", "AVD-AWS-0086", "aws-s3-block-public-acls", "aws_s3_bucket.tfer--logs", now))
+	assert.True(t, baseline.AcceptsSecurityFinding("my-aws-account", "AVD-AWS-0089", "aws-s3-enable-bucket-logging", "aws_s3_bucket.tfer--logs", now))
+	assert.False(t, baseline.AcceptsSecurityFinding("other-account", "AVD-AWS-0089", "aws-s3-enable-bucket-logging", "aws_s3_bucket.tfer--logs", now))
+	assert.False(t, baseline.AcceptsSecurityFinding("my-aws-account", "AVD-AWS-0089", "aws-s3-enable-bucket-logging", "aws_s3_bucket.tfer--logs", now.AddDate(0, 0, 1)))
+}
+
+func TestAcceptsSecurityFinding_EmptyEntry(t *testing.T) {
+	// Given
+	baseline := &Baseline{Security: []AcceptedSecurityFinding{
+		{},
+		{Division: "my-aws-account", Reason: "Accepted without a rule or resource."},
+	}}
+
+	// When
+	accepted := baseline.AcceptsSecurityFinding("my-aws-account", "AVD-AWS-0086", "aws-s3-block-public-acls", "aws_s3_bucket.tfer--public-assets", time.Now())
+
+	// Then
+	assert.False(t, accepted)
+}
+
+func TestLoad_InvalidSecurityExpiry(t *testing.T) {
+	// Given
+	path := filepath.Join(t.TempDir(), "baseline.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("security:\n  - rule_id: CKV_AWS_18\n    expires: 31/10/2026\n"), 0600))
+
+	// When
+	_, err := Load(path)
+
+	// Then
+	assert.Error(t, err)
+}
@@ -0,0 +1,5 @@
+package clonedrepo
+
+// Directory is the directory, relative to the working directory of the job, into which the scanned repository is
+// cloned.
+const Directory = "repo"
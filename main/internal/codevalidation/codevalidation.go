@@ -0,0 +1,378 @@
+package codevalidation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/clonedrepo"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/hclcreate"
+)
+
+// FailuresPath is the mapping file to which validation failures are written for the state of cloud report.
+const FailuresPath = "mappings/validation-failures.json"
+
+const (
+	// ValidatorTerraform runs `terraform validate` against each workspace receiving generated code.
+	ValidatorTerraform = "terraform"
+
+	// ValidatorTFLint runs tflint against each workspace receiving generated code.
+	ValidatorTFLint = "tflint"
+)
+
+// Config is the configuration of the validation of generated code.
+type Config struct {
+	// Validators are the validators run against the generated code, any of "terraform" and "tflint". Validation is
+	// disabled when empty.
+	Validators []string
+
+	// GeneratedDirectory is the subdirectory of each workspace within which the generated import files are written.
+	// Defaults to cloud-concierge.
+	GeneratedDirectory string
+}
+
+// Failure is an error or warning raised by a validator against the generated code of a workspace.
+type Failure struct {
+	// Workspace is the workspace whose code failed validation.
+	Workspace string `json:"workspace"`
+
+	// Validator is the validator raising the failure.
+	Validator string `json:"validator"`
+
+	// Severity is the severity of the failure, e.g. error or warning.
+	Severity string `json:"severity"`
+
+	// Message describes the failure.
+	Message string `json:"message"`
+
+	// File is the file, relative to the workspace directory, in which the failure was raised, when known.
+	File string `json:"file"`
+
+	// Line is the line of File on which the failure was raised, when known.
+	Line int `json:"line"`
+}
+
+// runCommand runs name with args within dir, returning its stdout and stderr.
+var runCommand = func(ctx context.Context, dir string, name string, args ...string) ([]byte, []byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	return stdout.Bytes(), stderr.Bytes(), err
+}
+
+// Validate runs the configured validators against a copy of the repository of each workspace receiving generated
+// code, with the generated import files placed alongside its configuration. Validation never writes into the
+// repository itself, so nothing it creates is committed.
+func Validate(ctx context.Context, config Config, workspaceToDirectory map[string]string) ([]Failure, error) {
+	failures := []Failure{}
+	if len(config.Validators) == 0 {
+		return failures, nil
+	}
+
+	generatedDirectory := config.GeneratedDirectory
+	if generatedDirectory == "" {
+		generatedDirectory = hclcreate.DefaultGeneratedDirectory
+	}
+
+	workspaces := generatedWorkspaces(workspaceToDirectory, generatedDirectory)
+	if len(workspaces) == 0 {
+		return failures, nil
+	}
+
+	copyDirectory, err := os.MkdirTemp("", "cloud-concierge-validation")
+	if err != nil {
+		return nil, fmt.Errorf("[codevalidation][validate][os.MkdirTemp]%w", err)
+	}
+	defer os.RemoveAll(copyDirectory)
+
+	err = copyRepository(clonedrepo.Directory, copyDirectory)
+	if err != nil {
+		return nil, fmt.Errorf("[codevalidation][validate]%w", err)
+	}
+
+	for _, workspace := range workspaces {
+		directory := filepath.Join(copyDirectory, workspaceToDirectory[workspace])
+
+		err = placeImportFiles(directory, generatedDirectory)
+		if err != nil {
+			return nil, fmt.Errorf("[codevalidation][validate][%v]%w", workspace, err)
+		}
+
+		for _, validator := range config.Validators {
+			var workspaceFailures []Failure
+			switch strings.ToLower(strings.TrimSpace(validator)) {
+			case ValidatorTerraform:
+				workspaceFailures = terraformValidate(ctx, directory)
+			case ValidatorTFLint:
+				workspaceFailures = tflint(ctx, directory)
+			default:
+				return nil, fmt.Errorf("[codevalidation][validate][validator %v is not supported]", validator)
+			}
+
+			for _, failure := range workspaceFailures {
+				failure.Workspace = workspace
+				failures = append(failures, failure)
+			}
+		}
+	}
+
+	return failures, nil
+}
+
+// ValidateConfig returns an error when a configured validator is not supported, or when the generated directory is
+// not a relative path within each workspace.
+func ValidateConfig(config Config) error {
+	generatedDirectory := filepath.ToSlash(config.GeneratedDirectory)
+	if filepath.IsAbs(config.GeneratedDirectory) || strings.HasPrefix(generatedDirectory, "/") {
+		return fmt.Errorf("[codevalidation][generated directory %v must be relative to the workspace]", config.GeneratedDirectory)
+	}
+	for _, element := range strings.Split(generatedDirectory, "/") {
+		if element == ".." {
+			return fmt.Errorf("[codevalidation][generated directory %v must not leave the workspace]", config.GeneratedDirectory)
+		}
+	}
+
+	for _, validator := range config.Validators {
+		switch strings.ToLower(strings.TrimSpace(validator)) {
+		case ValidatorTerraform, ValidatorTFLint:
+		default:
+			return fmt.Errorf("[codevalidation][validator %v is not supported]", validator)
+		}
+	}
+	return nil
+}
+
+// generatedWorkspaces returns the sorted workspaces whose repository directory received new resources files or
+// import files within generatedDirectory.
+func generatedWorkspaces(workspaceToDirectory map[string]string, generatedDirectory string) []string {
+	workspaces := []string{}
+	for workspace, directory := range workspaceToDirectory {
+		newResources, _ := filepath.Glob(fmt.Sprintf("repo%vnew-resources*.tf", directory))
+		_, importsErr := os.Stat(fmt.Sprintf("repo%v%v/imports", directory, generatedDirectory))
+		if len(newResources) > 0 || importsErr == nil {
+			workspaces = append(workspaces, workspace)
+		}
+	}
+	sort.Strings(workspaces)
+	return workspaces
+}
+
+// copyRepository copies the source repository to destination, without its git metadata.
+func copyRepository(source string, destination string) error {
+	return filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(destination, relativePath)
+
+		if info.IsDir() {
+			if info.Name() == ".git" || info.Name() == ".terraform" {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(target, 0700)
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		return copyFile(path, target)
+	})
+}
+
+// copyFile copies the source file to destination.
+func copyFile(source string, destination string) error {
+	sourceFile, err := os.Open(source)
+	if err != nil {
+		return fmt.Errorf("[copy_file][os.Open %v]%w", source, err)
+	}
+	defer sourceFile.Close()
+
+	destinationFile, err := os.OpenFile(destination, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("[copy_file][os.OpenFile %v]%w", destination, err)
+	}
+	defer destinationFile.Close()
+
+	_, err = io.Copy(destinationFile, sourceFile)
+	if err != nil {
+		return fmt.Errorf("[copy_file][io.Copy %v]%w", source, err)
+	}
+	return nil
+}
+
+// placeImportFiles copies the generated import files within generatedDirectory of directory alongside its
+// configuration, so that they are validated as part of the workspace.
+func placeImportFiles(directory string, generatedDirectory string) error {
+	importFiles, err := filepath.Glob(filepath.Join(directory, generatedDirectory, "imports", "*.tf"))
+	if err != nil {
+		return fmt.Errorf("[place_import_files][filepath.Glob]%w", err)
+	}
+
+	for _, importFile := range importFiles {
+		err = copyFile(importFile, filepath.Join(directory, "cloud_concierge_"+filepath.Base(importFile)))
+		if err != nil {
+			return fmt.Errorf("[place_import_files]%w", err)
+		}
+	}
+	return nil
+}
+
+// terraformValidate initializes directory without its backend and runs `terraform validate`, returning its
+// diagnostics as failures.
+func terraformValidate(ctx context.Context, directory string) []Failure {
+	stdout, stderr, err := runCommand(ctx, directory, "terraform", "init", "-backend=false", "-input=false", "-no-color")
+	if err != nil {
+		return []Failure{{
+			Validator: ValidatorTerraform,
+			Severity:  "error",
+			Message:   fmt.Sprintf("terraform init failed: %v", strings.TrimSpace(string(stderr)+" "+string(stdout))),
+		}}
+	}
+
+	// terraform validate exits non-zero when the configuration is invalid, while still writing its diagnostics.
+	stdout, stderr, _ = runCommand(ctx, directory, "terraform", "validate", "-json", "-no-color")
+	failures, err := parseTerraformValidate(stdout)
+	if err != nil {
+		return []Failure{{
+			Validator: ValidatorTerraform,
+			Severity:  "error",
+			Message:   fmt.Sprintf("terraform validate failed: %v", strings.TrimSpace(string(stderr))),
+		}}
+	}
+	return failures
+}
+
+// terraformValidateOutput is the json output of `terraform validate -json`.
+type terraformValidateOutput struct {
+	Diagnostics []struct {
+		Severity string `json:"severity"`
+		Summary  string `json:"summary"`
+		Detail   string `json:"detail"`
+		Range    *struct {
+			Filename string `json:"filename"`
+			Start    struct {
+				Line int `json:"line"`
+			} `json:"start"`
+		} `json:"range"`
+	} `json:"diagnostics"`
+}
+
+// parseTerraformValidate parses the diagnostics of `terraform validate -json` into failures.
+func parseTerraformValidate(content []byte) ([]Failure, error) {
+	output := terraformValidateOutput{}
+	err := json.Unmarshal(content, &output)
+	if err != nil {
+		return nil, fmt.Errorf("[parse_terraform_validate][json.Unmarshal]%w", err)
+	}
+
+	failures := []Failure{}
+	for _, diagnostic := range output.Diagnostics {
+		failure := Failure{
+			Validator: ValidatorTerraform,
+			Severity:  diagnostic.Severity,
+			Message:   strings.TrimSpace(strings.TrimSuffix(diagnostic.Summary+": "+diagnostic.Detail, ": ")),
+		}
+		if diagnostic.Range != nil {
+			failure.File = diagnostic.Range.Filename
+			failure.Line = diagnostic.Range.Start.Line
+		}
+		failures = append(failures, failure)
+	}
+	return failures, nil
+}
+
+// tflint runs tflint within directory, returning its issues and errors as failures.
+func tflint(ctx context.Context, directory string) []Failure {
+	_, _, err := runCommand(ctx, directory, "tflint", "--init")
+	if err != nil {
+		return []Failure{{Validator: ValidatorTFLint, Severity: "error", Message: fmt.Sprintf("tflint --init failed: %v", err)}}
+	}
+
+	// tflint exits non-zero when issues are found, while still writing them.
+	stdout, stderr, _ := runCommand(ctx, directory, "tflint", "--format", "json", "--no-color")
+	failures, err := parseTFLint(stdout)
+	if err != nil {
+		return []Failure{{
+			Validator: ValidatorTFLint,
+			Severity:  "error",
+			Message:   fmt.Sprintf("tflint failed: %v", strings.TrimSpace(string(stderr))),
+		}}
+	}
+	return failures
+}
+
+// tflintOutput is the json output of `tflint --format json`.
+type tflintOutput struct {
+	Issues []struct {
+		Rule struct {
+			Name     string `json:"name"`
+			Severity string `json:"severity"`
+		} `json:"rule"`
+		Message string `json:"message"`
+		Range   struct {
+			Filename string `json:"filename"`
+			Start    struct {
+				Line int `json:"line"`
+			} `json:"start"`
+		} `json:"range"`
+	} `json:"issues"`
+	Errors []struct {
+		Message  string `json:"message"`
+		Severity string `json:"severity"`
+		Range    *struct {
+			Filename string `json:"filename"`
+			Start    struct {
+				Line int `json:"line"`
+			} `json:"start"`
+		} `json:"range"`
+	} `json:"errors"`
+}
+
+// parseTFLint parses the issues and errors of `tflint --format json` into failures.
+func parseTFLint(content []byte) ([]Failure, error) {
+	output := tflintOutput{}
+	err := json.Unmarshal(content, &output)
+	if err != nil {
+		return nil, fmt.Errorf("[parse_tflint][json.Unmarshal]%w", err)
+	}
+
+	failures := []Failure{}
+	for _, issue := range output.Issues {
+		failures = append(failures, Failure{
+			Validator: ValidatorTFLint,
+			Severity:  issue.Rule.Severity,
+			Message:   fmt.Sprintf("%v: %v", issue.Rule.Name, issue.Message),
+			File:      issue.Range.Filename,
+			Line:      issue.Range.Start.Line,
+		})
+	}
+	for _, tflintError := range output.Errors {
+		failure := Failure{Validator: ValidatorTFLint, Severity: tflintError.Severity, Message: tflintError.Message}
+		if failure.Severity == "" {
+			failure.Severity = "error"
+		}
+		if tflintError.Range != nil {
+			failure.File = tflintError.Range.Filename
+			failure.Line = tflintError.Range.Start.Line
+		}
+		failures = append(failures, failure)
+	}
+	return failures, nil
+}
@@ -0,0 +1,139 @@
+package codevalidation
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTerraformValidate(t *testing.T) {
+	// Given
+	content := []byte(`{
+		"format_version": "1.0",
+		"valid": false,
+		"error_count": 1,
+		"warning_count": 0,
+		"diagnostics": [
+			{
+				"severity": "error",
+				"summary": "Unsupported argument",
+				"detail": "An argument named \"arn\" is not expected here.",
+				"range": {"filename": "new-resources.tf", "start": {"line": 4, "column": 3}}
+			},
+			{"severity": "warning", "summary": "Deprecated provider attribute", "detail": ""}
+		]
+	}`)
+
+	// When
+	failures, err := parseTerraformValidate(content)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, []Failure{
+		{Validator: "terraform", Severity: "error", Message: `Unsupported argument: An argument named "arn" is not expected here.`, File: "new-resources.tf", Line: 4},
+		{Validator: "terraform", Severity: "warning", Message: "Deprecated provider attribute"},
+	}, failures)
+
+	// When the output is invalid
+	_, err = parseTerraformValidate([]byte("Error: no configuration"))
+
+	// Then
+	assert.Error(t, err)
+}
+
+func TestParseTFLint(t *testing.T) {
+	// Given
+	content := []byte(`{
+		"issues": [
+			{
+				"rule": {"name": "aws_instance_invalid_type", "severity": "error", "link": ""},
+				"message": "\"t1.2xlarge\" is an invalid value as instance_type",
+				"range": {"filename": "new-resources.tf", "start": {"line": 12, "column": 19}}
+			}
+		],
+		"errors": [{"message": "Failed to load plugin", "severity": ""}]
+	}`)
+
+	// When
+	failures, err := parseTFLint(content)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, []Failure{
+		{Validator: "tflint", Severity: "error", Message: `aws_instance_invalid_type: "t1.2xlarge" is an invalid value as instance_type`, File: "new-resources.tf", Line: 12},
+		{Validator: "tflint", Severity: "error", Message: "Failed to load plugin"},
+	}, failures)
+}
+
+func TestValidate(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	require.NoError(t, os.MkdirAll("repo/.git", 0700))
+	require.NoError(t, os.MkdirAll("repo/prod/cloud-concierge/imports", 0700))
+	require.NoError(t, os.MkdirAll("repo/staging", 0700))
+	require.NoError(t, os.WriteFile("repo/prod/main.tf", []byte("terraform {}\n"), 0600))
+	require.NoError(t, os.WriteFile("repo/prod/new-resources.tf", []byte("resource \"aws_s3_bucket\" \"logs\" {}\n"), 0400))
+	require.NoError(t, os.WriteFile("repo/prod/cloud-concierge/imports/abc_imports.tf", []byte("import {}\n"), 0400))
+	require.NoError(t, os.WriteFile("repo/staging/main.tf", []byte("terraform {}\n"), 0600))
+
+	originalRunCommand := runCommand
+	defer func() { runCommand = originalRunCommand }()
+
+	commands := []string{}
+	runCommand = func(ctx context.Context, dir string, name string, args ...string) ([]byte, []byte, error) {
+		commands = append(commands, name+" "+strings.Join(args, " "))
+
+		_, err := os.Stat(filepath.Join(dir, "cloud_concierge_abc_imports.tf"))
+		require.NoError(t, err)
+		_, err = os.Stat(filepath.Join(dir, "..", ".git"))
+		assert.True(t, os.IsNotExist(err))
+
+		switch {
+		case name == "terraform" && args[0] == "validate":
+			return []byte(`{"valid": false, "diagnostics": [{"severity": "error", "summary": "Missing required argument"}]}`), nil, errors.New("exit status 1")
+		case name == "tflint" && args[0] == "--format":
+			return []byte(`{"issues": [], "errors": []}`), nil, nil
+		}
+		return nil, nil, nil
+	}
+
+	// When
+	failures, err := Validate(context.Background(), Config{Validators: []string{"terraform", "tflint"}}, map[string]string{"prod": "/prod/", "staging": "/staging/"})
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, []Failure{{Workspace: "prod", Validator: "terraform", Severity: "error", Message: "Missing required argument"}}, failures)
+	assert.Equal(t, []string{
+		"terraform init -backend=false -input=false -no-color",
+		"terraform validate -json -no-color",
+		"tflint --init",
+		"tflint --format json --no-color",
+	}, commands)
+	_, err = os.Stat("repo/prod/.terraform")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestValidateConfig(t *testing.T) {
+	// Given
+	supported := Config{Validators: []string{"terraform", " TFLint"}}
+	unsupported := Config{Validators: []string{"checkov"}}
+
+	// Then
+	assert.NoError(t, ValidateConfig(supported))
+	assert.NoError(t, ValidateConfig(Config{}))
+	assert.Error(t, ValidateConfig(unsupported))
+
+	assert.NoError(t, ValidateConfig(Config{GeneratedDirectory: "terraform/generated"}))
+	assert.Error(t, ValidateConfig(Config{GeneratedDirectory: "/etc"}))
+	assert.Error(t, ValidateConfig(Config{GeneratedDirectory: "../outside"}))
+	assert.Error(t, ValidateConfig(Config{GeneratedDirectory: "generated/../../outside"}))
+}
@@ -0,0 +1,118 @@
+package credentialrefresh
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"time"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+// Config is the configuration of the hook that returns refreshed division credentials, e.g. short-lived credentials
+// issued by Vault or an OIDC exchange.
+type Config struct {
+	// Command is run with `sh -c`, and must print the refreshed credentials to stdout.
+	Command string
+
+	// URL is requested with a GET, and must respond with the refreshed credentials.
+	URL string
+
+	// URLToken, when set, is passed as a bearer token when requesting URL.
+	URLToken string
+
+	// Timeout is the maximum duration of the hook.
+	Timeout time.Duration
+}
+
+// IsConfigured returns true if a credential refresh hook is configured.
+func (c Config) IsConfigured() bool {
+	return c.Command != "" || c.URL != ""
+}
+
+// Refresh runs the configured hook and returns its credentials. The hook's output is a json object mapping each
+// division to its credential object, in the same format as the division cloud credentials, for example
+// {"my-division": {"awsAccessKeyID": "...", "awsSecretAccessKey": "...", "token": "..."}}.
+func Refresh(ctx context.Context, config Config) (map[terraformValueObjects.Division]terraformValueObjects.Credential, error) {
+	if config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.Timeout)
+		defer cancel()
+	}
+
+	var output []byte
+	var err error
+	if config.Command != "" {
+		output, err = runCommand(ctx, config.Command)
+	} else {
+		output, err = requestURL(ctx, config.URL, config.URLToken)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCredentials(output)
+}
+
+// runCommand runs the hook command and returns its stdout.
+func runCommand(ctx context.Context, command string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("[credentialrefresh][run_command][%s]%w", string(exitErr.Stderr), err)
+		}
+		return nil, fmt.Errorf("[credentialrefresh][run_command]%w", err)
+	}
+	return output, nil
+}
+
+// requestURL requests the hook url and returns the response body.
+func requestURL(ctx context.Context, url string, token string) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("[credentialrefresh][request_url][http.NewRequestWithContext]%w", err)
+	}
+	if token != "" {
+		request.Header.Set("Authorization", fmt.Sprintf("Bearer %v", token))
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("[credentialrefresh][request_url][http.Do]%w", err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("[credentialrefresh][request_url][io.ReadAll]%w", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("[credentialrefresh][request_url][unexpected status code %d]", response.StatusCode)
+	}
+	return body, nil
+}
+
+// parseCredentials parses the hook's output into division credentials.
+func parseCredentials(output []byte) (map[terraformValueObjects.Division]terraformValueObjects.Credential, error) {
+	rawCredentials := map[string]json.RawMessage{}
+	err := json.Unmarshal(output, &rawCredentials)
+	if err != nil {
+		return nil, fmt.Errorf("[credentialrefresh][parse_credentials][json.Unmarshal]%w", err)
+	}
+
+	credentials := map[terraformValueObjects.Division]terraformValueObjects.Credential{}
+	for division, rawCredential := range rawCredentials {
+		credentialObject := map[string]interface{}{}
+		err = json.Unmarshal(rawCredential, &credentialObject)
+		if err != nil {
+			return nil, fmt.Errorf("[credentialrefresh][parse_credentials][credential of %v is not a json object]%w", division, err)
+		}
+		credentials[terraformValueObjects.Division(division)] = terraformValueObjects.Credential(rawCredential)
+	}
+	return credentials, nil
+}
@@ -0,0 +1,71 @@
+package credentialrefresh
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+func TestRefresh_Command(t *testing.T) {
+	// Given
+	config := Config{Command: `echo '{"prod": {"awsAccessKeyID": "key", "awsSecretAccessKey": "secret", "token": "session"}}'`}
+
+	// When
+	credentials, err := Refresh(context.Background(), config)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, map[terraformValueObjects.Division]terraformValueObjects.Credential{
+		"prod": `{"awsAccessKeyID": "key", "awsSecretAccessKey": "secret", "token": "session"}`,
+	}, credentials)
+}
+
+func TestRefresh_CommandFailure(t *testing.T) {
+	// Given
+	config := Config{Command: "echo 'vault is sealed' >&2; exit 1"}
+
+	// When
+	_, err := Refresh(context.Background(), config)
+
+	// Then
+	assert.ErrorContains(t, err, "vault is sealed")
+}
+
+func TestRefresh_URL(t *testing.T) {
+	// Given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer my-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"prod": {"ambient": "aws", "roleARN": "arn:aws:iam::123456789012:role/scanner"}}`))
+	}))
+	defer server.Close()
+
+	// When
+	credentials, err := Refresh(context.Background(), Config{URL: server.URL, URLToken: "my-token"})
+
+	// Then
+	require.NoError(t, err)
+	assert.True(t, credentials["prod"].IsAmbient())
+
+	// When
+	_, err = Refresh(context.Background(), Config{URL: server.URL})
+
+	// Then
+	assert.Error(t, err)
+}
+
+func TestRefresh_InvalidOutput(t *testing.T) {
+	// When
+	_, err := Refresh(context.Background(), Config{Command: `echo '{"prod": "not-an-object"}'`})
+
+	// Then
+	assert.Error(t, err)
+}
@@ -1479,3 +1479,82 @@ func azureResourceCategories() TypeToCategory {
 		},
 	}
 }
+
+// datadogResourceCategories stores categories for different terraform resources for datadog.
+// Possible Datadog Categories: operations, security, and application integration
+func datadogResourceCategories() TypeToCategory {
+	return TypeToCategory{
+		"datadog_dashboard": ResourceCategory{
+			primaryCat: "operations",
+		},
+		"datadog_dashboard_json": ResourceCategory{
+			primaryCat: "operations",
+		},
+		"datadog_dashboard_list": ResourceCategory{
+			primaryCat: "operations",
+		},
+		"datadog_downtime": ResourceCategory{
+			primaryCat: "operations",
+		},
+		"datadog_integration_aws": ResourceCategory{
+			primaryCat: "application integration",
+		},
+		"datadog_integration_azure": ResourceCategory{
+			primaryCat: "application integration",
+		},
+		"datadog_integration_gcp": ResourceCategory{
+			primaryCat: "application integration",
+		},
+		"datadog_integration_pagerduty": ResourceCategory{
+			primaryCat: "application integration",
+		},
+		"datadog_integration_slack_channel": ResourceCategory{
+			primaryCat: "application integration",
+		},
+		"datadog_logs_archive": ResourceCategory{
+			primaryCat:   "operations",
+			secondaryCat: "storage",
+		},
+		"datadog_logs_custom_pipeline": ResourceCategory{
+			primaryCat: "operations",
+		},
+		"datadog_logs_index": ResourceCategory{
+			primaryCat: "operations",
+		},
+		"datadog_logs_metric": ResourceCategory{
+			primaryCat: "operations",
+		},
+		"datadog_metric_metadata": ResourceCategory{
+			primaryCat: "operations",
+		},
+		"datadog_monitor": ResourceCategory{
+			primaryCat: "operations",
+		},
+		"datadog_role": ResourceCategory{
+			primaryCat: "security",
+		},
+		"datadog_security_monitoring_default_rule": ResourceCategory{
+			primaryCat:   "security",
+			secondaryCat: "operations",
+		},
+		"datadog_security_monitoring_rule": ResourceCategory{
+			primaryCat:   "security",
+			secondaryCat: "operations",
+		},
+		"datadog_service_level_objective": ResourceCategory{
+			primaryCat: "operations",
+		},
+		"datadog_synthetics_global_variable": ResourceCategory{
+			primaryCat: "operations",
+		},
+		"datadog_synthetics_private_location": ResourceCategory{
+			primaryCat: "operations",
+		},
+		"datadog_synthetics_test": ResourceCategory{
+			primaryCat: "operations",
+		},
+		"datadog_user": ResourceCategory{
+			primaryCat: "security",
+		},
+	}
+}
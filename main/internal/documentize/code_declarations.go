@@ -0,0 +1,237 @@
+package documentize
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Jeffail/gabs/v2"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+// identifyingAttributes are the attributes naming the cloud resource of resource types not identified by name, and
+// so identifying the resource declared by a resource block before it has been applied. Attributes that merely
+// reference another resource, e.g. key_name or log_group_name, are never identifying.
+var identifyingAttributes = map[ResourceType]string{
+	"aws_db_instance":                   "identifier",
+	"aws_docdb_cluster":                 "cluster_identifier",
+	"aws_elasticache_cluster":           "cluster_id",
+	"aws_elasticache_replication_group": "replication_group_id",
+	"aws_lambda_function":               "function_name",
+	"aws_neptune_cluster":               "cluster_identifier",
+	"aws_rds_cluster":                   "cluster_identifier",
+	"aws_redshift_cluster":              "cluster_identifier",
+	"aws_s3_bucket":                     "bucket",
+	"aws_sfn_state_machine":             "name",
+}
+
+// identifyingAttribute returns the attribute naming the cloud resource of a resource type, which is name unless
+// listed within identifyingAttributes.
+func identifyingAttribute(tfType ResourceType) string {
+	if attribute, ok := identifyingAttributes[tfType]; ok {
+		return attribute
+	}
+	return "name"
+}
+
+// pullWorkspaceCodeDeclarations extracts the literal identifying attribute of each resource block declared within the
+// .tf files of each workspace directory, keyed by workspace and resource type. Directories not present within the
+// repository and files that do not parse are skipped.
+func (d *documentize) pullWorkspaceCodeDeclarations(workspaceToDirectory map[string]string) (map[Workspace]map[ResourceType][]string, error) {
+	workspaceToDeclarations := map[Workspace]map[ResourceType][]string{}
+
+	for workspace, directory := range workspaceToDirectory {
+		entries, err := os.ReadDir(fmt.Sprintf("repo%v", directory))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("[os.ReadDir] Error reading repo%v: %v", directory, err)
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".tf") {
+				continue
+			}
+
+			path := fmt.Sprintf("repo%v%v", directory, entry.Name())
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("[os.ReadFile] Error reading %v: %v", path, err)
+			}
+
+			for tfType, declarations := range extractCodeDeclarations(content, path) {
+				if workspaceToDeclarations[Workspace(workspace)] == nil {
+					workspaceToDeclarations[Workspace(workspace)] = map[ResourceType][]string{}
+				}
+				workspaceToDeclarations[Workspace(workspace)][tfType] = append(workspaceToDeclarations[Workspace(workspace)][tfType], declarations...)
+			}
+		}
+	}
+
+	return workspaceToDeclarations, nil
+}
+
+// extractCodeDeclarations extracts the literal identifying attribute of each resource block within the HCL content,
+// keyed by resource type. Resource blocks without a literal identifying attribute cannot be matched to a cloud
+// resource and are left out.
+func extractCodeDeclarations(content []byte, path string) map[ResourceType][]string {
+	typeToDeclarations := map[ResourceType][]string{}
+
+	file, diags := hclsyntax.ParseConfig(content, path, hcl.InitialPos)
+	if diags.HasErrors() {
+		return typeToDeclarations
+	}
+
+	for _, block := range file.Body.(*hclsyntax.Body).Blocks {
+		if block.Type != "resource" || len(block.Labels) != 2 {
+			continue
+		}
+
+		tfType := ResourceType(block.Labels[0])
+		expression, ok := block.Body.Attributes[identifyingAttribute(tfType)]
+		if !ok {
+			continue
+		}
+
+		value, diags := expression.Expr.Value(nil)
+		if diags.HasErrors() || value.IsNull() || !value.IsWhollyKnown() || value.Type() != cty.String || value.AsString() == "" {
+			continue
+		}
+		typeToDeclarations[tfType] = append(typeToDeclarations[tfType], value.AsString())
+	}
+
+	return typeToDeclarations
+}
+
+// extractIdentifyingValuesFromTerraformerState extracts the value of the identifying attribute of each resource
+// instance within the current gabs-parsed terraformer-generated state json, when set.
+func extractIdentifyingValuesFromTerraformerState(tfStateParsed *gabs.Container) map[ResourceData]string {
+	outputMap := map[ResourceData]string{}
+
+	i := 0
+	for tfStateParsed.Exists("resources", strconv.Itoa(i)) {
+		j := 0
+		currentType := tfStateParsed.Search("resources", strconv.Itoa(i), "type").Data().(string)
+		currentName := tfStateParsed.Search("resources", strconv.Itoa(i), "name").Data().(string)
+
+		for tfStateParsed.Exists("resources", strconv.Itoa(i), "instances", strconv.Itoa(j), "attributes_flat", "id") {
+			attributesFlat := tfStateParsed.Search("resources", strconv.Itoa(i), "instances", strconv.Itoa(j), "attributes_flat")
+
+			currentResourceData := ResourceData{
+				id:     ResourceID(attributesFlat.Search("id").Data().(string)),
+				name:   ResourceName(currentName),
+				tfType: ResourceType(currentType),
+			}
+
+			if value, ok := attributesFlat.Search(identifyingAttribute(ResourceType(currentType))).Data().(string); ok && value != "" {
+				outputMap[currentResourceData] = value
+			}
+			j++
+		}
+		i++
+	}
+	return outputMap
+}
+
+// workspaceDivisions returns the divisions of each workspace, being those containing resources within the
+// workspace's state.
+func workspaceDivisions(
+	workspaceToIDMap map[Workspace]map[ResourceData]bool,
+	divToID map[terraformValueObjects.Division]map[ResourceData]bool,
+) map[Workspace]map[terraformValueObjects.Division]bool {
+	typeAndIDToDivision := map[string]terraformValueObjects.Division{}
+	for div, resourceSet := range divToID {
+		for resource := range resourceSet {
+			typeAndIDToDivision[fmt.Sprintf("%v.%v", resource.tfType, resource.id)] = div
+		}
+	}
+
+	workspaceToDivisions := map[Workspace]map[terraformValueObjects.Division]bool{}
+	for workspace, resourceSet := range workspaceToIDMap {
+		for resource := range resourceSet {
+			div, ok := typeAndIDToDivision[fmt.Sprintf("%v.%v", resource.tfType, resource.id)]
+			if !ok {
+				continue
+			}
+			if workspaceToDivisions[workspace] == nil {
+				workspaceToDivisions[workspace] = map[terraformValueObjects.Division]bool{}
+			}
+			workspaceToDivisions[workspace][div] = true
+		}
+	}
+	return workspaceToDivisions
+}
+
+// excludeDeclaredResources removes from divToID the resources already declared within the Terraform code of a
+// workspace, though not yet applied to its state. A resource is declared when a resource block of the same type
+// within a workspace of the resource's division has a literal identifying attribute equal to the resource's. As the
+// division of a workspace without scanned resources in its state is unknown, its declarations only match resources
+// whose identifying value is unique across divisions, so that names such as "default" do not match across accounts.
+func excludeDeclaredResources(
+	divToID map[terraformValueObjects.Division]map[ResourceData]bool,
+	divToIdentifiers map[terraformValueObjects.Division]map[ResourceData]string,
+	workspaceToDeclarations map[Workspace]map[ResourceType][]string,
+	workspaceToDivisions map[Workspace]map[terraformValueObjects.Division]bool,
+) map[terraformValueObjects.Division]map[ResourceData]bool {
+	if len(workspaceToDeclarations) == 0 {
+		return divToID
+	}
+
+	identifierToDivisions := map[string]map[terraformValueObjects.Division]bool{}
+	for div, resourceToIdentifier := range divToIdentifiers {
+		for resource, identifier := range resourceToIdentifier {
+			key := fmt.Sprintf("%v.%v", resource.tfType, identifier)
+			if identifierToDivisions[key] == nil {
+				identifierToDivisions[key] = map[terraformValueObjects.Division]bool{}
+			}
+			identifierToDivisions[key][div] = true
+		}
+	}
+
+	outputMap := map[terraformValueObjects.Division]map[ResourceData]bool{}
+	for div, resourceSet := range divToID {
+		outputMap[div] = map[ResourceData]bool{}
+		for resource := range resourceSet {
+			identifier, ok := divToIdentifiers[div][resource]
+			if ok && isDeclaredInCode(div, resource.tfType, identifier, workspaceToDeclarations, workspaceToDivisions, identifierToDivisions) {
+				continue
+			}
+			outputMap[div][resource] = true
+		}
+	}
+	return outputMap
+}
+
+// isDeclaredInCode returns true if a workspace that may contain resources of div declares a resource of tfType with
+// the identifying value identifier.
+func isDeclaredInCode(
+	div terraformValueObjects.Division,
+	tfType ResourceType,
+	identifier string,
+	workspaceToDeclarations map[Workspace]map[ResourceType][]string,
+	workspaceToDivisions map[Workspace]map[terraformValueObjects.Division]bool,
+	identifierToDivisions map[string]map[terraformValueObjects.Division]bool,
+) bool {
+	for workspace, typeToDeclarations := range workspaceToDeclarations {
+		divisions, known := workspaceToDivisions[workspace]
+		if known && !divisions[div] {
+			continue
+		}
+		if !known && len(identifierToDivisions[fmt.Sprintf("%v.%v", tfType, identifier)]) > 1 {
+			continue
+		}
+
+		for _, declaration := range typeToDeclarations[tfType] {
+			if declaration == identifier {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,170 @@
+package documentize
+
+import (
+	"os"
+	"testing"
+
+	"github.com/Jeffail/gabs/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+func TestExtractCodeDeclarations(t *testing.T) {
+	// Given
+	content := []byte(`
+resource "aws_s3_bucket" "logs" {
+  bucket = "acme-logs"
+  tags = {
+    name = "ignored"
+  }
+}
+
+resource "aws_iam_role" "ci" {
+  name        = "ci"
+  description = "CI role"
+}
+
+resource "aws_iam_role" "dynamic" {
+  name = var.role_name
+}
+
+resource "aws_instance" "web" {
+  key_name = "deployer"
+}
+
+resource "aws_lambda_permission" "invoke" {
+  function_name = "worker"
+}
+
+data "aws_s3_bucket" "existing" {
+  bucket = "acme-existing"
+}
+`)
+
+	// When
+	declarations := extractCodeDeclarations(content, "main.tf")
+
+	// Then
+	assert.Equal(t, map[ResourceType][]string{
+		"aws_s3_bucket": {"acme-logs"},
+		"aws_iam_role":  {"ci"},
+	}, declarations)
+}
+
+func TestPullWorkspaceCodeDeclarations(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	require.NoError(t, os.MkdirAll("repo/prod", 0700))
+	require.NoError(t, os.WriteFile("repo/prod/storage.tf", []byte(`resource "aws_s3_bucket" "logs" {
+  bucket = "acme-logs"
+}
+`), 0400))
+	require.NoError(t, os.WriteFile("repo/prod/broken.tf", []byte(`resource "aws_s3_bucket" {`), 0400))
+	require.NoError(t, os.WriteFile("repo/prod/README.md", []byte(`resource "aws_s3_bucket" "docs" { bucket = "docs" }`), 0400))
+
+	d := documentize{}
+
+	// When
+	declarations, err := d.pullWorkspaceCodeDeclarations(map[string]string{"prod": "/prod/", "staging": "/staging/"})
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, map[Workspace]map[ResourceType][]string{
+		"prod": {"aws_s3_bucket": {"acme-logs"}},
+	}, declarations)
+}
+
+func TestExcludeDeclaredResources(t *testing.T) {
+	// Given
+	prodState, err := gabs.ParseJSON([]byte(`{
+  "resources": [
+    {
+      "mode": "managed",
+      "type": "aws_s3_bucket",
+      "name": "tfer--acme-logs",
+      "instances": [{"attributes_flat": {"id": "acme-logs", "bucket": "acme-logs", "acl": "private"}}]
+    },
+    {
+      "mode": "managed",
+      "type": "aws_s3_bucket",
+      "name": "tfer--acme-assets",
+      "instances": [{"attributes_flat": {"id": "acme-assets", "bucket": "acme-assets"}}]
+    },
+    {
+      "mode": "managed",
+      "type": "aws_security_group",
+      "name": "tfer--default",
+      "instances": [{"attributes_flat": {"id": "sg-1", "name": "default"}}]
+    },
+    {
+      "mode": "managed",
+      "type": "aws_instance",
+      "name": "tfer--web",
+      "instances": [{"attributes_flat": {"id": "i-1", "key_name": "deployer"}}]
+    }
+  ]
+}`))
+	require.NoError(t, err)
+	stagingState, err := gabs.ParseJSON([]byte(`{
+  "resources": [
+    {
+      "mode": "managed",
+      "type": "aws_security_group",
+      "name": "tfer--default",
+      "instances": [{"attributes_flat": {"id": "sg-2", "name": "default"}}]
+    },
+    {
+      "mode": "managed",
+      "type": "aws_iam_role",
+      "name": "tfer--ci",
+      "instances": [{"attributes_flat": {"id": "ci", "name": "ci"}}]
+    }
+  ]
+}`))
+	require.NoError(t, err)
+
+	prodIDs, err := extractResourceIdsFromTerraformerState(prodState)
+	require.NoError(t, err)
+	stagingIDs, err := extractResourceIdsFromTerraformerState(stagingState)
+	require.NoError(t, err)
+
+	divToID := map[terraformValueObjects.Division]map[ResourceData]bool{"aws-prod": prodIDs, "aws-staging": stagingIDs}
+	divToIdentifiers := map[terraformValueObjects.Division]map[ResourceData]string{
+		"aws-prod":    extractIdentifyingValuesFromTerraformerState(prodState),
+		"aws-staging": extractIdentifyingValuesFromTerraformerState(stagingState),
+	}
+	workspaceToIDMap := map[Workspace]map[ResourceData]bool{
+		"prod": {ResourceData{tfType: "aws_s3_bucket", id: "acme-assets"}: true},
+	}
+	workspaceToDeclarations := map[Workspace]map[ResourceType][]string{
+		"prod": {
+			"aws_s3_bucket":      {"acme-logs"},
+			"aws_iam_role":       {"ci"},
+			"aws_security_group": {"default"},
+		},
+		"new": {
+			"aws_instance":       {"deployer"},
+			"aws_security_group": {"default"},
+		},
+	}
+
+	// When
+	newResources := excludeDeclaredResources(divToID, divToIdentifiers, workspaceToDeclarations, workspaceDivisions(workspaceToIDMap, divToID))
+
+	// Then
+	assert.Equal(t, map[terraformValueObjects.Division]map[ResourceData]bool{
+		"aws-prod": {
+			ResourceData{tfType: "aws_s3_bucket", id: "acme-assets", name: "tfer--acme-assets"}: true,
+			ResourceData{tfType: "aws_instance", id: "i-1", name: "tfer--web"}:                  true,
+		},
+		"aws-staging": {
+			ResourceData{tfType: "aws_security_group", id: "sg-2", name: "tfer--default"}: true,
+			ResourceData{tfType: "aws_iam_role", id: "ci", name: "tfer--ci"}:              true,
+		},
+	}, newResources)
+}
@@ -0,0 +1,171 @@
+package documentize
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Jeffail/gabs/v2"
+)
+
+// datadogResourceDetails is a struct for packaging all relevant information for a Datadog resource.
+type datadogResourceDetails struct {
+
+	// terraformName is the name of the datadog resource within Terraform configuration.
+	terraformName string
+
+	// terraformType is the name of the datadog resource type within Terraform configuration.
+	terraformType string
+
+	// terraformModule is the name of the module where the datadog resource resides.
+	terraformModule string
+
+	// datadogInstanceName is the name, or for dashboards the title, of the resource as it resides in Datadog.
+	datadogInstanceName string
+
+	// datadogInstanceTags are the tags on the resource
+	datadogInstanceTags map[string]string
+}
+
+// datadogResourceExtractor implements the ResourceExtractor interface for
+// Datadog resources.
+type datadogResourceExtractor struct {
+
+	// currentResourceDetails is a struct containing information about a resource necessary
+	// for generating a document about it.
+	currentResourceDetails *datadogResourceDetails
+
+	// typeToCategory is a map between datadog resource types and their categories.
+	typeToCategory TypeToCategory
+}
+
+// NewDatadogResourceExtractor returns an instance of datadogResourceExtractor.
+func NewDatadogResourceExtractor() ResourceExtractor {
+	return &datadogResourceExtractor{
+		currentResourceDetails: &datadogResourceDetails{},
+		typeToCategory:         datadogResourceCategories(),
+	}
+}
+
+// GetCurrentResourceDetails returns the details for a datadogResourceExtractor instance.
+func (drx *datadogResourceExtractor) GetCurrentResourceDetails() *datadogResourceDetails {
+	return drx.currentResourceDetails
+}
+
+// ExtractResourceDetails extracts relevant data points from a terraform state resource.
+func (drx *datadogResourceExtractor) ExtractResourceDetails(tfStateParsed *gabs.Container, isAttributesFlat bool, resourceIndex int, instanceIndex int) error {
+	attribute := "attributes"
+	if isAttributesFlat {
+		attribute += "_flat"
+	}
+
+	resourcesArray := tfStateParsed.Path("resources").Data().([]interface{})
+	if resourceIndex >= len(resourcesArray) {
+		return fmt.Errorf("resourceIndex out of bounds")
+	}
+
+	resource := resourcesArray[resourceIndex].(map[string]interface{})
+	instances := resource["instances"].([]interface{})
+	if instanceIndex >= len(instances) {
+		return fmt.Errorf("instanceIndex out of bounds")
+	}
+
+	instance := instances[instanceIndex].(map[string]interface{})
+	attributes := instance[attribute].(map[string]interface{})
+
+	drx.currentResourceDetails.terraformName = resource["name"].(string)
+	drx.currentResourceDetails.terraformType = resource["type"].(string)
+
+	drx.currentResourceDetails.terraformModule = "none"
+	if instance["module"] != nil {
+		drx.currentResourceDetails.terraformModule = instance["module"].(string)
+	}
+
+	drx.currentResourceDetails.datadogInstanceName = "none"
+	if name, ok := attributes["name"].(string); ok && name != "" {
+		drx.currentResourceDetails.datadogInstanceName = name
+	} else if title, ok := attributes["title"].(string); ok && title != "" {
+		drx.currentResourceDetails.datadogInstanceName = title
+	}
+
+	// Datadog tags are a list of "key:value" strings rather than a map.
+	tagValues := make([]string, 0)
+	if isAttributesFlat {
+		for key, value := range attributes {
+			if strings.HasPrefix(key, "tags.") && key != "tags.#" {
+				tagValues = append(tagValues, value.(string))
+			}
+		}
+	} else if tags, ok := attributes["tags"].([]interface{}); ok {
+		for _, value := range tags {
+			tagValues = append(tagValues, value.(string))
+		}
+	}
+
+	tags := make(map[string]string)
+	for _, tag := range tagValues {
+		key, value, _ := strings.Cut(tag, ":")
+		tags[key] = value
+	}
+	drx.currentResourceDetails.datadogInstanceTags = tags
+
+	return nil
+}
+
+// ResourceDetailsToSentence converts resource details to an english sentence format.
+func (drx *datadogResourceExtractor) ResourceDetailsToSentence() string {
+	// Base sentence structure
+	sentence := fmt.Sprintf(
+		"terraform name of %s and type %s",
+		stringToWords(drx.currentResourceDetails.terraformName),
+		stringToWords(drx.currentResourceDetails.terraformType),
+	)
+
+	if drx.currentResourceDetails.terraformModule != "none" {
+		sentence = fmt.Sprintf(
+			"%s within module %s",
+			sentence,
+			stringToWords(drx.currentResourceDetails.terraformModule),
+		)
+	}
+
+	if drx.currentResourceDetails.datadogInstanceName != "none" {
+		sentence = fmt.Sprintf("%v resource name of %v",
+			sentence,
+			stringToWords(drx.currentResourceDetails.datadogInstanceName),
+		)
+	}
+
+	// Add tags if they exist
+	if len(drx.currentResourceDetails.datadogInstanceTags) > 0 {
+		for key, value := range drx.currentResourceDetails.datadogInstanceTags {
+			sentence += fmt.Sprintf(" with tag key of %s and value of %s", key, value)
+		}
+	}
+
+	// Add category info
+	if category, ok := drx.typeToCategory[ResourceType(drx.currentResourceDetails.terraformType)]; ok {
+		sentence += fmt.Sprintf(" with primary category of %s", category.primaryCat)
+		if category.secondaryCat != "" {
+			sentence += fmt.Sprintf(" and secondary category of %s", category.secondaryCat)
+		}
+	}
+
+	// End the sentence
+	sentence += "."
+
+	return sentence
+}
+
+// OutputResourceDetailsSentence coordinates ExtractResourceDetails and ResourceDetailsToSentence in order
+// to extract and format as a sentence a resource's details from within a state file.
+func (drx *datadogResourceExtractor) OutputResourceDetailsSentence(tfStateParsed *gabs.Container, isAttributesFlat bool, resourceIndex int, instanceIndex int) (string, error) {
+	err := drx.ExtractResourceDetails(tfStateParsed, isAttributesFlat, resourceIndex, instanceIndex)
+
+	if err != nil {
+		return "", fmt.Errorf("[drx.ExtractResourceDetails] %v", err)
+	}
+
+	resourceSentenceDetails := drx.ResourceDetailsToSentence()
+
+	return resourceSentenceDetails, nil
+}
@@ -0,0 +1,126 @@
+package documentize
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Jeffail/gabs/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatadogResourceExtractor_ExtractResourceDetails_NotFlat(t *testing.T) {
+	dre := datadogResourceExtractor{
+		currentResourceDetails: &datadogResourceDetails{},
+	}
+
+	tfStateParsed, err := gabs.ParseJSON([]byte(`{
+		"resources": [
+			{
+			  "mode": "managed",
+			  "type": "datadog_monitor",
+			  "name": "tfer--monitor_123",
+			  "provider": "provider[\"registry.terraform.io/datadog/datadog\"]",
+			  "instances": [
+				{
+				  "schema_version": 0,
+				  "attributes": {
+					"id": "123",
+					"name": "High CPU usage",
+					"tags": ["team:platform", "env:prod"]
+				  },
+				  "sensitive_attributes": []
+				}
+			  ]
+			}
+		  ]
+	}`))
+	require.NoError(t, err)
+
+	err = dre.ExtractResourceDetails(tfStateParsed, false, 0, 0)
+	require.NoError(t, err)
+
+	actualResourceDetails := dre.GetCurrentResourceDetails()
+
+	expectedResourceDetails := &datadogResourceDetails{
+		terraformModule:     "none",
+		terraformName:       "tfer--monitor_123",
+		terraformType:       "datadog_monitor",
+		datadogInstanceName: "High CPU usage",
+		datadogInstanceTags: map[string]string{
+			"team": "platform",
+			"env":  "prod",
+		},
+	}
+
+	if !reflect.DeepEqual(actualResourceDetails, expectedResourceDetails) {
+		t.Errorf("got:\n%v\nexpected:\n%v", actualResourceDetails, expectedResourceDetails)
+	}
+}
+
+func TestDatadogResourceExtractor_ExtractResourceDetails(t *testing.T) {
+	dre := datadogResourceExtractor{
+		currentResourceDetails: &datadogResourceDetails{},
+	}
+
+	tfStateParsed, err := gabs.ParseJSON([]byte(`{
+		"resources": [
+			{
+			  "mode": "managed",
+			  "type": "datadog_dashboard",
+			  "name": "tfer--dashboard_abc-def",
+			  "provider": "provider[\"registry.terraform.io/datadog/datadog\"]",
+			  "instances": [
+				{
+				  "schema_version": 0,
+				  "attributes_flat": {
+					"id": "abc-def",
+					"title": "Service Overview",
+					"tags.#": "1",
+					"tags.0": "team:platform"
+				  },
+				  "sensitive_attributes": []
+				}
+			  ]
+			}
+		  ]
+	}`))
+	require.NoError(t, err)
+
+	err = dre.ExtractResourceDetails(tfStateParsed, true, 0, 0)
+	require.NoError(t, err)
+
+	actualResourceDetails := dre.GetCurrentResourceDetails()
+
+	expectedResourceDetails := &datadogResourceDetails{
+		terraformModule:     "none",
+		terraformName:       "tfer--dashboard_abc-def",
+		terraformType:       "datadog_dashboard",
+		datadogInstanceName: "Service Overview",
+		datadogInstanceTags: map[string]string{
+			"team": "platform",
+		},
+	}
+
+	if !reflect.DeepEqual(actualResourceDetails, expectedResourceDetails) {
+		t.Errorf("got:\n%v\nexpected:\n%v", actualResourceDetails, expectedResourceDetails)
+	}
+}
+
+func TestDatadogResourceExtractor_ResourceDetailsToSentence(t *testing.T) {
+	dre := datadogResourceExtractor{
+		currentResourceDetails: &datadogResourceDetails{
+			terraformModule:     "none",
+			terraformName:       "tfer--monitor_123",
+			terraformType:       "datadog_monitor",
+			datadogInstanceName: "none",
+			datadogInstanceTags: map[string]string{},
+		},
+		typeToCategory: datadogResourceCategories(),
+	}
+
+	sentence := dre.ResourceDetailsToSentence()
+
+	expectedSentence := "terraform name of " + stringToWords("tfer--monitor_123") +
+		" and type " + stringToWords("datadog_monitor") + " with primary category of operations."
+	require.Equal(t, expectedSentence, sentence)
+}
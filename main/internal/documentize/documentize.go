@@ -44,8 +44,8 @@ type Documentize interface {
 	ConvertNewResourcesToJSON(resourceDocMap map[ResourceName]string) ([]byte, error)
 
 	// IdentifyNewResources determines which resources in the remote cloud environment state files from
-	// terraformer are not present in the workspace state files. Returns a map of new resources to their
-	// corresponding provider.
+	// terraformer are neither present in the workspace state files nor declared within the workspace code.
+	// Returns a map of new resources to their corresponding provider.
 	IdentifyNewResources(workspaceToDirectory map[string]string) (map[terraformValueObjects.Division]map[ResourceData]bool, error)
 
 	// NewResourceDocuments creates a map between new resources and a document extracted from that
@@ -73,6 +73,7 @@ func NewDocumentize(divisionToProvider map[terraformValueObjects.Division]terraf
 		"aws":     NewAWSResourceExtractor(),
 		"google":  NewGoogleResourceExtractor(),
 		"azurerm": NewAzureResourceExtractor(),
+		"datadog": NewDatadogResourceExtractor(),
 	}
 
 	return &documentize{
@@ -22,6 +22,26 @@ type ResourceData struct {
 	name ResourceName
 }
 
+// NewResourceData creates a ResourceData for the resource of type tfType with the passed id and name.
+func NewResourceData(tfType string, id string, name string) ResourceData {
+	return ResourceData{tfType: ResourceType(tfType), id: ResourceID(id), name: ResourceName(name)}
+}
+
+// Type returns the Terraform resource's type.
+func (r ResourceData) Type() string {
+	return string(r.tfType)
+}
+
+// ID returns the Terraform resource's id.
+func (r ResourceData) ID() string {
+	return string(r.id)
+}
+
+// Name returns the name of the Terraform resource within the Terraform configuration.
+func (r ResourceData) Name() string {
+	return string(r.name)
+}
+
 // ConvertNewResourcesToJSON converts the output of NewResourceDocuments to a json-format byte array.
 func (d *documentize) ConvertNewResourcesToJSON(resourceDocMap map[ResourceName]string) ([]byte, error) {
 	jsonObj := gabs.New()
@@ -37,20 +57,26 @@ func (d *documentize) ConvertNewResourcesToJSON(resourceDocMap map[ResourceName]
 }
 
 // IdentifyNewResources determines which resources in the remote cloud environment state files from
-// terraformer are not present in the workspace state files. Returns a map of new resources to their
-// corresponding provider.
+// terraformer are neither present in the workspace state files nor declared within the workspace code.
+// Returns a map of new resources to their corresponding provider.
 func (d *documentize) IdentifyNewResources(workspaceToDirectory map[string]string) (map[terraformValueObjects.Division]map[ResourceData]bool, error) {
 	workspaceToIDMap, err := d.pullWorkspaceResourceIdentifiers(workspaceToDirectory)
 	if err != nil {
 		return nil, fmt.Errorf("[d.pullWorkspaceResourceIdentifiers] %v", err)
 	}
 
-	divToIDMap, err := d.pullTerraformerResourceIdentifiers()
+	divToIDMap, divToIdentifiers, err := d.pullTerraformerResourceIdentifiers()
 	if err != nil {
 		return nil, fmt.Errorf("[d.pullTerraformerResourceIdentifiers] %v", err)
 	}
 
-	divToResourceData := selectNewResources(workspaceToIDMap, divToIDMap)
+	workspaceToDeclarations, err := d.pullWorkspaceCodeDeclarations(workspaceToDirectory)
+	if err != nil {
+		return nil, fmt.Errorf("[d.pullWorkspaceCodeDeclarations] %v", err)
+	}
+
+	workspaceToDivisions := workspaceDivisions(workspaceToIDMap, divToIDMap)
+	divToResourceData := selectNewResources(workspaceToIDMap, excludeDeclaredResources(divToIDMap, divToIdentifiers, workspaceToDeclarations, workspaceToDivisions))
 
 	return divToResourceData, nil
 }
@@ -87,32 +113,39 @@ func isValidNewResource(resourceBlackList map[string]bool, tfrResource ResourceD
 	return true
 }
 
-// pullTerraformerResourceIdentifiers extracts identifiers for each unique resource instance within pulled terraformer generated state files.
-func (d *documentize) pullTerraformerResourceIdentifiers() (map[terraformValueObjects.Division]map[ResourceData]bool, error) {
+// pullTerraformerResourceIdentifiers extracts identifiers for each unique resource instance within pulled terraformer
+// generated state files, along with the value of the identifying attribute of each.
+func (d *documentize) pullTerraformerResourceIdentifiers() (
+	map[terraformValueObjects.Division]map[ResourceData]bool,
+	map[terraformValueObjects.Division]map[ResourceData]string,
+	error,
+) {
 	outputMap := map[terraformValueObjects.Division]map[ResourceData]bool{}
+	identifiersMap := map[terraformValueObjects.Division]map[ResourceData]string{}
 	for div, provider := range d.divisionToProvider {
 		tfStateBytes, err := os.ReadFile(fmt.Sprintf("current_cloud/%v-%v/terraform.tfstate", provider, div))
 		if err != nil {
-			return nil, fmt.Errorf("[os.ReadFile] Error reading in state file: %v", err)
+			return nil, nil, fmt.Errorf("[os.ReadFile] Error reading in state file: %v", err)
 		}
 
 		tfStateParsed, err := gabs.ParseJSON(tfStateBytes)
 		if err != nil {
-			return nil, fmt.Errorf("[gabs.ParseJSON] Error reading in state file: %v", err)
+			return nil, nil, fmt.Errorf("[gabs.ParseJSON] Error reading in state file: %v", err)
 		}
 
 		resourceDataSet, err := extractResourceIdsFromTerraformerState(tfStateParsed)
 
 		if err != nil {
-			return nil, fmt.Errorf("[extractResourceIdsFromWorkspaceState] Error in reading resource ids from workspace state: %v", err)
+			return nil, nil, fmt.Errorf("[extractResourceIdsFromWorkspaceState] Error in reading resource ids from workspace state: %v", err)
 		}
 
 		currentDiv := terraformValueObjects.Division(fmt.Sprintf("%v-%v", provider, div))
 
 		outputMap[currentDiv] = resourceDataSet
+		identifiersMap[currentDiv] = extractIdentifyingValuesFromTerraformerState(tfStateParsed)
 	}
 
-	return outputMap, nil
+	return outputMap, identifiersMap, nil
 }
 
 // extractResourceIdsFromWorkspaceState extracts identifying information for all resource instances
@@ -290,6 +323,13 @@ func (d *documentize) extractResourceDocument(tfStateParsed *gabs.Container, isA
 		}
 		return currentResourceSentence, nil
 
+	case "provider[\"registry.terraform.io/datadog/datadog\"]":
+		currentResourceSentence, err := d.resourceExtractors["datadog"].OutputResourceDetailsSentence(tfStateParsed, isAttributesFlat, i, j)
+		if err != nil {
+			return "", fmt.Errorf("[datadog.OutputResourceDetailsSentence] Error pulling details: %v", err)
+		}
+		return currentResourceSentence, nil
+
 	default:
 		resourceName := tfStateParsed.Search("resources", strconv.Itoa(i), "name").Data().(string)
 		fmt.Printf("Currently unsupported provider %v, skipping the resource: %v", currentTFProvider, resourceName)
@@ -0,0 +1,112 @@
+package gcpprojects
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/cloudresourcemanager/v3"
+	"google.golang.org/api/option"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+// resourceManager lists the direct children of a GCP organization or folder.
+type resourceManager interface {
+	// listActiveProjects returns the ids of the active projects directly within parent.
+	listActiveProjects(ctx context.Context, parent string) ([]string, error)
+
+	// listActiveFolders returns the resource names, e.g. folders/123, of the active folders directly within parent.
+	listActiveFolders(ctx context.Context, parent string) ([]string, error)
+}
+
+// newResourceManager returns the resourceManager authenticated with the passed division credential.
+var newResourceManager = func(ctx context.Context, credential terraformValueObjects.Credential) (resourceManager, error) {
+	options := make([]option.ClientOption, 0)
+	if !credential.IsAmbient() {
+		options = append(options, option.WithCredentialsJSON([]byte(credential)))
+	}
+
+	service, err := cloudresourcemanager.NewService(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+	return &cloudResourceManager{service: service}, nil
+}
+
+// DiscoverProjectDivisions enumerates every active project within parent, an organization (organizations/123) or
+// folder (folders/456), including projects within nested folders. Each project is returned as a division, named by
+// its project id, that is accessed with credential. Excluded project ids are skipped.
+func DiscoverProjectDivisions(ctx context.Context, credential terraformValueObjects.Credential, parent string, excludedProjects []string) (map[terraformValueObjects.Division]terraformValueObjects.Credential, error) {
+	if !strings.HasPrefix(parent, "organizations/") && !strings.HasPrefix(parent, "folders/") {
+		return nil, fmt.Errorf("[gcpprojects][parent %v must be of the form organizations/<id> or folders/<id>]", parent)
+	}
+
+	manager, err := newResourceManager(ctx, credential)
+	if err != nil {
+		return nil, fmt.Errorf("[gcpprojects][error creating resource manager client]%w", err)
+	}
+
+	excluded := map[string]bool{}
+	for _, projectID := range excludedProjects {
+		excluded[projectID] = true
+	}
+
+	divisions := map[terraformValueObjects.Division]terraformValueObjects.Credential{}
+	parents := []string{parent}
+	for len(parents) > 0 {
+		current := parents[0]
+		parents = parents[1:]
+
+		projectIDs, err := manager.listActiveProjects(ctx, current)
+		if err != nil {
+			return nil, fmt.Errorf("[gcpprojects][error listing projects within %v]%w", current, err)
+		}
+		for _, projectID := range projectIDs {
+			if !excluded[projectID] {
+				divisions[terraformValueObjects.Division(projectID)] = credential
+			}
+		}
+
+		folders, err := manager.listActiveFolders(ctx, current)
+		if err != nil {
+			return nil, fmt.Errorf("[gcpprojects][error listing folders within %v]%w", current, err)
+		}
+		parents = append(parents, folders...)
+	}
+
+	return divisions, nil
+}
+
+// cloudResourceManager implements resourceManager with the Cloud Resource Manager v3 API.
+type cloudResourceManager struct {
+	service *cloudresourcemanager.Service
+}
+
+// listActiveProjects returns the ids of the active projects directly within parent.
+func (m *cloudResourceManager) listActiveProjects(ctx context.Context, parent string) ([]string, error) {
+	projectIDs := make([]string, 0)
+	err := m.service.Projects.List().Parent(parent).Pages(ctx, func(response *cloudresourcemanager.ListProjectsResponse) error {
+		for _, project := range response.Projects {
+			if project.State == "ACTIVE" {
+				projectIDs = append(projectIDs, project.ProjectId)
+			}
+		}
+		return nil
+	})
+	return projectIDs, err
+}
+
+// listActiveFolders returns the resource names of the active folders directly within parent.
+func (m *cloudResourceManager) listActiveFolders(ctx context.Context, parent string) ([]string, error) {
+	folders := make([]string, 0)
+	err := m.service.Folders.List().Parent(parent).Pages(ctx, func(response *cloudresourcemanager.ListFoldersResponse) error {
+		for _, folder := range response.Folders {
+			if folder.State == "ACTIVE" {
+				folders = append(folders, folder.Name)
+			}
+		}
+		return nil
+	})
+	return folders, err
+}
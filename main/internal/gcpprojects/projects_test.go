@@ -0,0 +1,62 @@
+package gcpprojects
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+type mockResourceManager struct {
+	projects map[string][]string
+	folders  map[string][]string
+}
+
+func (m *mockResourceManager) listActiveProjects(_ context.Context, parent string) ([]string, error) {
+	return m.projects[parent], nil
+}
+
+func (m *mockResourceManager) listActiveFolders(_ context.Context, parent string) ([]string, error) {
+	return m.folders[parent], nil
+}
+
+func TestDiscoverProjectDivisions(t *testing.T) {
+	// Given
+	originalNewResourceManager := newResourceManager
+	newResourceManager = func(ctx context.Context, credential terraformValueObjects.Credential) (resourceManager, error) {
+		return &mockResourceManager{
+			projects: map[string][]string{
+				"organizations/123": {"shared-services"},
+				"folders/456":       {"payments-prod", "sandbox"},
+				"folders/789":       {"payments-dev"},
+			},
+			folders: map[string][]string{
+				"organizations/123": {"folders/456"},
+				"folders/456":       {"folders/789"},
+			},
+		}, nil
+	}
+	defer func() { newResourceManager = originalNewResourceManager }()
+
+	// When
+	divisions, err := DiscoverProjectDivisions(context.Background(), "{}", "organizations/123", []string{"sandbox"})
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, map[terraformValueObjects.Division]terraformValueObjects.Credential{
+		"shared-services": "{}",
+		"payments-prod":   "{}",
+		"payments-dev":    "{}",
+	}, divisions)
+}
+
+func TestDiscoverProjectDivisions_InvalidParent(t *testing.T) {
+	// When
+	_, err := DiscoverProjectDivisions(context.Background(), "{}", "123", nil)
+
+	// Then
+	assert.Error(t, err)
+}
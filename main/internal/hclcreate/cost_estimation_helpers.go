@@ -2,7 +2,6 @@ package hclcreate
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/Jeffail/gabs/v2"
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
@@ -48,17 +47,16 @@ type costComponent struct {
 	unit string
 }
 
-// gabsContainerToAllCostsStruct converts a gabs container to the allCosts struct.
-func gabsContainerToAllCostsStruct(c *gabs.Container) (allCosts, error) {
+// gabsContainerToAllCostsStruct converts a gabs container to the allCosts struct, keyed by the address of each
+// resource within the generated code.
+func gabsContainerToAllCostsStruct(c *gabs.Container, resourceNames ResourceNames) (allCosts, error) {
 	costs := allCosts{}
 
 	for division, entityArray := range c.ChildrenMap() {
 		divCosts := divisionCosts{}
 
 		for _, cost := range entityArray.Children() {
-			resourceName := cost.Search("resource_name").Data().(string)
-			intermediateString := strings.Replace(resourceName, "tfer--", "", -1)
-			resourceName = strings.Replace(intermediateString, "-", "_", -1)
+			resourceName := resourceNames.Address(division, cost.Search("resource_name").Data().(string))
 
 			var resourceCostEntry resourceCosts
 			existingResourceCost, ok := divCosts[terraformValueObjects.ResourceName(resourceName)]
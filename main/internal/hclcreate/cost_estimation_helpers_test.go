@@ -165,7 +165,7 @@ func Test_gabsContainerToAllCostsStruct(t *testing.T) {
 		},
 	}
 
-	output, err := gabsContainerToAllCostsStruct(inputContainer)
+	output, err := gabsContainerToAllCostsStruct(inputContainer, ResourceNames{})
 	if err != nil {
 		t.Errorf("unexpected error in gabsContainerToAllCostsStruct: %v", err)
 	}
@@ -0,0 +1,441 @@
+package hclcreate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/sensitiveattributes"
+)
+
+// defaultVariableExtractionPatterns match the attributes whose values, such as account ids and ARNs, are specific
+// to an environment rather than to the resource.
+var defaultVariableExtractionPatterns = []string{
+	`^(account_id|owner_id|.*_account_id)$`,
+	`(^|_)arn$`,
+	`^(project|project_id|subscription_id|tenant_id)$`,
+}
+
+// liftedVariablesFile and liftedValuesFile are the files of each workspace, owned by cloud-concierge, to which the
+// lifted variables and their values are written.
+const (
+	liftedVariablesFile = "lifted-variables.tf"
+	liftedValuesFile    = "lifted-variables.auto.tfvars"
+)
+
+// variableNameCharacters matches the characters not allowed within a Terraform variable name.
+var variableNameCharacters = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// extractedValue is a literal value within the generated resources lifted into a variable.
+type extractedValue struct {
+	// value is the literal value.
+	value string
+
+	// sensitive is true if any attribute to which the value is assigned is sensitive.
+	sensitive bool
+
+	// occurrences are the attributes to which the value is assigned.
+	occurrences []valueOccurrence
+}
+
+// valueOccurrence is an attribute of a generated resource assigned a lifted value.
+type valueOccurrence struct {
+	body      *hclwrite.Body
+	attribute string
+	address   string
+}
+
+// ExtractVariables lifts the literal values of the attributes matching the variable extraction patterns out of the
+// resources generated by this job within each workspace and into variables, declared within the workspace's
+// lifted-variables.tf. The values are written to the workspace's lifted-variables.auto.tfvars, apart from those of
+// sensitive attributes, which are to be supplied outside of version control. A value repeated across attributes is
+// lifted into a single variable. Resources merged by earlier jobs, and the user's own files, are left untouched.
+func (h *hclCreate) ExtractVariables(workspaceToDirectory map[string]string) error {
+	if !h.config.VariableExtraction {
+		return nil
+	}
+
+	extractionPatterns := h.config.VariableExtractionPatterns
+	if len(extractionPatterns) == 0 {
+		extractionPatterns = defaultVariableExtractionPatterns
+	}
+	compiledExtractionPatterns, err := compileAttributePatterns(extractionPatterns)
+	if err != nil {
+		return fmt.Errorf("[compileAttributePatterns] %v", err)
+	}
+	sensitivePatterns, err := sensitiveattributes.Compile(h.config.SensitiveAttributePatterns)
+	if err != nil {
+		return fmt.Errorf("[sensitiveattributes.Compile] %v", err)
+	}
+
+	workspaceToAddresses, err := h.generatedResourceAddresses()
+	if err != nil {
+		return fmt.Errorf("[h.generatedResourceAddresses] %v", err)
+	}
+
+	for workspace, directory := range workspaceToDirectory {
+		if len(workspaceToAddresses[workspace]) == 0 {
+			continue
+		}
+
+		err = extractWorkspaceVariables(
+			fmt.Sprintf("repo%v", directory), workspaceToAddresses[workspace], compiledExtractionPatterns, sensitivePatterns,
+		)
+		if err != nil {
+			return fmt.Errorf("[extractWorkspaceVariables] Error within %v: %v", directory, err)
+		}
+	}
+	return nil
+}
+
+// generatedResourceAddresses returns the type.name addresses of the resources generated by this job within each
+// workspace.
+func (h *hclCreate) generatedResourceAddresses() (map[string]map[string]bool, error) {
+	workspaceToAddresses := map[string]map[string]bool{}
+	if !h.artifactStore.Exists("mappings/new-resources-to-workspace.json") || !h.artifactStore.Exists("mappings/new-resources-to-names.json") {
+		return workspaceToAddresses, nil
+	}
+
+	content, err := h.artifactStore.ReadFile("mappings/new-resources-to-workspace.json")
+	if err != nil {
+		return nil, fmt.Errorf("[artifacts.ReadFile] mappings/new-resources-to-workspace.json error: %v", err)
+	}
+	newResourceToWorkspace := NewResourceToWorkspace{}
+	err = json.Unmarshal(content, &newResourceToWorkspace)
+	if err != nil {
+		return nil, fmt.Errorf("[json.Unmarshal] error unmarshalling `newResourceToWorkspace`: %v", err)
+	}
+
+	content, err = h.artifactStore.ReadFile("mappings/new-resources-to-names.json")
+	if err != nil {
+		return nil, fmt.Errorf("[artifacts.ReadFile] mappings/new-resources-to-names.json error: %v", err)
+	}
+	resourceNames := ResourceNames{}
+	err = json.Unmarshal(content, &resourceNames)
+	if err != nil {
+		return nil, fmt.Errorf("[json.Unmarshal] error unmarshalling `resourceNames`: %v", err)
+	}
+
+	for resource, workspace := range newResourceToWorkspace {
+		name, ok := resourceNames[resource]
+		if !ok {
+			continue
+		}
+		if _, ok := workspaceToAddresses[workspace]; !ok {
+			workspaceToAddresses[workspace] = map[string]bool{}
+		}
+		workspaceToAddresses[workspace][fmt.Sprintf("%v.%v", h.splitResourceIdentifier(resource).resourceType, name)] = true
+	}
+	return workspaceToAddresses, nil
+}
+
+// extractWorkspaceVariables lifts the values of the resources of generatedAddresses within a single workspace
+// directory, across each of its new resources files, into variables.
+func extractWorkspaceVariables(
+	directory string,
+	generatedAddresses map[string]bool,
+	extractionPatterns []*regexp.Regexp,
+	sensitivePatterns []*regexp.Regexp,
+) error {
+	newResourcesPaths, err := newResourcesPaths(directory)
+	if err != nil {
+		return fmt.Errorf("[newResourcesPaths] %v", err)
+	}
+
+	files := map[string]*hclwrite.File{}
+	values := []*extractedValue{}
+	valueIndex := map[string]*extractedValue{}
+	for _, newResourcesPath := range newResourcesPaths {
+		content, err := os.ReadFile(newResourcesPath)
+		if err != nil {
+			return fmt.Errorf("[os.ReadFile] Error reading %v: %v", newResourcesPath, err)
+		}
+
+		f, diags := hclwrite.ParseConfig(content, newResourcesPath, hcl.InitialPos)
+		if diags.HasErrors() {
+			return fmt.Errorf("[hclwrite.ParseConfig] Error parsing %v: %v", newResourcesPath, diags.Error())
+		}
+
+		occurrenceCount := countOccurrences(values)
+		for _, block := range f.Body().Blocks() {
+			if block.Type() != "resource" || len(block.Labels()) != 2 {
+				continue
+			}
+			address := strings.Join(block.Labels(), ".")
+			if !generatedAddresses[address] {
+				continue
+			}
+			collectExtractedValues(block.Body(), address, extractionPatterns, sensitivePatterns, &values, valueIndex)
+		}
+		if countOccurrences(values) > occurrenceCount {
+			files[newResourcesPath] = f
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	usedNames, err := declaredVariables(directory)
+	if err != nil {
+		return fmt.Errorf("[declaredVariables] %v", err)
+	}
+	names := extractedVariableNames(values, usedNames)
+
+	variablesFile := hclwrite.NewEmptyFile()
+	tfvarsFile := hclwrite.NewEmptyFile()
+	for index, value := range values {
+		name := names[index]
+		for _, occurrence := range value.occurrences {
+			occurrence.body.SetAttributeTraversal(occurrence.attribute, hcl.Traversal{
+				hcl.TraverseRoot{Name: "var"},
+				hcl.TraverseAttr{Name: name},
+			})
+		}
+
+		variablesFile.Body().AppendNewline()
+		variableBody := variablesFile.Body().AppendNewBlock("variable", []string{name}).Body()
+		variableBody.SetAttributeValue("description", cty.StringVal(variableDescription(value)))
+		variableBody.SetAttributeTraversal("type", hcl.Traversal{hcl.TraverseRoot{Name: "string"}})
+		if value.sensitive {
+			variableBody.SetAttributeValue("sensitive", cty.True)
+			tfvarsFile.Body().AppendUnstructuredTokens(commentTokens(fmt.Sprintf(
+				"# %v is sensitive, and to be supplied outside of version control, e.g. as TF_VAR_%v.", name, name,
+			)))
+			continue
+		}
+		tfvarsFile.Body().SetAttributeValue(name, cty.StringVal(value.value))
+	}
+
+	for newResourcesPath, f := range files {
+		err = replaceFile(newResourcesPath, hclwrite.Format(f.Bytes()), 0400)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = appendToFile(filepath.Join(directory, liftedVariablesFile), variablesFile.Bytes())
+	if err != nil {
+		return err
+	}
+
+	return appendToFile(filepath.Join(directory, liftedValuesFile), tfvarsFile.Bytes())
+}
+
+// collectExtractedValues collects the literal values of the attributes of body, and of its nested blocks, matching
+// the extraction or sensitive patterns.
+func collectExtractedValues(
+	body *hclwrite.Body,
+	address string,
+	extractionPatterns []*regexp.Regexp,
+	sensitivePatterns []*regexp.Regexp,
+	values *[]*extractedValue,
+	valueIndex map[string]*extractedValue,
+) {
+	attributes := body.Attributes()
+	attributeNames := make([]string, 0, len(attributes))
+	for name := range attributes {
+		attributeNames = append(attributeNames, name)
+	}
+	sort.Strings(attributeNames)
+
+	for _, name := range attributeNames {
+		sensitive := matchesAnyPattern(name, sensitivePatterns)
+		if !sensitive && !matchesAnyPattern(name, extractionPatterns) {
+			continue
+		}
+
+		value, ok := literalString(attributes[name])
+		if !ok || value == "" {
+			continue
+		}
+
+		extracted, ok := valueIndex[value]
+		if !ok {
+			extracted = &extractedValue{value: value}
+			valueIndex[value] = extracted
+			*values = append(*values, extracted)
+		}
+		extracted.sensitive = extracted.sensitive || sensitive
+		extracted.occurrences = append(extracted.occurrences, valueOccurrence{body: body, attribute: name, address: address})
+	}
+
+	for _, block := range body.Blocks() {
+		collectExtractedValues(block.Body(), address, extractionPatterns, sensitivePatterns, values, valueIndex)
+	}
+}
+
+// countOccurrences returns the number of attributes assigned any of the lifted values.
+func countOccurrences(values []*extractedValue) int {
+	count := 0
+	for _, value := range values {
+		count += len(value.occurrences)
+	}
+	return count
+}
+
+// literalString returns the value of an attribute assigned a string literal, without any interpolation.
+func literalString(attribute *hclwrite.Attribute) (string, bool) {
+	expression, diags := hclsyntax.ParseExpression(attribute.Expr().BuildTokens(nil).Bytes(), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return "", false
+	}
+
+	template, ok := expression.(*hclsyntax.TemplateExpr)
+	if !ok || !template.IsStringLiteral() {
+		return "", false
+	}
+
+	value, diags := template.Value(nil)
+	if diags.HasErrors() || !value.Type().Equals(cty.String) {
+		return "", false
+	}
+	return value.AsString(), true
+}
+
+// extractedVariableNames returns the name of the variable of each value. A value is named after its attribute when
+// no other value is assigned to an attribute of the same name, and after its resource and attribute otherwise.
+func extractedVariableNames(values []*extractedValue, usedNames map[string]bool) []string {
+	attributeValueCounts := map[string]int{}
+	for _, value := range values {
+		attributeValueCounts[value.occurrences[0].attribute]++
+	}
+
+	names := make([]string, 0, len(values))
+	for _, value := range values {
+		first := value.occurrences[0]
+		name := first.attribute
+		if attributeValueCounts[first.attribute] > 1 {
+			name = fmt.Sprintf("%v_%v", first.address[strings.Index(first.address, ".")+1:], first.attribute)
+		}
+
+		name = strings.Trim(variableNameCharacters.ReplaceAllString(strings.ToLower(name), "_"), "_")
+		if name == "" || (name[0] >= '0' && name[0] <= '9') {
+			name = "_" + name
+		}
+
+		candidate := name
+		for suffix := 2; usedNames[candidate]; suffix++ {
+			candidate = fmt.Sprintf("%v_%v", name, suffix)
+		}
+		usedNames[candidate] = true
+		names = append(names, candidate)
+	}
+	return names
+}
+
+// variableDescription describes the attributes from which a value was lifted.
+func variableDescription(value *extractedValue) string {
+	references := make([]string, 0, len(value.occurrences))
+	seen := map[string]bool{}
+	for _, occurrence := range value.occurrences {
+		reference := fmt.Sprintf("%v.%v", occurrence.address, occurrence.attribute)
+		if !seen[reference] {
+			seen[reference] = true
+			references = append(references, reference)
+		}
+	}
+	return fmt.Sprintf("Lifted by cloud-concierge from %v.", strings.Join(references, ", "))
+}
+
+// declaredVariables returns the names of the variables already declared within the .tf files of a directory.
+func declaredVariables(directory string) (map[string]bool, error) {
+	paths, err := filepath.Glob(filepath.Join(directory, "*.tf"))
+	if err != nil {
+		return nil, fmt.Errorf("[filepath.Glob] %v", err)
+	}
+
+	names := map[string]bool{}
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("[os.ReadFile] Error reading %v: %v", path, err)
+		}
+
+		f, diags := hclwrite.ParseConfig(content, path, hcl.InitialPos)
+		if diags.HasErrors() {
+			continue
+		}
+		for _, block := range f.Body().Blocks() {
+			if block.Type() == "variable" && len(block.Labels()) == 1 {
+				names[block.Labels()[0]] = true
+			}
+		}
+	}
+	return names, nil
+}
+
+// appendToFile appends content to the file at path, creating it as read only if it does not yet exist.
+func appendToFile(path string, content []byte) error {
+	existing, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return replaceFile(path, bytes.TrimLeft(content, "\n"), 0400)
+	}
+	if err != nil {
+		return fmt.Errorf("[os.ReadFile] Error reading %v: %v", path, err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("[os.Stat] %v", err)
+	}
+
+	if len(existing) > 0 && !bytes.HasSuffix(existing, []byte("\n")) {
+		existing = append(existing, '\n')
+	}
+	return replaceFile(path, append(existing, content...), info.Mode().Perm())
+}
+
+// replaceFile writes content to the file at path, replacing rather than truncating any existing file, as files within
+// the repository may be read only.
+func replaceFile(path string, content []byte, perm os.FileMode) error {
+	err := os.Remove(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("[os.Remove] Error removing %v: %v", path, err)
+	}
+
+	err = os.WriteFile(path, content, perm)
+	if err != nil {
+		return fmt.Errorf("[os.WriteFile] Error writing %v: %v", path, err)
+	}
+	return nil
+}
+
+// compileAttributePatterns compiles patterns for attribute names into case-insensitive regular expressions.
+func compileAttributePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+
+		expression, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, fmt.Errorf("[invalid pattern %v] %v", pattern, err)
+		}
+		compiled = append(compiled, expression)
+	}
+	return compiled, nil
+}
+
+// matchesAnyPattern returns true if name matches any of the patterns.
+func matchesAnyPattern(name string, patterns []*regexp.Regexp) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
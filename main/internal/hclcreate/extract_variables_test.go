@@ -0,0 +1,170 @@
+package hclcreate
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+)
+
+func TestExtractVariables(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	require.NoError(t, os.MkdirAll("mappings", 0700))
+	require.NoError(t, os.WriteFile("mappings/new-resources-to-workspace.json", []byte(`{
+		"aws-prod.aws_db_instance.tfer--orders": "app",
+		"aws-prod.aws_lambda_function.tfer--worker": "app",
+		"aws-prod.aws_sns_topic_subscription.tfer--alerts": "app"
+	}`), 0400))
+	require.NoError(t, os.WriteFile("mappings/new-resources-to-names.json", []byte(`{
+		"aws-prod.aws_db_instance.tfer--orders": "orders",
+		"aws-prod.aws_lambda_function.tfer--worker": "worker",
+		"aws-prod.aws_sns_topic_subscription.tfer--alerts": "alerts"
+	}`), 0400))
+
+	require.NoError(t, os.MkdirAll("repo/app", 0700))
+	require.NoError(t, os.WriteFile("repo/app/new-resources-s3.tf", []byte(`resource "aws_s3_bucket" "merged" {
+  bucket     = "merged"
+  policy_arn = "arn:aws:iam::123456789012:policy/merged"
+}
+`), 0400))
+	require.NoError(t, os.WriteFile("repo/app/new-resources.tf", []byte(`resource "aws_db_instance" "orders" {
+  password = "hunter2hunter2"
+  engine   = "postgres"
+}
+
+resource "aws_lambda_function" "worker" {
+  role = "arn:aws:iam::123456789012:role/worker"
+
+  dead_letter_config {
+    target_arn = "arn:aws:sqs:us-east-1:123456789012:dlq"
+  }
+}
+
+resource "aws_sns_topic_subscription" "alerts" {
+  topic_arn = "arn:aws:sns:us-east-1:123456789012:alerts"
+  role_arn  = "arn:aws:iam::123456789012:role/worker"
+}
+`), 0400))
+	userVariables := []byte(`variable "role_arn" {
+  type = string
+}
+`)
+	require.NoError(t, os.WriteFile("repo/app/variables.tf", userVariables, 0600))
+
+	h := hclCreate{artifactStore: artifacts.NewMemoryStore(true), config: Config{VariableExtraction: true, VariableExtractionPatterns: []string{`_arn$`, "^role$"}}}
+
+	// When
+	err := h.ExtractVariables(map[string]string{"app": "/app/"})
+
+	// Then
+	require.NoError(t, err)
+	newResources, err := os.ReadFile("repo/app/new-resources.tf")
+	require.NoError(t, err)
+	assert.Equal(t, `resource "aws_db_instance" "orders" {
+  password = var.password
+  engine   = "postgres"
+}
+
+resource "aws_lambda_function" "worker" {
+  role = var.role
+
+  dead_letter_config {
+    target_arn = var.target_arn
+  }
+}
+
+resource "aws_sns_topic_subscription" "alerts" {
+  topic_arn = var.topic_arn
+  role_arn  = var.role
+}
+`, string(newResources))
+
+	merged, err := os.ReadFile("repo/app/new-resources-s3.tf")
+	require.NoError(t, err)
+	assert.Contains(t, string(merged), `policy_arn = "arn:aws:iam::123456789012:policy/merged"`)
+
+	variables, err := os.ReadFile("repo/app/variables.tf")
+	require.NoError(t, err)
+	assert.Equal(t, userVariables, variables)
+
+	variables, err = os.ReadFile("repo/app/lifted-variables.tf")
+	require.NoError(t, err)
+	assert.Equal(t, `variable "password" {
+  description = "Lifted by cloud-concierge from aws_db_instance.orders.password."
+  type        = string
+  sensitive   = true
+}
+
+variable "role" {
+  description = "Lifted by cloud-concierge from aws_lambda_function.worker.role, aws_sns_topic_subscription.alerts.role_arn."
+  type        = string
+}
+
+variable "target_arn" {
+  description = "Lifted by cloud-concierge from aws_lambda_function.worker.target_arn."
+  type        = string
+}
+
+variable "topic_arn" {
+  description = "Lifted by cloud-concierge from aws_sns_topic_subscription.alerts.topic_arn."
+  type        = string
+}
+`, string(variables))
+
+	assert.NoFileExists(t, "repo/app/terraform.tfvars")
+	tfvars, err := os.ReadFile("repo/app/lifted-variables.auto.tfvars")
+	require.NoError(t, err)
+	assert.Equal(t, `# password is sensitive, and to be supplied outside of version control, e.g. as TF_VAR_password.
+role       = "arn:aws:iam::123456789012:role/worker"
+target_arn = "arn:aws:sqs:us-east-1:123456789012:dlq"
+topic_arn  = "arn:aws:sns:us-east-1:123456789012:alerts"
+`, string(tfvars))
+}
+
+func TestExtractVariables_Disabled(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	require.NoError(t, os.MkdirAll("repo/app", 0700))
+	newResources := []byte(`resource "aws_iam_role_policy_attachment" "ci" {
+  policy_arn = "arn:aws:iam::aws:policy/ReadOnlyAccess"
+}
+`)
+	require.NoError(t, os.WriteFile("repo/app/new-resources.tf", newResources, 0400))
+
+	h := hclCreate{}
+
+	// When
+	err := h.ExtractVariables(map[string]string{"app": "/app/"})
+
+	// Then
+	require.NoError(t, err)
+	content, err := os.ReadFile("repo/app/new-resources.tf")
+	require.NoError(t, err)
+	assert.Equal(t, newResources, content)
+	assert.NoFileExists(t, "repo/app/lifted-variables.tf")
+}
+
+func TestExtractedVariableNames(t *testing.T) {
+	// Given
+	values := []*extractedValue{
+		{value: "a", occurrences: []valueOccurrence{{attribute: "kms_key_arn", address: "aws_s3_bucket.logs"}}},
+		{value: "b", occurrences: []valueOccurrence{{attribute: "kms_key_arn", address: "aws_sqs_queue.orders"}}},
+		{value: "c", occurrences: []valueOccurrence{{attribute: "account_id", address: "aws_iam_role.ci"}}},
+	}
+
+	// When
+	names := extractedVariableNames(values, map[string]bool{"account_id": true})
+
+	// Then
+	assert.Equal(t, []string{"logs_kms_key_arn", "orders_kms_key_arn", "account_id_2"}, names)
+}
@@ -3,10 +3,10 @@ package hclcreate
 import (
 	"encoding/json"
 	"fmt"
-	"strings"
 
 	"github.com/hashicorp/hcl/v2/hclwrite"
 
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
 )
 
@@ -53,6 +53,47 @@ type Config struct {
 
 	// TerraformVersion is the version of Terraform used.
 	TerraformVersion string `required:"true"`
+
+	// VariableExtraction, when true, lifts environment-specific and sensitive values out of the resources generated
+	// by the job and into variables.
+	VariableExtraction bool
+
+	// VariableExtractionPatterns are regular expressions, matched case-insensitively against attribute names, for
+	// attributes whose values are lifted into variables. When set, they replace the default account id, ARN and
+	// project, subscription and tenant id patterns.
+	VariableExtractionPatterns []string
+
+	// SensitiveAttributePatterns are regular expressions, matched case-insensitively against attribute names, for
+	// attributes whose values are lifted into sensitive variables, in addition to the shared sensitive attribute
+	// patterns.
+	SensitiveAttributePatterns []string
+
+	// IgnoreChanges maps resource types to the attributes listed within the lifecycle ignore_changes of their
+	// generated resource blocks, so that imported resources do not produce plans for attributes managed elsewhere.
+	IgnoreChanges IgnoreChangesDecoder
+
+	// ModuleWrapping, when true, generates the new resources of each workspace within a child module per service,
+	// called from the workspace's new-resources.tf, rather than as flat resource blocks.
+	ModuleWrapping bool
+
+	// NewWorkspaceBackend is the template of the backend block written within the main.tf of each new workspace.
+	NewWorkspaceBackend BackendTemplateDecoder
+
+	// ImportOutput selects how new resources are imported, one of "auto", "import-blocks", "tfmigrate" or "script".
+	// auto writes import blocks for Terraform 1.5.0 or higher, and tfmigrate migrations otherwise.
+	ImportOutput string
+
+	// GeneratedDirectory is the subdirectory of each workspace within which supporting files, such as import blocks,
+	// migrations and child modules, are generated. Defaults to cloud-concierge.
+	GeneratedDirectory string
+
+	// NewResourcesLayout selects how the new resources of each workspace are split into files, one of "single",
+	// "service", "type" or "division".
+	NewResourcesLayout string
+
+	// DivisionRoleARNs maps aws divisions to the ARN of the role assumed to access them, which the aliased provider
+	// blocks of their new resources assume in turn.
+	DivisionRoleARNs map[string]string
 }
 
 // NewResourceToWorkspace is a map of resource unique id to workspace name
@@ -77,7 +118,7 @@ type HCLCreate interface {
 	// CreateMainTF outputs a bytes slice which defines a baseline main.tf file.
 	CreateMainTF(providers map[string]string) ([]byte, error)
 
-	// CreateImports creates either import blocks or tfmigrate configuration to import resources
+	// CreateImports creates either import blocks, tfmigrate configuration or an import script to import resources
 	// into Terraform state.
 	CreateImports(uniqueID string, workspaceToDirectory map[string]string) error
 
@@ -102,6 +143,33 @@ type HCLCreate interface {
 
 	// WriteImportBlocks writes import blocks to .tf files for configurations using Terraform version 1.5.0 or higher.
 	WriteImportBlocks(uniqueID string, workspaceToDirectory map[string]string) error
+
+	// WriteImportScript writes a shell script of terraform import commands for each workspace with new resources.
+	WriteImportScript(uniqueID string, workspaceToDirectory map[string]string) error
+
+	// WriteDriftRemediation writes suggested HCL patches for drifted resources to the remediation/ subdirectory of the
+	// generated directory.
+	WriteDriftRemediation(workspaceToDirectory map[string]string) error
+
+	// CreateNewWorkspaces creates the directories and main.tf files of proposed new workspaces, and returns
+	// workspaceToDirectory extended by them.
+	CreateNewWorkspaces(workspaceToDirectory map[string]string) (map[string]string, error)
+
+	// WriteWorkspaceMoves writes moved blocks and tfmigrate migrations reorganizing already-managed resources into
+	// the workspace or module path within which they belong.
+	WriteWorkspaceMoves(uniqueID string, workspaceToDirectory map[string]string) error
+
+	// WriteRemovedBlocks writes removed blocks or terraform state rm scripts reconciling the state of each workspace
+	// with the resources deleted outside of Terraform.
+	WriteRemovedBlocks(uniqueID string, workspaceToDirectory map[string]string) error
+
+	// ExtractVariables lifts environment-specific and sensitive values out of the resources generated within each
+	// workspace and into variables, along with a tfvars file of their values, when variable extraction is enabled.
+	ExtractVariables(workspaceToDirectory map[string]string) error
+
+	// WrapNewResourcesInModules moves the new resources of each workspace into a child module per service when
+	// module wrapping is enabled.
+	WrapNewResourcesInModules(workspaceToDirectory map[string]string) error
 }
 
 // hclCreate implements the HCLCreate interface.
@@ -112,13 +180,17 @@ type hclCreate struct {
 	// divisionToProvider is a mapping between a division and the provider that is responsible
 	// for that division.
 	divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider `required:"true"`
+
+	// artifactStore holds the mappings read and written while generating Terraform code.
+	artifactStore artifacts.Store
 }
 
 // NewHCLCreate creates and returns a struct which implements the HCLCreate interface.
-func NewHCLCreate(config Config, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider) (HCLCreate, error) {
+func NewHCLCreate(config Config, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider, artifactStore artifacts.Store) (HCLCreate, error) {
 	return &hclCreate{
 		config:             config,
 		divisionToProvider: divisionToProvider,
+		artifactStore:      artifactStore,
 	}, nil
 }
 
@@ -158,28 +230,35 @@ func (mhd *MigrationHistory) Decode(value string) error {
 	return nil
 }
 
-// CreateImports creates either import blocks or tfmigrate configuration to import resources into Terraform state.
+// CreateImports creates either import blocks, tfmigrate configuration or an import script to import resources into
+// Terraform state, as selected by the import output.
 func (h *hclCreate) CreateImports(uniqueID string, workspaceToDirectory map[string]string) error {
-	if h.config.TerraformVersion >= "1.5.0" {
+	importOutput := h.config.ImportOutput
+	if importOutput == "" || importOutput == ImportOutputAuto {
+		importOutput = ImportOutputTFMigrate
+		if terraformVersionAtLeast(h.config.TerraformVersion, "1.5.0") {
+			importOutput = ImportOutputBlocks
+		}
+	}
+
+	switch importOutput {
+	case ImportOutputBlocks:
 		err := h.WriteImportBlocks(uniqueID, workspaceToDirectory)
 		if err != nil {
 			return fmt.Errorf("error creating import blocks: %v", err)
 		}
-	} else {
+	case ImportOutputTFMigrate:
 		err := h.CreateTFMigrate(uniqueID, workspaceToDirectory)
 		if err != nil {
 			return fmt.Errorf("error creating tfmigrate configuration: %v", err)
 		}
+	case ImportOutputScript:
+		err := h.WriteImportScript(uniqueID, workspaceToDirectory)
+		if err != nil {
+			return fmt.Errorf("error creating import script: %v", err)
+		}
+	default:
+		return fmt.Errorf("unknown import output %v, expected one of auto, import-blocks, tfmigrate or script", importOutput)
 	}
 	return nil
 }
-
-// ConvertTerraformerResourceName takes an input resource name as output by the Terraformer
-// package and outputs the resource in gold-standard Terraform name format.
-func ConvertTerraformerResourceName(name string) string {
-	intermediateString := strings.Replace(name, "tfer--", "", -1)
-
-	finalResourceName := strings.Replace(intermediateString, "-", "_", -1)
-
-	return finalResourceName
-}
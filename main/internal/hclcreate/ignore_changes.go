@@ -0,0 +1,60 @@
+package hclcreate
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// IgnoreChangesDecoder is a map between resource types and the attributes of those resources to be listed within
+// the lifecycle ignore_changes of each generated resource block, e.g. the desired_capacity of an
+// aws_autoscaling_group, which is managed outside of Terraform.
+type IgnoreChangesDecoder map[string][]string
+
+// Decode provides the object decoding logic for IgnoreChangesDecoder, in accordance with the envconfig
+// package's requirements.
+func (d *IgnoreChangesDecoder) Decode(value string) error {
+	if strings.Trim(value, " ") == "" {
+		return nil
+	}
+
+	ignoreChanges := map[string][]string{}
+	err := json.Unmarshal([]byte(value), &ignoreChanges)
+	if err != nil {
+		return fmt.Errorf("expected ignore changes formatted as a json object of resource types to lists of attributes: %v", err)
+	}
+
+	for resourceType, attributes := range ignoreChanges {
+		for _, attribute := range attributes {
+			if !hclsyntax.ValidIdentifier(attribute) {
+				return fmt.Errorf("the ignore changes attribute %q of %v is not a valid attribute name", attribute, resourceType)
+			}
+		}
+	}
+
+	*d = ignoreChanges
+	return nil
+}
+
+// setIgnoreChanges sets the lifecycle ignore_changes of the resource block to the attributes configured for its
+// resource type.
+func (d IgnoreChangesDecoder) setIgnoreChanges(block *hclwrite.Block) {
+	labels := block.Labels()
+	if len(labels) == 0 || len(d[labels[0]]) == 0 {
+		return
+	}
+
+	lifecycle := block.Body().FirstMatchingBlock("lifecycle", nil)
+	if lifecycle == nil {
+		lifecycle = block.Body().AppendNewBlock("lifecycle", nil)
+	}
+
+	attributes := make([]hclwrite.Tokens, 0, len(d[labels[0]]))
+	for _, attribute := range d[labels[0]] {
+		attributes = append(attributes, hclwrite.TokensForIdentifier(attribute))
+	}
+	lifecycle.Body().SetAttributeRaw("ignore_changes", hclwrite.TokensForTuple(attributes))
+}
@@ -0,0 +1,59 @@
+package hclcreate
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIgnoreChangesDecoder_Decode(t *testing.T) {
+	// Given
+	var ignoreChanges IgnoreChangesDecoder
+
+	// When
+	err := ignoreChanges.Decode(`{"aws_autoscaling_group": ["desired_capacity", "max_size"]}`)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, IgnoreChangesDecoder{"aws_autoscaling_group": {"desired_capacity", "max_size"}}, ignoreChanges)
+	assert.Error(t, ignoreChanges.Decode(`{"aws_autoscaling_group": ["tags[\"team\"]"]}`))
+	assert.Error(t, ignoreChanges.Decode(`["desired_capacity"]`))
+}
+
+func TestSetIgnoreChanges(t *testing.T) {
+	// Given
+	f, diags := hclwrite.ParseConfig([]byte(`resource "aws_autoscaling_group" "workers" {
+  desired_capacity = 3
+  max_size         = 10
+}
+
+resource "aws_launch_template" "workers" {
+  name = "workers"
+}
+`), "", hcl.InitialPos)
+	require.False(t, diags.HasErrors())
+
+	ignoreChanges := IgnoreChangesDecoder{"aws_autoscaling_group": {"desired_capacity", "max_size"}}
+
+	// When
+	for _, block := range f.Body().Blocks() {
+		ignoreChanges.setIgnoreChanges(block)
+	}
+
+	// Then
+	assert.Equal(t, `resource "aws_autoscaling_group" "workers" {
+  desired_capacity = 3
+  max_size         = 10
+  lifecycle {
+    ignore_changes = [desired_capacity, max_size]
+  }
+}
+
+resource "aws_launch_template" "workers" {
+  name = "workers"
+}
+`, string(hclwrite.Format(f.Bytes())))
+}
@@ -0,0 +1,559 @@
+package hclcreate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+)
+
+// ResourceModules maps each new resource, identified as division.type.terraformer-name, to the child module within
+// which the resource is generated when module wrapping is enabled.
+type ResourceModules map[string]string
+
+// resourceAddress returns the address of a resource of the specified type and name within the generated Terraform
+// code of its workspace.
+func (m ResourceModules) resourceAddress(resource string, resourceType string, name string) string {
+	if module, ok := m[resource]; ok {
+		return fmt.Sprintf("module.%v.%v.%v", module, resourceType, name)
+	}
+	return fmt.Sprintf("%v.%v", resourceType, name)
+}
+
+// resourceService returns the service of a resource type, e.g. s3 for aws_s3_bucket.
+func resourceService(resourceType string) string {
+	typeSlice := strings.Split(resourceType, "_")
+	if len(typeSlice) < 2 {
+		return resourceType
+	}
+	return typeSlice[1]
+}
+
+// resourceModules returns the child module of each new resource when module wrapping is enabled. The resources of
+// a workspace are grouped into a module per service, where services whose resources reference one another share
+// a single module, named after the first of its services, so that the references remain valid. Modules whose
+// resources reference locals, data sources or resources outside of the module are left unwrapped within the root
+// module, as those references are not visible from within a child module.
+func (h *hclCreate) resourceModules(
+	workspaceToHCLFile WorkspaceToHCL,
+	newResourceToWorkspace NewResourceToWorkspace,
+	resourceNames ResourceNames,
+) ResourceModules {
+	resourceModules := ResourceModules{}
+	if !h.config.ModuleWrapping {
+		return resourceModules
+	}
+
+	workspaceToResources := map[string][]string{}
+	for resource, workspace := range newResourceToWorkspace {
+		workspaceToResources[workspace] = append(workspaceToResources[workspace], resource)
+	}
+
+	for workspace, resources := range workspaceToResources {
+		sort.Strings(resources)
+
+		// services are grouped with a union-find, keyed by the service name.
+		parents := map[string]string{}
+		var find func(service string) string
+		find = func(service string) string {
+			if parents[service] == service {
+				return service
+			}
+			parents[service] = find(parents[service])
+			return parents[service]
+		}
+		union := func(first string, second string) {
+			firstRoot, secondRoot := find(first), find(second)
+			if firstRoot < secondRoot {
+				parents[secondRoot] = firstRoot
+			} else {
+				parents[firstRoot] = secondRoot
+			}
+		}
+
+		addressToService := map[string]string{}
+		for _, resource := range resources {
+			resourceID := h.splitResourceIdentifier(resource)
+			service := resourceService(resourceID.resourceType)
+			parents[service] = service
+			addressToService[fmt.Sprintf("%v.%v", resourceID.resourceType, resourceNames[resource])] = service
+		}
+
+		externalReferences := map[string]bool{}
+		hclFile, ok := workspaceToHCLFile[workspace]
+		if ok {
+			for _, block := range hclFile.Body().Blocks() {
+				if block.Type() != "resource" || len(block.Labels()) != 2 {
+					continue
+				}
+				service, ok := addressToService[strings.Join(block.Labels(), ".")]
+				if !ok {
+					continue
+				}
+				for _, reference := range blockReferences(block.Body()) {
+					if referencedService, ok := addressToService[reference]; ok {
+						union(service, referencedService)
+					} else if isRootModuleReference(reference) {
+						externalReferences[service] = true
+					}
+				}
+			}
+		}
+
+		unwrappedModules := map[string]bool{}
+		for service := range externalReferences {
+			unwrappedModules[find(service)] = true
+		}
+
+		for _, resource := range resources {
+			module := find(resourceService(h.splitResourceIdentifier(resource).resourceType))
+			if !unwrappedModules[module] {
+				resourceModules[resource] = module
+			}
+		}
+	}
+
+	return resourceModules
+}
+
+// blockReferences returns the root.attribute prefix of each traversal within the attributes of body and of its
+// nested blocks, e.g. aws_vpc.main for aws_vpc.main.id, or var.region for var.region.
+func blockReferences(body *hclwrite.Body) []string {
+	references := []string{}
+	for _, attribute := range body.Attributes() {
+		for _, traversal := range attribute.Expr().Variables() {
+			traversalSlice := strings.Split(strings.TrimSpace(string(traversal.BuildTokens(nil).Bytes())), ".")
+			if len(traversalSlice) < 2 {
+				continue
+			}
+			references = append(references, fmt.Sprintf("%v.%v", strings.TrimSpace(traversalSlice[0]), strings.TrimSpace(traversalSlice[1])))
+		}
+	}
+	for _, block := range body.Blocks() {
+		references = append(references, blockReferences(block.Body())...)
+	}
+	return references
+}
+
+// moduleScopedReferences are the roots of references that remain valid within a child module, as opposed to
+// locals, data sources, module outputs and resources, which are only visible within the module declaring them.
+// Variables are passed through to the child module as inputs.
+var moduleScopedReferences = map[string]bool{
+	"var":       true,
+	"count":     true,
+	"each":      true,
+	"self":      true,
+	"path":      true,
+	"terraform": true,
+}
+
+// isRootModuleReference returns true if reference, of the form root.attribute, refers to an object of the root
+// module that a child module cannot reference, e.g. local.tags, data.aws_caller_identity.current or aws_vpc.main.
+func isRootModuleReference(reference string) bool {
+	root, _, _ := strings.Cut(reference, ".")
+	return !moduleScopedReferences[root]
+}
+
+// writeResourceModules writes the child module of each new resource to mappings/new-resources-to-modules.json.
+func writeResourceModules(store artifacts.Store, resourceModules ResourceModules) error {
+	content, err := json.MarshalIndent(resourceModules, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[json.MarshalIndent] error marshalling `resourceModules`: %v", err)
+	}
+
+	err = store.WriteFile("mappings/new-resources-to-modules.json", content, 0400)
+	if err != nil {
+		return fmt.Errorf("[artifacts.WriteFile] Error writing mappings/new-resources-to-modules.json: %v", err)
+	}
+	return nil
+}
+
+// loadResourceModules reads the child module of each new resource, which is empty without module wrapping.
+func loadResourceModules(store artifacts.Store) (ResourceModules, error) {
+	resourceModules := ResourceModules{}
+	if !store.Exists("mappings/new-resources-to-modules.json") {
+		return resourceModules, nil
+	}
+
+	content, err := store.ReadFile("mappings/new-resources-to-modules.json")
+	if err != nil {
+		return nil, fmt.Errorf("[artifacts.ReadFile] mappings/new-resources-to-modules.json error: %v", err)
+	}
+
+	err = json.Unmarshal(content, &resourceModules)
+	if err != nil {
+		return nil, fmt.Errorf("[json.Unmarshal] error unmarshalling `resourceModules`: %v", err)
+	}
+	return resourceModules, nil
+}
+
+// WrapNewResourcesInModules moves the new resources of each workspace into child modules within the modules/
+// subdirectory of the generated directory, called from the workspace's new-resources.tf, when module wrapping is
+// enabled.
+func (h *hclCreate) WrapNewResourcesInModules(workspaceToDirectory map[string]string) error {
+	if !h.config.ModuleWrapping {
+		return nil
+	}
+
+	resourceModules, err := loadResourceModules(h.artifactStore)
+	if err != nil {
+		return fmt.Errorf("[loadResourceModules] %v", err)
+	}
+
+	addressToModule := map[string]string{}
+	resourceNames, err := h.resourceNames(newResourcesOf(resourceModules))
+	if err != nil {
+		return fmt.Errorf("[h.resourceNames] %v", err)
+	}
+	for resource, module := range resourceModules {
+		resourceID := h.splitResourceIdentifier(resource)
+		addressToModule[fmt.Sprintf("%v.%v", resourceID.resourceType, resourceNames[resource])] = module
+	}
+
+	for _, directory := range workspaceToDirectory {
+		err = wrapWorkspaceResources(fmt.Sprintf("repo%v", directory), h.generatedDirectory(), addressToModule)
+		if err != nil {
+			return fmt.Errorf("[wrapWorkspaceResources] %v", err)
+		}
+	}
+
+	return nil
+}
+
+// newResourcesOf returns the resources of resourceModules as a NewResourceToWorkspace, without workspaces.
+func newResourcesOf(resourceModules ResourceModules) NewResourceToWorkspace {
+	newResources := NewResourceToWorkspace{}
+	for resource := range resourceModules {
+		newResources[resource] = ""
+	}
+	return newResources
+}
+
+// wrappedModule comprises the content of a child module generated from the new resources of a workspace.
+type wrappedModule struct {
+	// resources are the resource blocks of the module.
+	resources []*hclwrite.Block
+
+	// resourceComments are the comments preceding each of resources, e.g. its cost and cloud actor comments.
+	resourceComments []hclwrite.Tokens
+
+	// variables are the names of the variables referenced by the resources of the module.
+	variables map[string]bool
+
+	// providerAliases are the aliased provider configurations, e.g. aws.prod_us_east_1, used by the resources of
+	// the module.
+	providerAliases map[string]bool
+}
+
+// wrapWorkspaceResources moves the resource blocks of the new resources files within directory into their child
+// modules within generatedDirectory, and replaces them with a module call for each child module within
+// new-resources.tf. New resources files left without blocks are removed.
+func wrapWorkspaceResources(directory string, generatedDirectory string, addressToModule map[string]string) error {
+	paths, err := newResourcesPaths(directory)
+	if err != nil {
+		return fmt.Errorf("[newResourcesPaths] %v", err)
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+
+	rootVariables, err := variableBlocks(directory)
+	if err != nil {
+		return fmt.Errorf("[variableBlocks] %v", err)
+	}
+
+	modules := map[string]*wrappedModule{}
+	rootFiles := map[string]*hclwrite.File{}
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("[os.ReadFile] Error reading %v: %v", path, err)
+		}
+
+		newResourcesFile, diags := hclwrite.ParseConfig(content, path, hcl.InitialPos)
+		if diags.HasErrors() {
+			return fmt.Errorf("[hclwrite.ParseConfig] Error parsing %v: %v", path, diags.Error())
+		}
+
+		comments := detachedComments(content, newResourcesFile.Body().Blocks())
+
+		rootFiles[path] = hclwrite.NewEmptyFile()
+		for i, block := range newResourcesFile.Body().Blocks() {
+			module, ok := addressToModule[strings.Join(block.Labels(), ".")]
+			if block.Type() != "resource" || !ok {
+				rootFiles[path].Body().AppendUnstructuredTokens(comments[i])
+				rootFiles[path].Body().AppendBlock(block)
+				rootFiles[path].Body().AppendNewline()
+				continue
+			}
+
+			if _, ok := modules[module]; !ok {
+				modules[module] = &wrappedModule{variables: map[string]bool{}, providerAliases: map[string]bool{}}
+			}
+			modules[module].resources = append(modules[module].resources, block)
+			modules[module].resourceComments = append(modules[module].resourceComments, comments[i])
+
+			for _, reference := range blockReferences(block.Body()) {
+				if strings.HasPrefix(reference, "var.") {
+					modules[module].variables[strings.TrimPrefix(reference, "var.")] = true
+				}
+			}
+			if provider := block.Body().GetAttribute("provider"); provider != nil {
+				modules[module].providerAliases[strings.TrimSpace(string(provider.Expr().BuildTokens(nil).Bytes()))] = true
+			}
+		}
+	}
+	if len(modules) == 0 {
+		return nil
+	}
+
+	newResourcesPath := fmt.Sprintf("%vnew-resources.tf", directory)
+	moduleNames := make([]string, 0, len(modules))
+	for module := range modules {
+		moduleNames = append(moduleNames, module)
+	}
+	sort.Strings(moduleNames)
+
+	for _, module := range moduleNames {
+		moduleDirectory := fmt.Sprintf("%v%v/modules/%v", directory, generatedDirectory, module)
+		err = os.MkdirAll(moduleDirectory, 0400)
+		if err != nil {
+			return fmt.Errorf("[os.MkdirAll] error making directory %v: %v", moduleDirectory, err)
+		}
+
+		mainPath := fmt.Sprintf("%v/main.tf", moduleDirectory)
+		err = os.WriteFile(mainPath, modules[module].mainTF(), 0400)
+		if err != nil {
+			return fmt.Errorf("[os.WriteFile] Error writing %v: %v", mainPath, err)
+		}
+
+		if len(modules[module].variables) > 0 {
+			variablesPath := fmt.Sprintf("%v/variables.tf", moduleDirectory)
+			err = os.WriteFile(variablesPath, modules[module].variablesTF(rootVariables), 0400)
+			if err != nil {
+				return fmt.Errorf("[os.WriteFile] Error writing %v: %v", variablesPath, err)
+			}
+		}
+
+		if _, ok := rootFiles[newResourcesPath]; !ok {
+			rootFiles[newResourcesPath] = hclwrite.NewEmptyFile()
+		}
+		modules[module].appendModuleCall(rootFiles[newResourcesPath].Body(), generatedDirectory, module)
+	}
+
+	for path, rootFile := range rootFiles {
+		if len(rootFile.Body().Blocks()) == 0 {
+			err = os.Remove(path)
+			if err != nil {
+				return fmt.Errorf("[os.Remove] Error removing %v: %v", path, err)
+			}
+			continue
+		}
+
+		err = replaceFile(path, trimmedHCL(rootFile), 0400)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// detachedComments returns, for each of the top-level blocks parsed from content, the comments separated from the
+// block by a blank line, such as the cost and cloud actor comments written ahead of each new resource. hclwrite only
+// keeps the comments directly preceding a block as part of the block, and drops the others when blocks are moved.
+func detachedComments(content []byte, blocks []*hclwrite.Block) []hclwrite.Tokens {
+	comments := make([]hclwrite.Tokens, len(blocks))
+
+	file, diags := hclsyntax.ParseConfig(content, "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return comments
+	}
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok || len(body.Blocks) != len(blocks) {
+		return comments
+	}
+
+	previousEnd := 0
+	for i, block := range body.Blocks {
+		// the last line is the one on which the block starts.
+		lines := strings.Split(string(content[previousEnd:block.Range().Start.Byte]), "\n")
+		lines = lines[:len(lines)-1]
+		previousEnd = block.Range().End.Byte
+
+		// comments following the last blank line directly precede the block, and so are already part of it.
+		lastBlankLine := -1
+		for j, line := range lines {
+			if strings.TrimSpace(line) == "" {
+				lastBlankLine = j
+			}
+		}
+
+		tokens := hclwrite.Tokens{}
+		for _, line := range lines[:lastBlankLine+1] {
+			line = strings.TrimSpace(line)
+			if line == "" {
+				if len(tokens) > 0 {
+					tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenNewline, Bytes: []byte("\n")})
+				}
+				continue
+			}
+			if !strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "//") {
+				continue
+			}
+			tokens = append(tokens, commentTokens(line)...)
+		}
+		comments[i] = tokens
+	}
+	return comments
+}
+
+// mainTF returns the main.tf of the module, declaring the provider aliases it is passed ahead of its resources.
+func (m *wrappedModule) mainTF() []byte {
+	f := hclwrite.NewEmptyFile()
+	body := f.Body()
+
+	providerToAliases := map[string][]string{}
+	for providerAlias := range m.providerAliases {
+		provider, _, _ := strings.Cut(providerAlias, ".")
+		providerToAliases[provider] = append(providerToAliases[provider], providerAlias)
+	}
+	if len(providerToAliases) > 0 {
+		providers := make([]string, 0, len(providerToAliases))
+		for provider := range providerToAliases {
+			providers = append(providers, provider)
+		}
+		sort.Strings(providers)
+
+		requiredProviders := body.AppendNewBlock("terraform", nil).Body().AppendNewBlock("required_providers", nil).Body()
+		for _, provider := range providers {
+			aliases := providerToAliases[provider]
+			sort.Strings(aliases)
+
+			aliasTokens := make([]hclwrite.Tokens, 0, len(aliases))
+			for _, alias := range aliases {
+				aliasTokens = append(aliasTokens, hclwrite.TokensForIdentifier(alias))
+			}
+			requiredProviders.SetAttributeRaw(provider, hclwrite.TokensForObject([]hclwrite.ObjectAttrTokens{
+				{
+					Name:  hclwrite.TokensForIdentifier("source"),
+					Value: hclwrite.TokensForValue(cty.StringVal(fmt.Sprintf("hashicorp/%v", provider))),
+				},
+				{
+					Name:  hclwrite.TokensForIdentifier("configuration_aliases"),
+					Value: hclwrite.TokensForTuple(aliasTokens),
+				},
+			}))
+		}
+		body.AppendNewline()
+	}
+
+	for i, resource := range m.resources {
+		body.AppendUnstructuredTokens(m.resourceComments[i])
+		body.AppendBlock(resource)
+		body.AppendNewline()
+	}
+	return trimmedHCL(f)
+}
+
+// variablesTF returns the variables.tf of the module, declaring each variable referenced by its resources as it is
+// declared within the workspace.
+func (m *wrappedModule) variablesTF(rootVariables map[string]*hclwrite.Block) []byte {
+	f := hclwrite.NewEmptyFile()
+	body := f.Body()
+
+	for _, variable := range sortedKeys(m.variables) {
+		if block, ok := rootVariables[variable]; ok {
+			body.AppendBlock(block)
+		} else {
+			body.AppendNewBlock("variable", []string{variable})
+		}
+		body.AppendNewline()
+	}
+	return trimmedHCL(f)
+}
+
+// appendModuleCall appends the call of the module to body, passing the module its provider aliases and variables.
+func (m *wrappedModule) appendModuleCall(body *hclwrite.Body, generatedDirectory string, module string) {
+	moduleBody := body.AppendNewBlock("module", []string{module}).Body()
+	moduleBody.SetAttributeValue("source", cty.StringVal(fmt.Sprintf("./%v/modules/%v", generatedDirectory, module)))
+
+	if len(m.providerAliases) > 0 {
+		providers := make([]hclwrite.ObjectAttrTokens, 0, len(m.providerAliases))
+		for _, providerAlias := range sortedKeys(m.providerAliases) {
+			providers = append(providers, hclwrite.ObjectAttrTokens{
+				Name:  hclwrite.TokensForIdentifier(providerAlias),
+				Value: hclwrite.TokensForIdentifier(providerAlias),
+			})
+		}
+		moduleBody.AppendNewline()
+		moduleBody.SetAttributeRaw("providers", hclwrite.TokensForObject(providers))
+	}
+
+	if len(m.variables) > 0 {
+		moduleBody.AppendNewline()
+		for _, variable := range sortedKeys(m.variables) {
+			moduleBody.SetAttributeTraversal(variable, hcl.Traversal{
+				hcl.TraverseRoot{Name: "var"},
+				hcl.TraverseAttr{Name: variable},
+			})
+		}
+	}
+	body.AppendNewline()
+}
+
+// variableBlocks returns the variable blocks declared within the .tf files of directory, keyed by variable name.
+func variableBlocks(directory string) (map[string]*hclwrite.Block, error) {
+	variables := map[string]*hclwrite.Block{}
+
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, fmt.Errorf("[os.ReadDir] Error reading %v: %v", directory, err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".tf") {
+			continue
+		}
+
+		path := fmt.Sprintf("%v%v", directory, entry.Name())
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("[os.ReadFile] Error reading %v: %v", path, err)
+		}
+
+		f, diags := hclwrite.ParseConfig(content, path, hcl.InitialPos)
+		if diags.HasErrors() {
+			continue
+		}
+		for _, block := range f.Body().Blocks() {
+			if block.Type() == "variable" && len(block.Labels()) == 1 {
+				variables[block.Labels()[0]] = block
+			}
+		}
+	}
+	return variables, nil
+}
+
+// trimmedHCL returns the formatted content of f, without the blank line following its last block.
+func trimmedHCL(f *hclwrite.File) []byte {
+	return append(bytes.TrimRight(hclwrite.Format(f.Bytes()), "\n"), '\n')
+}
+
+// sortedKeys returns the keys of set in sorted order.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
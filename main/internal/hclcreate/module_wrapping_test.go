@@ -0,0 +1,187 @@
+package hclcreate
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+)
+
+func TestResourceModules(t *testing.T) {
+	// Given
+	h := hclCreate{config: Config{ModuleWrapping: true}}
+	appFile, diags := hclwrite.ParseConfig([]byte(`resource "aws_vpc" "main" {
+  cidr_block = "10.0.0.0/16"
+}
+
+resource "aws_security_group" "web" {
+  vpc_id = aws_vpc.main.id
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+`), "", hcl.InitialPos)
+	require.False(t, diags.HasErrors())
+
+	newResourceToWorkspace := NewResourceToWorkspace{
+		"aws-prod.aws_vpc.tfer--main":           "app",
+		"aws-prod.aws_security_group.tfer--web": "app",
+		"aws-prod.aws_s3_bucket.tfer--logs":     "app",
+	}
+
+	// When
+	resourceModules := h.resourceModules(WorkspaceToHCL{"app": appFile}, newResourceToWorkspace, assignResourceNames(newResourceToWorkspace, ResourceNames{}, nil))
+
+	// Then
+	assert.Equal(t, ResourceModules{
+		"aws-prod.aws_vpc.tfer--main":           "security",
+		"aws-prod.aws_security_group.tfer--web": "security",
+		"aws-prod.aws_s3_bucket.tfer--logs":     "s3",
+	}, resourceModules)
+	assert.Equal(t, "module.s3.aws_s3_bucket.logs", resourceModules.resourceAddress("aws-prod.aws_s3_bucket.tfer--logs", "aws_s3_bucket", "logs"))
+	assert.Equal(t, "aws_sqs_queue.jobs", resourceModules.resourceAddress("aws-prod.aws_sqs_queue.tfer--jobs", "aws_sqs_queue", "jobs"))
+}
+
+func TestResourceModules_RootModuleReferences(t *testing.T) {
+	// Given
+	h := hclCreate{config: Config{ModuleWrapping: true}}
+	appFile, diags := hclwrite.ParseConfig([]byte(`resource "aws_s3_bucket" "logs" {
+  bucket = "logs-${data.aws_caller_identity.current.account_id}"
+  tags   = local.tags
+}
+
+resource "aws_security_group" "web" {
+  vpc_id = aws_vpc.existing.id
+}
+
+resource "aws_sqs_queue" "jobs" {
+  name = var.queue_name
+}
+`), "", hcl.InitialPos)
+	require.False(t, diags.HasErrors())
+
+	newResourceToWorkspace := NewResourceToWorkspace{
+		"aws-prod.aws_s3_bucket.tfer--logs":     "app",
+		"aws-prod.aws_security_group.tfer--web": "app",
+		"aws-prod.aws_sqs_queue.tfer--jobs":     "app",
+	}
+
+	// When
+	resourceModules := h.resourceModules(WorkspaceToHCL{"app": appFile}, newResourceToWorkspace, assignResourceNames(newResourceToWorkspace, ResourceNames{}, nil))
+
+	// Then
+	assert.Equal(t, ResourceModules{"aws-prod.aws_sqs_queue.tfer--jobs": "sqs"}, resourceModules)
+}
+
+func TestWrapNewResourcesInModules(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	require.NoError(t, os.MkdirAll("repo/app", 0700))
+	require.NoError(t, os.WriteFile("repo/app/new-resources.tf", []byte(`provider "aws" {
+  alias  = "prod_eu_west_1"
+  region = "eu-west-1"
+}
+
+# Created at 2023-02-25 by jane@example.com
+resource "aws_s3_bucket" "logs" {
+  bucket   = "logs"
+  provider = aws.prod_eu_west_1
+}
+
+# Identified Resource Cost Components:
+## Requests (Usage-based)
+###### Price / Unit: $0.0000004 / requests
+
+resource "aws_sqs_queue" "jobs" {
+  name              = "jobs"
+  kms_master_key_id = var.kms_master_key_id
+}
+`), 0400))
+	require.NoError(t, os.WriteFile("repo/app/variables.tf", []byte(`variable "kms_master_key_id" {
+  type      = string
+  sensitive = true
+}
+`), 0600))
+	require.NoError(t, os.MkdirAll("mappings", 0700))
+	require.NoError(t, os.WriteFile("mappings/new-resources-to-modules.json", []byte(`{
+  "aws-prod.aws_s3_bucket.tfer--logs": "s3",
+  "aws-prod.aws_sqs_queue.tfer--jobs": "sqs"
+}`), 0400))
+
+	h := hclCreate{artifactStore: artifacts.NewMemoryStore(true), config: Config{ModuleWrapping: true}}
+
+	// When
+	err := h.WrapNewResourcesInModules(map[string]string{"app": "/app/"})
+
+	// Then
+	require.NoError(t, err)
+	newResources, err := os.ReadFile("repo/app/new-resources.tf")
+	require.NoError(t, err)
+	assert.Equal(t, `provider "aws" {
+  alias  = "prod_eu_west_1"
+  region = "eu-west-1"
+}
+
+module "s3" {
+  source = "./cloud-concierge/modules/s3"
+
+  providers = {
+    aws.prod_eu_west_1 = aws.prod_eu_west_1
+  }
+}
+
+module "sqs" {
+  source = "./cloud-concierge/modules/sqs"
+
+  kms_master_key_id = var.kms_master_key_id
+}
+`, string(newResources))
+
+	s3Module, err := os.ReadFile("repo/app/cloud-concierge/modules/s3/main.tf")
+	require.NoError(t, err)
+	assert.Equal(t, `terraform {
+  required_providers {
+    aws = {
+      source                = "hashicorp/aws"
+      configuration_aliases = [aws.prod_eu_west_1]
+    }
+  }
+}
+
+# Created at 2023-02-25 by jane@example.com
+resource "aws_s3_bucket" "logs" {
+  bucket   = "logs"
+  provider = aws.prod_eu_west_1
+}
+`, string(s3Module))
+
+	sqsModule, err := os.ReadFile("repo/app/cloud-concierge/modules/sqs/main.tf")
+	require.NoError(t, err)
+	assert.Equal(t, `# Identified Resource Cost Components:
+## Requests (Usage-based)
+###### Price / Unit: $0.0000004 / requests
+
+resource "aws_sqs_queue" "jobs" {
+  name              = "jobs"
+  kms_master_key_id = var.kms_master_key_id
+}
+`, string(sqsModule))
+
+	sqsVariables, err := os.ReadFile("repo/app/cloud-concierge/modules/sqs/variables.tf")
+	require.NoError(t, err)
+	assert.Equal(t, `variable "kms_master_key_id" {
+  type      = string
+  sensitive = true
+}
+`, string(sqsVariables))
+	assert.NoFileExists(t, "repo/app/cloud-concierge/modules/s3/variables.tf")
+}
@@ -0,0 +1,96 @@
+package hclcreate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// newWorkspaceHeader heads the main.tf file of a workspace proposed by cloud-concierge.
+const newWorkspaceHeader = `# This workspace is proposed by cloud-concierge for new resources that fit no existing workspace.
+# Review its backend and providers before applying it, or move its resources into an existing workspace.
+`
+
+// CreateNewWorkspaces creates the directories of the new workspaces proposed within mappings/new-workspaces.json,
+// each with its own main.tf file, and returns workspaceToDirectory extended by the new workspaces and by the existing
+// workspaces outside of workspaceToDirectory within mappings/unconfigured-workspaces.json.
+func (h *hclCreate) CreateNewWorkspaces(workspaceToDirectory map[string]string) (map[string]string, error) {
+	allWorkspaceToDirectory := map[string]string{}
+	for workspace, directory := range workspaceToDirectory {
+		allWorkspaceToDirectory[workspace] = directory
+	}
+
+	unconfiguredWorkspacesBytes, err := h.artifactStore.ReadFile("mappings/unconfigured-workspaces.json")
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("[artifacts.ReadFile] mappings/unconfigured-workspaces.json error: %v", err)
+	}
+	if err == nil {
+		unconfiguredWorkspaceToDirectory := map[string]string{}
+		err = json.Unmarshal(unconfiguredWorkspacesBytes, &unconfiguredWorkspaceToDirectory)
+		if err != nil {
+			return nil, fmt.Errorf("[json.Unmarshal] error unmarshalling `unconfiguredWorkspaceToDirectory`: %v", err)
+		}
+		for workspace, directory := range unconfiguredWorkspaceToDirectory {
+			allWorkspaceToDirectory[workspace] = directory
+		}
+	}
+
+	newWorkspacesBytes, err := h.artifactStore.ReadFile("mappings/new-workspaces.json")
+	if errors.Is(err, os.ErrNotExist) {
+		return allWorkspaceToDirectory, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[artifacts.ReadFile] mappings/new-workspaces.json error: %v", err)
+	}
+
+	newWorkspaceToDirectory := map[string]string{}
+	err = json.Unmarshal(newWorkspacesBytes, &newWorkspaceToDirectory)
+	if err != nil {
+		return nil, fmt.Errorf("[json.Unmarshal] error unmarshalling `newWorkspaceToDirectory`: %v", err)
+	}
+
+	// The providers and Terraform version that cloud resources were scanned with are pinned within current_cloud.
+	mainTF, err := os.ReadFile("current_cloud/main.tf")
+	if err != nil {
+		return nil, fmt.Errorf("[os.ReadFile] current_cloud/main.tf error: %v", err)
+	}
+
+	// Providers are pinned to the versions that the existing workspaces of the repository already use.
+	providerVersions, err := repositoryProviderVersions(workspaceToDirectory)
+	if err != nil {
+		return nil, fmt.Errorf("[repositoryProviderVersions]%v", err)
+	}
+
+	for workspace, directory := range newWorkspaceToDirectory {
+		err = os.MkdirAll(fmt.Sprintf("repo%v", directory), 0700)
+		if err != nil {
+			return nil, fmt.Errorf("[os.MkdirAll] error making directory repo%v: %v", directory, err)
+		}
+
+		outputPath := fmt.Sprintf("repo%vmain.tf", directory)
+		if _, err := os.Stat(outputPath); err == nil {
+			return nil, fmt.Errorf("[new workspace %v would overwrite %v]", workspace, outputPath)
+		}
+
+		workspaceMainTF, diags := hclwrite.ParseConfig(mainTF, "current_cloud/main.tf", hcl.InitialPos)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("[hclwrite.ParseConfig] current_cloud/main.tf error: %v", diags.Error())
+		}
+		if terraformBlock := workspaceMainTF.Body().FirstMatchingBlock("terraform", nil); terraformBlock != nil {
+			pinProviderVersions(terraformBlock.Body(), providerVersions)
+			h.config.NewWorkspaceBackend.appendBackendBlock(terraformBlock.Body(), workspace, directory)
+		}
+
+		err = os.WriteFile(outputPath, hclwrite.Format(append([]byte(newWorkspaceHeader+"\n"), workspaceMainTF.Bytes()...)), 0400)
+		if err != nil {
+			return nil, fmt.Errorf("[os.WriteFile] Error writing %v: %v", outputPath, err)
+		}
+		allWorkspaceToDirectory[workspace] = directory
+	}
+
+	return allWorkspaceToDirectory, nil
+}
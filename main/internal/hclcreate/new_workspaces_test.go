@@ -0,0 +1,164 @@
+package hclcreate
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+)
+
+func TestCreateNewWorkspaces(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	require.NoError(t, os.MkdirAll("mappings", 0700))
+	require.NoError(t, os.MkdirAll("current_cloud", 0700))
+	require.NoError(t, os.MkdirAll("repo/networking", 0700))
+	require.NoError(t, os.WriteFile("mappings/new-workspaces.json", []byte(`{"unmanaged": "/unmanaged/"}`), 0400))
+	require.NoError(t, os.WriteFile("current_cloud/main.tf", []byte("terraform {\n  required_version = \"1.5.0\"\n}\n"), 0400))
+
+	h := hclCreate{artifactStore: artifacts.NewMemoryStore(true)}
+	workspaceToDirectory := map[string]string{"networking": "/networking/"}
+
+	// When
+	allWorkspaceToDirectory, err := h.CreateNewWorkspaces(workspaceToDirectory)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"networking": "/networking/", "unmanaged": "/unmanaged/"}, allWorkspaceToDirectory)
+	assert.Equal(t, map[string]string{"networking": "/networking/"}, workspaceToDirectory)
+
+	mainTF, err := os.ReadFile("repo/unmanaged/main.tf")
+	require.NoError(t, err)
+	assert.Equal(t, newWorkspaceHeader+"\nterraform {\n  required_version = \"1.5.0\"\n}\n", string(mainTF))
+}
+
+func TestCreateNewWorkspaces_BackendAndProviderVersions(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	require.NoError(t, os.MkdirAll("mappings", 0700))
+	require.NoError(t, os.MkdirAll("current_cloud", 0700))
+	require.NoError(t, os.MkdirAll("repo/networking", 0700))
+	require.NoError(t, os.MkdirAll("repo/storage", 0700))
+	require.NoError(t, os.WriteFile("mappings/new-workspaces.json", []byte(`{"unmanaged": "/teams/unmanaged/"}`), 0400))
+	require.NoError(t, os.WriteFile("current_cloud/main.tf", []byte(`terraform {
+  required_version = "1.5.0"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~>5.31.0"
+    }
+  }
+}
+`), 0400))
+	require.NoError(t, os.WriteFile("repo/networking/versions.tf", []byte(`terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 4.67"
+    }
+  }
+}
+`), 0400))
+	require.NoError(t, os.WriteFile("repo/storage/main.tf", []byte(`terraform {
+  required_providers {
+    aws = "~> 4.67"
+  }
+}
+`), 0400))
+
+	h := hclCreate{artifactStore: artifacts.NewMemoryStore(true), config: Config{NewWorkspaceBackend: BackendTemplateDecoder{
+		Type:   "s3",
+		Config: map[string]interface{}{"bucket": "my-state", "key": "{directory}/terraform.tfstate", "encrypt": true},
+	}}}
+
+	// When
+	_, err := h.CreateNewWorkspaces(map[string]string{"networking": "/networking/", "storage": "/storage/"})
+
+	// Then
+	require.NoError(t, err)
+	mainTF, err := os.ReadFile("repo/teams/unmanaged/main.tf")
+	require.NoError(t, err)
+	assert.Equal(t, newWorkspaceHeader+`
+terraform {
+  required_version = "1.5.0"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 4.67"
+    }
+  }
+
+  backend "s3" {
+    bucket  = "my-state"
+    encrypt = true
+    key     = "teams/unmanaged/terraform.tfstate"
+  }
+}
+`, string(mainTF))
+}
+
+func TestBackendTemplateDecoder_Decode(t *testing.T) {
+	// Given
+	var template BackendTemplateDecoder
+
+	// When
+	err := template.Decode(`{"type": "gcs", "config": {"bucket": "my-state", "prefix": "{workspace}"}}`)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, BackendTemplateDecoder{Type: "gcs", Config: map[string]interface{}{"bucket": "my-state", "prefix": "{workspace}"}}, template)
+	assert.Error(t, template.Decode(`{"config": {"bucket": "my-state"}}`))
+	assert.Error(t, template.Decode(`{"type": "gcs", "config": {"bucket": ["my-state"]}}`))
+}
+
+func TestCreateNewWorkspaces_NoNewWorkspaces(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	h := hclCreate{artifactStore: artifacts.NewMemoryStore(true)}
+
+	// When
+	allWorkspaceToDirectory, err := h.CreateNewWorkspaces(map[string]string{"networking": "/networking/"})
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"networking": "/networking/"}, allWorkspaceToDirectory)
+}
+
+func TestCreateNewWorkspaces_UnconfiguredWorkspace(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	require.NoError(t, os.MkdirAll("mappings", 0700))
+	require.NoError(t, os.MkdirAll("repo/unmanaged", 0700))
+	require.NoError(t, os.WriteFile("repo/unmanaged/main.tf", []byte("terraform {}\n"), 0400))
+	require.NoError(t, os.WriteFile("mappings/unconfigured-workspaces.json", []byte(`{"unmanaged": "/unmanaged/"}`), 0400))
+
+	h := hclCreate{artifactStore: artifacts.NewMemoryStore(true)}
+
+	// When
+	allWorkspaceToDirectory, err := h.CreateNewWorkspaces(map[string]string{"networking": "/networking/"})
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"networking": "/networking/", "unmanaged": "/unmanaged/"}, allWorkspaceToDirectory)
+
+	mainTF, err := os.ReadFile("repo/unmanaged/main.tf")
+	require.NoError(t, err)
+	assert.Equal(t, "terraform {}\n", string(mainTF))
+}
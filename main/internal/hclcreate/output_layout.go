@@ -0,0 +1,131 @@
+package hclcreate
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+const (
+	// NewResourcesLayoutSingle writes the new resources of each workspace to a single new-resources.tf.
+	NewResourcesLayoutSingle = "single"
+
+	// NewResourcesLayoutService writes the new resources of each workspace to a new-resources-<service>.tf per
+	// service, e.g. new-resources-s3.tf.
+	NewResourcesLayoutService = "service"
+
+	// NewResourcesLayoutType writes the new resources of each workspace to a new-resources-<type>.tf per resource
+	// type, e.g. new-resources-aws_s3_bucket.tf.
+	NewResourcesLayoutType = "type"
+
+	// NewResourcesLayoutDivision writes the new resources of each workspace to a new-resources-<division>.tf per
+	// provider-division, e.g. new-resources-aws-prod.tf.
+	NewResourcesLayoutDivision = "division"
+)
+
+// DefaultGeneratedDirectory is the subdirectory of each workspace within which supporting files, such as import
+// blocks and migrations, are generated when no other subdirectory is configured.
+const DefaultGeneratedDirectory = "cloud-concierge"
+
+// generatedDirectory returns the subdirectory of each workspace within which supporting files are generated.
+func (h *hclCreate) generatedDirectory() string {
+	if h.config.GeneratedDirectory == "" {
+		return DefaultGeneratedDirectory
+	}
+	return h.config.GeneratedDirectory
+}
+
+// newResourcesFileName returns the name of the file within its workspace to which a new resource is written
+// under the configured layout.
+func (h *hclCreate) newResourcesFileName(resourceID ResourceIdentifier) (string, error) {
+	switch h.config.NewResourcesLayout {
+	case "", NewResourcesLayoutSingle:
+		return "new-resources.tf", nil
+	case NewResourcesLayoutService:
+		return fmt.Sprintf("new-resources-%v.tf", resourceService(resourceID.resourceType)), nil
+	case NewResourcesLayoutType:
+		return fmt.Sprintf("new-resources-%v.tf", resourceID.resourceType), nil
+	case NewResourcesLayoutDivision:
+		return fmt.Sprintf("new-resources-%v.tf", resourceID.division), nil
+	default:
+		return "", fmt.Errorf(
+			"unknown new resources layout %q, expected one of %q, %q, %q or %q",
+			h.config.NewResourcesLayout,
+			NewResourcesLayoutSingle,
+			NewResourcesLayoutService,
+			NewResourcesLayoutType,
+			NewResourcesLayoutDivision,
+		)
+	}
+}
+
+// newResourceFiles returns the file to which each new resource is written, keyed by its generated address,
+// type.name.
+func (h *hclCreate) newResourceFiles(
+	newResourceToWorkspace NewResourceToWorkspace, resourceNames ResourceNames,
+) (map[string]string, error) {
+	addressToFile := map[string]string{}
+	for resource := range newResourceToWorkspace {
+		resourceID := h.splitResourceIdentifier(resource)
+		fileName, err := h.newResourcesFileName(resourceID)
+		if err != nil {
+			return nil, err
+		}
+		addressToFile[fmt.Sprintf("%v.%v", resourceID.resourceType, resourceNames[resource])] = fileName
+	}
+	return addressToFile, nil
+}
+
+// splitNewResources splits the formatted new resources of a workspace into the files of addressToFile, keeping
+// each block together with the comments preceding it. Blocks other than new resources, such as aliased provider
+// blocks, stay within new-resources.tf.
+func splitNewResources(content []byte, addressToFile map[string]string) (map[string][]byte, error) {
+	file, diags := hclsyntax.ParseConfig(content, "new-resources.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("[hclsyntax.ParseConfig] Error parsing new resources: %v", diags.Error())
+	}
+
+	fileToChunks := map[string][][]byte{}
+	blocks := file.Body.(*hclsyntax.Body).Blocks
+	start := 0
+	for index, block := range blocks {
+		end := block.Range().End.Byte
+		if index == len(blocks)-1 {
+			end = len(content)
+		}
+
+		fileName := "new-resources.tf"
+		if block.Type == "resource" {
+			if resourceFile, ok := addressToFile[strings.Join(block.Labels, ".")]; ok {
+				fileName = resourceFile
+			}
+		}
+		fileToChunks[fileName] = append(fileToChunks[fileName], content[start:end])
+		start = end
+	}
+
+	fileToContent := map[string][]byte{}
+	for fileName, chunks := range fileToChunks {
+		trimmedChunks := make([]string, 0, len(chunks))
+		for _, chunk := range chunks {
+			trimmedChunks = append(trimmedChunks, strings.Trim(string(chunk), "\n"))
+		}
+		fileToContent[fileName] = hclwrite.Format([]byte(strings.Join(trimmedChunks, "\n\n") + "\n"))
+	}
+	return fileToContent, nil
+}
+
+// newResourcesPaths returns the paths of the new resources files within directory, in sorted order.
+func newResourcesPaths(directory string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(directory, "new-resources*.tf"))
+	if err != nil {
+		return nil, fmt.Errorf("[filepath.Glob] Error listing new resources files within %v: %v", directory, err)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
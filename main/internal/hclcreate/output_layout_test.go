@@ -0,0 +1,128 @@
+package hclcreate
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+)
+
+func TestNewResourcesFileName(t *testing.T) {
+	// Given
+	resourceID := ResourceIdentifier{division: "aws-prod", resourceType: "aws_s3_bucket", resourceName: "tfer--logs"}
+	layoutToFileName := map[string]string{
+		"":                         "new-resources.tf",
+		NewResourcesLayoutSingle:   "new-resources.tf",
+		NewResourcesLayoutService:  "new-resources-s3.tf",
+		NewResourcesLayoutType:     "new-resources-aws_s3_bucket.tf",
+		NewResourcesLayoutDivision: "new-resources-aws-prod.tf",
+	}
+
+	for layout, expected := range layoutToFileName {
+		h := hclCreate{config: Config{NewResourcesLayout: layout}}
+
+		// When
+		fileName, err := h.newResourcesFileName(resourceID)
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, expected, fileName, layout)
+	}
+
+	h := hclCreate{config: Config{NewResourcesLayout: "workspace"}}
+	_, err := h.newResourcesFileName(resourceID)
+	assert.Error(t, err)
+}
+
+func TestSplitNewResources(t *testing.T) {
+	// Given
+	content := []byte(`# Provider configuration for the resources of division aws-prod.
+provider "aws" {
+  alias = "prod"
+}
+
+# Monthly cost: $1
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+
+# Monthly cost: $2
+resource "aws_iam_role" "ci" {
+  name = "ci"
+}
+
+# Monthly cost: $3
+resource "aws_s3_bucket" "assets" {
+  bucket = "assets"
+}
+`)
+	addressToFile := map[string]string{
+		"aws_s3_bucket.logs":   "new-resources-s3.tf",
+		"aws_iam_role.ci":      "new-resources-iam.tf",
+		"aws_s3_bucket.assets": "new-resources-s3.tf",
+	}
+
+	// When
+	fileToContent, err := splitNewResources(content, addressToFile)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"new-resources.tf": `# Provider configuration for the resources of division aws-prod.
+provider "aws" {
+  alias = "prod"
+}
+`,
+		"new-resources-s3.tf": `# Monthly cost: $1
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+
+# Monthly cost: $3
+resource "aws_s3_bucket" "assets" {
+  bucket = "assets"
+}
+`,
+		"new-resources-iam.tf": `# Monthly cost: $2
+resource "aws_iam_role" "ci" {
+  name = "ci"
+}
+`,
+	}, map[string]string{
+		"new-resources.tf":     string(fileToContent["new-resources.tf"]),
+		"new-resources-s3.tf":  string(fileToContent["new-resources-s3.tf"]),
+		"new-resources-iam.tf": string(fileToContent["new-resources-iam.tf"]),
+	})
+	assert.Len(t, fileToContent, 3)
+}
+
+func TestCreateImportsGeneratedDirectory(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	require.NoError(t, os.MkdirAll("mappings", 0700))
+	require.NoError(t, os.MkdirAll("repo/app", 0700))
+	require.NoError(t, os.WriteFile("mappings/resources-to-import-location.json", []byte(`{
+  "aws-prod": {
+    "aws_s3_bucket.tfer--logs": {"TerraformConfigLocation": "aws_s3_bucket.tfer--logs", "RemoteCloudReference": "logs"}
+  }
+}`), 0400))
+	require.NoError(t, os.WriteFile("mappings/new-resources-to-workspace.json", []byte(`{
+  "aws-prod.aws_s3_bucket.tfer--logs": "app"
+}`), 0400))
+
+	h := hclCreate{artifactStore: artifacts.NewMemoryStore(true), config: Config{TerraformVersion: "1.5.0", ImportOutput: ImportOutputScript, GeneratedDirectory: "terraform-import"}}
+
+	// When
+	err := h.CreateImports("abc123", map[string]string{"app": "/app/"})
+
+	// Then
+	require.NoError(t, err)
+	assert.FileExists(t, "repo/app/terraform-import/imports/abc123_import.sh")
+	assert.NoDirExists(t, "repo/app/cloud-concierge")
+}
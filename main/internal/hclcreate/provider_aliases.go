@@ -0,0 +1,394 @@
+package hclcreate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+)
+
+// DivisionToResourceRegions is a map between the full provider-division name of each division and the region of
+// each of its terraformer-generated resources, keyed as type.terraformer-name.
+type DivisionToResourceRegions map[string]map[string]string
+
+// DivisionToAccount is a map between the full provider-division name of each division and the account its
+// resources belong to, the account id for aws and the subscription id for azurerm, where known.
+type DivisionToAccount map[string]string
+
+// providerConfiguration is the configuration of an aliased provider block, distinguishing the resources of one
+// division and region from those of another within the same workspace.
+type providerConfiguration struct {
+	// provider is the local name of the provider, e.g. aws.
+	provider string
+
+	// alias is the alias of the provider block.
+	alias string
+
+	// division is the full provider-division name of the division whose resources use the provider block.
+	division string
+
+	// region is the region of the resources using the provider block, if any.
+	region string
+
+	// account is the aws account id or azurerm subscription id of the resources using the provider block, if known.
+	account string
+
+	// roleARN is the ARN of the aws role assumed by the provider block, if any.
+	roleARN string
+}
+
+// loadDivisionResourceRegions reads the region of each resource within the terraformer state file of each division,
+// along with the account of each division. Divisions without a terraformer state file have no known resource
+// regions or account.
+func (h *hclCreate) loadDivisionResourceRegions() (DivisionToResourceRegions, DivisionToAccount, error) {
+	divisionToResourceRegions := DivisionToResourceRegions{}
+	divisionToAccount := DivisionToAccount{}
+
+	for division, provider := range h.divisionToProvider {
+		fullDivisionName := fmt.Sprintf("%v-%v", provider, division)
+		divisionToResourceRegions[fullDivisionName] = map[string]string{}
+
+		stateBytes, err := os.ReadFile(fmt.Sprintf("current_cloud/%v/terraform.tfstate", fullDivisionName))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("[os.ReadFile] Error reading in terraform.tfstate for %v: %v", fullDivisionName, err)
+		}
+
+		var stateFile driftDetector.TerraformerStateFile
+		err = json.Unmarshal(stateBytes, &stateFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("[json.Unmarshal] Error parsing terraform.tfstate for %v: %v", fullDivisionName, err)
+		}
+
+		for _, resource := range stateFile.Resources {
+			if len(resource.Instances) == 0 {
+				continue
+			}
+			attributes := resource.Instances[0].AttributesFlat
+			region, _ := driftDetector.ParseRegionFromTfStateMap(attributes, strings.Split(resource.Type, "_")[0])
+			divisionToResourceRegions[fullDivisionName][fmt.Sprintf("%v.%v", resource.Type, resource.Name)] = region
+
+			if _, ok := divisionToAccount[fullDivisionName]; !ok {
+				if account := resourceAccount(string(provider), attributes); account != "" {
+					divisionToAccount[fullDivisionName] = account
+				}
+			}
+		}
+	}
+
+	return divisionToResourceRegions, divisionToAccount, nil
+}
+
+// resourceAccount returns the account of a resource from its state attributes: the account id within the arn of an
+// aws resource, or the subscription id within the id of an azurerm resource.
+func resourceAccount(provider string, attributes map[string]string) string {
+	switch provider {
+	case "aws":
+		arnParts := strings.Split(attributes["arn"], ":")
+		if len(arnParts) > 4 {
+			return arnParts[4]
+		}
+	case "azurerm":
+		idParts := strings.Split(attributes["id"], "/")
+		if len(idParts) > 2 && strings.EqualFold(idParts[1], "subscriptions") {
+			return idParts[2]
+		}
+	}
+	return ""
+}
+
+// resourceProviderConfiguration returns the aliased provider configuration for a resource of the specified
+// division and region. aws provider blocks are distinguished by the division's account, assumed role and region,
+// google provider blocks by project, and azurerm provider blocks by subscription, so azurerm resources are only
+// aliased when their subscription is known.
+func resourceProviderConfiguration(resourceID ResourceIdentifier, region string, account string, roleARN string) (providerConfiguration, bool) {
+	provider, shortDivision, found := strings.Cut(resourceID.division, "-")
+	if !found || provider != strings.Split(resourceID.resourceType, "_")[0] {
+		return providerConfiguration{}, false
+	}
+
+	switch provider {
+	case "aws":
+		alias := shortDivision
+		if region != "" {
+			alias = fmt.Sprintf("%v_%v", shortDivision, region)
+		}
+		if account == "" && roleARN != "" {
+			account = resourceAccount(provider, map[string]string{"arn": roleARN})
+		}
+		return providerConfiguration{
+			provider: provider,
+			alias:    ConvertTerraformerResourceName(alias),
+			division: resourceID.division,
+			region:   region,
+			account:  account,
+			roleARN:  roleARN,
+		}, true
+	case "google":
+		return providerConfiguration{
+			provider: provider,
+			alias:    ConvertTerraformerResourceName(shortDivision),
+			division: resourceID.division,
+		}, true
+	case "azurerm":
+		if account == "" {
+			return providerConfiguration{}, false
+		}
+		return providerConfiguration{
+			provider: provider,
+			alias:    ConvertTerraformerResourceName(fmt.Sprintf("subscription_%v", account)),
+			division: resourceID.division,
+			account:  account,
+		}, true
+	default:
+		return providerConfiguration{}, false
+	}
+}
+
+// defaultProviders maps the local name of each provider configured without an alias within a workspace to the
+// literal string attributes of its provider block.
+type defaultProviders map[string]map[string]string
+
+// loadDefaultProviders reads the provider blocks configured without an alias within the top level .tf files of
+// the workspace within directory, e.g. provider "aws" { region = "us-east-1" }. Attributes whose values are not
+// literal strings are not recorded.
+func loadDefaultProviders(directory string) (defaultProviders, error) {
+	providers := defaultProviders{}
+
+	paths, err := filepath.Glob(filepath.Join(fmt.Sprintf("repo%v", directory), "*.tf"))
+	if err != nil {
+		return nil, fmt.Errorf("[filepath.Glob] %v", err)
+	}
+
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("[os.ReadFile] Error reading %v: %v", path, err)
+		}
+
+		file, diags := hclsyntax.ParseConfig(content, path, hcl.InitialPos)
+		if diags.HasErrors() {
+			continue
+		}
+
+		for _, block := range file.Body.(*hclsyntax.Body).Blocks {
+			if block.Type != "provider" || len(block.Labels) != 1 {
+				continue
+			}
+			if _, ok := block.Body.Attributes["alias"]; ok {
+				continue
+			}
+
+			attributes := map[string]string{}
+			for name, attribute := range block.Body.Attributes {
+				value, diags := attribute.Expr.Value(nil)
+				if diags.HasErrors() || !value.IsKnown() || value.IsNull() || value.Type() != cty.String {
+					continue
+				}
+				attributes[name] = value.AsString()
+			}
+			if assumeRole := firstNestedBlock(block.Body, "assume_role"); assumeRole != nil {
+				if roleARN, ok := assumeRole.Body.Attributes["role_arn"]; ok {
+					value, diags := roleARN.Expr.Value(nil)
+					if !diags.HasErrors() && value.IsKnown() && !value.IsNull() && value.Type() == cty.String {
+						attributes["role_arn"] = value.AsString()
+					}
+				}
+			}
+			providers[block.Labels[0]] = attributes
+		}
+	}
+
+	return providers, nil
+}
+
+// firstNestedBlock returns the first block of the specified type nested within body, if any.
+func firstNestedBlock(body *hclsyntax.Body, blockType string) *hclsyntax.Block {
+	for _, block := range body.Blocks {
+		if block.Type == blockType {
+			return block
+		}
+	}
+	return nil
+}
+
+// usesDefaultProvider returns true if the resources of configuration are served by the workspace's default provider
+// block as configured, i.e. the default provider block sets the same region, account, project or subscription.
+// Resources of a workspace without a default provider block are always aliased.
+func usesDefaultProvider(configuration providerConfiguration, providers defaultProviders) bool {
+	attributes, ok := providers[configuration.provider]
+	if !ok {
+		return false
+	}
+
+	switch configuration.provider {
+	case "aws":
+		if configuration.region == "" || attributes["region"] != configuration.region {
+			return false
+		}
+		defaultAccount := resourceAccount("aws", map[string]string{"arn": attributes["role_arn"]})
+		return configuration.account == "" || defaultAccount == "" || defaultAccount == configuration.account
+	case "google":
+		_, shortDivision, _ := strings.Cut(configuration.division, "-")
+		return attributes["project"] == shortDivision
+	case "azurerm":
+		return strings.EqualFold(attributes["subscription_id"], configuration.account)
+	}
+	return false
+}
+
+// setProviderAliases adds aliased provider blocks to each workspace whose new resources come from more than one
+// division or region of the same provider, or from a single one that the workspace's default provider block does
+// not serve, and points each of those resources at the provider block of its division and region with the provider
+// meta-argument.
+func (h *hclCreate) setProviderAliases(
+	workspaceToHCLFile WorkspaceToHCL,
+	workspaceToDirectory map[string]string,
+	newResourceToWorkspace NewResourceToWorkspace,
+	resourceNames ResourceNames,
+	divisionToResourceRegions DivisionToResourceRegions,
+	divisionToAccount DivisionToAccount,
+) (WorkspaceToHCL, error) {
+	workspaceToResourceConfigurations := map[string]map[string]providerConfiguration{}
+	for resource, workspace := range newResourceToWorkspace {
+		resourceID := h.splitResourceIdentifier(resource)
+		region := divisionToResourceRegions[resourceID.division][fmt.Sprintf("%v.%v", resourceID.resourceType, resourceID.resourceName)]
+		_, shortDivision, _ := strings.Cut(resourceID.division, "-")
+
+		configuration, ok := resourceProviderConfiguration(
+			resourceID, region, divisionToAccount[resourceID.division], h.config.DivisionRoleARNs[shortDivision],
+		)
+		if !ok {
+			continue
+		}
+		if _, ok := workspaceToResourceConfigurations[workspace]; !ok {
+			workspaceToResourceConfigurations[workspace] = map[string]providerConfiguration{}
+		}
+		workspaceToResourceConfigurations[workspace][resource] = configuration
+	}
+
+	for workspace, resourceConfigurations := range workspaceToResourceConfigurations {
+		providers, err := loadDefaultProviders(workspaceToDirectory[workspace])
+		if err != nil {
+			return nil, fmt.Errorf("[loadDefaultProviders] %v", err)
+		}
+
+		providerToConfigurations := map[string]map[string]providerConfiguration{}
+		for _, configuration := range resourceConfigurations {
+			if _, ok := providerToConfigurations[configuration.provider]; !ok {
+				providerToConfigurations[configuration.provider] = map[string]providerConfiguration{}
+			}
+			providerToConfigurations[configuration.provider][configuration.alias] = configuration
+		}
+
+		aliasedProviders := map[string]bool{}
+		aliasedConfigurations := []providerConfiguration{}
+		for provider, configurations := range providerToConfigurations {
+			servedByDefault := len(configurations) == 1
+			for _, configuration := range configurations {
+				servedByDefault = servedByDefault && usesDefaultProvider(configuration, providers)
+			}
+			if servedByDefault {
+				continue
+			}
+			for _, configuration := range configurations {
+				aliasedProviders[provider] = true
+				aliasedConfigurations = append(aliasedConfigurations, configuration)
+			}
+		}
+		if len(aliasedConfigurations) == 0 {
+			continue
+		}
+		sort.Slice(aliasedConfigurations, func(i, j int) bool {
+			if aliasedConfigurations[i].provider != aliasedConfigurations[j].provider {
+				return aliasedConfigurations[i].provider < aliasedConfigurations[j].provider
+			}
+			return aliasedConfigurations[i].alias < aliasedConfigurations[j].alias
+		})
+
+		body := workspaceToHCLFile[workspace].Body()
+		for resource, configuration := range resourceConfigurations {
+			if !aliasedProviders[configuration.provider] {
+				continue
+			}
+			resourceID := h.splitResourceIdentifier(resource)
+			block := body.FirstMatchingBlock("resource", []string{resourceID.resourceType, resourceNames[resource]})
+			if block == nil {
+				continue
+			}
+			block.Body().SetAttributeTraversal("provider", hcl.Traversal{
+				hcl.TraverseRoot{Name: configuration.provider},
+				hcl.TraverseAttr{Name: configuration.alias},
+			})
+		}
+
+		workspaceToHCLFile[workspace] = prependProviderBlocks(workspaceToHCLFile[workspace], aliasedConfigurations)
+	}
+
+	return workspaceToHCLFile, nil
+}
+
+// prependProviderBlocks returns hclFile preceded by an aliased provider block for each of configurations.
+func prependProviderBlocks(hclFile *hclwrite.File, configurations []providerConfiguration) *hclwrite.File {
+	providersFile := hclwrite.NewEmptyFile()
+	providersBody := providersFile.Body()
+
+	for _, configuration := range configurations {
+		providersBody.AppendUnstructuredTokens(hclwrite.Tokens{
+			{
+				Type:  hclsyntax.TokenComment,
+				Bytes: []byte(fmt.Sprintf("# Provider configuration for the resources of %v.\n", providerConfigurationScope(configuration))),
+			},
+		})
+
+		providerBody := providersBody.AppendNewBlock("provider", []string{configuration.provider}).Body()
+		providerBody.SetAttributeValue("alias", cty.StringVal(configuration.alias))
+
+		_, shortDivision, _ := strings.Cut(configuration.division, "-")
+		switch configuration.provider {
+		case "aws":
+			if configuration.region != "" {
+				providerBody.SetAttributeValue("region", cty.StringVal(configuration.region))
+			}
+			if configuration.account != "" {
+				providerBody.SetAttributeValue("allowed_account_ids", cty.ListVal([]cty.Value{cty.StringVal(configuration.account)}))
+			}
+			if configuration.roleARN != "" {
+				providerBody.AppendNewBlock("assume_role", nil).Body().SetAttributeValue("role_arn", cty.StringVal(configuration.roleARN))
+			}
+		case "google":
+			providerBody.SetAttributeValue("project", cty.StringVal(shortDivision))
+		case "azurerm":
+			providerBody.SetAttributeValue("subscription_id", cty.StringVal(configuration.account))
+			providerBody.AppendNewBlock("features", nil)
+		}
+		providersBody.AppendNewline()
+	}
+
+	providersBody.AppendUnstructuredTokens(hclFile.Body().BuildTokens(nil))
+	return providersFile
+}
+
+// providerConfigurationScope describes the division and region, or the azurerm subscription shared by divisions,
+// whose resources use an aliased provider block.
+func providerConfigurationScope(configuration providerConfiguration) string {
+	if configuration.provider == "azurerm" {
+		return fmt.Sprintf("subscription %v", configuration.account)
+	}
+	if configuration.region == "" {
+		return fmt.Sprintf("division %v", configuration.division)
+	}
+	return fmt.Sprintf("division %v in region %v", configuration.division, configuration.region)
+}
@@ -0,0 +1,214 @@
+package hclcreate
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+func TestLoadDivisionResourceRegions(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	require.NoError(t, os.MkdirAll("current_cloud/aws-prod", 0700))
+	require.NoError(t, os.WriteFile("current_cloud/aws-prod/terraform.tfstate", []byte(`{
+  "resources": [
+    {"type": "aws_s3_bucket", "name": "tfer--logs", "instances": [{"attributes_flat": {"arn": "arn:aws:s3:::logs", "region": "eu-west-1"}}]},
+    {"type": "aws_sqs_queue", "name": "tfer--orders", "instances": [{"attributes_flat": {"arn": "arn:aws:sqs:us-west-2:123456789012:orders"}}]}
+  ]
+}`), 0400))
+
+	h := hclCreate{divisionToProvider: map[terraformValueObjects.Division]terraformValueObjects.Provider{
+		"prod": "aws",
+		"dev":  "aws",
+	}}
+
+	// When
+	divisionToResourceRegions, divisionToAccount, err := h.loadDivisionResourceRegions()
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, DivisionToAccount{"aws-prod": "123456789012"}, divisionToAccount)
+	assert.Equal(t, DivisionToResourceRegions{
+		"aws-prod": {
+			"aws_s3_bucket.tfer--logs":   "eu-west-1",
+			"aws_sqs_queue.tfer--orders": "us-west-2",
+		},
+		"aws-dev": {},
+	}, divisionToResourceRegions)
+}
+
+func TestSetProviderAliases(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	require.NoError(t, os.MkdirAll("repo/data", 0700))
+	require.NoError(t, os.WriteFile("repo/data/main.tf", []byte(`provider "aws" {
+  region = "eu-west-1"
+}
+`), 0400))
+	require.NoError(t, os.MkdirAll("repo/ops", 0700))
+	require.NoError(t, os.WriteFile("repo/ops/main.tf", []byte(`provider "aws" {
+  region = "us-east-1"
+}
+`), 0400))
+
+	h := hclCreate{config: Config{DivisionRoleARNs: map[string]string{"prod": "arn:aws:iam::123456789012:role/deployer"}}}
+	appFile, diags := hclwrite.ParseConfig([]byte(`resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+
+resource "aws_sqs_queue" "orders" {
+  name = "orders"
+}
+`), "", hcl.InitialPos)
+	require.False(t, diags.HasErrors())
+	dataFile, diags := hclwrite.ParseConfig([]byte(`resource "aws_s3_bucket" "lake" {
+  bucket = "lake"
+}
+`), "", hcl.InitialPos)
+	require.False(t, diags.HasErrors())
+	opsFile, diags := hclwrite.ParseConfig([]byte(`resource "aws_s3_bucket" "audit" {
+  bucket = "audit"
+}
+`), "", hcl.InitialPos)
+	require.False(t, diags.HasErrors())
+
+	newResourceToWorkspace := NewResourceToWorkspace{
+		"aws-prod.aws_s3_bucket.tfer--logs":   "app",
+		"aws-prod.aws_sqs_queue.tfer--orders": "app",
+		"aws-prod.aws_s3_bucket.tfer--lake":   "data",
+		"aws-prod.aws_s3_bucket.tfer--audit":  "ops",
+	}
+	divisionToResourceRegions := DivisionToResourceRegions{
+		"aws-prod": {
+			"aws_s3_bucket.tfer--logs":   "eu-west-1",
+			"aws_sqs_queue.tfer--orders": "us-west-2",
+			"aws_s3_bucket.tfer--lake":   "eu-west-1",
+			"aws_s3_bucket.tfer--audit":  "eu-west-1",
+		},
+	}
+
+	// When
+	workspaceToHCLFile, err := h.setProviderAliases(
+		WorkspaceToHCL{"app": appFile, "data": dataFile, "ops": opsFile},
+		map[string]string{"app": "/app/", "data": "/data/", "ops": "/ops/"},
+		newResourceToWorkspace,
+		assignResourceNames(newResourceToWorkspace, ResourceNames{}, nil),
+		divisionToResourceRegions,
+		DivisionToAccount{"aws-prod": "123456789012"},
+	)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, `# Provider configuration for the resources of division aws-prod in region eu-west-1.
+provider "aws" {
+  alias               = "prod_eu_west_1"
+  region              = "eu-west-1"
+  allowed_account_ids = ["123456789012"]
+  assume_role {
+    role_arn = "arn:aws:iam::123456789012:role/deployer"
+  }
+}
+
+# Provider configuration for the resources of division aws-prod in region us-west-2.
+provider "aws" {
+  alias               = "prod_us_west_2"
+  region              = "us-west-2"
+  allowed_account_ids = ["123456789012"]
+  assume_role {
+    role_arn = "arn:aws:iam::123456789012:role/deployer"
+  }
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket   = "logs"
+  provider = aws.prod_eu_west_1
+}
+
+resource "aws_sqs_queue" "orders" {
+  name     = "orders"
+  provider = aws.prod_us_west_2
+}
+`, string(hclwrite.Format(workspaceToHCLFile["app"].Bytes())))
+	assert.Equal(t, `resource "aws_s3_bucket" "lake" {
+  bucket = "lake"
+}
+`, string(workspaceToHCLFile["data"].Bytes()))
+	assert.Contains(t, string(hclwrite.Format(workspaceToHCLFile["ops"].Bytes())), `resource "aws_s3_bucket" "audit" {
+  bucket   = "audit"
+  provider = aws.prod_eu_west_1
+}`)
+}
+
+func TestSetProviderAliases_AzureSubscriptions(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	h := hclCreate{}
+	appFile, diags := hclwrite.ParseConfig([]byte(`resource "azurerm_storage_account" "logs" {
+  name = "logs"
+}
+
+resource "azurerm_storage_account" "data" {
+  name = "data"
+}
+`), "", hcl.InitialPos)
+	require.False(t, diags.HasErrors())
+
+	newResourceToWorkspace := NewResourceToWorkspace{
+		"azurerm-rg-a.azurerm_storage_account.tfer--logs": "app",
+		"azurerm-rg-b.azurerm_storage_account.tfer--data": "app",
+	}
+
+	// When
+	workspaceToHCLFile, err := h.setProviderAliases(
+		WorkspaceToHCL{"app": appFile},
+		map[string]string{"app": "/app/"},
+		newResourceToWorkspace,
+		assignResourceNames(newResourceToWorkspace, ResourceNames{}, nil),
+		DivisionToResourceRegions{},
+		DivisionToAccount{"azurerm-rg-a": "sub-1", "azurerm-rg-b": "sub-2"},
+	)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, `# Provider configuration for the resources of subscription sub-1.
+provider "azurerm" {
+  alias           = "subscription_sub_1"
+  subscription_id = "sub-1"
+  features {
+  }
+}
+
+# Provider configuration for the resources of subscription sub-2.
+provider "azurerm" {
+  alias           = "subscription_sub_2"
+  subscription_id = "sub-2"
+  features {
+  }
+}
+
+resource "azurerm_storage_account" "logs" {
+  name     = "logs"
+  provider = azurerm.subscription_sub_1
+}
+
+resource "azurerm_storage_account" "data" {
+  name     = "data"
+  provider = azurerm.subscription_sub_2
+}
+`, string(hclwrite.Format(workspaceToHCLFile["app"].Bytes())))
+}
@@ -0,0 +1,247 @@
+package hclcreate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+)
+
+// ResourceNames maps each new resource, identified as division.type.terraformer-name, to the name of the resource
+// within the generated Terraform code.
+type ResourceNames map[string]string
+
+// ConvertTerraformerResourceName takes an input resource name as output by the Terraformer
+// package and outputs the resource in gold-standard Terraform name format.
+func ConvertTerraformerResourceName(name string) string {
+	intermediateString := strings.Replace(name, "tfer--", "", -1)
+
+	// Terraform names may only contain letters, digits, underscores and dashes, while dashes are replaced
+	// for consistency with the underscores of Terraform's own naming conventions.
+	finalResourceName := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+		return '_'
+	}, intermediateString)
+
+	// Terraform names must begin with a letter or an underscore.
+	if finalResourceName == "" || (finalResourceName[0] >= '0' && finalResourceName[0] <= '9') {
+		finalResourceName = "_" + finalResourceName
+	}
+
+	return finalResourceName
+}
+
+// ConvertTerraformerResourceAddress converts the name within a type.name resource address as output by the
+// Terraformer package, e.g. aws_s3_bucket.tfer--my-bucket, into gold-standard Terraform name format.
+func ConvertTerraformerResourceAddress(address string) string {
+	resourceType, name, found := strings.Cut(address, ".")
+	if !found {
+		return ConvertTerraformerResourceName(address)
+	}
+	return fmt.Sprintf("%v.%v", resourceType, ConvertTerraformerResourceName(name))
+}
+
+// ResourceNamesPath is the mapping file of the name of each new resource within the generated Terraform code, which
+// the cloud actors, costs, inventory and generated code of a new resource are all keyed on.
+const ResourceNamesPath = "mappings/new-resources-to-names.json"
+
+// Name returns the name within the generated Terraform code of the resource of a full division, e.g. aws-prod, with
+// the given type and Terraformer name. Resources without a recorded name fall back to their converted Terraformer name.
+func (n ResourceNames) Name(fullDivision string, resourceType string, terraformerName string) string {
+	if name, ok := n[fmt.Sprintf("%v.%v.%v", fullDivision, resourceType, terraformerName)]; ok {
+		return name
+	}
+	return ConvertTerraformerResourceName(terraformerName)
+}
+
+// Address returns the type.name address within the generated Terraform code of the resource of a full division with
+// the Terraformer address terraformerAddress, e.g. aws_s3_bucket.tfer--my-bucket.
+func (n ResourceNames) Address(fullDivision string, terraformerAddress string) string {
+	resourceType, terraformerName, found := strings.Cut(terraformerAddress, ".")
+	if !found {
+		return ConvertTerraformerResourceName(terraformerAddress)
+	}
+	return fmt.Sprintf("%v.%v", resourceType, n.Name(fullDivision, resourceType, terraformerName))
+}
+
+// NewResourceNames returns the name within the generated Terraform code of each resource of newResourceToWorkspace.
+// When a resource's converted name is already taken by a resource of the same type, either another new resource,
+// such as tfer--my-bucket and tfer--my_bucket, or a resource declared within the target workspace's directory, the
+// resource is given a numeric suffix, e.g. my_bucket_2, with new resources named in sorted order.
+func NewResourceNames(newResourceToWorkspace NewResourceToWorkspace, workspaceToDirectory map[string]string) (ResourceNames, error) {
+	workspaceToDeclared := map[string]map[string]bool{}
+	for _, workspace := range newResourceToWorkspace {
+		if _, ok := workspaceToDeclared[workspace]; ok {
+			continue
+		}
+
+		directory, ok := workspaceToDirectory[workspace]
+		if !ok {
+			workspaceToDeclared[workspace] = map[string]bool{}
+			continue
+		}
+
+		declared, err := declaredResourceAddresses(fmt.Sprintf("repo%v", directory))
+		if err != nil {
+			return nil, fmt.Errorf("[declaredResourceAddresses] %v", err)
+		}
+		workspaceToDeclared[workspace] = declared
+	}
+
+	return assignResourceNames(newResourceToWorkspace, ResourceNames{}, workspaceToDeclared), nil
+}
+
+// LoadResourceNames reads the names of the new resources from mappings/new-resources-to-names.json, which is empty
+// when no new resources were identified.
+func LoadResourceNames(store artifacts.Store) (ResourceNames, error) {
+	resourceNames := ResourceNames{}
+	if !store.Exists(ResourceNamesPath) {
+		return resourceNames, nil
+	}
+
+	content, err := store.ReadFile(ResourceNamesPath)
+	if err != nil {
+		return nil, fmt.Errorf("[artifacts.ReadFile] %v error: %v", ResourceNamesPath, err)
+	}
+
+	err = json.Unmarshal(content, &resourceNames)
+	if err != nil {
+		return nil, fmt.Errorf("[json.Unmarshal] error unmarshalling `resourceNames`: %v", err)
+	}
+	return resourceNames, nil
+}
+
+// resourceNames returns the name within the generated Terraform code of each resource of newResourceToWorkspace, as
+// recorded within mappings/new-resources-to-names.json. Resources without a recorded name are named after the
+// recorded names.
+func (h *hclCreate) resourceNames(newResourceToWorkspace NewResourceToWorkspace) (ResourceNames, error) {
+	recordedNames, err := LoadResourceNames(h.artifactStore)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceNames := ResourceNames{}
+	for resource := range newResourceToWorkspace {
+		if name, ok := recordedNames[resource]; ok {
+			resourceNames[resource] = name
+		}
+	}
+	return assignResourceNames(newResourceToWorkspace, resourceNames, nil), nil
+}
+
+// assignResourceNames names the resources of newResourceToWorkspace absent from resourceNames, in sorted order,
+// avoiding the addresses of the named resources and those declared within each target workspace.
+func assignResourceNames(
+	newResourceToWorkspace NewResourceToWorkspace,
+	resourceNames ResourceNames,
+	workspaceToDeclared map[string]map[string]bool,
+) ResourceNames {
+	resources := make([]string, 0, len(newResourceToWorkspace))
+	for resource := range newResourceToWorkspace {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+
+	usedAddresses := map[string]bool{}
+	for resource, name := range resourceNames {
+		usedAddresses[fmt.Sprintf("%v.%v", parseResourceIdentifier(resource).resourceType, name)] = true
+	}
+
+	for _, resource := range resources {
+		if _, ok := resourceNames[resource]; ok {
+			continue
+		}
+
+		resourceID := parseResourceIdentifier(resource)
+		declared := workspaceToDeclared[newResourceToWorkspace[resource]]
+		isTaken := func(name string) bool {
+			address := fmt.Sprintf("%v.%v", resourceID.resourceType, name)
+			return usedAddresses[address] || declared[address]
+		}
+
+		baseName := ConvertTerraformerResourceName(resourceID.resourceName)
+		name := baseName
+		for suffix := 2; isTaken(name); suffix++ {
+			name = fmt.Sprintf("%v_%v", baseName, suffix)
+		}
+
+		usedAddresses[fmt.Sprintf("%v.%v", resourceID.resourceType, name)] = true
+		resourceNames[resource] = name
+	}
+
+	return resourceNames
+}
+
+// declaredResourceAddresses returns the type.name addresses of the resources declared within the .tf files of a
+// directory, which is empty when the directory does not exist.
+func declaredResourceAddresses(directory string) (map[string]bool, error) {
+	paths, err := filepath.Glob(filepath.Join(directory, "*.tf"))
+	if err != nil {
+		return nil, fmt.Errorf("[filepath.Glob] %v", err)
+	}
+
+	addresses := map[string]bool{}
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("[os.ReadFile] Error reading %v: %v", path, err)
+		}
+
+		f, diags := hclwrite.ParseConfig(content, path, hcl.InitialPos)
+		if diags.HasErrors() {
+			continue
+		}
+		for _, block := range f.Body().Blocks() {
+			if block.Type() == "resource" && len(block.Labels()) == 2 {
+				addresses[strings.Join(block.Labels(), ".")] = true
+			}
+		}
+	}
+	return addresses, nil
+}
+
+// WriteResourceNames writes the names of the new resources to mappings/new-resources-to-names.json.
+func WriteResourceNames(store artifacts.Store, resourceNames ResourceNames) error {
+	content, err := json.MarshalIndent(resourceNames, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[json.MarshalIndent] error marshalling `resourceNames`: %v", err)
+	}
+
+	err = store.WriteFile(ResourceNamesPath, content, 0400)
+	if err != nil {
+		return fmt.Errorf("[artifacts.WriteFile] Error writing %v: %v", ResourceNamesPath, err)
+	}
+	return nil
+}
+
+// renameResourceReferences renames, within the attributes of a block and of its nested blocks, the references to
+// the resources of the same division whose names within the generated Terraform code differ from their names
+// within the Terraformer output.
+func renameResourceReferences(body *hclwrite.Body, renames map[ResourceIdentifier]string) {
+	for _, attribute := range body.Attributes() {
+		expressionBytes := attribute.Expr().BuildTokens(nil).Bytes()
+		for resourceID, name := range renames {
+			if !bytes.Contains(expressionBytes, []byte(resourceID.resourceName)) {
+				continue
+			}
+			attribute.Expr().RenameVariablePrefix(
+				[]string{resourceID.resourceType, resourceID.resourceName},
+				[]string{resourceID.resourceType, name},
+			)
+		}
+	}
+
+	for _, block := range body.Blocks() {
+		renameResourceReferences(block.Body(), renames)
+	}
+}
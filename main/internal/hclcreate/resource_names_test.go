@@ -0,0 +1,116 @@
+package hclcreate
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+)
+
+func TestConvertTerraformerResourceNameIllegalCharacters(t *testing.T) {
+	assert.Equal(t, "my_bucket_logs", ConvertTerraformerResourceName("tfer--my-bucket.logs"))
+	assert.Equal(t, "_0123_instance", ConvertTerraformerResourceName("tfer--0123-instance"))
+	assert.Equal(t, "caf_", ConvertTerraformerResourceName("tfer--café"))
+	assert.Equal(t, "_", ConvertTerraformerResourceName("tfer--"))
+}
+
+func TestConvertTerraformerResourceAddress(t *testing.T) {
+	assert.Equal(t, "aws_s3_bucket.my_bucket", ConvertTerraformerResourceAddress("aws_s3_bucket.tfer--my-bucket"))
+}
+
+func TestNewResourceNames(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	require.NoError(t, os.MkdirAll("repo/prod", 0700))
+	require.NoError(t, os.WriteFile("repo/prod/main.tf", []byte(`resource "aws_sqs_queue" "my_bucket" {}
+`), 0400))
+
+	newResourceToWorkspace := NewResourceToWorkspace{
+		"aws-dev.aws_s3_bucket.tfer--my-bucket":  "dev",
+		"aws-dev.aws_s3_bucket.tfer--my_bucket":  "dev",
+		"aws-prod.aws_s3_bucket.tfer--my-bucket": "prod",
+		"aws-dev.aws_sqs_queue.tfer--my-bucket":  "dev",
+		"aws-prod.aws_sqs_queue.tfer--my-bucket": "prod",
+	}
+
+	// When
+	resourceNames, err := NewResourceNames(newResourceToWorkspace, map[string]string{"dev": "/dev/", "prod": "/prod/"})
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, ResourceNames{
+		"aws-dev.aws_s3_bucket.tfer--my-bucket":  "my_bucket",
+		"aws-dev.aws_s3_bucket.tfer--my_bucket":  "my_bucket_2",
+		"aws-prod.aws_s3_bucket.tfer--my-bucket": "my_bucket_3",
+		"aws-dev.aws_sqs_queue.tfer--my-bucket":  "my_bucket",
+		"aws-prod.aws_sqs_queue.tfer--my-bucket": "my_bucket_2",
+	}, resourceNames)
+	assert.Equal(t, "aws_sqs_queue.my_bucket_2", resourceNames.Address("aws-prod", "aws_sqs_queue.tfer--my-bucket"))
+	assert.Equal(t, "aws_sqs_queue.other", resourceNames.Address("aws-prod", "aws_sqs_queue.tfer--other"))
+}
+
+func TestResourceNames_Recorded(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	require.NoError(t, os.MkdirAll("mappings", 0700))
+	h := hclCreate{artifactStore: artifacts.NewMemoryStore(true)}
+	require.NoError(t, WriteResourceNames(h.artifactStore, ResourceNames{"aws-dev.aws_s3_bucket.tfer--my-bucket": "my_bucket_2"}))
+
+	newResourceToWorkspace := NewResourceToWorkspace{
+		"aws-dev.aws_s3_bucket.tfer--my-bucket": "dev",
+		"aws-dev.aws_s3_bucket.tfer--my_bucket": "dev",
+	}
+
+	// When
+	resourceNames, err := h.resourceNames(newResourceToWorkspace)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, ResourceNames{
+		"aws-dev.aws_s3_bucket.tfer--my-bucket": "my_bucket_2",
+		"aws-dev.aws_s3_bucket.tfer--my_bucket": "my_bucket",
+	}, resourceNames)
+}
+
+func TestRenameResourceReferences(t *testing.T) {
+	// Given
+	f, diags := hclwrite.ParseConfig([]byte(`resource "aws_subnet" "tfer--private" {
+  vpc_id     = aws_vpc.tfer--main.id
+  cidr_block = "${aws_vpc.tfer--main.cidr_block}"
+
+  tags = {
+    Name = "tfer--main"
+  }
+}
+`), "", hcl.InitialPos)
+	require.False(t, diags.HasErrors())
+
+	renames := map[ResourceIdentifier]string{
+		{division: "aws-dev", resourceType: "aws_vpc", resourceName: "tfer--main"}: "main",
+	}
+
+	// When
+	renameResourceReferences(f.Body().Blocks()[0].Body(), renames)
+
+	// Then
+	assert.Equal(t, `resource "aws_subnet" "tfer--private" {
+  vpc_id     = aws_vpc.main.id
+  cidr_block = "${aws_vpc.main.cidr_block}"
+
+  tags = {
+    Name = "tfer--main"
+  }
+}
+`, string(f.Bytes()))
+}
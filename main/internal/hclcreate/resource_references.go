@@ -0,0 +1,295 @@
+package hclcreate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+)
+
+// DivisionToResourceIdentifiers is a map between the full provider-division name of each division and the cloud
+// identifiers, keyed by attribute, of each of its terraformer-generated resources, keyed as type.terraformer-name.
+type DivisionToResourceIdentifiers map[string]map[string]map[string]string
+
+// identifierAttributes are the attributes of a resource whose values other resources use to refer to it, in order
+// of preference when a value is held by more than one of them.
+var identifierAttributes = []string{"id", "arn", "self_link"}
+
+// unreferencedAttributes are the attributes whose values are never rewritten into references, as they coincide
+// with the identifiers of other resources by name only.
+var unreferencedAttributes = map[string]bool{
+	"name":        true,
+	"name_prefix": true,
+	"description": true,
+}
+
+// generatedResource is a resource block extracted from the terraformer output for a workspace, along with the
+// comments heading it.
+type generatedResource struct {
+	// resourceID identifies the terraformer-generated resource.
+	resourceID ResourceIdentifier
+
+	// address is the address of the generated resource block, type.name.
+	address string
+
+	// block is the generated resource block.
+	block *hclwrite.Block
+
+	// cloudIdentifierComment describes the cloud actor actions on the resource.
+	cloudIdentifierComment hclwrite.Tokens
+
+	// cloudCostComment describes the cost of the resource.
+	cloudCostComment hclwrite.Tokens
+}
+
+// loadDivisionResourceIdentifiers reads the cloud identifiers of each resource within the terraformer state file of
+// each division. Divisions without a terraformer state file have no known resource identifiers.
+func (h *hclCreate) loadDivisionResourceIdentifiers() (DivisionToResourceIdentifiers, error) {
+	divisionToResourceIdentifiers := DivisionToResourceIdentifiers{}
+
+	for division, provider := range h.divisionToProvider {
+		fullDivisionName := fmt.Sprintf("%v-%v", provider, division)
+		divisionToResourceIdentifiers[fullDivisionName] = map[string]map[string]string{}
+
+		stateBytes, err := os.ReadFile(fmt.Sprintf("current_cloud/%v/terraform.tfstate", fullDivisionName))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("[os.ReadFile] Error reading in terraform.tfstate for %v: %v", fullDivisionName, err)
+		}
+
+		var stateFile driftDetector.TerraformerStateFile
+		err = json.Unmarshal(stateBytes, &stateFile)
+		if err != nil {
+			return nil, fmt.Errorf("[json.Unmarshal] Error parsing terraform.tfstate for %v: %v", fullDivisionName, err)
+		}
+
+		for _, resource := range stateFile.Resources {
+			if len(resource.Instances) == 0 {
+				continue
+			}
+
+			identifiers := map[string]string{}
+			for _, attribute := range identifierAttributes {
+				if value := resource.Instances[0].AttributesFlat[attribute]; value != "" {
+					identifiers[attribute] = value
+				}
+			}
+			divisionToResourceIdentifiers[fullDivisionName][fmt.Sprintf("%v.%v", resource.Type, resource.Name)] = identifiers
+		}
+	}
+
+	return divisionToResourceIdentifiers, nil
+}
+
+// rewriteResourceReferences replaces the hardcoded cloud identifiers within the generated resources of a workspace
+// that identify another of its generated resources with a reference to that resource, e.g. "subnet-abc123" with
+// aws_subnet.private.id. Identifiers held by more than one of the generated resources are ambiguous and left as is,
+// as are those whose reference would introduce a dependency cycle.
+func rewriteResourceReferences(resources []*generatedResource, divisionToResourceIdentifiers DivisionToResourceIdentifiers) {
+	valueToTraversals := map[string][]hcl.Traversal{}
+	for _, resource := range resources {
+		identifiers := divisionToResourceIdentifiers[resource.resourceID.division][fmt.Sprintf(
+			"%v.%v", resource.resourceID.resourceType, resource.resourceID.resourceName,
+		)]
+
+		resourceValues := map[string]bool{}
+		for _, attribute := range identifierAttributes {
+			value, ok := identifiers[attribute]
+			if !ok || resourceValues[value] {
+				continue
+			}
+			resourceValues[value] = true
+			valueToTraversals[value] = append(valueToTraversals[value], hcl.Traversal{
+				hcl.TraverseRoot{Name: resource.resourceID.resourceType},
+				hcl.TraverseAttr{Name: resource.block.Labels()[1]},
+				hcl.TraverseAttr{Name: attribute},
+			})
+		}
+	}
+
+	dependencies := resourceDependencies(resources)
+	for _, resource := range resources {
+		rewriteBodyReferences(resource.block.Body(), resource.address, valueToTraversals, dependencies)
+	}
+}
+
+// rewriteBodyReferences rewrites the literal string and string list attributes of body, and of its nested blocks,
+// holding the identifiers of generated resources into references, recording each new dependency of address.
+func rewriteBodyReferences(
+	body *hclwrite.Body,
+	address string,
+	valueToTraversals map[string][]hcl.Traversal,
+	dependencies map[string]map[string]bool,
+) {
+	// resolve returns the reference for value, if it unambiguously identifies a generated resource other than
+	// address that does not already depend upon address.
+	resolve := func(value string) (hcl.Traversal, bool) {
+		traversals := valueToTraversals[value]
+		if len(traversals) != 1 {
+			return nil, false
+		}
+		dependency := fmt.Sprintf("%v.%v", traversals[0].RootName(), traversals[0][1].(hcl.TraverseAttr).Name)
+		if dependency == address || dependsOn(dependencies, dependency, address) {
+			return nil, false
+		}
+		if _, ok := dependencies[address]; !ok {
+			dependencies[address] = map[string]bool{}
+		}
+		dependencies[address][dependency] = true
+		return traversals[0], true
+	}
+
+	attributes := body.Attributes()
+	attributeNames := make([]string, 0, len(attributes))
+	for name := range attributes {
+		attributeNames = append(attributeNames, name)
+	}
+	sort.Strings(attributeNames)
+
+	for _, name := range attributeNames {
+		if unreferencedAttributes[name] {
+			continue
+		}
+
+		expressionBytes := attributes[name].Expr().BuildTokens(nil).Bytes()
+		expression, diags := hclsyntax.ParseExpression(expressionBytes, name, hcl.InitialPos)
+		if diags.HasErrors() {
+			continue
+		}
+		value, diags := expression.Value(nil)
+		if diags.HasErrors() || value.IsNull() || !value.IsWhollyKnown() {
+			continue
+		}
+
+		switch {
+		case value.Type() == cty.String:
+			if traversal, ok := resolve(value.AsString()); ok {
+				body.SetAttributeTraversal(name, traversal)
+			}
+		case (value.Type().IsTupleType() || value.Type().IsListType()) && allStrings(value.AsValueSlice()):
+			elements := []hclwrite.Tokens{}
+			rewritten := false
+			for _, element := range value.AsValueSlice() {
+				if traversal, ok := resolve(element.AsString()); ok {
+					elements = append(elements, hclwrite.TokensForTraversal(traversal))
+					rewritten = true
+					continue
+				}
+				elements = append(elements, hclwrite.TokensForValue(element))
+			}
+			if rewritten {
+				body.SetAttributeRaw(name, hclwrite.TokensForTuple(elements))
+			}
+		}
+	}
+
+	for _, block := range body.Blocks() {
+		rewriteBodyReferences(block.Body(), address, valueToTraversals, dependencies)
+	}
+}
+
+// allStrings returns true if each of values is a non-null string.
+func allStrings(values []cty.Value) bool {
+	for _, value := range values {
+		if value.IsNull() || value.Type() != cty.String {
+			return false
+		}
+	}
+	return true
+}
+
+// resourceDependencies returns the generated resources referenced by each of resources, keyed by address.
+func resourceDependencies(resources []*generatedResource) map[string]map[string]bool {
+	addresses := map[string]bool{}
+	for _, resource := range resources {
+		addresses[resource.address] = true
+	}
+
+	dependencies := map[string]map[string]bool{}
+	for _, resource := range resources {
+		dependencies[resource.address] = map[string]bool{}
+		for _, reference := range blockReferences(resource.block.Body()) {
+			if addresses[reference] && reference != resource.address {
+				dependencies[resource.address][reference] = true
+			}
+		}
+	}
+	return dependencies
+}
+
+// dependsOn returns true if address depends upon dependency, directly or through other resources.
+func dependsOn(dependencies map[string]map[string]bool, address string, dependency string) bool {
+	visited := map[string]bool{}
+	stack := []string{address}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+
+		for next := range dependencies[current] {
+			if next == dependency {
+				return true
+			}
+			stack = append(stack, next)
+		}
+	}
+	return false
+}
+
+// orderByDependencies returns resources ordered so that each resource follows the generated resources it references,
+// and otherwise by address. Resources within a dependency cycle follow in order of address.
+func orderByDependencies(resources []*generatedResource) []*generatedResource {
+	sorted := append([]*generatedResource{}, resources...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].address < sorted[j].address
+	})
+
+	dependencies := resourceDependencies(sorted)
+	ordered := make([]*generatedResource, 0, len(sorted))
+	placed := map[string]bool{}
+	for len(ordered) < len(sorted) {
+		progressed := false
+		for _, resource := range sorted {
+			if placed[resource.address] || !dependenciesPlaced(dependencies[resource.address], placed) {
+				continue
+			}
+			ordered = append(ordered, resource)
+			placed[resource.address] = true
+			progressed = true
+			break
+		}
+
+		if !progressed {
+			for _, resource := range sorted {
+				if !placed[resource.address] {
+					ordered = append(ordered, resource)
+					placed[resource.address] = true
+				}
+			}
+		}
+	}
+	return ordered
+}
+
+// dependenciesPlaced returns true if each of dependencies has been placed.
+func dependenciesPlaced(dependencies map[string]bool, placed map[string]bool) bool {
+	for dependency := range dependencies {
+		if !placed[dependency] {
+			return false
+		}
+	}
+	return true
+}
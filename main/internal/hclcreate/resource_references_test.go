@@ -0,0 +1,145 @@
+package hclcreate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parseGeneratedResources parses the resource blocks of content as generated resources of division aws-prod, named
+// tfer--<name> by terraformer.
+func parseGeneratedResources(t *testing.T, content string) []*generatedResource {
+	f, diags := hclwrite.ParseConfig([]byte(content), "new-resources.tf", hcl.InitialPos)
+	require.False(t, diags.HasErrors(), diags.Error())
+
+	resources := []*generatedResource{}
+	for _, block := range f.Body().Blocks() {
+		resources = append(resources, &generatedResource{
+			resourceID: ResourceIdentifier{
+				division:     "aws-prod",
+				resourceType: block.Labels()[0],
+				resourceName: "tfer--" + block.Labels()[1],
+			},
+			address: block.Labels()[0] + "." + block.Labels()[1],
+			block:   block,
+		})
+	}
+	return resources
+}
+
+// attributeExpression returns the expression assigned to the attribute of block.
+func attributeExpression(block *hclwrite.Block, attribute string) string {
+	return strings.TrimSpace(string(block.Body().GetAttribute(attribute).Expr().BuildTokens(nil).Bytes()))
+}
+
+func TestRewriteResourceReferences(t *testing.T) {
+	// Given
+	resources := parseGeneratedResources(t, `
+resource "aws_instance" "web" {
+  subnet_id              = "subnet-abc123"
+  vpc_security_group_ids = ["sg-111", "sg-external"]
+  tags = {
+    Subnet = "subnet-abc123"
+  }
+}
+
+resource "aws_subnet" "private" {
+  vpc_id = "vpc-1"
+  name   = "subnet-abc123"
+}
+
+resource "aws_security_group" "web" {
+  vpc_id = "vpc-1"
+}
+
+resource "aws_vpc" "main" {
+  cidr_block = "10.0.0.0/16"
+}
+`)
+	identifiers := DivisionToResourceIdentifiers{"aws-prod": {
+		"aws_instance.tfer--web":       {"id": "i-1", "arn": "arn:aws:ec2:us-east-1:123:instance/i-1"},
+		"aws_subnet.tfer--private":     {"id": "subnet-abc123"},
+		"aws_security_group.tfer--web": {"id": "sg-111"},
+		"aws_vpc.tfer--main":           {"id": "vpc-1"},
+	}}
+
+	// When
+	rewriteResourceReferences(resources, identifiers)
+
+	// Then
+	f := hclwrite.NewEmptyFile()
+	for _, resource := range orderByDependencies(resources) {
+		f.Body().AppendBlock(resource.block)
+	}
+	assert.Equal(t, `resource "aws_vpc" "main" {
+  cidr_block = "10.0.0.0/16"
+}
+resource "aws_security_group" "web" {
+  vpc_id = aws_vpc.main.id
+}
+resource "aws_subnet" "private" {
+  vpc_id = aws_vpc.main.id
+  name   = "subnet-abc123"
+}
+resource "aws_instance" "web" {
+  subnet_id              = aws_subnet.private.id
+  vpc_security_group_ids = [aws_security_group.web.id, "sg-external"]
+  tags = {
+    Subnet = "subnet-abc123"
+  }
+}
+`, string(hclwrite.Format(f.Bytes())))
+}
+
+func TestRewriteResourceReferencesAvoidsCycles(t *testing.T) {
+	// Given
+	resources := parseGeneratedResources(t, `
+resource "aws_security_group" "a" {
+  peer = "sg-b"
+}
+
+resource "aws_security_group" "b" {
+  peer = "sg-a"
+}
+`)
+	identifiers := DivisionToResourceIdentifiers{"aws-prod": {
+		"aws_security_group.tfer--a": {"id": "sg-a"},
+		"aws_security_group.tfer--b": {"id": "sg-b"},
+	}}
+
+	// When
+	rewriteResourceReferences(resources, identifiers)
+
+	// Then
+	assert.Equal(t, "aws_security_group.b.id", attributeExpression(resources[0].block, "peer"))
+	assert.Equal(t, `"sg-a"`, attributeExpression(resources[1].block, "peer"))
+}
+
+func TestRewriteResourceReferencesAmbiguousIdentifier(t *testing.T) {
+	// Given
+	resources := parseGeneratedResources(t, `
+resource "aws_s3_bucket_policy" "logs" {
+  bucket = "logs"
+}
+
+resource "aws_s3_bucket" "logs" {
+}
+
+resource "aws_cloudwatch_log_group" "logs" {
+}
+`)
+	identifiers := DivisionToResourceIdentifiers{"aws-prod": {
+		"aws_s3_bucket.tfer--logs":            {"id": "logs"},
+		"aws_cloudwatch_log_group.tfer--logs": {"id": "logs"},
+	}}
+
+	// When
+	rewriteResourceReferences(resources, identifiers)
+
+	// Then
+	assert.Equal(t, `"logs"`, attributeExpression(resources[0].block, "bucket"))
+}
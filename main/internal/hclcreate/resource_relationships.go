@@ -0,0 +1,65 @@
+package hclcreate
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+)
+
+// WorkspaceToResourceRelationships is a map between a workspace and the addresses of the new resources placed within
+// it, each mapped to the addresses of the other new resources of the workspace that it references.
+type WorkspaceToResourceRelationships map[string]map[string][]string
+
+// resourceRelationships returns the references between the resources placed within each workspace's new resources
+// file, from which the state of cloud report diagrams the new resources of each workspace.
+func resourceRelationships(workspaceToHCLFile WorkspaceToHCL) WorkspaceToResourceRelationships {
+	relationships := WorkspaceToResourceRelationships{}
+	for workspace, hclFile := range workspaceToHCLFile {
+		addresses := map[string]bool{}
+		for _, block := range hclFile.Body().Blocks() {
+			if block.Type() == "resource" && len(block.Labels()) == 2 {
+				addresses[fmt.Sprintf("%v.%v", block.Labels()[0], block.Labels()[1])] = true
+			}
+		}
+		if len(addresses) == 0 {
+			continue
+		}
+
+		relationships[workspace] = map[string][]string{}
+		for _, block := range hclFile.Body().Blocks() {
+			if block.Type() != "resource" || len(block.Labels()) != 2 {
+				continue
+			}
+			address := fmt.Sprintf("%v.%v", block.Labels()[0], block.Labels()[1])
+
+			referenced := map[string]bool{}
+			references := []string{}
+			for _, reference := range blockReferences(block.Body()) {
+				if addresses[reference] && reference != address && !referenced[reference] {
+					referenced[reference] = true
+					references = append(references, reference)
+				}
+			}
+			sort.Strings(references)
+			relationships[workspace][address] = references
+		}
+	}
+	return relationships
+}
+
+// writeResourceRelationships writes the references between new resources to
+// mappings/new-resources-relationships.json for the state of cloud report.
+func writeResourceRelationships(store artifacts.Store, relationships WorkspaceToResourceRelationships) error {
+	content, err := json.MarshalIndent(relationships, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[json.MarshalIndent] error marshalling `relationships`: %v", err)
+	}
+
+	err = store.WriteFile("mappings/new-resources-relationships.json", content, 0400)
+	if err != nil {
+		return fmt.Errorf("[artifacts.WriteFile] Error writing mappings/new-resources-relationships.json: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package hclcreate
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceRelationships(t *testing.T) {
+	// Given
+	appFile, diags := hclwrite.ParseConfig([]byte(`provider "aws" {
+  region = var.region
+}
+
+resource "aws_vpc" "main" {
+  cidr_block = "10.0.0.0/16"
+}
+
+resource "aws_subnet" "private" {
+  vpc_id = aws_vpc.main.id
+}
+
+resource "aws_security_group" "web" {
+  vpc_id = aws_vpc.main.id
+
+  egress {
+    cidr_blocks = [aws_subnet.private.cidr_block, aws_vpc.main.cidr_block]
+  }
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket = data.aws_caller_identity.current.account_id
+}
+`), "", hcl.InitialPos)
+	require.False(t, diags.HasErrors())
+
+	emptyFile := hclwrite.NewEmptyFile()
+
+	// When
+	relationships := resourceRelationships(WorkspaceToHCL{"app": appFile, "empty": emptyFile})
+
+	// Then
+	assert.Equal(t, WorkspaceToResourceRelationships{
+		"app": {
+			"aws_vpc.main":           {},
+			"aws_subnet.private":     {"aws_vpc.main"},
+			"aws_security_group.web": {"aws_subnet.private", "aws_vpc.main"},
+			"aws_s3_bucket.logs":     {},
+		},
+	}, relationships)
+}
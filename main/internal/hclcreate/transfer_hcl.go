@@ -25,26 +25,24 @@ func (h *hclCreate) ExtractResourceDefinitions(noNewResources bool, workspaceToD
 	// Create mapping between provider-division and the corresponding terraformer-generated resources file,
 	// Read in the required corresponding files.
 	divisionToTerraformerResources := DivisionToHCL{}
-	divisionToResourceActions := terraformValueObjects.DivisionResourceActions{}
-
-	rawCloudActions, err := os.ReadFile("mappings/resources-to-cloud-actions.json")
-	if err != nil {
-		return fmt.Errorf("[os.ReadFile resources-to-cloud-actions.json]%v", err)
-	}
-	parsedCloudActions, err := gabs.ParseJSON(rawCloudActions)
+	divisionToResourceActions, err := h.loadDivisionResourceActions()
 	if err != nil {
-		return fmt.Errorf("[gabs.ParseJSON rawCloudActions]%v", err)
+		return fmt.Errorf("[h.loadDivisionResourceActions]%v", err)
 	}
 
-	rawCloudCosts, err := os.ReadFile("mappings/division-to-cost-estimates.json")
+	rawCloudCosts, err := h.artifactStore.ReadFile("mappings/division-to-cost-estimates.json")
 	if err != nil {
-		return fmt.Errorf("[os.ReadFile resources-to-cloud-actions.json]%v", err)
+		return fmt.Errorf("[artifacts.ReadFile resources-to-cloud-actions.json]%v", err)
 	}
 	parsedCloudCosts, err := gabs.ParseJSON(rawCloudCosts)
 	if err != nil {
 		return fmt.Errorf("[gabs.ParseJSON rawCloudCosts]%v", err)
 	}
-	divisionToCostEstimates, err := gabsContainerToAllCostsStruct(parsedCloudCosts)
+	recordedNames, err := LoadResourceNames(h.artifactStore)
+	if err != nil {
+		return fmt.Errorf("[LoadResourceNames]%v", err)
+	}
+	divisionToCostEstimates, err := gabsContainerToAllCostsStruct(parsedCloudCosts, recordedNames)
 	if err != nil {
 		return fmt.Errorf("[gabsContainerToAllCostsStruct]%v", err)
 	}
@@ -68,20 +66,14 @@ func (h *hclCreate) ExtractResourceDefinitions(noNewResources bool, workspaceToD
 			return fmt.Errorf("[hclwrite.ParseConfig] Error for %v: %v", fullDivisionName, hclDiagnostics)
 		}
 		divisionToTerraformerResources[fullDivisionName] = divisionFile
-
-		resourceIDToCloudActions, err := h.subsetCloudActionsToCurrentDivision(string(provider), string(division), parsedCloudActions)
-		if err != nil {
-			return fmt.Errorf("[h.subsetCloudActionsToCurrentDivision]%v", err)
-		}
-		divisionToResourceActions[terraformValueObjects.Division(fullDivisionName)] = resourceIDToCloudActions
 	}
 
 	// Read in new-resources-to-workspace.json, parse as gabs file
 	newResourcesToWorkspace := []byte("{}")
 	if !noNewResources {
-		newResourcesToWorkspace, err = os.ReadFile("mappings/new-resources-to-workspace.json")
+		newResourcesToWorkspace, err = h.artifactStore.ReadFile("mappings/new-resources-to-workspace.json")
 		if err != nil {
-			return fmt.Errorf("[os.ReadFile()] Error reading in new-resources-to-workspace.json: %v", err)
+			return fmt.Errorf("[artifacts.ReadFile()] Error reading in new-resources-to-workspace.json: %v", err)
 		}
 	}
 
@@ -91,11 +83,32 @@ func (h *hclCreate) ExtractResourceDefinitions(noNewResources bool, workspaceToD
 		return fmt.Errorf("[gabs.ParseJSON] Error parsing new-resources-to-workspace.json")
 	}
 
+	newResourceToWorkspace := NewResourceToWorkspace{}
+	for resource, workspace := range parsedNewResourceToWorkspace.ChildrenMap() {
+		newResourceToWorkspace[resource] = workspace.Data().(string)
+	}
+	resourceNames, err := h.resourceNames(newResourceToWorkspace)
+	if err != nil {
+		return fmt.Errorf("[h.resourceNames] %v", err)
+	}
+
+	err = WriteResourceNames(h.artifactStore, resourceNames)
+	if err != nil {
+		return fmt.Errorf("[WriteResourceNames] %v", err)
+	}
+
+	divisionToResourceIdentifiers, err := h.loadDivisionResourceIdentifiers()
+	if err != nil {
+		return fmt.Errorf("[h.loadDivisionResourceIdentifiers] %v", err)
+	}
+
 	completeWorkspaceToHCLFile, err := h.placeHCLIntoNewFileDef(
 		divisionToResourceActions,
 		divisionToCostEstimates,
 		divisionToTerraformerResources,
 		parsedNewResourceToWorkspace,
+		resourceNames,
+		divisionToResourceIdentifiers,
 		workspaceToHCLFile,
 	)
 
@@ -103,9 +116,41 @@ func (h *hclCreate) ExtractResourceDefinitions(noNewResources bool, workspaceToD
 		return fmt.Errorf("[h.placeHCLIntoNewFileDef] %v", err)
 	}
 
+	divisionToResourceRegions, divisionToAccount, err := h.loadDivisionResourceRegions()
+	if err != nil {
+		return fmt.Errorf("[h.loadDivisionResourceRegions] %v", err)
+	}
+	completeWorkspaceToHCLFile, err = h.setProviderAliases(
+		completeWorkspaceToHCLFile,
+		workspaceToDirectory,
+		newResourceToWorkspace,
+		resourceNames,
+		divisionToResourceRegions,
+		divisionToAccount,
+	)
+	if err != nil {
+		return fmt.Errorf("[h.setProviderAliases] %v", err)
+	}
+
+	err = writeResourceRelationships(h.artifactStore, resourceRelationships(completeWorkspaceToHCLFile))
+	if err != nil {
+		return fmt.Errorf("[writeResourceRelationships] %v", err)
+	}
+
+	err = writeResourceModules(h.artifactStore, h.resourceModules(completeWorkspaceToHCLFile, newResourceToWorkspace, resourceNames))
+	if err != nil {
+		return fmt.Errorf("[writeResourceModules] %v", err)
+	}
+
+	addressToFile, err := h.newResourceFiles(newResourceToWorkspace, resourceNames)
+	if err != nil {
+		return fmt.Errorf("[h.newResourceFiles] %v", err)
+	}
+
 	err = h.writeNewResourceFiles(
 		workspaceToDirectory,
 		completeWorkspaceToHCLFile,
+		addressToFile,
 	)
 	if err != nil {
 		return fmt.Errorf("[h.writeNewHCLFiles] %v", err)
@@ -114,6 +159,33 @@ func (h *hclCreate) ExtractResourceDefinitions(noNewResources bool, workspaceToD
 	return nil
 }
 
+// loadDivisionResourceActions reads in the cloud actor actions identified for each resource, keyed by the full
+// provider-division name of each division.
+func (h *hclCreate) loadDivisionResourceActions() (terraformValueObjects.DivisionResourceActions, error) {
+	divisionToResourceActions := terraformValueObjects.DivisionResourceActions{}
+
+	rawCloudActions, err := h.artifactStore.ReadFile("mappings/resources-to-cloud-actions.json")
+	if err != nil {
+		return nil, fmt.Errorf("[artifacts.ReadFile resources-to-cloud-actions.json]%v", err)
+	}
+	parsedCloudActions, err := gabs.ParseJSON(rawCloudActions)
+	if err != nil {
+		return nil, fmt.Errorf("[gabs.ParseJSON rawCloudActions]%v", err)
+	}
+
+	for division, provider := range h.divisionToProvider {
+		fullDivisionName := fmt.Sprintf("%v-%v", provider, division)
+
+		resourceIDToCloudActions, err := h.subsetCloudActionsToCurrentDivision(string(provider), string(division), parsedCloudActions)
+		if err != nil {
+			return nil, fmt.Errorf("[h.subsetCloudActionsToCurrentDivision]%v", err)
+		}
+		divisionToResourceActions[terraformValueObjects.Division(fullDivisionName)] = resourceIDToCloudActions
+	}
+
+	return divisionToResourceActions, nil
+}
+
 // subsetCloudActionsToCurrentDivision takes in a gabs.Container, and converts to a subset of
 // resources for the specified provider and division within a map for faster downstream look-up.
 func (h *hclCreate) subsetCloudActionsToCurrentDivision(
@@ -150,45 +222,78 @@ func (h *hclCreate) subsetCloudActionsToCurrentDivision(
 }
 
 // placeHCLIntoNewFileDef transfers the relevant HCL created by terraformer
-// into the new file definition.
+// into the new file definition, with hardcoded identifiers of other new resources of the same workspace
+// rewritten into references and each resource following those it references.
 func (h *hclCreate) placeHCLIntoNewFileDef(
 	divisionToCloudActions terraformValueObjects.DivisionResourceActions,
 	divisionToCostEstimates allCosts,
 	divisionToTerraformerResources DivisionToHCL,
 	parsedNewResourceToWorkspace *gabs.Container,
+	resourceNames ResourceNames,
+	divisionToResourceIdentifiers DivisionToResourceIdentifiers,
 	workspaceToHCLFile WorkspaceToHCL,
 ) (WorkspaceToHCL, error) {
+	// references between resources of the same division follow the resources being renamed.
+	divisionToRenames := map[string]map[ResourceIdentifier]string{}
+	for resource, name := range resourceNames {
+		resourceID := h.splitResourceIdentifier(resource)
+		if resourceID.resourceName == name {
+			continue
+		}
+		if _, ok := divisionToRenames[resourceID.division]; !ok {
+			divisionToRenames[resourceID.division] = map[ResourceIdentifier]string{}
+		}
+		divisionToRenames[resourceID.division][resourceID] = name
+	}
+
+	workspaceToResources := map[string][]*generatedResource{}
 	for resource, workspaceName := range parsedNewResourceToWorkspace.ChildrenMap() {
 		resourceID := h.splitResourceIdentifier(resource)
 
 		// extract block of resource definition from Terraformer result
 		currentTerraformerFile := divisionToTerraformerResources[resourceID.division]
 
-		cleanResourceName := ConvertTerraformerResourceName(resourceID.resourceName)
 		extractedBlock, err := h.extractResourceBlockDefinition(
 			currentTerraformerFile,
-			cleanResourceName,
+			resourceNames[resource],
 			resourceID,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("[h.extractResourceBlockDefinition] %v", err)
 		}
+		renameResourceReferences(extractedBlock.Body(), divisionToRenames[resourceID.division])
+		h.config.IgnoreChanges.setIgnoreChanges(extractedBlock)
 
+		// cloud actor and cost data are keyed by the name of the resource within the generated code.
 		currentResourceToCloudActions := divisionToCloudActions[terraformValueObjects.Division(resourceID.division)]
-		cloudIdentifierComment := h.generateHCLCloudActorsComment(resourceID.resourceType, cleanResourceName, currentResourceToCloudActions)
+		cloudIdentifierComment := h.generateHCLCloudActorsComment(resourceID.resourceType, resourceNames[resource], currentResourceToCloudActions)
 
 		currentDivisionCostEstimates := divisionToCostEstimates[terraformValueObjects.Division(resourceID.division)]
-		cloudCostComment := h.generateHCLCloudCostComment(resourceID.resourceType, cleanResourceName, currentDivisionCostEstimates)
+		cloudCostComment := h.generateHCLCloudCostComment(resourceID.resourceType, resourceNames[resource], currentDivisionCostEstimates)
 
-		// place resource within the corresponding workspace's file.
 		workspaceNameString := workspaceName.Data().(string)
+		workspaceToResources[workspaceNameString] = append(workspaceToResources[workspaceNameString], &generatedResource{
+			resourceID:             resourceID,
+			address:                fmt.Sprintf("%v.%v", resourceID.resourceType, resourceNames[resource]),
+			block:                  extractedBlock,
+			cloudIdentifierComment: cloudIdentifierComment,
+			cloudCostComment:       cloudCostComment,
+		})
+	}
 
-		workspaceToHCLFile[workspaceNameString] = h.writeBlockToWorkspaceHCL(
-			workspaceToHCLFile[workspaceNameString],
-			cloudIdentifierComment,
-			cloudCostComment,
-			extractedBlock,
-		)
+	// place resources within the corresponding workspace's file, referencing and following one another.
+	for workspaceName, resources := range workspaceToResources {
+		resources = orderByDependencies(resources)
+		rewriteResourceReferences(resources, divisionToResourceIdentifiers)
+
+		for _, resource := range orderByDependencies(resources) {
+			workspaceToHCLFile[workspaceName] = h.writeBlockToWorkspaceHCL(
+				workspaceToHCLFile[workspaceName],
+				resource.cloudIdentifierComment,
+				resource.cloudCostComment,
+				resource.block,
+			)
+		}
 	}
 
 	return workspaceToHCLFile, nil
@@ -268,6 +373,11 @@ func (h *hclCreate) writeBlockToWorkspaceHCL(
 // splitResourceIdentifier takes information from the resourceIdentifier string and outputs it
 // organized within the ResourceIdentifier struct.
 func (h *hclCreate) splitResourceIdentifier(resourceIdentifier string) ResourceIdentifier {
+	return parseResourceIdentifier(resourceIdentifier)
+}
+
+// parseResourceIdentifier splits a division.type.terraformer-name resource identifier into its ResourceIdentifier.
+func parseResourceIdentifier(resourceIdentifier string) ResourceIdentifier {
 	resourceIDSlice := strings.Split(resourceIdentifier, ".")
 
 	return ResourceIdentifier{
@@ -278,9 +388,9 @@ func (h *hclCreate) splitResourceIdentifier(resourceIdentifier string) ResourceI
 }
 
 // writeNewResourceFiles takes the hcl files from workspaceToHCLFile and outputs each to the appropriate directory
-// as informed by completeWorkspaceToHCLFile.
+// as informed by completeWorkspaceToHCLFile, split into files as informed by addressToFile.
 func (h *hclCreate) writeNewResourceFiles(
-	workspaceToDirectoryMap map[string]string, completeWorkspaceToHCLFile WorkspaceToHCL,
+	workspaceToDirectoryMap map[string]string, completeWorkspaceToHCLFile WorkspaceToHCL, addressToFile map[string]string,
 ) error {
 	for workspace, hclFile := range completeWorkspaceToHCLFile {
 		fileContent := hclwrite.Format(hclFile.Bytes())
@@ -288,16 +398,24 @@ func (h *hclCreate) writeNewResourceFiles(
 		subDirectory := workspaceToDirectoryMap[workspace]
 
 		if string(fileContent) != "" {
-			filePath := fmt.Sprintf("repo%vnew-resources.tf", subDirectory)
+			fileToContent, err := splitNewResources(fileContent, addressToFile)
+			if err != nil {
+				return fmt.Errorf("[splitNewResources] Error for workspace %v: %v", workspace, err)
+			}
 
-			err := os.WriteFile(filePath, fileContent, 0400)
+			for fileName, content := range fileToContent {
+				filePath := fmt.Sprintf("repo%v%v", subDirectory, fileName)
 
-			if err != nil {
-				return fmt.Errorf(
-					"[os.WriteFile] Error for repo%vnew-resources.tf:  %v",
-					subDirectory,
-					err,
-				)
+				err = os.WriteFile(filePath, content, 0400)
+
+				if err != nil {
+					return fmt.Errorf(
+						"[os.WriteFile] Error for repo%v%v:  %v",
+						subDirectory,
+						fileName,
+						err,
+					)
+				}
 			}
 		}
 	}
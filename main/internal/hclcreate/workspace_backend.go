@@ -0,0 +1,200 @@
+package hclcreate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+// BackendTemplateDecoder is the template of the backend block written within the main.tf of each new workspace.
+// Within string values of the backend configuration, {workspace} is replaced with the name of the new workspace and
+// {directory} with its directory, without leading or trailing slashes.
+type BackendTemplateDecoder struct {
+	// Type is the backend type, e.g. s3, gcs or azurerm.
+	Type string `json:"type"`
+
+	// Config is the configuration of the backend, e.g. {"bucket": "my-state", "key": "{directory}/terraform.tfstate"}.
+	Config map[string]interface{} `json:"config"`
+}
+
+// Decode provides the object decoding logic for BackendTemplateDecoder, in accordance with the envconfig
+// package's requirements.
+func (d *BackendTemplateDecoder) Decode(value string) error {
+	if strings.Trim(value, " ") == "" {
+		return nil
+	}
+
+	template := BackendTemplateDecoder{}
+	err := json.Unmarshal([]byte(value), &template)
+	if err != nil {
+		return fmt.Errorf("expected the backend template formatted as a json object with `type` and `config` fields: %v", err)
+	}
+
+	if template.Type == "" {
+		return fmt.Errorf("the field `type` is required for the backend template")
+	}
+
+	for attribute, attributeValue := range template.Config {
+		switch attributeValue.(type) {
+		case string, bool, float64:
+		default:
+			return fmt.Errorf("the backend template attribute %v must be a string, number or boolean", attribute)
+		}
+	}
+
+	*d = template
+	return nil
+}
+
+// appendBackendBlock appends the backend block templated for the workspace within directory to terraformBody.
+func (d BackendTemplateDecoder) appendBackendBlock(terraformBody *hclwrite.Body, workspace string, directory string) {
+	if d.Type == "" {
+		return
+	}
+
+	replacer := strings.NewReplacer("{workspace}", workspace, "{directory}", strings.Trim(directory, "/"))
+
+	attributes := make([]string, 0, len(d.Config))
+	for attribute := range d.Config {
+		attributes = append(attributes, attribute)
+	}
+	sort.Strings(attributes)
+
+	terraformBody.AppendNewline()
+	backendBody := terraformBody.AppendNewBlock("backend", []string{d.Type}).Body()
+	for _, attribute := range attributes {
+		switch attributeValue := d.Config[attribute].(type) {
+		case string:
+			backendBody.SetAttributeValue(attribute, cty.StringVal(replacer.Replace(attributeValue)))
+		case bool:
+			backendBody.SetAttributeValue(attribute, cty.BoolVal(attributeValue))
+		case float64:
+			backendBody.SetAttributeValue(attribute, cty.NumberFloatVal(attributeValue))
+		}
+	}
+}
+
+// repositoryProviderVersions returns the version constraint of each provider required within the .tf files of the
+// existing workspace directories. When workspaces disagree, the constraint used by the most workspaces is returned,
+// with ties going to the first constraint in sorted order.
+func repositoryProviderVersions(workspaceToDirectory map[string]string) (map[string]string, error) {
+	providerToVersionCounts := map[string]map[string]int{}
+
+	for _, directory := range workspaceToDirectory {
+		entries, err := os.ReadDir(fmt.Sprintf("repo%v", directory))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("[os.ReadDir] Error reading repo%v: %v", directory, err)
+		}
+
+		workspaceVersions := map[string]string{}
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".tf") {
+				continue
+			}
+
+			path := fmt.Sprintf("repo%v%v", directory, entry.Name())
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("[os.ReadFile] Error reading %v: %v", path, err)
+			}
+
+			for provider, version := range requiredProviderVersions(content, path) {
+				workspaceVersions[provider] = version
+			}
+		}
+
+		for provider, version := range workspaceVersions {
+			if _, ok := providerToVersionCounts[provider]; !ok {
+				providerToVersionCounts[provider] = map[string]int{}
+			}
+			providerToVersionCounts[provider][version]++
+		}
+	}
+
+	providerVersions := map[string]string{}
+	for provider, versionCounts := range providerToVersionCounts {
+		versions := make([]string, 0, len(versionCounts))
+		for version := range versionCounts {
+			versions = append(versions, version)
+		}
+		sort.Strings(versions)
+
+		for _, version := range versions {
+			if versionCounts[version] > versionCounts[providerVersions[provider]] {
+				providerVersions[provider] = version
+			}
+		}
+	}
+	return providerVersions, nil
+}
+
+// requiredProviderVersions returns the version constraint of each provider within the required_providers of the
+// terraform blocks of a .tf file, in either the object or the legacy string form. Files that do not parse are
+// skipped.
+func requiredProviderVersions(content []byte, path string) map[string]string {
+	versions := map[string]string{}
+
+	file, diags := hclsyntax.ParseConfig(content, path, hcl.InitialPos)
+	if diags.HasErrors() {
+		return versions
+	}
+
+	for _, terraformBlock := range file.Body.(*hclsyntax.Body).Blocks {
+		if terraformBlock.Type != "terraform" {
+			continue
+		}
+		for _, requiredProvidersBlock := range terraformBlock.Body.Blocks {
+			if requiredProvidersBlock.Type != "required_providers" {
+				continue
+			}
+			for provider, attribute := range requiredProvidersBlock.Body.Attributes {
+				value, diags := attribute.Expr.Value(nil)
+				if diags.HasErrors() || value.IsNull() || !value.IsWhollyKnown() {
+					continue
+				}
+
+				switch {
+				case value.Type() == cty.String:
+					versions[provider] = value.AsString()
+				case value.Type().IsObjectType() && value.Type().HasAttribute("version"):
+					if version := value.GetAttr("version"); version.Type() == cty.String && !version.IsNull() {
+						versions[provider] = version.AsString()
+					}
+				}
+			}
+		}
+	}
+	return versions
+}
+
+// pinProviderVersions sets the version constraint of each provider within the required_providers of
+// terraformBody to the constraint already used for the provider within the repository.
+func pinProviderVersions(terraformBody *hclwrite.Body, providerVersions map[string]string) {
+	requiredProviders := terraformBody.FirstMatchingBlock("required_providers", nil)
+	if requiredProviders == nil {
+		return
+	}
+
+	for provider := range requiredProviders.Body().Attributes() {
+		version, ok := providerVersions[provider]
+		if !ok {
+			continue
+		}
+		requiredProviders.Body().SetAttributeValue(provider, cty.ObjectVal(map[string]cty.Value{
+			"source":  cty.StringVal(fmt.Sprintf("%v/%v", terraformValueObjects.Provider(provider).Namespace(), provider)),
+			"version": cty.StringVal(version),
+		}))
+	}
+}
@@ -0,0 +1,204 @@
+package hclcreate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// DriftedResources is the list of drifted resources read from mappings/drift-attribute-diffs.json.
+type DriftedResources []DriftedResource
+
+// DriftedResource mirrors a single resource instance entry within mappings/drift-attribute-diffs.json.
+type DriftedResource struct {
+	StateFileName     string
+	ResourceAddress   string
+	ModuleName        string
+	ResourceType      string
+	ResourceName      string
+	InstanceID        string
+	RemediationImpact string
+	Attributes        []DriftedAttribute
+}
+
+// DriftedAttribute mirrors a single attribute difference of a drifted resource instance.
+type DriftedAttribute struct {
+	AttributeName     string
+	Before            string
+	After             string
+	ChangeType        string
+	ForcesReplacement bool
+	Sensitive         bool
+
+	// ValueType is the type of the attribute's value within the Terraform state, one of bool, number or string, and
+	// empty when unknown.
+	ValueType string
+}
+
+// WriteDriftRemediation writes suggested HCL patches for drifted resources to the remediation/ subdirectory of the
+// generated directory within each workspace directory that contains drift.
+func (h *hclCreate) WriteDriftRemediation(workspaceToDirectory map[string]string) error {
+	driftBytes, err := h.artifactStore.ReadFile("mappings/drift-attribute-diffs.json")
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("[artifacts.ReadFile] mappings/drift-attribute-diffs.json error: %v", err)
+	}
+
+	driftedResources := DriftedResources{}
+	err = json.Unmarshal(driftBytes, &driftedResources)
+	if err != nil {
+		return fmt.Errorf("[json.Unmarshal] error unmarshalling `driftedResources`: %v", err)
+	}
+
+	workspaceToDrift := map[string]DriftedResources{}
+	for _, resource := range driftedResources {
+		workspaceToDrift[resource.StateFileName] = append(workspaceToDrift[resource.StateFileName], resource)
+	}
+
+	for workspace, directory := range workspaceToDirectory {
+		resources, ok := workspaceToDrift[workspace]
+		if !ok {
+			continue
+		}
+
+		err = os.MkdirAll(fmt.Sprintf("repo%v%v/remediation", directory, h.generatedDirectory()), 0400)
+		if err != nil {
+			return fmt.Errorf("[os.MkdirAll] error making directory: %v", err)
+		}
+
+		outputPath := fmt.Sprintf("repo%v%v/remediation/drift_remediation.tf", directory, h.generatedDirectory())
+		err = os.WriteFile(outputPath, h.generateDriftRemediationFile(resources), 0400)
+		if err != nil {
+			return fmt.Errorf("[os.WriteFile] Error writing %v: %v", outputPath, err)
+		}
+	}
+
+	return nil
+}
+
+// generateDriftRemediationFile generates a .tf file containing one resource block per drifted
+// resource instance, setting each top-level attribute to the value currently observed in the cloud.
+// Nested and removed attributes cannot be expressed safely as a partial block, so they are
+// written as comments for the reviewer to reconcile by hand, as are all attributes of instances
+// created with count or for_each, which share a single block.
+func (h *hclCreate) generateDriftRemediationFile(resources DriftedResources) []byte {
+	sort.SliceStable(resources, func(i, j int) bool {
+		return resources[i].ResourceAddress < resources[j].ResourceAddress
+	})
+
+	f := hclwrite.NewEmptyFile()
+	fBody := f.Body()
+
+	for i, resource := range resources {
+		if i > 0 {
+			fBody.AppendNewline()
+		}
+
+		header := fmt.Sprintf("# Drift detected on %v (id: %v)", resource.ResourceAddress, resource.InstanceID)
+		if resource.ModuleName != "" && resource.ModuleName != "root" {
+			header += fmt.Sprintf(", defined within %v", resource.ModuleName)
+		}
+		fBody.AppendUnstructuredTokens(commentTokens(header))
+		if resource.RemediationImpact != "" {
+			fBody.AppendUnstructuredTokens(commentTokens(fmt.Sprintf("# Remediation impact: %v", resource.RemediationImpact)))
+		}
+
+		if isIndexedInstance(resource.ResourceAddress) {
+			fBody.AppendUnstructuredTokens(commentTokens("# Created with count or for_each, reconcile within the shared resource block:"))
+			for _, attribute := range resource.Attributes {
+				fBody.AppendUnstructuredTokens(commentTokens(driftedAttributeComment(attribute)))
+			}
+			continue
+		}
+
+		block := fBody.AppendNewBlock("resource", []string{resource.ResourceType, resource.ResourceName})
+		blockBody := block.Body()
+
+		for _, attribute := range resource.Attributes {
+			replacementNote := replacementSuffix(attribute)
+
+			// Sensitive values are masked upstream, and must be reconciled from the secret's source of truth.
+			if attribute.Sensitive {
+				blockBody.AppendUnstructuredTokens(commentTokens(driftedAttributeComment(attribute)))
+				continue
+			}
+
+			if attribute.ChangeType == "removed" || strings.Contains(attribute.AttributeName, ".") {
+				blockBody.AppendUnstructuredTokens(commentTokens(driftedAttributeComment(attribute)))
+				continue
+			}
+
+			if replacementNote != "" {
+				blockBody.AppendUnstructuredTokens(commentTokens(fmt.Sprintf("# %v%v", attribute.AttributeName, replacementNote)))
+			}
+			blockBody.SetAttributeValue(attribute.AttributeName, remediationValue(attribute.After, attribute.ValueType))
+		}
+	}
+
+	return f.Bytes()
+}
+
+// driftedAttributeComment describes the change of a drifted attribute as a comment line.
+func driftedAttributeComment(attribute DriftedAttribute) string {
+	if attribute.Sensitive {
+		return fmt.Sprintf("# %v: sensitive value changed outside of Terraform%v", attribute.AttributeName, replacementSuffix(attribute))
+	}
+	return fmt.Sprintf("# %v: %q -> %q%v", attribute.AttributeName, attribute.Before, attribute.After, replacementSuffix(attribute))
+}
+
+// replacementSuffix notes when the change of a drifted attribute forces the replacement of its resource.
+func replacementSuffix(attribute DriftedAttribute) string {
+	if attribute.ForcesReplacement {
+		return " (forces replacement)"
+	}
+	return ""
+}
+
+// isIndexedInstance returns true if the resource address is that of an instance created with count or for_each.
+func isIndexedInstance(address string) bool {
+	return strings.HasSuffix(address, "]")
+}
+
+// jsonNumberPattern matches numbers as written within JSON, excluding values such as NaN, Inf or hexadecimal numbers.
+var jsonNumberPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// remediationValue converts the string representation of a cloud attribute value into a cty value of the
+// attribute's type within the Terraform state. Values of unknown type are kept as strings, except for booleans,
+// so that values such as "007" or long numeric identifiers are written unchanged.
+func remediationValue(value string, valueType string) cty.Value {
+	switch valueType {
+	case "number":
+		if jsonNumberPattern.MatchString(value) {
+			if number, err := cty.ParseNumberVal(value); err == nil {
+				return number
+			}
+		}
+	case "bool", "":
+		if value == "true" || value == "false" {
+			return cty.BoolVal(value == "true")
+		}
+	}
+
+	return cty.StringVal(value)
+}
+
+// commentTokens returns hclwrite tokens representing a single comment line.
+func commentTokens(comment string) hclwrite.Tokens {
+	return hclwrite.Tokens{
+		&hclwrite.Token{
+			Type:         hclsyntax.TokenComment,
+			Bytes:        []byte(comment + "\n"),
+			SpacesBefore: 0,
+		},
+	}
+}
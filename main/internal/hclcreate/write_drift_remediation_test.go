@@ -0,0 +1,110 @@
+package hclcreate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestGenerateDriftRemediationFile(t *testing.T) {
+	// Given
+	h := hclCreate{}
+	resources := DriftedResources{
+		{
+			StateFileName:     "prod",
+			ResourceAddress:   "module.storage.aws_s3_bucket.logs",
+			ModuleName:        "module.storage",
+			ResourceType:      "aws_s3_bucket",
+			ResourceName:      "logs",
+			InstanceID:        "logs-bucket",
+			RemediationImpact: "update in place",
+			Attributes: []DriftedAttribute{
+				{AttributeName: "force_destroy", Before: "false", After: "true", ChangeType: "updated"},
+				{AttributeName: "tags.team", Before: "data", After: "platform", ChangeType: "updated"},
+			},
+		},
+		{
+			StateFileName:   "prod",
+			ResourceAddress: "aws_instance.web",
+			ModuleName:      "root",
+			ResourceType:    "aws_instance",
+			ResourceName:    "web",
+			InstanceID:      "i-123",
+			Attributes: []DriftedAttribute{
+				{AttributeName: "ami", Before: "ami-1", After: "ami-2", ChangeType: "updated", ForcesReplacement: true},
+				{AttributeName: "cpu_core_count", Before: "2", After: "4", ChangeType: "updated", ValueType: "number"},
+				{AttributeName: "tenancy_id", Before: "001", After: "007", ChangeType: "updated", ValueType: "string"},
+				{AttributeName: "user_data", Before: "echo", After: "", ChangeType: "removed"},
+				{AttributeName: "user_data_secret", Before: "(sensitive value)", After: "(sensitive value)", ChangeType: "updated", Sensitive: true},
+			},
+		},
+		{
+			StateFileName:   "prod",
+			ResourceAddress: "aws_sqs_queue.jobs[0]",
+			ModuleName:      "root",
+			ResourceType:    "aws_sqs_queue",
+			ResourceName:    "jobs",
+			InstanceID:      "jobs-0",
+			Attributes: []DriftedAttribute{
+				{AttributeName: "delay_seconds", Before: "0", After: "30", ChangeType: "updated", ValueType: "number"},
+			},
+		},
+	}
+
+	expected := `# Drift detected on aws_instance.web (id: i-123)
+resource "aws_instance" "web" {
+  # ami (forces replacement)
+  ami            = "ami-2"
+  cpu_core_count = 4
+  tenancy_id     = "007"
+  # user_data: "echo" -> ""
+  # user_data_secret: sensitive value changed outside of Terraform
+}
+
+# Drift detected on aws_sqs_queue.jobs[0] (id: jobs-0)
+# Created with count or for_each, reconcile within the shared resource block:
+# delay_seconds: "0" -> "30"
+
+# Drift detected on module.storage.aws_s3_bucket.logs (id: logs-bucket), defined within module.storage
+# Remediation impact: update in place
+resource "aws_s3_bucket" "logs" {
+  force_destroy = true
+  # tags.team: "data" -> "platform"
+}
+`
+
+	// When
+	output := h.generateDriftRemediationFile(resources)
+
+	// Then
+	assert.Equal(t, expected, string(output))
+}
+
+func TestRemediationValue(t *testing.T) {
+	// Given
+	inputs := []struct {
+		value     string
+		valueType string
+		expected  cty.Value
+	}{
+		{"true", "bool", cty.True},
+		{"false", "", cty.False},
+		{"1.5", "number", cty.MustParseNumberVal("1.5")},
+		{"123456789012345678901", "number", cty.MustParseNumberVal("123456789012345678901")},
+		{"007", "number", cty.StringVal("007")},
+		{"007", "string", cty.StringVal("007")},
+		{"12", "", cty.StringVal("12")},
+		{"NaN", "number", cty.StringVal("NaN")},
+		{"Inf", "number", cty.StringVal("Inf")},
+		{"us-east-1", "string", cty.StringVal("us-east-1")},
+	}
+
+	for _, input := range inputs {
+		// When
+		value := remediationValue(input.value, input.valueType)
+
+		// Then
+		assert.True(t, input.expected.RawEquals(value), "%v (%v) resulted in %#v", input.value, input.valueType, value)
+	}
+}
@@ -5,6 +5,8 @@ import (
 
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/zclconf/go-cty/cty"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
 )
 
 // CreateMainTF outputs a bytes slice which defines a baseline main.tf file.
@@ -34,7 +36,7 @@ func (h *hclCreate) CreateMainTF(providers map[string]string) ([]byte, error) {
 // and version.
 func requiredProviderSubBlock(body *hclwrite.Body, provider string, version string) error {
 	body.SetAttributeValue(string(provider), cty.ObjectVal(map[string]cty.Value{
-		"source":  cty.StringVal(fmt.Sprintf("hashicorp/%v", string(provider))),
+		"source":  cty.StringVal(fmt.Sprintf("%v/%v", terraformValueObjects.Provider(provider).Namespace(), provider)),
 		"version": cty.StringVal(string(version)),
 	}))
 	body.AppendNewline()
@@ -4,6 +4,7 @@ import (
 	"strconv"
 	"testing"
 
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
 )
 
@@ -23,6 +24,7 @@ func TestCreateMainTF(t *testing.T) {
 			TerraformVersion: "~>1.2.4",
 		},
 		map[terraformValueObjects.Division]terraformValueObjects.Provider{},
+		artifacts.NewMemoryStore(true),
 	)
 	f, err := hclCreate.CreateMainTF(inputProvidersMap)
 
@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"strings"
 
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/zclconf/go-cty/cty"
 )
@@ -14,9 +16,9 @@ import (
 // configurations using Terraform version 1.5.0 or higher.
 func (h *hclCreate) WriteImportBlocks(uniqueID string, workspaceToDirectory map[string]string) error {
 	// load in resource to import location map
-	resourceToImportLoc, err := os.ReadFile("mappings/resources-to-import-location.json")
+	resourceToImportLoc, err := h.artifactStore.ReadFile("mappings/resources-to-import-location.json")
 	if err != nil {
-		return fmt.Errorf("[os.ReadFile] mappings/resources-to-import-location.json error: %v", err)
+		return fmt.Errorf("[artifacts.ReadFile] mappings/resources-to-import-location.json error: %v", err)
 	}
 
 	resourceImportsByDivision := ResourceImportsByDivision{}
@@ -26,9 +28,9 @@ func (h *hclCreate) WriteImportBlocks(uniqueID string, workspaceToDirectory map[
 	}
 
 	// load in resource to workspace map
-	resourceToWorkspace, err := os.ReadFile("mappings/new-resources-to-workspace.json")
+	resourceToWorkspace, err := h.artifactStore.ReadFile("mappings/new-resources-to-workspace.json")
 	if err != nil {
-		return fmt.Errorf("[os.ReadFile] mappings/new-resources-to-workspace.json error: %v", err)
+		return fmt.Errorf("[artifacts.ReadFile] mappings/new-resources-to-workspace.json error: %v", err)
 	}
 
 	newResourceToWorkspace := NewResourceToWorkspace{}
@@ -37,7 +39,20 @@ func (h *hclCreate) WriteImportBlocks(uniqueID string, workspaceToDirectory map[
 		return fmt.Errorf("[json.Unmarshal] error unmarshalling `resourceToWorkspace`: %v", err)
 	}
 
+	divisionToResourceActions, err := h.loadDivisionResourceActions()
+	if err != nil {
+		return fmt.Errorf("[h.loadDivisionResourceActions]%v", err)
+	}
+
 	workspacesWithMigrations := h.setOfWorkspacesWithMigrationsStruct(newResourceToWorkspace)
+	resourceNames, err := h.resourceNames(newResourceToWorkspace)
+	if err != nil {
+		return fmt.Errorf("[h.resourceNames]%v", err)
+	}
+	resourceModules, err := loadResourceModules(h.artifactStore)
+	if err != nil {
+		return fmt.Errorf("[loadResourceModules]%v", err)
+	}
 
 	for workspace, directory := range workspaceToDirectory {
 		if _, ok := workspacesWithMigrations[workspace]; !ok {
@@ -48,17 +63,20 @@ func (h *hclCreate) WriteImportBlocks(uniqueID string, workspaceToDirectory map[
 			workspace,
 			resourceImportsByDivision,
 			newResourceToWorkspace,
+			resourceNames,
+			resourceModules,
+			divisionToResourceActions,
 		)
 		if err != nil {
 			return fmt.Errorf("[h.generateImportBlockFile]%v", err)
 		}
 
-		err = os.MkdirAll(fmt.Sprintf("repo%vcloud-concierge/imports", directory), 0400)
+		err = os.MkdirAll(fmt.Sprintf("repo%v%v/imports", directory, h.generatedDirectory()), 0400)
 		if err != nil {
 			return fmt.Errorf("[os.MkdirAll] error making directory: %v", err)
 		}
 		// outputting the file
-		outputPath := fmt.Sprintf("repo%vcloud-concierge/imports/%v_imports.tf", directory, uniqueID)
+		outputPath := fmt.Sprintf("repo%v%v/imports/%v_imports.tf", directory, h.generatedDirectory(), uniqueID)
 		err = os.WriteFile(outputPath, importBlockFileBytes, 0400)
 		if err != nil {
 			return fmt.Errorf("[os.WriteFile] Error writing %v: %v", outputPath, err)
@@ -80,11 +98,15 @@ func (h *hclCreate) setOfWorkspacesWithMigrationsStruct(resourceToWorkspace NewR
 }
 
 // generateImportBlockFile generates a .tf file containing import blocks for
-// all resources within a workspace that are to be imported.
+// all resources within a workspace that are to be imported, each preceded by
+// the cloud actors who created and last modified the resource.
 func (h *hclCreate) generateImportBlockFile(
 	workspace string,
 	resourceToImportLocation ResourceImportsByDivision,
 	resourceToWorkspace NewResourceToWorkspace,
+	resourceNames ResourceNames,
+	resourceModules ResourceModules,
+	divisionToResourceActions terraformValueObjects.DivisionResourceActions,
 ) ([]byte, error) {
 	f := hclwrite.NewEmptyFile()
 	fBody := f.Body()
@@ -94,21 +116,55 @@ func (h *hclCreate) generateImportBlockFile(
 			currentResource := h.resourceToIdentifierStruct(resource)
 			resourceID := fmt.Sprintf("%v.%v", currentResource.resourceType, currentResource.resourceName)
 			currentImportDataPair := resourceToImportLocation[currentResource.division][resourceID]
-			fBody = h.hclImportBlock(fBody, currentImportDataPair)
+
+			generatedAddress := terraformValueObjects.ResourceName(fmt.Sprintf(
+				"%v.%v", currentResource.resourceType, resourceNames[resource],
+			))
+			resourceActions := divisionToResourceActions[terraformValueObjects.Division(currentResource.division)][generatedAddress]
+			fBody.AppendUnstructuredTokens(h.importBlockCloudActorsComment(resourceActions))
+			importBody, err := h.hclImportBlock(
+				fBody,
+				resourceModules.resourceAddress(resource, currentResource.resourceType, resourceNames[resource]),
+				currentImportDataPair,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("[h.hclImportBlock] Error with resource %v: %v", resource, err)
+			}
+			fBody = importBody
 		}
 	}
 
 	return f.Bytes(), nil
 }
 
-// hclImportBlock writes an import block to the passed-in hclwrite body.
-func (h *hclCreate) hclImportBlock(body *hclwrite.Body, importDataPair ImportDataPair) *hclwrite.Body {
+// hclImportBlock writes an import block, importing the resource into its address within the generated
+// Terraform code, to the passed-in hclwrite body.
+func (h *hclCreate) hclImportBlock(body *hclwrite.Body, address string, importDataPair ImportDataPair) (*hclwrite.Body, error) {
+	to, diags := hclsyntax.ParseTraversalAbs([]byte(address), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("[invalid resource address %v] %v", address, diags.Error())
+	}
+
 	importBlock := body.AppendNewBlock(
 		"import", nil)
-	importBlock.Body().SetAttributeValue(
-		"to",
-		cty.StringVal(strings.Replace(importDataPair.TerraformConfigLocation, "tfer--", "", -1)),
-	)
+	importBlock.Body().SetAttributeTraversal("to", to)
 	importBlock.Body().SetAttributeValue("id", cty.StringVal(importDataPair.RemoteCloudReference))
-	return body
+	return body, nil
+}
+
+// importBlockCloudActorsComment generates comment lines on the cloud actors who created and last modified
+// the resource being imported, or no tokens when no cloud actors were identified.
+func (h *hclCreate) importBlockCloudActorsComment(resourceActions terraformValueObjects.ResourceActions) hclwrite.Tokens {
+	comment := hclwrite.Tokens{}
+	if resourceActions.Creator.Actor != "" {
+		comment = append(comment, commentTokens(fmt.Sprintf(
+			"# Created at %v by %v", resourceActions.Creator.Timestamp, resourceActions.Creator.Actor,
+		))...)
+	}
+	if resourceActions.Modifier.Actor != "" {
+		comment = append(comment, commentTokens(fmt.Sprintf(
+			"# Last Modified at %v by %v", resourceActions.Modifier.Timestamp, resourceActions.Modifier.Actor,
+		))...)
+	}
+	return comment
 }
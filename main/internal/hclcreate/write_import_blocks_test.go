@@ -4,8 +4,7 @@ import (
 	"reflect"
 	"testing"
 
-	"github.com/hashicorp/hcl/v2/hclwrite"
-	"github.com/zclconf/go-cty/cty"
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
 )
 
 func Test_GenerateImportBlockFile(t *testing.T) {
@@ -34,20 +33,20 @@ func Test_GenerateImportBlockFile(t *testing.T) {
 
 	inputWorkspace := "my-dev-workspace"
 
-	expectedOutputFile := hclwrite.NewEmptyFile()
-	expectedOutputBody := expectedOutputFile.Body()
-
-	importBlock := expectedOutputBody.AppendNewBlock("import", nil)
-	importBlock.Body().SetAttributeValue("to", cty.StringVal("resource_type_1.resource_name_1"))
-	importBlock.Body().SetAttributeValue("id", cty.StringVal("remote/cloud/reference"))
-
-	expectedOutput := string(expectedOutputFile.Bytes())
+	expectedOutput := `import {
+  to = resource_type_1.resource_name_1
+  id = "remote/cloud/reference"
+}
+`
 
 	// When
 	hclFile, err := h.generateImportBlockFile(
 		inputWorkspace,
 		inputResourceToImportLoc,
 		inputResourceToWorkspace,
+		assignResourceNames(inputResourceToWorkspace, ResourceNames{}, nil),
+		ResourceModules{},
+		terraformValueObjects.DivisionResourceActions{},
 	)
 
 	if err != nil {
@@ -60,6 +59,60 @@ func Test_GenerateImportBlockFile(t *testing.T) {
 	}
 }
 
+func Test_GenerateImportBlockFileCloudActors(t *testing.T) {
+	// Given
+	h := hclCreate{}
+
+	inputResourceToImportLoc := ResourceImportsByDivision{
+		"google-my-project": {
+			"google_storage_bucket.tfer--my-bucket": {
+				TerraformConfigLocation: "google_storage_bucket.tfer--my-bucket",
+				RemoteCloudReference:    "my-project/my-bucket",
+			},
+		},
+	}
+
+	inputResourceToWorkspace := NewResourceToWorkspace{
+		"google-my-project.google_storage_bucket.tfer--my-bucket": "my-workspace",
+	}
+
+	inputResourceActions := terraformValueObjects.DivisionResourceActions{
+		"google-my-project": {
+			"google_storage_bucket.my_bucket": {
+				Creator:  terraformValueObjects.CloudActorTimeStamp{Actor: "jane@example.com", Timestamp: "2023-02-25"},
+				Modifier: terraformValueObjects.CloudActorTimeStamp{Actor: "john@example.com", Timestamp: "2023-03-08"},
+			},
+		},
+	}
+
+	expectedOutput := `# Created at 2023-02-25 by jane@example.com
+# Last Modified at 2023-03-08 by john@example.com
+import {
+  to = google_storage_bucket.my_bucket
+  id = "my-project/my-bucket"
+}
+`
+
+	// When
+	hclFile, err := h.generateImportBlockFile(
+		"my-workspace",
+		inputResourceToImportLoc,
+		inputResourceToWorkspace,
+		assignResourceNames(inputResourceToWorkspace, ResourceNames{}, nil),
+		ResourceModules{},
+		inputResourceActions,
+	)
+
+	if err != nil {
+		t.Errorf("unexpected error in h.generateImportBlockFile: %v", err)
+	}
+
+	// Then
+	if string(hclFile) != expectedOutput {
+		t.Errorf("expected:\n%v\ngot:\n%v", expectedOutput, string(hclFile))
+	}
+}
+
 func Test_SetOfWorkspacesWithMigrationsStruct(t *testing.T) {
 	// Given
 	h := hclCreate{}
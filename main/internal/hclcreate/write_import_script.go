@@ -0,0 +1,129 @@
+package hclcreate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Import output modes, selecting how new resources are imported into the state of their workspaces.
+const (
+	// ImportOutputAuto writes import blocks for Terraform 1.5.0 or higher, and tfmigrate migrations otherwise.
+	ImportOutputAuto = "auto"
+
+	// ImportOutputBlocks writes import blocks.
+	ImportOutputBlocks = "import-blocks"
+
+	// ImportOutputTFMigrate writes tfmigrate configuration and migrations.
+	ImportOutputTFMigrate = "tfmigrate"
+
+	// ImportOutputScript writes a shell script of terraform import commands.
+	ImportOutputScript = "script"
+)
+
+// WriteImportScript writes, for each workspace with new resources, a shell script of terraform import commands
+// importing the resources into the workspace's state, for configurations using Terraform versions below 1.5.0.
+func (h *hclCreate) WriteImportScript(uniqueID string, workspaceToDirectory map[string]string) error {
+	// load in resource to import location map
+	resourceToImportLoc, err := h.artifactStore.ReadFile("mappings/resources-to-import-location.json")
+	if err != nil {
+		return fmt.Errorf("[artifacts.ReadFile] mappings/resources-to-import-location.json error: %v", err)
+	}
+
+	resourceImportsByDivision := ResourceImportsByDivision{}
+	err = json.Unmarshal(resourceToImportLoc, &resourceImportsByDivision)
+	if err != nil {
+		return fmt.Errorf("[json.Unmarshal] error unmarshalling `resourceToImportLoc`: %v", err)
+	}
+
+	// load in resource to workspace map
+	resourceToWorkspace, err := h.artifactStore.ReadFile("mappings/new-resources-to-workspace.json")
+	if err != nil {
+		return fmt.Errorf("[artifacts.ReadFile] mappings/new-resources-to-workspace.json error: %v", err)
+	}
+
+	newResourceToWorkspace := NewResourceToWorkspace{}
+	err = json.Unmarshal(resourceToWorkspace, &newResourceToWorkspace)
+	if err != nil {
+		return fmt.Errorf("[json.Unmarshal] error unmarshalling `resourceToWorkspace`: %v", err)
+	}
+
+	resourceNames, err := h.resourceNames(newResourceToWorkspace)
+	if err != nil {
+		return fmt.Errorf("[h.resourceNames] %v", err)
+	}
+	resourceModules, err := loadResourceModules(h.artifactStore)
+	if err != nil {
+		return fmt.Errorf("[loadResourceModules] %v", err)
+	}
+
+	workspacesWithMigrations := h.setOfWorkspacesWithMigrationsStruct(newResourceToWorkspace)
+	for workspace, directory := range workspaceToDirectory {
+		if !workspacesWithMigrations[workspace] {
+			continue
+		}
+
+		err = os.MkdirAll(fmt.Sprintf("repo%v%v/imports", directory, h.generatedDirectory()), 0400)
+		if err != nil {
+			return fmt.Errorf("[os.MkdirAll] error making directory: %v", err)
+		}
+
+		outputPath := fmt.Sprintf("repo%v%v/imports/%v_import.sh", directory, h.generatedDirectory(), uniqueID)
+		err = os.WriteFile(
+			outputPath,
+			h.importScript(workspace, directory, resourceImportsByDivision, newResourceToWorkspace, resourceNames, resourceModules),
+			0500,
+		)
+		if err != nil {
+			return fmt.Errorf("[os.WriteFile] Error writing %v: %v", outputPath, err)
+		}
+	}
+
+	return nil
+}
+
+// importScript returns a shell script importing the new resources of the workspace into its state, ordered by
+// resource address.
+func (h *hclCreate) importScript(
+	workspace string,
+	directory string,
+	resourceImportsByDivision ResourceImportsByDivision,
+	newResourceToWorkspace NewResourceToWorkspace,
+	resourceNames ResourceNames,
+	resourceModules ResourceModules,
+) []byte {
+	addressToRemoteReference := map[string]string{}
+	for resource, currentWorkspace := range newResourceToWorkspace {
+		if currentWorkspace != workspace {
+			continue
+		}
+
+		resourceID := h.resourceToIdentifierStruct(resource)
+		importDataPair := resourceImportsByDivision[resourceID.division][fmt.Sprintf("%v.%v", resourceID.resourceType, resourceID.resourceName)]
+		address := resourceModules.resourceAddress(resource, resourceID.resourceType, resourceNames[resource])
+		addressToRemoteReference[address] = importDataPair.RemoteCloudReference
+	}
+
+	addresses := make([]string, 0, len(addressToRemoteReference))
+	for address := range addressToRemoteReference {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+
+	var script strings.Builder
+	script.WriteString("#!/bin/sh\n")
+	script.WriteString(fmt.Sprintf("# Imports the new resources codified by cloud-concierge into the state of workspace %v.\n", workspace))
+	script.WriteString(fmt.Sprintf("# Run from the %v directory of the repository.\n", directory))
+	script.WriteString("set -e\n\n")
+
+	for _, address := range addresses {
+		script.WriteString(fmt.Sprintf(
+			"terraform import '%v' '%v'\n",
+			strings.ReplaceAll(address, "'", `'\''`),
+			strings.ReplaceAll(addressToRemoteReference[address], "'", `'\''`),
+		))
+	}
+	return []byte(script.String())
+}
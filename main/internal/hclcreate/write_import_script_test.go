@@ -0,0 +1,62 @@
+package hclcreate
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+)
+
+func TestWriteImportScript(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	require.NoError(t, os.MkdirAll("mappings", 0700))
+	require.NoError(t, os.MkdirAll("repo/app", 0700))
+	require.NoError(t, os.WriteFile("mappings/resources-to-import-location.json", []byte(`{
+  "aws-prod": {
+    "aws_s3_bucket.tfer--logs": {"TerraformConfigLocation": "aws_s3_bucket.tfer--logs", "RemoteCloudReference": "logs"},
+    "aws_iam_role.tfer--ci": {"TerraformConfigLocation": "aws_iam_role.tfer--ci", "RemoteCloudReference": "ci's-role"}
+  }
+}`), 0400))
+	require.NoError(t, os.WriteFile("mappings/new-resources-to-workspace.json", []byte(`{
+  "aws-prod.aws_s3_bucket.tfer--logs": "app",
+  "aws-prod.aws_iam_role.tfer--ci": "app"
+}`), 0400))
+
+	h := hclCreate{artifactStore: artifacts.NewMemoryStore(true), config: Config{TerraformVersion: "1.4.6", ImportOutput: ImportOutputScript}}
+
+	// When
+	err := h.CreateImports("abc123", map[string]string{"app": "/app/", "other": "/other/"})
+
+	// Then
+	require.NoError(t, err)
+	script, err := os.ReadFile("repo/app/cloud-concierge/imports/abc123_import.sh")
+	require.NoError(t, err)
+	assert.Equal(t, `#!/bin/sh
+# Imports the new resources codified by cloud-concierge into the state of workspace app.
+# Run from the /app/ directory of the repository.
+set -e
+
+terraform import 'aws_iam_role.ci' 'ci'\''s-role'
+terraform import 'aws_s3_bucket.logs' 'logs'
+`, string(script))
+	assert.NoDirExists(t, "repo/app/cloud-concierge/tfmigrate")
+	assert.NoFileExists(t, "repo/other/cloud-concierge/imports/abc123_import.sh")
+}
+
+func TestCreateImportsUnknownImportOutput(t *testing.T) {
+	// Given
+	h := hclCreate{config: Config{TerraformVersion: "1.5.0", ImportOutput: "terraform-cloud"}}
+
+	// When
+	err := h.CreateImports("abc123", map[string]string{})
+
+	// Then
+	assert.Error(t, err)
+}
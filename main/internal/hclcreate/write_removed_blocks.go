@@ -0,0 +1,228 @@
+package hclcreate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// removedBlocksMinimumVersion is the first version of Terraform supporting removed blocks.
+const removedBlocksMinimumVersion = "1.7.0"
+
+// divisionScanScopesPath is the mapping file recording the extent of the scan, written when deleted resources are
+// limited to the resource types, regions and divisions that were successfully scanned.
+const divisionScanScopesPath = "mappings/division-scan-scopes.json"
+
+// DeletedResource mirrors a single resource instance entry within mappings/drift-resources-deleted.json.
+type DeletedResource struct {
+	StateFileName   string
+	ModuleName      string
+	ResourceType    string
+	ResourceName    string
+	InstanceID      string
+	ResourceAddress string
+}
+
+// WriteRemovedBlocks writes, within each workspace with resources deleted outside of Terraform, the changes that
+// remove them from the workspace's state. With Terraform 1.7 or higher, a resource of the root module is removed
+// from the workspace's code along with a removed block, provided that the extent of the scan was recorded, so that
+// only resources within scanned resource types, regions and divisions were reported as deleted. Other resources,
+// such as those within modules or created with count or for_each, whose addresses removed blocks cannot express,
+// are listed within a terraform state rm script for the reviewer to run.
+func (h *hclCreate) WriteRemovedBlocks(uniqueID string, workspaceToDirectory map[string]string) error {
+	deletedBytes, err := h.artifactStore.ReadFile("mappings/drift-resources-deleted.json")
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("[artifacts.ReadFile] mappings/drift-resources-deleted.json error: %v", err)
+	}
+
+	deletedResources := []DeletedResource{}
+	err = json.Unmarshal(deletedBytes, &deletedResources)
+	if err != nil {
+		return fmt.Errorf("[json.Unmarshal] error unmarshalling `deletedResources`: %v", err)
+	}
+
+	removeCode := terraformVersionAtLeast(h.config.TerraformVersion, removedBlocksMinimumVersion) && h.artifactStore.Exists(divisionScanScopesPath)
+
+	workspaceToDeleted := map[string][]DeletedResource{}
+	for _, resource := range deletedResources {
+		workspaceToDeleted[resource.StateFileName] = append(workspaceToDeleted[resource.StateFileName], resource)
+	}
+
+	for workspace, directory := range workspaceToDirectory {
+		resources, ok := workspaceToDeleted[workspace]
+		if !ok {
+			continue
+		}
+		sort.SliceStable(resources, func(i, j int) bool {
+			return resources[i].ResourceAddress < resources[j].ResourceAddress
+		})
+
+		removedResources, stateRmResources := []DeletedResource{}, []DeletedResource{}
+		for _, resource := range resources {
+			if removeCode && isRootModule(resource.ModuleName) &&
+				resource.ResourceAddress == fmt.Sprintf("%v.%v", resource.ResourceType, resource.ResourceName) {
+				removed, err := removeResourceBlock(fmt.Sprintf("repo%v", directory), resource.ResourceType, resource.ResourceName)
+				if err != nil {
+					return fmt.Errorf("[removeResourceBlock] %v", err)
+				}
+				if removed {
+					removedResources = append(removedResources, resource)
+					continue
+				}
+			}
+			stateRmResources = append(stateRmResources, resource)
+		}
+
+		err = os.MkdirAll(fmt.Sprintf("repo%v%v/removed", directory, h.generatedDirectory()), 0400)
+		if err != nil {
+			return fmt.Errorf("[os.MkdirAll] %v/removed within %v: %v", h.generatedDirectory(), directory, err)
+		}
+
+		if len(removedResources) > 0 {
+			outputPath := fmt.Sprintf("repo%v%v/removed/%v_removed.tf", directory, h.generatedDirectory(), uniqueID)
+			fileBytes, err := removedBlocks(removedResources)
+			if err != nil {
+				return fmt.Errorf("[removedBlocks] Error with workspace %v: %v", workspace, err)
+			}
+
+			err = os.WriteFile(outputPath, fileBytes, 0400)
+			if err != nil {
+				return fmt.Errorf("[os.WriteFile] Error writing %v: %v", outputPath, err)
+			}
+		}
+
+		if len(stateRmResources) > 0 {
+			outputPath := fmt.Sprintf("repo%v%v/removed/%v_state_rm.sh", directory, h.generatedDirectory(), uniqueID)
+			err = os.WriteFile(outputPath, stateRmScript(workspace, directory, stateRmResources), 0500)
+			if err != nil {
+				return fmt.Errorf("[os.WriteFile] Error writing %v: %v", outputPath, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// isRootModule returns true if the module name, as parsed from the state, is that of the root module.
+func isRootModule(moduleName string) bool {
+	return moduleName == "" || moduleName == "root"
+}
+
+// removedBlocks creates a removed block, keeping the deleted cloud resource from being destroyed, for each resource.
+func removedBlocks(resources []DeletedResource) ([]byte, error) {
+	f := hclwrite.NewEmptyFile()
+	fBody := f.Body()
+
+	for index, resource := range resources {
+		from, diags := hclsyntax.ParseTraversalAbs([]byte(resource.ResourceAddress), "", hcl.InitialPos)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("[invalid resource address %v] %v", resource.ResourceAddress, diags.Error())
+		}
+
+		if index > 0 {
+			fBody.AppendNewline()
+		}
+		removedBlockBody := fBody.AppendNewBlock("removed", nil).Body()
+		removedBlockBody.SetAttributeTraversal("from", from)
+		removedBlockBody.AppendNewBlock("lifecycle", nil).Body().SetAttributeValue("destroy", cty.False)
+	}
+
+	return f.Bytes(), nil
+}
+
+// stateRmScript creates a shell script removing each resource instance from the state of a workspace.
+func stateRmScript(workspace string, directory string, resources []DeletedResource) []byte {
+	var script strings.Builder
+	script.WriteString("#!/bin/sh\n")
+	script.WriteString(fmt.Sprintf("# Removes the resources deleted outside of Terraform from the state of workspace %v.\n", workspace))
+	script.WriteString(fmt.Sprintf("# Run from the %v directory of the repository.\n", directory))
+	script.WriteString("set -e\n\n")
+
+	for _, resource := range resources {
+		address := strings.ReplaceAll(resource.ResourceAddress, "'", `'\''`)
+		script.WriteString(fmt.Sprintf("terraform state rm '%v'\n", address))
+	}
+	return []byte(script.String())
+}
+
+// removeResourceBlock removes the block of a root module resource from the .tf files of a workspace directory,
+// returning false if no such block is found.
+func removeResourceBlock(directory string, resourceType string, resourceName string) (bool, error) {
+	paths, err := filepath.Glob(filepath.Join(directory, "*.tf"))
+	if err != nil {
+		return false, fmt.Errorf("[filepath.Glob] %v", err)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return false, fmt.Errorf("[os.ReadFile] Error reading %v: %v", path, err)
+		}
+
+		f, diags := hclwrite.ParseConfig(content, path, hcl.InitialPos)
+		if diags.HasErrors() {
+			continue
+		}
+
+		block := f.Body().FirstMatchingBlock("resource", []string{resourceType, resourceName})
+		if block == nil {
+			continue
+		}
+		f.Body().RemoveBlock(block)
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return false, fmt.Errorf("[os.Stat] %v", err)
+		}
+
+		// Files within the repository may be read only, so they are replaced rather than truncated.
+		err = os.Remove(path)
+		if err != nil {
+			return false, fmt.Errorf("[os.Remove] Error removing %v: %v", path, err)
+		}
+
+		err = os.WriteFile(path, hclwrite.Format(f.Bytes()), info.Mode().Perm())
+		if err != nil {
+			return false, fmt.Errorf("[os.WriteFile] Error writing %v: %v", path, err)
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// terraformVersionAtLeast returns true if the dotted Terraform version, which may be prefixed by a version constraint
+// operator such as ~>, is the minimum version or higher. A version that cannot be parsed is assumed to be lower.
+func terraformVersionAtLeast(version string, minimum string) bool {
+	versionParts := strings.Split(strings.TrimLeft(version, "~>=<! v"), ".")
+	minimumParts := strings.Split(minimum, ".")
+
+	for index, minimumPart := range minimumParts {
+		minimumNumber, _ := strconv.Atoi(minimumPart)
+		if index >= len(versionParts) {
+			return minimumNumber == 0
+		}
+
+		versionNumber, err := strconv.Atoi(strings.SplitN(versionParts[index], "-", 2)[0])
+		if err != nil {
+			return false
+		}
+		if versionNumber != minimumNumber {
+			return versionNumber > minimumNumber
+		}
+	}
+	return true
+}
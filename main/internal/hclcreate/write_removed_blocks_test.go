@@ -0,0 +1,153 @@
+package hclcreate
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+)
+
+func TestWriteRemovedBlocks(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	require.NoError(t, os.MkdirAll("mappings", 0700))
+	require.NoError(t, os.WriteFile("mappings/drift-resources-deleted.json", []byte(`[
+		{"StateFileName": "networking", "ModuleName": "root", "ResourceType": "aws_vpc", "ResourceName": "main", "InstanceID": "vpc-1", "ResourceAddress": "aws_vpc.main"},
+		{"StateFileName": "networking", "ModuleName": "root", "ResourceType": "aws_subnet", "ResourceName": "private", "InstanceID": "subnet-1", "ResourceAddress": "aws_subnet.private[\"a\"]"},
+		{"StateFileName": "networking", "ModuleName": "module.queues", "ResourceType": "aws_sqs_queue", "ResourceName": "orders", "InstanceID": "orders", "ResourceAddress": "module.queues.aws_sqs_queue.orders"}
+	]`), 0400))
+	require.NoError(t, os.WriteFile(divisionScanScopesPath, []byte(`{"aws-prod": {"regions": ["us-east-1"], "resourceTypes": ["aws_vpc"]}}`), 0400))
+
+	require.NoError(t, os.MkdirAll("repo/networking", 0700))
+	require.NoError(t, os.WriteFile("repo/networking/main.tf", []byte(`resource "aws_vpc" "main" {
+  cidr_block = "10.0.0.0/16"
+}
+
+resource "aws_vpc" "other" {
+  cidr_block = "10.1.0.0/16"
+}
+`), 0600))
+
+	h := hclCreate{artifactStore: artifacts.NewMemoryStore(true), config: Config{TerraformVersion: "~>1.7.0"}}
+	workspaceToDirectory := map[string]string{"networking": "/networking/"}
+
+	// When
+	err := h.WriteRemovedBlocks("abc123", workspaceToDirectory)
+
+	// Then
+	require.NoError(t, err)
+	removed, err := os.ReadFile("repo/networking/cloud-concierge/removed/abc123_removed.tf")
+	require.NoError(t, err)
+	assert.Equal(t, `removed {
+  from = aws_vpc.main
+  lifecycle {
+    destroy = false
+  }
+}
+`, string(removed))
+
+	mainTF, err := os.ReadFile("repo/networking/main.tf")
+	require.NoError(t, err)
+	assert.NotContains(t, string(mainTF), `"aws_vpc" "main"`)
+	assert.Contains(t, string(mainTF), `resource "aws_vpc" "other"`)
+
+	script, err := os.ReadFile("repo/networking/cloud-concierge/removed/abc123_state_rm.sh")
+	require.NoError(t, err)
+	assert.Equal(t, `#!/bin/sh
+# Removes the resources deleted outside of Terraform from the state of workspace networking.
+# Run from the /networking/ directory of the repository.
+set -e
+
+terraform state rm 'aws_subnet.private["a"]'
+terraform state rm 'module.queues.aws_sqs_queue.orders'
+`, string(script))
+}
+
+func TestWriteRemovedBlocksBeforeTerraform17(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	require.NoError(t, os.MkdirAll("mappings", 0700))
+	require.NoError(t, os.WriteFile("mappings/drift-resources-deleted.json", []byte(`[
+		{"StateFileName": "networking", "ModuleName": "root", "ResourceType": "aws_vpc", "ResourceName": "main", "InstanceID": "vpc-1", "ResourceAddress": "aws_vpc.main"}
+	]`), 0400))
+	require.NoError(t, os.WriteFile(divisionScanScopesPath, []byte(`{}`), 0400))
+
+	require.NoError(t, os.MkdirAll("repo/networking", 0700))
+	mainTF := `resource "aws_vpc" "main" {
+  cidr_block = "10.0.0.0/16"
+}
+`
+	require.NoError(t, os.WriteFile("repo/networking/main.tf", []byte(mainTF), 0600))
+
+	h := hclCreate{artifactStore: artifacts.NewMemoryStore(true), config: Config{TerraformVersion: "1.6.6"}}
+
+	// When
+	err := h.WriteRemovedBlocks("abc123", map[string]string{"networking": "/networking/"})
+
+	// Then
+	require.NoError(t, err)
+	_, err = os.Stat("repo/networking/cloud-concierge/removed/abc123_removed.tf")
+	assert.True(t, os.IsNotExist(err))
+
+	content, err := os.ReadFile("repo/networking/main.tf")
+	require.NoError(t, err)
+	assert.Equal(t, mainTF, string(content))
+
+	script, err := os.ReadFile("repo/networking/cloud-concierge/removed/abc123_state_rm.sh")
+	require.NoError(t, err)
+	assert.Contains(t, string(script), "terraform state rm 'aws_vpc.main'\n")
+}
+
+func TestWriteRemovedBlocksWithoutScanScopes(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	require.NoError(t, os.MkdirAll("mappings", 0700))
+	require.NoError(t, os.WriteFile("mappings/drift-resources-deleted.json", []byte(`[
+		{"StateFileName": "networking", "ModuleName": "root", "ResourceType": "aws_vpc", "ResourceName": "main", "InstanceID": "vpc-1", "ResourceAddress": "aws_vpc.main"}
+	]`), 0400))
+
+	require.NoError(t, os.MkdirAll("repo/networking", 0700))
+	mainTF := `resource "aws_vpc" "main" {
+  cidr_block = "10.0.0.0/16"
+}
+`
+	require.NoError(t, os.WriteFile("repo/networking/main.tf", []byte(mainTF), 0600))
+
+	h := hclCreate{artifactStore: artifacts.NewMemoryStore(true), config: Config{TerraformVersion: "1.7.0"}}
+
+	// When
+	err := h.WriteRemovedBlocks("abc123", map[string]string{"networking": "/networking/"})
+
+	// Then
+	require.NoError(t, err)
+	_, err = os.Stat("repo/networking/cloud-concierge/removed/abc123_removed.tf")
+	assert.True(t, os.IsNotExist(err))
+
+	content, err := os.ReadFile("repo/networking/main.tf")
+	require.NoError(t, err)
+	assert.Equal(t, mainTF, string(content))
+
+	script, err := os.ReadFile("repo/networking/cloud-concierge/removed/abc123_state_rm.sh")
+	require.NoError(t, err)
+	assert.Contains(t, string(script), "terraform state rm 'aws_vpc.main'\n")
+}
+
+func TestTerraformVersionAtLeast(t *testing.T) {
+	assert.True(t, terraformVersionAtLeast("1.7.0", "1.7.0"))
+	assert.True(t, terraformVersionAtLeast("~>1.10.2", "1.7.0"))
+	assert.True(t, terraformVersionAtLeast(">= 2.0", "1.7.0"))
+	assert.False(t, terraformVersionAtLeast("1.6.6", "1.7.0"))
+	assert.False(t, terraformVersionAtLeast("latest", "1.7.0"))
+}
@@ -14,9 +14,9 @@ import (
 // components for TFMigrate to operate successfully.
 func (h *hclCreate) CreateTFMigrate(uniqueID string, workspaceToDirectory map[string]string) error {
 	// load in resource to import location map
-	resourceToImportLoc, err := os.ReadFile("mappings/resources-to-import-location.json")
+	resourceToImportLoc, err := h.artifactStore.ReadFile("mappings/resources-to-import-location.json")
 	if err != nil {
-		return fmt.Errorf("[os.ReadFile] mappings/resources-to-import-location.json error: %v", err)
+		return fmt.Errorf("[artifacts.ReadFile] mappings/resources-to-import-location.json error: %v", err)
 	}
 
 	resourceImportsByDivision := ResourceImportsByDivision{}
@@ -26,9 +26,9 @@ func (h *hclCreate) CreateTFMigrate(uniqueID string, workspaceToDirectory map[st
 	}
 
 	// load in resource to workspace map
-	resourceToWorkspace, err := os.ReadFile("mappings/new-resources-to-workspace.json")
+	resourceToWorkspace, err := h.artifactStore.ReadFile("mappings/new-resources-to-workspace.json")
 	if err != nil {
-		return fmt.Errorf("[os.ReadFile] mappings/new-resources-to-workspace.json error: %v", err)
+		return fmt.Errorf("[artifacts.ReadFile] mappings/new-resources-to-workspace.json error: %v", err)
 	}
 
 	newResourceToWorkspace := NewResourceToWorkspace{}
@@ -58,12 +58,12 @@ func (h *hclCreate) CreateTFMigrate(uniqueID string, workspaceToDirectory map[st
 // CreateTFMigrateConfiguration saves HCL which defines TFMigrate configuration.
 func (h *hclCreate) CreateTFMigrateConfiguration(workspaceToDirectory map[string]string) error {
 	for workspace, directory := range workspaceToDirectory {
-		err := os.MkdirAll(fmt.Sprintf("repo%vcloud-concierge/tfmigrate", directory), 0400)
+		err := os.MkdirAll(fmt.Sprintf("repo%v%v/tfmigrate", directory, h.generatedDirectory()), 0400)
 		if err != nil {
-			return fmt.Errorf("[os.MkdirAll] cloud-concierge/tfmigrate within %v: %v", directory, err)
+			return fmt.Errorf("[os.MkdirAll] %v/tfmigrate within %v: %v", h.generatedDirectory(), directory, err)
 		}
 
-		newFilePath := fmt.Sprintf("repo%vcloud-concierge/tfmigrate/.tfmigrate.hcl", directory)
+		newFilePath := fmt.Sprintf("repo%v%v/tfmigrate/.tfmigrate.hcl", directory, h.generatedDirectory())
 
 		currentTfMigrateConfig, err := h.individualTFMigrateConfig(workspace)
 		if err != nil {
@@ -88,7 +88,7 @@ func (h *hclCreate) individualTFMigrateConfig(workspace string) ([]byte, error)
 
 	tfmigrateBlockBody := tfmigrateBlock.Body()
 
-	tfmigrateBlockBody.SetAttributeValue("migration_dir", cty.StringVal("./cloud-concierge/tfmigrate/"))
+	tfmigrateBlockBody.SetAttributeValue("migration_dir", cty.StringVal(fmt.Sprintf("./%v/tfmigrate/", h.generatedDirectory())))
 	tfmigrateBlockBody.SetAttributeValue("is_backend_terraform_cloud", cty.BoolVal(true))
 
 	historyBlock := tfmigrateBlockBody.AppendNewBlock("history", nil)
@@ -125,6 +125,14 @@ func (h *hclCreate) CreateTFMigrateMigration(
 	workspaceToDirectory map[string]string,
 ) error {
 	workspacesWithMigrations := h.setOfWorkspacesWithMigrationsStruct(newResourceToWorkspace)
+	resourceNames, err := h.resourceNames(newResourceToWorkspace)
+	if err != nil {
+		return fmt.Errorf("[h.resourceNames] %v", err)
+	}
+	resourceModules, err := loadResourceModules(h.artifactStore)
+	if err != nil {
+		return fmt.Errorf("[loadResourceModules] %v", err)
+	}
 
 	// complete one workspace migration file at a time
 	for workspace, directory := range workspaceToDirectory {
@@ -137,13 +145,15 @@ func (h *hclCreate) CreateTFMigrateMigration(
 			workspace,
 			resourceImportsByDivision,
 			newResourceToWorkspace,
+			resourceNames,
+			resourceModules,
 		)
 		if err != nil {
 			return fmt.Errorf("[h.individualTFMigrateMigration] %v", err)
 		}
 
 		// outputting the file
-		outputPath := fmt.Sprintf("repo%vcloud-concierge/tfmigrate/%v_migrations.hcl", directory, uniqueID)
+		outputPath := fmt.Sprintf("repo%v%v/tfmigrate/%v_migrations.hcl", directory, h.generatedDirectory(), uniqueID)
 		err = os.WriteFile(outputPath, migrationFileBytes, 0400)
 		if err != nil {
 			return fmt.Errorf("[os.WriteFile] Error writing %v: %v", outputPath, err)
@@ -159,6 +169,8 @@ func (h *hclCreate) individualTFMigrateMigration(
 	workspace string,
 	resourceImportsByDivision ResourceImportsByDivision,
 	newResourceToWorkspace NewResourceToWorkspace,
+	resourceNames ResourceNames,
+	resourceModules ResourceModules,
 ) ([]byte, error) {
 	f := hclwrite.NewEmptyFile()
 	fBody := f.Body()
@@ -179,6 +191,8 @@ func (h *hclCreate) individualTFMigrateMigration(
 			importStatement, err := h.generateImportStatement(
 				resource,
 				resourceImportsByDivision,
+				resourceNames,
+				resourceModules,
 			)
 			if err != nil {
 				return nil, fmt.Errorf("[h.generateImportStatement] Error with resource %v: %v", resource, err)
@@ -198,6 +212,8 @@ func (h *hclCreate) individualTFMigrateMigration(
 func (h *hclCreate) generateImportStatement(
 	resource string,
 	resourceImportsByDivision ResourceImportsByDivision,
+	resourceNames ResourceNames,
+	resourceModules ResourceModules,
 ) (string, error) {
 	resourceIDStruct := h.resourceToIdentifierStruct(resource)
 
@@ -205,16 +221,15 @@ func (h *hclCreate) generateImportStatement(
 
 	resourceImportData := resourceImports[fmt.Sprintf("%v.%v", resourceIDStruct.resourceType, resourceIDStruct.resourceName)]
 
-	importText := h.generateImportStatementText(resourceImportData.RemoteCloudReference, resourceIDStruct)
+	address := resourceModules.resourceAddress(resource, resourceIDStruct.resourceType, resourceNames[resource])
+	importText := h.generateImportStatementText(resourceImportData.RemoteCloudReference, address)
 	return importText, nil
 }
 
 // generateImportStatementText generates the final input statement text for a given cloud resource needing to be
-// imported into terraform control
-func (h *hclCreate) generateImportStatementText(remoteCloudReference string, resourceIDStruct ResourceIdentifier) string {
-	cleanedResourceName := ConvertTerraformerResourceName(resourceIDStruct.resourceName)
-
-	return fmt.Sprintf("import %v.%v %v", resourceIDStruct.resourceType, cleanedResourceName, remoteCloudReference)
+// imported into terraform control at its address within the generated Terraform code.
+func (h *hclCreate) generateImportStatementText(remoteCloudReference string, address string) string {
+	return fmt.Sprintf("import %v %v", address, remoteCloudReference)
 }
 
 // resourceToIdentifierStruct structures the information found within the resource string
@@ -51,7 +51,9 @@ func TestGenerateImportStatement(t *testing.T) {
 
 	expectedOutput := "import tf_type_abc.tf_name_xyz import_3"
 
-	output, err := h.generateImportStatement(inputResource, resourceImportsByDivision)
+	resourceNames := ResourceNames{inputResource: "tf_name_xyz"}
+
+	output, err := h.generateImportStatement(inputResource, resourceImportsByDivision, resourceNames, ResourceModules{})
 	if err != nil {
 		t.Errorf("unexpected error in h.generateImportStatement: %v", err)
 	}
@@ -61,17 +63,55 @@ func TestGenerateImportStatement(t *testing.T) {
 	}
 }
 
-func TestGenerateImportStatementText(t *testing.T) {
+func TestGenerateImportStatementModule(t *testing.T) {
 	h := hclCreate{}
 
-	resourceCloudID := "example-id"
+	inputResource := "google-dev.tf_type_abc.tfer--tf_name_xyz"
+
+	resourceImportsByDivision := ResourceImportsByDivision{
+		"google-dev": {
+			"tf_type_123.tfer--tf_name_xyz": {
+				TerraformConfigLocation: "tf_type_123.tfer--tf_name_xyz",
+				RemoteCloudReference:    "import_1",
+			},
+			"tf_type_abc.tfer--tf_name_123": {
+				TerraformConfigLocation: "tf_type_abc.tfer--tf_name_123",
+				RemoteCloudReference:    "import_2",
+			},
+			"tf_type_abc.tfer--tf_name_xyz": {
+				TerraformConfigLocation: "tf_type_abc.tfer--tf_name_xyz",
+				RemoteCloudReference:    "import_3",
+			},
+		},
+		"google-prod": {
+			"tf_type_123.tfer--tf_name_xyz": {
+				TerraformConfigLocation: "tf_type_123.tfer--tf_name_xyz",
+				RemoteCloudReference:    "import_1",
+			},
+		},
+	}
 
-	inputResourceID := ResourceIdentifier{
-		resourceName: "tfer--example-name_broski",
-		resourceType: "resource_type",
+	expectedOutput := "import module.type.tf_type_abc.tf_name_xyz import_3"
+
+	resourceNames := ResourceNames{inputResource: "tf_name_xyz"}
+	resourceModules := ResourceModules{inputResource: "type"}
+
+	output, err := h.generateImportStatement(inputResource, resourceImportsByDivision, resourceNames, resourceModules)
+	if err != nil {
+		t.Errorf("unexpected error in h.generateImportStatement: %v", err)
 	}
 
-	output := h.generateImportStatementText(resourceCloudID, inputResourceID)
+	if expectedOutput != output {
+		t.Errorf("got: %v\n\nexpected: %v", output, expectedOutput)
+	}
+}
+
+func TestGenerateImportStatementText(t *testing.T) {
+	h := hclCreate{}
+
+	resourceCloudID := "example-id"
+
+	output := h.generateImportStatementText(resourceCloudID, "resource_type.example_name_broski")
 	expectedOutput := "import resource_type.example_name_broski example-id"
 
 	if output != expectedOutput {
@@ -183,7 +223,10 @@ func TestIndividualTFMigrateMigration(t *testing.T) {
 
 	expectedOutputTwo := "migration \"state\" \"import\" {\n  dir       = \"/github/workspace/xyz\"\n  workspace = \"workspace_1\"\n  actions   = [\"import tf_type_123.tf_name_xyz import_1a\", \"import tf_type_123.tf_name_xyz_prod import_1b\"]\n}\n"
 
-	output, err := h.individualTFMigrateMigration("/xyz", "workspace_1", resourceImportsByDivision, newResourceToWorkspace)
+	output, err := h.individualTFMigrateMigration(
+		"/xyz", "workspace_1", resourceImportsByDivision, newResourceToWorkspace, assignResourceNames(newResourceToWorkspace, ResourceNames{}, nil),
+		ResourceModules{},
+	)
 	if err != nil {
 		t.Errorf("unexpected error in h.individualTFMigrateMigration(): %v", err)
 	}
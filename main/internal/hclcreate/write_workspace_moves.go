@@ -0,0 +1,163 @@
+package hclcreate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// WorkspaceMove is the reorganization of an already-managed resource into a different workspace or module path, as
+// written within mappings/managed-resources-to-moves.json.
+type WorkspaceMove struct {
+	FromWorkspace string
+	From          string
+	ToWorkspace   string
+	To            string
+}
+
+// WriteWorkspaceMoves writes the reorganizations of already-managed resources within the target workspace of each.
+// Moves beneath a different module of the same workspace are written as moved blocks, while moves between
+// workspaces, whose states differ, are written as tfmigrate multi_state migrations.
+func (h *hclCreate) WriteWorkspaceMoves(uniqueID string, workspaceToDirectory map[string]string) error {
+	movesBytes, err := h.artifactStore.ReadFile("mappings/managed-resources-to-moves.json")
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("[artifacts.ReadFile] mappings/managed-resources-to-moves.json error: %v", err)
+	}
+
+	moves := []WorkspaceMove{}
+	err = json.Unmarshal(movesBytes, &moves)
+	if err != nil {
+		return fmt.Errorf("[json.Unmarshal] error unmarshalling `moves`: %v", err)
+	}
+
+	workspaceToMovedBlocks := map[string][]WorkspaceMove{}
+	workspaceToMigrations := map[string][]WorkspaceMove{}
+	for _, move := range moves {
+		if _, ok := workspaceToDirectory[move.ToWorkspace]; !ok {
+			return fmt.Errorf("[workspace %v of move %v is not a configured workspace]", move.ToWorkspace, move.From)
+		}
+		if _, ok := workspaceToDirectory[move.FromWorkspace]; !ok {
+			return fmt.Errorf("[workspace %v of move %v is not a configured workspace]", move.FromWorkspace, move.From)
+		}
+
+		if move.FromWorkspace == move.ToWorkspace {
+			workspaceToMovedBlocks[move.ToWorkspace] = append(workspaceToMovedBlocks[move.ToWorkspace], move)
+		} else {
+			workspaceToMigrations[move.ToWorkspace] = append(workspaceToMigrations[move.ToWorkspace], move)
+		}
+	}
+
+	for workspace, workspaceMoves := range workspaceToMovedBlocks {
+		directory := workspaceToDirectory[workspace]
+		fileBytes, err := movedBlocks(workspaceMoves)
+		if err != nil {
+			return fmt.Errorf("[movedBlocks] Error with workspace %v: %v", workspace, err)
+		}
+
+		err = os.MkdirAll(fmt.Sprintf("repo%v%v/moves", directory, h.generatedDirectory()), 0400)
+		if err != nil {
+			return fmt.Errorf("[os.MkdirAll] %v/moves within %v: %v", h.generatedDirectory(), directory, err)
+		}
+
+		outputPath := fmt.Sprintf("repo%v%v/moves/%v_moved.tf", directory, h.generatedDirectory(), uniqueID)
+		err = os.WriteFile(outputPath, fileBytes, 0400)
+		if err != nil {
+			return fmt.Errorf("[os.WriteFile] Error writing %v: %v", outputPath, err)
+		}
+	}
+
+	for workspace, workspaceMoves := range workspaceToMigrations {
+		directory := workspaceToDirectory[workspace]
+		fileBytes := multiStateMigrations(workspaceMoves, workspaceToDirectory)
+
+		err = os.MkdirAll(fmt.Sprintf("repo%v%v/tfmigrate", directory, h.generatedDirectory()), 0400)
+		if err != nil {
+			return fmt.Errorf("[os.MkdirAll] %v/tfmigrate within %v: %v", h.generatedDirectory(), directory, err)
+		}
+
+		outputPath := fmt.Sprintf("repo%v%v/tfmigrate/%v_moves.hcl", directory, h.generatedDirectory(), uniqueID)
+		err = os.WriteFile(outputPath, fileBytes, 0400)
+		if err != nil {
+			return fmt.Errorf("[os.WriteFile] Error writing %v: %v", outputPath, err)
+		}
+	}
+
+	return nil
+}
+
+// movedBlocks creates the moved blocks of the moves within a single workspace.
+func movedBlocks(moves []WorkspaceMove) ([]byte, error) {
+	sort.Slice(moves, func(i, j int) bool { return moves[i].From < moves[j].From })
+
+	f := hclwrite.NewEmptyFile()
+	fBody := f.Body()
+
+	for index, move := range moves {
+		from, diags := hclsyntax.ParseTraversalAbs([]byte(move.From), "", hcl.InitialPos)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("[invalid resource address %v] %v", move.From, diags.Error())
+		}
+		to, diags := hclsyntax.ParseTraversalAbs([]byte(move.To), "", hcl.InitialPos)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("[invalid resource address %v] %v", move.To, diags.Error())
+		}
+
+		if index > 0 {
+			fBody.AppendNewline()
+		}
+		movedBlockBody := fBody.AppendNewBlock("moved", nil).Body()
+		movedBlockBody.SetAttributeTraversal("from", from)
+		movedBlockBody.SetAttributeTraversal("to", to)
+	}
+
+	return f.Bytes(), nil
+}
+
+// multiStateMigrations creates a tfmigrate multi_state migration from each other workspace of the moves into
+// their target workspace.
+func multiStateMigrations(moves []WorkspaceMove, workspaceToDirectory map[string]string) []byte {
+	sort.Slice(moves, func(i, j int) bool {
+		if moves[i].FromWorkspace != moves[j].FromWorkspace {
+			return moves[i].FromWorkspace < moves[j].FromWorkspace
+		}
+		return moves[i].From < moves[j].From
+	})
+
+	f := hclwrite.NewEmptyFile()
+	fBody := f.Body()
+
+	for start := 0; start < len(moves); {
+		end := start
+		var actions []cty.Value
+		for ; end < len(moves) && moves[end].FromWorkspace == moves[start].FromWorkspace; end++ {
+			actions = append(actions, cty.StringVal(fmt.Sprintf("mv %v %v", moves[end].From, moves[end].To)))
+		}
+
+		fromWorkspace, toWorkspace := moves[start].FromWorkspace, moves[start].ToWorkspace
+		if start > 0 {
+			fBody.AppendNewline()
+		}
+		migrationBlockBody := fBody.AppendNewBlock(
+			"migration", []string{"multi_state", fmt.Sprintf("%v_to_%v", fromWorkspace, toWorkspace)},
+		).Body()
+		migrationBlockBody.SetAttributeValue("from_dir", cty.StringVal(fmt.Sprintf("/github/workspace%v", workspaceToDirectory[fromWorkspace])))
+		migrationBlockBody.SetAttributeValue("to_dir", cty.StringVal(fmt.Sprintf("/github/workspace%v", workspaceToDirectory[toWorkspace])))
+		migrationBlockBody.SetAttributeValue("from_workspace", cty.StringVal(fromWorkspace))
+		migrationBlockBody.SetAttributeValue("to_workspace", cty.StringVal(toWorkspace))
+		migrationBlockBody.SetAttributeValue("actions", cty.ListVal(actions))
+
+		start = end
+	}
+
+	return f.Bytes()
+}
@@ -0,0 +1,54 @@
+package hclcreate
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+)
+
+func TestWriteWorkspaceMoves(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	require.NoError(t, os.MkdirAll("mappings", 0700))
+	require.NoError(t, os.WriteFile("mappings/managed-resources-to-moves.json", []byte(`[
+		{"FromWorkspace": "networking", "From": "aws_vpc.main", "ToWorkspace": "networking", "To": "module.vpc.aws_vpc.main"},
+		{"FromWorkspace": "legacy", "From": "module.queues.aws_sqs_queue.orders", "ToWorkspace": "networking", "To": "aws_sqs_queue.orders"}
+	]`), 0400))
+
+	h := hclCreate{artifactStore: artifacts.NewMemoryStore(true)}
+	workspaceToDirectory := map[string]string{"legacy": "/legacy/", "networking": "/networking/"}
+
+	// When
+	err := h.WriteWorkspaceMoves("abc123", workspaceToDirectory)
+
+	// Then
+	require.NoError(t, err)
+	moved, err := os.ReadFile("repo/networking/cloud-concierge/moves/abc123_moved.tf")
+	require.NoError(t, err)
+	assert.Equal(t, `moved {
+  from = aws_vpc.main
+  to   = module.vpc.aws_vpc.main
+}
+`, string(moved))
+
+	migrations, err := os.ReadFile("repo/networking/cloud-concierge/tfmigrate/abc123_moves.hcl")
+	require.NoError(t, err)
+	assert.Equal(t, `migration "multi_state" "legacy_to_networking" {
+  from_dir       = "/github/workspace/legacy/"
+  to_dir         = "/github/workspace/networking/"
+  from_workspace = "legacy"
+  to_workspace   = "networking"
+  actions        = ["mv module.queues.aws_sqs_queue.orders aws_sqs_queue.orders"]
+}
+`, string(migrations))
+
+	_, err = os.Stat("repo/legacy/cloud-concierge")
+	assert.True(t, os.IsNotExist(err))
+}
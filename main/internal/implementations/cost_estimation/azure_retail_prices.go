@@ -0,0 +1,321 @@
+package costEstimation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+// azureRetailPricesEndpoint is the Azure Retail Prices API endpoint, which requires no authentication.
+var azureRetailPricesEndpoint = "https://prices.azure.com/api/retail/prices"
+
+// hoursPerMonth is the number of hours by which hourly prices are converted to monthly estimates, matching Infracost.
+const hoursPerMonth = 730
+
+// AzureRetailPrice is a single meter price returned by the Azure Retail Prices API.
+type AzureRetailPrice struct {
+	ArmRegionName string  `json:"armRegionName"`
+	ArmSkuName    string  `json:"armSkuName"`
+	MeterName     string  `json:"meterName"`
+	ProductName   string  `json:"productName"`
+	ServiceName   string  `json:"serviceName"`
+	SkuName       string  `json:"skuName"`
+	RetailPrice   float64 `json:"retailPrice"`
+	UnitOfMeasure string  `json:"unitOfMeasure"`
+}
+
+// azureRetailPricesPage is a page of an Azure Retail Prices API response.
+type azureRetailPricesPage struct {
+	Items        []AzureRetailPrice `json:"Items"`
+	NextPageLink string             `json:"NextPageLink"`
+}
+
+// azurePricingRule prices the resources of an azurerm resource type from the Azure Retail Prices API.
+type azurePricingRule struct {
+	// costComponent names the priced meter within the cost estimates.
+	costComponent string
+
+	// filter returns the OData filter selecting the resource's candidate prices, or false when the resource's
+	// attributes do not determine a price.
+	filter func(attributesFlat map[string]string, region string) (string, bool)
+
+	// matches narrows the candidate prices to the resource's meter, e.g. excluding spot and Windows meters.
+	matches func(attributesFlat map[string]string, price AzureRetailPrice) bool
+
+	// monthlyQuantity returns the number of units billed per month.
+	monthlyQuantity func(attributesFlat map[string]string) float64
+}
+
+// azurePricingRules are keyed by the azurerm resource types found by the scan. Resource types without a rule are
+// not priced.
+var azurePricingRules = map[string]azurePricingRule{
+	"azurerm_linux_virtual_machine":   virtualMachinePricingRule("size", false, nil),
+	"azurerm_windows_virtual_machine": virtualMachinePricingRule("size", true, nil),
+	"azurerm_virtual_machine":         virtualMachinePricingRule("vm_size", false, nil),
+	"azurerm_linux_virtual_machine_scale_set": virtualMachinePricingRule("sku", false, func(attributesFlat map[string]string) float64 {
+		return attributeFloat(attributesFlat, "instances", 1)
+	}),
+	"azurerm_windows_virtual_machine_scale_set": virtualMachinePricingRule("sku", true, func(attributesFlat map[string]string) float64 {
+		return attributeFloat(attributesFlat, "instances", 1)
+	}),
+	"azurerm_managed_disk": {
+		costComponent: "Managed disk",
+		filter: func(attributesFlat map[string]string, region string) (string, bool) {
+			diskSku, ok := managedDiskSku(attributesFlat["storage_account_type"], attributeFloat(attributesFlat, "disk_size_gb", 0))
+			if !ok {
+				return "", false
+			}
+			return fmt.Sprintf("serviceName eq 'Storage' and armRegionName eq '%v' and skuName eq '%v' and priceType eq 'Consumption'", region, diskSku), true
+		},
+		matches: func(_ map[string]string, price AzureRetailPrice) bool {
+			return strings.HasSuffix(price.MeterName, "Disk") && price.UnitOfMeasure == "1/Month"
+		},
+		monthlyQuantity: func(_ map[string]string) float64 { return 1 },
+	},
+	"azurerm_public_ip": {
+		costComponent: "Public IP address",
+		filter: func(_ map[string]string, region string) (string, bool) {
+			return fmt.Sprintf("serviceName eq 'Virtual Network' and armRegionName eq '%v' and productName eq 'IP Addresses' and priceType eq 'Consumption'", region), true
+		},
+		matches: func(attributesFlat map[string]string, price AzureRetailPrice) bool {
+			sku := attributesFlat["sku"]
+			if sku == "" {
+				sku = "Basic"
+			}
+			return strings.EqualFold(price.SkuName, sku) &&
+				strings.Contains(price.MeterName, attributesFlat["allocation_method"]) &&
+				strings.Contains(price.MeterName, "IPv4") &&
+				price.UnitOfMeasure == "1 Hour"
+		},
+		monthlyQuantity: func(_ map[string]string) float64 { return hoursPerMonth },
+	},
+	"azurerm_nat_gateway": {
+		costComponent: "NAT gateway",
+		filter: func(_ map[string]string, region string) (string, bool) {
+			return fmt.Sprintf("serviceName eq 'NAT Gateway' and armRegionName eq '%v' and priceType eq 'Consumption'", region), true
+		},
+		matches: func(_ map[string]string, price AzureRetailPrice) bool {
+			return strings.HasSuffix(price.MeterName, "Gateway") && price.UnitOfMeasure == "1 Hour"
+		},
+		monthlyQuantity: func(_ map[string]string) float64 { return hoursPerMonth },
+	},
+}
+
+// virtualMachinePricingRule prices virtual machines, or scale sets, by the size within skuAttribute. Instances are
+// priced at the pay-as-you-go rate of their operating system, excluding spot and low priority meters.
+func virtualMachinePricingRule(skuAttribute string, windows bool, instances func(attributesFlat map[string]string) float64) azurePricingRule {
+	return azurePricingRule{
+		costComponent: "Instance usage",
+		filter: func(attributesFlat map[string]string, region string) (string, bool) {
+			size := attributesFlat[skuAttribute]
+			if size == "" {
+				return "", false
+			}
+			return fmt.Sprintf("serviceName eq 'Virtual Machines' and armRegionName eq '%v' and armSkuName eq '%v' and priceType eq 'Consumption'", region, size), true
+		},
+		matches: func(attributesFlat map[string]string, price AzureRetailPrice) bool {
+			isWindows := windows || strings.EqualFold(attributesFlat["storage_os_disk.0.os_type"], "Windows")
+			return !strings.Contains(price.MeterName, "Spot") &&
+				!strings.Contains(price.MeterName, "Low Priority") &&
+				strings.HasSuffix(price.ProductName, "Windows") == isWindows &&
+				price.UnitOfMeasure == "1 Hour"
+		},
+		monthlyQuantity: func(attributesFlat map[string]string) float64 {
+			if instances == nil {
+				return hoursPerMonth
+			}
+			return hoursPerMonth * instances(attributesFlat)
+		},
+	}
+}
+
+// managedDiskTierSizes are the maximum sizes, in GiB, of the managed disk tiers.
+var managedDiskTierSizes = []struct {
+	maxSizeGB float64
+	tier      string
+}{
+	{4, "1"}, {8, "2"}, {16, "3"}, {32, "4"}, {64, "6"}, {128, "10"}, {256, "15"}, {512, "20"},
+	{1024, "30"}, {2048, "40"}, {4096, "50"}, {8192, "60"}, {16384, "70"}, {32767, "80"},
+}
+
+// managedDiskSku returns the retail sku name of a managed disk, e.g. "P10 LRS" for a 128 GiB Premium_LRS disk.
+func managedDiskSku(storageAccountType string, sizeGB float64) (string, bool) {
+	diskType, redundancy, found := strings.Cut(storageAccountType, "_")
+	if !found || sizeGB <= 0 {
+		return "", false
+	}
+
+	prefixes := map[string]string{"Premium": "P", "StandardSSD": "E", "Standard": "S"}
+	prefix, ok := prefixes[diskType]
+	if !ok {
+		return "", false
+	}
+
+	for _, tierSize := range managedDiskTierSizes {
+		if sizeGB <= tierSize.maxSizeGB {
+			return fmt.Sprintf("%v%v %v", prefix, tierSize.tier, redundancy), true
+		}
+	}
+	return "", false
+}
+
+// attributeFloat parses a numeric flat attribute, returning defaultValue when it is missing or not a number.
+func attributeFloat(attributesFlat map[string]string, attribute string, defaultValue float64) float64 {
+	value, err := strconv.ParseFloat(attributesFlat[attribute], 64)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// azureRegion converts a location attribute, e.g. "East US" or "eastus", to its Azure Retail Prices region name.
+func azureRegion(location string) string {
+	return strings.ToLower(strings.ReplaceAll(location, " ", ""))
+}
+
+// GetAzureDivisionCostEstimate prices the resources within an Azure division's scanned state from the Azure Retail
+// Prices API, writing cost estimates in the same format as the formatted Infracost output.
+func (ce *CostEstimator) GetAzureDivisionCostEstimate(ctx context.Context, division terraformValueObjects.Division) error {
+	divisionFolderName := fmt.Sprintf("%v-%v", ce.divisionToProvider[division], division)
+
+	state, err := ce.loadTerraformerState(division)
+	if err != nil {
+		return fmt.Errorf("[get_azure_division_cost_estimate]%w", err)
+	}
+
+	rows, err := ce.priceAzureResources(ctx, state)
+	if err != nil {
+		return fmt.Errorf("[get_azure_division_cost_estimate]%w", err)
+	}
+
+	rowsJSON, err := json.Marshal(rows)
+	if err != nil {
+		return fmt.Errorf("[get_azure_division_cost_estimate][json.Marshal]%w", err)
+	}
+
+	err = os.WriteFile(fmt.Sprintf("current_cloud/%v/infracost-formatted.json", divisionFolderName), rowsJSON, 0400)
+	if err != nil {
+		return fmt.Errorf("[get_azure_division_cost_estimate][os.WriteFile]%w", err)
+	}
+	return nil
+}
+
+// priceAzureResources returns a cost component row for each resource with a pricing rule and a matching price.
+// Prices are fetched concurrently, and resources whose prices could not be fetched are marked with an unknown cost.
+func (ce *CostEstimator) priceAzureResources(ctx context.Context, state driftDetector.TerraformerStateFile) ([]map[string]interface{}, error) {
+	rows := make([]map[string]interface{}, 0)
+
+	resourceFilters := make([]string, len(state.Resources))
+	filters := make([]string, 0)
+	for i, resource := range state.Resources {
+		rule, ok := azurePricingRules[resource.Type]
+		if !ok || len(resource.Instances) == 0 {
+			continue
+		}
+
+		attributesFlat := resource.Instances[0].AttributesFlat
+		filter, ok := rule.filter(attributesFlat, azureRegion(attributesFlat["location"]))
+		if !ok {
+			continue
+		}
+
+		if !containsString(filters, filter) {
+			filters = append(filters, filter)
+		}
+		resourceFilters[i] = filter
+	}
+
+	filterToPrices, filterToErr := ce.fetchPrices(ctx, "azure", filters, func() interface{} {
+		return &[]AzureRetailPrice{}
+	}, func(filter string) (interface{}, error) {
+		return queryAzureRetailPrices(ctx, filter)
+	})
+
+	for i, resource := range state.Resources {
+		filter := resourceFilters[i]
+		if filter == "" {
+			continue
+		}
+
+		resourceName := fmt.Sprintf("%v.%v", resource.Type, resource.Name)
+		if err, ok := filterToErr[filter]; ok {
+			log.Warnf("[price_azure_resources] %v has an unknown cost: %v", resourceName, err)
+			rows = append(rows, newUnknownCostRow(resourceName))
+			continue
+		}
+
+		rule := azurePricingRules[resource.Type]
+		attributesFlat := resource.Instances[0].AttributesFlat
+		for _, price := range *filterToPrices[filter].(*[]AzureRetailPrice) {
+			if !rule.matches(attributesFlat, price) {
+				continue
+			}
+
+			rows = append(rows, newCostComponentRow(
+				resourceName,
+				fmt.Sprintf("%v (%v)", rule.costComponent, price.MeterName),
+				price.UnitOfMeasure,
+				price.RetailPrice,
+				rule.monthlyQuantity(attributesFlat),
+				false,
+			))
+			break
+		}
+	}
+
+	return rows, nil
+}
+
+// queryAzureRetailPrices returns every price matching the OData filter, sorted from lowest to highest so that the
+// first matching meter is the cheapest.
+func queryAzureRetailPrices(ctx context.Context, filter string) ([]AzureRetailPrice, error) {
+	pageURL := azureRetailPricesEndpoint + "?" + url.Values{"$filter": {filter}}.Encode()
+
+	prices := make([]AzureRetailPrice, 0)
+	for pageURL != "" {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			return nil, fmt.Errorf("[query_azure_retail_prices][http.Do]%w", err)
+		}
+		body, err := io.ReadAll(response.Body)
+		response.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("[query_azure_retail_prices][io.ReadAll]%w", err)
+		}
+
+		if response.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("[query_azure_retail_prices][unexpected status code %d]%s", response.StatusCode, string(body))
+		}
+
+		page := azureRetailPricesPage{}
+		err = json.Unmarshal(body, &page)
+		if err != nil {
+			return nil, fmt.Errorf("[query_azure_retail_prices][json.Unmarshal]%w", err)
+		}
+
+		prices = append(prices, page.Items...)
+		pageURL = page.NextPageLink
+	}
+
+	sort.SliceStable(prices, func(i, j int) bool {
+		return prices[i].RetailPrice < prices[j].RetailPrice
+	})
+	return prices, nil
+}
@@ -0,0 +1,120 @@
+package costEstimation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+func TestManagedDiskSku(t *testing.T) {
+	testCases := []struct {
+		storageAccountType string
+		sizeGB             float64
+		want               string
+		ok                 bool
+	}{
+		{storageAccountType: "Premium_LRS", sizeGB: 128, want: "P10 LRS", ok: true},
+		{storageAccountType: "StandardSSD_ZRS", sizeGB: 100, want: "E10 ZRS", ok: true},
+		{storageAccountType: "Standard_LRS", sizeGB: 4, want: "S1 LRS", ok: true},
+		{storageAccountType: "UltraSSD_LRS", sizeGB: 128},
+		{storageAccountType: "Premium_LRS", sizeGB: 0},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.storageAccountType, func(t *testing.T) {
+			// When
+			got, ok := managedDiskSku(testCase.storageAccountType, testCase.sizeGB)
+
+			// Then
+			assert.Equal(t, testCase.ok, ok)
+			assert.Equal(t, testCase.want, got)
+		})
+	}
+}
+
+func TestGetAzureDivisionCostEstimate(t *testing.T) {
+	// Given
+	filters := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filter := r.URL.Query().Get("$filter")
+		filters = append(filters, filter)
+
+		page := azureRetailPricesPage{}
+		switch filter {
+		case "serviceName eq 'Virtual Machines' and armRegionName eq 'eastus' and armSkuName eq 'Standard_D2s_v3' and priceType eq 'Consumption'":
+			page.Items = []AzureRetailPrice{
+				{MeterName: "D2s v3", ProductName: "Virtual Machines DSv3 Series Windows", RetailPrice: 0.188, UnitOfMeasure: "1 Hour"},
+				{MeterName: "D2s v3 Spot", ProductName: "Virtual Machines DSv3 Series", RetailPrice: 0.02, UnitOfMeasure: "1 Hour"},
+				{MeterName: "D2s v3", ProductName: "Virtual Machines DSv3 Series", RetailPrice: 0.096, UnitOfMeasure: "1 Hour"},
+			}
+		case "serviceName eq 'Storage' and armRegionName eq 'eastus' and skuName eq 'P10 LRS' and priceType eq 'Consumption'":
+			page.Items = []AzureRetailPrice{
+				{MeterName: "P10 Disk Mount", RetailPrice: 0.0013, UnitOfMeasure: "1/Hour"},
+				{MeterName: "P10 LRS Disk", RetailPrice: 19.71, UnitOfMeasure: "1/Month"},
+			}
+		}
+
+		body, err := json.Marshal(page)
+		require.NoError(t, err)
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	originalEndpoint := azureRetailPricesEndpoint
+	azureRetailPricesEndpoint = server.URL
+	defer func() { azureRetailPricesEndpoint = originalEndpoint }()
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+	require.NoError(t, os.MkdirAll("current_cloud/azurerm-my-rg", 0755))
+
+	state := `{"resources": [
+		{"type": "azurerm_linux_virtual_machine", "name": "tfer--web-1", "instances": [{"attributes_flat": {"size": "Standard_D2s_v3", "location": "East US"}}]},
+		{"type": "azurerm_linux_virtual_machine", "name": "tfer--web-2", "instances": [{"attributes_flat": {"size": "Standard_D2s_v3", "location": "eastus"}}]},
+		{"type": "azurerm_managed_disk", "name": "tfer--data", "instances": [{"attributes_flat": {"storage_account_type": "Premium_LRS", "disk_size_gb": "128", "location": "eastus"}}]},
+		{"type": "azurerm_resource_group", "name": "tfer--my-rg", "instances": [{"attributes_flat": {"location": "eastus"}}]}
+	]}`
+	require.NoError(t, os.WriteFile("current_cloud/azurerm-my-rg/terraform.tfstate", []byte(state), 0400))
+
+	ce := CostEstimator{divisionToProvider: map[terraformValueObjects.Division]terraformValueObjects.Provider{"my-rg": "azurerm"}}
+
+	// When
+	err = ce.GetAzureDivisionCostEstimate(context.Background(), "my-rg")
+
+	// Then
+	require.NoError(t, err)
+	assert.Len(t, filters, 2)
+
+	content, err := os.ReadFile("current_cloud/azurerm-my-rg/infracost-formatted.json")
+	require.NoError(t, err)
+	rows := make([]map[string]interface{}, 0)
+	require.NoError(t, json.Unmarshal(content, &rows))
+
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"resource_name": "azurerm_linux_virtual_machine.tfer--web-1", "cost_component": "Instance usage (D2s v3)",
+			"unit": "1 Hour", "price": "0.096", "monthly_quantity": "730", "monthly_cost": "70.0800",
+			"is_usage_based": false, "sub_resource_name": "",
+		},
+		{
+			"resource_name": "azurerm_linux_virtual_machine.tfer--web-2", "cost_component": "Instance usage (D2s v3)",
+			"unit": "1 Hour", "price": "0.096", "monthly_quantity": "730", "monthly_cost": "70.0800",
+			"is_usage_based": false, "sub_resource_name": "",
+		},
+		{
+			"resource_name": "azurerm_managed_disk.tfer--data", "cost_component": "Managed disk (P10 LRS Disk)",
+			"unit": "1/Month", "price": "19.71", "monthly_quantity": "1", "monthly_cost": "19.7100",
+			"is_usage_based": false, "sub_resource_name": "",
+		},
+	}, rows)
+}
@@ -3,13 +3,25 @@ package costEstimation
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/Jeffail/gabs/v2"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
+	log "github.com/sirupsen/logrus"
 )
 
 // CostEstimatorConfig is configuration for the CostEstimator struct that conforms
@@ -21,6 +33,50 @@ type CostEstimatorConfig struct {
 
 	// InfracostAPIToken is the token for accessing Infracost's API.
 	InfracostAPIToken string `required:"true"`
+
+	// PriceCacheDirectory is the directory, typically a mounted volume, in which pricing data is cached between runs.
+	// Caching is disabled when empty.
+	PriceCacheDirectory string
+
+	// PriceCacheMaxAge is the maximum age of cached pricing data before it is downloaded again.
+	PriceCacheMaxAge time.Duration
+
+	// PricingConcurrency is the maximum number of pricing requests, and of divisions priced by Infracost, at once.
+	PricingConcurrency int
+
+	// PricingMaxRetries is the number of times a failed pricing request is retried before the resources it prices
+	// are reported with an unknown cost.
+	PricingMaxRetries int
+
+	// PricingRetryBackoff is the backoff before the first retry of a failed pricing request, doubled on each retry.
+	PricingRetryBackoff time.Duration
+
+	// OfflinePricing flags that resources are priced only from a pricing snapshot, without querying any pricing API.
+	OfflinePricing bool
+
+	// PricingSnapshotDirectory is the pricing snapshot used when pricing offline, e.g. a copy of a price cache
+	// directory populated by a connected run. Defaults to the snapshot bundled within the container image.
+	PricingSnapshotDirectory string
+
+	// InfracostPricingAPIEndpoint is the url of a self-hosted Infracost Cloud Pricing API queried in place of
+	// Infracost's hosted pricing API. When pricing offline, estimates absent from the pricing snapshot are
+	// produced from it.
+	InfracostPricingAPIEndpoint string
+
+	// Currency is the ISO 4217 code of the currency in which cost outputs are expressed. Prices are quoted in USD
+	// and converted when another currency is configured.
+	Currency string
+
+	// ExchangeRate is a static number of units of Currency per USD. The ECB reference rate is used when unset.
+	ExchangeRate float64
+
+	// UsageFile is the path, relative to the root of the scanned repository, of an Infracost usage file estimating
+	// the consumption of usage based resources. Usage based resources are left unpriced when the file does not exist.
+	UsageFile string
+
+	// WorkspaceMonthlyBudget is the monthly cost, in Currency, of a workspace's uncontrolled resources at which the
+	// workspace's cost health score reaches 0.
+	WorkspaceMonthlyBudget float64
 }
 
 // CostEstimator is a struct that implements interfaces.CostEstimation.
@@ -34,33 +90,74 @@ type CostEstimator struct {
 	// For AWS, an account is the division, for GCP a project name is the division,
 	// and for azurerm a resource group is a division.
 	divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider `required:"true"`
+
+	// usageFile is the loaded usage file of the scanned repository, or nil when there is none.
+	usageFile *UsageFile
+
+	// prices caches the pricing data queried from the Azure Retail Prices and Cloud Billing Catalog APIs, or is nil
+	// when neither a price cache nor offline pricing is configured.
+	prices *priceCache
+
+	// currency is the display currency of the cost outputs.
+	currency CostCurrency
+
+	// unpricedDivisions are the divisions, stored as keys, whose Infracost estimates failed, so that their resources
+	// are reported with an unknown cost.
+	unpricedDivisions sync.Map
+
+	// artifactStore holds the mappings read and written by the cost estimator.
+	artifactStore artifacts.Store
 }
 
 // NewCostEstimator creates a new instance of CostEstimator a struct that implements interfaces.CostEstimation.
-func NewCostEstimator(config CostEstimatorConfig, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider) interfaces.CostEstimation {
+func NewCostEstimator(config CostEstimatorConfig, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider, artifactStore artifacts.Store) interfaces.CostEstimation {
 	return &CostEstimator{
 		config:             config,
 		divisionToProvider: divisionToProvider,
+		prices:             newPriceCache(config),
+		artifactStore:      artifactStore,
 	}
 }
 
 // Execute creates structured cost estimation data for the current identified/scanned
 // cloud resources.
 func (ce *CostEstimator) Execute(ctx context.Context) error {
-	if ce.config.InfracostAPIToken == "None" {
+	if len(ce.pricedDivisions()) == 0 {
 		fmt.Println("No Infracost token specified, skipping cost estimation.")
 		return nil
 	}
 
-	// Setting the Infracost API token
-	authArgs := []string{"configure", "set", "api_key", ce.config.InfracostAPIToken}
-	_, err := executeCommand("infracost", authArgs...)
+	if ce.infracostEnabled() && ce.config.InfracostAPIToken != "None" {
+		// Setting the Infracost API token
+		authArgs := []string{"configure", "set", "api_key", ce.config.InfracostAPIToken}
+		_, err := executeCommand("infracost", authArgs...)
+		if err != nil {
+			return fmt.Errorf("[gcloud_authentication][gcloud auth activate-service-account, failed to authenticate]%w", err)
+		}
+		fmt.Println("Done setting Infracost API token.")
+	}
+
+	if ce.infracostEnabled() && ce.config.InfracostPricingAPIEndpoint != "" {
+		endpointArgs := []string{"configure", "set", "pricing_api_endpoint", ce.config.InfracostPricingAPIEndpoint}
+		_, err := executeCommand("infracost", endpointArgs...)
+		if err != nil {
+			return fmt.Errorf("[infracost configure set pricing_api_endpoint]%w", err)
+		}
+	}
+
+	usageFile, err := ce.loadUsageFile()
 	if err != nil {
-		return fmt.Errorf("[gcloud_authentication][gcloud auth activate-service-account, failed to authenticate]%w", err)
+		return fmt.Errorf("[ce.loadUsageFile]%v", err)
 	}
-	fmt.Println("Done setting Infracost API token.")
+	ce.usageFile = usageFile
 
-	err = ce.GetAllCostEstimates()
+	currency, err := ce.resolveCurrency(ctx)
+	if err != nil {
+		return fmt.Errorf("[ce.resolveCurrency]%v", err)
+	}
+	ce.currency = currency
+
+	err = ce.GetAllCostEstimates(ctx)
 	if err != nil {
 		return fmt.Errorf("[ce.GetAllCostEstimates]%v", err)
 	}
@@ -70,20 +167,113 @@ func (ce *CostEstimator) Execute(ctx context.Context) error {
 		return fmt.Errorf("[ce.FormatAllCostEstimates]%v", err)
 	}
 
+	err = ce.GetAllGCPCatalogCostEstimates(ctx)
+	if err != nil {
+		return fmt.Errorf("[ce.GetAllGCPCatalogCostEstimates]%v", err)
+	}
+
 	err = ce.AggregateCostEstimates()
 	if err != nil {
 		return fmt.Errorf("[ce.AggregateCostEstimates]%v", err)
 	}
 
+	err = ce.EstimateDriftCostImpact(ctx)
+	if err != nil {
+		return fmt.Errorf("[ce.EstimateDriftCostImpact]%v", err)
+	}
+
+	err = ce.currency.writeCostCurrency(ce.artifactStore)
+	if err != nil {
+		return fmt.Errorf("[ce.currency.writeCostCurrency]%v", err)
+	}
+
+	err = ce.writeWorkspaceCostBudget()
+	if err != nil {
+		return fmt.Errorf("[ce.writeWorkspaceCostBudget]%v", err)
+	}
+
 	return nil
 }
 
+// infracostEnabled returns true if an Infracost API token is configured. When pricing offline, Infracost estimates
+// are instead read from the pricing snapshot, or produced by a self-hosted pricing API when one is configured.
+func (ce *CostEstimator) infracostEnabled() bool {
+	if ce.prices.isOffline() {
+		return ce.config.InfracostPricingAPIEndpoint != "" || ce.prices.hasSource(infracostPriceSource)
+	}
+	return ce.config.InfracostAPIToken != "None"
+}
+
+// isAzureDivision returns true if the division is priced by the Azure Retail Prices API rather than Infracost.
+func (ce *CostEstimator) isAzureDivision(division terraformValueObjects.Division) bool {
+	return ce.divisionToProvider[division] == "azurerm"
+}
+
+// isGCPDivision returns true if the division is priced by the Cloud Billing Catalog API, in addition to Infracost
+// when it is enabled.
+func (ce *CostEstimator) isGCPDivision(division terraformValueObjects.Division) bool {
+	return ce.divisionToProvider[division] == "google"
+}
+
+// usesInfracost returns true if the division is priced by Infracost.
+func (ce *CostEstimator) usesInfracost(division terraformValueObjects.Division) bool {
+	return ce.infracostEnabled() && !ce.isAzureDivision(division)
+}
+
+// pricedDivisions returns the divisions for which cost estimates are produced.
+func (ce *CostEstimator) pricedDivisions() []terraformValueObjects.Division {
+	divisions := make([]terraformValueObjects.Division, 0)
+	for division := range ce.config.DivisionCloudCredentials {
+		if ce.isAzureDivision(division) || ce.isGCPDivision(division) || ce.infracostEnabled() {
+			divisions = append(divisions, division)
+		}
+	}
+	sort.Slice(divisions, func(i, j int) bool { return divisions[i] < divisions[j] })
+	return divisions
+}
+
+// loadTerraformerState loads the scanned terraformer state of a division.
+func (ce *CostEstimator) loadTerraformerState(division terraformValueObjects.Division) (driftDetector.TerraformerStateFile, error) {
+	divisionFolderName := fmt.Sprintf("%v-%v", ce.divisionToProvider[division], division)
+
+	content, err := os.ReadFile(fmt.Sprintf("current_cloud/%v/terraform.tfstate", divisionFolderName))
+	if err != nil {
+		return driftDetector.TerraformerStateFile{}, fmt.Errorf("[load_terraformer_state][os.ReadFile]%w", err)
+	}
+
+	state, err := driftDetector.ParseTerraformerStateFile(content)
+	if err != nil {
+		return driftDetector.TerraformerStateFile{}, fmt.Errorf("[load_terraformer_state][driftDetector.ParseTerraformerStateFile]%w", err)
+	}
+	return state, nil
+}
+
+// newCostComponentRow returns a row of cost component data in the format of the formatted Infracost output. The
+// monthly quantity and cost of usage based components are left empty.
+func newCostComponentRow(resourceName string, costComponent string, unit string, price float64, monthlyQuantity float64, isUsageBased bool) map[string]interface{} {
+	row := map[string]interface{}{
+		"resource_name":     resourceName,
+		"cost_component":    costComponent,
+		"unit":              unit,
+		"price":             strconv.FormatFloat(price, 'f', -1, 64),
+		"monthly_quantity":  "",
+		"monthly_cost":      "",
+		"is_usage_based":    isUsageBased,
+		"sub_resource_name": "",
+	}
+	if !isUsageBased {
+		row["monthly_quantity"] = strconv.FormatFloat(monthlyQuantity, 'f', -1, 64)
+		row["monthly_cost"] = strconv.FormatFloat(price*monthlyQuantity, 'f', 4, 64)
+	}
+	return row
+}
+
 // AggregateCostEstimates merges all calculated and formatted cost estimations into a single
-// json object and outputs it to data maps for end consumption.
+// json object, converted into the display currency, and outputs it to data maps for end consumption.
 func (ce *CostEstimator) AggregateCostEstimates() error {
 	outputObj := gabs.New()
 
-	for division := range ce.config.DivisionCloudCredentials {
+	for _, division := range ce.pricedDivisions() {
 		divisionFolderName := fmt.Sprintf("%v-%v", ce.divisionToProvider[division], division)
 
 		infracostJSONPath := fmt.Sprintf("./current_cloud/%v/infracost-formatted.json", divisionFolderName)
@@ -93,45 +283,197 @@ func (ce *CostEstimator) AggregateCostEstimates() error {
 			return fmt.Errorf("[os.ReadFile]%v", err)
 		}
 
-		costContainer, err := gabs.ParseJSON(divisionCosts)
+		rows := make([]map[string]interface{}, 0)
+		err = json.Unmarshal(divisionCosts, &rows)
 		if err != nil {
-			return fmt.Errorf("[gabs.ParseJSON]%v", err)
+			return fmt.Errorf("[json.Unmarshal]%v", err)
 		}
+		ce.currency.convertRows(rows)
 
-		_, err = outputObj.Set(costContainer, divisionFolderName)
+		_, err = outputObj.Set(rows, divisionFolderName)
 		if err != nil {
 			return fmt.Errorf("[outputObj.Set()]%v", err)
 		}
 	}
 
-	err := os.WriteFile("mappings/division-to-cost-estimates.json", outputObj.Bytes(), 0400)
+	err := ce.artifactStore.WriteFile("mappings/division-to-cost-estimates.json", outputObj.Bytes(), 0400)
 	if err != nil {
-		return fmt.Errorf("[os.WriteFile]%v", err)
+		return fmt.Errorf("[artifacts.WriteFile]%v", err)
 	}
 	return nil
 }
 
-// GetAllCostEstimates invokes the infracost CLI to generate cost estimates for identified resources
-// within a all cloud divisions.
-func (ce *CostEstimator) GetAllCostEstimates() error {
-	for division := range ce.config.DivisionCloudCredentials {
-		err := ce.GetDivisionCostEstimate(division)
+// GetAllCostEstimates invokes the infracost CLI, or the Azure Retail Prices API for Azure divisions, to generate
+// cost estimates for identified resources within a all cloud divisions. Divisions are priced concurrently.
+func (ce *CostEstimator) GetAllCostEstimates(ctx context.Context) error {
+	divisions := make([]string, 0)
+	for _, division := range ce.pricedDivisions() {
+		if ce.isAzureDivision(division) || ce.usesInfracost(division) {
+			divisions = append(divisions, string(division))
+		}
+	}
+
+	mu := sync.Mutex{}
+	errs := make([]error, 0)
+	ce.forEachConcurrently(divisions, func(key string) {
+		division := terraformValueObjects.Division(key)
+
+		var err error
+		if ce.isAzureDivision(division) {
+			err = ce.GetAzureDivisionCostEstimate(ctx, division)
+			if err != nil {
+				err = fmt.Errorf("[ce.GetAzureDivisionCostEstimate for division %v]%v", division, err)
+			}
+		} else {
+			err = ce.GetDivisionCostEstimate(ctx, division)
+			if err != nil {
+				err = fmt.Errorf("[ce.GetDivisionCostEstimate for division %v]%v", division, err)
+			}
+		}
+
 		if err != nil {
-			return fmt.Errorf("[ce.GetDivisionCostEstimate for division %v]%v", division, err)
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
 		}
+	})
+
+	if len(errs) > 0 {
+		return errs[0]
 	}
 	return nil
 }
 
 // GetDivisionCostEstimate invokes the infracost CLI to generate cost estimates for identified resources
 // within a single, specified, cloud division.
-func (ce *CostEstimator) GetDivisionCostEstimate(division terraformValueObjects.Division) error {
+func (ce *CostEstimator) GetDivisionCostEstimate(ctx context.Context, division terraformValueObjects.Division) error {
 	divisionFolderName := fmt.Sprintf("%v-%v", ce.divisionToProvider[division], division)
 
 	infracostEstimationPath := fmt.Sprintf("./current_cloud/%v/", divisionFolderName)
 	infracostJSONPath := fmt.Sprintf("./current_cloud/%v/infracost.json", divisionFolderName)
 
-	costEstimateArgs := []string{"breakdown", "--path", infracostEstimationPath, "--format", "json", "--out-file", infracostJSONPath}
+	usageFilePath := ""
+	if ce.usageFile != nil {
+		state, err := ce.loadTerraformerState(division)
+		if err != nil {
+			return fmt.Errorf("[ce.loadTerraformerState]%v", err)
+		}
+
+		usageFilePath, err = ce.usageFile.forDivision(divisionFolderName, state).writeDivisionUsageFile(divisionFolderName)
+		if err != nil {
+			return fmt.Errorf("[writeDivisionUsageFile]%v", err)
+		}
+	}
+
+	err := ce.infracostEstimate(ctx, infracostEstimationPath, infracostJSONPath, usageFilePath)
+	if err != nil {
+		log.Warnf("[get_division_cost_estimate] resources within %v have an unknown cost: %v", division, err)
+		return ce.writeUnknownCostEstimate(division)
+	}
+
+	return nil
+}
+
+// writeUnknownCostEstimate writes an unknown cost row for every resource within the division's scanned state in
+// place of the formatted Infracost output, and flags the division so that its Infracost output is not formatted.
+func (ce *CostEstimator) writeUnknownCostEstimate(division terraformValueObjects.Division) error {
+	state, err := ce.loadTerraformerState(division)
+	if err != nil {
+		return fmt.Errorf("[write_unknown_cost_estimate]%w", err)
+	}
+
+	rows := make([]map[string]interface{}, 0)
+	for _, resource := range state.Resources {
+		rows = append(rows, newUnknownCostRow(fmt.Sprintf("%v.%v", resource.Type, resource.Name)))
+	}
+
+	rowsJSON, err := json.Marshal(rows)
+	if err != nil {
+		return fmt.Errorf("[write_unknown_cost_estimate][json.Marshal]%w", err)
+	}
+
+	divisionFolderName := fmt.Sprintf("%v-%v", ce.divisionToProvider[division], division)
+	err = os.WriteFile(fmt.Sprintf("current_cloud/%v/infracost-formatted.json", divisionFolderName), rowsJSON, 0400)
+	if err != nil {
+		return fmt.Errorf("[write_unknown_cost_estimate][os.WriteFile]%w", err)
+	}
+
+	ce.unpricedDivisions.Store(division, true)
+	return nil
+}
+
+// infracostEstimate writes the Infracost estimate of the Terraform files within estimationPath to jsonPath. Estimates
+// are cached by the digest of their input files, so that the infracost CLI is only invoked for inputs without a fresh
+// cached estimate, and pricing offline reads the estimates of the pricing snapshot.
+func (ce *CostEstimator) infracostEstimate(ctx context.Context, estimationPath string, jsonPath string, usageFilePath string) error {
+	key, err := infracostCacheKey(estimationPath, usageFilePath)
+	if err != nil {
+		return fmt.Errorf("[infracost_estimate]%w", err)
+	}
+
+	download := func() (interface{}, error) {
+		_, err := ce.withRetries(ctx, "infracost breakdown", func() (interface{}, error) {
+			return nil, infracostBreakdown(estimationPath, jsonPath, usageFilePath)
+		})()
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := os.ReadFile(jsonPath)
+		if err != nil {
+			return nil, fmt.Errorf("[os.ReadFile]%w", err)
+		}
+		return json.RawMessage(content), nil
+	}
+
+	estimate := json.RawMessage{}
+	err = ce.prices.fetch(infracostPriceSource, key, &estimate, download)
+	if errors.Is(err, errPriceNotInSnapshot) && ce.config.InfracostPricingAPIEndpoint != "" {
+		err = downloadInto(&estimate, download)
+	}
+	if err != nil {
+		return fmt.Errorf("[infracost_estimate]%w", err)
+	}
+
+	err = os.WriteFile(jsonPath, estimate, 0644)
+	if err != nil {
+		return fmt.Errorf("[infracost_estimate][os.WriteFile]%w", err)
+	}
+	return nil
+}
+
+// infracostCacheKey identifies the Infracost estimate of the Terraform files within estimationPath by the sha256
+// digest of their names and contents, together with the content of the usage file at usageFilePath, if any.
+func infracostCacheKey(estimationPath string, usageFilePath string) (string, error) {
+	paths, err := filepath.Glob(filepath.Join(estimationPath, "*.tf"))
+	if err != nil {
+		return "", fmt.Errorf("[infracost_cache_key][filepath.Glob]%w", err)
+	}
+	sort.Strings(paths)
+	if usageFilePath != "" {
+		paths = append(paths, usageFilePath)
+	}
+
+	hash := sha256.New()
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("[infracost_cache_key][os.ReadFile]%w", err)
+		}
+		fmt.Fprintf(hash, "%v %v\n", filepath.Base(path), len(content))
+		hash.Write(content)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// infracostBreakdown invokes the infracost CLI against the state within estimationPath, writing the JSON estimate to
+// jsonPath. Usage based resources are estimated from the usage file at usageFilePath, unless it is empty.
+func infracostBreakdown(estimationPath string, jsonPath string, usageFilePath string) error {
+	costEstimateArgs := []string{"breakdown", "--path", estimationPath, "--format", "json", "--out-file", jsonPath}
+	if usageFilePath != "" {
+		costEstimateArgs = append(costEstimateArgs, "--usage-file", usageFilePath)
+	}
+
 	_, err := executeCommand("infracost", costEstimateArgs...)
 	if err != nil {
 		return fmt.Errorf("[executeCommand]%v", err)
@@ -1 +1,89 @@
 package costEstimation
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+func TestPricedDivisions(t *testing.T) {
+	// Given
+	ce := CostEstimator{
+		config: CostEstimatorConfig{
+			InfracostAPIToken: "None",
+			DivisionCloudCredentials: terraformValueObjects.DivisionCloudCredentialDecoder{
+				"my-rg":        "{}",
+				"my-project":   "{}",
+				"111111111111": "{}",
+			},
+		},
+		divisionToProvider: map[terraformValueObjects.Division]terraformValueObjects.Provider{
+			"my-rg":        "azurerm",
+			"my-project":   "google",
+			"111111111111": "aws",
+		},
+	}
+
+	// When
+	divisions := ce.pricedDivisions()
+
+	// Then
+	assert.Equal(t, []terraformValueObjects.Division{"my-project", "my-rg"}, divisions)
+	assert.False(t, ce.usesInfracost("my-project"))
+
+	// When
+	ce.config.InfracostAPIToken = "ico-token"
+	divisions = ce.pricedDivisions()
+
+	// Then
+	assert.Equal(t, []terraformValueObjects.Division{"111111111111", "my-project", "my-rg"}, divisions)
+	assert.True(t, ce.usesInfracost("my-project"))
+	assert.False(t, ce.usesInfracost("my-rg"))
+}
+
+func TestInfracostEstimate_Offline(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	require.NoError(t, os.MkdirAll("current_cloud/aws-prod", 0700))
+	require.NoError(t, os.WriteFile("current_cloud/aws-prod/main.tf", []byte(`resource "aws_s3_bucket" "tfer--logs" {}`), 0400))
+
+	key, err := infracostCacheKey("./current_cloud/aws-prod/", "")
+	require.NoError(t, err)
+
+	estimate := `{"projects":[]}`
+	require.NoError(t, writePriceCacheEntry(
+		filepath.Join("snapshot", infracostPriceSource, priceCacheEntryName(key)),
+		priceCacheEntry{Key: key, Value: []byte(estimate)},
+	))
+
+	ce := CostEstimator{
+		config: CostEstimatorConfig{InfracostAPIToken: "None"},
+		prices: &priceCache{directory: "snapshot", offline: true},
+	}
+
+	// When
+	err = ce.infracostEstimate(context.Background(), "./current_cloud/aws-prod/", "./current_cloud/aws-prod/infracost.json", "")
+
+	// Then
+	require.NoError(t, err)
+	assert.True(t, ce.infracostEnabled())
+	content, err := os.ReadFile("current_cloud/aws-prod/infracost.json")
+	require.NoError(t, err)
+	assert.JSONEq(t, estimate, string(content))
+
+	// When
+	require.NoError(t, os.WriteFile("current_cloud/aws-prod/variables.tf", []byte(`variable "region" {}`), 0400))
+	err = ce.infracostEstimate(context.Background(), "./current_cloud/aws-prod/", "./current_cloud/aws-prod/infracost.json", "")
+
+	// Then
+	assert.ErrorIs(t, err, errPriceNotInSnapshot)
+}
@@ -0,0 +1,176 @@
+package costEstimation
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+)
+
+// baseCurrency is the currency in which all pricing sources quote prices.
+const baseCurrency = "USD"
+
+// ecbReferenceRatesEndpoint is the European Central Bank's daily euro foreign exchange reference rates feed.
+var ecbReferenceRatesEndpoint = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+
+// CostCurrency is the currency in which cost outputs are expressed, written to mappings/cost-currency.json.
+type CostCurrency struct {
+	// Currency is the ISO 4217 code of the display currency.
+	Currency string
+
+	// ExchangeRate is the number of units of Currency per USD.
+	ExchangeRate float64
+
+	// Source is where the exchange rate came from, either "static" or "ecb". Empty when costs are in USD.
+	Source string `json:",omitempty"`
+
+	// RateDate is the date of the ECB reference rates used.
+	RateDate string `json:",omitempty"`
+}
+
+// ecbReferenceRates are the euro reference rates of a single day, keyed by currency code.
+type ecbReferenceRates struct {
+	Date  string
+	Rates map[string]float64
+}
+
+// ecbEnvelope is the structure of the ECB reference rates feed.
+type ecbEnvelope struct {
+	Cube struct {
+		Cube struct {
+			Time  string `xml:"time,attr"`
+			Rates []struct {
+				Currency string  `xml:"currency,attr"`
+				Rate     float64 `xml:"rate,attr"`
+			} `xml:"Cube"`
+		} `xml:"Cube"`
+	} `xml:"Cube"`
+}
+
+// resolveCurrency returns the configured display currency along with its exchange rate from USD, using the static
+// exchange rate when one is configured and the ECB reference rates otherwise.
+func (ce *CostEstimator) resolveCurrency(ctx context.Context) (CostCurrency, error) {
+	currency := strings.ToUpper(strings.TrimSpace(ce.config.Currency))
+	if currency == "" || currency == baseCurrency {
+		return CostCurrency{Currency: baseCurrency, ExchangeRate: 1}, nil
+	}
+
+	if ce.config.ExchangeRate > 0 {
+		return CostCurrency{Currency: currency, ExchangeRate: ce.config.ExchangeRate, Source: "static"}, nil
+	}
+
+	rates := ecbReferenceRates{}
+	err := ce.prices.fetch("ecb", ecbReferenceRatesEndpoint, &rates, func() (interface{}, error) {
+		return queryECBReferenceRates(ctx)
+	})
+	if err != nil {
+		return CostCurrency{}, fmt.Errorf("[resolve_currency][ECB reference rates]%w", err)
+	}
+
+	exchangeRate, err := rates.exchangeRate(baseCurrency, currency)
+	if err != nil {
+		return CostCurrency{}, fmt.Errorf("[resolve_currency]%w", err)
+	}
+	return CostCurrency{Currency: currency, ExchangeRate: exchangeRate, Source: "ecb", RateDate: rates.Date}, nil
+}
+
+// exchangeRate returns the number of units of the target currency per unit of the source currency, crossing the
+// euro reference rates.
+func (r ecbReferenceRates) exchangeRate(source string, target string) (float64, error) {
+	rates := map[string]float64{"EUR": 1}
+	for currency, rate := range r.Rates {
+		rates[currency] = rate
+	}
+
+	sourceRate, ok := rates[source]
+	if !ok || sourceRate == 0 {
+		return 0, fmt.Errorf("[exchange_rate][no ECB reference rate for %v]", source)
+	}
+	targetRate, ok := rates[target]
+	if !ok {
+		return 0, fmt.Errorf("[exchange_rate][no ECB reference rate for %v]", target)
+	}
+	return targetRate / sourceRate, nil
+}
+
+// queryECBReferenceRates downloads the latest ECB euro foreign exchange reference rates.
+func queryECBReferenceRates(ctx context.Context) (ecbReferenceRates, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, ecbReferenceRatesEndpoint, nil)
+	if err != nil {
+		return ecbReferenceRates{}, err
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return ecbReferenceRates{}, fmt.Errorf("[query_ecb_reference_rates][http.Do]%w", err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return ecbReferenceRates{}, fmt.Errorf("[query_ecb_reference_rates][io.ReadAll]%w", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return ecbReferenceRates{}, fmt.Errorf("[query_ecb_reference_rates][unexpected status code %d]%s", response.StatusCode, string(body))
+	}
+
+	envelope := ecbEnvelope{}
+	err = xml.Unmarshal(body, &envelope)
+	if err != nil {
+		return ecbReferenceRates{}, fmt.Errorf("[query_ecb_reference_rates][xml.Unmarshal]%w", err)
+	}
+
+	rates := ecbReferenceRates{Date: envelope.Cube.Cube.Time, Rates: map[string]float64{}}
+	for _, rate := range envelope.Cube.Cube.Rates {
+		rates.Rates[rate.Currency] = rate.Rate
+	}
+	return rates, nil
+}
+
+// convert converts a USD amount into the display currency.
+func (c CostCurrency) convert(amount float64) float64 {
+	if c.ExchangeRate == 0 {
+		return amount
+	}
+	return amount * c.ExchangeRate
+}
+
+// convertRows converts the price and monthly cost of cost component rows into the display currency. Empty values,
+// e.g. the monthly cost of usage based components, are left empty.
+func (c CostCurrency) convertRows(rows []map[string]interface{}) {
+	if c.ExchangeRate == 0 || c.ExchangeRate == 1 {
+		return
+	}
+
+	for _, row := range rows {
+		for column, precision := range map[string]float64{"price": 1e6, "monthly_cost": 1e4} {
+			value, err := strconv.ParseFloat(fmt.Sprint(row[column]), 64)
+			if err != nil {
+				continue
+			}
+			row[column] = strconv.FormatFloat(math.Round(c.convert(value)*precision)/precision, 'f', -1, 64)
+		}
+	}
+}
+
+// writeCostCurrency writes the display currency of the cost outputs to mappings/cost-currency.json.
+func (c CostCurrency) writeCostCurrency(store artifacts.Store) error {
+	content, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[write_cost_currency][json.MarshalIndent]%w", err)
+	}
+
+	err = store.WriteFile("mappings/cost-currency.json", content, 0400)
+	if err != nil {
+		return fmt.Errorf("[write_cost_currency][artifacts.WriteFile]%w", err)
+	}
+	return nil
+}
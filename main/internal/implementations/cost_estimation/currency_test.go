@@ -0,0 +1,130 @@
+package costEstimation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+const ecbFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<Cube>
+		<Cube time="2026-10-14">
+			<Cube currency="USD" rate="1.25"/>
+			<Cube currency="GBP" rate="0.85"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`
+
+func TestResolveCurrency(t *testing.T) {
+	// Given
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(ecbFeed))
+	}))
+	defer server.Close()
+
+	originalEndpoint := ecbReferenceRatesEndpoint
+	ecbReferenceRatesEndpoint = server.URL
+	defer func() { ecbReferenceRatesEndpoint = originalEndpoint }()
+
+	testCases := []struct {
+		name   string
+		config CostEstimatorConfig
+		want   CostCurrency
+	}{
+		{name: "default", config: CostEstimatorConfig{}, want: CostCurrency{Currency: "USD", ExchangeRate: 1}},
+		{name: "static", config: CostEstimatorConfig{Currency: "chf", ExchangeRate: 0.9}, want: CostCurrency{Currency: "CHF", ExchangeRate: 0.9, Source: "static"}},
+		{name: "euro", config: CostEstimatorConfig{Currency: "EUR"}, want: CostCurrency{Currency: "EUR", ExchangeRate: 0.8, Source: "ecb", RateDate: "2026-10-14"}},
+		{name: "cross rate", config: CostEstimatorConfig{Currency: "GBP"}, want: CostCurrency{Currency: "GBP", ExchangeRate: 0.68, Source: "ecb", RateDate: "2026-10-14"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// When
+			ce := CostEstimator{config: testCase.config}
+			got, err := ce.resolveCurrency(context.Background())
+
+			// Then
+			require.NoError(t, err)
+			assert.Equal(t, testCase.want.Currency, got.Currency)
+			assert.InDelta(t, testCase.want.ExchangeRate, got.ExchangeRate, 0.000001)
+			assert.Equal(t, testCase.want.Source, got.Source)
+			assert.Equal(t, testCase.want.RateDate, got.RateDate)
+		})
+	}
+	assert.Equal(t, 2, requests)
+
+	// When the currency has no reference rate
+	ce := CostEstimator{config: CostEstimatorConfig{Currency: "XYZ"}}
+	_, err := ce.resolveCurrency(context.Background())
+
+	// Then
+	assert.Error(t, err)
+}
+
+func TestCostCurrency_ConvertRows(t *testing.T) {
+	// Given
+	rows := []map[string]interface{}{
+		{"resource_name": "aws_instance.tfer--web", "price": "0.096", "monthly_quantity": "730", "monthly_cost": "70.08", "is_usage_based": false},
+		{"resource_name": "aws_s3_bucket.tfer--logs", "price": "0.023", "monthly_quantity": "", "monthly_cost": "", "is_usage_based": true},
+	}
+
+	// When
+	CostCurrency{Currency: "EUR", ExchangeRate: 0.8}.convertRows(rows)
+
+	// Then
+	assert.Equal(t, []map[string]interface{}{
+		{"resource_name": "aws_instance.tfer--web", "price": "0.0768", "monthly_quantity": "730", "monthly_cost": "56.064", "is_usage_based": false},
+		{"resource_name": "aws_s3_bucket.tfer--logs", "price": "0.0184", "monthly_quantity": "", "monthly_cost": "", "is_usage_based": true},
+	}, rows)
+}
+
+func TestAggregateCostEstimates_ConvertsCurrency(t *testing.T) {
+	// Given
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+	require.NoError(t, os.MkdirAll("current_cloud/azurerm-my-rg", 0755))
+	require.NoError(t, os.MkdirAll("mappings", 0755))
+
+	rows := `[{"resource_name": "azurerm_managed_disk.tfer--data", "cost_component": "Managed disk (P10 LRS Disk)", "unit": "1/Month", "price": "19.71", "monthly_quantity": "1", "monthly_cost": "19.7100", "is_usage_based": false, "sub_resource_name": ""}]`
+	require.NoError(t, os.WriteFile("current_cloud/azurerm-my-rg/infracost-formatted.json", []byte(rows), 0400))
+
+	ce := CostEstimator{
+		artifactStore: artifacts.NewMemoryStore(true),
+		config: CostEstimatorConfig{
+			DivisionCloudCredentials: terraformValueObjects.DivisionCloudCredentialDecoder{"my-rg": "{}"},
+			InfracostAPIToken:        "None",
+		},
+		divisionToProvider: map[terraformValueObjects.Division]terraformValueObjects.Provider{"my-rg": "azurerm"},
+		currency:           CostCurrency{Currency: "GBP", ExchangeRate: 0.5, Source: "static"},
+	}
+
+	// When
+	err = ce.AggregateCostEstimates()
+
+	// Then
+	require.NoError(t, err)
+
+	content, err := os.ReadFile("mappings/division-to-cost-estimates.json")
+	require.NoError(t, err)
+	divisionToRows := map[string][]map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(content, &divisionToRows))
+
+	assert.Equal(t, "9.855", divisionToRows["azurerm-my-rg"][0]["price"])
+	assert.Equal(t, "9.855", divisionToRows["azurerm-my-rg"][0]["monthly_cost"])
+	assert.Equal(t, "1", divisionToRows["azurerm-my-rg"][0]["monthly_quantity"])
+}
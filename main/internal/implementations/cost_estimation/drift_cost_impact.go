@@ -0,0 +1,441 @@
+package costEstimation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	log "github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+// DriftCostImpact is the monthly cost impact of a managed resource instance having drifted from its Terraform state,
+// expressed in the display currency.
+type DriftCostImpact struct {
+	StateFileName        string
+	CloudDivision        string
+	ResourceAddress      string
+	InstanceID           string
+	TerraformMonthlyCost float64
+	CloudMonthlyCost     float64
+	MonthlyCostDelta     float64
+}
+
+// driftedInstance is a drifted managed resource instance along with its drifted attributes.
+type driftedInstance struct {
+	impact      DriftCostImpact
+	differences []driftDetector.AttributeDifference
+}
+
+// EstimateDriftCostImpact prices each drifted managed resource as declared within its Terraform state, by reverting
+// its drifted attributes within the scanned cloud state, and writes the monthly cost delta of every resource instance
+// whose drift changes its cost to mappings/drift-cost-impact.json. Divisions whose drift cannot be priced are logged
+// and left out, as the cost impact of drift is informational.
+func (ce *CostEstimator) EstimateDriftCostImpact(ctx context.Context) error {
+	differences, err := loadDriftDifferences(ce.artifactStore)
+	if err != nil {
+		return fmt.Errorf("[estimate_drift_cost_impact]%w", err)
+	}
+
+	folderToDivision := map[string]terraformValueObjects.Division{}
+	for _, division := range ce.pricedDivisions() {
+		folderToDivision[fmt.Sprintf("%v-%v", ce.divisionToProvider[division], division)] = division
+	}
+
+	folderToInstances := map[string]map[string]*driftedInstance{}
+	for _, difference := range differences {
+		if _, ok := folderToDivision[difference.CloudDivision]; !ok || difference.CloudResourceName == "" {
+			continue
+		}
+
+		instances, ok := folderToInstances[difference.CloudDivision]
+		if !ok {
+			instances = map[string]*driftedInstance{}
+			folderToInstances[difference.CloudDivision] = instances
+		}
+
+		cloudAddress := fmt.Sprintf("%v.%v", difference.ResourceType, difference.CloudResourceName)
+		instance, ok := instances[cloudAddress]
+		if !ok {
+			instance = &driftedInstance{
+				impact: DriftCostImpact{
+					StateFileName:   string(difference.StateFileName),
+					CloudDivision:   difference.CloudDivision,
+					ResourceAddress: difference.InstanceAddress(),
+					InstanceID:      difference.InstanceID,
+				},
+			}
+			instances[cloudAddress] = instance
+		}
+		instance.differences = append(instance.differences, difference)
+	}
+
+	impacts := make([]DriftCostImpact, 0)
+	for folder, instances := range folderToInstances {
+		divisionImpacts, err := ce.divisionDriftCostImpact(ctx, folderToDivision[folder], instances)
+		if err != nil {
+			log.Warnf("[estimate_drift_cost_impact] the cost impact of drift within %v is unknown: %v", folder, err)
+			continue
+		}
+		impacts = append(impacts, divisionImpacts...)
+	}
+
+	sort.Slice(impacts, func(i, j int) bool {
+		if impacts[i].StateFileName != impacts[j].StateFileName {
+			return impacts[i].StateFileName < impacts[j].StateFileName
+		}
+		return impacts[i].ResourceAddress < impacts[j].ResourceAddress
+	})
+
+	impactsJSON, err := json.MarshalIndent(impacts, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[estimate_drift_cost_impact][json.MarshalIndent]%w", err)
+	}
+
+	err = ce.artifactStore.WriteFile("mappings/drift-cost-impact.json", impactsJSON, 0400)
+	if err != nil {
+		return fmt.Errorf("[estimate_drift_cost_impact][artifacts.WriteFile]%w", err)
+	}
+	return nil
+}
+
+// divisionDriftCostImpact returns the cost impact of the drifted instances within a division, omitting instances
+// whose drift does not change their monthly cost.
+func (ce *CostEstimator) divisionDriftCostImpact(ctx context.Context, division terraformValueObjects.Division, instances map[string]*driftedInstance) ([]DriftCostImpact, error) {
+	divisionFolderName := fmt.Sprintf("%v-%v", ce.divisionToProvider[division], division)
+
+	cloudRows, err := loadCostComponentRows(fmt.Sprintf("current_cloud/%v/infracost-formatted.json", divisionFolderName))
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := ce.loadTerraformerState(division)
+	if err != nil {
+		return nil, err
+	}
+
+	terraformState := driftDetector.TerraformerStateFile{Resources: []*driftDetector.TerraformerResource{}}
+	for _, resource := range state.Resources {
+		instance, ok := instances[fmt.Sprintf("%v.%v", resource.Type, resource.Name)]
+		if !ok || len(resource.Instances) == 0 {
+			continue
+		}
+
+		terraformResource := *resource
+		terraformResource.Instances = []driftDetector.TerraformerInstance{{
+			SchemaVersion:  resource.Instances[0].SchemaVersion,
+			AttributesFlat: revertDriftedAttributes(resource.Instances[0].AttributesFlat, instance.differences),
+		}}
+		terraformState.Resources = append(terraformState.Resources, &terraformResource)
+	}
+
+	terraformRows, err := ce.priceDriftedState(ctx, division, terraformState, instances)
+	if err != nil {
+		return nil, err
+	}
+
+	impacts := make([]DriftCostImpact, 0)
+	for cloudAddress, instance := range instances {
+		impact := instance.impact
+		impact.CloudMonthlyCost = roundCost(ce.currency.convert(monthlyCost(cloudRows, cloudAddress)))
+		impact.TerraformMonthlyCost = roundCost(ce.currency.convert(monthlyCost(terraformRows, cloudAddress)))
+		impact.MonthlyCostDelta = roundCost(impact.CloudMonthlyCost - impact.TerraformMonthlyCost)
+		if impact.MonthlyCostDelta == 0 {
+			continue
+		}
+		impacts = append(impacts, impact)
+	}
+	return impacts, nil
+}
+
+// priceDriftedState returns the cost component rows of a division's drifted instances as declared within Terraform,
+// priced in the same way as the division's scanned resources.
+func (ce *CostEstimator) priceDriftedState(ctx context.Context, division terraformValueObjects.Division, state driftDetector.TerraformerStateFile, instances map[string]*driftedInstance) ([]map[string]interface{}, error) {
+	if len(state.Resources) == 0 {
+		return []map[string]interface{}{}, nil
+	}
+
+	if ce.isAzureDivision(division) {
+		return ce.priceAzureResources(ctx, state)
+	}
+
+	rows := make([]map[string]interface{}, 0)
+	if ce.usesInfracost(division) {
+		infracostRows, err := ce.infracostDriftedState(ctx, division, instances)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, infracostRows...)
+	}
+
+	if ce.isGCPDivision(division) {
+		estimatedResources := map[string]bool{}
+		for _, row := range rows {
+			estimatedResources[fmt.Sprint(row["resource_name"])] = true
+		}
+
+		catalogRows, err := ce.priceGCPResources(ctx, division, state, estimatedResources)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, catalogRows...)
+	}
+	return rows, nil
+}
+
+// infracostDriftedState writes the drifted resources of a division as HCL alongside the division's other scanned
+// configuration files and prices them with the infracost CLI, which parses Terraform code rather than state files.
+func (ce *CostEstimator) infracostDriftedState(ctx context.Context, division terraformValueObjects.Division, instances map[string]*driftedInstance) ([]map[string]interface{}, error) {
+	divisionFolderName := fmt.Sprintf("%v-%v", ce.divisionToProvider[division], division)
+	divisionPath := fmt.Sprintf("./current_cloud/%v/", divisionFolderName)
+	driftPath := divisionPath + "drift/"
+
+	err := os.MkdirAll(driftPath, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("[infracost_drifted_state][os.MkdirAll]%w", err)
+	}
+
+	err = writeDriftedHCL(divisionPath, driftPath, instances)
+	if err != nil {
+		return nil, fmt.Errorf("[infracost_drifted_state]%w", err)
+	}
+
+	usageFilePath := ""
+	if ce.usageFile != nil {
+		usageFilePath = divisionPath + "infracost-usage.yml"
+	}
+
+	err = ce.infracostEstimate(ctx, driftPath, driftPath+"infracost.json", usageFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("[infracost_drifted_state]%w", err)
+	}
+
+	content, err := os.ReadFile(driftPath + "infracost.json")
+	if err != nil {
+		return nil, fmt.Errorf("[infracost_drifted_state][os.ReadFile]%w", err)
+	}
+
+	resourceDataList, err := ce.ParseJSONToStruct(content)
+	if err != nil {
+		return nil, fmt.Errorf("[infracost_drifted_state][ce.ParseJSONToStruct]%w", err)
+	}
+
+	rowsJSON, err := ce.StructToJSONString(resourceDataList)
+	if err != nil {
+		return nil, fmt.Errorf("[infracost_drifted_state][ce.StructToJSONString]%w", err)
+	}
+
+	rows := make([]map[string]interface{}, 0)
+	err = json.Unmarshal([]byte(rowsJSON), &rows)
+	if err != nil {
+		return nil, fmt.Errorf("[infracost_drifted_state][json.Unmarshal]%w", err)
+	}
+	return rows, nil
+}
+
+// writeDriftedHCL writes to driftPath the scanned configuration files of divisionPath, keeping within resources.tf
+// only the drifted resources, with their drifted attributes set back to their Terraform values.
+func writeDriftedHCL(divisionPath string, driftPath string, instances map[string]*driftedInstance) error {
+	paths, err := filepath.Glob(divisionPath + "*.tf")
+	if err != nil {
+		return fmt.Errorf("[write_drifted_hcl][filepath.Glob]%w", err)
+	}
+
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("[write_drifted_hcl][os.ReadFile]%w", err)
+		}
+
+		if filepath.Base(path) == "resources.tf" {
+			content, err = driftedResourcesHCL(content, instances)
+			if err != nil {
+				return fmt.Errorf("[write_drifted_hcl]%w", err)
+			}
+		}
+
+		err = os.WriteFile(filepath.Join(driftPath, filepath.Base(path)), content, 0600)
+		if err != nil {
+			return fmt.Errorf("[write_drifted_hcl][os.WriteFile]%w", err)
+		}
+	}
+	return nil
+}
+
+// driftedResourcesHCL returns the resource blocks of the drifted instances within the scanned resources.tf content,
+// with their drifted attributes set back to their Terraform values. Attributes whose values are maps, such as tags,
+// are left unchanged, as they do not change the cost of a resource.
+func driftedResourcesHCL(content []byte, instances map[string]*driftedInstance) ([]byte, error) {
+	f, diags := hclwrite.ParseConfig(content, "resources.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("[drifted_resources_hcl][hclwrite.ParseConfig]%v", diags.Error())
+	}
+
+	for _, block := range f.Body().Blocks() {
+		labels := block.Labels()
+		if block.Type() != "resource" || len(labels) != 2 {
+			continue
+		}
+
+		instance, ok := instances[fmt.Sprintf("%v.%v", labels[0], labels[1])]
+		if !ok {
+			f.Body().RemoveBlock(block)
+			continue
+		}
+
+		for _, difference := range instance.differences {
+			if difference.Sensitive {
+				continue
+			}
+			revertBlockAttribute(block.Body(), strings.Split(difference.AttributeName, "."), difference)
+		}
+	}
+	return hclwrite.Format(f.Bytes()), nil
+}
+
+// revertBlockAttribute sets back the attribute at the flat attribute path, e.g. root_block_device.0.volume_size,
+// within the body of a resource block to its Terraform value.
+func revertBlockAttribute(body *hclwrite.Body, path []string, difference driftDetector.AttributeDifference) {
+	for len(path) > 2 {
+		index, err := strconv.Atoi(path[1])
+		if err != nil {
+			return
+		}
+
+		blocks := make([]*hclwrite.Block, 0)
+		for _, block := range body.Blocks() {
+			if block.Type() == path[0] {
+				blocks = append(blocks, block)
+			}
+		}
+		if index >= len(blocks) {
+			return
+		}
+		body, path = blocks[index].Body(), path[2:]
+	}
+	if len(path) != 1 {
+		return
+	}
+
+	if difference.ChangeType == driftDetector.AttributeChangeAdded {
+		body.RemoveAttribute(path[0])
+		return
+	}
+	body.SetAttributeValue(path[0], hclValue(difference.TerraformValue, difference.ValueType))
+}
+
+// jsonNumberPattern matches numbers as written within JSON, excluding values such as NaN, Inf or hexadecimal numbers.
+var jsonNumberPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// hclValue converts the string representation of an attribute value into a cty value, written as a number or
+// boolean when the value is of that type within the Terraform state or, when its type is unknown, looks like one.
+func hclValue(value string, valueType string) cty.Value {
+	if valueType == "" || valueType == "number" {
+		if jsonNumberPattern.MatchString(value) {
+			if number, err := cty.ParseNumberVal(value); err == nil {
+				return number
+			}
+		}
+	}
+	if (valueType == "" || valueType == "bool") && (value == "true" || value == "false") {
+		return cty.BoolVal(value == "true")
+	}
+	return cty.StringVal(value)
+}
+
+// revertDriftedAttributes returns a copy of the cloud attributes of a resource with its drifted attributes set back
+// to their Terraform values. Sensitive attributes are left unchanged, as their Terraform values are masked.
+func revertDriftedAttributes(cloudAttributes map[string]string, differences []driftDetector.AttributeDifference) map[string]string {
+	attributes := make(map[string]string, len(cloudAttributes))
+	for name, value := range cloudAttributes {
+		attributes[name] = value
+	}
+
+	for _, difference := range differences {
+		if difference.Sensitive {
+			continue
+		}
+
+		if difference.ChangeType == driftDetector.AttributeChangeAdded {
+			delete(attributes, difference.AttributeName)
+			continue
+		}
+		attributes[difference.AttributeName] = difference.TerraformValue
+	}
+	return attributes
+}
+
+// roundCost rounds a monthly cost to the precision of the formatted cost component rows.
+func roundCost(cost float64) float64 {
+	return math.Round(cost*10000) / 10000
+}
+
+// monthlyCost returns the total monthly cost of a resource's cost component rows. Usage based components without a
+// monthly cost are not counted.
+func monthlyCost(rows []map[string]interface{}, resourceName string) float64 {
+	total := 0.0
+	for _, row := range rows {
+		if fmt.Sprint(row["resource_name"]) != resourceName {
+			continue
+		}
+
+		cost, err := strconv.ParseFloat(fmt.Sprint(row["monthly_cost"]), 64)
+		if err == nil {
+			total += cost
+		}
+	}
+	return total
+}
+
+// loadCostComponentRows loads the cost component rows at path, returning no rows when the file does not exist.
+func loadCostComponentRows(path string) ([]map[string]interface{}, error) {
+	rows := make([]map[string]interface{}, 0)
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return rows, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[load_cost_component_rows][os.ReadFile]%w", err)
+	}
+
+	err = json.Unmarshal(content, &rows)
+	if err != nil {
+		return nil, fmt.Errorf("[load_cost_component_rows][json.Unmarshal]%w", err)
+	}
+	return rows, nil
+}
+
+// loadDriftDifferences loads the drifted attributes of managed resources, returning none when drift detection did
+// not run.
+func loadDriftDifferences(store artifacts.Store) ([]driftDetector.AttributeDifference, error) {
+	differences := make([]driftDetector.AttributeDifference, 0)
+
+	content, err := store.ReadFile("mappings/drift-resources-differences.json")
+	if errors.Is(err, os.ErrNotExist) {
+		return differences, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[load_drift_differences][artifacts.ReadFile]%w", err)
+	}
+
+	err = json.Unmarshal(content, &differences)
+	if err != nil {
+		return nil, fmt.Errorf("[load_drift_differences][json.Unmarshal]%w", err)
+	}
+	return differences, nil
+}
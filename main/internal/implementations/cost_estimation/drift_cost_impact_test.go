@@ -0,0 +1,235 @@
+package costEstimation
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+func TestRevertDriftedAttributes(t *testing.T) {
+	// Given
+	cloudAttributes := map[string]string{"machine_type": "e2-standard-4", "labels.team": "data", "metadata.key": "****"}
+	differences := []driftDetector.AttributeDifference{
+		{AttributeName: "machine_type", TerraformValue: "e2-standard-2", CloudValue: "e2-standard-4", ChangeType: driftDetector.AttributeChangeUpdated},
+		{AttributeName: "labels.team", CloudValue: "data", ChangeType: driftDetector.AttributeChangeAdded},
+		{AttributeName: "labels.env", TerraformValue: "prod", ChangeType: driftDetector.AttributeChangeRemoved},
+		{AttributeName: "metadata.key", TerraformValue: driftDetector.SensitiveValueMask, CloudValue: driftDetector.SensitiveValueMask, Sensitive: true},
+	}
+
+	// When
+	got := revertDriftedAttributes(cloudAttributes, differences)
+
+	// Then
+	assert.Equal(t, map[string]string{"machine_type": "e2-standard-2", "labels.env": "prod", "metadata.key": "****"}, got)
+	assert.Equal(t, "e2-standard-4", cloudAttributes["machine_type"])
+}
+
+func TestEstimateDriftCostImpact(t *testing.T) {
+	// Given
+	catalog := &fakeGCPBillingCatalog{serviceToSkus: map[string][]gcpSku{
+		gcpComputeEngineServiceID: {
+			{Description: "E2 Instance Core running in Americas", UsageType: "OnDemand", Regions: []string{"us-central1"}, UsageUnit: "h", Rates: []gcpTierRate{{UnitPrice: 0.02}}},
+			{Description: "E2 Instance Ram running in Americas", UsageType: "OnDemand", Regions: []string{"us-central1"}, UsageUnit: "GiBy.h", Rates: []gcpTierRate{{UnitPrice: 0.003}}},
+			{Description: "SSD backed PD Capacity", UsageType: "OnDemand", Regions: []string{"us-central1"}, UsageUnit: "GiBy.mo", Rates: []gcpTierRate{{UnitPrice: 0.17}}},
+		},
+	}}
+	originalCatalog := newGCPBillingCatalog
+	newGCPBillingCatalog = func(ctx context.Context, credential terraformValueObjects.Credential) (gcpBillingCatalog, error) {
+		return catalog, nil
+	}
+	defer func() { newGCPBillingCatalog = originalCatalog }()
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+	require.NoError(t, os.MkdirAll("current_cloud/google-my-project", 0755))
+	require.NoError(t, os.MkdirAll("mappings", 0755))
+
+	state := `{"resources": [
+		{"type": "google_compute_instance", "name": "tfer--web", "instances": [{"attributes_flat": {"machine_type": "e2-standard-4", "zone": "us-central1-a"}}]},
+		{"type": "google_compute_disk", "name": "tfer--data", "instances": [{"attributes_flat": {"type": "pd-ssd", "size": "100", "zone": "us-central1-b", "labels.team": "data"}}]}
+	]}`
+	require.NoError(t, os.WriteFile("current_cloud/google-my-project/terraform.tfstate", []byte(state), 0400))
+
+	ce := CostEstimator{
+		artifactStore: artifacts.NewMemoryStore(true),
+		config: CostEstimatorConfig{
+			DivisionCloudCredentials: terraformValueObjects.DivisionCloudCredentialDecoder{"my-project": "{}"},
+			InfracostAPIToken:        "None",
+		},
+		divisionToProvider: map[terraformValueObjects.Division]terraformValueObjects.Provider{"my-project": "google"},
+	}
+	require.NoError(t, ce.GetGCPCatalogCostEstimate(context.Background(), "my-project"))
+
+	webDetail := driftDetector.AttributeDetail{StateFileName: "compute", CloudDivision: "google-my-project", ModuleName: "root", ResourceType: "google_compute_instance", ResourceName: "web", CloudResourceName: "tfer--web"}
+	diskDetail := driftDetector.AttributeDetail{StateFileName: "compute", CloudDivision: "google-my-project", ModuleName: "root", ResourceType: "google_compute_disk", ResourceName: "data", CloudResourceName: "tfer--data"}
+	differences := []driftDetector.AttributeDifference{
+		{AttributeName: "machine_type", TerraformValue: "e2-standard-2", CloudValue: "e2-standard-4", InstanceID: "web", ChangeType: driftDetector.AttributeChangeUpdated, AttributeDetail: webDetail},
+		{AttributeName: "labels.team", CloudValue: "data", InstanceID: "data", ChangeType: driftDetector.AttributeChangeAdded, AttributeDetail: diskDetail},
+	}
+	differencesJSON, err := json.Marshal(differences)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile("mappings/drift-resources-differences.json", differencesJSON, 0400))
+
+	// When
+	err = ce.EstimateDriftCostImpact(context.Background())
+
+	// Then
+	require.NoError(t, err)
+
+	content, err := os.ReadFile("mappings/drift-cost-impact.json")
+	require.NoError(t, err)
+	impacts := make([]DriftCostImpact, 0)
+	require.NoError(t, json.Unmarshal(content, &impacts))
+
+	assert.Equal(t, []DriftCostImpact{
+		{
+			StateFileName:        "compute",
+			CloudDivision:        "google-my-project",
+			ResourceAddress:      "google_compute_instance.web",
+			InstanceID:           "web",
+			TerraformMonthlyCost: 46.72,
+			CloudMonthlyCost:     93.44,
+			MonthlyCostDelta:     46.72,
+		},
+	}, impacts)
+}
+
+// writeFakeInfracost puts on the PATH an infracost CLI pricing aws_instance.tfer--web from the instance type declared
+// within the resources.tf file of the priced path, or failing when failing is true.
+func writeFakeInfracost(t *testing.T, failing bool) {
+	binDirectory := t.TempDir()
+	script := `#!/bin/sh
+while [ $# -gt 0 ]; do
+  case "$1" in
+    --path) path="$2"; shift ;;
+    --out-file) out="$2"; shift ;;
+  esac
+  shift
+done
+cost=60.74
+if grep -q '"t3.small"' "$path/resources.tf"; then cost=15.18; fi
+printf '{"projects":[{"breakdown":{"resources":[{"name":"aws_instance.tfer--web","monthlyCost":"%s","costComponents":[{"name":"Instance usage","unit":"hours","price":"0","monthlyQuantity":"730","monthlyCost":"%s"}]}]}}]}' "$cost" "$cost" > "$out"
+`
+	if failing {
+		script = "#!/bin/sh\necho 'parsing failed' >&2\nexit 1\n"
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(binDirectory, "infracost"), []byte(script), 0700))
+	t.Setenv("PATH", binDirectory+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// writeAWSDriftFixtures writes the scanned state, code and cost of a division with a drifted aws_instance.
+func writeAWSDriftFixtures(t *testing.T) {
+	require.NoError(t, os.MkdirAll("current_cloud/aws-prod", 0755))
+	require.NoError(t, os.MkdirAll("mappings", 0755))
+
+	state := `{"resources": [{"type": "aws_instance", "name": "tfer--web", "instances": [{"attributes_flat": {"instance_type": "t3.large"}}]}]}`
+	require.NoError(t, os.WriteFile("current_cloud/aws-prod/terraform.tfstate", []byte(state), 0400))
+	require.NoError(t, os.WriteFile("current_cloud/aws-prod/provider.tf", []byte("provider \"aws\" {\n  region = \"us-east-1\"\n}\n"), 0400))
+	require.NoError(t, os.WriteFile("current_cloud/aws-prod/resources.tf", []byte(`resource "aws_instance" "tfer--web" {
+  instance_type = "t3.large"
+  tags = {
+    team = "data"
+  }
+}
+
+resource "aws_s3_bucket" "tfer--logs" {
+  bucket = "logs"
+}
+`), 0400))
+	require.NoError(t, os.WriteFile("current_cloud/aws-prod/infracost-formatted.json", []byte(`[{"resource_name": "aws_instance.tfer--web", "monthly_cost": "60.74"}]`), 0400))
+
+	detail := driftDetector.AttributeDetail{StateFileName: "compute", CloudDivision: "aws-prod", ModuleName: "root", ResourceType: "aws_instance", ResourceName: "web", CloudResourceName: "tfer--web"}
+	differences := []driftDetector.AttributeDifference{
+		{AttributeName: "instance_type", TerraformValue: "t3.small", CloudValue: "t3.large", InstanceID: "i-1", ChangeType: driftDetector.AttributeChangeUpdated, ValueType: "string", AttributeDetail: detail},
+	}
+	differencesJSON, err := json.Marshal(differences)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile("mappings/drift-resources-differences.json", differencesJSON, 0400))
+}
+
+func TestEstimateDriftCostImpact_AWS(t *testing.T) {
+	// Given
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+	writeAWSDriftFixtures(t)
+	writeFakeInfracost(t, false)
+
+	ce := CostEstimator{
+		artifactStore: artifacts.NewMemoryStore(true),
+		config: CostEstimatorConfig{
+			DivisionCloudCredentials: terraformValueObjects.DivisionCloudCredentialDecoder{"prod": "{}"},
+			InfracostAPIToken:        "token",
+		},
+		divisionToProvider: map[terraformValueObjects.Division]terraformValueObjects.Provider{"prod": "aws"},
+	}
+
+	// When
+	err = ce.EstimateDriftCostImpact(context.Background())
+
+	// Then
+	require.NoError(t, err)
+
+	driftedHCL, err := os.ReadFile("current_cloud/aws-prod/drift/resources.tf")
+	require.NoError(t, err)
+	assert.Contains(t, string(driftedHCL), `instance_type = "t3.small"`)
+	assert.NotContains(t, string(driftedHCL), "aws_s3_bucket")
+	_, err = os.Stat("current_cloud/aws-prod/drift/provider.tf")
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile("mappings/drift-cost-impact.json")
+	require.NoError(t, err)
+	impacts := make([]DriftCostImpact, 0)
+	require.NoError(t, json.Unmarshal(content, &impacts))
+	assert.Equal(t, []DriftCostImpact{
+		{
+			StateFileName:        "compute",
+			CloudDivision:        "aws-prod",
+			ResourceAddress:      "aws_instance.web",
+			InstanceID:           "i-1",
+			TerraformMonthlyCost: 15.18,
+			CloudMonthlyCost:     60.74,
+			MonthlyCostDelta:     45.56,
+		},
+	}, impacts)
+}
+
+func TestEstimateDriftCostImpact_InfracostFailureIsAWarning(t *testing.T) {
+	// Given
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+	writeAWSDriftFixtures(t)
+	writeFakeInfracost(t, true)
+
+	ce := CostEstimator{
+		artifactStore: artifacts.NewMemoryStore(true),
+		config: CostEstimatorConfig{
+			DivisionCloudCredentials: terraformValueObjects.DivisionCloudCredentialDecoder{"prod": "{}"},
+			InfracostAPIToken:        "token",
+		},
+		divisionToProvider: map[terraformValueObjects.Division]terraformValueObjects.Provider{"prod": "aws"},
+	}
+
+	// When
+	err = ce.EstimateDriftCostImpact(context.Background())
+
+	// Then
+	require.NoError(t, err)
+	content, err := os.ReadFile("mappings/drift-cost-impact.json")
+	require.NoError(t, err)
+	assert.JSONEq(t, "[]", string(content))
+}
@@ -1,6 +1,7 @@
 package costEstimation
 
 import (
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
 )
@@ -10,17 +11,17 @@ type Factory struct {
 }
 
 // Instantiate creates an implementation of interfaces.CostEstimation.
-func (f *Factory) Instantiate(environment string, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider, config CostEstimatorConfig) (interfaces.CostEstimation, error) {
+func (f *Factory) Instantiate(environment string, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider, config CostEstimatorConfig, artifactStore artifacts.Store) (interfaces.CostEstimation, error) {
 	switch environment {
 	case "isolated":
 		return new(IsolatedCostEstimator), nil
 	default:
-		return f.bootstrappedCostEstimator(divisionToProvider, config)
+		return f.bootstrappedCostEstimator(divisionToProvider, config, artifactStore)
 	}
 }
 
 // bootstrappedCostEstimator instantiates an instance of CostEstimator with the proper environment
 // variables read in.
-func (f *Factory) bootstrappedCostEstimator(divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider, config CostEstimatorConfig) (interfaces.CostEstimation, error) {
-	return NewCostEstimator(config, divisionToProvider), nil
+func (f *Factory) bootstrappedCostEstimator(divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider, config CostEstimatorConfig, artifactStore artifacts.Store) (interfaces.CostEstimation, error) {
+	return NewCostEstimator(config, divisionToProvider, artifactStore), nil
 }
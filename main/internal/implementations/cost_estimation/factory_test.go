@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
 )
 
@@ -15,7 +16,7 @@ func TestCreateNotSupported(t *testing.T) {
 	costEstimatorFactory := new(Factory)
 
 	// When
-	costEstimator, err := costEstimatorFactory.Instantiate("", divisionToProvider, config)
+	costEstimator, err := costEstimatorFactory.Instantiate("", divisionToProvider, config, artifacts.NewMemoryStore(true))
 
 	// Then
 	assert.Nil(t, err)
@@ -30,7 +31,7 @@ func TestCreateIsolatedDragonDrop(t *testing.T) {
 	divisionToProvider := make(map[terraformValueObjects.Division]terraformValueObjects.Provider)
 
 	// When
-	costEstimator, err := costEstimatorFactory.Instantiate(costEstimatorProtocol, divisionToProvider, config)
+	costEstimator, err := costEstimatorFactory.Instantiate(costEstimatorProtocol, divisionToProvider, config, artifacts.NewMemoryStore(true))
 
 	// Then
 	assert.Nil(t, err)
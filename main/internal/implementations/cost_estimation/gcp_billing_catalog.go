@@ -0,0 +1,575 @@
+package costEstimation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/api/cloudbilling/v1"
+	"google.golang.org/api/option"
+
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+// Cloud Billing Catalog service ids of the priced GCP services.
+const (
+	gcpComputeEngineServiceID    = "6F81-5844-456A"
+	gcpKubernetesEngineServiceID = "CCD8-9BF1-090E"
+	gcpCloudSQLServiceID         = "9662-B51E-5089"
+	gcpCloudStorageServiceID     = "95FF-2EF5-5EA1"
+)
+
+// gcpSku is a Cloud Billing Catalog sku with the fields used for pricing.
+type gcpSku struct {
+	Description   string
+	ResourceGroup string
+	UsageType     string
+	Regions       []string
+	UsageUnit     string
+	Rates         []gcpTierRate
+}
+
+// gcpTierRate is the unit price of a sku from a starting monthly usage.
+type gcpTierRate struct {
+	StartUsageAmount float64
+	UnitPrice        float64
+}
+
+// gcpBillingCatalog lists the skus of GCP services.
+type gcpBillingCatalog interface {
+	// listSkus returns the public skus of the service.
+	listSkus(ctx context.Context, serviceID string) ([]gcpSku, error)
+}
+
+// newGCPBillingCatalog returns the gcpBillingCatalog authenticated with the passed division credential.
+var newGCPBillingCatalog = func(ctx context.Context, credential terraformValueObjects.Credential) (gcpBillingCatalog, error) {
+	options := make([]option.ClientOption, 0)
+	if !credential.IsAmbient() {
+		options = append(options, option.WithCredentialsJSON([]byte(credential)))
+	}
+
+	service, err := cloudbilling.NewService(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+	return &cloudBillingCatalog{service: service}, nil
+}
+
+// cloudBillingCatalog implements gcpBillingCatalog with the Cloud Billing v1 API.
+type cloudBillingCatalog struct {
+	service *cloudbilling.APIService
+}
+
+// listSkus returns the public skus of the service, using the latest pricing of each.
+func (c *cloudBillingCatalog) listSkus(ctx context.Context, serviceID string) ([]gcpSku, error) {
+	skus := make([]gcpSku, 0)
+	err := c.service.Services.Skus.List("services/"+serviceID).Pages(ctx, func(response *cloudbilling.ListSkusResponse) error {
+		for _, sku := range response.Skus {
+			if sku.Category == nil || len(sku.PricingInfo) == 0 || sku.PricingInfo[0].PricingExpression == nil {
+				continue
+			}
+
+			expression := sku.PricingInfo[0].PricingExpression
+			rates := make([]gcpTierRate, 0, len(expression.TieredRates))
+			for _, rate := range expression.TieredRates {
+				if rate.UnitPrice == nil {
+					continue
+				}
+				rates = append(rates, gcpTierRate{
+					StartUsageAmount: rate.StartUsageAmount,
+					UnitPrice:        float64(rate.UnitPrice.Units) + float64(rate.UnitPrice.Nanos)/1e9,
+				})
+			}
+
+			skus = append(skus, gcpSku{
+				Description:   sku.Description,
+				ResourceGroup: sku.Category.ResourceGroup,
+				UsageType:     sku.Category.UsageType,
+				Regions:       sku.ServiceRegions,
+				UsageUnit:     expression.UsageUnit,
+				Rates:         rates,
+			})
+		}
+		return nil
+	})
+	return skus, err
+}
+
+// gcpCostComponent is a billed component of a GCP resource, priced by the first sku of its service that it matches.
+type gcpCostComponent struct {
+	name      string
+	serviceID string
+
+	// region is the region in which the sku must be offered, or empty for global skus.
+	region  string
+	matches func(sku gcpSku) bool
+
+	// monthlyQuantity is the number of units billed per month, unless the component is usage based.
+	monthlyQuantity float64
+	isUsageBased    bool
+
+	// usageKey is the usage file key estimating the monthly quantity of a usage based component.
+	usageKey string
+}
+
+// gcpPricingRules are keyed by the google resource types found by the scan, and return the cost components of a
+// resource from its flat attributes. Resource types without a rule are not priced.
+var gcpPricingRules = map[string]func(attributesFlat map[string]string) []gcpCostComponent{
+	"google_compute_instance": func(attributesFlat map[string]string) []gcpCostComponent {
+		return gcpMachineCostComponents(attributesFlat["machine_type"], gcpRegion(attributesFlat["zone"]), 1)
+	},
+	"google_container_node_pool": func(attributesFlat map[string]string) []gcpCostComponent {
+		nodeCount := attributeFloat(attributesFlat, "node_count", attributeFloat(attributesFlat, "initial_node_count", 1))
+		zoneCount := attributeFloat(attributesFlat, "node_locations.#", 1)
+		if zoneCount < 1 {
+			zoneCount = 1
+		}
+		return gcpMachineCostComponents(attributesFlat["node_config.0.machine_type"], gcpRegion(attributesFlat["location"]), nodeCount*zoneCount)
+	},
+	"google_container_cluster": func(attributesFlat map[string]string) []gcpCostComponent {
+		description := "Zonal Kubernetes Clusters"
+		if attributesFlat["enable_autopilot"] == "true" {
+			description = "Autopilot Kubernetes Clusters"
+		} else if gcpRegion(attributesFlat["location"]) == attributesFlat["location"] {
+			description = "Regional Kubernetes Clusters"
+		}
+		return []gcpCostComponent{{
+			name:            "Cluster management fee",
+			serviceID:       gcpKubernetesEngineServiceID,
+			matches:         func(sku gcpSku) bool { return sku.Description == description },
+			monthlyQuantity: hoursPerMonth,
+		}}
+	},
+	"google_compute_disk": func(attributesFlat map[string]string) []gcpCostComponent {
+		descriptions := map[string]string{
+			"pd-standard": "Storage PD Capacity",
+			"pd-balanced": "Balanced PD Capacity",
+			"pd-ssd":      "SSD backed PD Capacity",
+		}
+		description, ok := descriptions[attributesFlat["type"]]
+		if !ok {
+			return nil
+		}
+		return []gcpCostComponent{{
+			name:            fmt.Sprintf("Storage (%v)", attributesFlat["type"]),
+			serviceID:       gcpComputeEngineServiceID,
+			region:          gcpRegion(attributesFlat["zone"]),
+			matches:         func(sku gcpSku) bool { return sku.Description == description },
+			monthlyQuantity: attributeFloat(attributesFlat, "size", 0),
+		}}
+	},
+	"google_sql_database_instance": gcpCloudSQLCostComponents,
+	"google_storage_bucket": func(attributesFlat map[string]string) []gcpCostComponent {
+		location := strings.ToLower(attributesFlat["location"])
+		storageClass := attributesFlat["storage_class"]
+		resourceGroups := map[string]string{
+			"NEARLINE": "NearlineStorage",
+			"COLDLINE": "ColdlineStorage",
+			"ARCHIVE":  "ArchiveStorage",
+		}
+		resourceGroup, ok := resourceGroups[storageClass]
+		if !ok {
+			storageClass = "STANDARD"
+			resourceGroup = "RegionalStorage"
+			if !strings.Contains(location, "-") {
+				resourceGroup = "MultiRegionalStorage"
+			}
+		}
+		return []gcpCostComponent{{
+			name:      fmt.Sprintf("Storage (%v)", strings.ToLower(storageClass)),
+			serviceID: gcpCloudStorageServiceID,
+			region:    location,
+			matches: func(sku gcpSku) bool {
+				return sku.ResourceGroup == resourceGroup && sku.UsageUnit == "GiBy.mo" && !strings.Contains(sku.Description, "Dual-region")
+			},
+			isUsageBased: true,
+			usageKey:     "storage_gb",
+		}}
+	},
+}
+
+// gcpMachineFamily describes the core and ram skus of a machine family, and the memory per vCPU of its predefined
+// machine classes.
+type gcpMachineFamily struct {
+	coreDescription string
+	ramDescription  string
+	memoryPerCPU    map[string]float64
+}
+
+// gcpMachineFamilies are keyed by machine type prefix. N1 custom machine types, e.g. custom-2-4096, are keyed by
+// "custom".
+var gcpMachineFamilies = map[string]gcpMachineFamily{
+	"e2":     {"E2 Instance Core", "E2 Instance Ram", map[string]float64{"standard": 4, "highmem": 8, "highcpu": 1}},
+	"n1":     {"N1 Predefined Instance Core", "N1 Predefined Instance Ram", map[string]float64{"standard": 3.75, "highmem": 6.5, "highcpu": 0.9}},
+	"n2":     {"N2 Instance Core", "N2 Instance Ram", map[string]float64{"standard": 4, "highmem": 8, "highcpu": 1}},
+	"n2d":    {"N2D AMD Instance Core", "N2D AMD Instance Ram", map[string]float64{"standard": 4, "highmem": 8, "highcpu": 1}},
+	"c2":     {"Compute optimized Core", "Compute optimized Ram", map[string]float64{"standard": 4}},
+	"t2d":    {"T2D AMD Instance Core", "T2D AMD Instance Ram", map[string]float64{"standard": 4}},
+	"custom": {"Custom Instance Core", "Custom Instance Ram", nil},
+}
+
+// gcpSharedCoreMachineTypes are the vCPUs and memory, in GiB, billed for E2 shared-core machine types.
+var gcpSharedCoreMachineTypes = map[string][2]float64{
+	"e2-micro":  {0.25, 1},
+	"e2-small":  {0.5, 2},
+	"e2-medium": {1, 4},
+}
+
+// gcpMachineShape returns the family, vCPUs and memory in GiB of a predefined or custom machine type.
+func gcpMachineShape(machineType string) (gcpMachineFamily, float64, float64, bool) {
+	machineType = machineType[strings.LastIndex(machineType, "/")+1:]
+	if shape, ok := gcpSharedCoreMachineTypes[machineType]; ok {
+		return gcpMachineFamilies["e2"], shape[0], shape[1], true
+	}
+
+	parts := strings.Split(machineType, "-")
+	family, ok := gcpMachineFamilies[parts[0]]
+	if !ok {
+		return gcpMachineFamily{}, 0, 0, false
+	}
+
+	var customParts []string
+	switch {
+	case parts[0] == "custom":
+		customParts = parts[1:]
+	case len(parts) > 1 && parts[1] == "custom":
+		customParts = parts[2:]
+	}
+	if customParts != nil {
+		if len(customParts) != 2 {
+			return gcpMachineFamily{}, 0, 0, false
+		}
+		cpus, cpuErr := strconv.ParseFloat(customParts[0], 64)
+		memoryMB, memoryErr := strconv.ParseFloat(customParts[1], 64)
+		if cpuErr != nil || memoryErr != nil {
+			return gcpMachineFamily{}, 0, 0, false
+		}
+		return family, cpus, memoryMB / 1024, true
+	}
+
+	if len(parts) != 3 {
+		return gcpMachineFamily{}, 0, 0, false
+	}
+	memoryPerCPU, ok := family.memoryPerCPU[parts[1]]
+	cpus, err := strconv.ParseFloat(parts[2], 64)
+	if !ok || err != nil {
+		return gcpMachineFamily{}, 0, 0, false
+	}
+	return family, cpus, cpus * memoryPerCPU, true
+}
+
+// gcpMachineCostComponents returns the on-demand core and ram components of count machines of machineType.
+func gcpMachineCostComponents(machineType string, region string, count float64) []gcpCostComponent {
+	family, cpus, memoryGB, ok := gcpMachineShape(machineType)
+	if !ok {
+		return nil
+	}
+
+	onDemandSku := func(description string) func(sku gcpSku) bool {
+		return func(sku gcpSku) bool {
+			return sku.UsageType == "OnDemand" && strings.HasPrefix(sku.Description, description+" running in")
+		}
+	}
+	return []gcpCostComponent{
+		{
+			name:            fmt.Sprintf("Instance vCPUs (%v)", machineType),
+			serviceID:       gcpComputeEngineServiceID,
+			region:          region,
+			matches:         onDemandSku(family.coreDescription),
+			monthlyQuantity: hoursPerMonth * cpus * count,
+		},
+		{
+			name:            fmt.Sprintf("Instance memory (%v)", machineType),
+			serviceID:       gcpComputeEngineServiceID,
+			region:          region,
+			matches:         onDemandSku(family.ramDescription),
+			monthlyQuantity: hoursPerMonth * memoryGB * count,
+		},
+	}
+}
+
+// gcpCloudSQLCostComponents returns the instance and storage components of a Cloud SQL instance.
+func gcpCloudSQLCostComponents(attributesFlat map[string]string) []gcpCostComponent {
+	engines := map[string]string{"POSTGRES": "PostgreSQL", "MYSQL": "MySQL", "SQLSERVER": "SQL Server"}
+	engine, ok := engines[strings.Split(attributesFlat["database_version"], "_")[0]]
+	if !ok {
+		return nil
+	}
+
+	availability := "Zonal"
+	if attributesFlat["settings.0.availability_type"] == "REGIONAL" {
+		availability = "Regional"
+	}
+	prefix := fmt.Sprintf("Cloud SQL for %v: %v - ", engine, availability)
+	region := attributesFlat["region"]
+
+	component := func(name string, description string, monthlyQuantity float64) gcpCostComponent {
+		return gcpCostComponent{
+			name:            name,
+			serviceID:       gcpCloudSQLServiceID,
+			region:          region,
+			matches:         func(sku gcpSku) bool { return strings.HasPrefix(sku.Description, prefix+description+" in") },
+			monthlyQuantity: monthlyQuantity,
+		}
+	}
+
+	components := make([]gcpCostComponent, 0)
+	tier := attributesFlat["settings.0.tier"]
+	switch {
+	case tier == "db-f1-micro":
+		components = append(components, component("SQL instance (db-f1-micro)", "Micro instance", hoursPerMonth))
+	case tier == "db-g1-small":
+		components = append(components, component("SQL instance (db-g1-small)", "Small instance", hoursPerMonth))
+	default:
+		cpus, memoryGB, ok := gcpCloudSQLTierShape(tier)
+		if !ok {
+			return nil
+		}
+		components = append(components,
+			component(fmt.Sprintf("SQL instance vCPUs (%v)", tier), "vCPU", hoursPerMonth*cpus),
+			component(fmt.Sprintf("SQL instance memory (%v)", tier), "RAM", hoursPerMonth*memoryGB),
+		)
+	}
+
+	storageDescription := "Standard storage"
+	if attributesFlat["settings.0.disk_type"] == "PD_HDD" {
+		storageDescription = "Low cost storage"
+	}
+	components = append(components, component(
+		fmt.Sprintf("Storage (%v)", strings.ToLower(storageDescription)), storageDescription, attributeFloat(attributesFlat, "settings.0.disk_size", 10),
+	))
+	return components
+}
+
+// gcpCloudSQLTierShape returns the vCPUs and memory in GiB of a custom or legacy predefined Cloud SQL tier.
+func gcpCloudSQLTierShape(tier string) (float64, float64, bool) {
+	parts := strings.Split(tier, "-")
+	switch {
+	case len(parts) == 4 && parts[1] == "custom":
+		cpus, cpuErr := strconv.ParseFloat(parts[2], 64)
+		memoryMB, memoryErr := strconv.ParseFloat(parts[3], 64)
+		if cpuErr != nil || memoryErr != nil {
+			return 0, 0, false
+		}
+		return cpus, memoryMB / 1024, true
+	case len(parts) == 4 && parts[1] == "n1":
+		_, cpus, memoryGB, ok := gcpMachineShape(strings.Join(parts[1:], "-"))
+		return cpus, memoryGB, ok
+	}
+	return 0, 0, false
+}
+
+// gcpRegion converts a zone, e.g. us-central1-a, to its region. Regions and multi-regions are returned unchanged.
+func gcpRegion(location string) string {
+	location = location[strings.LastIndex(location, "/")+1:]
+	if index := strings.LastIndex(location, "-"); index != -1 && len(location)-index == 2 {
+		return location[:index]
+	}
+	return location
+}
+
+// gcpUnitPrice returns the unit price of the tier in which the monthly quantity falls. Usage based components are
+// priced at the first non-free tier.
+func gcpUnitPrice(rates []gcpTierRate, monthlyQuantity float64, isUsageBased bool) float64 {
+	price := 0.0
+	for _, rate := range rates {
+		if isUsageBased {
+			if rate.UnitPrice > 0 {
+				return rate.UnitPrice
+			}
+			continue
+		}
+		if rate.StartUsageAmount <= monthlyQuantity {
+			price = rate.UnitPrice
+		}
+	}
+	return price
+}
+
+// GetAllGCPCatalogCostEstimates prices the resources within every GCP division from the Cloud Billing Catalog API.
+func (ce *CostEstimator) GetAllGCPCatalogCostEstimates(ctx context.Context) error {
+	for _, division := range ce.pricedDivisions() {
+		if !ce.isGCPDivision(division) {
+			continue
+		}
+
+		err := ce.GetGCPCatalogCostEstimate(ctx, division)
+		if err != nil {
+			return fmt.Errorf("[ce.GetGCPCatalogCostEstimate for division %v]%v", division, err)
+		}
+	}
+	return nil
+}
+
+// GetGCPCatalogCostEstimate prices the resources within a GCP division's scanned state from the Cloud Billing
+// Catalog API. Resources already estimated by Infracost keep their Infracost estimates, so that the catalog only
+// fills in resources that Infracost does not price.
+func (ce *CostEstimator) GetGCPCatalogCostEstimate(ctx context.Context, division terraformValueObjects.Division) error {
+	divisionFolderName := fmt.Sprintf("%v-%v", ce.divisionToProvider[division], division)
+	formattedPath := fmt.Sprintf("current_cloud/%v/infracost-formatted.json", divisionFolderName)
+
+	rows := make([]map[string]interface{}, 0)
+	content, err := os.ReadFile(formattedPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("[get_gcp_catalog_cost_estimate][os.ReadFile]%w", err)
+	}
+	if err == nil {
+		err = json.Unmarshal(content, &rows)
+		if err != nil {
+			return fmt.Errorf("[get_gcp_catalog_cost_estimate][json.Unmarshal]%w", err)
+		}
+	}
+
+	estimatedResources := map[string]bool{}
+	for _, row := range rows {
+		if !isUnknownCostRow(row) {
+			estimatedResources[fmt.Sprint(row["resource_name"])] = true
+		}
+	}
+
+	state, err := ce.loadTerraformerState(division)
+	if err != nil {
+		return fmt.Errorf("[get_gcp_catalog_cost_estimate]%w", err)
+	}
+
+	catalogRows, err := ce.priceGCPResources(ctx, division, state, estimatedResources)
+	if err != nil {
+		return fmt.Errorf("[get_gcp_catalog_cost_estimate]%w", err)
+	}
+	rows = append(withoutUnknownCosts(rows, catalogRows), catalogRows...)
+
+	rowsJSON, err := json.Marshal(rows)
+	if err != nil {
+		return fmt.Errorf("[get_gcp_catalog_cost_estimate][json.Marshal]%w", err)
+	}
+
+	err = os.Remove(formattedPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("[get_gcp_catalog_cost_estimate][os.Remove]%w", err)
+	}
+	err = os.WriteFile(formattedPath, rowsJSON, 0400)
+	if err != nil {
+		return fmt.Errorf("[get_gcp_catalog_cost_estimate][os.WriteFile]%w", err)
+	}
+	return nil
+}
+
+// priceGCPResources returns a cost component row for each cost component of the state's resources with a pricing
+// rule and a matching sku. Resources within estimatedResources are skipped. Skus are fetched concurrently, and
+// resources whose skus could not be fetched are marked with an unknown cost.
+func (ce *CostEstimator) priceGCPResources(ctx context.Context, division terraformValueObjects.Division, state driftDetector.TerraformerStateFile, estimatedResources map[string]bool) ([]map[string]interface{}, error) {
+	rows := make([]map[string]interface{}, 0)
+
+	// The catalog client is only created once skus are not found within the price cache.
+	var catalog gcpBillingCatalog
+	var catalogErr error
+	catalogOnce := sync.Once{}
+	listSkus := func(serviceID string) (interface{}, error) {
+		catalogOnce.Do(func() {
+			catalog, catalogErr = newGCPBillingCatalog(ctx, ce.config.DivisionCloudCredentials[division])
+		})
+		if catalogErr != nil {
+			return nil, fmt.Errorf("[newGCPBillingCatalog]%w", catalogErr)
+		}
+		return catalog.listSkus(ctx, serviceID)
+	}
+
+	divisionFolderName := fmt.Sprintf("%v-%v", ce.divisionToProvider[division], division)
+	divisionUsage := ce.usageFile.forDivision(divisionFolderName, state)
+
+	resourceNames := make([]string, 0)
+	resourceToComponents := map[string][]gcpCostComponent{}
+	serviceIDs := make([]string, 0)
+	for _, resource := range state.Resources {
+		resourceName := fmt.Sprintf("%v.%v", resource.Type, resource.Name)
+		rule, ok := gcpPricingRules[resource.Type]
+		if !ok || len(resource.Instances) == 0 || estimatedResources[resourceName] {
+			continue
+		}
+
+		usage := divisionUsage.resourceUsage(resource.Type, resource.Name)
+		components := rule(resource.Instances[0].AttributesFlat)
+		for i, component := range components {
+			if quantity, ok := usageQuantity(usage, component.usageKey); component.isUsageBased && ok {
+				components[i].monthlyQuantity = quantity
+				components[i].isUsageBased = false
+			}
+
+			if !containsString(serviceIDs, component.serviceID) {
+				serviceIDs = append(serviceIDs, component.serviceID)
+			}
+		}
+
+		resourceNames = append(resourceNames, resourceName)
+		resourceToComponents[resourceName] = components
+	}
+
+	serviceToSkus, serviceToErr := ce.fetchPrices(ctx, "gcp", serviceIDs, func() interface{} {
+		return &[]gcpSku{}
+	}, listSkus)
+
+	for _, resourceName := range resourceNames {
+		resourceRows := make([]map[string]interface{}, 0)
+		for _, component := range resourceToComponents[resourceName] {
+			if err, ok := serviceToErr[component.serviceID]; ok {
+				log.Warnf("[price_gcp_resources] %v has an unknown cost: %v", resourceName, err)
+				resourceRows = []map[string]interface{}{newUnknownCostRow(resourceName)}
+				break
+			}
+
+			for _, sku := range *serviceToSkus[component.serviceID].(*[]gcpSku) {
+				if !component.matches(sku) || (component.region != "" && !containsString(sku.Regions, component.region)) {
+					continue
+				}
+
+				resourceRows = append(resourceRows, newCostComponentRow(
+					resourceName,
+					component.name,
+					sku.UsageUnit,
+					gcpUnitPrice(sku.Rates, component.monthlyQuantity, component.isUsageBased),
+					component.monthlyQuantity,
+					component.isUsageBased,
+				))
+				break
+			}
+		}
+		rows = append(rows, resourceRows...)
+	}
+	return rows, nil
+}
+
+// withoutUnknownCosts returns rows less the unknown cost rows of resources priced within pricedRows.
+func withoutUnknownCosts(rows []map[string]interface{}, pricedRows []map[string]interface{}) []map[string]interface{} {
+	pricedResources := map[string]bool{}
+	for _, row := range pricedRows {
+		if !isUnknownCostRow(row) {
+			pricedResources[fmt.Sprint(row["resource_name"])] = true
+		}
+	}
+
+	kept := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		if isUnknownCostRow(row) && pricedResources[fmt.Sprint(row["resource_name"])] {
+			continue
+		}
+		kept = append(kept, row)
+	}
+	return kept
+}
+
+// containsString returns true if the target string is within the passed slice.
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
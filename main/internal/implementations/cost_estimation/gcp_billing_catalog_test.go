@@ -0,0 +1,167 @@
+package costEstimation
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+// fakeGCPBillingCatalog is a gcpBillingCatalog returning fixed skus per service.
+type fakeGCPBillingCatalog struct {
+	serviceToSkus map[string][]gcpSku
+	calls         []string
+}
+
+func (c *fakeGCPBillingCatalog) listSkus(_ context.Context, serviceID string) ([]gcpSku, error) {
+	c.calls = append(c.calls, serviceID)
+	return c.serviceToSkus[serviceID], nil
+}
+
+func TestGCPMachineShape(t *testing.T) {
+	testCases := []struct {
+		machineType string
+		core        string
+		cpus        float64
+		memoryGB    float64
+		ok          bool
+	}{
+		{machineType: "e2-standard-4", core: "E2 Instance Core", cpus: 4, memoryGB: 16, ok: true},
+		{machineType: "n1-highcpu-8", core: "N1 Predefined Instance Core", cpus: 8, memoryGB: 7.2, ok: true},
+		{machineType: "e2-medium", core: "E2 Instance Core", cpus: 1, memoryGB: 4, ok: true},
+		{machineType: "zones/us-central1-a/machineTypes/n2-custom-2-8192", core: "N2 Instance Core", cpus: 2, memoryGB: 8, ok: true},
+		{machineType: "custom-4-6144", core: "Custom Instance Core", cpus: 4, memoryGB: 6, ok: true},
+		{machineType: "a2-highgpu-1g"},
+		{machineType: "c2-highmem-4"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.machineType, func(t *testing.T) {
+			// When
+			family, cpus, memoryGB, ok := gcpMachineShape(testCase.machineType)
+
+			// Then
+			assert.Equal(t, testCase.ok, ok)
+			assert.Equal(t, testCase.core, family.coreDescription)
+			assert.Equal(t, testCase.cpus, cpus)
+			assert.InDelta(t, testCase.memoryGB, memoryGB, 0.0001)
+		})
+	}
+}
+
+func TestGCPRegion(t *testing.T) {
+	assert.Equal(t, "us-central1", gcpRegion("us-central1-a"))
+	assert.Equal(t, "us-central1", gcpRegion("us-central1"))
+	assert.Equal(t, "europe-west1", gcpRegion("https://www.googleapis.com/compute/v1/projects/p/zones/europe-west1-b"))
+	assert.Equal(t, "us", gcpRegion("us"))
+}
+
+func TestGCPUnitPrice(t *testing.T) {
+	// Given
+	rates := []gcpTierRate{{StartUsageAmount: 0, UnitPrice: 0}, {StartUsageAmount: 5, UnitPrice: 0.02}, {StartUsageAmount: 1000, UnitPrice: 0.01}}
+
+	// Then
+	assert.Equal(t, 0.0, gcpUnitPrice(rates, 1, false))
+	assert.Equal(t, 0.02, gcpUnitPrice(rates, 10, false))
+	assert.Equal(t, 0.01, gcpUnitPrice(rates, 2000, false))
+	assert.Equal(t, 0.02, gcpUnitPrice(rates, 0, true))
+}
+
+func TestGetGCPCatalogCostEstimate(t *testing.T) {
+	// Given
+	catalog := &fakeGCPBillingCatalog{serviceToSkus: map[string][]gcpSku{
+		gcpComputeEngineServiceID: {
+			{Description: "E2 Instance Core running in Americas", UsageType: "Preemptible", Regions: []string{"us-central1"}, UsageUnit: "h", Rates: []gcpTierRate{{UnitPrice: 0.007}}},
+			{Description: "E2 Instance Core running in Americas", UsageType: "OnDemand", Regions: []string{"us-central1"}, UsageUnit: "h", Rates: []gcpTierRate{{UnitPrice: 0.02}}},
+			{Description: "E2 Instance Ram running in Americas", UsageType: "OnDemand", Regions: []string{"us-central1"}, UsageUnit: "GiBy.h", Rates: []gcpTierRate{{UnitPrice: 0.003}}},
+			{Description: "SSD backed PD Capacity", UsageType: "OnDemand", Regions: []string{"europe-west1"}, UsageUnit: "GiBy.mo", Rates: []gcpTierRate{{UnitPrice: 0.19}}},
+			{Description: "SSD backed PD Capacity", UsageType: "OnDemand", Regions: []string{"us-central1"}, UsageUnit: "GiBy.mo", Rates: []gcpTierRate{{UnitPrice: 0.17}}},
+		},
+		gcpCloudStorageServiceID: {
+			{Description: "Standard Storage US Multi-region", ResourceGroup: "MultiRegionalStorage", Regions: []string{"us"}, UsageUnit: "GiBy.mo", Rates: []gcpTierRate{{UnitPrice: 0.026}}},
+		},
+	}}
+	originalCatalog := newGCPBillingCatalog
+	newGCPBillingCatalog = func(ctx context.Context, credential terraformValueObjects.Credential) (gcpBillingCatalog, error) {
+		return catalog, nil
+	}
+	defer func() { newGCPBillingCatalog = originalCatalog }()
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+	require.NoError(t, os.MkdirAll("current_cloud/google-my-project", 0755))
+
+	state := `{"resources": [
+		{"type": "google_compute_instance", "name": "tfer--web", "instances": [{"attributes_flat": {"machine_type": "e2-standard-2", "zone": "us-central1-a"}}]},
+		{"type": "google_compute_disk", "name": "tfer--data", "instances": [{"attributes_flat": {"type": "pd-ssd", "size": "100", "zone": "us-central1-b"}}]},
+		{"type": "google_storage_bucket", "name": "tfer--logs", "instances": [{"attributes_flat": {"storage_class": "STANDARD", "location": "US"}}]},
+		{"type": "google_storage_bucket", "name": "tfer--backups", "instances": [{"attributes_flat": {"storage_class": "STANDARD", "location": "US"}}]},
+		{"type": "google_sql_database_instance", "name": "tfer--db", "instances": [{"attributes_flat": {"settings.0.tier": "db-f1-micro", "database_version": "POSTGRES_14", "region": "us-central1"}}]}
+	]}`
+	require.NoError(t, os.WriteFile("current_cloud/google-my-project/terraform.tfstate", []byte(state), 0400))
+
+	infracostRows := `[{"resource_name": "google_sql_database_instance.tfer--db", "cost_component": "SQL instance (db-f1-micro, zonal)", "unit": "hours", "price": "0.0105", "monthly_quantity": "730", "monthly_cost": "7.665", "is_usage_based": false, "sub_resource_name": ""}]`
+	require.NoError(t, os.WriteFile("current_cloud/google-my-project/infracost-formatted.json", []byte(infracostRows), 0400))
+
+	ce := CostEstimator{
+		config: CostEstimatorConfig{
+			DivisionCloudCredentials: terraformValueObjects.DivisionCloudCredentialDecoder{"my-project": "{}"},
+		},
+		divisionToProvider: map[terraformValueObjects.Division]terraformValueObjects.Provider{"my-project": "google"},
+		usageFile: &UsageFile{ResourceUsage: map[string]map[string]interface{}{
+			"google_storage_bucket.backups": {"storage_gb": 1000},
+		}},
+	}
+
+	// When
+	err = ce.GetGCPCatalogCostEstimate(context.Background(), "my-project")
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, []string{gcpComputeEngineServiceID, gcpCloudStorageServiceID}, catalog.calls)
+
+	content, err := os.ReadFile("current_cloud/google-my-project/infracost-formatted.json")
+	require.NoError(t, err)
+	rows := make([]map[string]interface{}, 0)
+	require.NoError(t, json.Unmarshal(content, &rows))
+
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"resource_name": "google_sql_database_instance.tfer--db", "cost_component": "SQL instance (db-f1-micro, zonal)",
+			"unit": "hours", "price": "0.0105", "monthly_quantity": "730", "monthly_cost": "7.665",
+			"is_usage_based": false, "sub_resource_name": "",
+		},
+		{
+			"resource_name": "google_compute_instance.tfer--web", "cost_component": "Instance vCPUs (e2-standard-2)",
+			"unit": "h", "price": "0.02", "monthly_quantity": "1460", "monthly_cost": "29.2000",
+			"is_usage_based": false, "sub_resource_name": "",
+		},
+		{
+			"resource_name": "google_compute_instance.tfer--web", "cost_component": "Instance memory (e2-standard-2)",
+			"unit": "GiBy.h", "price": "0.003", "monthly_quantity": "5840", "monthly_cost": "17.5200",
+			"is_usage_based": false, "sub_resource_name": "",
+		},
+		{
+			"resource_name": "google_compute_disk.tfer--data", "cost_component": "Storage (pd-ssd)",
+			"unit": "GiBy.mo", "price": "0.17", "monthly_quantity": "100", "monthly_cost": "17.0000",
+			"is_usage_based": false, "sub_resource_name": "",
+		},
+		{
+			"resource_name": "google_storage_bucket.tfer--logs", "cost_component": "Storage (standard)",
+			"unit": "GiBy.mo", "price": "0.026", "monthly_quantity": "", "monthly_cost": "",
+			"is_usage_based": true, "sub_resource_name": "",
+		},
+		{
+			"resource_name": "google_storage_bucket.tfer--backups", "cost_component": "Storage (standard)",
+			"unit": "GiBy.mo", "price": "0.026", "monthly_quantity": "1000", "monthly_cost": "26.0000",
+			"is_usage_based": false, "sub_resource_name": "",
+		},
+	}, rows)
+}
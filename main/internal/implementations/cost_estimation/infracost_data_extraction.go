@@ -66,9 +66,13 @@ type CostComponent struct {
 }
 
 // FormatAllCostEstimates processes infracost-generated cost estimation data into a more concise format for
-// downstream usage for all cloud divisions.
+// downstream usage for all cloud divisions priced by Infracost.
 func (ce *CostEstimator) FormatAllCostEstimates() error {
-	for division := range ce.config.DivisionCloudCredentials {
+	for _, division := range ce.pricedDivisions() {
+		if _, unpriced := ce.unpricedDivisions.Load(division); unpriced || !ce.usesInfracost(division) {
+			continue
+		}
+
 		gabsJSONString, err := ce.FormatCostEstimate(division)
 		if err != nil {
 			return fmt.Errorf("[ce.FormatCostEstimate for division %v]%v", division, err)
@@ -0,0 +1,187 @@
+package costEstimation
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// bundledPricingSnapshotDirectory is the pricing snapshot bundled within the container image at build time.
+var bundledPricingSnapshotDirectory = "/pricing-snapshot/"
+
+// infracostPriceSource is the price cache source of the estimates output by the infracost CLI.
+const infracostPriceSource = "infracost"
+
+// errPriceNotInSnapshot is returned when pricing offline and the pricing snapshot does not contain the requested
+// pricing data.
+var errPriceNotInSnapshot = errors.New("pricing data not found within the pricing snapshot")
+
+// priceCacheEntry is the pricing data cached for a single pricing request.
+type priceCacheEntry struct {
+	// Key identifies the pricing request, e.g. the Azure Retail Prices API filter.
+	Key string
+
+	// Value is the JSON encoded pricing data returned for the request.
+	Value json.RawMessage
+}
+
+// priceCache stores pricing data between runs, so that pricing APIs are only queried once entries become stale,
+// and runs can proceed from stale entries when a pricing API is unavailable.
+type priceCache struct {
+	// directory is the root directory of the cache. Can be a mounted volume.
+	directory string
+
+	// maxAge is the maximum age of a cache entry before it is considered stale.
+	maxAge time.Duration
+
+	// offline flags that directory is a read-only pricing snapshot. Snapshot entries never become stale, and pricing
+	// APIs are never queried.
+	offline bool
+}
+
+// newPriceCache returns the priceCache of the cost estimator configuration, or nil if neither a price cache nor
+// offline pricing is configured.
+func newPriceCache(config CostEstimatorConfig) *priceCache {
+	if config.OfflinePricing {
+		directory := config.PricingSnapshotDirectory
+		if strings.TrimSpace(directory) == "" {
+			directory = bundledPricingSnapshotDirectory
+		}
+		return &priceCache{directory: directory, offline: true}
+	}
+
+	if strings.TrimSpace(config.PriceCacheDirectory) == "" {
+		return nil
+	}
+	return &priceCache{directory: config.PriceCacheDirectory, maxAge: config.PriceCacheMaxAge}
+}
+
+// isOffline returns true if pricing data is read only from a pricing snapshot.
+func (c *priceCache) isOffline() bool {
+	return c != nil && c.offline
+}
+
+// hasSource returns true if the cache holds any pricing data of source.
+func (c *priceCache) hasSource(source string) bool {
+	if c == nil {
+		return false
+	}
+	info, err := os.Stat(filepath.Join(c.directory, source))
+	return err == nil && info.IsDir()
+}
+
+// fetch decodes the pricing data of the request identified by source and key into value. Fresh cache entries are
+// used as is, otherwise download is called and its result cached. A stale entry is used when download fails.
+func (c *priceCache) fetch(source string, key string, value interface{}, download func() (interface{}, error)) error {
+	if c == nil {
+		return downloadInto(value, download)
+	}
+
+	entryPath := filepath.Join(c.directory, source, priceCacheEntryName(key))
+	entry, modTime, err := readPriceCacheEntry(entryPath)
+	if err != nil {
+		return fmt.Errorf("[price_cache][fetch]%w", err)
+	}
+
+	if c.offline {
+		if entry == nil {
+			return fmt.Errorf("[price_cache][fetch][%v %v]%w", source, key, errPriceNotInSnapshot)
+		}
+		return json.Unmarshal(entry.Value, value)
+	}
+
+	if entry != nil && (c.maxAge <= 0 || time.Since(modTime) <= c.maxAge) {
+		return json.Unmarshal(entry.Value, value)
+	}
+
+	downloaded, err := download()
+	if err != nil {
+		if entry == nil {
+			return err
+		}
+		log.Warnf("[price_cache] using stale %v pricing data after failing to download it: %v", source, err)
+		return json.Unmarshal(entry.Value, value)
+	}
+
+	encoded, err := json.Marshal(downloaded)
+	if err != nil {
+		return fmt.Errorf("[price_cache][fetch][json.Marshal]%w", err)
+	}
+
+	err = writePriceCacheEntry(entryPath, priceCacheEntry{Key: key, Value: encoded})
+	if err != nil {
+		log.Warnf("[price_cache] unable to cache %v pricing data: %v", source, err)
+	}
+	return json.Unmarshal(encoded, value)
+}
+
+// downloadInto calls download and decodes its result into value.
+func downloadInto(value interface{}, download func() (interface{}, error)) error {
+	downloaded, err := download()
+	if err != nil {
+		return err
+	}
+
+	encoded, err := json.Marshal(downloaded)
+	if err != nil {
+		return fmt.Errorf("[download_into][json.Marshal]%w", err)
+	}
+	return json.Unmarshal(encoded, value)
+}
+
+// priceCacheEntryName returns the file name of the cache entry of a pricing request key.
+func priceCacheEntryName(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:]) + ".json"
+}
+
+// readPriceCacheEntry reads the cache entry at path along with its modification time, returning a nil entry when
+// the entry does not exist.
+func readPriceCacheEntry(path string) (*priceCacheEntry, time.Time, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, time.Time{}, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("[read_price_cache_entry][os.Stat]%w", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("[read_price_cache_entry][os.ReadFile]%w", err)
+	}
+
+	entry := &priceCacheEntry{}
+	err = json.Unmarshal(content, entry)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("[read_price_cache_entry][json.Unmarshal %v]%w", path, err)
+	}
+	return entry, info.ModTime(), nil
+}
+
+// writePriceCacheEntry writes the cache entry to path, replacing any previous entry.
+func writePriceCacheEntry(path string, entry priceCacheEntry) error {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return fmt.Errorf("[write_price_cache_entry][os.MkdirAll]%w", err)
+	}
+
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("[write_price_cache_entry][json.Marshal]%w", err)
+	}
+
+	err = os.WriteFile(path, content, 0600)
+	if err != nil {
+		return fmt.Errorf("[write_price_cache_entry][os.WriteFile]%w", err)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package costEstimation
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPriceCache(t *testing.T) {
+	assert.Nil(t, newPriceCache(CostEstimatorConfig{}))
+	assert.Equal(t, &priceCache{directory: "/cache", maxAge: time.Hour}, newPriceCache(CostEstimatorConfig{PriceCacheDirectory: "/cache", PriceCacheMaxAge: time.Hour}))
+	assert.Equal(t, &priceCache{directory: bundledPricingSnapshotDirectory, offline: true}, newPriceCache(CostEstimatorConfig{PriceCacheDirectory: "/cache", OfflinePricing: true}))
+	assert.Equal(t, &priceCache{directory: "/snapshot", offline: true}, newPriceCache(CostEstimatorConfig{OfflinePricing: true, PricingSnapshotDirectory: "/snapshot"}))
+}
+
+func TestPriceCache_Fetch(t *testing.T) {
+	// Given
+	cache := &priceCache{directory: t.TempDir(), maxAge: time.Hour}
+	downloads := 0
+	download := func(prices []AzureRetailPrice, err error) func() (interface{}, error) {
+		return func() (interface{}, error) {
+			downloads++
+			return prices, err
+		}
+	}
+	original := []AzureRetailPrice{{MeterName: "D2s v3", RetailPrice: 0.096}}
+	updated := []AzureRetailPrice{{MeterName: "D2s v3", RetailPrice: 0.1}}
+
+	// When
+	got := make([]AzureRetailPrice, 0)
+	err := cache.fetch("azure", "filter", &got, download(original, nil))
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, original, got)
+	assert.Equal(t, 1, downloads)
+
+	// When the entry is fresh
+	got = make([]AzureRetailPrice, 0)
+	err = cache.fetch("azure", "filter", &got, download(updated, nil))
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, original, got)
+	assert.Equal(t, 1, downloads)
+
+	// When the entry is stale and the download fails
+	entryPath := filepath.Join(cache.directory, "azure", priceCacheEntryName("filter"))
+	staleTime := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(entryPath, staleTime, staleTime))
+
+	got = make([]AzureRetailPrice, 0)
+	err = cache.fetch("azure", "filter", &got, download(nil, errors.New("service unavailable")))
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, original, got)
+	assert.Equal(t, 2, downloads)
+
+	// When the entry is stale and the download succeeds
+	got = make([]AzureRetailPrice, 0)
+	err = cache.fetch("azure", "filter", &got, download(updated, nil))
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, updated, got)
+	assert.Equal(t, 3, downloads)
+
+	// When the download of an uncached request fails
+	err = cache.fetch("azure", "other-filter", &got, download(nil, errors.New("service unavailable")))
+
+	// Then
+	assert.EqualError(t, err, "service unavailable")
+}
+
+func TestPriceCache_FetchOffline(t *testing.T) {
+	// Given
+	directory := t.TempDir()
+	skus := []gcpSku{{Description: "E2 Instance Core running in Americas", UsageUnit: "h", Rates: []gcpTierRate{{UnitPrice: 0.02}}}}
+	require.NoError(t, (&priceCache{directory: directory}).fetch("gcp", gcpComputeEngineServiceID, &[]gcpSku{}, func() (interface{}, error) {
+		return skus, nil
+	}))
+
+	cache := &priceCache{directory: directory, offline: true}
+	download := func() (interface{}, error) {
+		t.Fatal("pricing APIs must not be queried when pricing offline")
+		return nil, nil
+	}
+
+	// When
+	got := make([]gcpSku, 0)
+	err := cache.fetch("gcp", gcpComputeEngineServiceID, &got, download)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, skus, got)
+
+	// When
+	err = cache.fetch("gcp", gcpCloudStorageServiceID, &got, download)
+
+	// Then
+	assert.ErrorIs(t, err, errPriceNotInSnapshot)
+}
+
+func TestPriceCache_FetchWithoutCache(t *testing.T) {
+	// Given
+	var cache *priceCache
+
+	// When
+	got := make([]AzureRetailPrice, 0)
+	err := cache.fetch("azure", "filter", &got, func() (interface{}, error) {
+		return []AzureRetailPrice{{MeterName: "D2s v3"}}, nil
+	})
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, []AzureRetailPrice{{MeterName: "D2s v3"}}, got)
+	assert.False(t, cache.isOffline())
+}
@@ -0,0 +1,132 @@
+package costEstimation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultPricingConcurrency is the number of concurrent pricing requests when none is configured.
+	defaultPricingConcurrency = 1
+
+	// unknownCostComponent is the cost component of the row marking a resource that could not be priced.
+	unknownCostComponent = "Unknown cost (pricing unavailable)"
+)
+
+// pricingConcurrency returns the maximum number of pricing requests, or divisions, priced at once.
+func (ce *CostEstimator) pricingConcurrency() int {
+	if ce.config.PricingConcurrency < 1 {
+		return defaultPricingConcurrency
+	}
+	return ce.config.PricingConcurrency
+}
+
+// forEachConcurrently calls fn for each key, with at most pricingConcurrency calls in flight, and returns once all
+// calls have completed. fn must be safe to call concurrently.
+func (ce *CostEstimator) forEachConcurrently(keys []string, fn func(key string)) {
+	semaphore := make(chan struct{}, ce.pricingConcurrency())
+	wg := sync.WaitGroup{}
+
+	for _, key := range keys {
+		semaphore <- struct{}{}
+		wg.Add(1)
+		go func(key string) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			fn(key)
+		}(key)
+	}
+	wg.Wait()
+}
+
+// withRetries wraps download so that failed attempts are retried with an exponential backoff, up to the configured
+// maximum number of retries. Retries stop early once ctx is done.
+func (ce *CostEstimator) withRetries(ctx context.Context, description string, download func() (interface{}, error)) func() (interface{}, error) {
+	return func() (interface{}, error) {
+		backoff := ce.config.PricingRetryBackoff
+
+		var err error
+		for attempt := 0; attempt <= ce.config.PricingMaxRetries; attempt++ {
+			if attempt > 0 {
+				log.Warnf("[%v] attempt %v failed, retrying in %v: %v", description, attempt, backoff, err)
+				err = sleepContext(ctx, backoff)
+				if err != nil {
+					return nil, err
+				}
+				backoff *= 2
+			}
+
+			var value interface{}
+			value, err = download()
+			if err == nil {
+				return value, nil
+			}
+		}
+		return nil, fmt.Errorf("[%v failed after %v attempt(s)]%w", description, ce.config.PricingMaxRetries+1, err)
+	}
+}
+
+// fetchPrices fetches the pricing data of each key concurrently through the price cache, retrying failed downloads.
+// Keys whose pricing data could not be fetched are returned within the map of errors rather than failing the
+// others; keys missing from an offline pricing snapshot are only logged.
+func (ce *CostEstimator) fetchPrices(ctx context.Context, source string, keys []string, newValue func() interface{}, download func(key string) (interface{}, error)) (map[string]interface{}, map[string]error) {
+	mu := sync.Mutex{}
+	keyToValue := map[string]interface{}{}
+	keyToErr := map[string]error{}
+
+	ce.forEachConcurrently(keys, func(key string) {
+		value := newValue()
+		err := ce.prices.fetch(source, key, value, ce.withRetries(ctx, source+" pricing request", func() (interface{}, error) {
+			return download(key)
+		}))
+
+		mu.Lock()
+		defer mu.Unlock()
+		if errors.Is(err, errPriceNotInSnapshot) {
+			log.Warnf("[fetch_prices] %v: %v", source, err)
+		} else if err != nil {
+			keyToErr[key] = err
+			return
+		}
+		keyToValue[key] = value
+	})
+
+	return keyToValue, keyToErr
+}
+
+// newUnknownCostRow returns a cost component row marking a resource that could not be priced, so that it is
+// reported as having an unknown rather than a zero cost.
+func newUnknownCostRow(resourceName string) map[string]interface{} {
+	row := newCostComponentRow(resourceName, unknownCostComponent, "", 0, 0, false)
+	row["price"] = ""
+	row["monthly_quantity"] = ""
+	row["monthly_cost"] = ""
+	row["is_unknown_cost"] = true
+	return row
+}
+
+// isUnknownCostRow returns true if the cost component row marks a resource that could not be priced.
+func isUnknownCostRow(row map[string]interface{}) bool {
+	unknown, _ := row["is_unknown_cost"].(bool)
+	return unknown
+}
+
+// sleepContext sleeps for duration, returning early with an error if ctx is done.
+func sleepContext(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("[sleep_context][interrupted]%w", ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,125 @@
+package costEstimation
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+)
+
+func TestForEachConcurrently(t *testing.T) {
+	// Given
+	ce := CostEstimator{config: CostEstimatorConfig{PricingConcurrency: 3}}
+	mu := sync.Mutex{}
+	inFlight, maxInFlight := 0, 0
+	called := map[string]bool{}
+
+	// When
+	ce.forEachConcurrently([]string{"a", "b", "c", "d", "e", "f", "g", "h"}, func(key string) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		called[key] = true
+		mu.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+	})
+
+	// Then
+	assert.Len(t, called, 8)
+	assert.LessOrEqual(t, maxInFlight, 3)
+}
+
+func TestWithRetries(t *testing.T) {
+	// Given
+	ce := CostEstimator{config: CostEstimatorConfig{PricingMaxRetries: 2, PricingRetryBackoff: time.Millisecond}}
+	attempts := 0
+	download := func(failures int) func() (interface{}, error) {
+		return func() (interface{}, error) {
+			attempts++
+			if attempts <= failures {
+				return nil, errors.New("service unavailable")
+			}
+			return "prices", nil
+		}
+	}
+
+	// When
+	got, err := ce.withRetries(context.Background(), "test", download(2))()
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, "prices", got)
+	assert.Equal(t, 3, attempts)
+
+	// When every attempt fails
+	attempts = 0
+	_, err = ce.withRetries(context.Background(), "test", download(3))()
+
+	// Then
+	assert.EqualError(t, err, "[test failed after 3 attempt(s)]service unavailable")
+	assert.Equal(t, 3, attempts)
+}
+
+func TestPriceAzureResources_UnknownCost(t *testing.T) {
+	// Given
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	originalEndpoint := azureRetailPricesEndpoint
+	azureRetailPricesEndpoint = server.URL
+	defer func() { azureRetailPricesEndpoint = originalEndpoint }()
+
+	state := driftDetector.TerraformerStateFile{Resources: []*driftDetector.TerraformerResource{
+		{Type: "azurerm_linux_virtual_machine", Name: "tfer--web", Instances: []driftDetector.TerraformerInstance{{AttributesFlat: map[string]string{"size": "Standard_D2s_v3", "location": "eastus"}}}},
+		{Type: "azurerm_resource_group", Name: "tfer--my-rg", Instances: []driftDetector.TerraformerInstance{{AttributesFlat: map[string]string{"location": "eastus"}}}},
+	}}
+	ce := CostEstimator{config: CostEstimatorConfig{PricingConcurrency: 4, PricingMaxRetries: 1, PricingRetryBackoff: time.Millisecond}}
+
+	// When
+	rows, err := ce.priceAzureResources(context.Background(), state)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, []map[string]interface{}{{
+		"resource_name": "azurerm_linux_virtual_machine.tfer--web", "cost_component": unknownCostComponent,
+		"unit": "", "price": "", "monthly_quantity": "", "monthly_cost": "",
+		"is_usage_based": false, "sub_resource_name": "", "is_unknown_cost": true,
+	}}, rows)
+}
+
+func TestWithoutUnknownCosts(t *testing.T) {
+	// Given
+	rows := []map[string]interface{}{
+		newUnknownCostRow("google_compute_instance.tfer--web"),
+		newUnknownCostRow("google_compute_disk.tfer--data"),
+	}
+	pricedRows := []map[string]interface{}{
+		newCostComponentRow("google_compute_instance.tfer--web", "Instance usage", "h", 0.1, 730, false),
+	}
+
+	// When
+	got := withoutUnknownCosts(rows, pricedRows)
+
+	// Then
+	assert.Equal(t, []map[string]interface{}{newUnknownCostRow("google_compute_disk.tfer--data")}, got)
+}
@@ -0,0 +1,143 @@
+package costEstimation
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/clonedrepo"
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+)
+
+// terraformerNamePrefix is the prefix terraformer adds to the names of the resources it scans.
+const terraformerNamePrefix = "tfer--"
+
+// UsageFile is an Infracost usage file, estimating the consumption of usage based resources, e.g. the monthly
+// requests of a Lambda function or the stored GB of a bucket.
+type UsageFile struct {
+	// Version is the version of the usage file format.
+	Version string `yaml:"version"`
+
+	// ResourceTypeDefaultUsage is the usage of every resource of a type, keyed by resource type.
+	ResourceTypeDefaultUsage map[string]map[string]interface{} `yaml:"resource_type_default_usage,omitempty"`
+
+	// ResourceUsage is the usage of individual resources, keyed by resource address. Addresses may be prefixed with
+	// the division folder name, e.g. aws-123456789012.aws_s3_bucket.logs, to target a single division, and may omit
+	// the "tfer--" prefix of resource names.
+	ResourceUsage map[string]map[string]interface{} `yaml:"resource_usage,omitempty"`
+}
+
+// loadUsageFile loads the usage file within the cloned scanned repository, returning nil when no usage file is
+// configured or the repository does not contain one.
+func (ce *CostEstimator) loadUsageFile() (*UsageFile, error) {
+	if ce.config.UsageFile == "" || ce.config.UsageFile == "None" {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(filepath.Join(clonedrepo.Directory, ce.config.UsageFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[load_usage_file][os.ReadFile]%w", err)
+	}
+
+	usageFile := &UsageFile{}
+	err = yaml.Unmarshal(content, usageFile)
+	if err != nil {
+		return nil, fmt.Errorf("[load_usage_file][yaml.Unmarshal]%w", err)
+	}
+	return usageFile, nil
+}
+
+// forDivision returns the usage file of a division, with the resource usage keyed by the addresses of the
+// division's scanned resources. Usage targeted at other divisions or at resources that were not scanned is dropped.
+func (u *UsageFile) forDivision(divisionFolderName string, state driftDetector.TerraformerStateFile) *UsageFile {
+	if u == nil {
+		return nil
+	}
+
+	divisionUsage := &UsageFile{
+		Version:                  u.Version,
+		ResourceTypeDefaultUsage: u.ResourceTypeDefaultUsage,
+		ResourceUsage:            map[string]map[string]interface{}{},
+	}
+	if divisionUsage.Version == "" {
+		divisionUsage.Version = "0.1"
+	}
+
+	scannedAddresses := map[string]string{}
+	for _, resource := range state.Resources {
+		address := fmt.Sprintf("%v.%v", resource.Type, resource.Name)
+		scannedAddresses[address] = address
+		scannedAddresses[fmt.Sprintf("%v.%v", resource.Type, strings.TrimPrefix(resource.Name, terraformerNamePrefix))] = address
+	}
+
+	// Division scoped addresses are applied last, so that they take precedence over unscoped addresses.
+	for _, scoped := range []bool{false, true} {
+		for key, usage := range u.ResourceUsage {
+			if strings.HasPrefix(key, divisionFolderName+".") != scoped {
+				continue
+			}
+
+			address := strings.TrimPrefix(key, divisionFolderName+".")
+			if scannedAddress, ok := scannedAddresses[address]; ok {
+				divisionUsage.ResourceUsage[scannedAddress] = usage
+			}
+		}
+	}
+	return divisionUsage
+}
+
+// resourceUsage returns the usage of a scanned resource, falling back to the default usage of its type.
+func (u *UsageFile) resourceUsage(resourceType string, resourceName string) map[string]interface{} {
+	if u == nil {
+		return nil
+	}
+
+	if usage, ok := u.ResourceUsage[fmt.Sprintf("%v.%v", resourceType, resourceName)]; ok {
+		return usage
+	}
+	return u.ResourceTypeDefaultUsage[resourceType]
+}
+
+// writeDivisionUsageFile writes the usage file of a division alongside its scanned state, returning the path of
+// the written file.
+func (u *UsageFile) writeDivisionUsageFile(divisionFolderName string) (string, error) {
+	content, err := yaml.Marshal(u)
+	if err != nil {
+		return "", fmt.Errorf("[write_division_usage_file][yaml.Marshal]%w", err)
+	}
+
+	path := fmt.Sprintf("./current_cloud/%v/infracost-usage.yml", divisionFolderName)
+	err = os.Remove(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("[write_division_usage_file][os.Remove]%w", err)
+	}
+
+	err = os.WriteFile(path, content, 0400)
+	if err != nil {
+		return "", fmt.Errorf("[write_division_usage_file][os.WriteFile]%w", err)
+	}
+	return path, nil
+}
+
+// usageQuantity returns the numeric usage value of key, if it is specified.
+func usageQuantity(usage map[string]interface{}, key string) (float64, bool) {
+	switch value := usage[key].(type) {
+	case int:
+		return float64(value), true
+	case float64:
+		return value, true
+	case string:
+		quantity, err := strconv.ParseFloat(value, 64)
+		return quantity, err == nil
+	default:
+		return 0, false
+	}
+}
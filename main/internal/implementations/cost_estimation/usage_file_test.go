@@ -0,0 +1,111 @@
+package costEstimation
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+)
+
+func TestLoadUsageFile(t *testing.T) {
+	// Given
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+	require.NoError(t, os.MkdirAll("repo/costs", 0755))
+
+	usage := `version: 0.1
+resource_type_default_usage:
+  aws_lambda_function:
+    monthly_requests: 100000
+resource_usage:
+  aws_s3_bucket.logs:
+    standard:
+      storage_gb: 500
+`
+	require.NoError(t, os.WriteFile("repo/costs/usage.yml", []byte(usage), 0400))
+
+	// When
+	ce := CostEstimator{config: CostEstimatorConfig{UsageFile: "costs/usage.yml"}}
+	got, err := ce.loadUsageFile()
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, &UsageFile{
+		Version:                  "0.1",
+		ResourceTypeDefaultUsage: map[string]map[string]interface{}{"aws_lambda_function": {"monthly_requests": 100000}},
+		ResourceUsage: map[string]map[string]interface{}{
+			"aws_s3_bucket.logs": {"standard": map[string]interface{}{"storage_gb": 500}},
+		},
+	}, got)
+
+	// When
+	ce = CostEstimator{config: CostEstimatorConfig{UsageFile: "infracost-usage.yml"}}
+	got, err = ce.loadUsageFile()
+
+	// Then
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestUsageFile_ForDivision(t *testing.T) {
+	// Given
+	usageFile := &UsageFile{
+		ResourceUsage: map[string]map[string]interface{}{
+			"aws_s3_bucket.logs":                          {"monthly_tier_1_requests": 1000},
+			"aws-123456789012.aws_s3_bucket.tfer--logs":   {"monthly_tier_1_requests": 5000},
+			"aws_lambda_function.tfer--api":               {"monthly_requests": 100},
+			"aws-210987654321.aws_lambda_function.worker": {"monthly_requests": 200},
+			"aws_instance.not-scanned":                    {"operating_system": "linux"},
+		},
+	}
+	state := driftDetector.TerraformerStateFile{Resources: []*driftDetector.TerraformerResource{
+		{Type: "aws_s3_bucket", Name: "tfer--logs"},
+		{Type: "aws_lambda_function", Name: "tfer--api"},
+		{Type: "aws_lambda_function", Name: "tfer--worker"},
+	}}
+
+	// When
+	got := usageFile.forDivision("aws-123456789012", state)
+
+	// Then
+	assert.Equal(t, &UsageFile{
+		Version: "0.1",
+		ResourceUsage: map[string]map[string]interface{}{
+			"aws_s3_bucket.tfer--logs":      {"monthly_tier_1_requests": 5000},
+			"aws_lambda_function.tfer--api": {"monthly_requests": 100},
+		},
+	}, got)
+}
+
+func TestUsageQuantity(t *testing.T) {
+	usage := map[string]interface{}{"int": 10, "float": 2.5, "string": "7", "invalid": "many", "nested": map[string]interface{}{}}
+
+	testCases := []struct {
+		key  string
+		want float64
+		ok   bool
+	}{
+		{key: "int", want: 10, ok: true},
+		{key: "float", want: 2.5, ok: true},
+		{key: "string", want: 7, ok: true},
+		{key: "invalid", ok: false},
+		{key: "nested", ok: false},
+		{key: "missing", ok: false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.key, func(t *testing.T) {
+			// When
+			got, ok := usageQuantity(usage, testCase.key)
+
+			// Then
+			assert.Equal(t, testCase.ok, ok)
+			assert.Equal(t, testCase.want, got)
+		})
+	}
+}
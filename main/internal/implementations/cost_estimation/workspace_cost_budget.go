@@ -0,0 +1,34 @@
+package costEstimation
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// WorkspaceCostBudget is the budget against which the uncontrolled cost of each workspace is scored within the
+// workspace health scores, written to mappings/workspace-cost-budget.json.
+type WorkspaceCostBudget struct {
+	// MonthlyBudget is the monthly cost, in Currency, at which a workspace's cost score reaches 0. The report's
+	// default budget is used when it is not positive.
+	MonthlyBudget float64
+
+	// Currency is the ISO 4217 code of the currency of MonthlyBudget.
+	Currency string
+}
+
+// writeWorkspaceCostBudget writes the configured workspace cost budget to mappings/workspace-cost-budget.json.
+func (ce *CostEstimator) writeWorkspaceCostBudget() error {
+	content, err := json.MarshalIndent(WorkspaceCostBudget{
+		MonthlyBudget: ce.config.WorkspaceMonthlyBudget,
+		Currency:      ce.currency.Currency,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[write_workspace_cost_budget][json.MarshalIndent]%w", err)
+	}
+
+	err = ce.artifactStore.WriteFile("mappings/workspace-cost-budget.json", content, 0400)
+	if err != nil {
+		return fmt.Errorf("[write_workspace_cost_budget][artifacts.WriteFile]%w", err)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package costEstimation
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+)
+
+func TestWriteWorkspaceCostBudget(t *testing.T) {
+	// Given
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+	require.NoError(t, os.MkdirAll("mappings", 0755))
+
+	ce := CostEstimator{
+		artifactStore: artifacts.NewMemoryStore(true),
+		config:        CostEstimatorConfig{WorkspaceMonthlyBudget: 250},
+		currency:      CostCurrency{Currency: "EUR", ExchangeRate: 0.92, Source: "static"},
+	}
+
+	// When
+	err = ce.writeWorkspaceCostBudget()
+
+	// Then
+	require.NoError(t, err)
+
+	content, err := os.ReadFile("mappings/workspace-cost-budget.json")
+	require.NoError(t, err)
+	budget := WorkspaceCostBudget{}
+	require.NoError(t, json.Unmarshal(content, &budget))
+	assert.Equal(t, WorkspaceCostBudget{MonthlyBudget: 250, Currency: "EUR"}, budget)
+}
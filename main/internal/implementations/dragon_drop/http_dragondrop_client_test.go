@@ -2,6 +2,7 @@ package dragonDrop
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -39,3 +40,38 @@ func TestPostLog(t *testing.T) {
 	err = dragonDrop.(*HTTPDragonDropClient).postLog(ctx, "Example log", false)
 	assert.Nil(t, err)
 }
+
+func TestInformFailed(t *testing.T) {
+	// Given
+	dragonDropFactory := new(Factory)
+
+	ctx := context.Background()
+	mux := http.NewServeMux()
+
+	var body JobStatusPostBody
+	mux.HandleFunc(
+		"/job/status/",
+		func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			w.WriteHeader(http.StatusCreated)
+		})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	config := HTTPDragonDropClientConfig{
+		APIPath:  server.URL,
+		JobID:    "123",
+		OrgToken: "123",
+	}
+
+	dragonDrop, err := dragonDropFactory.Instantiate("", config)
+	assert.Nil(t, err)
+
+	// When
+	err = dragonDrop.InformFailed(ctx, "RATE_LIMITED")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, JobStatusPostBody{JobID: "123", Status: "Failed", ErrorCode: "RATE_LIMITED"}, body)
+}
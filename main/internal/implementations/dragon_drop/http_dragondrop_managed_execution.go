@@ -17,16 +17,19 @@ type AuthorizeManagedJobRequestBody struct {
 }
 
 // PutPRURLRequest is a struct for the data in a request to the dragondrop API to
-// update the PRURL for a given JobID.
+// update the PRURL for a given JobID. PRURL is the first of PRURLs, the urls of every pull request opened when the
+// changes are split across several.
 type PutPRURLRequest struct {
-	JobID string
-	PRURL string
+	JobID  string
+	PRURL  string
+	PRURLs []string
 }
 
 // JobStatusPostBody is a struct for sending a job status update to the dragondrop API.
 type JobStatusPostBody struct {
-	JobID  string
-	Status string
+	JobID     string
+	Status    string
+	ErrorCode string `json:",omitempty"`
 }
 
 // PostLogRequestBody is a struct for sending a log update to the dragondrop API.
@@ -50,6 +53,11 @@ func (c *HTTPDragonDropClient) InformComplete(ctx context.Context) error {
 	return c.postJobStatus(ctx, "Complete")
 }
 
+// InformFailed Informs to DragonDropAPI when job has Failed, along with the machine-readable error code
+func (c *HTTPDragonDropClient) InformFailed(ctx context.Context, errorCode string) error {
+	return c.postJobStatusWithErrorCode(ctx, "Failed", errorCode)
+}
+
 // InformRepositoryCloned Informs to DragonDropAPI when job cloned the repository
 func (c *HTTPDragonDropClient) InformRepositoryCloned(ctx context.Context) error {
 	return c.postJobStatus(ctx, "Pulled Repository from VCS")
@@ -87,6 +95,11 @@ func (c *HTTPDragonDropClient) InformNoResourcesFound(ctx context.Context) error
 
 // postJobStatus sends a job status to the dragondrop API.
 func (c *HTTPDragonDropClient) postJobStatus(ctx context.Context, status string) error {
+	return c.postJobStatusWithErrorCode(ctx, status, "")
+}
+
+// postJobStatusWithErrorCode sends a job status, and the error code of a failed job, to the dragondrop API.
+func (c *HTTPDragonDropClient) postJobStatusWithErrorCode(ctx context.Context, status string, errorCode string) error {
 	if c.config.JobID == "empty" || c.config.JobID == "" {
 		return nil
 	}
@@ -95,8 +108,9 @@ func (c *HTTPDragonDropClient) postJobStatus(ctx context.Context, status string)
 
 	// Building Post Request Body
 	jsonBody, err := json.Marshal(&JobStatusPostBody{
-		JobID:  c.config.JobID,
-		Status: status,
+		JobID:     c.config.JobID,
+		Status:    status,
+		ErrorCode: errorCode,
 	})
 
 	if err != nil {
@@ -194,17 +208,23 @@ func (c *HTTPDragonDropClient) postLog(ctx context.Context, log string, isAlert
 	return nil
 }
 
-// PutJobPullRequestURL sends the job url to the dragondrop API
-func (c *HTTPDragonDropClient) PutJobPullRequestURL(ctx context.Context, prURL string) error {
+// PutJobPullRequestURLs sends the urls of the pull requests opened by the job to the dragondrop API
+func (c *HTTPDragonDropClient) PutJobPullRequestURLs(ctx context.Context, prURLs []string) error {
 	if c.config.JobID == "empty" || c.config.JobID == "" {
 		return nil
 	}
 
+	prURL := ""
+	if len(prURLs) > 0 {
+		prURL = prURLs[0]
+	}
+
 	// Building Post Log Request body
 	jsonBody, err := json.Marshal(
 		PutPRURLRequest{
-			JobID: c.config.JobID,
-			PRURL: prURL,
+			JobID:  c.config.JobID,
+			PRURL:  prURL,
+			PRURLs: prURLs,
 		})
 
 	if err != nil {
@@ -220,18 +240,18 @@ func (c *HTTPDragonDropClient) PutJobPullRequestURL(ctx context.Context, prURL s
 	)
 
 	if err != nil {
-		return fmt.Errorf("[PutJobPullRequestURL][error in newRequest: %v]", err)
+		return fmt.Errorf("[PutJobPullRequestURLs][error in newRequest: %v]", err)
 	}
 
 	response, err := c.httpClient.Do(request)
 
 	if err != nil {
-		return fmt.Errorf("[PutJobPullRequestURL][error in http PUT request: %v]", err)
+		return fmt.Errorf("[PutJobPullRequestURLs][error in http PUT request: %v]", err)
 	}
 
 	defer response.Body.Close()
 	if response.StatusCode != 201 {
-		return fmt.Errorf("[PutJobPullRequestURL][ was unsuccessful, with the server returning: %v]", response.StatusCode)
+		return fmt.Errorf("[PutJobPullRequestURLs][ was unsuccessful, with the server returning: %v]", response.StatusCode)
 	}
 	return nil
 }
@@ -44,6 +44,11 @@ func (d *IsolatedDragonDrop) InformComplete(ctx context.Context) error {
 	return nil
 }
 
+// InformFailed Informs to DragonDropAPI when job has Failed
+func (d *IsolatedDragonDrop) InformFailed(ctx context.Context, errorCode string) error {
+	return nil
+}
+
 // InformRepositoryCloned Informs to DragonDropAPI when job cloned the repository
 func (d *IsolatedDragonDrop) InformRepositoryCloned(ctx context.Context) error {
 	return nil
@@ -85,7 +90,7 @@ func (d *IsolatedDragonDrop) PostLogAlert(ctx context.Context, log string) {}
 // PostLog sends log to the dragondrop API.
 func (d *IsolatedDragonDrop) PostLog(ctx context.Context, log string) {}
 
-// PutJobPullRequestURL sends the job url to the dragondrop API
-func (d *IsolatedDragonDrop) PutJobPullRequestURL(ctx context.Context, prURL string) error {
+// PutJobPullRequestURLs sends the urls of the pull requests opened by the job to the dragondrop API
+func (d *IsolatedDragonDrop) PutJobPullRequestURLs(ctx context.Context, prURLs []string) error {
 	return nil
 }
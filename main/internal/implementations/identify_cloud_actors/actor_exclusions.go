@@ -0,0 +1,62 @@
+package identifyCloudActors
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/rulelist"
+)
+
+// builtInActorExclusions match the automation principals acting on behalf of cloud services, whose changes are not
+// attributed to a cloud actor.
+var builtInActorExclusions = []string{
+	// AWS services and service-linked roles, e.g. autoscaling launching instances.
+	"*.amazonaws.com",
+	"AutoScaling",
+	"arn:aws:sts::*:assumed-role/AWSServiceRoleFor*/*",
+	"arn:aws:sts::*:assumed-role/AWSControlTowerExecution/*",
+	"arn:aws:sts::*:assumed-role/stacksets-exec-*/*",
+
+	// Google managed service agents, e.g. the Compute Engine and GKE service agents. Service accounts created within
+	// projects are attributed unless excluded by a user rule such as "*.gserviceaccount.com".
+	"*@cloudservices.gserviceaccount.com",
+	"*@gcp-sa-*.iam.gserviceaccount.com",
+	"*@compute-system.iam.gserviceaccount.com",
+	"*@container-engine-robot.iam.gserviceaccount.com",
+}
+
+// ActorExclusionRule matches principals, or the identities they resolve to, against a wildcard pattern in which *
+// matches any characters. Rules prefixed by ! are negated, attributing the matched principals rather than excluding
+// them.
+type ActorExclusionRule struct {
+	Negated bool
+	Pattern *regexp.Regexp
+}
+
+// ActorExclusionPolicy is an ordered list of rules for automation principals, in which the last matching rule decides
+// whether a principal is excluded from actor attribution.
+type ActorExclusionPolicy []ActorExclusionRule
+
+// ParseActorExclusionPolicy parses the built-in rules followed by the user-provided rules, which are wildcard patterns
+// such as "arn:aws:sts::*:assumed-role/github-actions-deployer/*" or "terraform@*". User rules prefixed by ! override
+// the built-in rules, e.g. "!*@gcp-sa-*.iam.gserviceaccount.com" or "!*" to disable them all.
+func ParseActorExclusionPolicy(rules []string) (ActorExclusionPolicy, error) {
+	parsedRules, err := rulelist.Parse(builtInActorExclusions, rules)
+	if err != nil {
+		return nil, fmt.Errorf("[parse_actor_exclusion_policy]%w", err)
+	}
+
+	policy := make(ActorExclusionPolicy, 0, len(parsedRules))
+	for _, rule := range parsedRules {
+		policy = append(policy, ActorExclusionRule{Negated: rule.Negated, Pattern: rulelist.Wildcard(rule.Spec)})
+	}
+
+	return policy, nil
+}
+
+// IsExcluded returns true if the last rule matching the principal, or the identity it resolves to, excludes it.
+func (p ActorExclusionPolicy) IsExcluded(principal string, identity string) bool {
+	return rulelist.LastMatch(len(p), func(i int) (bool, bool) {
+		return p[i].Pattern.MatchString(principal) || p[i].Pattern.MatchString(identity), p[i].Negated
+	})
+}
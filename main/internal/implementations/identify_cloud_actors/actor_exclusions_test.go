@@ -0,0 +1,81 @@
+package identifyCloudActors
+
+import (
+	"testing"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActorExclusionPolicy_IsExcluded(t *testing.T) {
+	// Given
+	policy, err := ParseActorExclusionPolicy([]string{
+		"arn:aws:sts::*:assumed-role/github-actions-deployer/*",
+		"!break-glass@my-project.iam.gserviceaccount.com",
+	})
+	require.NoError(t, err)
+
+	principals := map[string]bool{
+		"arn:aws:sts::123456789012:assumed-role/AWSServiceRoleForAutoScaling/AutoScaling": true,
+		"autoscaling.amazonaws.com": true,
+		"arn:aws:sts::123456789012:assumed-role/github-actions-deployer/run-42":   true,
+		"service-123@gcp-sa-artifactregistry.iam.gserviceaccount.com":             true,
+		"terraform@my-project.iam.gserviceaccount.com":                            false,
+		"arn:aws:sts::123456789012:assumed-role/AWSReservedSSO_Admin_1a2b3c/jdoe": false,
+		"jane.doe@example.com": false,
+	}
+
+	for principal, expected := range principals {
+		// When
+		isExcluded := policy.IsExcluded(principal, resolveAWSPrincipal(principal))
+
+		// Then
+		assert.Equal(t, expected, isExcluded, principal)
+	}
+}
+
+func TestParseActorExclusionPolicy_DisableBuiltIn(t *testing.T) {
+	// Given
+	rules := []string{"!*"}
+
+	// When
+	policy, err := ParseActorExclusionPolicy(rules)
+
+	// Then
+	require.NoError(t, err)
+	assert.False(t, policy.IsExcluded("service-123@gcp-sa-artifactregistry.iam.gserviceaccount.com", "service-123@gcp-sa-artifactregistry.iam.gserviceaccount.com"))
+}
+
+func TestParseActorExclusionPolicy_ProjectServiceAccounts(t *testing.T) {
+	// Given
+	rules := []string{"*.gserviceaccount.com", "!break-glass@my-project.iam.gserviceaccount.com"}
+
+	// When
+	policy, err := ParseActorExclusionPolicy(rules)
+
+	// Then
+	require.NoError(t, err)
+	assert.True(t, policy.IsExcluded("terraform@my-project.iam.gserviceaccount.com", "terraform@my-project.iam.gserviceaccount.com"))
+	assert.False(t, policy.IsExcluded("break-glass@my-project.iam.gserviceaccount.com", "break-glass@my-project.iam.gserviceaccount.com"))
+}
+
+func TestArchivedEventsToResourceActions_ExcludedActors(t *testing.T) {
+	// Given
+	policy, err := ParseActorExclusionPolicy([]string{"arn:aws:sts::*:assumed-role/deployer/*"})
+	require.NoError(t, err)
+
+	events := []archivedEvent{
+		{EventName: "ModifyInstanceAttribute", EventTime: "2022-06-01T10:00:00Z", UserArn: "arn:aws:sts::123456789012:assumed-role/deployer/ci"},
+		{EventName: "ModifyInstanceAttribute", EventTime: "2021-05-01T10:00:00Z", UserArn: "arn:aws:iam::123456789012:user/john"},
+		{EventName: "CreateInstance", EventTime: "2019-04-01T10:00:00Z", UserArn: "arn:aws:sts::123456789012:assumed-role/deployer/ci"},
+	}
+
+	// When
+	resourceActions := archivedEventsToResourceActions(events, true, nil, policy)
+
+	// Then
+	assert.Equal(t, terraformValueObjects.ResourceActions{
+		Modifier: terraformValueObjects.CloudActorTimeStamp{Actor: "john", Timestamp: "2021-05-01"},
+	}, resourceActions)
+}
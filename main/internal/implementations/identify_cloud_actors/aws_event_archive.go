@@ -0,0 +1,489 @@
+package identifyCloudActors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+const (
+	// EventArchiveCloudTrailLake queries a CloudTrail Lake event data store for events older than the
+	// CloudTrail LookupEvents window.
+	EventArchiveCloudTrailLake = "cloudtrail-lake"
+
+	// EventArchiveAthena queries an Athena table over the S3 bucket of a CloudTrail trail for events older than the
+	// CloudTrail LookupEvents window.
+	EventArchiveAthena = "athena"
+)
+
+// eventArchiveRowLimit is the maximum number of events returned for a single resource by an event archive query.
+const eventArchiveRowLimit = 50
+
+// eventArchivePollInterval is the interval between polls of the status of an event archive query.
+var eventArchivePollInterval = 2 * time.Second
+
+// eventArchiveQueryTimeout is the maximum time waited for an event archive query to complete.
+var eventArchiveQueryTimeout = 10 * time.Minute
+
+// executeAWSCommand runs an AWS CLI command, returning its standard output.
+var executeAWSCommand = executeCommandReturnStdOut
+
+// EventArchiveConfig configures the optional long-lookback search of AWS events, used to identify the actors of
+// resources whose events fall outside the 90-day window of CloudTrail LookupEvents.
+type EventArchiveConfig struct {
+	// Backend is the event archive queried when LookupEvents does not identify a resource's actors, either
+	// "cloudtrail-lake" or "athena". The long-lookback search is disabled when empty.
+	Backend string
+
+	// LookbackDays is the number of days of events searched within the event archive, unbounded when zero.
+	LookbackDays int
+
+	// Region is the region in which the event archive is queried, defaulting to the region of each resource.
+	Region string
+
+	// CloudTrailLakeEventDataStore is the id or ARN of the CloudTrail Lake event data store to query.
+	CloudTrailLakeEventDataStore string
+
+	// AthenaDatabase is the Athena database containing the CloudTrail table.
+	AthenaDatabase string
+
+	// AthenaTable is the Athena table over the S3 bucket of the CloudTrail trail.
+	AthenaTable string
+
+	// AthenaWorkGroup is the Athena work group in which queries are run.
+	AthenaWorkGroup string
+
+	// AthenaOutputLocation is the S3 location to which Athena writes query results, e.g. s3://bucket/prefix/.
+	// Optional when the work group defines an output location.
+	AthenaOutputLocation string
+}
+
+// enabled returns true if a long-lookback event archive is configured.
+func (c EventArchiveConfig) enabled() bool {
+	return c.Backend != ""
+}
+
+// validate returns an error if the configured event archive is not supported or is missing required settings.
+func (c EventArchiveConfig) validate() error {
+	switch c.Backend {
+	case "":
+	case EventArchiveCloudTrailLake:
+		if c.CloudTrailLakeEventDataStore == "" {
+			return fmt.Errorf("[event archive %v requires an event data store]", c.Backend)
+		}
+	case EventArchiveAthena:
+		if c.AthenaDatabase == "" || c.AthenaTable == "" {
+			return fmt.Errorf("[event archive %v requires a database and table]", c.Backend)
+		}
+	default:
+		return fmt.Errorf("[event archive %v is not supported]", c.Backend)
+	}
+
+	if c.LookbackDays < 0 {
+		return fmt.Errorf("[event archive lookback days must not be negative, got %d]", c.LookbackDays)
+	}
+	return nil
+}
+
+// archivedEvent is a single event returned from an event archive query.
+type archivedEvent struct {
+	EventName string
+	EventTime string
+	UserName  string
+	UserArn   string
+}
+
+// principal returns the ARN of the identity behind the event, falling back to its user name.
+func (e archivedEvent) principal() string {
+	if e.UserArn != "" {
+		return e.UserArn
+	}
+	return e.UserName
+}
+
+// eventArchiveSearch searches the configured event archive for the events of resourceID, filling the actors of
+// resourceActions that LookupEvents did not identify.
+func (alc *AWSLogQuerier) eventArchiveSearch(
+	ctx context.Context,
+	resourceID string,
+	resourceRegion string,
+	isNewToTerraform bool,
+	resourceActions terraformValueObjects.ResourceActions,
+) (terraformValueObjects.ResourceActions, error) {
+	region := alc.eventArchive.Region
+	if region == "" {
+		region = resourceRegion
+	}
+
+	var since time.Time
+	if alc.eventArchive.LookbackDays > 0 {
+		since = time.Now().UTC().AddDate(0, 0, -alc.eventArchive.LookbackDays)
+	}
+
+	var events []archivedEvent
+	var err error
+	switch alc.eventArchive.Backend {
+	case EventArchiveCloudTrailLake:
+		events, err = alc.queryCloudTrailLake(ctx, region, cloudTrailLakeQuery(alc.eventArchive.CloudTrailLakeEventDataStore, resourceID, since))
+	case EventArchiveAthena:
+		events, err = alc.queryAthena(ctx, region, athenaQuery(alc.eventArchive.AthenaTable, resourceID, since))
+	}
+	if err != nil {
+		return resourceActions, fmt.Errorf("[%v]%w", alc.eventArchive.Backend, err)
+	}
+
+	archiveActions := archivedEventsToResourceActions(events, isNewToTerraform, alc.identities, alc.actorExclusions)
+	if resourceActions.Creator.Actor == "" {
+		resourceActions.Creator = archiveActions.Creator
+	}
+	if resourceActions.Modifier.Actor == "" {
+		resourceActions.Modifier = archiveActions.Modifier
+	}
+
+	if resourceActions.Creator.Actor == "" && resourceActions.Modifier.Actor == "" {
+		return resourceActions, ErrNoCloudTrailEvents
+	}
+	return resourceActions, nil
+}
+
+// archivedEventsToResourceActions identifies the creator and most recent modifier of a resource from its events,
+// ordered from most to least recent. As with LookupEvents, the creator is only identified for resources new to
+// Terraform. The principal behind each event is resolved to a human identity by identities, and events of excluded
+// automation principals are not attributed.
+func archivedEventsToResourceActions(
+	events []archivedEvent,
+	isNewToTerraform bool,
+	identities *IdentityResolver,
+	actorExclusions ActorExclusionPolicy,
+) terraformValueObjects.ResourceActions {
+	resourceActions := terraformValueObjects.ResourceActions{}
+
+	for _, event := range events {
+		principal := event.principal()
+		actor := identities.Resolve(principal)
+		isExcluded := actorExclusions.IsExcluded(principal, actor)
+		actorTimestamp := terraformValueObjects.CloudActorTimeStamp{
+			Actor:     terraformValueObjects.CloudActor(actor),
+			Timestamp: terraformValueObjects.Timestamp(archivedEventDate(event.EventTime)),
+		}
+
+		switch determineActionClass(event.EventName) {
+		case "creation":
+			if isNewToTerraform {
+				if !isExcluded {
+					resourceActions.Creator = actorTimestamp
+				}
+				return resourceActions
+			}
+		case "modification":
+			if resourceActions.Modifier.Actor == "" && !isExcluded {
+				resourceActions.Modifier = actorTimestamp
+				if !isNewToTerraform {
+					return resourceActions
+				}
+			}
+		}
+	}
+
+	return resourceActions
+}
+
+// archivedEventDate formats the event time of an archived event, e.g. "2021-03-04 10:11:12.000" for CloudTrail Lake
+// or "2021-03-04T10:11:12Z" for Athena, as a date.
+func archivedEventDate(eventTime string) string {
+	if len(eventTime) < len("2006-01-02") {
+		return eventTime
+	}
+	return eventTime[:len("2006-01-02")]
+}
+
+// sqlLikeEscape is the escape character of the SQL LIKE patterns, declared by an ESCAPE '\' clause.
+const sqlLikeEscape = `\`
+
+// sqlLikeValue escapes value for use within a quoted SQL LIKE pattern with an ESCAPE '\' clause, so that the _ and %
+// wildcards within resource ids, e.g. of IAM policies or S3 objects, are matched literally.
+func sqlLikeValue(value string) string {
+	return strings.NewReplacer(
+		sqlLikeEscape, sqlLikeEscape+sqlLikeEscape,
+		"_", sqlLikeEscape+"_",
+		"%", sqlLikeEscape+"%",
+		"'", "''",
+	).Replace(value)
+}
+
+// cloudTrailLakeQuery returns the CloudTrail Lake query for the write events of resourceID since the passed time,
+// from most to least recent.
+func cloudTrailLakeQuery(eventDataStore string, resourceID string, since time.Time) string {
+	eventDataStoreID := eventDataStore[strings.LastIndex(eventDataStore, "/")+1:]
+
+	conditions := []string{
+		"readOnly = false",
+		fmt.Sprintf("element_at(resources, 1).arn LIKE '%%%v%%' ESCAPE '\\'", sqlLikeValue(resourceID)),
+	}
+	if !since.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("eventTime > '%v'", since.Format("2006-01-02 15:04:05")))
+	}
+
+	return fmt.Sprintf(
+		"SELECT eventName, eventTime, userIdentity.username AS userName, userIdentity.arn AS userArn FROM %v WHERE %v ORDER BY eventTime DESC LIMIT %d",
+		eventDataStoreID,
+		strings.Join(conditions, " AND "),
+		eventArchiveRowLimit,
+	)
+}
+
+// athenaQuery returns the Athena query over a CloudTrail table for the write events of resourceID since the passed
+// time, from most to least recent.
+func athenaQuery(table string, resourceID string, since time.Time) string {
+	likeResourceID := sqlLikeValue(resourceID)
+
+	conditions := []string{
+		"readonly = 'false'",
+		fmt.Sprintf(
+			"(requestparameters LIKE '%%%[1]v%%' ESCAPE '\\' OR responseelements LIKE '%%%[1]v%%' ESCAPE '\\' OR cardinality(filter(resources, r -> r.arn LIKE '%%%[1]v%%' ESCAPE '\\')) > 0)",
+			likeResourceID,
+		),
+	}
+	if !since.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("eventtime > '%v'", since.Format("2006-01-02T15:04:05Z")))
+	}
+
+	return fmt.Sprintf(
+		"SELECT eventname, eventtime, useridentity.username, useridentity.arn FROM %v WHERE %v ORDER BY eventtime DESC LIMIT %d",
+		table,
+		strings.Join(conditions, " AND "),
+		eventArchiveRowLimit,
+	)
+}
+
+// cloudTrailLakeQueryResults is the output of `aws cloudtrail get-query-results`.
+type cloudTrailLakeQueryResults struct {
+	QueryStatus     string                `json:"QueryStatus"`
+	ErrorMessage    string                `json:"ErrorMessage"`
+	QueryResultRows [][]map[string]string `json:"QueryResultRows"`
+}
+
+// queryCloudTrailLake runs query against CloudTrail Lake, waiting for its completion, and returns the resulting
+// events.
+func (alc *AWSLogQuerier) queryCloudTrailLake(ctx context.Context, region string, query string) ([]archivedEvent, error) {
+	var startOutput string
+	err := alc.limiter.Do(ctx, func() error {
+		var commandErr error
+		startOutput, commandErr = executeAWSCommand("aws", "cloudtrail", "start-query", "--region", region, "--output", "json", "--query-statement", query)
+		return commandErr
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[start-query]%w", err)
+	}
+
+	started := struct {
+		QueryID string `json:"QueryId"`
+	}{}
+	err = json.Unmarshal([]byte(startOutput), &started)
+	if err != nil {
+		return nil, fmt.Errorf("[start-query][json.Unmarshal]%w", err)
+	}
+
+	results := cloudTrailLakeQueryResults{}
+	err = pollEventArchiveQuery(ctx, func() (bool, error) {
+		var resultsOutput string
+		commandErr := alc.limiter.Do(ctx, func() error {
+			var err error
+			resultsOutput, err = executeAWSCommand("aws", "cloudtrail", "get-query-results", "--region", region, "--output", "json", "--query-id", started.QueryID)
+			return err
+		})
+		if commandErr != nil {
+			return false, fmt.Errorf("[get-query-results]%w", commandErr)
+		}
+
+		results = cloudTrailLakeQueryResults{}
+		commandErr = json.Unmarshal([]byte(resultsOutput), &results)
+		if commandErr != nil {
+			return false, fmt.Errorf("[get-query-results][json.Unmarshal]%w", commandErr)
+		}
+
+		switch results.QueryStatus {
+		case "FINISHED":
+			return true, nil
+		case "FAILED", "CANCELLED", "TIMED_OUT":
+			return false, fmt.Errorf("[query %v %v]%v", started.QueryID, results.QueryStatus, results.ErrorMessage)
+		}
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCloudTrailLakeRows(results.QueryResultRows), nil
+}
+
+// parseCloudTrailLakeRows converts CloudTrail Lake result rows, each a list of single column objects, into events.
+func parseCloudTrailLakeRows(rows [][]map[string]string) []archivedEvent {
+	events := []archivedEvent{}
+	for _, row := range rows {
+		columns := map[string]string{}
+		for _, column := range row {
+			for name, value := range column {
+				columns[name] = value
+			}
+		}
+
+		events = append(events, archivedEvent{
+			EventName: columns["eventName"],
+			EventTime: columns["eventTime"],
+			UserName:  columns["userName"],
+			UserArn:   columns["userArn"],
+		})
+	}
+	return events
+}
+
+// athenaQueryExecution is the output of `aws athena get-query-execution`.
+type athenaQueryExecution struct {
+	QueryExecution struct {
+		Status struct {
+			State             string `json:"State"`
+			StateChangeReason string `json:"StateChangeReason"`
+		} `json:"Status"`
+	} `json:"QueryExecution"`
+}
+
+// athenaQueryResults is the output of `aws athena get-query-results`.
+type athenaQueryResults struct {
+	ResultSet struct {
+		Rows []struct {
+			Data []struct {
+				VarCharValue string `json:"VarCharValue"`
+			} `json:"Data"`
+		} `json:"Rows"`
+	} `json:"ResultSet"`
+}
+
+// queryAthena runs query against the configured Athena database, waiting for its completion, and returns the
+// resulting events.
+func (alc *AWSLogQuerier) queryAthena(ctx context.Context, region string, query string) ([]archivedEvent, error) {
+	startArgs := []string{
+		"athena", "start-query-execution", "--region", region, "--output", "json",
+		"--query-string", query,
+		"--query-execution-context", fmt.Sprintf("Database=%v", alc.eventArchive.AthenaDatabase),
+	}
+	if alc.eventArchive.AthenaWorkGroup != "" {
+		startArgs = append(startArgs, "--work-group", alc.eventArchive.AthenaWorkGroup)
+	}
+	if alc.eventArchive.AthenaOutputLocation != "" {
+		startArgs = append(startArgs, "--result-configuration", fmt.Sprintf("OutputLocation=%v", alc.eventArchive.AthenaOutputLocation))
+	}
+
+	var startOutput string
+	err := alc.limiter.Do(ctx, func() error {
+		var commandErr error
+		startOutput, commandErr = executeAWSCommand("aws", startArgs...)
+		return commandErr
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[start-query-execution]%w", err)
+	}
+
+	started := struct {
+		QueryExecutionID string `json:"QueryExecutionId"`
+	}{}
+	err = json.Unmarshal([]byte(startOutput), &started)
+	if err != nil {
+		return nil, fmt.Errorf("[start-query-execution][json.Unmarshal]%w", err)
+	}
+
+	err = pollEventArchiveQuery(ctx, func() (bool, error) {
+		var executionOutput string
+		commandErr := alc.limiter.Do(ctx, func() error {
+			var err error
+			executionOutput, err = executeAWSCommand("aws", "athena", "get-query-execution", "--region", region, "--output", "json", "--query-execution-id", started.QueryExecutionID)
+			return err
+		})
+		if commandErr != nil {
+			return false, fmt.Errorf("[get-query-execution]%w", commandErr)
+		}
+
+		execution := athenaQueryExecution{}
+		commandErr = json.Unmarshal([]byte(executionOutput), &execution)
+		if commandErr != nil {
+			return false, fmt.Errorf("[get-query-execution][json.Unmarshal]%w", commandErr)
+		}
+
+		switch execution.QueryExecution.Status.State {
+		case "SUCCEEDED":
+			return true, nil
+		case "FAILED", "CANCELLED":
+			return false, fmt.Errorf(
+				"[query %v %v]%v", started.QueryExecutionID, execution.QueryExecution.Status.State, execution.QueryExecution.Status.StateChangeReason,
+			)
+		}
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var resultsOutput string
+	err = alc.limiter.Do(ctx, func() error {
+		var commandErr error
+		resultsOutput, commandErr = executeAWSCommand("aws", "athena", "get-query-results", "--region", region, "--output", "json", "--query-execution-id", started.QueryExecutionID)
+		return commandErr
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[get-query-results]%w", err)
+	}
+
+	return parseAthenaResults([]byte(resultsOutput))
+}
+
+// parseAthenaResults converts Athena query results, whose first row is the header, into events.
+func parseAthenaResults(content []byte) ([]archivedEvent, error) {
+	results := athenaQueryResults{}
+	err := json.Unmarshal(content, &results)
+	if err != nil {
+		return nil, fmt.Errorf("[parse_athena_results][json.Unmarshal]%w", err)
+	}
+
+	events := []archivedEvent{}
+	for index, row := range results.ResultSet.Rows {
+		if index == 0 || len(row.Data) < 4 {
+			continue
+		}
+
+		events = append(events, archivedEvent{
+			EventName: row.Data[0].VarCharValue,
+			EventTime: row.Data[1].VarCharValue,
+			UserName:  row.Data[2].VarCharValue,
+			UserArn:   row.Data[3].VarCharValue,
+		})
+	}
+	return events, nil
+}
+
+// pollEventArchiveQuery calls poll every eventArchivePollInterval until it reports the query complete, returns an
+// error, or eventArchiveQueryTimeout elapses.
+func pollEventArchiveQuery(ctx context.Context, poll func() (bool, error)) error {
+	deadline := time.Now().Add(eventArchiveQueryTimeout)
+	for {
+		complete, err := poll()
+		if err != nil {
+			return err
+		}
+		if complete {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("[query did not complete within %v]", eventArchiveQueryTimeout)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(eventArchivePollInterval):
+		}
+	}
+}
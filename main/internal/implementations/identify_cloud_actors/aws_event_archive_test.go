@@ -0,0 +1,166 @@
+package identifyCloudActors
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	queryParamData "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/identify_cloud_actors/query_param_data"
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventArchiveConfig_Validate(t *testing.T) {
+	// Given
+	cases := map[string]struct {
+		config  EventArchiveConfig
+		isValid bool
+	}{
+		"disabled":               {config: EventArchiveConfig{}, isValid: true},
+		"cloudtrail lake":        {config: EventArchiveConfig{Backend: EventArchiveCloudTrailLake, CloudTrailLakeEventDataStore: "eds"}, isValid: true},
+		"cloudtrail lake no eds": {config: EventArchiveConfig{Backend: EventArchiveCloudTrailLake}, isValid: false},
+		"athena":                 {config: EventArchiveConfig{Backend: EventArchiveAthena, AthenaDatabase: "db", AthenaTable: "trail"}, isValid: true},
+		"athena no table":        {config: EventArchiveConfig{Backend: EventArchiveAthena, AthenaDatabase: "db"}, isValid: false},
+		"unsupported":            {config: EventArchiveConfig{Backend: "bigquery"}, isValid: false},
+		"negative lookback":      {config: EventArchiveConfig{LookbackDays: -1}, isValid: false},
+	}
+
+	for name, testCase := range cases {
+		// When
+		err := testCase.config.validate()
+
+		// Then
+		assert.Equal(t, testCase.isValid, err == nil, name)
+	}
+}
+
+func TestCloudTrailLakeQuery(t *testing.T) {
+	// Given
+	eventDataStore := "arn:aws:cloudtrail:us-east-1:123456789012:eventdatastore/eds-id"
+	since := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	// When
+	query := cloudTrailLakeQuery(eventDataStore, "bucket'name_100%", since)
+
+	// Then
+	assert.Equal(
+		t,
+		"SELECT eventName, eventTime, userIdentity.username AS userName, userIdentity.arn AS userArn FROM eds-id WHERE readOnly = false AND element_at(resources, 1).arn LIKE '%bucket''name\\_100\\%%' ESCAPE '\\' AND eventTime > '2020-01-02 03:04:05' ORDER BY eventTime DESC LIMIT 50",
+		query,
+	)
+}
+
+func TestAthenaQuery(t *testing.T) {
+	// Given
+	table := "cloudtrail_logs"
+
+	// When
+	query := athenaQuery(table, "i-0123", time.Time{})
+
+	// Then
+	assert.Equal(
+		t,
+		"SELECT eventname, eventtime, useridentity.username, useridentity.arn FROM cloudtrail_logs WHERE readonly = 'false' AND (requestparameters LIKE '%i-0123%' ESCAPE '\\' OR responseelements LIKE '%i-0123%' ESCAPE '\\' OR cardinality(filter(resources, r -> r.arn LIKE '%i-0123%' ESCAPE '\\')) > 0) ORDER BY eventtime DESC LIMIT 50",
+		query,
+	)
+}
+
+func TestArchivedEventsToResourceActions(t *testing.T) {
+	// Given
+	events := []archivedEvent{
+		{EventName: "PutBucketTagging", EventTime: "2022-06-01 10:00:00.000", UserArn: "arn:aws:sts::123456789012:assumed-role/Admin/jane"},
+		{EventName: "ModifyBucket", EventTime: "2021-05-01 10:00:00.000", UserName: "john"},
+		{EventName: "CreateBucket", EventTime: "2019-04-01 10:00:00.000", UserName: "joan"},
+	}
+
+	// When
+	newResourceActions := archivedEventsToResourceActions(events, true, nil, nil)
+	driftedResourceActions := archivedEventsToResourceActions(events, false, nil, nil)
+
+	// Then
+	assert.Equal(t, terraformValueObjects.ResourceActions{
+		Creator:  terraformValueObjects.CloudActorTimeStamp{Actor: "joan", Timestamp: "2019-04-01"},
+		Modifier: terraformValueObjects.CloudActorTimeStamp{Actor: "john", Timestamp: "2021-05-01"},
+	}, newResourceActions)
+	assert.Equal(t, terraformValueObjects.ResourceActions{
+		Modifier: terraformValueObjects.CloudActorTimeStamp{Actor: "john", Timestamp: "2021-05-01"},
+	}, driftedResourceActions)
+}
+
+func TestParseAthenaResults(t *testing.T) {
+	// Given
+	content := []byte(`{"ResultSet": {"Rows": [
+		{"Data": [{"VarCharValue": "eventname"}, {"VarCharValue": "eventtime"}, {"VarCharValue": "username"}, {"VarCharValue": "arn"}]},
+		{"Data": [{"VarCharValue": "RunInstances"}, {"VarCharValue": "2019-04-01T10:00:00Z"}, {}, {"VarCharValue": "arn:aws:sts::123456789012:assumed-role/Admin/jane"}]}
+	]}}`)
+
+	// When
+	events, err := parseAthenaResults(content)
+
+	// Then
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "RunInstances", events[0].EventName)
+	assert.Equal(t, "arn:aws:sts::123456789012:assumed-role/Admin/jane", events[0].principal())
+}
+
+func TestAWSLogQuerier_CloudTrailEventHistorySearchFallsBackToCloudTrailLake(t *testing.T) {
+	// Given
+	originalExecuteAWSCommand := executeAWSCommand
+	defer func() { executeAWSCommand = originalExecuteAWSCommand }()
+
+	getQueryResultsCalls := 0
+	executeAWSCommand = func(command string, args ...string) (string, error) {
+		switch strings.Join(args[:2], " ") {
+		case "cloudtrail lookup-events":
+			return `{"Events": []}`, nil
+		case "cloudtrail start-query":
+			return `{"QueryId": "query-id"}`, nil
+		case "cloudtrail get-query-results":
+			getQueryResultsCalls++
+			if getQueryResultsCalls == 1 {
+				return `{"QueryStatus": "RUNNING"}`, nil
+			}
+			return `{"QueryStatus": "FINISHED", "QueryResultRows": [
+				[{"eventName": "CreateBucket"}, {"eventTime": "2019-04-01 10:00:00.000"}, {"userName": "joan"}, {"userArn": "arn:aws:iam::123456789012:user/joan"}]
+			]}`, nil
+		}
+		return "", nil
+	}
+
+	originalPollInterval := eventArchivePollInterval
+	defer func() { eventArchivePollInterval = originalPollInterval }()
+	eventArchivePollInterval = time.Millisecond
+
+	alc := AWSLogQuerier{
+		eventArchive:             EventArchiveConfig{Backend: EventArchiveCloudTrailLake, CloudTrailLakeEventDataStore: "eds-id"},
+		resourceToCloudTrailType: queryParamData.NewAWSResourceToCloudTrailLookup(),
+	}
+
+	// When
+	resourceActions, err := alc.cloudTrailEventHistorySearch(context.Background(), "aws-prod", "aws_s3_bucket", "bucket", "us-east-1", true)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, 2, getQueryResultsCalls)
+	assert.Equal(t, terraformValueObjects.CloudActorTimeStamp{Actor: "joan", Timestamp: "2019-04-01"}, resourceActions.Creator)
+}
+
+func TestAWSLogQuerier_CloudTrailEventHistorySearchWithoutEventArchive(t *testing.T) {
+	// Given
+	originalExecuteAWSCommand := executeAWSCommand
+	defer func() { executeAWSCommand = originalExecuteAWSCommand }()
+	executeAWSCommand = func(command string, args ...string) (string, error) {
+		return `{"Events": []}`, nil
+	}
+
+	alc := AWSLogQuerier{resourceToCloudTrailType: queryParamData.NewAWSResourceToCloudTrailLookup()}
+
+	// When
+	_, err := alc.cloudTrailEventHistorySearch(context.Background(), "aws-prod", "aws_s3_bucket", "bucket", "us-east-1", true)
+
+	// Then
+	assert.Equal(t, ErrNoCloudTrailEvents, err)
+}
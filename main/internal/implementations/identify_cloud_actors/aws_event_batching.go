@@ -0,0 +1,214 @@
+package identifyCloudActors
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+	log "github.com/sirupsen/logrus"
+)
+
+// EventBatchingConfig configures the batching of CloudTrail lookups, in which the write events of each region are
+// fetched once and indexed by resource, rather than looked up resource by resource.
+type EventBatchingConfig struct {
+	// Enabled flags that CloudTrail events are fetched once per region of each division.
+	Enabled bool
+
+	// CacheDirectory is the directory, typically a mounted volume, in which the fetched events are cached between runs,
+	// so that each run only fetches the events since the previous one. Caching is disabled when empty.
+	CacheDirectory string
+}
+
+// regionEvents are the write events of a single region of a division, indexed by resource.
+type regionEvents struct {
+	// LatestEventTime is the time, in seconds since the epoch, of the most recent event fetched, from which the next
+	// run fetches events.
+	LatestEventTime float64 `json:"latest_event_time"`
+
+	// ResourceEvents are the events of each resource, keyed by resource id or ARN, from most to least recent.
+	ResourceEvents map[string][]CloudTrailEvent `json:"resource_events"`
+}
+
+// resourceEvents returns the events of resourceID within the region of the division, fetching and indexing the
+// write events of the region on first use.
+func (alc *AWSLogQuerier) resourceEvents(ctx context.Context, division terraformValueObjects.Division, region string, resourceID string) ([]CloudTrailEvent, error) {
+	key := fmt.Sprintf("%v/%v", division, region)
+	if alc.regionEvents == nil {
+		alc.regionEvents = map[string]*regionEvents{}
+	}
+
+	events, ok := alc.regionEvents[key]
+	if !ok {
+		var err error
+		events, err = alc.fetchRegionEvents(ctx, division, region)
+		if err != nil {
+			return nil, fmt.Errorf("[fetch_region_events %v]%w", key, err)
+		}
+		alc.regionEvents[key] = events
+	}
+
+	return events.ResourceEvents[resourceID], nil
+}
+
+// fetchRegionEvents fetches the write events of region, merged into those cached by previous runs, of which only the
+// events since the most recent cached event are fetched.
+func (alc *AWSLogQuerier) fetchRegionEvents(ctx context.Context, division terraformValueObjects.Division, region string) (*regionEvents, error) {
+	cachePath := alc.regionEventsCachePath(division, region)
+	events, err := readRegionEvents(cachePath)
+	if err != nil {
+		return nil, err
+	}
+
+	lookupCommand := []string{
+		"cloudtrail", "lookup-events", "--output", "json", "--region", region,
+		"--lookup-attributes", "AttributeKey=ReadOnly,AttributeValue=false",
+	}
+	if events.LatestEventTime > 0 {
+		startTime := time.Unix(int64(events.LatestEventTime), 0).UTC().Format(time.RFC3339)
+		lookupCommand = append(lookupCommand, "--start-time", startTime)
+	}
+
+	var result string
+	err = alc.limiter.Do(ctx, func() error {
+		var commandErr error
+		result, commandErr = executeAWSCommand("aws", lookupCommand...)
+		return commandErr
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[executeAWSCommand]%w", err)
+	}
+
+	var cloudTrailEvents CloudTrailEvents
+	err = json.Unmarshal([]byte(result), &cloudTrailEvents)
+	if err != nil {
+		return nil, fmt.Errorf("[json.Unmarshal]%w", err)
+	}
+
+	events.merge(cloudTrailEvents.Events)
+
+	if cachePath != "" {
+		err = writeRegionEvents(cachePath, events)
+		if err != nil {
+			log.Warnf("[aws_event_batching] unable to cache the CloudTrail events of %v %v: %v", division, region, err)
+		}
+	}
+	return events, nil
+}
+
+// merge indexes fetched events by each of their resources, ignoring events already indexed.
+func (e *regionEvents) merge(fetched []CloudTrailEvent) {
+	for _, event := range fetched {
+		if event.EventTimeUnformatted > e.LatestEventTime {
+			e.LatestEventTime = event.EventTimeUnformatted
+		}
+
+		event = compactCloudTrailEvent(event)
+		for _, resource := range event.Resources {
+			if resource.ResourceName == "" || containsEvent(e.ResourceEvents[resource.ResourceName], event.EventID) {
+				continue
+			}
+			e.ResourceEvents[resource.ResourceName] = append(e.ResourceEvents[resource.ResourceName], event)
+		}
+	}
+
+	for resourceName, resourceEvents := range e.ResourceEvents {
+		sort.SliceStable(resourceEvents, func(i, j int) bool {
+			return resourceEvents[i].EventTimeUnformatted > resourceEvents[j].EventTimeUnformatted
+		})
+		e.ResourceEvents[resourceName] = resourceEvents
+	}
+}
+
+// compactCloudTrailEvent returns event with its raw CloudTrail event reduced to the identity of its principal, which
+// is all that is needed for actor attribution.
+func compactCloudTrailEvent(event CloudTrailEvent) CloudTrailEvent {
+	principal := event.principal()
+	if principal == event.UserName {
+		event.CloudTrailEvent = ""
+		return event
+	}
+
+	rawEvent, _ := json.Marshal(map[string]interface{}{"userIdentity": map[string]string{"arn": principal}})
+	event.CloudTrailEvent = string(rawEvent)
+	return event
+}
+
+// containsEvent returns true if events contains the event with eventID.
+func containsEvent(events []CloudTrailEvent, eventID string) bool {
+	for _, event := range events {
+		if event.EventID == eventID {
+			return true
+		}
+	}
+	return false
+}
+
+// regionEventsCachePath returns the path of the cached events of region within division, or an empty path when
+// caching is disabled.
+func (alc *AWSLogQuerier) regionEventsCachePath(division terraformValueObjects.Division, region string) string {
+	if alc.eventBatching.CacheDirectory == "" {
+		return ""
+	}
+
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%v/%v", division, region)))
+	return filepath.Join(alc.eventBatching.CacheDirectory, "aws", hex.EncodeToString(hash[:])+".json")
+}
+
+// readRegionEvents reads the cached region events at cachePath, returning empty region events when caching is
+// disabled or nothing has been cached yet.
+func readRegionEvents(cachePath string) (*regionEvents, error) {
+	events := &regionEvents{ResourceEvents: map[string][]CloudTrailEvent{}}
+	if cachePath == "" {
+		return events, nil
+	}
+
+	content, err := os.ReadFile(cachePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return events, nil
+		}
+		return nil, fmt.Errorf("[read_region_events][os.ReadFile %v]%w", cachePath, err)
+	}
+
+	err = json.Unmarshal(content, events)
+	if err != nil {
+		log.Warnf("[aws_event_batching] ignoring the unreadable cached CloudTrail events at %v: %v", cachePath, err)
+		return &regionEvents{ResourceEvents: map[string][]CloudTrailEvent{}}, nil
+	}
+	if events.ResourceEvents == nil {
+		events.ResourceEvents = map[string][]CloudTrailEvent{}
+	}
+
+	// LookupEvents only returns the last 90 days of events, so a watermark older than that fetches all of them.
+	if time.Since(time.Unix(int64(events.LatestEventTime), 0)) > 90*24*time.Hour {
+		events.LatestEventTime = 0
+	}
+	return events, nil
+}
+
+// writeRegionEvents writes the region events to cachePath.
+func writeRegionEvents(cachePath string, events *regionEvents) error {
+	err := os.MkdirAll(filepath.Dir(cachePath), 0700)
+	if err != nil {
+		return fmt.Errorf("[write_region_events][os.MkdirAll]%w", err)
+	}
+
+	content, err := json.Marshal(events)
+	if err != nil {
+		return fmt.Errorf("[write_region_events][json.Marshal]%w", err)
+	}
+
+	err = os.WriteFile(cachePath, content, 0600)
+	if err != nil {
+		return fmt.Errorf("[write_region_events][os.WriteFile %v]%w", cachePath, err)
+	}
+	return nil
+}
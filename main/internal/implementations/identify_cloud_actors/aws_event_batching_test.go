@@ -0,0 +1,100 @@
+package identifyCloudActors
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	queryParamData "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/identify_cloud_actors/query_param_data"
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bucketEvent returns a lookup-events event on a bucket.
+func bucketEvent(eventID string, eventName string, eventTime time.Time, bucket string, userName string) string {
+	return fmt.Sprintf(`{
+		"EventId": %q,
+		"EventName": %q,
+		"EventTime": %d,
+		"Username": %q,
+		"Resources": [{"ResourceType": "AWS::S3::Bucket", "ResourceName": %q}],
+		"CloudTrailEvent": "{\"userIdentity\":{\"arn\":\"arn:aws:iam::123456789012:user/%v\"}}"
+	}`, eventID, eventName, eventTime.Unix(), userName, bucket, userName)
+}
+
+func TestAWSLogQuerier_CloudTrailEventHistorySearchBatched(t *testing.T) {
+	// Given
+	originalExecuteAWSCommand := executeAWSCommand
+	defer func() { executeAWSCommand = originalExecuteAWSCommand }()
+
+	createdAt := time.Now().Add(-48 * time.Hour)
+	lookups := []string{}
+	executeAWSCommand = func(command string, args ...string) (string, error) {
+		lookups = append(lookups, strings.Join(args, " "))
+		return fmt.Sprintf(`{"Events": [%v, %v]}`,
+			bucketEvent("2", "CreateBucket", createdAt, "logs", "john"),
+			bucketEvent("1", "CreateBucket", createdAt, "assets", "joan"),
+		), nil
+	}
+
+	alc := AWSLogQuerier{
+		eventBatching:            EventBatchingConfig{Enabled: true},
+		resourceToCloudTrailType: queryParamData.NewAWSResourceToCloudTrailLookup(),
+	}
+
+	// When
+	logsActions, logsErr := alc.cloudTrailEventHistorySearch(context.Background(), "aws-prod", "aws_s3_bucket", "logs", "us-east-1", true)
+	assetsActions, assetsErr := alc.cloudTrailEventHistorySearch(context.Background(), "aws-prod", "aws_s3_bucket", "assets", "us-east-1", true)
+	_, missingErr := alc.cloudTrailEventHistorySearch(context.Background(), "aws-prod", "aws_s3_bucket", "missing", "us-east-1", true)
+
+	// Then
+	require.NoError(t, logsErr)
+	require.NoError(t, assetsErr)
+	assert.Equal(t, ErrNoCloudTrailEvents, missingErr)
+	assert.Len(t, lookups, 1)
+	assert.Contains(t, lookups[0], "AttributeKey=ReadOnly,AttributeValue=false")
+	assert.Equal(t, terraformValueObjects.CloudActor("john"), logsActions.Creator.Actor)
+	assert.Equal(t, terraformValueObjects.CloudActor("joan"), assetsActions.Creator.Actor)
+}
+
+func TestAWSLogQuerier_CloudTrailEventHistorySearchBatchedCache(t *testing.T) {
+	// Given
+	originalExecuteAWSCommand := executeAWSCommand
+	defer func() { executeAWSCommand = originalExecuteAWSCommand }()
+
+	cacheDirectory := t.TempDir()
+	createdAt := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	modifiedAt := time.Now().Add(-1 * time.Hour).Truncate(time.Second)
+
+	executeAWSCommand = func(command string, args ...string) (string, error) {
+		return fmt.Sprintf(`{"Events": [%v]}`, bucketEvent("1", "CreateBucket", createdAt, "logs", "joan")), nil
+	}
+	firstRun := AWSLogQuerier{
+		eventBatching:            EventBatchingConfig{Enabled: true, CacheDirectory: cacheDirectory},
+		resourceToCloudTrailType: queryParamData.NewAWSResourceToCloudTrailLookup(),
+	}
+	_, err := firstRun.cloudTrailEventHistorySearch(context.Background(), "aws-prod", "aws_s3_bucket", "logs", "us-east-1", true)
+	require.NoError(t, err)
+
+	secondRunLookup := ""
+	executeAWSCommand = func(command string, args ...string) (string, error) {
+		secondRunLookup = strings.Join(args, " ")
+		return fmt.Sprintf(`{"Events": [%v]}`, bucketEvent("2", "ModifyBucketPolicy", modifiedAt, "logs", "john")), nil
+	}
+	secondRun := AWSLogQuerier{
+		eventBatching:            EventBatchingConfig{Enabled: true, CacheDirectory: cacheDirectory},
+		resourceToCloudTrailType: queryParamData.NewAWSResourceToCloudTrailLookup(),
+	}
+
+	// When
+	resourceActions, err := secondRun.cloudTrailEventHistorySearch(context.Background(), "aws-prod", "aws_s3_bucket", "logs", "us-east-1", true)
+
+	// Then
+	require.NoError(t, err)
+	assert.Contains(t, secondRunLookup, "--start-time "+createdAt.UTC().Format(time.RFC3339))
+	assert.Equal(t, terraformValueObjects.CloudActor("joan"), resourceActions.Creator.Actor)
+	assert.Equal(t, terraformValueObjects.CloudActor("john"), resourceActions.Modifier.Actor)
+}
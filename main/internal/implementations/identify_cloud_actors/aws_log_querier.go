@@ -8,15 +8,17 @@ import (
 	"fmt"
 	"math"
 	"net/http"
-	"os"
 	"os/exec"
 	"time"
 
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/awscredentials"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/hclcreate"
 	queryParamData "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/identify_cloud_actors/query_param_data"
 	resourcesCalculator "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/resources_calculator"
 	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/ratelimit"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -31,23 +33,42 @@ type AWSLogQuerier struct {
 	// divisionToNewResources is a map between a division and a list of new resource objects.
 	divisionToNewResources resourcesCalculator.DivisionToNewResources
 
+	// resourceNames are the names of the new resources within the generated code, on which their actions are keyed.
+	resourceNames hclcreate.ResourceNames
+
 	// divisionToUniqueManagedDriftedResources is a map between a division and a list of unique drifted resource objects.
 	divisionToUniqueManagedDriftedResources DivisionToUniqueDriftedResources
 
+	// eventBatching configures the fetching of CloudTrail events once per region, rather than per resource.
+	eventBatching EventBatchingConfig
+
+	// eventArchive configures the long-lookback search of events not returned by CloudTrail LookupEvents.
+	eventArchive EventArchiveConfig
+
 	// httpClient is a http client shared across all http requests within this package.
 	httpClient http.Client
 
+	// actorExclusions are the automation principals whose changes are not attributed to a cloud actor.
+	actorExclusions ActorExclusionPolicy
+
+	// identities resolves the principals recorded by CloudTrail to human identities.
+	identities *IdentityResolver
+
+	// limiter rate limits CloudTrail lookups, nil when rate limiting is disabled.
+	limiter *ratelimit.Limiter
+
 	// managedDriftAttributeDifferences is a list of all attribute differences.
 	managedDriftAttributeDifferences []driftDetector.AttributeDifference
 
+	// regionEvents are the fetched events of each region of each division, keyed by "<division>/<region>", when
+	// batching CloudTrail lookups.
+	regionEvents map[string]*regionEvents
+
 	// resourceToCloudTrailType is a map between a Terraform resource type and the corresponding Cloud Trail event type.
 	resourceToCloudTrailType queryParamData.AWSResourceToCloudTrailResource
-}
 
-// AWSEnvironment is a struct defining the credential values needed for authenticating with an AWS account.
-type AWSEnvironment struct {
-	AWSAccessKeyID     string `json:"awsAccessKeyID"`
-	AWSSecretKeyAccess string `json:"awsSecretAccessKey"`
+	// artifactStore holds the mappings of the upstream stages and of the identified cloud actors.
+	artifactStore artifacts.Store
 }
 
 // CloudTrailEvents is a struct containing all the data returned from the AWS CLI command
@@ -65,6 +86,21 @@ type CloudTrailEvent struct {
 	EventTime            string
 	UserName             string               `json:"Username"`
 	Resources            []CloudTrailResource `json:"Resources"`
+	CloudTrailEvent      string               `json:"CloudTrailEvent"`
+}
+
+// principal returns the ARN of the identity behind the event, recorded within the raw CloudTrail event, falling back
+// to the event's Username.
+func (e CloudTrailEvent) principal() string {
+	rawEvent := struct {
+		UserIdentity struct {
+			ARN string `json:"arn"`
+		} `json:"userIdentity"`
+	}{}
+	if err := json.Unmarshal([]byte(e.CloudTrailEvent), &rawEvent); err == nil && rawEvent.UserIdentity.ARN != "" {
+		return rawEvent.UserIdentity.ARN
+	}
+	return e.UserName
 }
 
 // CloudTrailResource is a struct for a resource identity within a CloudTrailEvent.
@@ -76,9 +112,26 @@ type CloudTrailResource struct {
 // NewAWSLogQuerier instantiates a new instance of GoogleLogQuerier
 func NewAWSLogQuerier(
 	divisionToCredentials terraformValueObjects.DivisionCloudCredentialDecoder,
+	limiter *ratelimit.Limiter,
+	eventArchive EventArchiveConfig,
+	identities *IdentityResolver,
+	actorExclusions ActorExclusionPolicy,
+	eventBatching EventBatchingConfig,
+	artifactStore artifacts.Store,
 ) (LogQuerier, error) {
+	err := eventArchive.validate()
+	if err != nil {
+		return nil, fmt.Errorf("[eventArchive.validate]%w", err)
+	}
+
 	return &AWSLogQuerier{
+		actorExclusions:          actorExclusions,
+		artifactStore:            artifactStore,
 		divisionToCredentials:    divisionToCredentials,
+		eventArchive:             eventArchive,
+		eventBatching:            eventBatching,
+		identities:               identities,
+		limiter:                  limiter,
 		resourceToCloudTrailType: queryParamData.NewAWSResourceToCloudTrailLookup(),
 	}, nil
 }
@@ -86,15 +139,20 @@ func NewAWSLogQuerier(
 // loadUpstreamDataToAWSLogQuerier loads all data needed for querying logs from upstream
 // saved data sources.
 func (alc *AWSLogQuerier) loadUpstreamDataToAWSLogQuerier() error {
-	attributeDifferences, err := loadDriftResourcesDifferences()
+	attributeDifferences, err := loadDriftResourcesDifferences(alc.artifactStore)
 	if err != nil {
 		return fmt.Errorf("[loadDriftResourcesDifferences]%v", err)
 	}
 
-	divToNewResources, err := loadDivisionToNewResources()
+	divToNewResources, err := loadDivisionToNewResources(alc.artifactStore)
 	if err != nil {
 		return fmt.Errorf("[loadDivisionToNewResources]%v", err)
 	}
+	resourceNames, err := hclcreate.LoadResourceNames(alc.artifactStore)
+	if err != nil {
+		return fmt.Errorf("[hclcreate.LoadResourceNames]%v", err)
+	}
+
 	divToUniqueDriftedResources, err := createDivisionUniqueDriftedResources(attributeDifferences)
 	if err != nil {
 		return fmt.Errorf("[createDivisionUniqueDriftedResources]%v", err)
@@ -102,6 +160,7 @@ func (alc *AWSLogQuerier) loadUpstreamDataToAWSLogQuerier() error {
 
 	alc.divisionToUniqueManagedDriftedResources = divToUniqueDriftedResources
 	alc.divisionToNewResources = divToNewResources
+	alc.resourceNames = resourceNames
 	alc.managedDriftAttributeDifferences = attributeDifferences
 	return nil
 }
@@ -143,7 +202,7 @@ func (alc *AWSLogQuerier) QueryForResourcesInDivision(ctx context.Context, divis
 	currentUniqueDriftedResources, ok := alc.divisionToUniqueManagedDriftedResources[division]
 	if ok {
 		for _, driftedResource := range currentUniqueDriftedResources {
-			resourceActions, err := alc.cloudTrailEventHistorySearch(ctx, driftedResource.ResourceType, driftedResource.InstanceID, driftedResource.Region, false)
+			resourceActions, err := alc.cloudTrailEventHistorySearch(ctx, division, driftedResource.ResourceType, driftedResource.InstanceID, driftedResource.Region, false)
 			if err != nil {
 				if err != ErrNoCloudTrailEvents {
 					return nil, fmt.Errorf("[alc.cloudTrailEventHistorySearch]%v", err)
@@ -164,9 +223,9 @@ func (alc *AWSLogQuerier) QueryForResourcesInDivision(ctx context.Context, divis
 			return divisionResourceActions, fmt.Errorf("[json.MarshalIndent]%v", err)
 		}
 
-		err = os.WriteFile("mappings/drift-resources-differences.json", managedAttributeDifferencesBytes, 0400)
+		err = alc.artifactStore.WriteFile("mappings/drift-resources-differences.json", managedAttributeDifferencesBytes, 0400)
 		if err != nil {
-			return divisionResourceActions, fmt.Errorf("[os.WriteFile]%v", err)
+			return divisionResourceActions, fmt.Errorf("[artifacts.WriteFile]%v", err)
 		}
 	}
 
@@ -174,7 +233,7 @@ func (alc *AWSLogQuerier) QueryForResourcesInDivision(ctx context.Context, divis
 	currentNewResources, ok := alc.divisionToNewResources[division]
 	if ok {
 		for id, resource := range currentNewResources {
-			resourceActions, err := alc.cloudTrailEventHistorySearch(ctx, resource.ResourceType, string(id), resource.Region, true)
+			resourceActions, err := alc.cloudTrailEventHistorySearch(ctx, division, resource.ResourceType, string(id), resource.Region, true)
 			if err != nil {
 				if err != ErrNoCloudTrailEvents {
 					return nil, fmt.Errorf("[alc.cloudTrailEventHistorySearch]%v", err)
@@ -184,7 +243,7 @@ func (alc *AWSLogQuerier) QueryForResourcesInDivision(ctx context.Context, divis
 			}
 
 			currentResourceName := terraformValueObjects.ResourceName(
-				resource.ResourceType + "." + hclcreate.ConvertTerraformerResourceName(resource.ResourceTerraformerName),
+				alc.resourceNames.Address(string(division), resource.ResourceType+"."+resource.ResourceTerraformerName),
 			)
 			divisionResourceActions[currentResourceName] = resourceActions
 		}
@@ -215,18 +274,59 @@ func (alc *AWSLogQuerier) UpdateManagedDriftAttributeDifferences(
 	alc.managedDriftAttributeDifferences = newAttributeDifferences
 }
 
-// TODO: At some point results may be further back in time, a subsequent improvement would be to look further back if resource details are not found.
 // cloudTrailEventHistorySearch runs AWS CLI commands to pull data on who modified and created the cloud resource in question.
-func (alc *AWSLogQuerier) cloudTrailEventHistorySearch(ctx context.Context, resourceType string, resourceID string, resourceRegion string, isNewToTerraform bool) (terraformValueObjects.ResourceActions, error) {
-	lookupAttributeString := fmt.Sprintf("AttributeKey=ResourceName,AttributeValue=%v", resourceID)
-	cloudTrailCommand := []string{"cloudtrail", "lookup-events", "--max-results", "50", "--output", "json", "--region", resourceRegion, "--lookup-attributes", lookupAttributeString}
+// When LookupEvents, limited to the last 90 days, does not identify the resource's actors, the configured event archive
+// is searched further back.
+func (alc *AWSLogQuerier) cloudTrailEventHistorySearch(
+	ctx context.Context,
+	division terraformValueObjects.Division,
+	resourceType string,
+	resourceID string,
+	resourceRegion string,
+	isNewToTerraform bool,
+) (terraformValueObjects.ResourceActions, error) {
+	var resourceActions terraformValueObjects.ResourceActions
+	var err error
+	if alc.eventBatching.Enabled {
+		var events []CloudTrailEvent
+		events, err = alc.resourceEvents(ctx, division, resourceRegion, resourceID)
+		if err != nil {
+			return terraformValueObjects.ResourceActions{}, fmt.Errorf("[alc.resourceEvents]%w", err)
+		}
+		resourceActions, err = alc.extractResourceActions(events, resourceType, isNewToTerraform)
+	} else {
+		lookupAttributeString := fmt.Sprintf("AttributeKey=ResourceName,AttributeValue=%v", resourceID)
+		cloudTrailCommand := []string{"cloudtrail", "lookup-events", "--max-results", "50", "--output", "json", "--region", resourceRegion, "--lookup-attributes", lookupAttributeString}
+
+		var result string
+		err = alc.limiter.Do(ctx, func() error {
+			var commandErr error
+			result, commandErr = executeAWSCommand("aws", cloudTrailCommand...)
+			return commandErr
+		})
+		if err != nil {
+			return terraformValueObjects.ResourceActions{}, fmt.Errorf("[executeCommandReturnStdOut]%v", err)
+		}
 
-	result, err := executeCommandReturnStdOut("aws", cloudTrailCommand...)
-	if err != nil {
-		return terraformValueObjects.ResourceActions{}, fmt.Errorf("[executeCommandReturnStdOut]%v", err)
+		resourceActions, err = alc.ExtractDataFromResourceResult([]byte(result), resourceType, isNewToTerraform)
+	}
+	if err != nil && err != ErrNoCloudTrailEvents {
+		return resourceActions, err
+	}
+
+	isIdentified := resourceActions.Modifier.Actor != ""
+	if isNewToTerraform {
+		isIdentified = resourceActions.Creator.Actor != ""
+	}
+	if isIdentified || !alc.eventArchive.enabled() {
+		return resourceActions, err
 	}
 
-	return alc.ExtractDataFromResourceResult([]byte(result), resourceType, isNewToTerraform)
+	resourceActions, err = alc.eventArchiveSearch(ctx, resourceID, resourceRegion, isNewToTerraform, resourceActions)
+	if err != nil && err != ErrNoCloudTrailEvents {
+		return resourceActions, fmt.Errorf("[alc.eventArchiveSearch]%w", err)
+	}
+	return resourceActions, err
 }
 
 // ExtractDataFromResourceResult parses the log response from the provider API
@@ -238,18 +338,25 @@ func (alc *AWSLogQuerier) ExtractDataFromResourceResult(resourceResult []byte, r
 	if err := json.Unmarshal(resourceResult, &cloudTrailEvents); err != nil {
 		return resourceActions, fmt.Errorf("failed to parse resource results to cloudTrailEvents struct: %v", err)
 	}
-	if len(cloudTrailEvents.Events) == 0 {
+
+	return alc.extractResourceActions(cloudTrailEvents.Events, resourceType, isNewToTerraform)
+}
+
+// extractResourceActions extracts the most recent relevant actions on a resource from its events, ordered from most
+// to least recent.
+func (alc *AWSLogQuerier) extractResourceActions(events []CloudTrailEvent, resourceType string, isNewToTerraform bool) (terraformValueObjects.ResourceActions, error) {
+	resourceActions := terraformValueObjects.ResourceActions{}
+	if len(events) == 0 {
 		return resourceActions, ErrNoCloudTrailEvents
 	}
 
 	resourceType = string(alc.resourceToCloudTrailType[resourceType])
 
-	isComplete := false
 	isModificationIdentified := false
 	i := 0
 
-	for !isComplete {
-		event := cloudTrailEvents.Events[i]
+	for i < len(events) {
+		event := events[i]
 		classification := determineActionClass(event.EventName)
 		event.EventTime = decimalToFormattedTimestamp(event.EventTimeUnformatted)
 
@@ -267,20 +374,28 @@ func (alc *AWSLogQuerier) ExtractDataFromResourceResult(resourceResult []byte, r
 			continue
 		}
 
+		// Changes made by automation principals are not attributed, leaving the resource's creator unknown and
+		// attributing the most recent modification by a human instead.
+		principal := event.principal()
+		actor := alc.identities.Resolve(principal)
+		isExcluded := alc.actorExclusions.IsExcluded(principal, actor)
+
 		switch classification {
 		case "creation":
 			if isNewToTerraform {
-				resourceActions.Creator = terraformValueObjects.CloudActorTimeStamp{
-					Actor:     terraformValueObjects.CloudActor(event.UserName),
-					Timestamp: terraformValueObjects.Timestamp(event.EventTime),
+				if !isExcluded {
+					resourceActions.Creator = terraformValueObjects.CloudActorTimeStamp{
+						Actor:     terraformValueObjects.CloudActor(actor),
+						Timestamp: terraformValueObjects.Timestamp(event.EventTime),
+					}
 				}
 				return resourceActions, nil
 			}
 		case "modification":
-			if !isModificationIdentified {
+			if !isModificationIdentified && !isExcluded {
 				isModificationIdentified = true
 				resourceActions.Modifier = terraformValueObjects.CloudActorTimeStamp{
-					Actor:     terraformValueObjects.CloudActor(event.UserName),
+					Actor:     terraformValueObjects.CloudActor(actor),
 					Timestamp: terraformValueObjects.Timestamp(event.EventTime),
 				}
 				if !isNewToTerraform {
@@ -319,22 +434,12 @@ func executeCommandReturnStdOut(command string, args ...string) (string, error)
 	return out.String(), nil
 }
 
-// setAWSCredentials loads and sets as environment variables AWS credentials for a given AWS account.
+// setAWSCredentials resolves and sets as environment variables AWS credentials for a given AWS account, assuming the
+// division's role when one is configured.
 func (alc *AWSLogQuerier) setAWSCredentials(credential terraformValueObjects.Credential) error {
-	envVars := new(AWSEnvironment)
-	err := json.Unmarshal([]byte(credential), &envVars)
-	if err != nil {
-		return fmt.Errorf("[json.Unmarshal] %w", err)
-	}
-
-	err = os.Setenv("AWS_ACCESS_KEY_ID", envVars.AWSAccessKeyID)
-	if err != nil {
-		return fmt.Errorf("[os.Setenv]%w", err)
-	}
-
-	err = os.Setenv("AWS_SECRET_ACCESS_KEY", envVars.AWSSecretKeyAccess)
+	err := awscredentials.SetEnvironment(credential)
 	if err != nil {
-		return fmt.Errorf("[os.Setenv]%w", err)
+		return fmt.Errorf("[awscredentials.SetEnvironment]%w", err)
 	}
 
 	return nil
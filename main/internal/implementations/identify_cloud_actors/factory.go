@@ -3,6 +3,7 @@ package identifyCloudActors
 import (
 	"context"
 
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
 )
@@ -13,17 +14,17 @@ type Factory struct {
 
 // Instantiate returns an implementation of interfaces.IdentifyCloudActors depending on the passed
 // environment specification.
-func (f *Factory) Instantiate(ctx context.Context, environment string, dragonDrop interfaces.DragonDrop, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider, config Config) (interfaces.IdentifyCloudActors, error) {
+func (f *Factory) Instantiate(ctx context.Context, environment string, dragonDrop interfaces.DragonDrop, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider, config Config, artifactStore artifacts.Store) (interfaces.IdentifyCloudActors, error) {
 	switch environment {
 	case "isolated":
 		return new(IsolatedIdentifyCloudActors), nil
 	default:
-		return f.bootstrappedResourceCalculator(dragonDrop, divisionToProvider, config)
+		return f.bootstrappedResourceCalculator(dragonDrop, divisionToProvider, config, artifactStore)
 	}
 }
 
 // bootstrappedResourceCalculator creates a complete implementation of the interfaces.IdentifyCloudActors interface with
 // configuration specified via environment variables.
-func (f *Factory) bootstrappedResourceCalculator(dragonDrop interfaces.DragonDrop, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider, config Config) (interfaces.IdentifyCloudActors, error) {
-	return NewIdentifyCloudActors(config, dragonDrop, divisionToProvider)
+func (f *Factory) bootstrappedResourceCalculator(dragonDrop interfaces.DragonDrop, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider, config Config, artifactStore artifacts.Store) (interfaces.IdentifyCloudActors, error) {
+	return NewIdentifyCloudActors(config, dragonDrop, divisionToProvider, artifactStore)
 }
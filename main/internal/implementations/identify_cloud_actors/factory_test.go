@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
 	"github.com/stretchr/testify/assert"
@@ -19,7 +20,7 @@ func TestCreateNotIsolated(t *testing.T) {
 	divisionToProvider := make(map[terraformValueObjects.Division]terraformValueObjects.Provider)
 
 	// When
-	calculator, err := identifyCloudActorsFactory.Instantiate(ctx, env, dragonDrop, divisionToProvider, config)
+	calculator, err := identifyCloudActorsFactory.Instantiate(ctx, env, dragonDrop, divisionToProvider, config, artifacts.NewMemoryStore(true))
 
 	// Then
 	assert.Nil(t, err)
@@ -36,7 +37,7 @@ func TestCreateIsolatedResourcesCalculator(t *testing.T) {
 	divisionToProvider := make(map[terraformValueObjects.Division]terraformValueObjects.Provider)
 
 	// When
-	calculator, err := identifyCloudActorsFactory.Instantiate(ctx, env, dragonDrop, divisionToProvider, config)
+	calculator, err := identifyCloudActorsFactory.Instantiate(ctx, env, dragonDrop, divisionToProvider, config, artifacts.NewMemoryStore(true))
 
 	// Then
 	assert.Nil(t, err)
@@ -0,0 +1,334 @@
+package identifyCloudActors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+// bigQueryAPIURL is the base URL of the BigQuery REST API.
+var bigQueryAPIURL = "https://bigquery.googleapis.com/bigquery/v2"
+
+// bigQueryRowLimit is the maximum number of audit log entries returned for a single resource by a BigQuery query.
+const bigQueryRowLimit = 1000
+
+// bigQueryBatchSize is the maximum number of resources whose audit log entries are returned by a single BigQuery
+// query.
+const bigQueryBatchSize = 500
+
+// bigQueryPageSize is the maximum number of rows within each page of BigQuery query results.
+const bigQueryPageSize = 10000
+
+// auditLogScopeRegex matches the supported audit log scopes: a project into whose own log buckets an aggregated
+// sink routes audit logs, or the log bucket view of an aggregated sink. Entries listed from an organization or a
+// folder are only those of the organization or folder itself, rather than those of its child projects.
+var auditLogScopeRegex = regexp.MustCompile(
+	`^(projects/[^/]+|(projects|folders|organizations|billingAccounts)/[^/]+/locations/[^/]+/buckets/[^/]+/views/[^/]+)$`,
+)
+
+// GoogleAuditLogConfig configures where the admin activity audit logs of each Google Cloud project are queried from,
+// for organizations that aggregate audit logs above the project level.
+type GoogleAuditLogConfig struct {
+	// Scope is the resource name from which audit log entries are listed, either the log bucket view of an
+	// aggregated sink, e.g. "projects/central-logging/locations/global/buckets/org-audit-logs/views/_AllLogs", or
+	// the project into whose _Default log bucket an aggregated sink routes audit logs, e.g.
+	// "projects/central-logging". Each project's own audit logs are listed when empty.
+	Scope string
+
+	// BigQueryTable is the BigQuery table of a log sink into which admin activity audit logs are routed, in the form
+	// project.dataset.table, e.g. "central-logging.org_audit_logs.cloudaudit_googleapis_com_activity_*". When set,
+	// audit logs are queried from BigQuery rather than from Cloud Logging.
+	BigQueryTable string
+
+	// BigQueryProject is the project in which BigQuery query jobs are run, defaulting to the project of
+	// BigQueryTable.
+	BigQueryProject string
+
+	// LookbackDays is the number of days of audit logs queried from BigQuery, bounding both the entries' timestamp
+	// and, for a wildcard table, the date shards scanned.
+	LookbackDays int
+}
+
+// validate returns an error if the configured audit log source is malformed.
+func (c GoogleAuditLogConfig) validate() error {
+	if c.Scope != "" && c.BigQueryTable != "" {
+		return fmt.Errorf("[an audit log scope and a BigQuery table cannot both be configured]")
+	}
+
+	if c.Scope != "" && !auditLogScopeRegex.MatchString(c.Scope) {
+		return fmt.Errorf(
+			"[audit log scope %v is neither a project nor a log bucket view, organizations and folders do not list the audit logs of their projects]",
+			c.Scope,
+		)
+	}
+
+	if c.BigQueryTable != "" && len(strings.Split(c.BigQueryTable, ".")) != 3 {
+		return fmt.Errorf("[BigQuery table %v is not of the form project.dataset.table]", c.BigQueryTable)
+	}
+
+	if c.BigQueryTable != "" && c.LookbackDays <= 0 {
+		return fmt.Errorf("[BigQuery audit log lookback days must be positive, got %d]", c.LookbackDays)
+	}
+	return nil
+}
+
+// bigQueryJobProject returns the project in which BigQuery query jobs are run.
+func (c GoogleAuditLogConfig) bigQueryJobProject() string {
+	if c.BigQueryProject != "" {
+		return c.BigQueryProject
+	}
+	return strings.Split(c.BigQueryTable, ".")[0]
+}
+
+// auditLogResourceName returns the resource name from which the audit log entries of division are listed.
+func (glc *GoogleLogQuerier) auditLogResourceName(division terraformValueObjects.Division) string {
+	if glc.auditLogs.Scope != "" {
+		return glc.auditLogs.Scope
+	}
+	return fmt.Sprintf("projects/%v", division)
+}
+
+// bigQueryAuditLogQuery returns the BigQuery query for the admin activity audit log entries of the resources passed
+// as the resourceNames query parameter, from most to least recent, within the last lookbackDays days. The date
+// shards of a wildcard table are bounded by _TABLE_SUFFIX, so that only the shards of the lookback window are scanned.
+func bigQueryAuditLogQuery(table string) string {
+	tableSuffixFilter := ""
+	if strings.HasSuffix(table, "*") {
+		tableSuffixFilter = "_TABLE_SUFFIX >= FORMAT_DATE('%Y%m%d', DATE_SUB(CURRENT_DATE(), INTERVAL @lookbackDays DAY)) AND "
+	}
+
+	return fmt.Sprintf(
+		"SELECT protopayload_auditlog.resourceName, protopayload_auditlog.methodName, "+
+			"protopayload_auditlog.authenticationInfo.principalEmail, "+
+			"(SELECT delegation.firstPartyPrincipal.principalEmail "+
+			"FROM UNNEST(protopayload_auditlog.authenticationInfo.serviceAccountDelegationInfo) AS delegation "+
+			"WHERE delegation.firstPartyPrincipal.principalEmail IS NOT NULL LIMIT 1), "+
+			"FORMAT_TIMESTAMP('%%Y-%%m-%%dT%%H:%%M:%%SZ', receiveTimestamp) "+
+			"FROM `%v` WHERE %vtimestamp >= TIMESTAMP_SUB(CURRENT_TIMESTAMP(), INTERVAL @lookbackDays DAY) "+
+			"AND protopayload_auditlog.resourceName IN UNNEST(@resourceNames) "+
+			"QUALIFY ROW_NUMBER() OVER (PARTITION BY protopayload_auditlog.resourceName ORDER BY receiveTimestamp DESC) <= %d "+
+			"ORDER BY receiveTimestamp DESC",
+		table,
+		tableSuffixFilter,
+		bigQueryRowLimit,
+	)
+}
+
+// bigQueryQueryResponse is the response of the BigQuery jobs.query and jobs.getQueryResults methods.
+type bigQueryQueryResponse struct {
+	JobComplete  bool   `json:"jobComplete"`
+	PageToken    string `json:"pageToken"`
+	JobReference struct {
+		ProjectID string `json:"projectId"`
+		JobID     string `json:"jobId"`
+		Location  string `json:"location"`
+	} `json:"jobReference"`
+	Rows []struct {
+		F []struct {
+			V *string `json:"v"`
+		} `json:"f"`
+	} `json:"rows"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// queryBigQuery queries the configured BigQuery log sink table for the admin activity audit log entries of
+// resourceIDs, in batches of bigQueryBatchSize resources, returning the entries of each resource from most to least
+// recent.
+func (glc *GoogleLogQuerier) queryBigQuery(ctx context.Context, resourceIDs []string) (map[string][]Entry, error) {
+	resourceToEntries := map[string][]Entry{}
+	for start := 0; start < len(resourceIDs); start += bigQueryBatchSize {
+		end := start + bigQueryBatchSize
+		if end > len(resourceIDs) {
+			end = len(resourceIDs)
+		}
+
+		err := glc.queryBigQueryBatch(ctx, resourceIDs[start:end], resourceToEntries)
+		if err != nil {
+			return nil, fmt.Errorf("[glc.queryBigQueryBatch]%w", err)
+		}
+	}
+	return resourceToEntries, nil
+}
+
+// queryBigQueryBatch runs a single BigQuery query for the audit log entries of resourceIDs, waiting for the query to
+// complete and reading every page of its results into resourceToEntries.
+func (glc *GoogleLogQuerier) queryBigQueryBatch(ctx context.Context, resourceIDs []string, resourceToEntries map[string][]Entry) error {
+	resourceNames := make([]map[string]string, 0, len(resourceIDs))
+	for _, resourceID := range resourceIDs {
+		resourceNames = append(resourceNames, map[string]string{"value": resourceID})
+	}
+
+	jsonBody, err := json.Marshal(map[string]interface{}{
+		"query":         bigQueryAuditLogQuery(glc.auditLogs.BigQueryTable),
+		"useLegacySql":  false,
+		"parameterMode": "NAMED",
+		"queryParameters": []map[string]interface{}{
+			{
+				"name":           "resourceNames",
+				"parameterType":  map[string]interface{}{"type": "ARRAY", "arrayType": map[string]string{"type": "STRING"}},
+				"parameterValue": map[string]interface{}{"arrayValues": resourceNames},
+			},
+			{
+				"name":           "lookbackDays",
+				"parameterType":  map[string]string{"type": "INT64"},
+				"parameterValue": map[string]string{"value": strconv.Itoa(glc.auditLogs.LookbackDays)},
+			},
+		},
+		"maxResults": bigQueryPageSize,
+	})
+	if err != nil {
+		return fmt.Errorf("[json.Marshal]%w", err)
+	}
+
+	response := bigQueryQueryResponse{}
+	err = glc.limiter.Do(ctx, func() error {
+		var requestErr error
+		response, requestErr = glc.bigQueryRequest(
+			ctx, "POST", fmt.Sprintf("%v/projects/%v/queries", bigQueryAPIURL, glc.auditLogs.bigQueryJobProject()), jsonBody,
+		)
+		return requestErr
+	})
+	if err != nil {
+		return fmt.Errorf("[jobs.query]%w", err)
+	}
+
+	job := response.JobReference
+	resultsURL := fmt.Sprintf(
+		"%v/projects/%v/queries/%v?location=%v&maxResults=%d",
+		bigQueryAPIURL, job.ProjectID, job.JobID, url.QueryEscape(job.Location), bigQueryPageSize,
+	)
+
+	if !response.JobComplete {
+		err = pollEventArchiveQuery(ctx, func() (bool, error) {
+			requestErr := glc.limiter.Do(ctx, func() error {
+				var err error
+				response, err = glc.bigQueryRequest(ctx, "GET", resultsURL, nil)
+				return err
+			})
+			if requestErr != nil {
+				return false, fmt.Errorf("[jobs.getQueryResults]%w", requestErr)
+			}
+			return response.JobComplete, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	addBigQueryRowsToEntries(response, resourceToEntries)
+	for response.PageToken != "" {
+		pageURL := fmt.Sprintf("%v&pageToken=%v", resultsURL, url.QueryEscape(response.PageToken))
+		err = glc.limiter.Do(ctx, func() error {
+			var requestErr error
+			response, requestErr = glc.bigQueryRequest(ctx, "GET", pageURL, nil)
+			return requestErr
+		})
+		if err != nil {
+			return fmt.Errorf("[jobs.getQueryResults]%w", err)
+		}
+		addBigQueryRowsToEntries(response, resourceToEntries)
+	}
+	return nil
+}
+
+// bigQueryRequest sends a request with the passed JSON body, if any, to the BigQuery REST API, returning the parsed
+// query response.
+func (glc *GoogleLogQuerier) bigQueryRequest(ctx context.Context, method string, requestURL string, body []byte) (bigQueryQueryResponse, error) {
+	response := bigQueryQueryResponse{}
+
+	request, err := http.NewRequestWithContext(ctx, method, requestURL, bytes.NewReader(body))
+	if err != nil {
+		return response, fmt.Errorf("[http.NewRequestWithContext]%w", err)
+	}
+	request.Header = http.Header{
+		"Authorization": {fmt.Sprintf("Bearer %v", glc.authToken)},
+		"Content-Type":  {"application/json"},
+	}
+
+	httpResponse, err := glc.httpClient.Do(request)
+	if err != nil {
+		return response, fmt.Errorf("[glc.httpClient.Do]%w", err)
+	}
+	defer httpResponse.Body.Close()
+
+	outputBytes, err := io.ReadAll(httpResponse.Body)
+	if err != nil {
+		return response, fmt.Errorf("[io.ReadAll]%w", err)
+	}
+
+	if httpResponse.StatusCode != 200 {
+		return response, fmt.Errorf("[BigQuery request was unsuccessful, with the server returning: status code %v]%v", httpResponse.StatusCode, string(outputBytes))
+	}
+
+	err = json.Unmarshal(outputBytes, &response)
+	if err != nil {
+		return response, fmt.Errorf("[json.Unmarshal]%w", err)
+	}
+
+	if len(response.Errors) > 0 {
+		return response, fmt.Errorf("[BigQuery query failed]%v", response.Errors[0].Message)
+	}
+	return response, nil
+}
+
+// addBigQueryRowsToEntries converts the rows of a BigQuery audit log query into audit log entries, appending each to
+// the entries of its resource within resourceToEntries.
+func addBigQueryRowsToEntries(response bigQueryQueryResponse, resourceToEntries map[string][]Entry) {
+	for _, row := range response.Rows {
+		if len(row.F) < 5 {
+			continue
+		}
+
+		columns := make([]string, len(row.F))
+		for index, column := range row.F {
+			if column.V != nil {
+				columns[index] = *column.V
+			}
+		}
+
+		if len(columns[4]) < len("2006-01-02") {
+			continue
+		}
+
+		entry := Entry{ReceiveTimestamp: columns[4]}
+		entry.ProtoPayload.MethodName = columns[1]
+		entry.ProtoPayload.AuthenticationInfo.PrincipalEmail = columns[2]
+		if columns[3] != "" {
+			delegation := ServiceAccountDelegationInfo{}
+			delegation.FirstPartyPrincipal.PrincipalEmail = columns[3]
+			entry.ProtoPayload.AuthenticationInfo.ServiceAccountDelegationInfo = []ServiceAccountDelegationInfo{delegation}
+		}
+		resourceToEntries[columns[0]] = append(resourceToEntries[columns[0]], entry)
+	}
+}
+
+// divisionResourceIDs returns the sorted, unique ids of the drifted and new resources of division, whose audit log
+// entries are queried.
+func (glc *GoogleLogQuerier) divisionResourceIDs(division terraformValueObjects.Division) []string {
+	isQueried := map[string]bool{}
+	for _, driftedResource := range glc.divisionToUniqueManagedDriftedResources[division] {
+		isQueried[driftedResource.InstanceID] = true
+	}
+	for id := range glc.divisionToNewResources[division] {
+		isQueried[string(id)] = true
+	}
+
+	resourceIDs := make([]string, 0, len(isQueried))
+	for resourceID := range isQueried {
+		resourceIDs = append(resourceIDs, resourceID)
+	}
+	sort.Strings(resourceIDs)
+	return resourceIDs
+}
@@ -0,0 +1,152 @@
+package identifyCloudActors
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoogleAuditLogConfig_Validate(t *testing.T) {
+	// Given
+	configs := map[string]GoogleAuditLogConfig{
+		"":                          {},
+		"aggregating project":       {Scope: "projects/central-logging"},
+		"log bucket view":           {Scope: "projects/central-logging/locations/global/buckets/org-audit-logs/views/_AllLogs"},
+		"organization log bucket":   {Scope: "organizations/123456789012/locations/global/buckets/org-audit-logs/views/_AllLogs"},
+		"organization":              {Scope: "organizations/123456789012"},
+		"folder":                    {Scope: "folders/123456789012"},
+		"BigQuery":                  {BigQueryTable: "central-logging.org_audit_logs.cloudaudit_googleapis_com_activity_*", LookbackDays: 400},
+		"unbounded BigQuery":        {BigQueryTable: "central-logging.org_audit_logs.cloudaudit_googleapis_com_activity_*"},
+		"unsupported scope":         {Scope: "123456789012"},
+		"unqualified BigQuery":      {BigQueryTable: "cloudaudit_googleapis_com_activity", LookbackDays: 400},
+		"scope and BigQuery tables": {Scope: "projects/central-logging", BigQueryTable: "central-logging.org_audit_logs.activity", LookbackDays: 400},
+	}
+	expectedValid := map[string]bool{
+		"": true, "aggregating project": true, "log bucket view": true, "organization log bucket": true, "BigQuery": true,
+	}
+
+	for name, config := range configs {
+		// When
+		err := config.validate()
+
+		// Then
+		assert.Equal(t, expectedValid[name], err == nil, name)
+	}
+}
+
+func TestGenerateLogFilter_AggregatedScope(t *testing.T) {
+	// Given
+	glc := GoogleLogQuerier{auditLogs: GoogleAuditLogConfig{Scope: "projects/central-logging"}}
+
+	// When
+	resourceName := glc.auditLogResourceName("test-div")
+	filter := glc.generateLogFilter("test-div", "my-id")
+
+	// Then
+	assert.Equal(t, "projects/central-logging", resourceName)
+	assert.Equal(t, `log_id("cloudaudit.googleapis.com/activity") AND protoPayload.resourceName=my-id`, filter)
+}
+
+func TestBigQueryAuditLogQuery(t *testing.T) {
+	// When
+	wildcardQuery := bigQueryAuditLogQuery("central-logging.org_audit_logs.cloudaudit_googleapis_com_activity_*")
+	partitionedQuery := bigQueryAuditLogQuery("central-logging.org_audit_logs.cloudaudit_googleapis_com_activity")
+
+	// Then
+	assert.Contains(t, wildcardQuery, "WHERE _TABLE_SUFFIX >= FORMAT_DATE('%Y%m%d', DATE_SUB(CURRENT_DATE(), INTERVAL @lookbackDays DAY)) AND timestamp >= ")
+	assert.Contains(t, wildcardQuery, "protopayload_auditlog.resourceName IN UNNEST(@resourceNames)")
+	assert.Contains(t, wildcardQuery, "FORMAT_TIMESTAMP('%Y-%m-%dT%H:%M:%SZ', receiveTimestamp)")
+	assert.NotContains(t, partitionedQuery, "_TABLE_SUFFIX")
+	assert.Contains(t, partitionedQuery, "WHERE timestamp >= TIMESTAMP_SUB(CURRENT_TIMESTAMP(), INTERVAL @lookbackDays DAY)")
+}
+
+func TestGoogleLogQuerier_AdminLogSearchBigQuery(t *testing.T) {
+	// Given
+	originalPollInterval := eventArchivePollInterval
+	eventArchivePollInterval = time.Millisecond
+	defer func() { eventArchivePollInterval = originalPollInterval }()
+
+	queries := 0
+	var requestedResourceNames []string
+	requestedLookbackDays := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			queries++
+			body, _ := io.ReadAll(r.Body)
+			query := struct {
+				QueryParameters []struct {
+					ParameterValue struct {
+						Value       string `json:"value"`
+						ArrayValues []struct {
+							Value string `json:"value"`
+						} `json:"arrayValues"`
+					} `json:"parameterValue"`
+				} `json:"queryParameters"`
+			}{}
+			_ = json.Unmarshal(body, &query)
+			for _, value := range query.QueryParameters[0].ParameterValue.ArrayValues {
+				requestedResourceNames = append(requestedResourceNames, value.Value)
+			}
+			requestedLookbackDays = query.QueryParameters[1].ParameterValue.Value
+
+			assert.Equal(t, "/projects/central-logging/queries", r.URL.Path)
+			_, _ = w.Write([]byte(`{"jobComplete": false, "jobReference": {"projectId": "central-logging", "jobId": "job-1", "location": "US"}}`))
+			return
+		}
+
+		assert.Equal(t, "/projects/central-logging/queries/job-1", r.URL.Path)
+		if r.URL.Query().Get("pageToken") == "" {
+			_, _ = w.Write([]byte(`{"jobComplete": true, "pageToken": "page-2", "rows": [
+				{"f": [{"v": "projects/_/buckets/my-bucket"}, {"v": "storage.buckets.update"}, {"v": "ci@my-project.iam.gserviceaccount.com"}, {"v": null}, {"v": "2023-03-11T17:24:54Z"}]},
+				{"f": [{"v": "projects/_/buckets/my-bucket"}, {"v": "storage.buckets.update"}, {"v": "terraform@my-project.iam.gserviceaccount.com"}, {"v": "jane@example.com"}, {"v": "2023-03-08T17:24:54Z"}]}
+			]}`))
+			return
+		}
+
+		assert.Equal(t, "page-2", r.URL.Query().Get("pageToken"))
+		_, _ = w.Write([]byte(`{"jobComplete": true, "rows": [
+			{"f": [{"v": "projects/_/buckets/my-bucket"}, {"v": "storage.buckets.create"}, {"v": "john@example.com"}, {"v": null}, {"v": "2023-02-25T17:24:54Z"}]},
+			{"f": [{"v": "projects/my-project/topics/orders"}, {"v": "google.pubsub.v1.Publisher.CreateTopic"}, {"v": "john@example.com"}, {"v": null}, {"v": "2023-02-20T17:24:54Z"}]}
+		]}`))
+	}))
+	defer server.Close()
+
+	originalBigQueryAPIURL := bigQueryAPIURL
+	bigQueryAPIURL = server.URL
+	defer func() { bigQueryAPIURL = originalBigQueryAPIURL }()
+
+	actorExclusions, err := ParseActorExclusionPolicy([]string{"*.gserviceaccount.com"})
+	require.NoError(t, err)
+
+	glc := GoogleLogQuerier{
+		actorExclusions: actorExclusions,
+		auditLogs: GoogleAuditLogConfig{
+			BigQueryTable: "central-logging.org_audit_logs.cloudaudit_googleapis_com_activity_*",
+			LookbackDays:  400,
+		},
+	}
+
+	// When
+	glc.bigQueryEntries, err = glc.queryBigQuery(context.Background(), []string{"projects/_/buckets/my-bucket", "projects/my-project/topics/orders"})
+	require.NoError(t, err)
+	resourceActions, err := glc.adminLogSearch(context.Background(), "my-project", "projects/_/buckets/my-bucket", true)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, 1, queries)
+	assert.Equal(t, []string{"projects/_/buckets/my-bucket", "projects/my-project/topics/orders"}, requestedResourceNames)
+	assert.Equal(t, "400", requestedLookbackDays)
+	assert.Len(t, glc.bigQueryEntries["projects/my-project/topics/orders"], 1)
+	assert.Equal(t, terraformValueObjects.ResourceActions{
+		Creator:  terraformValueObjects.CloudActorTimeStamp{Actor: "john@example.com", Timestamp: "2023-02-25"},
+		Modifier: terraformValueObjects.CloudActorTimeStamp{Actor: "jane@example.com", Timestamp: "2023-03-08"},
+	}, resourceActions)
+}
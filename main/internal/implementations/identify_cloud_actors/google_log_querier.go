@@ -7,14 +7,15 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"os"
 	"strings"
 
 	"github.com/Jeffail/gabs/v2"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/hclcreate"
 	resourcesCalculator "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/resources_calculator"
 	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/ratelimit"
 )
 
 // GoogleLogQuerier implements the LogQuerier interface for Google Cloud.
@@ -29,36 +30,80 @@ type GoogleLogQuerier struct {
 	// divisionToNewResources is a map between a division and a list of new resource objects.
 	divisionToNewResources resourcesCalculator.DivisionToNewResources
 
+	// resourceNames are the names of the new resources within the generated code, on which their actions are keyed.
+	resourceNames hclcreate.ResourceNames
+
 	// divisionToUniqueManagedDriftedResources is a map between a division and a list of unique drifted resource objects.
 	divisionToUniqueManagedDriftedResources DivisionToUniqueDriftedResources
 
 	// httpClient is a http client shared across all http requests within this package.
 	httpClient http.Client
 
+	// auditLogs configures where the admin activity audit logs of each project are queried from.
+	auditLogs GoogleAuditLogConfig
+
+	// actorExclusions are the automation principals whose changes are not attributed to a cloud actor.
+	actorExclusions ActorExclusionPolicy
+
+	// identities resolves the principals recorded within Google Cloud audit logs to human identities.
+	identities *IdentityResolver
+
+	// limiter rate limits queries against the Google Cloud logging API, nil when rate limiting is disabled.
+	limiter *ratelimit.Limiter
+
+	// bigQueryEntries are the audit log entries of each resource of the division being queried, queried at once from
+	// the BigQuery log sink when one is configured.
+	bigQueryEntries map[string][]Entry
+
 	// managedDriftAttributeDifferences is a list of all attribute differences.
 	managedDriftAttributeDifferences []driftDetector.AttributeDifference
+
+	// artifactStore holds the mappings of the upstream stages and of the identified cloud actors.
+	artifactStore artifacts.Store
 }
 
 // NewGoogleLogQuerier instantiates a new instance of GoogleLogQuerier
-func NewGoogleLogQuerier(divisionToCredentials terraformValueObjects.DivisionCloudCredentialDecoder) (LogQuerier, error) {
+func NewGoogleLogQuerier(
+	divisionToCredentials terraformValueObjects.DivisionCloudCredentialDecoder,
+	limiter *ratelimit.Limiter,
+	identities *IdentityResolver,
+	actorExclusions ActorExclusionPolicy,
+	auditLogs GoogleAuditLogConfig,
+	artifactStore artifacts.Store,
+) (LogQuerier, error) {
+	err := auditLogs.validate()
+	if err != nil {
+		return nil, fmt.Errorf("[auditLogs.validate]%w", err)
+	}
+
 	return &GoogleLogQuerier{
+		actorExclusions:       actorExclusions,
+		artifactStore:         artifactStore,
+		auditLogs:             auditLogs,
 		divisionToCredentials: divisionToCredentials,
+		identities:            identities,
+		limiter:               limiter,
 	}, nil
 }
 
 // loadUpstreamDataToGoogleLogQuerier loads all data needed for querying logs from upstream
 // saved data sources.
 func (glc *GoogleLogQuerier) loadUpstreamDataToGoogleLogQuerier() error {
-	attributeDifferences, err := loadDriftResourcesDifferences()
+	attributeDifferences, err := loadDriftResourcesDifferences(glc.artifactStore)
 	if err != nil {
 		return fmt.Errorf("[loadDriftResourcesDifferences]%v", err)
 	}
 
-	divToNewResources, err := loadDivisionToNewResources()
+	divToNewResources, err := loadDivisionToNewResources(glc.artifactStore)
 	if err != nil {
 		return fmt.Errorf("[loadDivisionToNewResources]%v", err)
 	}
 
+	resourceNames, err := hclcreate.LoadResourceNames(glc.artifactStore)
+	if err != nil {
+		return fmt.Errorf("[hclcreate.LoadResourceNames]%v", err)
+	}
+
 	divToUniqueDriftedResources, err := createDivisionUniqueDriftedResources(attributeDifferences)
 	if err != nil {
 		return fmt.Errorf("[createDivisionUniqueDriftedResources]%v", err)
@@ -66,6 +111,7 @@ func (glc *GoogleLogQuerier) loadUpstreamDataToGoogleLogQuerier() error {
 
 	glc.divisionToUniqueManagedDriftedResources = divToUniqueDriftedResources
 	glc.divisionToNewResources = divToNewResources
+	glc.resourceNames = resourceNames
 	glc.managedDriftAttributeDifferences = attributeDifferences
 	return nil
 }
@@ -100,6 +146,13 @@ func (glc *GoogleLogQuerier) QueryForResourcesInDivision(ctx context.Context, di
 	}
 	dragondropDivision := terraformValueObjects.Division("google-" + string(division))
 
+	if glc.auditLogs.BigQueryTable != "" {
+		glc.bigQueryEntries, err = glc.queryBigQuery(ctx, glc.divisionResourceIDs(dragondropDivision))
+		if err != nil {
+			return divisionResourceActions, fmt.Errorf("[glc.queryBigQuery]%v", err)
+		}
+	}
+
 	// Calculating cloud actors for managed resource drift
 	currentUniqueDriftedResources, ok := glc.divisionToUniqueManagedDriftedResources[dragondropDivision]
 	if ok {
@@ -121,9 +174,9 @@ func (glc *GoogleLogQuerier) QueryForResourcesInDivision(ctx context.Context, di
 			return divisionResourceActions, fmt.Errorf("[json.MarshalIndent]%v", err)
 		}
 
-		err = os.WriteFile("mappings/drift-resources-differences.json", managedAttributeDifferencesBytes, 0400)
+		err = glc.artifactStore.WriteFile("mappings/drift-resources-differences.json", managedAttributeDifferencesBytes, 0400)
 		if err != nil {
-			return divisionResourceActions, fmt.Errorf("[os.WriteFile]%v", err)
+			return divisionResourceActions, fmt.Errorf("[artifacts.WriteFile]%v", err)
 		}
 	}
 
@@ -137,7 +190,7 @@ func (glc *GoogleLogQuerier) QueryForResourcesInDivision(ctx context.Context, di
 			}
 
 			currentResourceName := terraformValueObjects.ResourceName(
-				resource.ResourceType + "." + hclcreate.ConvertTerraformerResourceName(resource.ResourceTerraformerName),
+				glc.resourceNames.Address(string(dragondropDivision), resource.ResourceType+"."+resource.ResourceTerraformerName),
 			)
 			divisionResourceActions[currentResourceName] = resourceActions
 		}
@@ -168,11 +221,21 @@ func (glc *GoogleLogQuerier) UpdateManagedDriftAttributeDifferences(
 	glc.managedDriftAttributeDifferences = newAttributeDifferences
 }
 
-// adminLogSearch pulls logs for a single resource from the cloud provider.
+// adminLogSearch pulls logs for a single resource from the cloud provider, or reads the entries queried from the
+// BigQuery log sink for the division when one is configured.
 func (glc *GoogleLogQuerier) adminLogSearch(
 	ctx context.Context, division terraformValueObjects.Division, resourceID string, isNewToTerraform bool,
 ) (terraformValueObjects.ResourceActions, error) {
-	result, err := glc.queryGCPAPI(ctx, division, resourceID)
+	if glc.auditLogs.BigQueryTable != "" {
+		return glc.entriesToResourceActions(glc.bigQueryEntries[resourceID], isNewToTerraform), nil
+	}
+
+	var result []byte
+	err := glc.limiter.Do(ctx, func() error {
+		var queryErr error
+		result, queryErr = glc.queryGCPAPI(ctx, division, resourceID)
+		return queryErr
+	})
 	if err != nil {
 		return terraformValueObjects.ResourceActions{}, fmt.Errorf("[glc.queryGCPAPI]%w", err)
 	}
@@ -189,7 +252,8 @@ func (glc *GoogleLogQuerier) adminLogSearch(
 type GCPAdminLogPostBody struct {
 
 	// ResourceNames are the names of one or more parent resources from which to retrieve log entries.
-	// For our use case, each value will always take the form of "projects/[PROJECT_ID]"
+	// For our use case, each value takes the form of "projects/[PROJECT_ID]" unless an aggregated
+	// audit log scope, such as the log bucket view of an aggregated sink, is configured.
 	ResourceNames []string `json:"resourceNames"`
 
 	// Filter is the filter of the resource specified within resourceNames.
@@ -207,7 +271,7 @@ type GCPAdminLogPostBody struct {
 func (glc *GoogleLogQuerier) queryGCPAPI(ctx context.Context, division terraformValueObjects.Division, resourceID string) ([]byte, error) {
 	logFilterString := glc.generateLogFilter(division, resourceID)
 	jsonBody, err := json.Marshal(&GCPAdminLogPostBody{
-		ResourceNames: []string{fmt.Sprintf("projects/%v", division)},
+		ResourceNames: []string{glc.auditLogResourceName(division)},
 		Filter:        logFilterString,
 		OrderBy:       "timestamp desc",
 		PageSize:      1000,
@@ -234,7 +298,7 @@ func (glc *GoogleLogQuerier) queryGCPAPI(ctx context.Context, division terraform
 	}
 
 	if response.StatusCode != 200 {
-		return []byte{}, fmt.Errorf("[glc.queryGCPAPI POST request][was unsuccessful, with the server returning: %v]", response.StatusCode)
+		return []byte{}, fmt.Errorf("[glc.queryGCPAPI POST request][was unsuccessful, with the server returning: status code %v]", response.StatusCode)
 	}
 
 	// Read in response body to bytes array.
@@ -251,8 +315,13 @@ func (glc *GoogleLogQuerier) queryGCPAPI(ctx context.Context, division terraform
 }
 
 // generateLogFilter generates a string formatted for filtering admin query logs within the GCP API.
+// Entries listed from an aggregated audit log scope keep the log name of their originating project, which
+// may differ from the division for resources shared across projects, so are filtered by log id instead.
 func (glc *GoogleLogQuerier) generateLogFilter(division terraformValueObjects.Division, resourceID string) string {
 	logNameFilter := fmt.Sprintf("logName=projects/%v", division) + "/logs/cloudaudit.googleapis.com%2Factivity"
+	if glc.auditLogs.Scope != "" {
+		logNameFilter = `log_id("cloudaudit.googleapis.com/activity")`
+	}
 
 	resourceTypeFilter := fmt.Sprintf("protoPayload.resourceName=%v", resourceID)
 
@@ -296,53 +365,89 @@ type ProtoPayload struct {
 // AuthenticationInfo is a struct representing the authenticationInfo field of the ProtoPayload component
 // of a GCP logging query response.
 type AuthenticationInfo struct {
-	PrincipalEmail string `json:"principalEmail"`
+	PrincipalEmail               string                         `json:"principalEmail"`
+	ServiceAccountDelegationInfo []ServiceAccountDelegationInfo `json:"serviceAccountDelegationInfo"`
+}
+
+// ServiceAccountDelegationInfo is a struct representing a single delegation within the service account
+// impersonation chain of an AuthenticationInfo, ordered from the original caller.
+type ServiceAccountDelegationInfo struct {
+	FirstPartyPrincipal struct {
+		PrincipalEmail string `json:"principalEmail"`
+	} `json:"firstPartyPrincipal"`
+}
+
+// principal returns the original caller of a service account impersonation chain, or the principal email when the
+// service account was not impersonated.
+func (a AuthenticationInfo) principal() string {
+	for _, delegation := range a.ServiceAccountDelegationInfo {
+		if delegation.FirstPartyPrincipal.PrincipalEmail != "" {
+			return delegation.FirstPartyPrincipal.PrincipalEmail
+		}
+	}
+	return a.PrincipalEmail
 }
 
 // ExtractDataFromResourceResult parses the log response from the provider API
 // and extracts needed data (namely who made the most recent relevant change to the resource).
 func (glc *GoogleLogQuerier) ExtractDataFromResourceResult(resourceResult []byte, resourceType string, isNewToTerraform bool) (terraformValueObjects.ResourceActions, error) {
-	resourceActions := terraformValueObjects.ResourceActions{}
 	var entries Entries
 	if err := json.Unmarshal(resourceResult, &entries); err != nil {
-		return resourceActions, fmt.Errorf("failed to parse resource result: %v", err)
+		return terraformValueObjects.ResourceActions{}, fmt.Errorf("failed to parse resource result: %v", err)
 	}
+	return glc.entriesToResourceActions(entries.Entries, isNewToTerraform), nil
+}
+
+// entriesToResourceActions identifies the creator and most recent modifier of a resource from its audit log
+// entries, ordered from most to least recent.
+func (glc *GoogleLogQuerier) entriesToResourceActions(entries []Entry, isNewToTerraform bool) terraformValueObjects.ResourceActions {
+	resourceActions := terraformValueObjects.ResourceActions{}
 
 	// Algorithm: Iterate through each response entry.
 	// If "Create" is in the action, stop.
 	// If "Modify", capture the first one.
 	// ---- If isNewToTerraform continue until we hit "create", otherwise return immediately.
+	// Actions of excluded automation principals are not attributed.
 	isModifyIdentified := false
 
-	for _, entry := range entries.Entries {
+	for _, entry := range entries {
 		classification := determineActionClass(entry.ProtoPayload.MethodName)
+		principal := entry.ProtoPayload.AuthenticationInfo.principal()
+		actor := glc.identities.Resolve(principal)
+		isExcluded := glc.actorExclusions.IsExcluded(principal, actor)
 
 		switch classification {
 		case "creation":
-			resourceActions.Creator = terraformValueObjects.CloudActorTimeStamp{
-				Actor:     terraformValueObjects.CloudActor(entry.ProtoPayload.AuthenticationInfo.PrincipalEmail),
-				Timestamp: terraformValueObjects.Timestamp(entry.ReceiveTimestamp[:10]),
+			if !isExcluded {
+				resourceActions.Creator = terraformValueObjects.CloudActorTimeStamp{
+					Actor:     terraformValueObjects.CloudActor(actor),
+					Timestamp: terraformValueObjects.Timestamp(entry.ReceiveTimestamp[:10]),
+				}
 			}
-			return resourceActions, nil
+			return resourceActions
 		case "modification":
-			if !isModifyIdentified {
+			if !isModifyIdentified && !isExcluded {
 				isModifyIdentified = true
 				resourceActions.Modifier = terraformValueObjects.CloudActorTimeStamp{
-					Actor:     terraformValueObjects.CloudActor(entry.ProtoPayload.AuthenticationInfo.PrincipalEmail),
+					Actor:     terraformValueObjects.CloudActor(actor),
 					Timestamp: terraformValueObjects.Timestamp(entry.ReceiveTimestamp[:10]),
 				}
 				if !isNewToTerraform {
-					return resourceActions, nil
+					return resourceActions
 				}
 			}
 		}
 	}
-	return resourceActions, nil
+	return resourceActions
 }
 
 // gcloudAuthTokenFromServiceAccount gets an authentication token for REST API requests from the
 // passed service account keys.
 func (glc *GoogleLogQuerier) gcloudAuthTokenFromServiceAccount(division terraformValueObjects.Division) error {
+	if glc.divisionToCredentials[division].IsAmbient() {
+		return glc.gcloudAuthTokenFromAmbientCredentials()
+	}
+
 	account, err := glc.parseGCPServiceAccountEmailAddress(division)
 	if err != nil {
 		return fmt.Errorf("[gcloud_authentication][error parsing service account email address]%w", err)
@@ -368,6 +473,19 @@ func (glc *GoogleLogQuerier) gcloudAuthTokenFromServiceAccount(division terrafor
 	return nil
 }
 
+// gcloudAuthTokenFromAmbientCredentials gets an authentication token for REST API requests from the credentials
+// available within the container's environment, e.g. GKE workload identity.
+func (glc *GoogleLogQuerier) gcloudAuthTokenFromAmbientCredentials() error {
+	token, err := executeCommand("gcloud", "auth", "print-access-token")
+	if err != nil {
+		return fmt.Errorf("[executeCommand][gcloud auth print-access-token]%w", err)
+	}
+
+	glc.authToken = strings.Replace(token, "\n", "", -1)
+
+	return nil
+}
+
 // parseGCPServiceAccountEmailAddress pulls out the service account email address from the service account
 // key file.
 func (glc *GoogleLogQuerier) parseGCPServiceAccountEmailAddress(division terraformValueObjects.Division) (terraformValueObjects.Account, error) {
@@ -399,3 +399,50 @@ func TestExtractDataFromResourceResult_ManagedDriftRecordResponse(t *testing.T)
 		t.Errorf("got:\n%v\nexpected:\n%v", output, expectedOutput)
 	}
 }
+
+func TestExtractDataFromResourceResult_ServiceAccountImpersonation(t *testing.T) {
+	// Given
+	glc := GoogleLogQuerier{}
+	inputResourceResult := []byte(`{
+    "entries": [
+        {
+            "protoPayload": {
+                "authenticationInfo": {
+                    "principalEmail": "terraform@dragondrop-dev.iam.gserviceaccount.com",
+                    "serviceAccountDelegationInfo": [
+                        {
+                            "firstPartyPrincipal": {
+                                "principalEmail": "goodman.benjamin@dragondrop.cloud"
+                            }
+                        },
+                        {
+                            "firstPartyPrincipal": {
+                                "principalEmail": "deployer@dragondrop-dev.iam.gserviceaccount.com"
+                            }
+                        }
+                    ]
+                },
+                "methodName": "storage.buckets.create"
+            },
+            "receiveTimestamp": "2023-03-08T17:24:03.418303345Z"
+        }
+    ]
+}`)
+	// When
+	output, err := glc.ExtractDataFromResourceResult(inputResourceResult, "", true)
+	if err != nil {
+		t.Errorf("unexpected error in test: %v", err)
+	}
+
+	// Then
+	expectedOutput := terraformValueObjects.ResourceActions{
+		Creator: terraformValueObjects.CloudActorTimeStamp{
+			Actor:     terraformValueObjects.CloudActor("goodman.benjamin@dragondrop.cloud"),
+			Timestamp: terraformValueObjects.Timestamp("2023-03-08"),
+		},
+	}
+
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Errorf("got:\n%v\nexpected:\n%v", output, expectedOutput)
+	}
+}
@@ -4,18 +4,42 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"os"
 	"os/exec"
 
 	"github.com/Jeffail/gabs/v2"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/ratelimit"
 )
 
 // Config is a collection of query_param_data that parameterizes a IdentifyCloudActors instance.
 type Config struct {
 	// DivisionCloudCredentials is a map between a division and request cloud credentials.
 	DivisionCloudCredentials terraformValueObjects.DivisionCloudCredentialDecoder `required:"true"`
+
+	// RateLimit limits the queries made against each cloud provider's audit log APIs.
+	RateLimit ratelimit.Config
+
+	// EventBatching configures the fetching of AWS CloudTrail events once per region, rather than per resource, and
+	// their caching between runs.
+	EventBatching EventBatchingConfig
+
+	// EventArchive configures the long-lookback search of AWS events older than the CloudTrail LookupEvents window.
+	EventArchive EventArchiveConfig
+
+	// GoogleAuditLogs configures the querying of Google Cloud audit logs aggregated above the project level, such as
+	// within an organization log bucket or a BigQuery log sink.
+	GoogleAuditLogs GoogleAuditLogConfig
+
+	// IdentityMappingFile is the path of a yaml file mapping the principals recorded within audit logs, such as
+	// assumed-role sessions or service accounts, onto human identities. Only the built-in resolution applies when empty.
+	IdentityMappingFile string
+
+	// ActorExclusions are wildcard patterns for automation principals, such as CI roles, whose changes are not
+	// attributed, in addition to the built-in patterns for cloud service principals. Patterns prefixed by ! override the
+	// built-in patterns.
+	ActorExclusions []string
 }
 
 // IdentifyCloudActors implements the interfaces.IdentifyCloudActors interface.
@@ -34,11 +58,14 @@ type IdentifyCloudActors struct {
 	// For AWS, an account is the division, for GCP a project name is the division,
 	// and for azurerm a resource group is a division.
 	divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider `required:"true"`
+
+	// artifactStore holds the mappings of the identified cloud actors.
+	artifactStore artifacts.Store
 }
 
 // NewIdentifyCloudActors returns a new instance of IdentifyCloudActors.
-func NewIdentifyCloudActors(config Config, dragonDrop interfaces.DragonDrop, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider) (interfaces.IdentifyCloudActors, error) {
-	providerToLogQuerier, err := NewProviderToLogQuerierMap(config, divisionToProvider)
+func NewIdentifyCloudActors(config Config, dragonDrop interfaces.DragonDrop, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider, artifactStore artifacts.Store) (interfaces.IdentifyCloudActors, error) {
+	providerToLogQuerier, err := NewProviderToLogQuerierMap(config, divisionToProvider, artifactStore)
 	if err != nil {
 		return nil, fmt.Errorf("[NewProviderToLogQuerierMap]%w", err)
 	}
@@ -48,6 +75,7 @@ func NewIdentifyCloudActors(config Config, dragonDrop interfaces.DragonDrop, div
 		providerToLogQuerier: providerToLogQuerier,
 		dragonDrop:           dragonDrop,
 		divisionToProvider:   divisionToProvider,
+		artifactStore:        artifactStore,
 	}, nil
 }
 
@@ -69,9 +97,9 @@ func (ica *IdentifyCloudActors) Execute(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("[ica.convertProviderResourceActionsToJSON]%v", err)
 	}
-	err = os.WriteFile("mappings/resources-to-cloud-actions.json", jsonBytes, 0400)
+	err = ica.artifactStore.WriteFile("mappings/resources-to-cloud-actions.json", jsonBytes, 0400)
 	if err != nil {
-		return fmt.Errorf("[os.WriteFile mappings/resources-to-cloud-actions.json]%v", err)
+		return fmt.Errorf("[artifacts.WriteFile mappings/resources-to-cloud-actions.json]%v", err)
 	}
 	return nil
 }
@@ -0,0 +1,110 @@
+package identifyCloudActors
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/rulelist"
+)
+
+// IdentityMapping maps the principals recorded within cloud audit logs onto the human identities behind them.
+type IdentityMapping struct {
+	// Identities are the mapped principals, the first matching mapping applies.
+	Identities []MappedIdentity `yaml:"identities"`
+}
+
+// MappedIdentity maps the principals matching a glob onto a human identity.
+type MappedIdentity struct {
+	// Match is a wildcard pattern, in which * matches any characters including /, matched against the whole principal
+	// as recorded, e.g. arn:aws:sts::*:assumed-role/AWSReservedSSO_*/jdoe-session, and against its session or user name.
+	Match string `yaml:"match"`
+
+	// Identity is the human identity reported in place of a matching principal, e.g. jane.doe@example.com.
+	Identity string `yaml:"identity"`
+}
+
+// IdentityResolver resolves the principals recorded within cloud audit logs to human identities. A nil
+// IdentityResolver only applies the built-in resolution of AWS principal ARNs.
+type IdentityResolver struct {
+	mapping IdentityMapping
+
+	// patterns are the compiled Match patterns of the mapping's identities.
+	patterns []*regexp.Regexp
+}
+
+// LoadIdentityResolver returns an IdentityResolver applying the mapping file at mappingPath, or only the built-in
+// resolution when mappingPath is empty.
+func LoadIdentityResolver(mappingPath string) (*IdentityResolver, error) {
+	if mappingPath == "" {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(mappingPath)
+	if err != nil {
+		return nil, fmt.Errorf("[load_identity_resolver][os.ReadFile %v]%w", mappingPath, err)
+	}
+
+	mapping := IdentityMapping{}
+	err = yaml.Unmarshal(content, &mapping)
+	if err != nil {
+		return nil, fmt.Errorf("[load_identity_resolver][yaml.Unmarshal %v]%w", mappingPath, err)
+	}
+
+	patterns := make([]*regexp.Regexp, 0, len(mapping.Identities))
+	for _, identity := range mapping.Identities {
+		if identity.Match == "" || identity.Identity == "" {
+			return nil, fmt.Errorf("[load_identity_resolver][each identity mapping requires a match and an identity]")
+		}
+		patterns = append(patterns, rulelist.Wildcard(identity.Match))
+	}
+
+	return &IdentityResolver{mapping: mapping, patterns: patterns}, nil
+}
+
+// Resolve returns the human identity behind principal. Assumed-role and SSO sessions, federated users and IAM users
+// resolve to their session or user name, and the mapping file, when configured, maps either the principal or that
+// name onto a human identity.
+func (r *IdentityResolver) Resolve(principal string) string {
+	resolved := resolveAWSPrincipal(principal)
+	if r == nil {
+		return resolved
+	}
+
+	for i, identity := range r.mapping.Identities {
+		for _, value := range []string{principal, resolved} {
+			if r.patterns[i].MatchString(value) {
+				return identity.Identity
+			}
+		}
+	}
+	return resolved
+}
+
+// resolveAWSPrincipal returns the session or user name of an AWS principal ARN, e.g. jdoe-session for
+// arn:aws:sts::123456789012:assumed-role/AWSReservedSSO_Admin_1a2b3c/jdoe-session. Other principals are returned
+// unchanged.
+func resolveAWSPrincipal(principal string) string {
+	if !strings.HasPrefix(principal, "arn:aws") {
+		return principal
+	}
+
+	parts := strings.SplitN(principal, ":", 6)
+	if len(parts) < 6 {
+		return principal
+	}
+
+	resource := parts[5]
+	switch {
+	case resource == "root":
+		return "root"
+	case strings.HasPrefix(resource, "assumed-role/"),
+		strings.HasPrefix(resource, "federated-user/"),
+		strings.HasPrefix(resource, "user/"):
+		return resource[strings.LastIndex(resource, "/")+1:]
+	}
+	return principal
+}
@@ -0,0 +1,79 @@
+package identifyCloudActors
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdentityResolver_ResolveBuiltIn(t *testing.T) {
+	// Given
+	var resolver *IdentityResolver
+	principals := map[string]string{
+		"arn:aws:sts::123456789012:assumed-role/AWSReservedSSO_Admin_1a2b3c/jdoe@example.com": "jdoe@example.com",
+		"arn:aws:sts::123456789012:federated-user/jdoe":                                       "jdoe",
+		"arn:aws:iam::123456789012:user/engineering/jdoe":                                     "jdoe",
+		"arn:aws:iam::123456789012:root":                                                      "root",
+		"arn:aws:iam::123456789012:role/deployer":                                             "arn:aws:iam::123456789012:role/deployer",
+		"jane.doe@example.com":                                                                "jane.doe@example.com",
+	}
+
+	for principal, expected := range principals {
+		// When
+		resolved := resolver.Resolve(principal)
+
+		// Then
+		assert.Equal(t, expected, resolved, principal)
+	}
+}
+
+func TestLoadIdentityResolver(t *testing.T) {
+	// Given
+	mappingPath := filepath.Join(t.TempDir(), "identities.yaml")
+	err := os.WriteFile(mappingPath, []byte(`
+identities:
+  - match: "arn:aws:sts::*:assumed-role/AWSReservedSSO_Admin_*/jdoe-session"
+    identity: jane.doe@example.com
+  - match: "arn:aws:sts::*:assumed-role/*/jsmith"
+    identity: john.smith@example.com
+  - match: "ci-*"
+    identity: platform-team
+`), 0600)
+	require.NoError(t, err)
+
+	// When
+	resolver, err := LoadIdentityResolver(mappingPath)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, "jane.doe@example.com", resolver.Resolve("arn:aws:sts::123456789012:assumed-role/AWSReservedSSO_Admin_1a2b3c/jdoe-session"))
+	assert.Equal(t, "platform-team", resolver.Resolve("arn:aws:sts::123456789012:assumed-role/deployer/ci-run-42"))
+	assert.Equal(t, "john.smith@example.com", resolver.Resolve("arn:aws:sts::123456789012:assumed-role/org/admins/jsmith"))
+	assert.Equal(t, "jsmith-admin", resolver.Resolve("arn:aws:sts::123456789012:assumed-role/admins/jsmith-admin"))
+	assert.Equal(t, "john", resolver.Resolve("arn:aws:iam::123456789012:user/john"))
+}
+
+func TestLoadIdentityResolver_Invalid(t *testing.T) {
+	// Given
+	mappingPath := filepath.Join(t.TempDir(), "identities.yaml")
+	err := os.WriteFile(mappingPath, []byte("identities:\n  - match: \"ci-*\"\n"), 0600)
+	require.NoError(t, err)
+
+	// When
+	_, err = LoadIdentityResolver(mappingPath)
+
+	// Then
+	assert.Error(t, err)
+}
+
+func TestLoadIdentityResolver_NoMappingFile(t *testing.T) {
+	// When
+	resolver, err := LoadIdentityResolver("")
+
+	// Then
+	require.NoError(t, err)
+	assert.Nil(t, resolver)
+}
@@ -3,14 +3,14 @@ package identifyCloudActors
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"os"
 	"strings"
 
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
 	resourcesCalculator "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/resources_calculator"
 	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/ratelimit"
 )
 
 // LogQuerier is an interface for querying information from a single cloud providers
@@ -45,12 +45,22 @@ type UniqueDriftedResource struct {
 
 // NewProviderToLogQuerierMap returns a map between cloud providers and an instantiated LogQuerier
 // implementation for that provider.
-func NewProviderToLogQuerierMap(globalConfig Config, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider) (map[terraformValueObjects.Provider]LogQuerier, error) {
+func NewProviderToLogQuerierMap(globalConfig Config, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider, artifactStore artifacts.Store) (map[terraformValueObjects.Provider]LogQuerier, error) {
 	providerToQuerier := map[terraformValueObjects.Provider]LogQuerier{}
 
+	identities, err := LoadIdentityResolver(globalConfig.IdentityMappingFile)
+	if err != nil {
+		return nil, fmt.Errorf("[LoadIdentityResolver]%w", err)
+	}
+
+	actorExclusions, err := ParseActorExclusionPolicy(globalConfig.ActorExclusions)
+	if err != nil {
+		return nil, fmt.Errorf("[ParseActorExclusionPolicy]%w", err)
+	}
+
 	gcpDivCredentials := filterDivisionCloudCredentialsForProvider("google", divisionToProvider, globalConfig)
 	if len(gcpDivCredentials) > 0 {
-		googleLogQuerier, err := NewGoogleLogQuerier(gcpDivCredentials)
+		googleLogQuerier, err := NewGoogleLogQuerier(gcpDivCredentials, ratelimit.New(globalConfig.RateLimit), identities, actorExclusions, globalConfig.GoogleAuditLogs, artifactStore)
 		if err != nil {
 			return nil, fmt.Errorf("[NewGoogleLogQuerier]%v", err)
 		}
@@ -59,7 +69,7 @@ func NewProviderToLogQuerierMap(globalConfig Config, divisionToProvider map[terr
 
 	awsDivCredentials := filterDivisionCloudCredentialsForProvider("aws", divisionToProvider, globalConfig)
 	if len(awsDivCredentials) > 0 {
-		awsLogQuerier, err := NewAWSLogQuerier(awsDivCredentials)
+		awsLogQuerier, err := NewAWSLogQuerier(awsDivCredentials, ratelimit.New(globalConfig.RateLimit), globalConfig.EventArchive, identities, actorExclusions, globalConfig.EventBatching, artifactStore)
 		if err != nil {
 			return nil, fmt.Errorf("[NewAWSLogQuerier]%v", err)
 		}
@@ -102,15 +112,15 @@ func determineActionClass(value string) string {
 
 // loadDriftResourcesDifferences loads the drift-resources-differences file as a slice
 // of driftDetector.AttributeDifference.
-func loadDriftResourcesDifferences() ([]driftDetector.AttributeDifference, error) {
+func loadDriftResourcesDifferences(store artifacts.Store) ([]driftDetector.AttributeDifference, error) {
 	var resourceDifferences []driftDetector.AttributeDifference
-	if _, err := os.Stat("mappings/drift-resources-differences.json"); errors.Is(err, os.ErrNotExist) {
+	if !store.Exists("mappings/drift-resources-differences.json") {
 		return resourceDifferences, nil
 	}
 
-	fileContent, err := os.ReadFile("mappings/drift-resources-differences.json")
+	fileContent, err := store.ReadFile("mappings/drift-resources-differences.json")
 	if err != nil {
-		return nil, fmt.Errorf("[os.ReadFile]%v", err)
+		return nil, fmt.Errorf("[artifacts.ReadFile]%v", err)
 	}
 
 	err = json.Unmarshal(fileContent, &resourceDifferences)
@@ -123,15 +133,15 @@ func loadDriftResourcesDifferences() ([]driftDetector.AttributeDifference, error
 
 // loadDivisionToNewResources loads the division-to-new-resources file as a
 // resourcesCalculator.DivisionToNewResources struct.
-func loadDivisionToNewResources() (resourcesCalculator.DivisionToNewResources, error) {
+func loadDivisionToNewResources(store artifacts.Store) (resourcesCalculator.DivisionToNewResources, error) {
 	newResources := resourcesCalculator.DivisionToNewResources{}
-	if _, err := os.Stat("mappings/division-to-new-resources.json"); errors.Is(err, os.ErrNotExist) {
+	if !store.Exists("mappings/division-to-new-resources.json") {
 		return newResources, nil
 	}
 
-	fileContent, err := os.ReadFile("mappings/division-to-new-resources.json")
+	fileContent, err := store.ReadFile("mappings/division-to-new-resources.json")
 	if err != nil {
-		return newResources, fmt.Errorf("[os.ReadFile]%v", err)
+		return newResources, fmt.Errorf("[artifacts.ReadFile]%v", err)
 	}
 
 	err = json.Unmarshal(fileContent, &newResources)
@@ -0,0 +1,166 @@
+package inventoryExporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/sensitiveattributes"
+)
+
+const (
+	// costRollupsPath is the path to which the cost rollups are written for the state of cloud report.
+	costRollupsPath = "mappings/cost-rollups.json"
+
+	// untaggedGroup is the group of resources without a value for a cost allocation tag.
+	untaggedGroup = "(untagged)"
+)
+
+// CostRollups are the estimated monthly costs of all managed and unmanaged resources, grouped by workspace, by
+// division, and by the value of each cost allocation tag.
+type CostRollups struct {
+
+	// Workspaces are the costs of the resources managed by, or proposed to be codified within, each workspace.
+	Workspaces []CostRollup `json:"workspaces"`
+
+	// Divisions are the costs of the resources within each "provider-division".
+	Divisions []CostRollup `json:"divisions"`
+
+	// Tags are the costs of the resources with each value of each configured cost allocation tag.
+	Tags []CostRollup `json:"tags"`
+}
+
+// CostRollup is the estimated monthly cost of a group of resources, split into whether the resources are
+// controlled by Terraform or not.
+type CostRollup struct {
+	// Tag is the cost allocation tag by which resources are grouped, empty for workspace and division rollups.
+	Tag string `json:"tag,omitempty"`
+
+	Group                string  `json:"group"`
+	Resources            int     `json:"resources"`
+	ManagedMonthlyCost   float64 `json:"managed_monthly_cost"`
+	UnmanagedMonthlyCost float64 `json:"unmanaged_monthly_cost"`
+	TotalMonthlyCost     float64 `json:"total_monthly_cost"`
+}
+
+// buildCostRollups groups the monthly costs of the inventory's items. Items without a cost estimate are left out.
+func buildCostRollups(s sources, items []Item, costAllocationTags []string) CostRollups {
+	scanned := scannedResources(s.divisionToTerraformerState)
+
+	workspaces := map[string]*CostRollup{}
+	divisions := map[string]*CostRollup{}
+	tags := map[string]*CostRollup{}
+
+	for _, item := range items {
+		if item.MonthlyCost == nil {
+			continue
+		}
+
+		if item.Workspace != "" {
+			addToCostRollup(workspaces, CostRollup{Group: item.Workspace}, item)
+		}
+		if item.Division != "" {
+			addToCostRollup(divisions, CostRollup{Group: fmt.Sprintf("%v-%v", item.Provider, item.Division)}, item)
+		}
+
+		resourceTags := scanned[fmt.Sprintf("%v.%v", item.ResourceType, item.ResourceID)].tags
+		for _, tag := range costAllocationTags {
+			value, ok := resourceTags[tag]
+			if !ok || value == "" {
+				value = untaggedGroup
+			}
+			addToCostRollup(tags, CostRollup{Tag: tag, Group: value}, item)
+		}
+	}
+
+	return CostRollups{
+		Workspaces: sortedCostRollups(workspaces),
+		Divisions:  sortedCostRollups(divisions),
+		Tags:       sortedCostRollups(tags),
+	}
+}
+
+// addToCostRollup adds the monthly cost of item to the rollup of group, creating the rollup if needed.
+func addToCostRollup(rollups map[string]*CostRollup, group CostRollup, item Item) {
+	key := group.Tag + "\x00" + group.Group
+	if _, ok := rollups[key]; !ok {
+		rollups[key] = &group
+	}
+
+	rollup := rollups[key]
+	rollup.Resources++
+	if item.Status == StatusManaged {
+		rollup.ManagedMonthlyCost += *item.MonthlyCost
+	} else {
+		rollup.UnmanagedMonthlyCost += *item.MonthlyCost
+	}
+}
+
+// sortedCostRollups rounds the costs of the rollups and sorts them by tag and descending total cost.
+func sortedCostRollups(rollups map[string]*CostRollup) []CostRollup {
+	sorted := []CostRollup{}
+	for _, rollup := range rollups {
+		rollup.ManagedMonthlyCost = math.Round(rollup.ManagedMonthlyCost*100) / 100
+		rollup.UnmanagedMonthlyCost = math.Round(rollup.UnmanagedMonthlyCost*100) / 100
+		rollup.TotalMonthlyCost = math.Round((rollup.ManagedMonthlyCost+rollup.UnmanagedMonthlyCost)*100) / 100
+		sorted = append(sorted, *rollup)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Tag != sorted[j].Tag {
+			return sorted[i].Tag < sorted[j].Tag
+		}
+		if sorted[i].TotalMonthlyCost != sorted[j].TotalMonthlyCost {
+			return sorted[i].TotalMonthlyCost > sorted[j].TotalMonthlyCost
+		}
+		return sorted[i].Group < sorted[j].Group
+	})
+	return sorted
+}
+
+// nonSensitiveTags returns the cost allocation tags that do not match a sensitive attribute pattern, so that the
+// values of sensitive tags are never published as cost rollup groups.
+func nonSensitiveTags(costAllocationTags []string, patterns []*regexp.Regexp) []string {
+	tags := make([]string, 0, len(costAllocationTags))
+	for _, tag := range costAllocationTags {
+		if sensitiveattributes.Matches("tags."+tag, patterns) {
+			log.Warnf("[inventory_exporter] not rolling up costs by the sensitive tag %v", tag)
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// resourceTags returns the tags, or GCP labels, within the flattened attributes of a terraformer resource instance.
+func resourceTags(attributesFlat map[string]string) map[string]string {
+	tags := map[string]string{}
+	for attribute, value := range attributesFlat {
+		for _, prefix := range []string{"tags.", "labels."} {
+			if strings.HasPrefix(attribute, prefix) && attribute != prefix+"%" {
+				tags[strings.TrimPrefix(attribute, prefix)] = value
+			}
+		}
+	}
+	return tags
+}
+
+// writeCostRollups writes the cost rollups for the state of cloud report.
+func writeCostRollups(store artifacts.Store, rollups CostRollups) error {
+	rollupsJSON, err := json.MarshalIndent(rollups, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[write_cost_rollups][json.MarshalIndent]%w", err)
+	}
+
+	err = store.WriteFile(costRollupsPath, rollupsJSON, 0644)
+	if err != nil {
+		return fmt.Errorf("[write_cost_rollups][artifacts.WriteFile %v]%w", costRollupsPath, err)
+	}
+	return nil
+}
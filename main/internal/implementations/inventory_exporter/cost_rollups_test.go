@@ -0,0 +1,40 @@
+package inventoryExporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildCostRollups(t *testing.T) {
+	// Given
+	s := inventorySources()
+	vpc := s.divisionToTerraformerState["aws-111111111111"].Resources[0]
+	vpc.Instances[0].AttributesFlat["tags.%"] = "1"
+	vpc.Instances[0].AttributesFlat["tags.team"] = "network"
+	s.costEstimates["aws-111111111111"] = append(s.costEstimates["aws-111111111111"], costEstimate{ResourceName: "aws_vpc.tfer--vpc-123", MonthlyCost: "10"})
+
+	// When
+	rollups := buildCostRollups(s, buildInventory(s), []string{"team"})
+
+	// Then
+	assert.Equal(t, CostRollups{
+		Workspaces: []CostRollup{
+			{Group: "networking", Resources: 1, ManagedMonthlyCost: 10, TotalMonthlyCost: 10},
+			{Group: "storage", Resources: 1, UnmanagedMonthlyCost: 3.36, TotalMonthlyCost: 3.36},
+		},
+		Divisions: []CostRollup{
+			{Group: "aws-111111111111", Resources: 2, ManagedMonthlyCost: 10, UnmanagedMonthlyCost: 3.36, TotalMonthlyCost: 13.36},
+		},
+		Tags: []CostRollup{
+			{Tag: "team", Group: "network", Resources: 1, ManagedMonthlyCost: 10, TotalMonthlyCost: 10},
+			{Tag: "team", Group: untaggedGroup, Resources: 1, UnmanagedMonthlyCost: 3.36, TotalMonthlyCost: 3.36},
+		},
+	}, rollups)
+}
+
+func TestResourceTags(t *testing.T) {
+	assert.Equal(t, map[string]string{"team": "data", "env": "prod"}, resourceTags(map[string]string{
+		"id": "my-bucket", "tags.%": "1", "tags.team": "data", "labels.%": "1", "labels.env": "prod", "tags_all.team": "data",
+	}))
+}
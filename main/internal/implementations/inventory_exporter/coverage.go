@@ -0,0 +1,132 @@
+package inventoryExporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// iacCoveragePath is the path to which the IaC coverage is written for the state of cloud report.
+const iacCoveragePath = "mappings/iac-coverage.json"
+
+// Coverage is the share of scanned cloud resources under Terraform control, overall and within each division.
+type Coverage struct {
+	SchemaVersion int    `json:"schema_version"`
+	GeneratedAt   string `json:"generated_at"`
+
+	// Coverage is the percentage of all scanned resources that are managed by Terraform.
+	Coverage float64 `json:"coverage"`
+
+	// Managed counts the scanned resources that are managed by Terraform.
+	Managed int `json:"managed"`
+
+	// Unmanaged counts the scanned resources outside of Terraform control.
+	Unmanaged int `json:"unmanaged"`
+
+	// Divisions are the coverage of the scanned resources within each division.
+	Divisions []DivisionRunSummary `json:"divisions"`
+}
+
+// buildCoverage builds the IaC coverage of the job run from its run summary.
+func buildCoverage(summary RunSummary) Coverage {
+	coverage := Coverage{
+		SchemaVersion: SchemaVersion,
+		GeneratedAt:   summary.GeneratedAt,
+		Coverage:      summary.Coverage,
+		Divisions:     summary.Divisions,
+	}
+	for _, division := range summary.Divisions {
+		coverage.Managed += division.Managed
+		coverage.Unmanaged += division.Unmanaged
+	}
+	return coverage
+}
+
+// writeCoverage writes the IaC coverage as coverage.json and, in the OpenMetrics text format, as coverage.prom within
+// the output directory, as well as for the state of cloud report.
+func (e *InventoryExporter) writeCoverage(coverage Coverage) error {
+	coverageJSON, err := json.MarshalIndent(coverage, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[write_coverage][json.MarshalIndent]%w", err)
+	}
+
+	err = os.MkdirAll(e.config.OutputDirectory, 0755)
+	if err != nil {
+		return fmt.Errorf("[write_coverage][os.MkdirAll]%w", err)
+	}
+
+	err = os.WriteFile(filepath.Join(e.config.OutputDirectory, "coverage.json"), coverageJSON, 0644)
+	if err != nil {
+		return fmt.Errorf("[write_coverage][os.WriteFile coverage.json]%w", err)
+	}
+
+	err = os.WriteFile(filepath.Join(e.config.OutputDirectory, "coverage.prom"), []byte(coverageOpenMetrics(coverage)), 0644)
+	if err != nil {
+		return fmt.Errorf("[write_coverage][os.WriteFile coverage.prom]%w", err)
+	}
+
+	err = e.artifactStore.WriteFile(iacCoveragePath, coverageJSON, 0644)
+	if err != nil {
+		return fmt.Errorf("[write_coverage][artifacts.WriteFile %v]%w", iacCoveragePath, err)
+	}
+	return nil
+}
+
+// coverageOpenMetrics renders the IaC coverage in the OpenMetrics text format, e.g. for a node exporter textfile
+// collector or a Pushgateway.
+func coverageOpenMetrics(coverage Coverage) string {
+	var metrics strings.Builder
+
+	metrics.WriteString("# TYPE cloud_concierge_iac_coverage_percent gauge\n")
+	metrics.WriteString("# UNIT cloud_concierge_iac_coverage_percent percent\n")
+	metrics.WriteString("# HELP cloud_concierge_iac_coverage_percent Percentage of all scanned cloud resources managed by Terraform.\n")
+	metrics.WriteString(fmt.Sprintf("cloud_concierge_iac_coverage_percent %v\n", coverage.Coverage))
+
+	families := []struct {
+		name  string
+		unit  string
+		help  string
+		value func(division DivisionRunSummary) interface{}
+	}{
+		{
+			name:  "cloud_concierge_division_iac_coverage_percent",
+			unit:  "percent",
+			help:  "Percentage of the scanned cloud resources within the division managed by Terraform.",
+			value: func(division DivisionRunSummary) interface{} { return division.Coverage },
+		},
+		{
+			name:  "cloud_concierge_managed_resources",
+			help:  "Scanned cloud resources within the division managed by Terraform.",
+			value: func(division DivisionRunSummary) interface{} { return division.Managed },
+		},
+		{
+			name:  "cloud_concierge_unmanaged_resources",
+			help:  "Scanned cloud resources within the division outside of Terraform control.",
+			value: func(division DivisionRunSummary) interface{} { return division.Unmanaged },
+		},
+	}
+
+	for _, family := range families {
+		metrics.WriteString(fmt.Sprintf("# TYPE %v gauge\n", family.name))
+		if family.unit != "" {
+			metrics.WriteString(fmt.Sprintf("# UNIT %v %v\n", family.name, family.unit))
+		}
+		metrics.WriteString(fmt.Sprintf("# HELP %v %v\n", family.name, family.help))
+		for _, division := range coverage.Divisions {
+			metrics.WriteString(fmt.Sprintf(
+				"%v{provider=\"%v\",division=\"%v\"} %v\n",
+				family.name, openMetricsLabelValue(division.Provider), openMetricsLabelValue(division.Division), family.value(division),
+			))
+		}
+	}
+
+	metrics.WriteString("# EOF\n")
+	return metrics.String()
+}
+
+// openMetricsLabelValue escapes value for use as an OpenMetrics label value.
+func openMetricsLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
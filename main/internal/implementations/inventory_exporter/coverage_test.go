@@ -0,0 +1,82 @@
+package inventoryExporter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+)
+
+func TestBuildCoverage(t *testing.T) {
+	// Given
+	summary := RunSummary{
+		GeneratedAt: "2023-06-01T00:00:00Z",
+		Divisions: []DivisionRunSummary{
+			{Provider: "aws", Division: "111111111111", Managed: 3, Unmanaged: 1, Coverage: 75},
+			{Provider: "aws", Division: "222222222222", Managed: 0, Unmanaged: 1, Coverage: 0},
+		},
+		Coverage: 60,
+	}
+
+	// When
+	coverage := buildCoverage(summary)
+
+	// Then
+	assert.Equal(t, Coverage{
+		SchemaVersion: SchemaVersion,
+		GeneratedAt:   "2023-06-01T00:00:00Z",
+		Coverage:      60,
+		Managed:       3,
+		Unmanaged:     2,
+		Divisions:     summary.Divisions,
+	}, coverage)
+}
+
+func TestWriteCoverage(t *testing.T) {
+	// Given
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+	require.NoError(t, os.Mkdir("mappings", 0755))
+
+	exporter := &InventoryExporter{artifactStore: artifacts.NewMemoryStore(true), config: Config{OutputDirectory: "inventory"}}
+	coverage := Coverage{
+		SchemaVersion: SchemaVersion,
+		Coverage:      75,
+		Managed:       3,
+		Unmanaged:     1,
+		Divisions:     []DivisionRunSummary{{Provider: "aws", Division: "111111111111", Managed: 3, Unmanaged: 1, Coverage: 75}},
+	}
+
+	// When
+	err = exporter.writeCoverage(coverage)
+
+	// Then
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join("inventory", "coverage.json"))
+	assert.FileExists(t, iacCoveragePath)
+
+	metrics, err := os.ReadFile(filepath.Join("inventory", "coverage.prom"))
+	require.NoError(t, err)
+	assert.Equal(t, `# TYPE cloud_concierge_iac_coverage_percent gauge
+# UNIT cloud_concierge_iac_coverage_percent percent
+# HELP cloud_concierge_iac_coverage_percent Percentage of all scanned cloud resources managed by Terraform.
+cloud_concierge_iac_coverage_percent 75
+# TYPE cloud_concierge_division_iac_coverage_percent gauge
+# UNIT cloud_concierge_division_iac_coverage_percent percent
+# HELP cloud_concierge_division_iac_coverage_percent Percentage of the scanned cloud resources within the division managed by Terraform.
+cloud_concierge_division_iac_coverage_percent{provider="aws",division="111111111111"} 75
+# TYPE cloud_concierge_managed_resources gauge
+# HELP cloud_concierge_managed_resources Scanned cloud resources within the division managed by Terraform.
+cloud_concierge_managed_resources{provider="aws",division="111111111111"} 3
+# TYPE cloud_concierge_unmanaged_resources gauge
+# HELP cloud_concierge_unmanaged_resources Scanned cloud resources within the division outside of Terraform control.
+cloud_concierge_unmanaged_resources{provider="aws",division="111111111111"} 1
+# EOF
+`, string(metrics))
+}
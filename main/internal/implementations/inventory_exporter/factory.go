@@ -0,0 +1,21 @@
+package inventoryExporter
+
+import (
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
+)
+
+// Factory is a struct for creating different implementations of interfaces.InventoryExporter.
+type Factory struct {
+}
+
+// Instantiate creates an implementation of interfaces.InventoryExporter.
+func (f *Factory) Instantiate(environment string, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider, config Config, runStateStore interfaces.RunStateStore, artifactStore artifacts.Store) (interfaces.InventoryExporter, error) {
+	switch environment {
+	case "isolated":
+		return new(IsolatedInventoryExporter), nil
+	default:
+		return NewInventoryExporter(config, divisionToProvider, runStateStore, artifactStore), nil
+	}
+}
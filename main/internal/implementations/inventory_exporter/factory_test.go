@@ -0,0 +1,37 @@
+package inventoryExporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
+)
+
+func TestCreateInventoryExporter(t *testing.T) {
+	// Given
+	divisionToProvider := make(map[terraformValueObjects.Division]terraformValueObjects.Provider)
+	inventoryExporterFactory := new(Factory)
+
+	// When
+	inventoryExporter, err := inventoryExporterFactory.Instantiate("", divisionToProvider, Config{}, new(interfaces.RunStateStoreMock), artifacts.NewMemoryStore(true))
+
+	// Then
+	assert.Nil(t, err)
+	assert.IsType(t, &InventoryExporter{}, inventoryExporter)
+}
+
+func TestCreateIsolatedInventoryExporter(t *testing.T) {
+	// Given
+	divisionToProvider := make(map[terraformValueObjects.Division]terraformValueObjects.Provider)
+	inventoryExporterFactory := new(Factory)
+
+	// When
+	inventoryExporter, err := inventoryExporterFactory.Instantiate("isolated", divisionToProvider, Config{}, new(interfaces.RunStateStoreMock), artifacts.NewMemoryStore(true))
+
+	// Then
+	assert.Nil(t, err)
+	assert.IsType(t, &IsolatedInventoryExporter{}, inventoryExporter)
+}
@@ -0,0 +1,95 @@
+package inventoryExporter
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// findingUnmanaged is the finding of a resource outside of Terraform control.
+	findingUnmanaged = "unmanaged"
+
+	// findingDrifted is the finding of a managed resource whose attributes have drifted.
+	findingDrifted = "drifted"
+)
+
+// findingsCSVHeader is the header row of findings.csv.
+var findingsCSVHeader = []string{
+	"finding", "provider", "division", "workspace", "resource_type", "resource_name", "resource_id", "region",
+	"creator", "last_modified_by", "monthly_cost", "drifted_attributes",
+}
+
+// findingsRecords returns a findings.csv row for each unmanaged resource and each drifted managed resource,
+// enriched with the region, creator and cost of its inventory item.
+func findingsRecords(results Results, items []Item) [][]string {
+	managedItems := map[string]Item{}
+	records := [][]string{}
+
+	for _, item := range items {
+		if item.Status == StatusManaged {
+			managedItems[fmt.Sprintf("%v.%v", item.ResourceType, item.ResourceID)] = item
+			continue
+		}
+		records = append(records, findingRecord(findingUnmanaged, item, ""))
+	}
+
+	for _, drifted := range results.Drift {
+		attributes := make([]string, 0, len(drifted.Attributes))
+		for _, attribute := range drifted.Attributes {
+			attributes = append(attributes, attribute.Attribute)
+		}
+
+		item, ok := managedItems[fmt.Sprintf("%v.%v", drifted.ResourceType, drifted.ResourceID)]
+		if !ok {
+			item = Item{
+				Provider:     strings.Split(drifted.ResourceType, "_")[0],
+				Division:     drifted.Division,
+				Workspace:    drifted.StateFile,
+				ResourceType: drifted.ResourceType,
+				ResourceName: strings.TrimPrefix(drifted.ResourceAddress, drifted.ResourceType+"."),
+				ResourceID:   drifted.ResourceID,
+			}
+		}
+		records = append(records, findingRecord(findingDrifted, item, strings.Join(attributes, ";")))
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return strings.Join(records[i], "\x00") < strings.Join(records[j], "\x00")
+	})
+	return records
+}
+
+// findingRecord returns the findings.csv row of item with the passed finding and drifted attributes.
+func findingRecord(finding string, item Item, driftedAttributes string) []string {
+	record := item.csvRecord()
+	return []string{
+		finding, item.Provider, item.Division, item.Workspace, item.ResourceType, item.ResourceName, item.ResourceID,
+		item.Region, item.Owner, item.LastModifiedBy, record[len(record)-1], driftedAttributes,
+	}
+}
+
+// writeFindings writes the unmanaged and drifted resources as findings.csv within the output directory, for import
+// into spreadsheets and BI tools.
+func (e *InventoryExporter) writeFindings(results Results, items []Item) error {
+	err := os.MkdirAll(e.config.OutputDirectory, 0755)
+	if err != nil {
+		return fmt.Errorf("[write_findings][os.MkdirAll]%w", err)
+	}
+
+	csvFile, err := os.Create(filepath.Join(e.config.OutputDirectory, "findings.csv"))
+	if err != nil {
+		return fmt.Errorf("[write_findings][os.Create findings.csv]%w", err)
+	}
+	defer csvFile.Close()
+
+	writer := csv.NewWriter(csvFile)
+	err = writer.WriteAll(append([][]string{findingsCSVHeader}, findingsRecords(results, items)...))
+	if err != nil {
+		return fmt.Errorf("[write_findings][csv.WriteAll]%w", err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package inventoryExporter
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindingsRecords(t *testing.T) {
+	// Given
+	s := inventorySources()
+	items := buildInventory(s)
+	results := Results{Drift: []DriftedResource{
+		{
+			StateFile:       "networking",
+			Division:        "111111111111",
+			ResourceAddress: "module.vpc.aws_vpc.main",
+			ResourceType:    "aws_vpc",
+			ResourceID:      "vpc-123",
+			Attributes:      []DriftedAttribute{{Attribute: "cidr_block"}, {Attribute: "tags.env"}},
+		},
+		{
+			StateFile:       "compute",
+			Division:        "111111111111",
+			ResourceAddress: "aws_instance.web",
+			ResourceType:    "aws_instance",
+			ResourceID:      "i-123",
+			Attributes:      []DriftedAttribute{{Attribute: "instance_type"}},
+		},
+	}}
+
+	// When
+	records := findingsRecords(results, items)
+
+	// Then
+	assert.Equal(t, [][]string{
+		{"drifted", "aws", "111111111111", "compute", "aws_instance", "web", "i-123", "", "", "", "", "instance_type"},
+		{"drifted", "aws", "111111111111", "networking", "aws_vpc", "module.vpc.main", "vpc-123", "us-west-2", "", "", "", "cidr_block;tags.env"},
+		{"unmanaged", "aws", "111111111111", "storage", "aws_s3_bucket", "my_bucket", "my-bucket", "us-east-1", "alice", "bob", "3.36", ""},
+	}, records)
+}
+
+func TestWriteFindings(t *testing.T) {
+	// Given
+	outputDirectory := filepath.Join(t.TempDir(), "inventory")
+	exporter := &InventoryExporter{config: Config{OutputDirectory: outputDirectory}}
+
+	// When
+	err := exporter.writeFindings(Results{}, buildInventory(inventorySources()))
+
+	// Then
+	require.NoError(t, err)
+
+	csvFile, err := os.Open(filepath.Join(outputDirectory, "findings.csv"))
+	require.NoError(t, err)
+	defer csvFile.Close()
+
+	rows, err := csv.NewReader(csvFile).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, findingsCSVHeader, rows[0])
+	assert.Equal(t, "unmanaged", rows[1][0])
+}
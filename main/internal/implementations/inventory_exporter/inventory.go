@@ -0,0 +1,388 @@
+package inventoryExporter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/hclcreate"
+	resourcesCalculator "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/resources_calculator"
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+// SchemaVersion is the version of the inventory schema, incremented whenever a field is removed or changes meaning.
+const SchemaVersion = 1
+
+const (
+	// StatusManaged is the status of a resource defined within a workspace's Terraform state.
+	StatusManaged = "managed"
+
+	// StatusUnmanaged is the status of a resource found in the cloud but not defined within any Terraform state.
+	StatusUnmanaged = "unmanaged"
+)
+
+// Inventory is the complete inventory document written to inventory.json and pushed to the configured webhook.
+type Inventory struct {
+
+	// SchemaVersion is the version of the inventory schema.
+	SchemaVersion int `json:"schema_version"`
+
+	// Currency is the ISO 4217 code of the currency of the resources' monthly costs.
+	Currency string `json:"currency"`
+
+	// Resources is the list of all managed and unmanaged resources.
+	Resources []Item `json:"resources"`
+}
+
+// Item is a single resource within the inventory. Fields that could not be determined are empty, and MonthlyCost
+// is null when no cost estimate is available for the resource.
+type Item struct {
+
+	// Status is either "managed" or "unmanaged".
+	Status string `json:"status"`
+
+	// Provider is the Terraform provider of the resource, e.g. "aws", "google" or "azurerm".
+	Provider string `json:"provider"`
+
+	// Division is the AWS account, GCP project or Azure resource group the resource was scanned within.
+	Division string `json:"division"`
+
+	// Workspace is the workspace whose state manages the resource, or in which an unmanaged resource is proposed to
+	// be codified.
+	Workspace string `json:"workspace"`
+
+	// ResourceType is the Terraform resource type.
+	ResourceType string `json:"resource_type"`
+
+	// ResourceName is the Terraform resource name, prefixed by its module address for resources within modules.
+	ResourceName string `json:"resource_name"`
+
+	// ResourceID is the cloud provider's identifier of the resource.
+	ResourceID string `json:"resource_id"`
+
+	// Region is the cloud region of the resource.
+	Region string `json:"region"`
+
+	// Owner is the cloud actor that created the resource.
+	Owner string `json:"owner"`
+
+	// LastModifiedBy is the cloud actor that most recently modified the resource.
+	LastModifiedBy string `json:"last_modified_by"`
+
+	// MonthlyCost is the estimated monthly cost of the resource in the inventory's currency.
+	MonthlyCost *float64 `json:"monthly_cost"`
+}
+
+// csvHeader is the header row of inventory.csv, in the same order as the fields of Item.
+var csvHeader = []string{
+	"status", "provider", "division", "workspace", "resource_type", "resource_name",
+	"resource_id", "region", "owner", "last_modified_by", "monthly_cost",
+}
+
+// csvRecord returns the item as a row of inventory.csv.
+func (i Item) csvRecord() []string {
+	monthlyCost := ""
+	if i.MonthlyCost != nil {
+		monthlyCost = strconv.FormatFloat(*i.MonthlyCost, 'f', -1, 64)
+	}
+
+	return []string{
+		i.Status, i.Provider, i.Division, i.Workspace, i.ResourceType, i.ResourceName,
+		i.ResourceID, i.Region, i.Owner, i.LastModifiedBy, monthlyCost,
+	}
+}
+
+// cloudActorAction is a single creation or modification action within resources-to-cloud-actions.json.
+type cloudActorAction struct {
+	Actor     string `json:"actor"`
+	Timestamp string `json:"timestamp"`
+}
+
+// resourceCloudActions are the recorded actions of a resource within resources-to-cloud-actions.json.
+type resourceCloudActions struct {
+	Creation *cloudActorAction `json:"creation"`
+	Modified *cloudActorAction `json:"modified"`
+}
+
+// costCurrency is the display currency of the cost estimates within cost-currency.json.
+type costCurrency struct {
+	Currency string
+}
+
+// costEstimate is a single cost component within division-to-cost-estimates.json.
+type costEstimate struct {
+	ResourceName string `json:"resource_name"`
+	MonthlyCost  string `json:"monthly_cost"`
+}
+
+// sources are the files produced earlier in the job run from which the inventory is built.
+type sources struct {
+
+	// workspaceToState is a map between a workspace and its Terraform state.
+	workspaceToState map[string]driftDetector.TerraformStateFile
+
+	// divisionToTerraformerState is a map between a "provider-division" and the terraformer state of its scan.
+	divisionToTerraformerState map[string]driftDetector.TerraformerStateFile
+
+	// newResourcesToWorkspace is a map between a "provider-division.type.name" and its proposed workspace.
+	newResourcesToWorkspace map[string]string
+
+	// divisionToNewResources is the data of each new resource, keyed by "provider-division" and resource id.
+	divisionToNewResources resourcesCalculator.DivisionToNewResources
+
+	// resourceNames are the names of the new resources within the generated code, keyed by
+	// "provider-division.type.name".
+	resourceNames hclcreate.ResourceNames
+
+	// cloudActions are the recorded cloud actor actions, keyed by provider, division and "type.name".
+	cloudActions map[string]map[string]map[string]resourceCloudActions
+
+	// costEstimates are the cost components of each "provider-division".
+	costEstimates map[string][]costEstimate
+
+	// costCurrency is the currency in which the cost estimates are expressed.
+	costCurrency costCurrency
+
+	// driftAttributeDiffs are the attribute level diffs of each drifted managed resource instance.
+	driftAttributeDiffs []driftDetector.ResourceAttributeDiff
+
+	// deletedResources are the managed resources deleted outside of Terraform.
+	deletedResources []driftDetector.DeletedResource
+}
+
+// terraformerResource is the scan data of a single resource instance found by terraformer.
+type terraformerResource struct {
+	provider string
+	division string
+	name     string
+	region   string
+	tags     map[string]string
+}
+
+// loadSources reads the inventory sources from the job's working directory. Files that were not produced during the
+// job run, for example because cost estimation was skipped, are treated as empty.
+func loadSources(store artifacts.Store, workspaceToDirectory map[string]string, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider) (sources, error) {
+	s := sources{
+		workspaceToState:           map[string]driftDetector.TerraformStateFile{},
+		divisionToTerraformerState: map[string]driftDetector.TerraformerStateFile{},
+		newResourcesToWorkspace:    map[string]string{},
+		divisionToNewResources:     resourcesCalculator.DivisionToNewResources{},
+		resourceNames:              hclcreate.ResourceNames{},
+		cloudActions:               map[string]map[string]map[string]resourceCloudActions{},
+		costEstimates:              map[string][]costEstimate{},
+		costCurrency:               costCurrency{Currency: "USD"},
+		driftAttributeDiffs:        []driftDetector.ResourceAttributeDiff{},
+		deletedResources:           []driftDetector.DeletedResource{},
+	}
+
+	for workspace := range workspaceToDirectory {
+		state := driftDetector.TerraformStateFile{}
+		if err := readOptionalJSON(store, fmt.Sprintf("state_files/%v.json", workspace), &state); err != nil {
+			return sources{}, err
+		}
+		s.workspaceToState[workspace] = state
+	}
+
+	for division, provider := range divisionToProvider {
+		fullDivisionName := fmt.Sprintf("%v-%v", provider, division)
+		content, err := os.ReadFile(fmt.Sprintf("current_cloud/%v/terraform.tfstate", fullDivisionName))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return sources{}, fmt.Errorf("[load_sources][os.ReadFile terraform.tfstate for %v]%w", fullDivisionName, err)
+		}
+
+		state, err := driftDetector.ParseTerraformerStateFile(content)
+		if err != nil {
+			return sources{}, fmt.Errorf("[load_sources][driftDetector.ParseTerraformerStateFile]%w", err)
+		}
+		s.divisionToTerraformerState[fullDivisionName] = state
+	}
+
+	optionalFiles := map[string]interface{}{
+		"mappings/new-resources-to-workspace.json": &s.newResourcesToWorkspace,
+		"mappings/division-to-new-resources.json":  &s.divisionToNewResources,
+		hclcreate.ResourceNamesPath:                &s.resourceNames,
+		"mappings/resources-to-cloud-actions.json": &s.cloudActions,
+		"mappings/division-to-cost-estimates.json": &s.costEstimates,
+		"mappings/cost-currency.json":              &s.costCurrency,
+		"mappings/drift-attribute-diffs.json":      &s.driftAttributeDiffs,
+		"mappings/drift-resources-deleted.json":    &s.deletedResources,
+	}
+	for path, target := range optionalFiles {
+		if err := readOptionalJSON(store, path, target); err != nil {
+			return sources{}, err
+		}
+	}
+
+	return s, nil
+}
+
+// readOptionalJSON unmarshals the json file at path into target, leaving target unchanged if the file does not exist.
+func readOptionalJSON(store artifacts.Store, path string, target interface{}) error {
+	content, err := store.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("[read_optional_json][artifacts.ReadFile %v]%w", path, err)
+	}
+
+	if err = json.Unmarshal(content, target); err != nil {
+		return fmt.Errorf("[read_optional_json][json.Unmarshal %v]%w", path, err)
+	}
+	return nil
+}
+
+// buildInventory builds the sorted list of all managed and unmanaged resources from the job run's sources.
+func buildInventory(s sources) []Item {
+	scanned := scannedResources(s.divisionToTerraformerState)
+	costs := monthlyCosts(s.costEstimates, s.resourceNames)
+
+	items := make([]Item, 0)
+	for workspace, state := range s.workspaceToState {
+		for _, resource := range state.Resources {
+			if resource.Mode != "managed" {
+				continue
+			}
+
+			for _, instance := range resource.Instances {
+				resourceID := fmt.Sprint(instance.Attributes["id"])
+				item := Item{
+					Status:       StatusManaged,
+					Provider:     strings.Split(resource.Type, "_")[0],
+					Workspace:    workspace,
+					ResourceType: resource.Type,
+					ResourceName: resourceAddressName(resource.Module, resource.Name),
+					ResourceID:   resourceID,
+					Region:       stringAttribute(instance.Attributes, "region", "location"),
+				}
+
+				if scan, ok := scanned[fmt.Sprintf("%v.%v", resource.Type, resourceID)]; ok {
+					item.Provider = scan.provider
+					item.Division = scan.division
+					item.Region = scan.region
+					enrichItem(&item, s.resourceNames.Name(scan.provider+"-"+scan.division, resource.Type, scan.name), s.cloudActions, costs)
+				}
+				items = append(items, item)
+			}
+		}
+	}
+
+	for fullDivisionName, newResources := range s.divisionToNewResources {
+		provider, division, _ := strings.Cut(string(fullDivisionName), "-")
+		for resourceID, resourceData := range newResources {
+			resourceKey := fmt.Sprintf("%v.%v.%v", fullDivisionName, resourceData.ResourceType, resourceData.ResourceTerraformerName)
+			workspace, ok := s.newResourcesToWorkspace[resourceKey]
+			if !ok {
+				continue
+			}
+
+			item := Item{
+				Status:       StatusUnmanaged,
+				Provider:     provider,
+				Division:     division,
+				Workspace:    workspace,
+				ResourceType: resourceData.ResourceType,
+				ResourceName: s.resourceNames.Name(string(fullDivisionName), resourceData.ResourceType, resourceData.ResourceTerraformerName),
+				ResourceID:   string(resourceID),
+				Region:       resourceData.Region,
+			}
+			enrichItem(&item, item.ResourceName, s.cloudActions, costs)
+			items = append(items, item)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return strings.Join(items[i].csvRecord(), "\x00") < strings.Join(items[j].csvRecord(), "\x00")
+	})
+	return items
+}
+
+// scannedResources indexes every resource instance found by terraformer by its "type.id".
+func scannedResources(divisionToTerraformerState map[string]driftDetector.TerraformerStateFile) map[string]terraformerResource {
+	scanned := map[string]terraformerResource{}
+	for fullDivisionName, state := range divisionToTerraformerState {
+		provider, division, _ := strings.Cut(fullDivisionName, "-")
+		for _, resource := range state.Resources {
+			for _, instance := range resource.Instances {
+				region, err := driftDetector.ParseRegionFromTfStateMap(instance.AttributesFlat, provider)
+				if err != nil {
+					region = ""
+				}
+
+				scanned[fmt.Sprintf("%v.%v", resource.Type, instance.AttributesFlat["id"])] = terraformerResource{
+					provider: provider,
+					division: division,
+					name:     resource.Name,
+					region:   region,
+					tags:     resourceTags(instance.AttributesFlat),
+				}
+			}
+		}
+	}
+	return scanned
+}
+
+// monthlyCosts sums the monthly cost components of each resource, keyed by "provider-division" and the "type.name"
+// address of the resource within the generated code.
+func monthlyCosts(costEstimates map[string][]costEstimate, resourceNames hclcreate.ResourceNames) map[string]map[string]float64 {
+	costs := map[string]map[string]float64{}
+	for fullDivisionName, estimates := range costEstimates {
+		divisionCosts := map[string]float64{}
+		for _, estimate := range estimates {
+			monthlyCost, err := strconv.ParseFloat(estimate.MonthlyCost, 64)
+			if err != nil {
+				continue
+			}
+			resourceName := resourceNames.Address(fullDivisionName, estimate.ResourceName)
+			divisionCosts[resourceName] += monthlyCost
+		}
+		costs[fullDivisionName] = divisionCosts
+	}
+	return costs
+}
+
+// enrichItem sets the owner, last modifier and monthly cost of item from the cloud actor and cost estimate data of
+// the resource named generatedName within the generated code.
+func enrichItem(item *Item, generatedName string, cloudActions map[string]map[string]map[string]resourceCloudActions, costs map[string]map[string]float64) {
+	resourceName := fmt.Sprintf("%v.%v", item.ResourceType, generatedName)
+
+	if actions, ok := cloudActions[item.Provider][item.Division][resourceName]; ok {
+		if actions.Creation != nil {
+			item.Owner = actions.Creation.Actor
+		}
+		if actions.Modified != nil {
+			item.LastModifiedBy = actions.Modified.Actor
+		}
+	}
+
+	if monthlyCost, ok := costs[fmt.Sprintf("%v-%v", item.Provider, item.Division)][resourceName]; ok {
+		rounded := math.Round(monthlyCost*100) / 100
+		item.MonthlyCost = &rounded
+	}
+}
+
+// resourceAddressName returns the resource name prefixed by its module address, if the resource is within a module.
+func resourceAddressName(module string, name string) string {
+	if module == "" || module == "root" {
+		return name
+	}
+	return fmt.Sprintf("%v.%v", module, name)
+}
+
+// stringAttribute returns the first of keys that is a non-empty string attribute.
+func stringAttribute(attributes map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if value, ok := attributes[key].(string); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,183 @@
+package inventoryExporter
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/sensitiveattributes"
+)
+
+// Config is the configuration of the inventory export and of the external systems it is pushed to.
+type Config struct {
+
+	// OutputDirectory is the directory to which inventory.json, inventory.csv, findings.csv of the unmanaged and
+	// drifted resources, and the IaC coverage metrics, coverage.json and coverage.prom, are written.
+	OutputDirectory string
+
+	// ResultsPath is the path to which the machine-readable results of the job run are written as json.
+	ResultsPath string
+
+	// CostAllocationTags are the tag, or GCP label, keys by which resource costs are rolled up, e.g. "team" or "env".
+	CostAllocationTags []string
+
+	// SensitiveAttributePatterns are regular expressions, matched case-insensitively against attribute paths, for
+	// attributes whose values are masked within the results, in addition to the shared sensitive attribute patterns.
+	SensitiveAttributePatterns []string
+
+	// WebhookURL, when set, receives the inventory document as a json POST request.
+	WebhookURL string
+
+	// WebhookToken is the bearer token passed when posting to WebhookURL.
+	WebhookToken string
+
+	// ServiceNowInstanceURL, when set, is the ServiceNow instance whose CMDB the inventory is reconciled into,
+	// e.g. https://my-instance.service-now.com.
+	ServiceNowInstanceURL string
+
+	// ServiceNowUsername is the user authenticating with the ServiceNow instance.
+	ServiceNowUsername string
+
+	// ServiceNowPassword is the password of ServiceNowUsername.
+	ServiceNowPassword string
+
+	// ServiceNowClassName is the CMDB class that inventory items are reconciled as.
+	ServiceNowClassName string
+}
+
+// InventoryExporter is a struct that implements interfaces.InventoryExporter.
+type InventoryExporter struct {
+
+	// config is the configuration of the inventory export.
+	config Config
+
+	// divisionToProvider is a map between a division and its cloud provider.
+	divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider
+
+	// runStateStore persists the summaries of previous job runs, from which the drift trend is reported.
+	runStateStore interfaces.RunStateStore
+
+	// artifactStore holds the mappings from which the inventory is built, and to which it is written.
+	artifactStore artifacts.Store
+}
+
+// NewInventoryExporter creates a new instance of InventoryExporter.
+func NewInventoryExporter(config Config, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider, runStateStore interfaces.RunStateStore, artifactStore artifacts.Store) interfaces.InventoryExporter {
+	return &InventoryExporter{config: config, divisionToProvider: divisionToProvider, runStateStore: runStateStore, artifactStore: artifactStore}
+}
+
+// Execute writes the inventory of all managed and unmanaged resources identified during the job run, and pushes
+// it to any configured external systems.
+func (e *InventoryExporter) Execute(ctx context.Context, workspaceToDirectory map[string]string) error {
+	sensitivePatterns, err := sensitiveattributes.Compile(e.config.SensitiveAttributePatterns)
+	if err != nil {
+		return fmt.Errorf("[inventory_exporter][sensitiveattributes.Compile]%w", err)
+	}
+
+	s, err := loadSources(e.artifactStore, workspaceToDirectory, e.divisionToProvider)
+	if err != nil {
+		return fmt.Errorf("[inventory_exporter]%w", err)
+	}
+
+	inventory := Inventory{SchemaVersion: SchemaVersion, Currency: s.costCurrency.Currency, Resources: buildInventory(s)}
+
+	err = e.writeInventory(inventory)
+	if err != nil {
+		return fmt.Errorf("[inventory_exporter]%w", err)
+	}
+	log.Infof("exported inventory of %v resources", len(inventory.Resources))
+
+	results := buildResults(s, inventory.Resources)
+	results.GeneratedAt = time.Now().UTC().Format(time.RFC3339)
+	results.CostRollups = buildCostRollups(s, inventory.Resources, nonSensitiveTags(e.config.CostAllocationTags, sensitivePatterns))
+	results.MaskSensitiveValues(sensitivePatterns)
+	err = e.writeResults(results)
+	if err != nil {
+		return fmt.Errorf("[inventory_exporter]%w", err)
+	}
+
+	err = e.writeFindings(results, inventory.Resources)
+	if err != nil {
+		return fmt.Errorf("[inventory_exporter]%w", err)
+	}
+
+	err = writeCostRollups(e.artifactStore, results.CostRollups)
+	if err != nil {
+		return fmt.Errorf("[inventory_exporter]%w", err)
+	}
+
+	runSummary := buildRunSummary(results, inventory.Resources)
+	err = e.writeCoverage(buildCoverage(runSummary))
+	if err != nil {
+		return fmt.Errorf("[inventory_exporter]%w", err)
+	}
+
+	// The drift trend is informational, so a failure to record it does not fail the job run.
+	err = e.recordDriftTrend(ctx, runSummary)
+	if err != nil {
+		log.Warnf("[inventory_exporter][drift trend not recorded]%s", err.Error())
+	}
+
+	// Pushing the inventory is informational, so, as with notifications, a failure to deliver it does not fail the
+	// job run.
+	if e.config.WebhookURL != "" {
+		err = pushToWebhook(ctx, e.config, inventory)
+		if err != nil {
+			log.Warnf("[inventory_exporter][inventory not pushed to webhook]%s", err.Error())
+		}
+	}
+
+	if e.config.ServiceNowInstanceURL != "" {
+		err = pushToServiceNow(ctx, e.config, inventory)
+		if err != nil {
+			log.Warnf("[inventory_exporter][inventory not pushed to servicenow]%s", err.Error())
+		}
+	}
+
+	return nil
+}
+
+// writeInventory writes the inventory as inventory.json and inventory.csv within the output directory.
+func (e *InventoryExporter) writeInventory(inventory Inventory) error {
+	err := os.MkdirAll(e.config.OutputDirectory, 0755)
+	if err != nil {
+		return fmt.Errorf("[write_inventory][os.MkdirAll]%w", err)
+	}
+
+	inventoryJSON, err := json.MarshalIndent(inventory, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[write_inventory][json.MarshalIndent]%w", err)
+	}
+
+	err = os.WriteFile(filepath.Join(e.config.OutputDirectory, "inventory.json"), inventoryJSON, 0644)
+	if err != nil {
+		return fmt.Errorf("[write_inventory][os.WriteFile inventory.json]%w", err)
+	}
+
+	csvFile, err := os.Create(filepath.Join(e.config.OutputDirectory, "inventory.csv"))
+	if err != nil {
+		return fmt.Errorf("[write_inventory][os.Create inventory.csv]%w", err)
+	}
+	defer csvFile.Close()
+
+	writer := csv.NewWriter(csvFile)
+	records := [][]string{csvHeader}
+	for _, item := range inventory.Resources {
+		records = append(records, item.csvRecord())
+	}
+
+	err = writer.WriteAll(records)
+	if err != nil {
+		return fmt.Errorf("[write_inventory][csv.WriteAll]%w", err)
+	}
+	return nil
+}
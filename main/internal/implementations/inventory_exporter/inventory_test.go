@@ -0,0 +1,144 @@
+package inventoryExporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/hclcreate"
+	resourcesCalculator "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/resources_calculator"
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+)
+
+func inventorySources() sources {
+	return sources{
+		workspaceToState: map[string]driftDetector.TerraformStateFile{
+			"networking": {Resources: []*driftDetector.Resource{
+				{
+					Mode: "managed", Module: "module.vpc", Type: "aws_vpc", Name: "main",
+					Instances: []driftDetector.ResourceInstance{{Attributes: map[string]interface{}{"id": "vpc-123"}}},
+				},
+				{
+					Mode: "data", Type: "aws_caller_identity", Name: "current",
+					Instances: []driftDetector.ResourceInstance{{Attributes: map[string]interface{}{"id": "111111111111"}}},
+				},
+				{
+					Mode: "managed", Type: "google_storage_bucket", Name: "logs",
+					Instances: []driftDetector.ResourceInstance{{Attributes: map[string]interface{}{"id": "logs-bucket", "location": "US"}}},
+				},
+			}},
+		},
+		divisionToTerraformerState: map[string]driftDetector.TerraformerStateFile{
+			"aws-111111111111": {Resources: []*driftDetector.TerraformerResource{
+				{
+					Type: "aws_vpc", Name: "tfer--vpc-123",
+					Instances: []driftDetector.TerraformerInstance{{AttributesFlat: map[string]string{"id": "vpc-123", "arn": "arn:aws:ec2:us-west-2:111111111111:vpc/vpc-123"}}},
+				},
+			}},
+		},
+		newResourcesToWorkspace: map[string]string{
+			"aws-111111111111.aws_s3_bucket.tfer--my-bucket": "storage",
+		},
+		divisionToNewResources: resourcesCalculator.DivisionToNewResources{
+			"aws-111111111111": {
+				"my-bucket":   {ResourceType: "aws_s3_bucket", ResourceTerraformerName: "tfer--my-bucket", Region: "us-east-1"},
+				"excluded-id": {ResourceType: "aws_s3_bucket", ResourceTerraformerName: "tfer--excluded", Region: "us-east-1"},
+			},
+		},
+		cloudActions: map[string]map[string]map[string]resourceCloudActions{
+			"aws": {"111111111111": {
+				"aws_s3_bucket.my_bucket": {
+					Creation: &cloudActorAction{Actor: "alice", Timestamp: "2023-01-01"},
+					Modified: &cloudActorAction{Actor: "bob", Timestamp: "2023-02-01"},
+				},
+			}},
+		},
+		costEstimates: map[string][]costEstimate{
+			"aws-111111111111": {
+				{ResourceName: "aws_s3_bucket.tfer--my-bucket", MonthlyCost: "1.255"},
+				{ResourceName: "aws_s3_bucket.tfer--my-bucket", MonthlyCost: "2.1"},
+				{ResourceName: "aws_s3_bucket.tfer--my-bucket", MonthlyCost: ""},
+			},
+		},
+	}
+}
+
+func TestBuildInventory(t *testing.T) {
+	// Given
+	s := inventorySources()
+
+	// When
+	items := buildInventory(s)
+
+	// Then
+	require.Len(t, items, 3)
+
+	assert.Equal(t, Item{
+		Status:       StatusManaged,
+		Provider:     "aws",
+		Division:     "111111111111",
+		Workspace:    "networking",
+		ResourceType: "aws_vpc",
+		ResourceName: "module.vpc.main",
+		ResourceID:   "vpc-123",
+		Region:       "us-west-2",
+	}, items[0])
+
+	assert.Equal(t, Item{
+		Status:       StatusManaged,
+		Provider:     "google",
+		Workspace:    "networking",
+		ResourceType: "google_storage_bucket",
+		ResourceName: "logs",
+		ResourceID:   "logs-bucket",
+		Region:       "US",
+	}, items[1])
+
+	monthlyCost := 3.36
+	assert.Equal(t, Item{
+		Status:         StatusUnmanaged,
+		Provider:       "aws",
+		Division:       "111111111111",
+		Workspace:      "storage",
+		ResourceType:   "aws_s3_bucket",
+		ResourceName:   "my_bucket",
+		ResourceID:     "my-bucket",
+		Region:         "us-east-1",
+		Owner:          "alice",
+		LastModifiedBy: "bob",
+		MonthlyCost:    &monthlyCost,
+	}, items[2])
+}
+
+func TestBuildInventory_SuffixedName(t *testing.T) {
+	// Given
+	s := inventorySources()
+	s.resourceNames = hclcreate.ResourceNames{"aws-111111111111.aws_s3_bucket.tfer--my-bucket": "my_bucket_2"}
+	s.cloudActions["aws"]["111111111111"]["aws_s3_bucket.my_bucket_2"] = resourceCloudActions{
+		Creation: &cloudActorAction{Actor: "carol", Timestamp: "2023-03-01"},
+	}
+
+	// When
+	items := buildInventory(s)
+
+	// Then
+	require.Len(t, items, 3)
+	assert.Equal(t, "my_bucket_2", items[2].ResourceName)
+	assert.Equal(t, "carol", items[2].Owner)
+	require.NotNil(t, items[2].MonthlyCost)
+	assert.Equal(t, 3.36, *items[2].MonthlyCost)
+}
+
+func TestItemCSVRecord(t *testing.T) {
+	// Given
+	monthlyCost := 3.36
+	item := Item{Status: StatusUnmanaged, Provider: "aws", ResourceType: "aws_s3_bucket", ResourceName: "my_bucket", MonthlyCost: &monthlyCost}
+
+	// When
+	record := item.csvRecord()
+
+	// Then
+	assert.Len(t, record, len(csvHeader))
+	assert.Equal(t, []string{"unmanaged", "aws", "", "", "aws_s3_bucket", "my_bucket", "", "", "", "", "3.36"}, record)
+}
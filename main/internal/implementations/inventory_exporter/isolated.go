@@ -0,0 +1,16 @@
+package inventoryExporter
+
+import (
+	"context"
+)
+
+// IsolatedInventoryExporter is a struct that implements interfaces.InventoryExporter for the purpose
+// of end-to-end testing.
+type IsolatedInventoryExporter struct {
+}
+
+// Execute writes the inventory of all managed and unmanaged resources identified during the job run, and pushes
+// it to any configured external systems.
+func (e *IsolatedInventoryExporter) Execute(ctx context.Context, workspaceToDirectory map[string]string) error {
+	return nil
+}
@@ -0,0 +1,102 @@
+package inventoryExporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// serviceNowBatchSize is the maximum number of items reconciled into the ServiceNow CMDB within a single request.
+const serviceNowBatchSize = 100
+
+// serviceNowItem is a single configuration item within a ServiceNow Identification and Reconciliation request.
+type serviceNowItem struct {
+	ClassName string            `json:"className"`
+	Values    map[string]string `json:"values"`
+}
+
+// pushToWebhook posts the complete inventory document to the configured webhook.
+func pushToWebhook(ctx context.Context, config Config, inventory Inventory) error {
+	body, err := json.Marshal(inventory)
+	if err != nil {
+		return fmt.Errorf("[push_to_webhook][json.Marshal]%w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, config.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("[push_to_webhook][http.NewRequestWithContext]%w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if config.WebhookToken != "" {
+		request.Header.Set("Authorization", fmt.Sprintf("Bearer %v", config.WebhookToken))
+	}
+
+	err = doRequest(request)
+	if err != nil {
+		return fmt.Errorf("[push_to_webhook]%w", err)
+	}
+	return nil
+}
+
+// pushToServiceNow reconciles the inventory into the ServiceNow CMDB through the Identification and Reconciliation
+// API, so that resources already present in the CMDB are updated rather than duplicated.
+func pushToServiceNow(ctx context.Context, config Config, inventory Inventory) error {
+	endpoint := fmt.Sprintf("%v/api/now/identifyreconcile?sysparm_data_source=ServiceNow", strings.TrimSuffix(config.ServiceNowInstanceURL, "/"))
+
+	for start := 0; start < len(inventory.Resources); start += serviceNowBatchSize {
+		end := start + serviceNowBatchSize
+		if end > len(inventory.Resources) {
+			end = len(inventory.Resources)
+		}
+
+		items := make([]serviceNowItem, 0, end-start)
+		for _, item := range inventory.Resources[start:end] {
+			items = append(items, serviceNowItem{
+				ClassName: config.ServiceNowClassName,
+				Values: map[string]string{
+					"name":              fmt.Sprintf("%v.%v", item.ResourceType, item.ResourceName),
+					"object_id":         item.ResourceID,
+					"short_description": fmt.Sprintf("%v %v resource in %v (%v)", item.Status, item.Provider, item.Division, item.Region),
+				},
+			})
+		}
+
+		body, err := json.Marshal(map[string][]serviceNowItem{"items": items})
+		if err != nil {
+			return fmt.Errorf("[push_to_servicenow][json.Marshal]%w", err)
+		}
+
+		request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("[push_to_servicenow][http.NewRequestWithContext]%w", err)
+		}
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("Accept", "application/json")
+		request.SetBasicAuth(config.ServiceNowUsername, config.ServiceNowPassword)
+
+		err = doRequest(request)
+		if err != nil {
+			return fmt.Errorf("[push_to_servicenow]%w", err)
+		}
+	}
+	return nil
+}
+
+// doRequest sends request and returns an error if the response does not have a successful status code.
+func doRequest(request *http.Request) error {
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("[do_request][http.DefaultClient.Do]%w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		responseBody, _ := io.ReadAll(response.Body)
+		return fmt.Errorf("[do_request][unexpected status code %v: %s]", response.StatusCode, responseBody)
+	}
+	return nil
+}
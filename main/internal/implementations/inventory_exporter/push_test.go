@@ -0,0 +1,82 @@
+package inventoryExporter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPushToWebhook(t *testing.T) {
+	// Given
+	var received Inventory
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer my-token", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	inventory := Inventory{SchemaVersion: SchemaVersion, Resources: []Item{{Status: StatusManaged, ResourceID: "vpc-123"}}}
+
+	// When
+	err := pushToWebhook(context.Background(), Config{WebhookURL: server.URL, WebhookToken: "my-token"}, inventory)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, inventory, received)
+}
+
+func TestPushToWebhook_FailureStatus(t *testing.T) {
+	// Given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	// When
+	err := pushToWebhook(context.Background(), Config{WebhookURL: server.URL}, Inventory{})
+
+	// Then
+	assert.ErrorContains(t, err, "unexpected status code 502")
+}
+
+func TestPushToServiceNow(t *testing.T) {
+	// Given
+	batchSizes := make([]int, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/now/identifyreconcile", r.URL.Path)
+		user, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "admin", user)
+		assert.Equal(t, "secret", password)
+
+		body := map[string][]serviceNowItem{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "cmdb_ci_cloud_resource", body["items"][0].ClassName)
+		batchSizes = append(batchSizes, len(body["items"]))
+	}))
+	defer server.Close()
+
+	resources := make([]Item, 0)
+	for i := 0; i < 150; i++ {
+		resources = append(resources, Item{ResourceType: "aws_s3_bucket", ResourceName: fmt.Sprintf("bucket_%v", i)})
+	}
+	config := Config{
+		ServiceNowInstanceURL: server.URL + "/",
+		ServiceNowUsername:    "admin",
+		ServiceNowPassword:    "secret",
+		ServiceNowClassName:   "cmdb_ci_cloud_resource",
+	}
+
+	// When
+	err := pushToServiceNow(context.Background(), config, Inventory{Resources: resources})
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, []int{100, 50}, batchSizes)
+}
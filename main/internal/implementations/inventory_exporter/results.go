@@ -0,0 +1,254 @@
+package inventoryExporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/sensitiveattributes"
+)
+
+// ResultsSchemaVersion is the version of the results schema, incremented whenever a field is removed or changes
+// meaning. Fields may be added without incrementing the version.
+const ResultsSchemaVersion = 1
+
+// Results is the machine-readable summary of a job run, written alongside the pull request for downstream
+// automation to consume.
+type Results struct {
+
+	// SchemaVersion is the version of the results schema.
+	SchemaVersion int `json:"schema_version"`
+
+	// GeneratedAt is the RFC 3339 time at which the results were written.
+	GeneratedAt string `json:"generated_at"`
+
+	// Currency is the ISO 4217 code of the currency of all costs.
+	Currency string `json:"currency"`
+
+	// Summary counts the findings of the job run.
+	Summary ResultsSummary `json:"summary"`
+
+	// Drift are the managed resources whose attributes have drifted from their Terraform state.
+	Drift []DriftedResource `json:"drift"`
+
+	// DeletedResources are the managed resources deleted outside of Terraform.
+	DeletedResources []DeletedResource `json:"deleted_resources"`
+
+	// NewResources are the resources outside of Terraform control.
+	NewResources []Item `json:"new_resources"`
+
+	// Costs are the estimated monthly costs of the new resources within each division.
+	Costs []DivisionCost `json:"costs"`
+
+	// CostRollups are the estimated monthly costs of all resources grouped by workspace, division and tag.
+	CostRollups CostRollups `json:"cost_rollups"`
+
+	// Actors are the cloud actor actions recorded against drifted and new resources.
+	Actors []ActorAction `json:"actors"`
+}
+
+// ResultsSummary counts the findings of a job run.
+type ResultsSummary struct {
+	DriftedResources        int     `json:"drifted_resources"`
+	DriftedAttributes       int     `json:"drifted_attributes"`
+	DeletedResources        int     `json:"deleted_resources"`
+	NewResources            int     `json:"new_resources"`
+	NewResourcesMonthlyCost float64 `json:"new_resources_monthly_cost"`
+}
+
+// DriftedResource is a managed resource instance whose attributes have drifted.
+type DriftedResource struct {
+	StateFile         string             `json:"state_file"`
+	Division          string             `json:"division"`
+	ResourceAddress   string             `json:"resource_address"`
+	ResourceType      string             `json:"resource_type"`
+	ResourceID        string             `json:"resource_id"`
+	RemediationImpact string             `json:"remediation_impact"`
+	Attributes        []DriftedAttribute `json:"attributes"`
+}
+
+// DriftedAttribute is the before, within the Terraform state, and after, within the cloud, value of an attribute.
+type DriftedAttribute struct {
+	Attribute         string `json:"attribute"`
+	Before            string `json:"before"`
+	After             string `json:"after"`
+	Change            string `json:"change"`
+	ForcesReplacement bool   `json:"forces_replacement"`
+	Sensitive         bool   `json:"sensitive,omitempty"`
+}
+
+// DeletedResource is a managed resource instance that no longer exists within the cloud.
+type DeletedResource struct {
+	StateFile       string `json:"state_file"`
+	ResourceAddress string `json:"resource_address"`
+	ResourceType    string `json:"resource_type"`
+	ResourceID      string `json:"resource_id"`
+}
+
+// DivisionCost is the estimated monthly cost, in the results' currency, of the new resources within a division.
+type DivisionCost struct {
+	Provider    string  `json:"provider"`
+	Division    string  `json:"division"`
+	MonthlyCost float64 `json:"monthly_cost"`
+}
+
+// ActorAction is a creation or modification of a resource by a cloud actor.
+type ActorAction struct {
+	Provider  string `json:"provider"`
+	Division  string `json:"division"`
+	Resource  string `json:"resource"`
+	Action    string `json:"action"`
+	Actor     string `json:"actor"`
+	Timestamp string `json:"timestamp"`
+}
+
+// buildResults builds the results of the job run from its sources and the built inventory.
+func buildResults(s sources, items []Item) Results {
+	results := Results{
+		SchemaVersion:    ResultsSchemaVersion,
+		Currency:         s.costCurrency.Currency,
+		Drift:            []DriftedResource{},
+		DeletedResources: []DeletedResource{},
+		NewResources:     []Item{},
+		Costs:            []DivisionCost{},
+		CostRollups:      CostRollups{Workspaces: []CostRollup{}, Divisions: []CostRollup{}, Tags: []CostRollup{}},
+		Actors:           []ActorAction{},
+	}
+
+	for _, diff := range s.driftAttributeDiffs {
+		drifted := DriftedResource{
+			StateFile:         string(diff.StateFileName),
+			Division:          diff.CloudDivision,
+			ResourceAddress:   diff.ResourceAddress,
+			ResourceType:      diff.ResourceType,
+			ResourceID:        diff.InstanceID,
+			RemediationImpact: string(diff.RemediationImpact),
+			Attributes:        []DriftedAttribute{},
+		}
+		for _, attribute := range diff.Attributes {
+			drifted.Attributes = append(drifted.Attributes, DriftedAttribute{
+				Attribute:         attribute.AttributeName,
+				Before:            attribute.Before,
+				After:             attribute.After,
+				Change:            string(attribute.ChangeType),
+				ForcesReplacement: attribute.ForcesReplacement,
+				Sensitive:         attribute.Sensitive,
+			})
+		}
+		results.Drift = append(results.Drift, drifted)
+		results.Summary.DriftedAttributes += len(drifted.Attributes)
+	}
+
+	for _, deleted := range s.deletedResources {
+		results.DeletedResources = append(results.DeletedResources, DeletedResource{
+			StateFile:       string(deleted.StateFileName),
+			ResourceAddress: deleted.ResourceAddress,
+			ResourceType:    deleted.ResourceType,
+			ResourceID:      deleted.InstanceID,
+		})
+	}
+
+	divisionToCost := map[string]*DivisionCost{}
+	for _, item := range items {
+		if item.Status != StatusUnmanaged {
+			continue
+		}
+		results.NewResources = append(results.NewResources, item)
+
+		if item.MonthlyCost == nil {
+			continue
+		}
+		key := fmt.Sprintf("%v-%v", item.Provider, item.Division)
+		if _, ok := divisionToCost[key]; !ok {
+			divisionToCost[key] = &DivisionCost{Provider: item.Provider, Division: item.Division}
+		}
+		divisionToCost[key].MonthlyCost += *item.MonthlyCost
+		results.Summary.NewResourcesMonthlyCost += *item.MonthlyCost
+	}
+
+	for _, cost := range divisionToCost {
+		cost.MonthlyCost = math.Round(cost.MonthlyCost*100) / 100
+		results.Costs = append(results.Costs, *cost)
+	}
+	sort.Slice(results.Costs, func(i, j int) bool {
+		return results.Costs[i].Provider+"-"+results.Costs[i].Division < results.Costs[j].Provider+"-"+results.Costs[j].Division
+	})
+
+	for provider, divisions := range s.cloudActions {
+		for division, resources := range divisions {
+			for resource, actions := range resources {
+				if actions.Creation != nil {
+					results.Actors = append(results.Actors, ActorAction{
+						Provider: provider, Division: division, Resource: resource,
+						Action: "creation", Actor: actions.Creation.Actor, Timestamp: actions.Creation.Timestamp,
+					})
+				}
+				if actions.Modified != nil {
+					results.Actors = append(results.Actors, ActorAction{
+						Provider: provider, Division: division, Resource: resource,
+						Action: "modification", Actor: actions.Modified.Actor, Timestamp: actions.Modified.Timestamp,
+					})
+				}
+			}
+		}
+	}
+	sort.Slice(results.Actors, func(i, j int) bool {
+		return actorActionKey(results.Actors[i]) < actorActionKey(results.Actors[j])
+	})
+
+	results.Summary.DriftedResources = len(results.Drift)
+	results.Summary.DeletedResources = len(results.DeletedResources)
+	results.Summary.NewResources = len(results.NewResources)
+	results.Summary.NewResourcesMonthlyCost = math.Round(results.Summary.NewResourcesMonthlyCost*100) / 100
+
+	return results
+}
+
+// actorActionKey is the key by which actor actions are sorted.
+func actorActionKey(action ActorAction) string {
+	return strings.Join([]string{action.Provider, action.Division, action.Resource, action.Action}, "\x00")
+}
+
+// MaskSensitiveValues replaces the before and after values of the drifted attributes that are flagged as sensitive,
+// or that match one of the sensitive attribute patterns, with sensitiveattributes.Mask.
+func (r *Results) MaskSensitiveValues(patterns []*regexp.Regexp) {
+	for i := range r.Drift {
+		for j, attribute := range r.Drift[i].Attributes {
+			if !attribute.Sensitive && !sensitiveattributes.Matches(attribute.Attribute, patterns) {
+				continue
+			}
+
+			r.Drift[i].Attributes[j].Sensitive = true
+			if attribute.Before != "" {
+				r.Drift[i].Attributes[j].Before = sensitiveattributes.Mask
+			}
+			if attribute.After != "" {
+				r.Drift[i].Attributes[j].After = sensitiveattributes.Mask
+			}
+		}
+	}
+}
+
+// writeResults writes the results of the job run as json to the configured results path.
+func (e *InventoryExporter) writeResults(results Results) error {
+	err := os.MkdirAll(filepath.Dir(e.config.ResultsPath), 0755)
+	if err != nil {
+		return fmt.Errorf("[write_results][os.MkdirAll]%w", err)
+	}
+
+	resultsJSON, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[write_results][json.MarshalIndent]%w", err)
+	}
+
+	err = os.WriteFile(e.config.ResultsPath, resultsJSON, 0644)
+	if err != nil {
+		return fmt.Errorf("[write_results][os.WriteFile %v]%w", e.config.ResultsPath, err)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package inventoryExporter
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/sensitiveattributes"
+)
+
+func TestBuildResults(t *testing.T) {
+	// Given
+	s := inventorySources()
+	s.driftAttributeDiffs = []driftDetector.ResourceAttributeDiff{
+		{
+			StateFileName:     "networking",
+			CloudDivision:     "aws-111111111111",
+			ResourceAddress:   "module.vpc.aws_vpc.main",
+			ResourceType:      "aws_vpc",
+			InstanceID:        "vpc-123",
+			RemediationImpact: driftDetector.RemediationImpactInPlace,
+			Attributes: []driftDetector.AttributeDiff{
+				{AttributeName: "tags.env", Before: "dev", After: "prod", ChangeType: driftDetector.AttributeChangeUpdated},
+			},
+		},
+	}
+	s.deletedResources = []driftDetector.DeletedResource{
+		{InstanceID: "old-bucket", StateFileName: "storage", ResourceType: "aws_s3_bucket", ResourceAddress: "aws_s3_bucket.old"},
+	}
+	s.costCurrency = costCurrency{Currency: "EUR"}
+
+	// When
+	results := buildResults(s, buildInventory(s))
+
+	// Then
+	assert.Equal(t, ResultsSchemaVersion, results.SchemaVersion)
+	assert.Equal(t, "EUR", results.Currency)
+	assert.Equal(t, ResultsSummary{
+		DriftedResources:        1,
+		DriftedAttributes:       1,
+		DeletedResources:        1,
+		NewResources:            1,
+		NewResourcesMonthlyCost: 3.36,
+	}, results.Summary)
+
+	assert.Equal(t, []DriftedResource{{
+		StateFile:         "networking",
+		Division:          "aws-111111111111",
+		ResourceAddress:   "module.vpc.aws_vpc.main",
+		ResourceType:      "aws_vpc",
+		ResourceID:        "vpc-123",
+		RemediationImpact: "in-place",
+		Attributes:        []DriftedAttribute{{Attribute: "tags.env", Before: "dev", After: "prod", Change: "updated"}},
+	}}, results.Drift)
+
+	assert.Equal(t, []DeletedResource{
+		{StateFile: "storage", ResourceAddress: "aws_s3_bucket.old", ResourceType: "aws_s3_bucket", ResourceID: "old-bucket"},
+	}, results.DeletedResources)
+
+	require.Len(t, results.NewResources, 1)
+	assert.Equal(t, "my-bucket", results.NewResources[0].ResourceID)
+
+	assert.Equal(t, []DivisionCost{{Provider: "aws", Division: "111111111111", MonthlyCost: 3.36}}, results.Costs)
+
+	assert.Equal(t, []ActorAction{
+		{Provider: "aws", Division: "111111111111", Resource: "aws_s3_bucket.my_bucket", Action: "creation", Actor: "alice", Timestamp: "2023-01-01"},
+		{Provider: "aws", Division: "111111111111", Resource: "aws_s3_bucket.my_bucket", Action: "modification", Actor: "bob", Timestamp: "2023-02-01"},
+	}, results.Actors)
+}
+
+func TestWriteResults(t *testing.T) {
+	// Given
+	resultsPath := filepath.Join(t.TempDir(), "results", "results.json")
+	exporter := &InventoryExporter{config: Config{ResultsPath: resultsPath}}
+
+	// When
+	err := exporter.writeResults(buildResults(sources{}, []Item{}))
+
+	// Then
+	require.NoError(t, err)
+	content, err := os.ReadFile(resultsPath)
+	require.NoError(t, err)
+
+	written := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(content, &written))
+	assert.Equal(t, float64(ResultsSchemaVersion), written["schema_version"])
+	assert.Equal(t, []interface{}{}, written["drift"])
+}
+
+func TestResults_MaskSensitiveValues(t *testing.T) {
+	// Given
+	patterns, err := sensitiveattributes.Compile([]string{"^user_data$"})
+	require.NoError(t, err)
+	results := Results{Drift: []DriftedResource{{
+		ResourceAddress: "aws_instance.web",
+		Attributes: []DriftedAttribute{
+			{Attribute: "instance_type", Before: "t3.micro", After: "t3.large"},
+			{Attribute: "user_data", Before: "", After: "#!/bin/bash"},
+			{Attribute: "master_password", Before: "old", After: "new"},
+			{Attribute: "settings.0.value", Before: "a", After: "b", Sensitive: true},
+		},
+	}}}
+
+	// When
+	results.MaskSensitiveValues(patterns)
+
+	// Then
+	assert.Equal(t, []DriftedAttribute{
+		{Attribute: "instance_type", Before: "t3.micro", After: "t3.large"},
+		{Attribute: "user_data", Before: "", After: sensitiveattributes.Mask, Sensitive: true},
+		{Attribute: "master_password", Before: sensitiveattributes.Mask, After: sensitiveattributes.Mask, Sensitive: true},
+		{Attribute: "settings.0.value", Before: sensitiveattributes.Mask, After: sensitiveattributes.Mask, Sensitive: true},
+	}, results.Drift[0].Attributes)
+}
+
+func TestNonSensitiveTags(t *testing.T) {
+	// Given
+	patterns, err := sensitiveattributes.Compile(nil)
+	require.NoError(t, err)
+
+	// When
+	tags := nonSensitiveTags([]string{"team", "api_key", "env"}, patterns)
+
+	// Then
+	assert.Equal(t, []string{"team", "env"}, tags)
+}
@@ -0,0 +1,145 @@
+package inventoryExporter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	runStateStore "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/run_state_store"
+)
+
+const (
+	// driftTrendKey is the run state key under which the summaries of previous job runs are stored.
+	driftTrendKey = "drift-trend.json"
+
+	// driftTrendPath is the path to which the run summaries are written for the state of cloud report.
+	driftTrendPath = "mappings/drift-trend.json"
+
+	// maxDriftTrendRuns is the number of most recent run summaries that are kept.
+	maxDriftTrendRuns = 100
+)
+
+// RunSummary counts the findings of a single job run, recorded so that the report can show whether IaC coverage
+// is improving over time.
+type RunSummary struct {
+	GeneratedAt string `json:"generated_at"`
+
+	// Divisions are the counts of managed, unmanaged and drifted resources within each division.
+	Divisions []DivisionRunSummary `json:"divisions"`
+
+	// DeletedResources counts the managed resources deleted outside of Terraform. As these no longer exist within
+	// the cloud, they cannot be attributed to a division.
+	DeletedResources int `json:"deleted_resources"`
+
+	// Coverage is the percentage of all scanned resources that are managed by Terraform.
+	Coverage float64 `json:"coverage"`
+}
+
+// DivisionRunSummary counts the findings of a single job run within a division.
+type DivisionRunSummary struct {
+	Provider  string  `json:"provider"`
+	Division  string  `json:"division"`
+	Managed   int     `json:"managed"`
+	Unmanaged int     `json:"unmanaged"`
+	Drifted   int     `json:"drifted"`
+	Coverage  float64 `json:"coverage"`
+}
+
+// buildRunSummary summarizes the job run from its results and the built inventory.
+func buildRunSummary(results Results, items []Item) RunSummary {
+	divisionToSummary := map[string]*DivisionRunSummary{}
+	divisionSummary := func(provider string, division string) *DivisionRunSummary {
+		key := fmt.Sprintf("%v-%v", provider, division)
+		if _, ok := divisionToSummary[key]; !ok {
+			divisionToSummary[key] = &DivisionRunSummary{Provider: provider, Division: division}
+		}
+		return divisionToSummary[key]
+	}
+
+	for _, item := range items {
+		if item.Division == "" {
+			continue
+		}
+		if item.Status == StatusManaged {
+			divisionSummary(item.Provider, item.Division).Managed++
+		} else {
+			divisionSummary(item.Provider, item.Division).Unmanaged++
+		}
+	}
+
+	for _, drifted := range results.Drift {
+		if drifted.Division == "" {
+			continue
+		}
+		divisionSummary(strings.Split(drifted.ResourceType, "_")[0], drifted.Division).Drifted++
+	}
+
+	summary := RunSummary{
+		GeneratedAt:      results.GeneratedAt,
+		Divisions:        []DivisionRunSummary{},
+		DeletedResources: results.Summary.DeletedResources,
+	}
+	managed, total := 0, 0
+	for _, division := range divisionToSummary {
+		division.Coverage = coveragePercentage(division.Managed, division.Managed+division.Unmanaged)
+		summary.Divisions = append(summary.Divisions, *division)
+		managed += division.Managed
+		total += division.Managed + division.Unmanaged
+	}
+	summary.Coverage = coveragePercentage(managed, total)
+
+	sort.Slice(summary.Divisions, func(i, j int) bool {
+		return summary.Divisions[i].Provider+"-"+summary.Divisions[i].Division < summary.Divisions[j].Provider+"-"+summary.Divisions[j].Division
+	})
+	return summary
+}
+
+// recordDriftTrend appends the summary of the job run to those of previous runs within the run state store, and
+// writes the resulting history for the state of cloud report.
+func (e *InventoryExporter) recordDriftTrend(ctx context.Context, summary RunSummary) error {
+	history := []RunSummary{}
+
+	data, err := e.runStateStore.Get(ctx, driftTrendKey)
+	if err != nil && !errors.Is(err, runStateStore.ErrNotFound) {
+		return fmt.Errorf("[record_drift_trend]%w", err)
+	}
+	if err == nil {
+		err = json.Unmarshal(data, &history)
+		if err != nil {
+			return fmt.Errorf("[record_drift_trend][json.Unmarshal]%w", err)
+		}
+	}
+
+	history = append(history, summary)
+	if len(history) > maxDriftTrendRuns {
+		history = history[len(history)-maxDriftTrendRuns:]
+	}
+
+	historyJSON, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[record_drift_trend][json.MarshalIndent]%w", err)
+	}
+
+	err = e.runStateStore.Put(ctx, driftTrendKey, historyJSON)
+	if err != nil {
+		return fmt.Errorf("[record_drift_trend]%w", err)
+	}
+
+	err = e.artifactStore.WriteFile(driftTrendPath, historyJSON, 0644)
+	if err != nil {
+		return fmt.Errorf("[record_drift_trend][artifacts.WriteFile %v]%w", driftTrendPath, err)
+	}
+	return nil
+}
+
+// coveragePercentage returns the percentage of total resources that are managed, rounded to one decimal place.
+func coveragePercentage(managed int, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+	return math.Round(1000*float64(managed)/float64(total)) / 10
+}
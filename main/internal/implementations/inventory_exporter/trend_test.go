@@ -0,0 +1,75 @@
+package inventoryExporter
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	runStateStore "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/run_state_store"
+)
+
+func TestBuildRunSummary(t *testing.T) {
+	// Given
+	items := []Item{
+		{Status: StatusManaged, Provider: "aws", Division: "111111111111"},
+		{Status: StatusManaged, Provider: "aws", Division: "111111111111"},
+		{Status: StatusManaged, Provider: "aws", Division: "111111111111"},
+		{Status: StatusUnmanaged, Provider: "aws", Division: "111111111111"},
+		{Status: StatusUnmanaged, Provider: "google", Division: "my-project"},
+		{Status: StatusManaged, Provider: "aws"},
+	}
+	results := Results{
+		GeneratedAt: "2023-06-01T00:00:00Z",
+		Summary:     ResultsSummary{DeletedResources: 2},
+		Drift:       []DriftedResource{{Division: "111111111111", ResourceType: "aws_vpc"}},
+	}
+
+	// When
+	summary := buildRunSummary(results, items)
+
+	// Then
+	assert.Equal(t, RunSummary{
+		GeneratedAt: "2023-06-01T00:00:00Z",
+		Divisions: []DivisionRunSummary{
+			{Provider: "aws", Division: "111111111111", Managed: 3, Unmanaged: 1, Drifted: 1, Coverage: 75},
+			{Provider: "google", Division: "my-project", Unmanaged: 1, Coverage: 0},
+		},
+		DeletedResources: 2,
+		Coverage:         60,
+	}, summary)
+}
+
+func TestRecordDriftTrend(t *testing.T) {
+	// Given
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+	require.NoError(t, os.Mkdir("mappings", 0755))
+
+	ctx := context.Background()
+	exporter := &InventoryExporter{artifactStore: artifacts.NewMemoryStore(true), runStateStore: runStateStore.NewIsolatedRunStateStore()}
+
+	// When
+	require.NoError(t, exporter.recordDriftTrend(ctx, RunSummary{GeneratedAt: "2023-06-01T00:00:00Z", Coverage: 50}))
+	require.NoError(t, exporter.recordDriftTrend(ctx, RunSummary{GeneratedAt: "2023-06-08T00:00:00Z", Coverage: 75}))
+
+	// Then
+	content, err := os.ReadFile(driftTrendPath)
+	require.NoError(t, err)
+
+	var history []RunSummary
+	require.NoError(t, json.Unmarshal(content, &history))
+	require.Len(t, history, 2)
+	assert.Equal(t, float64(50), history[0].Coverage)
+	assert.Equal(t, "2023-06-08T00:00:00Z", history[1].GeneratedAt)
+
+	stored, err := exporter.runStateStore.Get(ctx, driftTrendKey)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(content), string(stored))
+}
@@ -0,0 +1,227 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// emailSender sends the state of cloud report as an html email, with the report markdown and the job results
+// attached, to a list of recipients via SMTP.
+type emailSender struct {
+
+	// host is the SMTP server host.
+	host string
+
+	// port is the SMTP server port.
+	port int
+
+	// username and password, when set, authenticate against the SMTP server.
+	username string
+	password string
+
+	// from is the sender address of each email.
+	from string
+
+	// recipients are the addresses each email is sent to.
+	recipients []string
+
+	// reportPath is the path of the state of cloud report markdown.
+	reportPath string
+
+	// resultsPath is the path of the machine-readable results of the job run.
+	resultsPath string
+
+	// sensitivePatterns are the user-provided patterns of sensitive attributes, whose values are masked.
+	sensitivePatterns []string
+
+	// sendMail sends msg via the SMTP server at addr, smtp.SendMail outside of tests.
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// newEmailSender creates an emailSender from the SMTP settings within config.
+func newEmailSender(config Config) *emailSender {
+	return &emailSender{
+		host:              config.SMTPHost,
+		port:              config.SMTPPort,
+		username:          config.SMTPUsername,
+		password:          config.SMTPPassword,
+		from:              config.EmailFrom,
+		recipients:        config.EmailRecipients,
+		reportPath:        config.ReportPath,
+		resultsPath:       config.ResultsPath,
+		sensitivePatterns: config.SensitiveAttributePatterns,
+		sendMail:          smtp.SendMail,
+	}
+}
+
+// name is the name of the destination, used within errors.
+func (s *emailSender) name() string {
+	return "email"
+}
+
+// send emails the state of cloud report to each recipient. The SMTP connection is upgraded to TLS whenever the
+// server supports it.
+func (s *emailSender) send(ctx context.Context, summary RunSummary) error {
+	if s.from == "" {
+		return errors.New("[email_sender][no sender address configured]")
+	}
+
+	message, err := s.message(summary)
+	if err != nil {
+		return fmt.Errorf("[email_sender]%w", err)
+	}
+
+	var auth smtp.Auth
+	if s.username != "" {
+		auth = smtp.PlainAuth("", s.username, s.password, s.host)
+	}
+
+	err = s.sendMail(net.JoinHostPort(s.host, strconv.Itoa(s.port)), auth, s.from, s.recipients, message)
+	if err != nil {
+		return fmt.Errorf("[email_sender][smtp.SendMail]%w", err)
+	}
+	return nil
+}
+
+// message builds the MIME email carrying the report rendered as html, falling back to the run summary when the
+// job run produced no report, along with the report markdown and job results as attachments.
+func (s *emailSender) message(summary RunSummary) ([]byte, error) {
+	attachments := map[string][]byte{}
+	htmlBody := summaryHTML(summary)
+
+	reportMarkdown, err := os.ReadFile(s.reportPath)
+	if err == nil {
+		htmlBody = reportHTML(pullRequestLine(summary) + string(reportMarkdown))
+		attachments[filepath.Base(s.reportPath)] = reportMarkdown
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("[message][os.ReadFile %v]%w", s.reportPath, err)
+	}
+
+	results, err := readMaskedResults(s.resultsPath, s.sensitivePatterns)
+	if err != nil {
+		return nil, fmt.Errorf("[message]%w", err)
+	}
+	if results != nil {
+		resultsJSON, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("[message][json.MarshalIndent]%w", err)
+		}
+		attachments[filepath.Base(s.resultsPath)] = resultsJSON
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	htmlPart, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/html; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[message][writer.CreatePart]%w", err)
+	}
+	htmlWriter := quotedprintable.NewWriter(htmlPart)
+	_, err = htmlWriter.Write([]byte(htmlBody))
+	if err != nil {
+		return nil, fmt.Errorf("[message][htmlWriter.Write]%w", err)
+	}
+	err = htmlWriter.Close()
+	if err != nil {
+		return nil, fmt.Errorf("[message][htmlWriter.Close]%w", err)
+	}
+
+	for _, fileName := range []string{filepath.Base(s.reportPath), filepath.Base(s.resultsPath)} {
+		content, ok := attachments[fileName]
+		if !ok {
+			continue
+		}
+
+		contentType := "application/json"
+		if strings.HasSuffix(fileName, ".md") {
+			contentType = "text/markdown; charset=utf-8"
+		}
+
+		attachmentPart, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {contentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": fileName})},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("[message][writer.CreatePart]%w", err)
+		}
+		_, err = attachmentPart.Write([]byte(wrapBase64(content)))
+		if err != nil {
+			return nil, fmt.Errorf("[message][attachmentPart.Write]%w", err)
+		}
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return nil, fmt.Errorf("[message][writer.Close]%w", err)
+	}
+
+	var message bytes.Buffer
+	message.WriteString(fmt.Sprintf("From: %v\r\n", s.from))
+	message.WriteString(fmt.Sprintf("To: %v\r\n", strings.Join(s.recipients, ", ")))
+	message.WriteString(fmt.Sprintf("Subject: %v\r\n", mime.QEncoding.Encode("utf-8", fmt.Sprintf("%v - State of Scanned Cloud Resources", summary.JobName))))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString(fmt.Sprintf("Content-Type: multipart/mixed; boundary=%v\r\n\r\n", writer.Boundary()))
+	message.Write(body.Bytes())
+
+	return message.Bytes(), nil
+}
+
+// pullRequestLine returns a markdown line linking to the pull requests of the job run, if any.
+func pullRequestLine(summary RunSummary) string {
+	switch len(summary.PullRequestURLs) {
+	case 0:
+		return ""
+	case 1:
+		return fmt.Sprintf("[View the pull request](%v)\n\n", summary.PullRequestURLs[0])
+	}
+
+	links := make([]string, 0, len(summary.PullRequestURLs))
+	for i, pullRequestURL := range summary.PullRequestURLs {
+		links = append(links, fmt.Sprintf("[part %v](%v)", i+1, pullRequestURL))
+	}
+	return fmt.Sprintf("View the pull requests: %v\n\n", strings.Join(links, ", "))
+}
+
+// summaryHTML renders the run summary as an html document, for job runs without a report.
+func summaryHTML(summary RunSummary) string {
+	return reportHTML(fmt.Sprintf(
+		"%v\n===\n\n%v| Finding | Count |\n| --- | --- |\n| New resources | %v |\n| Drifted resources | %v |\n| Drifted attributes | %v |\n| Deleted resources | %v |\n",
+		summary.JobName,
+		pullRequestLine(summary),
+		summary.Summary.NewResources,
+		summary.Summary.DriftedResources,
+		summary.Summary.DriftedAttributes,
+		summary.Summary.DeletedResources,
+	))
+}
+
+// wrapBase64 base64 encodes content in lines of 76 characters, as required of MIME bodies.
+func wrapBase64(content []byte) string {
+	encoded := base64.StdEncoding.EncodeToString(content)
+
+	var wrapped strings.Builder
+	for len(encoded) > 76 {
+		wrapped.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	wrapped.WriteString(encoded + "\r\n")
+	return wrapped.String()
+}
@@ -0,0 +1,91 @@
+package notifier
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailSender_Send(t *testing.T) {
+	// Given
+	directory := t.TempDir()
+	reportPath := filepath.Join(directory, "report.md")
+	require.NoError(t, os.WriteFile(reportPath, []byte("# Drift\n|Resource|\n| --- |\n|aws_s3_bucket.logs|\n"), 0o400))
+
+	var sentAddr, sentFrom string
+	var sentTo []string
+	var sentMessage []byte
+	sender := newEmailSender(Config{
+		ResultsPath:     writeResults(t),
+		ReportPath:      reportPath,
+		SMTPHost:        "smtp.example.com",
+		SMTPPort:        587,
+		EmailFrom:       "cloud-concierge@example.com",
+		EmailRecipients: []string{"platform@example.com", "finance@example.com"},
+	})
+	sender.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		sentAddr, sentFrom, sentTo, sentMessage = addr, from, to, msg
+		return nil
+	}
+
+	// When
+	err := sender.send(context.Background(), RunSummary{JobName: "my-job", PullRequestURLs: []string{"https://github.com/org/repo/pull/1"}})
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, "smtp.example.com:587", sentAddr)
+	assert.Equal(t, "cloud-concierge@example.com", sentFrom)
+	assert.Equal(t, []string{"platform@example.com", "finance@example.com"}, sentTo)
+
+	message, err := mail.ReadMessage(strings.NewReader(string(sentMessage)))
+	require.NoError(t, err)
+	assert.Equal(t, "my-job - State of Scanned Cloud Resources", message.Header.Get("Subject"))
+
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/mixed", mediaType)
+
+	parts := map[string]string{}
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(part)
+		require.NoError(t, err)
+
+		name := part.FileName()
+		if name == "" {
+			name = "body"
+		}
+		parts[name] = string(content)
+	}
+
+	assert.Contains(t, parts["body"], `<a href="https://github.com/org/repo/pull/1">View the pull request</a>`)
+	assert.Contains(t, parts["body"], "<td>aws_s3_bucket.logs</td>")
+	assert.Contains(t, parts, "report.md")
+	assert.Contains(t, parts, "results.json")
+}
+
+func TestEmailSender_SendWithoutFrom(t *testing.T) {
+	// Given
+	sender := newEmailSender(Config{SMTPHost: "smtp.example.com", EmailRecipients: []string{"platform@example.com"}})
+
+	// When
+	err := sender.send(context.Background(), RunSummary{})
+
+	// Then
+	assert.ErrorContains(t, err, "no sender address configured")
+}
@@ -0,0 +1,19 @@
+package notifier
+
+import (
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
+)
+
+// Factory is a struct for creating different implementations of interfaces.Notifier.
+type Factory struct {
+}
+
+// Instantiate creates an implementation of interfaces.Notifier.
+func (f *Factory) Instantiate(environment string, config Config) (interfaces.Notifier, error) {
+	switch environment {
+	case "isolated":
+		return new(IsolatedNotifier), nil
+	default:
+		return NewNotifier(config), nil
+	}
+}
@@ -0,0 +1,31 @@
+package notifier
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateNotifier(t *testing.T) {
+	// Given
+	notifierFactory := new(Factory)
+
+	// When
+	notifier, err := notifierFactory.Instantiate("", Config{})
+
+	// Then
+	assert.Nil(t, err)
+	assert.IsType(t, &Notifier{}, notifier)
+}
+
+func TestCreateIsolatedNotifier(t *testing.T) {
+	// Given
+	notifierFactory := new(Factory)
+
+	// When
+	notifier, err := notifierFactory.Instantiate("isolated", Config{})
+
+	// Then
+	assert.Nil(t, err)
+	assert.IsType(t, &IsolatedNotifier{}, notifier)
+}
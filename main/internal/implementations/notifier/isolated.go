@@ -0,0 +1,14 @@
+package notifier
+
+import (
+	"context"
+)
+
+// IsolatedNotifier is a struct that implements interfaces.Notifier for the purpose of end-to-end testing.
+type IsolatedNotifier struct {
+}
+
+// Notify sends the summary of the job run, linking to its pull requests, to each configured destination.
+func (n *IsolatedNotifier) Notify(ctx context.Context, pullRequestURLs []string) error {
+	return nil
+}
@@ -0,0 +1,404 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	inventoryExporter "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/inventory_exporter"
+)
+
+// jiraLabel labels every issue opened by cloud-concierge.
+const jiraLabel = "cloud-concierge"
+
+// jiraMaxTableRows is the maximum number of rows of each table within an issue description, keeping descriptions
+// within Jira's size limit.
+const jiraMaxTableRows = 100
+
+var jiraLabelRegex = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// jiraSender opens, or updates when one is already open, a Jira issue per division summarizing its drifted and
+// unmanaged resources.
+type jiraSender struct {
+
+	// baseURL is the url of the Jira site, e.g. https://my-org.atlassian.net.
+	baseURL string
+
+	// username, when set, authenticates with basic auth alongside apiToken, as for Jira Cloud. Otherwise, apiToken
+	// is used as a bearer personal access token, as for Jira Data Center.
+	username string
+
+	// apiToken is the Jira API token or personal access token.
+	apiToken string
+
+	// projectKey is the key of the project issues are opened within.
+	projectKey string
+
+	// issueType is the name of the type of the issues opened.
+	issueType string
+
+	// resultsPath is the path of the machine-readable results of the job run.
+	resultsPath string
+
+	// sensitivePatterns are the user-provided patterns of sensitive attributes, whose values are masked.
+	sensitivePatterns []string
+
+	// divisions are the divisions scanned by the job, whose open issues are resolved once they no longer have
+	// drifted or unmanaged resources.
+	divisions []string
+
+	// legacySearch flags that the Jira site only serves the /rest/api/2/search endpoint, as for Jira Data Center,
+	// rather than the /rest/api/3/search/jql endpoint that replaces it on Jira Cloud.
+	legacySearch bool
+}
+
+// jiraStatusError is returned when the Jira REST API responds with an unsuccessful status code.
+type jiraStatusError struct {
+	statusCode int
+	body       []byte
+}
+
+func (e *jiraStatusError) Error() string {
+	return fmt.Sprintf("[do][unexpected status code %v: %s]", e.statusCode, e.body)
+}
+
+// divisionFindings are the drifted and unmanaged resources within a division.
+type divisionFindings struct {
+	drift        []inventoryExporter.DriftedResource
+	newResources []inventoryExporter.Item
+}
+
+// name is the name of the destination, used within errors.
+func (s *jiraSender) name() string {
+	return "jira"
+}
+
+// send opens or updates the Jira issue of each division with drifted or unmanaged resources, and resolves the open
+// issue of each scanned division that no longer has any. Every division is attempted, and the errors of those that
+// failed are returned together.
+func (s *jiraSender) send(ctx context.Context, summary RunSummary) error {
+	results, err := readMaskedResults(s.resultsPath, s.sensitivePatterns)
+	if err != nil {
+		return fmt.Errorf("[jira_sender]%w", err)
+	}
+	if results == nil {
+		return nil
+	}
+
+	findings := findingsByDivision(*results)
+	divisions := make([]string, 0, len(findings))
+	for division := range findings {
+		divisions = append(divisions, division)
+	}
+	sort.Strings(divisions)
+
+	var issueErrors []error
+	for _, division := range divisions {
+		err = s.upsertIssue(ctx, summary, division, findings[division])
+		if err != nil {
+			issueErrors = append(issueErrors, fmt.Errorf("[division %v]%w", division, err))
+		}
+	}
+
+	for _, division := range s.divisions {
+		if _, ok := findings[division]; ok {
+			continue
+		}
+
+		err = s.resolveIssue(ctx, summary, division)
+		if err != nil {
+			issueErrors = append(issueErrors, fmt.Errorf("[division %v]%w", division, err))
+		}
+	}
+
+	if len(issueErrors) > 0 {
+		return fmt.Errorf("[jira_sender]%w", joinErrors(issueErrors))
+	}
+	return nil
+}
+
+// findingsByDivision groups the drifted and unmanaged resources of results by division.
+func findingsByDivision(results inventoryExporter.Results) map[string]*divisionFindings {
+	findings := map[string]*divisionFindings{}
+	forDivision := func(division string) *divisionFindings {
+		if _, ok := findings[division]; !ok {
+			findings[division] = &divisionFindings{}
+		}
+		return findings[division]
+	}
+
+	for _, drifted := range results.Drift {
+		forDivision(drifted.Division).drift = append(forDivision(drifted.Division).drift, drifted)
+	}
+	for _, item := range results.NewResources {
+		forDivision(item.Division).newResources = append(forDivision(item.Division).newResources, item)
+	}
+	return findings
+}
+
+// upsertIssue updates the open issue of division, or opens one when none is open.
+func (s *jiraSender) upsertIssue(ctx context.Context, summary RunSummary, division string, findings *divisionFindings) error {
+	labels := []string{jiraLabel, divisionLabel(division)}
+	fields := map[string]interface{}{
+		"summary": fmt.Sprintf(
+			"%v: %v drifted and %v unmanaged resources in %v",
+			summary.JobName, len(findings.drift), len(findings.newResources), division,
+		),
+		"description": jiraDescription(summary, division, findings),
+	}
+
+	issueKey, err := s.openIssueKey(ctx, labels)
+	if err != nil {
+		return fmt.Errorf("[upsert_issue]%w", err)
+	}
+
+	if issueKey != "" {
+		err = s.do(ctx, http.MethodPut, "/rest/api/2/issue/"+url.PathEscape(issueKey), map[string]interface{}{"fields": fields}, nil)
+		if err != nil {
+			return fmt.Errorf("[upsert_issue][update %v]%w", issueKey, err)
+		}
+		log.Infof("updated Jira issue %v for division %v", issueKey, division)
+		return nil
+	}
+
+	fields["project"] = map[string]string{"key": s.projectKey}
+	fields["issuetype"] = map[string]string{"name": s.issueType}
+	fields["labels"] = labels
+
+	var created struct {
+		Key string `json:"key"`
+	}
+	err = s.do(ctx, http.MethodPost, "/rest/api/2/issue", map[string]interface{}{"fields": fields}, &created)
+	if err != nil {
+		return fmt.Errorf("[upsert_issue][create]%w", err)
+	}
+	log.Infof("opened Jira issue %v for division %v", created.Key, division)
+	return nil
+}
+
+// resolveIssue comments on the open issue of division, which no longer has drifted or unmanaged resources, and
+// transitions it to a done status when the issue's workflow allows it.
+func (s *jiraSender) resolveIssue(ctx context.Context, summary RunSummary, division string) error {
+	issueKey, err := s.openIssueKey(ctx, []string{jiraLabel, divisionLabel(division)})
+	if err != nil {
+		return fmt.Errorf("[resolve_issue]%w", err)
+	}
+	if issueKey == "" {
+		return nil
+	}
+
+	issuePath := "/rest/api/2/issue/" + url.PathEscape(issueKey)
+	comment := fmt.Sprintf(
+		"The cloud-concierge job *%v* found no drifted or unmanaged resources in division {{%v}} as of %v.",
+		jiraEscape(summary.JobName), jiraEscape(division), summary.CompletedAt,
+	)
+	err = s.do(ctx, http.MethodPost, issuePath+"/comment", map[string]interface{}{"body": comment}, nil)
+	if err != nil {
+		return fmt.Errorf("[resolve_issue][comment %v]%w", issueKey, err)
+	}
+
+	var transitions struct {
+		Transitions []struct {
+			ID string `json:"id"`
+			To struct {
+				StatusCategory struct {
+					Key string `json:"key"`
+				} `json:"statusCategory"`
+			} `json:"to"`
+		} `json:"transitions"`
+	}
+	err = s.do(ctx, http.MethodGet, issuePath+"/transitions", nil, &transitions)
+	if err != nil {
+		return fmt.Errorf("[resolve_issue][transitions %v]%w", issueKey, err)
+	}
+
+	for _, transition := range transitions.Transitions {
+		if transition.To.StatusCategory.Key != "done" {
+			continue
+		}
+
+		body := map[string]interface{}{"transition": map[string]string{"id": transition.ID}}
+		err = s.do(ctx, http.MethodPost, issuePath+"/transitions", body, nil)
+		if err != nil {
+			return fmt.Errorf("[resolve_issue][transition %v]%w", issueKey, err)
+		}
+		log.Infof("resolved Jira issue %v for division %v", issueKey, division)
+		return nil
+	}
+
+	log.Warnf("commented on Jira issue %v for division %v, whose workflow has no transition to a done status", issueKey, division)
+	return nil
+}
+
+// openIssueKey returns the key of the unresolved issue within the project carrying all labels, or an empty string
+// when there is none.
+func (s *jiraSender) openIssueKey(ctx context.Context, labels []string) (string, error) {
+	jql := fmt.Sprintf("project = %q AND statusCategory != Done", s.projectKey)
+	for _, label := range labels {
+		jql += fmt.Sprintf(" AND labels = %q", label)
+	}
+	jql += " ORDER BY created DESC"
+
+	query := url.Values{"jql": {jql}, "fields": {"key"}, "maxResults": {"1"}}
+
+	var found struct {
+		Issues []struct {
+			Key string `json:"key"`
+		} `json:"issues"`
+	}
+	err := s.search(ctx, query, &found)
+	if err != nil {
+		return "", fmt.Errorf("[open_issue_key]%w", err)
+	}
+
+	if len(found.Issues) == 0 {
+		return "", nil
+	}
+	return found.Issues[0].Key, nil
+}
+
+// search runs a JQL search against the /rest/api/3/search/jql endpoint, falling back to the retired
+// /rest/api/2/search endpoint for Jira sites that do not serve it, as for Jira Data Center.
+func (s *jiraSender) search(ctx context.Context, query url.Values, out interface{}) error {
+	if !s.legacySearch {
+		err := s.do(ctx, http.MethodGet, "/rest/api/3/search/jql?"+query.Encode(), nil, out)
+
+		var statusErr *jiraStatusError
+		if !errors.As(err, &statusErr) || statusErr.statusCode != http.StatusNotFound {
+			return err
+		}
+		s.legacySearch = true
+	}
+
+	return s.do(ctx, http.MethodGet, "/rest/api/2/search?"+query.Encode(), nil, out)
+}
+
+// do sends an authenticated request to the Jira REST API, decoding the json response into out when it is not nil.
+func (s *jiraSender) do(ctx context.Context, method string, path string, body interface{}, out interface{}) error {
+	var requestBody io.Reader
+	if body != nil {
+		bodyJSON, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("[do][json.Marshal]%w", err)
+		}
+		requestBody = bytes.NewReader(bodyJSON)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(s.baseURL, "/")+path, requestBody)
+	if err != nil {
+		return fmt.Errorf("[do][http.NewRequestWithContext]%w", err)
+	}
+	request.Header.Set("Accept", "application/json")
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	if s.username != "" {
+		request.SetBasicAuth(s.username, s.apiToken)
+	} else {
+		request.Header.Set("Authorization", fmt.Sprintf("Bearer %v", s.apiToken))
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("[do][http.DefaultClient.Do]%w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		responseBody, _ := io.ReadAll(response.Body)
+		return &jiraStatusError{statusCode: response.StatusCode, body: responseBody}
+	}
+
+	if out != nil {
+		err = json.NewDecoder(response.Body).Decode(out)
+		if err != nil {
+			return fmt.Errorf("[do][json.Decode]%w", err)
+		}
+	}
+	return nil
+}
+
+// divisionLabel returns the label identifying the issues of division.
+func divisionLabel(division string) string {
+	return jiraLabel + "-" + strings.Trim(jiraLabelRegex.ReplaceAllString(division, "-"), "-")
+}
+
+// jiraDescription renders the findings within division as a Jira wiki markup issue description.
+func jiraDescription(summary RunSummary, division string, findings *divisionFindings) string {
+	var description strings.Builder
+	description.WriteString(fmt.Sprintf(
+		"The cloud-concierge job *%v* found drifted and unmanaged resources in division {{%v}} as of %v.\n",
+		jiraEscape(summary.JobName), jiraEscape(division), summary.CompletedAt,
+	))
+	switch len(summary.PullRequestURLs) {
+	case 0:
+	case 1:
+		description.WriteString(fmt.Sprintf("Remediating code is proposed in [this pull request|%v].\n", summary.PullRequestURLs[0]))
+	default:
+		links := make([]string, 0, len(summary.PullRequestURLs))
+		for i, pullRequestURL := range summary.PullRequestURLs {
+			links = append(links, fmt.Sprintf("[part %v|%v]", i+1, pullRequestURL))
+		}
+		description.WriteString(fmt.Sprintf("Remediating code is proposed across the pull requests %v.\n", strings.Join(links, ", ")))
+	}
+
+	if len(findings.drift) > 0 {
+		description.WriteString("\nh3. Drifted resources\n||Resource||Resource ID||Drifted attributes||Remediation impact||\n")
+		for i, drifted := range findings.drift {
+			if i == jiraMaxTableRows {
+				description.WriteString(fmt.Sprintf("\n...and %v more drifted resources.\n", len(findings.drift)-i))
+				break
+			}
+			attributes := make([]string, 0, len(drifted.Attributes))
+			for _, attribute := range drifted.Attributes {
+				attributes = append(attributes, attribute.Attribute)
+			}
+			description.WriteString(fmt.Sprintf(
+				"|%v|%v|%v|%v|\n",
+				jiraCell(drifted.ResourceAddress), jiraCell(drifted.ResourceID),
+				jiraCell(strings.Join(attributes, ", ")), jiraCell(drifted.RemediationImpact),
+			))
+		}
+	}
+
+	if len(findings.newResources) > 0 {
+		description.WriteString("\nh3. Unmanaged resources\n||Resource type||Resource ID||Region||Owner||\n")
+		for i, item := range findings.newResources {
+			if i == jiraMaxTableRows {
+				description.WriteString(fmt.Sprintf("\n...and %v more unmanaged resources.\n", len(findings.newResources)-i))
+				break
+			}
+			description.WriteString(fmt.Sprintf(
+				"|%v|%v|%v|%v|\n",
+				jiraCell(item.ResourceType), jiraCell(item.ResourceID), jiraCell(item.Region), jiraCell(item.Owner),
+			))
+		}
+	}
+
+	return description.String()
+}
+
+// jiraCell escapes value for a Jira wiki markup table cell, in which empty values are not rendered.
+func jiraCell(value string) string {
+	if value == "" {
+		return " "
+	}
+	return jiraEscape(value)
+}
+
+// jiraEscape escapes the Jira wiki markup characters within value.
+func jiraEscape(value string) string {
+	return strings.NewReplacer(
+		"|", "\\|", "{", "\\{", "}", "\\}", "[", "\\[", "]", "\\]", "*", "\\*", "\n", " ",
+	).Replace(value)
+}
@@ -0,0 +1,154 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	inventoryExporter "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/inventory_exporter"
+)
+
+func TestJiraSender_Send(t *testing.T) {
+	// Given
+	resultsPath := filepath.Join(t.TempDir(), "results.json")
+	resultsJSON, err := json.Marshal(inventoryExporter.Results{
+		Drift: []inventoryExporter.DriftedResource{{
+			Division:        "prod-account",
+			ResourceAddress: "aws_instance.web",
+			ResourceID:      "i-123",
+			Attributes:      []inventoryExporter.DriftedAttribute{{Attribute: "instance_type"}},
+		}},
+		NewResources: []inventoryExporter.Item{{
+			Division:     "dev account",
+			ResourceType: "aws_s3_bucket",
+			ResourceID:   "logs|bucket",
+		}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(resultsPath, resultsJSON, 0o400))
+
+	var searches []string
+	var created, updated []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, _ := r.BasicAuth()
+		assert.Equal(t, "platform@example.com", user)
+		assert.Equal(t, "my-token", password)
+
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/api/3/search/jql":
+			jql := r.URL.Query().Get("jql")
+			searches = append(searches, jql)
+			if strings.Contains(jql, `"cloud-concierge-prod-account"`) {
+				_, _ = w.Write([]byte(`{"issues": [{"key": "OPS-7"}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"issues": []}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue":
+			created = append(created, body["fields"].(map[string]interface{}))
+			_, _ = w.Write([]byte(`{"key": "OPS-8"}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/rest/api/2/issue/OPS-7":
+			updated = append(updated, body["fields"].(map[string]interface{}))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	sender := &jiraSender{
+		baseURL:     server.URL,
+		username:    "platform@example.com",
+		apiToken:    "my-token",
+		projectKey:  "OPS",
+		issueType:   "Task",
+		resultsPath: resultsPath,
+	}
+
+	// When
+	err = sender.send(context.Background(), RunSummary{JobName: "my-job", PullRequestURLs: []string{"https://github.com/org/repo/pull/1"}})
+
+	// Then
+	require.NoError(t, err)
+	assert.Len(t, searches, 2)
+	assert.Contains(t, searches[0], `labels = "cloud-concierge-dev-account"`)
+
+	require.Len(t, created, 1)
+	assert.Equal(t, "my-job: 0 drifted and 1 unmanaged resources in dev account", created[0]["summary"])
+	assert.Equal(t, []interface{}{"cloud-concierge", "cloud-concierge-dev-account"}, created[0]["labels"])
+	assert.Equal(t, map[string]interface{}{"key": "OPS"}, created[0]["project"])
+	assert.Contains(t, created[0]["description"], `|aws_s3_bucket|logs\|bucket| | |`)
+	assert.Contains(t, created[0]["description"], "[this pull request|https://github.com/org/repo/pull/1]")
+
+	require.Len(t, updated, 1)
+	assert.Equal(t, "my-job: 1 drifted and 0 unmanaged resources in prod-account", updated[0]["summary"])
+	assert.Contains(t, updated[0]["description"], "|aws_instance.web|i-123|instance_type| |")
+	assert.NotContains(t, updated[0], "project")
+}
+
+func TestJiraSender_SendResolvesCleanDivisions(t *testing.T) {
+	// Given
+	resultsPath := filepath.Join(t.TempDir(), "results.json")
+	require.NoError(t, os.WriteFile(resultsPath, []byte(`{"drift": [], "new_resources": []}`), 0o400))
+
+	var paths []string
+	var comments, transitions []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		paths = append(paths, r.Method+" "+r.URL.Path)
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/api/2/search":
+			if strings.Contains(r.URL.Query().Get("jql"), `"cloud-concierge-prod"`) {
+				_, _ = w.Write([]byte(`{"issues": [{"key": "OPS-7"}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"issues": []}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue/OPS-7/comment":
+			comments = append(comments, body)
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/api/2/issue/OPS-7/transitions":
+			_, _ = w.Write([]byte(`{"transitions": [
+				{"id": "11", "to": {"statusCategory": {"key": "indeterminate"}}},
+				{"id": "31", "to": {"statusCategory": {"key": "done"}}}
+			]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue/OPS-7/transitions":
+			transitions = append(transitions, body)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	sender := &jiraSender{
+		baseURL:     server.URL,
+		apiToken:    "my-token",
+		projectKey:  "OPS",
+		issueType:   "Task",
+		resultsPath: resultsPath,
+		divisions:   []string{"dev", "prod"},
+	}
+
+	// When
+	err := sender.send(context.Background(), RunSummary{JobName: "my-job"})
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, "GET /rest/api/3/search/jql", paths[0])
+	assert.True(t, sender.legacySearch)
+	require.Len(t, comments, 1)
+	assert.Contains(t, comments[0]["body"], "found no drifted or unmanaged resources in division {{prod}}")
+	assert.Equal(t, []map[string]interface{}{{"transition": map[string]interface{}{"id": "31"}}}, transitions)
+}
@@ -0,0 +1,280 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	inventoryExporter "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/inventory_exporter"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/sensitiveattributes"
+)
+
+// Config is the configuration of the destinations notified of the results of a job run.
+type Config struct {
+
+	// JobName is the name of the job, heading each notification.
+	JobName string
+
+	// ResultsPath is the path of the machine-readable results of the job run, from which the run summary is read.
+	ResultsPath string
+
+	// ReportPath is the path of the state of cloud report markdown, emailed when SMTP delivery is configured.
+	ReportPath string
+
+	// TeamsWebhookURL, when set, is the Microsoft Teams incoming webhook receiving the run summary as an
+	// adaptive card.
+	TeamsWebhookURL string
+
+	// WebhookURL, when set, receives the run summary as a json POST request.
+	WebhookURL string
+
+	// WebhookSecret, when set, signs the requests posted to WebhookURL with an HMAC-SHA256 signature of their body.
+	WebhookSecret string
+
+	// SMTPHost, when set along with EmailRecipients, is the SMTP server through which the report is emailed.
+	SMTPHost string
+
+	// SMTPPort is the port of the SMTP server.
+	SMTPPort int
+
+	// SMTPUsername and SMTPPassword, when set, authenticate against the SMTP server.
+	SMTPUsername string
+	SMTPPassword string
+
+	// EmailFrom is the sender address of the report emails.
+	EmailFrom string
+
+	// EmailRecipients are the addresses the report is emailed to.
+	EmailRecipients []string
+
+	// JiraURL, when set along with JiraProjectKey, is the Jira site within which an issue per division is opened, or
+	// updated, summarizing its drifted and unmanaged resources.
+	JiraURL string
+
+	// JiraUsername, when set, authenticates with basic auth alongside JiraAPIToken. Otherwise, JiraAPIToken is used
+	// as a bearer personal access token.
+	JiraUsername string
+
+	// JiraAPIToken is the Jira API token or personal access token.
+	JiraAPIToken string
+
+	// JiraProjectKey is the key of the project issues are opened within.
+	JiraProjectKey string
+
+	// JiraIssueType is the name of the type of the issues opened.
+	JiraIssueType string
+
+	// Divisions are the divisions scanned by the job, whose open Jira issues are resolved once they no longer have
+	// drifted or unmanaged resources.
+	Divisions []string
+
+	// SensitiveAttributePatterns are regular expressions, matched case-insensitively against attribute paths, for
+	// attributes whose values are masked within the results sent, in addition to the shared sensitive attribute
+	// patterns.
+	SensitiveAttributePatterns []string
+}
+
+// RunSummary is the summary of a job run sent to each notification destination.
+type RunSummary struct {
+
+	// JobName is the name of the job.
+	JobName string `json:"job_name"`
+
+	// PullRequestURL is the url of the first pull request opened by the job run, if any.
+	PullRequestURL string `json:"pull_request_url"`
+
+	// PullRequestURLs are the urls of every pull request opened by the job run, several when the changes are split
+	// across pull requests.
+	PullRequestURLs []string `json:"pull_request_urls"`
+
+	// CompletedAt is the RFC 3339 time at which the job run completed.
+	CompletedAt string `json:"completed_at"`
+
+	// Currency is the ISO 4217 code of the currency of all costs.
+	Currency string `json:"currency"`
+
+	// Summary counts the findings of the job run.
+	Summary inventoryExporter.ResultsSummary `json:"summary"`
+}
+
+// sender sends the run summary to a single notification destination.
+type sender interface {
+	// name is the name of the destination, used within errors.
+	name() string
+
+	// send sends the run summary to the destination.
+	send(ctx context.Context, summary RunSummary) error
+}
+
+// Notifier is a struct that implements interfaces.Notifier.
+type Notifier struct {
+
+	// config is the configuration of the notification destinations.
+	config Config
+
+	// senders send the run summary to each configured destination.
+	senders []sender
+}
+
+// NewNotifier creates a new instance of Notifier, sending to each destination configured within config.
+func NewNotifier(config Config) interfaces.Notifier {
+	senders := []sender{}
+	if config.TeamsWebhookURL != "" {
+		senders = append(senders, &teamsSender{webhookURL: config.TeamsWebhookURL})
+	}
+	if config.WebhookURL != "" {
+		senders = append(senders, &webhookSender{webhookURL: config.WebhookURL, secret: config.WebhookSecret})
+	}
+	if config.SMTPHost != "" && len(config.EmailRecipients) > 0 {
+		senders = append(senders, newEmailSender(config))
+	}
+	if config.JiraURL != "" && config.JiraProjectKey != "" {
+		senders = append(senders, &jiraSender{
+			baseURL:           config.JiraURL,
+			username:          config.JiraUsername,
+			apiToken:          config.JiraAPIToken,
+			projectKey:        config.JiraProjectKey,
+			issueType:         config.JiraIssueType,
+			resultsPath:       config.ResultsPath,
+			divisions:         config.Divisions,
+			sensitivePatterns: config.SensitiveAttributePatterns,
+		})
+	}
+	return &Notifier{config: config, senders: senders}
+}
+
+// Notify sends the summary of the job run, linking to its pull requests, to each configured destination. Every
+// destination is attempted, and the errors of those that failed are returned together.
+func (n *Notifier) Notify(ctx context.Context, pullRequestURLs []string) error {
+	if len(n.senders) == 0 {
+		return nil
+	}
+
+	summary, err := n.runSummary(pullRequestURLs)
+	if err != nil {
+		return fmt.Errorf("[notifier]%w", err)
+	}
+
+	var sendErrors []error
+	for _, s := range n.senders {
+		err = s.send(ctx, summary)
+		if err != nil {
+			sendErrors = append(sendErrors, fmt.Errorf("[%v]%w", s.name(), err))
+			continue
+		}
+		log.Infof("notified %v of the job run", s.name())
+	}
+
+	if len(sendErrors) > 0 {
+		return fmt.Errorf("[notifier]%w", joinErrors(sendErrors))
+	}
+	return nil
+}
+
+// runSummary builds the run summary from the results of the job run. A job run without results, e.g. one that only
+// scanned for drift, is summarized with no findings.
+func (n *Notifier) runSummary(pullRequestURLs []string) (RunSummary, error) {
+	summary := RunSummary{
+		JobName:         n.config.JobName,
+		PullRequestURLs: []string{},
+		CompletedAt:     time.Now().UTC().Format(time.RFC3339),
+	}
+	for _, pullRequestURL := range pullRequestURLs {
+		if pullRequestURL != "" {
+			summary.PullRequestURLs = append(summary.PullRequestURLs, pullRequestURL)
+		}
+	}
+	if len(summary.PullRequestURLs) > 0 {
+		summary.PullRequestURL = summary.PullRequestURLs[0]
+	}
+
+	resultsJSON, err := os.ReadFile(n.config.ResultsPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return summary, nil
+	}
+	if err != nil {
+		return RunSummary{}, fmt.Errorf("[run_summary][os.ReadFile %v]%w", n.config.ResultsPath, err)
+	}
+
+	var results inventoryExporter.Results
+	err = json.Unmarshal(resultsJSON, &results)
+	if err != nil {
+		return RunSummary{}, fmt.Errorf("[run_summary][json.Unmarshal %v]%w", n.config.ResultsPath, err)
+	}
+
+	summary.Currency = results.Currency
+	summary.Summary = results.Summary
+	return summary, nil
+}
+
+// readMaskedResults reads the results of the job run at path with the values of sensitive attributes masked, or
+// returns nil results when the job run wrote none.
+func readMaskedResults(path string, sensitivePatterns []string) (*inventoryExporter.Results, error) {
+	resultsJSON, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[read_masked_results][os.ReadFile %v]%w", path, err)
+	}
+
+	results := &inventoryExporter.Results{}
+	err = json.Unmarshal(resultsJSON, results)
+	if err != nil {
+		return nil, fmt.Errorf("[read_masked_results][json.Unmarshal %v]%w", path, err)
+	}
+
+	patterns, err := sensitiveattributes.Compile(sensitivePatterns)
+	if err != nil {
+		return nil, fmt.Errorf("[read_masked_results][sensitiveattributes.Compile]%w", err)
+	}
+	results.MaskSensitiveValues(patterns)
+	return results, nil
+}
+
+// postJSON posts body to url with the passed headers and returns an error if the response does not have a
+// successful status code.
+func postJSON(ctx context.Context, url string, body []byte, headers map[string]string) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("[post_json][http.NewRequestWithContext]%w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for header, value := range headers {
+		request.Header.Set(header, value)
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("[post_json][http.DefaultClient.Do]%w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		responseBody, _ := io.ReadAll(response.Body)
+		return fmt.Errorf("[post_json][unexpected status code %v: %s]", response.StatusCode, responseBody)
+	}
+	return nil
+}
+
+// joinErrors combines errs into a single error separated by "; ", keeping the first for unwrapping.
+func joinErrors(errs []error) error {
+	if len(errs) == 1 {
+		return errs[0]
+	}
+
+	message := ""
+	for _, err := range errs[1:] {
+		message += "; " + err.Error()
+	}
+	return fmt.Errorf("%w%v", errs[0], message)
+}
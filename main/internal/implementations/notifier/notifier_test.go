@@ -0,0 +1,153 @@
+package notifier
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	inventoryExporter "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/inventory_exporter"
+)
+
+func writeResults(t *testing.T) string {
+	resultsPath := filepath.Join(t.TempDir(), "results.json")
+	resultsJSON, err := json.Marshal(inventoryExporter.Results{
+		SchemaVersion: 1,
+		Currency:      "USD",
+		Summary: inventoryExporter.ResultsSummary{
+			DriftedResources:        2,
+			DriftedAttributes:       3,
+			DeletedResources:        1,
+			NewResources:            4,
+			NewResourcesMonthlyCost: 12.5,
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(resultsPath, resultsJSON, 0o400))
+	return resultsPath
+}
+
+func TestNotify_SignedWebhook(t *testing.T) {
+	// Given
+	var receivedBody []byte
+	var receivedSignature string
+	var receivedTimestamp string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedBody, _ = io.ReadAll(r.Body)
+		receivedSignature = r.Header.Get(SignatureHeader)
+		receivedTimestamp = r.Header.Get(TimestampHeader)
+	}))
+	defer server.Close()
+
+	notifier := NewNotifier(Config{
+		JobName:       "my-job",
+		ResultsPath:   writeResults(t),
+		WebhookURL:    server.URL,
+		WebhookSecret: "my-secret",
+	})
+
+	// When
+	err := notifier.Notify(context.Background(), []string{"https://github.com/org/repo/pull/1"})
+
+	// Then
+	require.NoError(t, err)
+	assert.True(t, hmac.Equal([]byte(signature("my-secret", receivedTimestamp, receivedBody)), []byte(receivedSignature)))
+	assert.False(t, hmac.Equal([]byte(signature("my-secret", "0", receivedBody)), []byte(receivedSignature)))
+	sentAt, err := strconv.ParseInt(receivedTimestamp, 10, 64)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), time.Unix(sentAt, 0), time.Minute)
+
+	var summary RunSummary
+	require.NoError(t, json.Unmarshal(receivedBody, &summary))
+	assert.Equal(t, "my-job", summary.JobName)
+	assert.Equal(t, "https://github.com/org/repo/pull/1", summary.PullRequestURL)
+	assert.Equal(t, []string{"https://github.com/org/repo/pull/1"}, summary.PullRequestURLs)
+	assert.Equal(t, "USD", summary.Currency)
+	assert.Equal(t, 4, summary.Summary.NewResources)
+	assert.Equal(t, 2, summary.Summary.DriftedResources)
+}
+
+func TestNotify_TeamsAdaptiveCard(t *testing.T) {
+	// Given
+	var message map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&message)
+	}))
+	defer server.Close()
+
+	notifier := NewNotifier(Config{
+		JobName:         "my-job",
+		ResultsPath:     writeResults(t),
+		TeamsWebhookURL: server.URL,
+	})
+
+	// When
+	err := notifier.Notify(context.Background(), []string{"https://github.com/org/repo/pull/1", "https://github.com/org/repo/pull/2"})
+
+	// Then
+	require.NoError(t, err)
+	attachment := message["attachments"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "application/vnd.microsoft.card.adaptive", attachment["contentType"])
+
+	card := attachment["content"].(map[string]interface{})
+	facts := card["body"].([]interface{})[2].(map[string]interface{})["facts"].([]interface{})
+	assert.Contains(t, facts, map[string]interface{}{"title": "New resources monthly cost", "value": "12.50 USD"})
+
+	actions := card["actions"].([]interface{})
+	require.Len(t, actions, 2)
+	assert.Equal(t, "https://github.com/org/repo/pull/1", actions[0].(map[string]interface{})["url"])
+	assert.Equal(t, "View pull request part 2", actions[1].(map[string]interface{})["title"])
+	assert.Equal(t, "https://github.com/org/repo/pull/2", actions[1].(map[string]interface{})["url"])
+}
+
+func TestNotify_FailedDestinationDoesNotStopOthers(t *testing.T) {
+	// Given
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	webhookCalls := 0
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		webhookCalls++
+	}))
+	defer webhook.Close()
+
+	notifier := NewNotifier(Config{
+		JobName:         "my-job",
+		ResultsPath:     filepath.Join(t.TempDir(), "missing.json"),
+		TeamsWebhookURL: failing.URL,
+		WebhookURL:      webhook.URL,
+	})
+
+	// When
+	err := notifier.Notify(context.Background(), nil)
+
+	// Then
+	assert.ErrorContains(t, err, "[teams]")
+	assert.Equal(t, 1, webhookCalls)
+}
+
+func TestJoinErrors(t *testing.T) {
+	// Given
+	first := errors.New("[teams][unexpected status code 500]")
+	errs := []error{first, errors.New("[webhook][unexpected status code 502]"), errors.New("[email][dial tcp]")}
+
+	// When
+	err := joinErrors(errs)
+
+	// Then
+	assert.Equal(t, "[teams][unexpected status code 500]; [webhook][unexpected status code 502]; [email][dial tcp]", err.Error())
+	assert.ErrorIs(t, err, first)
+}
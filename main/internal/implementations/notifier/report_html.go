@@ -0,0 +1,151 @@
+package notifier
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	atxHeaderRegex  = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*$`)
+	setextRuleRegex = regexp.MustCompile(`^(=+|-+)\s*$`)
+	tableRuleRegex  = regexp.MustCompile(`^\|?(\s*:?-+:?\s*\|)+\s*:?-*:?\s*$`)
+	listItemRegex   = regexp.MustCompile(`^\s*[-*]\s+(.*)$`)
+	codeSpanRegex   = regexp.MustCompile("`([^`]+)`")
+	boldRegex       = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	linkRegex       = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+)
+
+// reportHTML renders the markdown of the state of cloud report as an html document. Only the markdown produced
+// by the report script is supported: headers, tables, lists, fenced code blocks, paragraphs and inline code, bold
+// text and links.
+func reportHTML(markdown string) string {
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+
+	var body strings.Builder
+	var paragraph []string
+	inList := false
+
+	closeBlocks := func() {
+		if len(paragraph) > 0 {
+			body.WriteString("<p>" + strings.Join(paragraph, "<br>\n") + "</p>\n")
+			paragraph = nil
+		}
+		if inList {
+			body.WriteString("</ul>\n")
+			inList = false
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			closeBlocks()
+
+		case strings.HasPrefix(strings.TrimSpace(line), "```"):
+			closeBlocks()
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, html.EscapeString(lines[i]))
+			}
+			body.WriteString("<pre><code>" + strings.Join(code, "\n") + "</code></pre>\n")
+
+		case i+1 < len(lines) && len(paragraph) == 0 && !inList && setextRuleRegex.MatchString(lines[i+1]):
+			closeBlocks()
+			level := "h1"
+			if strings.HasPrefix(lines[i+1], "-") {
+				level = "h2"
+			}
+			body.WriteString("<" + level + ">" + inlineHTML(line) + "</" + level + ">\n")
+			i++
+
+		case atxHeaderRegex.MatchString(line):
+			closeBlocks()
+			match := atxHeaderRegex.FindStringSubmatch(line)
+			level := "h" + string(rune('0'+len(match[1])))
+			body.WriteString("<" + level + ">" + inlineHTML(match[2]) + "</" + level + ">\n")
+
+		case strings.HasPrefix(strings.TrimSpace(line), "|"):
+			closeBlocks()
+			var rows []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|"); i++ {
+				rows = append(rows, strings.TrimSpace(lines[i]))
+			}
+			i--
+			body.WriteString(tableHTML(rows))
+
+		case listItemRegex.MatchString(line):
+			if len(paragraph) > 0 {
+				body.WriteString("<p>" + strings.Join(paragraph, "<br>\n") + "</p>\n")
+				paragraph = nil
+			}
+			if !inList {
+				body.WriteString("<ul>\n")
+				inList = true
+			}
+			body.WriteString("<li>" + inlineHTML(listItemRegex.FindStringSubmatch(line)[1]) + "</li>\n")
+
+		default:
+			if inList {
+				body.WriteString("</ul>\n")
+				inList = false
+			}
+			paragraph = append(paragraph, inlineHTML(strings.TrimSpace(line)))
+		}
+	}
+	closeBlocks()
+
+	return "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<style>\n" +
+		"body { font-family: sans-serif; }\n" +
+		"table { border-collapse: collapse; }\n" +
+		"th, td { border: 1px solid #d0d7de; padding: 4px 8px; }\n" +
+		"code, pre { background-color: #f6f8fa; }\n" +
+		"</style>\n</head>\n<body>\n" + body.String() + "</body>\n</html>\n"
+}
+
+// tableHTML renders the rows of a markdown table, the first of which is its header, as an html table.
+func tableHTML(rows []string) string {
+	var table strings.Builder
+	table.WriteString("<table>\n")
+
+	for i, row := range rows {
+		if tableRuleRegex.MatchString(row) {
+			continue
+		}
+		cell := "td"
+		if i == 0 {
+			cell = "th"
+		}
+
+		table.WriteString("<tr>")
+		for _, value := range tableCells(row) {
+			table.WriteString("<" + cell + ">" + inlineHTML(value) + "</" + cell + ">")
+		}
+		table.WriteString("</tr>\n")
+	}
+
+	table.WriteString("</table>\n")
+	return table.String()
+}
+
+// tableCells splits a markdown table row into the trimmed values of its cells.
+func tableCells(row string) []string {
+	row = strings.TrimSuffix(strings.TrimPrefix(row, "|"), "|")
+
+	var cells []string
+	for _, value := range strings.Split(row, "|") {
+		cells = append(cells, strings.TrimSpace(value))
+	}
+	return cells
+}
+
+// inlineHTML escapes text and renders its inline code, bold text and links as html.
+func inlineHTML(text string) string {
+	text = html.EscapeString(text)
+	text = codeSpanRegex.ReplaceAllString(text, "<code>$1</code>")
+	text = boldRegex.ReplaceAllString(text, "<strong>$1</strong>")
+	text = linkRegex.ReplaceAllString(text, `<a href="$2">$1</a>`)
+	return text
+}
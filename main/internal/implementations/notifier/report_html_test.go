@@ -0,0 +1,32 @@
+package notifier
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportHTML(t *testing.T) {
+	// Given
+	markdown := "\n\nmy-job - State of Scanned Cloud Resources\n" +
+		"=========================================\n\n" +
+		"# How to Read this Report  \n" +
+		"Your job has run with **2** drifted resources in `prod`.  \n" +
+		"See [the docs](https://docs.example.com?a=1&b=2).\n\n" +
+		"|Resource|Cost|\n| :---: | :---: |\n|aws_instance.<web>|$10.00|\n\n" +
+		"- first\n- second\n\n" +
+		"```mermaid\nflowchart LR\n    a --> b\n```\n"
+
+	// When
+	got := reportHTML(markdown)
+
+	// Then
+	assert.Contains(t, got, "<h1>my-job - State of Scanned Cloud Resources</h1>")
+	assert.Contains(t, got, "<h1>How to Read this Report</h1>")
+	assert.Contains(t, got, "<p>Your job has run with <strong>2</strong> drifted resources in <code>prod</code>.<br>\n"+
+		`See <a href="https://docs.example.com?a=1&amp;b=2">the docs</a>.</p>`)
+	assert.Contains(t, got, "<table>\n<tr><th>Resource</th><th>Cost</th></tr>\n"+
+		"<tr><td>aws_instance.&lt;web&gt;</td><td>$10.00</td></tr>\n</table>")
+	assert.Contains(t, got, "<ul>\n<li>first</li>\n<li>second</li>\n</ul>")
+	assert.Contains(t, got, "<pre><code>flowchart LR\n    a --&gt; b</code></pre>")
+}
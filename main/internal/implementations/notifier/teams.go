@@ -0,0 +1,95 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// teamsSender sends the run summary to a Microsoft Teams incoming webhook as an adaptive card.
+type teamsSender struct {
+
+	// webhookURL is the url of the Teams incoming webhook.
+	webhookURL string
+}
+
+// name is the name of the destination, used within errors.
+func (s *teamsSender) name() string {
+	return "teams"
+}
+
+// send posts the run summary to the Teams incoming webhook as an adaptive card.
+func (s *teamsSender) send(ctx context.Context, summary RunSummary) error {
+	body, err := json.Marshal(teamsMessage(summary))
+	if err != nil {
+		return fmt.Errorf("[teams_sender][json.Marshal]%w", err)
+	}
+
+	err = postJSON(ctx, s.webhookURL, body, nil)
+	if err != nil {
+		return fmt.Errorf("[teams_sender]%w", err)
+	}
+	return nil
+}
+
+// teamsMessage returns the Teams message carrying the run summary as an adaptive card, with a fact for each count
+// and a button opening the pull request.
+func teamsMessage(summary RunSummary) map[string]interface{} {
+	facts := []map[string]string{
+		{"title": "New resources", "value": fmt.Sprintf("%v", summary.Summary.NewResources)},
+		{"title": "New resources monthly cost", "value": fmt.Sprintf("%.2f %v", summary.Summary.NewResourcesMonthlyCost, summary.Currency)},
+		{"title": "Drifted resources", "value": fmt.Sprintf("%v", summary.Summary.DriftedResources)},
+		{"title": "Drifted attributes", "value": fmt.Sprintf("%v", summary.Summary.DriftedAttributes)},
+		{"title": "Deleted resources", "value": fmt.Sprintf("%v", summary.Summary.DeletedResources)},
+	}
+
+	card := map[string]interface{}{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body": []interface{}{
+			map[string]interface{}{
+				"type":   "TextBlock",
+				"text":   summary.JobName,
+				"size":   "Large",
+				"weight": "Bolder",
+				"wrap":   true,
+			},
+			map[string]interface{}{
+				"type":     "TextBlock",
+				"text":     fmt.Sprintf("Completed at %v", summary.CompletedAt),
+				"isSubtle": true,
+				"wrap":     true,
+			},
+			map[string]interface{}{
+				"type":  "FactSet",
+				"facts": facts,
+			},
+		},
+	}
+	if len(summary.PullRequestURLs) > 0 {
+		actions := []interface{}{}
+		for i, pullRequestURL := range summary.PullRequestURLs {
+			title := "View pull request"
+			if len(summary.PullRequestURLs) > 1 {
+				title = fmt.Sprintf("View pull request part %v", i+1)
+			}
+			actions = append(actions, map[string]interface{}{
+				"type":  "Action.OpenUrl",
+				"title": title,
+				"url":   pullRequestURL,
+			})
+		}
+		card["actions"] = actions
+	}
+
+	return map[string]interface{}{
+		"type": "message",
+		"attachments": []interface{}{
+			map[string]interface{}{
+				"contentType": "application/vnd.microsoft.card.adaptive",
+				"content":     card,
+			},
+		},
+	}
+}
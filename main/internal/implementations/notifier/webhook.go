@@ -0,0 +1,64 @@
+package notifier
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// SignatureHeader is the header carrying the HMAC-SHA256 signature of each webhook request, formatted as
+// sha256=<hex digest>, when a webhook secret is configured. The signed content is the TimestampHeader value, a ".",
+// and the body, so that receivers rejecting stale timestamps are not open to replayed requests.
+const SignatureHeader = "X-Cloud-Concierge-Signature"
+
+// TimestampHeader is the header carrying the unix time, in seconds, at which a signed webhook request was sent.
+const TimestampHeader = "X-Cloud-Concierge-Timestamp"
+
+// webhookSender posts the run summary as json to a generic webhook.
+type webhookSender struct {
+
+	// webhookURL is the url receiving the run summary.
+	webhookURL string
+
+	// secret, when set, signs the body of each request.
+	secret string
+}
+
+// name is the name of the destination, used within errors.
+func (s *webhookSender) name() string {
+	return "webhook"
+}
+
+// send posts the run summary to the webhook, signed with the webhook secret when one is configured.
+func (s *webhookSender) send(ctx context.Context, summary RunSummary) error {
+	body, err := json.Marshal(summary)
+	if err != nil {
+		return fmt.Errorf("[webhook_sender][json.Marshal]%w", err)
+	}
+
+	headers := map[string]string{}
+	if s.secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		headers[TimestampHeader] = timestamp
+		headers[SignatureHeader] = signature(s.secret, timestamp, body)
+	}
+
+	err = postJSON(ctx, s.webhookURL, body, headers)
+	if err != nil {
+		return fmt.Errorf("[webhook_sender]%w", err)
+	}
+	return nil
+}
+
+// signature returns the HMAC-SHA256 signature of timestamp and body keyed by secret, formatted as sha256=<hex digest>.
+func signature(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return fmt.Sprintf("sha256=%v", hex.EncodeToString(mac.Sum(nil)))
+}
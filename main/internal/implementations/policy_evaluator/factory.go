@@ -0,0 +1,21 @@
+package policyEvaluator
+
+import (
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
+)
+
+// Factory is a struct for creating different implementations of interfaces.PolicyEvaluator.
+type Factory struct {
+}
+
+// Instantiate creates an implementation of interfaces.PolicyEvaluator.
+func (f *Factory) Instantiate(environment string, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider, config Config, artifactStore artifacts.Store) (interfaces.PolicyEvaluator, error) {
+	switch environment {
+	case "isolated":
+		return new(IsolatedPolicyEvaluator), nil
+	default:
+		return NewRegoPolicyEvaluator(divisionToProvider, config, artifactStore), nil
+	}
+}
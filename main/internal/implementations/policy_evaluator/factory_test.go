@@ -0,0 +1,36 @@
+package policyEvaluator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+func TestCreatePolicyEvaluator(t *testing.T) {
+	// Given
+	divisionToProvider := make(map[terraformValueObjects.Division]terraformValueObjects.Provider)
+	policyEvaluatorFactory := new(Factory)
+
+	// When
+	policyEvaluator, err := policyEvaluatorFactory.Instantiate("", divisionToProvider, Config{}, artifacts.NewMemoryStore(true))
+
+	// Then
+	assert.Nil(t, err)
+	assert.IsType(t, &RegoPolicyEvaluator{}, policyEvaluator)
+}
+
+func TestCreateIsolatedPolicyEvaluator(t *testing.T) {
+	// Given
+	divisionToProvider := make(map[terraformValueObjects.Division]terraformValueObjects.Provider)
+	policyEvaluatorFactory := new(Factory)
+
+	// When
+	policyEvaluator, err := policyEvaluatorFactory.Instantiate("isolated", divisionToProvider, Config{}, artifacts.NewMemoryStore(true))
+
+	// Then
+	assert.Nil(t, err)
+	assert.IsType(t, &IsolatedPolicyEvaluator{}, policyEvaluator)
+}
@@ -0,0 +1,15 @@
+package policyEvaluator
+
+import (
+	"context"
+)
+
+// IsolatedPolicyEvaluator is a struct that implements interfaces.PolicyEvaluator for the purpose
+// of end-to-end testing.
+type IsolatedPolicyEvaluator struct {
+}
+
+// Execute evaluates the run output against the configured policies, writing any violations.
+func (e *IsolatedPolicyEvaluator) Execute(ctx context.Context) error {
+	return nil
+}
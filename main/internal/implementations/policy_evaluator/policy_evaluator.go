@@ -0,0 +1,416 @@
+package policyEvaluator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/sensitiveattributes"
+)
+
+// ErrPolicyViolations is returned when the run output violates at least one policy and the job is configured to
+// fail on policy violations.
+var ErrPolicyViolations = errors.New("[run output violates policies]")
+
+// Config is the configuration of the evaluation of the run output against Rego policies.
+type Config struct {
+
+	// PolicyDirectories are directories of Rego policies evaluated against the run output. Policies are not
+	// evaluated when empty.
+	PolicyDirectories []string
+
+	// Query is the Rego query whose results are the policy violations, e.g. "data.cloudconcierge.deny".
+	Query string
+
+	// FailOnViolations is whether policy violations fail the job. Otherwise, they are listed within the pull request.
+	FailOnViolations bool
+
+	// SensitiveAttributePatterns are regular expressions, matched case-insensitively against attribute paths, for
+	// attributes of new resources whose values are masked within the policy input, in addition to the shared
+	// sensitive attribute patterns.
+	SensitiveAttributePatterns []string
+}
+
+// PolicyInput is the run output against which policies are evaluated, written to mappings/policy-input.json.
+type PolicyInput struct {
+
+	// NewResources are the resources outside of Terraform control that are codified within the pull request.
+	NewResources []NewResource `json:"new_resources"`
+
+	// Drift is the attribute drift of resources managed by Terraform.
+	Drift json.RawMessage `json:"drift"`
+
+	// DeletedResources are the resources managed by Terraform but deleted outside of it.
+	DeletedResources json.RawMessage `json:"deleted_resources"`
+
+	// Costs are the cost estimates of each division.
+	Costs json.RawMessage `json:"costs"`
+
+	// SecurityFindings are the security scan findings of each division.
+	SecurityFindings json.RawMessage `json:"security_findings"`
+}
+
+// NewResource is a single resource outside of Terraform control, along with its scanned attributes.
+type NewResource struct {
+	Provider     string            `json:"provider"`
+	Division     string            `json:"division"`
+	Workspace    string            `json:"workspace"`
+	ResourceType string            `json:"resource_type"`
+	ResourceName string            `json:"resource_name"`
+	ResourceID   string            `json:"resource_id"`
+	Region       string            `json:"region"`
+	Attributes   map[string]string `json:"attributes"`
+}
+
+// PolicyViolation is a single policy violation, written to mappings/policy-violations.json.
+type PolicyViolation struct {
+
+	// Message describes the violation.
+	Message string `json:"message"`
+
+	// Resource is the resource in violation, when reported by the policy.
+	Resource string `json:"resource,omitempty"`
+}
+
+// opaEvalOutput is the json output of opa eval.
+type opaEvalOutput struct {
+	Result []struct {
+		Expressions []struct {
+			Value interface{} `json:"value"`
+		} `json:"expressions"`
+	} `json:"result"`
+}
+
+// RegoPolicyEvaluator is a struct that implements interfaces.PolicyEvaluator by evaluating the run output against
+// Rego policies with the opa CLI.
+type RegoPolicyEvaluator struct {
+	// divisionToProvider is a map between the string representing a division and the corresponding
+	// cloud provider (aws, azurerm, google, etc.).
+	divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider
+
+	// config is the configuration of the policy evaluation.
+	config Config
+
+	// artifactStore holds the mappings read and written by the policy evaluation.
+	artifactStore artifacts.Store
+}
+
+// NewRegoPolicyEvaluator generates a new instance from RegoPolicyEvaluator
+func NewRegoPolicyEvaluator(divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider, config Config, artifactStore artifacts.Store) *RegoPolicyEvaluator {
+	return &RegoPolicyEvaluator{
+		divisionToProvider: divisionToProvider,
+		config:             config,
+		artifactStore:      artifactStore,
+	}
+}
+
+// Execute evaluates the run output against the configured Rego policies and writes any violations to
+// mappings/policy-violations.json. ErrPolicyViolations is returned for violations when configured to fail.
+func (e *RegoPolicyEvaluator) Execute(ctx context.Context) error {
+	if len(e.config.PolicyDirectories) == 0 {
+		return nil
+	}
+
+	input, err := e.buildPolicyInput()
+	if err != nil {
+		return fmt.Errorf("[rego_policy_evaluator][execute]%w", err)
+	}
+
+	err = writeJSON(e.artifactStore, "mappings/policy-input.json", input)
+	if err != nil {
+		return fmt.Errorf("[rego_policy_evaluator][execute]%w", err)
+	}
+
+	err = e.artifactStore.Flush()
+	if err != nil {
+		return fmt.Errorf("[rego_policy_evaluator][execute][artifacts.Flush]%w", err)
+	}
+
+	output, err := e.runOPA(ctx, "mappings/policy-input.json")
+	if err != nil {
+		return fmt.Errorf("[rego_policy_evaluator][execute][error running opa eval]%w", err)
+	}
+
+	violations, err := parsePolicyViolations(output)
+	if err != nil {
+		return fmt.Errorf("[rego_policy_evaluator][execute][error parsing opa eval output]%w", err)
+	}
+
+	err = writeJSON(e.artifactStore, "mappings/policy-violations.json", violations)
+	if err != nil {
+		return fmt.Errorf("[rego_policy_evaluator][execute]%w", err)
+	}
+
+	if len(violations) > 0 && e.config.FailOnViolations {
+		return fmt.Errorf("[rego_policy_evaluator][execute][%d violation(s)]%w", len(violations), ErrPolicyViolations)
+	}
+	return nil
+}
+
+// buildPolicyInput combines the outputs written earlier in the job run into the policy input.
+func (e *RegoPolicyEvaluator) buildPolicyInput() (PolicyInput, error) {
+	newResources, err := e.newResources()
+	if err != nil {
+		return PolicyInput{}, err
+	}
+
+	input := PolicyInput{NewResources: newResources}
+	optionalFiles := map[string]*json.RawMessage{
+		"mappings/drift-resources-differences.json": &input.Drift,
+		"mappings/drift-resources-deleted.json":     &input.DeletedResources,
+		"mappings/division-to-cost-estimates.json":  &input.Costs,
+		"mappings/division-to-security-scan.json":   &input.SecurityFindings,
+	}
+	for path, target := range optionalFiles {
+		content, err := e.artifactStore.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			*target = json.RawMessage("null")
+			continue
+		}
+		if err != nil {
+			return PolicyInput{}, fmt.Errorf("[build_policy_input][artifacts.ReadFile %v]%w", path, err)
+		}
+		if !json.Valid(content) {
+			return PolicyInput{}, fmt.Errorf("[build_policy_input][%v is not valid json]", path)
+		}
+		*target = content
+	}
+
+	return input, nil
+}
+
+// newResources returns the resources outside of Terraform control that were placed within a workspace, along with
+// their attributes from the terraformer state of their division. The values of sensitive attributes are masked, so
+// that violation messages cannot publish them.
+func (e *RegoPolicyEvaluator) newResources() ([]NewResource, error) {
+	newResources := make([]NewResource, 0)
+
+	sensitivePatterns, err := sensitiveattributes.Compile(e.config.SensitiveAttributePatterns)
+	if err != nil {
+		return nil, fmt.Errorf("[new_resources][sensitiveattributes.Compile]%w", err)
+	}
+
+	divisionToNewResources := map[string]map[string]struct {
+		ResourceType            string `json:"ResourceType"`
+		ResourceTerraformerName string `json:"ResourceTerraformerName"`
+		Region                  string `json:"Region"`
+	}{}
+	err = readOptionalJSON(e.artifactStore, "mappings/division-to-new-resources.json", &divisionToNewResources)
+	if err != nil {
+		return nil, err
+	}
+
+	newResourcesToWorkspace := map[string]string{}
+	err = readOptionalJSON(e.artifactStore, "mappings/new-resources-to-workspace.json", &newResourcesToWorkspace)
+	if err != nil {
+		return nil, err
+	}
+
+	for fullDivisionName, resources := range divisionToNewResources {
+		provider, division, _ := strings.Cut(fullDivisionName, "-")
+
+		attributes, err := terraformerAttributes(fullDivisionName)
+		if err != nil {
+			return nil, err
+		}
+
+		for resourceID, resource := range resources {
+			resourceName := fmt.Sprintf("%v.%v", resource.ResourceType, resource.ResourceTerraformerName)
+			workspace, ok := newResourcesToWorkspace[fmt.Sprintf("%v.%v", fullDivisionName, resourceName)]
+			if !ok {
+				continue
+			}
+
+			newResources = append(newResources, NewResource{
+				Provider:     provider,
+				Division:     division,
+				Workspace:    workspace,
+				ResourceType: resource.ResourceType,
+				ResourceName: resource.ResourceTerraformerName,
+				ResourceID:   resourceID,
+				Region:       resource.Region,
+				Attributes:   maskSensitiveAttributes(attributes[resourceName], sensitivePatterns),
+			})
+		}
+	}
+
+	sort.Slice(newResources, func(i, j int) bool {
+		if newResources[i].Division != newResources[j].Division {
+			return newResources[i].Division < newResources[j].Division
+		}
+		if newResources[i].ResourceType != newResources[j].ResourceType {
+			return newResources[i].ResourceType < newResources[j].ResourceType
+		}
+		return newResources[i].ResourceName < newResources[j].ResourceName
+	})
+	return newResources, nil
+}
+
+// maskSensitiveAttributes returns a copy of the flat attributes with the values of those matching patterns masked.
+func maskSensitiveAttributes(attributes map[string]string, patterns []*regexp.Regexp) map[string]string {
+	if attributes == nil {
+		return nil
+	}
+
+	masked := make(map[string]string, len(attributes))
+	for attribute, value := range attributes {
+		if value != "" && sensitiveattributes.Matches(attribute, patterns) {
+			value = sensitiveattributes.Mask
+		}
+		masked[attribute] = value
+	}
+	return masked
+}
+
+// terraformerAttributes returns the flat attributes of each "type.name" resource within the terraformer state of a
+// division, or none when the division was not scanned.
+func terraformerAttributes(fullDivisionName string) (map[string]map[string]string, error) {
+	attributes := map[string]map[string]string{}
+
+	content, err := os.ReadFile(fmt.Sprintf("current_cloud/%v/terraform.tfstate", fullDivisionName))
+	if errors.Is(err, os.ErrNotExist) {
+		return attributes, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[terraformer_attributes][os.ReadFile]%w", err)
+	}
+
+	stateFile, err := driftDetector.ParseTerraformerStateFile(content)
+	if err != nil {
+		return nil, fmt.Errorf("[terraformer_attributes][ParseTerraformerStateFile]%w", err)
+	}
+
+	for _, resource := range stateFile.Resources {
+		if len(resource.Instances) == 0 {
+			continue
+		}
+		attributes[fmt.Sprintf("%v.%v", resource.Type, resource.Name)] = resource.Instances[0].AttributesFlat
+	}
+	return attributes, nil
+}
+
+// runOPA evaluates the configured query against the policy input with the opa CLI, returning its json output.
+func (e *RegoPolicyEvaluator) runOPA(ctx context.Context, inputPath string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "opa", e.opaArgs(inputPath)...)
+
+	var out bytes.Buffer
+	var stderr bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("%s, %w", stderr.String(), err)
+	}
+	return out.Bytes(), nil
+}
+
+// opaArgs returns the arguments of an opa evaluation of the configured query against the policy input.
+func (e *RegoPolicyEvaluator) opaArgs(inputPath string) []string {
+	args := []string{"eval", "--format", "json", "--input", inputPath}
+	for _, directory := range e.config.PolicyDirectories {
+		args = append(args, "--data", directory)
+	}
+	return append(args, e.config.Query)
+}
+
+// parsePolicyViolations converts the output of opa eval into policy violations. The query may evaluate to a set of
+// messages, or of objects with a "msg" or "message" and an optional "resource".
+func parsePolicyViolations(output []byte) ([]PolicyViolation, error) {
+	evalOutput := opaEvalOutput{}
+	err := json.Unmarshal(output, &evalOutput)
+	if err != nil {
+		return nil, fmt.Errorf("[json.Unmarshal]%w", err)
+	}
+
+	violations := make([]PolicyViolation, 0)
+	for _, result := range evalOutput.Result {
+		for _, expression := range result.Expressions {
+			values, ok := expression.Value.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("[the policy query must evaluate to a set or array, got %T]", expression.Value)
+			}
+
+			for _, value := range values {
+				violations = append(violations, toPolicyViolation(value))
+			}
+		}
+	}
+
+	sort.Slice(violations, func(i, j int) bool {
+		if violations[i].Resource != violations[j].Resource {
+			return violations[i].Resource < violations[j].Resource
+		}
+		return violations[i].Message < violations[j].Message
+	})
+	return violations, nil
+}
+
+// toPolicyViolation converts a single value of the policy query's result into a policy violation.
+func toPolicyViolation(value interface{}) PolicyViolation {
+	switch typedValue := value.(type) {
+	case string:
+		return PolicyViolation{Message: typedValue}
+	case map[string]interface{}:
+		violation := PolicyViolation{}
+		for _, key := range []string{"msg", "message"} {
+			if message, ok := typedValue[key].(string); ok {
+				violation.Message = message
+				break
+			}
+		}
+		if resource, ok := typedValue["resource"].(string); ok {
+			violation.Resource = resource
+		}
+		if violation.Message == "" {
+			content, _ := json.Marshal(typedValue)
+			violation.Message = string(content)
+		}
+		return violation
+	default:
+		content, _ := json.Marshal(typedValue)
+		return PolicyViolation{Message: string(content)}
+	}
+}
+
+// readOptionalJSON unmarshals the json content of path into target, leaving target untouched when path does not
+// exist.
+func readOptionalJSON(store artifacts.Store, path string, target interface{}) error {
+	content, err := store.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("[artifacts.ReadFile %v]%w", path, err)
+	}
+
+	err = json.Unmarshal(content, target)
+	if err != nil {
+		return fmt.Errorf("[json.Unmarshal %v]%w", path, err)
+	}
+	return nil
+}
+
+// writeJSON writes the indented json of value to path.
+func writeJSON(store artifacts.Store, path string, value interface{}) error {
+	content, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[json.MarshalIndent %v]%w", path, err)
+	}
+
+	err = store.WriteFile(path, content, 0400)
+	if err != nil {
+		return fmt.Errorf("[artifacts.WriteFile %v]%w", path, err)
+	}
+	return nil
+}
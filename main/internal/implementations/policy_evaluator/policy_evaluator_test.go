@@ -0,0 +1,149 @@
+package policyEvaluator
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+)
+
+func TestRegoPolicyEvaluator_BuildPolicyInput(t *testing.T) {
+	// Given
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+	require.NoError(t, os.MkdirAll("mappings", 0755))
+	require.NoError(t, os.MkdirAll("current_cloud/aws-prod", 0755))
+
+	require.NoError(t, os.WriteFile("mappings/division-to-new-resources.json", []byte(`{
+		"aws-prod": {
+			"arn:aws:s3:::public-assets": {"ResourceType": "aws_s3_bucket", "ResourceTerraformerName": "tfer--public-assets", "Region": "us-east-1"},
+			"arn:aws:s3:::too-young": {"ResourceType": "aws_s3_bucket", "ResourceTerraformerName": "tfer--too-young", "Region": "us-east-1"}
+		}
+	}`), 0400))
+	require.NoError(t, os.WriteFile("mappings/new-resources-to-workspace.json", []byte(`{"aws-prod.aws_s3_bucket.tfer--public-assets": "storage"}`), 0400))
+	require.NoError(t, os.WriteFile("current_cloud/aws-prod/terraform.tfstate", []byte(`{
+		"version": 4,
+		"resources": [
+			{
+				"mode": "managed",
+				"type": "aws_s3_bucket",
+				"name": "tfer--public-assets",
+				"provider": "provider[\"registry.terraform.io/hashicorp/aws\"]",
+				"instances": [
+					{
+						"schema_version": 0,
+						"attributes_flat": {"id": "public-assets", "acl": "public-read", "tags.%": "1", "tags.team": "web", "tags.api_key": "abc123"}
+					}
+				]
+			}
+		]
+	}`), 0400))
+	require.NoError(t, os.WriteFile("mappings/division-to-security-scan.json", []byte(`{"prod": []}`), 0400))
+
+	evaluator := NewRegoPolicyEvaluator(nil, Config{}, artifacts.NewMemoryStore(true))
+
+	// When
+	input, err := evaluator.buildPolicyInput()
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, []NewResource{
+		{
+			Provider:     "aws",
+			Division:     "prod",
+			Workspace:    "storage",
+			ResourceType: "aws_s3_bucket",
+			ResourceName: "tfer--public-assets",
+			ResourceID:   "arn:aws:s3:::public-assets",
+			Region:       "us-east-1",
+			Attributes:   map[string]string{"acl": "public-read", "tags.%": "1", "tags.team": "web", "tags.api_key": "(sensitive value)", "id": "public-assets"},
+		},
+	}, input.NewResources)
+	assert.JSONEq(t, `{"prod": []}`, string(input.SecurityFindings))
+	assert.JSONEq(t, `null`, string(input.Drift))
+
+	_, err = json.Marshal(input)
+	assert.NoError(t, err)
+}
+
+func TestParsePolicyViolations(t *testing.T) {
+	// Given
+	output := []byte(`{
+		"result": [
+			{
+				"expressions": [
+					{
+						"value": [
+							"aws_s3_bucket.tfer--public-assets is public but has no data-classification tag",
+							{"msg": "monthly cost of division prod exceeds 1000", "resource": "prod"},
+							{"severity": "high"}
+						],
+						"text": "data.cloudconcierge.deny",
+						"location": {"row": 1, "col": 1}
+					}
+				]
+			}
+		]
+	}`)
+
+	// When
+	violations, err := parsePolicyViolations(output)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, []PolicyViolation{
+		{Message: "aws_s3_bucket.tfer--public-assets is public but has no data-classification tag"},
+		{Message: `{"severity":"high"}`},
+		{Message: "monthly cost of division prod exceeds 1000", Resource: "prod"},
+	}, violations)
+
+	// When the query is undefined
+	violations, err = parsePolicyViolations([]byte(`{}`))
+
+	// Then
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+
+	// When the query does not evaluate to a collection
+	_, err = parsePolicyViolations([]byte(`{"result": [{"expressions": [{"value": true}]}]}`))
+
+	// Then
+	assert.Error(t, err)
+}
+
+func TestRegoPolicyEvaluator_OPAArgs(t *testing.T) {
+	// Given
+	evaluator := NewRegoPolicyEvaluator(nil, Config{PolicyDirectories: []string{"/policies/platform", "/policies/finops"}, Query: "data.cloudconcierge.deny"}, artifacts.NewMemoryStore(true))
+
+	// When
+	args := evaluator.opaArgs("mappings/policy-input.json")
+
+	// Then
+	assert.Equal(t, []string{
+		"eval", "--format", "json", "--input", "mappings/policy-input.json",
+		"--data", "/policies/platform", "--data", "/policies/finops",
+		"data.cloudconcierge.deny",
+	}, args)
+}
+
+func TestRegoPolicyEvaluator_ExecuteWithoutPolicies(t *testing.T) {
+	// Given
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	// When
+	err = NewRegoPolicyEvaluator(nil, Config{}, artifacts.NewMemoryStore(true)).Execute(context.Background())
+
+	// Then
+	assert.NoError(t, err)
+	assert.NoFileExists(t, "mappings/policy-violations.json")
+}
@@ -0,0 +1,90 @@
+package resourcesCalculator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// catchAllWorkspace returns the name of the workspace within the configured catch-all directory, and whether that
+// workspace is new. A workspace outside of workspaceToDirectory is named after its directory, e.g. "unmanaged" for
+// "/unmanaged/", and is only new when the directory holds no main.tf, e.g. one merged from an earlier pull request.
+func (c *TerraformResourcesCalculator) catchAllWorkspace(workspaceToDirectory map[string]string) (string, bool, error) {
+	catchAllDirectory := strings.Trim(c.config.CatchAllWorkspaceDirectory, "/")
+	if catchAllDirectory == "" {
+		return "", false, fmt.Errorf("[catch_all_workspace][the catch-all workspace directory cannot be the repository root]")
+	}
+
+	for workspace, directory := range workspaceToDirectory {
+		if strings.Trim(directory, "/") == catchAllDirectory {
+			return workspace, false, nil
+		}
+	}
+
+	workspace := strings.ReplaceAll(catchAllDirectory, "/", "-")
+	if _, ok := workspaceToDirectory[workspace]; ok {
+		return "", false, fmt.Errorf("[catch_all_workspace][new workspace %v would share the name of an existing workspace]", workspace)
+	}
+
+	_, err := os.Stat(fmt.Sprintf("repo/%v/main.tf", catchAllDirectory))
+	if err == nil {
+		return workspace, false, nil
+	}
+	if !os.IsNotExist(err) {
+		return "", false, fmt.Errorf("[catch_all_workspace][os.Stat]%w", err)
+	}
+	return workspace, true, nil
+}
+
+// placeWithinCatchAllWorkspace places the resources fitting no workspace within the catch-all workspace, writing
+// mappings/new-resources-to-workspace.json. A new catch-all workspace is proposed within mappings/new-workspaces.json,
+// while an existing one absent from workspaceToDirectory is recorded within mappings/unconfigured-workspaces.json.
+func (c *TerraformResourcesCalculator) placeWithinCatchAllWorkspace(
+	resourceToWorkspace map[string]string,
+	unplacedResources map[string]ManualPlacement,
+	workspaceToDirectory map[string]string,
+) error {
+	if len(unplacedResources) == 0 {
+		return nil
+	}
+
+	workspace, isNew, err := c.catchAllWorkspace(workspaceToDirectory)
+	if err != nil {
+		return fmt.Errorf("[place_within_catch_all_workspace]%w", err)
+	}
+
+	for resource := range unplacedResources {
+		resourceToWorkspace[resource] = workspace
+	}
+
+	resourceToWorkspaceBytes, err := json.MarshalIndent(resourceToWorkspace, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[place_within_catch_all_workspace][json.MarshalIndent]%w", err)
+	}
+	err = rewriteMappingFile(c.artifactStore, "mappings/new-resources-to-workspace.json", resourceToWorkspaceBytes)
+	if err != nil {
+		return fmt.Errorf("[place_within_catch_all_workspace]%w", err)
+	}
+
+	if _, ok := workspaceToDirectory[workspace]; ok {
+		return nil
+	}
+
+	workspacesBytes, err := json.MarshalIndent(map[string]string{
+		workspace: "/" + strings.Trim(c.config.CatchAllWorkspaceDirectory, "/") + "/",
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[place_within_catch_all_workspace][json.MarshalIndent]%w", err)
+	}
+
+	workspacesPath := "mappings/new-workspaces.json"
+	if !isNew {
+		workspacesPath = "mappings/unconfigured-workspaces.json"
+	}
+	err = rewriteMappingFile(c.artifactStore, workspacesPath, workspacesBytes)
+	if err != nil {
+		return fmt.Errorf("[place_within_catch_all_workspace]%w", err)
+	}
+	return nil
+}
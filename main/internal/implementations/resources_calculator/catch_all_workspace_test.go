@@ -0,0 +1,99 @@
+package resourcesCalculator
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+)
+
+func TestRouteLowConfidencePlacements_CatchAllWorkspace(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+	require.NoError(t, os.MkdirAll("mappings", 0700))
+
+	require.NoError(t, os.WriteFile("mappings/new-resources-to-workspace.json", []byte(`{
+		"aws-111111111111.aws_subnet.tfer--subnet-1": "networking",
+		"aws-111111111111.aws_iam_role.tfer--role": "networking"
+	}`), 0400))
+	require.NoError(t, os.WriteFile("mappings/new-resources-to-placement-confidence.json", []byte(`{
+		"aws-111111111111.aws_subnet.tfer--subnet-1": 0.82,
+		"aws-111111111111.aws_iam_role.tfer--role": 0
+	}`), 0400))
+
+	c := TerraformResourcesCalculator{artifactStore: artifacts.NewMemoryStore(true), config: Config{CatchAllWorkspaceDirectory: "unmanaged"}}
+
+	// When
+	err := c.routeLowConfidencePlacements(map[string]string{"networking": "/networking/"})
+
+	// Then
+	require.NoError(t, err)
+
+	content, err := os.ReadFile("mappings/new-resources-to-workspace.json")
+	require.NoError(t, err)
+	resourceToWorkspace := map[string]string{}
+	require.NoError(t, json.Unmarshal(content, &resourceToWorkspace))
+	assert.Equal(t, map[string]string{
+		"aws-111111111111.aws_subnet.tfer--subnet-1": "networking",
+		"aws-111111111111.aws_iam_role.tfer--role":   "unmanaged",
+	}, resourceToWorkspace)
+
+	content, err = os.ReadFile("mappings/new-workspaces.json")
+	require.NoError(t, err)
+	newWorkspaces := map[string]string{}
+	require.NoError(t, json.Unmarshal(content, &newWorkspaces))
+	assert.Equal(t, map[string]string{"unmanaged": "/unmanaged/"}, newWorkspaces)
+
+	_, err = os.Stat("mappings/needs-manual-placement.json")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestCatchAllWorkspace_ExistingWorkspace(t *testing.T) {
+	// Given
+	c := TerraformResourcesCalculator{config: Config{CatchAllWorkspaceDirectory: "/shared/"}}
+
+	// When
+	workspace, isNew, err := c.catchAllWorkspace(map[string]string{"networking": "/networking/", "shared-infra": "/shared/"})
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, "shared-infra", workspace)
+	assert.False(t, isNew)
+}
+
+func TestPlaceWithinCatchAllWorkspace_MergedWorkspace(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+	require.NoError(t, os.MkdirAll("mappings", 0700))
+	require.NoError(t, os.MkdirAll("repo/unmanaged", 0700))
+	require.NoError(t, os.WriteFile("repo/unmanaged/main.tf", []byte("terraform {}\n"), 0400))
+
+	c := TerraformResourcesCalculator{artifactStore: artifacts.NewMemoryStore(true), config: Config{CatchAllWorkspaceDirectory: "unmanaged"}}
+	resourceToWorkspace := map[string]string{}
+
+	// When
+	err := c.placeWithinCatchAllWorkspace(
+		resourceToWorkspace,
+		map[string]ManualPlacement{"aws-111111111111.aws_iam_role.tfer--role": {}},
+		map[string]string{"networking": "/networking/"},
+	)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"aws-111111111111.aws_iam_role.tfer--role": "unmanaged"}, resourceToWorkspace)
+	assert.NoFileExists(t, "mappings/new-workspaces.json")
+
+	content, err := os.ReadFile("mappings/unconfigured-workspaces.json")
+	require.NoError(t, err)
+	unconfiguredWorkspaces := map[string]string{}
+	require.NoError(t, json.Unmarshal(content, &unconfiguredWorkspaces))
+	assert.Equal(t, map[string]string{"unmanaged": "/unmanaged/"}, unconfiguredWorkspaces)
+}
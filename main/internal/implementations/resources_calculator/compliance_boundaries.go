@@ -0,0 +1,208 @@
+package resourcesCalculator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrComplianceBoundaryCoMingled is returned when resources from an isolated compliance boundary would be
+// placed within the same workspace as resources outside of that boundary.
+var ErrComplianceBoundaryCoMingled = errors.New("[resources from an isolated compliance boundary cannot share a workspace with other resources]")
+
+// ComplianceBoundary defines a set of divisions and/or regions whose new resources are routed to a dedicated
+// workspace directory, and optionally must never be placed alongside resources outside the boundary.
+type ComplianceBoundary struct {
+	// Name is the name of the compliance boundary, e.g. "pci".
+	Name string `json:"name"`
+
+	// Divisions are the divisions whose resources fall within the boundary. When empty, all divisions match.
+	Divisions []string `json:"divisions"`
+
+	// Regions are the regions whose resources fall within the boundary. When empty, all regions match.
+	Regions []string `json:"regions"`
+
+	// Directory is the workspace directory into which all resources within the boundary are placed. When empty,
+	// the recommended workspace placement is kept.
+	Directory string `json:"directory"`
+
+	// Isolated flags that resources within the boundary must not share a workspace with resources outside of it.
+	Isolated bool `json:"isolated"`
+}
+
+// ComplianceBoundariesDecoder is an ordered list of compliance boundaries. A resource belongs to the
+// first boundary that it matches.
+type ComplianceBoundariesDecoder []ComplianceBoundary
+
+// Decode provides the object decoding logic for ComplianceBoundariesDecoder, in accordance with the envconfig
+// package's requirements.
+func (d *ComplianceBoundariesDecoder) Decode(value string) error {
+	if strings.Trim(value, " ") == "" {
+		return nil
+	}
+
+	boundaries := make([]ComplianceBoundary, 0)
+	err := json.Unmarshal([]byte(value), &boundaries)
+	if err != nil {
+		return fmt.Errorf("expected the compliance boundaries formatted as a json list of objects: %v", err)
+	}
+
+	boundaryNames := map[string]bool{}
+	for _, boundary := range boundaries {
+		if boundary.Name == "" {
+			return fmt.Errorf("the field `name` is required for every compliance boundary")
+		}
+		if boundaryNames[boundary.Name] {
+			return fmt.Errorf("the compliance boundary %v is defined more than once", boundary.Name)
+		}
+		boundaryNames[boundary.Name] = true
+
+		if len(boundary.Divisions) == 0 && len(boundary.Regions) == 0 {
+			return fmt.Errorf("the compliance boundary %v must specify at least one division or region", boundary.Name)
+		}
+	}
+
+	*d = boundaries
+	return nil
+}
+
+// boundaryForResource returns the first compliance boundary matching the passed division and region, if any.
+func (d ComplianceBoundariesDecoder) boundaryForResource(division string, region string) (ComplianceBoundary, bool) {
+	for _, boundary := range d {
+		if len(boundary.Divisions) > 0 && !containsString(boundary.Divisions, division) {
+			continue
+		}
+		if len(boundary.Regions) > 0 && !containsString(boundary.Regions, region) {
+			continue
+		}
+		return boundary, true
+	}
+	return ComplianceBoundary{}, false
+}
+
+// applyComplianceBoundaries reroutes recommended workspace placements in mappings/new-resources-to-workspace.json
+// according to the configured compliance boundaries.
+func (c *TerraformResourcesCalculator) applyComplianceBoundaries(workspaceToDirectory map[string]string) error {
+	if len(c.config.ComplianceBoundaries) == 0 {
+		return nil
+	}
+
+	resourceToWorkspaceBytes, err := c.artifactStore.ReadFile("mappings/new-resources-to-workspace.json")
+	if err != nil {
+		return fmt.Errorf("[apply_compliance_boundaries][artifacts.ReadFile new-resources-to-workspace.json]%w", err)
+	}
+	resourceToWorkspace := map[string]string{}
+	err = json.Unmarshal(resourceToWorkspaceBytes, &resourceToWorkspace)
+	if err != nil {
+		return fmt.Errorf("[apply_compliance_boundaries][json.Unmarshal new-resources-to-workspace.json]%w", err)
+	}
+
+	divisionToNewResourcesBytes, err := c.artifactStore.ReadFile("mappings/division-to-new-resources.json")
+	if err != nil {
+		return fmt.Errorf("[apply_compliance_boundaries][artifacts.ReadFile division-to-new-resources.json]%w", err)
+	}
+	divisionToNewResources := DivisionToNewResources{}
+	err = json.Unmarshal(divisionToNewResourcesBytes, &divisionToNewResources)
+	if err != nil {
+		return fmt.Errorf("[apply_compliance_boundaries][json.Unmarshal division-to-new-resources.json]%w", err)
+	}
+
+	resourceToWorkspace, err = c.placeResourcesByComplianceBoundary(resourceToWorkspace, divisionToNewResources, workspaceToDirectory)
+	if err != nil {
+		return fmt.Errorf("[apply_compliance_boundaries]%w", err)
+	}
+
+	outputBytes, err := json.MarshalIndent(resourceToWorkspace, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[apply_compliance_boundaries][json.MarshalIndent]%w", err)
+	}
+
+	err = c.artifactStore.WriteFile("mappings/new-resources-to-workspace.json", outputBytes, 0400)
+	if err != nil {
+		return fmt.Errorf("[apply_compliance_boundaries][artifacts.WriteFile new-resources-to-workspace.json]%w", err)
+	}
+
+	return nil
+}
+
+// placeResourcesByComplianceBoundary moves resources within a compliance boundary into the boundary's workspace, and
+// validates that no workspace mixes resources of an isolated boundary with resources outside of it.
+func (c *TerraformResourcesCalculator) placeResourcesByComplianceBoundary(
+	resourceToWorkspace map[string]string,
+	divisionToNewResources DivisionToNewResources,
+	workspaceToDirectory map[string]string,
+) (map[string]string, error) {
+	resourceToRegion := map[string]string{}
+	for division, newResources := range divisionToNewResources {
+		for _, resourceData := range newResources {
+			resourceKey := fmt.Sprintf("%v.%v.%v", division, resourceData.ResourceType, resourceData.ResourceTerraformerName)
+			resourceToRegion[resourceKey] = resourceData.Region
+		}
+	}
+
+	directoryToWorkspace := map[string]string{}
+	for workspace, directory := range workspaceToDirectory {
+		directoryToWorkspace[strings.Trim(directory, "/")] = workspace
+	}
+
+	placedResources := map[string]string{}
+	workspaceToBoundaries := map[string]map[string]bool{}
+	isolatedBoundaries := map[string]bool{}
+	for resource, workspace := range resourceToWorkspace {
+		fullDivision := strings.Split(resource, ".")[0]
+		division := fullDivision
+		if divisionSlice := strings.SplitN(fullDivision, "-", 2); len(divisionSlice) == 2 {
+			division = divisionSlice[1]
+		}
+
+		boundaryName := ""
+		boundary, ok := c.config.ComplianceBoundaries.boundaryForResource(division, resourceToRegion[resource])
+		if ok {
+			boundaryName = boundary.Name
+			if boundary.Isolated {
+				isolatedBoundaries[boundary.Name] = true
+			}
+
+			if boundary.Directory != "" {
+				boundaryWorkspace, found := directoryToWorkspace[strings.Trim(boundary.Directory, "/")]
+				if !found {
+					return nil, fmt.Errorf(
+						"[the directory %v of compliance boundary %v is not a configured workspace directory]",
+						boundary.Directory, boundary.Name,
+					)
+				}
+				workspace = boundaryWorkspace
+			}
+		}
+
+		placedResources[resource] = workspace
+		if _, ok := workspaceToBoundaries[workspace]; !ok {
+			workspaceToBoundaries[workspace] = map[string]bool{}
+		}
+		workspaceToBoundaries[workspace][boundaryName] = true
+	}
+
+	for workspace, boundaries := range workspaceToBoundaries {
+		if len(boundaries) < 2 {
+			continue
+		}
+		for boundaryName := range boundaries {
+			if isolatedBoundaries[boundaryName] {
+				return nil, fmt.Errorf("[workspace %v, boundary %v]%w", workspace, boundaryName, ErrComplianceBoundaryCoMingled)
+			}
+		}
+	}
+
+	return placedResources, nil
+}
+
+// containsString returns true if the target string is within the passed slice.
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
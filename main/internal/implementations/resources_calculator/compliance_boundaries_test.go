@@ -0,0 +1,110 @@
+package resourcesCalculator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComplianceBoundariesDecoder_Decode(t *testing.T) {
+	// Given
+	decoder := ComplianceBoundariesDecoder{}
+	input := `[{"name": "pci", "divisions": ["payments"], "directory": "/pci/", "isolated": true}, {"name": "eu", "regions": ["eu-west-1"]}]`
+
+	// When
+	err := decoder.Decode(input)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, ComplianceBoundariesDecoder{
+		{Name: "pci", Divisions: []string{"payments"}, Directory: "/pci/", Isolated: true},
+		{Name: "eu", Regions: []string{"eu-west-1"}},
+	}, decoder)
+
+	assert.Error(t, decoder.Decode(`[{"name": "pci"}]`))
+	assert.Error(t, decoder.Decode(`[{"divisions": ["payments"]}]`))
+	assert.Error(t, decoder.Decode(`{"name": "pci"}`))
+}
+
+func TestPlaceResourcesByComplianceBoundary(t *testing.T) {
+	// Given
+	c := TerraformResourcesCalculator{
+		config: Config{
+			ComplianceBoundaries: ComplianceBoundariesDecoder{
+				{Name: "pci", Divisions: []string{"payments"}, Directory: "/pci/", Isolated: true},
+				{Name: "eu", Regions: []string{"eu-west-1"}, Directory: "eu/"},
+			},
+		},
+	}
+	resourceToWorkspace := map[string]string{
+		"aws-payments.aws_s3_bucket.tfer--cards": "core",
+		"aws-shared.aws_s3_bucket.tfer--logs":    "core",
+		"aws-shared.aws_s3_bucket.tfer--gdpr":    "core",
+	}
+	divisionToNewResources := DivisionToNewResources{
+		"aws-shared": {
+			"arn:aws:s3:::gdpr": {ResourceType: "aws_s3_bucket", ResourceTerraformerName: "tfer--gdpr", Region: "eu-west-1"},
+			"arn:aws:s3:::logs": {ResourceType: "aws_s3_bucket", ResourceTerraformerName: "tfer--logs", Region: "us-east-1"},
+		},
+	}
+	workspaceToDirectory := map[string]string{
+		"core":     "/core/",
+		"pci-ws":   "/pci/",
+		"eu-ws":    "/eu/",
+		"other-ws": "/other/",
+	}
+
+	// When
+	output, err := c.placeResourcesByComplianceBoundary(resourceToWorkspace, divisionToNewResources, workspaceToDirectory)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"aws-payments.aws_s3_bucket.tfer--cards": "pci-ws",
+		"aws-shared.aws_s3_bucket.tfer--logs":    "core",
+		"aws-shared.aws_s3_bucket.tfer--gdpr":    "eu-ws",
+	}, output)
+}
+
+func TestPlaceResourcesByComplianceBoundary_CoMingled(t *testing.T) {
+	// Given
+	c := TerraformResourcesCalculator{
+		config: Config{
+			ComplianceBoundaries: ComplianceBoundariesDecoder{
+				{Name: "pci", Divisions: []string{"payments"}, Isolated: true},
+			},
+		},
+	}
+	resourceToWorkspace := map[string]string{
+		"aws-payments.aws_s3_bucket.tfer--cards": "core",
+		"aws-shared.aws_s3_bucket.tfer--logs":    "core",
+	}
+
+	// When
+	_, err := c.placeResourcesByComplianceBoundary(resourceToWorkspace, DivisionToNewResources{}, map[string]string{"core": "/core/"})
+
+	// Then
+	assert.True(t, errors.Is(err, ErrComplianceBoundaryCoMingled))
+}
+
+func TestPlaceResourcesByComplianceBoundary_UnknownDirectory(t *testing.T) {
+	// Given
+	c := TerraformResourcesCalculator{
+		config: Config{
+			ComplianceBoundaries: ComplianceBoundariesDecoder{
+				{Name: "pci", Divisions: []string{"payments"}, Directory: "/pci/"},
+			},
+		},
+	}
+	resourceToWorkspace := map[string]string{
+		"aws-payments.aws_s3_bucket.tfer--cards": "core",
+	}
+
+	// When
+	_, err := c.placeResourcesByComplianceBoundary(resourceToWorkspace, DivisionToNewResources{}, map[string]string{"core": "/core/"})
+
+	// Then
+	assert.Error(t, err)
+}
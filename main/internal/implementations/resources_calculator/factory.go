@@ -2,10 +2,13 @@ package resourcesCalculator
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/documentize"
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/nlpengine"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/pyscriptexec"
 )
 
@@ -17,13 +20,14 @@ type Factory struct {
 // environment specification.
 func (f *Factory) Instantiate(
 	ctx context.Context, environment string, dragonDrop interfaces.DragonDrop,
-	divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider,
+	divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider, config Config,
+	artifactStore artifacts.Store,
 ) (interfaces.ResourcesCalculator, error) {
 	switch environment {
 	case "isolated":
 		return new(IsolatedResourcesCalculator), nil
 	default:
-		return f.bootstrappedResourceCalculator(ctx, dragonDrop, divisionToProvider)
+		return f.bootstrappedResourceCalculator(ctx, dragonDrop, divisionToProvider, config, artifactStore)
 	}
 }
 
@@ -31,13 +35,35 @@ func (f *Factory) Instantiate(
 // configuration specified via environment variables.
 func (f *Factory) bootstrappedResourceCalculator(
 	ctx context.Context, dragonDrop interfaces.DragonDrop,
-	divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider,
+	divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider, config Config,
+	artifactStore artifacts.Store,
 ) (interfaces.ResourcesCalculator, error) {
 	doc, _ := documentize.NewDocumentize(divisionToProvider)
 
 	dragonDrop.PostLog(ctx, "Created Documentize client.")
 
-	pyScriptExec := pyscriptexec.NewPyScriptExec()
+	nlpEngine, err := newNLPEngine(config, artifactStore)
+	if err != nil {
+		return nil, fmt.Errorf("[newNLPEngine]%w", err)
+	}
+
+	return NewTerraformResourcesCalculator(&doc, nlpEngine, dragonDrop, config, artifactStore), nil
+}
 
-	return NewTerraformResourcesCalculator(&doc, pyScriptExec, dragonDrop), nil
+// newNLPEngine returns the configured engine for placing new resources within workspaces, defaulting to the
+// native TF-IDF engine.
+func newNLPEngine(config Config, artifactStore artifacts.Store) (nlpengine.NLPEngine, error) {
+	switch config.PlacementEngine {
+	case "", PlacementEngineTFIDF:
+		return nlpengine.NewTFIDFEngine(artifactStore), nil
+	case PlacementEnginePython:
+		return pyscriptexec.NewPyScriptExec(artifactStore), nil
+	case PlacementEngineEmbeddings:
+		if config.PlacementEmbeddings.Endpoint == "" {
+			return nil, fmt.Errorf("[placement engine %v requires an embeddings API endpoint]", config.PlacementEngine)
+		}
+		return nlpengine.NewEmbeddingsEngine(config.PlacementEmbeddings, artifactStore), nil
+	default:
+		return nil, fmt.Errorf("[placement engine %v is not supported]", config.PlacementEngine)
+	}
 }
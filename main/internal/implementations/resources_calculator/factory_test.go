@@ -7,6 +7,7 @@ import (
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
 )
 
@@ -19,7 +20,7 @@ func TestCreateNotIsolated(t *testing.T) {
 	divisionToProvider := make(map[terraformValueObjects.Division]terraformValueObjects.Provider)
 
 	// When
-	calculator, err := resourcesCalculatorFactory.Instantiate(ctx, provider, dragonDrop, divisionToProvider)
+	calculator, err := resourcesCalculatorFactory.Instantiate(ctx, provider, dragonDrop, divisionToProvider, Config{}, artifacts.NewMemoryStore(true))
 
 	// Then
 	assert.Nil(t, err)
@@ -35,9 +36,39 @@ func TestCreateIsolatedResourcesCalculator(t *testing.T) {
 	divisionToProvider := make(map[terraformValueObjects.Division]terraformValueObjects.Provider)
 
 	// When
-	calculator, err := resourcesCalculatorFactory.Instantiate(ctx, provider, dragonDrop, divisionToProvider)
+	calculator, err := resourcesCalculatorFactory.Instantiate(ctx, provider, dragonDrop, divisionToProvider, Config{}, artifacts.NewMemoryStore(true))
 
 	// Then
 	assert.Nil(t, err)
 	assert.NotNil(t, calculator)
 }
+
+func TestCreateUnsupportedPlacementEngine(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	resourcesCalculatorFactory := new(Factory)
+	dragonDrop := new(interfaces.DragonDropMock)
+	divisionToProvider := make(map[terraformValueObjects.Division]terraformValueObjects.Provider)
+
+	// When
+	calculator, err := resourcesCalculatorFactory.Instantiate(ctx, "not_isolated", dragonDrop, divisionToProvider, Config{PlacementEngine: "spacy"}, artifacts.NewMemoryStore(true))
+
+	// Then
+	assert.Error(t, err)
+	assert.Nil(t, calculator)
+}
+
+func TestCreateEmbeddingsPlacementEngineWithoutEndpoint(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	resourcesCalculatorFactory := new(Factory)
+	dragonDrop := new(interfaces.DragonDropMock)
+	divisionToProvider := make(map[terraformValueObjects.Division]terraformValueObjects.Provider)
+
+	// When
+	calculator, err := resourcesCalculatorFactory.Instantiate(ctx, "not_isolated", dragonDrop, divisionToProvider, Config{PlacementEngine: "embeddings"}, artifacts.NewMemoryStore(true))
+
+	// Then
+	assert.Error(t, err)
+	assert.Nil(t, calculator)
+}
@@ -22,3 +22,9 @@ func (c *IsolatedResourcesCalculator) Execute(ctx context.Context, workspaceToDi
 	log.Debug("Executing resource calculator")
 	return nil
 }
+
+// ApplyMinimumResourceAge removes the recently created new resources identified by cloud actor identification.
+func (c *IsolatedResourcesCalculator) ApplyMinimumResourceAge(ctx context.Context) (bool, error) {
+	log.Debug("Applying minimum resource age")
+	return true, nil
+}
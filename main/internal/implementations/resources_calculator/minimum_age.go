@@ -0,0 +1,241 @@
+package resourcesCalculator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/documentize"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/hclcreate"
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+// creationTimeAttributes are the flat state attributes in which providers record the creation time of a resource.
+var creationTimeAttributes = []string{
+	"creation_date",
+	"create_date",
+	"created_date",
+	"creation_time",
+	"create_time",
+	"created_time",
+	"time_created",
+	"creation_timestamp",
+	"created_at",
+	"launch_time",
+}
+
+// creationTimeLayouts are the layouts in which creation times are recorded, from most to least precise. Cloud actor
+// identification only records the date of the creating action.
+var creationTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.000-0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// parseCreationTime parses a creation time recorded in any of the creationTimeLayouts.
+func parseCreationTime(value string) (time.Time, bool) {
+	for _, layout := range creationTimeLayouts {
+		if parsedTime, err := time.Parse(layout, value); err == nil {
+			return parsedTime, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// resourceCreationTime returns the creation time of a resource from its flat state attributes, if recorded.
+func resourceCreationTime(attributesFlat map[string]string) (time.Time, bool) {
+	for _, attribute := range creationTimeAttributes {
+		if value, ok := attributesFlat[attribute]; ok {
+			if creationTime, ok := parseCreationTime(value); ok {
+				return creationTime, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// isRecent returns true if a resource created at creationTime is younger than the minimum resource age.
+func (c *TerraformResourcesCalculator) isRecent(creationTime time.Time, now time.Time) bool {
+	return now.Sub(creationTime) < c.config.MinimumResourceAge
+}
+
+// excludeRecentResources removes the new resources whose state records a creation time more recent than the
+// minimum resource age, such as resources created by CI runs or autoscaling during the scan window. Resources
+// without a recorded creation time are kept, and are checked again after cloud actor identification.
+func (c *TerraformResourcesCalculator) excludeRecentResources(
+	newResources map[terraformValueObjects.Division]map[documentize.ResourceData]bool,
+	divisionToTerraformerState map[terraformValueObjects.Division]driftDetector.TerraformerStateFile,
+	now time.Time,
+) map[terraformValueObjects.Division]map[documentize.ResourceData]bool {
+	if c.config.MinimumResourceAge <= 0 {
+		return newResources
+	}
+
+	filteredResources := map[terraformValueObjects.Division]map[documentize.ResourceData]bool{}
+	excludedCount := 0
+	for division, resources := range newResources {
+		for resource := range resources {
+			attributesFlat := terraformerAttributes(divisionToTerraformerState[division], resource)
+			if creationTime, ok := resourceCreationTime(attributesFlat); ok && c.isRecent(creationTime, now) {
+				excludedCount++
+				continue
+			}
+
+			if _, ok := filteredResources[division]; !ok {
+				filteredResources[division] = map[documentize.ResourceData]bool{}
+			}
+			filteredResources[division][resource] = true
+		}
+	}
+
+	log.Infof("[minimum_resource_age] excluded %v new resources created after %v", excludedCount, now.Add(-c.config.MinimumResourceAge).Format(time.RFC3339))
+	return filteredResources
+}
+
+// cloudActorCreation is the creation action of a resource within mappings/resources-to-cloud-actions.json.
+type cloudActorCreation struct {
+	Creation struct {
+		Actor     string `json:"actor"`
+		Timestamp string `json:"timestamp"`
+	} `json:"creation"`
+}
+
+// ApplyMinimumResourceAge removes the new resources that cloud actor identification found to be created more
+// recently than the minimum resource age from the new resource mappings. Returns false if no new resources remain.
+func (c *TerraformResourcesCalculator) ApplyMinimumResourceAge(ctx context.Context) (bool, error) {
+	return c.applyMinimumResourceAge(time.Now())
+}
+
+// applyMinimumResourceAge removes the recently created new resources, as of now, from the new resource mappings.
+func (c *TerraformResourcesCalculator) applyMinimumResourceAge(now time.Time) (bool, error) {
+	if c.config.MinimumResourceAge <= 0 {
+		return true, nil
+	}
+
+	cloudActionsBytes, err := c.artifactStore.ReadFile("mappings/resources-to-cloud-actions.json")
+	if errors.Is(err, os.ErrNotExist) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("[apply_minimum_resource_age][artifacts.ReadFile resources-to-cloud-actions.json]%w", err)
+	}
+	providerToCloudActions := map[string]map[string]map[string]cloudActorCreation{}
+	err = json.Unmarshal(cloudActionsBytes, &providerToCloudActions)
+	if err != nil {
+		return false, fmt.Errorf("[apply_minimum_resource_age][json.Unmarshal resources-to-cloud-actions.json]%w", err)
+	}
+
+	divisionToNewResourcesBytes, err := c.artifactStore.ReadFile("mappings/division-to-new-resources.json")
+	if err != nil {
+		return false, fmt.Errorf("[apply_minimum_resource_age][artifacts.ReadFile division-to-new-resources.json]%w", err)
+	}
+	divisionToNewResources := DivisionToNewResources{}
+	err = json.Unmarshal(divisionToNewResourcesBytes, &divisionToNewResources)
+	if err != nil {
+		return false, fmt.Errorf("[apply_minimum_resource_age][json.Unmarshal division-to-new-resources.json]%w", err)
+	}
+
+	resourceNames, err := hclcreate.LoadResourceNames(c.artifactStore)
+	if err != nil {
+		return false, fmt.Errorf("[apply_minimum_resource_age][hclcreate.LoadResourceNames]%w", err)
+	}
+
+	recentResources := map[string]bool{}
+	remainingCount := 0
+	for fullDivision, newResources := range divisionToNewResources {
+		provider, division, found := strings.Cut(string(fullDivision), "-")
+		if !found {
+			division = provider
+		}
+
+		for resourceID, resourceData := range newResources {
+			resourceName := resourceNames.Address(string(fullDivision), resourceData.ResourceType+"."+resourceData.ResourceTerraformerName)
+			cloudAction := providerToCloudActions[provider][division][resourceName]
+			if creationTime, ok := parseCreationTime(cloudAction.Creation.Timestamp); ok && c.isRecent(creationTime, now) {
+				recentResources[fmt.Sprintf("%v.%v.%v", fullDivision, resourceData.ResourceType, resourceData.ResourceTerraformerName)] = true
+				delete(newResources, resourceID)
+				continue
+			}
+			remainingCount++
+		}
+		if len(newResources) == 0 {
+			delete(divisionToNewResources, fullDivision)
+		}
+	}
+
+	log.Infof("[minimum_resource_age] excluded %v new resources created after %v", len(recentResources), now.Add(-c.config.MinimumResourceAge).Format(time.RFC3339))
+	if len(recentResources) == 0 {
+		return true, nil
+	}
+
+	divisionToNewResourcesBytes, err = json.MarshalIndent(divisionToNewResources, "", "  ")
+	if err != nil {
+		return false, fmt.Errorf("[apply_minimum_resource_age][json.MarshalIndent]%w", err)
+	}
+	err = rewriteMappingFile(c.artifactStore, "mappings/division-to-new-resources.json", divisionToNewResourcesBytes)
+	if err != nil {
+		return false, fmt.Errorf("[apply_minimum_resource_age]%w", err)
+	}
+
+	for _, path := range []string{"mappings/new-resources-to-documents.json", "mappings/new-resources-to-workspace.json"} {
+		err = removeMappingKeys(c.artifactStore, path, recentResources)
+		if err != nil {
+			return false, fmt.Errorf("[apply_minimum_resource_age]%w", err)
+		}
+	}
+
+	for _, path := range []string{"mappings/new-resources-to-placement-confidence.json", "mappings/needs-manual-placement.json"} {
+		if !c.artifactStore.Exists(path) {
+			continue
+		}
+		err = removeMappingKeys(c.artifactStore, path, recentResources)
+		if err != nil {
+			return false, fmt.Errorf("[apply_minimum_resource_age]%w", err)
+		}
+	}
+
+	return remainingCount > 0, nil
+}
+
+// removeMappingKeys removes the given "<division>.<type>.<name>" keys from a new resource mapping file.
+func removeMappingKeys(store artifacts.Store, path string, keys map[string]bool) error {
+	content, err := store.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("[remove_mapping_keys][artifacts.ReadFile %v]%w", path, err)
+	}
+
+	mapping := map[string]json.RawMessage{}
+	err = json.Unmarshal(content, &mapping)
+	if err != nil {
+		return fmt.Errorf("[remove_mapping_keys][json.Unmarshal %v]%w", path, err)
+	}
+
+	for key := range keys {
+		delete(mapping, key)
+	}
+
+	content, err = json.MarshalIndent(mapping, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[remove_mapping_keys][json.MarshalIndent]%w", err)
+	}
+	return rewriteMappingFile(store, path, content)
+}
+
+// rewriteMappingFile replaces a mapping with new content. The artifact store replaces any read-only copy of the
+// mapping previously spilled to disk.
+func rewriteMappingFile(store artifacts.Store, path string, content []byte) error {
+	err := store.WriteFile(path, content, 0400)
+	if err != nil {
+		return fmt.Errorf("[rewrite_mapping_file][artifacts.WriteFile %v]%w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package resourcesCalculator
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/documentize"
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+func TestResourceCreationTime(t *testing.T) {
+	testCases := []struct {
+		name           string
+		attributesFlat map[string]string
+		want           time.Time
+		ok             bool
+	}{
+		{
+			name:           "aws create date",
+			attributesFlat: map[string]string{"create_date": "2026-10-14T08:30:00Z"},
+			want:           time.Date(2026, 10, 14, 8, 30, 0, 0, time.UTC),
+			ok:             true,
+		},
+		{
+			name:           "gcp creation timestamp",
+			attributesFlat: map[string]string{"creation_timestamp": "2026-10-14T01:30:00.000-07:00"},
+			want:           time.Date(2026, 10, 14, 8, 30, 0, 0, time.UTC),
+			ok:             true,
+		},
+		{
+			name:           "unparseable value",
+			attributesFlat: map[string]string{"created_at": "yesterday"},
+		},
+		{
+			name:           "no creation time",
+			attributesFlat: map[string]string{"id": "bucket"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// When
+			got, ok := resourceCreationTime(testCase.attributesFlat)
+
+			// Then
+			assert.Equal(t, testCase.ok, ok)
+			assert.True(t, testCase.want.Equal(got))
+		})
+	}
+}
+
+func TestExcludeRecentResources(t *testing.T) {
+	// Given
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	c := TerraformResourcesCalculator{config: Config{MinimumResourceAge: 24 * time.Hour}}
+	recent := documentize.NewResourceData("aws_instance", "i-recent", "tfer--i-recent")
+	old := documentize.NewResourceData("aws_instance", "i-old", "tfer--i-old")
+	unknown := documentize.NewResourceData("aws_s3_bucket", "bucket", "tfer--bucket")
+	newResources := map[terraformValueObjects.Division]map[documentize.ResourceData]bool{
+		"aws-111111111111": {recent: true, old: true, unknown: true},
+	}
+	divisionToTerraformerState := map[terraformValueObjects.Division]driftDetector.TerraformerStateFile{
+		"aws-111111111111": {Resources: []*driftDetector.TerraformerResource{
+			{
+				Type: "aws_instance",
+				Instances: []driftDetector.TerraformerInstance{
+					{AttributesFlat: map[string]string{"id": "i-recent", "launch_time": "2026-10-15T09:00:00Z"}},
+					{AttributesFlat: map[string]string{"id": "i-old", "launch_time": "2026-10-01T09:00:00Z"}},
+				},
+			},
+		}},
+	}
+
+	// When
+	output := c.excludeRecentResources(newResources, divisionToTerraformerState, now)
+
+	// Then
+	assert.Equal(t, map[terraformValueObjects.Division]map[documentize.ResourceData]bool{
+		"aws-111111111111": {old: true, unknown: true},
+	}, output)
+
+	// When
+	c.config.MinimumResourceAge = 0
+	output = c.excludeRecentResources(newResources, divisionToTerraformerState, now)
+
+	// Then
+	assert.Equal(t, newResources, output)
+}
+
+func TestApplyMinimumResourceAge(t *testing.T) {
+	// Given
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+	require.NoError(t, os.Mkdir("mappings", 0755))
+
+	writeMapping := func(path string, content interface{}) {
+		contentBytes, err := json.Marshal(content)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, contentBytes, 0400))
+	}
+	writeMapping("mappings/division-to-new-resources.json", DivisionToNewResources{
+		"aws-111111111111": {
+			"i-recent": {ResourceType: "aws_instance", ResourceTerraformerName: "tfer--i-recent", Region: "us-east-1"},
+			"i-old":    {ResourceType: "aws_instance", ResourceTerraformerName: "tfer--i-old", Region: "us-east-1"},
+		},
+	})
+	writeMapping("mappings/new-resources-to-workspace.json", map[string]string{
+		"aws-111111111111.aws_instance.tfer--i-recent": "prod",
+		"aws-111111111111.aws_instance.tfer--i-old":    "prod",
+	})
+	writeMapping("mappings/new-resources-to-documents.json", map[string]string{
+		"aws-111111111111.aws_instance.tfer--i-recent": "instance recent",
+		"aws-111111111111.aws_instance.tfer--i-old":    "instance old",
+	})
+	writeMapping("mappings/resources-to-cloud-actions.json", map[string]interface{}{
+		"aws": map[string]interface{}{
+			"111111111111": map[string]interface{}{
+				"aws_instance.i_recent": map[string]interface{}{"creation": map[string]string{"actor": "ci", "timestamp": "2026-10-15"}},
+				"aws_instance.i_old":    map[string]interface{}{"creation": map[string]string{"actor": "alice", "timestamp": "2026-09-01"}},
+			},
+		},
+	})
+
+	c := TerraformResourcesCalculator{artifactStore: artifacts.NewMemoryStore(true), config: Config{MinimumResourceAge: 48 * time.Hour}}
+
+	// When
+	remain, err := c.applyMinimumResourceAge(time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC))
+
+	// Then
+	require.NoError(t, err)
+	assert.True(t, remain)
+
+	divisionToNewResources := DivisionToNewResources{}
+	content, err := os.ReadFile("mappings/division-to-new-resources.json")
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(content, &divisionToNewResources))
+	assert.Equal(t, DivisionToNewResources{
+		"aws-111111111111": {
+			"i-old": {ResourceType: "aws_instance", ResourceTerraformerName: "tfer--i-old", Region: "us-east-1"},
+		},
+	}, divisionToNewResources)
+
+	for _, path := range []string{"mappings/new-resources-to-workspace.json", "mappings/new-resources-to-documents.json"} {
+		mapping := map[string]string{}
+		content, err = os.ReadFile(path)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(content, &mapping))
+		assert.NotContains(t, mapping, "aws-111111111111.aws_instance.tfer--i-recent")
+		assert.Contains(t, mapping, "aws-111111111111.aws_instance.tfer--i-old")
+	}
+
+	// When
+	remain, err = c.applyMinimumResourceAge(time.Date(2026, 9, 2, 12, 0, 0, 0, time.UTC))
+
+	// Then
+	require.NoError(t, err)
+	assert.False(t, remain)
+}
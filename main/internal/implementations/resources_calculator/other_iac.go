@@ -0,0 +1,228 @@
+package resourcesCalculator
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/documentize"
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+const (
+	// OtherIaCInclude proposes Terraform imports for resources owned by other IaC systems, like any other new resource.
+	OtherIaCInclude = "include"
+
+	// OtherIaCExclude drops resources owned by other IaC systems from the new resources.
+	OtherIaCExclude = "exclude"
+
+	// OtherIaCBucket drops resources owned by other IaC systems from the new resources, and reports them separately.
+	OtherIaCBucket = "bucket"
+)
+
+// otherIaCResourcesPath is the path to which the resources owned by other IaC systems are written in bucket mode.
+const otherIaCResourcesPath = "mappings/other-iac-resources.json"
+
+// OtherIaCResource is a new resource owned by an IaC system or controller other than Terraform.
+type OtherIaCResource struct {
+	Division     string `json:"Division"`
+	ResourceType string `json:"ResourceType"`
+	ResourceName string `json:"ResourceName"`
+	ResourceID   string `json:"ResourceID"`
+
+	// Owner is the owning system, e.g. "CloudFormation", "Pulumi", "EKS" or "GKE".
+	Owner string `json:"Owner"`
+
+	// Detail identifies the owning stack or cluster, when known.
+	Detail string `json:"Detail"`
+}
+
+// otherIaCTagRule identifies the resources owned by another IaC system from the keys of their tags or labels.
+// When the first capture group of keyPattern matches, it is used as the detail, otherwise the tag value is.
+type otherIaCTagRule struct {
+	owner      string
+	keyPattern *regexp.Regexp
+}
+
+// otherIaCNameRule identifies the resources created by a Kubernetes controller from their naming convention.
+type otherIaCNameRule struct {
+	owner         string
+	resourceTypes map[string]bool
+	namePattern   *regexp.Regexp
+}
+
+// otherIaCTagRules are checked in order, so that a CloudFormation stack created by eksctl is attributed to
+// CloudFormation rather than EKS.
+var otherIaCTagRules = []otherIaCTagRule{
+	{owner: "CloudFormation", keyPattern: regexp.MustCompile(`^aws:cloudformation:stack-name$`)},
+	{owner: "Pulumi", keyPattern: regexp.MustCompile(`^pulumi:stack$`)},
+	{owner: "Pulumi", keyPattern: regexp.MustCompile(`^pulumi:project$`)},
+	{owner: "EKS", keyPattern: regexp.MustCompile(`^eks:cluster-name$`)},
+	{owner: "EKS", keyPattern: regexp.MustCompile(`^elbv2\.k8s\.aws/cluster$`)},
+	{owner: "EKS", keyPattern: regexp.MustCompile(`^kubernetes\.io/cluster/(.+)$`)},
+	{owner: "EKS", keyPattern: regexp.MustCompile(`^karpenter\.sh/discovery$`)},
+	{owner: "GKE", keyPattern: regexp.MustCompile(`^goog-k8s-cluster-name$`)},
+	{owner: "GKE", keyPattern: regexp.MustCompile(`^goog-gke-node$`)},
+	{owner: "GKE", keyPattern: regexp.MustCompile(`^goog-gke-volume$`)},
+}
+
+// otherIaCNameRules match the names given by the AWS load balancer controller, EKS managed node groups, and GKE.
+var otherIaCNameRules = []otherIaCNameRule{
+	{
+		owner:         "EKS",
+		resourceTypes: map[string]bool{"aws_lb": true, "aws_alb": true, "aws_lb_target_group": true, "aws_alb_target_group": true},
+		namePattern:   regexp.MustCompile(`^k8s-`),
+	},
+	{
+		owner:         "EKS",
+		resourceTypes: map[string]bool{"aws_autoscaling_group": true, "aws_launch_template": true},
+		namePattern:   regexp.MustCompile(`^eks-`),
+	},
+	{
+		owner: "GKE",
+		resourceTypes: map[string]bool{
+			"google_compute_instance": true, "google_compute_instance_group_manager": true,
+			"google_compute_instance_template": true, "google_compute_firewall": true,
+		},
+		namePattern: regexp.MustCompile(`^gke-`),
+	},
+	{
+		owner: "GKE",
+		resourceTypes: map[string]bool{
+			"google_compute_firewall": true, "google_compute_forwarding_rule": true,
+			"google_compute_health_check": true, "google_compute_backend_service": true,
+		},
+		namePattern: regexp.MustCompile(`^k8s[12]?-`),
+	},
+}
+
+// otherIaCTagAttributes are the flat attribute prefixes under which tags and labels are stored.
+var otherIaCTagAttributes = []string{"tags.", "tags_all.", "labels.", "effective_labels."}
+
+// detectOtherIaCOwner returns the IaC system or controller owning a resource, and the owning stack or cluster,
+// from its flat attributes. Returns false for resources with no known owner.
+func detectOtherIaCOwner(resourceType string, attributesFlat map[string]string) (string, string, bool) {
+	tagKeys := make([]string, 0)
+	tagValues := map[string]string{}
+	for attribute, value := range attributesFlat {
+		for _, prefix := range otherIaCTagAttributes {
+			if strings.HasPrefix(attribute, prefix) && attribute != prefix+"%" {
+				key := strings.TrimPrefix(attribute, prefix)
+				if _, ok := tagValues[key]; !ok {
+					tagKeys = append(tagKeys, key)
+				}
+				tagValues[key] = value
+			}
+		}
+	}
+	sort.Strings(tagKeys)
+
+	for _, rule := range otherIaCTagRules {
+		for _, key := range tagKeys {
+			match := rule.keyPattern.FindStringSubmatch(key)
+			if match == nil {
+				continue
+			}
+			if len(match) > 1 {
+				return rule.owner, match[1], true
+			}
+			return rule.owner, tagValues[key], true
+		}
+	}
+
+	name := attributesFlat["name"]
+	for _, rule := range otherIaCNameRules {
+		if rule.resourceTypes[resourceType] && rule.namePattern.MatchString(name) {
+			return rule.owner, "", true
+		}
+	}
+
+	return "", "", false
+}
+
+// separateOtherIaCResources removes the new resources owned by other IaC systems, returning them separately.
+func separateOtherIaCResources(
+	newResources map[terraformValueObjects.Division]map[documentize.ResourceData]bool,
+	divisionToTerraformerState map[terraformValueObjects.Division]driftDetector.TerraformerStateFile,
+) (map[terraformValueObjects.Division]map[documentize.ResourceData]bool, []OtherIaCResource) {
+	filteredResources := map[terraformValueObjects.Division]map[documentize.ResourceData]bool{}
+	otherIaCResources := make([]OtherIaCResource, 0)
+
+	for division, resources := range newResources {
+		for resource := range resources {
+			attributesFlat := terraformerAttributes(divisionToTerraformerState[division], resource)
+			if owner, detail, ok := detectOtherIaCOwner(resource.Type(), attributesFlat); ok {
+				otherIaCResources = append(otherIaCResources, OtherIaCResource{
+					Division:     string(division),
+					ResourceType: resource.Type(),
+					ResourceName: resource.Name(),
+					ResourceID:   resource.ID(),
+					Owner:        owner,
+					Detail:       detail,
+				})
+				continue
+			}
+
+			if _, ok := filteredResources[division]; !ok {
+				filteredResources[division] = map[documentize.ResourceData]bool{}
+			}
+			filteredResources[division][resource] = true
+		}
+	}
+
+	sort.Slice(otherIaCResources, func(i, j int) bool {
+		a, b := otherIaCResources[i], otherIaCResources[j]
+		return a.Division+"."+a.ResourceType+"."+a.ResourceID < b.Division+"."+b.ResourceType+"."+b.ResourceID
+	})
+	return filteredResources, otherIaCResources
+}
+
+// terraformerAttributes returns the flat attributes of a resource within its division's terraformer state.
+func terraformerAttributes(state driftDetector.TerraformerStateFile, resource documentize.ResourceData) map[string]string {
+	for _, terraformerResource := range state.Resources {
+		if terraformerResource.Type != resource.Type() {
+			continue
+		}
+		for _, instance := range terraformerResource.Instances {
+			if instance.AttributesFlat["id"] == resource.ID() {
+				return instance.AttributesFlat
+			}
+		}
+	}
+	return map[string]string{}
+}
+
+// handleOtherIaCResources applies the configured handling of the new resources owned by other IaC systems.
+func (c *TerraformResourcesCalculator) handleOtherIaCResources(
+	newResources map[terraformValueObjects.Division]map[documentize.ResourceData]bool,
+	divisionToTerraformerState map[terraformValueObjects.Division]driftDetector.TerraformerStateFile,
+) (map[terraformValueObjects.Division]map[documentize.ResourceData]bool, error) {
+	switch c.config.OtherIaCResources {
+	case OtherIaCInclude:
+		return newResources, nil
+	case "", OtherIaCExclude, OtherIaCBucket:
+	default:
+		return nil, fmt.Errorf("[handle_other_iac_resources][unknown handling %v, expected one of include, exclude or bucket]", c.config.OtherIaCResources)
+	}
+
+	filteredResources, otherIaCResources := separateOtherIaCResources(newResources, divisionToTerraformerState)
+	fmt.Printf("Identified %v new resources owned by other IaC systems\n", len(otherIaCResources))
+
+	if c.config.OtherIaCResources == OtherIaCExclude {
+		return filteredResources, nil
+	}
+
+	otherIaCResourcesJSON, err := json.MarshalIndent(otherIaCResources, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("[handle_other_iac_resources][json.MarshalIndent]%w", err)
+	}
+
+	err = c.artifactStore.WriteFile(otherIaCResourcesPath, otherIaCResourcesJSON, 0400)
+	if err != nil {
+		return nil, fmt.Errorf("[handle_other_iac_resources][artifacts.WriteFile %v]%w", otherIaCResourcesPath, err)
+	}
+	return filteredResources, nil
+}
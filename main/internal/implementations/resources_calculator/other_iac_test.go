@@ -0,0 +1,157 @@
+package resourcesCalculator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/documentize"
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+func TestDetectOtherIaCOwner(t *testing.T) {
+	testCases := []struct {
+		name           string
+		resourceType   string
+		attributesFlat map[string]string
+		owner          string
+		detail         string
+		ok             bool
+	}{
+		{
+			name:           "cloudformation stack tag",
+			resourceType:   "aws_s3_bucket",
+			attributesFlat: map[string]string{"tags.%": "2", "tags.aws:cloudformation:stack-name": "network", "tags.Name": "logs"},
+			owner:          "CloudFormation",
+			detail:         "network",
+			ok:             true,
+		},
+		{
+			name:           "cloudformation stack created by eksctl",
+			resourceType:   "aws_vpc",
+			attributesFlat: map[string]string{"tags_all.alpha.eksctl.io/cluster-name": "prod", "tags_all.aws:cloudformation:stack-name": "eksctl-prod-cluster", "tags_all.eks:cluster-name": "prod"},
+			owner:          "CloudFormation",
+			detail:         "eksctl-prod-cluster",
+			ok:             true,
+		},
+		{
+			name:           "pulumi stack tag",
+			resourceType:   "aws_sqs_queue",
+			attributesFlat: map[string]string{"tags.pulumi:stack": "dev"},
+			owner:          "Pulumi",
+			detail:         "dev",
+			ok:             true,
+		},
+		{
+			name:           "kubernetes cluster tag",
+			resourceType:   "aws_security_group",
+			attributesFlat: map[string]string{"tags.kubernetes.io/cluster/prod": "owned"},
+			owner:          "EKS",
+			detail:         "prod",
+			ok:             true,
+		},
+		{
+			name:           "load balancer controller naming",
+			resourceType:   "aws_lb",
+			attributesFlat: map[string]string{"name": "k8s-default-web-0123456789"},
+			owner:          "EKS",
+			ok:             true,
+		},
+		{
+			name:           "gke label",
+			resourceType:   "google_compute_disk",
+			attributesFlat: map[string]string{"labels.goog-k8s-cluster-name": "autopilot"},
+			owner:          "GKE",
+			detail:         "autopilot",
+			ok:             true,
+		},
+		{
+			name:           "gke naming",
+			resourceType:   "google_compute_instance_template",
+			attributesFlat: map[string]string{"name": "gke-autopilot-default-pool-1a2b3c4d"},
+			owner:          "GKE",
+			ok:             true,
+		},
+		{
+			name:           "naming of an unrelated resource type",
+			resourceType:   "google_storage_bucket",
+			attributesFlat: map[string]string{"name": "gke-backups"},
+		},
+		{
+			name:           "terraform owned resource",
+			resourceType:   "aws_s3_bucket",
+			attributesFlat: map[string]string{"tags.%": "1", "tags.Name": "logs"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// When
+			owner, detail, ok := detectOtherIaCOwner(testCase.resourceType, testCase.attributesFlat)
+
+			// Then
+			assert.Equal(t, testCase.ok, ok)
+			assert.Equal(t, testCase.owner, owner)
+			assert.Equal(t, testCase.detail, detail)
+		})
+	}
+}
+
+func TestSeparateOtherIaCResources(t *testing.T) {
+	// Given
+	stackBucket := documentize.NewResourceData("aws_s3_bucket", "stack-bucket", "tfer--stack-bucket")
+	bucket := documentize.NewResourceData("aws_s3_bucket", "bucket", "tfer--bucket")
+	newResources := map[terraformValueObjects.Division]map[documentize.ResourceData]bool{
+		"aws-111111111111": {stackBucket: true, bucket: true},
+	}
+	divisionToTerraformerState := map[terraformValueObjects.Division]driftDetector.TerraformerStateFile{
+		"aws-111111111111": {Resources: []*driftDetector.TerraformerResource{
+			{
+				Type: "aws_s3_bucket",
+				Instances: []driftDetector.TerraformerInstance{
+					{AttributesFlat: map[string]string{"id": "stack-bucket", "tags.aws:cloudformation:stack-name": "storage"}},
+					{AttributesFlat: map[string]string{"id": "bucket"}},
+				},
+			},
+		}},
+	}
+
+	// When
+	filtered, otherIaCResources := separateOtherIaCResources(newResources, divisionToTerraformerState)
+
+	// Then
+	assert.Equal(t, map[terraformValueObjects.Division]map[documentize.ResourceData]bool{
+		"aws-111111111111": {bucket: true},
+	}, filtered)
+	assert.Equal(t, []OtherIaCResource{{
+		Division:     "aws-111111111111",
+		ResourceType: "aws_s3_bucket",
+		ResourceName: "tfer--stack-bucket",
+		ResourceID:   "stack-bucket",
+		Owner:        "CloudFormation",
+		Detail:       "storage",
+	}}, otherIaCResources)
+}
+
+func TestHandleOtherIaCResources_Include(t *testing.T) {
+	// Given
+	c := TerraformResourcesCalculator{config: Config{OtherIaCResources: OtherIaCInclude}}
+	newResources := map[terraformValueObjects.Division]map[documentize.ResourceData]bool{
+		"aws-111111111111": {documentize.ResourceData{}: true},
+	}
+
+	// When
+	output, err := c.handleOtherIaCResources(newResources, nil)
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, newResources, output)
+
+	// When
+	c.config.OtherIaCResources = "unknown"
+	_, err = c.handleOtherIaCResources(newResources, nil)
+
+	// Then
+	assert.Error(t, err)
+}
@@ -0,0 +1,139 @@
+package resourcesCalculator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+)
+
+// ManualPlacement is a new resource whose recommended placement fell below the placement confidence threshold, and
+// which is therefore left for manual placement rather than codified within a workspace.
+type ManualPlacement struct {
+	// SuggestedWorkspace is the workspace recommended by the placement engine.
+	SuggestedWorkspace string `json:"SuggestedWorkspace"`
+
+	// Confidence is the confidence, between 0 and 1, of the recommended placement.
+	Confidence float64 `json:"Confidence"`
+}
+
+// writeRulePlacementConfidence records full confidence in the placements made by ownership tags, placement rules and
+// overrides within mappings/new-resources-to-placement-confidence.json, alongside the confidence of the placement
+// engine's placements.
+func writeRulePlacementConfidence(store artifacts.Store, rulePlacements map[string]string) error {
+	resourceToConfidence := map[string]float64{}
+	content, err := store.ReadFile("mappings/new-resources-to-placement-confidence.json")
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("[write_rule_placement_confidence][artifacts.ReadFile new-resources-to-placement-confidence.json]%w", err)
+	}
+	if err == nil {
+		err = json.Unmarshal(content, &resourceToConfidence)
+		if err != nil {
+			return fmt.Errorf("[write_rule_placement_confidence][json.Unmarshal new-resources-to-placement-confidence.json]%w", err)
+		}
+	}
+
+	for resource := range rulePlacements {
+		resourceToConfidence[resource] = 1
+	}
+
+	content, err = json.MarshalIndent(resourceToConfidence, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[write_rule_placement_confidence][json.MarshalIndent]%w", err)
+	}
+	return rewriteMappingFile(store, "mappings/new-resources-to-placement-confidence.json", content)
+}
+
+// confidenceThreshold returns the placement confidence threshold of the configured placement engine.
+func (c *TerraformResourcesCalculator) confidenceThreshold() float64 {
+	if c.config.PlacementEngine == PlacementEngineEmbeddings {
+		return c.config.PlacementEmbeddingsConfidenceThreshold
+	}
+	return c.config.PlacementConfidenceThreshold
+}
+
+// isLowConfidence returns true if a placement of the passed confidence fits its workspace too poorly to be kept. With
+// a catch-all workspace, placements of resources similar to no workspace at all are low confidence regardless of the
+// threshold.
+func (c *TerraformResourcesCalculator) isLowConfidence(confidence float64) bool {
+	if confidence < c.confidenceThreshold() {
+		return true
+	}
+	return c.config.CatchAllWorkspaceDirectory != "" && confidence == 0
+}
+
+// routeLowConfidencePlacements moves the new resources placed with a confidence below the configured threshold out of
+// mappings/new-resources-to-workspace.json and into mappings/needs-manual-placement.json, or into the catch-all
+// workspace when one is configured. Placements without a confidence, such as those of the python placement engine,
+// are left as is.
+func (c *TerraformResourcesCalculator) routeLowConfidencePlacements(workspaceToDirectory map[string]string) error {
+	if c.confidenceThreshold() <= 0 && c.config.CatchAllWorkspaceDirectory == "" {
+		return nil
+	}
+
+	confidenceBytes, err := c.artifactStore.ReadFile("mappings/new-resources-to-placement-confidence.json")
+	if errors.Is(err, os.ErrNotExist) {
+		fmt.Println("No placement confidence available, skipping the placement confidence threshold")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("[route_low_confidence_placements][artifacts.ReadFile new-resources-to-placement-confidence.json]%w", err)
+	}
+	resourceToConfidence := map[string]float64{}
+	err = json.Unmarshal(confidenceBytes, &resourceToConfidence)
+	if err != nil {
+		return fmt.Errorf("[route_low_confidence_placements][json.Unmarshal new-resources-to-placement-confidence.json]%w", err)
+	}
+
+	resourceToWorkspaceBytes, err := c.artifactStore.ReadFile("mappings/new-resources-to-workspace.json")
+	if err != nil {
+		return fmt.Errorf("[route_low_confidence_placements][artifacts.ReadFile new-resources-to-workspace.json]%w", err)
+	}
+	resourceToWorkspace := map[string]string{}
+	err = json.Unmarshal(resourceToWorkspaceBytes, &resourceToWorkspace)
+	if err != nil {
+		return fmt.Errorf("[route_low_confidence_placements][json.Unmarshal new-resources-to-workspace.json]%w", err)
+	}
+
+	needsManualPlacement := map[string]ManualPlacement{}
+	for resource, workspace := range resourceToWorkspace {
+		confidence, ok := resourceToConfidence[resource]
+		if !ok || !c.isLowConfidence(confidence) {
+			continue
+		}
+
+		needsManualPlacement[resource] = ManualPlacement{SuggestedWorkspace: workspace, Confidence: confidence}
+		delete(resourceToWorkspace, resource)
+	}
+
+	if c.config.CatchAllWorkspaceDirectory != "" {
+		fmt.Printf("Placed %v new resources fitting no workspace within the catch-all workspace\n", len(needsManualPlacement))
+		return c.placeWithinCatchAllWorkspace(resourceToWorkspace, needsManualPlacement, workspaceToDirectory)
+	}
+
+	fmt.Printf("Routed %v new resources placed below a confidence of %v to manual placement\n", len(needsManualPlacement), c.confidenceThreshold())
+	if len(needsManualPlacement) == 0 {
+		return nil
+	}
+
+	resourceToWorkspaceBytes, err = json.MarshalIndent(resourceToWorkspace, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[route_low_confidence_placements][json.MarshalIndent]%w", err)
+	}
+	err = rewriteMappingFile(c.artifactStore, "mappings/new-resources-to-workspace.json", resourceToWorkspaceBytes)
+	if err != nil {
+		return fmt.Errorf("[route_low_confidence_placements]%w", err)
+	}
+
+	needsManualPlacementBytes, err := json.MarshalIndent(needsManualPlacement, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[route_low_confidence_placements][json.MarshalIndent]%w", err)
+	}
+	err = rewriteMappingFile(c.artifactStore, "mappings/needs-manual-placement.json", needsManualPlacementBytes)
+	if err != nil {
+		return fmt.Errorf("[route_low_confidence_placements]%w", err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package resourcesCalculator
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+)
+
+func TestRouteLowConfidencePlacements(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+	require.NoError(t, os.MkdirAll("mappings", 0700))
+
+	require.NoError(t, os.WriteFile("mappings/new-resources-to-workspace.json", []byte(`{
+		"aws-111111111111.aws_subnet.tfer--subnet-1": "networking",
+		"aws-111111111111.aws_iam_role.tfer--role": "networking",
+		"aws-111111111111.aws_sqs_queue.tfer--queue": "payments"
+	}`), 0400))
+	require.NoError(t, os.WriteFile("mappings/new-resources-to-placement-confidence.json", []byte(`{
+		"aws-111111111111.aws_subnet.tfer--subnet-1": 0.82,
+		"aws-111111111111.aws_iam_role.tfer--role": 0.12
+	}`), 0400))
+
+	c := TerraformResourcesCalculator{artifactStore: artifacts.NewMemoryStore(true), config: Config{PlacementConfidenceThreshold: 0.4}}
+
+	// When
+	err := c.routeLowConfidencePlacements(map[string]string{})
+
+	// Then
+	require.NoError(t, err)
+
+	content, err := os.ReadFile("mappings/new-resources-to-workspace.json")
+	require.NoError(t, err)
+	resourceToWorkspace := map[string]string{}
+	require.NoError(t, json.Unmarshal(content, &resourceToWorkspace))
+	assert.Equal(t, map[string]string{
+		"aws-111111111111.aws_subnet.tfer--subnet-1": "networking",
+		"aws-111111111111.aws_sqs_queue.tfer--queue": "payments",
+	}, resourceToWorkspace)
+
+	content, err = os.ReadFile("mappings/needs-manual-placement.json")
+	require.NoError(t, err)
+	needsManualPlacement := map[string]ManualPlacement{}
+	require.NoError(t, json.Unmarshal(content, &needsManualPlacement))
+	assert.Equal(t, map[string]ManualPlacement{
+		"aws-111111111111.aws_iam_role.tfer--role": {SuggestedWorkspace: "networking", Confidence: 0.12},
+	}, needsManualPlacement)
+}
+
+func TestRouteLowConfidencePlacements_Disabled(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	c := TerraformResourcesCalculator{config: Config{}}
+
+	// When
+	err := c.routeLowConfidencePlacements(map[string]string{})
+
+	// Then
+	require.NoError(t, err)
+	_, err = os.Stat("mappings/needs-manual-placement.json")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestConfidenceThreshold(t *testing.T) {
+	// Given
+	config := Config{PlacementConfidenceThreshold: 0.4, PlacementEmbeddingsConfidenceThreshold: 0.75}
+
+	// When
+	tfidf := TerraformResourcesCalculator{config: config}
+	config.PlacementEngine = PlacementEngineEmbeddings
+	embeddings := TerraformResourcesCalculator{config: config}
+
+	// Then
+	assert.Equal(t, 0.4, tfidf.confidenceThreshold())
+	assert.True(t, tfidf.isLowConfidence(0.3))
+	assert.Equal(t, 0.75, embeddings.confidenceThreshold())
+	assert.True(t, embeddings.isLowConfidence(0.6))
+	assert.False(t, embeddings.isLowConfidence(0.8))
+}
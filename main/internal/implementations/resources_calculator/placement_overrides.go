@@ -0,0 +1,398 @@
+package resourcesCalculator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/clonedrepo"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/documentize"
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"gopkg.in/yaml.v3"
+)
+
+// PlacementOverridesPath is the path, relative to the root of the scanned repository, of the placement overrides file.
+const PlacementOverridesPath = ".cloud-concierge/placements.yaml"
+
+// PlacementOverrides are the workspace placements of new resources decided by reviewers, which take precedence over
+// placement rules and the placement engine so that the decisions stick across runs. Placements are captured from the
+// workspaces to which reviewers move generated import blocks, or may be edited by hand.
+type PlacementOverrides struct {
+	// Placements are the overridden placements of individual new resources.
+	Placements []PlacementOverride `yaml:"placements"`
+}
+
+// PlacementOverride places a single new resource within a workspace.
+type PlacementOverride struct {
+	// Division is the cloud division of the resource, e.g. my-aws-account or aws-my-aws-account.
+	Division string `yaml:"division"`
+
+	// Type is the Terraform resource type, e.g. aws_s3_bucket.
+	Type string `yaml:"type"`
+
+	// ID is the cloud id of the resource.
+	ID string `yaml:"id"`
+
+	// Workspace is the workspace within which the resource is placed.
+	Workspace string `yaml:"workspace"`
+}
+
+// placementOverridesPath returns the path of the placement overrides file within the cloned scanned repository.
+func placementOverridesPath() string {
+	return filepath.Join(clonedrepo.Directory, PlacementOverridesPath)
+}
+
+// loadPlacementOverrides reads the placement overrides file at overridesPath, returning no overrides when the file
+// does not exist.
+func loadPlacementOverrides(overridesPath string) (PlacementOverrides, error) {
+	overrides := PlacementOverrides{}
+
+	content, err := os.ReadFile(overridesPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return overrides, nil
+	}
+	if err != nil {
+		return overrides, fmt.Errorf("[load_placement_overrides][os.ReadFile %v]%w", overridesPath, err)
+	}
+
+	err = yaml.Unmarshal(content, &overrides)
+	if err != nil {
+		return overrides, fmt.Errorf("[load_placement_overrides][yaml.Unmarshal %v]%w", overridesPath, err)
+	}
+
+	for index, override := range overrides.Placements {
+		if override.Division == "" || override.Type == "" || override.ID == "" || override.Workspace == "" {
+			return overrides, fmt.Errorf("[load_placement_overrides][placement %d must specify a division, type, id and workspace]", index+1)
+		}
+	}
+	return overrides, nil
+}
+
+// matches returns true if the override is of the passed resource within the passed full division, e.g.
+// aws-my-aws-account.
+func (o PlacementOverride) matches(division terraformValueObjects.Division, resource documentize.ResourceData) bool {
+	if o.Type != resource.Type() || o.ID != resource.ID() {
+		return false
+	}
+
+	shortDivision := string(division)
+	if divisionSlice := strings.SplitN(shortDivision, "-", 2); len(divisionSlice) == 2 {
+		shortDivision = divisionSlice[1]
+	}
+	return o.Division == string(division) || o.Division == shortDivision
+}
+
+// placeResources returns the overridden workspace of each new resource, keyed as within
+// mappings/new-resources-to-workspace.json.
+func (o PlacementOverrides) placeResources(
+	newResources map[terraformValueObjects.Division]map[documentize.ResourceData]bool,
+	workspaceToDirectory map[string]string,
+) (map[string]string, error) {
+	for _, override := range o.Placements {
+		if _, ok := workspaceToDirectory[override.Workspace]; !ok {
+			return nil, fmt.Errorf("[placement override workspace %v is not a configured workspace]", override.Workspace)
+		}
+	}
+
+	placements := map[string]string{}
+	for division, resources := range newResources {
+		for resource := range resources {
+			for _, override := range o.Placements {
+				if override.matches(division, resource) {
+					placements[fmt.Sprintf("%v.%v.%v", division, resource.Type(), resource.Name())] = override.Workspace
+					break
+				}
+			}
+		}
+	}
+	return placements, nil
+}
+
+// updatePlacementOverrides captures the placements of new resources whose import blocks were moved to, or merged
+// within, a workspace of the scanned repository, and prunes the overrides of resources confirmed deleted, within the
+// placement overrides file of the scanned repository.
+func (c *TerraformResourcesCalculator) updatePlacementOverrides(
+	newResources map[terraformValueObjects.Division]map[documentize.ResourceData]bool,
+	workspaceToDirectory map[string]string,
+) error {
+	overrides, err := loadPlacementOverrides(placementOverridesPath())
+	if err != nil {
+		return fmt.Errorf("[update_placement_overrides]%w", err)
+	}
+
+	scanScopes, err := driftDetector.LoadScanScopes(c.artifactStore)
+	if err != nil {
+		return fmt.Errorf("[update_placement_overrides]%w", err)
+	}
+
+	remaining, pruned, err := pruneDeletedResourceOverrides(overrides, scanScopes)
+	if err != nil {
+		return fmt.Errorf("[update_placement_overrides]%w", err)
+	}
+
+	importedPlacements, err := loadImportBlockPlacements(workspaceToDirectory)
+	if err != nil {
+		return fmt.Errorf("[update_placement_overrides]%w", err)
+	}
+
+	captured, err := remaining.capturePlacements(c.artifactStore, newResources, importedPlacements)
+	if err != nil {
+		return fmt.Errorf("[update_placement_overrides]%w", err)
+	}
+
+	if pruned == 0 && captured == 0 {
+		return nil
+	}
+
+	content, err := yaml.Marshal(remaining)
+	if err != nil {
+		return fmt.Errorf("[update_placement_overrides][yaml.Marshal]%w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(placementOverridesPath()), 0700)
+	if err != nil {
+		return fmt.Errorf("[update_placement_overrides][os.MkdirAll]%w", err)
+	}
+
+	err = os.WriteFile(placementOverridesPath(), content, 0600)
+	if err != nil {
+		return fmt.Errorf("[update_placement_overrides][os.WriteFile %v]%w", placementOverridesPath(), err)
+	}
+	fmt.Printf("Captured %v and pruned %v placement overrides\n", captured, pruned)
+	return nil
+}
+
+// pruneDeletedResourceOverrides returns the overrides excluding those of resources confirmed deleted, along with the
+// number of overrides pruned. A resource is confirmed deleted when its type was successfully scanned within its
+// division, but it is absent from the division's scan. Overrides are kept when the scan did not record its extent.
+func pruneDeletedResourceOverrides(overrides PlacementOverrides, scanScopes map[string]driftDetector.ScanScope) (*PlacementOverrides, int, error) {
+	remaining := &PlacementOverrides{Placements: []PlacementOverride{}}
+	divisionToTerraformerState := map[string]driftDetector.TerraformerStateFile{}
+
+	for _, override := range overrides.Placements {
+		deleted, err := override.isDeleted(scanScopes, divisionToTerraformerState)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !deleted {
+			remaining.Placements = append(remaining.Placements, override)
+		}
+	}
+	return remaining, len(overrides.Placements) - len(remaining.Placements), nil
+}
+
+// isDeleted returns true if the overridden resource's type was successfully scanned within its division, but the
+// resource is absent from the division's scan. Terraformer states are loaded into divisionToTerraformerState as needed.
+func (o PlacementOverride) isDeleted(
+	scanScopes map[string]driftDetector.ScanScope,
+	divisionToTerraformerState map[string]driftDetector.TerraformerStateFile,
+) (bool, error) {
+	resource := documentize.NewResourceData(o.Type, o.ID, "")
+	for division, scope := range scanScopes {
+		if !o.matches(terraformValueObjects.Division(division), resource) || !containsString(scope.ResourceTypes, o.Type) {
+			continue
+		}
+
+		state, ok := divisionToTerraformerState[division]
+		if !ok {
+			content, err := os.ReadFile(fmt.Sprintf("current_cloud/%v/terraform.tfstate", division))
+			if err != nil {
+				return false, fmt.Errorf("[is_deleted][os.ReadFile]%w", err)
+			}
+
+			state, err = driftDetector.ParseTerraformerStateFile(content)
+			if err != nil {
+				return false, fmt.Errorf("[is_deleted][driftDetector.ParseTerraformerStateFile]%w", err)
+			}
+			divisionToTerraformerState[division] = state
+		}
+
+		return len(terraformerAttributes(state, resource)) == 0, nil
+	}
+	return false, nil
+}
+
+// importBlockPlacement is the workspace of an import block within the scanned repository.
+type importBlockPlacement struct {
+	// resourceType is the Terraform resource type of the import block's address.
+	resourceType string
+
+	// id is the cloud reference imported.
+	id string
+}
+
+// loadImportBlockPlacements returns the workspace of each import block within the workspace directories of the scanned
+// repository, searching the workspaces in order of name. Directories of nested workspaces are left to those
+// workspaces.
+func loadImportBlockPlacements(workspaceToDirectory map[string]string) (map[importBlockPlacement]string, error) {
+	workspaces := make([]string, 0, len(workspaceToDirectory))
+	workspaceDirectories := map[string]bool{}
+	for workspace, directory := range workspaceToDirectory {
+		workspaces = append(workspaces, workspace)
+		workspaceDirectories[filepath.Join(clonedrepo.Directory, directory)] = true
+	}
+	sort.Strings(workspaces)
+
+	placements := map[importBlockPlacement]string{}
+	for _, workspace := range workspaces {
+		root := filepath.Join(clonedrepo.Directory, workspaceToDirectory[workspace])
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					return nil
+				}
+				return err
+			}
+			if entry.IsDir() {
+				if path != root && (workspaceDirectories[path] || entry.Name() == ".terraform" || entry.Name() == ".git") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if filepath.Ext(path) != ".tf" {
+				return nil
+			}
+
+			filePlacements, err := fileImportBlocks(path)
+			if err != nil {
+				return err
+			}
+			for _, placement := range filePlacements {
+				if _, ok := placements[placement]; !ok {
+					placements[placement] = workspace
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("[load_import_block_placements][filepath.WalkDir %v]%w", root, err)
+		}
+	}
+	return placements, nil
+}
+
+// fileImportBlocks returns the resource type and imported cloud reference of each import block within a .tf file.
+// Import blocks whose address or id are not static are skipped.
+func fileImportBlocks(path string) ([]importBlockPlacement, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("[file_import_blocks][os.ReadFile %v]%w", path, err)
+	}
+
+	file, diags := hclsyntax.ParseConfig(content, path, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("[file_import_blocks][hclsyntax.ParseConfig %v]%v", path, diags.Error())
+	}
+
+	placements := []importBlockPlacement{}
+	for _, block := range file.Body.(*hclsyntax.Body).Blocks {
+		if block.Type != "import" || block.Body.Attributes["to"] == nil || block.Body.Attributes["id"] == nil {
+			continue
+		}
+
+		to, diags := hcl.AbsTraversalForExpr(block.Body.Attributes["to"].Expr)
+		if diags.HasErrors() {
+			continue
+		}
+		names := []string{}
+		for _, step := range to {
+			switch step := step.(type) {
+			case hcl.TraverseRoot:
+				names = append(names, step.Name)
+			case hcl.TraverseAttr:
+				names = append(names, step.Name)
+			}
+		}
+		if len(names) < 2 {
+			continue
+		}
+
+		id, diags := block.Body.Attributes["id"].Expr.Value(nil)
+		if diags.HasErrors() || !id.IsKnown() || id.IsNull() || id.Type() != cty.String {
+			continue
+		}
+
+		placements = append(placements, importBlockPlacement{resourceType: names[len(names)-2], id: id.AsString()})
+	}
+	return placements, nil
+}
+
+// capturePlacements overrides the placement of each new resource with an import block within a workspace of the
+// scanned repository, so that reviewers' moves of generated code stick. Returns the number of overrides captured.
+func (o *PlacementOverrides) capturePlacements(
+	store artifacts.Store,
+	newResources map[terraformValueObjects.Division]map[documentize.ResourceData]bool,
+	importedPlacements map[importBlockPlacement]string,
+) (int, error) {
+	if len(importedPlacements) == 0 {
+		return 0, nil
+	}
+
+	resourceImportsByDivision := map[string]map[string]terraformValueObjects.ImportMigration{}
+	content, err := store.ReadFile("mappings/resources-to-import-location.json")
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("[capture_placements][artifacts.ReadFile resources-to-import-location.json]%w", err)
+	}
+	err = json.Unmarshal(content, &resourceImportsByDivision)
+	if err != nil {
+		return 0, fmt.Errorf("[capture_placements][json.Unmarshal resources-to-import-location.json]%w", err)
+	}
+
+	captured := 0
+	for division, resources := range newResources {
+		for resource := range resources {
+			importMigration, ok := resourceImportsByDivision[string(division)][fmt.Sprintf("%v.%v", resource.Type(), resource.Name())]
+			if !ok {
+				continue
+			}
+			workspace, ok := importedPlacements[importBlockPlacement{
+				resourceType: resource.Type(),
+				id:           string(importMigration.RemoteCloudReference),
+			}]
+			if !ok {
+				continue
+			}
+
+			if o.capturePlacement(division, resource, workspace) {
+				captured++
+			}
+		}
+	}
+	return captured, nil
+}
+
+// capturePlacement overrides the placement of resource within the full division to workspace, returning true if the
+// overrides changed.
+func (o *PlacementOverrides) capturePlacement(division terraformValueObjects.Division, resource documentize.ResourceData, workspace string) bool {
+	for index, override := range o.Placements {
+		if !override.matches(division, resource) {
+			continue
+		}
+		if override.Workspace == workspace {
+			return false
+		}
+		o.Placements[index].Workspace = workspace
+		return true
+	}
+
+	o.Placements = append(o.Placements, PlacementOverride{
+		Division:  string(division),
+		Type:      resource.Type(),
+		ID:        resource.ID(),
+		Workspace: workspace,
+	})
+	return true
+}
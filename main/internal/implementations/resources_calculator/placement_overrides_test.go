@@ -0,0 +1,144 @@
+package resourcesCalculator
+
+import (
+	"os"
+	"testing"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/documentize"
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlacementOverrides_PlaceResources(t *testing.T) {
+	// Given
+	overrides := PlacementOverrides{Placements: []PlacementOverride{
+		{Division: "111111111111", Type: "aws_subnet", ID: "subnet-1", Workspace: "networking"},
+		{Division: "aws-111111111111", Type: "aws_sqs_queue", ID: "queue", Workspace: "payments"},
+		{Division: "222222222222", Type: "aws_s3_bucket", ID: "bucket", Workspace: "payments"},
+	}}
+
+	subnet := documentize.NewResourceData("aws_subnet", "subnet-1", "tfer--subnet-1")
+	queue := documentize.NewResourceData("aws_sqs_queue", "queue", "tfer--queue")
+	bucket := documentize.NewResourceData("aws_s3_bucket", "bucket", "tfer--bucket")
+	newResources := map[terraformValueObjects.Division]map[documentize.ResourceData]bool{
+		"aws-111111111111": {subnet: true, queue: true, bucket: true},
+	}
+	workspaceToDirectory := map[string]string{"networking": "/networking/", "payments": "/payments/"}
+
+	// When
+	placements, err := overrides.placeResources(newResources, workspaceToDirectory)
+	_, unknownWorkspaceErr := overrides.placeResources(newResources, map[string]string{"networking": "/networking/"})
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"aws-111111111111.aws_subnet.tfer--subnet-1": "networking",
+		"aws-111111111111.aws_sqs_queue.tfer--queue": "payments",
+	}, placements)
+	assert.Error(t, unknownWorkspaceErr)
+}
+
+func TestUpdatePlacementOverrides(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	require.NoError(t, os.MkdirAll("repo/.cloud-concierge", 0700))
+	require.NoError(t, os.WriteFile("repo/.cloud-concierge/placements.yaml", []byte(`
+placements:
+  - division: "111111111111"
+    type: aws_subnet
+    id: subnet-1
+    workspace: networking
+  - division: "111111111111"
+    type: aws_vpc
+    id: vpc-deleted
+    workspace: networking
+  - division: "111111111111"
+    type: aws_db_instance
+    id: db-unscanned
+    workspace: data
+`), 0600))
+	require.NoError(t, os.MkdirAll("repo/storage/imports", 0700))
+	require.NoError(t, os.WriteFile("repo/storage/imports/imports.tf", []byte(`
+import {
+  to = aws_subnet.subnet_1
+  id = "subnet-1"
+}
+
+import {
+  to = module.logs.aws_s3_bucket.logs
+  id = "bucket-1"
+}
+`), 0600))
+	require.NoError(t, os.MkdirAll("mappings", 0700))
+	require.NoError(t, os.WriteFile("mappings/division-scan-scopes.json", []byte(`{
+  "aws-111111111111": {"regions": ["us-east-1"], "resourceTypes": ["aws_subnet", "aws_vpc", "aws_s3_bucket"]}
+}`), 0400))
+	require.NoError(t, os.WriteFile("mappings/resources-to-import-location.json", []byte(`{
+  "aws-111111111111": {
+    "aws_subnet.tfer--subnet-1": {"TerraformConfigLocation": "aws_subnet.tfer--subnet-1", "RemoteCloudReference": "subnet-1"},
+    "aws_s3_bucket.tfer--bucket-1": {"TerraformConfigLocation": "aws_s3_bucket.tfer--bucket-1", "RemoteCloudReference": "bucket-1"}
+  }
+}`), 0400))
+	require.NoError(t, os.MkdirAll("current_cloud/aws-111111111111", 0700))
+	require.NoError(t, os.WriteFile("current_cloud/aws-111111111111/terraform.tfstate", []byte(`{
+  "resources": [
+    {"type": "aws_subnet", "name": "tfer--subnet-1", "instances": [{"attributes_flat": {"id": "subnet-1"}}]},
+    {"type": "aws_s3_bucket", "name": "tfer--bucket-1", "instances": [{"attributes_flat": {"id": "bucket-1"}}]}
+  ]
+}`), 0400))
+
+	c := TerraformResourcesCalculator{artifactStore: artifacts.NewMemoryStore(true)}
+	newResources := map[terraformValueObjects.Division]map[documentize.ResourceData]bool{
+		"aws-111111111111": {
+			documentize.NewResourceData("aws_subnet", "subnet-1", "tfer--subnet-1"):    true,
+			documentize.NewResourceData("aws_s3_bucket", "bucket-1", "tfer--bucket-1"): true,
+		},
+	}
+	workspaceToDirectory := map[string]string{"networking": "/networking/", "storage": "/storage/", "data": "/data/"}
+
+	// When
+	err := c.updatePlacementOverrides(newResources, workspaceToDirectory)
+
+	// Then
+	require.NoError(t, err)
+	overrides, err := loadPlacementOverrides("repo/.cloud-concierge/placements.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, PlacementOverrides{Placements: []PlacementOverride{
+		{Division: "111111111111", Type: "aws_subnet", ID: "subnet-1", Workspace: "storage"},
+		{Division: "111111111111", Type: "aws_db_instance", ID: "db-unscanned", Workspace: "data"},
+		{Division: "aws-111111111111", Type: "aws_s3_bucket", ID: "bucket-1", Workspace: "storage"},
+	}}, overrides)
+}
+
+func TestUpdatePlacementOverrides_NoScanScopes(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	overridesContent := []byte(`
+placements:
+  - division: "111111111111"
+    type: aws_vpc
+    id: vpc-1
+    workspace: networking
+`)
+	require.NoError(t, os.MkdirAll("repo/.cloud-concierge", 0700))
+	require.NoError(t, os.WriteFile("repo/.cloud-concierge/placements.yaml", overridesContent, 0600))
+
+	c := TerraformResourcesCalculator{artifactStore: artifacts.NewMemoryStore(true)}
+
+	// When
+	err := c.updatePlacementOverrides(map[terraformValueObjects.Division]map[documentize.ResourceData]bool{}, map[string]string{"networking": "/networking/"})
+
+	// Then
+	require.NoError(t, err)
+	content, err := os.ReadFile("repo/.cloud-concierge/placements.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, overridesContent, content)
+}
@@ -0,0 +1,153 @@
+package resourcesCalculator
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/documentize"
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+	"gopkg.in/yaml.v3"
+)
+
+// PlacementRules are deterministic rules placing new resources within workspaces, which take precedence over the
+// placements recommended by the NLP engine.
+type PlacementRules struct {
+	// Rules are checked in order, and a new resource is placed by the first rule that it matches.
+	Rules []PlacementRule `yaml:"rules"`
+}
+
+// PlacementRule places the new resources matching all of its criteria within a workspace. Each criterion is a list
+// of glob patterns, of which any may match, and is ignored when empty.
+type PlacementRule struct {
+	// Workspace is the workspace within which matching resources are placed.
+	Workspace string `yaml:"workspace"`
+
+	// ResourceTypes are patterns for the Terraform types of matching resources, e.g. "aws_subnet" or "google_sql_*".
+	ResourceTypes []string `yaml:"resource_types"`
+
+	// Tags are tag or label keys mapped onto patterns for their values, all of which matching resources must have.
+	Tags map[string]string `yaml:"tags"`
+
+	// Regions are patterns for the regions of matching resources, e.g. "eu-*".
+	Regions []string `yaml:"regions"`
+
+	// Divisions are patterns for the divisions of matching resources, e.g. an AWS account or GCP project.
+	Divisions []string `yaml:"divisions"`
+
+	// Module is the module call, e.g. module.networking, beneath which already-managed resources matching the rule
+	// are reorganized within the workspace. New resources are placed within the root module.
+	Module string `yaml:"module"`
+}
+
+// loadPlacementRules reads the placement rules file at rulesPath, returning no rules when no file is configured.
+func loadPlacementRules(rulesPath string) (PlacementRules, error) {
+	rules := PlacementRules{}
+	if rulesPath == "" {
+		return rules, nil
+	}
+
+	content, err := os.ReadFile(rulesPath)
+	if err != nil {
+		return rules, fmt.Errorf("[load_placement_rules][os.ReadFile %v]%w", rulesPath, err)
+	}
+
+	err = yaml.Unmarshal(content, &rules)
+	if err != nil {
+		return rules, fmt.Errorf("[load_placement_rules][yaml.Unmarshal %v]%w", rulesPath, err)
+	}
+
+	for index, rule := range rules.Rules {
+		if rule.Workspace == "" {
+			return rules, fmt.Errorf("[load_placement_rules][rule %d does not specify a workspace]", index+1)
+		}
+		if len(rule.ResourceTypes) == 0 && len(rule.Tags) == 0 && len(rule.Regions) == 0 && len(rule.Divisions) == 0 {
+			return rules, fmt.Errorf("[load_placement_rules][rule %d for workspace %v does not specify any criteria]", index+1, rule.Workspace)
+		}
+	}
+	return rules, nil
+}
+
+// placeResources returns the workspace of each new resource matching a placement rule, keyed as within
+// mappings/new-resources-to-workspace.json.
+func (r PlacementRules) placeResources(
+	newResources map[terraformValueObjects.Division]map[documentize.ResourceData]bool,
+	divisionToTerraformerState map[terraformValueObjects.Division]driftDetector.TerraformerStateFile,
+	workspaceToDirectory map[string]string,
+) (map[string]string, error) {
+	for _, rule := range r.Rules {
+		if _, ok := workspaceToDirectory[rule.Workspace]; !ok {
+			return nil, fmt.Errorf("[placement rule workspace %v is not a configured workspace]", rule.Workspace)
+		}
+	}
+
+	placements := map[string]string{}
+	for division, resources := range newResources {
+		shortDivision := string(division)
+		if divisionSlice := strings.SplitN(shortDivision, "-", 2); len(divisionSlice) == 2 {
+			shortDivision = divisionSlice[1]
+		}
+
+		for resource := range resources {
+			attributesFlat := terraformerAttributes(divisionToTerraformerState[division], resource)
+			region, _ := driftDetector.ParseRegionFromTfStateMap(attributesFlat, strings.Split(resource.Type(), "_")[0])
+			tags := resourceTags(attributesFlat)
+
+			for _, rule := range r.Rules {
+				if rule.matches(resource.Type(), tags, region, []string{shortDivision, string(division)}) {
+					placements[fmt.Sprintf("%v.%v.%v", division, resource.Type(), resource.Name())] = rule.Workspace
+					break
+				}
+			}
+		}
+	}
+	return placements, nil
+}
+
+// matches returns true if a resource of the passed type, tags, region and division names matches every criterion
+// of the rule.
+func (r PlacementRule) matches(resourceType string, tags map[string]string, region string, divisions []string) bool {
+	if len(r.ResourceTypes) > 0 && !matchesAnyPattern(r.ResourceTypes, resourceType) {
+		return false
+	}
+	if len(r.Regions) > 0 && !matchesAnyPattern(r.Regions, region) {
+		return false
+	}
+	if len(r.Divisions) > 0 && !matchesAnyPattern(r.Divisions, divisions...) {
+		return false
+	}
+	for key, valuePattern := range r.Tags {
+		value, ok := tags[key]
+		if !ok || !matchesAnyPattern([]string{valuePattern}, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesAnyPattern returns true if any of the values matches any of the glob patterns.
+func matchesAnyPattern(patterns []string, values ...string) bool {
+	for _, pattern := range patterns {
+		for _, value := range values {
+			if matched, _ := path.Match(pattern, value); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// resourceTags returns the tags or labels of a resource from its flat attributes.
+func resourceTags(attributesFlat map[string]string) map[string]string {
+	tags := map[string]string{}
+	for attribute, value := range attributesFlat {
+		for _, prefix := range otherIaCTagAttributes {
+			if strings.HasPrefix(attribute, prefix) && attribute != prefix+"%" {
+				tags[strings.TrimPrefix(attribute, prefix)] = value
+			}
+		}
+	}
+	return tags
+}
@@ -0,0 +1,156 @@
+package resourcesCalculator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/documentize"
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingNLPEngine is an NLP engine that fails if run.
+type failingNLPEngine struct{}
+
+// RunNLPEngine returns an error.
+func (e failingNLPEngine) RunNLPEngine() error {
+	return errors.New("[nlp engine should not run]")
+}
+
+func TestLoadPlacementRules(t *testing.T) {
+	// Given
+	rulesPath := t.TempDir() + "/placement-rules.yaml"
+	require.NoError(t, os.WriteFile(rulesPath, []byte(`
+rules:
+  - workspace: networking
+    resource_types: ["aws_vpc", "aws_subnet"]
+  - workspace: payments
+    tags:
+      team: payments
+`), 0600))
+
+	invalidRulesPath := t.TempDir() + "/invalid-placement-rules.yaml"
+	require.NoError(t, os.WriteFile(invalidRulesPath, []byte(`
+rules:
+  - workspace: networking
+`), 0600))
+
+	// When
+	rules, err := loadPlacementRules(rulesPath)
+	_, invalidErr := loadPlacementRules(invalidRulesPath)
+	noRules, noRulesErr := loadPlacementRules("")
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, PlacementRules{Rules: []PlacementRule{
+		{Workspace: "networking", ResourceTypes: []string{"aws_vpc", "aws_subnet"}},
+		{Workspace: "payments", Tags: map[string]string{"team": "payments"}},
+	}}, rules)
+	assert.Error(t, invalidErr)
+	require.NoError(t, noRulesErr)
+	assert.Empty(t, noRules.Rules)
+}
+
+func TestPlacementRules_PlaceResources(t *testing.T) {
+	// Given
+	rules := PlacementRules{Rules: []PlacementRule{
+		{Workspace: "eu", Regions: []string{"eu-*"}, Divisions: []string{"111111111111"}},
+		{Workspace: "networking", ResourceTypes: []string{"aws_subnet", "aws_vpc"}},
+		{Workspace: "payments", Tags: map[string]string{"team": "pay*"}},
+	}}
+
+	subnet := documentize.NewResourceData("aws_subnet", "subnet-1", "tfer--subnet-1")
+	euSubnet := documentize.NewResourceData("aws_subnet", "subnet-2", "tfer--subnet-2")
+	queue := documentize.NewResourceData("aws_sqs_queue", "queue", "tfer--queue")
+	bucket := documentize.NewResourceData("aws_s3_bucket", "bucket", "tfer--bucket")
+	newResources := map[terraformValueObjects.Division]map[documentize.ResourceData]bool{
+		"aws-111111111111": {subnet: true, euSubnet: true, queue: true, bucket: true},
+	}
+	divisionToTerraformerState := map[terraformValueObjects.Division]driftDetector.TerraformerStateFile{
+		"aws-111111111111": {Resources: []*driftDetector.TerraformerResource{
+			{
+				Type: "aws_subnet",
+				Instances: []driftDetector.TerraformerInstance{
+					{AttributesFlat: map[string]string{"id": "subnet-1", "arn": "arn:aws:ec2:us-east-1:111111111111:subnet/subnet-1"}},
+					{AttributesFlat: map[string]string{"id": "subnet-2", "arn": "arn:aws:ec2:eu-west-1:111111111111:subnet/subnet-2"}},
+				},
+			},
+			{
+				Type:      "aws_sqs_queue",
+				Instances: []driftDetector.TerraformerInstance{{AttributesFlat: map[string]string{"id": "queue", "tags.team": "payments"}}},
+			},
+			{
+				Type:      "aws_s3_bucket",
+				Instances: []driftDetector.TerraformerInstance{{AttributesFlat: map[string]string{"id": "bucket", "tags.team": "data"}}},
+			},
+		}},
+	}
+	workspaceToDirectory := map[string]string{"eu": "/eu/", "networking": "/networking/", "payments": "/payments/"}
+
+	// When
+	placements, err := rules.placeResources(newResources, divisionToTerraformerState, workspaceToDirectory)
+	_, unknownWorkspaceErr := rules.placeResources(newResources, divisionToTerraformerState, map[string]string{"eu": "/eu/"})
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"aws-111111111111.aws_subnet.tfer--subnet-1": "networking",
+		"aws-111111111111.aws_subnet.tfer--subnet-2": "eu",
+		"aws-111111111111.aws_sqs_queue.tfer--queue": "payments",
+	}, placements)
+	assert.Error(t, unknownWorkspaceErr)
+}
+
+func TestGetResourceToWorkspaceMapping_AllResourcesPlacedByRules(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+	require.NoError(t, os.MkdirAll("mappings", 0700))
+
+	require.NoError(t, os.WriteFile("placement-rules.yaml", []byte(`
+rules:
+  - workspace: networking
+    resource_types: ["aws_subnet"]
+`), 0600))
+
+	ctx := context.Background()
+	dragonDrop := new(interfaces.DragonDropMock)
+	c := TerraformResourcesCalculator{
+		artifactStore: artifacts.NewMemoryStore(true),
+		nlpEngine:     failingNLPEngine{},
+		dragonDrop:    dragonDrop,
+		config:        Config{PlacementRulesFile: "placement-rules.yaml"},
+	}
+
+	subnet := documentize.NewResourceData("aws_subnet", "subnet-1", "tfer--subnet-1")
+	newResources := map[terraformValueObjects.Division]map[documentize.ResourceData]bool{
+		"aws-111111111111": {subnet: true},
+	}
+
+	// When
+	err := c.getResourceToWorkspaceMapping(ctx, newResources, map[terraformValueObjects.Division]driftDetector.TerraformerStateFile{}, map[string]string{"networking": "/networking/"})
+
+	// Then
+	require.NoError(t, err)
+	content, err := os.ReadFile("mappings/new-resources-to-workspace.json")
+	require.NoError(t, err)
+
+	resourceToWorkspace := map[string]string{}
+	require.NoError(t, json.Unmarshal(content, &resourceToWorkspace))
+	assert.Equal(t, map[string]string{"aws-111111111111.aws_subnet.tfer--subnet-1": "networking"}, resourceToWorkspace)
+
+	content, err = os.ReadFile("mappings/new-resources-to-placement-confidence.json")
+	require.NoError(t, err)
+
+	resourceToConfidence := map[string]float64{}
+	require.NoError(t, json.Unmarshal(content, &resourceToConfidence))
+	assert.Equal(t, map[string]float64{"aws-111111111111.aws_subnet.tfer--subnet-1": 1}, resourceToConfidence)
+}
@@ -0,0 +1,64 @@
+package resourcesCalculator
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/hclcreate"
+)
+
+// writeResourceNames names each placed new resource within the generated code, avoiding the names of other new
+// resources and of the resources declared within its workspace, and writes the names to
+// mappings/new-resources-to-names.json, on which the cloud actors, costs and inventory of new resources are keyed.
+func (c *TerraformResourcesCalculator) writeResourceNames(workspaceToDirectory map[string]string) error {
+	newResourceToWorkspace := hclcreate.NewResourceToWorkspace{}
+	err := readMappingFile(c.artifactStore, "mappings/new-resources-to-workspace.json", &newResourceToWorkspace)
+	if err != nil {
+		return fmt.Errorf("[write_resource_names]%w", err)
+	}
+
+	allWorkspaceToDirectory := map[string]string{}
+	for workspace, directory := range workspaceToDirectory {
+		allWorkspaceToDirectory[workspace] = directory
+	}
+	for _, path := range []string{"mappings/new-workspaces.json", "mappings/unconfigured-workspaces.json"} {
+		if !c.artifactStore.Exists(path) {
+			continue
+		}
+
+		placedWorkspaceToDirectory := map[string]string{}
+		err = readMappingFile(c.artifactStore, path, &placedWorkspaceToDirectory)
+		if err != nil {
+			return fmt.Errorf("[write_resource_names]%w", err)
+		}
+		for workspace, directory := range placedWorkspaceToDirectory {
+			allWorkspaceToDirectory[workspace] = directory
+		}
+	}
+
+	resourceNames, err := hclcreate.NewResourceNames(newResourceToWorkspace, allWorkspaceToDirectory)
+	if err != nil {
+		return fmt.Errorf("[write_resource_names][hclcreate.NewResourceNames]%w", err)
+	}
+
+	err = hclcreate.WriteResourceNames(c.artifactStore, resourceNames)
+	if err != nil {
+		return fmt.Errorf("[write_resource_names][hclcreate.WriteResourceNames]%w", err)
+	}
+	return nil
+}
+
+// readMappingFile reads and unmarshals the mapping file at path into value.
+func readMappingFile(store artifacts.Store, path string, value interface{}) error {
+	content, err := store.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("[artifacts.ReadFile %v]%w", path, err)
+	}
+
+	err = json.Unmarshal(content, value)
+	if err != nil {
+		return fmt.Errorf("[json.Unmarshal %v]%w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package resourcesCalculator
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/hclcreate"
+)
+
+func TestWriteResourceNames(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	require.NoError(t, os.MkdirAll("mappings", 0700))
+	require.NoError(t, os.MkdirAll("repo/unmanaged", 0700))
+	require.NoError(t, os.WriteFile("repo/unmanaged/new-resources.tf", []byte(`resource "aws_iam_role" "ci" {}
+`), 0400))
+	require.NoError(t, os.WriteFile("mappings/new-resources-to-workspace.json", []byte(`{
+		"aws-prod.aws_iam_role.tfer--ci": "unmanaged",
+		"aws-prod.aws_s3_bucket.tfer--logs": "networking"
+	}`), 0400))
+	require.NoError(t, os.WriteFile("mappings/unconfigured-workspaces.json", []byte(`{"unmanaged": "/unmanaged/"}`), 0400))
+
+	c := TerraformResourcesCalculator{artifactStore: artifacts.NewMemoryStore(true)}
+
+	// When
+	err := c.writeResourceNames(map[string]string{"networking": "/networking/"})
+
+	// Then
+	require.NoError(t, err)
+	resourceNames, err := hclcreate.LoadResourceNames(c.artifactStore)
+	require.NoError(t, err)
+	assert.Equal(t, hclcreate.ResourceNames{
+		"aws-prod.aws_iam_role.tfer--ci":    "ci_2",
+		"aws-prod.aws_s3_bucket.tfer--logs": "logs",
+	}, resourceNames)
+}
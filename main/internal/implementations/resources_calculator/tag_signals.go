@@ -0,0 +1,174 @@
+package resourcesCalculator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/documentize"
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+// stateTagAttributes are the attributes in which providers record the tags or labels of a resource within a
+// Terraform state file.
+var stateTagAttributes = []string{"tags", "tags_all", "labels", "effective_labels"}
+
+// tagSignals places new resources within workspaces by their ownership tags, e.g. team, service or env, which are
+// a more reliable signal of ownership than the similarity of resource documents.
+type tagSignals struct {
+	// keys are the ownership tag keys, in order of precedence.
+	keys []string
+
+	// mappedWorkspaces are the configured workspaces of "key=value" tags.
+	mappedWorkspaces map[string]string
+
+	// learnedWorkspaces are the workspaces of "key=value" ownership tags found on the managed resources of a single
+	// workspace only.
+	learnedWorkspaces map[string]string
+}
+
+// loadTagSignals returns the tag signals of the configured ownership tag keys, learning the workspace of ownership
+// tags from the state file of each workspace.
+func (c *TerraformResourcesCalculator) loadTagSignals(workspaceToDirectory map[string]string) (tagSignals, error) {
+	signals := tagSignals{keys: c.config.PlacementTagKeys, mappedWorkspaces: map[string]string{}, learnedWorkspaces: map[string]string{}}
+
+	for tag, workspace := range c.config.PlacementTagWorkspaces {
+		if !strings.Contains(tag, "=") {
+			return signals, fmt.Errorf("[load_tag_signals][tag %v mapped to workspace %v is not of the form key=value]", tag, workspace)
+		}
+		if _, ok := workspaceToDirectory[workspace]; !ok {
+			return signals, fmt.Errorf("[load_tag_signals][tag %v is mapped to %v, which is not a configured workspace]", tag, workspace)
+		}
+		signals.mappedWorkspaces[tag] = workspace
+	}
+
+	if len(signals.keys) == 0 {
+		return signals, nil
+	}
+
+	tagToWorkspaces := map[string]map[string]bool{}
+	for workspace := range workspaceToDirectory {
+		tags, err := workspaceOwnershipTags(workspace, signals.keys)
+		if err != nil {
+			return signals, fmt.Errorf("[load_tag_signals]%w", err)
+		}
+		for tag := range tags {
+			if _, ok := tagToWorkspaces[tag]; !ok {
+				tagToWorkspaces[tag] = map[string]bool{}
+			}
+			tagToWorkspaces[tag][workspace] = true
+		}
+	}
+
+	for tag, workspaces := range tagToWorkspaces {
+		if len(workspaces) != 1 {
+			continue
+		}
+		for workspace := range workspaces {
+			signals.learnedWorkspaces[tag] = workspace
+		}
+	}
+	return signals, nil
+}
+
+// workspaceOwnershipTags returns the "key=value" ownership tags of the managed resources within a workspace's
+// state file.
+func workspaceOwnershipTags(workspace string, keys []string) (map[string]bool, error) {
+	tags := map[string]bool{}
+
+	content, err := os.ReadFile(fmt.Sprintf("state_files/%v.json", workspace))
+	if errors.Is(err, os.ErrNotExist) {
+		return tags, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[workspace_ownership_tags][os.ReadFile state_files/%v.json]%w", workspace, err)
+	}
+
+	state := driftDetector.TerraformStateFile{}
+	err = json.Unmarshal(content, &state)
+	if err != nil {
+		return nil, fmt.Errorf("[workspace_ownership_tags][json.Unmarshal state_files/%v.json]%w", workspace, err)
+	}
+
+	for _, resource := range state.Resources {
+		if resource.Mode != "" && resource.Mode != "managed" {
+			continue
+		}
+		for _, instance := range resource.Instances {
+			for _, attribute := range stateTagAttributes {
+				attributeTags, ok := instance.Attributes[attribute].(map[string]interface{})
+				if !ok {
+					continue
+				}
+				for _, key := range keys {
+					if value, ok := attributeTags[key].(string); ok && value != "" {
+						tags[key+"="+value] = true
+					}
+				}
+			}
+		}
+	}
+	return tags, nil
+}
+
+// placeResources returns the workspace signalled by the ownership tags of each new resource, keyed as within
+// mappings/new-resources-to-workspace.json. Configured tag mappings take precedence over learned ones, and tag keys
+// are considered in order of precedence.
+func (s tagSignals) placeResources(
+	newResources map[terraformValueObjects.Division]map[documentize.ResourceData]bool,
+	divisionToTerraformerState map[terraformValueObjects.Division]driftDetector.TerraformerStateFile,
+) map[string]string {
+	keys := s.precedenceKeys()
+
+	placements := map[string]string{}
+	for division, resources := range newResources {
+		for resource := range resources {
+			tags := resourceTags(terraformerAttributes(divisionToTerraformerState[division], resource))
+			if workspace, ok := s.workspaceOfTags(tags, keys); ok {
+				placements[fmt.Sprintf("%v.%v.%v", division, resource.Type(), resource.Name())] = workspace
+			}
+		}
+	}
+	return placements
+}
+
+// precedenceKeys returns the ownership tag keys in order of precedence, followed by the other keys of configured tag
+// mappings in sorted order.
+func (s tagSignals) precedenceKeys() []string {
+	mappedKeys := []string{}
+	for tag := range s.mappedWorkspaces {
+		key, _, _ := strings.Cut(tag, "=")
+		if !containsString(s.keys, key) && !containsString(mappedKeys, key) {
+			mappedKeys = append(mappedKeys, key)
+		}
+	}
+	sort.Strings(mappedKeys)
+	return append(append([]string{}, s.keys...), mappedKeys...)
+}
+
+// workspaceOfTags returns the workspace signalled by the tags, considering the keys in order.
+func (s tagSignals) workspaceOfTags(tags map[string]string, keys []string) (string, bool) {
+	for _, key := range keys {
+		value, ok := tags[key]
+		if !ok {
+			continue
+		}
+		if workspace, ok := s.mappedWorkspaces[key+"="+value]; ok {
+			return workspace, true
+		}
+	}
+	for _, key := range s.keys {
+		value, ok := tags[key]
+		if !ok {
+			continue
+		}
+		if workspace, ok := s.learnedWorkspaces[key+"="+value]; ok {
+			return workspace, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,81 @@
+package resourcesCalculator
+
+import (
+	"os"
+	"testing"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/documentize"
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagSignals_PlaceResources(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	require.NoError(t, os.MkdirAll("state_files", 0700))
+	require.NoError(t, os.WriteFile("state_files/payments.json", []byte(`{"resources": [
+		{"mode": "managed", "type": "aws_sqs_queue", "name": "orders", "instances": [
+			{"attributes": {"id": "orders", "tags": {"team": "payments", "env": "prod"}}}
+		]}
+	]}`), 0600))
+	require.NoError(t, os.WriteFile("state_files/storefront.json", []byte(`{"resources": [
+		{"mode": "managed", "type": "aws_instance", "name": "web", "instances": [
+			{"attributes": {"id": "i-123", "tags_all": {"team": "storefront", "env": "prod"}}}
+		]}
+	]}`), 0600))
+
+	c := TerraformResourcesCalculator{config: Config{
+		PlacementTagKeys:       []string{"team", "env"},
+		PlacementTagWorkspaces: map[string]string{"service=checkout": "storefront"},
+	}}
+	workspaceToDirectory := map[string]string{"payments": "/payments/", "storefront": "/storefront/"}
+
+	dlq := documentize.NewResourceData("aws_sqs_queue", "dlq", "tfer--dlq")
+	checkout := documentize.NewResourceData("aws_sqs_queue", "checkout", "tfer--checkout")
+	bucket := documentize.NewResourceData("aws_s3_bucket", "bucket", "tfer--bucket")
+	newResources := map[terraformValueObjects.Division]map[documentize.ResourceData]bool{
+		"aws-111111111111": {dlq: true, checkout: true, bucket: true},
+	}
+	divisionToTerraformerState := map[terraformValueObjects.Division]driftDetector.TerraformerStateFile{
+		"aws-111111111111": {Resources: []*driftDetector.TerraformerResource{
+			{
+				Type: "aws_sqs_queue",
+				Instances: []driftDetector.TerraformerInstance{
+					{AttributesFlat: map[string]string{"id": "dlq", "tags.team": "payments", "tags.env": "prod"}},
+					{AttributesFlat: map[string]string{"id": "checkout", "tags.team": "payments", "tags.service": "checkout"}},
+				},
+			},
+			{
+				Type:      "aws_s3_bucket",
+				Instances: []driftDetector.TerraformerInstance{{AttributesFlat: map[string]string{"id": "bucket", "tags.env": "prod"}}},
+			},
+		}},
+	}
+
+	// When
+	signals, err := c.loadTagSignals(workspaceToDirectory)
+	require.NoError(t, err)
+	placements := signals.placeResources(newResources, divisionToTerraformerState)
+
+	// Then
+	assert.Equal(t, map[string]string{
+		"aws-111111111111.aws_sqs_queue.tfer--dlq":      "payments",
+		"aws-111111111111.aws_sqs_queue.tfer--checkout": "storefront",
+	}, placements)
+}
+
+func TestLoadTagSignals_UnknownWorkspace(t *testing.T) {
+	// Given
+	c := TerraformResourcesCalculator{config: Config{PlacementTagWorkspaces: map[string]string{"team=data": "data"}}}
+
+	// When
+	_, err := c.loadTagSignals(map[string]string{"payments": "/payments/"})
+
+	// Then
+	assert.Error(t, err)
+}
@@ -7,28 +7,111 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/Jeffail/gabs/v2"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/baseline"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/documentize"
 	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
-	"github.com/dragondrop-cloud/cloud-concierge/main/internal/pyscriptexec"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/nlpengine"
 )
 
 var ErrNoNewResources = errors.New("[no new resources identified]")
 
+const (
+	// PlacementEngineTFIDF places new resources natively, by the TF-IDF similarity of their documents with those
+	// of each workspace.
+	PlacementEngineTFIDF = "tfidf"
+
+	// PlacementEnginePython places new resources with the python_scripts/nlpengine text classification model.
+	PlacementEnginePython = "python"
+
+	// PlacementEngineEmbeddings places new resources by the similarity of embeddings of their documents, requested
+	// from an external embeddings API, with those of each workspace, falling back to TF-IDF when the API is
+	// unavailable.
+	PlacementEngineEmbeddings = "embeddings"
+)
+
+// Config is a struct containing the variables that determine the specific behavior of the
+// TerraformResourcesCalculator.
+type Config struct {
+	// ComplianceBoundaries are the compliance boundaries by which new resources are routed to dedicated
+	// workspaces.
+	ComplianceBoundaries ComplianceBoundariesDecoder
+
+	// ManagedDriftOnlyDivisions are the divisions whose new resources are not codified.
+	ManagedDriftOnlyDivisions []string
+
+	// ResourceIDMappings are user-provided expressions for the unique id of resource types that the built-in
+	// calculation does not cover.
+	ResourceIDMappings driftDetector.ResourceIDMappings
+
+	// OtherIaCResources is the handling of new resources owned by other IaC systems such as CloudFormation, Pulumi,
+	// or the EKS and GKE controllers, one of "include", "exclude" or "bucket".
+	OtherIaCResources string
+
+	// DefaultResourceExclusions are rules for cloud default resources, in addition to the built-in rules, that are
+	// not proposed for import. Rules prefixed by ! override the built-in rules.
+	DefaultResourceExclusions []string
+
+	// PlacementEngine is the engine placing new resources within workspaces, one of "tfidf", "embeddings" or
+	// "python".
+	PlacementEngine string
+
+	// PlacementEmbeddings is the configuration of the embeddings API used by the "embeddings" placement engine.
+	PlacementEmbeddings nlpengine.EmbeddingsConfig
+
+	// PlacementRulesFile is the path of a yaml file of deterministic rules placing new resources within workspaces by
+	// resource type, tag, region and division, which take precedence over the placement engine.
+	PlacementRulesFile string
+
+	// PlacementTagKeys are the keys of ownership tags, e.g. team, service and env, in order of precedence. A new resource
+	// is placed within the single workspace whose managed resources carry the same value of an ownership tag.
+	PlacementTagKeys []string
+
+	// PlacementTagWorkspaces maps "key=value" tags onto the workspaces within which new resources carrying them are
+	// placed, taking precedence over the workspaces learned from ownership tags.
+	PlacementTagWorkspaces map[string]string
+
+	// PlacementConfidenceThreshold is the minimum confidence of a placement by the tfidf engine, below which a new
+	// resource is listed within mappings/needs-manual-placement.json rather than placed within a workspace. Zero
+	// disables the threshold.
+	PlacementConfidenceThreshold float64
+
+	// PlacementEmbeddingsConfidenceThreshold is the minimum confidence of a placement by the embeddings engine, whose
+	// similarities are not comparable with those of the tfidf engine. Zero disables the threshold.
+	PlacementEmbeddingsConfidenceThreshold float64
+
+	// CatchAllWorkspaceDirectory is the directory of a catch-all workspace, e.g. "/unmanaged/", within which new
+	// resources fitting no workspace are placed rather than listed for manual placement. A directory that is not an
+	// existing workspace is proposed as a new workspace.
+	CatchAllWorkspaceDirectory string
+
+	// MinimumResourceAge is the minimum age of a new resource before it is proposed for import, which leaves out
+	// short-lived resources created by CI and autoscaling. Zero disables the filter.
+	MinimumResourceAge time.Duration
+}
+
 // TerraformResourcesCalculator is a struct that implements the interfaces.ResourcesCalculator interface for
 // running within a "live" dragondrop job.
 type TerraformResourcesCalculator struct {
 	// documentize implements the Document
 	documentize *documentize.Documentize
 
-	// pyScriptExec is an implementation of the PyScriptExec python scripts
-	pyScriptExec pyscriptexec.PyScriptExec
+	// nlpEngine places new resources within the workspace whose resources they most resemble.
+	nlpEngine nlpengine.NLPEngine
 
 	// dragonDrop interface implementation for sending requests to the dragondrop API.
 	dragonDrop interfaces.DragonDrop
+
+	// config contains the variables that determine the specific behavior of the TerraformResourcesCalculator.
+	config Config
+
+	// artifactStore holds the mappings read and written by the TerraformResourcesCalculator.
+	artifactStore artifacts.Store
 }
 
 // ResourceID is a string that represents a resource id for a cloud resource within a terraform state file.
@@ -46,13 +129,18 @@ type NewResourceData struct {
 }
 
 // NewTerraformResourcesCalculator creates and returns an instance of the TerraformResourcesCalculator.
-func NewTerraformResourcesCalculator(documentize *documentize.Documentize, pyScriptExec pyscriptexec.PyScriptExec, dragonDrop interfaces.DragonDrop) interfaces.ResourcesCalculator {
-	return &TerraformResourcesCalculator{documentize: documentize, pyScriptExec: pyScriptExec, dragonDrop: dragonDrop}
+func NewTerraformResourcesCalculator(documentize *documentize.Documentize, nlpEngine nlpengine.NLPEngine, dragonDrop interfaces.DragonDrop, config Config, artifactStore artifacts.Store) interfaces.ResourcesCalculator {
+	return &TerraformResourcesCalculator{documentize: documentize, nlpEngine: nlpEngine, dragonDrop: dragonDrop, config: config, artifactStore: artifactStore}
 }
 
 // Execute calculates the association between resources and a state file.
 func (c *TerraformResourcesCalculator) Execute(ctx context.Context, workspaceToDirectory map[string]string) error {
-	_, err := c.calculateResourceToWorkspaceMapping(ctx, *c.documentize, workspaceToDirectory)
+	err := c.identifyWorkspaceMoves(workspaceToDirectory)
+	if err != nil {
+		return fmt.Errorf("[resources_calculator][error identifying workspace moves]%w", err)
+	}
+
+	_, err = c.calculateResourceToWorkspaceMapping(ctx, *c.documentize, workspaceToDirectory)
 	if err != nil {
 		if errors.Unwrap(err) == ErrNoNewResources {
 			err := c.dragonDrop.InformNoResourcesFound(ctx)
@@ -80,6 +168,35 @@ func (c *TerraformResourcesCalculator) calculateResourceToWorkspaceMapping(ctx c
 		return message, err
 	}
 
+	err = c.updatePlacementOverrides(newResources, workspaceToDirectory)
+	if err != nil {
+		return message, fmt.Errorf("[calculate_resource_to_workspace_mapping][error updating placement overrides]%w", err)
+	}
+	newResources = c.excludeManagedDriftOnlyDivisions(newResources)
+
+	divisionToTerraformerState, err := loadNewResourcesTerraformerStates(newResources)
+	if err != nil {
+		return message, fmt.Errorf("[calculate_resource_to_workspace_mapping]%w", err)
+	}
+
+	newResources, err = c.excludeDefaultResources(newResources, divisionToTerraformerState)
+	if err != nil {
+		return message, fmt.Errorf("[calculate_resource_to_workspace_mapping][error excluding default resources]%w", err)
+	}
+
+	newResources = c.excludeRecentResources(newResources, divisionToTerraformerState, time.Now())
+
+	newResources, err = c.handleOtherIaCResources(newResources, divisionToTerraformerState)
+	if err != nil {
+		return message, fmt.Errorf("[calculate_resource_to_workspace_mapping][error handling other IaC resources]%w", err)
+	}
+
+	accepted, err := baseline.LoadFromRepository()
+	if err != nil {
+		return message, fmt.Errorf("[calculate_resource_to_workspace_mapping][error loading baseline]%w", err)
+	}
+	newResources = c.excludeAcceptedResources(newResources, accepted)
+
 	if len(newResources) == 0 {
 		fmt.Println("No new resources identified")
 		return "no new resources", fmt.Errorf("[calculate_resource_to_workspace][error identifying new resources]%w", ErrNoNewResources)
@@ -90,21 +207,215 @@ func (c *TerraformResourcesCalculator) calculateResourceToWorkspaceMapping(ctx c
 		return message, err
 	}
 
-	err = c.getResourceToWorkspaceMapping(ctx)
+	err = c.getResourceToWorkspaceMapping(ctx, newResources, divisionToTerraformerState, workspaceToDirectory)
 	if err != nil {
 		return message, err
 	}
 
+	err = c.routeLowConfidencePlacements(workspaceToDirectory)
+	if err != nil {
+		return message, fmt.Errorf("[calculate_resource_to_workspace_mapping][error routing low confidence placements]%w", err)
+	}
+
+	err = c.applyComplianceBoundaries(workspaceToDirectory)
+	if err != nil {
+		return message, fmt.Errorf("[calculate_resource_to_workspace_mapping][error applying compliance boundaries]%w", err)
+	}
+
+	err = c.writeResourceNames(workspaceToDirectory)
+	if err != nil {
+		return message, fmt.Errorf("[calculate_resource_to_workspace_mapping][error naming new resources]%w", err)
+	}
+
 	return "", nil
 }
 
-// getResourceToWorkspaceMapping runs the NLPEngine python script to produce a mapping of new resources to suggested workspace.
-func (c *TerraformResourcesCalculator) getResourceToWorkspaceMapping(ctx context.Context) error {
+// excludeManagedDriftOnlyDivisions removes the new resources of divisions configured for managed drift only scanning.
+func (c *TerraformResourcesCalculator) excludeManagedDriftOnlyDivisions(
+	newResources map[terraformValueObjects.Division]map[documentize.ResourceData]bool,
+) map[terraformValueObjects.Division]map[documentize.ResourceData]bool {
+	if len(c.config.ManagedDriftOnlyDivisions) == 0 {
+		return newResources
+	}
+
+	filteredResources := map[terraformValueObjects.Division]map[documentize.ResourceData]bool{}
+	for fullDivision, resources := range newResources {
+		division := string(fullDivision)
+		if divisionSlice := strings.SplitN(division, "-", 2); len(divisionSlice) == 2 {
+			division = divisionSlice[1]
+		}
+
+		if containsString(c.config.ManagedDriftOnlyDivisions, division) {
+			continue
+		}
+		filteredResources[fullDivision] = resources
+	}
+
+	return filteredResources
+}
+
+// excludeAcceptedResources removes the new resources accepted as unmanaged within the repository's baseline.
+func (c *TerraformResourcesCalculator) excludeAcceptedResources(
+	newResources map[terraformValueObjects.Division]map[documentize.ResourceData]bool,
+	accepted *baseline.Baseline,
+) map[terraformValueObjects.Division]map[documentize.ResourceData]bool {
+	if accepted.IsEmpty() {
+		return newResources
+	}
+
+	filteredResources := map[terraformValueObjects.Division]map[documentize.ResourceData]bool{}
+	for division, resources := range newResources {
+		for resource := range resources {
+			if accepted.AcceptsUnmanaged(string(division), resource.Type(), resource.ID()) {
+				continue
+			}
+
+			if _, ok := filteredResources[division]; !ok {
+				filteredResources[division] = map[documentize.ResourceData]bool{}
+			}
+			filteredResources[division][resource] = true
+		}
+	}
+
+	return filteredResources
+}
+
+// excludeDefaultResources removes the new resources matched by the default resource policy, such as default VPCs
+// and service-linked roles.
+func (c *TerraformResourcesCalculator) excludeDefaultResources(
+	newResources map[terraformValueObjects.Division]map[documentize.ResourceData]bool,
+	divisionToTerraformerState map[terraformValueObjects.Division]driftDetector.TerraformerStateFile,
+) (map[terraformValueObjects.Division]map[documentize.ResourceData]bool, error) {
+	policy, err := driftDetector.ParseDefaultResourcePolicy(c.config.DefaultResourceExclusions)
+	if err != nil {
+		return nil, fmt.Errorf("[exclude_default_resources]%w", err)
+	}
+
+	filteredResources := map[terraformValueObjects.Division]map[documentize.ResourceData]bool{}
+	for division, resources := range newResources {
+		for resource := range resources {
+			attributesFlat := terraformerAttributes(divisionToTerraformerState[division], resource)
+			if policy.IsDefaultResource(resource.Type(), attributesFlat) {
+				continue
+			}
+
+			if _, ok := filteredResources[division]; !ok {
+				filteredResources[division] = map[documentize.ResourceData]bool{}
+			}
+			filteredResources[division][resource] = true
+		}
+	}
+
+	return filteredResources, nil
+}
+
+// loadNewResourcesTerraformerStates loads the terraformer state of each division with new resources.
+func loadNewResourcesTerraformerStates(
+	newResources map[terraformValueObjects.Division]map[documentize.ResourceData]bool,
+) (map[terraformValueObjects.Division]driftDetector.TerraformerStateFile, error) {
+	divisionToTerraformerState := map[terraformValueObjects.Division]driftDetector.TerraformerStateFile{}
+	for division := range newResources {
+		content, err := os.ReadFile(fmt.Sprintf("current_cloud/%v/terraform.tfstate", division))
+		if err != nil {
+			return nil, fmt.Errorf("[load_new_resources_terraformer_states][os.ReadFile]%w", err)
+		}
+
+		state, err := driftDetector.ParseTerraformerStateFile(content)
+		if err != nil {
+			return nil, fmt.Errorf("[load_new_resources_terraformer_states][driftDetector.ParseTerraformerStateFile]%w", err)
+		}
+		divisionToTerraformerState[division] = state
+	}
+	return divisionToTerraformerState, nil
+}
+
+// getResourceToWorkspaceMapping produces a mapping of new resources to suggested workspace, placing resources with a
+// placement override, matching a placement rule or carrying an ownership tag accordingly, in that order of precedence,
+// and the remaining resources by the NLP engine.
+func (c *TerraformResourcesCalculator) getResourceToWorkspaceMapping(
+	ctx context.Context,
+	newResources map[terraformValueObjects.Division]map[documentize.ResourceData]bool,
+	divisionToTerraformerState map[terraformValueObjects.Division]driftDetector.TerraformerStateFile,
+	workspaceToDirectory map[string]string,
+) error {
 	c.dragonDrop.PostLog(ctx, "Beginning to calculate recommended placement of resources to workspace.")
-	err := c.pyScriptExec.RunNLPEngine()
 
+	signals, err := c.loadTagSignals(workspaceToDirectory)
+	if err != nil {
+		return fmt.Errorf("[get_resource_to_workspace]%w", err)
+	}
+	rulePlacements := signals.placeResources(newResources, divisionToTerraformerState)
+
+	rules, err := loadPlacementRules(c.config.PlacementRulesFile)
+	if err != nil {
+		return fmt.Errorf("[get_resource_to_workspace]%w", err)
+	}
+
+	matchedRulePlacements, err := rules.placeResources(newResources, divisionToTerraformerState, workspaceToDirectory)
+	if err != nil {
+		return fmt.Errorf("[get_resource_to_workspace][rules.placeResources]%w", err)
+	}
+	for resource, workspace := range matchedRulePlacements {
+		rulePlacements[resource] = workspace
+	}
+
+	overrides, err := loadPlacementOverrides(placementOverridesPath())
+	if err != nil {
+		return fmt.Errorf("[get_resource_to_workspace]%w", err)
+	}
+
+	overridePlacements, err := overrides.placeResources(newResources, workspaceToDirectory)
+	if err != nil {
+		return fmt.Errorf("[get_resource_to_workspace][overrides.placeResources]%w", err)
+	}
+	for resource, workspace := range overridePlacements {
+		rulePlacements[resource] = workspace
+	}
+
+	newResourceCount := 0
+	for _, resources := range newResources {
+		newResourceCount += len(resources)
+	}
+
+	resourceToWorkspace := map[string]string{}
+	if len(rulePlacements) < newResourceCount {
+		err = c.nlpEngine.RunNLPEngine()
+		if err != nil {
+			return fmt.Errorf("[get_resource_to_workspace][nlpEngine.RunNLPEngine]%w", err)
+		}
+
+		if len(rulePlacements) == 0 {
+			c.dragonDrop.PostLog(ctx, "Done making a map of workspaces to documents.")
+			return nil
+		}
+
+		resourceToWorkspaceBytes, err := c.artifactStore.ReadFile("mappings/new-resources-to-workspace.json")
+		if err != nil {
+			return fmt.Errorf("[get_resource_to_workspace][artifacts.ReadFile new-resources-to-workspace.json]%w", err)
+		}
+		err = json.Unmarshal(resourceToWorkspaceBytes, &resourceToWorkspace)
+		if err != nil {
+			return fmt.Errorf("[get_resource_to_workspace][json.Unmarshal new-resources-to-workspace.json]%w", err)
+		}
+	}
+
+	for resource, workspace := range rulePlacements {
+		resourceToWorkspace[resource] = workspace
+	}
+
+	err = writeRulePlacementConfidence(c.artifactStore, rulePlacements)
+	if err != nil {
+		return fmt.Errorf("[get_resource_to_workspace]%w", err)
+	}
+
+	outputBytes, err := json.MarshalIndent(resourceToWorkspace, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[get_resource_to_workspace][json.MarshalIndent]%w", err)
+	}
+
+	err = c.artifactStore.WriteFile("mappings/new-resources-to-workspace.json", outputBytes, 0400)
 	if err != nil {
-		return fmt.Errorf("[get_resource_to_workspace][pse.RunNLPEngine]%w", err)
+		return fmt.Errorf("[get_resource_to_workspace][artifacts.WriteFile new-resources-to-workspace.json]%w", err)
 	}
 
 	c.dragonDrop.PostLog(ctx, "Done making a map of workspaces to documents.")
@@ -126,7 +437,7 @@ func (c *TerraformResourcesCalculator) createNewResourceDocuments(ctx context.Co
 		return fmt.Errorf("[create_new_resource_documents][docu.ConvertNewResourcesToJSON] Error: %v", err)
 	}
 
-	err = os.WriteFile("mappings/new-resources-to-documents.json", resourceDocsJSON, 0400)
+	err = c.artifactStore.WriteFile("mappings/new-resources-to-documents.json", resourceDocsJSON, 0400)
 	if err != nil {
 		return fmt.Errorf("[create_new_resource_documents][write mappings/new-resources-to-documents.json] Error: %v", err)
 	}
@@ -146,7 +457,7 @@ func (c *TerraformResourcesCalculator) createNewResourceDocuments(ctx context.Co
 		return fmt.Errorf("[json.MarshalIndent]%v", err)
 	}
 
-	err = os.WriteFile("mappings/division-to-new-resources.json", divisionToNewResourceDataJSON, 0400)
+	err = c.artifactStore.WriteFile("mappings/division-to-new-resources.json", divisionToNewResourceDataJSON, 0400)
 	if err != nil {
 		return fmt.Errorf("[create_new_resource_documents][write mappings/division-to-new-resources.json] Error: %v", err)
 	}
@@ -214,9 +525,9 @@ func (c *TerraformResourcesCalculator) createDivisionToNewResourceData(
 			if resource.Type == resourceType && resource.Name == resourceName {
 				cloudProvider := strings.Split(resource.Type, "_")[0]
 				attributesFlat := resource.Instances[0].AttributesFlat
-				resourceID, err = driftDetector.ResourceIDCalculator(attributesFlat, cloudProvider, resourceType)
+				resourceID, err = c.config.ResourceIDMappings.Calculate(attributesFlat, cloudProvider, resourceType)
 				if err != nil {
-					return nil, fmt.Errorf("[driftDetector.ResourceIDCalculator]%v", err)
+					return nil, fmt.Errorf("[c.config.ResourceIDMappings.Calculate]%v", err)
 				}
 				region, err = driftDetector.ParseRegionFromTfStateMap(
 					resource.Instances[0].AttributesFlat,
@@ -282,7 +593,7 @@ func (c *TerraformResourcesCalculator) createWorkspaceDocuments(ctx context.Cont
 		return "[createWorkspacesToDocuments] %v", err
 	}
 
-	err = os.WriteFile("mappings/workspace-to-documents.json", outputBytes, 0400)
+	err = c.artifactStore.WriteFile("mappings/workspace-to-documents.json", outputBytes, 0400)
 
 	if err != nil {
 		return "[createWorkspacesToDocuments] %v", err
@@ -5,6 +5,10 @@ import (
 	"testing"
 
 	"github.com/Jeffail/gabs/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/baseline"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/documentize"
 	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
 )
@@ -88,3 +92,73 @@ func TestCreateDivisionToNewResourceData(t *testing.T) {
 		t.Errorf("expected output to be:\n%v\ngot:\n%v\n", expectedOutput, output)
 	}
 }
+
+func TestExcludeManagedDriftOnlyDivisions(t *testing.T) {
+	// Given
+	c := TerraformResourcesCalculator{config: Config{ManagedDriftOnlyDivisions: []string{"prod"}}}
+	newResources := map[terraformValueObjects.Division]map[documentize.ResourceData]bool{
+		"aws-prod":    {documentize.ResourceData{}: true},
+		"aws-sandbox": {documentize.ResourceData{}: true},
+	}
+
+	// When
+	output := c.excludeManagedDriftOnlyDivisions(newResources)
+
+	// Then
+	assert.Equal(t, map[terraformValueObjects.Division]map[documentize.ResourceData]bool{
+		"aws-sandbox": {documentize.ResourceData{}: true},
+	}, output)
+}
+
+func TestExcludeAcceptedResources(t *testing.T) {
+	// Given
+	c := TerraformResourcesCalculator{}
+	accepted := &baseline.Baseline{Unmanaged: []baseline.AcceptedUnmanaged{{Division: "sandbox"}}}
+	newResources := map[terraformValueObjects.Division]map[documentize.ResourceData]bool{
+		"aws-prod":    {documentize.ResourceData{}: true},
+		"aws-sandbox": {documentize.ResourceData{}: true},
+	}
+
+	// When
+	output := c.excludeAcceptedResources(newResources, accepted)
+
+	// Then
+	assert.Equal(t, map[terraformValueObjects.Division]map[documentize.ResourceData]bool{
+		"aws-prod": {documentize.ResourceData{}: true},
+	}, output)
+}
+
+func TestExcludeDefaultResources(t *testing.T) {
+	// Given
+	c := TerraformResourcesCalculator{config: Config{DefaultResourceExclusions: []string{"aws_iam_role:path=/service-role/*"}}}
+	defaultGroup := documentize.NewResourceData("aws_security_group", "sg-default", "tfer--default")
+	webGroup := documentize.NewResourceData("aws_security_group", "sg-web", "tfer--web")
+	serviceRole := documentize.NewResourceData("aws_iam_role", "lambda-role", "tfer--lambda-role")
+	newResources := map[terraformValueObjects.Division]map[documentize.ResourceData]bool{
+		"aws-111111111111": {defaultGroup: true, webGroup: true, serviceRole: true},
+	}
+	divisionToTerraformerState := map[terraformValueObjects.Division]driftDetector.TerraformerStateFile{
+		"aws-111111111111": {Resources: []*driftDetector.TerraformerResource{
+			{
+				Type: "aws_security_group",
+				Instances: []driftDetector.TerraformerInstance{
+					{AttributesFlat: map[string]string{"id": "sg-default", "name": "default"}},
+					{AttributesFlat: map[string]string{"id": "sg-web", "name": "web"}},
+				},
+			},
+			{
+				Type:      "aws_iam_role",
+				Instances: []driftDetector.TerraformerInstance{{AttributesFlat: map[string]string{"id": "lambda-role", "path": "/service-role/"}}},
+			},
+		}},
+	}
+
+	// When
+	output, err := c.excludeDefaultResources(newResources, divisionToTerraformerState)
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, map[terraformValueObjects.Division]map[documentize.ResourceData]bool{
+		"aws-111111111111": {webGroup: true},
+	}, output)
+}
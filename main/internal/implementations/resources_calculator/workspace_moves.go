@@ -0,0 +1,159 @@
+package resourcesCalculator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+)
+
+// WorkspaceMove is the reorganization of an already-managed resource into a different workspace or module path.
+type WorkspaceMove struct {
+	// FromWorkspace is the workspace whose state currently holds the resource.
+	FromWorkspace string
+
+	// From is the current address of the resource, e.g. module.legacy.aws_s3_bucket.logs.
+	From string
+
+	// ToWorkspace is the workspace within which the resource belongs.
+	ToWorkspace string
+
+	// To is the address of the resource within ToWorkspace.
+	To string
+}
+
+// identifyWorkspaceMoves identifies the already-managed resources that the placement rules or configured ownership
+// tag mappings place within a different workspace, or beneath a different module of their workspace, and writes
+// them to mappings/managed-resources-to-moves.json. Rules with region or division criteria are not applied, as
+// neither is recorded within the state of managed resources.
+func (c *TerraformResourcesCalculator) identifyWorkspaceMoves(workspaceToDirectory map[string]string) error {
+	rules, err := loadPlacementRules(c.config.PlacementRulesFile)
+	if err != nil {
+		return fmt.Errorf("[identify_workspace_moves]%w", err)
+	}
+
+	signals, err := c.loadTagSignals(workspaceToDirectory)
+	if err != nil {
+		return fmt.Errorf("[identify_workspace_moves]%w", err)
+	}
+	// Learned tag signals reflect where resources currently are, so only configured mappings can move them.
+	signals.learnedWorkspaces = map[string]string{}
+
+	if len(rules.Rules) == 0 && len(signals.mappedWorkspaces) == 0 {
+		return nil
+	}
+
+	for _, rule := range rules.Rules {
+		if _, ok := workspaceToDirectory[rule.Workspace]; !ok {
+			return fmt.Errorf("[identify_workspace_moves][placement rule workspace %v is not a configured workspace]", rule.Workspace)
+		}
+	}
+
+	workspaces := make([]string, 0, len(workspaceToDirectory))
+	for workspace := range workspaceToDirectory {
+		workspaces = append(workspaces, workspace)
+	}
+	sort.Strings(workspaces)
+
+	moves := []WorkspaceMove{}
+	for _, workspace := range workspaces {
+		workspaceMoves, err := workspaceResourceMoves(workspace, rules, signals)
+		if err != nil {
+			return fmt.Errorf("[identify_workspace_moves]%w", err)
+		}
+		moves = append(moves, workspaceMoves...)
+	}
+
+	if len(moves) == 0 {
+		return nil
+	}
+
+	content, err := json.MarshalIndent(moves, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[identify_workspace_moves][json.MarshalIndent]%w", err)
+	}
+
+	err = c.artifactStore.WriteFile("mappings/managed-resources-to-moves.json", content, 0400)
+	if err != nil {
+		return fmt.Errorf("[identify_workspace_moves][artifacts.WriteFile managed-resources-to-moves.json]%w", err)
+	}
+	return nil
+}
+
+// workspaceResourceMoves returns the moves of the managed resources within a workspace's state file.
+func workspaceResourceMoves(workspace string, rules PlacementRules, signals tagSignals) ([]WorkspaceMove, error) {
+	content, err := os.ReadFile(fmt.Sprintf("state_files/%v.json", workspace))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[workspace_resource_moves][os.ReadFile state_files/%v.json]%w", workspace, err)
+	}
+
+	state := driftDetector.TerraformStateFile{}
+	err = json.Unmarshal(content, &state)
+	if err != nil {
+		return nil, fmt.Errorf("[workspace_resource_moves][json.Unmarshal state_files/%v.json]%w", workspace, err)
+	}
+
+	keys := signals.precedenceKeys()
+	moves := []WorkspaceMove{}
+	for _, resource := range state.Resources {
+		if (resource.Mode != "" && resource.Mode != "managed") || len(resource.Instances) == 0 {
+			continue
+		}
+
+		tags := stateResourceTags(resource.Instances[0])
+		targetWorkspace, targetModule := "", ""
+		for _, rule := range rules.Rules {
+			if rule.matches(resource.Type, tags, "", nil) {
+				targetWorkspace, targetModule = rule.Workspace, rule.Module
+				break
+			}
+		}
+		if targetWorkspace == "" {
+			targetWorkspace, _ = signals.workspaceOfTags(tags, keys)
+		}
+		if targetWorkspace == "" {
+			continue
+		}
+
+		from := resourceAddress(resource.Module, resource.Type, resource.Name)
+		to := resourceAddress(targetModule, resource.Type, resource.Name)
+		if targetWorkspace == workspace && (targetModule == "" || from == to) {
+			continue
+		}
+
+		moves = append(moves, WorkspaceMove{FromWorkspace: workspace, From: from, ToWorkspace: targetWorkspace, To: to})
+	}
+	return moves, nil
+}
+
+// stateResourceTags returns the tags or labels of a managed resource instance within a Terraform state file.
+func stateResourceTags(instance driftDetector.ResourceInstance) map[string]string {
+	tags := map[string]string{}
+	for _, attribute := range stateTagAttributes {
+		attributeTags, ok := instance.Attributes[attribute].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for key, value := range attributeTags {
+			if stringValue, ok := value.(string); ok {
+				tags[key] = stringValue
+			}
+		}
+	}
+	return tags
+}
+
+// resourceAddress returns the address of a resource beneath a module, e.g. module.networking.aws_vpc.main, or within
+// the root module when module is empty.
+func resourceAddress(module string, resourceType string, name string) string {
+	if module == "" {
+		return fmt.Sprintf("%v.%v", resourceType, name)
+	}
+	return fmt.Sprintf("%v.%v.%v", module, resourceType, name)
+}
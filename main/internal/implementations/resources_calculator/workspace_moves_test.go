@@ -0,0 +1,80 @@
+package resourcesCalculator
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+)
+
+func TestIdentifyWorkspaceMoves(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	require.NoError(t, os.MkdirAll("mappings", 0700))
+	require.NoError(t, os.MkdirAll("state_files", 0700))
+	require.NoError(t, os.WriteFile("state_files/legacy.json", []byte(`{"resources": [
+		{"mode": "managed", "type": "aws_vpc", "name": "main", "instances": [{"attributes": {"id": "vpc-1"}}]},
+		{"mode": "managed", "module": "module.queues", "type": "aws_sqs_queue", "name": "orders", "instances": [
+			{"attributes": {"id": "orders", "tags": {"team": "payments"}}}
+		]},
+		{"mode": "data", "type": "aws_vpc", "name": "default", "instances": [{"attributes": {"id": "vpc-0"}}]}
+	]}`), 0600))
+	require.NoError(t, os.WriteFile("state_files/networking.json", []byte(`{"resources": [
+		{"mode": "managed", "type": "aws_subnet", "name": "private", "instances": [{"attributes": {"id": "subnet-1"}}]},
+		{"mode": "managed", "module": "module.vpc", "type": "aws_vpc", "name": "shared", "instances": [{"attributes": {"id": "vpc-2"}}]}
+	]}`), 0600))
+	require.NoError(t, os.WriteFile("placement-rules.yaml", []byte(`
+rules:
+  - workspace: networking
+    module: module.vpc
+    resource_types: ["aws_vpc"]
+  - workspace: networking
+    resource_types: ["aws_subnet"]
+`), 0600))
+
+	c := TerraformResourcesCalculator{artifactStore: artifacts.NewMemoryStore(true), config: Config{
+		PlacementRulesFile:     "placement-rules.yaml",
+		PlacementTagWorkspaces: map[string]string{"team=payments": "payments"},
+	}}
+	workspaceToDirectory := map[string]string{"legacy": "/legacy/", "networking": "/networking/", "payments": "/payments/"}
+
+	// When
+	err := c.identifyWorkspaceMoves(workspaceToDirectory)
+
+	// Then
+	require.NoError(t, err)
+	content, err := os.ReadFile("mappings/managed-resources-to-moves.json")
+	require.NoError(t, err)
+
+	moves := []WorkspaceMove{}
+	require.NoError(t, json.Unmarshal(content, &moves))
+	assert.Equal(t, []WorkspaceMove{
+		{FromWorkspace: "legacy", From: "aws_vpc.main", ToWorkspace: "networking", To: "module.vpc.aws_vpc.main"},
+		{FromWorkspace: "legacy", From: "module.queues.aws_sqs_queue.orders", ToWorkspace: "payments", To: "aws_sqs_queue.orders"},
+	}, moves)
+}
+
+func TestIdentifyWorkspaceMoves_NoPlacementSignals(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	c := TerraformResourcesCalculator{config: Config{PlacementTagKeys: []string{"team"}}}
+
+	// When
+	err := c.identifyWorkspaceMoves(map[string]string{"legacy": "/legacy/"})
+
+	// Then
+	require.NoError(t, err)
+	_, err = os.Stat("mappings/managed-resources-to-moves.json")
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/pyscriptexec"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/codevalidation"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/hclcreate"
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
@@ -18,19 +20,24 @@ type Factory struct {
 
 // Instantiate creates an instance that implements the ResourcesWriter interface, with the implementation
 // depending on the current environment.
-func (f *Factory) Instantiate(ctx context.Context, environment string, vcs interfaces.VCS, dragonDrop interfaces.DragonDrop, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider, hclConfig hclcreate.Config) (interfaces.ResourcesWriter, error) {
+func (f *Factory) Instantiate(ctx context.Context, environment string, vcs interfaces.VCS, dragonDrop interfaces.DragonDrop, terraformSecurity interfaces.TerraformSecurity, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider, hclConfig hclcreate.Config, validationConfig codevalidation.Config, maxResourcesPerPullRequest int, artifactStore artifacts.Store) (interfaces.ResourcesWriter, error) {
 	switch environment {
 	case "isolated":
 		return new(IsolatedResourcesWriter), nil
 	default:
-		return f.bootstrappedResourceWriter(ctx, vcs, dragonDrop, divisionToProvider, hclConfig)
+		return f.bootstrappedResourceWriter(ctx, vcs, dragonDrop, terraformSecurity, divisionToProvider, hclConfig, validationConfig, maxResourcesPerPullRequest, artifactStore)
 	}
 }
 
 // bootstrappedResourceWriter creates a complete implementation of the ResourcesWriter interface with
 // configuration specified via environment variables.
-func (f *Factory) bootstrappedResourceWriter(ctx context.Context, vcs interfaces.VCS, dragonDrop interfaces.DragonDrop, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider, hclConfig hclcreate.Config) (interfaces.ResourcesWriter, error) {
-	hclCreate, err := hclcreate.NewHCLCreate(hclConfig, divisionToProvider)
+func (f *Factory) bootstrappedResourceWriter(ctx context.Context, vcs interfaces.VCS, dragonDrop interfaces.DragonDrop, terraformSecurity interfaces.TerraformSecurity, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider, hclConfig hclcreate.Config, validationConfig codevalidation.Config, maxResourcesPerPullRequest int, artifactStore artifacts.Store) (interfaces.ResourcesWriter, error) {
+	err := codevalidation.ValidateConfig(validationConfig)
+	if err != nil {
+		return nil, fmt.Errorf("[invalid generated code validation config]%w", err)
+	}
+
+	hclCreate, err := hclcreate.NewHCLCreate(hclConfig, divisionToProvider, artifactStore)
 	if err != nil {
 		log.Errorf("[cannot instantiate hclCreate config]%s", err.Error())
 		return nil, fmt.Errorf("[cannot instantiate hclCreate config]%w", err)
@@ -38,6 +45,11 @@ func (f *Factory) bootstrappedResourceWriter(ctx context.Context, vcs interfaces
 
 	dragonDrop.PostLog(ctx, "Created HCLCreate client.")
 
-	pyScriptExec := pyscriptexec.NewPyScriptExec()
-	return NewTerraformResourceWriter(hclCreate, vcs, pyScriptExec, dragonDrop), nil
+	generatedDirectory := hclConfig.GeneratedDirectory
+	if generatedDirectory == "" {
+		generatedDirectory = hclcreate.DefaultGeneratedDirectory
+	}
+
+	pyScriptExec := pyscriptexec.NewPyScriptExec(artifactStore)
+	return NewTerraformResourceWriter(hclCreate, vcs, pyScriptExec, dragonDrop, terraformSecurity, validationConfig, generatedDirectory, maxResourcesPerPullRequest, artifactStore), nil
 }
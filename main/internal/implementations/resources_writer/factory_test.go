@@ -4,6 +4,8 @@ import (
 	"context"
 	"testing"
 
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/codevalidation"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/hclcreate"
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
@@ -21,7 +23,7 @@ func TestCreateIsolatedResourcesWriter(t *testing.T) {
 	divisionToProvider := make(map[terraformValueObjects.Division]terraformValueObjects.Provider)
 
 	// When
-	resourcesWriter, err := resourcesWriterFactory.Instantiate(ctx, resourcesWriterProvider, vcs, dragonDrop, divisionToProvider, hclConfig)
+	resourcesWriter, err := resourcesWriterFactory.Instantiate(ctx, resourcesWriterProvider, vcs, dragonDrop, new(interfaces.TerraformSecurityMock), divisionToProvider, hclConfig, codevalidation.Config{}, 0, artifacts.NewMemoryStore(true))
 
 	// Then
 	assert.Nil(t, err)
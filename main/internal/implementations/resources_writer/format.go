@@ -0,0 +1,63 @@
+package resourcesWriter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/secretscan"
+)
+
+// formatGeneratedCode formats the generated files to be committed in the canonical style of terraform fmt, so that
+// they pass formatting checks such as pre-commit hooks.
+func (w *TerraformResourceWriter) formatGeneratedCode(ctx context.Context, workspaceToDirectory map[string]string) error {
+	w.dragonDrop.PostLog(ctx, "Beginning to format generated code.")
+
+	paths, err := secretscan.GeneratedFiles(workspaceToDirectory, w.generatedDirectory)
+	if err != nil {
+		return fmt.Errorf("[format_generated_code]%w", err)
+	}
+
+	err = formatFiles(paths)
+	if err != nil {
+		return fmt.Errorf("[format_generated_code]%w", err)
+	}
+
+	w.dragonDrop.PostLog(ctx, "Done formatting generated code.")
+	return nil
+}
+
+// formatFiles formats each of the HCL files at paths, rewriting only those not already formatted.
+func formatFiles(paths []string) error {
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("[format_files][os.ReadFile %v]%w", path, err)
+		}
+
+		formatted := hclwrite.Format(content)
+		if bytes.Equal(content, formatted) {
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("[format_files][os.Stat %v]%w", path, err)
+		}
+
+		// Generated files are written as read only, so they are replaced rather than truncated.
+		err = os.Remove(path)
+		if err != nil {
+			return fmt.Errorf("[format_files][os.Remove %v]%w", path, err)
+		}
+
+		err = os.WriteFile(path, formatted, info.Mode().Perm())
+		if err != nil {
+			return fmt.Errorf("[format_files][os.WriteFile %v]%w", path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package resourcesWriter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatFiles(t *testing.T) {
+	// Given
+	directory := t.TempDir()
+	unformattedPath := filepath.Join(directory, "new-resources.tf")
+	require.NoError(t, os.WriteFile(unformattedPath, []byte(`resource "aws_s3_bucket" "tfer--logs" {
+bucket = "logs"
+  force_destroy   = false
+}
+`), 0400))
+
+	formattedPath := filepath.Join(directory, "imports.tf")
+	formatted := `import {
+  to = aws_s3_bucket.tfer--logs
+  id = "logs"
+}
+`
+	require.NoError(t, os.WriteFile(formattedPath, []byte(formatted), 0400))
+
+	// When
+	err := formatFiles([]string{unformattedPath, formattedPath})
+
+	// Then
+	require.NoError(t, err)
+	content, err := os.ReadFile(unformattedPath)
+	require.NoError(t, err)
+	assert.Equal(t, `resource "aws_s3_bucket" "tfer--logs" {
+  bucket        = "logs"
+  force_destroy = false
+}
+`, string(content))
+
+	info, err := os.Stat(unformattedPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0400), info.Mode().Perm())
+
+	content, err = os.ReadFile(formattedPath)
+	require.NoError(t, err)
+	assert.Equal(t, formatted, string(content))
+}
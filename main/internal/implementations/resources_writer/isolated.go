@@ -17,7 +17,7 @@ func NewIsolatedResourcesWriter() interfaces.ResourcesWriter {
 }
 
 // Execute writes new resources to the relevant version control system,
-// and returns a pull request url corresponding to the new changes.
-func (w *IsolatedResourcesWriter) Execute(ctx context.Context, jobName string, createDummyFile bool, workspaceToDirectory map[string]string) (string, error) {
-	return "", nil
+// and returns the urls of the pull requests opened for the new changes.
+func (w *IsolatedResourcesWriter) Execute(ctx context.Context, jobName string, createDummyFile bool, workspaceToDirectory map[string]string) ([]string, error) {
+	return []string{}, nil
 }
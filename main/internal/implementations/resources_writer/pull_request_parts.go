@@ -0,0 +1,316 @@
+package resourcesWriter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+)
+
+// workspaceNewResourceCounts counts the new resources proposed within each workspace, as recorded within
+// mappings/new-resources-to-workspace.json.
+func workspaceNewResourceCounts(store artifacts.Store) (map[string]int, error) {
+	counts := map[string]int{}
+
+	content, err := store.ReadFile("mappings/new-resources-to-workspace.json")
+	if errors.Is(err, os.ErrNotExist) {
+		return counts, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[workspace_new_resource_counts][artifacts.ReadFile]%w", err)
+	}
+
+	newResourceToWorkspace := map[string]string{}
+	err = json.Unmarshal(content, &newResourceToWorkspace)
+	if err != nil {
+		return nil, fmt.Errorf("[workspace_new_resource_counts][json.Unmarshal]%w", err)
+	}
+
+	for _, workspace := range newResourceToWorkspace {
+		counts[workspace]++
+	}
+	return counts, nil
+}
+
+// pullRequestPart comprises the changed paths committed within a single pull request.
+type pullRequestPart struct {
+	// paths are the repository relative changed paths committed within the part.
+	paths []string
+
+	// contents maps those paths of a workspace split across parts to the content they are committed with within the
+	// part, holding only the part's share of the new resources and import blocks of the workspace.
+	contents map[string][]byte
+
+	// newResources is the number of new resources within the part.
+	newResources int
+}
+
+// pullRequestParts splits the changed paths into the parts of separate pull requests, each holding at most
+// maxResources new resources. Workspaces are kept whole where possible, while a workspace with more than maxResources
+// new resources is split by the blocks of its new resources files, each part holding the import blocks of its share
+// of the new resources alongside their definitions. Changes outside of any workspace are part of the first pull
+// request. A single part holds all changed paths when maxResources is not positive.
+func pullRequestParts(
+	changedPaths []string,
+	workspaceToDirectory map[string]string,
+	workspaceToNewResources map[string]int,
+	maxResources int,
+	repositoryDirectory string,
+	generatedDirectory string,
+) ([]pullRequestPart, error) {
+	if maxResources <= 0 || len(changedPaths) == 0 {
+		return []pullRequestPart{{paths: changedPaths}}, nil
+	}
+
+	workspaceToPaths := map[string][]string{}
+	var unassignedPaths []string
+	for _, path := range changedPaths {
+		workspace, ok := pathWorkspace(path, workspaceToDirectory)
+		if !ok {
+			unassignedPaths = append(unassignedPaths, path)
+			continue
+		}
+		workspaceToPaths[workspace] = append(workspaceToPaths[workspace], path)
+	}
+
+	workspaces := make([]string, 0, len(workspaceToPaths))
+	for workspace := range workspaceToPaths {
+		workspaces = append(workspaces, workspace)
+	}
+	sort.Strings(workspaces)
+
+	parts := []pullRequestPart{{paths: unassignedPaths}}
+	for _, workspace := range workspaces {
+		workspaceParts := []pullRequestPart{{paths: workspaceToPaths[workspace], newResources: workspaceToNewResources[workspace]}}
+		if workspaceToNewResources[workspace] > maxResources {
+			var err error
+			workspaceParts, err = splitWorkspaceChanges(
+				workspaceToPaths[workspace], repositoryDirectory, workspaceToDirectory[workspace], generatedDirectory, maxResources,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("[pull_request_parts][splitting workspace %v]%w", workspace, err)
+			}
+			if len(workspaceParts) == 1 {
+				workspaceParts = []pullRequestPart{{paths: workspaceToPaths[workspace], newResources: workspaceToNewResources[workspace]}}
+			}
+		}
+
+		for _, workspacePart := range workspaceParts {
+			if workspacePart.newResources > maxResources {
+				log.Warnf(
+					"[pull_request_parts] a part of workspace %v holds %v new resources, more than the maximum of %v per pull request, as its new resources blocks cannot be split further",
+					workspace, workspacePart.newResources, maxResources,
+				)
+			}
+
+			lastPart := &parts[len(parts)-1]
+			if len(lastPart.paths) > 0 && lastPart.newResources+workspacePart.newResources > maxResources {
+				parts = append(parts, pullRequestPart{})
+				lastPart = &parts[len(parts)-1]
+			}
+			lastPart.paths = append(lastPart.paths, workspacePart.paths...)
+			lastPart.newResources += workspacePart.newResources
+			for path, content := range workspacePart.contents {
+				if lastPart.contents == nil {
+					lastPart.contents = map[string][]byte{}
+				}
+				lastPart.contents[path] = content
+			}
+		}
+	}
+
+	for _, part := range parts {
+		sort.Strings(part.paths)
+	}
+	return parts, nil
+}
+
+// hclSegment is a top level block of a generated file, along with the comments and blank lines preceding it.
+type hclSegment struct {
+	// address is the address the block declares, e.g. aws_s3_bucket.logs or module.s3, or the address an import
+	// block imports to.
+	address string
+
+	// content is the source of the block and the comments preceding it.
+	content []byte
+}
+
+// splitWorkspaceChanges splits the changed paths of the workspace within directory into parts of at most
+// maxResources new resources each. The resource and module blocks of the workspace's new resources files are
+// divided between the parts, in order, and each part holds the import blocks and child modules of its blocks.
+// Every other changed path of the workspace is part of the first part.
+func splitWorkspaceChanges(paths []string, repositoryDirectory string, directory string, generatedDirectory string, maxResources int) ([]pullRequestPart, error) {
+	directory = strings.Trim(directory, "/")
+	if directory != "" {
+		directory += "/"
+	}
+	importsPrefix := fmt.Sprintf("%v%v/imports/", directory, generatedDirectory)
+	modulesPrefix := fmt.Sprintf("%v%v/modules/", directory, generatedDirectory)
+
+	var newResourcesPaths, importsPaths, otherPaths []string
+	for _, path := range paths {
+		name := strings.TrimPrefix(path, directory)
+		switch {
+		case !strings.Contains(name, "/") && strings.HasPrefix(name, "new-resources") && strings.HasSuffix(name, ".tf"):
+			newResourcesPaths = append(newResourcesPaths, path)
+		case strings.HasPrefix(path, importsPrefix) && strings.HasSuffix(path, "_imports.tf"):
+			importsPaths = append(importsPaths, path)
+		default:
+			otherPaths = append(otherPaths, path)
+		}
+	}
+	sort.Strings(newResourcesPaths)
+
+	pathToSegments := map[string][]hclSegment{}
+	for _, path := range append(append([]string{}, newResourcesPaths...), importsPaths...) {
+		segments, err := readHCLSegments(filepath.Join(repositoryDirectory, path))
+		if err != nil {
+			return nil, err
+		}
+		pathToSegments[path] = segments
+	}
+
+	// Each resource or module block declared within the new resources files counts the import blocks it is the
+	// target of, and blocks are assigned to parts in order.
+	importAddresses := []string{}
+	for _, path := range importsPaths {
+		for _, segment := range pathToSegments[path] {
+			importAddresses = append(importAddresses, segment.address)
+		}
+	}
+
+	addressToPart := map[string]int{}
+	partResources := []int{0}
+	for _, path := range newResourcesPaths {
+		for _, segment := range pathToSegments[path] {
+			if segment.address == "" {
+				continue
+			}
+			blockResources := 0
+			for _, importAddress := range importAddresses {
+				if importAddress == segment.address || strings.HasPrefix(importAddress, segment.address+".") {
+					blockResources++
+				}
+			}
+
+			lastPart := len(partResources) - 1
+			if partResources[lastPart] > 0 && partResources[lastPart]+blockResources > maxResources {
+				partResources = append(partResources, 0)
+				lastPart++
+			}
+			addressToPart[segment.address] = lastPart
+			partResources[lastPart] += blockResources
+		}
+	}
+
+	parts := make([]pullRequestPart, len(partResources))
+	for i := range parts {
+		parts[i] = pullRequestPart{contents: map[string][]byte{}, newResources: partResources[i]}
+	}
+
+	for _, path := range append(append([]string{}, newResourcesPaths...), importsPaths...) {
+		partContents := make([][]byte, len(parts))
+		for _, segment := range pathToSegments[path] {
+			part := segmentPart(segment.address, addressToPart)
+			partContents[part] = append(partContents[part], segment.content...)
+		}
+		for i, content := range partContents {
+			content = bytes.TrimSpace(content)
+			if len(content) == 0 {
+				continue
+			}
+			parts[i].paths = append(parts[i].paths, path)
+			parts[i].contents[path] = append(content, '\n')
+		}
+	}
+
+	for _, path := range otherPaths {
+		part := 0
+		if strings.HasPrefix(path, modulesPrefix) {
+			module := strings.SplitN(strings.TrimPrefix(path, modulesPrefix), "/", 2)[0]
+			part = segmentPart("module."+module, addressToPart)
+		}
+		parts[part].paths = append(parts[part].paths, path)
+	}
+
+	return parts, nil
+}
+
+// segmentPart returns the part holding the block at address, or the import block targeting it, defaulting to the
+// first part.
+func segmentPart(address string, addressToPart map[string]int) int {
+	for address != "" {
+		if part, ok := addressToPart[address]; ok {
+			return part
+		}
+		lastSeparator := strings.LastIndex(address, ".")
+		if lastSeparator < 0 {
+			break
+		}
+		address = address[:lastSeparator]
+	}
+	return 0
+}
+
+// readHCLSegments reads the top level blocks of the HCL file at path, each along with the comments preceding it.
+// The address of a resource or module block is the address it declares, and that of an import block is the address
+// it imports to.
+func readHCLSegments(path string) ([]hclSegment, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("[read_hcl_segments][os.ReadFile %v]%w", path, err)
+	}
+
+	file, diags := hclsyntax.ParseConfig(content, path, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("[read_hcl_segments][hclsyntax.ParseConfig %v]%w", path, diags)
+	}
+
+	segments := []hclSegment{}
+	start := 0
+	for _, block := range file.Body.(*hclsyntax.Body).Blocks {
+		address := ""
+		switch block.Type {
+		case "resource":
+			address = strings.Join(block.Labels, ".")
+		case "module":
+			address = "module." + strings.Join(block.Labels, ".")
+		case "import":
+			if to, ok := block.Body.Attributes["to"]; ok {
+				toRange := to.Expr.Range()
+				address = strings.TrimSpace(string(content[toRange.Start.Byte:toRange.End.Byte]))
+			}
+		}
+
+		end := block.Range().End.Byte
+		segments = append(segments, hclSegment{address: address, content: content[start:end]})
+		start = end
+	}
+	return segments, nil
+}
+
+// pathWorkspace returns the workspace whose directory most closely contains the repository relative path.
+func pathWorkspace(path string, workspaceToDirectory map[string]string) (string, bool) {
+	workspace, longestDirectory := "", -1
+	for currentWorkspace, directory := range workspaceToDirectory {
+		directory = strings.Trim(directory, "/")
+		if directory != "" {
+			directory += "/"
+		}
+		if !strings.HasPrefix(path, directory) || len(directory) <= longestDirectory {
+			continue
+		}
+		workspace, longestDirectory = currentWorkspace, len(directory)
+	}
+	return workspace, longestDirectory >= 0
+}
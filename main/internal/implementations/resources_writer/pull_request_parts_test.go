@@ -0,0 +1,173 @@
+package resourcesWriter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
+)
+
+func TestPullRequestParts(t *testing.T) {
+	// Given
+	changedPaths := []string{
+		".github/cloud-concierge.md",
+		"terraform/app/imports.tf",
+		"terraform/app/new-resources.tf",
+		"terraform/app/network/new-resources.tf",
+		"terraform/data/new-resources.tf",
+		"terraform/logs/main.tf",
+	}
+	workspaceToDirectory := map[string]string{
+		"app":     "/terraform/app/",
+		"network": "/terraform/app/network/",
+		"data":    "/terraform/data/",
+		"logs":    "/terraform/logs/",
+	}
+	workspaceToNewResources := map[string]int{"app": 3, "network": 2, "data": 4}
+
+	// When
+	parts, err := pullRequestParts(changedPaths, workspaceToDirectory, workspaceToNewResources, 5, "repo", "cloud-concierge")
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, []pullRequestPart{
+		{paths: []string{".github/cloud-concierge.md", "terraform/app/imports.tf", "terraform/app/new-resources.tf"}, newResources: 3},
+		{paths: []string{"terraform/data/new-resources.tf", "terraform/logs/main.tf"}, newResources: 4},
+		{paths: []string{"terraform/app/network/new-resources.tf"}, newResources: 2},
+	}, parts)
+}
+
+func TestPullRequestPartsDisabled(t *testing.T) {
+	// Given
+	changedPaths := []string{"terraform/app/new-resources.tf", "terraform/data/new-resources.tf"}
+	workspaceToDirectory := map[string]string{"app": "/terraform/app/", "data": "/terraform/data/"}
+
+	// When
+	parts, err := pullRequestParts(changedPaths, workspaceToDirectory, map[string]int{"app": 100, "data": 100}, 0, "repo", "cloud-concierge")
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, []pullRequestPart{{paths: changedPaths}}, parts)
+}
+
+func TestPullRequestPartsSplitsWorkspace(t *testing.T) {
+	// Given
+	repositoryDirectory := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(repositoryDirectory, "app/cloud-concierge/imports"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(repositoryDirectory, "app/cloud-concierge/modules/queues"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(repositoryDirectory, "app/new-resources.tf"), []byte(`# This resource has no identified cost
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+
+resource "aws_s3_bucket" "data" {
+  bucket = "data"
+}
+
+module "queues" {
+  source = "./cloud-concierge/modules/queues"
+}
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(repositoryDirectory, "app/cloud-concierge/imports/abc_imports.tf"), []byte(`# Created at 2023-01-01 by alice
+import {
+  to = aws_s3_bucket.logs
+  id = "logs"
+}
+import {
+  to = aws_s3_bucket.data
+  id = "data"
+}
+import {
+  to = module.queues.aws_sqs_queue.jobs
+  id = "jobs"
+}
+`), 0644))
+
+	changedPaths := []string{
+		"app/cloud-concierge/imports/abc_imports.tf",
+		"app/cloud-concierge/modules/queues/main.tf",
+		"app/new-resources.tf",
+		"app/variables.tf",
+	}
+
+	// When
+	parts, err := pullRequestParts(changedPaths, map[string]string{"app": "/app/"}, map[string]int{"app": 3}, 2, repositoryDirectory, "cloud-concierge")
+
+	// Then
+	require.NoError(t, err)
+	require.Len(t, parts, 2)
+	assert.Equal(t, []string{"app/cloud-concierge/imports/abc_imports.tf", "app/new-resources.tf", "app/variables.tf"}, parts[0].paths)
+	assert.Equal(t, 2, parts[0].newResources)
+	assert.Equal(t, `# This resource has no identified cost
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+
+resource "aws_s3_bucket" "data" {
+  bucket = "data"
+}
+`, string(parts[0].contents["app/new-resources.tf"]))
+	assert.Equal(t, `# Created at 2023-01-01 by alice
+import {
+  to = aws_s3_bucket.logs
+  id = "logs"
+}
+import {
+  to = aws_s3_bucket.data
+  id = "data"
+}
+`, string(parts[0].contents["app/cloud-concierge/imports/abc_imports.tf"]))
+
+	assert.Equal(t, []string{"app/cloud-concierge/imports/abc_imports.tf", "app/cloud-concierge/modules/queues/main.tf", "app/new-resources.tf"}, parts[1].paths)
+	assert.Equal(t, 1, parts[1].newResources)
+	assert.Equal(t, `module "queues" {
+  source = "./cloud-concierge/modules/queues"
+}
+`, string(parts[1].contents["app/new-resources.tf"]))
+	assert.Equal(t, `import {
+  to = module.queues.aws_sqs_queue.jobs
+  id = "jobs"
+}
+`, string(parts[1].contents["app/cloud-concierge/imports/abc_imports.tf"]))
+}
+
+func TestCommitChangesOpenPullRequestParts(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+	require.NoError(t, os.MkdirAll("mappings", 0755))
+	require.NoError(t, os.WriteFile("mappings/new-resources-to-workspace.json", []byte(`{
+		"aws-prod.aws_s3_bucket.tfer--logs": "app",
+		"aws-prod.aws_s3_bucket.tfer--data": "data"
+	}`), 0644))
+
+	vcs := new(interfaces.VCSMock)
+	vcs.On("ChangedPaths").Return([]string{"app/new-resources.tf", "data/new-resources.tf"}, nil)
+	vcs.On("CommitPaths", []string{"app/new-resources.tf"}).Return(nil)
+	vcs.On("CheckoutPart", 2).Return(nil)
+	vcs.On("CommitPaths", []string{"data/new-resources.tf"}).Return(nil)
+	vcs.On("Push").Return(nil)
+	vcs.On("UploadSARIF").Return(nil)
+	vcs.On("OpenPullRequest").Return("https://github.com/org/repo/pull/1", nil).Once()
+	vcs.On("OpenPullRequest").Return("https://github.com/org/repo/pull/2", nil).Once()
+
+	writer := &TerraformResourceWriter{artifactStore: artifacts.NewMemoryStore(true), vcs: vcs, dragonDrop: new(interfaces.DragonDropMock), jobName: "my-job", maxResourcesPerPullRequest: 1}
+
+	// When
+	prURLs, err := writer.commitChangesOpenPullRequest(context.Background(), map[string]string{"app": "/app/", "data": "/data/"})
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://github.com/org/repo/pull/1", "https://github.com/org/repo/pull/2"}, prURLs)
+	vcs.AssertExpectations(t)
+	vcs.AssertNumberOfCalls(t, "Push", 2)
+	vcs.AssertNumberOfCalls(t, "UploadSARIF", 1)
+	vcs.AssertNotCalled(t, "AddChanges")
+}
@@ -2,12 +2,21 @@ package resourcesWriter
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
+	log "github.com/sirupsen/logrus"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/clonedrepo"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/codevalidation"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/hclcreate"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/pyscriptexec"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/secretscan"
 )
 
 // TerraformResourceWriter is a struct that implements the ResourcesWriter interface for usage within
@@ -27,69 +36,236 @@ type TerraformResourceWriter struct {
 
 	// dragonDrop is an implementation of the DragonDrop interface
 	dragonDrop interfaces.DragonDrop
+
+	// terraformSecurity scans the repository directories changed by the pull request once the code is written
+	terraformSecurity interfaces.TerraformSecurity
+
+	// validationConfig is the configuration of the validation of generated code
+	validationConfig codevalidation.Config
+
+	// generatedDirectory is the subdirectory of each workspace within which supporting files are generated
+	generatedDirectory string
+
+	// maxResourcesPerPullRequest, when positive, is the maximum number of new resources within each pull request, with
+	// the changes of further workspaces opened as separate pull requests
+	maxResourcesPerPullRequest int
+
+	// artifactStore holds the mappings read and written by the TerraformResourceWriter.
+	artifactStore artifacts.Store
 }
 
 // NewTerraformResourceWriter instantiates and returns a new instance of the TerraformResourceWriter.
-func NewTerraformResourceWriter(hclCreate hclcreate.HCLCreate, vcs interfaces.VCS, pyScriptExec pyscriptexec.PyScriptExec, dragonDrop interfaces.DragonDrop) interfaces.ResourcesWriter {
-	return &TerraformResourceWriter{hclCreate: hclCreate, vcs: vcs, pyScriptExec: pyScriptExec, dragonDrop: dragonDrop}
+func NewTerraformResourceWriter(hclCreate hclcreate.HCLCreate, vcs interfaces.VCS, pyScriptExec pyscriptexec.PyScriptExec, dragonDrop interfaces.DragonDrop, terraformSecurity interfaces.TerraformSecurity, validationConfig codevalidation.Config, generatedDirectory string, maxResourcesPerPullRequest int, artifactStore artifacts.Store) interfaces.ResourcesWriter {
+	return &TerraformResourceWriter{hclCreate: hclCreate, vcs: vcs, pyScriptExec: pyScriptExec, dragonDrop: dragonDrop, terraformSecurity: terraformSecurity, validationConfig: validationConfig, generatedDirectory: generatedDirectory, maxResourcesPerPullRequest: maxResourcesPerPullRequest, artifactStore: artifactStore}
 }
 
 // Execute writes new resources to the relevant version control system,
-// and returns a pull request url corresponding to the new changes.
-func (w *TerraformResourceWriter) Execute(ctx context.Context, jobName string, createDummyFile bool, workspaceToDirectory map[string]string) (string, error) {
+// and returns the urls of the pull requests opened for the new changes.
+func (w *TerraformResourceWriter) Execute(ctx context.Context, jobName string, createDummyFile bool, workspaceToDirectory map[string]string) ([]string, error) {
 	w.jobName = jobName
 
 	err := w.checkoutNewBranch(ctx)
 	if err != nil {
-		return "", fmt.Errorf("[terraform_resource_writer]%w", err)
+		return nil, fmt.Errorf("[terraform_resource_writer]%w", err)
+	}
+
+	if !createDummyFile {
+		workspaceToDirectory, err = w.hclCreate.CreateNewWorkspaces(workspaceToDirectory)
+		if err != nil {
+			return nil, fmt.Errorf("[terraform_resource_writer][error in hclc.CreateNewWorkspaces]%w", err)
+		}
 	}
 
 	err = w.writeNewResourcesAndMigrationStatements(ctx, createDummyFile, workspaceToDirectory)
 	if err != nil {
-		return "", fmt.Errorf("[terraform_resource_writer]%w", err)
+		return nil, fmt.Errorf("[terraform_resource_writer]%w", err)
+	}
+
+	err = w.extractVariables(ctx, createDummyFile, workspaceToDirectory)
+	if err != nil {
+		return nil, fmt.Errorf("[terraform_resource_writer]%w", err)
+	}
+
+	err = w.wrapNewResourcesInModules(ctx, createDummyFile, workspaceToDirectory)
+	if err != nil {
+		return nil, fmt.Errorf("[terraform_resource_writer]%w", err)
+	}
+
+	err = w.writeDriftRemediation(ctx, workspaceToDirectory)
+	if err != nil {
+		return nil, fmt.Errorf("[terraform_resource_writer]%w", err)
+	}
+
+	err = w.writeWorkspaceMoves(ctx, workspaceToDirectory)
+	if err != nil {
+		return nil, fmt.Errorf("[terraform_resource_writer]%w", err)
+	}
+
+	err = w.writeRemovedBlocks(ctx, workspaceToDirectory)
+	if err != nil {
+		return nil, fmt.Errorf("[terraform_resource_writer]%w", err)
+	}
+
+	err = w.redactSecrets(ctx, workspaceToDirectory)
+	if err != nil {
+		return nil, fmt.Errorf("[terraform_resource_writer]%w", err)
+	}
+
+	err = w.formatGeneratedCode(ctx, workspaceToDirectory)
+	if err != nil {
+		return nil, fmt.Errorf("[terraform_resource_writer]%w", err)
+	}
+
+	err = w.validateGeneratedCode(ctx, workspaceToDirectory)
+	if err != nil {
+		return nil, fmt.Errorf("[terraform_resource_writer]%w", err)
+	}
+
+	err = w.scanRepository(ctx, workspaceToDirectory)
+	if err != nil {
+		return nil, fmt.Errorf("[terraform_resource_writer]%w", err)
 	}
 
 	err = w.writeNewMarkdownAnalysis(ctx)
 	if err != nil {
-		return "", fmt.Errorf("[terraform_resource_writer]%w", err)
+		return nil, fmt.Errorf("[terraform_resource_writer]%w", err)
+	}
+
+	err = w.redactPublishedFiles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[terraform_resource_writer]%w", err)
 	}
 
-	prURL, err := w.commitChangesOpenPullRequest(ctx)
+	prURLs, err := w.commitChangesOpenPullRequest(ctx, workspaceToDirectory)
 	if err != nil {
-		return "", fmt.Errorf("[terraform_resource_writer]%w", err)
+		return nil, fmt.Errorf("[terraform_resource_writer]%w", err)
 	}
 
-	w.dragonDrop.PostLogAlert(ctx, fmt.Sprintf("Job is complete, pull request opened at URL: %v", prURL))
-	return prURL, nil
+	w.dragonDrop.PostLogAlert(ctx, fmt.Sprintf("Job is complete, pull requests opened at URLs: %v", strings.Join(prURLs, ", ")))
+	return prURLs, nil
 }
 
 // commitChangesOpenPullRequest adds new files to the VCS, commits the changes,
-// and opens a pull request for the branch.
-func (w *TerraformResourceWriter) commitChangesOpenPullRequest(ctx context.Context) (string, error) {
+// and opens a pull request for the branch. When the changes hold more new resources than a single pull request
+// may, they are split across several pull requests, and the urls of each are returned.
+func (w *TerraformResourceWriter) commitChangesOpenPullRequest(ctx context.Context, workspaceToDirectory map[string]string) ([]string, error) {
 	w.dragonDrop.PostLog(ctx, "Beginning to add, commit, push and open a pull request for changes made.")
 
+	if w.maxResourcesPerPullRequest > 0 {
+		changedPaths, err := w.vcs.ChangedPaths()
+		if err != nil {
+			return nil, fmt.Errorf("[commit_changes_open_pull_request][error in vcs.ChangedPaths]%w", err)
+		}
+
+		workspaceToNewResources, err := workspaceNewResourceCounts(w.artifactStore)
+		if err != nil {
+			return nil, fmt.Errorf("[commit_changes_open_pull_request]%w", err)
+		}
+
+		parts, err := pullRequestParts(
+			changedPaths, workspaceToDirectory, workspaceToNewResources, w.maxResourcesPerPullRequest, clonedrepo.Directory, w.generatedDirectory,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("[commit_changes_open_pull_request]%w", err)
+		}
+		if len(parts) > 1 {
+			return w.openPullRequestParts(ctx, parts)
+		}
+	}
+
 	err := w.vcs.AddChanges()
 	if err != nil {
-		return "", fmt.Errorf("[commit_changes_open_pull_request][error in vcs.AddChanges]%w", err)
+		return nil, fmt.Errorf("[commit_changes_open_pull_request][error in vcs.AddChanges]%w", err)
 	}
 
 	err = w.vcs.Commit()
 	if err != nil {
-		return "", fmt.Errorf("[commit_changes_open_pull_request][error in vcs.Commit]%w", err)
+		return nil, fmt.Errorf("[commit_changes_open_pull_request][error in vcs.Commit]%w", err)
 	}
 
 	err = w.vcs.Push()
 	if err != nil {
-		return "", fmt.Errorf("[commit_changes_open_pull_request][error in vcs.Push]%w", err)
+		return nil, fmt.Errorf("[commit_changes_open_pull_request][error in vcs.Push]%w", err)
+	}
+
+	// Code scanning is not available to every repository, so a failed upload does not prevent opening the pull request.
+	err = w.vcs.UploadSARIF()
+	if err != nil {
+		log.Warnf("[commit_changes_open_pull_request][error in vcs.UploadSARIF]%s", err.Error())
 	}
 
 	prURL, err := w.vcs.OpenPullRequest(w.jobName)
 	if err != nil {
-		return "", fmt.Errorf("[commit_changes_open_pull_request][error in vcs.OpenPullRequest]%w", err)
+		return nil, fmt.Errorf("[commit_changes_open_pull_request][error in vcs.OpenPullRequest]%w", err)
 	}
 
 	w.dragonDrop.PostLog(ctx, "Done opening a pull request for changes made.")
-	return prURL, nil
+	return []string{prURL}, nil
+}
+
+// openPullRequestParts commits each part of the changed paths to a branch of its own, based upon the base branch,
+// and opens a pull request for each, returning the urls of every part. The paths of a workspace split across parts
+// are rewritten with the part's share of their content ahead of each commit.
+func (w *TerraformResourceWriter) openPullRequestParts(ctx context.Context, parts []pullRequestPart) ([]string, error) {
+	w.dragonDrop.PostLog(ctx, fmt.Sprintf("Splitting changes made across %v pull requests.", len(parts)))
+
+	prURLs := []string{}
+	for i, part := range parts {
+		if i > 0 {
+			err := w.vcs.CheckoutPart(i + 1)
+			if err != nil {
+				return nil, fmt.Errorf("[open_pull_request_parts][error in vcs.CheckoutPart]%w", err)
+			}
+		}
+
+		for path, content := range part.contents {
+			err := rewriteFile(filepath.Join(clonedrepo.Directory, path), content)
+			if err != nil {
+				return nil, fmt.Errorf("[open_pull_request_parts]%w", err)
+			}
+		}
+
+		err := w.vcs.CommitPaths(part.paths)
+		if err != nil {
+			return nil, fmt.Errorf("[open_pull_request_parts][error in vcs.CommitPaths]%w", err)
+		}
+
+		err = w.vcs.Push()
+		if err != nil {
+			return nil, fmt.Errorf("[open_pull_request_parts][error in vcs.Push]%w", err)
+		}
+
+		// Code scanning is not available to every repository, so a failed upload does not prevent opening the pull request.
+		if i == 0 {
+			err = w.vcs.UploadSARIF()
+			if err != nil {
+				log.Warnf("[open_pull_request_parts][error in vcs.UploadSARIF]%s", err.Error())
+			}
+		}
+
+		prURL, err := w.vcs.OpenPullRequest(fmt.Sprintf("%v (part %v of %v)", w.jobName, i+1, len(parts)))
+		if err != nil {
+			return nil, fmt.Errorf("[open_pull_request_parts][error in vcs.OpenPullRequest]%w", err)
+		}
+		prURLs = append(prURLs, prURL)
+	}
+
+	w.dragonDrop.PostLog(ctx, fmt.Sprintf("Done opening pull requests for changes made: %v", strings.Join(prURLs, ", ")))
+	return prURLs, nil
+}
+
+// rewriteFile replaces the content of the generated, read only, file at path.
+func rewriteFile(path string, content []byte) error {
+	err := os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("[rewrite_file][os.Remove %v]%w", path, err)
+	}
+
+	err = os.WriteFile(path, content, 0400)
+	if err != nil {
+		return fmt.Errorf("[rewrite_file][os.WriteFile %v]%w", path, err)
+	}
+	return nil
 }
 
 // writeNewMarkdownAnalysis writes out the markdown analysis of the identified resources which are currently outside
@@ -146,6 +322,208 @@ func (w *TerraformResourceWriter) writeNewResourcesAndMigrationStatements(ctx co
 	return nil
 }
 
+// extractVariables lifts environment-specific and sensitive values out of the new resources and into variables.
+func (w *TerraformResourceWriter) extractVariables(ctx context.Context, createDummyFile bool, workspaceToDirectory map[string]string) error {
+	if createDummyFile {
+		return nil
+	}
+	w.dragonDrop.PostLog(ctx, "Beginning to extract variables from new resources.")
+
+	err := w.hclCreate.ExtractVariables(workspaceToDirectory)
+	if err != nil {
+		return fmt.Errorf("[extract_variables][error in hclc.ExtractVariables]%w", err)
+	}
+
+	w.dragonDrop.PostLog(ctx, "Done extracting variables from new resources.")
+	return nil
+}
+
+// wrapNewResourcesInModules moves the new resources into a child module per service when module wrapping is enabled.
+func (w *TerraformResourceWriter) wrapNewResourcesInModules(ctx context.Context, createDummyFile bool, workspaceToDirectory map[string]string) error {
+	if createDummyFile {
+		return nil
+	}
+	w.dragonDrop.PostLog(ctx, "Beginning to wrap new resources in modules.")
+
+	err := w.hclCreate.WrapNewResourcesInModules(workspaceToDirectory)
+	if err != nil {
+		return fmt.Errorf("[wrap_new_resources_in_modules][error in hclc.WrapNewResourcesInModules]%w", err)
+	}
+
+	w.dragonDrop.PostLog(ctx, "Done wrapping new resources in modules.")
+	return nil
+}
+
+// writeDriftRemediation writes suggested HCL patches that reconcile Terraform code with the
+// drifted attribute values observed in the cloud.
+func (w *TerraformResourceWriter) writeDriftRemediation(ctx context.Context, workspaceToDirectory map[string]string) error {
+	w.dragonDrop.PostLog(ctx, "Beginning to write drift remediation suggestions.")
+
+	err := w.hclCreate.WriteDriftRemediation(workspaceToDirectory)
+	if err != nil {
+		return fmt.Errorf("[write_drift_remediation][error in hclc.WriteDriftRemediation]%w", err)
+	}
+
+	w.dragonDrop.PostLog(ctx, "Done writing drift remediation suggestions.")
+	return nil
+}
+
+// writeWorkspaceMoves writes the state moves that reorganize already-managed resources into the workspace or module
+// path within which they belong.
+func (w *TerraformResourceWriter) writeWorkspaceMoves(ctx context.Context, workspaceToDirectory map[string]string) error {
+	w.dragonDrop.PostLog(ctx, "Beginning to write workspace moves.")
+
+	id, err := w.vcs.GetID()
+	if err != nil {
+		return fmt.Errorf("[write_workspace_moves][error getting the vcs id]%w", err)
+	}
+
+	err = w.hclCreate.WriteWorkspaceMoves(id, workspaceToDirectory)
+	if err != nil {
+		return fmt.Errorf("[write_workspace_moves][error in hclc.WriteWorkspaceMoves]%w", err)
+	}
+
+	w.dragonDrop.PostLog(ctx, "Done writing workspace moves.")
+	return nil
+}
+
+// writeRemovedBlocks writes the removed blocks or state rm scripts that reconcile each workspace's state with the
+// resources deleted outside of Terraform.
+func (w *TerraformResourceWriter) writeRemovedBlocks(ctx context.Context, workspaceToDirectory map[string]string) error {
+	w.dragonDrop.PostLog(ctx, "Beginning to write removed blocks.")
+
+	id, err := w.vcs.GetID()
+	if err != nil {
+		return fmt.Errorf("[write_removed_blocks][error getting the vcs id]%w", err)
+	}
+
+	err = w.hclCreate.WriteRemovedBlocks(id, workspaceToDirectory)
+	if err != nil {
+		return fmt.Errorf("[write_removed_blocks][error in hclc.WriteRemovedBlocks]%w", err)
+	}
+
+	w.dragonDrop.PostLog(ctx, "Done writing removed blocks.")
+	return nil
+}
+
+// redactSecrets replaces secrets within the generated files to be committed with references to sensitive variables,
+// and writes the redacted secrets for the markdown analysis.
+func (w *TerraformResourceWriter) redactSecrets(ctx context.Context, workspaceToDirectory map[string]string) error {
+	w.dragonDrop.PostLog(ctx, "Beginning to scan generated files for secrets.")
+
+	paths, err := secretscan.GeneratedFiles(workspaceToDirectory, w.generatedDirectory)
+	if err != nil {
+		return fmt.Errorf("[redact_secrets]%w", err)
+	}
+
+	findings, err := secretscan.RedactFiles(paths)
+	if err != nil {
+		return fmt.Errorf("[redact_secrets]%w", err)
+	}
+
+	for index := range findings {
+		findings[index].File = strings.TrimPrefix(findings[index].File, clonedrepo.Directory)
+	}
+
+	findingsJSON, err := json.MarshalIndent(findings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[redact_secrets][json.MarshalIndent]%w", err)
+	}
+
+	err = w.artifactStore.WriteFile(secretscan.FindingsPath, findingsJSON, 0400)
+	if err != nil {
+		return fmt.Errorf("[redact_secrets][artifacts.WriteFile %v]%w", secretscan.FindingsPath, err)
+	}
+
+	if len(findings) > 0 {
+		w.dragonDrop.PostLogAlert(ctx, fmt.Sprintf("Redacted %v secrets from the generated files.", len(findings)))
+	}
+	w.dragonDrop.PostLog(ctx, "Done scanning generated files for secrets.")
+	return nil
+}
+
+// reportPath is the state of cloud report, published as the description of the pull request.
+const reportPath = "state_of_cloud/report.md"
+
+// redactPublishedFiles scans every file changed within the repository, along with the state of cloud report, for
+// secrets once all of them have been written, as files outside of the generated ones, such as the report or the
+// tfmigrate and moved files, may still be committed or published with the pull request.
+func (w *TerraformResourceWriter) redactPublishedFiles(ctx context.Context) error {
+	w.dragonDrop.PostLog(ctx, "Beginning to scan published files for secrets.")
+
+	changedPaths, err := w.vcs.ChangedPaths()
+	if err != nil {
+		return fmt.Errorf("[redact_published_files][error in vcs.ChangedPaths]%w", err)
+	}
+
+	candidates := []string{reportPath}
+	for _, changedPath := range changedPaths {
+		candidates = append(candidates, filepath.Join(clonedrepo.Directory, changedPath))
+	}
+
+	paths := []string{}
+	for _, path := range candidates {
+		// Deleted files are within the changes but have no content to be scanned.
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		paths = append(paths, path)
+	}
+
+	findings, err := secretscan.RedactFiles(paths)
+	if err != nil {
+		return fmt.Errorf("[redact_published_files]%w", err)
+	}
+
+	if len(findings) > 0 {
+		w.dragonDrop.PostLogAlert(ctx, fmt.Sprintf("Redacted %v secrets from the files published with the pull request.", len(findings)))
+	}
+	w.dragonDrop.PostLog(ctx, "Done scanning published files for secrets.")
+	return nil
+}
+
+// validateGeneratedCode runs terraform validate and tflint against the workspaces receiving generated code, and
+// writes any failures for the markdown analysis so that broken HCL is surfaced within the pull request.
+func (w *TerraformResourceWriter) validateGeneratedCode(ctx context.Context, workspaceToDirectory map[string]string) error {
+	w.dragonDrop.PostLog(ctx, "Beginning to validate generated code.")
+
+	failures, err := codevalidation.Validate(ctx, w.validationConfig, workspaceToDirectory)
+	if err != nil {
+		return fmt.Errorf("[validate_generated_code]%w", err)
+	}
+
+	failuresJSON, err := json.MarshalIndent(failures, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[validate_generated_code][json.MarshalIndent]%w", err)
+	}
+
+	err = w.artifactStore.WriteFile(codevalidation.FailuresPath, failuresJSON, 0400)
+	if err != nil {
+		return fmt.Errorf("[validate_generated_code][artifacts.WriteFile %v]%w", codevalidation.FailuresPath, err)
+	}
+
+	if len(failures) > 0 {
+		w.dragonDrop.PostLogAlert(ctx, fmt.Sprintf("Generated code failed validation with %v issues.", len(failures)))
+	}
+	w.dragonDrop.PostLog(ctx, "Done validating generated code.")
+	return nil
+}
+
+// scanRepository security scans the repository directories of the changed and repository scopes, now that the
+// generated code is written to them.
+func (w *TerraformResourceWriter) scanRepository(ctx context.Context, workspaceToDirectory map[string]string) error {
+	w.dragonDrop.PostLog(ctx, "Beginning to security scan the changed repository directories.")
+
+	err := w.terraformSecurity.ScanRepository(ctx, workspaceToDirectory)
+	if err != nil {
+		return fmt.Errorf("[scan_repository][error in terraformSecurity.ScanRepository]%w", err)
+	}
+
+	w.dragonDrop.PostLog(ctx, "Done security scanning the changed repository directories.")
+	return nil
+}
+
 // checkoutNewBranch checks out a new branch within the version control system
 func (w *TerraformResourceWriter) checkoutNewBranch(ctx context.Context) error {
 	w.dragonDrop.PostLog(ctx, "Beginning to checkout new branch.")
@@ -163,19 +541,19 @@ func (w *TerraformResourceWriter) checkoutNewBranch(ctx context.Context) error {
 
 func (w *TerraformResourceWriter) writeDummyFile(ctx context.Context, workspaceToDirectory map[string]string) error {
 	for _, directory := range workspaceToDirectory {
-		err := os.MkdirAll(fmt.Sprintf("repo%vcloud-concierge/placeholder", directory), 0400)
+		err := os.MkdirAll(fmt.Sprintf("repo%v%v/placeholder", directory, w.generatedDirectory), 0400)
 		if err != nil {
 			return fmt.Errorf("error creating placeholder folder %v: %v", directory, err)
 		}
 
-		newFilePath := fmt.Sprintf("repo%vcloud-concierge/placeholder/dragondrop_placeholder.txt", directory)
+		newFilePath := fmt.Sprintf("repo%v%v/placeholder/dragondrop_placeholder.txt", directory, w.generatedDirectory)
 
 		err = os.WriteFile(newFilePath, []byte("Placeholder file for opening a PR"), 0400)
 		if err != nil {
 			return fmt.Errorf("error writing the placeholder file %v", err)
 		}
 
-		err = os.WriteFile("mappings/new-resources-to-documents.json", []byte("{}"), 0400)
+		err = w.artifactStore.WriteFile("mappings/new-resources-to-documents.json", []byte("{}"), 0400)
 		if err != nil {
 			return fmt.Errorf("error writing new resources empty JSON file: %v", err)
 		}
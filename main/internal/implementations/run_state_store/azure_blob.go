@@ -0,0 +1,79 @@
+package runStateStore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
+)
+
+// AzureBlobRunStateStore implements interfaces.RunStateStore with blobs in an Azure storage container, so that
+// state is shared between runs without depending upon AWS or GCP.
+type AzureBlobRunStateStore struct {
+	config Config
+
+	// containerURL is the url of the blob container in which run state is stored.
+	containerURL azblob.ContainerURL
+}
+
+// NewAzureBlobRunStateStore creates an instance of AzureBlobRunStateStore authenticated with the storage account's
+// shared key.
+func NewAzureBlobRunStateStore(config Config) (interfaces.RunStateStore, error) {
+	if config.AzureStorageAccountName == "" || config.AzureContainerName == "" {
+		return nil, fmt.Errorf("[azure_blob_run_state_store][a storage account name and container name are required]")
+	}
+
+	sharedCredential, err := azblob.NewSharedKeyCredential(config.AzureStorageAccountName, config.AzureStorageAccountKey)
+	if err != nil {
+		return nil, fmt.Errorf("[azure_blob_run_state_store][azblob.NewSharedKeyCredential]%w", err)
+	}
+
+	serviceURL, err := url.Parse(fmt.Sprintf("https://%s.blob.core.windows.net", config.AzureStorageAccountName))
+	if err != nil {
+		return nil, fmt.Errorf("[azure_blob_run_state_store][url.Parse]%w", err)
+	}
+
+	pipeline := azblob.NewPipeline(sharedCredential, azblob.PipelineOptions{})
+	containerURL := azblob.NewServiceURL(*serviceURL, pipeline).NewContainerURL(config.AzureContainerName)
+
+	return &AzureBlobRunStateStore{config: config, containerURL: containerURL}, nil
+}
+
+// Get returns the data stored under key.
+func (s *AzureBlobRunStateStore) Get(ctx context.Context, key string) ([]byte, error) {
+	blobURL := s.containerURL.NewBlobURL(s.config.Prefix + key)
+
+	response, err := blobURL.Download(ctx, 0, azblob.CountToEnd, azblob.BlobAccessConditions{}, false, azblob.ClientProvidedKeyOptions{})
+	if err != nil {
+		var storageErr azblob.StorageError
+		if errors.As(err, &storageErr) && storageErr.ServiceCode() == azblob.ServiceCodeBlobNotFound {
+			return nil, fmt.Errorf("[azure_blob_run_state_store][get %v]%w", key, ErrNotFound)
+		}
+		return nil, fmt.Errorf("[azure_blob_run_state_store][get %v]%w", key, err)
+	}
+
+	body := response.Body(azblob.RetryReaderOptions{MaxRetryRequests: 3})
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("[azure_blob_run_state_store][get %v][io.ReadAll]%w", key, err)
+	}
+	return data, nil
+}
+
+// Put stores data under key, replacing any existing blob.
+func (s *AzureBlobRunStateStore) Put(ctx context.Context, key string, data []byte) error {
+	blockBlobURL := s.containerURL.NewBlockBlobURL(s.config.Prefix + key)
+
+	_, err := azblob.UploadBufferToBlockBlob(ctx, data, blockBlobURL, azblob.UploadToBlockBlobOptions{})
+	if err != nil {
+		return fmt.Errorf("[azure_blob_run_state_store][put %v]%w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package runStateStore
+
+import "errors"
+
+// ErrNotFound is returned when no run state is stored under a key.
+var ErrNotFound = errors.New("[run state not found]")
+
+// Config is the configuration of the store in which state is persisted between job runs.
+type Config struct {
+	// Backend is the name of the storage backend, one of "local", "azureblob", "s3" or "dynamodb".
+	Backend string
+
+	// Directory is the directory in which run state is stored by the local backend.
+	Directory string
+
+	// AzureStorageAccountName is the name of the storage account used by the azureblob backend.
+	AzureStorageAccountName string
+
+	// AzureStorageAccountKey is the shared key of the storage account used by the azureblob backend.
+	AzureStorageAccountKey string
+
+	// AzureContainerName is the name of the blob container in which run state is stored by the azureblob backend.
+	AzureContainerName string
+
+	// S3Bucket is the name of the bucket in which run state is stored by the s3 backend.
+	S3Bucket string
+
+	// DynamoDBTable is the name of the table in which run state is stored by the dynamodb backend.
+	DynamoDBTable string
+
+	// AWSRegion is the region of the bucket or table used by the s3 and dynamodb backends.
+	AWSRegion string
+
+	// Prefix is prepended to every key, allowing several jobs to share a single store.
+	Prefix string
+}
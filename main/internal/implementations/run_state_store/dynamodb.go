@@ -0,0 +1,170 @@
+package runStateStore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
+)
+
+const (
+	// dynamoDBKeyAttribute is the string partition key of the run state table.
+	dynamoDBKeyAttribute = "key"
+
+	// dynamoDBDataAttribute is the binary attribute holding the run state stored under a key.
+	dynamoDBDataAttribute = "data"
+
+	// dynamoDBChunksAttribute is the number attribute holding the number of chunks in which the run state stored
+	// under a key is split, set in place of dynamoDBDataAttribute.
+	dynamoDBChunksAttribute = "chunks"
+
+	// dynamoDBMaxChunkSize is the largest run state stored within a single item, leaving room within DynamoDB's
+	// 400 KB item limit for the key and attribute names.
+	dynamoDBMaxChunkSize = 350 * 1024
+)
+
+// DynamoDBRunStateStore implements interfaces.RunStateStore with items in a DynamoDB table whose partition key is
+// the string attribute "key", authenticated with the default AWS credential chain. Run state larger than a single
+// item is split into chunks stored under "<key>#chunk-<index>", with the item of the key recording the number of
+// chunks.
+type DynamoDBRunStateStore struct {
+	config Config
+
+	// dynamoDBClient is the client used to send DynamoDB requests.
+	dynamoDBClient dynamodbiface.DynamoDBAPI
+}
+
+// NewDynamoDBRunStateStore creates an instance of DynamoDBRunStateStore.
+func NewDynamoDBRunStateStore(config Config) (interfaces.RunStateStore, error) {
+	if config.DynamoDBTable == "" {
+		return nil, fmt.Errorf("[dynamodb_run_state_store][a table name is required]")
+	}
+
+	newSession, err := session.NewSession(aws.NewConfig().WithRegion(config.AWSRegion))
+	if err != nil {
+		return nil, fmt.Errorf("[dynamodb_run_state_store][session.NewSession]%w", err)
+	}
+
+	return &DynamoDBRunStateStore{config: config, dynamoDBClient: dynamodb.New(newSession)}, nil
+}
+
+// Get returns the data stored under key, joining its chunks when it was split.
+func (s *DynamoDBRunStateStore) Get(ctx context.Context, key string) ([]byte, error) {
+	item, err := s.getItem(ctx, s.config.Prefix+key)
+	if err != nil {
+		return nil, fmt.Errorf("[dynamodb_run_state_store][get %v]%w", key, err)
+	}
+
+	if data, ok := item[dynamoDBDataAttribute]; ok {
+		return data.B, nil
+	}
+
+	chunks, ok := item[dynamoDBChunksAttribute]
+	if !ok || chunks.N == nil {
+		return nil, fmt.Errorf("[dynamodb_run_state_store][get %v]%w", key, ErrNotFound)
+	}
+	chunkCount, err := strconv.Atoi(*chunks.N)
+	if err != nil {
+		return nil, fmt.Errorf("[dynamodb_run_state_store][get %v][invalid chunk count %v]%w", key, *chunks.N, err)
+	}
+
+	data := make([]byte, 0, chunkCount*dynamoDBMaxChunkSize)
+	for i := 0; i < chunkCount; i++ {
+		chunkItem, err := s.getItem(ctx, dynamoDBChunkKey(s.config.Prefix+key, i))
+		if err != nil {
+			return nil, fmt.Errorf("[dynamodb_run_state_store][get %v][chunk %v]%w", key, i, err)
+		}
+
+		chunk, ok := chunkItem[dynamoDBDataAttribute]
+		if !ok {
+			return nil, fmt.Errorf("[dynamodb_run_state_store][get %v][chunk %v is missing]", key, i)
+		}
+		data = append(data, chunk.B...)
+	}
+	return data, nil
+}
+
+// Put stores data under key, replacing any existing item. Data larger than a single item is split into chunks,
+// which are written before the item of the key so that a partially written value is never read.
+func (s *DynamoDBRunStateStore) Put(ctx context.Context, key string, data []byte) error {
+	if len(data) <= dynamoDBMaxChunkSize {
+		err := s.putItem(ctx, s.config.Prefix+key, dynamoDBDataAttribute, &dynamodb.AttributeValue{B: data})
+		if err != nil {
+			return fmt.Errorf("[dynamodb_run_state_store][put %v]%w", key, err)
+		}
+		return nil
+	}
+
+	chunkCount := 0
+	for start := 0; start < len(data); start += dynamoDBMaxChunkSize {
+		end := start + dynamoDBMaxChunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+
+		err := s.putItem(ctx, dynamoDBChunkKey(s.config.Prefix+key, chunkCount), dynamoDBDataAttribute, &dynamodb.AttributeValue{B: data[start:end]})
+		if err != nil {
+			return fmt.Errorf("[dynamodb_run_state_store][put %v][chunk %v]%w", key, chunkCount, err)
+		}
+		chunkCount++
+	}
+
+	err := s.putItem(ctx, s.config.Prefix+key, dynamoDBChunksAttribute, &dynamodb.AttributeValue{N: aws.String(strconv.Itoa(chunkCount))})
+	if err != nil {
+		return fmt.Errorf("[dynamodb_run_state_store][put %v]%w", key, err)
+	}
+	return nil
+}
+
+// getItem returns the attributes of the item stored under the full key, or ErrNotFound.
+func (s *DynamoDBRunStateStore) getItem(ctx context.Context, fullKey string) (map[string]*dynamodb.AttributeValue, error) {
+	output, err := s.dynamoDBClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(s.config.DynamoDBTable),
+		Key:            map[string]*dynamodb.AttributeValue{dynamoDBKeyAttribute: {S: aws.String(fullKey)}},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, s.describeError("dynamodb:GetItem", err)
+	}
+	if len(output.Item) == 0 {
+		return nil, ErrNotFound
+	}
+	return output.Item, nil
+}
+
+// putItem stores value as attribute of the item under the full key, replacing any existing item.
+func (s *DynamoDBRunStateStore) putItem(ctx context.Context, fullKey string, attribute string, value *dynamodb.AttributeValue) error {
+	_, err := s.dynamoDBClient.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(s.config.DynamoDBTable),
+		Item: map[string]*dynamodb.AttributeValue{
+			dynamoDBKeyAttribute: {S: aws.String(fullKey)},
+			attribute:            value,
+		},
+	})
+	if err != nil {
+		return s.describeError("dynamodb:PutItem", err)
+	}
+	return nil
+}
+
+// describeError names the missing permission when a request is denied.
+func (s *DynamoDBRunStateStore) describeError(action string, err error) error {
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) && awsErr.Code() == "AccessDeniedException" {
+		return fmt.Errorf("[access denied to table %v, the job's AWS identity requires %v]%w", s.config.DynamoDBTable, action, err)
+	}
+	return err
+}
+
+// dynamoDBChunkKey returns the key of the chunk at index of the run state stored under the full key.
+func dynamoDBChunkKey(fullKey string, index int) string {
+	return fmt.Sprintf("%v#chunk-%v", fullKey, index)
+}
@@ -0,0 +1,91 @@
+package runStateStore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockDynamoDBClient stores items within a map keyed by their partition key, failing every request with err when set.
+type mockDynamoDBClient struct {
+	dynamodbiface.DynamoDBAPI
+	items map[string]map[string]*dynamodb.AttributeValue
+	err   error
+}
+
+func (m *mockDynamoDBClient) GetItemWithContext(ctx aws.Context, input *dynamodb.GetItemInput, options ...request.Option) (*dynamodb.GetItemOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &dynamodb.GetItemOutput{Item: m.items[*input.Key[dynamoDBKeyAttribute].S]}, nil
+}
+
+func (m *mockDynamoDBClient) PutItemWithContext(ctx aws.Context, input *dynamodb.PutItemInput, options ...request.Option) (*dynamodb.PutItemOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	for _, value := range input.Item {
+		if len(value.B) > 400*1024 {
+			return nil, errors.New("ValidationException: Item size has exceeded the maximum allowed size")
+		}
+	}
+	m.items[*input.Item[dynamoDBKeyAttribute].S] = input.Item
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func TestDynamoDBRunStateStore_PutAndGet(t *testing.T) {
+	tests := []struct {
+		name          string
+		data          []byte
+		expectedItems int
+	}{
+		{"single item", []byte(`{"stage": 3}`), 1},
+		{"chunked", bytes.Repeat([]byte("a"), 2*dynamoDBMaxChunkSize+10), 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			ctx := context.Background()
+			client := &mockDynamoDBClient{items: map[string]map[string]*dynamodb.AttributeValue{}}
+			store := &DynamoDBRunStateStore{config: Config{DynamoDBTable: "run-state", Prefix: "my-job/"}, dynamoDBClient: client}
+
+			// When
+			_, err := store.Get(ctx, "scan_progress/aws-prod.json")
+
+			// Then
+			assert.ErrorIs(t, err, ErrNotFound)
+
+			// When
+			err = store.Put(ctx, "scan_progress/aws-prod.json", tt.data)
+			require.NoError(t, err)
+			data, err := store.Get(ctx, "scan_progress/aws-prod.json")
+
+			// Then
+			require.NoError(t, err)
+			assert.Equal(t, tt.data, data)
+			assert.Len(t, client.items, tt.expectedItems)
+		})
+	}
+}
+
+func TestDynamoDBRunStateStore_AccessDenied(t *testing.T) {
+	// Given
+	client := &mockDynamoDBClient{err: awserr.New("AccessDeniedException", "User is not authorized to perform: dynamodb:PutItem", nil)}
+	store := &DynamoDBRunStateStore{config: Config{DynamoDBTable: "run-state"}, dynamoDBClient: client}
+
+	// When
+	err := store.Put(context.Background(), "summary.json", []byte("{}"))
+
+	// Then
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "access denied to table run-state, the job's AWS identity requires dynamodb:PutItem")
+}
@@ -0,0 +1,37 @@
+package runStateStore
+
+import (
+	"fmt"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
+)
+
+// Factory is a struct for creating different implementations of interfaces.RunStateStore.
+type Factory struct {
+}
+
+// Instantiate creates an implementation of interfaces.RunStateStore.
+func (f *Factory) Instantiate(environment string, config Config) (interfaces.RunStateStore, error) {
+	switch environment {
+	case "isolated":
+		return NewIsolatedRunStateStore(), nil
+	default:
+		return f.bootstrappedRunStateStore(config)
+	}
+}
+
+// bootstrappedRunStateStore instantiates the run state store for the configured backend.
+func (f *Factory) bootstrappedRunStateStore(config Config) (interfaces.RunStateStore, error) {
+	switch config.Backend {
+	case "", "local":
+		return NewLocalRunStateStore(config), nil
+	case "azureblob":
+		return NewAzureBlobRunStateStore(config)
+	case "s3":
+		return NewS3RunStateStore(config)
+	case "dynamodb":
+		return NewDynamoDBRunStateStore(config)
+	default:
+		return nil, fmt.Errorf("[run state store backend %v is not supported]", config.Backend)
+	}
+}
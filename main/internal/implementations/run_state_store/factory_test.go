@@ -0,0 +1,75 @@
+package runStateStore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateIsolatedRunStateStore(t *testing.T) {
+	// Given
+	factory := new(Factory)
+
+	// When
+	store, err := factory.Instantiate("isolated", Config{})
+
+	// Then
+	assert.Nil(t, err)
+	assert.IsType(t, &IsolatedRunStateStore{}, store)
+}
+
+func TestCreateRunStateStore_Backends(t *testing.T) {
+	// Given
+	factory := new(Factory)
+
+	// When
+	store, err := factory.Instantiate("", Config{Backend: "local"})
+
+	// Then
+	assert.Nil(t, err)
+	assert.IsType(t, &LocalRunStateStore{}, store)
+
+	// When
+	store, err = factory.Instantiate("", Config{
+		Backend:                 "azureblob",
+		AzureStorageAccountName: "myaccount",
+		AzureStorageAccountKey:  "bXlrZXk=",
+		AzureContainerName:      "cloud-concierge",
+	})
+
+	// Then
+	assert.Nil(t, err)
+	assert.IsType(t, &AzureBlobRunStateStore{}, store)
+
+	// When
+	store, err = factory.Instantiate("", Config{Backend: "s3", S3Bucket: "cloud-concierge", AWSRegion: "us-east-1"})
+
+	// Then
+	assert.Nil(t, err)
+	assert.IsType(t, &S3RunStateStore{}, store)
+
+	// When
+	store, err = factory.Instantiate("", Config{Backend: "dynamodb", DynamoDBTable: "cloud-concierge", AWSRegion: "us-east-1"})
+
+	// Then
+	assert.Nil(t, err)
+	assert.IsType(t, &DynamoDBRunStateStore{}, store)
+
+	// When
+	_, err = factory.Instantiate("", Config{Backend: "azureblob"})
+
+	// Then
+	assert.Error(t, err)
+
+	// When
+	_, err = factory.Instantiate("", Config{Backend: "s3"})
+
+	// Then
+	assert.Error(t, err)
+
+	// When
+	_, err = factory.Instantiate("", Config{Backend: "unknown"})
+
+	// Then
+	assert.Error(t, err)
+}
@@ -0,0 +1,42 @@
+package runStateStore
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
+)
+
+// IsolatedRunStateStore is a struct that implements interfaces.RunStateStore in memory for the purpose
+// of end-to-end testing.
+type IsolatedRunStateStore struct {
+	mutex sync.Mutex
+	data  map[string][]byte
+}
+
+// NewIsolatedRunStateStore creates an instance of IsolatedRunStateStore
+func NewIsolatedRunStateStore() interfaces.RunStateStore {
+	return &IsolatedRunStateStore{data: map[string][]byte{}}
+}
+
+// Get returns the data stored under key.
+func (s *IsolatedRunStateStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	data, ok := s.data[key]
+	if !ok {
+		return nil, fmt.Errorf("[isolated_run_state_store][get %v]%w", key, ErrNotFound)
+	}
+	return data, nil
+}
+
+// Put stores data under key.
+func (s *IsolatedRunStateStore) Put(ctx context.Context, key string, data []byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.data[key] = data
+	return nil
+}
@@ -0,0 +1,62 @@
+package runStateStore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
+)
+
+// LocalRunStateStore implements interfaces.RunStateStore with files in a local directory, e.g. a mounted volume.
+type LocalRunStateStore struct {
+	config Config
+}
+
+// NewLocalRunStateStore creates an instance of LocalRunStateStore.
+func NewLocalRunStateStore(config Config) interfaces.RunStateStore {
+	return &LocalRunStateStore{config: config}
+}
+
+// Get returns the data stored under key.
+func (s *LocalRunStateStore) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := os.ReadFile(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("[local_run_state_store][get %v]%w", key, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[local_run_state_store][get %v]%w", key, err)
+	}
+	return data, nil
+}
+
+// Put stores data under key, writing to a temporary file first so that a concurrent Get never reads partial data.
+func (s *LocalRunStateStore) Put(ctx context.Context, key string, data []byte) error {
+	path := s.path(key)
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return fmt.Errorf("[local_run_state_store][put %v][os.MkdirAll]%w", key, err)
+	}
+
+	err = os.WriteFile(path+".tmp", data, 0600)
+	if err != nil {
+		return fmt.Errorf("[local_run_state_store][put %v][os.WriteFile]%w", key, err)
+	}
+
+	err = os.Rename(path+".tmp", path)
+	if err != nil {
+		return fmt.Errorf("[local_run_state_store][put %v][os.Rename]%w", key, err)
+	}
+	return nil
+}
+
+// path returns the file path under which key is stored.
+func (s *LocalRunStateStore) path(key string) string {
+	directory := s.config.Directory
+	if directory == "" {
+		directory = "run_state"
+	}
+	return filepath.Join(directory, filepath.FromSlash(s.config.Prefix+key))
+}
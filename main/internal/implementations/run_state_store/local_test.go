@@ -0,0 +1,30 @@
+package runStateStore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalRunStateStore_PutAndGet(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	store := NewLocalRunStateStore(Config{Directory: t.TempDir(), Prefix: "my-job/"})
+
+	// When
+	_, err := store.Get(ctx, "checkpoints/latest.json")
+
+	// Then
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// When
+	err = store.Put(ctx, "checkpoints/latest.json", []byte(`{"stage": 3}`))
+	require.NoError(t, err)
+	data, err := store.Get(ctx, "checkpoints/latest.json")
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, `{"stage": 3}`, string(data))
+}
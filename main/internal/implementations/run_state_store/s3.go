@@ -0,0 +1,91 @@
+package runStateStore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
+)
+
+// S3RunStateStore implements interfaces.RunStateStore with objects in an S3 bucket, authenticated with the default
+// AWS credential chain.
+type S3RunStateStore struct {
+	config Config
+
+	// s3Client is the client used to send s3 requests.
+	s3Client s3iface.S3API
+}
+
+// NewS3RunStateStore creates an instance of S3RunStateStore.
+func NewS3RunStateStore(config Config) (interfaces.RunStateStore, error) {
+	if config.S3Bucket == "" {
+		return nil, fmt.Errorf("[s3_run_state_store][a bucket name is required]")
+	}
+
+	newSession, err := session.NewSession(aws.NewConfig().WithRegion(config.AWSRegion))
+	if err != nil {
+		return nil, fmt.Errorf("[s3_run_state_store][session.NewSession]%w", err)
+	}
+
+	return &S3RunStateStore{config: config, s3Client: s3.New(newSession)}, nil
+}
+
+// Get returns the data stored under key.
+func (s *S3RunStateStore) Get(ctx context.Context, key string) ([]byte, error) {
+	output, err := s.s3Client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.config.S3Bucket),
+		Key:    aws.String(s.config.Prefix + key),
+	})
+	if err != nil {
+		var awsErr awserr.Error
+		if errors.As(err, &awsErr) && awsErr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, fmt.Errorf("[s3_run_state_store][get %v]%w", key, ErrNotFound)
+		}
+		if isS3AccessDenied(err) {
+			return nil, fmt.Errorf(
+				"[s3_run_state_store][get %v][access denied to bucket %v, the job's AWS identity requires s3:GetObject, "+
+					"and s3:ListBucket for missing run state to be reported as not found]%w", key, s.config.S3Bucket, err,
+			)
+		}
+		return nil, fmt.Errorf("[s3_run_state_store][get %v]%w", key, err)
+	}
+	defer output.Body.Close()
+
+	data, err := io.ReadAll(output.Body)
+	if err != nil {
+		return nil, fmt.Errorf("[s3_run_state_store][get %v][io.ReadAll]%w", key, err)
+	}
+	return data, nil
+}
+
+// Put stores data under key, replacing any existing object.
+func (s *S3RunStateStore) Put(ctx context.Context, key string, data []byte) error {
+	_, err := s.s3Client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.config.S3Bucket),
+		Key:    aws.String(s.config.Prefix + key),
+		Body:   bytes.NewReader(data),
+	})
+	if err != nil {
+		if isS3AccessDenied(err) {
+			return fmt.Errorf("[s3_run_state_store][put %v][access denied to bucket %v, the job's AWS identity requires s3:PutObject]%w", key, s.config.S3Bucket, err)
+		}
+		return fmt.Errorf("[s3_run_state_store][put %v]%w", key, err)
+	}
+	return nil
+}
+
+// isS3AccessDenied returns true if err is an S3 request denied by the bucket's or the identity's policies. Without
+// s3:ListBucket, reading a missing object is also denied rather than reported as missing.
+func isS3AccessDenied(err error) bool {
+	var awsErr awserr.Error
+	return errors.As(err, &awsErr) && awsErr.Code() == "AccessDenied"
+}
@@ -0,0 +1,53 @@
+package runStateStore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockS3Client fails every request with err.
+type mockS3Client struct {
+	s3iface.S3API
+	err error
+}
+
+func (m *mockS3Client) GetObjectWithContext(ctx aws.Context, input *s3.GetObjectInput, options ...request.Option) (*s3.GetObjectOutput, error) {
+	return nil, m.err
+}
+
+func (m *mockS3Client) PutObjectWithContext(ctx aws.Context, input *s3.PutObjectInput, options ...request.Option) (*s3.PutObjectOutput, error) {
+	return nil, m.err
+}
+
+func TestS3RunStateStore_Errors(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	store := &S3RunStateStore{config: Config{S3Bucket: "run-state"}}
+
+	// When
+	store.s3Client = &mockS3Client{err: awserr.New(s3.ErrCodeNoSuchKey, "The specified key does not exist.", nil)}
+	_, err := store.Get(ctx, "summary.json")
+
+	// Then
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// When
+	store.s3Client = &mockS3Client{err: awserr.New("AccessDenied", "Access Denied", nil)}
+	_, getErr := store.Get(ctx, "summary.json")
+	putErr := store.Put(ctx, "summary.json", []byte("{}"))
+
+	// Then
+	require.Error(t, getErr)
+	assert.NotErrorIs(t, getErr, ErrNotFound)
+	assert.Contains(t, getErr.Error(), "access denied to bucket run-state, the job's AWS identity requires s3:GetObject")
+	require.Error(t, putErr)
+	assert.Contains(t, putErr.Error(), "access denied to bucket run-state, the job's AWS identity requires s3:PutObject")
+}
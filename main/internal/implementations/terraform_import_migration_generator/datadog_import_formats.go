@@ -0,0 +1,7 @@
+package terraformImportMigrationGenerator
+
+// DatadogDefaultLocation is the import location of Datadog resources, which are imported by their id.
+var DatadogDefaultLocation = ImportLocationFormat{
+	StringFormat: "$0",
+	Attributes:   []string{"id"},
+}
@@ -0,0 +1,7 @@
+package terraformImportMigrationGenerator
+
+// DigitalOceanDefaultLocation is the import location of DigitalOcean resources, which are imported by their id.
+var DigitalOceanDefaultLocation = ImportLocationFormat{
+	StringFormat: "$0",
+	Attributes:   []string{"id"},
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
 	"github.com/stretchr/testify/assert"
 
@@ -20,7 +21,7 @@ func TestCreateIsolatedTerraformImportMigrationGenerator(t *testing.T) {
 	divisionToProvider := make(map[terraformValueObjects.Division]terraformValueObjects.Provider)
 
 	// When
-	terraformImporter, err := terraformImporterFactory.Instantiate(ctx, terraformImporterProvider, dragonDrop, divisionToProvider, config)
+	terraformImporter, err := terraformImporterFactory.Instantiate(ctx, terraformImporterProvider, dragonDrop, divisionToProvider, config, artifacts.NewMemoryStore(true))
 
 	// Then
 	assert.Nil(t, err)
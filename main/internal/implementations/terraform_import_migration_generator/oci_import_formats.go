@@ -0,0 +1,7 @@
+package terraformImportMigrationGenerator
+
+// OCIDefaultLocation is the import location of OCI resources, which are imported by their OCID.
+var OCIDefaultLocation = ImportLocationFormat{
+	StringFormat: "$0",
+	Attributes:   []string{"id"},
+}
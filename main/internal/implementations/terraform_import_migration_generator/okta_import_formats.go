@@ -0,0 +1,36 @@
+package terraformImportMigrationGenerator
+
+// OktaDefaultLocation is the import location of Okta resources, which are imported by their id.
+var OktaDefaultLocation = ImportLocationFormat{
+	StringFormat: "$0",
+	Attributes:   []string{"id"},
+}
+
+// OktaResourceTypeLocations are the import locations of Okta resources nested within an authorization server or a
+// policy. Policy rules record their policy as policy_id, or as policyid in earlier provider versions.
+var OktaResourceTypeLocations = map[ResourceType]ImportLocationFormat{
+	"okta_auth_server_claim": {
+		StringFormat: "$0/$1",
+		Attributes:   []string{"auth_server_id", "id"},
+	},
+	"okta_auth_server_policy": {
+		StringFormat: "$0/$1",
+		Attributes:   []string{"auth_server_id", "id"},
+	},
+	"okta_auth_server_scope": {
+		StringFormat: "$0/$1",
+		Attributes:   []string{"auth_server_id", "id"},
+	},
+	"okta_policy_rule_mfa": {
+		StringFormat: "$0/$1",
+		Attributes:   []string{"policy_id|policyid", "id"},
+	},
+	"okta_policy_rule_password": {
+		StringFormat: "$0/$1",
+		Attributes:   []string{"policy_id|policyid", "id"},
+	},
+	"okta_policy_rule_signon": {
+		StringFormat: "$0/$1",
+		Attributes:   []string{"policy_id|policyid", "id"},
+	},
+}
@@ -3,10 +3,9 @@ package terraformImportMigrationGenerator
 import (
 	"context"
 	"fmt"
-	"io/ioutil"
-	"os"
 
 	"github.com/Jeffail/gabs/v2"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
 )
@@ -30,13 +29,16 @@ type TerraformImportMigrationGenerator struct {
 
 	// config contains the variables that determine the specific behavior of the TerraformImportMigrationGenerator struct.
 	config Config
+
+	// artifactStore holds the mappings read and written by the generator.
+	artifactStore artifacts.Store
 }
 
 // NewTerraformImportMigrationGenerator creates and returns a new instance of TerraformImportMigrationGenerator
-func NewTerraformImportMigrationGenerator(ctx context.Context, config Config, dragonDrop interfaces.DragonDrop, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider) interfaces.TerraformImportMigrationGenerator {
+func NewTerraformImportMigrationGenerator(ctx context.Context, config Config, dragonDrop interfaces.DragonDrop, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider, artifactStore artifacts.Store) interfaces.TerraformImportMigrationGenerator {
 	dragonDrop.PostLog(ctx, "Created TFImport client.")
 
-	return &TerraformImportMigrationGenerator{config: config, dragonDrop: dragonDrop, divisionToProvider: divisionToProvider}
+	return &TerraformImportMigrationGenerator{config: config, dragonDrop: dragonDrop, divisionToProvider: divisionToProvider, artifactStore: artifactStore}
 }
 
 // Execute generates terraform state migration statements for identified resources.
@@ -67,34 +69,11 @@ func (i *TerraformImportMigrationGenerator) Execute(ctx context.Context) error {
 	return nil
 }
 
-// writeResourcesMap writes a json file for the resource Import Map.
+// writeResourcesMap writes a json file for the resource Import Map within the working directory of the job.
 func (i *TerraformImportMigrationGenerator) writeResourcesMap(resourceImportMapJSON string) error {
-	err := os.Chdir("/")
-	if err != nil {
-		return fmt.Errorf("[map_resources][os.Chdir(/)]%w", err)
-	}
-
-	// Check to see if running locally and move into the docker volume directory if necessary
-	files, err := ioutil.ReadDir("./")
-	if err != nil {
-		return fmt.Errorf("[map_resources][ioutil.ReadDir('./')]%w", err)
-	}
-
-	for _, f := range files {
-		fmt.Println(f.Name())
-		if f.Name() == "main" {
-			err = os.Chdir("/main")
-			if err != nil {
-				return fmt.Errorf("[map_resources][os.Chdir(/main)]%w", err)
-			}
-			break
-		}
-	}
-
-	_ = os.MkdirAll("mappings", 0660)
-	err = os.WriteFile("mappings/resources-to-import-location.json", []byte(resourceImportMapJSON), 0400)
+	err := i.artifactStore.WriteFile("mappings/resources-to-import-location.json", []byte(resourceImportMapJSON), 0400)
 	if err != nil {
-		return fmt.Errorf("[map_resources][os.WriteFile(resources-to-import-location.json]%w", err)
+		return fmt.Errorf("[map_resources][artifacts.WriteFile(resources-to-import-location.json]%w", err)
 	}
 
 	return nil
@@ -12,7 +12,9 @@ import (
 // ResourceType is the type of the terraform cloud resource
 type ResourceType string
 
-// ImportLocationFormat is the format which is applied to the import statement
+// ImportLocationFormat is the format which is applied to the import statement. Each attribute substitutes the
+// placeholder of its index, and may list alternative attribute names separated by "|", of which the first set
+// within the resource's state is used, e.g. for attributes renamed between provider versions.
 type ImportLocationFormat struct {
 	StringFormat string
 	Attributes   []string
@@ -22,15 +24,36 @@ type ImportLocationFormat struct {
 var providerResourceLocationFormats = map[terraformValueObjects.Provider]map[ResourceType]ImportLocationFormat{
 	"aws":    ResourceTypeLocations,
 	"google": GoogleResourceTypeLocations,
+	"okta":   OktaResourceTypeLocations,
+}
+
+// providerDefaultLocationFormats are the format rules applied to resource types of a provider without a specific rule
+var providerDefaultLocationFormats = map[terraformValueObjects.Provider]ImportLocationFormat{
+	"datadog":      DatadogDefaultLocation,
+	"digitalocean": DigitalOceanDefaultLocation,
+	"oci":          OCIDefaultLocation,
+	"okta":         OktaDefaultLocation,
 }
 
 // GetRemoteCloudReference extracts the formatted string from the resources json
 func GetRemoteCloudReference(resource *gabs.Container, provider terraformValueObjects.Provider, resourceType ResourceType) (string, error) {
-	format := providerResourceLocationFormats[provider][resourceType]
+	format, ok := providerResourceLocationFormats[provider][resourceType]
+	if !ok {
+		format = providerDefaultLocationFormats[provider]
+	}
 	formattedString := format.StringFormat
 
 	for i, attribute := range format.Attributes {
-		value := resource.Path(fmt.Sprintf("instances.0.attributes_flat.%s", attribute)).Data().(string)
+		value := ""
+		for _, alternative := range strings.Split(attribute, "|") {
+			value, _ = resource.Path(fmt.Sprintf("instances.0.attributes_flat.%s", alternative)).Data().(string)
+			if value != "" {
+				break
+			}
+		}
+		if value == "" {
+			return "", fmt.Errorf("[get_remote_cloud_reference][%v has no %v attribute]", resourceType, attribute)
+		}
 		formattedString = strings.Replace(formattedString, fmt.Sprintf("$%d", i), value, -1)
 	}
 
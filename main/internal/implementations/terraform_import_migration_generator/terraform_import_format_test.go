@@ -1,6 +1,7 @@
 package terraformImportMigrationGenerator
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/Jeffail/gabs/v2"
@@ -37,6 +38,89 @@ func TestGetResourceLocationFormatted_GCP_StorageBucket(t *testing.T) {
 	assert.Equal(t, "example-project/dragondrop-example-2", resourceFormatted)
 }
 
+func TestGetResourceLocationFormatted_Datadog_Monitor(t *testing.T) {
+	// Given
+	provider := terraformValueObjects.Provider("datadog")
+	resourceType := ResourceType("datadog_monitor")
+	resourcesJSON := []byte(`{
+		"name": "tfer--monitor_12345",
+		"instances": [
+			{
+				"attributes_flat": {
+					"id": "12345",
+					"name": "High CPU usage"
+				}
+			}
+		]
+	}`)
+	resourcesParsed, err := gabs.ParseJSON(resourcesJSON)
+	assert.Nil(t, err)
+
+	// When
+	resourceFormatted, err := GetRemoteCloudReference(resourcesParsed, provider, resourceType)
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, "12345", resourceFormatted)
+}
+
+func TestGetResourceLocationFormatted_Okta_AuthServerScope(t *testing.T) {
+	// Given
+	provider := terraformValueObjects.Provider("okta")
+	resourceType := ResourceType("okta_auth_server_scope")
+	resourcesJSON := []byte(`{
+		"name": "tfer--scope_read",
+		"instances": [
+			{
+				"attributes_flat": {
+					"auth_server_id": "aus1abcd",
+					"id": "scp1efgh",
+					"name": "read"
+				}
+			}
+		]
+	}`)
+	resourcesParsed, err := gabs.ParseJSON(resourcesJSON)
+	assert.Nil(t, err)
+
+	// When
+	resourceFormatted, err := GetRemoteCloudReference(resourcesParsed, provider, resourceType)
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, "aus1abcd/scp1efgh", resourceFormatted)
+}
+
+func TestGetResourceLocationFormatted_Okta_PolicyRules(t *testing.T) {
+	// Given
+	provider := terraformValueObjects.Provider("okta")
+	attributes := map[string]string{
+		"okta_policy_rule_mfa":      `"policy_id": "00p1abcd", "id": "0pr1efgh"`,
+		"okta_policy_rule_password": `"policyid": "00p1abcd", "id": "0pr1efgh"`,
+		"okta_policy_rule_signon":   `"policy_id": "", "policyid": "00p1abcd", "id": "0pr1efgh"`,
+	}
+
+	for resourceType, resourceAttributes := range attributes {
+		resourcesParsed, err := gabs.ParseJSON([]byte(fmt.Sprintf(`{"instances": [{"attributes_flat": {%v}}]}`, resourceAttributes)))
+		assert.Nil(t, err)
+
+		// When
+		resourceFormatted, err := GetRemoteCloudReference(resourcesParsed, provider, ResourceType(resourceType))
+
+		// Then
+		assert.Nil(t, err, resourceType)
+		assert.Equal(t, "00p1abcd/0pr1efgh", resourceFormatted, resourceType)
+	}
+
+	// When
+	resourcesParsed, err := gabs.ParseJSON([]byte(`{"instances": [{"attributes_flat": {"id": "0pr1efgh"}}]}`))
+	assert.Nil(t, err)
+	_, err = GetRemoteCloudReference(resourcesParsed, provider, "okta_policy_rule_mfa")
+
+	// Then
+	assert.ErrorContains(t, err, "okta_policy_rule_mfa has no policy_id|policyid attribute")
+}
+
 func TestGetResourceLocationFormatted_GCP_Resources(t *testing.T) {
 	type args struct {
 		provider      string
@@ -0,0 +1,47 @@
+package driftDetector
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/baseline"
+)
+
+// filterAcceptedDifferences removes the attribute differences accepted within the repository's baseline.
+func (m *ManagedResourcesDriftDetector) filterAcceptedDifferences(differences []AttributeDifference, accepted *baseline.Baseline) []AttributeDifference {
+	if accepted.IsEmpty() {
+		return differences
+	}
+
+	filtered := make([]AttributeDifference, 0, len(differences))
+	for _, difference := range differences {
+		// Drift may be accepted for all instances of a resource, or for a single count or for_each instance.
+		stateFile := string(difference.StateFileName)
+		address := resourceAddress(difference.ModuleName, difference.ResourceType, difference.ResourceName)
+		if accepted.AcceptsDrift(stateFile, address, difference.InstanceID, difference.AttributeName, difference.CloudValue) ||
+			accepted.AcceptsDrift(stateFile, difference.InstanceAddress(), difference.InstanceID, difference.AttributeName, difference.CloudValue) {
+			continue
+		}
+		filtered = append(filtered, difference)
+	}
+
+	log.Infof("[drift_detector] suppressed %v drifted attributes accepted within the baseline", len(differences)-len(filtered))
+	return filtered
+}
+
+// filterAcceptedDeletedResources removes the deleted resources accepted within the repository's baseline.
+func (m *ManagedResourcesDriftDetector) filterAcceptedDeletedResources(deleted []DeletedResource, accepted *baseline.Baseline) []DeletedResource {
+	if accepted.IsEmpty() {
+		return deleted
+	}
+
+	filtered := make([]DeletedResource, 0, len(deleted))
+	for _, resource := range deleted {
+		if accepted.AcceptsDeleted(string(resource.StateFileName), resource.ResourceAddress) {
+			continue
+		}
+		filtered = append(filtered, resource)
+	}
+
+	log.Infof("[drift_detector] suppressed %v deleted resources accepted within the baseline", len(deleted)-len(filtered))
+	return filtered
+}
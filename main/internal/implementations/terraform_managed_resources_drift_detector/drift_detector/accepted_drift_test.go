@@ -0,0 +1,42 @@
+package driftDetector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/baseline"
+)
+
+func TestFilterAcceptedDifferences(t *testing.T) {
+	// Given
+	detector := &ManagedResourcesDriftDetector{}
+	acceptedValue := "3"
+	accepted := &baseline.Baseline{
+		Drift: []baseline.AcceptedDrift{
+			{StateFile: "compute", Address: "aws_autoscaling_group.web", Attribute: "desired_capacity", Value: &acceptedValue},
+		},
+		Deleted: []baseline.AcceptedDeleted{
+			{Address: "aws_s3_bucket.logs"},
+		},
+	}
+
+	asgDetail := AttributeDetail{StateFileName: "compute", ModuleName: "root", ResourceType: "aws_autoscaling_group", ResourceName: "web"}
+	differences := []AttributeDifference{
+		{AttributeName: "desired_capacity", CloudValue: "3", AttributeDetail: asgDetail},
+		{AttributeName: "desired_capacity", CloudValue: "5", AttributeDetail: asgDetail},
+		{AttributeName: "max_size", CloudValue: "3", AttributeDetail: asgDetail},
+	}
+	deleted := []DeletedResource{
+		{StateFileName: "storage", ResourceAddress: "aws_s3_bucket.logs"},
+		{StateFileName: "storage", ResourceAddress: "aws_s3_bucket.assets"},
+	}
+
+	// When
+	filteredDifferences := detector.filterAcceptedDifferences(differences, accepted)
+	filteredDeleted := detector.filterAcceptedDeletedResources(deleted, accepted)
+
+	// Then
+	assert.Equal(t, differences[1:], filteredDifferences)
+	assert.Equal(t, deleted[1:], filteredDeleted)
+}
@@ -0,0 +1,96 @@
+package driftDetector
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// ResourceAttributeDiff is the attribute level before and after diff of a single drifted resource instance.
+type ResourceAttributeDiff struct {
+	StateFileName     StateFileName
+	CloudDivision     string
+	ResourceAddress   string
+	ModuleName        string
+	ResourceType      string
+	ResourceName      string
+	ModuleSource      string `json:",omitempty"`
+	ModuleCallingFile string `json:",omitempty"`
+	InstanceID        string
+	RemediationImpact RemediationImpact
+	Attributes        []AttributeDiff
+}
+
+// AttributeDiff is the before, within the Terraform state, and after, within the cloud, value of a drifted attribute.
+type AttributeDiff struct {
+	AttributeName     string
+	Before            string
+	After             string
+	ChangeType        AttributeChangeType
+	ForcesReplacement bool
+	Sensitive         bool   `json:",omitempty"`
+	ValueType         string `json:",omitempty"`
+}
+
+// buildAttributeDiffs groups the drifted attributes by resource instance, ordering both the resource instances and
+// their attributes deterministically.
+func buildAttributeDiffs(differences []AttributeDifference) []ResourceAttributeDiff {
+	keyToDiff := map[string]*ResourceAttributeDiff{}
+	keys := make([]string, 0)
+
+	for _, difference := range differences {
+		address := difference.InstanceAddress()
+		key := fmt.Sprintf("%v/%v/%v", difference.StateFileName, address, difference.InstanceID)
+
+		diff, ok := keyToDiff[key]
+		if !ok {
+			diff = &ResourceAttributeDiff{
+				StateFileName:     difference.StateFileName,
+				CloudDivision:     difference.CloudDivision,
+				ResourceAddress:   address,
+				ModuleName:        difference.ModuleName,
+				ResourceType:      difference.ResourceType,
+				ResourceName:      difference.ResourceName,
+				ModuleSource:      difference.ModuleSource,
+				ModuleCallingFile: difference.ModuleCallingFile,
+				InstanceID:        difference.InstanceID,
+				RemediationImpact: difference.RemediationImpact,
+				Attributes:        []AttributeDiff{},
+			}
+			keyToDiff[key] = diff
+			keys = append(keys, key)
+		}
+
+		diff.Attributes = append(diff.Attributes, AttributeDiff{
+			AttributeName:     difference.AttributeName,
+			Before:            difference.TerraformValue,
+			After:             difference.CloudValue,
+			ChangeType:        difference.ChangeType,
+			ForcesReplacement: difference.ForcesReplacement,
+			Sensitive:         difference.Sensitive,
+			ValueType:         difference.ValueType,
+		})
+	}
+
+	sort.Strings(keys)
+	output := make([]ResourceAttributeDiff, 0, len(keys))
+	for _, key := range keys {
+		diff := keyToDiff[key]
+		sort.Slice(diff.Attributes, func(i, j int) bool {
+			return diff.Attributes[i].AttributeName < diff.Attributes[j].AttributeName
+		})
+		output = append(output, *diff)
+	}
+
+	return output
+}
+
+// writeAttributeDiffs writes within a json file the attribute level diff of each drifted resource instance.
+func (m *ManagedResourcesDriftDetector) writeAttributeDiffs(differences []AttributeDifference) error {
+	diffsJSON, err := json.MarshalIndent(buildAttributeDiffs(differences), "", "  ")
+	if err != nil {
+		return fmt.Errorf("[json.MarshalIndent]%w", err)
+	}
+
+	return m.artifactStore.WriteFile("mappings/drift-attribute-diffs.json", diffsJSON, 0400)
+}
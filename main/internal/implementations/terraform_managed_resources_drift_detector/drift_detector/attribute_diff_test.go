@@ -0,0 +1,75 @@
+package driftDetector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeAttributeValue(t *testing.T) {
+	// Given
+	pairs := [][2]string{
+		{"1", "1.0"},
+		{"100", "1e2"},
+		{"0.10", ".1"},
+		{"true", "True"},
+		{`{"b": 1, "a": [1, 2]}`, `{"a":[1,2],"b":1}`},
+	}
+
+	// When / Then
+	for _, pair := range pairs {
+		assert.True(t, attributeValuesEqual(pair[0], pair[1]), pair)
+	}
+	assert.False(t, attributeValuesEqual("1", "2"))
+	assert.False(t, attributeValuesEqual("abc", "ABC"))
+	assert.False(t, attributeValuesEqual("12345678901234567890", "12345678901234567891"))
+	assert.False(t, attributeValuesEqual("0.1000000000000000000001", "0.1"))
+	assert.False(t, attributeValuesEqual(`{"id": 12345678901234567890}`, `{"id": 12345678901234567891}`))
+	assert.False(t, attributeValuesEqual("0x10", "16"))
+	assert.True(t, isEmptyAttributeValue("{}"))
+	assert.False(t, isEmptyAttributeValue("0"))
+}
+
+func TestBuildAttributeDiffs(t *testing.T) {
+	// Given
+	vpcDetail := AttributeDetail{StateFileName: "network", CloudDivision: "prod", ModuleName: "module.net", ResourceType: "aws_vpc", ResourceName: "main"}
+	bucketDetail := AttributeDetail{StateFileName: "storage", CloudDivision: "prod", ModuleName: "root", ResourceType: "aws_s3_bucket", ResourceName: "logs"}
+
+	differences := []AttributeDifference{
+		{AttributeName: "tags.env", TerraformValue: "dev", CloudValue: "prod", InstanceID: "vpc-1", ChangeType: AttributeChangeUpdated, AttributeDetail: vpcDetail},
+		{AttributeName: "acl", TerraformValue: "private", InstanceID: "logs", ChangeType: AttributeChangeRemoved, AttributeDetail: bucketDetail},
+		{AttributeName: "cidr_block", TerraformValue: "10.0.0.0/16", CloudValue: "10.1.0.0/16", InstanceID: "vpc-1", ChangeType: AttributeChangeUpdated, ForcesReplacement: true, AttributeDetail: vpcDetail},
+	}
+
+	// When
+	output := buildAttributeDiffs(differences)
+
+	// Then
+	assert.Equal(t, []ResourceAttributeDiff{
+		{
+			StateFileName:   "network",
+			CloudDivision:   "prod",
+			ResourceAddress: "module.net.aws_vpc.main",
+			ModuleName:      "module.net",
+			ResourceType:    "aws_vpc",
+			ResourceName:    "main",
+			InstanceID:      "vpc-1",
+			Attributes: []AttributeDiff{
+				{AttributeName: "cidr_block", Before: "10.0.0.0/16", After: "10.1.0.0/16", ChangeType: AttributeChangeUpdated, ForcesReplacement: true},
+				{AttributeName: "tags.env", Before: "dev", After: "prod", ChangeType: AttributeChangeUpdated},
+			},
+		},
+		{
+			StateFileName:   "storage",
+			CloudDivision:   "prod",
+			ResourceAddress: "aws_s3_bucket.logs",
+			ModuleName:      "root",
+			ResourceType:    "aws_s3_bucket",
+			ResourceName:    "logs",
+			InstanceID:      "logs",
+			Attributes: []AttributeDiff{
+				{AttributeName: "acl", Before: "private", ChangeType: AttributeChangeRemoved},
+			},
+		},
+	}, output)
+}
@@ -0,0 +1,80 @@
+package driftDetector
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// AttributeChangeType describes how a drifted attribute differs between the Terraform state and the cloud.
+type AttributeChangeType string
+
+const (
+	// AttributeChangeUpdated indicates that the attribute has a different value within the cloud.
+	AttributeChangeUpdated AttributeChangeType = "updated"
+
+	// AttributeChangeAdded indicates that the attribute is set within the cloud but not within the Terraform state.
+	AttributeChangeAdded AttributeChangeType = "added"
+
+	// AttributeChangeRemoved indicates that the attribute is set within the Terraform state but not within the cloud.
+	AttributeChangeRemoved AttributeChangeType = "removed"
+)
+
+// decimalNumberRegex matches decimal numbers, with an exponent of at most three digits so that parsing stays cheap.
+var decimalNumberRegex = regexp.MustCompile(`^[-+]?(\d+\.?\d*|\.\d+)([eE][-+]?\d{1,3})?$`)
+
+// normalizeAttributeValue returns a canonical representation of a flat attribute value, so that values which are
+// equal once their type is considered compare as equal. Decimal numbers are compared exactly as rationals, so that
+// large integer ids and high precision values never collide, booleans are lower cased, and JSON documents are
+// re-encoded with sorted keys and without insignificant whitespace, keeping their numbers verbatim. IAM policy
+// documents are additionally rewritten so that semantically equivalent policies compare as equal.
+func normalizeAttributeValue(value string) string {
+	trimmed := strings.TrimSpace(value)
+
+	switch strings.ToLower(trimmed) {
+	case "true", "false":
+		return strings.ToLower(trimmed)
+	}
+
+	if decimalNumberRegex.MatchString(trimmed) {
+		if number, ok := new(big.Rat).SetString(trimmed); ok {
+			return number.RatString()
+		}
+	}
+
+	trimmed = decodePolicyDocument(trimmed)
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		var document interface{}
+		decoder := json.NewDecoder(bytes.NewReader([]byte(trimmed)))
+		decoder.UseNumber()
+		if err := decoder.Decode(&document); err == nil && !decoder.More() {
+			if isPolicyDocument(document) {
+				document = normalizePolicyDocument(document.(map[string]interface{}))
+			}
+
+			canonical, err := json.Marshal(document)
+			if err == nil {
+				return string(canonical)
+			}
+		}
+	}
+
+	return value
+}
+
+// attributeValuesEqual returns true if two flat attribute values are equal once normalized.
+func attributeValuesEqual(terraformValue string, cloudValue string) bool {
+	return terraformValue == cloudValue || normalizeAttributeValue(terraformValue) == normalizeAttributeValue(cloudValue)
+}
+
+// isEmptyAttributeValue returns true for values equivalent to an unset attribute.
+func isEmptyAttributeValue(value string) bool {
+	switch normalizeAttributeValue(value) {
+	case "", "{}", "[]", "null":
+		return true
+	default:
+		return false
+	}
+}
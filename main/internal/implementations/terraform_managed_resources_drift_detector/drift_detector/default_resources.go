@@ -0,0 +1,135 @@
+package driftDetector
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/rulelist"
+)
+
+// builtInDefaultResourceRules match the resources that cloud providers create by default or on behalf of their
+// services, which are rarely worth codifying and otherwise flood the unmanaged resources.
+var builtInDefaultResourceRules = []string{
+	// AWS default VPCs and their subnets, default security groups, and service-linked or AWS managed resources.
+	"aws_vpc:cidr_block=172.31.0.0/16&tags.%=0",
+	"aws_subnet:cidr_block=172.31.*.0/20&map_public_ip_on_launch=true&tags.%=0",
+	"aws_security_group:name=default",
+	"aws_iam_role:path=/aws-service-role/*",
+	"aws_iam_policy:arn=arn:aws:iam::aws:policy/*",
+	"aws_kms_alias:name=alias/aws/*",
+	"aws_db_parameter_group:name=default.*",
+	"aws_db_option_group:name=default:*",
+	"aws_elasticache_parameter_group:name=default.*",
+	"aws_cloudwatch_event_bus:name=default",
+
+	// GCP default networks and firewall rules, and the default compute and App Engine service accounts.
+	"google_compute_network:name=default",
+	"google_compute_subnetwork:network=*/networks/default",
+	"google_compute_firewall:name=default-allow-*",
+	"google_compute_route:name=default-route-*",
+	"google_service_account:email=*-compute@developer.gserviceaccount.com",
+	"google_service_account:email=*@appspot.gserviceaccount.com",
+
+	// Azure resources created automatically for Network Watcher and Defender for Cloud.
+	"azurerm_resource_group:name=NetworkWatcherRG",
+	"azurerm_network_watcher:name=NetworkWatcher_*",
+	"azurerm_resource_group:name=DefaultResourceGroup-*",
+	"azurerm_log_analytics_workspace:name=DefaultWorkspace-*",
+}
+
+// DefaultResourceCondition matches a flat attribute against a wildcard pattern, in which * matches any characters.
+type DefaultResourceCondition struct {
+	Attribute string
+	Pattern   *regexp.Regexp
+}
+
+// DefaultResourceRule matches resources of types matching TypePattern whose attributes meet all Conditions. Rules
+// prefixed by ! are negated, including the matched resources rather than excluding them.
+type DefaultResourceRule struct {
+	Negated     bool
+	TypePattern *regexp.Regexp
+	Conditions  []DefaultResourceCondition
+}
+
+// DefaultResourcePolicy is an ordered list of rules for cloud default resources, in which the last matching rule
+// decides whether a resource is excluded.
+type DefaultResourcePolicy []DefaultResourceRule
+
+// ParseDefaultResourcePolicy parses the built-in rules followed by the user-provided rules, which are of the form
+// "<type>:<attribute>=<pattern>&<attribute>=<pattern>", or "<type>" to match all resources of a type. User rules
+// prefixed by ! override the built-in rules, e.g. "!aws_security_group" or "!*" to disable them all.
+func ParseDefaultResourcePolicy(rules []string) (DefaultResourcePolicy, error) {
+	parsedRules, err := rulelist.Parse(builtInDefaultResourceRules, rules)
+	if err != nil {
+		return nil, fmt.Errorf("[parse_default_resource_policy]%w", err)
+	}
+
+	policy := make(DefaultResourcePolicy, 0, len(parsedRules))
+	for _, rule := range parsedRules {
+		parsedRule := DefaultResourceRule{Negated: rule.Negated}
+
+		resourceType, conditions, _ := strings.Cut(rule.Spec, ":")
+		if resourceType == "" {
+			return nil, fmt.Errorf("[parse_default_resource_policy][rule %v has no resource type]", rule.Spec)
+		}
+		parsedRule.TypePattern = rulelist.Wildcard(resourceType)
+
+		if conditions != "" {
+			for _, condition := range strings.Split(conditions, "&") {
+				attribute, pattern, found := strings.Cut(condition, "=")
+				if !found || attribute == "" {
+					return nil, fmt.Errorf("[parse_default_resource_policy][condition %v of rule %v is not of the form <attribute>=<pattern>]", condition, rule.Spec)
+				}
+				parsedRule.Conditions = append(parsedRule.Conditions, DefaultResourceCondition{
+					Attribute: attribute,
+					Pattern:   rulelist.Wildcard(pattern),
+				})
+			}
+		}
+
+		policy = append(policy, parsedRule)
+	}
+
+	return policy, nil
+}
+
+// IsDefaultResource returns true if the last rule matching the resource excludes it as a cloud default.
+func (p DefaultResourcePolicy) IsDefaultResource(resourceType string, attributesFlat map[string]string) bool {
+	return rulelist.LastMatch(len(p), func(i int) (bool, bool) {
+		return p[i].matches(resourceType, attributesFlat), p[i].Negated
+	})
+}
+
+// matches returns true if the resource is of a matching type and its attributes meet all conditions of the rule.
+func (r DefaultResourceRule) matches(resourceType string, attributesFlat map[string]string) bool {
+	if !r.TypePattern.MatchString(resourceType) {
+		return false
+	}
+
+	for _, condition := range r.Conditions {
+		value, ok := attributesFlat[condition.Attribute]
+		if !ok && condition.Attribute == "tags.%" {
+			// Resources without any tags may omit the tag count entirely.
+			value, ok = "0", true
+		}
+		if !ok || !condition.Pattern.MatchString(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// excludeDefaultResources removes the cloud default resources absent from the Terraform state from the terraformer
+// resources. Default resources that are managed within a workspace, e.g. an imported default VPC, are kept so that
+// their drift is still detected.
+func (m *ManagedResourcesDriftDetector) excludeDefaultResources(terraformerResources TerraformerResourceIDToData, terraformResources TerraformStateResourceIDToData) {
+	for id, data := range terraformerResources {
+		if _, isManaged := terraformResources[id]; isManaged {
+			continue
+		}
+		if m.defaultResourcePolicy.IsDefaultResource(data.Type, data.AttributesFlat) {
+			delete(terraformerResources, id)
+		}
+	}
+}
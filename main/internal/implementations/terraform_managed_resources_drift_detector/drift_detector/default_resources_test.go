@@ -0,0 +1,85 @@
+package driftDetector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultResourcePolicy_IsDefaultResource(t *testing.T) {
+	// Given
+	policy, err := ParseDefaultResourcePolicy([]string{"aws_iam_role:path=/service-role/*", "!google_compute_network"})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name           string
+		resourceType   string
+		attributesFlat map[string]string
+		isDefault      bool
+	}{
+		{"default vpc", "aws_vpc", map[string]string{"cidr_block": "172.31.0.0/16"}, true},
+		{"tagged vpc with the default cidr", "aws_vpc", map[string]string{"cidr_block": "172.31.0.0/16", "tags.%": "1", "tags.Name": "main"}, false},
+		{"default subnet", "aws_subnet", map[string]string{"cidr_block": "172.31.32.0/20", "map_public_ip_on_launch": "true", "tags.%": "0"}, true},
+		{"default security group", "aws_security_group", map[string]string{"name": "default"}, true},
+		{"service-linked role", "aws_iam_role", map[string]string{"path": "/aws-service-role/elasticloadbalancing.amazonaws.com/"}, true},
+		{"user rule", "aws_iam_role", map[string]string{"path": "/service-role/"}, true},
+		{"user role", "aws_iam_role", map[string]string{"path": "/"}, false},
+		{"overridden built-in rule", "google_compute_network", map[string]string{"name": "default"}, false},
+		{"default firewall rule", "google_compute_firewall", map[string]string{"name": "default-allow-ssh"}, true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// When
+			isDefault := policy.IsDefaultResource(testCase.resourceType, testCase.attributesFlat)
+
+			// Then
+			assert.Equal(t, testCase.isDefault, isDefault)
+		})
+	}
+}
+
+func TestParseDefaultResourcePolicy_DisableBuiltIn(t *testing.T) {
+	// Given
+	policy, err := ParseDefaultResourcePolicy([]string{"!*"})
+	require.NoError(t, err)
+
+	// When
+	isDefault := policy.IsDefaultResource("aws_security_group", map[string]string{"name": "default"})
+
+	// Then
+	assert.False(t, isDefault)
+
+	// When
+	_, err = ParseDefaultResourcePolicy([]string{"aws_vpc:cidr_block"})
+
+	// Then
+	assert.Error(t, err)
+}
+
+func TestExcludeDefaultResources(t *testing.T) {
+	// Given
+	policy, err := ParseDefaultResourcePolicy(nil)
+	require.NoError(t, err)
+	m := ManagedResourcesDriftDetector{defaultResourcePolicy: policy}
+
+	terraformerResources := TerraformerResourceIDToData{
+		"sg-default":         {Type: "aws_security_group", AttributesFlat: map[string]string{"name": "default"}},
+		"sg-managed-default": {Type: "aws_security_group", AttributesFlat: map[string]string{"name": "default"}},
+		"sg-web":             {Type: "aws_security_group", AttributesFlat: map[string]string{"name": "web"}},
+	}
+	terraformResources := TerraformStateResourceIDToData{
+		"sg-managed-default": {Type: "aws_security_group"},
+		"sg-web":             {Type: "aws_security_group"},
+	}
+
+	// When
+	m.excludeDefaultResources(terraformerResources, terraformResources)
+
+	// Then
+	assert.Len(t, terraformerResources, 2)
+	assert.Contains(t, terraformerResources, "sg-managed-default")
+	assert.Contains(t, terraformerResources, "sg-web")
+	assert.Len(t, terraformResources, 2)
+}
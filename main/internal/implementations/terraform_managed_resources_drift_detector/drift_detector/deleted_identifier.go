@@ -1,31 +1,55 @@
 package driftDetector
 
-// DeletedResource represents a drifted deleted resource
+import (
+	"fmt"
+	"sort"
+)
+
+// DeletedResource represents a drifted deleted resource, i.e. a resource instance within a workspace's state
+// that no longer exists within the cloud.
 type DeletedResource struct {
 	InstanceID    string
 	StateFileName StateFileName
 	ModuleName    string
 	ResourceType  string
 	ResourceName  string
+
+	// ResourceAddress is the address of the resource instance within its state file, e.g.
+	// module.network.aws_vpc.main["primary"], for use in targeted `terraform state rm` or `terraform apply -target`.
+	ResourceAddress string
 }
 
 // identifyDeletedResources identifies the deleted resources from the current TerraformState TerraformStateResourceIDToData
-// compared with the current cloud state obtained with terraformer TerraformerResourceIDToData
+// compared with the current cloud state obtained with terraformer TerraformerResourceIDToData. When the extent of the
+// scan is known, only resources of the types, regions and divisions that were successfully scanned are considered, so
+// that resources which were never scanned, or whose resource group failed to import, are not reported as deleted.
 func (m *ManagedResourcesDriftDetector) identifyDeletedResources(terraformerResources TerraformerResourceIDToData, terraformResources TerraformStateResourceIDToData) ([]DeletedResource, error) {
 	deletedResources := make([]DeletedResource, 0)
+	divisionAccounts := scannedDivisionAccounts(terraformerResources)
 
 	for id, data := range terraformResources {
+		if m.scanScopes != nil && !isWithinScanScope(data, m.scanScopes, divisionAccounts) {
+			continue
+		}
 		if _, ok := terraformerResources[id]; !ok {
 			deletedResource := DeletedResource{
-				InstanceID:    data.Attributes["id"].(string),
-				StateFileName: StateFileName(data.StateFile),
-				ModuleName:    data.Module,
-				ResourceType:  data.Type,
-				ResourceName:  data.Name,
+				InstanceID:      fmt.Sprint(data.Attributes["id"]),
+				StateFileName:   StateFileName(data.StateFile),
+				ModuleName:      data.Module,
+				ResourceType:    data.Type,
+				ResourceName:    data.Name,
+				ResourceAddress: resourceInstanceAddress(data.Module, data.Type, data.Name, data.IndexKey),
 			}
 			deletedResources = append(deletedResources, deletedResource)
 		}
 	}
 
+	sort.Slice(deletedResources, func(i, j int) bool {
+		if deletedResources[i].StateFileName != deletedResources[j].StateFileName {
+			return deletedResources[i].StateFileName < deletedResources[j].StateFileName
+		}
+		return deletedResources[i].ResourceAddress < deletedResources[j].ResourceAddress
+	})
+
 	return deletedResources, nil
 }
@@ -146,10 +146,18 @@ func TestManagedResourcesDriftDetector_identifyDeletedResources_OneDeletedResour
 	require.Len(t, deletedResources, 1)
 
 	require.Contains(t, deletedResources, DeletedResource{
-		InstanceID:    "dragondrop-modules-2",
-		StateFileName: "example2.tfstate",
-		ModuleName:    "module_name",
-		ResourceType:  "google_storage_bucket",
-		ResourceName:  "dragondrop_modules_old",
+		InstanceID:      "dragondrop-modules-2",
+		StateFileName:   "example2.tfstate",
+		ModuleName:      "module_name",
+		ResourceType:    "google_storage_bucket",
+		ResourceName:    "dragondrop_modules_old",
+		ResourceAddress: "module_name.google_storage_bucket.dragondrop_modules_old",
 	})
 }
+
+func TestResourceInstanceAddress(t *testing.T) {
+	// Given / When / Then
+	require.Equal(t, "aws_subnet.private", resourceInstanceAddress("root", "aws_subnet", "private", nil))
+	require.Equal(t, "aws_subnet.private[0]", resourceInstanceAddress("", "aws_subnet", "private", float64(0)))
+	require.Equal(t, `module.network.aws_subnet.private["a"]`, resourceInstanceAddress("module.network", "aws_subnet", "private", "a"))
+}
@@ -0,0 +1,156 @@
+package driftDetector
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// IgnoreRuleKind is what an IgnoreRule's glob pattern is matched against.
+type IgnoreRuleKind string
+
+const (
+	// IgnoreRuleResourceType ignores all drift of resources whose type matches the pattern, e.g. aws_cloudwatch_*.
+	IgnoreRuleResourceType IgnoreRuleKind = "type"
+
+	// IgnoreRuleResourceAddress ignores all drift of resources whose state address matches the pattern,
+	// e.g. module.legacy.*.
+	IgnoreRuleResourceAddress IgnoreRuleKind = "address"
+
+	// IgnoreRuleAttribute ignores drift of the attributes whose path, prefixed by the resource address, matches the
+	// pattern, e.g. aws_autoscaling_group.*.desired_capacity.
+	IgnoreRuleAttribute IgnoreRuleKind = "attribute"
+)
+
+// IgnoreRule is a glob pattern for drift that is expected and should not be reported.
+type IgnoreRule struct {
+	Kind    IgnoreRuleKind
+	Pattern string
+}
+
+// ParseIgnoreRules parses rules of the form "<kind>:<glob>", where kind is one of "type", "address" or "attribute".
+func ParseIgnoreRules(rules []string) ([]IgnoreRule, error) {
+	ignoreRules := make([]IgnoreRule, 0, len(rules))
+
+	for _, rule := range rules {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+
+		kind, pattern, found := strings.Cut(rule, ":")
+		if !found || pattern == "" {
+			return nil, fmt.Errorf("[parse_ignore_rules][rule %v is not of the form <kind>:<glob>]", rule)
+		}
+
+		switch IgnoreRuleKind(kind) {
+		case IgnoreRuleResourceType, IgnoreRuleResourceAddress, IgnoreRuleAttribute:
+		default:
+			return nil, fmt.Errorf("[parse_ignore_rules][rule %v has unknown kind %v, expected type, address or attribute]", rule, kind)
+		}
+
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("[parse_ignore_rules][rule %v has an invalid glob]%w", rule, err)
+		}
+
+		ignoreRules = append(ignoreRules, IgnoreRule{Kind: IgnoreRuleKind(kind), Pattern: pattern})
+	}
+
+	return ignoreRules, nil
+}
+
+// ignoresResource returns true if the rule ignores all drift of the resource.
+func (r IgnoreRule) ignoresResource(resourceType string, addresses ...string) bool {
+	switch r.Kind {
+	case IgnoreRuleResourceType:
+		return globMatches(r.Pattern, resourceType)
+	case IgnoreRuleResourceAddress:
+		return globMatches(r.Pattern, addresses...)
+	default:
+		return false
+	}
+}
+
+// ignoresAttribute returns true if the rule ignores drift of the attribute of the resource.
+func (r IgnoreRule) ignoresAttribute(resourceType string, attribute string, addresses ...string) bool {
+	if r.Kind != IgnoreRuleAttribute {
+		return r.ignoresResource(resourceType, addresses...)
+	}
+
+	attributePaths := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		attributePaths = append(attributePaths, fmt.Sprintf("%v.%v", address, attribute))
+	}
+	return globMatches(r.Pattern, attributePaths...)
+}
+
+// globMatches returns true if any of the values matches the glob pattern.
+func globMatches(pattern string, values ...string) bool {
+	for _, value := range values {
+		if matched, _ := path.Match(pattern, value); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// filterIgnoredDifferences removes the attribute differences matched by an ignore rule.
+func (m *ManagedResourcesDriftDetector) filterIgnoredDifferences(differences []AttributeDifference) []AttributeDifference {
+	if len(m.ignoreRules) == 0 {
+		return differences
+	}
+
+	filtered := make([]AttributeDifference, 0, len(differences))
+	for _, difference := range differences {
+		addresses := []string{
+			resourceAddress(difference.ModuleName, difference.ResourceType, difference.ResourceName),
+			resourceAddress("", difference.ResourceType, difference.ResourceName),
+		}
+		if difference.InstanceKey != "" {
+			addresses = append(addresses, difference.InstanceAddress())
+		}
+
+		ignored := false
+		for _, rule := range m.ignoreRules {
+			if rule.ignoresAttribute(difference.ResourceType, difference.AttributeName, addresses...) {
+				ignored = true
+				break
+			}
+		}
+
+		if !ignored {
+			filtered = append(filtered, difference)
+		}
+	}
+
+	return filtered
+}
+
+// filterIgnoredDeletedResources removes the deleted resources matched by a resource type or address ignore rule.
+func (m *ManagedResourcesDriftDetector) filterIgnoredDeletedResources(deleted []DeletedResource) []DeletedResource {
+	if len(m.ignoreRules) == 0 {
+		return deleted
+	}
+
+	filtered := make([]DeletedResource, 0, len(deleted))
+	for _, resource := range deleted {
+		addresses := []string{
+			resource.ResourceAddress,
+			resourceAddress(resource.ModuleName, resource.ResourceType, resource.ResourceName),
+		}
+
+		ignored := false
+		for _, rule := range m.ignoreRules {
+			if rule.ignoresResource(resource.ResourceType, addresses...) {
+				ignored = true
+				break
+			}
+		}
+
+		if !ignored {
+			filtered = append(filtered, resource)
+		}
+	}
+
+	return filtered
+}
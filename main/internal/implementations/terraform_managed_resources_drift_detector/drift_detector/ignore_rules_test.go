@@ -0,0 +1,64 @@
+package driftDetector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIgnoreRules(t *testing.T) {
+	// Given
+	rules := []string{"type:aws_cloudwatch_*", " address:module.legacy.* ", ""}
+
+	// When
+	output, err := ParseIgnoreRules(rules)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, []IgnoreRule{
+		{Kind: IgnoreRuleResourceType, Pattern: "aws_cloudwatch_*"},
+		{Kind: IgnoreRuleResourceAddress, Pattern: "module.legacy.*"},
+	}, output)
+
+	_, err = ParseIgnoreRules([]string{"aws_instance"})
+	assert.Error(t, err)
+
+	_, err = ParseIgnoreRules([]string{"resource:aws_instance"})
+	assert.Error(t, err)
+
+	_, err = ParseIgnoreRules([]string{"attribute:aws_instance.[.tags"})
+	assert.Error(t, err)
+}
+
+func TestFilterIgnoredDifferences(t *testing.T) {
+	// Given
+	ignoreRules, err := ParseIgnoreRules([]string{
+		"attribute:aws_autoscaling_group.*.desired_capacity",
+		"type:aws_cloudwatch_*",
+		"address:module.legacy.*",
+	})
+	require.NoError(t, err)
+	detector := &ManagedResourcesDriftDetector{ignoreRules: ignoreRules}
+
+	asgDetail := AttributeDetail{ModuleName: "module.compute", ResourceType: "aws_autoscaling_group", ResourceName: "web"}
+	differences := []AttributeDifference{
+		{AttributeName: "desired_capacity", AttributeDetail: asgDetail},
+		{AttributeName: "max_size", AttributeDetail: asgDetail},
+		{AttributeName: "retention_in_days", AttributeDetail: AttributeDetail{ModuleName: "root", ResourceType: "aws_cloudwatch_log_group", ResourceName: "app"}},
+		{AttributeName: "tags.env", AttributeDetail: AttributeDetail{ModuleName: "module.legacy", ResourceType: "aws_instance", ResourceName: "old"}},
+	}
+
+	deleted := []DeletedResource{
+		{ModuleName: "module.legacy", ResourceType: "aws_instance", ResourceName: "old", ResourceAddress: "module.legacy.aws_instance.old"},
+		{ModuleName: "root", ResourceType: "aws_autoscaling_group", ResourceName: "web", ResourceAddress: "aws_autoscaling_group.web"},
+	}
+
+	// When
+	filteredDifferences := detector.filterIgnoredDifferences(differences)
+	filteredDeleted := detector.filterIgnoredDeletedResources(deleted)
+
+	// Then
+	assert.Equal(t, []AttributeDifference{differences[1]}, filteredDifferences)
+	assert.Equal(t, []DeletedResource{deleted[1]}, filteredDeleted)
+}
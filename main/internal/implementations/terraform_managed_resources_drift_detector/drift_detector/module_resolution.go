@@ -0,0 +1,172 @@
+package driftDetector
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/clonedrepo"
+)
+
+// moduleSourcePattern extracts the string literal assigned to a module block's source attribute.
+var moduleSourcePattern = regexp.MustCompile(`"(.*)"`)
+
+// moduleNamePattern extracts the name of each module call within a state module path.
+var moduleNamePattern = regexp.MustCompile(`(?:^|\.)module\.([^.\[]+)`)
+
+// ModuleCall is a module block declared within a Terraform configuration.
+type ModuleCall struct {
+	// Source is the value of the module block's source attribute.
+	Source string
+
+	// CallingFile is the path, relative to the repository root, of the file declaring the module block.
+	CallingFile string
+}
+
+// moduleResolver resolves module paths from state, such as module.network.module.subnets, to the module
+// blocks declaring them within the cloned repository. Module calls are parsed once per directory.
+type moduleResolver struct {
+	directoryToModuleCalls map[string]map[string]ModuleCall
+}
+
+// newModuleResolver creates a moduleResolver with an empty cache.
+func newModuleResolver() *moduleResolver {
+	return &moduleResolver{directoryToModuleCalls: map[string]map[string]ModuleCall{}}
+}
+
+// annotateModuleSources sets the source module and calling file of each difference declared within a module.
+func (m *ManagedResourcesDriftDetector) annotateModuleSources(differences []AttributeDifference, workspaceToDirectory map[string]string) []AttributeDifference {
+	resolver := newModuleResolver()
+
+	for i, difference := range differences {
+		if difference.ModuleName == "" || difference.ModuleName == "root" {
+			continue
+		}
+
+		directory, ok := workspaceToDirectory[string(difference.StateFileName)]
+		if !ok {
+			continue
+		}
+
+		moduleCall, ok := resolver.resolve(directory, difference.ModuleName)
+		if !ok {
+			continue
+		}
+
+		differences[i].ModuleSource = moduleCall.Source
+		differences[i].ModuleCallingFile = moduleCall.CallingFile
+	}
+
+	return differences
+}
+
+// resolve returns the module block declaring modulePath, walking nested module calls through local module sources.
+// The workspace directory is of the form "/path/", relative to the root of the cloned repository.
+func (r *moduleResolver) resolve(workspaceDirectory string, modulePath string) (ModuleCall, bool) {
+	moduleNames := moduleNamesFromPath(modulePath)
+	if len(moduleNames) == 0 {
+		return ModuleCall{}, false
+	}
+
+	directory := filepath.Clean(strings.TrimPrefix(workspaceDirectory, "/"))
+	var moduleCall ModuleCall
+	for i, moduleName := range moduleNames {
+		call, ok := r.moduleCalls(directory)[moduleName]
+		if !ok {
+			return ModuleCall{}, false
+		}
+		moduleCall = call
+
+		if i < len(moduleNames)-1 {
+			if !isLocalModuleSource(call.Source) {
+				return ModuleCall{}, false
+			}
+			directory = filepath.Join(directory, call.Source)
+		}
+	}
+
+	return moduleCall, true
+}
+
+// moduleCalls returns the module blocks declared by the .tf files of directory, keyed by module name.
+func (r *moduleResolver) moduleCalls(directory string) map[string]ModuleCall {
+	if calls, ok := r.directoryToModuleCalls[directory]; ok {
+		return calls
+	}
+
+	calls := map[string]ModuleCall{}
+	r.directoryToModuleCalls[directory] = calls
+
+	filePaths, err := filepath.Glob(filepath.Join(clonedrepo.Directory, directory, "*.tf"))
+	if err != nil {
+		return calls
+	}
+
+	for _, filePath := range filePaths {
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			log.Debugf("[module_calls][unable to read %v]%v", filePath, err)
+			continue
+		}
+
+		fileCalls, err := parseModuleCalls(content)
+		if err != nil {
+			log.Debugf("[module_calls][unable to parse %v]%v", filePath, err)
+			continue
+		}
+
+		callingFile := filepath.ToSlash(filepath.Join(directory, filepath.Base(filePath)))
+		for moduleName, source := range fileCalls {
+			calls[moduleName] = ModuleCall{Source: source, CallingFile: callingFile}
+		}
+	}
+
+	return calls
+}
+
+// parseModuleCalls returns the source of each module block within fileContent, keyed by module name.
+func parseModuleCalls(fileContent []byte) (map[string]string, error) {
+	hclFile, diagnostics := hclwrite.ParseConfig(fileContent, "placeholder.tf", hcl.Pos{Line: 0, Column: 0, Byte: 0})
+	if diagnostics.HasErrors() {
+		return nil, fmt.Errorf("error parsing HCL file: %s", diagnostics.Error())
+	}
+
+	calls := map[string]string{}
+	for _, block := range hclFile.Body().Blocks() {
+		if block.Type() != "module" || len(block.Labels()) != 1 {
+			continue
+		}
+
+		source := ""
+		if attribute := block.Body().GetAttribute("source"); attribute != nil {
+			matches := moduleSourcePattern.FindStringSubmatch(string(attribute.Expr().BuildTokens(nil).Bytes()))
+			if len(matches) == 2 {
+				source = matches[1]
+			}
+		}
+		calls[block.Labels()[0]] = source
+	}
+
+	return calls, nil
+}
+
+// moduleNamesFromPath splits a state module path, such as module.network.module.subnets["a"], into the names
+// of its module calls.
+func moduleNamesFromPath(modulePath string) []string {
+	names := make([]string, 0)
+	for _, match := range moduleNamePattern.FindAllStringSubmatch(modulePath, -1) {
+		names = append(names, match[1])
+	}
+	return names
+}
+
+// isLocalModuleSource returns whether source refers to a module within the same repository.
+func isLocalModuleSource(source string) bool {
+	return strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../")
+}
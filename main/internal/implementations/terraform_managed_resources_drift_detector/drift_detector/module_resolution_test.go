@@ -0,0 +1,75 @@
+package driftDetector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModuleNamesFromPath(t *testing.T) {
+	// Given
+	inputs := map[string][]string{
+		"root":           {},
+		"module.network": {"network"},
+		`module.network["east"].module.subnets[0]`: {"network", "subnets"},
+	}
+
+	for input, expected := range inputs {
+		// When
+		output := moduleNamesFromPath(input)
+
+		// Then
+		assert.Equal(t, expected, output, input)
+	}
+}
+
+func TestAnnotateModuleSources(t *testing.T) {
+	// Given
+	workingDirectory, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(workingDirectory) }()
+
+	writeTestFile(t, "repo/prod/main.tf", `
+module "network" {
+  source = "./modules/network"
+}
+
+module "dns" {
+  source  = "terraform-aws-modules/route53/aws"
+  version = "2.10.0"
+}
+`)
+	writeTestFile(t, "repo/prod/modules/network/subnets.tf", `
+module "subnets" {
+  source = "../subnets"
+}
+`)
+
+	m := ManagedResourcesDriftDetector{}
+	differences := []AttributeDifference{
+		{AttributeDetail: AttributeDetail{StateFileName: "prod", ModuleName: "module.network.module.subnets"}},
+		{AttributeDetail: AttributeDetail{StateFileName: "prod", ModuleName: "module.dns"}},
+		{AttributeDetail: AttributeDetail{StateFileName: "prod", ModuleName: "root"}},
+		{AttributeDetail: AttributeDetail{StateFileName: "prod", ModuleName: "module.unknown"}},
+	}
+
+	// When
+	output := m.annotateModuleSources(differences, map[string]string{"prod": "/prod/"})
+
+	// Then
+	assert.Equal(t, "../subnets", output[0].ModuleSource)
+	assert.Equal(t, "prod/modules/network/subnets.tf", output[0].ModuleCallingFile)
+	assert.Equal(t, "terraform-aws-modules/route53/aws", output[1].ModuleSource)
+	assert.Equal(t, "prod/main.tf", output[1].ModuleCallingFile)
+	assert.Empty(t, output[2].ModuleSource)
+	assert.Empty(t, output[3].ModuleCallingFile)
+}
+
+func writeTestFile(t *testing.T, path string, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+}
@@ -0,0 +1,190 @@
+package driftDetector
+
+import (
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// policyListKeys are the statement keys whose value may be either a single string or a list of strings, with the
+// order of the list being insignificant.
+var policyListKeys = map[string]bool{
+	"Action":      true,
+	"NotAction":   true,
+	"Resource":    true,
+	"NotResource": true,
+}
+
+// policyPrincipalKeys are the statement keys holding principals, keyed by principal type.
+var policyPrincipalKeys = map[string]bool{
+	"Principal":    true,
+	"NotPrincipal": true,
+}
+
+// decodePolicyDocument returns the JSON policy document held by value, which IAM may return URL encoded.
+func decodePolicyDocument(value string) string {
+	if strings.HasPrefix(value, "%7B") || strings.HasPrefix(value, "%7b") {
+		if decoded, err := url.QueryUnescape(value); err == nil {
+			return decoded
+		}
+	}
+	return value
+}
+
+// isPolicyDocument returns true if document is an IAM style policy document.
+func isPolicyDocument(document interface{}) bool {
+	object, ok := document.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	_, ok = object["Statement"]
+	return ok
+}
+
+// normalizePolicyDocument rewrites an IAM policy document into a canonical form in which semantically equivalent
+// policies are equal: single statements and values become lists, lists whose order is insignificant are sorted and
+// de-duplicated, actions are lower cased, and statements are sorted.
+func normalizePolicyDocument(document map[string]interface{}) map[string]interface{} {
+	statements := toList(document["Statement"])
+
+	normalizedStatements := make([]interface{}, 0, len(statements))
+	for _, statement := range statements {
+		statementObject, ok := statement.(map[string]interface{})
+		if !ok {
+			normalizedStatements = append(normalizedStatements, statement)
+			continue
+		}
+		normalizedStatements = append(normalizedStatements, normalizePolicyStatement(statementObject))
+	}
+
+	sort.SliceStable(normalizedStatements, func(i, j int) bool {
+		return canonicalJSON(normalizedStatements[i]) < canonicalJSON(normalizedStatements[j])
+	})
+
+	normalized := map[string]interface{}{}
+	for key, value := range document {
+		normalized[key] = value
+	}
+	normalized["Statement"] = normalizedStatements
+	return normalized
+}
+
+// normalizePolicyStatement rewrites a single policy statement into its canonical form.
+func normalizePolicyStatement(statement map[string]interface{}) map[string]interface{} {
+	normalized := map[string]interface{}{}
+
+	for key, value := range statement {
+		switch {
+		case policyListKeys[key]:
+			// Action names are case-insensitive.
+			if key == "Action" || key == "NotAction" {
+				value = lowerCaseStrings(value)
+			}
+			normalized[key] = sortedStringSet(value)
+		case policyPrincipalKeys[key]:
+			normalized[key] = normalizePolicyPrincipal(value)
+		case key == "Condition":
+			normalized[key] = normalizePolicyCondition(value)
+		default:
+			normalized[key] = value
+		}
+	}
+
+	return normalized
+}
+
+// normalizePolicyPrincipal rewrites a principal, treating "*" as equivalent to {"AWS": "*"}.
+func normalizePolicyPrincipal(principal interface{}) interface{} {
+	if principal == "*" {
+		principal = map[string]interface{}{"AWS": "*"}
+	}
+
+	principalObject, ok := principal.(map[string]interface{})
+	if !ok {
+		return principal
+	}
+
+	normalized := map[string]interface{}{}
+	for principalType, values := range principalObject {
+		normalized[principalType] = sortedStringSet(values)
+	}
+	return normalized
+}
+
+// normalizePolicyCondition rewrites the values of each condition operator and key into sorted lists.
+func normalizePolicyCondition(condition interface{}) interface{} {
+	conditionObject, ok := condition.(map[string]interface{})
+	if !ok {
+		return condition
+	}
+
+	normalized := map[string]interface{}{}
+	for operator, keys := range conditionObject {
+		keysObject, ok := keys.(map[string]interface{})
+		if !ok {
+			normalized[operator] = keys
+			continue
+		}
+
+		normalizedKeys := map[string]interface{}{}
+		for conditionKey, values := range keysObject {
+			normalizedKeys[conditionKey] = sortedStringSet(values)
+		}
+		normalized[operator] = normalizedKeys
+	}
+	return normalized
+}
+
+// sortedStringSet returns the string representation of a single value or list of values, sorted and de-duplicated.
+func sortedStringSet(value interface{}) []string {
+	set := map[string]bool{}
+	for _, item := range toList(value) {
+		if text, ok := item.(string); ok {
+			set[text] = true
+		} else {
+			set[canonicalJSON(item)] = true
+		}
+	}
+
+	values := make([]string, 0, len(set))
+	for item := range set {
+		values = append(values, item)
+	}
+	sort.Strings(values)
+	return values
+}
+
+// toList wraps a single value within a list, leaving lists unchanged.
+func toList(value interface{}) []interface{} {
+	switch typed := value.(type) {
+	case nil:
+		return []interface{}{}
+	case []interface{}:
+		return typed
+	default:
+		return []interface{}{typed}
+	}
+}
+
+// lowerCaseStrings lower cases the strings of a single value or list of values.
+func lowerCaseStrings(value interface{}) []interface{} {
+	list := toList(value)
+	lowered := make([]interface{}, 0, len(list))
+	for _, item := range list {
+		if text, ok := item.(string); ok {
+			item = strings.ToLower(text)
+		}
+		lowered = append(lowered, item)
+	}
+	return lowered
+}
+
+// canonicalJSON encodes value with sorted keys and without insignificant whitespace.
+func canonicalJSON(value interface{}) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(encoded)
+}
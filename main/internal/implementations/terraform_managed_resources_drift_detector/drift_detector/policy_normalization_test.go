@@ -0,0 +1,46 @@
+package driftDetector
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttributeValuesEqual_PolicyDocuments(t *testing.T) {
+	// Given
+	terraformPolicy := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetObject","s3:ListBucket"],"Resource":"arn:aws:s3:::logs/*","Principal":"*"},{"Effect":"Deny","Action":"iam:*","Resource":"*","Condition":{"StringEquals":{"aws:PrincipalTag/team":"data"}}}]}`
+	cloudPolicy := `{
+		"Statement": [
+			{"Resource": "*", "Effect": "Deny", "Action": ["IAM:*"], "Condition": {"StringEquals": {"aws:PrincipalTag/team": ["data"]}}},
+			{"Principal": {"AWS": "*"}, "Action": ["s3:ListBucket", "s3:GetObject", "s3:GetObject"], "Effect": "Allow", "Resource": ["arn:aws:s3:::logs/*"]}
+		],
+		"Version": "2012-10-17"
+	}`
+	changedPolicy := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:*","Resource":"*"}]}`
+
+	// When
+	equivalent := attributeValuesEqual(terraformPolicy, cloudPolicy)
+	encodedEquivalent := attributeValuesEqual(terraformPolicy, url.QueryEscape(cloudPolicy))
+	changed := attributeValuesEqual(terraformPolicy, changedPolicy)
+
+	// Then
+	assert.True(t, equivalent)
+	assert.True(t, encodedEquivalent)
+	assert.False(t, changed)
+}
+
+func TestNormalizePolicyDocument_SingleStatement(t *testing.T) {
+	// Given
+	document := map[string]interface{}{
+		"Statement": map[string]interface{}{"Effect": "Allow", "Action": "S3:GetObject", "Resource": "*"},
+	}
+
+	// When
+	normalized := normalizePolicyDocument(document)
+
+	// Then
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"Effect": "Allow", "Action": []string{"s3:getobject"}, "Resource": []string{"*"}},
+	}, normalized["Statement"])
+}
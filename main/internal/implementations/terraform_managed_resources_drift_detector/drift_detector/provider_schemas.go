@@ -0,0 +1,155 @@
+package driftDetector
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+)
+
+// providerSchemasPath is the path of the provider schemas written by the terraformer executor, as output by
+// `terraform providers schema -json`.
+const providerSchemasPath = "mappings/provider-schemas.json"
+
+// ProviderSchemas is the output of `terraform providers schema -json`.
+type ProviderSchemas struct {
+	ProviderSchemas map[string]ProviderSchema `json:"provider_schemas"`
+}
+
+// ProviderSchema is the schema of a single provider.
+type ProviderSchema struct {
+	ResourceSchemas map[string]ResourceSchema `json:"resource_schemas"`
+}
+
+// ResourceSchema is the schema of a single resource type.
+type ResourceSchema struct {
+	Block SchemaBlock `json:"block"`
+}
+
+// SchemaBlock is the schema of a resource, or of a block nested within a resource.
+type SchemaBlock struct {
+	Attributes map[string]SchemaAttribute   `json:"attributes"`
+	BlockTypes map[string]SchemaNestedBlock `json:"block_types"`
+}
+
+// SchemaAttribute is the schema of a single attribute.
+type SchemaAttribute struct {
+	Required  bool `json:"required"`
+	Optional  bool `json:"optional"`
+	Computed  bool `json:"computed"`
+	Sensitive bool `json:"sensitive"`
+	WriteOnly bool `json:"write_only"`
+}
+
+// SchemaNestedBlock is the schema of a block nested within a resource.
+type SchemaNestedBlock struct {
+	Block SchemaBlock `json:"block"`
+}
+
+// ResourceTypeToSchema is a map between a resource type and its schema.
+type ResourceTypeToSchema map[string]SchemaBlock
+
+// loadProviderSchemas loads the resource schemas of all providers. Returns an empty map if the schemas were not
+// written, in which case all attributes are compared.
+func loadProviderSchemas(store artifacts.Store) (ResourceTypeToSchema, error) {
+	content, err := store.ReadFile(providerSchemasPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ResourceTypeToSchema{}, nil
+		}
+		return nil, fmt.Errorf("[artifacts.ReadFile]%w", err)
+	}
+
+	schemas := ProviderSchemas{}
+	err = json.Unmarshal(content, &schemas)
+	if err != nil {
+		return nil, fmt.Errorf("[json.Unmarshal][%v]%w", providerSchemasPath, err)
+	}
+
+	resourceTypeToSchema := ResourceTypeToSchema{}
+	for _, providerSchema := range schemas.ProviderSchemas {
+		for resourceType, resourceSchema := range providerSchema.ResourceSchemas {
+			resourceTypeToSchema[resourceType] = resourceSchema.Block
+		}
+	}
+
+	return resourceTypeToSchema, nil
+}
+
+// attributeSchema returns the schema of the attribute at a flat attribute path, e.g. ebs_block_device.0.volume_size,
+// walking through nested blocks and skipping their list and set indices. Returns false if the path is not described
+// by the schema.
+func (s ResourceTypeToSchema) attributeSchema(resourceType string, attributePath string) (SchemaAttribute, bool) {
+	block, ok := s[resourceType]
+	if !ok {
+		return SchemaAttribute{}, false
+	}
+
+	segments := strings.Split(attributePath, ".")
+	for i := 0; i < len(segments); i++ {
+		if attribute, ok := block.Attributes[segments[i]]; ok {
+			return attribute, true
+		}
+
+		nestedBlock, ok := block.BlockTypes[segments[i]]
+		if !ok {
+			return SchemaAttribute{}, false
+		}
+		block = nestedBlock.Block
+
+		if i+1 < len(segments) && isIndexSegment(segments[i+1]) {
+			i++
+		}
+	}
+
+	return SchemaAttribute{}, false
+}
+
+// isComparable returns false for attributes that cannot drift from configuration: attributes that are computed by
+// the provider and cannot be set, and write-only attributes, whose values are never persisted to state.
+func (s ResourceTypeToSchema) isComparable(resourceType string, attributePath string) bool {
+	attribute, ok := s.attributeSchema(resourceType, attributePath)
+	if !ok {
+		return true
+	}
+
+	readOnly := attribute.Computed && !attribute.Optional && !attribute.Required
+	return !readOnly && !attribute.WriteOnly
+}
+
+// isSensitive returns true if the provider schema marks the attribute at attributePath as sensitive.
+func (s ResourceTypeToSchema) isSensitive(resourceType string, attributePath string) bool {
+	attribute, ok := s.attributeSchema(resourceType, attributePath)
+	return ok && attribute.Sensitive
+}
+
+// filterSchemaExcludedDifferences removes the differences of attributes that cannot drift from configuration.
+func (m *ManagedResourcesDriftDetector) filterSchemaExcludedDifferences(differences []AttributeDifference) []AttributeDifference {
+	if len(m.resourceSchemas) == 0 {
+		return differences
+	}
+
+	filtered := make([]AttributeDifference, 0, len(differences))
+	for _, difference := range differences {
+		if m.resourceSchemas.isComparable(difference.ResourceType, difference.AttributeName) {
+			filtered = append(filtered, difference)
+		}
+	}
+	return filtered
+}
+
+// isIndexSegment returns true if segment is a list index or set hash within a flat attribute path.
+func isIndexSegment(segment string) bool {
+	if segment == "" {
+		return false
+	}
+	for _, r := range segment {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
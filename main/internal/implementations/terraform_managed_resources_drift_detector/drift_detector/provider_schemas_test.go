@@ -0,0 +1,83 @@
+package driftDetector
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterSchemaExcludedDifferences(t *testing.T) {
+	// Given
+	schemas := ProviderSchemas{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"provider_schemas": {
+			"registry.terraform.io/hashicorp/aws": {
+				"resource_schemas": {
+					"aws_instance": {
+						"block": {
+							"attributes": {
+								"arn": {"computed": true},
+								"instance_type": {"optional": true},
+								"private_ip": {"optional": true, "computed": true},
+								"password_wo": {"optional": true, "write_only": true},
+								"user_data": {"optional": true, "sensitive": true}
+							},
+							"block_types": {
+								"ebs_block_device": {
+									"block": {
+										"attributes": {
+											"volume_id": {"computed": true},
+											"volume_size": {"optional": true}
+										}
+									}
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}`), &schemas))
+
+	m := ManagedResourcesDriftDetector{resourceSchemas: ResourceTypeToSchema{}}
+	for _, providerSchema := range schemas.ProviderSchemas {
+		for resourceType, resourceSchema := range providerSchema.ResourceSchemas {
+			m.resourceSchemas[resourceType] = resourceSchema.Block
+		}
+	}
+
+	differences := []AttributeDifference{}
+	for _, attribute := range []string{
+		"arn", "instance_type", "private_ip", "password_wo", "ebs_block_device.1234.volume_id",
+		"ebs_block_device.1234.volume_size", "tags.env",
+	} {
+		differences = append(differences, AttributeDifference{
+			AttributeName:   attribute,
+			AttributeDetail: AttributeDetail{ResourceType: "aws_instance"},
+		})
+	}
+	differences = append(differences, AttributeDifference{
+		AttributeName:   "arn",
+		AttributeDetail: AttributeDetail{ResourceType: "aws_s3_bucket"},
+	})
+
+	// When
+	output := m.filterSchemaExcludedDifferences(differences)
+
+	// Then
+	var remaining []string
+	for _, difference := range output {
+		remaining = append(remaining, difference.ResourceType+"."+difference.AttributeName)
+	}
+	assert.Equal(t, []string{
+		"aws_instance.instance_type",
+		"aws_instance.private_ip",
+		"aws_instance.ebs_block_device.1234.volume_size",
+		"aws_instance.tags.env",
+		"aws_s3_bucket.arn",
+	}, remaining)
+	assert.True(t, m.resourceSchemas.isSensitive("aws_instance", "user_data"))
+	assert.False(t, m.resourceSchemas.isSensitive("aws_instance", "instance_type"))
+}
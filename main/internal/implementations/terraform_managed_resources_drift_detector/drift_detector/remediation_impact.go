@@ -0,0 +1,156 @@
+package driftDetector
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RemediationImpact describes the operational risk of applying Terraform to remediate a drifted resource. It is a
+// best-effort estimate from forceNewAttributes, and `terraform plan` remains authoritative.
+type RemediationImpact string
+
+const (
+	// RemediationImpactInPlace indicates that none of the drifted attributes of the resource are known to force
+	// replacement, so that the resource is expected to be updated in place.
+	RemediationImpactInPlace RemediationImpact = "in-place"
+
+	// RemediationImpactReplacement indicates that the drifted resource must be destroyed and re-created.
+	RemediationImpactReplacement RemediationImpact = "replacement"
+
+	// RemediationImpactCascade indicates that the drifted resource must be replaced, and that other managed
+	// resources depend on it and may be updated or replaced as a consequence.
+	RemediationImpactCascade RemediationImpact = "cascade"
+
+	// RemediationImpactUnknown indicates that the ForceNew attributes of the resource type are not known, so the
+	// drifted resource may require replacement.
+	RemediationImpactUnknown RemediationImpact = "unknown"
+)
+
+// forceNewAttributes is a best-effort, hand-maintained table mapping a resource type to the top level attributes
+// marked as ForceNew within the corresponding provider's source. A change to any of these attributes requires the
+// resource to be replaced. ForceNew is not part of the schemas output by `terraform providers schema -json`, so it
+// cannot be derived at runtime, and the table may lag behind provider releases: an attribute missing from it is
+// estimated to update in place. Resource types absent from this table have an unknown remediation impact.
+var forceNewAttributes = map[string]map[string]bool{
+	"aws_db_instance":                {"availability_zone": true, "character_set_name": true, "engine": true, "identifier": true, "kms_key_id": true, "snapshot_identifier": true, "storage_encrypted": true, "timezone": true, "username": true},
+	"aws_dynamodb_table":             {"hash_key": true, "name": true, "range_key": true},
+	"aws_ebs_volume":                 {"availability_zone": true, "encrypted": true, "kms_key_id": true, "snapshot_id": true},
+	"aws_eks_cluster":                {"kubernetes_network_config": true, "name": true},
+	"aws_iam_role":                   {"name": true, "name_prefix": true, "path": true},
+	"aws_instance":                   {"ami": true, "associate_public_ip_address": true, "availability_zone": true, "cpu_core_count": true, "cpu_threads_per_core": true, "ebs_block_device": true, "ephemeral_block_device": true, "host_id": true, "key_name": true, "network_interface": true, "placement_group": true, "private_ip": true, "subnet_id": true, "tenancy": true},
+	"aws_lambda_function":            {"function_name": true, "package_type": true},
+	"aws_lb":                         {"internal": true, "load_balancer_type": true, "name": true, "name_prefix": true},
+	"aws_lb_target_group":            {"name": true, "name_prefix": true, "port": true, "protocol": true, "target_type": true, "vpc_id": true},
+	"aws_s3_bucket":                  {"bucket": true, "bucket_prefix": true},
+	"aws_security_group":             {"description": true, "name": true, "name_prefix": true, "vpc_id": true},
+	"aws_sns_topic":                  {"fifo_topic": true, "name": true, "name_prefix": true},
+	"aws_sqs_queue":                  {"fifo_queue": true, "name": true, "name_prefix": true},
+	"aws_subnet":                     {"availability_zone": true, "availability_zone_id": true, "cidr_block": true, "vpc_id": true},
+	"aws_vpc":                        {"cidr_block": true},
+	"azurerm_key_vault":              {"location": true, "name": true, "resource_group_name": true},
+	"azurerm_resource_group":         {"location": true, "name": true},
+	"azurerm_kubernetes_cluster":     {"dns_prefix": true, "location": true, "name": true, "resource_group_name": true},
+	"azurerm_linux_virtual_machine":  {"admin_username": true, "location": true, "name": true, "resource_group_name": true, "source_image_reference": true, "zone": true},
+	"azurerm_network_security_group": {"location": true, "name": true, "resource_group_name": true},
+	"azurerm_storage_account":        {"account_tier": true, "is_hns_enabled": true, "location": true, "name": true, "resource_group_name": true},
+	"azurerm_subnet":                 {"name": true, "resource_group_name": true, "virtual_network_name": true},
+	"azurerm_virtual_network":        {"location": true, "name": true, "resource_group_name": true},
+	"google_compute_disk":            {"image": true, "name": true, "project": true, "snapshot": true, "type": true, "zone": true},
+	"google_compute_firewall":        {"direction": true, "name": true, "network": true, "project": true},
+	"google_compute_instance":        {"boot_disk": true, "name": true, "project": true, "scratch_disk": true, "zone": true},
+	"google_compute_network":         {"auto_create_subnetworks": true, "name": true, "project": true},
+	"google_compute_subnetwork":      {"name": true, "network": true, "project": true, "region": true},
+	"google_container_cluster":       {"location": true, "name": true, "network": true, "project": true, "subnetwork": true},
+	"google_pubsub_topic":            {"name": true, "project": true},
+	"google_service_account":         {"account_id": true, "project": true},
+	"google_sql_database_instance":   {"database_version": true, "name": true, "project": true, "region": true},
+	"google_storage_bucket":          {"location": true, "name": true, "project": true},
+}
+
+// attributeForcesReplacement returns true if a change to the passed flat attribute path requires the resource to
+// be replaced.
+func attributeForcesReplacement(resourceType string, attributePath string) bool {
+	topLevelAttribute := strings.Split(attributePath, ".")[0]
+	return forceNewAttributes[resourceType][topLevelAttribute]
+}
+
+// resourceAddress returns the address of a resource within its state file, e.g. module.network.aws_vpc.main.
+func resourceAddress(module string, resourceType string, resourceName string) string {
+	if module == "" || module == "root" {
+		return fmt.Sprintf("%v.%v", resourceType, resourceName)
+	}
+	return fmt.Sprintf("%v.%v.%v", module, resourceType, resourceName)
+}
+
+// resourceInstanceAddress returns the address of a resource instance within its state file, including the instance
+// key of resources created with count or for_each, e.g. aws_subnet.private[0] or aws_subnet.private["a"].
+func resourceInstanceAddress(module string, resourceType string, resourceName string, indexKey interface{}) string {
+	return resourceAddress(module, resourceType, resourceName) + instanceKeySuffix(indexKey)
+}
+
+// instanceKeySuffix returns the address suffix of a resource instance created with count or for_each, e.g. [0] or
+// ["a"], and an empty string for resources with a single instance.
+func instanceKeySuffix(indexKey interface{}) string {
+	switch key := indexKey.(type) {
+	case nil:
+		return ""
+	case string:
+		return fmt.Sprintf("[%q]", key)
+	case float64:
+		return fmt.Sprintf("[%v]", int(key))
+	default:
+		return fmt.Sprintf("[%v]", key)
+	}
+}
+
+// annotateRemediationImpact sets whether each drifted attribute forces replacement, and the remediation impact of the
+// resource instance that the attribute belongs to.
+func (m *ManagedResourcesDriftDetector) annotateRemediationImpact(differences []AttributeDifference, terraformResources TerraformStateResourceIDToData) []AttributeDifference {
+	stateFileToDependedOn := map[string]map[string]bool{}
+	for _, data := range terraformResources {
+		if _, ok := stateFileToDependedOn[data.StateFile]; !ok {
+			stateFileToDependedOn[data.StateFile] = map[string]bool{}
+		}
+		for _, dependency := range data.Dependencies {
+			stateFileToDependedOn[data.StateFile][dependency] = true
+		}
+	}
+
+	instanceRequiresReplacement := map[string]bool{}
+	for i, difference := range differences {
+		differences[i].ForcesReplacement = attributeForcesReplacement(difference.ResourceType, difference.AttributeName)
+		if differences[i].ForcesReplacement {
+			instanceRequiresReplacement[instanceKey(difference)] = true
+		}
+	}
+
+	for i, difference := range differences {
+		impact := RemediationImpactInPlace
+		if _, ok := forceNewAttributes[difference.ResourceType]; !ok {
+			impact = RemediationImpactUnknown
+		}
+
+		if instanceRequiresReplacement[instanceKey(difference)] {
+			impact = RemediationImpactReplacement
+
+			// Dependencies within state reference resources rather than their individual instances.
+			address := resourceAddress(difference.ModuleName, difference.ResourceType, difference.ResourceName)
+			if stateFileToDependedOn[string(difference.StateFileName)][address] {
+				impact = RemediationImpactCascade
+			}
+		}
+		differences[i].RemediationImpact = impact
+	}
+
+	return differences
+}
+
+// instanceKey uniquely identifies the resource instance to which an AttributeDifference belongs.
+func instanceKey(difference AttributeDifference) string {
+	return fmt.Sprintf(
+		"%v/%v/%v",
+		difference.StateFileName,
+		difference.InstanceAddress(),
+		difference.InstanceID,
+	)
+}
@@ -0,0 +1,54 @@
+package driftDetector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnnotateRemediationImpact(t *testing.T) {
+	detector := &ManagedResourcesDriftDetector{}
+
+	// Given
+	vpcDetail := AttributeDetail{StateFileName: "network", ModuleName: "root", ResourceType: "aws_vpc", ResourceName: "main"}
+	sgDetail := AttributeDetail{StateFileName: "network", ModuleName: "module.app", ResourceType: "aws_security_group", ResourceName: "web"}
+	bucketDetail := AttributeDetail{StateFileName: "storage", ModuleName: "root", ResourceType: "aws_s3_bucket", ResourceName: "logs"}
+	queueDetail := AttributeDetail{StateFileName: "storage", ModuleName: "root", ResourceType: "aws_mq_broker", ResourceName: "events"}
+
+	differences := []AttributeDifference{
+		{AttributeName: "cidr_block", InstanceID: "vpc-1", AttributeDetail: vpcDetail},
+		{AttributeName: "tags.env", InstanceID: "vpc-1", AttributeDetail: vpcDetail},
+		{AttributeName: "name", InstanceID: "sg-1", AttributeDetail: sgDetail},
+		{AttributeName: "versioning.0.enabled", InstanceID: "logs", AttributeDetail: bucketDetail},
+		{AttributeName: "engine_type", InstanceID: "events", AttributeDetail: queueDetail},
+	}
+
+	terraformResources := TerraformStateResourceIDToData{
+		"aws_subnet.subnet-1": TerraformStateUniqueResourceData{
+			StateFile:    "network",
+			Module:       "root",
+			Type:         "aws_subnet",
+			Name:         "private",
+			Dependencies: []string{"aws_vpc.main"},
+		},
+	}
+
+	// When
+	output := detector.annotateRemediationImpact(differences, terraformResources)
+
+	// Then
+	assert.True(t, output[0].ForcesReplacement)
+	assert.Equal(t, RemediationImpactCascade, output[0].RemediationImpact)
+
+	assert.False(t, output[1].ForcesReplacement)
+	assert.Equal(t, RemediationImpactCascade, output[1].RemediationImpact)
+
+	assert.True(t, output[2].ForcesReplacement)
+	assert.Equal(t, RemediationImpactReplacement, output[2].RemediationImpact)
+
+	assert.False(t, output[3].ForcesReplacement)
+	assert.Equal(t, RemediationImpactInPlace, output[3].RemediationImpact)
+
+	assert.False(t, output[4].ForcesReplacement)
+	assert.Equal(t, RemediationImpactUnknown, output[4].RemediationImpact)
+}
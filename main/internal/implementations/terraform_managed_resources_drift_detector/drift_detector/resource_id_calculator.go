@@ -30,6 +30,18 @@ func ResourceIDCalculator(attributesFlat map[string]string, cloudProvider string
 			return fmt.Sprintf(string(stringToFill), attributesFlat["id"]), nil
 		}
 		return attributesFlat["id"], nil
+	case "digitalocean":
+		if urn, ok := attributesFlat["urn"]; ok && urn != "" {
+			return urn, nil
+		}
+
+		// Droplet, load balancer and database ids are not unique across resource types, so a URN is
+		// constructed when the urn attribute is not present.
+		digitalOceanToURN := NewDigitalOceanTfToURN()
+		if stringToFill, ok := digitalOceanToURN[TerraformResourceType(resourceType)]; ok {
+			return fmt.Sprintf(stringToFill, attributesFlat["id"]), nil
+		}
+		return attributesFlat["id"], nil
 	default:
 		return attributesFlat["id"], nil
 	}
@@ -68,3 +80,12 @@ func NewGoogleTfToQueryName() TerraformResourceToLoggingName {
 		"google_storage_bucket": "projects/_/buckets/%v",
 	}
 }
+
+// NewDigitalOceanTfToURN returns a map between a DigitalOcean Terraform resource type and the format of its URN.
+func NewDigitalOceanTfToURN() map[TerraformResourceType]string {
+	return map[TerraformResourceType]string{
+		"digitalocean_droplet":          "do:droplet:%v",
+		"digitalocean_loadbalancer":     "do:loadbalancer:%v",
+		"digitalocean_database_cluster": "do:dbaas:%v",
+	}
+}
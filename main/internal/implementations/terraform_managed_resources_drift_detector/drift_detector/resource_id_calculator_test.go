@@ -82,3 +82,34 @@ func TestResourceIDCalculator_NonGoogle(t *testing.T) {
 		t.Errorf("got:\n%s\nexpected:\n%s\n", output, "namespaces/example-id")
 	}
 }
+
+func TestResourceIDCalculator_DigitalOceanURN(t *testing.T) {
+	// Given
+	attributesFlat := map[string]string{
+		"id":  "3164444",
+		"urn": "do:droplet:3164444",
+	}
+
+	// When
+	output, _ := ResourceIDCalculator(attributesFlat, "digitalocean", "digitalocean_droplet")
+
+	// Then
+	if output != "do:droplet:3164444" {
+		t.Errorf("got:\n%s\nexpected:\n%s\n", output, "do:droplet:3164444")
+	}
+}
+
+func TestResourceIDCalculator_DigitalOceanConstructedURN(t *testing.T) {
+	// Given
+	attributesFlat := map[string]string{
+		"id": "245bcfd0-7f31-4ce6-a2bc-475a116cca97",
+	}
+
+	// When
+	output, _ := ResourceIDCalculator(attributesFlat, "digitalocean", "digitalocean_database_cluster")
+
+	// Then
+	if output != "do:dbaas:245bcfd0-7f31-4ce6-a2bc-475a116cca97" {
+		t.Errorf("got:\n%s\nexpected:\n%s\n", output, "do:dbaas:245bcfd0-7f31-4ce6-a2bc-475a116cca97")
+	}
+}
@@ -0,0 +1,103 @@
+package driftDetector
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// resourceIDPlaceholderPattern matches the {attribute} placeholders of a resource id expression.
+var resourceIDPlaceholderPattern = regexp.MustCompile(`{([^{}]*)}`)
+
+// fallbackIDAttributes are the attributes, in order of preference, used as the id of a resource whose type is not
+// known and whose id attribute is empty.
+var fallbackIDAttributes = []string{"arn", "self_link", "urn", "name"}
+
+// ResourceIDMappings is a user-provided mapping between a Terraform resource type and the expression used to
+// calculate the unique id of its resources. An expression is either the name of an attribute, e.g. arn, or a
+// template of {attribute} placeholders, e.g. projects/{project}/global/networks/{name}.
+type ResourceIDMappings map[string]string
+
+// Decode is a custom decoder of ResourceIDMappings for use with the envconfig library. The value is either a json
+// object or the path of a yaml or json file containing the mappings.
+func (r *ResourceIDMappings) Decode(value string) error {
+	if value == "" {
+		return nil
+	}
+
+	mappings := ResourceIDMappings{}
+	if strings.HasPrefix(strings.TrimSpace(value), "{") {
+		if err := json.Unmarshal([]byte(value), &mappings); err != nil {
+			return fmt.Errorf("[resource_id_mappings_decode][error parsing json]%w", err)
+		}
+	} else {
+		content, err := os.ReadFile(value)
+		if err != nil {
+			return fmt.Errorf("[resource_id_mappings_decode][os.ReadFile]%w", err)
+		}
+
+		if err := yaml.Unmarshal(content, &mappings); err != nil {
+			return fmt.Errorf("[resource_id_mappings_decode][error parsing %v]%w", value, err)
+		}
+	}
+
+	for resourceType, expression := range mappings {
+		if strings.TrimSpace(expression) == "" || strings.Count(expression, "{") != strings.Count(expression, "}") {
+			return fmt.Errorf("[resource_id_mappings_decode][invalid id expression %q for %v]", expression, resourceType)
+		}
+	}
+
+	*r = mappings
+	return nil
+}
+
+// Calculate determines the unique id of a Terraform resource, using the user-provided expression for its type when
+// present, then the built-in ResourceIDCalculator, and finally a heuristic over common identifying attributes.
+func (r ResourceIDMappings) Calculate(attributesFlat map[string]string, cloudProvider string, resourceType string) (string, error) {
+	if expression, ok := r[resourceType]; ok {
+		if id, ok := evaluateResourceIDExpression(expression, attributesFlat); ok {
+			return id, nil
+		}
+		log.Warnf("[resource_id_mappings] id expression %q for %v references attributes that are not set", expression, resourceType)
+	}
+
+	id, err := ResourceIDCalculator(attributesFlat, cloudProvider, resourceType)
+	if err != nil {
+		return "", fmt.Errorf("[ResourceIDCalculator]%w", err)
+	}
+	if id != "" {
+		return id, nil
+	}
+
+	for _, attribute := range fallbackIDAttributes {
+		if value := attributesFlat[attribute]; value != "" {
+			log.Debugf("[resource_id_mappings] no id for %v, falling back to the %v attribute", resourceType, attribute)
+			return value, nil
+		}
+	}
+	return "", nil
+}
+
+// evaluateResourceIDExpression evaluates expression against attributesFlat. Returns false if an attribute referenced
+// by the expression is not set.
+func evaluateResourceIDExpression(expression string, attributesFlat map[string]string) (string, bool) {
+	if !strings.Contains(expression, "{") {
+		value := attributesFlat[expression]
+		return value, value != ""
+	}
+
+	allSet := true
+	id := resourceIDPlaceholderPattern.ReplaceAllStringFunc(expression, func(placeholder string) string {
+		value := attributesFlat[strings.Trim(placeholder, "{}")]
+		if value == "" {
+			allSet = false
+		}
+		return value
+	})
+	return id, allSet
+}
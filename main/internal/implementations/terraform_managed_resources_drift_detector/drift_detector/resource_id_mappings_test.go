@@ -0,0 +1,90 @@
+package driftDetector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceIDMappings_Decode(t *testing.T) {
+	// Given
+	filePath := filepath.Join(t.TempDir(), "resource-id-mappings.yaml")
+	require.NoError(t, os.WriteFile(filePath, []byte("aws_networkfirewall_rule_group: arn\n"), 0600))
+
+	// When
+	var fromJSON, fromFile, invalid ResourceIDMappings
+	errJSON := fromJSON.Decode(`{"google_compute_network_firewall_policy": "projects/{project}/global/firewallPolicies/{name}"}`)
+	errFile := fromFile.Decode(filePath)
+	errInvalid := invalid.Decode(`{"aws_example": "{arn"}`)
+
+	// Then
+	require.NoError(t, errJSON)
+	assert.Equal(t, ResourceIDMappings{"google_compute_network_firewall_policy": "projects/{project}/global/firewallPolicies/{name}"}, fromJSON)
+	require.NoError(t, errFile)
+	assert.Equal(t, ResourceIDMappings{"aws_networkfirewall_rule_group": "arn"}, fromFile)
+	assert.Error(t, errInvalid)
+}
+
+func TestResourceIDMappings_Calculate(t *testing.T) {
+	// Given
+	mappings := ResourceIDMappings{
+		"aws_networkfirewall_rule_group":         "arn",
+		"google_compute_network_firewall_policy": "projects/{project}/global/firewallPolicies/{name}",
+	}
+
+	testCases := []struct {
+		name           string
+		attributesFlat map[string]string
+		cloudProvider  string
+		resourceType   string
+		expected       string
+	}{
+		{
+			name:           "attribute expression",
+			attributesFlat: map[string]string{"id": "rule-group", "arn": "arn:aws:network-firewall:us-east-1:123:stateful-rulegroup/rule-group"},
+			cloudProvider:  "aws",
+			resourceType:   "aws_networkfirewall_rule_group",
+			expected:       "arn:aws:network-firewall:us-east-1:123:stateful-rulegroup/rule-group",
+		},
+		{
+			name:           "template expression",
+			attributesFlat: map[string]string{"id": "123", "project": "my-project", "name": "policy"},
+			cloudProvider:  "google",
+			resourceType:   "google_compute_network_firewall_policy",
+			expected:       "projects/my-project/global/firewallPolicies/policy",
+		},
+		{
+			name:           "template expression with unset attributes",
+			attributesFlat: map[string]string{"id": "123", "name": "policy"},
+			cloudProvider:  "google",
+			resourceType:   "google_compute_network_firewall_policy",
+			expected:       "123",
+		},
+		{
+			name:           "built-in calculation",
+			attributesFlat: map[string]string{"id": "i-123"},
+			cloudProvider:  "aws",
+			resourceType:   "aws_instance",
+			expected:       "i-123",
+		},
+		{
+			name:           "fallback heuristic",
+			attributesFlat: map[string]string{"arn": "arn:aws:example:us-east-1:123:thing/a"},
+			cloudProvider:  "aws",
+			resourceType:   "aws_example",
+			expected:       "arn:aws:example:us-east-1:123:thing/a",
+		},
+	}
+
+	for _, testCase := range testCases {
+		// When
+		id, err := mappings.Calculate(testCase.attributesFlat, testCase.cloudProvider, testCase.resourceType)
+
+		// Then
+		require.NoError(t, err, testCase.name)
+		assert.Equal(t, testCase.expected, id, testCase.name)
+	}
+}
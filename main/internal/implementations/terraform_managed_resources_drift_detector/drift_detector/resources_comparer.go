@@ -1,6 +1,7 @@
 package driftDetector
 
 import (
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strconv"
@@ -19,6 +20,14 @@ type AttributeDifference struct {
 	CloudValue            string
 	InstanceID            string
 	InstanceRegion        string
+	ForcesReplacement     bool
+	ChangeType            AttributeChangeType
+	RemediationImpact     RemediationImpact
+	Sensitive             bool `json:",omitempty"`
+
+	// ValueType is the type of a top level attribute's value within the Terraform state, one of bool, number or
+	// string, and empty when unknown.
+	ValueType string `json:",omitempty"`
 	AttributeDetail
 }
 
@@ -29,51 +38,144 @@ type AttributeDetail struct {
 	ModuleName    string
 	ResourceType  string
 	ResourceName  string
+
+	// InstanceKey is the address suffix of a resource instance created with count or for_each, e.g. [0] or ["a"].
+	InstanceKey string `json:",omitempty"`
+
+	// ModuleSource is the source of the module declaring the resource, when declared within a module.
+	ModuleSource string `json:",omitempty"`
+
+	// ModuleCallingFile is the repository path of the file containing the module block that declares the resource.
+	ModuleCallingFile string `json:",omitempty"`
+
+	// CloudResourceName is the name of the resource within the scanned cloud state of its division.
+	CloudResourceName string `json:",omitempty"`
+}
+
+// InstanceAddress returns the address of the resource instance within its state file, including its instance key.
+func (d AttributeDetail) InstanceAddress() string {
+	return resourceAddress(d.ModuleName, d.ResourceType, d.ResourceName) + d.InstanceKey
 }
 
 // identifyResourceDifferences compares TerraformerResources and RemoteResources,
 // and returns a slice of AttributeDifference with any differences found between the two maps of resources.
+// Resources are compared concurrently, bounded by the configured concurrency.
 // It also returns an error if there's any issue during the comparison process.
 func (m *ManagedResourcesDriftDetector) identifyResourceDifferences(
 	terraformerResources TerraformerResourceIDToData,
 	terraformResources TerraformStateResourceIDToData,
 ) ([]AttributeDifference, error) {
-	attributeDifferences := make([]AttributeDifference, 0)
+	ids := make([]string, 0)
+	for id := range terraformResources {
+		if _, ok := terraformerResources[id]; ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
 
-	for id, data := range terraformResources {
+	workers := m.concurrency
+	if workers < 1 {
+		workers = 1
+	}
+	chunkSize := (len(ids) + workers - 1) / workers
+	if chunkSize == 0 {
+		chunkSize = 1
+	}
+	chunkCount := (len(ids) + chunkSize - 1) / chunkSize
 
-		if terraformerResource, ok := terraformerResources[id]; ok {
-			terraformInstanceConverted, err := convertNestedMapToFlatAttributes(data.Attributes)
+	differencesByChunk := make([][]AttributeDifference, chunkCount)
+	err := runBounded(chunkCount, workers, func(i int) error {
+		end := (i + 1) * chunkSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		for _, id := range ids[i*chunkSize : end] {
+			driftedResources, err := compareResource(terraformResources[id], terraformerResources[id], m.resourceIDMappings)
 			if err != nil {
-				return nil, fmt.Errorf("[convertNestedMapToFlatAttributes]%v", err)
+				return err
 			}
+			driftedResources = m.filterSchemaExcludedDifferences(driftedResources)
+			driftedResources = m.maskSensitiveDifferences(driftedResources, terraformResources[id])
+			differencesByChunk[i] = append(differencesByChunk[i], driftedResources...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-			attributeComplement := &AttributeDetail{
-				StateFileName: StateFileName(data.StateFile),
-				CloudDivision: terraformerResource.CloudDivision,
-				ModuleName:    data.Module,
-				ResourceType:  data.Type,
-				ResourceName:  data.Name,
-			}
+	attributeDifferences := make([]AttributeDifference, 0)
+	for _, differences := range differencesByChunk {
+		attributeDifferences = append(attributeDifferences, differences...)
+	}
 
-			driftedResources, resourcesChanged, err := compareFlatAttributesAndGetDrifted(terraformInstanceConverted, terraformerResource.AttributesFlat, attributeComplement)
-			if err != nil {
-				return nil, fmt.Errorf("[compareFlatAttributesAndGetDrifted]%v", err)
-			}
+	return attributeDifferences, nil
+}
 
-			if resourcesChanged {
-				attributeDifferences = append(attributeDifferences, driftedResources...)
-			}
+// compareResource compares a single resource's Terraform state representation with its cloud representation,
+// and returns the drifted attributes, if any.
+func compareResource(
+	data TerraformStateUniqueResourceData,
+	terraformerResource TerraformerUniqueResourceData,
+	idMappings ResourceIDMappings,
+) ([]AttributeDifference, error) {
+	terraformInstanceConverted, err := convertNestedMapToFlatAttributes(data.Attributes)
+	if err != nil {
+		return nil, fmt.Errorf("[convertNestedMapToFlatAttributes]%v", err)
+	}
+
+	attributeComplement := &AttributeDetail{
+		StateFileName:     StateFileName(data.StateFile),
+		CloudDivision:     terraformerResource.CloudDivision,
+		ModuleName:        data.Module,
+		ResourceType:      data.Type,
+		ResourceName:      data.Name,
+		InstanceKey:       instanceKeySuffix(data.IndexKey),
+		CloudResourceName: terraformerResource.Name,
+	}
+
+	driftedResources, resourcesChanged, err := compareFlatAttributesAndGetDrifted(terraformInstanceConverted, terraformerResource.AttributesFlat, attributeComplement, idMappings)
+	if err != nil {
+		return nil, fmt.Errorf("[compareFlatAttributesAndGetDrifted]%v", err)
+	}
+
+	if !resourcesChanged {
+		return nil, nil
+	}
+
+	for i, difference := range driftedResources {
+		if !strings.Contains(difference.AttributeName, ".") {
+			driftedResources[i].ValueType = stateValueType(data.Attributes[difference.AttributeName])
 		}
 	}
+	return driftedResources, nil
+}
 
-	return attributeDifferences, nil
+// stateValueType returns the type of a top level attribute's value decoded from the Terraform state, empty when it
+// is not a primitive value.
+func stateValueType(value interface{}) string {
+	switch value.(type) {
+	case bool:
+		return "bool"
+	case float32, float64, int, int64, json.Number:
+		return "number"
+	case string:
+		return "string"
+	}
+	return ""
 }
 
 // compareFlatAttributesAndGetDrifted compares the attributes of remoteResourceAttributes and terraformerAttributes,
 // and returns a slice of AttributeDifference with any differences found between the two attribute maps.
-// It also returns a boolean value indicating if any differences were found.
-func compareFlatAttributesAndGetDrifted(terraformResourceAttributes map[string]string, terraformerAttributes map[string]string, complement *AttributeDetail) ([]AttributeDifference, bool, error) {
+// Values are compared once normalized by type, and attributes that are unset on one side and empty on the other
+// are not considered drifted. It also returns a boolean value indicating if any differences were found.
+func compareFlatAttributesAndGetDrifted(
+	terraformResourceAttributes map[string]string,
+	terraformerAttributes map[string]string,
+	complement *AttributeDetail,
+	idMappings ResourceIDMappings,
+) ([]AttributeDifference, bool, error) {
 	var differences []AttributeDifference
 	resourcesChanged := false
 
@@ -83,9 +185,9 @@ func compareFlatAttributesAndGetDrifted(terraformResourceAttributes map[string]s
 		return nil, true, fmt.Errorf("[parseRegionFromTfStateMap]%v", err)
 	}
 
-	id, err := ResourceIDCalculator(terraformerAttributes, cloudProvider, complement.ResourceType)
+	id, err := idMappings.Calculate(terraformerAttributes, cloudProvider, complement.ResourceType)
 	if err != nil {
-		return nil, true, fmt.Errorf("[resourcesCalculator.ResourceIDCalculator]%v", err)
+		return nil, true, fmt.Errorf("[idMappings.Calculate]%v", err)
 	}
 
 	// case where the cloud representation of the resource has an attribute that is different from terraform
@@ -97,7 +199,16 @@ func compareFlatAttributesAndGetDrifted(terraformResourceAttributes map[string]s
 		}
 
 		terraformValue, ok := terraformResourceAttributes[attribute]
-		if !ok || terraformValue != value {
+		if !ok && isEmptyAttributeValue(value) {
+			continue
+		}
+
+		if !ok || !attributeValuesEqual(terraformValue, value) {
+			changeType := AttributeChangeUpdated
+			if !ok {
+				changeType = AttributeChangeAdded
+			}
+
 			resourcesChanged = true
 			differences = append(differences, AttributeDifference{
 				AttributeName:   attribute,
@@ -105,6 +216,7 @@ func compareFlatAttributesAndGetDrifted(terraformResourceAttributes map[string]s
 				CloudValue:      value,
 				InstanceID:      id,
 				InstanceRegion:  region,
+				ChangeType:      changeType,
 				AttributeDetail: *complement,
 			})
 		}
@@ -112,7 +224,7 @@ func compareFlatAttributesAndGetDrifted(terraformResourceAttributes map[string]s
 
 	// case where terraform has an attribute that the cloud representation of the resource does not
 	for attribute, terraformValue := range terraformResourceAttributes {
-		if _, ok := terraformerAttributes[attribute]; !ok && !strings.ContainsAny(attribute, "#%") {
+		if _, ok := terraformerAttributes[attribute]; !ok && !strings.ContainsAny(attribute, "#%") && !isEmptyAttributeValue(terraformValue) {
 			resourcesChanged = true
 
 			differences = append(differences, AttributeDifference{
@@ -121,6 +233,7 @@ func compareFlatAttributesAndGetDrifted(terraformResourceAttributes map[string]s
 				CloudValue:      "",
 				InstanceID:      id,
 				InstanceRegion:  region,
+				ChangeType:      AttributeChangeRemoved,
 				AttributeDetail: *complement,
 			})
 		}
@@ -134,10 +247,14 @@ func ParseRegionFromTfStateMap(attributes map[string]string, cloudProvider strin
 	switch cloudProvider {
 	case "aws":
 		return extractRegionFromAWSAttributes(attributes)
-	case "azurerm":
+	case "azurerm", "datadog", "okta":
 		return "", nil
 	case "google":
 		return attributes["location"], nil
+	case "oci":
+		return extractRegionFromOCIAttributes(attributes), nil
+	case "digitalocean":
+		return attributes["region"], nil
 	default:
 		return "", fmt.Errorf("unknown cloud provider: %s", cloudProvider)
 	}
@@ -158,6 +275,23 @@ func extractRegionFromAWSAttributes(attributes map[string]string) (string, error
 	return "us-east-1", nil
 }
 
+// extractRegionFromOCIAttributes extracts the region identifier, e.g. us-ashburn-1, from the OCID of an OCI resource,
+// which has the format ocid1.<resource type>.<realm>.<region>.<unique id>. The region of an OCID is usually the
+// region's three-letter key, e.g. iad, and is otherwise its identifier. The region is empty for tenancy-wide
+// resources.
+func extractRegionFromOCIAttributes(attributes map[string]string) string {
+	ocidSplit := strings.Split(attributes["id"], ".")
+	if len(ocidSplit) < 5 {
+		return ""
+	}
+
+	region := strings.ToLower(ocidSplit[3])
+	if identifier, ok := terraformValueObjects.OciRegionKeys[region]; ok {
+		return identifier
+	}
+	return region
+}
+
 // stringMapsEqual compares two maps with string keys and string values for equality.
 // It returns true if both maps have the same length and corresponding key-value pairs,
 // and false otherwise.
@@ -236,9 +370,9 @@ func recursiveToFlatAttributes(
 			if value != nil {
 				switch t := value.(type) {
 				case float32:
-					output[currentBase+key] = strconv.Itoa(int(value.(float32)))
+					output[currentBase+key] = strconv.FormatFloat(float64(value.(float32)), 'f', -1, 32)
 				case float64:
-					output[currentBase+key] = strconv.Itoa(int(value.(float64)))
+					output[currentBase+key] = strconv.FormatFloat(value.(float64), 'f', -1, 64)
 				case bool:
 					output[currentBase+key] = strconv.FormatBool(value.(bool))
 				case int:
@@ -265,6 +399,10 @@ func recursiveToFlatAttributes(
 			switch t := value.(type) {
 			case string:
 				output[currentBase+strconv.Itoa(i)] = value.(string)
+			case float64:
+				output[currentBase+strconv.Itoa(i)] = strconv.FormatFloat(value.(float64), 'f', -1, 64)
+			case bool:
+				output[currentBase+strconv.Itoa(i)] = strconv.FormatBool(value.(bool))
 			case []interface{}:
 				err := recursiveToFlatAttributes(output, currentBase+strconv.Itoa(i), false, value.([]interface{}), nil)
 				if err != nil {
@@ -56,12 +56,15 @@ func TestGetExistentResourcesHaveChanged(t *testing.T) {
 		TerraformValue: "modified-dragondrop-modules",
 		CloudValue:     "id_1",
 		InstanceID:     "id_1",
+		ChangeType:     AttributeChangeUpdated,
+		ValueType:      "string",
 		AttributeDetail: AttributeDetail{
-			StateFileName: "My State File",
-			CloudDivision: "google-cloud-division",
-			ModuleName:    "root",
-			ResourceType:  "google_example",
-			ResourceName:  "my_resource",
+			StateFileName:     "My State File",
+			CloudDivision:     "google-cloud-division",
+			ModuleName:        "root",
+			ResourceType:      "google_example",
+			ResourceName:      "my_resource",
+			CloudResourceName: "my_resource",
 		},
 	},
 	)
@@ -104,12 +107,15 @@ func TestGetExistentResourcesHaveChanged_MoreThanOneAttribute(t *testing.T) {
 		TerraformValue: "id_2",
 		CloudValue:     "id_1",
 		InstanceID:     "id_1",
+		ChangeType:     AttributeChangeUpdated,
+		ValueType:      "string",
 		AttributeDetail: AttributeDetail{
-			CloudDivision: "google-cloud-division",
-			StateFileName: "My State File",
-			ModuleName:    "root",
-			ResourceType:  "google_example",
-			ResourceName:  "my_resource",
+			CloudDivision:     "google-cloud-division",
+			StateFileName:     "My State File",
+			ModuleName:        "root",
+			ResourceType:      "google_example",
+			ResourceName:      "my_resource",
+			CloudResourceName: "my_resource",
 		},
 	},
 	)
@@ -119,17 +125,62 @@ func TestGetExistentResourcesHaveChanged_MoreThanOneAttribute(t *testing.T) {
 		TerraformValue: "456",
 		CloudValue:     "123",
 		InstanceID:     "id_1",
+		ChangeType:     AttributeChangeUpdated,
+		ValueType:      "string",
 		AttributeDetail: AttributeDetail{
-			CloudDivision: "google-cloud-division",
-			StateFileName: "My State File",
-			ModuleName:    "root",
-			ResourceType:  "google_example",
-			ResourceName:  "my_resource",
+			CloudDivision:     "google-cloud-division",
+			StateFileName:     "My State File",
+			ModuleName:        "root",
+			ResourceType:      "google_example",
+			ResourceName:      "my_resource",
+			CloudResourceName: "my_resource",
 		},
 	},
 	)
 }
 
+func TestGetExistentResourcesHaveChanged_InstanceKeys(t *testing.T) {
+	detector := &ManagedResourcesDriftDetector{concurrency: 2}
+
+	// Given
+	terraformerResourcesIDToData := TerraformerResourceIDToData{}
+	stateFileResourcesIDToData := TerraformStateResourceIDToData{}
+	for id, indexKey := range map[string]interface{}{"id_a": "a", "id_b": "b", "id_0": float64(0)} {
+		terraformerResourcesIDToData["google_example."+id] = TerraformerUniqueResourceData{
+			CloudDivision:  "google-cloud-division",
+			Type:           "google_example",
+			Name:           "tfer--" + id,
+			AttributesFlat: map[string]string{"id": id, "size": "2"},
+		}
+		stateFileResourcesIDToData["google_example."+id] = TerraformStateUniqueResourceData{
+			StateFile:  "My State File",
+			Module:     "root",
+			Type:       "google_example",
+			Name:       "my_resource",
+			IndexKey:   indexKey,
+			Attributes: map[string]interface{}{"id": id, "size": float64(1)},
+		}
+	}
+
+	// When
+	differences, err := detector.identifyResourceDifferences(terraformerResourcesIDToData, stateFileResourcesIDToData)
+
+	// Then
+	require.NoError(t, err)
+	require.Len(t, differences, 3)
+
+	instanceAddresses := map[string]string{}
+	for _, difference := range differences {
+		instanceAddresses[difference.InstanceID] = difference.InstanceAddress()
+	}
+	assert.Equal(t, map[string]string{
+		"id_a": `google_example.my_resource["a"]`,
+		"id_b": `google_example.my_resource["b"]`,
+		"id_0": "google_example.my_resource[0]",
+	}, instanceAddresses)
+	assert.Len(t, buildAttributeDiffs(differences), 3)
+}
+
 func TestConvertNestedMapToFlatAttributes(t *testing.T) {
 	// Given
 	input := map[string]interface{}{
@@ -521,7 +572,7 @@ func Test_compareAttributesAndGetDrifted(t *testing.T) {
 	}
 
 	// When
-	differences, resourcesChanged, err := compareFlatAttributesAndGetDrifted(remoteResourceAttributes, terraformerResourceAttributes, attributeComplement)
+	differences, resourcesChanged, err := compareFlatAttributesAndGetDrifted(remoteResourceAttributes, terraformerResourceAttributes, attributeComplement, nil)
 	if err != nil {
 		t.Errorf("Error should be nil, got: %v", err)
 	}
@@ -562,7 +613,7 @@ func Test_compareAttributesAndGetDrifted_OneAttribute(t *testing.T) {
 	}
 
 	// When
-	differences, resourcesChanged, err := compareFlatAttributesAndGetDrifted(remoteResourceAttributes, terraformerResourceAttributes, attributeComplement)
+	differences, resourcesChanged, err := compareFlatAttributesAndGetDrifted(remoteResourceAttributes, terraformerResourceAttributes, attributeComplement, nil)
 	if err != nil {
 		t.Errorf("Error should be nil, got: %v", err)
 	}
@@ -575,6 +626,7 @@ func Test_compareAttributesAndGetDrifted_OneAttribute(t *testing.T) {
 		TerraformValue: "OldValue",
 		CloudValue:     "NewValue",
 		InstanceID:     "projects/_/buckets/dragondrop-modules",
+		ChangeType:     AttributeChangeUpdated,
 		AttributeDetail: AttributeDetail{
 			StateFileName: "state_file_name",
 			ModuleName:    "module_name",
@@ -614,7 +666,7 @@ func Test_compareAttributesAndGetDrifted_MoreThanOneAttributes(t *testing.T) {
 	}
 
 	// When
-	differences, resourcesChanged, err := compareFlatAttributesAndGetDrifted(remoteResourceAttributes, terraformerResourceAttributes, attributeComplement)
+	differences, resourcesChanged, err := compareFlatAttributesAndGetDrifted(remoteResourceAttributes, terraformerResourceAttributes, attributeComplement, nil)
 	if err != nil {
 		t.Errorf("Error should be nil, got: %v", err)
 	}
@@ -627,6 +679,7 @@ func Test_compareAttributesAndGetDrifted_MoreThanOneAttributes(t *testing.T) {
 		TerraformValue: "",
 		CloudValue:     "NewValue",
 		InstanceID:     "projects/_/buckets/dragondrop-modules",
+		ChangeType:     AttributeChangeAdded,
 		AttributeDetail: AttributeDetail{
 			StateFileName: "state_file_name",
 			ModuleName:    "module_name",
@@ -639,6 +692,7 @@ func Test_compareAttributesAndGetDrifted_MoreThanOneAttributes(t *testing.T) {
 		TerraformValue: "OldValue",
 		CloudValue:     "NewValue",
 		InstanceID:     "projects/_/buckets/dragondrop-modules",
+		ChangeType:     AttributeChangeUpdated,
 		AttributeDetail: AttributeDetail{
 			StateFileName: "state_file_name",
 			ModuleName:    "module_name",
@@ -651,6 +705,7 @@ func Test_compareAttributesAndGetDrifted_MoreThanOneAttributes(t *testing.T) {
 		TerraformValue: "0",
 		CloudValue:     "NewValue",
 		InstanceID:     "projects/_/buckets/dragondrop-modules",
+		ChangeType:     AttributeChangeUpdated,
 		AttributeDetail: AttributeDetail{
 			StateFileName: "state_file_name",
 			ModuleName:    "module_name",
@@ -692,7 +747,7 @@ func Test_compareAttributesAndGetDrifted_DeleteAttribute(t *testing.T) {
 	}
 
 	// When
-	differences, resourcesChanged, err := compareFlatAttributesAndGetDrifted(remoteResourceAttributes, terraformerResourceAttributes, attributeComplement)
+	differences, resourcesChanged, err := compareFlatAttributesAndGetDrifted(remoteResourceAttributes, terraformerResourceAttributes, attributeComplement, nil)
 	if err != nil {
 		t.Errorf("Error should be nil, got: %v", err)
 	}
@@ -705,6 +760,7 @@ func Test_compareAttributesAndGetDrifted_DeleteAttribute(t *testing.T) {
 		TerraformValue: "deleted_value",
 		CloudValue:     "",
 		InstanceID:     "projects/_/buckets/dragondrop-modules",
+		ChangeType:     AttributeChangeRemoved,
 		AttributeDetail: AttributeDetail{
 			StateFileName: "state_file_name",
 			ModuleName:    "module_name",
@@ -861,3 +917,58 @@ func TestExtractRegionFromAWSAttributes(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "us-east-1", region)
 }
+
+func TestExtractRegionFromOCIAttributes(t *testing.T) {
+	// Given, a regional resource
+	ociAttributes := map[string]string{
+		"id": "ocid1.instance.oc1.iad.anuwcljrabcdefg",
+	}
+
+	// When
+	region := extractRegionFromOCIAttributes(ociAttributes)
+
+	// Then
+	require.Equal(t, "us-ashburn-1", region)
+
+	// Given a resource in another region
+	ociAttributes = map[string]string{
+		"id": "ocid1.instance.oc1.phx.anyhqljrabcdefg",
+	}
+
+	// When
+	region = extractRegionFromOCIAttributes(ociAttributes)
+
+	// Then
+	require.Equal(t, "us-phoenix-1", region)
+
+	// Given a resource whose OCID holds the region identifier
+	ociAttributes = map[string]string{
+		"id": "ocid1.bucket.oc1.uk-london-1.aaaaaaaabcdefg",
+	}
+
+	// When
+	region = extractRegionFromOCIAttributes(ociAttributes)
+
+	// Then
+	require.Equal(t, "uk-london-1", region)
+
+	// Given a tenancy-wide resource
+	ociAttributes = map[string]string{
+		"id": "ocid1.compartment.oc1..aaaaaaaabcdefg",
+	}
+
+	// When
+	region = extractRegionFromOCIAttributes(ociAttributes)
+
+	// Then
+	require.Equal(t, "", region)
+
+	// Given no id in map
+	ociAttributes = map[string]string{}
+
+	// When
+	region = extractRegionFromOCIAttributes(ociAttributes)
+
+	// Then
+	require.Equal(t, "", region)
+}
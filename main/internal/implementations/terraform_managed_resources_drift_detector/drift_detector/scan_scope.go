@@ -0,0 +1,138 @@
+package driftDetector
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+)
+
+// divisionScanScopesPath is the mapping file listing the resource types and regions successfully scanned within each
+// division.
+const divisionScanScopesPath = "mappings/division-scan-scopes.json"
+
+// ScanScope is the extent of the scan of a division.
+type ScanScope struct {
+	// Regions are the regions scanned, empty when the provider's resources are not scanned by region.
+	Regions []string `json:"regions"`
+
+	// ResourceTypes are the resource types scanned, excluding those of resource groups that failed to import.
+	ResourceTypes []string `json:"resourceTypes"`
+}
+
+// LoadScanScopes reads the extent of the scan of each division, keyed by the full provider-division name. Returns
+// nil when the scan did not record its extent.
+func LoadScanScopes(store artifacts.Store) (map[string]ScanScope, error) {
+	content, err := store.ReadFile(divisionScanScopesPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[load_scan_scopes][artifacts.ReadFile]%w", err)
+	}
+
+	scanScopes := map[string]ScanScope{}
+	err = json.Unmarshal(content, &scanScopes)
+	if err != nil {
+		return nil, fmt.Errorf("[load_scan_scopes][json.Unmarshal]%w", err)
+	}
+	return scanScopes, nil
+}
+
+// scannedDivisionAccounts returns the accounts, projects or subscriptions of the resources scanned within each
+// division, keyed by the full provider-division name.
+func scannedDivisionAccounts(terraformerResources TerraformerResourceIDToData) map[string]map[string]bool {
+	divisionAccounts := map[string]map[string]bool{}
+	for _, data := range terraformerResources {
+		account := resourceAccount(strings.Split(data.Type, "_")[0], data.AttributesFlat)
+		if account == "" {
+			continue
+		}
+		if divisionAccounts[data.CloudDivision] == nil {
+			divisionAccounts[data.CloudDivision] = map[string]bool{}
+		}
+		divisionAccounts[data.CloudDivision][account] = true
+	}
+	return divisionAccounts
+}
+
+// isWithinScanScope returns true if the state resource is of a type, within a region and within the account of a
+// division that was successfully scanned, so that its absence from the scan means that it was deleted.
+func isWithinScanScope(data TerraformStateUniqueResourceData, scanScopes map[string]ScanScope, divisionAccounts map[string]map[string]bool) bool {
+	provider := strings.Split(data.Type, "_")[0]
+	attributes := topLevelStringAttributes(data.Attributes)
+	region := stateResourceRegion(provider, attributes)
+	account := resourceAccount(provider, attributes)
+
+	for division, scope := range scanScopes {
+		if !strings.HasPrefix(division, provider+"-") || !containsFold(scope.ResourceTypes, data.Type) {
+			continue
+		}
+		if region != "" && len(scope.Regions) > 0 && !containsFold(scope.Regions, region) {
+			continue
+		}
+		if account != "" && !divisionAccounts[division][account] {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// stateResourceRegion returns the region of a state resource, empty when it is global or its region is unknown.
+func stateResourceRegion(provider string, attributes map[string]string) string {
+	if provider == "aws" {
+		if region, ok := attributes["region"]; ok {
+			return region
+		}
+		if arnSplit := strings.Split(attributes["arn"], ":"); len(arnSplit) > 3 {
+			return arnSplit[3]
+		}
+		return ""
+	}
+
+	region, _ := ParseRegionFromTfStateMap(attributes, provider)
+	return region
+}
+
+// resourceAccount returns the AWS account, GCP project or Azure subscription of a resource, empty when unknown.
+func resourceAccount(provider string, attributes map[string]string) string {
+	switch provider {
+	case "aws":
+		if arnSplit := strings.Split(attributes["arn"], ":"); len(arnSplit) > 4 {
+			return arnSplit[4]
+		}
+	case "google":
+		return attributes["project"]
+	case "azurerm":
+		idSplit := strings.Split(attributes["id"], "/")
+		if len(idSplit) > 2 && strings.EqualFold(idSplit[1], "subscriptions") {
+			return strings.ToLower(idSplit[2])
+		}
+	}
+	return ""
+}
+
+// topLevelStringAttributes returns the top level string attributes of a state resource instance.
+func topLevelStringAttributes(attributes map[string]interface{}) map[string]string {
+	stringAttributes := map[string]string{}
+	for key, value := range attributes {
+		if stringValue, ok := value.(string); ok {
+			stringAttributes[key] = stringValue
+		}
+	}
+	return stringAttributes
+}
+
+// containsFold returns true if values contains value, ignoring case.
+func containsFold(values []string, value string) bool {
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package driftDetector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestManagedResourcesDriftDetector_identifyDeletedResources_OutsideScanScope(t *testing.T) {
+	// Given
+	detector := &ManagedResourcesDriftDetector{
+		scanScopes: map[string]ScanScope{
+			"aws-prod": {
+				Regions:       []string{"us-east-1"},
+				ResourceTypes: []string{"aws_s3_bucket", "aws_sqs_queue"},
+			},
+		},
+	}
+	terraformerResources := TerraformerResourceIDToData{
+		"arn:aws:sqs:us-east-1:111111111111:scanned": TerraformerUniqueResourceData{
+			CloudDivision: "aws-prod",
+			Type:          "aws_sqs_queue",
+			AttributesFlat: map[string]string{
+				"arn": "arn:aws:sqs:us-east-1:111111111111:scanned",
+			},
+		},
+	}
+	stateResource := func(resourceType string, name string, arn string) TerraformStateUniqueResourceData {
+		return TerraformStateUniqueResourceData{
+			StateFile:  "prod.tfstate",
+			Type:       resourceType,
+			Name:       name,
+			Module:     "root",
+			Attributes: map[string]interface{}{"id": name, "arn": arn},
+		}
+	}
+	terraformStateResources := TerraformStateResourceIDToData{
+		"deleted":          stateResource("aws_sqs_queue", "deleted", "arn:aws:sqs:us-east-1:111111111111:deleted"),
+		"global":           stateResource("aws_s3_bucket", "global", "arn:aws:s3:::global"),
+		"unscanned-type":   stateResource("aws_lambda_function", "unscanned_type", "arn:aws:lambda:us-east-1:111111111111:function:example"),
+		"unscanned-region": stateResource("aws_sqs_queue", "unscanned_region", "arn:aws:sqs:eu-west-1:111111111111:other-region"),
+		"other-account":    stateResource("aws_sqs_queue", "other_account", "arn:aws:sqs:us-east-1:222222222222:other-account"),
+		"failed-group":     stateResource("aws_dynamodb_table", "failed_group", "arn:aws:dynamodb:us-east-1:111111111111:table/example"),
+	}
+
+	// When
+	deletedResources, err := detector.identifyDeletedResources(terraformerResources, terraformStateResources)
+
+	// Then
+	require.NoError(t, err)
+	require.Len(t, deletedResources, 2)
+	require.Equal(t, "global", deletedResources[0].InstanceID)
+	require.Equal(t, "deleted", deletedResources[1].InstanceID)
+}
+
+func TestIsWithinScanScope_UnregionalProvider(t *testing.T) {
+	// Given
+	scanScopes := map[string]ScanScope{
+		"azurerm-prod": {ResourceTypes: []string{"azurerm_storage_account"}},
+	}
+	divisionAccounts := map[string]map[string]bool{
+		"azurerm-prod": {"sub-1": true},
+	}
+	inScope := TerraformStateUniqueResourceData{
+		Type:       "azurerm_storage_account",
+		Attributes: map[string]interface{}{"id": "/subscriptions/SUB-1/resourceGroups/rg/providers/x", "location": "westeurope"},
+	}
+	otherSubscription := TerraformStateUniqueResourceData{
+		Type:       "azurerm_storage_account",
+		Attributes: map[string]interface{}{"id": "/subscriptions/sub-2/resourceGroups/rg/providers/x"},
+	}
+
+	// When / Then
+	require.True(t, isWithinScanScope(inScope, scanScopes, divisionAccounts))
+	require.False(t, isWithinScanScope(otherSubscription, scanScopes, divisionAccounts))
+}
@@ -0,0 +1,87 @@
+package driftDetector
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/sensitiveattributes"
+)
+
+// SensitiveValueMask replaces the values of sensitive attributes within drift output.
+const SensitiveValueMask = sensitiveattributes.Mask
+
+// SensitivePathStep is a single step of the path to a sensitive attribute, as recorded within the
+// sensitive_attributes of a Terraform state resource instance.
+type SensitivePathStep struct {
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// ParseSensitiveAttributePatterns compiles the shared default and the user-provided patterns for sensitive attribute
+// paths into case-insensitive regular expressions.
+func ParseSensitiveAttributePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled, err := sensitiveattributes.Compile(patterns)
+	if err != nil {
+		return nil, fmt.Errorf("[parse_sensitive_attribute_patterns]%w", err)
+	}
+	return compiled, nil
+}
+
+// sensitivePathsFromState converts the sensitive_attributes of a state resource instance into flat attribute paths,
+// e.g. [{"type": "get_attr", "value": "settings"}, {"type": "index", "value": 0}] becomes settings.0.
+func sensitivePathsFromState(sensitiveAttributes [][]SensitivePathStep) []string {
+	var paths []string
+
+	for _, steps := range sensitiveAttributes {
+		segments := make([]string, 0, len(steps))
+		for _, step := range steps {
+			switch value := step.Value.(type) {
+			case float64:
+				segments = append(segments, fmt.Sprint(int(value)))
+			default:
+				segments = append(segments, fmt.Sprint(value))
+			}
+		}
+		if len(segments) > 0 {
+			paths = append(paths, strings.Join(segments, "."))
+		}
+	}
+
+	return paths
+}
+
+// isSensitiveAttribute returns true if the provider schema marks attribute as sensitive, if attribute is, or is nested
+// within, a path marked as sensitive within state, or if it matches one of the sensitive attribute patterns.
+func (m *ManagedResourcesDriftDetector) isSensitiveAttribute(resourceType string, attribute string, stateSensitivePaths []string) bool {
+	if m.resourceSchemas.isSensitive(resourceType, attribute) {
+		return true
+	}
+
+	for _, sensitivePath := range stateSensitivePaths {
+		if attribute == sensitivePath || strings.HasPrefix(attribute, sensitivePath+".") {
+			return true
+		}
+	}
+
+	return sensitiveattributes.Matches(attribute, m.sensitivePatterns)
+}
+
+// maskSensitiveDifferences replaces the Terraform and cloud values of sensitive drifted attributes.
+func (m *ManagedResourcesDriftDetector) maskSensitiveDifferences(differences []AttributeDifference, data TerraformStateUniqueResourceData) []AttributeDifference {
+	for i, difference := range differences {
+		if !m.isSensitiveAttribute(difference.ResourceType, difference.AttributeName, data.SensitivePaths) {
+			continue
+		}
+
+		differences[i].Sensitive = true
+		if difference.TerraformValue != "" {
+			differences[i].TerraformValue = SensitiveValueMask
+		}
+		if difference.CloudValue != "" {
+			differences[i].CloudValue = SensitiveValueMask
+		}
+	}
+
+	return differences
+}
@@ -0,0 +1,68 @@
+package driftDetector
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSensitivePathsFromState(t *testing.T) {
+	// Given
+	var instance ResourceInstance
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"attributes": {"id": "db"},
+		"sensitive_attributes": [
+			[{"type": "get_attr", "value": "password"}],
+			[{"type": "get_attr", "value": "settings"}, {"type": "index", "value": 0}, {"type": "get_attr", "value": "key"}]
+		]
+	}`), &instance))
+
+	// When
+	paths := sensitivePathsFromState(instance.SensitiveAttributes)
+
+	// Then
+	assert.Equal(t, []string{"password", "settings.0.key"}, paths)
+	assert.Nil(t, sensitivePathsFromState(nil))
+}
+
+func TestParseSensitiveAttributePatterns_Invalid(t *testing.T) {
+	// When
+	_, err := ParseSensitiveAttributePatterns([]string{"(unclosed"})
+
+	// Then
+	assert.Error(t, err)
+}
+
+func TestMaskSensitiveDifferences(t *testing.T) {
+	// Given
+	patterns, err := ParseSensitiveAttributePatterns([]string{"^custom_data$"})
+	require.NoError(t, err)
+	m := ManagedResourcesDriftDetector{sensitivePatterns: patterns}
+
+	data := TerraformStateUniqueResourceData{SensitivePaths: []string{"settings.0"}}
+	differences := []AttributeDifference{
+		{AttributeName: "settings.0.value", TerraformValue: "a", CloudValue: "b"},
+		{AttributeName: "master_password", TerraformValue: "old", CloudValue: "new"},
+		{AttributeName: "custom_data", TerraformValue: "", CloudValue: "script"},
+		{AttributeName: "storage_account.0.primary_key", TerraformValue: "key", CloudValue: ""},
+		{AttributeName: "instance_class", TerraformValue: "db.t3.micro", CloudValue: "db.t3.large"},
+		{AttributeName: "token_expiry_days", TerraformValue: "1", CloudValue: "2"},
+	}
+
+	// When
+	output := m.maskSensitiveDifferences(differences, data)
+
+	// Then
+	for _, difference := range output[:4] {
+		assert.True(t, difference.Sensitive, difference.AttributeName)
+		assert.NotContains(t, []string{"a", "b", "old", "new", "script", "key"}, difference.TerraformValue)
+		assert.NotContains(t, []string{"a", "b", "old", "new", "script", "key"}, difference.CloudValue)
+	}
+	assert.Equal(t, "", output[2].TerraformValue)
+	assert.Equal(t, "", output[3].CloudValue)
+	assert.False(t, output[4].Sensitive)
+	assert.Equal(t, "db.t3.large", output[4].CloudValue)
+	assert.False(t, output[5].Sensitive)
+}
@@ -0,0 +1,102 @@
+package driftDetector
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// streamStateResources decodes the "resources" array of a state file one element at a time, calling decodeResource
+// with the decoder positioned at each element. Other top level keys are skipped without being held in memory, so
+// that multi-hundred-MB state files are never loaded whole.
+func streamStateResources(reader io.Reader, decodeResource func(decoder *json.Decoder) error) error {
+	decoder := json.NewDecoder(reader)
+
+	if err := expectDelimiter(decoder, '{'); err != nil {
+		return err
+	}
+
+	for decoder.More() {
+		keyToken, err := decoder.Token()
+		if err != nil {
+			return fmt.Errorf("[decoder.Token]%w", err)
+		}
+
+		if key, _ := keyToken.(string); key != "resources" {
+			var skipped json.RawMessage
+			if err := decoder.Decode(&skipped); err != nil {
+				return fmt.Errorf("[decoder.Decode][key %v]%w", keyToken, err)
+			}
+			continue
+		}
+
+		token, err := decoder.Token()
+		if err != nil {
+			return fmt.Errorf("[decoder.Token]%w", err)
+		}
+		// A state file without resources may serialize the array as null.
+		if token == nil {
+			continue
+		}
+		if delimiter, ok := token.(json.Delim); !ok || delimiter != '[' {
+			return fmt.Errorf("[stream_state_resources][expected resources to be an array, got %v]", token)
+		}
+
+		for decoder.More() {
+			if err := decodeResource(decoder); err != nil {
+				return fmt.Errorf("[decodeResource]%w", err)
+			}
+		}
+
+		if err := expectDelimiter(decoder, ']'); err != nil {
+			return err
+		}
+	}
+
+	return expectDelimiter(decoder, '}')
+}
+
+// expectDelimiter reads the next token from decoder and errors if it is not the expected delimiter.
+func expectDelimiter(decoder *json.Decoder, expected json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return fmt.Errorf("[decoder.Token]%w", err)
+	}
+
+	if delimiter, ok := token.(json.Delim); !ok || delimiter != expected {
+		return fmt.Errorf("[expect_delimiter][expected %v, got %v]", expected, token)
+	}
+	return nil
+}
+
+// runBounded calls work for each index in [0, count) with at most concurrency calls running at once, and
+// returns the error of the lowest index that failed.
+func runBounded(count int, concurrency int, work func(i int) error) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	slots := make(chan struct{}, concurrency)
+	errs := make([]error, count)
+
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		slots <- struct{}{}
+
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			errs[i] = work(i)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
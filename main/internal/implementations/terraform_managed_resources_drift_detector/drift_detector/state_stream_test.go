@@ -0,0 +1,101 @@
+package driftDetector
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamStateResources(t *testing.T) {
+	// Given
+	stateFile := `{
+		"version": 4,
+		"outputs": {"bucket": {"value": "logs", "type": "string"}},
+		"resources": [
+			{"mode": "managed", "type": "aws_s3_bucket", "name": "logs", "instances": []},
+			{"mode": "data", "type": "aws_caller_identity", "name": "current", "instances": []}
+		],
+		"check_results": null
+	}`
+
+	// When
+	var names []string
+	err := streamStateResources(strings.NewReader(stateFile), func(decoder *json.Decoder) error {
+		var resource Resource
+		if err := decoder.Decode(&resource); err != nil {
+			return err
+		}
+		names = append(names, resource.Name)
+		return nil
+	})
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, []string{"logs", "current"}, names)
+}
+
+func TestStreamStateResources_InvalidInput(t *testing.T) {
+	// Given
+	inputs := []string{`[]`, `{"resources": {}}`, `{"resources": [`}
+
+	for _, input := range inputs {
+		// When
+		err := streamStateResources(strings.NewReader(input), func(decoder *json.Decoder) error {
+			var resource Resource
+			return decoder.Decode(&resource)
+		})
+
+		// Then
+		assert.Error(t, err, input)
+	}
+}
+
+func TestStreamStateResources_NullResources(t *testing.T) {
+	// Given
+	stateFile := `{"version": 4, "resources": null}`
+
+	// When
+	calls := 0
+	err := streamStateResources(strings.NewReader(stateFile), func(decoder *json.Decoder) error {
+		calls++
+		return nil
+	})
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, 0, calls)
+}
+
+func TestRunBounded(t *testing.T) {
+	// Given
+	var running, maxRunning int32
+	results := make([]int, 10)
+
+	// When
+	err := runBounded(len(results), 3, func(i int) error {
+		current := atomic.AddInt32(&running, 1)
+		for {
+			observed := atomic.LoadInt32(&maxRunning)
+			if current <= observed || atomic.CompareAndSwapInt32(&maxRunning, observed, current) {
+				break
+			}
+		}
+		defer atomic.AddInt32(&running, -1)
+
+		results[i] = i * i
+		if i == 7 || i == 4 {
+			return errors.New("failed")
+		}
+		return nil
+	})
+
+	// Then
+	assert.EqualError(t, err, "failed")
+	assert.LessOrEqual(t, maxRunning, int32(3))
+	assert.Equal(t, 81, results[9])
+}
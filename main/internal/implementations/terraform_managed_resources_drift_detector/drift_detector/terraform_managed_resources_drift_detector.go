@@ -4,30 +4,111 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
+	"regexp"
 
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/baseline"
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
 )
 
+// Config is the configuration of the ManagedResourcesDriftDetector.
+type Config struct {
+	// IgnoreRules are rules of the form "<kind>:<glob>" for drift that is expected and should not be reported, where
+	// kind is one of "type", "address" or "attribute".
+	IgnoreRules []string
+
+	// Concurrency is the number of state files parsed, and resources compared, at once.
+	Concurrency int
+
+	// ResourceIDMappings are user-provided expressions for the unique id of resource types that the built-in
+	// calculation does not cover.
+	ResourceIDMappings ResourceIDMappings
+
+	// SensitiveAttributePatterns are regular expressions, matched case-insensitively against attribute paths, for
+	// attributes whose values are masked within drift output in addition to those marked sensitive by the provider.
+	SensitiveAttributePatterns []string
+
+	// DefaultResourceExclusions are rules for cloud default resources, in addition to the built-in rules, whose drift
+	// is not reported. Rules prefixed by ! override the built-in rules.
+	DefaultResourceExclusions []string
+}
+
 // ManagedResourcesDriftDetector is a type that identifies resources
 // managed by Terraform that have drifted from their expected state.
 type ManagedResourcesDriftDetector struct {
 	// DivisionToProvider is a mapping between a division and the provider that is responsible
 	// for that division.
 	divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider `required:"true"`
+
+	// ignoreRules are the parsed rules for drift that is not reported.
+	ignoreRules []IgnoreRule
+
+	// concurrency is the number of state files parsed, and resources compared, at once.
+	concurrency int
+
+	// resourceIDMappings are user-provided expressions for the unique id of resource types.
+	resourceIDMappings ResourceIDMappings
+
+	// sensitivePatterns match the paths of attributes whose values are masked within drift output.
+	sensitivePatterns []*regexp.Regexp
+
+	// defaultResourcePolicy matches the cloud default resources whose drift is not reported.
+	defaultResourcePolicy DefaultResourcePolicy
+
+	// resourceSchemas are the provider schemas of each resource type, loaded when executed.
+	resourceSchemas ResourceTypeToSchema
+
+	// scanScopes are the extent of the scan of each division, loaded when executed, nil when unknown.
+	scanScopes map[string]ScanScope
+
+	// artifactStore holds the mappings read and written by the drift detector.
+	artifactStore artifacts.Store
 }
 
 // NewManagedResourcesDriftDetector generated a terraformer instance from ManagedResourcesDriftDetector
-func NewManagedResourcesDriftDetector(divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider) *ManagedResourcesDriftDetector {
-	return &ManagedResourcesDriftDetector{
-		divisionToProvider: divisionToProvider,
+func NewManagedResourcesDriftDetector(config Config, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider, artifactStore artifacts.Store) (*ManagedResourcesDriftDetector, error) {
+	ignoreRules, err := ParseIgnoreRules(config.IgnoreRules)
+	if err != nil {
+		return nil, fmt.Errorf("[NewManagedResourcesDriftDetector]%w", err)
+	}
+
+	sensitivePatterns, err := ParseSensitiveAttributePatterns(config.SensitiveAttributePatterns)
+	if err != nil {
+		return nil, fmt.Errorf("[NewManagedResourcesDriftDetector]%w", err)
 	}
+
+	defaultResourcePolicy, err := ParseDefaultResourcePolicy(config.DefaultResourceExclusions)
+	if err != nil {
+		return nil, fmt.Errorf("[NewManagedResourcesDriftDetector]%w", err)
+	}
+
+	return &ManagedResourcesDriftDetector{
+		divisionToProvider:    divisionToProvider,
+		ignoreRules:           ignoreRules,
+		concurrency:           config.Concurrency,
+		resourceIDMappings:    config.ResourceIDMappings,
+		sensitivePatterns:     sensitivePatterns,
+		defaultResourcePolicy: defaultResourcePolicy,
+		artifactStore:         artifactStore,
+	}, nil
 }
 
 // Execute initiates the process of detecting drift in managed resources
 // by comparing the current state of resources with their expected state.
 // It takes a context as input to support cancellation and timeouts.
 func (m *ManagedResourcesDriftDetector) Execute(ctx context.Context, workspaceToDirectory map[string]string) (bool, error) {
+	resourceSchemas, err := loadProviderSchemas(m.artifactStore)
+	if err != nil {
+		return false, fmt.Errorf("[loadProviderSchemas]%w", err)
+	}
+	m.resourceSchemas = resourceSchemas
+
+	scanScopes, err := LoadScanScopes(m.artifactStore)
+	if err != nil {
+		return false, fmt.Errorf("[LoadScanScopes]%w", err)
+	}
+	m.scanScopes = scanScopes
+
 	remoteStateResources, err := m.loadAllRemoteStateFiles(workspaceToDirectory)
 	if err != nil {
 		return false, fmt.Errorf("[m.loadAllRemoteStateFiles]%w", err)
@@ -37,13 +118,19 @@ func (m *ManagedResourcesDriftDetector) Execute(ctx context.Context, workspaceTo
 	if err != nil {
 		return false, fmt.Errorf("[m.loadAllTerraformerStateFiles]%w", err)
 	}
+	m.excludeDefaultResources(terraformerStateResources, remoteStateResources)
 
-	wereDeleted, err := m.identifyAndWriteDeletedResources(terraformerStateResources, remoteStateResources)
+	accepted, err := baseline.LoadFromRepository()
+	if err != nil {
+		return false, fmt.Errorf("[baseline.LoadFromRepository]%w", err)
+	}
+
+	wereDeleted, err := m.identifyAndWriteDeletedResources(terraformerStateResources, remoteStateResources, accepted)
 	if err != nil {
 		return false, fmt.Errorf("[m.identifyAndWriteDeletedResources]%w", err)
 	}
 
-	differencesFound, err := m.identifyAndWriteResourcesDifferences(terraformerStateResources, remoteStateResources)
+	differencesFound, err := m.identifyAndWriteResourcesDifferences(terraformerStateResources, remoteStateResources, accepted, workspaceToDirectory)
 	if err != nil {
 		return false, fmt.Errorf("[m.identifyAndWriteResourcesDifferences]%w", err)
 	}
@@ -52,27 +139,45 @@ func (m *ManagedResourcesDriftDetector) Execute(ctx context.Context, workspaceTo
 }
 
 // identifyAndWriteResourcesDifferences found the resources differences and writes in the mapping file
-func (m *ManagedResourcesDriftDetector) identifyAndWriteResourcesDifferences(terraformerResources TerraformerResourceIDToData, terraformResources TerraformStateResourceIDToData) (bool, error) {
+func (m *ManagedResourcesDriftDetector) identifyAndWriteResourcesDifferences(
+	terraformerResources TerraformerResourceIDToData,
+	terraformResources TerraformStateResourceIDToData,
+	accepted *baseline.Baseline,
+	workspaceToDirectory map[string]string,
+) (bool, error) {
 	differences, err := m.identifyResourceDifferences(terraformerResources, terraformResources)
 	if err != nil {
 		return false, fmt.Errorf("[m.identifyResourceDifferences]%w", err)
 	}
 
+	differences = m.filterIgnoredDifferences(differences)
+	differences = m.filterAcceptedDifferences(differences, accepted)
+	differences = m.annotateRemediationImpact(differences, terraformResources)
+	differences = m.annotateModuleSources(differences, workspaceToDirectory)
+
 	err = m.writeDifferences(differences)
 	if err != nil {
 		return false, fmt.Errorf("[m.writeDifferences]%w", err)
 	}
 
+	err = m.writeAttributeDiffs(differences)
+	if err != nil {
+		return false, fmt.Errorf("[m.writeAttributeDiffs]%w", err)
+	}
+
 	return len(differences) > 0, nil
 }
 
 // identifyAndWriteDeletedResources found the resources differences and writes in the mapping file
-func (m *ManagedResourcesDriftDetector) identifyAndWriteDeletedResources(terraformerResources TerraformerResourceIDToData, terraformResources TerraformStateResourceIDToData) (bool, error) {
+func (m *ManagedResourcesDriftDetector) identifyAndWriteDeletedResources(terraformerResources TerraformerResourceIDToData, terraformResources TerraformStateResourceIDToData, accepted *baseline.Baseline) (bool, error) {
 	deleted, err := m.identifyDeletedResources(terraformerResources, terraformResources)
 	if err != nil {
 		return false, fmt.Errorf("[m.identifyDeletedResources]%w", err)
 	}
 
+	deleted = m.filterIgnoredDeletedResources(deleted)
+	deleted = m.filterAcceptedDeletedResources(deleted, accepted)
+
 	err = m.writeDeletedResources(deleted)
 	if err != nil {
 		return false, fmt.Errorf("[m.writeDeletedResources]%w", err)
@@ -88,7 +193,7 @@ func (m *ManagedResourcesDriftDetector) writeDeletedResources(deleted []DeletedR
 		return fmt.Errorf("[json.MarshalIndent]%w", err)
 	}
 
-	return os.WriteFile("mappings/drift-resources-deleted.json", differencesJSON, 0400)
+	return m.artifactStore.WriteFile("mappings/drift-resources-deleted.json", differencesJSON, 0400)
 }
 
 // writeDifferences writes within a json file the differences between all the drifted resources to render within the PR
@@ -98,5 +203,5 @@ func (m *ManagedResourcesDriftDetector) writeDifferences(differences []Attribute
 		return fmt.Errorf("[json.MarshalIndent]%w", err)
 	}
 
-	return os.WriteFile("mappings/drift-resources-differences.json", differencesJSON, 0400)
+	return m.artifactStore.WriteFile("mappings/drift-resources-differences.json", differencesJSON, 0400)
 }
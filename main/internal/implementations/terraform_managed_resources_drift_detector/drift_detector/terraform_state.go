@@ -1,8 +1,10 @@
 package driftDetector
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 )
 
 // TerraformStateFile represents the structure of a Terraform state file from terraform cloud.
@@ -16,12 +18,17 @@ type TerraformStateResourceIDToData map[string]TerraformStateUniqueResourceData
 
 // TerraformStateUniqueResourceData is a struct for storing the data definition of a single Terraform State resource instance.
 type TerraformStateUniqueResourceData struct {
-	StateFile  string
-	Module     string
-	Type       string
-	Name       string
-	Provider   string
-	Attributes map[string]interface{}
+	StateFile    string
+	Module       string
+	Type         string
+	Name         string
+	Provider     string
+	IndexKey     interface{}
+	Attributes   map[string]interface{}
+	Dependencies []string
+
+	// SensitivePaths are the flat paths of the attributes marked as sensitive by the provider schema.
+	SensitivePaths []string
 }
 
 // Resource represents a Terraform resource within a state file.
@@ -36,32 +43,38 @@ type Resource struct {
 
 // ResourceInstance represents a Terraform resource instance within a state file.
 type ResourceInstance struct {
-	SchemaVersion int                    `json:"schema_version"`
-	Attributes    map[string]interface{} `json:"attributes"`
+	SchemaVersion       int                    `json:"schema_version"`
+	IndexKey            interface{}            `json:"index_key"`
+	Attributes          map[string]interface{} `json:"attributes"`
+	SensitiveAttributes [][]SensitivePathStep  `json:"sensitive_attributes"`
+	Dependencies        []string               `json:"dependencies"`
 }
 
-// loadAllRemoteStateFiles loads from memory the remote state files and aggregates data.
+// loadAllRemoteStateFiles loads from memory the remote state files and aggregates data. State files are
+// parsed concurrently, bounded by the configured concurrency.
 func (m *ManagedResourcesDriftDetector) loadAllRemoteStateFiles(workspaceToDirectory map[string]string) (TerraformStateResourceIDToData, error) {
 	fileNames := make([]string, 0)
 	for workspaceName := range workspaceToDirectory {
 		fileNames = append(fileNames, workspaceName)
 	}
+	sort.Strings(fileNames)
 
-	resources := TerraformStateResourceIDToData{}
-
-	for _, remoteStateFile := range fileNames {
-		fileContent, err := os.ReadFile(fmt.Sprintf("state_files/%v.json", remoteStateFile))
+	resourcesByFile := make([]TerraformStateResourceIDToData, len(fileNames))
+	err := runBounded(len(fileNames), m.concurrency, func(i int) error {
+		fileResources, err := m.loadRemoteStateFile(fileNames[i])
 		if err != nil {
-			return nil, fmt.Errorf("failed to read state file %s: %v", remoteStateFile, err)
+			return fmt.Errorf("[m.loadRemoteStateFile]%w", err)
 		}
 
-		file, err := m.parseRemoteStateFile(fileContent)
-		if err != nil {
-			return nil, err
-		}
-
-		resourcesFromStateFile := m.terraformStateExtractUniqueResourceIDToData(remoteStateFile, file)
+		resourcesByFile[i] = fileResources
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	resources := TerraformStateResourceIDToData{}
+	for _, resourcesFromStateFile := range resourcesByFile {
 		for resourceID, resourceData := range resourcesFromStateFile {
 			resources[resourceID] = resourceData
 		}
@@ -70,23 +83,69 @@ func (m *ManagedResourcesDriftDetector) loadAllRemoteStateFiles(workspaceToDirec
 	return resources, nil
 }
 
+// loadRemoteStateFile streams a single remote state file from disk, extracting each resource as it is decoded.
+func (m *ManagedResourcesDriftDetector) loadRemoteStateFile(stateFileName string) (TerraformStateResourceIDToData, error) {
+	file, err := os.Open(fmt.Sprintf("state_files/%v.json", stateFileName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file %s: %v", stateFileName, err)
+	}
+	defer file.Close()
+
+	outputIDToData := TerraformStateResourceIDToData{}
+	err = streamStateResources(file, func(decoder *json.Decoder) error {
+		var resource Resource
+		if err := decoder.Decode(&resource); err != nil {
+			return fmt.Errorf("failed to parse state file: %v", err)
+		}
+
+		if resource.Module == "" {
+			resource.Module = "root"
+		}
+		m.addTerraformStateResource(outputIDToData, stateFileName, &resource)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[streamStateResources][state file %s]%w", stateFileName, err)
+	}
+
+	return outputIDToData, nil
+}
+
 // terraformStateExtractUniqueResourceIDToData reformats resource data to pull out the attribute "id" as the unique
 // resource identifier.
 func (m *ManagedResourcesDriftDetector) terraformStateExtractUniqueResourceIDToData(stateFileName string, stateFile TerraformStateFile) TerraformStateResourceIDToData {
 	outputIDToData := TerraformStateResourceIDToData{}
 
 	for _, resource := range stateFile.Resources {
-		for _, instance := range resource.Instances {
-			id := fmt.Sprintf("%v.%v", resource.Type, instance.Attributes["id"])
-			outputIDToData[id] = TerraformStateUniqueResourceData{
-				StateFile:  stateFileName,
-				Module:     resource.Module,
-				Type:       resource.Type,
-				Name:       resource.Name,
-				Provider:   resource.Provider,
-				Attributes: instance.Attributes,
-			}
-		}
+		m.addTerraformStateResource(outputIDToData, stateFileName, resource)
 	}
 	return outputIDToData
 }
+
+// addTerraformStateResource adds each instance of resource to outputIDToData, keyed by its unique resource identifier.
+func (m *ManagedResourcesDriftDetector) addTerraformStateResource(outputIDToData TerraformStateResourceIDToData, stateFileName string, resource *Resource) {
+	// Data sources are read rather than managed by Terraform, and so cannot drift.
+	if resource.Mode == "data" {
+		return
+	}
+
+	for _, instance := range resource.Instances {
+		// Instances without an id cannot be matched to the cloud, and would otherwise collide with one another.
+		if instance.Attributes["id"] == nil {
+			continue
+		}
+
+		id := fmt.Sprintf("%v.%v", resource.Type, instance.Attributes["id"])
+		outputIDToData[id] = TerraformStateUniqueResourceData{
+			StateFile:      stateFileName,
+			Module:         resource.Module,
+			Type:           resource.Type,
+			Name:           resource.Name,
+			Provider:       resource.Provider,
+			IndexKey:       instance.IndexKey,
+			Attributes:     instance.Attributes,
+			Dependencies:   instance.Dependencies,
+			SensitivePaths: sensitivePathsFromState(instance.SensitiveAttributes),
+		}
+	}
+}
@@ -30,6 +30,11 @@ func Test_TerraformStateExtractUniqueResourceIdToData(t *testing.T) {
 							"id": "id_2",
 						},
 					},
+					ResourceInstance{
+						SchemaVersion: 1,
+						IndexKey:      "pending",
+						Attributes:    map[string]interface{}{},
+					},
 				},
 			},
 			{
@@ -48,6 +53,21 @@ func Test_TerraformStateExtractUniqueResourceIdToData(t *testing.T) {
 					},
 				},
 			},
+			{
+				Mode:     "data",
+				Module:   "root",
+				Type:     "google_example",
+				Name:     "my_data_source",
+				Provider: "google",
+				Instances: []ResourceInstance{
+					ResourceInstance{
+						SchemaVersion: 1,
+						Attributes: map[string]interface{}{
+							"id": "id_4",
+						},
+					},
+				},
+			},
 		},
 	}
 
@@ -1,8 +1,10 @@
 package driftDetector
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 )
 
 // TerraformerStateFile represents the structure of a Terraform state file generated by terraformer.
@@ -41,29 +43,32 @@ type TerraformerInstance struct {
 	AttributesFlat map[string]string `json:"attributes_flat"`
 }
 
-// loadAllTerraformerStateFiles loads from memory the terraformer state files
+// loadAllTerraformerStateFiles loads from memory the terraformer state files. State files are parsed
+// concurrently, bounded by the configured concurrency.
 func (m *ManagedResourcesDriftDetector) loadAllTerraformerStateFiles() (TerraformerResourceIDToData, error) {
 	fileNames := make([]string, 0)
 	for division, provider := range m.divisionToProvider {
 		fullDivisionName := fmt.Sprintf("%v-%v", provider, division)
 		fileNames = append(fileNames, fullDivisionName)
 	}
+	sort.Strings(fileNames)
 
-	resources := TerraformerResourceIDToData{}
-
-	for _, divisionName := range fileNames {
-		fileContent, err := os.ReadFile(fmt.Sprintf("current_cloud/%v/terraform.tfstate", divisionName))
-		if err != nil {
-			return nil, fmt.Errorf("failed to read state file %s: %v", divisionName, err)
-		}
-
-		stateFile, err := ParseTerraformerStateFile(fileContent)
+	resourcesByFile := make([]TerraformerResourceIDToData, len(fileNames))
+	err := runBounded(len(fileNames), m.concurrency, func(i int) error {
+		fileResources, err := m.loadTerraformerStateFile(fileNames[i])
 		if err != nil {
-			return nil, fmt.Errorf("[ParseTerraformerStateFile]%v", err)
+			return fmt.Errorf("[m.loadTerraformerStateFile]%w", err)
 		}
 
-		resourcesFromStateFile := m.extractUniqueResourceIDToData(divisionName, stateFile)
+		resourcesByFile[i] = fileResources
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	resources := TerraformerResourceIDToData{}
+	for _, resourcesFromStateFile := range resourcesByFile {
 		for resourceID, resourceData := range resourcesFromStateFile {
 			resources[resourceID] = resourceData
 		}
@@ -72,24 +77,58 @@ func (m *ManagedResourcesDriftDetector) loadAllTerraformerStateFiles() (Terrafor
 	return resources, nil
 }
 
+// loadTerraformerStateFile streams a single terraformer state file from disk, extracting each resource as it
+// is decoded.
+func (m *ManagedResourcesDriftDetector) loadTerraformerStateFile(divisionName string) (TerraformerResourceIDToData, error) {
+	file, err := os.Open(fmt.Sprintf("current_cloud/%v/terraform.tfstate", divisionName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file %s: %v", divisionName, err)
+	}
+	defer file.Close()
+
+	outputIDToData := TerraformerResourceIDToData{}
+	err = streamStateResources(file, func(decoder *json.Decoder) error {
+		var resource TerraformerResource
+		if err := decoder.Decode(&resource); err != nil {
+			return fmt.Errorf("failed to parse state file: %v", err)
+		}
+
+		if resource.Module == "" {
+			resource.Module = "root"
+		}
+		m.addTerraformerResource(outputIDToData, divisionName, &resource)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[streamStateResources][state file %s]%w", divisionName, err)
+	}
+
+	return outputIDToData, nil
+}
+
 // extractUniqueResourceIDToData reformats resource data to pull out the attribute "id" as the unique
 // resource identifier.
 func (m *ManagedResourcesDriftDetector) extractUniqueResourceIDToData(divisionName string, stateFile TerraformerStateFile) TerraformerResourceIDToData {
 	outputIDToData := TerraformerResourceIDToData{}
 
 	for _, resource := range stateFile.Resources {
-		for _, instance := range resource.Instances {
-			id := fmt.Sprintf("%v.%v", resource.Type, instance.AttributesFlat["id"])
-
-			outputIDToData[id] = TerraformerUniqueResourceData{
-				CloudDivision:  divisionName,
-				Module:         resource.Module,
-				Type:           resource.Type,
-				Name:           resource.Name,
-				Provider:       resource.Provider,
-				AttributesFlat: instance.AttributesFlat,
-			}
-		}
+		m.addTerraformerResource(outputIDToData, divisionName, resource)
 	}
 	return outputIDToData
 }
+
+// addTerraformerResource adds each instance of resource to outputIDToData, keyed by its unique resource identifier.
+func (m *ManagedResourcesDriftDetector) addTerraformerResource(outputIDToData TerraformerResourceIDToData, divisionName string, resource *TerraformerResource) {
+	for _, instance := range resource.Instances {
+		id := fmt.Sprintf("%v.%v", resource.Type, instance.AttributesFlat["id"])
+
+		outputIDToData[id] = TerraformerUniqueResourceData{
+			CloudDivision:  divisionName,
+			Module:         resource.Module,
+			Type:           resource.Type,
+			Name:           resource.Name,
+			Provider:       resource.Provider,
+			AttributesFlat: instance.AttributesFlat,
+		}
+	}
+}
@@ -3,6 +3,7 @@ package terraformManagedResourcesDriftDetector
 import (
 	"context"
 
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
 	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
@@ -14,17 +15,17 @@ type Factory struct {
 
 // Instantiate returns an implementation of interfaces.TerraformManagedResourcesDriftDetector depending on the passed
 // environment specification.
-func (f *Factory) Instantiate(ctx context.Context, environment string, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider) (interfaces.TerraformManagedResourcesDriftDetector, error) {
+func (f *Factory) Instantiate(ctx context.Context, environment string, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider, config driftDetector.Config, artifactStore artifacts.Store) (interfaces.TerraformManagedResourcesDriftDetector, error) {
 	switch environment {
 	case "isolated":
 		return NewIsolatedDriftDetector(), nil
 	default:
-		return f.bootstrappedDriftDetector(ctx, divisionToProvider)
+		return f.bootstrappedDriftDetector(ctx, divisionToProvider, config, artifactStore)
 	}
 }
 
 // bootstrappedDriftDetector creates a complete implementation of the interfaces.TerraformManagedResourcesDriftDetector interface with
 // configuration specified via environment variables.
-func (f *Factory) bootstrappedDriftDetector(ctx context.Context, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider) (interfaces.TerraformManagedResourcesDriftDetector, error) {
-	return driftDetector.NewManagedResourcesDriftDetector(divisionToProvider), nil
+func (f *Factory) bootstrappedDriftDetector(ctx context.Context, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider, config driftDetector.Config, artifactStore artifacts.Store) (interfaces.TerraformManagedResourcesDriftDetector, error) {
+	return driftDetector.NewManagedResourcesDriftDetector(config, divisionToProvider, artifactStore)
 }
@@ -0,0 +1,56 @@
+package terraformSecurity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/baseline"
+)
+
+// suppressAcceptedFindings removes the security findings accepted within the baseline of the cloned repository.
+func suppressAcceptedFindings(resultsPerDivision TFSecResultsPerDivision) (TFSecResultsPerDivision, error) {
+	accepted, err := baseline.LoadFromRepository()
+	if err != nil {
+		return nil, fmt.Errorf("[suppress_accepted_findings][error loading the baseline]%w", err)
+	}
+
+	return filterAcceptedFindings(resultsPerDivision, accepted, time.Now()), nil
+}
+
+// filterAcceptedFindings removes the security findings accepted within the repository's baseline, unless the
+// acceptance has expired by now.
+func filterAcceptedFindings(resultsPerDivision TFSecResultsPerDivision, accepted *baseline.Baseline, now time.Time) TFSecResultsPerDivision {
+	if accepted.IsEmpty() {
+		return resultsPerDivision
+	}
+
+	suppressed := 0
+	filteredPerDivision := TFSecResultsPerDivision{}
+	for division, results := range resultsPerDivision {
+		filtered := make([]Result, 0, len(results))
+		for _, result := range results {
+			if accepted.AcceptsSecurityFinding(string(division), result.RuleID, result.LongID, findingAddress(result.Resource), now) {
+				suppressed++
+				continue
+			}
+			filtered = append(filtered, result)
+		}
+		filteredPerDivision[division] = filtered
+	}
+
+	log.Infof("[terraform_security] suppressed %v security findings accepted within the baseline", suppressed)
+	return filteredPerDivision
+}
+
+// findingAddress returns the type.name address of the resource of a finding, dropping any
+// nested block or attribute path reported by the scanner.
+func findingAddress(resource string) string {
+	parts := strings.Split(resource, ".")
+	if len(parts) <= 2 {
+		return resource
+	}
+	return strings.Join(parts[:2], ".")
+}
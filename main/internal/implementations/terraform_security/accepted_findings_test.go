@@ -0,0 +1,47 @@
+package terraformSecurity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/baseline"
+)
+
+func TestFilterAcceptedFindings(t *testing.T) {
+	// Given
+	results := TFSecResultsPerDivision{
+		"my-aws-account": {
+			{RuleID: "AVD-AWS-0086", LongID: "aws-s3-block-public-acls", Resource: "aws_s3_bucket.tfer--public-assets"},
+			{RuleID: "AVD-AWS-0089", LongID: "aws-s3-enable-bucket-logging", Resource: "aws_s3_bucket.tfer--public-assets.logging"},
+			{RuleID: "AVD-AWS-0086", LongID: "aws-s3-block-public-acls", Resource: "aws_s3_bucket.tfer--logs"},
+		},
+	}
+	accepted := &baseline.Baseline{Security: []baseline.AcceptedSecurityFinding{
+		{RuleID: "aws-s3-block-public-acls", Resource: "aws_s3_bucket.tfer--public-assets"},
+		{RuleID: "AVD-AWS-0089", Expires: "2026-01-31"},
+	}}
+
+	// When
+	filtered := filterAcceptedFindings(results, accepted, time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC))
+
+	// Then
+	assert.Equal(t, TFSecResultsPerDivision{
+		"my-aws-account": {
+			{RuleID: "AVD-AWS-0086", LongID: "aws-s3-block-public-acls", Resource: "aws_s3_bucket.tfer--logs"},
+		},
+	}, filtered)
+
+	// When the acceptance has expired
+	filtered = filterAcceptedFindings(results, accepted, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC))
+
+	// Then
+	assert.Len(t, filtered["my-aws-account"], 2)
+
+	// When there is no baseline
+	filtered = filterAcceptedFindings(results, &baseline.Baseline{}, time.Now())
+
+	// Then
+	assert.Equal(t, results, filtered)
+}
@@ -0,0 +1,220 @@
+package terraformSecurity
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+// checkovUnknownSeverity is the severity of checkov findings without a severity, which checkov only provides when
+// connected to the Prisma Cloud platform.
+const checkovUnknownSeverity = "UNKNOWN"
+
+// checkovPolicyIndex is the index of checkov's terraform checks, linked for findings without a guideline.
+const checkovPolicyIndex = "https://www.checkov.io/5.Policy%20Index/terraform.html"
+
+// CheckovReport is a structure that represents the json output of a checkov scan of a single framework
+type CheckovReport struct {
+	CheckType string `json:"check_type"`
+	Results   struct {
+		FailedChecks []CheckovCheck `json:"failed_checks"`
+	} `json:"results"`
+}
+
+// CheckovCheck is a single failed check within a checkov json output
+type CheckovCheck struct {
+	CheckID          string  `json:"check_id"`
+	CheckName        string  `json:"check_name"`
+	FilePath         string  `json:"file_path"`
+	FileLineRange    []int   `json:"file_line_range"`
+	Resource         string  `json:"resource"`
+	Guideline        string  `json:"guideline"`
+	Severity         *string `json:"severity"`
+	Description      *string `json:"description"`
+	ShortDescription *string `json:"short_description"`
+}
+
+// Checkov is a struct that implements the interfaces.TerraformSecurity by executing checkov against the terraform
+// framework. Findings are shaped as tfsec results, so that the report generator is agnostic of the scanner.
+type Checkov struct {
+	// divisionToProvider is a map between the string representing a division and the corresponding
+	// cloud provider (aws, azurerm, google, etc.).
+	divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider
+
+	// results adds resource ids to, and writes, the tfsec shaped results.
+	results *TFSec
+
+	// config is the configuration of the security scan.
+	config Config
+
+	// artifactStore holds the mappings read and written by the scan.
+	artifactStore artifacts.Store
+}
+
+// NewCheckov generates a new instance from Checkov
+func NewCheckov(divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider, config Config, artifactStore artifacts.Store) *Checkov {
+	return &Checkov{
+		divisionToProvider: divisionToProvider,
+		results:            NewTFSec(divisionToProvider, config, artifactStore),
+		config:             config,
+		artifactStore:      artifactStore,
+	}
+}
+
+// ExecuteScan is called from the main job flow to execute the checkov command and save the output
+// to show to the user in the PR
+func (s *Checkov) ExecuteScan(ctx context.Context, workspaceToDirectory map[string]string) error {
+	results := TFSecResultsPerDivision{}
+	if s.config.scansScope(ScopeGenerated) {
+		var err error
+		results, err = s.runCheckov(ctx)
+		if err != nil {
+			return fmt.Errorf("[checkov][execute_scan][error running checkov command]%w", err)
+		}
+	}
+
+	resultsWithID, err := s.results.addIDToResources(results)
+	if err != nil {
+		return fmt.Errorf("[checkov][execute_scan][error adding the id to the checkov results]%w", err)
+	}
+
+	unacceptedResults, err := suppressAcceptedFindings(resultsWithID)
+	if err != nil {
+		return fmt.Errorf("[checkov][execute_scan]%w", err)
+	}
+
+	err = s.results.writeResultsToMappingFile(unacceptedResults)
+	if err != nil {
+		return fmt.Errorf("[checkov][execute_scan][error writing checkov results]%w", err)
+	}
+
+	return nil
+}
+
+// ScanRepository is called once the generated code is written to the repository to scan the directories of the
+// configured changed and repository scopes.
+func (s *Checkov) ScanRepository(ctx context.Context, workspaceToDirectory map[string]string) error {
+	err := scanRepositoryScopes(ctx, s.artifactStore, s, s.config, workspaceToDirectory)
+	if err != nil {
+		return fmt.Errorf("[checkov][scan_repository]%w", err)
+	}
+	return nil
+}
+
+// runCheckov runs a checkov scan of the directory of each division, returning the findings as tfsec results
+func (s *Checkov) runCheckov(ctx context.Context) (TFSecResultsPerDivision, error) {
+	resultsPerDivision := TFSecResultsPerDivision{}
+
+	for division, provider := range s.divisionToProvider {
+		scanningPath := fmt.Sprintf("./current_cloud/%v-%v", provider, division)
+
+		results, err := s.scanPath(ctx, scanningPath)
+		if err != nil {
+			return nil, fmt.Errorf("[%v]%w", division, err)
+		}
+		resultsPerDivision[division] = results
+	}
+
+	return resultsPerDivision, nil
+}
+
+// scanPath runs a checkov scan of scanningPath, returning the findings as tfsec results
+func (s *Checkov) scanPath(ctx context.Context, scanningPath string) ([]Result, error) {
+	cmd := exec.CommandContext(ctx, "checkov", s.checkovArgs(scanningPath)...)
+
+	var out bytes.Buffer
+	var stderr bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("%s, %w", stderr.String(), err)
+	}
+
+	results, err := parseCheckovResults(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("[parseCheckovResults]%w", err)
+	}
+	return results, nil
+}
+
+// checkovArgs returns the arguments of a checkov scan of scanningPath, including any custom checks and policy bundles.
+func (s *Checkov) checkovArgs(scanningPath string) []string {
+	args := []string{"--directory", scanningPath, "--framework", "terraform", "--output", "json", "--soft-fail", "--compact"}
+	for _, directory := range s.config.policyDirectories() {
+		args = append(args, "--external-checks-dir", directory)
+	}
+	return args
+}
+
+// parseCheckovResults converts the failed checks of a checkov json output into tfsec results. Checkov outputs a
+// single report, or a list of reports when several frameworks are scanned.
+func parseCheckovResults(content []byte) ([]Result, error) {
+	reports := make([]CheckovReport, 0)
+	if trimmed := bytes.TrimSpace(content); len(trimmed) > 0 && trimmed[0] == '[' {
+		err := json.Unmarshal(trimmed, &reports)
+		if err != nil {
+			return nil, fmt.Errorf("[json.Unmarshal]%w", err)
+		}
+	} else {
+		report := CheckovReport{}
+		err := json.Unmarshal(trimmed, &report)
+		if err != nil {
+			return nil, fmt.Errorf("[json.Unmarshal]%w", err)
+		}
+		reports = append(reports, report)
+	}
+
+	results := make([]Result, 0)
+	for _, report := range reports {
+		for _, check := range report.Results.FailedChecks {
+			result := Result{
+				RuleID:          check.CheckID,
+				LongID:          check.CheckID,
+				RuleDescription: check.CheckName,
+				RuleProvider:    checkovRuleProvider(check.CheckID),
+				Description:     check.CheckName,
+				Severity:        checkovUnknownSeverity,
+				Links:           []string{checkovPolicyIndex},
+				Resource:        check.Resource,
+				Location:        Location{FileName: strings.TrimPrefix(check.FilePath, "/")},
+			}
+
+			if check.Severity != nil && *check.Severity != "" {
+				result.Severity = strings.ToUpper(*check.Severity)
+			}
+			if check.Description != nil && *check.Description != "" {
+				result.Description = *check.Description
+			} else if check.ShortDescription != nil && *check.ShortDescription != "" {
+				result.Description = *check.ShortDescription
+			}
+			if check.Guideline != "" {
+				result.Links = []string{check.Guideline}
+				result.Resolution = "Follow the remediation steps within the check's guideline."
+			}
+			if len(check.FileLineRange) == 2 {
+				result.Location.StartLine = check.FileLineRange[0]
+				result.Location.EndLine = check.FileLineRange[1]
+			}
+
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+// checkovRuleProvider returns the cloud provider of a checkov check from its id, e.g. "aws" for CKV_AWS_18.
+func checkovRuleProvider(checkID string) string {
+	parts := strings.Split(checkID, "_")
+	if len(parts) < 3 {
+		return ""
+	}
+	return strings.ToLower(parts[1])
+}
@@ -0,0 +1,100 @@
+package terraformSecurity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+)
+
+func TestParseCheckovResults(t *testing.T) {
+	// Given
+	content := []byte(`{
+		"check_type": "terraform",
+		"results": {
+			"passed_checks": [{"check_id": "CKV_AWS_19", "resource": "aws_s3_bucket.tfer--my-bucket"}],
+			"failed_checks": [
+				{
+					"check_id": "CKV_AWS_18",
+					"bc_check_id": "BC_AWS_S3_13",
+					"check_name": "Ensure the S3 bucket has access logging enabled",
+					"check_result": {"result": "FAILED"},
+					"file_path": "/resources.tf",
+					"file_line_range": [1, 10],
+					"resource": "aws_s3_bucket.tfer--my-bucket",
+					"guideline": "https://docs.prismacloud.io/en/enterprise-edition/policy-reference/aws-policies/s3-policies/s3-13-enable-logging",
+					"severity": null,
+					"description": null,
+					"short_description": null
+				},
+				{
+					"check_id": "CKV_GCP_29",
+					"check_name": "Ensure that Cloud Storage buckets have uniform bucket-level access enabled",
+					"file_path": "/resources.tf",
+					"file_line_range": [12, 20],
+					"resource": "google_storage_bucket.tfer--backups",
+					"guideline": "",
+					"severity": "high"
+				}
+			],
+			"skipped_checks": [],
+			"parsing_errors": []
+		},
+		"summary": {"passed": 1, "failed": 2}
+	}`)
+
+	// When
+	results, err := parseCheckovResults(content)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, []Result{
+		{
+			RuleID:          "CKV_AWS_18",
+			LongID:          "CKV_AWS_18",
+			RuleDescription: "Ensure the S3 bucket has access logging enabled",
+			RuleProvider:    "aws",
+			Resolution:      "Follow the remediation steps within the check's guideline.",
+			Links:           []string{"https://docs.prismacloud.io/en/enterprise-edition/policy-reference/aws-policies/s3-policies/s3-13-enable-logging"},
+			Description:     "Ensure the S3 bucket has access logging enabled",
+			Severity:        checkovUnknownSeverity,
+			Resource:        "aws_s3_bucket.tfer--my-bucket",
+			Location:        Location{FileName: "resources.tf", StartLine: 1, EndLine: 10},
+		},
+		{
+			RuleID:          "CKV_GCP_29",
+			LongID:          "CKV_GCP_29",
+			RuleDescription: "Ensure that Cloud Storage buckets have uniform bucket-level access enabled",
+			RuleProvider:    "gcp",
+			Links:           []string{checkovPolicyIndex},
+			Description:     "Ensure that Cloud Storage buckets have uniform bucket-level access enabled",
+			Severity:        "HIGH",
+			Resource:        "google_storage_bucket.tfer--backups",
+			Location:        Location{FileName: "resources.tf", StartLine: 12, EndLine: 20},
+		},
+	}, results)
+
+	// When several frameworks are reported
+	results, err = parseCheckovResults([]byte(`[{"check_type": "terraform", "results": {"failed_checks": [{"check_id": "CKV_AZURE_1", "resource": "azurerm_linux_virtual_machine.tfer--web"}]}}]`))
+
+	// Then
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "azure", results[0].RuleProvider)
+}
+
+func TestCheckov_CheckovArgs(t *testing.T) {
+	// Given
+	checkov := NewCheckov(nil, Config{CustomChecksDirectory: "/custom-checks", PolicyBundles: []string{"/policy-bundles/org"}}, artifacts.NewMemoryStore(true))
+
+	// When
+	args := checkov.checkovArgs("./current_cloud/google-my-project")
+
+	// Then
+	assert.Equal(t, []string{
+		"--directory", "./current_cloud/google-my-project", "--framework", "terraform", "--output", "json", "--soft-fail", "--compact",
+		"--external-checks-dir", "/custom-checks", "--external-checks-dir", "/policy-bundles/org",
+	}, args)
+}
@@ -0,0 +1,78 @@
+package terraformSecurity
+
+import (
+	"fmt"
+	"os"
+)
+
+// Config is the configuration of the static security scan of the scanned cloud's Terraform representation.
+type Config struct {
+	// Scanner is the security scanner, one of "trivy", "checkov" or the deprecated "tfsec".
+	Scanner string
+
+	// SeverityThreshold is the minimum severity, one of "LOW", "MEDIUM", "HIGH" or "CRITICAL", of findings within newly
+	// generated resources that trip the security gate. The gate is disabled when empty.
+	SeverityThreshold string
+
+	// UnknownSeverity is the severity, one of "LOW", "MEDIUM", "HIGH" or "CRITICAL", that findings without a known
+	// severity are gated as, e.g. checkov findings without a Prisma Cloud API key. Such findings never trip the
+	// security gate when empty.
+	UnknownSeverity string
+
+	// FailOnFindings is whether findings that trip the security gate fail the job. Otherwise, they are called out at
+	// the top of the pull request.
+	FailOnFindings bool
+
+	// SARIFOutputPath is the path to which findings are written as a SARIF file. No SARIF file is written when empty.
+	SARIFOutputPath string
+
+	// CustomChecksDirectory is a directory of custom checks passed to the scanner: rego checks for trivy and tfsec,
+	// or python and yaml checks for checkov.
+	CustomChecksDirectory string
+
+	// PolicyBundles are directories of organization policy bundles passed to the scanner alongside the custom checks.
+	PolicyBundles []string
+
+	// PolicyNamespaces are the rego namespaces, beyond trivy's default "user" namespace, of the custom checks and
+	// policy bundles evaluated by trivy.
+	PolicyNamespaces []string
+
+	// Scopes are the scopes of the scan, any of "generated" for the code generated by cloud-concierge, "changed" for
+	// the repository directories changed by the pull request, and "repository" for the entire repository. Each scope
+	// is reported within its own section. Defaults to "generated".
+	Scopes []string
+}
+
+// policyDirectories returns the custom checks directory followed by the policy bundles.
+func (c Config) policyDirectories() []string {
+	directories := make([]string, 0, len(c.PolicyBundles)+1)
+	if c.CustomChecksDirectory != "" {
+		directories = append(directories, c.CustomChecksDirectory)
+	}
+	for _, bundle := range c.PolicyBundles {
+		if bundle != "" {
+			directories = append(directories, bundle)
+		}
+	}
+	return directories
+}
+
+// validatePolicyDirectories returns an error when a custom checks directory or policy bundle is not a mounted
+// directory, or when more are configured than the scanner supports.
+func (c Config) validatePolicyDirectories() error {
+	directories := c.policyDirectories()
+	if c.Scanner == "tfsec" && len(directories) > 1 {
+		return fmt.Errorf("[tfsec supports a single custom checks directory or policy bundle, got %d]", len(directories))
+	}
+
+	for _, directory := range directories {
+		info, err := os.Stat(directory)
+		if err != nil {
+			return fmt.Errorf("[custom security checks %v are not mounted]%w", directory, err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("[custom security checks %v are not a directory]", directory)
+		}
+	}
+	return nil
+}
@@ -2,7 +2,9 @@ package terraformSecurity
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
 )
@@ -13,17 +15,57 @@ type Factory struct {
 
 // Instantiate returns an implementation of interfaces.TerraformSecurity depending on the passed
 // environment specification.
-func (f *Factory) Instantiate(ctx context.Context, environment string, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider) (interfaces.TerraformSecurity, error) {
+func (f *Factory) Instantiate(ctx context.Context, environment string, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider, config Config, artifactStore artifacts.Store) (interfaces.TerraformSecurity, error) {
 	switch environment {
 	case "isolated":
 		return NewIsolatedTerraformSecurity(), nil
 	default:
-		return f.bootstrappedTerraformSecurity(divisionToProvider)
+		return f.bootstrappedTerraformSecurity(divisionToProvider, config, artifactStore)
 	}
 }
 
-// bootstrappedTerraformSecurity creates a complete implementation of the interfaces.TerraformSecurity interface with
-// configuration specified via environment variables.
-func (f *Factory) bootstrappedTerraformSecurity(divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider) (interfaces.TerraformSecurity, error) {
-	return NewTFSec(divisionToProvider), nil
+// bootstrappedTerraformSecurity creates a complete implementation of the interfaces.TerraformSecurity interface for
+// the configured scanner. Findings are written as a SARIF file when a SARIF output path is configured, and gated by
+// severity when a severity threshold is configured.
+func (f *Factory) bootstrappedTerraformSecurity(divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider, config Config, artifactStore artifacts.Store) (interfaces.TerraformSecurity, error) {
+	var scanner interfaces.TerraformSecurity
+	switch config.Scanner {
+	case "", "trivy":
+		scanner = NewTrivy(divisionToProvider, config, artifactStore)
+	case "checkov":
+		scanner = NewCheckov(divisionToProvider, config, artifactStore)
+	case "tfsec":
+		scanner = NewTFSec(divisionToProvider, config, artifactStore)
+	default:
+		return nil, fmt.Errorf("[security scanner %v is not supported]", config.Scanner)
+	}
+
+	err := config.validatePolicyDirectories()
+	if err != nil {
+		return nil, err
+	}
+
+	err = config.validateScopes()
+	if err != nil {
+		return nil, err
+	}
+
+	if config.SARIFOutputPath != "" {
+		scanner = NewSARIFScanner(scanner, divisionToProvider, config, artifactStore)
+	}
+
+	if config.SeverityThreshold == "" {
+		return scanner, nil
+	}
+
+	err = validateSeverityThreshold(config.SeverityThreshold)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateUnknownSeverity(config.UnknownSeverity)
+	if err != nil {
+		return nil, err
+	}
+	return NewSeverityGatedScanner(scanner, divisionToProvider, config, artifactStore), nil
 }
@@ -0,0 +1,95 @@
+package terraformSecurity
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+)
+
+func TestCreateTerraformSecurity_Scanners(t *testing.T) {
+	// Given
+	factory := new(Factory)
+
+	// When
+	scanner, err := factory.Instantiate(context.Background(), "isolated", nil, Config{Scanner: "trivy"}, artifacts.NewMemoryStore(true))
+
+	// Then
+	assert.Nil(t, err)
+	assert.IsType(t, &IsolatedTerraformSecurity{}, scanner)
+
+	// When
+	scanner, err = factory.Instantiate(context.Background(), "", nil, Config{}, artifacts.NewMemoryStore(true))
+
+	// Then
+	assert.Nil(t, err)
+	assert.IsType(t, &Trivy{}, scanner)
+
+	// When
+	scanner, err = factory.Instantiate(context.Background(), "", nil, Config{Scanner: "checkov"}, artifacts.NewMemoryStore(true))
+
+	// Then
+	assert.Nil(t, err)
+	assert.IsType(t, &Checkov{}, scanner)
+
+	// When
+	scanner, err = factory.Instantiate(context.Background(), "", nil, Config{Scanner: "tfsec"}, artifacts.NewMemoryStore(true))
+
+	// Then
+	assert.Nil(t, err)
+	assert.IsType(t, &TFSec{}, scanner)
+
+	// When
+	_, err = factory.Instantiate(context.Background(), "", nil, Config{Scanner: "unknown"}, artifacts.NewMemoryStore(true))
+
+	// Then
+	assert.Error(t, err)
+
+	// When
+	scanner, err = factory.Instantiate(context.Background(), "", nil, Config{Scanner: "checkov", SeverityThreshold: "high"}, artifacts.NewMemoryStore(true))
+
+	// Then
+	assert.Nil(t, err)
+	assert.IsType(t, &SeverityGatedScanner{}, scanner)
+
+	// When
+	scanner, err = factory.Instantiate(context.Background(), "", nil, Config{SARIFOutputPath: "security/results.sarif"}, artifacts.NewMemoryStore(true))
+
+	// Then
+	assert.Nil(t, err)
+	assert.IsType(t, &SARIFScanner{}, scanner)
+
+	// When
+	_, err = factory.Instantiate(context.Background(), "", nil, Config{SeverityThreshold: "severe"}, artifacts.NewMemoryStore(true))
+
+	// Then
+	assert.Error(t, err)
+}
+
+func TestCreateTerraformSecurity_CustomChecks(t *testing.T) {
+	// Given
+	factory := new(Factory)
+	customChecks := t.TempDir()
+	policyBundle := t.TempDir()
+
+	// When
+	scanner, err := factory.Instantiate(context.Background(), "", nil, Config{CustomChecksDirectory: customChecks, PolicyBundles: []string{policyBundle}}, artifacts.NewMemoryStore(true))
+
+	// Then
+	assert.Nil(t, err)
+	assert.IsType(t, &Trivy{}, scanner)
+
+	// When the custom checks are not mounted
+	_, err = factory.Instantiate(context.Background(), "", nil, Config{CustomChecksDirectory: customChecks + "/missing"}, artifacts.NewMemoryStore(true))
+
+	// Then
+	assert.Error(t, err)
+
+	// When tfsec is given more than one directory
+	_, err = factory.Instantiate(context.Background(), "", nil, Config{Scanner: "tfsec", CustomChecksDirectory: customChecks, PolicyBundles: []string{policyBundle}}, artifacts.NewMemoryStore(true))
+
+	// Then
+	assert.Error(t, err)
+}
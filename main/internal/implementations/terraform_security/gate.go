@@ -0,0 +1,217 @@
+package terraformSecurity
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
+)
+
+// ErrFindingsAboveThreshold is returned when newly generated resources have findings at or above the severity
+// threshold and the job is configured to fail on them.
+var ErrFindingsAboveThreshold = errors.New("[security findings at or above the severity threshold in newly generated resources]")
+
+// severityRanks orders the severities of security findings. Findings without a known severity, e.g. checkov's
+// UNKNOWN, are ranked as the configured UnknownSeverity.
+var severityRanks = map[string]int{
+	"LOW":      1,
+	"MEDIUM":   2,
+	"HIGH":     3,
+	"CRITICAL": 4,
+}
+
+// SecurityGate is the outcome of gating a security scan by severity, written to mappings/security-gate.json.
+type SecurityGate struct {
+	// Threshold is the minimum severity of a finding that trips the gate.
+	Threshold string `json:"threshold"`
+
+	// FailOnFindings is whether tripping the gate fails the job, rather than marking the pull request.
+	FailOnFindings bool `json:"fail_on_findings"`
+
+	// Findings are the findings within newly generated resources at or above Threshold.
+	Findings []GatedFinding `json:"findings"`
+}
+
+// GatedFinding is a single finding that trips the security gate.
+type GatedFinding struct {
+	Division        string `json:"division"`
+	Resource        string `json:"resource"`
+	RuleID          string `json:"rule_id"`
+	RuleDescription string `json:"rule_description"`
+	Severity        string `json:"severity"`
+}
+
+// SeverityGatedScanner is a struct that implements interfaces.TerraformSecurity by wrapping a security scanner, and
+// gating the findings of newly generated resources by severity once the scan completes.
+type SeverityGatedScanner struct {
+	// scanner is the wrapped security scanner.
+	scanner interfaces.TerraformSecurity
+
+	// divisionToProvider is a map between the string representing a division and the corresponding
+	// cloud provider (aws, azurerm, google, etc.).
+	divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider
+
+	// config is the configuration of the security scan.
+	config Config
+
+	// artifactStore holds the mappings read and written by the scan.
+	artifactStore artifacts.Store
+}
+
+// NewSeverityGatedScanner generates a new instance from SeverityGatedScanner
+func NewSeverityGatedScanner(scanner interfaces.TerraformSecurity, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider, config Config, artifactStore artifacts.Store) *SeverityGatedScanner {
+	return &SeverityGatedScanner{
+		scanner:            scanner,
+		divisionToProvider: divisionToProvider,
+		config:             config,
+		artifactStore:      artifactStore,
+	}
+}
+
+// ExecuteScan executes the wrapped security scan, then writes the findings of newly generated resources at or
+// above the severity threshold. ErrFindingsAboveThreshold is returned for such findings when configured to fail.
+func (s *SeverityGatedScanner) ExecuteScan(ctx context.Context, workspaceToDirectory map[string]string) error {
+	err := s.scanner.ExecuteScan(ctx, workspaceToDirectory)
+	if err != nil {
+		return err
+	}
+
+	resultsPerDivision := TFSecResultsPerDivision{}
+	err = readMappingFile(s.artifactStore, "mappings/division-to-security-scan.json", &resultsPerDivision)
+	if err != nil {
+		return fmt.Errorf("[severity_gated_scanner][execute_scan]%w", err)
+	}
+
+	newResourcesToWorkspace := map[string]string{}
+	if s.artifactStore.Exists("mappings/new-resources-to-workspace.json") {
+		err = readMappingFile(s.artifactStore, "mappings/new-resources-to-workspace.json", &newResourcesToWorkspace)
+		if err != nil {
+			return fmt.Errorf("[severity_gated_scanner][execute_scan]%w", err)
+		}
+	}
+
+	gate := SecurityGate{
+		Threshold:      normalizeSeverity(s.config.SeverityThreshold),
+		FailOnFindings: s.config.FailOnFindings,
+		Findings:       s.gatedFindings(resultsPerDivision, newResourcesToWorkspace),
+	}
+
+	gateJSON, err := json.MarshalIndent(gate, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[severity_gated_scanner][execute_scan][json.MarshalIndent]%w", err)
+	}
+
+	err = s.artifactStore.WriteFile("mappings/security-gate.json", gateJSON, 0400)
+	if err != nil {
+		return fmt.Errorf("[severity_gated_scanner][execute_scan][artifacts.WriteFile]%w", err)
+	}
+
+	if len(gate.Findings) > 0 && gate.FailOnFindings {
+		return fmt.Errorf("[severity_gated_scanner][execute_scan][%d finding(s) at or above %v]%w", len(gate.Findings), gate.Threshold, ErrFindingsAboveThreshold)
+	}
+	return nil
+}
+
+// gatedFindings returns the findings of newly generated resources at or above the severity threshold, sorted by
+// division, resource and rule.
+func (s *SeverityGatedScanner) gatedFindings(resultsPerDivision TFSecResultsPerDivision, newResourcesToWorkspace map[string]string) []GatedFinding {
+	thresholdRank := severityRanks[normalizeSeverity(s.config.SeverityThreshold)]
+	unknownRank, gateUnknown := severityRanks[normalizeSeverity(s.config.UnknownSeverity)]
+
+	findings := make([]GatedFinding, 0)
+	for division, results := range resultsPerDivision {
+		fullDivisionName := fmt.Sprintf("%v-%v", s.divisionToProvider[division], division)
+
+		for _, result := range results {
+			rank, ok := severityRanks[normalizeSeverity(result.Severity)]
+			if !ok {
+				if !gateUnknown {
+					continue
+				}
+				rank = unknownRank
+			}
+			if rank < thresholdRank {
+				continue
+			}
+
+			resourceNameParts := strings.Split(result.Resource, ".")
+			if len(resourceNameParts) < 2 {
+				continue
+			}
+			resource := strings.Join(resourceNameParts[:2], ".")
+
+			if _, isNew := newResourcesToWorkspace[fmt.Sprintf("%v.%v", fullDivisionName, resource)]; !isNew {
+				continue
+			}
+
+			findings = append(findings, GatedFinding{
+				Division:        string(division),
+				Resource:        resource,
+				RuleID:          result.RuleID,
+				RuleDescription: result.RuleDescription,
+				Severity:        normalizeSeverity(result.Severity),
+			})
+		}
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].Division != findings[j].Division {
+			return findings[i].Division < findings[j].Division
+		}
+		if findings[i].Resource != findings[j].Resource {
+			return findings[i].Resource < findings[j].Resource
+		}
+		return findings[i].RuleID < findings[j].RuleID
+	})
+	return findings
+}
+
+// validateSeverityThreshold returns an error when the configured severity threshold is not a known severity.
+func validateSeverityThreshold(threshold string) error {
+	if _, ok := severityRanks[normalizeSeverity(threshold)]; !ok {
+		return fmt.Errorf("[security severity threshold %v is not one of LOW, MEDIUM, HIGH or CRITICAL]", threshold)
+	}
+	return nil
+}
+
+// validateUnknownSeverity returns an error when the severity configured for findings without a known severity is
+// neither empty nor a known severity.
+func validateUnknownSeverity(severity string) error {
+	if severity == "" {
+		return nil
+	}
+	if _, ok := severityRanks[normalizeSeverity(severity)]; !ok {
+		return fmt.Errorf("[security unknown severity %v is not one of LOW, MEDIUM, HIGH or CRITICAL]", severity)
+	}
+	return nil
+}
+
+// normalizeSeverity upper-cases a severity and trims the padding added to it within reports.
+func normalizeSeverity(severity string) string {
+	return strings.ToUpper(strings.TrimSpace(severity))
+}
+
+// readMappingFile unmarshals the json content of a mapping file into target.
+func readMappingFile(store artifacts.Store, path string, target interface{}) error {
+	content, err := store.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("[artifacts.ReadFile %v]%w", path, err)
+	}
+
+	err = json.Unmarshal(content, target)
+	if err != nil {
+		return fmt.Errorf("[json.Unmarshal %v]%w", path, err)
+	}
+	return nil
+}
+
+// ScanRepository scans the changed and repository scopes with the wrapped security scanner.
+func (s *SeverityGatedScanner) ScanRepository(ctx context.Context, workspaceToDirectory map[string]string) error {
+	return s.scanner.ScanRepository(ctx, workspaceToDirectory)
+}
@@ -0,0 +1,90 @@
+package terraformSecurity
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
+)
+
+func TestSeverityGatedScanner_ExecuteScan(t *testing.T) {
+	// Given
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+	require.NoError(t, os.MkdirAll("mappings", 0755))
+
+	results := TFSecResultsPerDivision{
+		"my-account": {
+			{RuleID: "AVD-AWS-0086", RuleDescription: "S3 Access block should block public ACL", Severity: "HIGH", Resource: "aws_s3_bucket.tfer--public"},
+			{RuleID: "AVD-AWS-0089", RuleDescription: "S3 Bucket logging", Severity: "LOW", Resource: "aws_s3_bucket.tfer--public"},
+			{RuleID: "AVD-AWS-0132", RuleDescription: "S3 encryption should use Customer Managed Keys", Severity: "critical", Resource: "aws_s3_bucket.tfer--managed"},
+			{RuleID: "CKV_AWS_18", RuleDescription: "Ensure the S3 bucket has access logging enabled", Severity: checkovUnknownSeverity, Resource: "aws_s3_bucket.tfer--public"},
+		},
+	}
+	resultsJSON, err := json.Marshal(results)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile("mappings/division-to-security-scan.json", resultsJSON, 0400))
+	require.NoError(t, os.WriteFile("mappings/new-resources-to-workspace.json", []byte(`{"aws-my-account.aws_s3_bucket.tfer--public": "storage"}`), 0400))
+
+	divisionToProvider := map[terraformValueObjects.Division]terraformValueObjects.Provider{"my-account": "aws"}
+	scanner := new(interfaces.TerraformSecurityMock)
+	scanner.On("ExecuteScan", mock.Anything, mock.Anything).Return(nil)
+
+	// When
+	err = NewSeverityGatedScanner(scanner, divisionToProvider, Config{SeverityThreshold: "medium"}, artifacts.NewMemoryStore(true)).ExecuteScan(context.Background(), nil)
+
+	// Then
+	require.NoError(t, err)
+
+	content, err := os.ReadFile("mappings/security-gate.json")
+	require.NoError(t, err)
+	gate := SecurityGate{}
+	require.NoError(t, json.Unmarshal(content, &gate))
+	assert.Equal(t, SecurityGate{
+		Threshold: "MEDIUM",
+		Findings: []GatedFinding{
+			{Division: "my-account", Resource: "aws_s3_bucket.tfer--public", RuleID: "AVD-AWS-0086", RuleDescription: "S3 Access block should block public ACL", Severity: "HIGH"},
+		},
+	}, gate)
+
+	// When failing on findings
+	require.NoError(t, os.Remove("mappings/security-gate.json"))
+	err = NewSeverityGatedScanner(scanner, divisionToProvider, Config{SeverityThreshold: "HIGH", FailOnFindings: true}, artifacts.NewMemoryStore(true)).ExecuteScan(context.Background(), nil)
+
+	// Then
+	assert.True(t, errors.Is(err, ErrFindingsAboveThreshold))
+	assert.FileExists(t, "mappings/security-gate.json")
+
+	// When no findings of new resources reach the threshold
+	require.NoError(t, os.Remove("mappings/security-gate.json"))
+	err = NewSeverityGatedScanner(scanner, divisionToProvider, Config{SeverityThreshold: "CRITICAL", FailOnFindings: true}, artifacts.NewMemoryStore(true)).ExecuteScan(context.Background(), nil)
+
+	// Then
+	assert.NoError(t, err)
+
+	// When findings without a known severity are gated
+	require.NoError(t, os.Remove("mappings/security-gate.json"))
+	err = NewSeverityGatedScanner(scanner, divisionToProvider, Config{SeverityThreshold: "HIGH", UnknownSeverity: "high"}, artifacts.NewMemoryStore(true)).ExecuteScan(context.Background(), nil)
+
+	// Then
+	require.NoError(t, err)
+	content, err = os.ReadFile("mappings/security-gate.json")
+	require.NoError(t, err)
+	gate = SecurityGate{}
+	require.NoError(t, json.Unmarshal(content, &gate))
+	assert.Equal(t, []GatedFinding{
+		{Division: "my-account", Resource: "aws_s3_bucket.tfer--public", RuleID: "AVD-AWS-0086", RuleDescription: "S3 Access block should block public ACL", Severity: "HIGH"},
+		{Division: "my-account", Resource: "aws_s3_bucket.tfer--public", RuleID: "CKV_AWS_18", RuleDescription: "Ensure the S3 bucket has access logging enabled", Severity: "UNKNOWN"},
+	}, gate.Findings)
+}
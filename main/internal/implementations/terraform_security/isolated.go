@@ -13,6 +13,11 @@ func NewIsolatedTerraformSecurity() *IsolatedTerraformSecurity {
 
 // ExecuteScan is called from the main job flow to mock the output files from tfsec to show
 // to the user in the PR
-func (i *IsolatedTerraformSecurity) ExecuteScan(ctx context.Context) error {
+func (i *IsolatedTerraformSecurity) ExecuteScan(ctx context.Context, workspaceToDirectory map[string]string) error {
+	return nil
+}
+
+// ScanRepository is called once the generated code is written to the repository, and scans nothing in isolation
+func (i *IsolatedTerraformSecurity) ScanRepository(ctx context.Context, workspaceToDirectory map[string]string) error {
 	return nil
 }
@@ -0,0 +1,408 @@
+package terraformSecurity
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/clonedrepo"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/hclcreate"
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
+)
+
+// sarifSchema is the json schema of SARIF 2.1.0 files.
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// sarifLevels maps the severity of a finding to the level of a SARIF result.
+var sarifLevels = map[string]string{
+	"LOW":      "note",
+	"MEDIUM":   "warning",
+	"HIGH":     "error",
+	"CRITICAL": "error",
+}
+
+// sarifSecuritySeverities maps the severity of a finding to the numeric security severity read by GitHub code
+// scanning.
+var sarifSecuritySeverities = map[string]string{
+	"LOW":      "2.0",
+	"MEDIUM":   "5.5",
+	"HIGH":     "8.0",
+	"CRITICAL": "9.5",
+}
+
+// SARIFLog is the root of a SARIF 2.1.0 file.
+type SARIFLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+// SARIFRun is a single run of a security scanner within a SARIF file.
+type SARIFRun struct {
+	Tool    SARIFTool     `json:"tool"`
+	Results []SARIFResult `json:"results"`
+}
+
+// SARIFTool describes the security scanner and the rules it evaluated.
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+// SARIFDriver is the security scanner of a SARIF run.
+type SARIFDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []SARIFRule `json:"rules"`
+}
+
+// SARIFRule is a single rule that produced at least one finding.
+type SARIFRule struct {
+	ID               string            `json:"id"`
+	ShortDescription SARIFMessage      `json:"shortDescription"`
+	HelpURI          string            `json:"helpUri,omitempty"`
+	Properties       map[string]string `json:"properties,omitempty"`
+}
+
+// SARIFResult is a single finding within a SARIF run.
+type SARIFResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   SARIFMessage    `json:"message"`
+	Locations []SARIFLocation `json:"locations"`
+}
+
+// SARIFMessage is the text of a SARIF rule description or result.
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+// SARIFLocation is the file and resource in which a finding was identified.
+type SARIFLocation struct {
+	PhysicalLocation SARIFPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []SARIFLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+// SARIFPhysicalLocation is the file, and lines within it, in which a finding was identified.
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           *SARIFRegion          `json:"region,omitempty"`
+}
+
+// SARIFArtifactLocation is the uri of the file in which a finding was identified.
+type SARIFArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// SARIFRegion is the lines of a file in which a finding was identified.
+type SARIFRegion struct {
+	StartLine int `json:"startLine"`
+	EndLine   int `json:"endLine,omitempty"`
+}
+
+// SARIFLogicalLocation is the Terraform resource in which a finding was identified.
+type SARIFLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// SARIFScanner is a struct that implements interfaces.TerraformSecurity by wrapping a security scanner, and writing
+// its findings as a SARIF file once the generated code is written to the repository.
+type SARIFScanner struct {
+	// scanner is the wrapped security scanner.
+	scanner interfaces.TerraformSecurity
+
+	// divisionToProvider is a map between the string representing a division and the corresponding
+	// cloud provider (aws, azurerm, google, etc.).
+	divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider
+
+	// config is the configuration of the security scan.
+	config Config
+
+	// artifactStore holds the mappings read and written by the scan.
+	artifactStore artifacts.Store
+}
+
+// NewSARIFScanner generates a new instance from SARIFScanner
+func NewSARIFScanner(scanner interfaces.TerraformSecurity, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider, config Config, artifactStore artifacts.Store) *SARIFScanner {
+	return &SARIFScanner{
+		scanner:            scanner,
+		divisionToProvider: divisionToProvider,
+		config:             config,
+		artifactStore:      artifactStore,
+	}
+}
+
+// ExecuteScan executes the wrapped security scan. Its findings are written as a SARIF file by ScanRepository, once
+// the generated code they are located within is written to the repository.
+func (s *SARIFScanner) ExecuteScan(ctx context.Context, workspaceToDirectory map[string]string) error {
+	return s.scanner.ExecuteScan(ctx, workspaceToDirectory)
+}
+
+// ScanRepository scans the changed and repository scopes with the wrapped security scanner, then writes the
+// findings of the generated code to the configured SARIF output path, located within the repository files the new
+// resources are written to.
+func (s *SARIFScanner) ScanRepository(ctx context.Context, workspaceToDirectory map[string]string) error {
+	err := s.scanner.ScanRepository(ctx, workspaceToDirectory)
+	if err != nil {
+		return err
+	}
+
+	resultsPerDivision := TFSecResultsPerDivision{}
+	if s.artifactStore.Exists("mappings/division-to-security-scan.json") {
+		err = readMappingFile(s.artifactStore, "mappings/division-to-security-scan.json", &resultsPerDivision)
+		if err != nil {
+			return fmt.Errorf("[sarif_scanner][scan_repository]%w", err)
+		}
+	}
+
+	locations, err := loadGeneratedLocations(s.artifactStore, workspaceToDirectory)
+	if err != nil {
+		return fmt.Errorf("[sarif_scanner][scan_repository]%w", err)
+	}
+
+	sarifJSON, err := json.MarshalIndent(s.toSARIF(resultsPerDivision, locations), "", "  ")
+	if err != nil {
+		return fmt.Errorf("[sarif_scanner][scan_repository][json.MarshalIndent]%w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(s.config.SARIFOutputPath), 0755)
+	if err != nil {
+		return fmt.Errorf("[sarif_scanner][scan_repository][os.MkdirAll]%w", err)
+	}
+
+	err = os.WriteFile(s.config.SARIFOutputPath, sarifJSON, 0644)
+	if err != nil {
+		return fmt.Errorf("[sarif_scanner][scan_repository][os.WriteFile]%w", err)
+	}
+	return nil
+}
+
+// toSARIF converts the findings of each division into a single SARIF run, ordered by division. Each finding is
+// located within the repository file its resource is written to, and findings of resources that are not written to
+// the repository, e.g. those already managed by Terraform, are left out.
+func (s *SARIFScanner) toSARIF(resultsPerDivision TFSecResultsPerDivision, locations generatedLocations) SARIFLog {
+	scanner := s.config.Scanner
+	if scanner == "" {
+		scanner = "trivy"
+	}
+
+	divisions := make([]string, 0, len(resultsPerDivision))
+	for division := range resultsPerDivision {
+		divisions = append(divisions, string(division))
+	}
+	sort.Strings(divisions)
+
+	rules := make([]SARIFRule, 0)
+	ruleIDs := map[string]bool{}
+	results := make([]SARIFResult, 0)
+	unlocated := 0
+
+	for _, division := range divisions {
+		fullDivisionName := fmt.Sprintf("%v-%v", s.divisionToProvider[terraformValueObjects.Division(division)], division)
+
+		for _, result := range resultsPerDivision[terraformValueObjects.Division(division)] {
+			generated, ok := locations[fmt.Sprintf("%v.%v", fullDivisionName, result.Resource)]
+			if !ok {
+				unlocated++
+				continue
+			}
+			severity := normalizeSeverity(result.Severity)
+
+			if !ruleIDs[result.RuleID] {
+				ruleIDs[result.RuleID] = true
+				rule := SARIFRule{ID: result.RuleID, ShortDescription: SARIFMessage{Text: result.RuleDescription}}
+				if len(result.Links) > 0 {
+					rule.HelpURI = result.Links[0]
+				}
+				if securitySeverity, ok := sarifSecuritySeverities[severity]; ok {
+					rule.Properties = map[string]string{"security-severity": securitySeverity}
+				}
+				rules = append(rules, rule)
+			}
+
+			level, ok := sarifLevels[severity]
+			if !ok {
+				level = "warning"
+			}
+
+			location := SARIFLocation{
+				PhysicalLocation: SARIFPhysicalLocation{
+					ArtifactLocation: SARIFArtifactLocation{URI: generated.path},
+					Region:           &SARIFRegion{StartLine: generated.startLine, EndLine: generated.endLine},
+				},
+				LogicalLocations: []SARIFLogicalLocation{{FullyQualifiedName: generated.address, Kind: "resource"}},
+			}
+
+			results = append(results, SARIFResult{
+				RuleID:    result.RuleID,
+				Level:     level,
+				Message:   SARIFMessage{Text: fmt.Sprintf("%v (%v, division %v)", result.Description, generated.address, division)},
+				Locations: []SARIFLocation{location},
+			})
+		}
+	}
+	if unlocated > 0 {
+		log.Infof("[sarif_scanner] %v findings of resources not written to the repository are left out of the SARIF file", unlocated)
+	}
+
+	return SARIFLog{
+		Schema:  sarifSchema,
+		Version: "2.1.0",
+		Runs: []SARIFRun{
+			{
+				Tool: SARIFTool{
+					Driver: SARIFDriver{
+						Name:           scanner,
+						InformationURI: "https://github.com/dragondrop-cloud/cloud-concierge",
+						Rules:          rules,
+					},
+				},
+				Results: results,
+			},
+		},
+	}
+}
+
+// generatedLocation is the block of a new resource within the files written to the repository.
+type generatedLocation struct {
+	// path is the path of the file relative to the root of the repository.
+	path string
+
+	// startLine and endLine are the lines of the resource block within the file.
+	startLine int
+	endLine   int
+
+	// address is the type.name address of the resource within the generated code.
+	address string
+}
+
+// generatedLocations maps the division.type.terraformer-name identifier of each new resource to its block within the
+// files written to the repository.
+type generatedLocations map[string]generatedLocation
+
+// loadGeneratedLocations locates the block of each new resource, recorded within
+// mappings/new-resources-to-workspace.json, within the directory of its workspace under its generated name.
+func loadGeneratedLocations(store artifacts.Store, workspaceToDirectory map[string]string) (generatedLocations, error) {
+	locations := generatedLocations{}
+	if !store.Exists("mappings/new-resources-to-workspace.json") {
+		return locations, nil
+	}
+
+	newResourceToWorkspace := hclcreate.NewResourceToWorkspace{}
+	err := readMappingFile(store, "mappings/new-resources-to-workspace.json", &newResourceToWorkspace)
+	if err != nil {
+		return nil, fmt.Errorf("[load_generated_locations]%w", err)
+	}
+
+	resourceNames, err := hclcreate.LoadResourceNames(store)
+	if err != nil {
+		return nil, fmt.Errorf("[load_generated_locations][hclcreate.LoadResourceNames]%w", err)
+	}
+
+	workspaceToBlocks := map[string]map[string]generatedLocation{}
+	for resource, workspace := range newResourceToWorkspace {
+		directory, ok := workspaceToDirectory[workspace]
+		if !ok {
+			continue
+		}
+		if _, ok := workspaceToBlocks[workspace]; !ok {
+			workspaceToBlocks[workspace], err = resourceBlockLocations(directory, workspaceToDirectory)
+			if err != nil {
+				return nil, fmt.Errorf("[load_generated_locations]%w", err)
+			}
+		}
+
+		fullDivision, terraformerAddress, found := strings.Cut(resource, ".")
+		if !found {
+			continue
+		}
+		if location, ok := workspaceToBlocks[workspace][resourceNames.Address(fullDivision, terraformerAddress)]; ok {
+			locations[resource] = location
+		}
+	}
+	return locations, nil
+}
+
+// resourceBlockLocations locates each resource block within the workspace directory, keyed by its type.name
+// address. Blocks within the top level files of the workspace take precedence over those within its subdirectories,
+// e.g. child modules, and the directories of other workspaces are not searched.
+func resourceBlockLocations(directory string, workspaceToDirectory map[string]string) (map[string]generatedLocation, error) {
+	root := filepath.Join(clonedrepo.Directory, directory)
+	otherDirectories := map[string]bool{}
+	for _, otherDirectory := range workspaceToDirectory {
+		otherRoot := filepath.Join(clonedrepo.Directory, otherDirectory)
+		if otherRoot != root {
+			otherDirectories[otherRoot] = true
+		}
+	}
+
+	topLevelPaths, nestedPaths := []string{}, []string{}
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != root && (otherDirectories[path] || entry.Name() == ".terraform") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".tf" {
+			return nil
+		}
+		if filepath.Dir(path) == root {
+			topLevelPaths = append(topLevelPaths, path)
+		} else {
+			nestedPaths = append(nestedPaths, path)
+		}
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]generatedLocation{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[resource_block_locations][filepath.WalkDir %v]%w", root, err)
+	}
+
+	locations := map[string]generatedLocation{}
+	for _, path := range append(topLevelPaths, nestedPaths...) {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("[resource_block_locations][os.ReadFile %v]%w", path, err)
+		}
+
+		file, diags := hclsyntax.ParseConfig(content, path, hcl.InitialPos)
+		if diags.HasErrors() {
+			continue
+		}
+
+		for _, block := range file.Body.(*hclsyntax.Body).Blocks {
+			if block.Type != "resource" || len(block.Labels) != 2 {
+				continue
+			}
+			address := strings.Join(block.Labels, ".")
+			if _, ok := locations[address]; ok {
+				continue
+			}
+			locations[address] = generatedLocation{
+				path:      filepath.ToSlash(strings.TrimPrefix(path, clonedrepo.Directory+string(filepath.Separator))),
+				startLine: block.Range().Start.Line,
+				endLine:   block.Range().End.Line,
+				address:   address,
+			}
+		}
+	}
+	return locations, nil
+}
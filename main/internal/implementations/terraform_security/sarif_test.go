@@ -0,0 +1,124 @@
+package terraformSecurity
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
+)
+
+func TestSARIFScanner_ScanRepository(t *testing.T) {
+	// Given
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+	require.NoError(t, os.MkdirAll("mappings", 0755))
+
+	results := TFSecResultsPerDivision{
+		"my-project": {
+			{
+				RuleID:          "AVD-GCP-0001",
+				RuleDescription: "Cloud Storage buckets should not be publicly accessible",
+				Description:     "Bucket allows public access.",
+				Severity:        "HIGH",
+				Links:           []string{"https://avd.aquasec.com/misconfig/avd-gcp-0001"},
+				Resource:        "google_storage_bucket.tfer--public",
+				Location:        Location{FileName: "storage_bucket.tf", StartLine: 3, EndLine: 9},
+			},
+			{
+				RuleID:          "CKV_GCP_29",
+				RuleDescription: "Ensure that Cloud Storage buckets have uniform bucket-level access enabled",
+				Description:     "Ensure that Cloud Storage buckets have uniform bucket-level access enabled",
+				Severity:        checkovUnknownSeverity,
+				Links:           []string{checkovPolicyIndex},
+				Resource:        "google_storage_bucket.tfer--public",
+				Location:        Location{FileName: "/storage_bucket.tf"},
+			},
+			{
+				RuleID:          "AVD-GCP-0001",
+				RuleDescription: "Cloud Storage buckets should not be publicly accessible",
+				Description:     "Bucket allows public access.",
+				Severity:        "HIGH",
+				Resource:        "google_storage_bucket.tfer--managed",
+				Location:        Location{FileName: "storage_bucket.tf", StartLine: 12, EndLine: 18},
+			},
+		},
+	}
+	resultsJSON, err := json.Marshal(results)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile("mappings/division-to-security-scan.json", resultsJSON, 0400))
+	require.NoError(t, os.WriteFile("mappings/new-resources-to-workspace.json", []byte(`{
+		"google-my-project.google_storage_bucket.tfer--public": "storage"
+	}`), 0400))
+	require.NoError(t, os.WriteFile("mappings/new-resources-to-names.json", []byte(`{
+		"google-my-project.google_storage_bucket.tfer--public": "public_2"
+	}`), 0400))
+	require.NoError(t, os.MkdirAll("repo/terraform/storage", 0755))
+	require.NoError(t, os.WriteFile("repo/terraform/storage/new-resources.tf", []byte(`# This resource has no identified cost
+resource "google_storage_bucket" "public_2" {
+  name     = "public"
+  location = "US"
+}
+`), 0400))
+
+	scanner := new(interfaces.TerraformSecurityMock)
+	scanner.On("ScanRepository", mock.Anything, mock.Anything).Return(nil)
+	divisionToProvider := map[terraformValueObjects.Division]terraformValueObjects.Provider{"my-project": "google"}
+
+	// When
+	err = NewSARIFScanner(scanner, divisionToProvider, Config{SARIFOutputPath: "security/results.sarif"}, artifacts.NewMemoryStore(true)).ScanRepository(
+		context.Background(), map[string]string{"storage": "/terraform/storage/"},
+	)
+
+	// Then
+	require.NoError(t, err)
+
+	content, err := os.ReadFile("security/results.sarif")
+	require.NoError(t, err)
+	sarif := SARIFLog{}
+	require.NoError(t, json.Unmarshal(content, &sarif))
+
+	assert.Equal(t, "2.1.0", sarif.Version)
+	require.Len(t, sarif.Runs, 1)
+	assert.Equal(t, "trivy", sarif.Runs[0].Tool.Driver.Name)
+	assert.Equal(t, []SARIFRule{
+		{
+			ID:               "AVD-GCP-0001",
+			ShortDescription: SARIFMessage{Text: "Cloud Storage buckets should not be publicly accessible"},
+			HelpURI:          "https://avd.aquasec.com/misconfig/avd-gcp-0001",
+			Properties:       map[string]string{"security-severity": "8.0"},
+		},
+		{
+			ID:               "CKV_GCP_29",
+			ShortDescription: SARIFMessage{Text: "Ensure that Cloud Storage buckets have uniform bucket-level access enabled"},
+			HelpURI:          checkovPolicyIndex,
+		},
+	}, sarif.Runs[0].Tool.Driver.Rules)
+
+	require.Len(t, sarif.Runs[0].Results, 2)
+	assert.Equal(t, SARIFResult{
+		RuleID:  "AVD-GCP-0001",
+		Level:   "error",
+		Message: SARIFMessage{Text: "Bucket allows public access. (google_storage_bucket.public_2, division my-project)"},
+		Locations: []SARIFLocation{
+			{
+				PhysicalLocation: SARIFPhysicalLocation{
+					ArtifactLocation: SARIFArtifactLocation{URI: "terraform/storage/new-resources.tf"},
+					Region:           &SARIFRegion{StartLine: 2, EndLine: 5},
+				},
+				LogicalLocations: []SARIFLogicalLocation{{FullyQualifiedName: "google_storage_bucket.public_2", Kind: "resource"}},
+			},
+		},
+	}, sarif.Runs[0].Results[0])
+	assert.Equal(t, "warning", sarif.Runs[0].Results[1].Level)
+	assert.Equal(t, "terraform/storage/new-resources.tf", sarif.Runs[0].Results[1].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+}
@@ -0,0 +1,175 @@
+package terraformSecurity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/baseline"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/clonedrepo"
+)
+
+const (
+	// ScopeGenerated scans the Terraform code generated by cloud-concierge for each division.
+	ScopeGenerated = "generated"
+
+	// ScopeChanged scans the repository directories changed by the pull request, i.e. those receiving new resources
+	// or drift remediation.
+	ScopeChanged = "changed"
+
+	// ScopeRepository scans the entire repository.
+	ScopeRepository = "repository"
+)
+
+// ScopeResults relates each repository scan scope to the findings within each scanned repository directory.
+type ScopeResults map[string]map[string][]Result
+
+// pathScanner is a security scanner able to scan an arbitrary directory.
+type pathScanner interface {
+	scanPath(ctx context.Context, scanningPath string) ([]Result, error)
+}
+
+// scopes returns the configured scan scopes, defaulting to the generated code.
+func (c Config) scopes() []string {
+	scopes := []string{}
+	for _, scope := range c.Scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		return []string{ScopeGenerated}
+	}
+	return scopes
+}
+
+// scansScope returns true if scope is one of the configured scan scopes.
+func (c Config) scansScope(scope string) bool {
+	for _, configured := range c.scopes() {
+		if configured == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// validateScopes returns an error when a configured scan scope is not supported.
+func (c Config) validateScopes() error {
+	for _, scope := range c.scopes() {
+		switch scope {
+		case ScopeGenerated, ScopeChanged, ScopeRepository:
+		default:
+			return fmt.Errorf("[security scan scope %v is not supported]", scope)
+		}
+	}
+	return nil
+}
+
+// scanRepositoryScopes loads the baseline of the repository, then scans the directories of the configured changed and
+// repository scopes. It runs once the generated code is written, so that the changed directories include it.
+func scanRepositoryScopes(ctx context.Context, store artifacts.Store, scanner pathScanner, config Config, workspaceToDirectory map[string]string) error {
+	if !config.scansScope(ScopeChanged) && !config.scansScope(ScopeRepository) {
+		return nil
+	}
+
+	accepted, err := baseline.LoadFromRepository()
+	if err != nil {
+		return fmt.Errorf("[scan_repository_scopes][error loading the baseline]%w", err)
+	}
+	return scanScopes(ctx, store, scanner, config, workspaceToDirectory, accepted)
+}
+
+// scanScopes scans the repository directories of the configured changed and repository scopes, writing the findings
+// not accepted within the baseline to mappings/scope-to-security-scan.json.
+func scanScopes(ctx context.Context, store artifacts.Store, scanner pathScanner, config Config, workspaceToDirectory map[string]string, accepted *baseline.Baseline) error {
+	scopeResults := ScopeResults{}
+
+	for _, scope := range []string{ScopeChanged, ScopeRepository} {
+		if !config.scansScope(scope) {
+			continue
+		}
+
+		directories := []string{"/"}
+		if scope == ScopeChanged {
+			var err error
+			directories, err = changedDirectories(store, workspaceToDirectory)
+			if err != nil {
+				return fmt.Errorf("[scan_scopes]%w", err)
+			}
+		}
+
+		scopeResults[scope] = map[string][]Result{}
+		for _, directory := range directories {
+			results, err := scanner.scanPath(ctx, filepath.Join(clonedrepo.Directory, directory))
+			if err != nil {
+				return fmt.Errorf("[scan_scopes][%v %v]%w", scope, directory, err)
+			}
+
+			filtered := make([]Result, 0, len(results))
+			for _, result := range results {
+				if !accepted.AcceptsSecurityFinding("", result.RuleID, result.LongID, findingAddress(result.Resource), time.Now()) {
+					filtered = append(filtered, result)
+				}
+			}
+			scopeResults[scope][directory] = filtered
+		}
+	}
+
+	if len(scopeResults) == 0 {
+		return nil
+	}
+
+	content, err := json.MarshalIndent(scopeResults, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[scan_scopes][json.MarshalIndent]%w", err)
+	}
+	return store.WriteFile("mappings/scope-to-security-scan.json", content, 0400)
+}
+
+// changedDirectories returns the sorted repository directories of the workspaces receiving new resources or drift
+// remediation within the pull request.
+func changedDirectories(store artifacts.Store, workspaceToDirectory map[string]string) ([]string, error) {
+	changedWorkspaces := map[string]bool{}
+
+	newResourcesToWorkspace := map[string]string{}
+	if store.Exists("mappings/new-resources-to-workspace.json") {
+		err := readMappingFile(store, "mappings/new-resources-to-workspace.json", &newResourcesToWorkspace)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, workspace := range newResourcesToWorkspace {
+		changedWorkspaces[workspace] = true
+	}
+
+	driftedResources := []struct{ StateFileName string }{}
+	if store.Exists("mappings/drift-attribute-diffs.json") {
+		err := readMappingFile(store, "mappings/drift-attribute-diffs.json", &driftedResources)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, resource := range driftedResources {
+		changedWorkspaces[resource.StateFileName] = true
+	}
+
+	changedDirectories := map[string]bool{}
+	for workspace, directory := range workspaceToDirectory {
+		if changedWorkspaces[workspace] {
+			changedDirectories[directory] = true
+		}
+	}
+
+	directories := make([]string, 0, len(changedDirectories))
+	for directory := range changedDirectories {
+		directories = append(directories, directory)
+	}
+	sort.Strings(directories)
+	return directories, nil
+}
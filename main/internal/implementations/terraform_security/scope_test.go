@@ -0,0 +1,104 @@
+package terraformSecurity
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/baseline"
+)
+
+// fakePathScanner returns a single finding for every scanned path.
+type fakePathScanner struct {
+	scannedPaths []string
+}
+
+func (s *fakePathScanner) scanPath(ctx context.Context, scanningPath string) ([]Result, error) {
+	s.scannedPaths = append(s.scannedPaths, scanningPath)
+	return []Result{{RuleID: "AVD-AWS-0086", Resource: "aws_s3_bucket.assets", Severity: "HIGH"}}, nil
+}
+
+func TestConfig_Scopes(t *testing.T) {
+	// Given
+	config := Config{Scopes: []string{" Changed", "repository", ""}}
+
+	// Then
+	assert.Equal(t, []string{ScopeGenerated}, Config{}.scopes())
+	assert.Equal(t, []string{ScopeChanged, ScopeRepository}, config.scopes())
+	assert.False(t, config.scansScope(ScopeGenerated))
+	assert.True(t, config.scansScope(ScopeChanged))
+	assert.NoError(t, config.validateScopes())
+	assert.Error(t, Config{Scopes: []string{"modules"}}.validateScopes())
+}
+
+func TestScanScopes(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	require.NoError(t, os.Mkdir("mappings", 0700))
+	require.NoError(t, os.WriteFile("mappings/new-resources-to-workspace.json", []byte(`{"aws-prod.aws_s3_bucket.tfer--assets": "prod"}`), 0600))
+	require.NoError(t, os.WriteFile("mappings/drift-attribute-diffs.json", []byte(`[{"StateFileName": "staging"}]`), 0600))
+	workspaceToDirectory := map[string]string{"prod": "/prod/", "staging": "/staging/", "dev": "/dev/"}
+
+	store := artifacts.NewMemoryStore(true)
+	scanner := &fakePathScanner{}
+	config := Config{Scopes: []string{"generated", "changed", "repository"}}
+
+	// When
+	err := scanScopes(context.Background(), store, scanner, config, workspaceToDirectory, &baseline.Baseline{})
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, []string{"repo/prod", "repo/staging", "repo"}, scanner.scannedPaths)
+
+	scopeResults := ScopeResults{}
+	require.NoError(t, readMappingFile(store, "mappings/scope-to-security-scan.json", &scopeResults))
+	assert.Len(t, scopeResults[ScopeChanged], 2)
+	assert.Len(t, scopeResults[ScopeRepository]["/"], 1)
+	assert.NotContains(t, scopeResults, ScopeGenerated)
+
+	// When the finding is accepted within the baseline
+	require.NoError(t, os.Remove("mappings/scope-to-security-scan.json"))
+	accepted := &baseline.Baseline{Security: []baseline.AcceptedSecurityFinding{{RuleID: "AVD-AWS-0086"}}}
+	err = scanScopes(context.Background(), store, &fakePathScanner{}, Config{Scopes: []string{"repository"}}, workspaceToDirectory, accepted)
+
+	// Then
+	require.NoError(t, err)
+	scopeResults = ScopeResults{}
+	require.NoError(t, readMappingFile(store, "mappings/scope-to-security-scan.json", &scopeResults))
+	assert.Empty(t, scopeResults[ScopeRepository]["/"])
+}
+
+func TestScanRepositoryScopes(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	require.NoError(t, os.Mkdir("mappings", 0700))
+	require.NoError(t, os.WriteFile("mappings/new-resources-to-workspace.json", []byte(`{"aws-prod.aws_s3_bucket.tfer--assets": "unmanaged"}`), 0600))
+	workspaceToDirectory := map[string]string{"prod": "/prod/", "unmanaged": "/unmanaged/"}
+
+	// When only the generated code is scanned
+	store := artifacts.NewMemoryStore(true)
+	scanner := &fakePathScanner{}
+	err := scanRepositoryScopes(context.Background(), store, scanner, Config{}, workspaceToDirectory)
+
+	// Then
+	require.NoError(t, err)
+	assert.Empty(t, scanner.scannedPaths)
+	assert.NoFileExists(t, "mappings/scope-to-security-scan.json")
+
+	// When the changed directories are scanned, including a workspace created for the generated code
+	err = scanRepositoryScopes(context.Background(), store, scanner, Config{Scopes: []string{"changed"}}, workspaceToDirectory)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, []string{"repo/unmanaged"}, scanner.scannedPaths)
+}
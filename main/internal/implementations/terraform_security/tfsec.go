@@ -7,8 +7,10 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
 	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
 )
@@ -61,21 +63,33 @@ type TFSec struct {
 	// For AWS, an account is the division, for GCP a project name is the division,
 	// and for azurerm a resource group is a division.
 	divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider
+
+	// config is the configuration of the security scan.
+	config Config
+
+	// artifactStore holds the mappings read and written by the scan.
+	artifactStore artifacts.Store
 }
 
 // NewTFSec generates a new instance from TFSec
-func NewTFSec(divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider) *TFSec {
+func NewTFSec(divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider, config Config, artifactStore artifacts.Store) *TFSec {
 	return &TFSec{
 		divisionToProvider: divisionToProvider,
+		config:             config,
+		artifactStore:      artifactStore,
 	}
 }
 
 // ExecuteScan is called from the main job flow to execute the tfsec command and save the output
 // to show to the user in the PR
-func (s *TFSec) ExecuteScan(ctx context.Context) error {
-	contentResults, err := s.runTFSec()
-	if err != nil {
-		return fmt.Errorf("[tfsec][execute_scan][error running tfsec command][%v]", err)
+func (s *TFSec) ExecuteScan(ctx context.Context, workspaceToDirectory map[string]string) error {
+	contentResults := TFSecFileBytesPerDivision{}
+	if s.config.scansScope(ScopeGenerated) {
+		var err error
+		contentResults, err = s.runTFSec()
+		if err != nil {
+			return fmt.Errorf("[tfsec][execute_scan][error running tfsec command][%v]", err)
+		}
 	}
 
 	parsedContentResults, err := s.parseContentResults(contentResults)
@@ -93,7 +107,12 @@ func (s *TFSec) ExecuteScan(ctx context.Context) error {
 		return fmt.Errorf("[tfsec][execute_scan][error adding the id to the tfsec results][%v]", err)
 	}
 
-	err = s.writeResultsToMappingFile(mergedResultsWithID)
+	unacceptedResults, err := suppressAcceptedFindings(mergedResultsWithID)
+	if err != nil {
+		return fmt.Errorf("[tfsec][execute_scan][%v]", err)
+	}
+
+	err = s.writeResultsToMappingFile(unacceptedResults)
 	if err != nil {
 		return fmt.Errorf("[tfsec][execute_scan][error writing tfsec results][%v]", err)
 	}
@@ -101,6 +120,16 @@ func (s *TFSec) ExecuteScan(ctx context.Context) error {
 	return nil
 }
 
+// ScanRepository is called once the generated code is written to the repository to scan the directories of the
+// configured changed and repository scopes.
+func (s *TFSec) ScanRepository(ctx context.Context, workspaceToDirectory map[string]string) error {
+	err := scanRepositoryScopes(ctx, s.artifactStore, s, s.config, workspaceToDirectory)
+	if err != nil {
+		return fmt.Errorf("[tfsec][scan_repository][%v]", err)
+	}
+	return nil
+}
+
 // runTFSec runs the tfsec command through the directories from the divisions configured by the user
 func (s *TFSec) runTFSec() (TFSecFileBytesPerDivision, error) {
 	contentResults := map[terraformValueObjects.Division][]byte{}
@@ -111,7 +140,7 @@ func (s *TFSec) runTFSec() (TFSecFileBytesPerDivision, error) {
 		outLocationFlag := fmt.Sprintf("./current_cloud/%s/tfsec.json", divisionFolderName)
 		outFlag := fmt.Sprintf("--out=%s", outLocationFlag)
 
-		cmd := exec.Command("tfsec", outFlag, "--format=json", "--soft-fail", tfsecScanningPath)
+		cmd := exec.Command("tfsec", s.tfsecArgs(outFlag, tfsecScanningPath)...)
 
 		var out bytes.Buffer
 		cmd.Stdout = &out
@@ -132,6 +161,47 @@ func (s *TFSec) runTFSec() (TFSecFileBytesPerDivision, error) {
 	return contentResults, nil
 }
 
+// scanPath runs a tfsec scan of scanningPath, writing the output outside of the scanned directory.
+func (s *TFSec) scanPath(ctx context.Context, scanningPath string) ([]Result, error) {
+	outputDirectory, err := os.MkdirTemp("", "tfsec")
+	if err != nil {
+		return nil, fmt.Errorf("[os.MkdirTemp]%w", err)
+	}
+	defer os.RemoveAll(outputDirectory)
+
+	outputPath := filepath.Join(outputDirectory, "tfsec.json")
+	cmd := exec.CommandContext(ctx, "tfsec", s.tfsecArgs(fmt.Sprintf("--out=%s", outputPath), scanningPath)...)
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+
+	err = cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("%s, %w", out.String(), err)
+	}
+
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		return nil, fmt.Errorf("[os.ReadFile]%w", err)
+	}
+
+	var tfSecFile TFSecFile
+	err = json.Unmarshal(content, &tfSecFile)
+	if err != nil {
+		return nil, fmt.Errorf("[json.Unmarshal]%w", err)
+	}
+	return tfSecFile.Results, nil
+}
+
+// tfsecArgs returns the arguments of a tfsec scan of tfsecScanningPath, including any custom rego checks.
+func (s *TFSec) tfsecArgs(outFlag string, tfsecScanningPath string) []string {
+	args := []string{outFlag, "--format=json", "--soft-fail"}
+	for _, directory := range s.config.policyDirectories() {
+		args = append(args, fmt.Sprintf("--rego-policy-dir=%s", directory))
+	}
+	return append(args, tfsecScanningPath)
+}
+
 // parseContentResults takes the bytes of the output tfsec results and returns the same bytes parsed
 func (s *TFSec) parseContentResults(contentResults TFSecFileBytesPerDivision) (TFSecParsedFilePerDivision, error) {
 	tfSecFiles := map[terraformValueObjects.Division]TFSecFile{}
@@ -167,7 +237,7 @@ func (s *TFSec) writeResultsToMappingFile(results TFSecResultsPerDivision) error
 		return err
 	}
 
-	return os.WriteFile("mappings/division-to-security-scan.json", differencesJSON, 0400)
+	return s.artifactStore.WriteFile("mappings/division-to-security-scan.json", differencesJSON, 0400)
 }
 
 // addIDToResources takes the results grouped by division and adds the id of the resource
@@ -221,9 +291,11 @@ func (s *TFSec) getResultsWithResourceID(results []Result, resources map[driftDe
 
 		// split tfSecResourceName by "." and join the first two elements by "."
 		// to match what is in the state file
-		tfSecResourceName := strings.Join(strings.Split(result.Resource, ".")[:2], ".")
-
-		result.ID = resources[driftDetector.ResourceIdentifier(tfSecResourceName)]
+		resourceNameParts := strings.Split(result.Resource, ".")
+		if len(resourceNameParts) >= 2 {
+			tfSecResourceName := strings.Join(resourceNameParts[:2], ".")
+			result.ID = resources[driftDetector.ResourceIdentifier(tfSecResourceName)]
+		}
 		resultsWithID = append(resultsWithID, result)
 	}
 	return resultsWithID
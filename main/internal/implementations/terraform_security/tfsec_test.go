@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
 	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
 )
 
@@ -77,3 +78,14 @@ func TestMapResourceIDsFromStateFile(t *testing.T) {
 	// Then
 	assert.Equal(t, expectedResourcesMap, resourcesMap, "The expected and actual resource maps should match")
 }
+
+func TestTFSec_TFSecArgs(t *testing.T) {
+	// Given
+	tfsec := NewTFSec(nil, Config{Scanner: "tfsec", CustomChecksDirectory: "/custom-checks"}, artifacts.NewMemoryStore(true))
+
+	// When
+	args := tfsec.tfsecArgs("--out=./current_cloud/aws-prod/tfsec.json", "./current_cloud/aws-prod")
+
+	// Then
+	assert.Equal(t, []string{"--out=./current_cloud/aws-prod/tfsec.json", "--format=json", "--soft-fail", "--rego-policy-dir=/custom-checks", "./current_cloud/aws-prod"}, args)
+}
@@ -0,0 +1,239 @@
+package terraformSecurity
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+// TrivyFile is a structure that represents the content of a trivy config scan output file
+type TrivyFile struct {
+	Results []TrivyResult `json:"Results"`
+}
+
+// TrivyResult is the set of misconfigurations found within a single scanned file
+type TrivyResult struct {
+	Target            string                  `json:"Target"`
+	Misconfigurations []TrivyMisconfiguration `json:"Misconfigurations"`
+}
+
+// TrivyMisconfiguration is a single failed check within a trivy config scan output file
+type TrivyMisconfiguration struct {
+	ID            string             `json:"ID"`
+	AVDID         string             `json:"AVDID"`
+	Title         string             `json:"Title"`
+	Description   string             `json:"Description"`
+	Message       string             `json:"Message"`
+	Resolution    string             `json:"Resolution"`
+	Severity      string             `json:"Severity"`
+	PrimaryURL    string             `json:"PrimaryURL"`
+	References    []string           `json:"References"`
+	Status        string             `json:"Status"`
+	CauseMetadata TrivyCauseMetadata `json:"CauseMetadata"`
+}
+
+// TrivyCauseMetadata is the location of the resource that caused a misconfiguration
+type TrivyCauseMetadata struct {
+	Resource  string `json:"Resource"`
+	Provider  string `json:"Provider"`
+	Service   string `json:"Service"`
+	StartLine int    `json:"StartLine"`
+	EndLine   int    `json:"EndLine"`
+}
+
+// Trivy is a struct that implements the interfaces.TerraformSecurity by executing trivy in config scanning mode.
+// Findings are shaped as tfsec results, so that the report generator is agnostic of the scanner.
+type Trivy struct {
+	// divisionToProvider is a map between the string representing a division and the corresponding
+	// cloud provider (aws, azurerm, google, etc.).
+	divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider
+
+	// results adds resource ids to, and writes, the tfsec shaped results.
+	results *TFSec
+
+	// config is the configuration of the security scan.
+	config Config
+
+	// artifactStore holds the mappings read and written by the scan.
+	artifactStore artifacts.Store
+}
+
+// NewTrivy generates a new instance from Trivy
+func NewTrivy(divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider, config Config, artifactStore artifacts.Store) *Trivy {
+	return &Trivy{
+		divisionToProvider: divisionToProvider,
+		results:            NewTFSec(divisionToProvider, config, artifactStore),
+		config:             config,
+		artifactStore:      artifactStore,
+	}
+}
+
+// ExecuteScan is called from the main job flow to execute the trivy command and save the output
+// to show to the user in the PR
+func (s *Trivy) ExecuteScan(ctx context.Context, workspaceToDirectory map[string]string) error {
+	results := TFSecResultsPerDivision{}
+	if s.config.scansScope(ScopeGenerated) {
+		var err error
+		results, err = s.runTrivy(ctx)
+		if err != nil {
+			return fmt.Errorf("[trivy][execute_scan][error running trivy command]%w", err)
+		}
+	}
+
+	resultsWithID, err := s.results.addIDToResources(results)
+	if err != nil {
+		return fmt.Errorf("[trivy][execute_scan][error adding the id to the trivy results]%w", err)
+	}
+
+	unacceptedResults, err := suppressAcceptedFindings(resultsWithID)
+	if err != nil {
+		return fmt.Errorf("[trivy][execute_scan]%w", err)
+	}
+
+	err = s.results.writeResultsToMappingFile(unacceptedResults)
+	if err != nil {
+		return fmt.Errorf("[trivy][execute_scan][error writing trivy results]%w", err)
+	}
+
+	return nil
+}
+
+// ScanRepository is called once the generated code is written to the repository to scan the directories of the
+// configured changed and repository scopes.
+func (s *Trivy) ScanRepository(ctx context.Context, workspaceToDirectory map[string]string) error {
+	err := scanRepositoryScopes(ctx, s.artifactStore, s, s.config, workspaceToDirectory)
+	if err != nil {
+		return fmt.Errorf("[trivy][scan_repository]%w", err)
+	}
+	return nil
+}
+
+// runTrivy runs a trivy config scan of the directory of each division, returning the findings as tfsec results
+func (s *Trivy) runTrivy(ctx context.Context) (TFSecResultsPerDivision, error) {
+	resultsPerDivision := TFSecResultsPerDivision{}
+
+	for division, provider := range s.divisionToProvider {
+		divisionFolderName := fmt.Sprintf("%v-%v", provider, division)
+		scanningPath := fmt.Sprintf("./current_cloud/%v", divisionFolderName)
+		outputPath := fmt.Sprintf("./current_cloud/%v/trivy.json", divisionFolderName)
+
+		results, err := s.scan(ctx, outputPath, scanningPath)
+		if err != nil {
+			return nil, fmt.Errorf("[%v]%w", division, err)
+		}
+		resultsPerDivision[division] = results
+	}
+
+	return resultsPerDivision, nil
+}
+
+// scanPath runs a trivy config scan of scanningPath, writing the output outside of the scanned directory.
+func (s *Trivy) scanPath(ctx context.Context, scanningPath string) ([]Result, error) {
+	outputDirectory, err := os.MkdirTemp("", "trivy")
+	if err != nil {
+		return nil, fmt.Errorf("[os.MkdirTemp]%w", err)
+	}
+	defer os.RemoveAll(outputDirectory)
+
+	return s.scan(ctx, filepath.Join(outputDirectory, "trivy.json"), scanningPath)
+}
+
+// scan runs a trivy config scan of scanningPath, returning the findings written to outputPath as tfsec results
+func (s *Trivy) scan(ctx context.Context, outputPath string, scanningPath string) ([]Result, error) {
+	cmd := exec.CommandContext(ctx, "trivy", s.trivyArgs(outputPath, scanningPath)...)
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	err := cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("%s, %w", out.String(), err)
+	}
+
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		return nil, fmt.Errorf("[os.ReadFile]%w", err)
+	}
+
+	results, err := parseTrivyResults(content)
+	if err != nil {
+		return nil, fmt.Errorf("[parseTrivyResults]%w", err)
+	}
+	return results, nil
+}
+
+// trivyArgs returns the arguments of a trivy config scan of scanningPath, including any custom checks and policy
+// bundles.
+func (s *Trivy) trivyArgs(outputPath string, scanningPath string) []string {
+	args := []string{"config", "--format", "json", "--output", outputPath, "--exit-code", "0"}
+	for _, directory := range s.config.policyDirectories() {
+		args = append(args, "--config-policy", directory)
+	}
+	if len(s.config.PolicyNamespaces) > 0 {
+		args = append(args, "--policy-namespaces", strings.Join(s.config.PolicyNamespaces, ","))
+	}
+	return append(args, scanningPath)
+}
+
+// parseTrivyResults converts the failed checks of a trivy config scan output file into tfsec results
+func parseTrivyResults(content []byte) ([]Result, error) {
+	trivyFile := TrivyFile{}
+	err := json.Unmarshal(content, &trivyFile)
+	if err != nil {
+		return nil, fmt.Errorf("[json.Unmarshal]%w", err)
+	}
+
+	results := make([]Result, 0)
+	for _, trivyResult := range trivyFile.Results {
+		for _, misconfiguration := range trivyResult.Misconfigurations {
+			if misconfiguration.Status != "" && misconfiguration.Status != "FAIL" {
+				continue
+			}
+
+			links := make([]string, 0)
+			if misconfiguration.PrimaryURL != "" {
+				links = append(links, misconfiguration.PrimaryURL)
+			}
+			for _, reference := range misconfiguration.References {
+				if reference != misconfiguration.PrimaryURL {
+					links = append(links, reference)
+				}
+			}
+
+			// Custom checks are not registered within the Aqua vulnerability database, so only have an ID.
+			ruleID := misconfiguration.AVDID
+			if ruleID == "" {
+				ruleID = misconfiguration.ID
+			}
+
+			results = append(results, Result{
+				RuleID:          ruleID,
+				LongID:          misconfiguration.ID,
+				RuleDescription: misconfiguration.Title,
+				RuleProvider:    strings.ToLower(misconfiguration.CauseMetadata.Provider),
+				RuleService:     misconfiguration.CauseMetadata.Service,
+				Impact:          misconfiguration.Message,
+				Resolution:      misconfiguration.Resolution,
+				Links:           links,
+				Description:     misconfiguration.Description,
+				Severity:        misconfiguration.Severity,
+				Resource:        misconfiguration.CauseMetadata.Resource,
+				Location: Location{
+					FileName:  trivyResult.Target,
+					StartLine: misconfiguration.CauseMetadata.StartLine,
+					EndLine:   misconfiguration.CauseMetadata.EndLine,
+				},
+			})
+		}
+	}
+	return results, nil
+}
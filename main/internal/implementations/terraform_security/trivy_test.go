@@ -0,0 +1,102 @@
+package terraformSecurity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+)
+
+func TestParseTrivyResults(t *testing.T) {
+	// Given
+	content := []byte(`{
+		"SchemaVersion": 2,
+		"Results": [
+			{
+				"Target": "resources.tf",
+				"Class": "config",
+				"Type": "terraform",
+				"Misconfigurations": [
+					{
+						"Type": "Terraform Security Check",
+						"ID": "AVD-AWS-0086",
+						"AVDID": "AVD-AWS-0086",
+						"Title": "S3 Access block should block public ACL",
+						"Description": "S3 buckets should block public ACLs on buckets and any objects they contain.",
+						"Message": "No public access block so not blocking public acls",
+						"Resolution": "Enable blocking any PUT calls with a public ACL specified",
+						"Severity": "HIGH",
+						"PrimaryURL": "https://avd.aquasec.com/misconfig/avd-aws-0086",
+						"References": ["https://avd.aquasec.com/misconfig/avd-aws-0086", "https://docs.aws.amazon.com/AmazonS3/latest/userguide/access-control-block-public-access.html"],
+						"Status": "FAIL",
+						"CauseMetadata": {"Resource": "aws_s3_bucket.tfer--my-bucket", "Provider": "AWS", "Service": "s3", "StartLine": 1, "EndLine": 10}
+					},
+					{
+						"ID": "AVD-AWS-0088",
+						"AVDID": "AVD-AWS-0088",
+						"Status": "PASS",
+						"CauseMetadata": {"Resource": "aws_s3_bucket.tfer--my-bucket"}
+					}
+				]
+			},
+			{"Target": "provider.tf", "Class": "config", "Type": "terraform"}
+		]
+	}`)
+
+	// When
+	results, err := parseTrivyResults(content)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, []Result{{
+		RuleID:          "AVD-AWS-0086",
+		LongID:          "AVD-AWS-0086",
+		RuleDescription: "S3 Access block should block public ACL",
+		RuleProvider:    "aws",
+		RuleService:     "s3",
+		Impact:          "No public access block so not blocking public acls",
+		Resolution:      "Enable blocking any PUT calls with a public ACL specified",
+		Links: []string{
+			"https://avd.aquasec.com/misconfig/avd-aws-0086",
+			"https://docs.aws.amazon.com/AmazonS3/latest/userguide/access-control-block-public-access.html",
+		},
+		Description: "S3 buckets should block public ACLs on buckets and any objects they contain.",
+		Severity:    "HIGH",
+		Resource:    "aws_s3_bucket.tfer--my-bucket",
+		Location:    Location{FileName: "resources.tf", StartLine: 1, EndLine: 10},
+	}}, results)
+
+	// When the output is invalid
+	_, err = parseTrivyResults([]byte("not json"))
+
+	// Then
+	assert.Error(t, err)
+}
+
+func TestTrivy_TrivyArgs(t *testing.T) {
+	// Given
+	trivy := NewTrivy(nil, Config{
+		CustomChecksDirectory: "/custom-checks",
+		PolicyBundles:         []string{"/policy-bundles/org"},
+		PolicyNamespaces:      []string{"org", "custom"},
+	}, artifacts.NewMemoryStore(true))
+
+	// When
+	args := trivy.trivyArgs("./current_cloud/aws-prod/trivy.json", "./current_cloud/aws-prod")
+
+	// Then
+	assert.Equal(t, []string{
+		"config", "--format", "json", "--output", "./current_cloud/aws-prod/trivy.json", "--exit-code", "0",
+		"--config-policy", "/custom-checks", "--config-policy", "/policy-bundles/org",
+		"--policy-namespaces", "org,custom",
+		"./current_cloud/aws-prod",
+	}, args)
+
+	// When without custom checks
+	args = NewTrivy(nil, Config{}, artifacts.NewMemoryStore(true)).trivyArgs("./current_cloud/aws-prod/trivy.json", "./current_cloud/aws-prod")
+
+	// Then
+	assert.Equal(t, []string{"config", "--format", "json", "--output", "./current_cloud/aws-prod/trivy.json", "--exit-code", "0", "./current_cloud/aws-prod"}, args)
+}
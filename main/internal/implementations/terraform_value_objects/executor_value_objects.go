@@ -1,6 +1,7 @@
 package terraformValueObjects
 
 import (
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"strings"
@@ -10,6 +11,35 @@ import (
 // The string is a json structure in json format.
 type Credential string
 
+// AmbientCredentialField is the credential field naming the provider whose ambient credentials, e.g. an instance
+// profile, workload identity or managed identity, are used for the division instead of key material.
+const AmbientCredentialField = "ambient"
+
+// IsAmbient returns true if the credential is an empty json object or sets AmbientCredentialField, in which case
+// the credentials available within the container's environment are used.
+func (c Credential) IsAmbient() bool {
+	credentialMapped := map[string]interface{}{}
+	err := json.Unmarshal([]byte(c), &credentialMapped)
+	if err != nil {
+		return false
+	}
+
+	_, ok := credentialMapped[AmbientCredentialField]
+	return ok || len(credentialMapped) == 0
+}
+
+// AmbientProvider returns the provider named within an ambient credential, or an empty string if none is named.
+func (c Credential) AmbientProvider() Provider {
+	credentialMapped := map[string]interface{}{}
+	err := json.Unmarshal([]byte(c), &credentialMapped)
+	if err != nil {
+		return ""
+	}
+
+	provider, _ := credentialMapped[AmbientCredentialField].(string)
+	return Provider(strings.TrimSpace(provider))
+}
+
 // Division is the name of a division within a cloud provider. For AWS a region, for Azure a resource group, and for GCP
 // this is a project name.
 type Division string
@@ -48,6 +78,22 @@ type Path string
 // Provider is the name of a cloud computing resource provider.
 type Provider string
 
+// Namespace returns the Terraform registry namespace under which the provider is published.
+func (p Provider) Namespace() string {
+	switch p {
+	case "datadog":
+		return "datadog"
+	case "oci":
+		return "oracle"
+	case "digitalocean":
+		return "digitalocean"
+	case "okta":
+		return "okta"
+	default:
+		return "hashicorp"
+	}
+}
+
 // Version is a Terraform module version string.
 type Version string
 
@@ -100,3 +100,26 @@ func TestVersionDecoder(t *testing.T) {
 		})
 	}
 }
+
+func TestCredential_IsAmbient(t *testing.T) {
+	assert.True(t, Credential(`{}`).IsAmbient())
+	assert.True(t, Credential(`{"ambient": "aws"}`).IsAmbient())
+	assert.False(t, Credential(`{"awsAccessKeyID": "key", "awsSecretAccessKey": "secret"}`).IsAmbient())
+	assert.False(t, Credential(`not json`).IsAmbient())
+
+	assert.Equal(t, Provider("google"), Credential(`{"ambient": "google"}`).AmbientProvider())
+	assert.Equal(t, Provider(""), Credential(`{}`).AmbientProvider())
+}
+
+func TestDivisionCloudCredentialDecoder_Ambient(t *testing.T) {
+	// Given
+	decoder := DivisionCloudCredentialDecoder{}
+
+	// When
+	err := decoder.Decode(`prod:{},sandbox:{"ambient": "aws"}`)
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, Credential(`{}`), decoder["prod"])
+	assert.Equal(t, Credential(`{"ambient": "aws"}`), decoder["sandbox"])
+}
@@ -1,7 +1,9 @@
 package terraformValueObjects
 
 import (
+	"encoding/json"
 	"fmt"
+	"regexp"
 	"strings"
 )
 
@@ -36,6 +38,105 @@ func (rnl *ResourceNameList) Decode(value string) error {
 	return nil
 }
 
+// ResourceTagSelectors is a map between a resource tag key and the tag values that select a resource
+// for that key. Multiple keys must all match, while multiple values for the same key match any value.
+type ResourceTagSelectors map[string][]string
+
+// Decode allows ResourceTagSelectors to be decoded by the ENVConfig function from a json object
+// of the form {"env": ["prod"], "team": ["platform", "data"]}.
+func (rts *ResourceTagSelectors) Decode(value string) error {
+	if strings.Trim(value, " ") == "" {
+		return nil
+	}
+
+	selectors := map[string][]string{}
+	err := json.Unmarshal([]byte(value), &selectors)
+	if err != nil {
+		return fmt.Errorf("expected the tag selectors formatted as a json object of tag keys to a list of tag values: %v", err)
+	}
+
+	for key, values := range selectors {
+		if strings.Trim(key, " ") == "" {
+			return fmt.Errorf("tag selector keys cannot be empty")
+		}
+		if len(values) == 0 {
+			return fmt.Errorf("at least one tag value must be specified for the tag key %v", key)
+		}
+	}
+
+	*rts = selectors
+	return nil
+}
+
+// Matches returns true if the passed flattened resource attributes contain, for every selector key,
+// one of the selected values under the specified tag attribute prefix (e.g. "tags" or "labels").
+func (rts ResourceTagSelectors) Matches(tagPrefix string, attributesFlat map[string]string) bool {
+	if len(rts) == 0 {
+		return false
+	}
+
+	for key, values := range rts {
+		tagValue, ok := attributesFlat[fmt.Sprintf("%v.%v", tagPrefix, key)]
+		if !ok {
+			return false
+		}
+
+		keyMatched := false
+		for _, value := range values {
+			if tagValue == value {
+				keyMatched = true
+				break
+			}
+		}
+
+		if !keyMatched {
+			return false
+		}
+	}
+	return true
+}
+
+// ResourcePatternList is a list of compiled regular expressions used to match resource identifiers or names.
+type ResourcePatternList []*regexp.Regexp
+
+// Decode allows ResourcePatternList to be decoded by the ENVConfig function from a json list of
+// regular expressions, e.g. ["^packer-.*", "-tmp$"].
+func (rpl *ResourcePatternList) Decode(value string) error {
+	if strings.Trim(value, " ") == "" {
+		return nil
+	}
+
+	rawPatterns := make([]string, 0)
+	err := json.Unmarshal([]byte(value), &rawPatterns)
+	if err != nil {
+		return fmt.Errorf("expected the patterns formatted as a json list of regular expressions: %v", err)
+	}
+
+	patterns := ResourcePatternList{}
+	for _, rawPattern := range rawPatterns {
+		pattern, err := regexp.Compile(rawPattern)
+		if err != nil {
+			return fmt.Errorf("the pattern %v is not a valid regular expression: %v", rawPattern, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+
+	*rpl = patterns
+	return nil
+}
+
+// MatchesAny returns true if any of the passed values is matched by any pattern within the list.
+func (rpl ResourcePatternList) MatchesAny(values ...string) bool {
+	for _, pattern := range rpl {
+		for _, value := range values {
+			if value != "" && pattern.MatchString(value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // RemoteCloudReference is the identifying string where a resource is located within a remote cloud
 // environment for use within a `terraform import` statement
 type RemoteCloudReference string
@@ -61,3 +61,96 @@ func TestResourceNameList_Decode(t *testing.T) {
 		t.Errorf("got length:\n%v\nexpected length of 0.", len(envVar))
 	}
 }
+
+func TestResourceTagSelectors_Decode(t *testing.T) {
+	// Simple case with expected success
+	envVar := ResourceTagSelectors{}
+	input := `{"env": ["prod"], "team": ["platform", "data"]}`
+
+	err := envVar.Decode(input)
+	if err != nil {
+		t.Errorf("unexpected error in envVar.Decode: %v", err)
+	}
+
+	expectedValue := ResourceTagSelectors{
+		"env":  {"prod"},
+		"team": {"platform", "data"},
+	}
+
+	if !reflect.DeepEqual(expectedValue, envVar) {
+		t.Errorf("got:\n%v\nexpected:\n%v\n", envVar, expectedValue)
+	}
+
+	// Case with expected error due to a key without values
+	envVar = ResourceTagSelectors{}
+	input = `{"env": []}`
+
+	err = envVar.Decode(input)
+	if err == nil {
+		t.Errorf("expected error in envVar.Decode: %v", err)
+	}
+
+	// Case with expected error due to a non-json value
+	envVar = ResourceTagSelectors{}
+	input = `env=prod`
+
+	err = envVar.Decode(input)
+	if err == nil {
+		t.Errorf("expected error in envVar.Decode: %v", err)
+	}
+}
+
+func TestResourceTagSelectors_Matches(t *testing.T) {
+	selectors := ResourceTagSelectors{
+		"env":  {"prod"},
+		"team": {"platform", "data"},
+	}
+
+	if !selectors.Matches("tags", map[string]string{"tags.env": "prod", "tags.team": "data"}) {
+		t.Errorf("expected attributes to match the tag selectors")
+	}
+
+	if selectors.Matches("tags", map[string]string{"tags.env": "prod"}) {
+		t.Errorf("expected attributes missing a tag key not to match the tag selectors")
+	}
+
+	if selectors.Matches("tags", map[string]string{"labels.env": "prod", "labels.team": "data"}) {
+		t.Errorf("expected attributes with a different tag prefix not to match the tag selectors")
+	}
+
+	if (ResourceTagSelectors{}).Matches("tags", map[string]string{"tags.env": "prod"}) {
+		t.Errorf("expected empty tag selectors not to match")
+	}
+}
+
+func TestResourcePatternList_Decode(t *testing.T) {
+	// Simple case with expected success
+	envVar := ResourcePatternList{}
+	input := `["^packer-.*", "-tmp$"]`
+
+	err := envVar.Decode(input)
+	if err != nil {
+		t.Errorf("unexpected error in envVar.Decode: %v", err)
+	}
+
+	if len(envVar) != 2 {
+		t.Errorf("got length:\n%v\nexpected length of 2.", len(envVar))
+	}
+
+	if !envVar.MatchesAny("", "packer-1234") {
+		t.Errorf("expected packer-1234 to be matched")
+	}
+
+	if envVar.MatchesAny("web-server", "") {
+		t.Errorf("expected web-server not to be matched")
+	}
+
+	// Case with expected error due to an invalid regular expression
+	envVar = ResourcePatternList{}
+	input = `["packer-(.*"]`
+
+	err = envVar.Decode(input)
+	if err == nil {
+		t.Errorf("expected error in envVar.Decode: %v", err)
+	}
+}
@@ -101,6 +101,81 @@ var GoogleRegions = map[string]bool{
 	"us-west4":                true,
 }
 
+var OciRegions = map[string]bool{
+	"af-johannesburg-1": true,
+	"ap-chuncheon-1":    true,
+	"ap-hyderabad-1":    true,
+	"ap-melbourne-1":    true,
+	"ap-mumbai-1":       true,
+	"ap-osaka-1":        true,
+	"ap-seoul-1":        true,
+	"ap-singapore-1":    true,
+	"ap-sydney-1":       true,
+	"ap-tokyo-1":        true,
+	"ca-montreal-1":     true,
+	"ca-toronto-1":      true,
+	"eu-amsterdam-1":    true,
+	"eu-frankfurt-1":    true,
+	"eu-madrid-1":       true,
+	"eu-marseille-1":    true,
+	"eu-milan-1":        true,
+	"eu-paris-1":        true,
+	"eu-stockholm-1":    true,
+	"eu-zurich-1":       true,
+	"il-jerusalem-1":    true,
+	"me-abudhabi-1":     true,
+	"me-dubai-1":        true,
+	"me-jeddah-1":       true,
+	"mx-queretaro-1":    true,
+	"sa-santiago-1":     true,
+	"sa-saopaulo-1":     true,
+	"sa-vinhedo-1":      true,
+	"uk-cardiff-1":      true,
+	"uk-london-1":       true,
+	"us-ashburn-1":      true,
+	"us-chicago-1":      true,
+	"us-phoenix-1":      true,
+	"us-sanjose-1":      true,
+}
+
+// OciRegionKeys maps the lower-case three-letter key of each OCI region, used within OCIDs, to its region identifier.
+var OciRegionKeys = map[string]string{
+	"ams": "eu-amsterdam-1",
+	"arn": "eu-stockholm-1",
+	"auh": "me-abudhabi-1",
+	"bom": "ap-mumbai-1",
+	"cdg": "eu-paris-1",
+	"cwl": "uk-cardiff-1",
+	"dxb": "me-dubai-1",
+	"fra": "eu-frankfurt-1",
+	"gru": "sa-saopaulo-1",
+	"hyd": "ap-hyderabad-1",
+	"iad": "us-ashburn-1",
+	"icn": "ap-seoul-1",
+	"jed": "me-jeddah-1",
+	"jnb": "af-johannesburg-1",
+	"kix": "ap-osaka-1",
+	"lhr": "uk-london-1",
+	"lin": "eu-milan-1",
+	"mad": "eu-madrid-1",
+	"mel": "ap-melbourne-1",
+	"mrs": "eu-marseille-1",
+	"mtz": "il-jerusalem-1",
+	"nrt": "ap-tokyo-1",
+	"ord": "us-chicago-1",
+	"phx": "us-phoenix-1",
+	"qro": "mx-queretaro-1",
+	"scl": "sa-santiago-1",
+	"sin": "ap-singapore-1",
+	"sjc": "us-sanjose-1",
+	"syd": "ap-sydney-1",
+	"vcp": "sa-vinhedo-1",
+	"yny": "ap-chuncheon-1",
+	"yul": "ca-montreal-1",
+	"yyz": "ca-toronto-1",
+	"zrh": "eu-zurich-1",
+}
+
 type CloudRegion string
 
 type CloudRegionsDecoder []CloudRegion
@@ -109,6 +184,7 @@ func (d *CloudRegionsDecoder) Decode(value string) error {
 	azureAlreadySet := false
 	awsAlreadySet := false
 	googleAlreadySet := false
+	ociAlreadySet := false
 	regions := make([]CloudRegion, 0)
 	if strings.Trim(value, " ") == "" || strings.Trim(value, " ") == "[]" {
 		return nil
@@ -147,6 +223,11 @@ func (d *CloudRegionsDecoder) Decode(value string) error {
 			if err != nil {
 				return err
 			}
+		} else if OciRegions[region] {
+			err := addProviderRegionIfValid(region, &regions, "oci", &ociAlreadySet)
+			if err != nil {
+				return err
+			}
 		} else {
 			return fmt.Errorf("the region %v is not valid", region)
 		}
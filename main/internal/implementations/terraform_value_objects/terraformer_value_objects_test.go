@@ -74,6 +74,20 @@ func TestCloudRegionsDecoder_Decode(t *testing.T) {
 			expectedValue: []CloudRegion{"us-east-1", "us-west1", "westus2"},
 			wantErr:       nil,
 		},
+		{
+			name:          "happy path with oci",
+			d:             CloudRegionsDecoder{},
+			args:          args{value: `["us-east-1","us-ashburn-1"]`},
+			expectedValue: []CloudRegion{"us-east-1", "us-ashburn-1"},
+			wantErr:       nil,
+		},
+		{
+			name:          "only one region per provider error oci",
+			d:             CloudRegionsDecoder{},
+			args:          args{value: `["us-ashburn-1","uk-london-1"]`},
+			expectedValue: []CloudRegion{},
+			wantErr:       assert.Error,
+		},
 		{
 			name:          "only one region per provider error aws",
 			d:             CloudRegionsDecoder{},
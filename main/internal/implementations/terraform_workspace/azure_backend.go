@@ -4,8 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 	"net/url"
 	"os"
+	"strings"
 
 	"github.com/Azure/azure-storage-blob-go/azblob"
 
@@ -13,6 +16,16 @@ import (
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
 )
 
+// azureStorageResource is the resource for which Azure Storage access tokens are requested.
+const azureStorageResource = "https://storage.azure.com/"
+
+// azureLoginEndpoint is the Microsoft identity platform endpoint from which service principal tokens are requested.
+var azureLoginEndpoint = "https://login.microsoftonline.com"
+
+// azureManagedIdentityEndpoint is the instance metadata service endpoint from which managed identity tokens are
+// requested.
+var azureManagedIdentityEndpoint = "http://169.254.169.254/metadata/identity/oauth2/token"
+
 // AzureBlobBackend is an implementation of the interfaces.TerraformWorkspace interface that uses Azure Blob Storage as the backend.
 type AzureBlobBackend struct {
 	// config is the configuration for the Azure Blob Storage backend.
@@ -49,8 +62,8 @@ func (b *AzureBlobBackend) DownloadWorkspaceState(ctx context.Context, workspace
 
 	for workspaceName := range workspaceToDirectory {
 		err := b.getWorkspaceStateByTestingAllAzureCredentials(ctx, workspaceName)
-		if err == nil {
-			break
+		if err != nil {
+			return fmt.Errorf("[download_workspace_state]%w", err)
 		}
 	}
 
@@ -58,57 +71,151 @@ func (b *AzureBlobBackend) DownloadWorkspaceState(ctx context.Context, workspace
 	return nil
 }
 
-// getWorkspaceStateByTestingAllAzureCredentials downloads the state file for the given workspace from the Azure Blob Storage backend.
+// getWorkspaceStateByTestingAllAzureCredentials downloads the state file for the given workspace from the Azure Blob
+// Storage backend with the first division credential granted access to it, returning the error of every credential
+// when none is.
 func (b *AzureBlobBackend) getWorkspaceStateByTestingAllAzureCredentials(ctx context.Context, workspaceName string) error {
-	for _, credential := range b.config.DivisionCloudCredentials {
-		serviceURL, err := b.configureAzureBlobURL(ctx, credential, b.workspaceToBackendDetails[workspaceName].(AzureBackendBlock))
+	azureBackendDetails := b.workspaceToBackendDetails[workspaceName].(AzureBackendBlock)
+	stateFileName := fmt.Sprintf("%v.json", workspaceName)
+	fileOutPath := fmt.Sprintf("state_files/%v", stateFileName)
+
+	credentialErrors := make([]string, 0)
+	for division, credential := range b.config.DivisionCloudCredentials {
+		serviceURL, err := b.configureAzureBlobURL(ctx, credential, azureBackendDetails)
 		if err != nil {
+			credentialErrors = append(credentialErrors, fmt.Sprintf("%v: %v", division, err))
 			continue
 		}
 
-		stateFileName := fmt.Sprintf("%v.json", workspaceName)
-
-		fileOutPath := fmt.Sprintf("state_files/%v", stateFileName)
-
 		outFile, err := os.Create(fileOutPath)
 		if err != nil {
-			continue
+			return fmt.Errorf("[get_workspace_state][os.Create]%w", err)
 		}
 
-		azureBackendDetails := b.workspaceToBackendDetails[workspaceName].(AzureBackendBlock)
 		blobURL := serviceURL.NewContainerURL(azureBackendDetails.ContainerName).NewBlobURL(stateFileName)
-
 		err = azblob.DownloadBlobToFile(ctx, blobURL, 0, azblob.CountToEnd, outFile, azblob.DownloadFromBlobOptions{})
+		outFile.Close()
 		if err != nil {
-			outFile.Close()
+			credentialErrors = append(credentialErrors, fmt.Sprintf("%v: %v", division, err))
 			continue
 		}
-
-		outFile.Close()
+		return nil
 	}
 
-	return nil
+	return fmt.Errorf(
+		"[get_workspace_state][unable to download the state of workspace %v from storage account %v with any division credential][%v]",
+		workspaceName, azureBackendDetails.StorageAccountName, strings.Join(credentialErrors, "; "),
+	)
 }
 
 // AzureCredentials is a struct that holds the credentials for an Azure Blob Storage backend.
 type AzureCredentials struct {
 	AzureStorageAccountKey string `json:"azure_storage_account_key"`
+	ClientID               string `json:"client_id"`
+	ClientSecret           string `json:"client_secret"`
+	TenantID               string `json:"tenant_id"`
 }
 
-// configureAzureBlobURL configures the Azure Blob Storage URL.
+// configureAzureBlobURL configures the Azure Blob Storage URL, authenticated with the storage account key of the
+// credential when it has one, otherwise with an Azure AD access token from azureStorageToken.
 func (b *AzureBlobBackend) configureAzureBlobURL(ctx context.Context, credential terraformValueObjects.Credential, backendAzure AzureBackendBlock) (azblob.ServiceURL, error) {
-	azureCredentials := new(AzureCredentials)
+	azureCredentials := AzureCredentials{}
 	err := json.Unmarshal([]byte(credential), &azureCredentials)
 	if err != nil {
-		return azblob.ServiceURL{}, err
+		return azblob.ServiceURL{}, fmt.Errorf("[configure_azure_blob_url][json.Unmarshal]%w", err)
 	}
 
-	sharedCredential, err := azblob.NewSharedKeyCredential(backendAzure.StorageAccountName, azureCredentials.AzureStorageAccountKey)
-	if err != nil {
-		return azblob.ServiceURL{}, err
+	var blobCredential azblob.Credential
+	if azureCredentials.AzureStorageAccountKey != "" {
+		blobCredential, err = azblob.NewSharedKeyCredential(backendAzure.StorageAccountName, azureCredentials.AzureStorageAccountKey)
+		if err != nil {
+			return azblob.ServiceURL{}, fmt.Errorf("[configure_azure_blob_url][azblob.NewSharedKeyCredential]%w", err)
+		}
+	} else {
+		token, err := azureStorageToken(ctx, azureCredentials, credential.IsAmbient())
+		if err != nil {
+			return azblob.ServiceURL{}, fmt.Errorf("[configure_azure_blob_url]%w", err)
+		}
+		blobCredential = azblob.NewTokenCredential(token, nil)
 	}
 
-	p := azblob.NewPipeline(sharedCredential, azblob.PipelineOptions{})
+	p := azblob.NewPipeline(blobCredential, azblob.PipelineOptions{})
 	URL, _ := url.Parse(fmt.Sprintf("https://%s.blob.core.windows.net", backendAzure.StorageAccountName))
 	return azblob.NewServiceURL(*URL, p), nil
 }
+
+// azureStorageToken returns an Azure Storage access token from the first configured source, in the order of the Azure
+// SDK's DefaultAzureCredential: the service principal of the credential, or else of the AZURE_CLIENT_ID,
+// AZURE_CLIENT_SECRET and AZURE_TENANT_ID environment variables, then the workload identity federated through
+// AZURE_FEDERATED_TOKEN_FILE, then, for an ambient credential, the container's managed identity. An error is
+// returned when none is configured, rather than the state download failing silently.
+func azureStorageToken(ctx context.Context, azureCredentials AzureCredentials, ambient bool) (string, error) {
+	tokenURL := func(tenantID string) string {
+		return fmt.Sprintf("%v/%v/oauth2/v2.0/token", azureLoginEndpoint, tenantID)
+	}
+	form := url.Values{"grant_type": {"client_credentials"}, "scope": {azureStorageResource + ".default"}}
+
+	var request *http.Request
+	var err error
+	switch {
+	case azureCredentials.ClientID != "" && azureCredentials.ClientSecret != "" && azureCredentials.TenantID != "":
+		form.Set("client_id", azureCredentials.ClientID)
+		form.Set("client_secret", azureCredentials.ClientSecret)
+		request, err = http.NewRequestWithContext(ctx, http.MethodPost, tokenURL(azureCredentials.TenantID), strings.NewReader(form.Encode()))
+	case os.Getenv("AZURE_CLIENT_ID") != "" && os.Getenv("AZURE_CLIENT_SECRET") != "" && os.Getenv("AZURE_TENANT_ID") != "":
+		form.Set("client_id", os.Getenv("AZURE_CLIENT_ID"))
+		form.Set("client_secret", os.Getenv("AZURE_CLIENT_SECRET"))
+		request, err = http.NewRequestWithContext(ctx, http.MethodPost, tokenURL(os.Getenv("AZURE_TENANT_ID")), strings.NewReader(form.Encode()))
+	case os.Getenv("AZURE_FEDERATED_TOKEN_FILE") != "" && os.Getenv("AZURE_CLIENT_ID") != "" && os.Getenv("AZURE_TENANT_ID") != "":
+		assertion, readErr := os.ReadFile(os.Getenv("AZURE_FEDERATED_TOKEN_FILE"))
+		if readErr != nil {
+			return "", fmt.Errorf("[azure_storage_token][os.ReadFile]%w", readErr)
+		}
+		form.Set("client_id", os.Getenv("AZURE_CLIENT_ID"))
+		form.Set("client_assertion_type", "urn:ietf:params:oauth:client-assertion-type:jwt-bearer")
+		form.Set("client_assertion", strings.TrimSpace(string(assertion)))
+		request, err = http.NewRequestWithContext(ctx, http.MethodPost, tokenURL(os.Getenv("AZURE_TENANT_ID")), strings.NewReader(form.Encode()))
+	case ambient:
+		query := url.Values{"api-version": {"2018-02-01"}, "resource": {azureStorageResource}}
+		if azureCredentials.ClientID != "" {
+			query.Set("client_id", azureCredentials.ClientID)
+		}
+		request, err = http.NewRequestWithContext(ctx, http.MethodGet, azureManagedIdentityEndpoint+"?"+query.Encode(), nil)
+		if err == nil {
+			request.Header.Set("Metadata", "true")
+		}
+	default:
+		return "", fmt.Errorf(
+			"[azure_storage_token][no storage account key, service principal, workload identity or managed identity is configured]",
+		)
+	}
+	if err != nil {
+		return "", fmt.Errorf("[azure_storage_token][http.NewRequestWithContext]%w", err)
+	}
+	if request.Method == http.MethodPost {
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("[azure_storage_token][http.Do]%w", err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return "", fmt.Errorf("[azure_storage_token][io.ReadAll]%w", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("[azure_storage_token][unexpected status code %d]%s", response.StatusCode, string(body))
+	}
+
+	token := struct {
+		AccessToken string `json:"access_token"`
+	}{}
+	err = json.Unmarshal(body, &token)
+	if err != nil {
+		return "", fmt.Errorf("[azure_storage_token][json.Unmarshal]%w", err)
+	}
+	return token.AccessToken, nil
+}
@@ -0,0 +1,67 @@
+package terraformWorkspace
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAzureStorageToken(t *testing.T) {
+	// Given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/my-tenant/oauth2/v2.0/token":
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "https://storage.azure.com/.default", r.PostForm.Get("scope"))
+			if r.PostForm.Get("client_assertion") == "federated-token" {
+				_, _ = w.Write([]byte(`{"access_token": "workload-token"}`))
+				return
+			}
+			assert.Equal(t, "my-secret", r.PostForm.Get("client_secret"))
+			_, _ = w.Write([]byte(`{"access_token": "principal-token"}`))
+		case r.Header.Get("Metadata") == "true":
+			assert.Equal(t, "https://storage.azure.com/", r.URL.Query().Get("resource"))
+			_, _ = w.Write([]byte(`{"access_token": "identity-token"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	originalLoginEndpoint, originalManagedIdentityEndpoint := azureLoginEndpoint, azureManagedIdentityEndpoint
+	azureLoginEndpoint, azureManagedIdentityEndpoint = server.URL, server.URL+"/metadata/identity/oauth2/token"
+	defer func() {
+		azureLoginEndpoint, azureManagedIdentityEndpoint = originalLoginEndpoint, originalManagedIdentityEndpoint
+	}()
+
+	for _, variable := range []string{"AZURE_CLIENT_ID", "AZURE_CLIENT_SECRET", "AZURE_TENANT_ID", "AZURE_FEDERATED_TOKEN_FILE"} {
+		t.Setenv(variable, "")
+	}
+
+	// When
+	_, unconfiguredErr := azureStorageToken(context.Background(), AzureCredentials{}, false)
+	principalToken, principalErr := azureStorageToken(context.Background(), AzureCredentials{ClientID: "id", ClientSecret: "my-secret", TenantID: "my-tenant"}, false)
+	identityToken, identityErr := azureStorageToken(context.Background(), AzureCredentials{}, true)
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("federated-token\n"), 0400))
+	t.Setenv("AZURE_CLIENT_ID", "id")
+	t.Setenv("AZURE_TENANT_ID", "my-tenant")
+	t.Setenv("AZURE_FEDERATED_TOKEN_FILE", tokenFile)
+	workloadToken, workloadErr := azureStorageToken(context.Background(), AzureCredentials{}, true)
+
+	// Then
+	assert.ErrorContains(t, unconfiguredErr, "no storage account key, service principal, workload identity or managed identity is configured")
+	require.NoError(t, principalErr)
+	assert.Equal(t, "principal-token", principalToken)
+	require.NoError(t, identityErr)
+	assert.Equal(t, "identity-token", identityToken)
+	require.NoError(t, workloadErr)
+	assert.Equal(t, "workload-token", workloadToken)
+}
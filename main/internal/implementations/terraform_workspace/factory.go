@@ -2,10 +2,6 @@ package terraformWorkspace
 
 import (
 	"context"
-	"fmt"
-
-	"github.com/kelseyhightower/envconfig"
-	log "github.com/sirupsen/logrus"
 
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
 )
@@ -16,25 +12,18 @@ type Factory struct {
 
 // Instantiate returns an implementation of the interfaces.TerraformWorkspace interface depending on the passed
 // environment specification.
-func (f *Factory) Instantiate(ctx context.Context, environment string, dragonDrop interfaces.DragonDrop, config TerraformCloudConfig) (interfaces.TerraformWorkspace, error) {
+func (f *Factory) Instantiate(ctx context.Context, environment string, dragonDrop interfaces.DragonDrop, config TerraformCloudConfig, backendConfig ContainerBackendConfig) (interfaces.TerraformWorkspace, error) {
 	switch environment {
 	case "isolated":
 		return new(IsolatedTerraformWorkspace), nil
 	default:
-		return f.bootstrappedTerraformWorkspace(ctx, dragonDrop, config)
+		return f.bootstrappedTerraformWorkspace(ctx, dragonDrop, config, backendConfig)
 	}
 }
 
-// bootstrappedTerraformWorkspace creates a complete implementation of the interfaces.TerraformWorkspace interface with
-// configuration specified via environment variables.
-func (f *Factory) bootstrappedTerraformWorkspace(ctx context.Context, dragonDrop interfaces.DragonDrop, config TerraformCloudConfig) (interfaces.TerraformWorkspace, error) {
-	var backendConfig ContainerBackendConfig
-	err := envconfig.Process("CLOUDCONCIERGE", &backendConfig)
-	if err != nil {
-		log.Errorf("[cannot create terraform workspace config]%s", err.Error())
-		return nil, fmt.Errorf("[cannot create terraform workspace config]%w", err)
-	}
-
+// bootstrappedTerraformWorkspace creates a complete implementation of the interfaces.TerraformWorkspace interface for
+// the configured state backend.
+func (f *Factory) bootstrappedTerraformWorkspace(ctx context.Context, dragonDrop interfaces.DragonDrop, config TerraformCloudConfig, backendConfig ContainerBackendConfig) (interfaces.TerraformWorkspace, error) {
 	switch config.StateBackend {
 	case "s3":
 		return NewS3Backend(ctx, backendConfig, dragonDrop), nil
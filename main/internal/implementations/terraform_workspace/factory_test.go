@@ -18,7 +18,7 @@ func TestCreateIsolatedTerraformWorkspace(t *testing.T) {
 	dragonDrop := new(interfaces.DragonDropMock)
 
 	// When
-	terraformWorkspace, err := terraformWorkspaceFactory.Instantiate(ctx, terraformWorkspaceProvider, dragonDrop, config)
+	terraformWorkspace, err := terraformWorkspaceFactory.Instantiate(ctx, terraformWorkspaceProvider, dragonDrop, config, ContainerBackendConfig{})
 
 	// Then
 	assert.Nil(t, err)
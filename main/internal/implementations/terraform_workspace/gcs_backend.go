@@ -69,7 +69,12 @@ func (b *GCSBackend) getWorkspaceStateByTestingAllGoogleCredentials(ctx context.
 			continue
 		}
 
-		client, err := storage.NewClient(ctx, option.WithCredentialsJSON([]byte(credential)))
+		clientOptions := []option.ClientOption{option.WithCredentialsJSON([]byte(credential))}
+		if credential.IsAmbient() {
+			clientOptions = []option.ClientOption{}
+		}
+
+		client, err := storage.NewClient(ctx, clientOptions...)
 		if err != nil {
 			continue
 		}
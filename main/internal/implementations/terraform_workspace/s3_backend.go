@@ -2,7 +2,6 @@ package terraformWorkspace
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 
@@ -11,6 +10,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/awscredentials"
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
 )
@@ -69,22 +69,19 @@ func (s *S3Backend) DownloadWorkspaceState(ctx context.Context, workspaceToDirec
 	return nil
 }
 
-// AWSCredentials is a struct that contains the AWS credentials for a specific workspace.
-type AWSCredentials struct {
-	AWSAccessKeyID     string `json:"awsAccessKeyID"`
-	AWSSecretKeyAccess string `json:"awsSecretAccessKey"`
-	Token              string `json:"token"`
-}
-
 // configureS3Client configures the S3 client to use the correct credentials that have read-access for the specified storage bucket.
 func (s *S3Backend) configureS3Client(credential terraformValueObjects.Credential) error {
-	awsCredentials := new(AWSCredentials)
-	err := json.Unmarshal([]byte(credential), &awsCredentials)
+	awsCredential, err := awscredentials.Parse(credential)
+	if err != nil {
+		return err
+	}
+
+	value, err := awsCredential.Resolve()
 	if err != nil {
 		return err
 	}
 
-	staticCredentials := credentials.NewStaticCredentials(awsCredentials.AWSAccessKeyID, awsCredentials.AWSSecretKeyAccess, awsCredentials.Token)
+	staticCredentials := credentials.NewStaticCredentials(value.AccessKeyID, value.SecretAccessKey, value.SessionToken)
 	_, err = staticCredentials.Get()
 	if err != nil {
 		return err
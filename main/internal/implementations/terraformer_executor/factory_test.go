@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/hclcreate"
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
 	terraformerCli "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraformer_executor/terraformer_cli"
@@ -24,7 +25,7 @@ func TestCreateIsolatedTerraformerExecutor(t *testing.T) {
 	divisionToProvider := make(map[terraformValueObjects.Division]terraformValueObjects.Provider)
 
 	// When
-	terraformerExecutor, err := terraformerExecutorFactory.Instantiate(ctx, terraformerExecutorProvider, dragonDrop, divisionToProvider, hclConfig, executorConfig, cliConfig)
+	terraformerExecutor, err := terraformerExecutorFactory.Instantiate(ctx, terraformerExecutorProvider, dragonDrop, divisionToProvider, hclConfig, executorConfig, cliConfig, new(interfaces.RunStateStoreMock), artifacts.NewMemoryStore(true))
 
 	// Then
 	assert.Nil(t, err)
@@ -0,0 +1,136 @@
+package terraformerCLI
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+// writeNativeInventory writes inventoried resources to path as the terraform.tfstate, resources.tf and provider.tf
+// files that terraformer would otherwise generate.
+func writeNativeInventory(path terraformValueObjects.Path, region string, resources []nativeResource) error {
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].Type != resources[j].Type {
+			return resources[i].Type < resources[j].Type
+		}
+		return resources[i].Name < resources[j].Name
+	})
+
+	err := os.MkdirAll(string(path), 0755)
+	if err != nil {
+		return fmt.Errorf("[write_native_inventory][error creating %v]%w", path, err)
+	}
+
+	stateBytes, err := nativeResourcesToState(resources)
+	if err != nil {
+		return fmt.Errorf("[write_native_inventory]%w", err)
+	}
+
+	files := map[string][]byte{
+		"terraform.tfstate": stateBytes,
+		"resources.tf":      nativeResourcesToHCL(resources),
+		"provider.tf":       nativeProviderHCL(region),
+	}
+	for fileName, content := range files {
+		err = os.WriteFile(fmt.Sprintf("%v%v", path, fileName), content, 0644)
+		if err != nil {
+			return fmt.Errorf("[write_native_inventory][error writing %v]%w", fileName, err)
+		}
+	}
+
+	return nil
+}
+
+// nativeResourcesToState returns a version 4 state file, with flattened attributes as generated by terraformer,
+// containing the inventoried resources.
+func nativeResourcesToState(resources []nativeResource) ([]byte, error) {
+	stateResources := make([]map[string]interface{}, 0)
+	for _, resource := range resources {
+		stateResources = append(stateResources, map[string]interface{}{
+			"mode":     "managed",
+			"type":     resource.Type,
+			"name":     resource.Name,
+			"provider": `provider["registry.terraform.io/hashicorp/aws"]`,
+			"instances": []map[string]interface{}{
+				{
+					"schema_version":  0,
+					"attributes_flat": resource.attributesFlat(),
+				},
+			},
+		})
+	}
+
+	stateBytes, err := json.MarshalIndent(map[string]interface{}{
+		"version":           4,
+		"terraform_version": nativeStateTerraformVersion,
+		"serial":            1,
+		"lineage":           "",
+		"outputs":           map[string]interface{}{},
+		"resources":         stateResources,
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("[native_resources_to_state][json.MarshalIndent]%w", err)
+	}
+
+	return stateBytes, nil
+}
+
+// nativeResourcesToHCL returns a resource block per inventoried resource.
+func nativeResourcesToHCL(resources []nativeResource) []byte {
+	file := hclwrite.NewEmptyFile()
+	body := file.Body()
+
+	for i, resource := range resources {
+		if i > 0 {
+			body.AppendNewline()
+		}
+
+		resourceBody := body.AppendNewBlock("resource", []string{resource.Type, resource.Name}).Body()
+
+		attributes := make([]string, 0)
+		for attribute := range resource.Attributes {
+			if attribute == "arn" {
+				continue
+			}
+			attributes = append(attributes, attribute)
+		}
+		sort.Strings(attributes)
+
+		for _, attribute := range attributes {
+			resourceBody.SetAttributeValue(attribute, resource.Attributes[attribute])
+		}
+
+		if len(resource.Tags) > 0 {
+			tags := make(map[string]cty.Value)
+			for key, value := range resource.Tags {
+				tags[key] = cty.StringVal(value)
+			}
+			resourceBody.SetAttributeValue("tags", cty.MapVal(tags))
+		}
+	}
+
+	return file.Bytes()
+}
+
+// nativeProviderHCL returns the provider configuration of the inventoried region.
+func nativeProviderHCL(region string) []byte {
+	file := hclwrite.NewEmptyFile()
+	body := file.Body()
+
+	providerBody := body.AppendNewBlock("provider", []string{"aws"}).Body()
+	providerBody.SetAttributeValue("region", cty.StringVal(region))
+	body.AppendNewline()
+
+	requiredProvidersBody := body.AppendNewBlock("terraform", nil).Body().AppendNewBlock("required_providers", nil).Body()
+	requiredProvidersBody.SetAttributeValue("aws", cty.ObjectVal(map[string]cty.Value{
+		"source": cty.StringVal("hashicorp/aws"),
+	}))
+
+	return file.Bytes()
+}
@@ -0,0 +1,142 @@
+package terraformerCLI
+
+import terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+
+// awsNativeResourceType describes how resources of a Cloud Control API resource type are synthesized as
+// Terraform resources.
+type awsNativeResourceType struct {
+	// cloudControlType is the CloudFormation resource type name listed through the Cloud Control API. Its primary
+	// identifier matches the id of the Terraform resource.
+	cloudControlType string
+
+	// attributes maps top-level Cloud Control resource properties to the Terraform attribute they populate.
+	// The "Arn" and "Tags" properties are mapped for every resource type.
+	attributes map[string]string
+}
+
+// awsNativeResourceTypes are the Terraform resource types inventoried through the Cloud Control API.
+var awsNativeResourceTypes = map[terraformValueObjects.ResourceName]awsNativeResourceType{
+	"aws_cloudwatch_log_group": {
+		cloudControlType: "AWS::Logs::LogGroup",
+		attributes: map[string]string{
+			"LogGroupName":    "name",
+			"RetentionInDays": "retention_in_days",
+			"KmsKeyId":        "kms_key_id",
+		},
+	},
+	"aws_db_instance": {
+		cloudControlType: "AWS::RDS::DBInstance",
+		attributes: map[string]string{
+			"DBInstanceIdentifier": "identifier",
+			"DBInstanceClass":      "instance_class",
+			"Engine":               "engine",
+			"EngineVersion":        "engine_version",
+			"AllocatedStorage":     "allocated_storage",
+			"StorageType":          "storage_type",
+			"MultiAZ":              "multi_az",
+			"PubliclyAccessible":   "publicly_accessible",
+		},
+	},
+	"aws_dynamodb_table": {
+		cloudControlType: "AWS::DynamoDB::Table",
+		attributes: map[string]string{
+			"TableName":   "name",
+			"BillingMode": "billing_mode",
+		},
+	},
+	"aws_ecr_repository": {
+		cloudControlType: "AWS::ECR::Repository",
+		attributes: map[string]string{
+			"RepositoryName":     "name",
+			"ImageTagMutability": "image_tag_mutability",
+		},
+	},
+	"aws_iam_role": {
+		cloudControlType: "AWS::IAM::Role",
+		attributes: map[string]string{
+			"RoleName":                 "name",
+			"Path":                     "path",
+			"Description":              "description",
+			"MaxSessionDuration":       "max_session_duration",
+			"AssumeRolePolicyDocument": "assume_role_policy",
+		},
+	},
+	"aws_instance": {
+		cloudControlType: "AWS::EC2::Instance",
+		attributes: map[string]string{
+			"ImageId":      "ami",
+			"InstanceType": "instance_type",
+			"SubnetId":     "subnet_id",
+			"KeyName":      "key_name",
+		},
+	},
+	"aws_kms_key": {
+		cloudControlType: "AWS::KMS::Key",
+		attributes: map[string]string{
+			"Description":       "description",
+			"KeyUsage":          "key_usage",
+			"EnableKeyRotation": "enable_key_rotation",
+		},
+	},
+	"aws_lambda_function": {
+		cloudControlType: "AWS::Lambda::Function",
+		attributes: map[string]string{
+			"FunctionName": "function_name",
+			"Runtime":      "runtime",
+			"Handler":      "handler",
+			"Role":         "role",
+			"MemorySize":   "memory_size",
+			"Timeout":      "timeout",
+		},
+	},
+	"aws_s3_bucket": {
+		cloudControlType: "AWS::S3::Bucket",
+		attributes: map[string]string{
+			"BucketName": "bucket",
+		},
+	},
+	"aws_security_group": {
+		cloudControlType: "AWS::EC2::SecurityGroup",
+		attributes: map[string]string{
+			"GroupName":        "name",
+			"GroupDescription": "description",
+			"VpcId":            "vpc_id",
+		},
+	},
+	"aws_sns_topic": {
+		cloudControlType: "AWS::SNS::Topic",
+		attributes: map[string]string{
+			"TopicName":   "name",
+			"DisplayName": "display_name",
+			"FifoTopic":   "fifo_topic",
+		},
+	},
+	"aws_sqs_queue": {
+		cloudControlType: "AWS::SQS::Queue",
+		attributes: map[string]string{
+			"QueueName":              "name",
+			"DelaySeconds":           "delay_seconds",
+			"MessageRetentionPeriod": "message_retention_seconds",
+			"VisibilityTimeout":      "visibility_timeout_seconds",
+			"FifoQueue":              "fifo_queue",
+		},
+	},
+	"aws_subnet": {
+		cloudControlType: "AWS::EC2::Subnet",
+		attributes: map[string]string{
+			"VpcId":               "vpc_id",
+			"CidrBlock":           "cidr_block",
+			"AvailabilityZone":    "availability_zone",
+			"MapPublicIpOnLaunch": "map_public_ip_on_launch",
+		},
+	},
+	"aws_vpc": {
+		cloudControlType: "AWS::EC2::VPC",
+		attributes: map[string]string{
+			"CidrBlock":          "cidr_block",
+			"EnableDnsHostnames": "enable_dns_hostnames",
+			"EnableDnsSupport":   "enable_dns_support",
+			"InstanceTenancy":    "instance_tenancy",
+		},
+	},
+}
@@ -0,0 +1,339 @@
+package terraformerCLI
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudcontrolapi"
+	"github.com/aws/aws-sdk-go/service/cloudcontrolapi/cloudcontrolapiiface"
+	log "github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/awscredentials"
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+// nativeStateTerraformVersion is the terraform version recorded within synthesized state files.
+const nativeStateTerraformVersion = "1.0.0"
+
+// invalidResourceNameCharacters matches the characters that terraformer replaces within generated resource names.
+var invalidResourceNameCharacters = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// newCloudControlClient returns the Cloud Control API client used with a division's resolved credentials.
+var newCloudControlClient = func(value credentials.Value, region string) (cloudcontrolapiiface.CloudControlApiAPI, error) {
+	newSession, err := session.NewSession(aws.NewConfig().WithRegion(region).WithCredentials(credentials.NewStaticCredentialsFromCreds(value)))
+	if err != nil {
+		return nil, err
+	}
+	return cloudcontrolapi.New(newSession), nil
+}
+
+// AWSNativeScanner implements the Scanner interface for AWS accounts by inventorying resources directly through the
+// AWS Cloud Control API, rather than with terraformer. The output is a terraformer-compatible terraform.tfstate and
+// resources.tf for the supported resource types.
+type AWSNativeScanner struct {
+	// Config is the needed configuration of a mapping between Division name and the corresponding
+	// Credential needed to access that environment.
+	config map[terraformValueObjects.Division]terraformValueObjects.Credential
+
+	// cliConfig contains the resource inclusion and exclusion rules applied to the inventory.
+	cliConfig Config
+
+	// CloudRegions represents the list of cloud regions that will be considered for inclusion in the inventory.
+	CloudRegions []terraformValueObjects.CloudRegion `required:"true"`
+}
+
+// NewAWSNativeScanner creates and returns a new instance of AWSNativeScanner.
+func NewAWSNativeScanner(config map[terraformValueObjects.Division]terraformValueObjects.Credential, cliConfig Config, cloudRegions []terraformValueObjects.CloudRegion) (Scanner, error) {
+	return &AWSNativeScanner{
+		CloudRegions: cloudRegions,
+		config:       config,
+		cliConfig:    cliConfig,
+	}, nil
+}
+
+// nativeResource is a single resource inventoried through the Cloud Control API.
+type nativeResource struct {
+	// Type is the Terraform resource type, e.g. aws_s3_bucket.
+	Type string
+
+	// Name is the terraformer-style resource name, e.g. tfer--my-bucket.
+	Name string
+
+	// ID is the Cloud Control primary identifier of the resource, which is also its Terraform id.
+	ID string
+
+	// Attributes are the Terraform attributes of the resource, excluding tags.
+	Attributes map[string]cty.Value
+
+	// Tags are the tags of the resource.
+	Tags map[string]string
+}
+
+// ScanAll wraps Scan to scan each division for the provider.
+func (nativeScanner *AWSNativeScanner) ScanAll(ctx context.Context, options ...string) (*MultiScanResult, error) {
+	fmt.Println("Inventorying all specified AWS divisions through the Cloud Control API.")
+	scanMap := make(map[terraformValueObjects.Division]terraformValueObjects.Path)
+
+	for div, credential := range nativeScanner.config {
+		path, err := nativeScanner.Scan(ctx, div, credential)
+		if err != nil {
+			return nil, fmt.Errorf("[ScanAll] Error in nativeScanner.Scan: %v", err)
+		}
+		scanMap[div] = path
+	}
+
+	return &MultiScanResult{scanMap}, nil
+}
+
+// Scan inventories a given division's AWS account within each configured region through the Cloud Control API, and
+// writes the inventory in the same location and format as terraformer.
+func (nativeScanner *AWSNativeScanner) Scan(ctx context.Context, account terraformValueObjects.Division, credential terraformValueObjects.Credential, options ...string) (terraformValueObjects.Path, error) {
+	awsCredential, err := awscredentials.Parse(credential)
+	if err != nil {
+		return "", fmt.Errorf("[aws_native_scanner][scan]%w", err)
+	}
+
+	value, err := awsCredential.Resolve()
+	if err != nil {
+		return "", fmt.Errorf("[aws_native_scanner][scan]%w", err)
+	}
+
+	regions := getAllValidRegions(nativeScanner.CloudRegions, terraformValueObjects.AwsRegions, defaultAwsRegions)
+	resources := make([]nativeResource, 0)
+	for _, region := range regions {
+		client, err := newCloudControlClient(value, region)
+		if err != nil {
+			return "", fmt.Errorf("[aws_native_scanner][scan][error creating cloud control client for %v]%w", region, err)
+		}
+
+		regionResources, err := listNativeResources(client, nativeScanner.resourceTypes())
+		if err != nil {
+			return "", fmt.Errorf("[aws_native_scanner][scan][region %v]%w", region, err)
+		}
+		resources = mergeRegionResources(resources, regionResources, region)
+	}
+	log.Infof("Inventoried %v resources within %v through the Cloud Control API", len(resources), account)
+	nativeScanner.cliConfig.ScanProgress.recordScanScope(
+		fmt.Sprintf("aws-%v", account), regions, resourceTypeNames(nativeScanner.resourceTypes()),
+	)
+
+	path := terraformValueObjects.Path(fmt.Sprintf("./aws-%v/", account))
+	err = writeNativeInventory(path, regions[0], resources)
+	if err != nil {
+		return "", fmt.Errorf("[aws_native_scanner][scan]%w", err)
+	}
+
+	tfrCLI := &terraformerCLI{config: nativeScanner.cliConfig}
+	if tfrCLI.hasResourceExclusions() {
+		err = pruneImportedResources(path, func(resource importedResource) bool {
+			return tfrCLI.isResourceExcluded("tags", resource)
+		})
+		if err != nil {
+			return "", fmt.Errorf("[aws_native_scanner][scan][error pruning excluded resources]%w", err)
+		}
+	}
+
+	return path, nil
+}
+
+// mergeRegionResources appends the resources inventoried within region to resources. Global resources, such as IAM
+// roles or S3 buckets, are listed within every region and are kept once, identified by their ARN when known. Regional
+// resources sharing a name with a resource of another region, e.g. Lambda functions, have the region appended to
+// their resource name.
+func mergeRegionResources(resources []nativeResource, regionResources []nativeResource, region string) []nativeResource {
+	seen := map[string]bool{}
+	names := map[string]bool{}
+	for _, resource := range resources {
+		seen[resource.Type+"/"+resource.uniqueKey()] = true
+		names[resource.Type+"."+resource.Name] = true
+	}
+
+	for _, resource := range regionResources {
+		if seen[resource.Type+"/"+resource.uniqueKey()] {
+			continue
+		}
+		seen[resource.Type+"/"+resource.uniqueKey()] = true
+
+		if names[resource.Type+"."+resource.Name] {
+			resource.Name = fmt.Sprintf("%v-%v", resource.Name, region)
+		}
+		names[resource.Type+"."+resource.Name] = true
+		resources = append(resources, resource)
+	}
+	return resources
+}
+
+// uniqueKey returns the ARN of the resource when known, which is unique across regions, otherwise its identifier.
+func (r nativeResource) uniqueKey() string {
+	arn, ok := r.Attributes["arn"]
+	if ok && arn.Type() == cty.String && arn.IsKnown() && !arn.IsNull() && arn.AsString() != "" {
+		return arn.AsString()
+	}
+	return r.ID
+}
+
+// resourceTypes returns the Terraform resource types to inventory, in accordance with the resource black and
+// white lists.
+func (nativeScanner *AWSNativeScanner) resourceTypes() []terraformValueObjects.ResourceName {
+	included := make(map[terraformValueObjects.ResourceName]bool)
+	for _, resourceType := range nativeScanner.cliConfig.ResourcesWhiteList {
+		included[resourceType] = true
+	}
+
+	excluded := make(map[terraformValueObjects.ResourceName]bool)
+	for _, resourceType := range nativeScanner.cliConfig.ResourcesBlackList {
+		excluded[resourceType] = true
+	}
+
+	resourceTypes := make([]terraformValueObjects.ResourceName, 0)
+	for resourceType := range awsNativeResourceTypes {
+		if excluded[resourceType] || len(included) > 0 && !included[resourceType] {
+			continue
+		}
+		resourceTypes = append(resourceTypes, resourceType)
+	}
+	sort.Slice(resourceTypes, func(i, j int) bool { return resourceTypes[i] < resourceTypes[j] })
+
+	return resourceTypes
+}
+
+// listNativeResources lists every resource of the passed resource types, reading each resource's properties.
+func listNativeResources(client cloudcontrolapiiface.CloudControlApiAPI, resourceTypes []terraformValueObjects.ResourceName) ([]nativeResource, error) {
+	resources := make([]nativeResource, 0)
+
+	for _, resourceType := range resourceTypes {
+		nativeType := awsNativeResourceTypes[resourceType]
+
+		identifiers := make([]string, 0)
+		err := client.ListResourcesPages(&cloudcontrolapi.ListResourcesInput{
+			TypeName: aws.String(nativeType.cloudControlType),
+		}, func(output *cloudcontrolapi.ListResourcesOutput, lastPage bool) bool {
+			for _, description := range output.ResourceDescriptions {
+				identifiers = append(identifiers, aws.StringValue(description.Identifier))
+			}
+			return true
+		})
+		if err != nil {
+			return nil, fmt.Errorf("[list_native_resources][error listing %v]%w", nativeType.cloudControlType, err)
+		}
+
+		for _, identifier := range identifiers {
+			output, err := client.GetResource(&cloudcontrolapi.GetResourceInput{
+				TypeName:   aws.String(nativeType.cloudControlType),
+				Identifier: aws.String(identifier),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("[list_native_resources][error reading %v %v]%w", nativeType.cloudControlType, identifier, err)
+			}
+
+			resource, err := newNativeResource(string(resourceType), identifier, aws.StringValue(output.ResourceDescription.Properties))
+			if err != nil {
+				return nil, fmt.Errorf("[list_native_resources]%w", err)
+			}
+			resources = append(resources, resource)
+		}
+	}
+
+	return resources, nil
+}
+
+// newNativeResource converts the json properties of a Cloud Control resource into a nativeResource.
+func newNativeResource(resourceType string, identifier string, propertiesJSON string) (nativeResource, error) {
+	properties := make(map[string]interface{})
+	if propertiesJSON != "" {
+		err := json.Unmarshal([]byte(propertiesJSON), &properties)
+		if err != nil {
+			return nativeResource{}, fmt.Errorf("[new_native_resource][error unmarshalling properties of %v]%w", identifier, err)
+		}
+	}
+
+	resource := nativeResource{
+		Type:       resourceType,
+		Name:       fmt.Sprintf("tfer--%v", invalidResourceNameCharacters.ReplaceAllString(identifier, "-")),
+		ID:         identifier,
+		Attributes: map[string]cty.Value{},
+		Tags:       map[string]string{},
+	}
+
+	attributes := map[string]string{"Arn": "arn"}
+	for property, attribute := range awsNativeResourceTypes[terraformValueObjects.ResourceName(resourceType)].attributes {
+		attributes[property] = attribute
+	}
+
+	for property, attribute := range attributes {
+		value, ok := properties[property]
+		if !ok {
+			continue
+		}
+
+		ctyValue, err := propertyToCtyValue(value)
+		if err != nil {
+			return nativeResource{}, fmt.Errorf("[new_native_resource][error converting %v of %v]%w", property, identifier, err)
+		}
+		resource.Attributes[attribute] = ctyValue
+	}
+
+	if tags, ok := properties["Tags"].([]interface{}); ok {
+		for _, tag := range tags {
+			tagMap, ok := tag.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			key, _ := tagMap["Key"].(string)
+			value, _ := tagMap["Value"].(string)
+			resource.Tags[key] = value
+		}
+	}
+
+	return resource, nil
+}
+
+// propertyToCtyValue converts a json-decoded property value into a cty value. Objects and lists, such as policy
+// documents, are encoded as json strings.
+func propertyToCtyValue(value interface{}) (cty.Value, error) {
+	switch typedValue := value.(type) {
+	case string:
+		return cty.StringVal(typedValue), nil
+	case float64:
+		return cty.NumberFloatVal(typedValue), nil
+	case bool:
+		return cty.BoolVal(typedValue), nil
+	default:
+		encoded, err := json.Marshal(typedValue)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		return cty.StringVal(string(encoded)), nil
+	}
+}
+
+// attributesFlat returns the resource's attributes in the flattened format of a terraformer state file.
+func (r nativeResource) attributesFlat() map[string]string {
+	attributesFlat := map[string]string{"id": r.ID}
+
+	for attribute, value := range r.Attributes {
+		switch value.Type() {
+		case cty.Number:
+			attributesFlat[attribute] = value.AsBigFloat().Text('f', -1)
+		case cty.Bool:
+			attributesFlat[attribute] = strconv.FormatBool(value.True())
+		default:
+			attributesFlat[attribute] = value.AsString()
+		}
+	}
+
+	attributesFlat["tags.%"] = strconv.Itoa(len(r.Tags))
+	for key, value := range r.Tags {
+		attributesFlat[fmt.Sprintf("tags.%v", key)] = value
+	}
+
+	return attributesFlat
+}
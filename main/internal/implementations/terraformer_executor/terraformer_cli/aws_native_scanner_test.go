@@ -0,0 +1,201 @@
+package terraformerCLI
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/service/cloudcontrolapi"
+	"github.com/aws/aws-sdk-go/service/cloudcontrolapi/cloudcontrolapiiface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+type mockCloudControlClient struct {
+	cloudcontrolapiiface.CloudControlApiAPI
+}
+
+func (m *mockCloudControlClient) ListResourcesPages(input *cloudcontrolapi.ListResourcesInput, fn func(*cloudcontrolapi.ListResourcesOutput, bool) bool) error {
+	if aws.StringValue(input.TypeName) != "AWS::S3::Bucket" {
+		fn(&cloudcontrolapi.ListResourcesOutput{}, true)
+		return nil
+	}
+
+	fn(&cloudcontrolapi.ListResourcesOutput{
+		ResourceDescriptions: []*cloudcontrolapi.ResourceDescription{{Identifier: aws.String("logs.example.com")}},
+	}, true)
+	return nil
+}
+
+func (m *mockCloudControlClient) GetResource(input *cloudcontrolapi.GetResourceInput) (*cloudcontrolapi.GetResourceOutput, error) {
+	return &cloudcontrolapi.GetResourceOutput{
+		TypeName: input.TypeName,
+		ResourceDescription: &cloudcontrolapi.ResourceDescription{
+			Identifier: input.Identifier,
+			Properties: aws.String(`{
+				"BucketName": "logs.example.com",
+				"Arn": "arn:aws:s3:::logs.example.com",
+				"Tags": [{"Key": "env", "Value": "prod"}, {"Key": "team", "Value": "platform"}]
+			}`),
+		},
+	}, nil
+}
+
+func TestAWSNativeScanner_Scan(t *testing.T) {
+	// Given
+	workingDirectory, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(workingDirectory) }()
+
+	originalNewCloudControlClient := newCloudControlClient
+	newCloudControlClient = func(value credentials.Value, region string) (cloudcontrolapiiface.CloudControlApiAPI, error) {
+		assert.Equal(t, "key", value.AccessKeyID)
+		assert.Equal(t, "us-east-2", region)
+		return &mockCloudControlClient{}, nil
+	}
+	defer func() { newCloudControlClient = originalNewCloudControlClient }()
+
+	scanner, err := NewAWSNativeScanner(nil, Config{}, []terraformValueObjects.CloudRegion{"us-east-2"})
+	require.NoError(t, err)
+	credential := terraformValueObjects.Credential(`{"awsAccessKeyID": "key", "awsSecretAccessKey": "secret"}`)
+
+	// When
+	path, err := scanner.Scan(context.Background(), "my-account", credential)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, terraformValueObjects.Path("./aws-my-account/"), path)
+
+	stateBytes, err := os.ReadFile(filepath.Join(string(path), "terraform.tfstate"))
+	require.NoError(t, err)
+	assert.Contains(t, string(stateBytes), `"name": "tfer--logs-example-com"`)
+	assert.Contains(t, string(stateBytes), `"arn": "arn:aws:s3:::logs.example.com"`)
+	assert.Contains(t, string(stateBytes), `"tags.env": "prod"`)
+
+	hclBytes, err := os.ReadFile(filepath.Join(string(path), "resources.tf"))
+	require.NoError(t, err)
+	assert.Equal(t, `resource "aws_s3_bucket" "tfer--logs-example-com" {
+  bucket = "logs.example.com"
+  tags = {
+    env  = "prod"
+    team = "platform"
+  }
+}
+`, string(hclBytes))
+
+	providerBytes, err := os.ReadFile(filepath.Join(string(path), "provider.tf"))
+	require.NoError(t, err)
+	assert.Contains(t, string(providerBytes), `region = "us-east-2"`)
+}
+
+func TestAWSNativeScanner_resourceTypes(t *testing.T) {
+	// Given
+	scanner := AWSNativeScanner{cliConfig: Config{
+		ResourcesWhiteList: terraformValueObjects.ResourceNameList{"aws_s3_bucket", "aws_vpc", "aws_not_inventoried"},
+		ResourcesBlackList: terraformValueObjects.ResourceNameList{"aws_vpc"},
+	}}
+
+	// When
+	resourceTypes := scanner.resourceTypes()
+
+	// Then
+	assert.Equal(t, []terraformValueObjects.ResourceName{"aws_s3_bucket"}, resourceTypes)
+}
+
+func Test_newNativeResource(t *testing.T) {
+	// Given
+	properties := `{
+		"RoleName": "deployer",
+		"MaxSessionDuration": 3600,
+		"AssumeRolePolicyDocument": {"Version": "2012-10-17"}
+	}`
+
+	// When
+	resource, err := newNativeResource("aws_iam_role", "deployer", properties)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"id":                   "deployer",
+		"name":                 "deployer",
+		"max_session_duration": "3600",
+		"assume_role_policy":   `{"Version":"2012-10-17"}`,
+		"tags.%":               "0",
+	}, resource.attributesFlat())
+}
+
+type regionalCloudControlClient struct {
+	cloudcontrolapiiface.CloudControlApiAPI
+	region string
+}
+
+func (m *regionalCloudControlClient) ListResourcesPages(input *cloudcontrolapi.ListResourcesInput, fn func(*cloudcontrolapi.ListResourcesOutput, bool) bool) error {
+	descriptions := []*cloudcontrolapi.ResourceDescription{}
+	switch aws.StringValue(input.TypeName) {
+	case "AWS::S3::Bucket", "AWS::Lambda::Function":
+		descriptions = append(descriptions, &cloudcontrolapi.ResourceDescription{Identifier: aws.String("worker")})
+	}
+	fn(&cloudcontrolapi.ListResourcesOutput{ResourceDescriptions: descriptions}, true)
+	return nil
+}
+
+func (m *regionalCloudControlClient) GetResource(input *cloudcontrolapi.GetResourceInput) (*cloudcontrolapi.GetResourceOutput, error) {
+	arn := "arn:aws:s3:::worker"
+	if aws.StringValue(input.TypeName) == "AWS::Lambda::Function" {
+		arn = "arn:aws:lambda:" + m.region + ":111111111111:function:worker"
+	}
+	return &cloudcontrolapi.GetResourceOutput{
+		TypeName: input.TypeName,
+		ResourceDescription: &cloudcontrolapi.ResourceDescription{
+			Identifier: input.Identifier,
+			Properties: aws.String(`{"Arn": "` + arn + `"}`),
+		},
+	}, nil
+}
+
+func TestAWSNativeScanner_Scan_EveryRegion(t *testing.T) {
+	// Given
+	workingDirectory, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(workingDirectory) }()
+
+	scannedRegions := []string{}
+	originalNewCloudControlClient := newCloudControlClient
+	newCloudControlClient = func(value credentials.Value, region string) (cloudcontrolapiiface.CloudControlApiAPI, error) {
+		scannedRegions = append(scannedRegions, region)
+		return &regionalCloudControlClient{region: region}, nil
+	}
+	defer func() { newCloudControlClient = originalNewCloudControlClient }()
+
+	progress := NewScanProgress(nil, 0)
+	config := Config{
+		ResourcesWhiteList: terraformValueObjects.ResourceNameList{"aws_s3_bucket", "aws_lambda_function"},
+		ScanProgress:       progress,
+	}
+	scanner, err := NewAWSNativeScanner(nil, config, []terraformValueObjects.CloudRegion{"us-east-1", "eu-west-1"})
+	require.NoError(t, err)
+	credential := terraformValueObjects.Credential(`{"awsAccessKeyID": "key", "awsSecretAccessKey": "secret"}`)
+
+	// When
+	path, err := scanner.Scan(context.Background(), "my-account", credential)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, []string{"us-east-1", "eu-west-1"}, scannedRegions)
+
+	stateBytes, err := os.ReadFile(filepath.Join(string(path), "terraform.tfstate"))
+	require.NoError(t, err)
+	assert.Contains(t, string(stateBytes), `"arn": "arn:aws:lambda:us-east-1:111111111111:function:worker"`)
+	assert.Contains(t, string(stateBytes), `"arn": "arn:aws:lambda:eu-west-1:111111111111:function:worker"`)
+	assert.Contains(t, string(stateBytes), `"name": "tfer--worker-eu-west-1"`)
+	assert.Equal(t, 1, strings.Count(string(stateBytes), `"arn": "arn:aws:s3:::worker"`))
+	assert.Equal(t, []string{"us-east-1", "eu-west-1"}, progress.ScanScopes()["aws-my-account"].Regions)
+}
@@ -1,10 +1,10 @@
 package terraformerCLI
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"os"
 
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/awscredentials"
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
 )
 
@@ -21,25 +21,44 @@ type AWSScanner struct {
 
 	// CloudRegions represents the list of cloud regions that will be considered for inclusion in the import statement.
 	CloudRegions []terraformValueObjects.CloudRegion `required:"true"`
+
+	// nativeDivisions are the divisions inventoried by nativeScanner rather than with terraformer.
+	nativeDivisions map[terraformValueObjects.Division]bool
+
+	// nativeScanner inventories divisions through the Cloud Control API.
+	nativeScanner Scanner
 }
 
-// NewAWSScanner creates and returns a new instance of AWSScanner.
-func NewAWSScanner(config map[terraformValueObjects.Division]terraformValueObjects.Credential, cliConfig Config, cloudRegions []terraformValueObjects.CloudRegion) (Scanner, error) {
+// NewAWSScanner creates and returns a new instance of AWSScanner. Divisions within nativeDivisions are inventoried
+// through the Cloud Control API in place of terraformer.
+func NewAWSScanner(config map[terraformValueObjects.Division]terraformValueObjects.Credential, cliConfig Config, cloudRegions []terraformValueObjects.CloudRegion, nativeDivisions []string) (Scanner, error) {
+	nativeDivisionSet := make(map[terraformValueObjects.Division]bool)
+	for _, division := range nativeDivisions {
+		nativeDivisionSet[terraformValueObjects.Division(division)] = true
+	}
+
+	nativeScanner, err := NewAWSNativeScanner(config, cliConfig, cloudRegions)
+	if err != nil {
+		return nil, fmt.Errorf("[NewAWSScanner] Error in NewAWSNativeScanner(): %w", err)
+	}
+
 	return &AWSScanner{
-		CloudRegions: cloudRegions,
-		config:       config,
-		terraformer:  newTerraformerCLI(cliConfig),
+		CloudRegions:    cloudRegions,
+		config:          config,
+		terraformer:     newTerraformerCLI(cliConfig),
+		nativeDivisions: nativeDivisionSet,
+		nativeScanner:   nativeScanner,
 	}, nil
 }
 
 // Scan uses the TerraformerCLI interface to scan a given division's cloud environment
-func (awsScanner *AWSScanner) Scan(project terraformValueObjects.Division, credential terraformValueObjects.Credential, options ...string) (terraformValueObjects.Path, error) {
+func (awsScanner *AWSScanner) Scan(ctx context.Context, project terraformValueObjects.Division, credential terraformValueObjects.Credential, options ...string) (terraformValueObjects.Path, error) {
 	err := awsScanner.configureEnvironment(credential)
 	if err != nil {
 		return "", fmt.Errorf("[AWS Scanner] Error configuring environment %w", err)
 	}
 
-	path, err := awsScanner.terraformer.Import(TerraformImportMigrationGeneratorParams{
+	path, err := awsScanner.terraformer.Import(ctx, TerraformImportMigrationGeneratorParams{
 		Provider:       "aws",
 		Division:       project,
 		Resources:      []string{},
@@ -52,7 +71,7 @@ func (awsScanner *AWSScanner) Scan(project terraformValueObjects.Division, crede
 		return "", fmt.Errorf("[Scan] Error in terraformer.Import(): %v", err)
 	}
 
-	err = awsScanner.terraformer.UpdateState("aws", string(path))
+	err = awsScanner.terraformer.UpdateState(ctx, "aws", string(path))
 
 	if err != nil {
 		return "", fmt.Errorf("[Scan] Error in terraformer.UpdateState(): %v", err)
@@ -62,12 +81,17 @@ func (awsScanner *AWSScanner) Scan(project terraformValueObjects.Division, crede
 }
 
 // ScanAll wraps Scan to scan each division for the provider.
-func (awsScanner *AWSScanner) ScanAll(options ...string) (*MultiScanResult, error) {
+func (awsScanner *AWSScanner) ScanAll(ctx context.Context, options ...string) (*MultiScanResult, error) {
 	fmt.Println("Scanning all specified AWS divisions.")
 	scanMap := make(map[terraformValueObjects.Division]terraformValueObjects.Path)
 
 	for div, credential := range awsScanner.config {
-		path, err := awsScanner.Scan(div, credential)
+		scanner := Scanner(awsScanner)
+		if awsScanner.nativeDivisions[div] {
+			scanner = awsScanner.nativeScanner
+		}
+
+		path, err := scanner.Scan(ctx, div, credential)
 		if err != nil {
 			return nil, fmt.Errorf("[ScanAll] Error in awsScanner.Scan: %v", err)
 		}
@@ -77,28 +101,12 @@ func (awsScanner *AWSScanner) ScanAll(options ...string) (*MultiScanResult, erro
 	return &MultiScanResult{scanMap}, nil
 }
 
-// AWSEnvironment is a struct defining the credential values needed for authenticating with an AWS account.
-type AWSEnvironment struct {
-	AWSAccessKeyID     string `json:"awsAccessKeyID"`
-	AWSSecretKeyAccess string `json:"awsSecretAccessKey"`
-}
-
-// configureEnvironment loads and sets as environment variables AWS credentials for a given AWS account.
+// configureEnvironment resolves and sets as environment variables AWS credentials for a given AWS account,
+// assuming the division's role when one is configured.
 func (awsScanner *AWSScanner) configureEnvironment(credential terraformValueObjects.Credential) error {
-	env := new(AWSEnvironment)
-	err := json.Unmarshal([]byte(credential), &env)
-	if err != nil {
-		return fmt.Errorf("[aws_scanner][configure_environment][error unmarshalling credentials] %w", err)
-	}
-
-	err = os.Setenv("AWS_ACCESS_KEY_ID", env.AWSAccessKeyID)
-	if err != nil {
-		return fmt.Errorf("[aws_scanner][configure_environment][error setting access_key_id credential] %w", err)
-	}
-
-	err = os.Setenv("AWS_SECRET_ACCESS_KEY", env.AWSSecretKeyAccess)
+	err := awscredentials.SetEnvironment(credential)
 	if err != nil {
-		return fmt.Errorf("[aws_scanner][configure_environment][error setting secret_access_key credential] %w", err)
+		return fmt.Errorf("[aws_scanner][configure_environment]%w", err)
 	}
 
 	return nil
@@ -12,6 +12,9 @@ import (
 func TestAWSScanner_configureEnvironment(t *testing.T) {
 	// Given
 	scanner := AWSScanner{}
+	for _, variable := range []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN"} {
+		t.Setenv(variable, "")
+	}
 	credentials := terraformValueObjects.Credential("{\n\"awsAccessKeyID\": \"123456ASD\",\n\"awsSecretAccessKey\": \"987654MNB\"\n}")
 
 	// When
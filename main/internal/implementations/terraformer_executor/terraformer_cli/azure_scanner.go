@@ -1,10 +1,12 @@
 package terraformerCLI
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/azuremanagementgroups"
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
 )
 
@@ -33,12 +35,12 @@ func NewAzureScanner(config map[terraformValueObjects.Division]terraformValueObj
 }
 
 // ScanAll wraps Scan to scan each division for the provider.
-func (azureScanner *AzureScanner) ScanAll(options ...string) (*MultiScanResult, error) {
+func (azureScanner *AzureScanner) ScanAll(ctx context.Context, options ...string) (*MultiScanResult, error) {
 	fmt.Println("Scanning all specified azure divisions.")
 	scanMap := make(map[terraformValueObjects.Division]terraformValueObjects.Path)
 
 	for division, credential := range azureScanner.config {
-		path, err := azureScanner.Scan(division, credential)
+		path, err := azureScanner.Scan(ctx, division, credential)
 		if err != nil {
 			return nil, fmt.Errorf("[ScanAll] Error in azureScanner.Scan: %v", err)
 		}
@@ -57,21 +59,27 @@ type AzureEnvironment struct {
 }
 
 // Scan uses the TerraformerCLI interface to scan a given division's cloud environment
-func (azureScanner *AzureScanner) Scan(resourceGroup terraformValueObjects.Division, credential terraformValueObjects.Credential, options ...string) (terraformValueObjects.Path, error) {
+func (azureScanner *AzureScanner) Scan(ctx context.Context, resourceGroup terraformValueObjects.Division, credential terraformValueObjects.Credential, options ...string) (terraformValueObjects.Path, error) {
 	env := new(AzureEnvironment)
 	err := json.Unmarshal([]byte(credential), &env)
 	if err != nil {
 		return "", fmt.Errorf("[azure_scanner][configure_environment][error unmarshalling credentials] %w", err)
 	}
 
-	err = azureScanner.configureEnvironment(*env)
+	if credential.IsAmbient() {
+		err = azureScanner.configureManagedIdentityEnvironment(*env)
+	} else {
+		err = azureScanner.configureEnvironment(*env)
+	}
 	if err != nil {
 		return "", fmt.Errorf("[Azure Scanner] Error configuring environment %w", err)
 	}
 
-	filterValue := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s", env.SubscriptionID, resourceGroup)
+	filterValue := fmt.Sprintf(
+		"/subscriptions/%s/resourceGroups/%s", env.SubscriptionID, azuremanagementgroups.ResourceGroupName(resourceGroup, env.SubscriptionID),
+	)
 
-	path, err := azureScanner.terraformer.Import(TerraformImportMigrationGeneratorParams{
+	path, err := azureScanner.terraformer.Import(ctx, TerraformImportMigrationGeneratorParams{
 		Provider:       "azurerm",
 		Division:       resourceGroup,
 		Resources:      []string{},
@@ -84,7 +92,7 @@ func (azureScanner *AzureScanner) Scan(resourceGroup terraformValueObjects.Divis
 		return "", fmt.Errorf("[Scan] Error in terraformer.Import(): %v", err)
 	}
 
-	err = azureScanner.terraformer.UpdateState("azurerm", string(path))
+	err = azureScanner.terraformer.UpdateState(ctx, "azurerm", string(path))
 
 	if err != nil {
 		return "", fmt.Errorf("[Scan] Error in terraformer.UpdateState(): %v", err)
@@ -94,7 +102,12 @@ func (azureScanner *AzureScanner) Scan(resourceGroup terraformValueObjects.Divis
 }
 
 func (azureScanner *AzureScanner) configureEnvironment(env AzureEnvironment) error {
-	err := os.Setenv("ARM_CLIENT_ID", env.ClientID)
+	err := os.Unsetenv("ARM_USE_MSI")
+	if err != nil {
+		return fmt.Errorf("[azure_scanner][configure_environment][error unsetting use_msi] %w", err)
+	}
+
+	err = os.Setenv("ARM_CLIENT_ID", env.ClientID)
 	if err != nil {
 		return fmt.Errorf("[azure_scanner][configure_environment][error setting client_id credential] %w", err)
 	}
@@ -116,3 +129,35 @@ func (azureScanner *AzureScanner) configureEnvironment(env AzureEnvironment) err
 
 	return nil
 }
+
+// configureManagedIdentityEnvironment configures terraformer and terraform to authenticate with the managed identity
+// available within the container's environment. A client_id may be passed to select a user-assigned identity.
+func (azureScanner *AzureScanner) configureManagedIdentityEnvironment(env AzureEnvironment) error {
+	if env.SubscriptionID == "" {
+		return fmt.Errorf("[azure_scanner][configure_managed_identity_environment][subscription_id is required for ambient credentials]")
+	}
+
+	err := os.Unsetenv("ARM_CLIENT_SECRET")
+	if err != nil {
+		return fmt.Errorf("[azure_scanner][configure_managed_identity_environment][error unsetting client_secret] %w", err)
+	}
+
+	environment := map[string]string{
+		"ARM_USE_MSI":         "true",
+		"ARM_CLIENT_ID":       env.ClientID,
+		"ARM_TENANT_ID":       env.TenantID,
+		"ARM_SUBSCRIPTION_ID": env.SubscriptionID,
+	}
+	for variable, value := range environment {
+		if value == "" {
+			err = os.Unsetenv(variable)
+		} else {
+			err = os.Setenv(variable, value)
+		}
+		if err != nil {
+			return fmt.Errorf("[azure_scanner][configure_managed_identity_environment][error setting %v] %w", variable, err)
+		}
+	}
+
+	return nil
+}
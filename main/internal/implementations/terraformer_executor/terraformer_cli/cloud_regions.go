@@ -21,3 +21,20 @@ func getValidRegions(cloudRegions []terraformValueObjects.CloudRegion, providerR
 
 	return regions
 }
+
+// getAllValidRegions returns every configured region known to the provider, or the default regions when none is.
+// Unlike getValidRegions, which limits terraformer to a single region, it is used by scanners that scan each region
+// separately.
+func getAllValidRegions(cloudRegions []terraformValueObjects.CloudRegion, providerRegions map[string]bool, defaultRegions []string) []string {
+	regions := make([]string, 0)
+	for _, region := range cloudRegions {
+		if providerRegions[string(region)] {
+			regions = append(regions, string(region))
+		}
+	}
+
+	if len(regions) == 0 {
+		return defaultRegions
+	}
+	return regions
+}
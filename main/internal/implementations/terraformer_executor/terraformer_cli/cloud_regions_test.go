@@ -61,3 +61,17 @@ func Test_getValidRegions_emptyCloudRegions(t *testing.T) {
 	// Then
 	require.Equal(t, []string{"us-east-1"}, regions)
 }
+
+func Test_getAllValidRegions(t *testing.T) {
+	// Given
+	cloudRegions := []terraformValueObjects.CloudRegion{"us-east-1", "westus2", "us-east-2"}
+	providerRegions := map[string]bool{"us-east-1": true, "us-east-2": true}
+	defaultRegions := []string{"us-west-2"}
+
+	// When
+	regions := getAllValidRegions(cloudRegions, providerRegions, defaultRegions)
+
+	// Then
+	require.Equal(t, []string{"us-east-1", "us-east-2"}, regions)
+	require.Equal(t, defaultRegions, getAllValidRegions(nil, providerRegions, defaultRegions))
+}
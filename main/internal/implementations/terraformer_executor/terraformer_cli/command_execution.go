@@ -0,0 +1,84 @@
+package terraformerCLI
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/ratelimit"
+)
+
+// ErrCommandTimedOut is returned when a command does not complete within the configured timeout.
+var ErrCommandTimedOut = errors.New("[command timed out]")
+
+// executeCommandWithRetries runs a command with the configured per-command timeout, retrying failed attempts
+// with an exponential backoff up to the configured maximum number of retries. Each attempt is rate limited by
+// limiter, which may be nil for commands that do not call cloud APIs. Cancelling ctx kills the command and stops
+// retrying.
+func (tfrCLI *terraformerCLI) executeCommandWithRetries(ctx context.Context, limiter *ratelimit.Limiter, command string, args ...string) error {
+	backoff := tfrCLI.config.CommandRetryBackoff
+
+	var err error
+	for attempt := 0; attempt <= tfrCLI.config.CommandMaxRetries; attempt++ {
+		if attempt > 0 {
+			log.Warnf("[%v] attempt %v failed, retrying in %v: %v", command, attempt, backoff, err)
+			timer := time.NewTimer(backoff)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return fmt.Errorf("[%v cancelled after %v attempt(s)][%v]%w", command, attempt, err, ctx.Err())
+			case <-timer.C:
+			}
+			backoff *= 2
+		}
+
+		err = limiter.Do(ctx, func() error {
+			return executeCommandWithTimeout(ctx, tfrCLI.config.CommandTimeout, command, args...)
+		})
+		if err == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("[%v failed after %v attempt(s)]%w", command, tfrCLI.config.CommandMaxRetries+1, err)
+}
+
+// executeCommandWithTimeout wraps executeCommand, killing the command if it has not completed within timeout or
+// ctx is cancelled. A timeout of zero disables the limit.
+func executeCommandWithTimeout(ctx context.Context, timeout time.Duration, command string, args ...string) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	err := executeCommand(ctx, command, args...)
+	if err != nil && timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("[%v exceeded %v]%w", command, timeout, ErrCommandTimedOut)
+	}
+	return err
+}
+
+// executeCommand wraps os.exec.CommandContext with capturing of std output and errors.
+func executeCommand(ctx context.Context, command string, args ...string) error {
+	cmd := exec.CommandContext(ctx, command, args...)
+
+	// Setting up logging objects
+	var out bytes.Buffer
+	cmd.Stdout = &out
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+
+	if err != nil {
+		return fmt.Errorf("%v\n\n%v", err, stderr.String()+out.String())
+	}
+	fmt.Printf("\n%s Output:\n\n%v\n", command, out.String())
+	return nil
+}
@@ -0,0 +1,56 @@
+package terraformerCLI
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteCommandWithTimeout(t *testing.T) {
+	// When
+	err := executeCommandWithTimeout(context.Background(), 50*time.Millisecond, "sleep", "5")
+
+	// Then
+	assert.True(t, errors.Is(err, ErrCommandTimedOut))
+	assert.NoError(t, executeCommandWithTimeout(context.Background(), time.Second, "true"))
+	assert.NoError(t, executeCommandWithTimeout(context.Background(), 0, "true"))
+}
+
+func TestExecuteCommandWithRetries(t *testing.T) {
+	// Given
+	attemptsFile := filepath.Join(t.TempDir(), "attempts")
+	tfrCLI := &terraformerCLI{config: Config{CommandMaxRetries: 2, CommandRetryBackoff: time.Millisecond}}
+
+	// When
+	err := tfrCLI.executeCommandWithRetries(context.Background(), nil, "sh", "-c", "echo attempt >> "+attemptsFile+"; exit 1")
+
+	// Then
+	assert.Error(t, err)
+	attempts, readErr := os.ReadFile(attemptsFile)
+	require.NoError(t, readErr)
+	assert.Equal(t, 3, strings.Count(string(attempts), "attempt"))
+}
+
+func TestExecuteCommandWithRetries_Cancelled(t *testing.T) {
+	// Given
+	attemptsFile := filepath.Join(t.TempDir(), "attempts")
+	tfrCLI := &terraformerCLI{config: Config{CommandMaxRetries: 2, CommandRetryBackoff: time.Hour}}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// When
+	err := tfrCLI.executeCommandWithRetries(ctx, nil, "sh", "-c", "echo attempt >> "+attemptsFile+"; exit 1")
+
+	// Then
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	attempts, readErr := os.ReadFile(attemptsFile)
+	require.NoError(t, readErr)
+	assert.Equal(t, 1, strings.Count(string(attempts), "attempt"))
+}
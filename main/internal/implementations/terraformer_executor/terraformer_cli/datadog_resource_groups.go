@@ -0,0 +1,36 @@
+package terraformerCLI
+
+import terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+
+var datadogResourceGroups = map[terraformValueObjects.ResourceName]string{
+	"datadog_dashboard":                            "dashboard",
+	"datadog_dashboard_json":                       "dashboard_json",
+	"datadog_dashboard_list":                       "dashboard_list",
+	"datadog_downtime":                             "downtime",
+	"datadog_integration_aws":                      "integration_aws",
+	"datadog_integration_aws_lambda_arn":           "integration_aws_lambda_arn",
+	"datadog_integration_aws_log_collection":       "integration_aws_log_collection",
+	"datadog_integration_azure":                    "integration_azure",
+	"datadog_integration_gcp":                      "integration_gcp",
+	"datadog_integration_pagerduty":                "integration_pagerduty",
+	"datadog_integration_pagerduty_service_object": "integration_pagerduty_service_object",
+	"datadog_integration_slack_channel":            "integration_slack_channel",
+	"datadog_logs_archive":                         "logs_archive",
+	"datadog_logs_archive_order":                   "logs_archive_order",
+	"datadog_logs_custom_pipeline":                 "logs_custom_pipeline",
+	"datadog_logs_index":                           "logs_index",
+	"datadog_logs_index_order":                     "logs_index_order",
+	"datadog_logs_integration_pipeline":            "logs_integration_pipeline",
+	"datadog_logs_metric":                          "logs_metric",
+	"datadog_logs_pipeline_order":                  "logs_pipeline_order",
+	"datadog_metric_metadata":                      "metric_metadata",
+	"datadog_monitor":                              "monitor",
+	"datadog_role":                                 "role",
+	"datadog_security_monitoring_default_rule":     "security_monitoring_default_rule",
+	"datadog_security_monitoring_rule":             "security_monitoring_rule",
+	"datadog_service_level_objective":              "service_level_objective",
+	"datadog_synthetics_global_variable":           "synthetics_global_variable",
+	"datadog_synthetics_private_location":          "synthetics_private_location",
+	"datadog_synthetics_test":                      "synthetics_test",
+	"datadog_user":                                 "user",
+}
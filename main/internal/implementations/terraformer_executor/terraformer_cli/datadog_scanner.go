@@ -0,0 +1,109 @@
+package terraformerCLI
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+// DatadogScanner implements the Scanner interface for use with Datadog organizations.
+type DatadogScanner struct {
+	// Config is the needed configuration of a mapping between Division name and the corresponding
+	// Credential needed to access that environment.
+	config map[terraformValueObjects.Division]terraformValueObjects.Credential
+
+	// terraformer is the TerraformerCLI interface used to scan the Datadog organization.
+	terraformer TerraformerCLI
+}
+
+// NewDatadogScanner creates and returns a new instance of DatadogScanner.
+func NewDatadogScanner(config map[terraformValueObjects.Division]terraformValueObjects.Credential, cliConfig Config) (Scanner, error) {
+	return &DatadogScanner{
+		config:      config,
+		terraformer: newTerraformerCLI(cliConfig),
+	}, nil
+}
+
+// DatadogEnvironment represents the configuration to run terraformer for Datadog
+type DatadogEnvironment struct {
+	APIKey string `json:"datadog_api_key"`
+	AppKey string `json:"datadog_app_key"`
+	APIURL string `json:"datadog_api_url"`
+}
+
+// ScanAll wraps Scan to scan each division for the provider.
+func (datadogScanner *DatadogScanner) ScanAll(ctx context.Context, options ...string) (*MultiScanResult, error) {
+	fmt.Println("Scanning all specified Datadog divisions.")
+	scanMap := make(map[terraformValueObjects.Division]terraformValueObjects.Path)
+
+	for division, credential := range datadogScanner.config {
+		path, err := datadogScanner.Scan(ctx, division, credential)
+		if err != nil {
+			return nil, fmt.Errorf("[ScanAll] Error in datadogScanner.Scan: %v", err)
+		}
+		scanMap[division] = path
+	}
+
+	return &MultiScanResult{scanMap}, nil
+}
+
+// Scan uses the TerraformerCLI interface to scan a given division's Datadog organization. Datadog resources are not
+// regional, so no regions are passed to terraformer.
+func (datadogScanner *DatadogScanner) Scan(ctx context.Context, organization terraformValueObjects.Division, credential terraformValueObjects.Credential, options ...string) (terraformValueObjects.Path, error) {
+	err := datadogScanner.configureEnvironment(credential)
+	if err != nil {
+		return "", fmt.Errorf("[Datadog Scanner] Error configuring environment %w", err)
+	}
+
+	path, err := datadogScanner.terraformer.Import(ctx, TerraformImportMigrationGeneratorParams{
+		Provider:       "datadog",
+		Division:       organization,
+		Resources:      []string{},
+		AdditionalArgs: []string{},
+		Regions:        []string{},
+		IsCompact:      true,
+	})
+
+	if err != nil {
+		return "", fmt.Errorf("[Scan] Error in terraformer.Import(): %v", err)
+	}
+
+	err = datadogScanner.terraformer.UpdateState(ctx, "datadog", string(path))
+
+	if err != nil {
+		return "", fmt.Errorf("[Scan] Error in terraformer.UpdateState(): %v", err)
+	}
+
+	return path, nil
+}
+
+// configureEnvironment exports the division's API and application keys, read by both terraformer and the Datadog
+// Terraform provider. The keys are not passed as arguments, which would otherwise be logged.
+func (datadogScanner *DatadogScanner) configureEnvironment(credential terraformValueObjects.Credential) error {
+	env := new(DatadogEnvironment)
+	err := json.Unmarshal([]byte(credential), &env)
+	if err != nil {
+		return fmt.Errorf("[datadog_scanner][configure_environment][error unmarshalling credentials] %w", err)
+	}
+
+	environment := map[string]string{
+		"DATADOG_API_KEY": env.APIKey,
+		"DATADOG_APP_KEY": env.AppKey,
+		"DATADOG_HOST":    env.APIURL,
+	}
+	for variable, value := range environment {
+		if value == "" {
+			err = os.Unsetenv(variable)
+		} else {
+			err = os.Setenv(variable, value)
+		}
+		if err != nil {
+			return fmt.Errorf("[datadog_scanner][configure_environment][error setting %v] %w", variable, err)
+		}
+	}
+
+	return nil
+}
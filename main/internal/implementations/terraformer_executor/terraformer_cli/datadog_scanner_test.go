@@ -0,0 +1,29 @@
+package terraformerCLI
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+func TestDatadogScanner_configureEnvironment(t *testing.T) {
+	// Given
+	scanner := DatadogScanner{}
+	t.Setenv("DATADOG_API_KEY", "")
+	t.Setenv("DATADOG_APP_KEY", "")
+	t.Setenv("DATADOG_HOST", "https://api.datadoghq.eu/")
+	credentials := terraformValueObjects.Credential(`{"datadog_api_key": "api-key", "datadog_app_key": "app-key"}`)
+
+	// When
+	err := scanner.configureEnvironment(credentials)
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, "api-key", os.Getenv("DATADOG_API_KEY"))
+	assert.Equal(t, "app-key", os.Getenv("DATADOG_APP_KEY"))
+	_, ok := os.LookupEnv("DATADOG_HOST")
+	assert.False(t, ok)
+}
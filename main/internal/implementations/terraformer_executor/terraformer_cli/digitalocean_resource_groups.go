@@ -0,0 +1,29 @@
+package terraformerCLI
+
+import terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+
+var digitalOceanResourceGroups = map[terraformValueObjects.ResourceName]string{
+	"digitalocean_cdn":                      "cdn",
+	"digitalocean_certificate":              "certificate",
+	"digitalocean_database_cluster":         "database_cluster",
+	"digitalocean_database_connection_pool": "database_connection_pool",
+	"digitalocean_database_db":              "database_db",
+	"digitalocean_database_firewall":        "database_firewall",
+	"digitalocean_database_replica":         "database_replica",
+	"digitalocean_database_user":            "database_user",
+	"digitalocean_domain":                   "domain",
+	"digitalocean_droplet":                  "droplet",
+	"digitalocean_droplet_snapshot":         "droplet_snapshot",
+	"digitalocean_firewall":                 "firewall",
+	"digitalocean_floating_ip":              "floating_ip",
+	"digitalocean_kubernetes_cluster":       "kubernetes_cluster",
+	"digitalocean_kubernetes_node_pool":     "kubernetes_node_pool",
+	"digitalocean_loadbalancer":             "loadbalancer",
+	"digitalocean_project":                  "project",
+	"digitalocean_record":                   "domain",
+	"digitalocean_ssh_key":                  "ssh_key",
+	"digitalocean_tag":                      "tag",
+	"digitalocean_volume":                   "volume",
+	"digitalocean_volume_snapshot":          "volume_snapshot",
+	"digitalocean_vpc":                      "vpc",
+}
@@ -0,0 +1,96 @@
+package terraformerCLI
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+// DigitalOceanScanner implements the Scanner interface for use with DigitalOcean teams.
+type DigitalOceanScanner struct {
+	// Config is the needed configuration of a mapping between Division name and the corresponding
+	// Credential needed to access that environment.
+	config map[terraformValueObjects.Division]terraformValueObjects.Credential
+
+	// terraformer is the TerraformerCLI interface used to scan the DigitalOcean team.
+	terraformer TerraformerCLI
+}
+
+// NewDigitalOceanScanner creates and returns a new instance of DigitalOceanScanner.
+func NewDigitalOceanScanner(config map[terraformValueObjects.Division]terraformValueObjects.Credential, cliConfig Config) (Scanner, error) {
+	return &DigitalOceanScanner{
+		config:      config,
+		terraformer: newTerraformerCLI(cliConfig),
+	}, nil
+}
+
+// DigitalOceanEnvironment represents the configuration to run terraformer for DigitalOcean
+type DigitalOceanEnvironment struct {
+	Token string `json:"digitalocean_token"`
+}
+
+// ScanAll wraps Scan to scan each division for the provider.
+func (doScanner *DigitalOceanScanner) ScanAll(ctx context.Context, options ...string) (*MultiScanResult, error) {
+	fmt.Println("Scanning all specified DigitalOcean divisions.")
+	scanMap := make(map[terraformValueObjects.Division]terraformValueObjects.Path)
+
+	for division, credential := range doScanner.config {
+		path, err := doScanner.Scan(ctx, division, credential)
+		if err != nil {
+			return nil, fmt.Errorf("[ScanAll] Error in doScanner.Scan: %v", err)
+		}
+		scanMap[division] = path
+	}
+
+	return &MultiScanResult{scanMap}, nil
+}
+
+// Scan uses the TerraformerCLI interface to scan a given division's DigitalOcean team. Terraformer imports
+// DigitalOcean resources from every region at once, so no regions are passed.
+func (doScanner *DigitalOceanScanner) Scan(ctx context.Context, team terraformValueObjects.Division, credential terraformValueObjects.Credential, options ...string) (terraformValueObjects.Path, error) {
+	err := doScanner.configureEnvironment(credential)
+	if err != nil {
+		return "", fmt.Errorf("[DigitalOcean Scanner] Error configuring environment %w", err)
+	}
+
+	path, err := doScanner.terraformer.Import(ctx, TerraformImportMigrationGeneratorParams{
+		Provider:       "digitalocean",
+		Division:       team,
+		Resources:      []string{},
+		AdditionalArgs: []string{},
+		Regions:        []string{},
+		IsCompact:      true,
+	})
+
+	if err != nil {
+		return "", fmt.Errorf("[Scan] Error in terraformer.Import(): %v", err)
+	}
+
+	err = doScanner.terraformer.UpdateState(ctx, "digitalocean", string(path))
+
+	if err != nil {
+		return "", fmt.Errorf("[Scan] Error in terraformer.UpdateState(): %v", err)
+	}
+
+	return path, nil
+}
+
+// configureEnvironment exports the division's personal access token, read by both terraformer and the
+// DigitalOcean Terraform provider.
+func (doScanner *DigitalOceanScanner) configureEnvironment(credential terraformValueObjects.Credential) error {
+	env := new(DigitalOceanEnvironment)
+	err := json.Unmarshal([]byte(credential), &env)
+	if err != nil {
+		return fmt.Errorf("[digitalocean_scanner][configure_environment][error unmarshalling credentials] %w", err)
+	}
+
+	err = os.Setenv("DIGITALOCEAN_TOKEN", env.Token)
+	if err != nil {
+		return fmt.Errorf("[digitalocean_scanner][configure_environment][error setting token credential] %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package terraformerCLI
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+func TestDigitalOceanScanner_configureEnvironment(t *testing.T) {
+	// Given
+	scanner := DigitalOceanScanner{}
+	t.Setenv("DIGITALOCEAN_TOKEN", "")
+	credentials := terraformValueObjects.Credential(`{"digitalocean_token": "dop_v1_token"}`)
+
+	// When
+	err := scanner.configureEnvironment(credentials)
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, "dop_v1_token", os.Getenv("DIGITALOCEAN_TOKEN"))
+}
@@ -1,6 +1,7 @@
 package terraformerCLI
 
 import (
+	"context"
 	"fmt"
 	"os"
 
@@ -32,23 +33,14 @@ func NewGoogleScanner(config map[terraformValueObjects.Division]terraformValueOb
 }
 
 // Scan uses the TerraformerCLI interface to scan a given division's cloud environment
-func (gcpScan *GoogleScanner) Scan(project terraformValueObjects.Division, credential terraformValueObjects.Credential, options ...string) (terraformValueObjects.Path, error) {
-	_ = os.MkdirAll("credentials", 0660)
-
-	err := os.WriteFile(fmt.Sprintf("credentials/google-%v.json", project), []byte(credential), 0400)
-
+func (gcpScan *GoogleScanner) Scan(ctx context.Context, project terraformValueObjects.Division, credential terraformValueObjects.Credential, options ...string) (terraformValueObjects.Path, error) {
+	err := gcpScan.configureEnvironment(project, credential)
 	if err != nil {
-		return "", fmt.Errorf("[Scan] error saving credential file: %v", err)
-	}
-
-	err = os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", fmt.Sprintf("credentials/google-%s.json", project))
-
-	if err != nil {
-		return "", fmt.Errorf("[Scan] Error in setting GOOGLE_APPLICATION_CREDENTIALS value: %v", err)
+		return "", fmt.Errorf("[Scan] Error configuring environment: %v", err)
 	}
 
 	projectsFlag := fmt.Sprintf("--projects=%v", project)
-	path, err := gcpScan.terraformer.Import(TerraformImportMigrationGeneratorParams{
+	path, err := gcpScan.terraformer.Import(ctx, TerraformImportMigrationGeneratorParams{
 		Provider:       "google",
 		Division:       project,
 		Regions:        []string{"us-east4", "global"},
@@ -61,7 +53,7 @@ func (gcpScan *GoogleScanner) Scan(project terraformValueObjects.Division, crede
 		return "", fmt.Errorf("[Scan] Error in terraformer.Import(): %v", err)
 	}
 
-	err = gcpScan.terraformer.UpdateState("google", string(path))
+	err = gcpScan.terraformer.UpdateState(ctx, "google", string(path))
 
 	if err != nil {
 		return "", fmt.Errorf("[Scan] Error in terraformer.UpdateState(): %v", err)
@@ -70,13 +62,39 @@ func (gcpScan *GoogleScanner) Scan(project terraformValueObjects.Division, crede
 	return path, nil
 }
 
+// configureEnvironment points GOOGLE_APPLICATION_CREDENTIALS at the division's service account key. For ambient
+// credentials the variable is unset, so that application default credentials such as workload identity are used.
+func (gcpScan *GoogleScanner) configureEnvironment(project terraformValueObjects.Division, credential terraformValueObjects.Credential) error {
+	if credential.IsAmbient() {
+		err := os.Unsetenv("GOOGLE_APPLICATION_CREDENTIALS")
+		if err != nil {
+			return fmt.Errorf("[google_scanner][configure_environment][error unsetting GOOGLE_APPLICATION_CREDENTIALS]%w", err)
+		}
+		return nil
+	}
+
+	_ = os.MkdirAll("credentials", 0660)
+
+	err := os.WriteFile(fmt.Sprintf("credentials/google-%v.json", project), []byte(credential), 0400)
+	if err != nil {
+		return fmt.Errorf("[google_scanner][configure_environment][error saving credential file]%w", err)
+	}
+
+	err = os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", fmt.Sprintf("credentials/google-%s.json", project))
+	if err != nil {
+		return fmt.Errorf("[google_scanner][configure_environment][error setting GOOGLE_APPLICATION_CREDENTIALS]%w", err)
+	}
+
+	return nil
+}
+
 // ScanAll wraps Scan to scan each division for the provider.
-func (gcpScan *GoogleScanner) ScanAll(options ...string) (*MultiScanResult, error) {
+func (gcpScan *GoogleScanner) ScanAll(ctx context.Context, options ...string) (*MultiScanResult, error) {
 	fmt.Println("Scanning all specified GCP divisions.")
 	scanMap := make(map[terraformValueObjects.Division]terraformValueObjects.Path)
 
 	for div, credential := range gcpScan.config {
-		path, err := gcpScan.Scan(div, credential)
+		path, err := gcpScan.Scan(ctx, div, credential)
 		if err != nil {
 			return nil, fmt.Errorf("[ScanAll] Error in gcpScan.Scan: %v", err)
 		}
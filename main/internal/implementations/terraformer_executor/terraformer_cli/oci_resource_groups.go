@@ -0,0 +1,122 @@
+package terraformerCLI
+
+import terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+
+var ociResourceGroups = map[terraformValueObjects.ResourceName]string{
+	"oci_apigateway_api":                              "apigateway",
+	"oci_apigateway_certificate":                      "apigateway",
+	"oci_apigateway_deployment":                       "apigateway",
+	"oci_apigateway_gateway":                          "apigateway",
+	"oci_bastion_bastion":                             "bastion",
+	"oci_containerengine_cluster":                     "containerengine",
+	"oci_containerengine_node_pool":                   "containerengine",
+	"oci_core_boot_volume":                            "core",
+	"oci_core_boot_volume_backup":                     "core",
+	"oci_core_cluster_network":                        "core",
+	"oci_core_console_history":                        "core",
+	"oci_core_cpe":                                    "core",
+	"oci_core_dedicated_vm_host":                      "core",
+	"oci_core_dhcp_options":                           "core",
+	"oci_core_drg":                                    "core",
+	"oci_core_drg_attachment":                         "core",
+	"oci_core_drg_route_distribution":                 "core",
+	"oci_core_drg_route_table":                        "core",
+	"oci_core_image":                                  "core",
+	"oci_core_instance":                               "core",
+	"oci_core_instance_configuration":                 "core",
+	"oci_core_instance_pool":                          "core",
+	"oci_core_internet_gateway":                       "core",
+	"oci_core_ipsec":                                  "core",
+	"oci_core_local_peering_gateway":                  "core",
+	"oci_core_nat_gateway":                            "core",
+	"oci_core_network_security_group":                 "core",
+	"oci_core_network_security_group_security_rule":   "core",
+	"oci_core_private_ip":                             "core",
+	"oci_core_public_ip":                              "core",
+	"oci_core_remote_peering_connection":              "core",
+	"oci_core_route_table":                            "core",
+	"oci_core_security_list":                          "core",
+	"oci_core_service_gateway":                        "core",
+	"oci_core_subnet":                                 "core",
+	"oci_core_vcn":                                    "core",
+	"oci_core_vlan":                                   "core",
+	"oci_core_vnic_attachment":                        "core",
+	"oci_core_volume":                                 "core",
+	"oci_core_volume_attachment":                      "core",
+	"oci_core_volume_backup":                          "core",
+	"oci_core_volume_backup_policy":                   "core",
+	"oci_core_volume_group":                           "core",
+	"oci_database_autonomous_container_database":      "database",
+	"oci_database_autonomous_database":                "database",
+	"oci_database_autonomous_exadata_infrastructure":  "database",
+	"oci_database_backup_destination":                 "database",
+	"oci_database_db_home":                            "database",
+	"oci_database_db_system":                          "database",
+	"oci_database_exadata_infrastructure":             "database",
+	"oci_database_vm_cluster":                         "database",
+	"oci_database_vm_cluster_network":                 "database",
+	"oci_dns_record":                                  "dns",
+	"oci_dns_resolver":                                "dns",
+	"oci_dns_rrset":                                   "dns",
+	"oci_dns_steering_policy":                         "dns",
+	"oci_dns_tsig_key":                                "dns",
+	"oci_dns_view":                                    "dns",
+	"oci_dns_zone":                                    "dns",
+	"oci_events_rule":                                 "events",
+	"oci_file_storage_export":                         "file_storage",
+	"oci_file_storage_export_set":                     "file_storage",
+	"oci_file_storage_file_system":                    "file_storage",
+	"oci_file_storage_mount_target":                   "file_storage",
+	"oci_functions_application":                       "functions",
+	"oci_functions_function":                          "functions",
+	"oci_identity_api_key":                            "identity",
+	"oci_identity_auth_token":                         "identity",
+	"oci_identity_authentication_policy":              "identity",
+	"oci_identity_compartment":                        "identity",
+	"oci_identity_customer_secret_key":                "identity",
+	"oci_identity_dynamic_group":                      "identity",
+	"oci_identity_group":                              "identity",
+	"oci_identity_identity_provider":                  "identity",
+	"oci_identity_policy":                             "identity",
+	"oci_identity_smtp_credential":                    "identity",
+	"oci_identity_tag":                                "identity",
+	"oci_identity_tag_default":                        "identity",
+	"oci_identity_tag_namespace":                      "identity",
+	"oci_identity_user":                               "identity",
+	"oci_identity_user_group_membership":              "identity",
+	"oci_kms_key":                                     "kms",
+	"oci_kms_key_version":                             "kms",
+	"oci_kms_vault":                                   "kms",
+	"oci_load_balancer_backend":                       "load_balancer",
+	"oci_load_balancer_backend_set":                   "load_balancer",
+	"oci_load_balancer_certificate":                   "load_balancer",
+	"oci_load_balancer_hostname":                      "load_balancer",
+	"oci_load_balancer_listener":                      "load_balancer",
+	"oci_load_balancer_load_balancer":                 "load_balancer",
+	"oci_load_balancer_path_route_set":                "load_balancer",
+	"oci_load_balancer_rule_set":                      "load_balancer",
+	"oci_logging_log":                                 "logging",
+	"oci_logging_log_group":                           "logging",
+	"oci_monitoring_alarm":                            "monitoring",
+	"oci_mysql_mysql_backup":                          "mysql",
+	"oci_mysql_mysql_db_system":                       "mysql",
+	"oci_network_load_balancer_backend":               "network_load_balancer",
+	"oci_network_load_balancer_backend_set":           "network_load_balancer",
+	"oci_network_load_balancer_listener":              "network_load_balancer",
+	"oci_network_load_balancer_network_load_balancer": "network_load_balancer",
+	"oci_nosql_index":                                 "nosql",
+	"oci_nosql_table":                                 "nosql",
+	"oci_objectstorage_bucket":                        "object_storage",
+	"oci_objectstorage_namespace_metadata":            "object_storage",
+	"oci_objectstorage_object_lifecycle_policy":       "object_storage",
+	"oci_objectstorage_replication_policy":            "object_storage",
+	"oci_ons_notification_topic":                      "ons",
+	"oci_ons_subscription":                            "ons",
+	"oci_streaming_connect_harness":                   "streaming",
+	"oci_streaming_stream":                            "streaming",
+	"oci_streaming_stream_pool":                       "streaming",
+	"oci_waas_address_list":                           "waas",
+	"oci_waas_custom_protection_rule":                 "waas",
+	"oci_waas_http_redirect":                          "waas",
+	"oci_waas_waas_policy":                            "waas",
+}
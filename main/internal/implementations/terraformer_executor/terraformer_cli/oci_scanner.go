@@ -0,0 +1,176 @@
+package terraformerCLI
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+var defaultOciRegions = []string{"us-ashburn-1"}
+
+// OCIScanner implements the Scanner interface for use with Oracle Cloud Infrastructure tenancies.
+type OCIScanner struct {
+	// Config is the needed configuration of a mapping between Division name and the corresponding
+	// Credential needed to access that environment.
+	config map[terraformValueObjects.Division]terraformValueObjects.Credential
+
+	// terraformer is the TerraformerCLI interface used to scan the OCI tenancy.
+	terraformer TerraformerCLI
+
+	// CloudRegions represents the list of cloud regions that will be considered for inclusion in the import statement.
+	CloudRegions []terraformValueObjects.CloudRegion `required:"true"`
+}
+
+// NewOCIScanner creates and returns a new instance of OCIScanner.
+func NewOCIScanner(config map[terraformValueObjects.Division]terraformValueObjects.Credential, cliConfig Config, cloudRegions []terraformValueObjects.CloudRegion) (Scanner, error) {
+	return &OCIScanner{
+		CloudRegions: cloudRegions,
+		config:       config,
+		terraformer:  newTerraformerCLI(cliConfig),
+	}, nil
+}
+
+// OCIEnvironment represents the API signing key configuration to run terraformer for OCI
+type OCIEnvironment struct {
+	TenancyOCID string `json:"tenancy_ocid"`
+	UserOCID    string `json:"user_ocid"`
+	Fingerprint string `json:"fingerprint"`
+	PrivateKey  string `json:"private_key"`
+	Region      string `json:"region"`
+}
+
+// ScanAll wraps Scan to scan each division for the provider.
+func (ociScanner *OCIScanner) ScanAll(ctx context.Context, options ...string) (*MultiScanResult, error) {
+	fmt.Println("Scanning all specified oci divisions.")
+	scanMap := make(map[terraformValueObjects.Division]terraformValueObjects.Path)
+
+	for division, credential := range ociScanner.config {
+		path, err := ociScanner.Scan(ctx, division, credential)
+		if err != nil {
+			return nil, fmt.Errorf("[ScanAll] Error in ociScanner.Scan: %v", err)
+		}
+		scanMap[division] = path
+	}
+
+	return &MultiScanResult{scanMap}, nil
+}
+
+// Scan uses the TerraformerCLI interface to scan a given division's OCI tenancy within each configured region, or
+// within the credential's home region when no OCI region is configured. As the OCI API is regional, each region is
+// imported separately, and the imports are merged into a single output.
+func (ociScanner *OCIScanner) Scan(ctx context.Context, tenancy terraformValueObjects.Division, credential terraformValueObjects.Credential, options ...string) (terraformValueObjects.Path, error) {
+	env, err := parseOCIEnvironment(credential)
+	if err != nil {
+		return "", fmt.Errorf("[OCI Scanner] Error configuring environment %w", err)
+	}
+
+	defaultRegions := defaultOciRegions
+	if terraformValueObjects.OciRegions[env.Region] {
+		defaultRegions = []string{env.Region}
+	}
+	regions := getAllValidRegions(ociScanner.CloudRegions, terraformValueObjects.OciRegions, defaultRegions)
+
+	outputDirectory := fmt.Sprintf("./oci-%v", tenancy)
+	regionsDirectory := fmt.Sprintf("%s-regions", outputDirectory)
+	defer os.RemoveAll(regionsDirectory)
+
+	var path terraformValueObjects.Path
+	regionDirectories := make([]string, 0, len(regions))
+	for _, region := range regions {
+		err = ociScanner.configureEnvironment(env, region)
+		if err != nil {
+			return "", fmt.Errorf("[OCI Scanner] Error configuring environment %w", err)
+		}
+
+		path, err = ociScanner.terraformer.Import(ctx, TerraformImportMigrationGeneratorParams{
+			Provider:       "oci",
+			Division:       tenancy,
+			Resources:      []string{},
+			AdditionalArgs: []string{},
+			Regions:        []string{region},
+			IsCompact:      true,
+		})
+
+		if err != nil {
+			return "", fmt.Errorf("[Scan] Error in terraformer.Import() within %v: %v", region, err)
+		}
+
+		if len(regions) > 1 {
+			regionDirectory, err := moveRegionOutput(outputDirectory, regionsDirectory, region)
+			if err != nil {
+				return "", fmt.Errorf("[Scan]%w", err)
+			}
+			regionDirectories = append(regionDirectories, regionDirectory)
+		}
+	}
+
+	if len(regions) > 1 {
+		err = mergeTerraformerOutputs(regionDirectories, outputDirectory)
+		if err != nil {
+			return "", fmt.Errorf("[Scan] Error merging the regional imports: %w", err)
+		}
+	}
+
+	err = ociScanner.terraformer.UpdateState(ctx, "oci", string(path))
+
+	if err != nil {
+		return "", fmt.Errorf("[Scan] Error in terraformer.UpdateState(): %v", err)
+	}
+
+	return path, nil
+}
+
+// parseOCIEnvironment parses the division's API signing key configuration.
+func parseOCIEnvironment(credential terraformValueObjects.Credential) (*OCIEnvironment, error) {
+	env := new(OCIEnvironment)
+	err := json.Unmarshal([]byte(credential), &env)
+	if err != nil {
+		return nil, fmt.Errorf("[oci_scanner][parse_oci_environment][error unmarshalling credentials] %w", err)
+	}
+	return env, nil
+}
+
+// configureEnvironment exports the division's API signing key, read by both terraformer and the OCI Terraform
+// provider, together with the region to scan, which determines the regional endpoints called.
+func (ociScanner *OCIScanner) configureEnvironment(env *OCIEnvironment, scanRegion string) error {
+	environment := map[string]string{
+		"OCI_TENANCY_OCID": env.TenancyOCID,
+		"OCI_USER_OCID":    env.UserOCID,
+		"OCI_FINGERPRINT":  env.Fingerprint,
+		"OCI_PRIVATE_KEY":  env.PrivateKey,
+		"OCI_REGION":       scanRegion,
+	}
+	for variable, value := range environment {
+		err := os.Setenv(variable, value)
+		if err != nil {
+			return fmt.Errorf("[oci_scanner][configure_environment][error setting %v] %w", variable, err)
+		}
+	}
+
+	return nil
+}
+
+// moveRegionOutput moves the import within outputDirectory to a directory of region within regionsDirectory, so that
+// it is not overwritten by the import of the next region. Returns the directory of region.
+func moveRegionOutput(outputDirectory string, regionsDirectory string, region string) (string, error) {
+	err := os.MkdirAll(regionsDirectory, 0755)
+	if err != nil {
+		return "", fmt.Errorf("[move_region_output][error creating %v]%w", regionsDirectory, err)
+	}
+
+	regionDirectory := filepath.Join(regionsDirectory, region)
+	err = os.RemoveAll(regionDirectory)
+	if err != nil {
+		return "", fmt.Errorf("[move_region_output][error removing %v]%w", regionDirectory, err)
+	}
+
+	err = os.Rename(outputDirectory, regionDirectory)
+	if err != nil {
+		return "", fmt.Errorf("[move_region_output][error moving %v]%w", outputDirectory, err)
+	}
+	return regionDirectory, nil
+}
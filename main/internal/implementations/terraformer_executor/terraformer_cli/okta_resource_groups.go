@@ -0,0 +1,38 @@
+package terraformerCLI
+
+import terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+
+var oktaResourceGroups = map[terraformValueObjects.ResourceName]string{
+	"okta_app_auto_login":            "okta_app_auto_login",
+	"okta_app_basic_auth":            "okta_app_basic_auth",
+	"okta_app_bookmark":              "okta_app_bookmark",
+	"okta_app_oauth":                 "okta_app_oauth",
+	"okta_app_saml":                  "okta_app_saml",
+	"okta_app_secure_password_store": "okta_app_secure_password_store",
+	"okta_app_swa":                   "okta_app_swa",
+	"okta_app_three_field":           "okta_app_three_field",
+	"okta_auth_server":               "okta_auth_server",
+	"okta_auth_server_claim":         "okta_auth_server_claim",
+	"okta_auth_server_policy":        "okta_auth_server_policy",
+	"okta_auth_server_scope":         "okta_auth_server_scope",
+	"okta_event_hook":                "okta_event_hook",
+	"okta_factor":                    "okta_factor",
+	"okta_group":                     "okta_group",
+	"okta_group_rule":                "okta_group_rule",
+	"okta_idp_oidc":                  "okta_idp_oidc",
+	"okta_idp_saml":                  "okta_idp_saml",
+	"okta_idp_social":                "okta_idp_social",
+	"okta_inline_hook":               "okta_inline_hook",
+	"okta_network_zone":              "okta_network_zone",
+	"okta_policy_mfa":                "okta_policy_mfa",
+	"okta_policy_password":           "okta_policy_password",
+	"okta_policy_rule_mfa":           "okta_policy_rule_mfa",
+	"okta_policy_rule_password":      "okta_policy_rule_password",
+	"okta_policy_rule_signon":        "okta_policy_rule_signon",
+	"okta_policy_signon":             "okta_policy_signon",
+	"okta_template_sms":              "okta_template_sms",
+	"okta_trusted_origin":            "okta_trusted_origin",
+	"okta_user":                      "okta_user",
+	"okta_user_schema":               "okta_user_schema",
+	"okta_user_type":                 "okta_user_type",
+}
@@ -0,0 +1,110 @@
+package terraformerCLI
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+// OktaScanner implements the Scanner interface for use with Okta organizations.
+type OktaScanner struct {
+	// Config is the needed configuration of a mapping between Division name and the corresponding
+	// Credential needed to access that environment.
+	config map[terraformValueObjects.Division]terraformValueObjects.Credential
+
+	// terraformer is the TerraformerCLI interface used to scan the Okta organization.
+	terraformer TerraformerCLI
+}
+
+// NewOktaScanner creates and returns a new instance of OktaScanner.
+func NewOktaScanner(config map[terraformValueObjects.Division]terraformValueObjects.Credential, cliConfig Config) (Scanner, error) {
+	return &OktaScanner{
+		config:      config,
+		terraformer: newTerraformerCLI(cliConfig),
+	}, nil
+}
+
+// OktaEnvironment represents the configuration to run terraformer for Okta
+type OktaEnvironment struct {
+	OrgName  string `json:"okta_org_name"`
+	BaseURL  string `json:"okta_base_url"`
+	APIToken string `json:"okta_api_token"`
+}
+
+// ScanAll wraps Scan to scan each division for the provider.
+func (oktaScanner *OktaScanner) ScanAll(ctx context.Context, options ...string) (*MultiScanResult, error) {
+	fmt.Println("Scanning all specified Okta divisions.")
+	scanMap := make(map[terraformValueObjects.Division]terraformValueObjects.Path)
+
+	for division, credential := range oktaScanner.config {
+		path, err := oktaScanner.Scan(ctx, division, credential)
+		if err != nil {
+			return nil, fmt.Errorf("[ScanAll] Error in oktaScanner.Scan: %v", err)
+		}
+		scanMap[division] = path
+	}
+
+	return &MultiScanResult{scanMap}, nil
+}
+
+// Scan uses the TerraformerCLI interface to scan a given division's Okta organization. Okta resources are not
+// regional, so no regions are passed to terraformer.
+func (oktaScanner *OktaScanner) Scan(ctx context.Context, organization terraformValueObjects.Division, credential terraformValueObjects.Credential, options ...string) (terraformValueObjects.Path, error) {
+	err := oktaScanner.configureEnvironment(credential)
+	if err != nil {
+		return "", fmt.Errorf("[Okta Scanner] Error configuring environment %w", err)
+	}
+
+	path, err := oktaScanner.terraformer.Import(ctx, TerraformImportMigrationGeneratorParams{
+		Provider:       "okta",
+		Division:       organization,
+		Resources:      []string{},
+		AdditionalArgs: []string{},
+		Regions:        []string{},
+		IsCompact:      true,
+	})
+
+	if err != nil {
+		return "", fmt.Errorf("[Scan] Error in terraformer.Import(): %v", err)
+	}
+
+	err = oktaScanner.terraformer.UpdateState(ctx, "okta", string(path))
+
+	if err != nil {
+		return "", fmt.Errorf("[Scan] Error in terraformer.UpdateState(): %v", err)
+	}
+
+	return path, nil
+}
+
+// configureEnvironment exports the division's organization and API token, read by both terraformer and the Okta
+// Terraform provider. The base url defaults to okta.com when not specified.
+func (oktaScanner *OktaScanner) configureEnvironment(credential terraformValueObjects.Credential) error {
+	env := new(OktaEnvironment)
+	err := json.Unmarshal([]byte(credential), &env)
+	if err != nil {
+		return fmt.Errorf("[okta_scanner][configure_environment][error unmarshalling credentials] %w", err)
+	}
+
+	baseURL := env.BaseURL
+	if baseURL == "" {
+		baseURL = "okta.com"
+	}
+
+	environment := map[string]string{
+		"OKTA_ORG_NAME":  env.OrgName,
+		"OKTA_BASE_URL":  baseURL,
+		"OKTA_API_TOKEN": env.APIToken,
+	}
+	for variable, value := range environment {
+		err = os.Setenv(variable, value)
+		if err != nil {
+			return fmt.Errorf("[okta_scanner][configure_environment][error setting %v] %w", variable, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package terraformerCLI
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+func TestOktaScanner_configureEnvironment(t *testing.T) {
+	// Given
+	scanner := OktaScanner{}
+	for _, variable := range []string{"OKTA_ORG_NAME", "OKTA_BASE_URL", "OKTA_API_TOKEN"} {
+		t.Setenv(variable, "")
+	}
+	credentials := terraformValueObjects.Credential(`{"okta_org_name": "my-org", "okta_api_token": "api-token"}`)
+
+	// When
+	err := scanner.configureEnvironment(credentials)
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, "my-org", os.Getenv("OKTA_ORG_NAME"))
+	assert.Equal(t, "okta.com", os.Getenv("OKTA_BASE_URL"))
+	assert.Equal(t, "api-token", os.Getenv("OKTA_API_TOKEN"))
+}
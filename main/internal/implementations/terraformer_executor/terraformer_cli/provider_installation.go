@@ -0,0 +1,250 @@
+package terraformerCLI
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	log "github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+// ErrProviderNotMirrored is returned when a required provider is missing from the local plugin mirror.
+var ErrProviderNotMirrored = errors.New("[required provider not found within the plugin mirror]")
+
+// ErrNoPluginMirror is returned when running network isolated without a plugin mirror or bundled plugins.
+var ErrNoPluginMirror = errors.New("[network isolated mode requires a plugin mirror directory or bundled plugins]")
+
+// ErrTerraformVersionNotInstalled is returned when running network isolated and the pre-installed terraform binary
+// is not of the requested version.
+var ErrTerraformVersionNotInstalled = errors.New("[requested terraform version is not installed]")
+
+// bundledPluginDirectory is the provider filesystem mirror pre-seeded within the container image at build time.
+var bundledPluginDirectory = "/terraform-plugins/"
+
+// terraformCLIConfigFileName is the name of the Terraform CLI configuration file written when a plugin mirror or
+// plugin cache is configured.
+const terraformCLIConfigFileName = "cloud-concierge.tfrc"
+
+// configureProviderInstallation points all terraform and terraformer invocations at the configured plugin mirror
+// and plugin cache, by writing a Terraform CLI configuration file and exporting TF_CLI_CONFIG_FILE. When a
+// mirror is configured, providers are never downloaded from the network.
+func (e *TerraformerExecutor) configureProviderInstallation() error {
+	mirrorDirectory, err := e.pluginMirrorDirectory()
+	if err != nil {
+		return fmt.Errorf("[configure_provider_installation]%w", err)
+	}
+
+	if mirrorDirectory == "" && e.config.PluginCacheDirectory == "" {
+		return nil
+	}
+
+	if mirrorDirectory != "" {
+		err := validateMirroredProviders(mirrorDirectory, e.config.Providers)
+		if err != nil {
+			return fmt.Errorf("[configure_provider_installation]%w", err)
+		}
+	}
+
+	if e.config.PluginCacheDirectory != "" {
+		err := os.MkdirAll(e.config.PluginCacheDirectory, 0755)
+		if err != nil {
+			return fmt.Errorf("[configure_provider_installation][error creating plugin cache directory]%w", err)
+		}
+	}
+
+	cliConfigPath := filepath.Join(os.TempDir(), terraformCLIConfigFileName)
+	err = os.WriteFile(cliConfigPath, terraformCLIConfig(mirrorDirectory, e.config.PluginCacheDirectory), 0600)
+	if err != nil {
+		return fmt.Errorf("[configure_provider_installation][error writing terraform cli configuration]%w", err)
+	}
+
+	err = os.Setenv("TF_CLI_CONFIG_FILE", cliConfigPath)
+	if err != nil {
+		return fmt.Errorf("[configure_provider_installation][error setting TF_CLI_CONFIG_FILE]%w", err)
+	}
+
+	err = os.Setenv("CHECKPOINT_DISABLE", "1")
+	if err != nil {
+		return fmt.Errorf("[configure_provider_installation][error setting CHECKPOINT_DISABLE]%w", err)
+	}
+
+	return nil
+}
+
+// pluginMirrorDirectory returns the provider filesystem mirror from which providers are installed. The configured
+// mirror takes precedence, and when network isolated the plugins bundled within the image are used otherwise.
+func (e *TerraformerExecutor) pluginMirrorDirectory() (string, error) {
+	if e.config.PluginMirrorDirectory != "" || !e.config.NetworkIsolated {
+		return e.config.PluginMirrorDirectory, nil
+	}
+
+	if _, err := os.Stat(bundledPluginDirectory); err != nil {
+		return "", fmt.Errorf("[plugin_mirror_directory][%v]%w", bundledPluginDirectory, ErrNoPluginMirror)
+	}
+
+	return bundledPluginDirectory, nil
+}
+
+// validateInstalledTerraformVersion checks that the terraform binary on the PATH is of the requested version.
+func validateInstalledTerraformVersion(version string) error {
+	cmd := exec.Command("terraform", "version", "-json")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("[validate_installed_terraform_version][error in running 'terraform version']%w", err)
+	}
+
+	installedVersion, err := parseTerraformVersion(out.Bytes())
+	if err != nil {
+		return fmt.Errorf("[validate_installed_terraform_version]%w", err)
+	}
+
+	if installedVersion != version {
+		return fmt.Errorf(
+			"[validate_installed_terraform_version][requested %v, found %v]%w",
+			version, installedVersion, ErrTerraformVersionNotInstalled,
+		)
+	}
+
+	log.Infof("[validate_installed_terraform_version] using pre-installed terraform %v", installedVersion)
+	return nil
+}
+
+// parseTerraformVersion extracts the terraform version from the output of `terraform version -json`.
+func parseTerraformVersion(output []byte) (string, error) {
+	versionOutput := struct {
+		TerraformVersion string `json:"terraform_version"`
+	}{}
+
+	err := json.Unmarshal(output, &versionOutput)
+	if err != nil {
+		return "", fmt.Errorf("[parse_terraform_version][error unmarshalling 'terraform version' output]%w", err)
+	}
+
+	return versionOutput.TerraformVersion, nil
+}
+
+// terraformCLIConfig returns the contents of a Terraform CLI configuration file which installs providers exclusively
+// from mirrorDirectory, and caches them within cacheDirectory. Empty values leave the corresponding setting unset.
+func terraformCLIConfig(mirrorDirectory string, cacheDirectory string) []byte {
+	file := hclwrite.NewEmptyFile()
+	body := file.Body()
+
+	if cacheDirectory != "" {
+		body.SetAttributeValue("plugin_cache_dir", cty.StringVal(cacheDirectory))
+	}
+
+	if mirrorDirectory != "" {
+		installationBody := body.AppendNewBlock("provider_installation", nil).Body()
+		mirrorBody := installationBody.AppendNewBlock("filesystem_mirror", nil).Body()
+		mirrorBody.SetAttributeValue("path", cty.StringVal(mirrorDirectory))
+		mirrorBody.SetAttributeValue("include", cty.ListVal([]cty.Value{cty.StringVal("registry.terraform.io/*/*")}))
+	}
+
+	return file.Bytes()
+}
+
+// validateMirroredProviders checks that each required provider has at least one version for the current
+// platform satisfying its version constraint within the plugin mirror, in either the packed or unpacked filesystem
+// mirror layout.
+func validateMirroredProviders(mirrorDirectory string, providers map[terraformValueObjects.Provider]string) error {
+	platform := fmt.Sprintf("%v_%v", runtime.GOOS, runtime.GOARCH)
+
+	for provider, versionConstraint := range providers {
+		providerDirectory := filepath.Join(mirrorDirectory, "registry.terraform.io", provider.Namespace(), string(provider))
+
+		versions, err := mirroredProviderVersions(providerDirectory, string(provider), platform)
+		if err != nil {
+			return fmt.Errorf("[validate_mirrored_providers][error reading %v]%w", providerDirectory, err)
+		}
+
+		versions, err = satisfyingVersions(versions, versionConstraint)
+		if err != nil {
+			return fmt.Errorf("[validate_mirrored_providers][%v]%w", provider, err)
+		}
+
+		if len(versions) == 0 {
+			return fmt.Errorf(
+				"[validate_mirrored_providers][%v %v for %v within %v]%w",
+				provider, versionConstraint, platform, mirrorDirectory, ErrProviderNotMirrored,
+			)
+		}
+
+		log.Infof("[validate_mirrored_providers] found %v versions %v for constraint %v", provider, strings.Join(versions, ","), versionConstraint)
+	}
+
+	return nil
+}
+
+// satisfyingVersions returns those of versions which satisfy versionConstraint, with any version not parsable
+// as a semantic version ignored. An empty constraint is satisfied by every version.
+func satisfyingVersions(versions []string, versionConstraint string) ([]string, error) {
+	if strings.TrimSpace(versionConstraint) == "" {
+		return versions, nil
+	}
+
+	constraints, err := version.NewConstraint(versionConstraint)
+	if err != nil {
+		return nil, fmt.Errorf("[satisfying_versions][error parsing constraint %v]%w", versionConstraint, err)
+	}
+
+	satisfying := make([]string, 0)
+	for _, mirroredVersion := range versions {
+		parsed, err := version.NewVersion(mirroredVersion)
+		if err != nil {
+			log.Warnf("[satisfying_versions] ignoring mirrored version %v: %v", mirroredVersion, err)
+			continue
+		}
+
+		if constraints.Check(parsed) {
+			satisfying = append(satisfying, mirroredVersion)
+		}
+	}
+
+	return satisfying, nil
+}
+
+// mirroredProviderVersions lists the versions of a provider available for platform within providerDirectory.
+func mirroredProviderVersions(providerDirectory string, provider string, platform string) ([]string, error) {
+	entries, err := os.ReadDir(providerDirectory)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+
+	packedPrefix := fmt.Sprintf("terraform-provider-%v_", provider)
+	packedSuffix := fmt.Sprintf("_%v.zip", platform)
+
+	versions := make([]string, 0)
+	for _, entry := range entries {
+		name := entry.Name()
+
+		if entry.IsDir() {
+			if _, err := os.Stat(filepath.Join(providerDirectory, name, platform)); err == nil {
+				versions = append(versions, name)
+			}
+			continue
+		}
+
+		if strings.HasPrefix(name, packedPrefix) && strings.HasSuffix(name, packedSuffix) {
+			versions = append(versions, strings.TrimSuffix(strings.TrimPrefix(name, packedPrefix), packedSuffix))
+		}
+	}
+
+	return versions, nil
+}
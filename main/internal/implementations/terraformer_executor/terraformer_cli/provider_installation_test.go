@@ -0,0 +1,117 @@
+package terraformerCLI
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+func TestTerraformCLIConfig(t *testing.T) {
+	// When
+	output := terraformCLIConfig("/mirror", "/cache")
+
+	// Then
+	expectedOutput := "plugin_cache_dir = \"/cache\"\n" +
+		"provider_installation {\n" +
+		"  filesystem_mirror {\n" +
+		"    path    = \"/mirror\"\n" +
+		"    include = [\"registry.terraform.io/*/*\"]\n" +
+		"  }\n" +
+		"}\n"
+	assert.Equal(t, expectedOutput, string(output))
+}
+
+func TestValidateMirroredProviders(t *testing.T) {
+	// Given
+	mirrorDirectory := t.TempDir()
+	platform := fmt.Sprintf("%v_%v", runtime.GOOS, runtime.GOARCH)
+
+	awsDirectory := filepath.Join(mirrorDirectory, "registry.terraform.io", "hashicorp", "aws")
+	require.NoError(t, os.MkdirAll(filepath.Join(awsDirectory, "4.59.0", platform), 0755))
+
+	googleDirectory := filepath.Join(mirrorDirectory, "registry.terraform.io", "hashicorp", "google")
+	require.NoError(t, os.MkdirAll(googleDirectory, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(googleDirectory, fmt.Sprintf("terraform-provider-google_4.27.0_%v.zip", platform)), []byte{}, 0600))
+
+	// Then
+	assert.NoError(t, validateMirroredProviders(mirrorDirectory, map[terraformValueObjects.Provider]string{
+		"aws":    "~>4.59.0",
+		"google": "~>4.27.0",
+	}))
+
+	err := validateMirroredProviders(mirrorDirectory, map[terraformValueObjects.Provider]string{"azurerm": "~>3.0.0"})
+	assert.True(t, errors.Is(err, ErrProviderNotMirrored))
+
+	err = validateMirroredProviders(mirrorDirectory, map[terraformValueObjects.Provider]string{"aws": "~>5.0.0"})
+	assert.True(t, errors.Is(err, ErrProviderNotMirrored))
+
+	err = validateMirroredProviders(mirrorDirectory, map[terraformValueObjects.Provider]string{"aws": "not a constraint"})
+	assert.Error(t, err)
+}
+
+func TestSatisfyingVersions(t *testing.T) {
+	// Given
+	versions := []string{"4.27.0", "4.59.0", "4.60.1", "5.1.0", "latest"}
+
+	// When
+	satisfying, err := satisfyingVersions(versions, ">= 4.59.0, < 5.0.0")
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, []string{"4.59.0", "4.60.1"}, satisfying)
+
+	satisfying, err = satisfyingVersions(versions, "")
+	require.NoError(t, err)
+	assert.Equal(t, versions, satisfying)
+}
+
+func TestPluginMirrorDirectory(t *testing.T) {
+	// Given
+	defaultBundledPluginDirectory := bundledPluginDirectory
+	defer func() { bundledPluginDirectory = defaultBundledPluginDirectory }()
+	bundledPluginDirectory = t.TempDir()
+
+	configured := &TerraformerExecutor{config: TerraformerExecutorConfig{PluginMirrorDirectory: "/mirror/", NetworkIsolated: true}}
+	bundled := &TerraformerExecutor{config: TerraformerExecutorConfig{NetworkIsolated: true}}
+	online := &TerraformerExecutor{config: TerraformerExecutorConfig{}}
+
+	// Then
+	mirrorDirectory, err := configured.pluginMirrorDirectory()
+	require.NoError(t, err)
+	assert.Equal(t, "/mirror/", mirrorDirectory)
+
+	mirrorDirectory, err = bundled.pluginMirrorDirectory()
+	require.NoError(t, err)
+	assert.Equal(t, bundledPluginDirectory, mirrorDirectory)
+
+	mirrorDirectory, err = online.pluginMirrorDirectory()
+	require.NoError(t, err)
+	assert.Equal(t, "", mirrorDirectory)
+
+	bundledPluginDirectory = filepath.Join(bundledPluginDirectory, "missing")
+	_, err = bundled.pluginMirrorDirectory()
+	assert.True(t, errors.Is(err, ErrNoPluginMirror))
+}
+
+func TestParseTerraformVersion(t *testing.T) {
+	// Given
+	output := []byte(`{"terraform_version": "1.5.0", "platform": "linux_amd64", "provider_selections": {}, "terraform_outdated": false}`)
+
+	// When
+	version, err := parseTerraformVersion(output)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, "1.5.0", version)
+
+	_, err = parseTerraformVersion([]byte("Terraform v1.5.0"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,271 @@
+package terraformerCLI
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+// importByResourceGroup runs `terraformer import` once per resource group, so that a failing group does not prevent
+// the remaining groups from being imported, and so that the memory used by each invocation is bounded by a single
+// group. Up to the configured import concurrency groups are imported at once. When resuming, groups imported by a
+// previous run with the same scan configuration, identified by fingerprint, are restored rather than re-imported.
+// The outputs of the successful groups are merged into outputDirectory. Returns the resource groups that failed to
+// import.
+func (tfrCLI *terraformerCLI) importByResourceGroup(
+	ctx context.Context, params TerraformImportMigrationGeneratorParams, outputDirectory string, fingerprint string,
+) ([]string, error) {
+	groupsDirectory := fmt.Sprintf("%s-resource-groups", outputDirectory)
+	defer os.RemoveAll(groupsDirectory)
+
+	entryName := fmt.Sprintf("%s-%v", params.Provider, params.Division)
+
+	groups := tfrCLI.getImportResourceGroups(params.Provider)
+	groupErrors := make([]error, len(groups))
+
+	concurrency := tfrCLI.config.ImportConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	slots := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for i, group := range groups {
+		wg.Add(1)
+		slots <- struct{}{}
+
+		go func(i int, group string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			groupErrors[i] = tfrCLI.importResourceGroup(ctx, params, filepath.Join(groupsDirectory, group), group, entryName, fingerprint)
+		}(i, group)
+	}
+	wg.Wait()
+
+	failedGroups := make([]string, 0)
+	groupDirectories := make([]string, 0)
+	for i, group := range groups {
+		if groupErrors[i] != nil {
+			log.Errorf("[import_by_resource_group] skipping resource group %v: %v", group, groupErrors[i])
+			failedGroups = append(failedGroups, group)
+			continue
+		}
+		groupDirectories = append(groupDirectories, filepath.Join(groupsDirectory, group))
+	}
+
+	if len(groupDirectories) == 0 {
+		return nil, fmt.Errorf("[import_by_resource_group][all resource groups failed to import: %v]", strings.Join(failedGroups, ","))
+	}
+
+	err := mergeTerraformerOutputs(groupDirectories, outputDirectory)
+	if err != nil {
+		return nil, fmt.Errorf("[import_by_resource_group]%w", err)
+	}
+
+	if len(failedGroups) > 0 {
+		log.Warnf("[import_by_resource_group] resource groups that failed to import: %v", strings.Join(failedGroups, ","))
+	} else {
+		err = tfrCLI.config.ScanProgress.complete(entryName, fingerprint)
+		if err != nil {
+			return nil, fmt.Errorf("[import_by_resource_group]%w", err)
+		}
+	}
+
+	return failedGroups, nil
+}
+
+// importResourceGroup imports a single resource group into groupDirectory, restoring the output persisted by a
+// previous run when resuming, and otherwise persisting the output once imported.
+func (tfrCLI *terraformerCLI) importResourceGroup(
+	ctx context.Context, params TerraformImportMigrationGeneratorParams, groupDirectory string, group string, entryName string, fingerprint string,
+) error {
+	restored, err := tfrCLI.config.ScanProgress.restoreGroup(entryName, fingerprint, group, groupDirectory)
+	if err != nil {
+		return err
+	}
+	if restored {
+		return nil
+	}
+
+	args := tfrCLI.getImportArgs(params, groupDirectory, []string{fmt.Sprintf("--resources=%s", group)})
+	err = tfrCLI.executeCommandWithRetries(ctx, tfrCLI.limiter, "terraformer", args...)
+	if err != nil {
+		return err
+	}
+
+	err = tfrCLI.config.ScanProgress.storeGroup(entryName, fingerprint, group, groupDirectory)
+	if err != nil {
+		log.Warnf("[import_resource_group] unable to persist the progress of %v for %v: %v", group, entryName, err)
+	}
+	return nil
+}
+
+// getImportResourceGroups returns the terraformer resource groups to import for a provider, honoring the resource
+// black and white lists.
+func (tfrCLI *terraformerCLI) getImportResourceGroups(provider string) []string {
+	if len(tfrCLI.config.ResourcesWhiteList) > 0 && len(tfrCLI.config.ResourcesBlackList) == 0 {
+		return uniqueSortedGroups(tfrCLI.getGroupListByResourceNames(tfrCLI.config.ResourcesWhiteList))
+	}
+
+	excludedGroups := map[string]bool{}
+	for _, group := range tfrCLI.getGroupListByResourceNames(tfrCLI.config.ResourcesBlackList) {
+		excludedGroups[group] = true
+	}
+
+	groups := make([]string, 0)
+	for _, group := range providerResourceGroups(provider) {
+		if !excludedGroups[group] {
+			groups = append(groups, group)
+		}
+	}
+	return uniqueSortedGroups(groups)
+}
+
+// providerResourceGroupTable returns the terraformer resource group of each resource type known for a provider.
+func providerResourceGroupTable(provider string) map[terraformValueObjects.ResourceName]string {
+	switch provider {
+	case "aws":
+		return awsResourceGroups
+	case "google":
+		return googleResourceGroups
+	case "azurerm":
+		return azureResourceGroups
+	case "datadog":
+		return datadogResourceGroups
+	case "oci":
+		return ociResourceGroups
+	case "digitalocean":
+		return digitalOceanResourceGroups
+	case "okta":
+		return oktaResourceGroups
+	}
+	return map[terraformValueObjects.ResourceName]string{}
+}
+
+// providerResourceGroups returns all terraformer resource groups known for a provider.
+func providerResourceGroups(provider string) []string {
+	groups := make([]string, 0)
+	for _, group := range providerResourceGroupTable(provider) {
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// uniqueSortedGroups de-duplicates and sorts resource groups, dropping empty values.
+func uniqueSortedGroups(groups []string) []string {
+	groupSet := map[string]bool{}
+	for _, group := range groups {
+		if group != "" {
+			groupSet[group] = true
+		}
+	}
+
+	uniqueGroups := make([]string, 0)
+	for group := range groupSet {
+		uniqueGroups = append(uniqueGroups, group)
+	}
+	sort.Strings(uniqueGroups)
+	return uniqueGroups
+}
+
+// mergeTerraformerOutputs combines the terraformer outputs within groupDirectories into outputDirectory. State file
+// resources are concatenated, provider.tf is written once, and all other files are appended to one another.
+func mergeTerraformerOutputs(groupDirectories []string, outputDirectory string) error {
+	err := os.RemoveAll(outputDirectory)
+	if err != nil {
+		return fmt.Errorf("[merge_terraformer_outputs][error removing %v]%w", outputDirectory, err)
+	}
+
+	err = os.MkdirAll(outputDirectory, 0755)
+	if err != nil {
+		return fmt.Errorf("[merge_terraformer_outputs][error creating %v]%w", outputDirectory, err)
+	}
+
+	var mergedState map[string]interface{}
+	mergedFiles := map[string][]byte{}
+	for _, groupDirectory := range groupDirectories {
+		entries, err := os.ReadDir(groupDirectory)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return fmt.Errorf("[merge_terraformer_outputs][error reading %v]%w", groupDirectory, err)
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+
+			content, err := os.ReadFile(filepath.Join(groupDirectory, entry.Name()))
+			if err != nil {
+				return fmt.Errorf("[merge_terraformer_outputs][error reading %v]%w", entry.Name(), err)
+			}
+
+			switch entry.Name() {
+			case "terraform.tfstate":
+				mergedState, err = mergeStateResources(mergedState, content)
+				if err != nil {
+					return fmt.Errorf("[merge_terraformer_outputs][error merging state of %v]%w", groupDirectory, err)
+				}
+			case "provider.tf":
+				if _, ok := mergedFiles[entry.Name()]; !ok {
+					mergedFiles[entry.Name()] = content
+				}
+			default:
+				mergedFiles[entry.Name()] = append(mergedFiles[entry.Name()], append([]byte("\n"), content...)...)
+			}
+		}
+	}
+
+	for fileName, content := range mergedFiles {
+		err = os.WriteFile(filepath.Join(outputDirectory, fileName), content, 0600)
+		if err != nil {
+			return fmt.Errorf("[merge_terraformer_outputs][error writing %v]%w", fileName, err)
+		}
+	}
+
+	if mergedState != nil {
+		stateBytes, err := json.MarshalIndent(mergedState, "", "  ")
+		if err != nil {
+			return fmt.Errorf("[merge_terraformer_outputs][json.MarshalIndent]%w", err)
+		}
+
+		err = os.WriteFile(filepath.Join(outputDirectory, "terraform.tfstate"), stateBytes, 0600)
+		if err != nil {
+			return fmt.Errorf("[merge_terraformer_outputs][error writing terraform.tfstate]%w", err)
+		}
+	}
+
+	return nil
+}
+
+// mergeStateResources appends the resources of stateBytes to those of mergedState. When mergedState is nil,
+// the parsed state is returned as is.
+func mergeStateResources(mergedState map[string]interface{}, stateBytes []byte) (map[string]interface{}, error) {
+	var state map[string]interface{}
+	err := json.Unmarshal(stateBytes, &state)
+	if err != nil {
+		return nil, fmt.Errorf("[json.Unmarshal]%w", err)
+	}
+
+	if mergedState == nil {
+		return state, nil
+	}
+
+	mergedResources, _ := mergedState["resources"].([]interface{})
+	resources, _ := state["resources"].([]interface{})
+	mergedState["resources"] = append(mergedResources, resources...)
+
+	return mergedState, nil
+}
@@ -0,0 +1,109 @@
+package terraformerCLI
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+func TestGetImportResourceGroups(t *testing.T) {
+	// Given
+	whiteListed := &terraformerCLI{config: Config{
+		ResourcesWhiteList: terraformValueObjects.ResourceNameList{"aws_s3_bucket", "aws_lb", "aws_lb_listener"},
+	}}
+	blackListed := &terraformerCLI{config: Config{
+		ResourcesBlackList: terraformValueObjects.ResourceNameList{"aws_s3_bucket"},
+	}}
+
+	// Then
+	assert.Equal(t, []string{"alb", "s3"}, whiteListed.getImportResourceGroups("aws"))
+
+	groups := blackListed.getImportResourceGroups("aws")
+	assert.NotContains(t, groups, "s3")
+	assert.Contains(t, groups, "alb")
+}
+
+func TestMergeTerraformerOutputs(t *testing.T) {
+	// Given
+	root := t.TempDir()
+	firstGroup := filepath.Join(root, "groups", "s3")
+	secondGroup := filepath.Join(root, "groups", "alb")
+	missingGroup := filepath.Join(root, "groups", "ec2_instance")
+	outputDirectory := filepath.Join(root, "aws-division")
+
+	require.NoError(t, os.MkdirAll(firstGroup, 0755))
+	require.NoError(t, os.MkdirAll(secondGroup, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(firstGroup, "provider.tf"), []byte(`provider "aws" {}`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(secondGroup, "provider.tf"), []byte(`provider "aws" {}`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(firstGroup, "resources.tf"), []byte(`resource "aws_s3_bucket" "tfer--logs" {}`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(secondGroup, "resources.tf"), []byte(`resource "aws_lb" "tfer--web" {}`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(firstGroup, "terraform.tfstate"), []byte(`{"version": 4, "resources": [{"type": "aws_s3_bucket", "name": "tfer--logs"}]}`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(secondGroup, "terraform.tfstate"), []byte(`{"version": 4, "resources": [{"type": "aws_lb", "name": "tfer--web"}]}`), 0600))
+
+	// When
+	err := mergeTerraformerOutputs([]string{firstGroup, secondGroup, missingGroup}, outputDirectory)
+
+	// Then
+	require.NoError(t, err)
+
+	providerContent, err := os.ReadFile(filepath.Join(outputDirectory, "provider.tf"))
+	require.NoError(t, err)
+	assert.Equal(t, `provider "aws" {}`, string(providerContent))
+
+	resourcesContent, err := os.ReadFile(filepath.Join(outputDirectory, "resources.tf"))
+	require.NoError(t, err)
+	assert.Contains(t, string(resourcesContent), `resource "aws_s3_bucket" "tfer--logs" {}`)
+	assert.Contains(t, string(resourcesContent), `resource "aws_lb" "tfer--web" {}`)
+
+	stateContent, err := os.ReadFile(filepath.Join(outputDirectory, "terraform.tfstate"))
+	require.NoError(t, err)
+	state := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(stateContent, &state))
+	assert.Len(t, state["resources"], 2)
+	assert.Equal(t, float64(4), state["version"])
+}
+
+func TestImportByResourceGroup(t *testing.T) {
+	// Given
+	binDirectory := t.TempDir()
+	fakeTerraformer := `#!/bin/sh
+for arg in "$@"; do
+  case "$arg" in
+    --path-output=*) output="${arg#--path-output=}" ;;
+    --resources=*) group="${arg#--resources=}" ;;
+  esac
+done
+if [ "$group" = "s3" ]; then exit 1; fi
+mkdir -p "$output"
+echo "{\"version\": 4, \"resources\": [{\"type\": \"$group\", \"name\": \"tfer--$group\"}]}" > "$output/terraform.tfstate"
+`
+	require.NoError(t, os.WriteFile(filepath.Join(binDirectory, "terraformer"), []byte(fakeTerraformer), 0700))
+	t.Setenv("PATH", binDirectory+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	outputDirectory := filepath.Join(t.TempDir(), "aws-division")
+	tfrCLI := &terraformerCLI{config: Config{
+		ResourcesWhiteList: terraformValueObjects.ResourceNameList{"aws_s3_bucket", "aws_lb", "aws_instance"},
+		ChunkedImport:      true,
+		ImportConcurrency:  2,
+	}}
+
+	// When
+	failedGroups, err := tfrCLI.importByResourceGroup(context.Background(), TerraformImportMigrationGeneratorParams{Provider: "aws"}, outputDirectory, "fingerprint")
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, []string{"s3"}, failedGroups)
+
+	stateContent, err := os.ReadFile(filepath.Join(outputDirectory, "terraform.tfstate"))
+	require.NoError(t, err)
+	state := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(stateContent, &state))
+	assert.Len(t, state["resources"], 2)
+}
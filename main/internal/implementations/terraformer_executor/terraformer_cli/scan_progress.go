@@ -0,0 +1,369 @@
+package terraformerCLI
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	runStateStore "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/run_state_store"
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
+)
+
+// scanProgressKeyPrefix is the run state store key prefix under which per resource group import progress is stored.
+const scanProgressKeyPrefix = "scan-progress"
+
+// scanFingerprint calculates a fingerprint of the groups of arguments used to run terraformer for a division.
+func scanFingerprint(argGroups ...[]string) string {
+	hash := sha256.New()
+	for _, args := range argGroups {
+		hash.Write([]byte(strings.Join(args, "\n")))
+		hash.Write([]byte("\n"))
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// ScanProgress tracks the resource groups imported for each division. With a run state store, the output of each
+// imported resource group is persisted so that an interrupted or partially failed scan resumes from the groups that
+// have not yet been imported. The resource groups that failed to import within the current run are recorded for the
+// report.
+type ScanProgress struct {
+	// store persists the imported resource groups between runs, nil when resuming is disabled.
+	store interfaces.RunStateStore
+
+	// maxAge is the maximum age of persisted progress before a division is scanned from scratch.
+	maxAge time.Duration
+
+	// mu guards the manifests, failedResourceGroups and scanScopes, as resource groups may be imported concurrently.
+	mu sync.Mutex
+
+	// manifests are the progress manifests loaded for each division within the current run.
+	manifests map[string]*scanProgressManifest
+
+	// failedResourceGroups are the resource groups that failed to import within the current run, keyed by
+	// division, region and resource group.
+	failedResourceGroups map[failedResourceGroupKey]FailedResourceGroup
+
+	// scanScopes maps each division scanned within the current run, by full provider-division name, to the extent
+	// of its scan.
+	scanScopes map[string]driftDetector.ScanScope
+}
+
+// scanProgressManifest lists the resource groups of a division whose import output has been persisted.
+type scanProgressManifest struct {
+	// Fingerprint is the fingerprint of the scan configuration with which the resource groups were imported.
+	Fingerprint string `json:"fingerprint"`
+
+	// UpdatedAt is the time at which a resource group was last persisted.
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	// CompletedGroups are the resource groups whose import output has been persisted.
+	CompletedGroups []string `json:"completedGroups"`
+}
+
+// FailedResourceGroup is a resource group that failed to import within a region of a division.
+type FailedResourceGroup struct {
+	// Division is the full provider-division name, e.g. aws-prod.
+	Division string `json:"division"`
+
+	// Region is the region within which the resource group failed to import, empty when the provider's resources
+	// are not scanned by region.
+	Region string `json:"region"`
+
+	// ResourceGroup is the terraformer resource group, e.g. s3.
+	ResourceGroup string `json:"resourceGroup"`
+
+	// ResourceTypes are the resource types within the resource group.
+	ResourceTypes []string `json:"resourceTypes"`
+}
+
+// failedResourceGroupKey identifies a FailedResourceGroup.
+type failedResourceGroupKey struct {
+	division      string
+	region        string
+	resourceGroup string
+}
+
+// NewScanProgress creates a ScanProgress persisting progress within store, which may be nil to only record the
+// resource groups that fail to import.
+func NewScanProgress(store interfaces.RunStateStore, maxAge time.Duration) *ScanProgress {
+	return &ScanProgress{
+		store:                store,
+		maxAge:               maxAge,
+		manifests:            map[string]*scanProgressManifest{},
+		failedResourceGroups: map[failedResourceGroupKey]FailedResourceGroup{},
+		scanScopes:           map[string]driftDetector.ScanScope{},
+	}
+}
+
+// canResume returns true if the imported resource groups are persisted between runs.
+func (p *ScanProgress) canResume() bool {
+	return p != nil && p.store != nil
+}
+
+// restoreGroup writes the persisted import output of a resource group into groupDirectory, if the group was
+// imported by a previous run with the same scan configuration. Returns true if the output was restored.
+func (p *ScanProgress) restoreGroup(entryName string, fingerprint string, group string, groupDirectory string) (bool, error) {
+	if !p.canResume() {
+		return false, nil
+	}
+
+	manifest, err := p.manifest(entryName, fingerprint)
+	if err != nil {
+		return false, fmt.Errorf("[scan_progress][restore_group]%w", err)
+	}
+
+	if !containsString(manifest.CompletedGroups, group) {
+		return false, nil
+	}
+
+	data, err := p.store.Get(context.Background(), p.groupKey(entryName, group))
+	if err != nil {
+		if errors.Is(err, runStateStore.ErrNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("[scan_progress][restore_group][error getting %v of %v]%w", group, entryName, err)
+	}
+
+	files := map[string][]byte{}
+	err = json.Unmarshal(data, &files)
+	if err != nil {
+		return false, fmt.Errorf("[scan_progress][restore_group][error unmarshalling %v of %v]%w", group, entryName, err)
+	}
+
+	err = os.MkdirAll(groupDirectory, 0755)
+	if err != nil {
+		return false, fmt.Errorf("[scan_progress][restore_group][error creating %v]%w", groupDirectory, err)
+	}
+
+	for fileName, content := range files {
+		err = os.WriteFile(filepath.Join(groupDirectory, fileName), content, 0600)
+		if err != nil {
+			return false, fmt.Errorf("[scan_progress][restore_group][error writing %v]%w", fileName, err)
+		}
+	}
+
+	log.Infof("[scan_progress] resuming with the previously imported resource group %v of %v", group, entryName)
+	return true, nil
+}
+
+// storeGroup persists the import output of a resource group within groupDirectory and marks the group as completed.
+func (p *ScanProgress) storeGroup(entryName string, fingerprint string, group string, groupDirectory string) error {
+	if !p.canResume() {
+		return nil
+	}
+
+	files, err := readGroupFiles(groupDirectory)
+	if err != nil {
+		return fmt.Errorf("[scan_progress][store_group]%w", err)
+	}
+
+	data, err := json.Marshal(files)
+	if err != nil {
+		return fmt.Errorf("[scan_progress][store_group][json.Marshal]%w", err)
+	}
+
+	err = p.store.Put(context.Background(), p.groupKey(entryName, group), data)
+	if err != nil {
+		return fmt.Errorf("[scan_progress][store_group][error putting %v of %v]%w", group, entryName, err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	manifest := p.manifests[entryName]
+	if manifest == nil {
+		manifest = &scanProgressManifest{Fingerprint: fingerprint, CompletedGroups: []string{}}
+		p.manifests[entryName] = manifest
+	}
+	if !containsString(manifest.CompletedGroups, group) {
+		manifest.CompletedGroups = append(manifest.CompletedGroups, group)
+		sort.Strings(manifest.CompletedGroups)
+	}
+	manifest.UpdatedAt = time.Now()
+
+	return p.putManifest(entryName, manifest)
+}
+
+// complete clears the persisted progress of a division once all of its resource groups have been imported, so that
+// the next run scans the division from scratch.
+func (p *ScanProgress) complete(entryName string, fingerprint string) error {
+	if !p.canResume() {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	manifest := &scanProgressManifest{Fingerprint: fingerprint, UpdatedAt: time.Now(), CompletedGroups: []string{}}
+	p.manifests[entryName] = manifest
+
+	err := p.putManifest(entryName, manifest)
+	if err != nil {
+		return fmt.Errorf("[scan_progress][complete]%w", err)
+	}
+	return nil
+}
+
+// recordFailedGroups records the resource groups of provider that failed to import within the regions of the
+// division named entryName, e.g. aws-prod, within the current run. Groups of providers whose resources are not
+// scanned by region are recorded without a region.
+func (p *ScanProgress) recordFailedGroups(provider string, entryName string, regions []string, groups []string) {
+	if p == nil || len(groups) == 0 {
+		return
+	}
+
+	failedRegions := scopeRegions(provider, regions)
+	if len(failedRegions) == 0 {
+		failedRegions = []string{""}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, region := range failedRegions {
+		for _, group := range groups {
+			p.failedResourceGroups[failedResourceGroupKey{entryName, region, group}] = FailedResourceGroup{
+				Division:      entryName,
+				Region:        region,
+				ResourceGroup: group,
+				ResourceTypes: scannedResourceTypes(provider, []string{group}, nil),
+			}
+		}
+	}
+}
+
+// FailedResourceGroups returns the resource groups that failed to import within the current run, sorted by
+// division, region and resource group.
+func (p *ScanProgress) FailedResourceGroups() []FailedResourceGroup {
+	failedResourceGroups := make([]FailedResourceGroup, 0)
+	if p == nil {
+		return failedResourceGroups
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, failedResourceGroup := range p.failedResourceGroups {
+		failedResourceGroups = append(failedResourceGroups, failedResourceGroup)
+	}
+
+	sort.Slice(failedResourceGroups, func(i, j int) bool {
+		a, b := failedResourceGroups[i], failedResourceGroups[j]
+		if a.Division != b.Division {
+			return a.Division < b.Division
+		}
+		if a.Region != b.Region {
+			return a.Region < b.Region
+		}
+		return a.ResourceGroup < b.ResourceGroup
+	})
+	return failedResourceGroups
+}
+
+// manifest returns the progress manifest of entryName, loading it from the store on first use. Progress that was
+// recorded with a different scan configuration, or that is older than the maximum age, is discarded.
+func (p *ScanProgress) manifest(entryName string, fingerprint string) (*scanProgressManifest, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if manifest, ok := p.manifests[entryName]; ok {
+		return manifest, nil
+	}
+
+	manifest := &scanProgressManifest{Fingerprint: fingerprint, CompletedGroups: []string{}}
+	data, err := p.store.Get(context.Background(), p.manifestKey(entryName))
+	if err != nil && !errors.Is(err, runStateStore.ErrNotFound) {
+		return nil, fmt.Errorf("[error getting the manifest of %v]%w", entryName, err)
+	}
+
+	if err == nil {
+		storedManifest := &scanProgressManifest{}
+		err = json.Unmarshal(data, storedManifest)
+		if err != nil {
+			return nil, fmt.Errorf("[error unmarshalling the manifest of %v]%w", entryName, err)
+		}
+
+		switch {
+		case storedManifest.Fingerprint != fingerprint:
+			log.Infof("[scan_progress] scan configuration changed for %v, scanning from scratch", entryName)
+		case p.maxAge > 0 && time.Since(storedManifest.UpdatedAt) > p.maxAge:
+			log.Infof("[scan_progress] progress of %v is stale, scanning from scratch", entryName)
+		default:
+			manifest = storedManifest
+		}
+	}
+
+	p.manifests[entryName] = manifest
+	return manifest, nil
+}
+
+// putManifest stores the progress manifest of entryName. Callers must hold mu.
+func (p *ScanProgress) putManifest(entryName string, manifest *scanProgressManifest) error {
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("[json.Marshal]%w", err)
+	}
+
+	err = p.store.Put(context.Background(), p.manifestKey(entryName), data)
+	if err != nil {
+		return fmt.Errorf("[error putting the manifest of %v]%w", entryName, err)
+	}
+	return nil
+}
+
+// manifestKey is the run state store key of the progress manifest of entryName.
+func (p *ScanProgress) manifestKey(entryName string) string {
+	return fmt.Sprintf("%v/%v/manifest.json", scanProgressKeyPrefix, entryName)
+}
+
+// groupKey is the run state store key of the persisted import output of a resource group of entryName.
+func (p *ScanProgress) groupKey(entryName string, group string) string {
+	return fmt.Sprintf("%v/%v/groups/%v.json", scanProgressKeyPrefix, entryName, group)
+}
+
+// readGroupFiles reads the regular files within the import output of a resource group.
+func readGroupFiles(groupDirectory string) (map[string][]byte, error) {
+	files := map[string][]byte{}
+
+	entries, err := os.ReadDir(groupDirectory)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return files, nil
+		}
+		return nil, fmt.Errorf("[error reading %v]%w", groupDirectory, err)
+	}
+
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(groupDirectory, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("[error reading %v]%w", entry.Name(), err)
+		}
+		files[entry.Name()] = content
+	}
+
+	return files, nil
+}
+
+// containsString returns true if value is within values.
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package terraformerCLI
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	runStateStore "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/run_state_store"
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+)
+
+func TestScanProgress_ResumeResourceGroup(t *testing.T) {
+	// Given
+	store := runStateStore.NewLocalRunStateStore(runStateStore.Config{Directory: t.TempDir()})
+	groupDirectory := filepath.Join(t.TempDir(), "s3")
+	require.NoError(t, os.MkdirAll(groupDirectory, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(groupDirectory, "terraform.tfstate"), []byte(`{"version": 4}`), 0600))
+
+	firstRun := NewScanProgress(store, time.Hour)
+	require.NoError(t, firstRun.storeGroup("aws-prod", "fingerprint", "s3", groupDirectory))
+
+	// When
+	secondRun := NewScanProgress(store, time.Hour)
+	restoredDirectory := filepath.Join(t.TempDir(), "s3")
+	restored, err := secondRun.restoreGroup("aws-prod", "fingerprint", "s3", restoredDirectory)
+
+	// Then
+	require.NoError(t, err)
+	assert.True(t, restored)
+	content, err := os.ReadFile(filepath.Join(restoredDirectory, "terraform.tfstate"))
+	require.NoError(t, err)
+	assert.Equal(t, `{"version": 4}`, string(content))
+
+	restored, err = secondRun.restoreGroup("aws-prod", "fingerprint", "alb", t.TempDir())
+	require.NoError(t, err)
+	assert.False(t, restored)
+
+	changedConfiguration := NewScanProgress(store, time.Hour)
+	restored, err = changedConfiguration.restoreGroup("aws-prod", "changed-fingerprint", "s3", t.TempDir())
+	require.NoError(t, err)
+	assert.False(t, restored)
+
+	require.NoError(t, secondRun.complete("aws-prod", "fingerprint"))
+	completedRun := NewScanProgress(store, time.Hour)
+	restored, err = completedRun.restoreGroup("aws-prod", "fingerprint", "s3", t.TempDir())
+	require.NoError(t, err)
+	assert.False(t, restored)
+}
+
+func TestScanProgress_FailedResourceGroups(t *testing.T) {
+	// Given
+	progress := NewScanProgress(nil, 0)
+	var disabled *ScanProgress
+
+	// When
+	progress.recordFailedGroups("aws", "aws-prod", []string{"us-east-1"}, []string{"sqs"})
+	progress.recordFailedGroups("aws", "aws-prod", []string{"eu-west-1"}, []string{"sqs"})
+	progress.recordFailedGroups("aws", "aws-prod", []string{"us-east-1"}, []string{"sqs"})
+	progress.recordFailedGroups("google", "google-dev", []string{"us-east1"}, []string{"pubsub"})
+	progress.recordFailedGroups("aws", "aws-dev", []string{"us-east-1"}, []string{})
+	disabled.recordFailedGroups("aws", "aws-prod", []string{"us-east-1"}, []string{"sqs"})
+
+	// Then
+	assert.Equal(t, []FailedResourceGroup{
+		{Division: "aws-prod", Region: "eu-west-1", ResourceGroup: "sqs", ResourceTypes: []string{"aws_sqs_queue"}},
+		{Division: "aws-prod", Region: "us-east-1", ResourceGroup: "sqs", ResourceTypes: []string{"aws_sqs_queue"}},
+		{Division: "google-dev", Region: "", ResourceGroup: "pubsub", ResourceTypes: []string{"google_pubsub_subscription", "google_pubsub_topic"}},
+	}, progress.FailedResourceGroups())
+	assert.Empty(t, disabled.FailedResourceGroups())
+
+	restored, err := progress.restoreGroup("aws-prod", "fingerprint", "s3", t.TempDir())
+	require.NoError(t, err)
+	assert.False(t, restored)
+}
+
+func TestScannedResourceTypes_ExcludesFailedGroups(t *testing.T) {
+	// When
+	resourceTypes := scannedResourceTypes("aws", []string{"accessanalyzer", "acm"}, []string{"acm"})
+
+	// Then
+	assert.Equal(t, []string{"aws_accessanalyzer_analyzer"}, resourceTypes)
+}
+
+func TestScopeRegions_OnlyBoundsRegionalProviders(t *testing.T) {
+	// When / Then
+	assert.Equal(t, []string{"us-east-1"}, scopeRegions("aws", []string{"us-east-1"}))
+	assert.Equal(t, []string{}, scopeRegions("google", []string{"us-east4", "global"}))
+}
+
+func TestScanFingerprint(t *testing.T) {
+	// When
+	fingerprint := scanFingerprint([]string{"import", "aws", "--resources=*"}, []string{"id-exclusion=^tmp"})
+
+	// Then
+	assert.Equal(t, fingerprint, scanFingerprint([]string{"import", "aws", "--resources=*"}, []string{"id-exclusion=^tmp"}))
+	assert.NotEqual(t, fingerprint, scanFingerprint([]string{"import", "aws", "--resources=vpc"}, []string{"id-exclusion=^tmp"}))
+	assert.NotEqual(t, fingerprint, scanFingerprint([]string{"import", "aws", "--resources=*"}, []string{}))
+}
+
+func TestScanProgress_recordScanScope(t *testing.T) {
+	// Given
+	progress := NewScanProgress(nil, 0)
+
+	// When
+	progress.recordScanScope("oci-tenancy", []string{"uk-london-1"}, []string{"oci_core_subnet", "oci_core_vcn"})
+	progress.recordScanScope("oci-tenancy", []string{"eu-frankfurt-1"}, []string{"oci_core_vcn"})
+
+	// Then
+	assert.Equal(t, map[string]driftDetector.ScanScope{
+		"oci-tenancy": {Regions: []string{"uk-london-1", "eu-frankfurt-1"}, ResourceTypes: []string{"oci_core_vcn"}},
+	}, progress.ScanScopes())
+}
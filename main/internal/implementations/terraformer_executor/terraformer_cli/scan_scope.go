@@ -0,0 +1,102 @@
+package terraformerCLI
+
+import (
+	"sort"
+
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+// divisionScanScopesPath is the path, relative to current_cloud/, of the file listing the resource types and regions
+// successfully scanned within each division, so that resources outside of them are not reported as deleted.
+const divisionScanScopesPath = "../mappings/division-scan-scopes.json"
+
+// recordScanScope records the resource types and regions successfully scanned within the division named
+// entryName, e.g. aws-prod. When the division is scanned once per region, the regions of each scan are combined,
+// and only the resource types scanned within every region are kept.
+func (p *ScanProgress) recordScanScope(entryName string, regions []string, resourceTypes []string) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	scope, ok := p.scanScopes[entryName]
+	if !ok {
+		p.scanScopes[entryName] = driftDetector.ScanScope{Regions: append([]string{}, regions...), ResourceTypes: resourceTypes}
+		return
+	}
+
+	for _, region := range regions {
+		if !containsString(scope.Regions, region) {
+			scope.Regions = append(scope.Regions, region)
+		}
+	}
+
+	commonResourceTypes := make([]string, 0)
+	for _, resourceType := range scope.ResourceTypes {
+		if containsString(resourceTypes, resourceType) {
+			commonResourceTypes = append(commonResourceTypes, resourceType)
+		}
+	}
+	scope.ResourceTypes = commonResourceTypes
+	p.scanScopes[entryName] = scope
+}
+
+// ScanScopes returns the extent of the scan of each division scanned within the current run, keyed by the full
+// provider-division name.
+func (p *ScanProgress) ScanScopes() map[string]driftDetector.ScanScope {
+	scanScopes := map[string]driftDetector.ScanScope{}
+	if p == nil {
+		return scanScopes
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for entryName, scope := range p.scanScopes {
+		scanScopes[entryName] = scope
+	}
+	return scanScopes
+}
+
+// scannedResourceTypes returns the sorted resource types of provider within groups, excluding failedGroups.
+func scannedResourceTypes(provider string, groups []string, failedGroups []string) []string {
+	scannedGroups := map[string]bool{}
+	for _, group := range groups {
+		scannedGroups[group] = true
+	}
+	for _, group := range failedGroups {
+		delete(scannedGroups, group)
+	}
+
+	resourceTypes := make([]string, 0)
+	for resourceType, group := range providerResourceGroupTable(provider) {
+		if scannedGroups[group] {
+			resourceTypes = append(resourceTypes, string(resourceType))
+		}
+	}
+	sort.Strings(resourceTypes)
+	return resourceTypes
+}
+
+// regionalScopeProviders are the providers whose scanned regions bound the resources they scan. The regions passed
+// to terraformer for other providers do not correspond to the regions of their resources.
+var regionalScopeProviders = map[string]bool{"aws": true, "oci": true}
+
+// scopeRegions returns the regions bounding the resources scanned for provider with the passed terraformer regions.
+func scopeRegions(provider string, regions []string) []string {
+	if !regionalScopeProviders[provider] {
+		return []string{}
+	}
+	return regions
+}
+
+// resourceTypeNames converts resource names to their string form.
+func resourceTypeNames(resourceNames []terraformValueObjects.ResourceName) []string {
+	names := make([]string, 0, len(resourceNames))
+	for _, resourceName := range resourceNames {
+		names = append(names, string(resourceName))
+	}
+	return names
+}
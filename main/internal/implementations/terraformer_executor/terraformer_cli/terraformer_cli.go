@@ -1,15 +1,17 @@
 package terraformerCLI
 
 import (
-	"bytes"
+	"context"
 	"fmt"
-	"os/exec"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/ratelimit"
 )
 
 // TerraformImportMigrationGeneratorParams is the struct that wraps the params to run terraform import statement
@@ -28,11 +30,11 @@ type TerraformerCLI interface {
 
 	// Import runs the `terraformer import` command to import all resources for the specified provider, division,
 	// and specified credentials.
-	Import(params TerraformImportMigrationGeneratorParams) (terraformValueObjects.Path, error)
+	Import(ctx context.Context, params TerraformImportMigrationGeneratorParams) (terraformValueObjects.Path, error)
 
 	// UpdateState runs the `terraform state replace-provider` command to upgrade the state file generated
 	// to version 4.
-	UpdateState(provider string, location string) error
+	UpdateState(ctx context.Context, provider string, location string) error
 }
 
 // Config is the struct that contains parameters considered to import the resources
@@ -42,63 +44,267 @@ type Config struct {
 
 	// ResourcesBlackList represents the list of resource names that will be excluded from consideration for inclusion in the import statement.
 	ResourcesBlackList terraformValueObjects.ResourceNameList
+
+	// ResourceTagInclusions are the tag selectors that a resource must match to be imported. Translated
+	// into terraformer `--filter` arguments.
+	ResourceTagInclusions terraformValueObjects.ResourceTagSelectors
+
+	// ResourceTagExclusions are the tag selectors for which matching resources are pruned from the
+	// terraformer output after import.
+	ResourceTagExclusions terraformValueObjects.ResourceTagSelectors
+
+	// ResourceIDExclusions are the regular expressions for which resources with a matching cloud id are pruned
+	// from the terraformer output after import.
+	ResourceIDExclusions terraformValueObjects.ResourcePatternList
+
+	// ResourceNameExclusions are the regular expressions for which resources with a matching cloud name are pruned
+	// from the terraformer output after import.
+	ResourceNameExclusions terraformValueObjects.ResourcePatternList
+
+	// CommandTimeout is the maximum duration of a single terraformer or terraform invocation. Zero disables the limit.
+	CommandTimeout time.Duration
+
+	// CommandMaxRetries is the number of times a failed terraformer or terraform invocation is retried.
+	CommandMaxRetries int
+
+	// CommandRetryBackoff is the wait before the first retry of a failed invocation, doubled on each subsequent retry.
+	CommandRetryBackoff time.Duration
+
+	// ContinueOnPartialFailure flags that, when a division's import fails, each resource group is imported
+	// individually and the groups that succeed are kept.
+	ContinueOnPartialFailure bool
+
+	// ChunkedImport flags that each resource group, i.e. each cloud service, is imported by a separate terraformer
+	// invocation and the outputs merged, capping the memory used when scanning large divisions.
+	ChunkedImport bool
+
+	// ImportConcurrency is the number of resource groups imported at once when importing by resource group.
+	ImportConcurrency int
+
+	// ResumeScans flags that the output of each imported resource group is persisted within the run state store,
+	// so that a division whose scan was interrupted or partially failed resumes from the groups not yet imported.
+	ResumeScans bool
+
+	// ResumeMaxAge is the maximum age of persisted progress before a division is scanned from scratch.
+	ResumeMaxAge time.Duration
+
+	// ScanProgress tracks the resource groups imported for each division, persisting them between runs when
+	// resuming. Shared by the scanners of every provider, and set by NewTerraformerExecutor.
+	ScanProgress *ScanProgress
+
+	// RateLimit limits the terraformer invocations made against cloud provider APIs. When enabled, each resource
+	// group is imported by a separate, rate limited, invocation.
+	RateLimit ratelimit.Config
 }
 
 // terraformerCLI implements the TerraformerCLI interface.
 type terraformerCLI struct {
 	// config is the struct that contains parameters considered to import the resources such the black and white resources list
 	config Config
+
+	// limiter rate limits terraformer invocations, nil when rate limiting is disabled.
+	limiter *ratelimit.Limiter
 }
 
 // newTerraformerCLI creates a new instance of the terraformerCLI struct.
 func newTerraformerCLI(config Config) TerraformerCLI {
-	return &terraformerCLI{config: config}
+	return &terraformerCLI{
+		config:  config,
+		limiter: ratelimit.New(config.RateLimit),
+	}
+}
+
+// runImport runs terraformer for a division, returning the resource groups that failed to import. When chunked
+// imports, rate limiting or resuming are enabled, each resource group is imported by its own invocation, bounding
+// memory usage, spreading out the calls made against the provider's APIs, and allowing progress to be persisted.
+func (tfrCLI *terraformerCLI) runImport(
+	ctx context.Context, params TerraformImportMigrationGeneratorParams, outputDirectory string, args []string, entryName string, fingerprint string,
+) ([]string, error) {
+	if tfrCLI.config.ChunkedImport || tfrCLI.config.RateLimit.IsEnabled() || tfrCLI.config.ScanProgress.canResume() {
+		failedGroups, err := tfrCLI.importByResourceGroup(ctx, params, outputDirectory, fingerprint)
+		if err != nil {
+			return nil, fmt.Errorf("[runImport] Error in importing by resource group: %v", err)
+		}
+		if len(failedGroups) > 0 && !tfrCLI.config.ContinueOnPartialFailure {
+			return nil, fmt.Errorf("[runImport] Error in importing resource groups %v for %v", failedGroups, entryName)
+		}
+		return failedGroups, nil
+	}
+
+	log.Infof("Terraformer ARGS: %s", args)
+	err := tfrCLI.executeCommandWithRetries(ctx, tfrCLI.limiter, "terraformer", args...)
+	if err == nil {
+		return []string{}, nil
+	}
+
+	if !tfrCLI.config.ContinueOnPartialFailure {
+		return nil, fmt.Errorf("[runImport] Error in running 'terraformer import': %v", err)
+	}
+
+	log.Warnf("[runImport] 'terraformer import' failed for %v, importing each resource group individually: %v", entryName, err)
+	failedGroups, err := tfrCLI.importByResourceGroup(ctx, params, outputDirectory, fingerprint)
+	if err != nil {
+		return nil, fmt.Errorf("[runImport] Error in importing by resource group: %v", err)
+	}
+	return failedGroups, nil
 }
 
 // Import runs the `terraformer import` command.
-func (tfrCLI *terraformerCLI) Import(params TerraformImportMigrationGeneratorParams) (terraformValueObjects.Path, error) {
-	divisionOutput := fmt.Sprintf("--path-output=./%s-%v", params.Provider, params.Division)
+func (tfrCLI *terraformerCLI) Import(ctx context.Context, params TerraformImportMigrationGeneratorParams) (terraformValueObjects.Path, error) {
+	outputDirectory := fmt.Sprintf("./%s-%v", params.Provider, params.Division)
+	args := tfrCLI.getImportArgs(params, outputDirectory, tfrCLI.getResourceArgs())
+	path := terraformValueObjects.Path(fmt.Sprintf("./%s-%v/", params.Provider, params.Division))
+	entryName := fmt.Sprintf("%s-%v", params.Provider, params.Division)
+	fingerprint := scanFingerprint(args, tfrCLI.exclusionFingerprintArgs())
+
+	failedGroups, err := tfrCLI.runImport(ctx, params, outputDirectory, args, entryName, fingerprint)
+	if err != nil {
+		return "", err
+	}
+	tfrCLI.config.ScanProgress.recordFailedGroups(params.Provider, entryName, params.Regions, failedGroups)
+	tfrCLI.config.ScanProgress.recordScanScope(
+		entryName, scopeRegions(params.Provider, params.Regions),
+		scannedResourceTypes(params.Provider, tfrCLI.getImportResourceGroups(params.Provider), failedGroups),
+	)
+
+	if tfrCLI.hasResourceExclusions() {
+		tagPrefix := getTagAttributePrefix(params.Provider)
+		err = pruneImportedResources(path, func(resource importedResource) bool {
+			return tfrCLI.isResourceExcluded(tagPrefix, resource)
+		})
+		if err != nil {
+			return "", fmt.Errorf("[Import] Error in pruning excluded resources: %v", err)
+		}
+	}
+
+	return path, nil
+}
 
+// getImportArgs returns the arguments of a `terraformer import` command writing to outputDirectory and
+// scoped by resourceArgs.
+func (tfrCLI *terraformerCLI) getImportArgs(params TerraformImportMigrationGeneratorParams, outputDirectory string, resourceArgs []string) []string {
 	importProvider := getActualImportProvider(params.Provider)
-	mainArgs := []string{
+	args := []string{
 		"import", importProvider,
 		fmt.Sprintf("--compact=%s", strconv.FormatBool(params.IsCompact)),
-		divisionOutput,
+		fmt.Sprintf("--path-output=%s", outputDirectory),
 		"--path-pattern={output}",
 	}
 
 	if len(params.Regions) > 0 {
 		regions := strings.Join(params.Regions, ",")
-		mainArgs = append(mainArgs, fmt.Sprintf("--regions=%s", regions))
+		args = append(args, fmt.Sprintf("--regions=%s", regions))
 	}
 
+	args = append(args, resourceArgs...)
+	args = append(args, tfrCLI.getTagFilterArgs(params.Provider)...)
+
+	return append(args, params.AdditionalArgs...)
+}
+
+// getResourceArgs returns the `--resources` and `--excludes` arguments derived from the resource black and white lists.
+func (tfrCLI *terraformerCLI) getResourceArgs() []string {
 	if len(tfrCLI.config.ResourcesBlackList) > 0 {
 		resourceGroups := tfrCLI.getGroupListByResourceNames(tfrCLI.config.ResourcesBlackList)
 
 		if len(resourceGroups) > 0 {
 			excludes := strings.Join(resourceGroups, ",")
-			mainArgs = append(mainArgs, fmt.Sprintf("--excludes=%s", excludes))
-			mainArgs = append(mainArgs, "--resources=*")
+			return []string{fmt.Sprintf("--excludes=%s", excludes), "--resources=*"}
 		}
 	} else if len(tfrCLI.config.ResourcesWhiteList) > 0 {
 		resourceGroups := tfrCLI.getGroupListByResourceNames(tfrCLI.config.ResourcesWhiteList)
 
 		if len(resourceGroups) > 0 {
-			resources := strings.Join(tfrCLI.getGroupListByResourceNames(tfrCLI.config.ResourcesWhiteList), ",")
-			mainArgs = append(mainArgs, fmt.Sprintf("--resources=%s", resources))
+			return []string{fmt.Sprintf("--resources=%s", strings.Join(resourceGroups, ","))}
 		}
 	} else {
-		mainArgs = append(mainArgs, "--resources=*")
+		return []string{"--resources=*"}
 	}
 
-	args := append(mainArgs, params.AdditionalArgs...)
-	log.Infof("Terraformer ARGS: %s", args)
-	err := executeCommand("terraformer", args...)
+	return []string{}
+}
 
-	if err != nil {
-		return "", fmt.Errorf("[Import] Error in running 'terraformer import': %v", err)
+// exclusionFingerprintArgs returns a representation of the post-import exclusion rules, so that changes to these
+// rules invalidate the persisted progress of interrupted scans.
+func (tfrCLI *terraformerCLI) exclusionFingerprintArgs() []string {
+	fingerprintArgs := make([]string, 0)
+
+	tagKeys := make([]string, 0)
+	for key := range tfrCLI.config.ResourceTagExclusions {
+		tagKeys = append(tagKeys, key)
 	}
-	return terraformValueObjects.Path(fmt.Sprintf("./%s-%v/", params.Provider, params.Division)), nil
+	sort.Strings(tagKeys)
+	for _, key := range tagKeys {
+		fingerprintArgs = append(fingerprintArgs, fmt.Sprintf("tag-exclusion=%v:%v", key, strings.Join(tfrCLI.config.ResourceTagExclusions[key], ",")))
+	}
+
+	for _, pattern := range tfrCLI.config.ResourceIDExclusions {
+		fingerprintArgs = append(fingerprintArgs, fmt.Sprintf("id-exclusion=%v", pattern.String()))
+	}
+
+	for _, pattern := range tfrCLI.config.ResourceNameExclusions {
+		fingerprintArgs = append(fingerprintArgs, fmt.Sprintf("name-exclusion=%v", pattern.String()))
+	}
+
+	return fingerprintArgs
+}
+
+// hasResourceExclusions returns true if any post-import resource exclusion rule is configured.
+func (tfrCLI *terraformerCLI) hasResourceExclusions() bool {
+	return len(tfrCLI.config.ResourceTagExclusions) > 0 ||
+		len(tfrCLI.config.ResourceIDExclusions) > 0 ||
+		len(tfrCLI.config.ResourceNameExclusions) > 0
+}
+
+// isResourceExcluded determines whether an imported resource matches any of the configured tag, id or
+// name exclusion rules.
+func (tfrCLI *terraformerCLI) isResourceExcluded(tagPrefix string, resource importedResource) bool {
+	if tfrCLI.config.ResourceTagExclusions.Matches(tagPrefix, resource.AttributesFlat) {
+		return true
+	}
+
+	if tfrCLI.config.ResourceIDExclusions.MatchesAny(resource.AttributesFlat["id"]) {
+		return true
+	}
+
+	return tfrCLI.config.ResourceNameExclusions.MatchesAny(
+		resource.AttributesFlat["name"],
+		resource.AttributesFlat[fmt.Sprintf("%v.Name", tagPrefix)],
+		strings.TrimPrefix(resource.Name, "tfer--"),
+	)
+}
+
+// getTagFilterArgs translates the configured tag inclusion selectors into terraformer `--filter` arguments.
+// Terraformer matches any of the ':' separated values for a given field path.
+func (tfrCLI *terraformerCLI) getTagFilterArgs(provider string) []string {
+	tagPrefix := getTagAttributePrefix(provider)
+
+	tagKeys := make([]string, 0)
+	for key := range tfrCLI.config.ResourceTagInclusions {
+		tagKeys = append(tagKeys, key)
+	}
+	sort.Strings(tagKeys)
+
+	filterArgs := make([]string, 0)
+	for _, key := range tagKeys {
+		values := strings.Join(tfrCLI.config.ResourceTagInclusions[key], ":")
+		filterArgs = append(filterArgs, fmt.Sprintf("--filter=Name=%v.%v;Value=%v", tagPrefix, key, values))
+	}
+
+	return filterArgs
+}
+
+// getTagAttributePrefix returns the name of the attribute in which a provider's resources store tags.
+func getTagAttributePrefix(provider string) string {
+	if provider == "google" {
+		return "labels"
+	}
+
+	if provider == "oci" {
+		return "freeform_tags"
+	}
+
+	return "tags"
 }
 
 func getActualImportProvider(provider string) string {
@@ -109,15 +315,15 @@ func getActualImportProvider(provider string) string {
 	return provider
 }
 
-func (tfrCLI *terraformerCLI) UpdateState(provider string, location string) error {
+func (tfrCLI *terraformerCLI) UpdateState(ctx context.Context, provider string, location string) error {
 	// Specify the location of the state file, as well as the from and to provider plug in values.
 	stateFlag := fmt.Sprintf("-state=%s/terraform.tfstate", location)
 	fromProvider := fmt.Sprintf("registry.terraform.io/-/%s", provider)
-	toProvider := fmt.Sprintf("hashicorp/%s", provider)
+	toProvider := fmt.Sprintf("%s/%s", terraformValueObjects.Provider(provider).Namespace(), provider)
 
 	args := []string{"state", "replace-provider", "-auto-approve", stateFlag, fromProvider, toProvider}
 
-	err := executeCommand("terraform", args...)
+	err := tfrCLI.executeCommandWithRetries(ctx, nil, "terraform", args...)
 	if err != nil {
 		return fmt.Errorf("[UpdateState] Error in running 'terraform state replace-provider': %v", err)
 	}
@@ -135,27 +341,20 @@ func (tfrCLI *terraformerCLI) getGroupListByResourceNames(list terraformValueObj
 		if resourceGroup == "" {
 			resourceGroup = azureResourceGroups[resourceName]
 		}
+		if resourceGroup == "" {
+			resourceGroup = datadogResourceGroups[resourceName]
+		}
+		if resourceGroup == "" {
+			resourceGroup = ociResourceGroups[resourceName]
+		}
+		if resourceGroup == "" {
+			resourceGroup = digitalOceanResourceGroups[resourceName]
+		}
+		if resourceGroup == "" {
+			resourceGroup = oktaResourceGroups[resourceName]
+		}
 		resourceGroups = append(resourceGroups, resourceGroup)
 	}
 
 	return resourceGroups
 }
-
-// executeCommand wraps os.exec.Command with capturing of std output and errors.
-func executeCommand(command string, args ...string) error {
-	cmd := exec.Command(command, args...)
-
-	// Setting up logging objects
-	var out bytes.Buffer
-	cmd.Stdout = &out
-
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-	err := cmd.Run()
-
-	if err != nil {
-		return fmt.Errorf("%v\n\n%v", err, stderr.String()+out.String())
-	}
-	fmt.Printf("\n%s Output:\n\n%v\n", command, out.String())
-	return nil
-}
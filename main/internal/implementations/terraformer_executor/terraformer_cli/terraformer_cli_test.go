@@ -129,3 +129,108 @@ func Test_terraformerCLI_getGroupListByResourceNames(t *testing.T) {
 		})
 	}
 }
+
+func Test_terraformerCLI_getTagFilterArgs(t *testing.T) {
+	type args struct {
+		provider string
+	}
+	tests := []struct {
+		name   string
+		config Config
+		args   args
+		want   []string
+	}{
+		{
+			name:   "Test with no tag inclusions",
+			config: Config{},
+			args:   args{provider: "aws"},
+			want:   []string{},
+		},
+		{
+			name: "Test with multiple aws tag inclusions",
+			config: Config{
+				ResourceTagInclusions: terraformValueObjects.ResourceTagSelectors{
+					"team": {"platform", "data"},
+					"env":  {"prod"},
+				},
+			},
+			args: args{provider: "aws"},
+			want: []string{
+				"--filter=Name=tags.env;Value=prod",
+				"--filter=Name=tags.team;Value=platform:data",
+			},
+		},
+		{
+			name: "Test with google label inclusions",
+			config: Config{
+				ResourceTagInclusions: terraformValueObjects.ResourceTagSelectors{
+					"env": {"prod"},
+				},
+			},
+			args: args{provider: "google"},
+			want: []string{
+				"--filter=Name=labels.env;Value=prod",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tfrCLI := &terraformerCLI{
+				config: tt.config,
+			}
+			assert.Equalf(t, tt.want, tfrCLI.getTagFilterArgs(tt.args.provider), "getTagFilterArgs(%v)", tt.args.provider)
+		})
+	}
+}
+
+func Test_terraformerCLI_isResourceExcluded(t *testing.T) {
+	idPatterns := terraformValueObjects.ResourcePatternList{}
+	_ = idPatterns.Decode(`["^i-0packer"]`)
+	namePatterns := terraformValueObjects.ResourcePatternList{}
+	_ = namePatterns.Decode(`["^packer-"]`)
+
+	tfrCLI := &terraformerCLI{
+		config: Config{
+			ResourceTagExclusions:  terraformValueObjects.ResourceTagSelectors{"env": {"sandbox"}},
+			ResourceIDExclusions:   idPatterns,
+			ResourceNameExclusions: namePatterns,
+		},
+	}
+
+	tests := []struct {
+		name     string
+		resource importedResource
+		want     bool
+	}{
+		{
+			name:     "Excluded by tag",
+			resource: importedResource{Name: "tfer--web", AttributesFlat: map[string]string{"id": "i-1", "tags.env": "sandbox"}},
+			want:     true,
+		},
+		{
+			name:     "Excluded by id",
+			resource: importedResource{Name: "tfer--web", AttributesFlat: map[string]string{"id": "i-0packer123"}},
+			want:     true,
+		},
+		{
+			name:     "Excluded by name tag",
+			resource: importedResource{Name: "tfer--i-1", AttributesFlat: map[string]string{"id": "i-1", "tags.Name": "packer-build"}},
+			want:     true,
+		},
+		{
+			name:     "Excluded by terraformer name",
+			resource: importedResource{Name: "tfer--packer-build", AttributesFlat: map[string]string{"id": "sg-1"}},
+			want:     true,
+		},
+		{
+			name:     "Not excluded",
+			resource: importedResource{Name: "tfer--web", AttributesFlat: map[string]string{"id": "i-1", "tags.env": "prod", "name": "web"}},
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equalf(t, tt.want, tfrCLI.isResourceExcluded("tags", tt.resource), "isResourceExcluded(%v)", tt.resource)
+		})
+	}
+}
@@ -3,17 +3,27 @@ package terraformerCLI
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/hclcreate"
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
 )
 
+// failedResourceGroupsPath is the path, relative to current_cloud/, of the file listing the resource groups that
+// failed to import, by division and region.
+const failedResourceGroupsPath = "../mappings/failed-resource-groups.json"
+
+// providerSchemasPath is the path, relative to current_cloud/, of the provider schemas used to exclude computed and
+// write-only attributes from drift comparison.
+const providerSchemasPath = "../mappings/provider-schemas.json"
+
 // TerraformerExecutorConfig is a struct containing the variables that determine the specific
 // behavior of the TerraformerExecutor.
 type TerraformerExecutorConfig struct {
@@ -28,6 +38,21 @@ type TerraformerExecutorConfig struct {
 
 	// CloudRegions represents the list of cloud regions that will be considered for inclusion in the import statement.
 	CloudRegions terraformValueObjects.CloudRegionsDecoder `required:"true"`
+
+	// PluginMirrorDirectory is a pre-populated provider filesystem mirror. When set, providers are installed
+	// exclusively from the mirror, allowing scans within network-restricted environments.
+	PluginMirrorDirectory string
+
+	// PluginCacheDirectory is the directory in which terraform caches installed providers.
+	PluginCacheDirectory string
+
+	// NetworkIsolated flags that the job runs without outbound registry access. Providers are installed from the
+	// plugin mirror, or the plugins bundled within the image, and the pre-installed terraform binary is used.
+	NetworkIsolated bool
+
+	// AWSNativeInventoryDivisions are the AWS divisions inventoried through the Cloud Control API rather than with
+	// terraformer.
+	AWSNativeInventoryDivisions []string
 }
 
 // TerraformerExecutor is a struct that implements interfaces.TerraformerExecutor
@@ -43,17 +68,30 @@ type TerraformerExecutor struct {
 
 	// config contains the variables that determine the specific behavior of the TerraformerExecutor
 	config TerraformerExecutorConfig
+
+	// scanProgress tracks the resource groups imported by every scanner.
+	scanProgress *ScanProgress
+
+	// artifactStore holds the mappings describing the extent of the scan, written for later pipeline stages.
+	artifactStore artifacts.Store
 }
 
-// NewTerraformerExecutor creates and returns a new instance of TerraformerExecutor.
-func NewTerraformerExecutor(ctx context.Context, hclCreate hclcreate.HCLCreate, dragonDrop interfaces.DragonDrop, config TerraformerExecutorConfig, cliConfig Config, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider) (interfaces.TerraformerExecutor, error) {
+// NewTerraformerExecutor creates and returns a new instance of TerraformerExecutor. When resuming scans, the
+// progress of each division is persisted within runStateStore.
+func NewTerraformerExecutor(ctx context.Context, hclCreate hclcreate.HCLCreate, dragonDrop interfaces.DragonDrop, config TerraformerExecutorConfig, cliConfig Config, divisionToProvider map[terraformValueObjects.Division]terraformValueObjects.Provider, runStateStore interfaces.RunStateStore, artifactStore artifacts.Store) (interfaces.TerraformerExecutor, error) {
+	var progressStore interfaces.RunStateStore
+	if cliConfig.ResumeScans {
+		progressStore = runStateStore
+	}
+	cliConfig.ScanProgress = NewScanProgress(progressStore, cliConfig.ResumeMaxAge)
+
 	scanners, err := getScanners(config, cliConfig, divisionToProvider)
 	if err != nil {
 		return nil, err
 	}
 
 	dragonDrop.PostLog(ctx, "Created TFExec.")
-	return &TerraformerExecutor{hclCreate: hclCreate, scanners: scanners, config: config, dragonDrop: dragonDrop}, nil
+	return &TerraformerExecutor{hclCreate: hclCreate, scanners: scanners, config: config, dragonDrop: dragonDrop, scanProgress: cliConfig.ScanProgress, artifactStore: artifactStore}, nil
 }
 
 // getScanners provisions all needed cloud environment scanners by Terraform provider to scan.
@@ -78,7 +116,7 @@ func getScanners(config TerraformerExecutorConfig, cliConfig Config, divisionToP
 			scanners[p] = googleScanner
 		case "aws":
 			awsScannerConfig := subsetMapOfDivisionToCredentials(config.DivisionCloudCredentials, divisionToProvider, p)
-			awsScanner, err := NewAWSScanner(awsScannerConfig, cliConfig, config.CloudRegions)
+			awsScanner, err := NewAWSScanner(awsScannerConfig, cliConfig, config.CloudRegions, config.AWSNativeInventoryDivisions)
 
 			if err != nil {
 				log.Errorf("[NewTerraformerExec] Error in NewAWSScanner(): %s", err.Error())
@@ -96,9 +134,49 @@ func getScanners(config TerraformerExecutorConfig, cliConfig Config, divisionToP
 			}
 
 			scanners[p] = azureScanner
+		case "datadog":
+			datadogScannerConfig := subsetMapOfDivisionToCredentials(config.DivisionCloudCredentials, divisionToProvider, p)
+			datadogScanner, err := NewDatadogScanner(datadogScannerConfig, cliConfig)
+
+			if err != nil {
+				log.Errorf("[NewTerraformerExec] Error in NewDatadogScanner(): %s", err.Error())
+				return nil, fmt.Errorf("[NewTerraformerExec] Error in NewDatadogScanner(): %w", err)
+			}
+
+			scanners[p] = datadogScanner
+		case "oci":
+			ociScannerConfig := subsetMapOfDivisionToCredentials(config.DivisionCloudCredentials, divisionToProvider, p)
+			ociScanner, err := NewOCIScanner(ociScannerConfig, cliConfig, config.CloudRegions)
+
+			if err != nil {
+				log.Errorf("[NewTerraformerExec] Error in NewOCIScanner(): %s", err.Error())
+				return nil, fmt.Errorf("[NewTerraformerExec] Error in NewOCIScanner(): %w", err)
+			}
+
+			scanners[p] = ociScanner
+		case "digitalocean":
+			digitalOceanScannerConfig := subsetMapOfDivisionToCredentials(config.DivisionCloudCredentials, divisionToProvider, p)
+			digitalOceanScanner, err := NewDigitalOceanScanner(digitalOceanScannerConfig, cliConfig)
+
+			if err != nil {
+				log.Errorf("[NewTerraformerExec] Error in NewDigitalOceanScanner(): %s", err.Error())
+				return nil, fmt.Errorf("[NewTerraformerExec] Error in NewDigitalOceanScanner(): %w", err)
+			}
+
+			scanners[p] = digitalOceanScanner
+		case "okta":
+			oktaScannerConfig := subsetMapOfDivisionToCredentials(config.DivisionCloudCredentials, divisionToProvider, p)
+			oktaScanner, err := NewOktaScanner(oktaScannerConfig, cliConfig)
+
+			if err != nil {
+				log.Errorf("[NewTerraformerExec] Error in NewOktaScanner(): %s", err.Error())
+				return nil, fmt.Errorf("[NewTerraformerExec] Error in NewOktaScanner(): %w", err)
+			}
+
+			scanners[p] = oktaScanner
 		default:
-			log.Errorf("currently only a scanner for [google, aws, azurerm] is supported. Specified %s", p)
-			return nil, fmt.Errorf("currently only a scanner for [google, aws, azurerm] is supported. Specified %s", p)
+			log.Errorf("currently only a scanner for [google, aws, azurerm, datadog, oci, digitalocean, okta] is supported. Specified %s", p)
+			return nil, fmt.Errorf("currently only a scanner for [google, aws, azurerm, datadog, oci, digitalocean, okta] is supported. Specified %s", p)
 		}
 	}
 
@@ -131,9 +209,14 @@ func subsetMapOfDivisionToCredentials(divisionCloudCredentials terraformValueObj
 // Execute runs the workflow needed to capture the current state of an
 // external cloud environment via the terraformer package.
 func (e *TerraformerExecutor) Execute(ctx context.Context) error {
+	err := e.configureProviderInstallation()
+	if err != nil {
+		return fmt.Errorf("[terraformer_executor][set_up][error configuring provider installation]%w", err)
+	}
+
 	e.dragonDrop.PostLog(ctx, "Beginning to make main.tf file.")
 
-	err := e.makeProviderVersionFile()
+	err = e.makeProviderVersionFile()
 	if err != nil {
 		return fmt.Errorf("[terraformer_executor][set_up][error making provider version file]%w", err)
 	}
@@ -152,15 +235,30 @@ func (e *TerraformerExecutor) Execute(ctx context.Context) error {
 		return fmt.Errorf("[terraformer_executor][set_up][error initializing terraform]%w", err)
 	}
 
+	err = e.writeProviderSchemas()
+	if err != nil {
+		log.Warnf("[terraformer_executor] drift will be compared without provider schemas: %v", err)
+	}
+
 	e.dragonDrop.PostLog(ctx, "Done with running `terraform init`.\n Beginning to scan existing cloud environment.")
 
-	err = e.scanAllProviders()
+	err = e.scanAllProviders(ctx)
 	if err != nil {
 		return fmt.Errorf("[terraformer_executor][set_up][error scanning all providers]%w", err)
 	}
 
 	e.dragonDrop.PostLog(ctx, "Executed terraformer scan.")
 
+	err = e.writeFailedResourceGroups()
+	if err != nil {
+		return fmt.Errorf("[terraformer_executor][set_up][error writing failed resource groups]%w", err)
+	}
+
+	err = e.writeScanScopes()
+	if err != nil {
+		return fmt.Errorf("[terraformer_executor][set_up][error writing scan scopes]%w", err)
+	}
+
 	err = e.dragonDrop.InformCloudEnvironmentScanned(ctx)
 	if err != nil {
 		return fmt.Errorf("[terraformer_executor][set_up][error informing cloud environment scanned]%w", err)
@@ -171,15 +269,15 @@ func (e *TerraformerExecutor) Execute(ctx context.Context) error {
 
 // scanAllProviders runs terraformer against all specified providers and all divisions,
 // within each provider.
-func (e *TerraformerExecutor) scanAllProviders() error {
+func (e *TerraformerExecutor) scanAllProviders(ctx context.Context) error {
 	scanOutput := make(map[terraformValueObjects.Provider]*MultiScanResult)
 
 	for provider, s := range e.scanners {
-		currentMultiScan, err := s.ScanAll()
+		currentMultiScan, err := s.ScanAll(ctx)
 
 		if err != nil {
 			return fmt.Errorf(
-				"[scan_all_providers][error in s.ScanAll() for provider %s]%w", provider, err,
+				"[scan_all_providers][error in s.ScanAll(ctx) for provider %s]%w", provider, err,
 			)
 		}
 		scanOutput[provider] = currentMultiScan
@@ -188,6 +286,65 @@ func (e *TerraformerExecutor) scanAllProviders() error {
 	return nil
 }
 
+// writeFailedResourceGroups writes the resource groups that failed to import within each division and region, so
+// that they are listed within the report. The executor runs within current_cloud/, so the file is written to the
+// sibling mappings directory.
+func (e *TerraformerExecutor) writeFailedResourceGroups() error {
+	failedResourceGroups := e.scanProgress.FailedResourceGroups()
+	for _, failedResourceGroup := range failedResourceGroups {
+		log.Warnf(
+			"[write_failed_resource_groups] resource group %v of %v failed to import within region %q",
+			failedResourceGroup.ResourceGroup, failedResourceGroup.Division, failedResourceGroup.Region,
+		)
+	}
+
+	failedResourceGroupsJSON, err := json.MarshalIndent(failedResourceGroups, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[write_failed_resource_groups][json.MarshalIndent]%w", err)
+	}
+
+	err = e.artifactStore.WriteFile(failedResourceGroupsPath, failedResourceGroupsJSON, 0400)
+	if err != nil {
+		return fmt.Errorf("[write_failed_resource_groups]%w", err)
+	}
+	return nil
+}
+
+// writeScanScopes writes the resource types and regions successfully scanned within each division, so that resources
+// outside of them are not reported as deleted.
+func (e *TerraformerExecutor) writeScanScopes() error {
+	scanScopesJSON, err := json.MarshalIndent(e.scanProgress.ScanScopes(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("[write_scan_scopes][json.MarshalIndent]%w", err)
+	}
+
+	err = e.artifactStore.WriteFile(divisionScanScopesPath, scanScopesJSON, 0400)
+	if err != nil {
+		return fmt.Errorf("[write_scan_scopes]%w", err)
+	}
+	return nil
+}
+
+// writeProviderSchemas writes the schemas of the initialized providers, as output by
+// `terraform providers schema -json`, for use in drift detection.
+func (e *TerraformerExecutor) writeProviderSchemas() error {
+	cmd := exec.Command("terraform", "providers", "schema", "-json")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("[write_provider_schemas][error in running 'terraform providers schema -json': %s]%w", stderr.String(), err)
+	}
+
+	err = e.artifactStore.WriteFile(providerSchemasPath, stdout.Bytes(), 0400)
+	if err != nil {
+		return fmt.Errorf("[write_provider_schemas]%w", err)
+	}
+	return nil
+}
+
 // initializeTerraform initializes Terraform within the current working directory.
 func (e *TerraformerExecutor) initializeTerraform() error {
 	err := os.Chdir("current_cloud/")
@@ -208,9 +365,14 @@ func (e *TerraformerExecutor) initializeTerraform() error {
 	return nil
 }
 
-// setTerraformVersion uses tfswitch to install the user-specified version of terraform
+// setTerraformVersion uses tfswitch to install the user-specified version of terraform. When network isolated,
+// the pre-installed terraform binary is instead checked to be of the user-specified version.
 func (e *TerraformerExecutor) setTerraformVersion() error {
 	tfVersion := string(e.config.TerraformVersion)
+	if e.config.NetworkIsolated {
+		return validateInstalledTerraformVersion(tfVersion)
+	}
+
 	cmd := exec.Command("tfswitch", tfVersion)
 	var out bytes.Buffer
 	cmd.Stdout = &out
@@ -1,6 +1,7 @@
 package terraformerCLI
 
 import (
+	"context"
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
 )
 
@@ -14,8 +15,8 @@ type Scanner interface {
 
 	// Scan uses the TerraformerCLI interface to scan a given division's cloud environment. Returns
 	// the name of the Division scanned, and the Stack of Terraformer output for that division.
-	Scan(division terraformValueObjects.Division, credential terraformValueObjects.Credential, options ...string) (terraformValueObjects.Path, error)
+	Scan(ctx context.Context, division terraformValueObjects.Division, credential terraformValueObjects.Credential, options ...string) (terraformValueObjects.Path, error)
 
 	// ScanAll wraps Scan to scan each division for the provider.
-	ScanAll(options ...string) (*MultiScanResult, error)
+	ScanAll(ctx context.Context, options ...string) (*MultiScanResult, error)
 }
@@ -0,0 +1,150 @@
+package terraformerCLI
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+// importedResource is a single resource instance generated by terraformer that is evaluated for pruning.
+type importedResource struct {
+	// Type is the Terraform resource type, e.g. aws_instance.
+	Type string
+
+	// Name is the terraformer generated resource name.
+	Name string
+
+	// AttributesFlat are the flattened attributes of the resource instance.
+	AttributesFlat map[string]string
+}
+
+// pruneImportedResources removes all resources for which isExcluded returns true from the terraformer
+// output located at path, keeping the terraform.tfstate and resources.tf files consistent.
+func pruneImportedResources(path terraformValueObjects.Path, isExcluded func(resource importedResource) bool) error {
+	statePath := fmt.Sprintf("%vterraform.tfstate", path)
+	stateBytes, err := os.ReadFile(statePath)
+	if err != nil {
+		return fmt.Errorf("[prune_imported_resources][error reading %v]%w", statePath, err)
+	}
+
+	prunedState, prunedResources, err := pruneStateResources(stateBytes, isExcluded)
+	if err != nil {
+		return fmt.Errorf("[prune_imported_resources][error pruning state resources]%w", err)
+	}
+
+	if len(prunedResources) == 0 {
+		return nil
+	}
+
+	err = os.WriteFile(statePath, prunedState, 0400)
+	if err != nil {
+		return fmt.Errorf("[prune_imported_resources][error writing %v]%w", statePath, err)
+	}
+
+	hclPath := fmt.Sprintf("%vresources.tf", path)
+	hclBytes, err := os.ReadFile(hclPath)
+	if err != nil {
+		return fmt.Errorf("[prune_imported_resources][error reading %v]%w", hclPath, err)
+	}
+
+	prunedHCL, err := pruneHCLResources(hclBytes, prunedResources)
+	if err != nil {
+		return fmt.Errorf("[prune_imported_resources][error pruning hcl resources]%w", err)
+	}
+
+	err = os.WriteFile(hclPath, prunedHCL, 0400)
+	if err != nil {
+		return fmt.Errorf("[prune_imported_resources][error writing %v]%w", hclPath, err)
+	}
+
+	return nil
+}
+
+// pruneStateResources removes the excluded resource instances from a terraformer state file, and returns the
+// pruned state file along with the set of "type.name" resource locations that were removed entirely.
+func pruneStateResources(stateBytes []byte, isExcluded func(resource importedResource) bool) ([]byte, map[string]bool, error) {
+	var state map[string]interface{}
+	err := json.Unmarshal(stateBytes, &state)
+	if err != nil {
+		return nil, nil, fmt.Errorf("[json.Unmarshal]%w", err)
+	}
+
+	rawResources, ok := state["resources"].([]interface{})
+	if !ok {
+		return stateBytes, map[string]bool{}, nil
+	}
+
+	prunedResources := map[string]bool{}
+	keptResources := make([]interface{}, 0)
+	for _, rawResource := range rawResources {
+		resource, ok := rawResource.(map[string]interface{})
+		if !ok {
+			keptResources = append(keptResources, rawResource)
+			continue
+		}
+		resourceType, _ := resource["type"].(string)
+		resourceName, _ := resource["name"].(string)
+
+		rawInstances, _ := resource["instances"].([]interface{})
+		keptInstances := make([]interface{}, 0)
+		for _, rawInstance := range rawInstances {
+			instance, _ := rawInstance.(map[string]interface{})
+			attributesFlat := map[string]string{}
+			if rawAttributes, ok := instance["attributes_flat"].(map[string]interface{}); ok {
+				for key, value := range rawAttributes {
+					attributesFlat[key] = fmt.Sprintf("%v", value)
+				}
+			}
+
+			if !isExcluded(importedResource{Type: resourceType, Name: resourceName, AttributesFlat: attributesFlat}) {
+				keptInstances = append(keptInstances, rawInstance)
+			}
+		}
+
+		if len(rawInstances) > 0 && len(keptInstances) == 0 {
+			prunedResources[fmt.Sprintf("%v.%v", resourceType, resourceName)] = true
+			continue
+		}
+		resource["instances"] = keptInstances
+		keptResources = append(keptResources, resource)
+	}
+
+	if len(prunedResources) == 0 {
+		return stateBytes, prunedResources, nil
+	}
+
+	state["resources"] = keptResources
+	prunedState, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return nil, nil, fmt.Errorf("[json.MarshalIndent]%w", err)
+	}
+
+	return prunedState, prunedResources, nil
+}
+
+// pruneHCLResources removes resource blocks whose "type.name" location is within prunedResources.
+func pruneHCLResources(hclBytes []byte, prunedResources map[string]bool) ([]byte, error) {
+	hclFile, diagnostics := hclwrite.ParseConfig(hclBytes, "resources.tf", hcl.Pos{Line: 0, Column: 0, Byte: 0})
+	if diagnostics.HasErrors() {
+		return nil, fmt.Errorf("[hclwrite.ParseConfig]%v", diagnostics)
+	}
+
+	body := hclFile.Body()
+	for _, block := range body.Blocks() {
+		labels := block.Labels()
+		if block.Type() != "resource" || len(labels) != 2 {
+			continue
+		}
+
+		if prunedResources[fmt.Sprintf("%v.%v", labels[0], labels[1])] {
+			body.RemoveBlock(block)
+		}
+	}
+
+	return hclFile.Bytes(), nil
+}
@@ -0,0 +1,80 @@
+package terraformerCLI
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+func Test_pruneStateResources(t *testing.T) {
+	// Given
+	inputState := []byte(`{
+  "version": 4,
+  "resources": [
+    {
+      "mode": "managed",
+      "type": "aws_instance",
+      "name": "tfer--sandbox",
+      "provider": "provider[\"registry.terraform.io/hashicorp/aws\"]",
+      "instances": [{"schema_version": 1, "attributes_flat": {"id": "i-1", "tags.env": "sandbox"}}]
+    },
+    {
+      "mode": "managed",
+      "type": "aws_instance",
+      "name": "tfer--prod",
+      "provider": "provider[\"registry.terraform.io/hashicorp/aws\"]",
+      "instances": [{"schema_version": 1, "attributes_flat": {"id": "i-2", "tags.env": "prod"}}]
+    }
+  ]
+}`)
+	exclusions := terraformValueObjects.ResourceTagSelectors{"env": {"sandbox"}}
+
+	// When
+	outputState, prunedResources, err := pruneStateResources(inputState, func(resource importedResource) bool {
+		return exclusions.Matches("tags", resource.AttributesFlat)
+	})
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"aws_instance.tfer--sandbox": true}, prunedResources)
+	assert.NotContains(t, string(outputState), "tfer--sandbox")
+	assert.Contains(t, string(outputState), "tfer--prod")
+}
+
+func Test_pruneStateResources_NothingPruned(t *testing.T) {
+	// Given
+	inputState := []byte(`{"version": 4, "resources": [{"type": "aws_instance", "name": "tfer--prod", "instances": [{"attributes_flat": {"id": "i-2"}}]}]}`)
+
+	// When
+	outputState, prunedResources, err := pruneStateResources(inputState, func(resource importedResource) bool {
+		return false
+	})
+
+	// Then
+	require.NoError(t, err)
+	assert.Empty(t, prunedResources)
+	assert.Equal(t, inputState, outputState)
+}
+
+func Test_pruneHCLResources(t *testing.T) {
+	// Given
+	inputHCL := []byte(`resource "aws_instance" "tfer--sandbox" {
+  ami = "ami-1"
+}
+
+resource "aws_instance" "tfer--prod" {
+  ami = "ami-2"
+}
+`)
+
+	// When
+	output, err := pruneHCLResources(inputHCL, map[string]bool{"aws_instance.tfer--sandbox": true})
+
+	// Then
+	require.NoError(t, err)
+	assert.NotContains(t, string(output), "tfer--sandbox")
+	assert.Contains(t, string(output), `resource "aws_instance" "tfer--prod"`)
+}
@@ -23,4 +23,14 @@ type Config struct {
 
 	// PullReviewers is the name of the pull request reviewer who will be tagged on the opened pull request.
 	PullReviewers []string `default:"NoReviewer"`
+
+	// ActorReviewersFile is the path of a yaml file mapping cloud actors onto VCS users, who are requested to review
+	// the pull request when they created unmanaged resources or modified managed resources outside of Terraform.
+	ActorReviewersFile string
+
+	// SARIFPath is the path of the SARIF file of security findings.
+	SARIFPath string
+
+	// UploadSARIF is whether the SARIF file of security findings is uploaded to the repository's code scanning.
+	UploadSARIF bool
 }
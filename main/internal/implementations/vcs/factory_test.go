@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
 )
 
@@ -18,7 +19,7 @@ func TestCreateNotSupported(t *testing.T) {
 	dragonDrop := new(interfaces.DragonDropMock)
 
 	// When
-	vcs, err := vcsFactory.Instantiate(ctx, vcsProvider, dragonDrop, config)
+	vcs, err := vcsFactory.Instantiate(ctx, vcsProvider, dragonDrop, config, artifacts.NewMemoryStore(true))
 
 	// Then
 	assert.NotNil(t, err)
@@ -34,7 +35,7 @@ func TestCreateIsolatedVCS(t *testing.T) {
 	dragonDrop := new(interfaces.DragonDropMock)
 
 	// When
-	vcs, err := vcsFactory.Instantiate(ctx, vcsProvider, dragonDrop, config)
+	vcs, err := vcsFactory.Instantiate(ctx, vcsProvider, dragonDrop, config, artifacts.NewMemoryStore(true))
 
 	// Then
 	assert.Nil(t, err)
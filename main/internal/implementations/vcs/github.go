@@ -1,11 +1,15 @@
 package vcs
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"os"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -14,8 +18,11 @@ import (
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/google/go-github/v45/github"
+	log "github.com/sirupsen/logrus"
 	"golang.org/x/oauth2"
 
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/clonedrepo"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
 )
 
@@ -35,6 +42,17 @@ type GitHub struct {
 	// newBranchName is the name of the new branch name for the new pull request.
 	newBranchName string
 
+	// firstBranchName is the name of the branch of the first part of the changes, from which the branches of further
+	// parts are named.
+	firstBranchName string
+
+	// baseCommitHash is the hash of the commit of the base branch from which new branches are created.
+	baseCommitHash plumbing.Hash
+
+	// commitHash is the hash of the commit of cloud-concierge results, or of their first part when the results are
+	// split across pull requests.
+	commitHash plumbing.Hash
+
 	// repository is a code repository object from the go-git package which represents the customer's
 	// code repository containing IaC.
 	repository *git.Repository
@@ -48,10 +66,13 @@ type GitHub struct {
 
 	// dragonDrop is needed to inform cloned status
 	dragonDrop interfaces.DragonDrop
+
+	// artifactStore holds the mappings of the cloud actors from which pull request reviewers are mapped.
+	artifactStore artifacts.Store
 }
 
 // NewGitHub creates a new instance of the GitHub struct.
-func NewGitHub(ctx context.Context, dragonDrop interfaces.DragonDrop, config Config) interfaces.VCS {
+func NewGitHub(ctx context.Context, dragonDrop interfaces.DragonDrop, config Config, artifactStore artifacts.Store) interfaces.VCS {
 	ts := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: config.VCSToken},
 	)
@@ -67,8 +88,9 @@ func NewGitHub(ctx context.Context, dragonDrop interfaces.DragonDrop, config Con
 			Username: config.VCSUser,
 			Password: config.VCSToken,
 		},
-		oauth2Client: authenticatedClient,
-		dragonDrop:   dragonDrop,
+		oauth2Client:  authenticatedClient,
+		dragonDrop:    dragonDrop,
+		artifactStore: artifactStore,
 	}
 }
 
@@ -91,12 +113,12 @@ func (g *GitHub) Clone() error {
 	}
 
 	// Cleaning out the existing repository folder. Cannot clone into an already existing directory.
-	err := os.RemoveAll("./repo/")
+	err := os.RemoveAll(clonedrepo.Directory)
 	if err != nil {
 		return err
 	}
 
-	repo, err := git.PlainClone("./repo/", false, cloneOptions)
+	repo, err := git.PlainClone(clonedrepo.Directory, false, cloneOptions)
 
 	if err != nil {
 		return err
@@ -144,6 +166,11 @@ func (g *GitHub) Checkout(jobName string) error {
 		Create: true,
 	}
 
+	head, err := g.repository.Head()
+	if err != nil {
+		return fmt.Errorf("[vcs][checkout][error in repository.Head]%w", err)
+	}
+
 	workTree, err := g.repository.Worktree()
 
 	if err != nil {
@@ -158,14 +185,89 @@ func (g *GitHub) Checkout(jobName string) error {
 
 	g.workTree = workTree
 	g.ID = branchUniqueID
+	g.firstBranchName = newBranchName
+	g.baseCommitHash = head.Hash()
 
 	return nil
 }
 
 // Commit commits code changes to the current branch of the remote repository.
 func (g *GitHub) Commit() error {
+	return g.commit(true)
+}
+
+// ChangedPaths returns the repository relative paths of all uncommitted changes.
+func (g *GitHub) ChangedPaths() ([]string, error) {
+	status, err := g.workTree.Status()
+	if err != nil {
+		return nil, fmt.Errorf("[vcs][changed_paths][error in worktree.Status]%w", err)
+	}
+
+	paths := []string{}
+	for path, fileStatus := range status {
+		if fileStatus.Worktree == git.Unmodified && fileStatus.Staging == git.Unmodified {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// CommitPaths commits the changes to paths alone to the current branch of the remote repository.
+func (g *GitHub) CommitPaths(paths []string) error {
+	status, err := g.workTree.Status()
+	if err != nil {
+		return fmt.Errorf("[vcs][commit_paths][error in worktree.Status]%w", err)
+	}
+
+	for _, path := range paths {
+		if status.File(path).Worktree == git.Deleted {
+			_, err = g.workTree.Remove(path)
+		} else {
+			_, err = g.workTree.Add(path)
+		}
+		if err != nil {
+			return fmt.Errorf("[vcs][commit_paths][error staging %v]%w", path, err)
+		}
+	}
+
+	return g.commit(false)
+}
+
+// CheckoutPart creates a new branch, based upon the base branch, for a further part of the changes to be opened
+// as a separate pull request. Uncommitted changes are kept.
+func (g *GitHub) CheckoutPart(part int) error {
+	newBranchName := fmt.Sprintf("%v_part_%v", g.firstBranchName, part)
+
+	checkoutOptions := &git.CheckoutOptions{
+		Hash:   g.baseCommitHash,
+		Branch: plumbing.NewBranchReferenceName(newBranchName),
+		Create: true,
+		Keep:   true,
+	}
+
+	err := g.workTree.Checkout(checkoutOptions)
+	if err != nil {
+		return fmt.Errorf("[vcs][checkout_part][error in checking out %v]%w", newBranchName, err)
+	}
+
+	// The index still holds the changes committed for the previous part, so is reset to the base commit while the
+	// uncommitted changes of the remaining parts are kept within the worktree.
+	err = g.workTree.Reset(&git.ResetOptions{Commit: g.baseCommitHash, Mode: git.MixedReset})
+	if err != nil {
+		return fmt.Errorf("[vcs][checkout_part][error in worktree.Reset]%w", err)
+	}
+
+	g.newBranchName = newBranchName
+	return nil
+}
+
+// commit commits the staged code changes, along with all changes to tracked files when all is true, to the current
+// branch.
+func (g *GitHub) commit(all bool) error {
 	commitOptions := &git.CommitOptions{
-		All: true,
+		All: all,
 		Author: &object.Signature{
 			Name:  "dragondrop.cloud",
 			Email: "cloud-concierge@dragondrop.cloud",
@@ -180,6 +282,9 @@ func (g *GitHub) Commit() error {
 	}
 
 	fmt.Printf("Commit made with hash: %v\n", commitHash)
+	if g.commitHash.IsZero() {
+		g.commitHash = commitHash
+	}
 
 	return nil
 }
@@ -236,27 +341,108 @@ func (g *GitHub) OpenPullRequest(jobName string) (string, error) {
 		return "", fmt.Errorf("error in github.PullRequests.Create(): %v", err)
 	}
 
-	if g.config.PullReviewers[0] != "NoReviewer" {
-		rr := github.ReviewersRequest{
-			Reviewers: g.config.PullReviewers,
-		}
-
-		_, _, err = g.oauth2Client.PullRequests.RequestReviewers(
-			context.Background(),
-			orgName,
-			repoName,
-			pr.GetNumber(),
-			rr,
-		)
+	reviewers, err := pullRequestReviewers(g.artifactStore, g.config)
+	if err != nil {
+		return "", fmt.Errorf("[pullRequestReviewers]%w", err)
+	}
 
+	if len(reviewers) > 0 {
+		err = g.requestReviewers(orgName, repoName, pr.GetNumber(), reviewers)
+
+		// Cloud actors mapped onto users without access to the repository cannot be requested to review, in which case
+		// only the static reviewers are requested.
+		static := staticReviewers(g.config)
+		if err != nil && len(static) < len(reviewers) {
+			log.Warnf("[vcs][open_pull_request] unable to request review from the cloud actors' users, requesting only the static reviewers: %v", err)
+			err = nil
+			if len(static) > 0 {
+				err = g.requestReviewers(orgName, repoName, pr.GetNumber(), static)
+			}
+		}
 		if err != nil {
-			return "", fmt.Errorf("error in github.PullRequests.RequestReviewers(): %v", err)
+			return "", err
 		}
 	}
 
 	return pr.GetURL(), nil
 }
 
+// requestReviewers requests review of the pull request from reviewers.
+func (g *GitHub) requestReviewers(orgName string, repoName string, number int, reviewers []string) error {
+	_, _, err := g.oauth2Client.PullRequests.RequestReviewers(
+		context.Background(),
+		orgName,
+		repoName,
+		number,
+		github.ReviewersRequest{Reviewers: reviewers},
+	)
+	if err != nil {
+		return fmt.Errorf("error in github.PullRequests.RequestReviewers(): %v", err)
+	}
+	return nil
+}
+
+// UploadSARIF uploads the SARIF file of security findings to the repository's code scanning, for the pushed commit,
+// when configured to do so.
+func (g *GitHub) UploadSARIF() error {
+	if !g.config.UploadSARIF {
+		return nil
+	}
+
+	content, err := os.ReadFile(g.config.SARIFPath)
+	if err != nil {
+		return fmt.Errorf("[vcs][upload_sarif][os.ReadFile]%w", err)
+	}
+
+	sarif, err := compressSARIF(content)
+	if err != nil {
+		return fmt.Errorf("[vcs][upload_sarif]%w", err)
+	}
+
+	orgName, repoName, err := g.extractOrgAndRepoName(g.config.VCSRepo)
+	if err != nil {
+		return fmt.Errorf("[vcs][upload_sarif][extractOrgAndRepoName]%w", err)
+	}
+
+	_, _, err = g.oauth2Client.CodeScanning.UploadSarif(
+		context.Background(),
+		orgName,
+		repoName,
+		&github.SarifAnalysis{
+			CommitSHA: github.String(g.commitHash.String()),
+			Ref:       github.String(plumbing.NewBranchReferenceName(g.newBranchName).String()),
+			Sarif:     github.String(sarif),
+			ToolName:  github.String("cloud-concierge"),
+		},
+	)
+
+	// Uploads are processed asynchronously, with GitHub responding 202 Accepted.
+	var acceptedErr *github.AcceptedError
+	if err != nil && !errors.As(err, &acceptedErr) {
+		return fmt.Errorf("[vcs][upload_sarif][error in github.CodeScanning.UploadSarif()]%w", err)
+	}
+
+	return nil
+}
+
+// compressSARIF gzip compresses and base64 encodes the content of a SARIF file, as required by the code scanning API.
+func compressSARIF(content []byte) (string, error) {
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+
+	_, err := writer.Write(content)
+	if err != nil {
+		return "", fmt.Errorf("[compress_sarif][gzip.Write]%w", err)
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return "", fmt.Errorf("[compress_sarif][gzip.Close]%w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(compressed.Bytes()), nil
+}
+
 // extractOrgAndRepoName pulls out the organization and repository name from the
 // repositories full path.
 func (g *GitHub) extractOrgAndRepoName(repoFullPath string) (string, string, error) {
@@ -1,9 +1,20 @@
 package vcs
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestExtractOrgAndRepoName(t *testing.T) {
@@ -19,3 +30,80 @@ func TestExtractOrgAndRepoName(t *testing.T) {
 	assert.Equal(t, "dragondrop-cloud-org", org)
 	assert.Equal(t, "dragondrop-cloud-repo1", repo)
 }
+
+func TestCompressSARIF(t *testing.T) {
+	// Given
+	content := []byte(`{"version": "2.1.0", "runs": []}`)
+
+	// When
+	sarif, err := compressSARIF(content)
+
+	// Then
+	require.NoError(t, err)
+
+	compressed, err := base64.StdEncoding.DecodeString(sarif)
+	require.NoError(t, err)
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	require.NoError(t, err)
+	decompressed, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, content, decompressed)
+}
+
+func TestCommitPathsAcrossParts(t *testing.T) {
+	// Given
+	directory := t.TempDir()
+	repository, err := git.PlainInit(directory, false)
+	require.NoError(t, err)
+	workTree, err := repository.Worktree()
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(directory, "main.tf"), []byte("# base\n"), 0644))
+	_, err = workTree.Add("main.tf")
+	require.NoError(t, err)
+	_, err = workTree.Commit("initial", &git.CommitOptions{Author: &object.Signature{Name: "test", When: time.Now()}})
+	require.NoError(t, err)
+
+	g := &GitHub{repository: repository}
+	require.NoError(t, g.Checkout("my job"))
+
+	require.NoError(t, os.MkdirAll(filepath.Join(directory, "app"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(directory, "data"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(directory, "app", "new-resources.tf"), []byte("# app\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(directory, "data", "new-resources.tf"), []byte("# data\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(directory, "main.tf"), []byte("# remediated\n"), 0644))
+
+	// When
+	changedPaths, err := g.ChangedPaths()
+	require.NoError(t, err)
+
+	require.NoError(t, g.CommitPaths([]string{"app/new-resources.tf", "main.tf"}))
+	firstPartHash := g.commitHash
+
+	require.NoError(t, g.CheckoutPart(2))
+	require.NoError(t, g.CommitPaths([]string{"data/new-resources.tf"}))
+	head, err := repository.Head()
+	require.NoError(t, err)
+
+	// Then
+	assert.Equal(t, []string{"app/new-resources.tf", "data/new-resources.tf", "main.tf"}, changedPaths)
+	assert.Equal(t, g.firstBranchName+"_part_2", g.newBranchName)
+	assert.Equal(t, firstPartHash, g.commitHash)
+
+	treeFiles := func(hash plumbing.Hash) map[string]string {
+		commit, err := repository.CommitObject(hash)
+		require.NoError(t, err)
+		files, err := commit.Files()
+		require.NoError(t, err)
+
+		contents := map[string]string{}
+		require.NoError(t, files.ForEach(func(file *object.File) error {
+			contents[file.Name], err = file.Contents()
+			return err
+		}))
+		return contents
+	}
+
+	assert.Equal(t, map[string]string{"main.tf": "# remediated\n", "app/new-resources.tf": "# app\n"}, treeFiles(firstPartHash))
+	assert.Equal(t, map[string]string{"main.tf": "# base\n", "data/new-resources.tf": "# data\n"}, treeFiles(head.Hash()))
+}
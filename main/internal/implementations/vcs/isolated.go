@@ -38,6 +38,22 @@ func (v *IsolatedVCS) Commit() error {
 	return nil
 }
 
+// ChangedPaths returns the repository relative paths of all uncommitted changes.
+func (v *IsolatedVCS) ChangedPaths() ([]string, error) {
+	return []string{}, nil
+}
+
+// CommitPaths commits the changes to paths alone to the current branch of the remote repository.
+func (v *IsolatedVCS) CommitPaths(paths []string) error {
+	return nil
+}
+
+// CheckoutPart creates a new branch, based upon the base branch, for a further part of the changes to be opened
+// as a separate pull request. Uncommitted changes are kept.
+func (v *IsolatedVCS) CheckoutPart(part int) error {
+	return nil
+}
+
 // Push pushes current branch to remote repository.
 func (v *IsolatedVCS) Push() error {
 	return nil
@@ -49,6 +65,12 @@ func (v *IsolatedVCS) OpenPullRequest(jobName string) (string, error) {
 	return "", nil
 }
 
+// UploadSARIF uploads the SARIF file of security findings to the remote repository's code scanning, for the
+// pushed commit, when configured to do so.
+func (v *IsolatedVCS) UploadSARIF() error {
+	return nil
+}
+
 // GetID returns a string which is a random, 10 character unique identifier
 // for a dragondrop built commit/pull request
 func (v *IsolatedVCS) GetID() (string, error) {
@@ -0,0 +1,153 @@
+package vcs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+
+	"gopkg.in/yaml.v3"
+)
+
+// cloudActionsPath is the mapping file of the cloud actors who created or modified each resource.
+const cloudActionsPath = "mappings/resources-to-cloud-actions.json"
+
+// noReviewer is the placeholder for pull requests opened without reviewers.
+const noReviewer = "NoReviewer"
+
+// ActorReviewerMapping maps cloud actors onto the VCS users requested to review pull requests.
+type ActorReviewerMapping struct {
+	// Reviewers are the mapped cloud actors, the first matching mapping applies.
+	Reviewers []ActorReviewer `yaml:"reviewers"`
+}
+
+// ActorReviewer maps the cloud actors matching a glob onto a VCS user.
+type ActorReviewer struct {
+	// Match is a glob matched against the cloud actor, e.g. jane.doe@example.com or
+	// arn:aws:sts::*:assumed-role/*/jdoe-session.
+	Match string `yaml:"match"`
+
+	// Username is the VCS username of the cloud actor, e.g. the GitHub login.
+	Username string `yaml:"username"`
+}
+
+// staticReviewers returns the configured static reviewers, without duplicates and without the user opening the pull
+// request, who cannot review it.
+func staticReviewers(config Config) []string {
+	return appendReviewers([]string{}, config.VCSUser, config.PullReviewers...)
+}
+
+// pullRequestReviewers returns the static reviewers followed by the VCS users mapped from the cloud actors who created
+// unmanaged resources or modified managed resources outside of Terraform.
+func pullRequestReviewers(store artifacts.Store, config Config) ([]string, error) {
+	actorReviewers, err := mappedActorReviewers(store, config.ActorReviewersFile)
+	if err != nil {
+		return nil, fmt.Errorf("[pull_request_reviewers]%w", err)
+	}
+	return appendReviewers(staticReviewers(config), config.VCSUser, actorReviewers...), nil
+}
+
+// appendReviewers appends to reviewers each of the candidates not already present, other than the placeholder for no
+// reviewer and the pull request author.
+func appendReviewers(reviewers []string, author string, candidates ...string) []string {
+	for _, candidate := range candidates {
+		if candidate == "" || candidate == noReviewer || candidate == author || containsReviewer(reviewers, candidate) {
+			continue
+		}
+		reviewers = append(reviewers, candidate)
+	}
+	return reviewers
+}
+
+// containsReviewer returns true if reviewers contains reviewer.
+func containsReviewer(reviewers []string, reviewer string) bool {
+	for _, existing := range reviewers {
+		if existing == reviewer {
+			return true
+		}
+	}
+	return false
+}
+
+// mappedActorReviewers returns the sorted VCS users mapped by the file at mappingPath from the cloud actors of the
+// run, or none when no mapping file is configured.
+func mappedActorReviewers(store artifacts.Store, mappingPath string) ([]string, error) {
+	if mappingPath == "" {
+		return []string{}, nil
+	}
+
+	content, err := os.ReadFile(mappingPath)
+	if err != nil {
+		return nil, fmt.Errorf("[mapped_actor_reviewers][os.ReadFile %v]%w", mappingPath, err)
+	}
+
+	mapping := ActorReviewerMapping{}
+	err = yaml.Unmarshal(content, &mapping)
+	if err != nil {
+		return nil, fmt.Errorf("[mapped_actor_reviewers][yaml.Unmarshal %v]%w", mappingPath, err)
+	}
+
+	actors, err := cloudActors(store)
+	if err != nil {
+		return nil, fmt.Errorf("[mapped_actor_reviewers]%w", err)
+	}
+
+	usernames := map[string]bool{}
+	for _, actor := range actors {
+		for _, reviewer := range mapping.Reviewers {
+			if matched, _ := path.Match(reviewer.Match, actor); matched && reviewer.Username != "" {
+				usernames[reviewer.Username] = true
+				break
+			}
+		}
+	}
+
+	sortedUsernames := make([]string, 0, len(usernames))
+	for username := range usernames {
+		sortedUsernames = append(sortedUsernames, username)
+	}
+	sort.Strings(sortedUsernames)
+	return sortedUsernames, nil
+}
+
+// cloudActionsFile is the structure of the cloud actions mapping file: provider, division and resource name to the
+// creation and modification of the resource.
+type cloudActionsFile map[string]map[string]map[string]map[string]struct {
+	Actor string `json:"actor"`
+}
+
+// cloudActors returns the cloud actors who created or modified the resources of the run, or none when cloud actors
+// were not identified.
+func cloudActors(store artifacts.Store) ([]string, error) {
+	content, err := store.ReadFile(cloudActionsPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []string{}, nil
+		}
+		return nil, fmt.Errorf("[cloud_actors][artifacts.ReadFile]%w", err)
+	}
+
+	actions := cloudActionsFile{}
+	err = json.Unmarshal(content, &actions)
+	if err != nil {
+		return nil, fmt.Errorf("[cloud_actors][json.Unmarshal]%w", err)
+	}
+
+	actors := []string{}
+	for _, divisions := range actions {
+		for _, resources := range divisions {
+			for _, resourceActions := range resources {
+				for _, action := range resourceActions {
+					if action.Actor != "" {
+						actors = append(actors, action.Actor)
+					}
+				}
+			}
+		}
+	}
+	return actors, nil
+}
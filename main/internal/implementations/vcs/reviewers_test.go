@@ -0,0 +1,64 @@
+package vcs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+)
+
+func TestPullRequestReviewers(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	require.NoError(t, os.MkdirAll("mappings", 0700))
+	require.NoError(t, os.WriteFile(cloudActionsPath, []byte(`{
+		"aws": {"123456789012": {
+			"aws_s3_bucket.logs": {"creation": {"actor": "jdoe-session", "timestamp": "2023-05-18"}},
+			"aws_instance.web": {"modified": {"actor": "ci-bot", "timestamp": "2023-05-19"}}
+		}},
+		"google": {"my-project": {
+			"google_storage_bucket.assets": {"creation": {"actor": "alex@example.com", "timestamp": "2023-05-18"}}
+		}}
+	}`), 0600))
+	require.NoError(t, os.WriteFile("reviewers.yaml", []byte(`
+reviewers:
+  - match: "jdoe*"
+    username: janedoe
+  - match: "*@example.com"
+    username: alexsmith
+  - match: "ci-bot"
+    username: cloud-concierge-bot
+`), 0600))
+
+	config := Config{
+		VCSUser:            "cloud-concierge-bot",
+		PullReviewers:      []string{"platform-lead", "janedoe"},
+		ActorReviewersFile: filepath.Join(".", "reviewers.yaml"),
+	}
+
+	// When
+	reviewers, err := pullRequestReviewers(artifacts.NewMemoryStore(true), config)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, []string{"platform-lead", "janedoe", "alexsmith"}, reviewers)
+}
+
+func TestPullRequestReviewers_NoReviewer(t *testing.T) {
+	// Given
+	config := Config{VCSUser: "cloud-concierge-bot", PullReviewers: []string{"NoReviewer"}}
+
+	// When
+	reviewers, err := pullRequestReviewers(artifacts.NewMemoryStore(true), config)
+
+	// Then
+	require.NoError(t, err)
+	assert.Empty(t, reviewers)
+}
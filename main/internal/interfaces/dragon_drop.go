@@ -44,14 +44,17 @@ type DragonDrop interface {
 	// InformComplete Informs to DragonDropAPI when job is Complete
 	InformComplete(ctx context.Context) error
 
+	// InformFailed Informs to DragonDropAPI when job has Failed, along with the machine-readable error code
+	InformFailed(ctx context.Context, errorCode string) error
+
 	// PostLogAlert sends alert log to the dragondrop API.
 	PostLogAlert(ctx context.Context, log string)
 
 	// PostLog sends log to the dragondrop API.
 	PostLog(ctx context.Context, log string)
 
-	// PutJobPullRequestURL sends the job url to the dragondrop API
-	PutJobPullRequestURL(ctx context.Context, prURL string) error
+	// PutJobPullRequestURLs sends the urls of the pull requests opened by the job to the dragondrop API
+	PutJobPullRequestURLs(ctx context.Context, prURLs []string) error
 }
 
 // DragonDropMock is a struct that implements the DragonDrop interface solely for the purpose
@@ -90,6 +93,12 @@ func (m *DragonDropMock) InformComplete(ctx context.Context) error {
 	return args.Error(0)
 }
 
+// InformFailed Informs to DragonDropAPI when job has Failed, along with the machine-readable error code
+func (m *DragonDropMock) InformFailed(ctx context.Context, errorCode string) error {
+	args := m.Called(ctx, errorCode)
+	return args.Error(0)
+}
+
 // InformRepositoryCloned Informs to DragonDropAPI when job cloned the repository
 func (m *DragonDropMock) InformRepositoryCloned(ctx context.Context) error {
 	args := m.Called(ctx)
@@ -138,8 +147,8 @@ func (m *DragonDropMock) PostLogAlert(ctx context.Context, log string) {}
 // PostLog sends log to the dragondrop API.
 func (m *DragonDropMock) PostLog(ctx context.Context, log string) {}
 
-// PutJobPullRequestURL sends the job url to the dragondrop API
-func (m *DragonDropMock) PutJobPullRequestURL(ctx context.Context, prURL string) error {
-	args := m.Called(ctx, prURL)
+// PutJobPullRequestURLs sends the urls of the pull requests opened by the job to the dragondrop API
+func (m *DragonDropMock) PutJobPullRequestURLs(ctx context.Context, prURLs []string) error {
+	args := m.Called(ctx, prURLs)
 	return args.Error(0)
 }
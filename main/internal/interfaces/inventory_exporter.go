@@ -0,0 +1,27 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// InventoryExporter is an interface for exporting the inventory of managed and unmanaged cloud resources.
+type InventoryExporter interface {
+
+	// Execute writes the inventory of all managed and unmanaged resources identified during the job run, and pushes
+	// it to any configured external systems.
+	Execute(ctx context.Context, workspaceToDirectory map[string]string) error
+}
+
+// InventoryExporterMock implements the InventoryExporter interface for testing purposes.
+type InventoryExporterMock struct {
+	mock.Mock
+}
+
+// Execute writes the inventory of all managed and unmanaged resources identified during the job run, and pushes
+// it to any configured external systems.
+func (m *InventoryExporterMock) Execute(ctx context.Context, workspaceToDirectory map[string]string) error {
+	args := m.Called(ctx, workspaceToDirectory)
+	return args.Error(0)
+}
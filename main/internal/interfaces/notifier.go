@@ -0,0 +1,25 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// Notifier is an interface for notifying external tooling of the results of a job run.
+type Notifier interface {
+
+	// Notify sends the summary of the job run, linking to its pull requests, to each configured destination.
+	Notify(ctx context.Context, pullRequestURLs []string) error
+}
+
+// NotifierMock implements the Notifier interface for testing purposes.
+type NotifierMock struct {
+	mock.Mock
+}
+
+// Notify sends the summary of the job run, linking to its pull requests, to each configured destination.
+func (m *NotifierMock) Notify(ctx context.Context, pullRequestURLs []string) error {
+	args := m.Called(ctx, pullRequestURLs)
+	return args.Error(0)
+}
@@ -0,0 +1,27 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// PolicyEvaluator is an interface for evaluating the combined output of a job run against user-supplied policies.
+type PolicyEvaluator interface {
+
+	// Execute evaluates the new resources, drift, costs and security findings of the job run against the configured
+	// policies, writing any violations.
+	Execute(ctx context.Context) error
+}
+
+// PolicyEvaluatorMock implements the PolicyEvaluator interface for testing purposes.
+type PolicyEvaluatorMock struct {
+	mock.Mock
+}
+
+// Execute evaluates the new resources, drift, costs and security findings of the job run against the configured
+// policies, writing any violations.
+func (m *PolicyEvaluatorMock) Execute(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
@@ -12,6 +12,10 @@ type ResourcesCalculator interface {
 
 	// Execute calculates the association between resources and a state file.
 	Execute(ctx context.Context, workspaceToDirectory map[string]string) error
+
+	// ApplyMinimumResourceAge removes the new resources that cloud actor identification found to be created more
+	// recently than the minimum resource age. Returns false if no new resources remain.
+	ApplyMinimumResourceAge(ctx context.Context) (bool, error)
 }
 
 // ResourcesCalculatorMock implements the ResourcesCalculator interface for testing purposes.
@@ -24,3 +28,10 @@ func (m *ResourcesCalculatorMock) Execute(ctx context.Context, workspaceToDirect
 	args := m.Called()
 	return args.Error(0)
 }
+
+// ApplyMinimumResourceAge removes the new resources that cloud actor identification found to be created more
+// recently than the minimum resource age. Returns false if no new resources remain.
+func (m *ResourcesCalculatorMock) ApplyMinimumResourceAge(ctx context.Context) (bool, error) {
+	args := m.Called()
+	return args.Bool(0), args.Error(1)
+}
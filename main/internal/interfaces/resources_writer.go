@@ -11,8 +11,8 @@ import (
 type ResourcesWriter interface {
 
 	// Execute writes new resources to the relevant version control system,
-	// and returns a pull request url corresponding to the new changes.
-	Execute(ctx context.Context, jobName string, createDummyFile bool, workspaceToDirectory map[string]string) (string, error)
+	// and returns the urls of the pull requests opened for the new changes.
+	Execute(ctx context.Context, jobName string, createDummyFile bool, workspaceToDirectory map[string]string) ([]string, error)
 }
 
 // ResourcesWriterMock implements the ResourcesWriter interface for testing purposes.
@@ -21,8 +21,8 @@ type ResourcesWriterMock struct {
 }
 
 // Execute writes new resources to the relevant version control system,
-// and returns a pull request url corresponding to the new changes.
-func (m *ResourcesWriterMock) Execute(ctx context.Context, jobName string, createDummyFile bool, workspaceToDirectory map[string]string) (string, error) {
+// and returns the urls of the pull requests opened for the new changes.
+func (m *ResourcesWriterMock) Execute(ctx context.Context, jobName string, createDummyFile bool, workspaceToDirectory map[string]string) ([]string, error) {
 	args := m.Called()
-	return args.String(0), args.Error(1)
+	return args.Get(0).([]string), args.Error(1)
 }
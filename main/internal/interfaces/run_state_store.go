@@ -0,0 +1,34 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// RunStateStore is an interface for persisting state between job runs, such as checkpoints and prior results.
+type RunStateStore interface {
+	// Get returns the data stored under key, or an error wrapping runStateStore.ErrNotFound if there is none.
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Put stores data under key, replacing any existing data.
+	Put(ctx context.Context, key string, data []byte) error
+}
+
+// RunStateStoreMock implements the RunStateStore interface for testing purposes.
+type RunStateStoreMock struct {
+	mock.Mock
+}
+
+// Get returns the data stored under key, or an error wrapping runStateStore.ErrNotFound if there is none.
+func (m *RunStateStoreMock) Get(ctx context.Context, key string) ([]byte, error) {
+	args := m.Called(ctx, key)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
+// Put stores data under key, replacing any existing data.
+func (m *RunStateStoreMock) Put(ctx context.Context, key string, data []byte) error {
+	args := m.Called(ctx, key, data)
+	return args.Error(0)
+}
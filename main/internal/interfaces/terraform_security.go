@@ -6,9 +6,10 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
-// TerraformSecurity is an interface to execute a scanning with tfsec or mocking the files
+// TerraformSecurity is an interface to execute a scanning with trivy, checkov or tfsec, or mocking the files
 type TerraformSecurity interface {
-	ExecuteScan(ctx context.Context) error
+	ExecuteScan(ctx context.Context, workspaceToDirectory map[string]string) error
+	ScanRepository(ctx context.Context, workspaceToDirectory map[string]string) error
 }
 
 // TerraformSecurityMock is a mock for testing purposes that implements the TerraformSecurity interface
@@ -16,9 +17,16 @@ type TerraformSecurityMock struct {
 	mock.Mock
 }
 
-// ExecuteScan is called from the main job flow to execute the tfsec command and save the output
+// ExecuteScan is called from the main job flow to execute the security scanner and save the output
 // to show to the user in the PR
-func (m *TerraformSecurityMock) ExecuteScan(ctx context.Context) error {
-	args := m.Called(ctx)
+func (m *TerraformSecurityMock) ExecuteScan(ctx context.Context, workspaceToDirectory map[string]string) error {
+	args := m.Called(ctx, workspaceToDirectory)
+	return args.Error(0)
+}
+
+// ScanRepository is called once the generated code is written to the repository to scan the changed and repository
+// scopes
+func (m *TerraformSecurityMock) ScanRepository(ctx context.Context, workspaceToDirectory map[string]string) error {
+	args := m.Called(ctx, workspaceToDirectory)
 	return args.Error(0)
 }
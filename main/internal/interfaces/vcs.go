@@ -18,6 +18,16 @@ type VCS interface {
 	// Commit commits code changes to the current branch of the remote repository.
 	Commit() error
 
+	// ChangedPaths returns the repository relative paths of all uncommitted changes.
+	ChangedPaths() ([]string, error)
+
+	// CommitPaths commits the changes to paths alone to the current branch of the remote repository.
+	CommitPaths(paths []string) error
+
+	// CheckoutPart creates a new branch, based upon the base branch, for a further part of the changes to be opened
+	// as a separate pull request. Uncommitted changes are kept.
+	CheckoutPart(part int) error
+
 	// Push pushes current branch to remote repository.
 	Push() error
 
@@ -25,6 +35,10 @@ type VCS interface {
 	// and returns the url of this pull request
 	OpenPullRequest(jobName string) (string, error)
 
+	// UploadSARIF uploads the SARIF file of security findings to the remote repository's code scanning, for the
+	// pushed commit, when configured to do so.
+	UploadSARIF() error
+
 	// GetID returns a string which is a random, 10 character unique identifier
 	// for a dragondrop built commit/pull request
 	GetID() (string, error)
@@ -59,6 +73,25 @@ func (m *VCSMock) Commit() error {
 	return args.Error(0)
 }
 
+// ChangedPaths returns the repository relative paths of all uncommitted changes.
+func (m *VCSMock) ChangedPaths() ([]string, error) {
+	args := m.Called()
+	return args.Get(0).([]string), args.Error(1)
+}
+
+// CommitPaths commits the changes to paths alone to the current branch of the remote repository.
+func (m *VCSMock) CommitPaths(paths []string) error {
+	args := m.Called(paths)
+	return args.Error(0)
+}
+
+// CheckoutPart creates a new branch, based upon the base branch, for a further part of the changes to be opened
+// as a separate pull request. Uncommitted changes are kept.
+func (m *VCSMock) CheckoutPart(part int) error {
+	args := m.Called(part)
+	return args.Error(0)
+}
+
 // Push pushes current branch to remote repository.
 func (m *VCSMock) Push() error {
 	args := m.Called()
@@ -72,6 +105,13 @@ func (m *VCSMock) OpenPullRequest(jobName string) (string, error) {
 	return args.String(0), args.Error(1)
 }
 
+// UploadSARIF uploads the SARIF file of security findings to the remote repository's code scanning, for the
+// pushed commit, when configured to do so.
+func (m *VCSMock) UploadSARIF() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
 // GetID returns a string which is a random, 10 character unique identifier
 // for a dragondrop built commit/pull request
 func (m *VCSMock) GetID() (string, error) {
@@ -0,0 +1,156 @@
+package joberrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/ratelimit"
+)
+
+// Code is a machine-readable classification of a job failure.
+type Code string
+
+const (
+	// CodeConfiguration is an invalid or incomplete job configuration.
+	CodeConfiguration Code = "CONFIGURATION"
+
+	// CodeAuthentication is a rejected or expired credential, for a cloud provider, VCS or the dragondrop API.
+	CodeAuthentication Code = "AUTHENTICATION"
+
+	// CodeAuthorization is a valid credential lacking the permissions for a request.
+	CodeAuthorization Code = "AUTHORIZATION"
+
+	// CodeRateLimited is a request throttled by a remote API.
+	CodeRateLimited Code = "RATE_LIMITED"
+
+	// CodeTimeout is an operation that exceeded its deadline.
+	CodeTimeout Code = "TIMEOUT"
+
+	// CodeDragonDropAPI is a failed request to the dragondrop API.
+	CodeDragonDropAPI Code = "DRAGONDROP_API"
+
+	// CodeVCS is a failure cloning the repository or opening the pull request.
+	CodeVCS Code = "VCS"
+
+	// CodeStateBackend is a failure finding workspaces or downloading their state.
+	CodeStateBackend Code = "STATE_BACKEND"
+
+	// CodeCloudScan is a failure scanning a cloud environment with terraformer.
+	CodeCloudScan Code = "CLOUD_SCAN"
+
+	// CodeGeneration is a failure generating Terraform code, import statements or workspace placements.
+	CodeGeneration Code = "GENERATION"
+
+	// CodeDriftDetection is a failure detecting drift of managed resources.
+	CodeDriftDetection Code = "DRIFT_DETECTION"
+
+	// CodeCloudActorIdentification is a failure querying cloud audit logs.
+	CodeCloudActorIdentification Code = "CLOUD_ACTOR_IDENTIFICATION"
+
+	// CodeCostEstimation is a failure estimating costs.
+	CodeCostEstimation Code = "COST_ESTIMATION"
+
+	// CodeSecurityScan is a failure scanning generated code for security risks.
+	CodeSecurityScan Code = "SECURITY_SCAN"
+
+	// CodePolicyEvaluation is a failure evaluating, or a violation of, user-supplied policies.
+	CodePolicyEvaluation Code = "POLICY_EVALUATION"
+
+	// CodeUnknown is an error without a classification.
+	CodeUnknown Code = "UNKNOWN"
+)
+
+// authorizationIndicators are substrings of provider error messages that indicate a credential lacking permissions.
+// They are checked before authenticationIndicators, as e.g. AWS's UnauthorizedOperation is a missing permission.
+var authorizationIndicators = []string{"unauthorizedoperation", "accessdenied", "access denied", "authorizationfailed", "authorizationerror", "forbidden", "status code 403", "permission denied"}
+
+// authenticationIndicators are substrings of provider error messages that indicate a rejected credential.
+var authenticationIndicators = []string{"unauthorized", "expiredtoken", "invalidclienttokenid", "signaturedoesnotmatch", "invalid_grant", "invalid_client", "authentication failed", "status code 401"}
+
+// Error is a job failure within a phase, with a machine-readable Code.
+type Error struct {
+	// Code classifies the failure.
+	Code Code
+
+	// Phase is the job phase in which the failure occurred, e.g. run_job.
+	Phase string
+
+	// Message describes the step that failed.
+	Message string
+
+	// Err is the underlying error.
+	Err error
+}
+
+// Error formats the error in the bracketed style used throughout the job, including the code.
+func (e *Error) Error() string {
+	return fmt.Sprintf("[%v][%v][%v]%v", e.Phase, e.Message, e.Code, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Wrap wraps err as an Error of the passed phase. The code is code, unless err is itself an Error, or describes a
+// timeout, throttling, rejected credential or missing permission, in which case the more specific code is used.
+func Wrap(phase string, message string, code Code, err error) error {
+	return &Error{Code: classify(err, code), Phase: phase, Message: message, Err: err}
+}
+
+// CodeOf returns the Code of the outermost Error within err's chain, or CodeUnknown.
+func CodeOf(err error) Code {
+	var jobErr *Error
+	if errors.As(err, &jobErr) {
+		return jobErr.Code
+	}
+	return CodeUnknown
+}
+
+// PhaseOf returns the phase of the outermost Error within err's chain, or an empty string.
+func PhaseOf(err error) string {
+	var jobErr *Error
+	if errors.As(err, &jobErr) {
+		return jobErr.Phase
+	}
+	return ""
+}
+
+// classify returns the most specific code describing err, falling back to code.
+func classify(err error, code Code) Code {
+	var jobErr *Error
+	if errors.As(err, &jobErr) {
+		return jobErr.Code
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return CodeTimeout
+	}
+
+	if err == nil {
+		return code
+	}
+
+	if ratelimit.IsThrottlingError(err) {
+		return CodeRateLimited
+	}
+
+	message := strings.ToLower(err.Error())
+	for _, indicator := range authorizationIndicators {
+		if strings.Contains(message, indicator) {
+			return CodeAuthorization
+		}
+	}
+	for _, indicator := range authenticationIndicators {
+		if strings.Contains(message, indicator) {
+			return CodeAuthentication
+		}
+	}
+	if strings.Contains(message, "timed out") {
+		return CodeTimeout
+	}
+
+	return code
+}
@@ -0,0 +1,56 @@
+package joberrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrap(t *testing.T) {
+	type args struct {
+		code Code
+		err  error
+	}
+	tests := []struct {
+		name string
+		args args
+		want Code
+	}{
+		{"phase code", args{CodeVCS, errors.New("repository not found")}, CodeVCS},
+		{"throttled", args{CodeCloudScan, errors.New("ThrottlingException: Rate exceeded")}, CodeRateLimited},
+		{"rejected credential", args{CodeCloudScan, errors.New("InvalidClientTokenId: The security token included in the request is invalid")}, CodeAuthentication},
+		{"missing permission", args{CodeCloudScan, errors.New("AccessDenied: User is not authorized to perform: s3:ListAllMyBuckets")}, CodeAuthorization},
+		{"unauthorized operation", args{CodeCloudScan, errors.New("UnauthorizedOperation: You are not authorized to perform this operation.")}, CodeAuthorization},
+		{"permission denied", args{CodeStateBackend, errors.New("googleapi: Error 403: Permission denied on resource, forbidden")}, CodeAuthorization},
+		{"deadline", args{CodeCostEstimation, fmt.Errorf("[infracost]%w", context.DeadlineExceeded)}, CodeTimeout},
+		{"command timed out", args{CodeCloudScan, errors.New("[execute_command][terraformer][command timed out]")}, CodeTimeout},
+		{"nested code", args{CodeVCS, Wrap("create_job", "error authorizing job", CodeDragonDropAPI, errors.New("bad gateway"))}, CodeDragonDropAPI},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			err := Wrap("run_job", "error", tt.args.code, tt.args.err)
+
+			// Then
+			assert.Equal(t, tt.want, CodeOf(err))
+			assert.ErrorIs(t, err, tt.args.err)
+		})
+	}
+}
+
+func TestError_Error(t *testing.T) {
+	// When
+	err := Wrap("run_job", "error clonning repo", CodeVCS, errors.New("repository not found"))
+
+	// Then
+	assert.Equal(t, "[run_job][error clonning repo][VCS]repository not found", err.Error())
+	assert.Equal(t, "run_job", PhaseOf(fmt.Errorf("[main]%w", err)))
+}
+
+func TestCodeOf_Unknown(t *testing.T) {
+	assert.Equal(t, CodeUnknown, CodeOf(errors.New("unclassified")))
+	assert.Equal(t, "", PhaseOf(errors.New("unclassified")))
+}
@@ -0,0 +1,61 @@
+package joberrors
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// RunSummary is the machine-readable outcome of a job run.
+type RunSummary struct {
+	// Status is either "succeeded" or "failed".
+	Status string `json:"status"`
+
+	// ErrorCode classifies the failure of a failed run.
+	ErrorCode Code `json:"errorCode,omitempty"`
+
+	// Phase is the job phase in which a failed run failed.
+	Phase string `json:"phase,omitempty"`
+
+	// Error is the full error message of a failed run.
+	Error string `json:"error,omitempty"`
+
+	// StartedAt is the time at which the run started.
+	StartedAt time.Time `json:"startedAt"`
+
+	// FinishedAt is the time at which the run finished.
+	FinishedAt time.Time `json:"finishedAt"`
+}
+
+// NewRunSummary returns the summary of a run started at startedAt, which failed if err is not nil.
+func NewRunSummary(startedAt time.Time, err error) RunSummary {
+	summary := RunSummary{
+		Status:     "succeeded",
+		StartedAt:  startedAt.UTC(),
+		FinishedAt: time.Now().UTC(),
+	}
+
+	if err != nil {
+		summary.Status = "failed"
+		summary.ErrorCode = CodeOf(err)
+		summary.Phase = PhaseOf(err)
+		summary.Error = err.Error()
+	}
+
+	return summary
+}
+
+// Write saves the summary as json to path.
+func (s RunSummary) Write(path string) error {
+	content, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[run_summary][json.MarshalIndent]%w", err)
+	}
+
+	err = os.WriteFile(path, content, 0644)
+	if err != nil {
+		return fmt.Errorf("[run_summary][os.WriteFile]%w", err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package joberrors
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunSummary_Write(t *testing.T) {
+	// Given
+	path := filepath.Join(t.TempDir(), "run-summary.json")
+	summary := NewRunSummary(time.Now(), Wrap("run_job", "error estimating cost", CodeCostEstimation, errors.New("429 too many requests")))
+
+	// When
+	err := summary.Write(path)
+
+	// Then
+	require.NoError(t, err)
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	written := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(content, &written))
+	assert.Equal(t, "failed", written["status"])
+	assert.Equal(t, "RATE_LIMITED", written["errorCode"])
+	assert.Equal(t, "run_job", written["phase"])
+}
+
+func TestNewRunSummary_Succeeded(t *testing.T) {
+	// When
+	summary := NewRunSummary(time.Now(), nil)
+
+	// Then
+	assert.Equal(t, "succeeded", summary.Status)
+	assert.Empty(t, summary.ErrorCode)
+}
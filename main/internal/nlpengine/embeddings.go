@@ -0,0 +1,297 @@
+package nlpengine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+)
+
+// embeddingsBatchSize is the maximum number of documents vectorized within a single embeddings API request.
+const embeddingsBatchSize = 100
+
+// embeddingsRequestTimeout bounds each embeddings API request, after which the engine falls back to TF-IDF.
+const embeddingsRequestTimeout = 60 * time.Second
+
+// EmbeddingsConfig is the configuration of an OpenAI-compatible embeddings API.
+type EmbeddingsConfig struct {
+	// Endpoint is the URL of the embeddings API, e.g. https://api.openai.com/v1/embeddings.
+	Endpoint string
+
+	// APIKey is the bearer token passed to the embeddings API.
+	APIKey string
+
+	// Model is the embeddings model requested, e.g. text-embedding-3-small.
+	Model string
+}
+
+// embeddingsRequest is the body of a request to an OpenAI-compatible embeddings API.
+type embeddingsRequest struct {
+	Model string   `json:"model,omitempty"`
+	Input []string `json:"input"`
+}
+
+// embeddingsResponse is the body of a response from an OpenAI-compatible embeddings API.
+type embeddingsResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float64 `json:"embedding"`
+	} `json:"data"`
+}
+
+// embeddingsEngine implements the NLPEngine interface by comparing embeddings of the documentized resources of each
+// workspace with those of each new resource, falling back to the TF-IDF engine when the embeddings API is unavailable.
+type embeddingsEngine struct {
+	// config is the configuration of the embeddings API.
+	config EmbeddingsConfig
+
+	// artifactStore holds the mappings read and written by the engine.
+	artifactStore artifacts.Store
+
+	// fallback places new resources when the embeddings API is unavailable.
+	fallback NLPEngine
+}
+
+// NewEmbeddingsEngine returns an instance of the NLPEngine interface based upon the similarity of document embeddings.
+func NewEmbeddingsEngine(config EmbeddingsConfig, artifactStore artifacts.Store) NLPEngine {
+	return &embeddingsEngine{config: config, artifactStore: artifactStore, fallback: NewTFIDFEngine(artifactStore)}
+}
+
+// RunNLPEngine places each new resource within the workspace whose document embeddings are most similar to its own.
+func (e *embeddingsEngine) RunNLPEngine() error {
+	newResourceDocs, err := readDocuments(e.artifactStore, "mappings/new-resources-to-documents.json")
+	if err != nil {
+		return fmt.Errorf("[run_nlp_engine]%w", err)
+	}
+
+	workspaceDocs, err := readDocuments(e.artifactStore, "mappings/workspace-to-documents.json")
+	if err != nil {
+		return fmt.Errorf("[run_nlp_engine]%w", err)
+	}
+
+	resourceToWorkspace, resourceToConfidence, err := PredictWorkspacesByEmbeddings(newResourceDocs, workspaceDocs, e.embed)
+	if err != nil {
+		fmt.Printf("Falling back to TF-IDF placement, as placement by embeddings failed: %v\n", err)
+		return e.fallback.RunNLPEngine()
+	}
+
+	err = writeMapping(e.artifactStore, "mappings/new-resources-to-workspace.json", resourceToWorkspace)
+	if err != nil {
+		return fmt.Errorf("[run_nlp_engine]%w", err)
+	}
+
+	err = writeMapping(e.artifactStore, "mappings/new-resources-to-placement-confidence.json", resourceToConfidence)
+	if err != nil {
+		return fmt.Errorf("[run_nlp_engine]%w", err)
+	}
+	return nil
+}
+
+// embed returns the embedding of each of the documents, in order, requested in batches from the embeddings API.
+func (e *embeddingsEngine) embed(documents []string) ([][]float64, error) {
+	embeddings := make([][]float64, 0, len(documents))
+	for start := 0; start < len(documents); start += embeddingsBatchSize {
+		end := start + embeddingsBatchSize
+		if end > len(documents) {
+			end = len(documents)
+		}
+
+		batch, err := e.embedBatch(documents[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("[embed]%w", err)
+		}
+		embeddings = append(embeddings, batch...)
+	}
+	return embeddings, nil
+}
+
+// embedBatch returns the embedding of each of the documents, in order, from a single embeddings API request.
+func (e *embeddingsEngine) embedBatch(documents []string) ([][]float64, error) {
+	body, err := json.Marshal(embeddingsRequest{Model: e.config.Model, Input: documents})
+	if err != nil {
+		return nil, fmt.Errorf("[embed_batch][json.Marshal]%w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), embeddingsRequestTimeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, e.config.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("[embed_batch][http.NewRequestWithContext]%w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if e.config.APIKey != "" {
+		request.Header.Set("Authorization", fmt.Sprintf("Bearer %v", e.config.APIKey))
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("[embed_batch][http.DefaultClient.Do]%w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		responseBody, _ := io.ReadAll(response.Body)
+		return nil, fmt.Errorf("[embed_batch][unexpected status code %v: %s]", response.StatusCode, responseBody)
+	}
+
+	embeddingsResponse := embeddingsResponse{}
+	err = json.NewDecoder(response.Body).Decode(&embeddingsResponse)
+	if err != nil {
+		return nil, fmt.Errorf("[embed_batch][json.Decode]%w", err)
+	}
+
+	embeddings := make([][]float64, len(documents))
+	for _, data := range embeddingsResponse.Data {
+		if data.Index < 0 || data.Index >= len(documents) {
+			return nil, fmt.Errorf("[embed_batch][unexpected embedding index %v]", data.Index)
+		}
+		embeddings[data.Index] = normalize(data.Embedding)
+	}
+	for index, embedding := range embeddings {
+		if len(embedding) == 0 {
+			return nil, fmt.Errorf("[embed_batch][no embedding returned for document %v]", index)
+		}
+	}
+	return embeddings, nil
+}
+
+// PredictWorkspacesByEmbeddings returns the workspace predicted for each new resource and the confidence of each
+// prediction, as PredictWorkspaces does, but comparing the embeddings returned by embed rather than TF-IDF vectors.
+func PredictWorkspacesByEmbeddings(
+	newResourceDocs map[string]string,
+	workspaceDocs map[string]string,
+	embed func(documents []string) ([][]float64, error),
+) (map[string]string, map[string]float64, error) {
+	workspaceNames := []string{}
+	for workspace, doc := range workspaceDocs {
+		if len(splitSentences(doc)) > 0 {
+			workspaceNames = append(workspaceNames, workspace)
+		}
+	}
+	if len(workspaceNames) == 0 {
+		return nil, nil, fmt.Errorf("[predict_workspaces_by_embeddings][no workspace documents to place new resources within]")
+	}
+	sort.Strings(workspaceNames)
+
+	resources := make([]string, 0, len(newResourceDocs))
+	for resource := range newResourceDocs {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+
+	documents := []string{}
+	for _, resource := range resources {
+		documents = append(documents, newResourceDocs[resource])
+	}
+	for _, workspace := range workspaceNames {
+		documents = append(documents, splitSentences(workspaceDocs[workspace])...)
+	}
+
+	embeddings, err := embed(documents)
+	if err != nil {
+		return nil, nil, fmt.Errorf("[predict_workspaces_by_embeddings]%w", err)
+	}
+	if len(embeddings) != len(documents) {
+		return nil, nil, fmt.Errorf("[predict_workspaces_by_embeddings][expected %v embeddings, received %v]", len(documents), len(embeddings))
+	}
+
+	models := []denseWorkspaceModel{}
+	defaultWorkspace := ""
+	mostSentences := -1
+	next := len(resources)
+	for _, workspace := range workspaceNames {
+		sentenceCount := len(splitSentences(workspaceDocs[workspace]))
+		model := denseWorkspaceModel{name: workspace, sentences: embeddings[next : next+sentenceCount]}
+		model.centroid = centroid(model.sentences)
+		models = append(models, model)
+		next += sentenceCount
+
+		if sentenceCount > mostSentences {
+			defaultWorkspace = workspace
+			mostSentences = sentenceCount
+		}
+	}
+
+	resourceToWorkspace := map[string]string{}
+	resourceToConfidence := map[string]float64{}
+	for index, resource := range resources {
+		bestWorkspace := defaultWorkspace
+		bestScore := 0.0
+		for _, model := range models {
+			score := model.similarity(embeddings[index])
+			if score > bestScore {
+				bestWorkspace = model.name
+				bestScore = score
+			}
+		}
+		resourceToWorkspace[resource] = bestWorkspace
+		resourceToConfidence[resource] = math.Round(math.Min(bestScore, 1)*1000) / 1000
+	}
+	return resourceToWorkspace, resourceToConfidence, nil
+}
+
+// denseWorkspaceModel holds the normalized embeddings of a single workspace: the centroid of all its resources and
+// the embedding of each of its resources.
+type denseWorkspaceModel struct {
+	name      string
+	centroid  []float64
+	sentences [][]float64
+}
+
+// similarity returns the average of the cosine similarity of v with the workspace centroid and with its most
+// similar resource.
+func (m denseWorkspaceModel) similarity(v []float64) float64 {
+	bestSentence := 0.0
+	for _, sentence := range m.sentences {
+		bestSentence = math.Max(bestSentence, dot(v, sentence))
+	}
+	return (dot(v, m.centroid) + bestSentence) / 2
+}
+
+// centroid returns the normalized mean of the vectors.
+func centroid(vectors [][]float64) []float64 {
+	if len(vectors) == 0 {
+		return nil
+	}
+
+	mean := make([]float64, len(vectors[0]))
+	for _, v := range vectors {
+		for index := range mean {
+			if index < len(v) {
+				mean[index] += v[index]
+			}
+		}
+	}
+	return normalize(mean)
+}
+
+// normalize returns v scaled to unit length.
+func normalize(v []float64) []float64 {
+	norm := math.Sqrt(dot(v, v))
+	if norm == 0 {
+		return v
+	}
+
+	normalized := make([]float64, len(v))
+	for index, value := range v {
+		normalized[index] = value / norm
+	}
+	return normalized
+}
+
+// dot returns the dot product of two vectors, being their cosine similarity when normalized.
+func dot(a []float64, b []float64) float64 {
+	product := 0.0
+	for index := 0; index < len(a) && index < len(b); index++ {
+		product += a[index] * b[index]
+	}
+	return product
+}
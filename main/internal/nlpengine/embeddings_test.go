@@ -0,0 +1,120 @@
+package nlpengine
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+)
+
+// keywordEmbeddings embeds documents over the dimensions "network", "payments" and "storage" by keyword.
+func keywordEmbeddings(documents []string) [][]float64 {
+	embeddings := [][]float64{}
+	for _, document := range documents {
+		embedding := []float64{0, 0, 0}
+		for index, keyword := range []string{"subnet", "payments", "bucket"} {
+			if strings.Contains(document, keyword) {
+				embedding[index] = 1
+			}
+		}
+		embeddings = append(embeddings, embedding)
+	}
+	return embeddings
+}
+
+func TestEmbeddingsEngine_RunNLPEngine(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer my-key", r.Header.Get("Authorization"))
+
+		request := embeddingsRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.Equal(t, "my-model", request.Model)
+
+		response := map[string][]map[string]interface{}{"data": {}}
+		for index, embedding := range keywordEmbeddings(request.Input) {
+			response["data"] = append(response["data"], map[string]interface{}{"index": index, "embedding": embedding})
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(response))
+	}))
+	defer server.Close()
+
+	require.NoError(t, os.MkdirAll("mappings", 0700))
+	require.NoError(t, os.WriteFile("mappings/workspace-to-documents.json", []byte(`{
+		"networking": "terraform name of private subnet. terraform name of public subnet. ",
+		"payments": "terraform name of payments queue. "
+	}`), 0600))
+	require.NoError(t, os.WriteFile("mappings/new-resources-to-documents.json", []byte(`{
+		"aws-prod.aws_sqs_queue.tfer--payments-dlq": "terraform name of payments dlq. ",
+		"aws-prod.aws_s3_bucket.tfer--logs": "terraform name of logs bucket. "
+	}`), 0600))
+
+	engine := NewEmbeddingsEngine(EmbeddingsConfig{Endpoint: server.URL, APIKey: "my-key", Model: "my-model"}, artifacts.NewMemoryStore(true))
+
+	// When
+	err := engine.RunNLPEngine()
+
+	// Then
+	require.NoError(t, err)
+	content, err := os.ReadFile("mappings/new-resources-to-workspace.json")
+	require.NoError(t, err)
+	resourceToWorkspace := map[string]string{}
+	require.NoError(t, json.Unmarshal(content, &resourceToWorkspace))
+	assert.Equal(t, map[string]string{
+		"aws-prod.aws_sqs_queue.tfer--payments-dlq": "payments",
+		"aws-prod.aws_s3_bucket.tfer--logs":         "networking",
+	}, resourceToWorkspace)
+
+	content, err = os.ReadFile("mappings/new-resources-to-placement-confidence.json")
+	require.NoError(t, err)
+	resourceToConfidence := map[string]float64{}
+	require.NoError(t, json.Unmarshal(content, &resourceToConfidence))
+	assert.Equal(t, map[string]float64{
+		"aws-prod.aws_sqs_queue.tfer--payments-dlq": 1,
+		"aws-prod.aws_s3_bucket.tfer--logs":         0,
+	}, resourceToConfidence)
+}
+
+func TestEmbeddingsEngine_RunNLPEngineFallback(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	require.NoError(t, os.MkdirAll("mappings", 0700))
+	require.NoError(t, os.WriteFile("mappings/workspace-to-documents.json", []byte(`{
+		"storage": "terraform name of assets and type google storage bucket resource at location us. "
+	}`), 0600))
+	require.NoError(t, os.WriteFile("mappings/new-resources-to-documents.json", []byte(`{
+		"google-my-project.google_storage_bucket.tfer--logs": "terraform name of logs and type google storage bucket resource at location us. "
+	}`), 0600))
+
+	engine := NewEmbeddingsEngine(EmbeddingsConfig{Endpoint: server.URL}, artifacts.NewMemoryStore(true))
+
+	// When
+	err := engine.RunNLPEngine()
+
+	// Then
+	require.NoError(t, err)
+	content, err := os.ReadFile("mappings/new-resources-to-workspace.json")
+	require.NoError(t, err)
+	resourceToWorkspace := map[string]string{}
+	require.NoError(t, json.Unmarshal(content, &resourceToWorkspace))
+	assert.Equal(t, map[string]string{"google-my-project.google_storage_bucket.tfer--logs": "storage"}, resourceToWorkspace)
+}
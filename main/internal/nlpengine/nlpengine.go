@@ -0,0 +1,87 @@
+package nlpengine
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+)
+
+// NLPEngine is an interface for placing new resources within the workspace whose resources they most resemble.
+type NLPEngine interface {
+	// RunNLPEngine places each of the new resource documents within mappings/new-resources-to-documents.json
+	// into one of the workspaces documented within mappings/workspace-to-documents.json, writing the placements
+	// to mappings/new-resources-to-workspace.json. Engines able to score their placements also write the confidence
+	// of each placement, between 0 and 1, to mappings/new-resources-to-placement-confidence.json.
+	RunNLPEngine() error
+}
+
+// tfidfEngine implements the NLPEngine interface natively by comparing TF-IDF vectors of the documentized
+// resources of each workspace with those of each new resource.
+type tfidfEngine struct {
+	// artifactStore holds the mappings read and written by the engine.
+	artifactStore artifacts.Store
+}
+
+// NewTFIDFEngine returns an instance of the NLPEngine interface based upon TF-IDF similarity.
+func NewTFIDFEngine(artifactStore artifacts.Store) NLPEngine {
+	return &tfidfEngine{artifactStore: artifactStore}
+}
+
+// RunNLPEngine places each new resource within the workspace whose documents are most similar to its own.
+func (e *tfidfEngine) RunNLPEngine() error {
+	newResourceDocs, err := readDocuments(e.artifactStore, "mappings/new-resources-to-documents.json")
+	if err != nil {
+		return fmt.Errorf("[run_nlp_engine]%w", err)
+	}
+
+	workspaceDocs, err := readDocuments(e.artifactStore, "mappings/workspace-to-documents.json")
+	if err != nil {
+		return fmt.Errorf("[run_nlp_engine]%w", err)
+	}
+
+	resourceToWorkspace, resourceToConfidence, err := PredictWorkspaces(newResourceDocs, workspaceDocs)
+	if err != nil {
+		return fmt.Errorf("[run_nlp_engine]%w", err)
+	}
+
+	err = writeMapping(e.artifactStore, "mappings/new-resources-to-workspace.json", resourceToWorkspace)
+	if err != nil {
+		return fmt.Errorf("[run_nlp_engine]%w", err)
+	}
+
+	err = writeMapping(e.artifactStore, "mappings/new-resources-to-placement-confidence.json", resourceToConfidence)
+	if err != nil {
+		return fmt.Errorf("[run_nlp_engine]%w", err)
+	}
+	return nil
+}
+
+// writeMapping writes a json mapping to path.
+func writeMapping(store artifacts.Store, path string, mapping interface{}) error {
+	content, err := json.Marshal(mapping)
+	if err != nil {
+		return fmt.Errorf("[write_mapping][json.Marshal]%w", err)
+	}
+
+	err = store.WriteFile(path, content, 0400)
+	if err != nil {
+		return fmt.Errorf("[write_mapping][artifacts.WriteFile %v]%w", path, err)
+	}
+	return nil
+}
+
+// readDocuments reads a json mapping of names to documents.
+func readDocuments(store artifacts.Store, path string) (map[string]string, error) {
+	content, err := store.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("[read_documents][artifacts.ReadFile %v]%w", path, err)
+	}
+
+	documents := map[string]string{}
+	err = json.Unmarshal(content, &documents)
+	if err != nil {
+		return nil, fmt.Errorf("[read_documents][json.Unmarshal %v]%w", path, err)
+	}
+	return documents, nil
+}
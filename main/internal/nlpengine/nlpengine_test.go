@@ -0,0 +1,90 @@
+package nlpengine
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+)
+
+func TestPredictWorkspaces(t *testing.T) {
+	// Given
+	workspaceDocs := map[string]string{
+		"networking": "terraform name of main vpc and type aws vpc resource at location us east 1 with primary category of networking. " +
+			"terraform name of private subnet and type aws subnet resource at location us east 1 with primary category of networking. ",
+		"payments": "terraform name of payments db and type aws db instance resource at location us east 1 with tag key of team and value of payments. " +
+			"terraform name of payments queue and type aws sqs queue resource at location us east 1 with tag key of team and value of payments. ",
+		"empty": "",
+	}
+	newResourceDocs := map[string]string{
+		"aws-prod.aws_subnet.tfer--public-subnet":   "terraform name of public subnet and type aws subnet resource at location us east 1 with primary category of networking. ",
+		"aws-prod.aws_sqs_queue.tfer--payments-dlq": "terraform name of payments dlq and type aws sqs queue resource at location us east 1 with tag key of team and value of payments. ",
+		"aws-prod.aws_iam_role.tfer--unrelated":     "terraform name of unrelated and type aws iam role resource at location global. ",
+	}
+
+	// When
+	resourceToWorkspace, resourceToConfidence, err := PredictWorkspaces(newResourceDocs, workspaceDocs)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"aws-prod.aws_subnet.tfer--public-subnet":   "networking",
+		"aws-prod.aws_sqs_queue.tfer--payments-dlq": "payments",
+		"aws-prod.aws_iam_role.tfer--unrelated":     "networking",
+	}, resourceToWorkspace)
+	assert.Greater(t, resourceToConfidence["aws-prod.aws_subnet.tfer--public-subnet"], 0.5)
+	assert.Greater(t, resourceToConfidence["aws-prod.aws_sqs_queue.tfer--payments-dlq"], 0.5)
+	assert.Less(t, resourceToConfidence["aws-prod.aws_iam_role.tfer--unrelated"], 0.5)
+}
+
+func TestPredictWorkspaces_NoWorkspaces(t *testing.T) {
+	// Given
+	newResourceDocs := map[string]string{"aws-prod.aws_subnet.tfer--public-subnet": "terraform name of public subnet. "}
+
+	// When
+	_, _, err := PredictWorkspaces(newResourceDocs, map[string]string{})
+
+	// Then
+	assert.Error(t, err)
+}
+
+func TestTFIDFEngine_RunNLPEngine(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	require.NoError(t, os.MkdirAll("mappings", 0700))
+	require.NoError(t, os.WriteFile("mappings/workspace-to-documents.json", []byte(`{
+		"storage": "terraform name of assets and type google storage bucket resource at location us. ",
+		"compute": "terraform name of web and type google compute instance resource at location us central1 a. "
+	}`), 0600))
+	require.NoError(t, os.WriteFile("mappings/new-resources-to-documents.json", []byte(`{
+		"google-my-project.google_storage_bucket.tfer--logs": "terraform name of logs and type google storage bucket resource at location us. "
+	}`), 0600))
+
+	engine := NewTFIDFEngine(artifacts.NewMemoryStore(true))
+
+	// When
+	err := engine.RunNLPEngine()
+
+	// Then
+	require.NoError(t, err)
+	content, err := os.ReadFile("mappings/new-resources-to-workspace.json")
+	require.NoError(t, err)
+
+	resourceToWorkspace := map[string]string{}
+	require.NoError(t, json.Unmarshal(content, &resourceToWorkspace))
+	assert.Equal(t, map[string]string{"google-my-project.google_storage_bucket.tfer--logs": "storage"}, resourceToWorkspace)
+
+	content, err = os.ReadFile("mappings/new-resources-to-placement-confidence.json")
+	require.NoError(t, err)
+
+	resourceToConfidence := map[string]float64{}
+	require.NoError(t, json.Unmarshal(content, &resourceToConfidence))
+	assert.Greater(t, resourceToConfidence["google-my-project.google_storage_bucket.tfer--logs"], 0.0)
+}
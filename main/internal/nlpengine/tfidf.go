@@ -0,0 +1,185 @@
+package nlpengine
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// templateWords are the words of the sentence template with which documentize describes every resource, which
+// carry no signal as to where a resource belongs.
+var templateWords = map[string]bool{
+	"a": true, "and": true, "account": true, "at": true, "category": true, "key": true, "location": true,
+	"module": true, "name": true, "none": true, "of": true, "primary": true, "resource": true, "secondary": true,
+	"tag": true, "terraform": true, "the": true, "type": true, "value": true, "with": true, "within": true,
+}
+
+// vector is a sparse, L2-normalized TF-IDF vector.
+type vector map[string]float64
+
+// workspaceModel holds the TF-IDF vectors of a single workspace: that of all its resources together and that of
+// each of its resources.
+type workspaceModel struct {
+	name      string
+	centroid  vector
+	sentences []vector
+}
+
+// PredictWorkspaces returns the workspace predicted for each new resource, chosen as the workspace whose resources
+// are most similar to the new resource. Similarity with a workspace averages the cosine similarity of the TF-IDF
+// vector of the new resource with that of all of the workspace's resources and with that of its most similar
+// resource, so that a new resource resembling a single existing resource is placed beside it. A new resource
+// similar to no workspace is placed within the workspace with the most resources.
+//
+// The similarity of each new resource with its predicted workspace, between 0 and 1, is returned as the confidence
+// of the prediction.
+func PredictWorkspaces(newResourceDocs map[string]string, workspaceDocs map[string]string) (map[string]string, map[string]float64, error) {
+	if len(workspaceDocs) == 0 {
+		return nil, nil, fmt.Errorf("[predict_workspaces][no workspace documents to place new resources within]")
+	}
+
+	workspaceNames := make([]string, 0, len(workspaceDocs))
+	for workspace := range workspaceDocs {
+		workspaceNames = append(workspaceNames, workspace)
+	}
+	sort.Strings(workspaceNames)
+
+	workspaceTokens := map[string][]string{}
+	for _, workspace := range workspaceNames {
+		workspaceTokens[workspace] = tokenize(workspaceDocs[workspace])
+	}
+	idf := inverseDocumentFrequencies(workspaceTokens)
+
+	models := []workspaceModel{}
+	defaultWorkspace := ""
+	mostSentences := -1
+	for _, workspace := range workspaceNames {
+		model := workspaceModel{name: workspace, centroid: tfidfVector(workspaceTokens[workspace], idf)}
+		for _, sentence := range splitSentences(workspaceDocs[workspace]) {
+			model.sentences = append(model.sentences, tfidfVector(tokenize(sentence), idf))
+		}
+		models = append(models, model)
+
+		if len(model.sentences) > mostSentences {
+			defaultWorkspace = workspace
+			mostSentences = len(model.sentences)
+		}
+	}
+
+	resourceToWorkspace := map[string]string{}
+	resourceToConfidence := map[string]float64{}
+	for resource, doc := range newResourceDocs {
+		resourceVector := tfidfVector(tokenize(doc), idf)
+
+		bestWorkspace := defaultWorkspace
+		bestScore := 0.0
+		for _, model := range models {
+			score := model.similarity(resourceVector)
+			if score > bestScore {
+				bestWorkspace = model.name
+				bestScore = score
+			}
+		}
+		resourceToWorkspace[resource] = bestWorkspace
+		resourceToConfidence[resource] = math.Round(bestScore*1000) / 1000
+	}
+	return resourceToWorkspace, resourceToConfidence, nil
+}
+
+// similarity returns the average of the cosine similarity of v with the workspace as a whole and with its most
+// similar resource.
+func (m workspaceModel) similarity(v vector) float64 {
+	bestSentence := 0.0
+	for _, sentence := range m.sentences {
+		bestSentence = math.Max(bestSentence, cosine(v, sentence))
+	}
+	return (cosine(v, m.centroid) + bestSentence) / 2
+}
+
+// tokenize splits a document into lowercase words, dropping the words of the documentize sentence template.
+func tokenize(doc string) []string {
+	words := strings.FieldsFunc(strings.ToLower(doc), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	tokens := []string{}
+	for _, word := range words {
+		if !templateWords[word] {
+			tokens = append(tokens, word)
+		}
+	}
+	return tokens
+}
+
+// splitSentences splits a document into the sentences documenting each of its resources.
+func splitSentences(doc string) []string {
+	sentences := []string{}
+	for _, sentence := range strings.Split(doc, ". ") {
+		if strings.TrimSpace(sentence) != "" {
+			sentences = append(sentences, sentence)
+		}
+	}
+	return sentences
+}
+
+// inverseDocumentFrequencies returns the smoothed inverse document frequency of each token across the
+// documents, so that tokens common to every workspace, such as a shared region, weigh less than distinctive ones.
+func inverseDocumentFrequencies(documentTokens map[string][]string) map[string]float64 {
+	documentFrequencies := map[string]int{}
+	for _, tokens := range documentTokens {
+		seen := map[string]bool{}
+		for _, token := range tokens {
+			if !seen[token] {
+				seen[token] = true
+				documentFrequencies[token]++
+			}
+		}
+	}
+
+	documentCount := float64(len(documentTokens))
+	idf := map[string]float64{}
+	for token, frequency := range documentFrequencies {
+		idf[token] = math.Log((1+documentCount)/(1+float64(frequency))) + 1
+	}
+	return idf
+}
+
+// tfidfVector returns the normalized TF-IDF vector of tokens, with sublinear term frequencies and ignoring tokens
+// absent from idf.
+func tfidfVector(tokens []string, idf map[string]float64) vector {
+	termFrequencies := map[string]int{}
+	for _, token := range tokens {
+		if _, ok := idf[token]; ok {
+			termFrequencies[token]++
+		}
+	}
+
+	v := vector{}
+	norm := 0.0
+	for token, frequency := range termFrequencies {
+		weight := (1 + math.Log(float64(frequency))) * idf[token]
+		v[token] = weight
+		norm += weight * weight
+	}
+
+	norm = math.Sqrt(norm)
+	for token := range v {
+		v[token] /= norm
+	}
+	return v
+}
+
+// cosine returns the cosine similarity of two normalized vectors.
+func cosine(a vector, b vector) float64 {
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+
+	similarity := 0.0
+	for token, weight := range a {
+		similarity += weight * b[token]
+	}
+	return similarity
+}
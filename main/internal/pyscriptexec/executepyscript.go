@@ -9,6 +9,12 @@ import (
 // ExecutePythonScript is a generic function for executing a python script
 // from within the python_scripts directory.
 func (pse *pyScriptExec) ExecutePythonScript(name string, otherArgs []string) error {
+	// The python scripts read the artifacts of earlier pipeline stages from disk.
+	err := pse.artifactStore.Flush()
+	if err != nil {
+		return fmt.Errorf("[artifacts.Flush]%w", err)
+	}
+
 	path := fmt.Sprintf("/python_scripts/%v/main.py", name)
 	args := []string{path}
 	args = append(args, otherArgs...)
@@ -21,7 +27,7 @@ func (pse *pyScriptExec) ExecutePythonScript(name string, otherArgs []string) er
 
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
-	err := cmd.Run()
+	err = cmd.Run()
 
 	if err != nil {
 		return fmt.Errorf(
@@ -1,5 +1,7 @@
 package pyscriptexec
 
+import "github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+
 // PyScriptExec is an interface for running python scripts programmatically.
 type PyScriptExec interface {
 
@@ -18,9 +20,11 @@ type PyScriptExec interface {
 
 // pyScriptExec implements the PyScriptExec interface.
 type pyScriptExec struct {
+	// artifactStore holds the artifacts flushed to disk before each python script is run.
+	artifactStore artifacts.Store
 }
 
 // NewPyScriptExec returns an instance of the PyScriptExec interface.
-func NewPyScriptExec() PyScriptExec {
-	return &pyScriptExec{}
+func NewPyScriptExec(artifactStore artifacts.Store) PyScriptExec {
+	return &pyScriptExec{artifactStore: artifactStore}
 }
@@ -0,0 +1,75 @@
+"""
+Helper functions for reporting monthly costs rolled up by workspace and by cost allocation tag.
+"""
+from mdutils.mdutils import MdUtils
+
+COST_ROLLUP_COLUMNS = [
+    "Resources",
+    "Uncontrolled Resources Cost",
+    "Terraform Controlled Resources Cost",
+    "Total Cost",
+]
+
+
+def cost_rollup_rows(cost_rollups: list, currency_symbol: str = "$") -> list:
+    """Convert cost rollups into (group, resources, uncontrolled, controlled, total) table rows."""
+    return [
+        [
+            rollup["group"],
+            str(rollup["resources"]),
+            f"{currency_symbol}{rollup['unmanaged_monthly_cost']:,.2f}",
+            f"{currency_symbol}{rollup['managed_monthly_cost']:,.2f}",
+            f"{currency_symbol}{rollup['total_monthly_cost']:,.2f}",
+        ]
+        for rollup in cost_rollups
+    ]
+
+
+def tag_cost_rollups(cost_rollups: dict) -> dict:
+    """Group the tag cost rollups by cost allocation tag, preserving their order."""
+    tag_to_rollups = {}
+    for rollup in cost_rollups.get("tags") or []:
+        tag_to_rollups.setdefault(rollup["tag"], []).append(rollup)
+    return tag_to_rollups
+
+
+def _create_markdown_table_cost_rollup(
+    first_column: str, rows: list, markdown_file: MdUtils
+) -> MdUtils:
+    """Create a new Markdown table of cost rollup rows."""
+    list_of_strings = [first_column] + COST_ROLLUP_COLUMNS
+    for row in rows:
+        list_of_strings.extend(row)
+
+    _ = markdown_file.new_table(
+        columns=len(COST_ROLLUP_COLUMNS) + 1,
+        rows=len(rows) + 1,
+        text=list_of_strings,
+        text_align="center",
+    )
+    return markdown_file
+
+
+def create_markdown_cost_rollups(
+    cost_rollups: dict, markdown_file: MdUtils, currency_symbol: str = "$"
+) -> MdUtils:
+    """Create tables of monthly costs by workspace and by the value of each cost allocation tag."""
+    if cost_rollups.get("workspaces"):
+        markdown_file.new_header(level=2, title="Monthly Cost by Workspace", style="atx")
+        markdown_file = _create_markdown_table_cost_rollup(
+            first_column="Workspace",
+            rows=cost_rollup_rows(cost_rollups["workspaces"], currency_symbol),
+            markdown_file=markdown_file,
+        )
+
+    for tag, rollups in tag_cost_rollups(cost_rollups).items():
+        markdown_file.new_header(
+            level=2, title=f"Monthly Cost by Tag `{tag}`", style="atx"
+        )
+        markdown_file = _create_markdown_table_cost_rollup(
+            first_column=tag,
+            rows=cost_rollup_rows(rollups, currency_symbol),
+            markdown_file=markdown_file,
+        )
+
+    return markdown_file
@@ -0,0 +1,34 @@
+"""
+Helper functions for displaying costs in the configured display currency.
+"""
+CURRENCY_SYMBOLS = {
+    "USD": "$",
+    "EUR": "€",
+    "GBP": "£",
+    "JPY": "¥",
+    "INR": "₹",
+}
+
+
+def currency_symbol(cost_currency: dict) -> str:
+    """
+    Return the symbol prefixed to costs in the display currency, falling back to the currency code
+    for currencies without a well known symbol.
+    """
+    currency = (cost_currency or {}).get("Currency") or "USD"
+    return CURRENCY_SYMBOLS.get(currency, f"{currency} ")
+
+
+def currency_conversion_note(cost_currency: dict) -> str:
+    """
+    Describe how costs were converted from USD into the display currency, or return an empty string
+    when costs are shown in USD.
+    """
+    currency = (cost_currency or {}).get("Currency") or "USD"
+    if currency == "USD":
+        return ""
+
+    note = f"Costs are shown in {currency}, converted from USD at {cost_currency['ExchangeRate']:g} {currency} per USD"
+    if cost_currency.get("Source") == "ecb":
+        note += f" (ECB reference rate of {cost_currency.get('RateDate')})"
+    return note + "."
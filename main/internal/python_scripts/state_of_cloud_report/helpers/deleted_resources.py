@@ -0,0 +1,44 @@
+"""
+Helper functions for reporting managed resources deleted outside of Terraform.
+"""
+from mdutils.mdutils import MdUtils
+
+
+def deleted_resource_rows(deleted_resources: list) -> list:
+    """
+    Converts a json load of deleted resources into sorted (state file, resource address, instance id) rows.
+    """
+    rows = []
+    for resource in deleted_resources or []:
+        address = resource.get("ResourceAddress") or ".".join(
+            [resource["ResourceType"], resource["ResourceName"]]
+        )
+        rows.append((resource["StateFileName"], address, resource["InstanceID"]))
+
+    return sorted(rows)
+
+
+def create_markdown_table_deleted_resources(
+    deleted_resources: list, markdown_file: MdUtils
+) -> MdUtils:
+    """Create a new Markdown table of the managed resources deleted outside of Terraform"""
+    rows = deleted_resource_rows(deleted_resources)
+
+    markdown_file.new_line(
+        "The following resources are tracked within Terraform state but were not found within the cloud. "
+        "Either remove them from state with `terraform state rm '<address>'`, or restore them with "
+        "`terraform apply -target='<address>'`."
+    )
+
+    list_of_strings = ["State File", "Resource Address", "Instance ID"]
+    for state_file, address, instance_id in rows:
+        list_of_strings.extend([f"`{state_file}`", f"`{address}`", f"`{instance_id}`"])
+
+    markdown_file.new_line()
+    markdown_file.new_table(
+        columns=3,
+        rows=len(rows) + 1,
+        text=list_of_strings,
+        text_align="center",
+    )
+    return markdown_file
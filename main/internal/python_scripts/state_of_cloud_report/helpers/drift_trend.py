@@ -0,0 +1,148 @@
+"""
+Helper functions for reporting the trend of IaC coverage and drift across job runs.
+"""
+from mdutils.mdutils import MdUtils
+
+MAX_TREND_RUNS = 10
+
+
+def run_totals(run_summary: dict) -> dict:
+    """Sum the per-division counts of a single run summary."""
+    divisions = run_summary.get("divisions") or []
+    return {
+        "run": (run_summary.get("generated_at") or "")[:10],
+        "managed": sum(division.get("managed", 0) for division in divisions),
+        "unmanaged": sum(division.get("unmanaged", 0) for division in divisions),
+        "drifted": sum(division.get("drifted", 0) for division in divisions),
+        "deleted": run_summary.get("deleted_resources", 0),
+        "coverage": run_summary.get("coverage", 100.0),
+    }
+
+
+def coverage_trend_sentence(drift_trend: list) -> str:
+    """Describe whether IaC coverage has improved since the previous run."""
+    if len(drift_trend) < 2:
+        return (
+            "This is the first recorded run, so no trend is available yet. "
+            "Coverage will be compared against this run going forward."
+        )
+
+    previous = drift_trend[-2].get("coverage", 100.0)
+    latest = drift_trend[-1].get("coverage", 100.0)
+    change = round(latest - previous, 1)
+    if change > 0:
+        return (
+            f"IaC coverage is improving, up {change} percentage points from {previous}% "
+            f"to {latest}% since the previous run."
+        )
+    if change < 0:
+        return (
+            f"IaC coverage is declining, down {-change} percentage points from {previous}% "
+            f"to {latest}% since the previous run."
+        )
+    return f"IaC coverage is unchanged at {latest}% since the previous run."
+
+
+def division_changes(drift_trend: list) -> list:
+    """
+    Compare each division's counts within the latest run against the previous run, returning sorted
+    (division, coverage, coverage change, unmanaged change, drifted change) rows.
+    """
+    if not drift_trend:
+        return []
+
+    def by_division(run_summary: dict) -> dict:
+        return {
+            f"{division['provider']}-{division['division']}": division
+            for division in run_summary.get("divisions") or []
+        }
+
+    latest = by_division(drift_trend[-1])
+    previous = by_division(drift_trend[-2]) if len(drift_trend) > 1 else {}
+
+    rows = []
+    for name, division in latest.items():
+        # Divisions first scanned within the latest run are reported without change.
+        before = previous.get(name, division)
+        rows.append(
+            (
+                name,
+                division.get("coverage", 100.0),
+                round(division.get("coverage", 100.0) - before.get("coverage", 100.0), 1),
+                division.get("unmanaged", 0) - before.get("unmanaged", 0),
+                division.get("drifted", 0) - before.get("drifted", 0),
+            )
+        )
+    return sorted(rows)
+
+
+def _signed(value) -> str:
+    return f"+{value}" if value > 0 else f"{value}"
+
+
+def create_markdown_drift_trend(drift_trend: list, markdown_file: MdUtils) -> MdUtils:
+    """
+    Create Markdown tables of the totals of recent runs, and of each division's change
+    since the previous run.
+    """
+    markdown_file.new_line(coverage_trend_sentence(drift_trend))
+
+    recent_runs = [
+        run_totals(run_summary) for run_summary in drift_trend[-MAX_TREND_RUNS:]
+    ]
+    list_of_strings = [
+        "Run",
+        "Managed",
+        "Unmanaged",
+        "Drifted",
+        "Deleted",
+        "Coverage (%)",
+    ]
+    for totals in recent_runs:
+        list_of_strings.extend(
+            [
+                totals["run"],
+                totals["managed"],
+                totals["unmanaged"],
+                totals["drifted"],
+                totals["deleted"],
+                totals["coverage"],
+            ]
+        )
+
+    markdown_file.new_line()
+    markdown_file.new_table(
+        columns=6,
+        rows=len(recent_runs) + 1,
+        text=list_of_strings,
+        text_align="center",
+    )
+
+    rows = division_changes(drift_trend)
+    if len(drift_trend) > 1 and rows:
+        list_of_strings = [
+            "Division",
+            "Coverage (%)",
+            "Coverage Change",
+            "Unmanaged Change",
+            "Drifted Change",
+        ]
+        for division, coverage, coverage_change, unmanaged_change, drifted_change in rows:
+            list_of_strings.extend(
+                [
+                    division,
+                    coverage,
+                    _signed(coverage_change),
+                    _signed(unmanaged_change),
+                    _signed(drifted_change),
+                ]
+            )
+
+        markdown_file.new_line()
+        markdown_file.new_table(
+            columns=5,
+            rows=len(rows) + 1,
+            text=list_of_strings,
+            text_align="center",
+        )
+    return markdown_file
@@ -0,0 +1,50 @@
+"""
+Helper functions for reporting cloud services that failed to be scanned.
+"""
+from mdutils.mdutils import MdUtils
+
+
+def failed_resource_group_rows(failed_resource_groups: list) -> list:
+    """
+    Flattens a json load of failed resource groups into sorted (division, region, resource group, resource types)
+    rows, deduplicated by division, region and resource group.
+    """
+    rows = {}
+    for failed_resource_group in failed_resource_groups or []:
+        key = (
+            failed_resource_group.get("division", ""),
+            failed_resource_group.get("region", ""),
+            failed_resource_group.get("resourceGroup", ""),
+        )
+        rows[key] = key + (
+            ", ".join(sorted(failed_resource_group.get("resourceTypes") or [])),
+        )
+
+    return [rows[key] for key in sorted(rows)]
+
+
+def create_markdown_table_failed_scans(
+    failed_resource_groups: list, markdown_file: MdUtils
+) -> MdUtils:
+    """Create a new Markdown table of the cloud services that failed to be scanned within each division and region"""
+    rows = failed_resource_group_rows(failed_resource_groups)
+
+    list_of_strings = ["Division", "Region", "Cloud Service", "Resource Types"]
+    for division, region, resource_group, resource_types in rows:
+        list_of_strings.extend(
+            [
+                f"`{division}`",
+                f"`{region}`" if region else "All",
+                f"`{resource_group}`",
+                resource_types,
+            ]
+        )
+
+    markdown_file.new_line()
+    markdown_file.new_table(
+        columns=4,
+        rows=len(rows) + 1,
+        text=list_of_strings,
+        text_align="center",
+    )
+    return markdown_file
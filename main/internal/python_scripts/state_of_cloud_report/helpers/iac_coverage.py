@@ -0,0 +1,53 @@
+"""
+Helper functions for reporting the share of scanned cloud resources under Terraform control.
+"""
+from mdutils.mdutils import MdUtils
+
+
+def iac_coverage_sentence(iac_coverage: dict) -> str:
+    """Describe the overall IaC coverage of the scanned cloud resources."""
+    managed = iac_coverage.get("managed", 0)
+    scanned = managed + iac_coverage.get("unmanaged", 0)
+    return (
+        f"{iac_coverage.get('coverage', 100.0)}% of the {scanned} scanned cloud resources "
+        f"({managed} resources) are under Terraform control."
+    )
+
+
+def iac_coverage_rows(iac_coverage: dict) -> list:
+    """
+    Convert the IaC coverage of each division into (division, managed, unmanaged, coverage) rows,
+    sorted by ascending coverage so that the least covered divisions are listed first.
+    """
+    rows = [
+        (
+            f"{division['provider']}-{division['division']}",
+            division.get("managed", 0),
+            division.get("unmanaged", 0),
+            division.get("coverage", 100.0),
+        )
+        for division in iac_coverage.get("divisions") or []
+    ]
+    return sorted(rows, key=lambda row: (row[3], row[0]))
+
+
+def create_markdown_iac_coverage(iac_coverage: dict, markdown_file: MdUtils) -> MdUtils:
+    """Create a Markdown summary and table of IaC coverage within each division."""
+    markdown_file.new_line(iac_coverage_sentence(iac_coverage))
+
+    rows = iac_coverage_rows(iac_coverage)
+    if not rows:
+        return markdown_file
+
+    list_of_strings = ["Division", "Managed", "Unmanaged", "Coverage (%)"]
+    for division, managed, unmanaged, coverage in rows:
+        list_of_strings.extend([f"`{division}`", managed, unmanaged, coverage])
+
+    markdown_file.new_line()
+    markdown_file.new_table(
+        columns=4,
+        rows=len(rows) + 1,
+        text=list_of_strings,
+        text_align="center",
+    )
+    return markdown_file
@@ -5,23 +5,71 @@ from typing import Tuple
 import pandas as pd
 from mdutils.mdutils import MdUtils
 
+REMEDIATION_IMPACT_DESCRIPTIONS = {
+    "in-place": "In-place update (estimated, confirm with terraform plan)",
+    "replacement": "Requires replacement",
+    "cascade": "Requires replacement, cascades to dependent resources",
+    "unknown": "Unknown, may require replacement",
+}
+
+ATTRIBUTE_CHANGE_DESCRIPTIONS = {
+    "updated": "Updated",
+    "added": "Added outside of Terraform",
+    "removed": "Removed outside of Terraform",
+}
+
+# Replaces the values of the attributes flagged as sensitive by the drift detector's shared sensitive patterns.
+SENSITIVE_VALUE_MASK = "(sensitive value)"
+
+
+def attribute_value(record: dict, key: str) -> str:
+    """Return the value of a drifted attribute, masked when the attribute is flagged as sensitive."""
+    value = record[key]
+    sensitive = record.get("Sensitive")
+    if pd.notna(sensitive) and bool(sensitive) and value:
+        return SENSITIVE_VALUE_MASK
+    return value
+
+
+def drift_cost_impact_by_instance(drift_cost_impact: list) -> dict:
+    """
+    Converts a json load of drift cost impacts into a dictionary keyed by (state file, instance id).
+    """
+    return {
+        (impact["StateFileName"], impact["InstanceID"]): impact
+        for impact in drift_cost_impact or []
+    }
+
+
+def format_cost_delta(delta: float, currency_symbol: str = "$") -> str:
+    """Format a monthly cost delta as a signed amount in the display currency."""
+    sign = "+" if delta >= 0 else "-"
+    return f"{sign}{currency_symbol}{abs(delta):,.2f}"
+
 
 def create_markdown_table_resource_attribute_changes(
     instance_attribute_changes_df: pd.DataFrame, markdown_file: MdUtils
 ) -> Tuple[MdUtils, str]:
-    """Create a new Markdown table out of actor_action_count_df"""
+    """Create a new Markdown table of the before and after value of each drifted attribute."""
+    include_change = "ChangeType" in instance_attribute_changes_df.columns
     list_of_strings = ["Attribute", "Terraform Value", "Cloud Value"]
-    for record in instance_attribute_changes_df.to_dict("records"):
-        list_of_strings.extend(
-            [
-                record["AttributeName"],
-                record["TerraformValue"],
-                record["CloudValue"],
-            ]
-        )
+    if include_change:
+        list_of_strings.append("Change")
+
+    for record in instance_attribute_changes_df.sort_values("AttributeName").to_dict(
+        "records"
+    ):
+        row = [
+            record["AttributeName"],
+            attribute_value(record, "TerraformValue"),
+            attribute_value(record, "CloudValue"),
+        ]
+        if include_change:
+            row.append(ATTRIBUTE_CHANGE_DESCRIPTIONS.get(record["ChangeType"], ""))
+        list_of_strings.extend(row)
 
     new_table_str = markdown_file.new_table(
-        columns=3,
+        columns=4 if include_change else 3,
         rows=len(instance_attribute_changes_df) + 1,
         text=list_of_strings,
         text_align="center",
@@ -29,10 +77,38 @@ def create_markdown_table_resource_attribute_changes(
     return markdown_file, new_table_str
 
 
+def create_module_attribution_lines(
+    instance_attribute_changes_df: pd.DataFrame, markdown_file: MdUtils
+) -> MdUtils:
+    """Add the source module and calling file of a resource declared within a module."""
+    for column, label in [
+        ("ModuleSource", "Source Module"),
+        ("ModuleCallingFile", "Module Called From"),
+    ]:
+        if column not in instance_attribute_changes_df.columns:
+            continue
+        value = instance_attribute_changes_df[column].dropna().unique()
+        if len(value) > 0 and value[0] != "":
+            markdown_file.new_line(f"**{label}**: `{value[0]}`")
+    return markdown_file
+
+
 def create_managed_drift_markdown(
-    managed_drift_df: pd.DataFrame, markdown_file: MdUtils
+    managed_drift_df: pd.DataFrame,
+    markdown_file: MdUtils,
+    drift_cost_impact: list = None,
+    currency_symbol: str = "$",
 ) -> MdUtils:
     """Create structured tables of managed drift data."""
+    instance_to_cost_impact = drift_cost_impact_by_instance(drift_cost_impact)
+    if instance_to_cost_impact:
+        total_delta = sum(
+            impact["MonthlyCostDelta"] for impact in instance_to_cost_impact.values()
+        )
+        markdown_file.new_line(
+            f"Drift changes the monthly cost of managed resources by `{format_cost_delta(total_delta, currency_symbol)}`."
+        )
+
     for state_file in managed_drift_df["StateFileName"].unique():
         markdown_file.new_header(
             level=2,
@@ -69,6 +145,24 @@ def create_managed_drift_markdown(
                     f"**Most Recent Non-Terraform Actor**: `{actor}`"
                 )
                 markdown_file.new_line(f"**Most Recent Action Date**: `{timestamp}`")
+                markdown_file = create_module_attribution_lines(
+                    instance_attribute_changes_df=instance_attribute_changes_df,
+                    markdown_file=markdown_file,
+                )
+                if "RemediationImpact" in instance_attribute_changes_df.columns:
+                    remediation_impact = instance_attribute_changes_df[
+                        "RemediationImpact"
+                    ].unique()[0]
+                    markdown_file.new_line(
+                        f"**Remediation Impact**: `{REMEDIATION_IMPACT_DESCRIPTIONS.get(remediation_impact, remediation_impact)}`"
+                    )
+                cost_impact = instance_to_cost_impact.get((state_file, instance_id))
+                if cost_impact:
+                    markdown_file.new_line(
+                        f"**Monthly Cost Impact**: `{format_cost_delta(cost_impact['MonthlyCostDelta'], currency_symbol)}` "
+                        f"(`{currency_symbol}{cost_impact['TerraformMonthlyCost']:,.2f}` in Terraform, "
+                        f"`{currency_symbol}{cost_impact['CloudMonthlyCost']:,.2f}` in the cloud)"
+                    )
                 markdown_file.new_line("")
                 markdown_file.new_line(f"- [ ] Completed")
                 markdown_file.new_line("")
@@ -257,6 +257,7 @@ def single_provider_costs_by_type_tabular_output(
 def process_pricing_data(
     divisions_to_cost_estimates: dict,
     new_resources: dict,
+    currency_symbol: str = "$",
 ) -> dict:
     """
     Process pricing data in the following format:
@@ -299,13 +300,20 @@ def process_pricing_data(
         new_resources=new_resources,
     )
 
-    combined_cost_summary_df = _calculate_aggregate_costs_across_scan(df)
+    combined_cost_summary_df = _calculate_aggregate_costs_across_scan(
+        df, currency_symbol=currency_symbol
+    )
+
+    uncontrolled_cost_by_div_by_type_df = _uncontrolled_cost_by_div_by_type(
+        df=df, currency_symbol=currency_symbol
+    )
 
-    uncontrolled_cost_by_div_by_type_df = _uncontrolled_cost_by_div_by_type(df=df)
+    cost_by_division_df = _cost_by_division(df=df, currency_symbol=currency_symbol)
 
     return {
         "cost_summary": combined_cost_summary_df,
         "uncontrolled_cost_by_div_by_type_df": uncontrolled_cost_by_div_by_type_df,
+        "cost_by_division": cost_by_division_df,
     }
 
 
@@ -343,7 +351,9 @@ def _dataframe_from_divisions_to_cost_estimates_dict(
     return df
 
 
-def _calculate_aggregate_costs_across_scan(df: pd.DataFrame) -> pd.DataFrame:
+def _calculate_aggregate_costs_across_scan(
+    df: pd.DataFrame, currency_symbol: str = "$"
+) -> pd.DataFrame:
     """
     Calculate aggregate cloud costs by provider split into whether the costs are controlled by Terraform
     or not.
@@ -396,14 +406,18 @@ def _calculate_aggregate_costs_across_scan(df: pd.DataFrame) -> pd.DataFrame:
         "Terraform Controlled Resources Monthly Cost",
         "Uncontrolled Resources Monthly Cost",
     ]:
-        combined_cost_summary_df[cost_column] = "$" + combined_cost_summary_df[
+        combined_cost_summary_df[
+            cost_column
+        ] = currency_symbol + combined_cost_summary_df[
             cost_column
         ].astype(str)
 
     return combined_cost_summary_df
 
 
-def _uncontrolled_cost_by_div_by_type(df: pd.DataFrame) -> pd.DataFrame:
+def _uncontrolled_cost_by_div_by_type(
+    df: pd.DataFrame, currency_symbol: str = "$"
+) -> pd.DataFrame:
     """Calculated uncontrolled cost by division and by resource type"""
     uncontrolled_cost_by_div_by_type_df = (
         df.query("is_new_resource == True")
@@ -418,7 +432,9 @@ def _uncontrolled_cost_by_div_by_type(df: pd.DataFrame) -> pd.DataFrame:
 
     uncontrolled_cost_by_div_by_type_df[
         "monthly_cost"
-    ] = "$" + uncontrolled_cost_by_div_by_type_df["monthly_cost"].round(2).astype(str)
+    ] = currency_symbol + uncontrolled_cost_by_div_by_type_df["monthly_cost"].round(
+        2
+    ).astype(str)
     uncontrolled_cost_by_div_by_type_df.loc[
         uncontrolled_cost_by_div_by_type_df["is_usage_based"], "monthly_cost"
     ] = (
@@ -428,9 +444,82 @@ def _uncontrolled_cost_by_div_by_type(df: pd.DataFrame) -> pd.DataFrame:
         + "*"
     )
 
+    if "is_unknown_cost" in df.columns:
+        unknown_cost_types = df.loc[
+            df["is_new_resource"] & (df["is_unknown_cost"] == True), "resource_type"
+        ].unique()
+        unknown_cost_mask = uncontrolled_cost_by_div_by_type_df["resource_type"].isin(
+            unknown_cost_types
+        )
+        uncontrolled_cost_by_div_by_type_df.loc[unknown_cost_mask, "monthly_cost"] = (
+            uncontrolled_cost_by_div_by_type_df.loc[unknown_cost_mask, "monthly_cost"]
+            + "†"
+        )
+
     return uncontrolled_cost_by_div_by_type_df
 
 
+def _cost_by_division(df: pd.DataFrame, currency_symbol: str = "$") -> pd.DataFrame:
+    """
+    Calculate monthly cloud costs by division, split into whether the costs are controlled by Terraform
+    or not.
+    """
+    df = df.assign(
+        uncontrolled_cost=df["monthly_cost"].where(df["is_new_resource"], 0.0),
+        controlled_cost=df["monthly_cost"].where(~df["is_new_resource"], 0.0),
+    )
+
+    cost_by_division_df = (
+        df.groupby(by=["provider", "division"])
+        .agg(
+            uncontrolled_cost=pd.NamedAgg(aggfunc="sum", column="uncontrolled_cost"),
+            controlled_cost=pd.NamedAgg(aggfunc="sum", column="controlled_cost"),
+        )
+        .reset_index()
+    )
+    cost_by_division_df["total_cost"] = (
+        cost_by_division_df["uncontrolled_cost"]
+        + cost_by_division_df["controlled_cost"]
+    )
+
+    for cost_column in ["uncontrolled_cost", "controlled_cost", "total_cost"]:
+        cost_by_division_df[cost_column] = currency_symbol + cost_by_division_df[
+            cost_column
+        ].round(2).astype(str)
+
+    return cost_by_division_df
+
+
+def create_markdown_table_cost_by_division(
+    cost_by_division_df: pd.DataFrame, markdown_file: MdUtils
+) -> MdUtils:
+    """Create a new Markdown table out of cost_by_division_df"""
+    list_of_strings = [
+        "Division",
+        "Uncontrolled Resources Cost",
+        "Terraform Controlled Resources Cost",
+        "Total Cost",
+    ]
+
+    for record in cost_by_division_df.to_dict("records"):
+        list_of_strings.extend(
+            [
+                record["division"],
+                record["uncontrolled_cost"],
+                record["controlled_cost"],
+                record["total_cost"],
+            ]
+        )
+
+    _ = markdown_file.new_table(
+        columns=4,
+        rows=len(cost_by_division_df) + 1,
+        text=list_of_strings,
+        text_align="center",
+    )
+    return markdown_file
+
+
 def create_markdown_table_cost_summary(
     cost_summary_df: pd.DataFrame, markdown_file: MdUtils
 ) -> MdUtils:
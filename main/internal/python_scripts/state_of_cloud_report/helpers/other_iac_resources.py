@@ -0,0 +1,57 @@
+"""
+Helper functions for reporting new resources owned by IaC systems other than Terraform.
+"""
+from mdutils.mdutils import MdUtils
+
+
+def other_iac_resource_rows(other_iac_resources: list) -> list:
+    """
+    Converts a json load of resources owned by other IaC systems into sorted
+    (owner, stack or cluster, division, resource type, resource id) rows.
+    """
+    rows = []
+    for resource in other_iac_resources or []:
+        rows.append(
+            (
+                resource["Owner"],
+                resource.get("Detail") or "-",
+                resource["Division"],
+                resource["ResourceType"],
+                resource["ResourceID"],
+            )
+        )
+
+    return sorted(rows)
+
+
+def create_markdown_table_other_iac_resources(
+    other_iac_resources: list, markdown_file: MdUtils
+) -> MdUtils:
+    """Create a new Markdown table of the new resources owned by other IaC systems"""
+    rows = other_iac_resource_rows(other_iac_resources)
+
+    markdown_file.new_line(
+        "The following resources are owned by CloudFormation, Pulumi, or a Kubernetes controller, so no "
+        "Terraform code or import statements have been generated for them."
+    )
+
+    list_of_strings = [
+        "Owner",
+        "Stack or Cluster",
+        "Division",
+        "Resource Type",
+        "Resource ID",
+    ]
+    for owner, detail, division, resource_type, resource_id in rows:
+        list_of_strings.extend(
+            [owner, f"`{detail}`", division, resource_type, f"`{resource_id}`"]
+        )
+
+    markdown_file.new_line()
+    markdown_file.new_table(
+        columns=5,
+        rows=len(rows) + 1,
+        text=list_of_strings,
+        text_align="center",
+    )
+    return markdown_file
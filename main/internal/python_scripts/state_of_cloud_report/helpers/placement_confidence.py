@@ -0,0 +1,100 @@
+"""
+Helper functions for reporting the confidence of new resource placements within workspaces.
+"""
+from mdutils.mdutils import MdUtils
+
+from .resource_names import generated_resource_name
+
+
+def placement_rows(
+    new_resources_to_workspace: dict,
+    placement_confidence: dict,
+    resource_names: dict = None,
+) -> list:
+    """
+    Converts json loads of new resources to workspace and of their placement confidence into
+    (workspace, resource, confidence) rows, sorted by workspace and then by ascending confidence.
+    """
+    rows = []
+    for resource, workspace in (new_resources_to_workspace or {}).items():
+        if resource in (placement_confidence or {}):
+            rows.append(
+                (
+                    workspace,
+                    generated_resource_name(resource, resource_names),
+                    placement_confidence[resource],
+                )
+            )
+
+    return sorted(rows, key=lambda row: (row[0], row[2], row[1]))
+
+
+def manual_placement_rows(needs_manual_placement: dict) -> list:
+    """
+    Converts a json load of new resources needing manual placement into
+    (resource, suggested workspace, confidence) rows, sorted by ascending confidence.
+    """
+    rows = []
+    for resource, placement in (needs_manual_placement or {}).items():
+        rows.append(
+            (resource, placement["SuggestedWorkspace"], placement["Confidence"])
+        )
+
+    return sorted(rows, key=lambda row: (row[2], row[0]))
+
+
+def create_markdown_table_placement_confidence(
+    new_resources_to_workspace: dict,
+    placement_confidence: dict,
+    markdown_file: MdUtils,
+    resource_names: dict = None,
+) -> MdUtils:
+    """Create a new Markdown table of the confidence of each new resource's workspace placement"""
+    rows = placement_rows(
+        new_resources_to_workspace, placement_confidence, resource_names
+    )
+
+    markdown_file.new_line(
+        "Each new resource is placed within the workspace whose resources it most resembles. A confidence of 1 "
+        "indicates a placement by an ownership tag, a placement rule or an override within "
+        ".cloud-concierge/placements.yaml."
+    )
+
+    list_of_strings = ["Workspace", "Resource", "Confidence"]
+    for workspace, resource, confidence in rows:
+        list_of_strings.extend([workspace, f"`{resource}`", f"{confidence:.2f}"])
+
+    markdown_file.new_line()
+    markdown_file.new_table(
+        columns=3,
+        rows=len(rows) + 1,
+        text=list_of_strings,
+        text_align="center",
+    )
+    return markdown_file
+
+
+def create_markdown_table_manual_placement(
+    needs_manual_placement: dict, markdown_file: MdUtils
+) -> MdUtils:
+    """Create a new Markdown table of the new resources needing manual placement"""
+    rows = manual_placement_rows(needs_manual_placement)
+
+    markdown_file.new_line(
+        "The following resources could not be placed within a workspace with sufficient confidence, so no "
+        "Terraform code or import statements have been generated for them. Record their workspace within "
+        ".cloud-concierge/placements.yaml, or cover them with a placement rule."
+    )
+
+    list_of_strings = ["Resource", "Suggested Workspace", "Confidence"]
+    for resource, workspace, confidence in rows:
+        list_of_strings.extend([f"`{resource}`", workspace, f"{confidence:.2f}"])
+
+    markdown_file.new_line()
+    markdown_file.new_table(
+        columns=3,
+        rows=len(rows) + 1,
+        text=list_of_strings,
+        text_align="center",
+    )
+    return markdown_file
@@ -0,0 +1,44 @@
+"""
+Helper functions for reporting violations of user-supplied Rego policies.
+"""
+from mdutils.mdutils import MdUtils
+
+from .resource_names import generated_resource_name
+
+
+def policy_violation_rows(policy_violations: list, resource_names: dict = None) -> list:
+    """
+    Converts a json load of policy violations into (resource, message) rows.
+    """
+    return [
+        (
+            generated_resource_name(violation.get("resource") or "-", resource_names),
+            violation["message"],
+        )
+        for violation in policy_violations or []
+    ]
+
+
+def create_markdown_table_policy_violations(
+    policy_violations: list, markdown_file: MdUtils, resource_names: dict = None
+) -> MdUtils:
+    """Create a new Markdown table of the violations of user-supplied policies"""
+    rows = policy_violation_rows(policy_violations, resource_names)
+
+    markdown_file.new_line(
+        "The new resources, drift, costs and security findings identified by this run violate the "
+        "following policies of your organization."
+    )
+
+    list_of_strings = ["Resource", "Violation"]
+    for resource, message in rows:
+        list_of_strings.extend([f"`{resource}`" if resource != "-" else "-", message])
+
+    markdown_file.new_line()
+    markdown_file.new_table(
+        columns=2,
+        rows=len(rows) + 1,
+        text=list_of_strings,
+        text_align="center",
+    )
+    return markdown_file
@@ -0,0 +1,27 @@
+"""
+Helper functions for referring to new resources by their names within the generated Terraform code.
+"""
+
+
+def generated_resource_name(resource: str, resource_names: dict) -> str:
+    """
+    Returns the resource, identified either as division.type.terraformer-name or as type.terraformer-name, with its
+    name within the generated Terraform code. A type.terraformer-name resource whose name differs between divisions,
+    or a resource without generated code, is returned unchanged.
+    """
+    if not resource_names:
+        return resource
+
+    if resource in resource_names:
+        division, resource_type, _ = resource.split(".", 2)
+        return f"{division}.{resource_type}.{resource_names[resource]}"
+
+    names = set()
+    for key, name in resource_names.items():
+        _, resource_type, terraformer_name = key.split(".", 2)
+        if f"{resource_type}.{terraformer_name}" == resource:
+            names.add(f"{resource_type}.{name}")
+
+    if len(names) == 1:
+        return names.pop()
+    return resource
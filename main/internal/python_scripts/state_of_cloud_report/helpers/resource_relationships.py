@@ -0,0 +1,76 @@
+"""
+Helper functions for diagramming the relationships between new resources within each workspace.
+"""
+import re
+
+from mdutils.mdutils import MdUtils
+
+MAX_DIAGRAM_RESOURCES = 100
+
+
+def mermaid_node_id(address: str) -> str:
+    """Convert a resource address into a Mermaid node identifier."""
+    return re.sub(r"[^A-Za-z0-9_]", "_", address)
+
+
+def diagram_addresses(relationships: dict) -> list:
+    """
+    Select the resource addresses to diagram. When a workspace has more resources than can be legibly
+    diagrammed, only the resources referencing, or referenced by, another resource are drawn.
+    """
+    addresses = sorted(relationships)
+    if len(addresses) <= MAX_DIAGRAM_RESOURCES:
+        return addresses
+
+    related = set()
+    for address, references in relationships.items():
+        if references:
+            related.add(address)
+            related.update(references)
+    return sorted(related)[:MAX_DIAGRAM_RESOURCES]
+
+
+def mermaid_diagram(relationships: dict) -> str:
+    """
+    Create a Mermaid flowchart of the resources within a workspace, with an edge from each resource to
+    each of the resources it references.
+    """
+    addresses = diagram_addresses(relationships)
+    drawn = set(addresses)
+
+    lines = ["flowchart LR"]
+    for address in addresses:
+        lines.append(f'    {mermaid_node_id(address)}["{address}"]')
+    for address in addresses:
+        for reference in relationships.get(address) or []:
+            if reference in drawn:
+                lines.append(
+                    f"    {mermaid_node_id(address)} --> {mermaid_node_id(reference)}"
+                )
+    return "\n".join(lines)
+
+
+def create_markdown_resource_relationships(
+    workspace_to_relationships: dict, markdown_file: MdUtils
+) -> MdUtils:
+    """Create a Mermaid diagram of the relationships between the new resources of each workspace."""
+    markdown_file.new_line(
+        "Each new resource is connected to the new resources of the same workspace it references."
+    )
+
+    for workspace, relationships in sorted(workspace_to_relationships.items()):
+        if not relationships:
+            continue
+
+        markdown_file.new_header(
+            level=3, title=f"Workspace `{workspace}`", add_table_of_contents="n"
+        )
+        omitted = len(relationships) - len(diagram_addresses(relationships))
+        if omitted > 0:
+            markdown_file.new_line(
+                f"{omitted} resources without relationships, or beyond the first "
+                f"{MAX_DIAGRAM_RESOURCES} resources, are not shown."
+            )
+        markdown_file.insert_code(mermaid_diagram(relationships), language="mermaid")
+
+    return markdown_file
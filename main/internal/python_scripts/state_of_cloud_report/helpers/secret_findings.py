@@ -0,0 +1,52 @@
+"""
+Helper functions for reporting secrets redacted from the generated code.
+"""
+from mdutils.mdutils import MdUtils
+
+
+def secret_finding_rows(secret_findings: list) -> list:
+    """
+    Converts a json load of redacted secrets into (file, line, kind, variable) rows.
+    """
+    return [
+        (
+            finding["file"],
+            finding["line"],
+            finding["description"],
+            finding.get("variable") or "-",
+        )
+        for finding in secret_findings or []
+    ]
+
+
+def create_markdown_table_secret_findings(
+    secret_findings: list, markdown_file: MdUtils
+) -> MdUtils:
+    """Create a new Markdown table of the secrets redacted from the generated code"""
+    rows = secret_finding_rows(secret_findings)
+
+    markdown_file.new_line(
+        "The following secrets were identified within the generated code and replaced with references to "
+        "sensitive variables, which should be supplied outside of version control. Consider rotating them, "
+        "as they are readable by anyone with access to the scanned cloud resources."
+    )
+
+    list_of_strings = ["File", "Line", "Secret", "Variable"]
+    for file, line, description, variable in rows:
+        list_of_strings.extend(
+            [
+                f"`{file}`",
+                str(line),
+                description,
+                f"`var.{variable}`" if variable != "-" else "-",
+            ]
+        )
+
+    markdown_file.new_line()
+    markdown_file.new_table(
+        columns=4,
+        rows=len(rows) + 1,
+        text=list_of_strings,
+        text_align="center",
+    )
+    return markdown_file
@@ -4,6 +4,8 @@ Helper functions for formatting security scan results.
 import pandas as pd
 from mdutils.mdutils import MdUtils
 
+from .resource_names import generated_resource_name
+
 
 def division_to_security_scan_to_df_dict(divisions_to_security_scan: dict) -> dict:
     """
@@ -45,6 +47,16 @@ def _security_scan_to_df(list_of_dicts: list) -> pd.DataFrame:
     return data_df
 
 
+def _doc_links(links: list) -> str:
+    """Formats the documentation links of a security scan result, which custom checks may not have"""
+    links = links or []
+    if len(links) > 1:
+        return f"[Rule]({links[0]}), [Tf Doc]({links[1]})"
+    if len(links) == 1:
+        return f"[Rule]({links[0]})"
+    return "-"
+
+
 def create_markdown_table_security_scans(
     division_to_security_df_dict: dict, markdown_file: MdUtils
 ) -> MdUtils:
@@ -74,7 +86,7 @@ def create_markdown_table_security_scans(
                             record["rule_description"],
                             record["severity"],
                             record["resolution"],
-                            f'[Rule]({record["links"][0]}), [Tf Doc]({record["links"][1]})' if len(record["links"]) > 1 else f'[Rule]({record["links"][0]})'
+                            _doc_links(record["links"]),
                         ]
                     )
 
@@ -85,3 +97,101 @@ def create_markdown_table_security_scans(
                     text_align="center",
                 )
     return markdown_file
+
+
+def security_gate_rows(security_gate: dict, resource_names: dict = None) -> list:
+    """
+    Converts the findings of a json load of the security gate into (division, resource, severity, rule) rows.
+    """
+    return [
+        (
+            finding["division"],
+            generated_resource_name(finding["resource"], resource_names),
+            finding["severity"],
+            f'{finding["rule_id"]}: {finding["rule_description"]}',
+        )
+        for finding in security_gate.get("findings") or []
+    ]
+
+
+def create_markdown_security_gate(
+    security_gate: dict, markdown_file: MdUtils, resource_names: dict = None
+) -> MdUtils:
+    """Create a new Markdown call out of the newly generated resources with findings at or above the threshold"""
+    rows = security_gate_rows(security_gate, resource_names)
+
+    markdown_file.new_line(
+        f"**{len(rows)} security finding(s) at or above the `{security_gate['threshold']}` severity threshold "
+        "were identified within the newly generated resources of this pull request.** Please resolve them "
+        "before merging."
+    )
+
+    list_of_strings = ["Division", "Resource", "Severity", "Rule"]
+    for division, resource, severity, rule in rows:
+        list_of_strings.extend([f"`{division}`", f"`{resource}`", severity, rule])
+
+    markdown_file.new_line()
+    markdown_file.new_table(
+        columns=4,
+        rows=len(rows) + 1,
+        text=list_of_strings,
+        text_align="center",
+    )
+    return markdown_file
+
+
+SCOPE_TITLES = {
+    "changed": "Changed Directories",
+    "repository": "Entire Repository",
+}
+
+
+def scope_security_rows(directory_to_results: dict) -> list:
+    """
+    Converts the findings of a json load of a repository scan scope into (directory, resource, severity, rule,
+    doc links) rows.
+    """
+    rows = []
+    for directory, results in sorted((directory_to_results or {}).items()):
+        for result in results or []:
+            rows.append(
+                (
+                    directory,
+                    result["resource"],
+                    result["severity"],
+                    f'{result["rule_id"]}: {result["rule_description"]}',
+                    _doc_links(result.get("links")),
+                )
+            )
+    return sorted(rows, key=lambda row: (row[0], row[1], row[3]))
+
+
+def create_markdown_security_scope(
+    scope: str, directory_to_results: dict, markdown_file: MdUtils
+) -> MdUtils:
+    """Create a new Markdown section out of the findings of a repository scan scope"""
+    markdown_file.new_header(
+        level=1,
+        title=f"Identified Security Risks: {SCOPE_TITLES.get(scope, scope)}",
+        style="atx",
+    )
+
+    rows = scope_security_rows(directory_to_results)
+    if not rows:
+        markdown_file.new_line("No security risks identified.")
+        return markdown_file
+
+    list_of_strings = ["Directory", "Resource", "Severity", "Rule", "Doc Links"]
+    for directory, resource, severity, rule, links in rows:
+        list_of_strings.extend(
+            [f"`{directory}`", f"`{resource}`", severity, rule, links]
+        )
+
+    markdown_file.new_line()
+    markdown_file.new_table(
+        columns=5,
+        rows=len(rows) + 1,
+        text=list_of_strings,
+        text_align="center",
+    )
+    return markdown_file
@@ -0,0 +1,59 @@
+"""
+Helper functions for reporting validation failures of the generated code.
+"""
+from mdutils.mdutils import MdUtils
+
+
+def validation_failure_rows(validation_failures: list) -> list:
+    """
+    Converts a json load of validation failures into (workspace, validator, severity, location, message) rows.
+    """
+    rows = []
+    for failure in validation_failures or []:
+        location = failure.get("file") or "-"
+        if failure.get("file") and failure.get("line"):
+            location = f'{failure["file"]}:{failure["line"]}'
+
+        rows.append(
+            (
+                failure["workspace"],
+                failure["validator"],
+                failure["severity"],
+                location,
+                failure["message"],
+            )
+        )
+    return rows
+
+
+def create_markdown_table_validation_failures(
+    validation_failures: list, markdown_file: MdUtils
+) -> MdUtils:
+    """Create a new Markdown table of the validation failures of the generated code"""
+    rows = validation_failure_rows(validation_failures)
+
+    markdown_file.new_line(
+        "The generated code did not pass validation within the following workspaces. Please review and fix "
+        "these issues before merging."
+    )
+
+    list_of_strings = ["Workspace", "Validator", "Severity", "Location", "Message"]
+    for workspace, validator, severity, location, message in rows:
+        list_of_strings.extend(
+            [
+                f"`{workspace}`",
+                validator,
+                severity,
+                f"`{location}`" if location != "-" else "-",
+                message,
+            ]
+        )
+
+    markdown_file.new_line()
+    markdown_file.new_table(
+        columns=5,
+        rows=len(rows) + 1,
+        text=list_of_strings,
+        text_align="center",
+    )
+    return markdown_file
@@ -0,0 +1,268 @@
+"""
+Helper functions for calculating a composite health score per Terraform workspace
+within the state of cloud report.
+"""
+import json
+import os
+
+import pandas as pd
+from mdutils.mdutils import MdUtils
+
+COMPONENT_WEIGHTS = {
+    "drift_score": 0.35,
+    "coverage_score": 0.35,
+    "security_score": 0.2,
+    "cost_score": 0.1,
+}
+
+# Monthly uncontrolled cost at which a workspace's cost score reaches 0, when no budget is configured.
+DEFAULT_WORKSPACE_MONTHLY_COST_BUDGET = 1000.0
+
+SEVERITY_PENALTIES = {
+    "CRITICAL": 10,
+    "HIGH": 5,
+    "MEDIUM": 2,
+    "LOW": 1,
+}
+
+
+def calculate_workspace_health_scores(
+    managed_drift_df: pd.DataFrame,
+    new_resources_to_workspace: dict,
+    workspace_to_managed_count: dict,
+    divisions_to_security_scan: dict,
+    divisions_to_cost_estimates: dict,
+    workspace_monthly_cost_budget: float = DEFAULT_WORKSPACE_MONTHLY_COST_BUDGET,
+) -> pd.DataFrame:
+    """
+    Combine drift, coverage, security and cost signals into a single 0-100 health score per workspace.
+
+    Each component is itself scored between 0 and 100:
+    - drift_score: share of managed resources that have not drifted.
+    - coverage_score: share of the workspace's resources that are under Terraform control.
+    - security_score: 100 less a severity weighted penalty for each security finding.
+    - cost_score: 100 less the workspace's uncontrolled monthly cost as a share of the monthly cost
+      budget, reaching 0 once the budget is spent.
+
+    Returns a dataframe ranked from the healthiest to the least healthy workspace.
+    """
+    workspaces = set(workspace_to_managed_count.keys())
+    workspaces.update(new_resources_to_workspace.values())
+    if not managed_drift_df.empty:
+        workspaces.update(managed_drift_df["StateFileName"].unique())
+
+    if not workspaces:
+        return pd.DataFrame()
+
+    drifted_count = _drifted_instances_by_workspace(managed_drift_df)
+    unmanaged_count = _unmanaged_resources_by_workspace(new_resources_to_workspace)
+    security_penalty = _security_penalty_by_workspace(
+        divisions_to_security_scan, new_resources_to_workspace
+    )
+    uncontrolled_cost = _uncontrolled_cost_by_workspace(
+        divisions_to_cost_estimates, new_resources_to_workspace
+    )
+    if not workspace_monthly_cost_budget or workspace_monthly_cost_budget <= 0:
+        workspace_monthly_cost_budget = DEFAULT_WORKSPACE_MONTHLY_COST_BUDGET
+
+    list_of_dicts = []
+    for workspace in workspaces:
+        managed = workspace_to_managed_count.get(workspace, 0)
+        unmanaged = unmanaged_count.get(workspace, 0)
+        drifted = min(drifted_count.get(workspace, 0), managed)
+
+        coverage_score = (
+            100.0 * managed / (managed + unmanaged) if managed + unmanaged else 100.0
+        )
+        drift_score = 100.0 * (1 - drifted / managed) if managed else 100.0
+        security_score = max(0.0, 100.0 - security_penalty.get(workspace, 0))
+        cost_score = max(
+            0.0,
+            100.0
+            * (1 - uncontrolled_cost.get(workspace, 0.0) / workspace_monthly_cost_budget),
+        )
+
+        record = {
+            "workspace": workspace,
+            "drift_score": round(drift_score, 1),
+            "coverage_score": round(coverage_score, 1),
+            "security_score": round(security_score, 1),
+            "cost_score": round(cost_score, 1),
+        }
+        record["health_score"] = round(
+            sum(record[column] * weight for column, weight in COMPONENT_WEIGHTS.items()),
+            1,
+        )
+        list_of_dicts.append(record)
+
+    health_df = pd.DataFrame(list_of_dicts)
+    health_df = health_df.sort_values(
+        by=["health_score", "workspace"], ascending=[False, True]
+    ).reset_index(drop=True)
+    health_df.insert(0, "rank", range(1, len(health_df) + 1))
+
+    return health_df
+
+
+def managed_resource_count_by_workspace(state_files_directory: str) -> dict:
+    """Count the number of managed resource instances within each workspace's state file."""
+    workspace_to_managed_count = {}
+    if not os.path.isdir(state_files_directory):
+        return workspace_to_managed_count
+
+    for file_name in sorted(os.listdir(state_files_directory)):
+        if not file_name.endswith(".json"):
+            continue
+
+        with open(os.path.join(state_files_directory, file_name), "r") as json_file:
+            state = json.loads(json_file.read())
+
+        count = 0
+        for resource in state.get("resources", []):
+            if resource.get("mode") == "managed":
+                count += len(resource.get("instances", []))
+
+        workspace_to_managed_count[file_name[: -len(".json")]] = count
+
+    return workspace_to_managed_count
+
+
+def create_markdown_table_workspace_health(
+    health_df: pd.DataFrame, markdown_file: MdUtils
+) -> MdUtils:
+    """Create a ranked league table of workspace health scores."""
+    list_of_strings = [
+        "Rank",
+        "Workspace",
+        "Health Score",
+        "Drift",
+        "Coverage",
+        "Security",
+        "Cost",
+    ]
+    for record in health_df.to_dict("records"):
+        list_of_strings.extend(
+            [
+                record["rank"],
+                record["workspace"],
+                record["health_score"],
+                record["drift_score"],
+                record["coverage_score"],
+                record["security_score"],
+                record["cost_score"],
+            ]
+        )
+
+    markdown_file.new_line()
+    markdown_file.new_table(
+        columns=7,
+        rows=len(health_df) + 1,
+        text=list_of_strings,
+        text_align="center",
+    )
+    return markdown_file
+
+
+def create_open_metrics_workspace_health(health_df: pd.DataFrame) -> str:
+    """
+    Render the workspace health scores in the OpenMetrics text format, e.g. for a node exporter
+    textfile collector or a Pushgateway.
+    """
+    families = [
+        (
+            "cloud_concierge_workspace_health_score",
+            "health_score",
+            "Composite health score of the workspace, from 0 to 100.",
+        ),
+        (
+            "cloud_concierge_workspace_drift_score",
+            "drift_score",
+            "Share of the workspace's managed resources that have not drifted, from 0 to 100.",
+        ),
+        (
+            "cloud_concierge_workspace_coverage_score",
+            "coverage_score",
+            "Share of the workspace's resources under Terraform control, from 0 to 100.",
+        ),
+        (
+            "cloud_concierge_workspace_security_score",
+            "security_score",
+            "Security score of the workspace, from 0 to 100.",
+        ),
+        (
+            "cloud_concierge_workspace_cost_score",
+            "cost_score",
+            "Share of the monthly cost budget left after the workspace's uncontrolled cost, from 0 to 100.",
+        ),
+    ]
+    records = health_df.to_dict("records") if not health_df.empty else []
+
+    lines = []
+    for name, column, help_text in families:
+        lines.append(f"# TYPE {name} gauge")
+        lines.append(f"# HELP {name} {help_text}")
+        for record in records:
+            lines.append(
+                f'{name}{{workspace="{_open_metrics_label_value(record["workspace"])}"}} {record[column]}'
+            )
+    lines.append("# EOF")
+    return "\n".join(lines) + "\n"
+
+
+def _open_metrics_label_value(value: str) -> str:
+    return str(value).replace("\\", "\\\\").replace('"', '\\"').replace("\n", "\\n")
+
+
+def _drifted_instances_by_workspace(managed_drift_df: pd.DataFrame) -> dict:
+    if managed_drift_df.empty:
+        return {}
+
+    drifted_instances_df = managed_drift_df.drop_duplicates(
+        subset=["StateFileName", "ModuleName", "ResourceType", "ResourceName"]
+    )
+    return drifted_instances_df.groupby("StateFileName").size().to_dict()
+
+
+def _unmanaged_resources_by_workspace(new_resources_to_workspace: dict) -> dict:
+    unmanaged_count = {}
+    for workspace in new_resources_to_workspace.values():
+        unmanaged_count[workspace] = unmanaged_count.get(workspace, 0) + 1
+    return unmanaged_count
+
+
+def _security_penalty_by_workspace(
+    divisions_to_security_scan: dict, new_resources_to_workspace: dict
+) -> dict:
+    security_penalty = {}
+    for division, results in divisions_to_security_scan.items():
+        for result in results or []:
+            resource_type, resource_name = result["resource"].split(".")[:2]
+            provider = resource_type.split("_")[0]
+            workspace = new_resources_to_workspace.get(
+                f"{provider}-{division}.{resource_type}.{resource_name}"
+            )
+            if workspace is None:
+                continue
+
+            security_penalty[workspace] = security_penalty.get(
+                workspace, 0
+            ) + SEVERITY_PENALTIES.get(result.get("severity", "").upper(), 0)
+    return security_penalty
+
+
+def _uncontrolled_cost_by_workspace(
+    divisions_to_cost_estimates: dict, new_resources_to_workspace: dict
+) -> dict:
+    uncontrolled_cost = {}
+    for division, cost_items in divisions_to_cost_estimates.items():
+        for cost_item in cost_items or []:
+            workspace = new_resources_to_workspace.get(
+                f"{division}.{cost_item['resource_name']}"
+            )
+            if workspace is None:
+                continue
+
+            uncontrolled_cost[workspace] = uncontrolled_cost.get(workspace, 0.0) + float(
+                cost_item.get("monthly_cost") or 0.0
+            )
+    return uncontrolled_cost
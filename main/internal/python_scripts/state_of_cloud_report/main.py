@@ -15,19 +15,44 @@ from helpers.cloud_actor_identificaton import (
     create_markdown_table_cloud_actor_summary,
     process_cloud_actor_actions,
 )
+from helpers.cost_rollups import create_markdown_cost_rollups
+from helpers.currency import currency_conversion_note, currency_symbol
 from helpers.new_resources_and_cost_estimation import (
+    create_markdown_table_cost_by_division,
     create_markdown_table_cost_summary,
     create_new_resource_tabular_breakdowns_with_cost,
     process_new_resources,
     process_pricing_data,
 )
+from helpers.deleted_resources import create_markdown_table_deleted_resources
+from helpers.drift_trend import create_markdown_drift_trend
+from helpers.failed_scans import create_markdown_table_failed_scans
+from helpers.iac_coverage import create_markdown_iac_coverage
 from helpers.managed_resource_drift import (
     create_managed_drift_markdown,
 )
+from helpers.other_iac_resources import create_markdown_table_other_iac_resources
+from helpers.placement_confidence import (
+    create_markdown_table_manual_placement,
+    create_markdown_table_placement_confidence,
+)
+from helpers.policy_violations import create_markdown_table_policy_violations
+from helpers.resource_relationships import create_markdown_resource_relationships
+from helpers.secret_findings import create_markdown_table_secret_findings
 from helpers.security_scanning import (
+    create_markdown_security_gate,
+    create_markdown_security_scope,
     create_markdown_table_security_scans,
     division_to_security_scan_to_df_dict,
 )
+from helpers.validation_failures import create_markdown_table_validation_failures
+from helpers.workspace_health import (
+    DEFAULT_WORKSPACE_MONTHLY_COST_BUDGET,
+    calculate_workspace_health_scores,
+    create_open_metrics_workspace_health,
+    create_markdown_table_workspace_health,
+    managed_resource_count_by_workspace,
+)
 
 
 def create_markdown_file(job_name: str, markdown_text_output_path):
@@ -57,9 +82,130 @@ def create_markdown_file(job_name: str, markdown_text_output_path):
             + managed_drift_df["ResourceName"]
             + '"'
         )
+        if "InstanceKey" in managed_drift_df.columns:
+            managed_drift_df["ResourcePath"] += managed_drift_df["InstanceKey"].fillna("")
     else:
         managed_drift_df = pd.DataFrame()
 
+    deleted_resources = []
+    if os.path.exists("mappings/drift-resources-deleted.json"):
+        with open("mappings/drift-resources-deleted.json", "r") as json_file:
+            deleted_resources = json.loads(json_file.read()) or []
+
+    drift_cost_impact = []
+    if os.path.exists("mappings/drift-cost-impact.json"):
+        with open("mappings/drift-cost-impact.json", "r") as json_file:
+            drift_cost_impact = json.loads(json_file.read()) or []
+
+    cost_currency = {}
+    if os.path.exists("mappings/cost-currency.json"):
+        with open("mappings/cost-currency.json", "r") as json_file:
+            cost_currency = json.loads(json_file.read()) or {}
+
+    cost_rollups = {}
+    if os.path.exists("mappings/cost-rollups.json"):
+        with open("mappings/cost-rollups.json", "r") as json_file:
+            cost_rollups = json.loads(json_file.read()) or {}
+
+    security_gate = {}
+    if os.path.exists("mappings/security-gate.json"):
+        with open("mappings/security-gate.json", "r") as json_file:
+            security_gate = json.loads(json_file.read()) or {}
+
+    scope_to_security_scan = {}
+    if os.path.exists("mappings/scope-to-security-scan.json"):
+        with open("mappings/scope-to-security-scan.json", "r") as json_file:
+            scope_to_security_scan = json.loads(json_file.read()) or {}
+
+    validation_failures = []
+    if os.path.exists("mappings/validation-failures.json"):
+        with open("mappings/validation-failures.json", "r") as json_file:
+            validation_failures = json.loads(json_file.read()) or []
+
+    secret_findings = []
+    if os.path.exists("mappings/secret-findings.json"):
+        with open("mappings/secret-findings.json", "r") as json_file:
+            secret_findings = json.loads(json_file.read()) or []
+
+    policy_violations = []
+    if os.path.exists("mappings/policy-violations.json"):
+        with open("mappings/policy-violations.json", "r") as json_file:
+            policy_violations = json.loads(json_file.read()) or []
+
+    failed_resource_groups = []
+    if os.path.exists("mappings/failed-resource-groups.json"):
+        with open("mappings/failed-resource-groups.json", "r") as json_file:
+            failed_resource_groups = json.loads(json_file.read()) or []
+
+    other_iac_resources = []
+    if os.path.exists("mappings/other-iac-resources.json"):
+        with open("mappings/other-iac-resources.json", "r") as json_file:
+            other_iac_resources = json.loads(json_file.read()) or []
+
+    iac_coverage = {}
+    if os.path.exists("mappings/iac-coverage.json"):
+        with open("mappings/iac-coverage.json", "r") as json_file:
+            iac_coverage = json.loads(json_file.read()) or {}
+
+    drift_trend = []
+    if os.path.exists("mappings/drift-trend.json"):
+        with open("mappings/drift-trend.json", "r") as json_file:
+            drift_trend = json.loads(json_file.read()) or []
+
+    workspace_cost_budget = {}
+    if os.path.exists("mappings/workspace-cost-budget.json"):
+        with open("mappings/workspace-cost-budget.json", "r") as json_file:
+            workspace_cost_budget = json.loads(json_file.read()) or {}
+    workspace_monthly_cost_budget = workspace_cost_budget.get("MonthlyBudget", 0)
+    if workspace_monthly_cost_budget <= 0:
+        workspace_monthly_cost_budget = DEFAULT_WORKSPACE_MONTHLY_COST_BUDGET
+
+    new_resources_to_workspace = {}
+    if os.path.exists("mappings/new-resources-to-workspace.json"):
+        with open("mappings/new-resources-to-workspace.json", "r") as json_file:
+            new_resources_to_workspace = json.loads(json_file.read())
+
+    new_resources_relationships = {}
+    if os.path.exists("mappings/new-resources-relationships.json"):
+        with open("mappings/new-resources-relationships.json", "r") as json_file:
+            new_resources_relationships = json.loads(json_file.read()) or {}
+
+    placement_confidence = {}
+    if os.path.exists("mappings/new-resources-to-placement-confidence.json"):
+        with open(
+            "mappings/new-resources-to-placement-confidence.json", "r"
+        ) as json_file:
+            placement_confidence = json.loads(json_file.read()) or {}
+
+    resource_names = {}
+    if os.path.exists("mappings/new-resources-to-names.json"):
+        with open("mappings/new-resources-to-names.json", "r") as json_file:
+            resource_names = json.loads(json_file.read()) or {}
+
+    needs_manual_placement = {}
+    if os.path.exists("mappings/needs-manual-placement.json"):
+        with open("mappings/needs-manual-placement.json", "r") as json_file:
+            needs_manual_placement = json.loads(json_file.read()) or {}
+
+    health_df = calculate_workspace_health_scores(
+        managed_drift_df=managed_drift_df,
+        new_resources_to_workspace=new_resources_to_workspace,
+        workspace_to_managed_count=managed_resource_count_by_workspace("state_files"),
+        divisions_to_security_scan=divisions_to_security_scan or {},
+        divisions_to_cost_estimates=divisions_to_cost_estimates or {},
+        workspace_monthly_cost_budget=workspace_monthly_cost_budget,
+    )
+    with open(
+        f"{markdown_text_output_path}/workspace-health-scores.json", "w"
+    ) as json_file:
+        json_file.write(
+            json.dumps(
+                health_df.to_dict("records") if not health_df.empty else [], indent=2
+            )
+        )
+    with open(f"{markdown_text_output_path}/workspace-health.prom", "w") as prom_file:
+        prom_file.write(create_open_metrics_workspace_health(health_df=health_df))
+
     resource_count_dict_of_dfs = {}
     if len(new_resources) > 0:
         resource_count_dict_of_dfs = process_new_resources(new_resources=new_resources)
@@ -73,6 +219,7 @@ def create_markdown_file(job_name: str, markdown_text_output_path):
         cost_summary_dict_of_dfs = process_pricing_data(
             divisions_to_cost_estimates=divisions_to_cost_estimates,
             new_resources=new_resources,
+            currency_symbol=currency_symbol(cost_currency),
         )
 
     markdown_file = MdUtils(
@@ -89,6 +236,78 @@ def create_markdown_file(job_name: str, markdown_text_output_path):
         "current IaC posture."
     )
 
+    if security_gate.get("findings"):
+        markdown_file.new_header(level=1, title="Security Gate", style="atx")
+        markdown_file = create_markdown_security_gate(
+            security_gate=security_gate,
+            markdown_file=markdown_file,
+            resource_names=resource_names,
+        )
+
+    if validation_failures:
+        markdown_file.new_header(level=1, title="Validation Failures", style="atx")
+        markdown_file = create_markdown_table_validation_failures(
+            validation_failures=validation_failures,
+            markdown_file=markdown_file,
+        )
+
+    if secret_findings:
+        markdown_file.new_header(level=1, title="Redacted Secrets", style="atx")
+        markdown_file = create_markdown_table_secret_findings(
+            secret_findings=secret_findings,
+            markdown_file=markdown_file,
+        )
+
+    if policy_violations:
+        markdown_file.new_header(level=1, title="Policy Violations", style="atx")
+        markdown_file = create_markdown_table_policy_violations(
+            policy_violations=policy_violations,
+            markdown_file=markdown_file,
+            resource_names=resource_names,
+        )
+
+    if failed_resource_groups:
+        markdown_file.new_header(level=1, title="Incomplete Scans", style="atx")
+        markdown_file.new_line(
+            "The following cloud services could not be scanned within the listed regions, so resources within "
+            "them are not included in this report. They are retried on the next run."
+        )
+        markdown_file = create_markdown_table_failed_scans(
+            failed_resource_groups=failed_resource_groups,
+            markdown_file=markdown_file,
+        )
+
+    if iac_coverage:
+        markdown_file.new_header(
+            level=1, title="Infrastructure as Code Coverage", style="atx"
+        )
+        markdown_file = create_markdown_iac_coverage(
+            iac_coverage=iac_coverage,
+            markdown_file=markdown_file,
+        )
+
+    if drift_trend:
+        markdown_file.new_header(level=1, title="Drift Trend", style="atx")
+        markdown_file = create_markdown_drift_trend(
+            drift_trend=drift_trend,
+            markdown_file=markdown_file,
+        )
+
+    markdown_file.new_header(level=1, title="Workspace Health", style="atx")
+    if not health_df.empty:
+        markdown_file.new_line(
+            "Each workspace is scored from 0 to 100 by combining managed resource drift (35%), "
+            "Terraform coverage (35%), security findings (20%) and uncontrolled cost (10%). "
+            "The cost score falls to 0 as the workspace's uncontrolled monthly cost reaches the budget of "
+            f"{workspace_monthly_cost_budget:g} {workspace_cost_budget.get('Currency', 'USD')}."
+        )
+        markdown_file = create_markdown_table_workspace_health(
+            health_df=health_df,
+            markdown_file=markdown_file,
+        )
+    else:
+        markdown_file.new_line("No workspaces identified.")
+
     markdown_file.new_header(level=1, title="Identified Security Risks", style="atx")
     if divisions_to_security_scan:
         division_to_security_df_dict = division_to_security_scan_to_df_dict(
@@ -102,14 +321,39 @@ def create_markdown_file(job_name: str, markdown_text_output_path):
     else:
         markdown_file.new_line("Security scan not run.")
 
+    for scope in ["changed", "repository"]:
+        if scope in scope_to_security_scan:
+            markdown_file = create_markdown_security_scope(
+                scope=scope,
+                directory_to_results=scope_to_security_scan[scope],
+                markdown_file=markdown_file,
+            )
+
     markdown_file.new_header(
         level=1, title="Calculable Cloud Costs (Monthly)", style="atx"
     )
     if divisions_to_cost_estimates:
+        if currency_conversion_note(cost_currency):
+            markdown_file.new_line(currency_conversion_note(cost_currency))
         markdown_file = create_markdown_table_cost_summary(
             markdown_file=markdown_file,
             cost_summary_df=cost_summary_dict_of_dfs["cost_summary"],
         )
+
+        if not cost_summary_dict_of_dfs["cost_by_division"].empty:
+            markdown_file.new_header(
+                level=2, title="Monthly Cost by Division", style="atx"
+            )
+            markdown_file = create_markdown_table_cost_by_division(
+                markdown_file=markdown_file,
+                cost_by_division_df=cost_summary_dict_of_dfs["cost_by_division"],
+            )
+
+        markdown_file = create_markdown_cost_rollups(
+            cost_rollups=cost_rollups,
+            markdown_file=markdown_file,
+            currency_symbol=currency_symbol(cost_currency),
+        )
     else:
         markdown_file.new_line("Cost estimation not run.")
 
@@ -130,6 +374,52 @@ def create_markdown_file(job_name: str, markdown_text_output_path):
     else:
         markdown_file.new_line("No new resources found!")
 
+    if new_resources_to_workspace and placement_confidence:
+        markdown_file.new_header(
+            level=2,
+            title="Workspace Placement",
+            add_table_of_contents="n",
+        )
+        markdown_file = create_markdown_table_placement_confidence(
+            new_resources_to_workspace=new_resources_to_workspace,
+            placement_confidence=placement_confidence,
+            markdown_file=markdown_file,
+            resource_names=resource_names,
+        )
+
+    if any(new_resources_relationships.values()):
+        markdown_file.new_header(
+            level=2,
+            title="Resource Relationships",
+            add_table_of_contents="n",
+        )
+        markdown_file = create_markdown_resource_relationships(
+            workspace_to_relationships=new_resources_relationships,
+            markdown_file=markdown_file,
+        )
+
+    if needs_manual_placement:
+        markdown_file.new_header(
+            level=2,
+            title="Needs Manual Placement",
+            add_table_of_contents="n",
+        )
+        markdown_file = create_markdown_table_manual_placement(
+            needs_manual_placement=needs_manual_placement,
+            markdown_file=markdown_file,
+        )
+
+    if other_iac_resources:
+        markdown_file.new_header(
+            level=2,
+            title="Owned by Other IaC Tools",
+            add_table_of_contents="n",
+        )
+        markdown_file = create_markdown_table_other_iac_resources(
+            other_iac_resources=other_iac_resources,
+            markdown_file=markdown_file,
+        )
+
     markdown_file.new_header(
         level=1, title="Drifted Resources Managed By Terraform", style="atx"
     )
@@ -137,10 +427,23 @@ def create_markdown_file(job_name: str, markdown_text_output_path):
         markdown_file = create_managed_drift_markdown(
             managed_drift_df=managed_drift_df,
             markdown_file=markdown_file,
+            drift_cost_impact=drift_cost_impact,
+            currency_symbol=currency_symbol(cost_currency),
         )
     else:
         markdown_file.new_line("No controlled resources have drifted!")
 
+    if deleted_resources:
+        markdown_file.new_header(
+            level=2,
+            title="Deleted Outside of Terraform",
+            add_table_of_contents="n",
+        )
+        markdown_file = create_markdown_table_deleted_resources(
+            deleted_resources=deleted_resources,
+            markdown_file=markdown_file,
+        )
+
     markdown_file.new_header(level=1, title="Root Causes of Drift", style="atx")
     markdown_file.new_header(
         level=2,
@@ -157,8 +460,13 @@ def create_markdown_file(job_name: str, markdown_text_output_path):
 
     markdown_file.new_header(level=4, title="Disclaimer", add_table_of_contents="n")
     markdown_file.new_line(
-        "*Indicates that a resource's cost is usage based. Since we currently do not infer/have knowledge of usage, "
-        "costs may be material although indicated as 0 here."
+        "*Indicates that a resource's cost is usage based. Unless its usage is estimated within the repository's "
+        "Infracost usage file, costs may be material although indicated as 0 here."
+    )
+    markdown_file.new_line()
+    markdown_file.new_line(
+        "†Indicates that at least one resource of the type could not be priced, as its pricing data was "
+        "unavailable. Its cost is unknown and excluded from the totals shown here."
     )
     markdown_file.new_line()
     markdown_file.new_line(
@@ -0,0 +1,41 @@
+"""
+Unit tests for helpers in reporting monthly costs rolled up by workspace and by cost allocation tag.
+"""
+from main.internal.python_scripts.state_of_cloud_report.helpers.cost_rollups import (
+    cost_rollup_rows,
+    tag_cost_rollups,
+)
+
+
+def test_cost_rollup_rows():
+    """
+    Unit test for cost_rollup_rows
+    """
+    cost_rollups = [
+        {
+            "group": "networking",
+            "resources": 2,
+            "managed_monthly_cost": 1250.5,
+            "unmanaged_monthly_cost": 3.36,
+            "total_monthly_cost": 1253.86,
+        }
+    ]
+
+    assert cost_rollup_rows(cost_rollups, "€") == [
+        ["networking", "2", "€3.36", "€1,250.50", "€1,253.86"]
+    ]
+
+
+def test_tag_cost_rollups():
+    """
+    Unit test for tag_cost_rollups
+    """
+    network = {"tag": "team", "group": "network"}
+    untagged = {"tag": "team", "group": "(untagged)"}
+    prod = {"tag": "env", "group": "prod"}
+
+    assert tag_cost_rollups({"tags": [prod, network, untagged]}) == {
+        "env": [prod],
+        "team": [network, untagged],
+    }
+    assert tag_cost_rollups({"tags": None}) == {}
@@ -0,0 +1,41 @@
+"""
+Unit tests for helpers in displaying costs in the configured display currency.
+"""
+from main.internal.python_scripts.state_of_cloud_report.helpers.currency import (
+    currency_conversion_note,
+    currency_symbol,
+)
+
+
+def test_currency_symbol():
+    """
+    Unit test for currency_symbol
+    """
+    assert currency_symbol({}) == "$"
+    assert currency_symbol(None) == "$"
+    assert currency_symbol({"Currency": "EUR", "ExchangeRate": 0.92}) == "€"
+    assert currency_symbol({"Currency": "CHF", "ExchangeRate": 0.9}) == "CHF "
+
+
+def test_currency_conversion_note():
+    """
+    Unit test for currency_conversion_note
+    """
+    assert currency_conversion_note({"Currency": "USD", "ExchangeRate": 1}) == ""
+    assert (
+        currency_conversion_note(
+            {"Currency": "CHF", "ExchangeRate": 0.9, "Source": "static"}
+        )
+        == "Costs are shown in CHF, converted from USD at 0.9 CHF per USD."
+    )
+    assert (
+        currency_conversion_note(
+            {
+                "Currency": "EUR",
+                "ExchangeRate": 0.8,
+                "Source": "ecb",
+                "RateDate": "2026-10-14",
+            }
+        )
+        == "Costs are shown in EUR, converted from USD at 0.8 EUR per USD (ECB reference rate of 2026-10-14)."
+    )
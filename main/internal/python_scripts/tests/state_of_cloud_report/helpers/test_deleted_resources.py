@@ -0,0 +1,36 @@
+"""
+Unit tests for helpers in reporting resources deleted outside of Terraform.
+"""
+from main.internal.python_scripts.state_of_cloud_report.helpers.deleted_resources import (
+    deleted_resource_rows,
+)
+
+
+def test_deleted_resource_rows():
+    """
+    Unit test for deleted_resource_rows
+    """
+    deleted_resources = [
+        {
+            "InstanceID": "subnet-2",
+            "StateFileName": "network",
+            "ModuleName": "root",
+            "ResourceType": "aws_subnet",
+            "ResourceName": "private",
+            "ResourceAddress": 'aws_subnet.private["b"]',
+        },
+        {
+            "InstanceID": "logs",
+            "StateFileName": "storage",
+            "ModuleName": "root",
+            "ResourceType": "aws_s3_bucket",
+            "ResourceName": "logs",
+        },
+    ]
+
+    rows = deleted_resource_rows(deleted_resources)
+
+    assert rows == [
+        ("network", 'aws_subnet.private["b"]', "subnet-2"),
+        ("storage", "aws_s3_bucket.logs", "logs"),
+    ]
@@ -0,0 +1,82 @@
+"""
+Unit tests for helpers in reporting the trend of IaC coverage and drift across job runs.
+"""
+from main.internal.python_scripts.state_of_cloud_report.helpers.drift_trend import (
+    coverage_trend_sentence,
+    division_changes,
+    run_totals,
+)
+
+DRIFT_TREND = [
+    {
+        "generated_at": "2023-06-01T00:00:00Z",
+        "divisions": [
+            {
+                "provider": "aws",
+                "division": "111111111111",
+                "managed": 3,
+                "unmanaged": 3,
+                "drifted": 2,
+                "coverage": 50.0,
+            },
+        ],
+        "deleted_resources": 1,
+        "coverage": 50.0,
+    },
+    {
+        "generated_at": "2023-06-08T00:00:00Z",
+        "divisions": [
+            {
+                "provider": "aws",
+                "division": "111111111111",
+                "managed": 6,
+                "unmanaged": 2,
+                "drifted": 1,
+                "coverage": 75.0,
+            },
+            {
+                "provider": "google",
+                "division": "my-project",
+                "managed": 2,
+                "unmanaged": 0,
+                "drifted": 0,
+                "coverage": 100.0,
+            },
+        ],
+        "deleted_resources": 0,
+        "coverage": 80.0,
+    },
+]
+
+
+def test_run_totals():
+    """
+    Unit test for run_totals
+    """
+    assert run_totals(DRIFT_TREND[1]) == {
+        "run": "2023-06-08",
+        "managed": 8,
+        "unmanaged": 2,
+        "drifted": 1,
+        "deleted": 0,
+        "coverage": 80.0,
+    }
+
+
+def test_coverage_trend_sentence():
+    """
+    Unit test for coverage_trend_sentence
+    """
+    assert "improving, up 30.0 percentage points" in coverage_trend_sentence(DRIFT_TREND)
+    assert "declining" in coverage_trend_sentence(list(reversed(DRIFT_TREND)))
+    assert "first recorded run" in coverage_trend_sentence(DRIFT_TREND[:1])
+
+
+def test_division_changes():
+    """
+    Unit test for division_changes
+    """
+    assert division_changes(DRIFT_TREND) == [
+        ("aws-111111111111", 75.0, 25.0, -1, -1),
+        ("google-my-project", 100.0, 0.0, 0, 0),
+    ]
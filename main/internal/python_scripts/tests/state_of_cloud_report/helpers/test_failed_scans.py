@@ -0,0 +1,52 @@
+"""
+Unit tests for helpers in reporting failed scans.
+"""
+from main.internal.python_scripts.state_of_cloud_report.helpers.failed_scans import (
+    failed_resource_group_rows,
+)
+
+
+def test_failed_resource_group_rows():
+    """
+    Unit test for failed_resource_group_rows
+    """
+    failed_resource_groups = [
+        {
+            "division": "aws-prod",
+            "region": "us-east-1",
+            "resourceGroup": "s3",
+            "resourceTypes": ["aws_s3_bucket"],
+        },
+        {
+            "division": "aws-prod",
+            "region": "eu-west-1",
+            "resourceGroup": "sqs",
+            "resourceTypes": ["aws_sqs_queue"],
+        },
+        {
+            "division": "aws-prod",
+            "region": "us-east-1",
+            "resourceGroup": "s3",
+            "resourceTypes": ["aws_s3_bucket"],
+        },
+        {
+            "division": "google-dev",
+            "region": "",
+            "resourceGroup": "pubsub",
+            "resourceTypes": ["google_pubsub_topic", "google_pubsub_subscription"],
+        },
+    ]
+
+    rows = failed_resource_group_rows(failed_resource_groups)
+
+    assert rows == [
+        ("aws-prod", "eu-west-1", "sqs", "aws_sqs_queue"),
+        ("aws-prod", "us-east-1", "s3", "aws_s3_bucket"),
+        (
+            "google-dev",
+            "",
+            "pubsub",
+            "google_pubsub_subscription, google_pubsub_topic",
+        ),
+    ]
+    assert failed_resource_group_rows(None) == []
@@ -0,0 +1,47 @@
+"""
+Unit tests for helpers in reporting the share of scanned cloud resources under Terraform control.
+"""
+from main.internal.python_scripts.state_of_cloud_report.helpers.iac_coverage import (
+    iac_coverage_rows,
+    iac_coverage_sentence,
+)
+
+
+def test_iac_coverage_sentence():
+    """
+    Unit test for iac_coverage_sentence
+    """
+    assert (
+        iac_coverage_sentence({"coverage": 60.0, "managed": 3, "unmanaged": 2})
+        == "60.0% of the 5 scanned cloud resources (3 resources) are under Terraform control."
+    )
+
+
+def test_iac_coverage_rows():
+    """
+    Unit test for iac_coverage_rows
+    """
+    iac_coverage = {
+        "divisions": [
+            {
+                "provider": "aws",
+                "division": "111",
+                "managed": 3,
+                "unmanaged": 1,
+                "coverage": 75.0,
+            },
+            {
+                "provider": "aws",
+                "division": "222",
+                "managed": 0,
+                "unmanaged": 1,
+                "coverage": 0.0,
+            },
+        ]
+    }
+
+    assert iac_coverage_rows(iac_coverage) == [
+        ("aws-222", 0, 1, 0.0),
+        ("aws-111", 3, 1, 75.0),
+    ]
+    assert iac_coverage_rows({"divisions": None}) == []
@@ -0,0 +1,36 @@
+"""
+Unit tests for helpers in formatting managed resource drift results.
+"""
+from main.internal.python_scripts.state_of_cloud_report.helpers.managed_resource_drift import (
+    drift_cost_impact_by_instance,
+    format_cost_delta,
+)
+
+
+def test_drift_cost_impact_by_instance():
+    """
+    Unit test for drift_cost_impact_by_instance
+    """
+    web_impact = {
+        "StateFileName": "compute",
+        "CloudDivision": "google-my-project",
+        "ResourceAddress": "google_compute_instance.web",
+        "InstanceID": "web",
+        "TerraformMonthlyCost": 46.72,
+        "CloudMonthlyCost": 93.44,
+        "MonthlyCostDelta": 46.72,
+    }
+
+    assert drift_cost_impact_by_instance([web_impact]) == {
+        ("compute", "web"): web_impact
+    }
+    assert drift_cost_impact_by_instance(None) == {}
+
+
+def test_format_cost_delta():
+    """
+    Unit test for format_cost_delta
+    """
+    assert format_cost_delta(46.72) == "+$46.72"
+    assert format_cost_delta(-1234.5) == "-$1,234.50"
+    assert format_cost_delta(0) == "+$0.00"
@@ -9,6 +9,7 @@ from main.internal.python_scripts.state_of_cloud_report.helpers.new_resources_an
     create_markdown_table_new_resources,
     process_new_resources,
     _calculate_aggregate_costs_across_scan,
+    _cost_by_division,
     _dataframe_from_divisions_to_cost_estimates_dict,
     _uncontrolled_cost_by_div_by_type,
     _query_sort_and_clip_grouped_data,
@@ -158,6 +159,48 @@ def test_uncontrolled_cost_by_div_by_type():
     pd.testing.assert_frame_equal(expected_output_df, output_df)
 
 
+def test_uncontrolled_cost_by_div_by_type_unknown_cost():
+    """Unit test for _uncontrolled_cost_by_div_by_type marking resource types with an unknown cost"""
+    input_df = _create_baseline_expected_df()
+    input_df["is_unknown_cost"] = [False, True]
+
+    output_df = _uncontrolled_cost_by_div_by_type(input_df)
+
+    assert output_df["monthly_cost"].tolist() == ["$9.36†"]
+
+
+def test_cost_by_division():
+    """Unit test for _cost_by_division"""
+    controlled_df = _create_baseline_expected_df(is_new_resource=False)
+    controlled_df["division"] = "google-dragondrop-prod"
+    input_df = pd.concat(
+        [_create_baseline_expected_df(), controlled_df], ignore_index=True
+    )
+
+    expected_output_df = pd.DataFrame(
+        [
+            {
+                "provider": "google",
+                "division": "google-dragondrop-dev",
+                "uncontrolled_cost": "$9.36",
+                "controlled_cost": "$0.0",
+                "total_cost": "$9.36",
+            },
+            {
+                "provider": "google",
+                "division": "google-dragondrop-prod",
+                "uncontrolled_cost": "$0.0",
+                "controlled_cost": "$9.36",
+                "total_cost": "$9.36",
+            },
+        ]
+    )
+
+    output_df = _cost_by_division(input_df)
+
+    pd.testing.assert_frame_equal(expected_output_df, output_df)
+
+
 def test_create_markdown_table_new_resources():
     """Unit test for create_new_markdown_table()"""
     case = TestCase()
@@ -0,0 +1,37 @@
+"""
+Unit tests for helpers in reporting new resources owned by IaC systems other than Terraform.
+"""
+from main.internal.python_scripts.state_of_cloud_report.helpers.other_iac_resources import (
+    other_iac_resource_rows,
+)
+
+
+def test_other_iac_resource_rows():
+    """
+    Unit test for other_iac_resource_rows
+    """
+    other_iac_resources = [
+        {
+            "Division": "google-my-project",
+            "ResourceType": "google_compute_instance",
+            "ResourceName": "tfer--gke-autopilot-pool-1",
+            "ResourceID": "gke-autopilot-pool-1",
+            "Owner": "GKE",
+            "Detail": "",
+        },
+        {
+            "Division": "aws-111111111111",
+            "ResourceType": "aws_s3_bucket",
+            "ResourceName": "tfer--logs",
+            "ResourceID": "logs",
+            "Owner": "CloudFormation",
+            "Detail": "storage",
+        },
+    ]
+
+    rows = other_iac_resource_rows(other_iac_resources)
+
+    assert rows == [
+        ("CloudFormation", "storage", "aws-111111111111", "aws_s3_bucket", "logs"),
+        ("GKE", "-", "google-my-project", "google_compute_instance", "gke-autopilot-pool-1"),
+    ]
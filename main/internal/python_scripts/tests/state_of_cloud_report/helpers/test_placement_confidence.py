@@ -0,0 +1,52 @@
+"""
+Unit tests for helpers in reporting the confidence of new resource placements within workspaces.
+"""
+from main.internal.python_scripts.state_of_cloud_report.helpers.placement_confidence import (
+    manual_placement_rows,
+    placement_rows,
+)
+
+
+def test_placement_rows():
+    """
+    Unit test for placement_rows
+    """
+    new_resources_to_workspace = {
+        "aws-111111111111.aws_subnet.tfer--subnet-1": "networking",
+        "aws-111111111111.aws_vpc.tfer--main": "networking",
+        "aws-111111111111.aws_sqs_queue.tfer--queue": "payments",
+    }
+    placement_confidence = {
+        "aws-111111111111.aws_subnet.tfer--subnet-1": 0.82,
+        "aws-111111111111.aws_vpc.tfer--main": 0.41,
+    }
+
+    rows = placement_rows(new_resources_to_workspace, placement_confidence)
+
+    assert rows == [
+        ("networking", "aws-111111111111.aws_vpc.tfer--main", 0.41),
+        ("networking", "aws-111111111111.aws_subnet.tfer--subnet-1", 0.82),
+    ]
+
+
+def test_manual_placement_rows():
+    """
+    Unit test for manual_placement_rows
+    """
+    needs_manual_placement = {
+        "aws-111111111111.aws_iam_role.tfer--role": {
+            "SuggestedWorkspace": "networking",
+            "Confidence": 0.12,
+        },
+        "aws-111111111111.aws_kms_key.tfer--key": {
+            "SuggestedWorkspace": "payments",
+            "Confidence": 0.05,
+        },
+    }
+
+    rows = manual_placement_rows(needs_manual_placement)
+
+    assert rows == [
+        ("aws-111111111111.aws_kms_key.tfer--key", "payments", 0.05),
+        ("aws-111111111111.aws_iam_role.tfer--role", "networking", 0.12),
+    ]
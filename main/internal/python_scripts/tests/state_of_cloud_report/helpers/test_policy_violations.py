@@ -0,0 +1,36 @@
+"""
+Unit tests for helpers in reporting policy violations.
+"""
+from main.internal.python_scripts.state_of_cloud_report.helpers.policy_violations import (
+    policy_violation_rows,
+)
+
+
+def test_policy_violation_rows():
+    """
+    Unit test for policy_violation_rows
+    """
+    policy_violations = [
+        {"message": "monthly cost of division prod exceeds 1000"},
+        {
+            "message": "public buckets must have a data-classification tag",
+            "resource": "aws_s3_bucket.tfer--public-assets",
+        },
+    ]
+
+    rows = policy_violation_rows(policy_violations)
+
+    assert rows == [
+        ("-", "monthly cost of division prod exceeds 1000"),
+        (
+            "aws_s3_bucket.tfer--public-assets",
+            "public buckets must have a data-classification tag",
+        ),
+    ]
+    assert policy_violation_rows(None) == []
+
+    resource_names = {"aws-dev.aws_s3_bucket.tfer--public-assets": "public_assets"}
+    assert policy_violation_rows(policy_violations, resource_names)[1] == (
+        "aws_s3_bucket.public_assets",
+        "public buckets must have a data-classification tag",
+    )
@@ -0,0 +1,29 @@
+"""
+Unit tests for helpers in referring to new resources by their names within the generated Terraform code.
+"""
+from main.internal.python_scripts.state_of_cloud_report.helpers.resource_names import (
+    generated_resource_name,
+)
+
+
+def test_generated_resource_name():
+    """
+    Unit test for generated_resource_name
+    """
+    resource_names = {
+        "aws-dev.aws_s3_bucket.tfer--my-bucket": "my_bucket",
+        "aws-prod.aws_s3_bucket.tfer--my-bucket": "my_bucket_2",
+        "aws-dev.aws_vpc.tfer--main": "main",
+    }
+
+    assert (
+        generated_resource_name("aws-prod.aws_s3_bucket.tfer--my-bucket", resource_names)
+        == "aws-prod.aws_s3_bucket.my_bucket_2"
+    )
+    assert generated_resource_name("aws_vpc.tfer--main", resource_names) == "aws_vpc.main"
+    assert (
+        generated_resource_name("aws_s3_bucket.tfer--my-bucket", resource_names)
+        == "aws_s3_bucket.tfer--my-bucket"
+    )
+    assert generated_resource_name("-", resource_names) == "-"
+    assert generated_resource_name("aws_vpc.tfer--main", None) == "aws_vpc.tfer--main"
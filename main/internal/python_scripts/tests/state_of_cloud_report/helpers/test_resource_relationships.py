@@ -0,0 +1,51 @@
+"""
+Unit tests for helpers in diagramming the relationships between new resources within each workspace.
+"""
+from main.internal.python_scripts.state_of_cloud_report.helpers import (
+    resource_relationships,
+)
+from main.internal.python_scripts.state_of_cloud_report.helpers.resource_relationships import (
+    diagram_addresses,
+    mermaid_diagram,
+    mermaid_node_id,
+)
+
+
+def test_mermaid_node_id():
+    """
+    Unit test for mermaid_node_id
+    """
+    assert mermaid_node_id("aws_vpc.main-vpc") == "aws_vpc_main_vpc"
+
+
+def test_mermaid_diagram():
+    """
+    Unit test for mermaid_diagram
+    """
+    relationships = {
+        "aws_vpc.main": [],
+        "aws_subnet.private": ["aws_vpc.main"],
+    }
+
+    assert mermaid_diagram(relationships) == "\n".join(
+        [
+            "flowchart LR",
+            '    aws_subnet_private["aws_subnet.private"]',
+            '    aws_vpc_main["aws_vpc.main"]',
+            "    aws_subnet_private --> aws_vpc_main",
+        ]
+    )
+
+
+def test_diagram_addresses(monkeypatch):
+    """
+    Unit test for diagram_addresses
+    """
+    monkeypatch.setattr(resource_relationships, "MAX_DIAGRAM_RESOURCES", 2)
+    relationships = {
+        "aws_vpc.main": [],
+        "aws_subnet.private": ["aws_vpc.main"],
+        "aws_s3_bucket.logs": [],
+    }
+
+    assert diagram_addresses(relationships) == ["aws_subnet.private", "aws_vpc.main"]
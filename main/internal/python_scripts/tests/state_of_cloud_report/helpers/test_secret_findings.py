@@ -0,0 +1,41 @@
+"""
+Unit tests for helpers in reporting redacted secrets.
+"""
+from main.internal.python_scripts.state_of_cloud_report.helpers.secret_findings import (
+    secret_finding_rows,
+)
+
+
+def test_secret_finding_rows():
+    """
+    Unit test for secret_finding_rows
+    """
+    secret_findings = [
+        {
+            "file": "/prod/new-resources.tf",
+            "line": 12,
+            "rule_id": "aws-access-key-id",
+            "description": "AWS Access Key ID",
+            "variable": "aws_iam_access_key_ci_id",
+        },
+        {
+            "file": "/prod/new-resources.tf",
+            "line": 30,
+            "rule_id": "github-token",
+            "description": "GitHub Token",
+            "variable": "",
+        },
+    ]
+
+    rows = secret_finding_rows(secret_findings)
+
+    assert rows == [
+        (
+            "/prod/new-resources.tf",
+            12,
+            "AWS Access Key ID",
+            "aws_iam_access_key_ci_id",
+        ),
+        ("/prod/new-resources.tf", 30, "GitHub Token", "-"),
+    ]
+    assert secret_finding_rows(None) == []
@@ -3,7 +3,10 @@ Unit tests for helpers in security scanning formatting.
 """
 import pandas as pd
 from main.internal.python_scripts.state_of_cloud_report.helpers.security_scanning import (
+    _doc_links,
     _security_scan_to_df,
+    scope_security_rows,
+    security_gate_rows,
 )
 
 
@@ -88,3 +91,89 @@ def test_division_to_security_scan_to_df_dict():
     )
 
     pd.testing.assert_frame_equal(output_df, expected_output_df)
+
+
+def test_security_gate_rows():
+    """
+    Unit test for security_gate_rows
+    """
+    security_gate = {
+        "threshold": "HIGH",
+        "fail_on_findings": False,
+        "findings": [
+            {
+                "division": "my-account",
+                "resource": "aws_s3_bucket.tfer--public",
+                "rule_id": "AVD-AWS-0086",
+                "rule_description": "S3 Access block should block public ACL",
+                "severity": "HIGH",
+            }
+        ],
+    }
+
+    assert security_gate_rows(security_gate) == [
+        (
+            "my-account",
+            "aws_s3_bucket.tfer--public",
+            "HIGH",
+            "AVD-AWS-0086: S3 Access block should block public ACL",
+        )
+    ]
+    assert security_gate_rows({"threshold": "HIGH", "findings": None}) == []
+
+
+def test_doc_links():
+    """
+    Unit test for _doc_links
+    """
+    assert (
+        _doc_links(["https://avd.aquasec.com/a", "https://registry.terraform.io/b"])
+        == "[Rule](https://avd.aquasec.com/a), [Tf Doc](https://registry.terraform.io/b)"
+    )
+    assert _doc_links(["https://avd.aquasec.com/a"]) == "[Rule](https://avd.aquasec.com/a)"
+    assert _doc_links([]) == "-"
+    assert _doc_links(None) == "-"
+
+
+def test_scope_security_rows():
+    """
+    Unit test for scope_security_rows
+    """
+    directory_to_results = {
+        "/staging/": [
+            {
+                "resource": "aws_s3_bucket.logs",
+                "rule_id": "CKV_AWS_18",
+                "rule_description": "Ensure the S3 bucket has access logging enabled",
+                "severity": "UNKNOWN",
+                "links": [],
+            }
+        ],
+        "/prod/": [
+            {
+                "resource": "aws_s3_bucket.assets",
+                "rule_id": "AVD-AWS-0086",
+                "rule_description": "S3 Access block should block public ACL",
+                "severity": "HIGH",
+                "links": ["https://avd.aquasec.com/misconfig/avd-aws-0086"],
+            }
+        ],
+    }
+
+    assert scope_security_rows(directory_to_results) == [
+        (
+            "/prod/",
+            "aws_s3_bucket.assets",
+            "HIGH",
+            "AVD-AWS-0086: S3 Access block should block public ACL",
+            "[Rule](https://avd.aquasec.com/misconfig/avd-aws-0086)",
+        ),
+        (
+            "/staging/",
+            "aws_s3_bucket.logs",
+            "UNKNOWN",
+            "CKV_AWS_18: Ensure the S3 bucket has access logging enabled",
+            "-",
+        ),
+    ]
+    assert scope_security_rows({"/": None}) == []
@@ -0,0 +1,44 @@
+"""
+Unit tests for helpers in reporting validation failures of the generated code.
+"""
+from main.internal.python_scripts.state_of_cloud_report.helpers.validation_failures import (
+    validation_failure_rows,
+)
+
+
+def test_validation_failure_rows():
+    """
+    Unit test for validation_failure_rows
+    """
+    validation_failures = [
+        {
+            "workspace": "prod",
+            "validator": "terraform",
+            "severity": "error",
+            "message": "Unsupported argument",
+            "file": "new-resources.tf",
+            "line": 4,
+        },
+        {
+            "workspace": "prod",
+            "validator": "terraform",
+            "severity": "error",
+            "message": "terraform init failed: provider not found",
+            "file": "",
+            "line": 0,
+        },
+    ]
+
+    rows = validation_failure_rows(validation_failures)
+
+    assert rows == [
+        ("prod", "terraform", "error", "new-resources.tf:4", "Unsupported argument"),
+        (
+            "prod",
+            "terraform",
+            "error",
+            "-",
+            "terraform init failed: provider not found",
+        ),
+    ]
+    assert validation_failure_rows(None) == []
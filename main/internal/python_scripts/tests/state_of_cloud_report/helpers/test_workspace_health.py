@@ -0,0 +1,187 @@
+"""
+Unit tests for helpers in workspace health scoring.
+"""
+import json
+
+import pandas as pd
+from main.internal.python_scripts.state_of_cloud_report.helpers.workspace_health import (
+    calculate_workspace_health_scores,
+    create_open_metrics_workspace_health,
+    managed_resource_count_by_workspace,
+)
+
+
+def test_calculate_workspace_health_scores():
+    """
+    Unit test for calculate_workspace_health_scores
+    """
+    managed_drift_df = pd.DataFrame(
+        [
+            {
+                "StateFileName": "prod",
+                "ModuleName": "root",
+                "ResourceType": "aws_s3_bucket",
+                "ResourceName": "logs",
+            },
+            {
+                "StateFileName": "prod",
+                "ModuleName": "root",
+                "ResourceType": "aws_s3_bucket",
+                "ResourceName": "logs",
+            },
+        ]
+    )
+    new_resources_to_workspace = {
+        "aws-dev.aws_lb.tfer--public": "prod",
+        "aws-dev.aws_sqs_queue.tfer--queue": "dev",
+    }
+    workspace_to_managed_count = {"prod": 4, "dev": 3}
+    divisions_to_security_scan = {
+        "dev": [{"resource": "aws_lb.tfer--public", "severity": "HIGH"}],
+    }
+    divisions_to_cost_estimates = {
+        "aws-dev": [
+            {"resource_name": "aws_lb.tfer--public", "monthly_cost": "30"},
+            {"resource_name": "aws_sqs_queue.tfer--queue", "monthly_cost": "10"},
+        ],
+    }
+
+    output_df = calculate_workspace_health_scores(
+        managed_drift_df=managed_drift_df,
+        new_resources_to_workspace=new_resources_to_workspace,
+        workspace_to_managed_count=workspace_to_managed_count,
+        divisions_to_security_scan=divisions_to_security_scan,
+        divisions_to_cost_estimates=divisions_to_cost_estimates,
+        workspace_monthly_cost_budget=40,
+    )
+
+    expected_output_df = pd.DataFrame(
+        [
+            {
+                "rank": 1,
+                "workspace": "dev",
+                "drift_score": 100.0,
+                "coverage_score": 75.0,
+                "security_score": 100.0,
+                "cost_score": 75.0,
+                "health_score": 88.8,
+            },
+            {
+                "rank": 2,
+                "workspace": "prod",
+                "drift_score": 75.0,
+                "coverage_score": 80.0,
+                "security_score": 95.0,
+                "cost_score": 25.0,
+                "health_score": 75.8,
+            },
+        ]
+    )
+
+    pd.testing.assert_frame_equal(output_df, expected_output_df)
+
+
+def test_calculate_workspace_health_scores_cost_budget():
+    """
+    Unit test for calculate_workspace_health_scores scoring uncontrolled cost against the budget,
+    regardless of the cost of other workspaces
+    """
+    new_resources_to_workspace = {
+        "aws-dev.aws_lb.tfer--public": "prod",
+        "aws-dev.aws_sqs_queue.tfer--queue": "dev",
+    }
+    divisions_to_cost_estimates = {
+        "aws-dev": [
+            {"resource_name": "aws_lb.tfer--public", "monthly_cost": "1500"},
+            {"resource_name": "aws_sqs_queue.tfer--queue", "monthly_cost": "10"},
+        ],
+    }
+
+    output_df = calculate_workspace_health_scores(
+        managed_drift_df=pd.DataFrame(),
+        new_resources_to_workspace=new_resources_to_workspace,
+        workspace_to_managed_count={"prod": 1, "dev": 1},
+        divisions_to_security_scan={},
+        divisions_to_cost_estimates=divisions_to_cost_estimates,
+    )
+
+    cost_scores = dict(zip(output_df["workspace"], output_df["cost_score"]))
+    assert cost_scores == {"dev": 99.0, "prod": 0.0}
+
+    output_df = calculate_workspace_health_scores(
+        managed_drift_df=pd.DataFrame(),
+        new_resources_to_workspace={"aws-dev.aws_sqs_queue.tfer--queue": "dev"},
+        workspace_to_managed_count={"dev": 1},
+        divisions_to_security_scan={},
+        divisions_to_cost_estimates=divisions_to_cost_estimates,
+        workspace_monthly_cost_budget=0,
+    )
+
+    assert output_df["cost_score"].tolist() == [99.0]
+
+
+def test_calculate_workspace_health_scores_no_workspaces():
+    """
+    Unit test for calculate_workspace_health_scores when no workspaces are known
+    """
+    output_df = calculate_workspace_health_scores(
+        managed_drift_df=pd.DataFrame(),
+        new_resources_to_workspace={},
+        workspace_to_managed_count={},
+        divisions_to_security_scan={},
+        divisions_to_cost_estimates={},
+    )
+
+    assert output_df.empty
+
+
+def test_managed_resource_count_by_workspace(tmp_path):
+    """
+    Unit test for managed_resource_count_by_workspace
+    """
+    state = {
+        "resources": [
+            {"mode": "managed", "instances": [{}, {}]},
+            {"mode": "data", "instances": [{}]},
+        ]
+    }
+    (tmp_path / "prod.json").write_text(json.dumps(state))
+    (tmp_path / "notes.txt").write_text("ignored")
+
+    assert managed_resource_count_by_workspace(str(tmp_path)) == {"prod": 2}
+    assert managed_resource_count_by_workspace(str(tmp_path / "missing")) == {}
+
+
+def test_create_open_metrics_workspace_health():
+    """
+    Unit test for create_open_metrics_workspace_health
+    """
+    health_df = pd.DataFrame(
+        [
+            {
+                "rank": 1,
+                "workspace": 'prod"east',
+                "drift_score": 100.0,
+                "coverage_score": 50.0,
+                "security_score": 90.0,
+                "cost_score": 100.0,
+                "health_score": 82.0,
+            }
+        ]
+    )
+
+    output = create_open_metrics_workspace_health(health_df=health_df)
+
+    assert (
+        "# TYPE cloud_concierge_workspace_health_score gauge\n"
+        "# HELP cloud_concierge_workspace_health_score Composite health score of the workspace, from 0 to 100.\n"
+        'cloud_concierge_workspace_health_score{workspace="prod\\"east"} 82.0\n'
+    ) in output
+    assert (
+        'cloud_concierge_workspace_coverage_score{workspace="prod\\"east"} 50.0\n'
+        in output
+    )
+    assert output.endswith("# EOF\n")
+
+    empty_output = create_open_metrics_workspace_health(health_df=pd.DataFrame())
+    assert "{" not in empty_output
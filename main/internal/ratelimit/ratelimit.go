@@ -0,0 +1,199 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrThrottled is returned when a call remains throttled after the configured number of throttling retries.
+var ErrThrottled = errors.New("[cloud api throttled]")
+
+// minimumBackoff is the backoff applied after the first throttling response.
+const minimumBackoff = time.Second
+
+// throttlingMessages are substrings of the errors returned by cloud APIs, and printed by terraformer, when a request
+// is throttled. They are shared with the classification of job failures within joberrors.
+var throttlingMessages = []string{
+	"throttl",
+	"rate exceeded",
+	"rate limit",
+	"requestlimitexceeded",
+	"toomanyrequests",
+	"too many requests",
+	"slowdown",
+	"ratelimit",
+	"quota exceeded",
+	"resource_exhausted",
+	"error 429",
+	"status code 429",
+}
+
+// Config is the configuration of the requests made against cloud provider APIs.
+type Config struct {
+	// RequestsPerSecond is the sustained rate of requests. Zero disables rate limiting.
+	RequestsPerSecond float64
+
+	// Burst is the number of requests that may be made at once before RequestsPerSecond applies.
+	Burst int
+
+	// MaxThrottleRetries is the number of times a throttled request is retried with an adaptive backoff.
+	MaxThrottleRetries int
+
+	// MaxBackoff is the upper bound of the adaptive backoff applied after throttled requests.
+	MaxBackoff time.Duration
+}
+
+// IsEnabled returns true if requests are rate limited.
+func (c Config) IsEnabled() bool {
+	return c.RequestsPerSecond > 0
+}
+
+// Limiter is a token bucket rate limiter with an adaptive backoff: each throttled request doubles the wait applied
+// before subsequent requests, and each successful request halves it. A nil Limiter does not limit requests.
+type Limiter struct {
+	config Config
+
+	mu       sync.Mutex
+	tokens   float64
+	lastFill time.Time
+	backoff  time.Duration
+
+	// now and sleep are replaced within tests.
+	now   func() time.Time
+	sleep func(ctx context.Context, duration time.Duration) error
+}
+
+// New returns a Limiter for config, or nil when rate limiting is disabled.
+func New(config Config) *Limiter {
+	if !config.IsEnabled() {
+		return nil
+	}
+
+	if config.Burst < 1 {
+		config.Burst = 1
+	}
+
+	return &Limiter{
+		config:   config,
+		tokens:   float64(config.Burst),
+		lastFill: time.Now(),
+		now:      time.Now,
+		sleep:    sleepContext,
+	}
+}
+
+// Do waits for the rate limit, then calls fn. When fn fails with a throttling error, the adaptive backoff is
+// increased and fn is retried up to the configured number of throttling retries.
+func (l *Limiter) Do(ctx context.Context, fn func() error) error {
+	if l == nil {
+		return fn()
+	}
+
+	var err error
+	for attempt := 0; attempt <= l.config.MaxThrottleRetries; attempt++ {
+		err = l.Wait(ctx)
+		if err != nil {
+			return err
+		}
+
+		err = fn()
+		if err == nil {
+			l.succeeded()
+			return nil
+		}
+
+		if !IsThrottlingError(err) {
+			return err
+		}
+		l.throttled()
+	}
+
+	return fmt.Errorf("%w[after %v attempt(s)]%v", ErrThrottled, l.config.MaxThrottleRetries+1, err)
+}
+
+// Wait blocks until a request may be made, or ctx is done.
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := l.now()
+	l.tokens += now.Sub(l.lastFill).Seconds() * l.config.RequestsPerSecond
+	if l.tokens > float64(l.config.Burst) {
+		l.tokens = float64(l.config.Burst)
+	}
+	l.lastFill = now
+
+	wait := l.backoff
+	l.tokens--
+	if l.tokens < 0 {
+		tokenWait := time.Duration(-l.tokens / l.config.RequestsPerSecond * float64(time.Second))
+		if tokenWait > wait {
+			wait = tokenWait
+		}
+	}
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	return l.sleep(ctx, wait)
+}
+
+// throttled doubles the adaptive backoff, up to the configured maximum.
+func (l *Limiter) throttled() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.backoff *= 2
+	if l.backoff < minimumBackoff {
+		l.backoff = minimumBackoff
+	}
+	if l.config.MaxBackoff > 0 && l.backoff > l.config.MaxBackoff {
+		l.backoff = l.config.MaxBackoff
+	}
+}
+
+// succeeded halves the adaptive backoff.
+func (l *Limiter) succeeded() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.backoff /= 2
+	if l.backoff < minimumBackoff {
+		l.backoff = 0
+	}
+}
+
+// IsThrottlingError returns true if err describes a throttled cloud API request.
+func IsThrottlingError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	message := strings.ToLower(err.Error())
+	for _, throttlingMessage := range throttlingMessages {
+		if strings.Contains(message, throttlingMessage) {
+			return true
+		}
+	}
+	return false
+}
+
+// sleepContext sleeps for duration, returning early with an error if ctx is done.
+func sleepContext(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("[ratelimit][wait interrupted]%w", ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
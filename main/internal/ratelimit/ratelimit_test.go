@@ -0,0 +1,110 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestLimiter returns a Limiter whose clock only advances when it sleeps, recording each sleep.
+func newTestLimiter(config Config) (*Limiter, *[]time.Duration) {
+	limiter := New(config)
+	current := time.Unix(0, 0)
+	sleeps := make([]time.Duration, 0)
+
+	limiter.lastFill = current
+	limiter.now = func() time.Time { return current }
+	limiter.sleep = func(ctx context.Context, duration time.Duration) error {
+		sleeps = append(sleeps, duration)
+		current = current.Add(duration)
+		return nil
+	}
+	return limiter, &sleeps
+}
+
+func TestNew_Disabled(t *testing.T) {
+	// Given
+	config := Config{}
+
+	// When
+	limiter := New(config)
+
+	// Then
+	assert.Nil(t, limiter)
+	assert.NoError(t, limiter.Wait(context.Background()))
+}
+
+func TestLimiter_Wait(t *testing.T) {
+	// Given
+	limiter, sleeps := newTestLimiter(Config{RequestsPerSecond: 2, Burst: 2})
+
+	// When
+	for i := 0; i < 4; i++ {
+		require.NoError(t, limiter.Wait(context.Background()))
+	}
+
+	// Then
+	assert.Equal(t, []time.Duration{500 * time.Millisecond, 500 * time.Millisecond}, *sleeps)
+}
+
+func TestLimiter_Do_AdaptiveBackoff(t *testing.T) {
+	// Given
+	limiter, sleeps := newTestLimiter(Config{RequestsPerSecond: 100, Burst: 100, MaxThrottleRetries: 3, MaxBackoff: 3 * time.Second})
+	calls := 0
+
+	// When
+	err := limiter.Do(context.Background(), func() error {
+		calls++
+		if calls < 4 {
+			return errors.New("ThrottlingException: Rate exceeded")
+		}
+		return nil
+	})
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, 4, calls)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}, *sleeps)
+	assert.Equal(t, 1500*time.Millisecond, limiter.backoff)
+}
+
+func TestLimiter_Do_ThrottledAfterRetries(t *testing.T) {
+	// Given
+	limiter, _ := newTestLimiter(Config{RequestsPerSecond: 100, Burst: 100, MaxThrottleRetries: 1})
+
+	// When
+	err := limiter.Do(context.Background(), func() error {
+		return errors.New("googleapi: Error 429: rateLimitExceeded")
+	})
+
+	// Then
+	assert.ErrorIs(t, err, ErrThrottled)
+}
+
+func TestLimiter_Do_OtherError(t *testing.T) {
+	// Given
+	limiter, _ := newTestLimiter(Config{RequestsPerSecond: 100, Burst: 100, MaxThrottleRetries: 3})
+	calls := 0
+
+	// When
+	err := limiter.Do(context.Background(), func() error {
+		calls++
+		return errors.New("AccessDenied")
+	})
+
+	// Then
+	assert.EqualError(t, err, "AccessDenied")
+	assert.Equal(t, 1, calls)
+}
+
+func TestIsThrottlingError(t *testing.T) {
+	assert.True(t, IsThrottlingError(errors.New("RequestLimitExceeded: Request limit exceeded.")))
+	assert.True(t, IsThrottlingError(errors.New("rpc error: code = ResourceExhausted desc = RESOURCE_EXHAUSTED")))
+	assert.True(t, IsThrottlingError(errors.New("GitHub API rate limit exceeded")))
+	assert.False(t, IsThrottlingError(errors.New("AccessDenied")))
+	assert.False(t, IsThrottlingError(nil))
+}
@@ -0,0 +1,61 @@
+package rulelist
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Rule is an entry of an ordered rule list. Rules prefixed by ! are negated, overriding the earlier rules that match
+// the same values.
+type Rule struct {
+	// Negated is true if the rule was prefixed by !.
+	Negated bool
+
+	// Spec is the rule without its ! prefix, whose syntax is left to the caller.
+	Spec string
+}
+
+// Parse parses the built-in rules followed by the user-provided rules, so that user rules take precedence. Rules are
+// trimmed, and empty rules are skipped.
+func Parse(builtIn []string, rules []string) ([]Rule, error) {
+	allRules := append(append([]string{}, builtIn...), rules...)
+	parsedRules := make([]Rule, 0, len(allRules))
+
+	for _, rule := range allRules {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+
+		parsedRule := Rule{Spec: rule}
+		if strings.HasPrefix(rule, "!") {
+			parsedRule.Negated = true
+			parsedRule.Spec = strings.TrimSpace(strings.TrimPrefix(rule, "!"))
+		}
+		if parsedRule.Spec == "" {
+			return nil, fmt.Errorf("[rulelist][parse][rule ! has no pattern]")
+		}
+
+		parsedRules = append(parsedRules, parsedRule)
+	}
+
+	return parsedRules, nil
+}
+
+// LastMatch returns true if the last of count rules that matches, as reported by match, is not negated, and false if
+// no rule matches.
+func LastMatch(count int, match func(i int) (matches bool, negated bool)) bool {
+	decision := false
+	for i := 0; i < count; i++ {
+		if matches, negated := match(i); matches {
+			decision = !negated
+		}
+	}
+	return decision
+}
+
+// Wildcard compiles an anchored pattern in which * matches any characters, including path separators.
+func Wildcard(pattern string) *regexp.Regexp {
+	return regexp.MustCompile("^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$")
+}
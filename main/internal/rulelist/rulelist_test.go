@@ -0,0 +1,58 @@
+package rulelist
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	// Given
+	builtIn := []string{"*.amazonaws.com"}
+	rules := []string{" deployer@* ", "", "!ci.amazonaws.com"}
+
+	// When
+	parsedRules, err := Parse(builtIn, rules)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, []Rule{
+		{Spec: "*.amazonaws.com"},
+		{Spec: "deployer@*"},
+		{Negated: true, Spec: "ci.amazonaws.com"},
+	}, parsedRules)
+
+	// When
+	_, err = Parse(nil, []string{"!"})
+
+	// Then
+	assert.Error(t, err)
+}
+
+func TestLastMatch(t *testing.T) {
+	// Given
+	rules, err := Parse([]string{"*.amazonaws.com"}, []string{"!ci.amazonaws.com"})
+	require.NoError(t, err)
+
+	decide := func(value string) bool {
+		return LastMatch(len(rules), func(i int) (bool, bool) {
+			return Wildcard(rules[i].Spec).MatchString(value), rules[i].Negated
+		})
+	}
+
+	// Then
+	assert.True(t, decide("autoscaling.amazonaws.com"))
+	assert.False(t, decide("ci.amazonaws.com"))
+	assert.False(t, decide("jane.doe@example.com"))
+}
+
+func TestWildcard(t *testing.T) {
+	// Given
+	pattern := Wildcard("arn:aws:sts::*:assumed-role/deployer/*")
+
+	// Then
+	assert.True(t, pattern.MatchString("arn:aws:sts::123456789012:assumed-role/deployer/run/42"))
+	assert.False(t, pattern.MatchString("prefix-arn:aws:sts::123456789012:assumed-role/deployer/run"))
+	assert.False(t, Wildcard("a.b").MatchString("axb"))
+}
@@ -0,0 +1,204 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSearchYears bounds the search for the next activation of a schedule, such as "0 0 30 2 *", that never fires.
+const maxSearchYears = 5
+
+// macros are the predefined schedules which may be used in place of the five cron fields.
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// monthNames and dayNames are the names which may be used in place of numbers within the month and day of week fields.
+var monthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var dayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// field describes the bounds and names of a single cron field.
+type field struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	minuteField     = field{name: "minute", min: 0, max: 59}
+	hourField       = field{name: "hour", min: 0, max: 23}
+	dayOfMonthField = field{name: "day of month", min: 1, max: 31}
+	monthField      = field{name: "month", min: 1, max: 12, names: monthNames}
+	dayOfWeekField  = field{name: "day of week", min: 0, max: 7, names: dayNames}
+)
+
+// Schedule is a parsed cron expression, each field being the set of matching values as a bit set.
+type Schedule struct {
+	minutes     uint64
+	hours       uint64
+	daysOfMonth uint64
+	months      uint64
+	daysOfWeek  uint64
+
+	// anyDayOfMonth and anyDayOfWeek flag that the respective field is "*", in which case a day matches only on the
+	// other field. Otherwise, as with cron, a day matches if either field matches.
+	anyDayOfMonth bool
+	anyDayOfWeek  bool
+}
+
+// Parse parses a standard five field cron expression, "minute hour day-of-month month day-of-week", supporting
+// "*", lists, ranges, steps, month and day names and the @hourly, @daily, @weekly, @monthly and @yearly macros.
+func Parse(expression string) (Schedule, error) {
+	expression = strings.TrimSpace(expression)
+	if macro, ok := macros[strings.ToLower(expression)]; ok {
+		expression = macro
+	}
+
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return Schedule{}, fmt.Errorf("[parse][expected 5 fields in %q, got %d]", expression, len(fields))
+	}
+
+	var schedule Schedule
+	var err error
+	parsed := []struct {
+		field field
+		bits  *uint64
+	}{
+		{minuteField, &schedule.minutes},
+		{hourField, &schedule.hours},
+		{dayOfMonthField, &schedule.daysOfMonth},
+		{monthField, &schedule.months},
+		{dayOfWeekField, &schedule.daysOfWeek},
+	}
+	for i, p := range parsed {
+		*p.bits, err = parseField(fields[i], p.field)
+		if err != nil {
+			return Schedule{}, fmt.Errorf("[parse]%w", err)
+		}
+	}
+
+	// Sunday may be written as either 0 or 7.
+	if schedule.daysOfWeek&(1<<7) != 0 {
+		schedule.daysOfWeek = schedule.daysOfWeek&^(1<<7) | 1
+	}
+	schedule.anyDayOfMonth = fields[2] == "*"
+	schedule.anyDayOfWeek = fields[4] == "*"
+
+	return schedule, nil
+}
+
+// parseField parses a comma separated list of "*", values, ranges and steps into the bit set of matching values.
+func parseField(expression string, f field) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(expression, ",") {
+		rangeExpression, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			rangeExpression = part[:i]
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("[invalid step in %v field %q]", f.name, part)
+			}
+		}
+
+		start, end := f.min, f.max
+		if rangeExpression != "*" {
+			bounds := strings.SplitN(rangeExpression, "-", 2)
+
+			var err error
+			start, err = parseValue(bounds[0], f)
+			if err != nil {
+				return 0, err
+			}
+
+			end = start
+			if len(bounds) == 2 {
+				end, err = parseValue(bounds[1], f)
+				if err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				end = f.max
+			}
+			if end < start {
+				return 0, fmt.Errorf("[invalid range in %v field %q]", f.name, part)
+			}
+		}
+
+		for value := start; value <= end; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+	return bits, nil
+}
+
+// parseValue parses a single number or name within the bounds of f.
+func parseValue(expression string, f field) (int, error) {
+	if value, ok := f.names[strings.ToLower(expression)]; ok {
+		return value, nil
+	}
+
+	value, err := strconv.Atoi(expression)
+	if err != nil {
+		return 0, fmt.Errorf("[invalid value in %v field %q]", f.name, expression)
+	}
+	if value < f.min || value > f.max {
+		return 0, fmt.Errorf("[%v field value %d out of range %d-%d]", f.name, value, f.min, f.max)
+	}
+	return value, nil
+}
+
+// Next returns the first time strictly after the passed time at which the schedule fires, in the location of the
+// passed time. The zero time is returned if the schedule does not fire within the next five years.
+func (s Schedule) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxSearchYears, 0, 0)
+
+	for t.Before(limit) {
+		if s.months&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hours&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minutes&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// matchesDay returns whether the day of t matches the day of month and day of week fields of the schedule.
+func (s Schedule) matchesDay(t time.Time) bool {
+	dayOfMonth := s.daysOfMonth&(1<<uint(t.Day())) != 0
+	dayOfWeek := s.daysOfWeek&(1<<uint(t.Weekday())) != 0
+
+	if s.anyDayOfMonth || s.anyDayOfWeek {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
@@ -0,0 +1,54 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNext(t *testing.T) {
+	// Given
+	// Wednesday, the 15th of March 2023.
+	after := time.Date(2023, time.March, 15, 10, 7, 30, 0, time.UTC)
+	cases := map[string]time.Time{
+		"*/15 * * * *":    time.Date(2023, time.March, 15, 10, 15, 0, 0, time.UTC),
+		"0 6 * * 1-5":     time.Date(2023, time.March, 16, 6, 0, 0, 0, time.UTC),
+		"30 2 * * sat,7":  time.Date(2023, time.March, 18, 2, 30, 0, 0, time.UTC),
+		"0 0 1 jan-mar *": time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+		"0 12 17 * mon":   time.Date(2023, time.March, 17, 12, 0, 0, 0, time.UTC),
+		"@hourly":         time.Date(2023, time.March, 15, 11, 0, 0, 0, time.UTC),
+		"@monthly":        time.Date(2023, time.April, 1, 0, 0, 0, 0, time.UTC),
+		"0 0 29 2 *":      time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC),
+		"0 0 30 2 *":      {},
+	}
+
+	for expression, expected := range cases {
+		// When
+		schedule, err := Parse(expression)
+
+		// Then
+		require.NoError(t, err, expression)
+		assert.Equal(t, expected, schedule.Next(after), expression)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	// Given
+	expressions := []string{
+		"* * * *",
+		"60 * * * *",
+		"* 5-2 * * *",
+		"*/0 * * * *",
+		"* * * foo *",
+	}
+
+	for _, expression := range expressions {
+		// When
+		_, err := Parse(expression)
+
+		// Then
+		assert.Error(t, err, expression)
+	}
+}
@@ -0,0 +1,341 @@
+package secretscan
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// FindingsPath is the mapping file to which the redacted secrets are written for the state of cloud report.
+const FindingsPath = "mappings/secret-findings.json"
+
+// entropyThreshold is the minimum shannon entropy, in bits per character, of a value assigned to a secret-like
+// attribute for it to be considered a secret.
+const entropyThreshold = 3.5
+
+// minimumEntropyLength is the minimum length of a value assigned to a secret-like attribute for its entropy to be
+// considered.
+const minimumEntropyLength = 12
+
+// Rule is a pattern identifying a well known kind of secret.
+type Rule struct {
+	// ID is the identifier of the rule.
+	ID string
+
+	// Description describes the kind of secret identified by the rule.
+	Description string
+
+	// pattern matches the secret itself.
+	pattern *regexp.Regexp
+}
+
+// rules are the patterns of well known kinds of secrets, following those of gitleaks.
+var rules = []Rule{
+	{ID: "private-key", Description: "Private Key", pattern: regexp.MustCompile(`-----BEGIN[ A-Z0-9_-]{0,100}PRIVATE KEY( BLOCK)?-----[\s\S]*?-----END[ A-Z0-9_-]{0,100}PRIVATE KEY( BLOCK)?-----`)},
+	{ID: "aws-access-key-id", Description: "AWS Access Key ID", pattern: regexp.MustCompile(`\b(A3T[A-Z0-9]|AKIA|ASIA|ABIA|ACCA)[A-Z0-9]{16}\b`)},
+	{ID: "github-token", Description: "GitHub Token", pattern: regexp.MustCompile(`\b(ghp|gho|ghu|ghs|ghr)_[0-9a-zA-Z]{36}\b`)},
+	{ID: "gitlab-token", Description: "GitLab Personal Access Token", pattern: regexp.MustCompile(`\bglpat-[0-9a-zA-Z_-]{20}\b`)},
+	{ID: "slack-token", Description: "Slack Token", pattern: regexp.MustCompile(`\bxox[baprs]-[0-9a-zA-Z-]{10,48}\b`)},
+	{ID: "gcp-api-key", Description: "GCP API Key", pattern: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{ID: "stripe-secret-key", Description: "Stripe Secret Key", pattern: regexp.MustCompile(`\b(sk|rk)_live_[0-9a-zA-Z]{24,99}\b`)},
+	{ID: "azure-storage-account-key", Description: "Azure Storage Account Key", pattern: regexp.MustCompile(`AccountKey=[0-9a-zA-Z+/]{86}==`)},
+}
+
+// genericRule identifies high entropy values assigned to secret-like attributes.
+var genericRule = Rule{ID: "generic-high-entropy-secret", Description: "High Entropy Secret"}
+
+// secretAttribute matches the assignment of a quoted value to an attribute whose name suggests a secret.
+var secretAttribute = regexp.MustCompile(`(?i)^\s*"?([a-z0-9_-]*(password|passwd|secret|token|api_?key|private_?key|access_?key|credentials?))"?\s*[=:]\s*"([^"]*)"`)
+
+// resourceHeader matches the opening line of a resource block.
+var resourceHeader = regexp.MustCompile(`^\s*resource\s+"([^"]+)"\s+"([^"]+)"`)
+
+// privateKeyBegin matches the header of a private key.
+var privateKeyBegin = regexp.MustCompile(`-----BEGIN[ A-Z0-9_-]{0,100}PRIVATE KEY( BLOCK)?-----`)
+
+// privateKeyEnd matches the footer of a private key.
+var privateKeyEnd = regexp.MustCompile(`-----END[ A-Z0-9_-]{0,100}PRIVATE KEY( BLOCK)?-----`)
+
+// attributeName matches the name of the attribute assigned on a line.
+var attributeName = regexp.MustCompile(`^\s*"?([A-Za-z0-9_-]+)"?\s*[=:]`)
+
+// nonIdentifier matches the characters not allowed within a Terraform variable name.
+var nonIdentifier = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// Finding is a secret identified, and redacted, within a file to be committed.
+type Finding struct {
+	// File is the path of the file containing the secret.
+	File string `json:"file"`
+
+	// Line is the line of the file on which the secret was identified.
+	Line int `json:"line"`
+
+	// RuleID is the id of the rule identifying the secret.
+	RuleID string `json:"rule_id"`
+
+	// Description describes the kind of secret.
+	Description string `json:"description"`
+
+	// Variable is the name of the variable replacing the secret, empty when the secret was within a comment.
+	Variable string `json:"variable"`
+}
+
+// RedactFiles scans each of the passed files for secrets, replacing each within HCL with a reference to a new
+// sensitive variable declared at the end of the file, and each within a comment or a non-HCL file with a redaction
+// marker.
+func RedactFiles(paths []string) ([]Finding, error) {
+	findings := []Finding{}
+
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("[secretscan][redact_files][os.ReadFile %v]%w", path, err)
+		}
+
+		redact := RedactText
+		if extension := filepath.Ext(path); extension == ".tf" || extension == ".hcl" {
+			redact = Redact
+		}
+
+		redacted, fileFindings := redact(string(content))
+		if len(fileFindings) == 0 {
+			continue
+		}
+
+		// Generated files are written as read only, so they are replaced rather than truncated.
+		err = os.Remove(path)
+		if err != nil {
+			return nil, fmt.Errorf("[secretscan][redact_files][os.Remove %v]%w", path, err)
+		}
+
+		err = os.WriteFile(path, []byte(redacted), 0400)
+		if err != nil {
+			return nil, fmt.Errorf("[secretscan][redact_files][os.WriteFile %v]%w", path, err)
+		}
+
+		for _, finding := range fileFindings {
+			finding.File = path
+			findings = append(findings, finding)
+		}
+	}
+
+	return findings, nil
+}
+
+// Redact returns content with each of the identified secrets replaced, and the findings of those secrets.
+func Redact(content string) (string, []Finding) {
+	findings := []Finding{}
+	variables := []string{}
+	usedVariables := map[string]bool{}
+	resource := ""
+	attribute := ""
+
+	var lines []string
+	sourceLines := strings.Split(content, "\n")
+	for index := 0; index < len(sourceLines); index++ {
+		line := sourceLines[index]
+		lineNumber := index + 1
+
+		if match := resourceHeader.FindStringSubmatch(line); match != nil {
+			resource = fmt.Sprintf("%v_%v", match[1], strings.TrimPrefix(match[2], "tfer--"))
+		}
+		if match := attributeName.FindStringSubmatch(line); match != nil {
+			attribute = match[1]
+		}
+
+		// Private keys within heredocs span several lines, and are joined into a single line to be replaced.
+		if privateKeyBegin.MatchString(line) && !privateKeyEnd.MatchString(line) {
+			for end := index + 1; end < len(sourceLines); end++ {
+				if privateKeyEnd.MatchString(sourceLines[end]) {
+					line = strings.Join(sourceLines[index:end+1], "\n")
+					index = end
+					break
+				}
+			}
+		}
+
+		for _, hit := range lineSecrets(line) {
+			finding := Finding{Line: lineNumber, RuleID: hit.rule.ID, Description: hit.rule.Description}
+
+			if isComment(line) {
+				line = strings.Replace(line, hit.value, "[REDACTED]", 1)
+				findings = append(findings, finding)
+				continue
+			}
+
+			finding.Variable = variableName(resource, attribute, usedVariables)
+			usedVariables[finding.Variable] = true
+			variables = append(variables, finding.Variable)
+
+			reference := fmt.Sprintf("var.%v", finding.Variable)
+			if strings.Contains(line, fmt.Sprintf("%q", hit.value)) {
+				line = strings.Replace(line, fmt.Sprintf("%q", hit.value), reference, 1)
+			} else {
+				line = strings.Replace(line, hit.value, fmt.Sprintf("${%v}", reference), 1)
+			}
+			findings = append(findings, finding)
+		}
+		lines = append(lines, line)
+	}
+
+	redacted := strings.Join(lines, "\n")
+	for _, variable := range variables {
+		redacted += fmt.Sprintf(
+			"\n# Secret identified and redacted by cloud-concierge, to be supplied outside of version control.\nvariable %q {\n  type      = string\n  sensitive = true\n}\n",
+			variable,
+		)
+	}
+	return redacted, findings
+}
+
+// RedactText returns content, which need not be HCL, with each of the identified secrets replaced with a redaction
+// marker, and the findings of those secrets.
+func RedactText(content string) (string, []Finding) {
+	findings := []Finding{}
+
+	var lines []string
+	sourceLines := strings.Split(content, "\n")
+	for index := 0; index < len(sourceLines); index++ {
+		line := sourceLines[index]
+		lineNumber := index + 1
+
+		if privateKeyBegin.MatchString(line) && !privateKeyEnd.MatchString(line) {
+			for end := index + 1; end < len(sourceLines); end++ {
+				if privateKeyEnd.MatchString(sourceLines[end]) {
+					line = strings.Join(sourceLines[index:end+1], "\n")
+					index = end
+					break
+				}
+			}
+		}
+
+		for _, hit := range lineSecrets(line) {
+			line = strings.Replace(line, hit.value, "[REDACTED]", 1)
+			findings = append(findings, Finding{Line: lineNumber, RuleID: hit.rule.ID, Description: hit.rule.Description})
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n"), findings
+}
+
+// secretHit is a secret value identified on a line, along with the rule identifying it.
+type secretHit struct {
+	rule  Rule
+	value string
+}
+
+// lineSecrets returns the secrets identified on line, first by the well known rules, then by the entropy of values
+// assigned to secret-like attributes.
+func lineSecrets(line string) []secretHit {
+	hits := []secretHit{}
+	for _, rule := range rules {
+		for _, value := range rule.pattern.FindAllString(line, -1) {
+			hits = append(hits, secretHit{rule: rule, value: value})
+		}
+	}
+	if len(hits) > 0 {
+		return hits
+	}
+
+	match := secretAttribute.FindStringSubmatch(line)
+	if match == nil {
+		return hits
+	}
+
+	value := match[3]
+	if len(value) >= minimumEntropyLength && !strings.Contains(value, "${") && shannonEntropy(value) >= entropyThreshold {
+		hits = append(hits, secretHit{rule: genericRule, value: value})
+	}
+	return hits
+}
+
+// shannonEntropy returns the shannon entropy, in bits per character, of value.
+func shannonEntropy(value string) float64 {
+	counts := map[rune]int{}
+	for _, character := range value {
+		counts[character]++
+	}
+
+	length := float64(len([]rune(value)))
+	entropy := 0.0
+	for _, count := range counts {
+		frequency := float64(count) / length
+		entropy -= frequency * math.Log2(frequency)
+	}
+	return entropy
+}
+
+// isComment returns true if line is an HCL comment.
+func isComment(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "/*")
+}
+
+// variableName returns a unique variable name for a secret assigned to attribute within resource.
+func variableName(resource string, attribute string, usedVariables map[string]bool) string {
+	parts := []string{}
+	for _, part := range []string{resource, attribute} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		parts = append(parts, "secret")
+	}
+
+	name := strings.Trim(nonIdentifier.ReplaceAllString(strings.ToLower(strings.Join(parts, "_")), "_"), "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "secret_" + name
+	}
+
+	candidate := name
+	for suffix := 2; usedVariables[candidate]; suffix++ {
+		candidate = fmt.Sprintf("%v_%d", name, suffix)
+	}
+	return candidate
+}
+
+// GeneratedFiles returns the sorted HCL files written by cloud-concierge within the repository directory of each
+// workspace: the new resources files and everything beneath generatedDirectory.
+func GeneratedFiles(workspaceToDirectory map[string]string, generatedDirectory string) ([]string, error) {
+	paths := map[string]bool{}
+
+	for _, directory := range workspaceToDirectory {
+		newResources, err := filepath.Glob(fmt.Sprintf("repo%vnew-resources*.tf", directory))
+		if err != nil {
+			return nil, fmt.Errorf("[secretscan][generated_files][filepath.Glob]%w", err)
+		}
+		for _, path := range newResources {
+			paths[path] = true
+		}
+
+		workspaceGeneratedDirectory := fmt.Sprintf("repo%v%v", directory, generatedDirectory)
+		err = filepath.Walk(workspaceGeneratedDirectory, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if !info.IsDir() && (strings.HasSuffix(path, ".tf") || strings.HasSuffix(path, ".hcl")) {
+				paths[path] = true
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("[secretscan][generated_files][filepath.Walk %v]%w", workspaceGeneratedDirectory, err)
+		}
+	}
+
+	sortedPaths := make([]string, 0, len(paths))
+	for path := range paths {
+		sortedPaths = append(sortedPaths, path)
+	}
+	sort.Strings(sortedPaths)
+	return sortedPaths, nil
+}
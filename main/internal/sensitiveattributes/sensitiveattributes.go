@@ -0,0 +1,58 @@
+package sensitiveattributes
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultPatterns match the attribute names and paths that commonly hold secrets, regardless of whether the provider
+// schema marks them as sensitive. They are shared by every output of a job that writes attribute values, so that a
+// value masked within one output is not published by another.
+var DefaultPatterns = []string{
+	`passw(or)?d`,
+	`secret`,
+	`private_?key`,
+	`(^|[._])token($|\.)`,
+	`connection_?string`,
+	`access_?key`,
+	`api_?key`,
+	`credentials?($|\.)`,
+	`primary_key|secondary_key`,
+	`sas_?(url|token)`,
+}
+
+// Mask replaces the values of sensitive attributes within every output of a job.
+const Mask = "(sensitive value)"
+
+// Compile compiles the default and the user-provided patterns into case-insensitive regular expressions, skipping
+// empty patterns.
+func Compile(patterns []string) ([]*regexp.Regexp, error) {
+	allPatterns := append(append([]string{}, DefaultPatterns...), patterns...)
+	compiled := make([]*regexp.Regexp, 0, len(allPatterns))
+
+	for _, pattern := range allPatterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+
+		expression, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, fmt.Errorf("[compile][invalid pattern %v]%w", pattern, err)
+		}
+		compiled = append(compiled, expression)
+	}
+
+	return compiled, nil
+}
+
+// Matches returns true if attribute matches any of the patterns.
+func Matches(attribute string, patterns []*regexp.Regexp) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(attribute) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,30 @@
+package sensitiveattributes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompile(t *testing.T) {
+	// When
+	patterns, err := Compile([]string{" ^custom_data$ ", ""})
+
+	// Then
+	require.NoError(t, err)
+	assert.Len(t, patterns, len(DefaultPatterns)+1)
+	assert.True(t, Matches("master_password", patterns))
+	assert.True(t, Matches("site_config.0.auth_token", patterns))
+	assert.True(t, Matches("Custom_Data", patterns))
+	assert.False(t, Matches("tokenizer", patterns))
+	assert.False(t, Matches("bucket", patterns))
+}
+
+func TestCompile_Invalid(t *testing.T) {
+	// When
+	_, err := Compile([]string{"(unclosed"})
+
+	// Then
+	assert.Error(t, err)
+}
@@ -5,17 +5,26 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 
-	"github.com/kelseyhightower/envconfig"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/artifacts"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/awscredentials"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/azuremanagementgroups"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/credentialrefresh"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/documentize"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/gcpprojects"
 	costEstimation "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/cost_estimation"
 	dragonDrop "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/dragon_drop"
 	identifyCloudActors "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/identify_cloud_actors"
+	inventoryExporter "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/inventory_exporter"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/notifier"
+	policyEvaluator "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/policy_evaluator"
 	resourcesCalculator "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/resources_calculator"
 	resourcesWriter "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/resources_writer"
+	runStateStore "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/run_state_store"
 	terraformImportMigrationGenerator "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_import_migration_generator"
 	terraformManagedResourcesDriftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector"
 	terraformSecurity "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_security"
@@ -24,6 +33,7 @@ import (
 	terraformerExecutor "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraformer_executor"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/vcs"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/joberrors"
 )
 
 type InferredData struct {
@@ -81,6 +91,18 @@ type Job struct {
 	// terraformSecurity
 	terraformSecurity interfaces.TerraformSecurity
 
+	// inventoryExporter is the implementation of interfaces.InventoryExporter for exporting the inventory of
+	// managed and unmanaged resources.
+	inventoryExporter interfaces.InventoryExporter
+
+	// policyEvaluator is the implementation of interfaces.PolicyEvaluator for evaluating the run output against
+	// user-supplied policies.
+	policyEvaluator interfaces.PolicyEvaluator
+
+	// notifier is the implementation of interfaces.Notifier for notifying external tooling of the results of the
+	// job run.
+	notifier interfaces.Notifier
+
 	// name is the name of the current job
 	name string
 
@@ -89,6 +111,10 @@ type Job struct {
 
 	// config is the configuration to run successfully the job
 	config JobConfig
+
+	// workingDirectory is the absolute path of the directory within which the job clones the repository and writes
+	// its artifacts.
+	workingDirectory string
 }
 
 // Authorize ensures that the Job is valid by checking against the dragondrop
@@ -98,17 +124,17 @@ func (j *Job) Authorize(ctx context.Context) error {
 	if j.config.JobID != "empty" && j.config.JobID != "" {
 		err := j.dragonDrop.CheckLoggerAndToken(ctx)
 		if err != nil {
-			return fmt.Errorf("[create_job][error checking logger and token][%w]", err)
+			return joberrors.Wrap("create_job", "error checking logger and token", joberrors.CodeDragonDropAPI, err)
 		}
 
 		err = j.dragonDrop.InformStarted(ctx)
 		if err != nil {
-			return fmt.Errorf("[create_job][error informing started job][%w]", err)
+			return joberrors.Wrap("create_job", "error informing started job", joberrors.CodeDragonDropAPI, err)
 		}
 
 		jobName, err := j.dragonDrop.AuthorizeManagedJob(ctx)
 		if err != nil {
-			return fmt.Errorf("[create_job][error authorizing managed job][%w]", err)
+			return joberrors.Wrap("create_job", "error authorizing managed job", joberrors.CodeAuthentication, err)
 		}
 		j.name = jobName
 		j.dragonDrop.PostLog(ctx, "Authorized against billing plan.")
@@ -116,7 +142,7 @@ func (j *Job) Authorize(ctx context.Context) error {
 		err := j.dragonDrop.AuthorizeJob(ctx)
 		if err != nil {
 			fmt.Printf("Error authenticating the job run, please get an Organization token by signing up at https://app.dragondrop.cloud.")
-			return fmt.Errorf("[create_job][error authorizing job][%w]", err)
+			return joberrors.Wrap("create_job", "error authorizing job", joberrors.CodeAuthentication, err)
 		}
 		j.name = j.config.JobName
 	}
@@ -128,39 +154,45 @@ func (j *Job) Authorize(ctx context.Context) error {
 func (j *Job) Run(ctx context.Context) error {
 	err := j.vcs.Clone()
 	if err != nil {
-		return fmt.Errorf("[run_job][error clonning repo][%w]", err)
+		return joberrors.Wrap("run_job", "error clonning repo", joberrors.CodeVCS, err)
 	}
 
 	err = j.dragonDrop.InformRepositoryCloned(ctx)
 	if err != nil {
-		return fmt.Errorf("[run_job][error posting cloned status]%w", err)
+		return joberrors.Wrap("run_job", "error posting cloned status", joberrors.CodeDragonDropAPI, err)
 	}
 
 	workspaceToDirectory, err := j.terraformWorkspace.FindTerraformWorkspaces(ctx)
 	if err != nil {
-		return fmt.Errorf("[run_job][error finding terraform workspaces][%w]", err)
+		return joberrors.Wrap("run_job", "error finding terraform workspaces", joberrors.CodeStateBackend, err)
 	}
 
 	err = j.terraformWorkspace.DownloadWorkspaceState(ctx, workspaceToDirectory)
 	if err != nil {
-		return fmt.Errorf("[run_job][error downloading workspace state][%w]", err)
+		return joberrors.Wrap("run_job", "error downloading workspace state", joberrors.CodeStateBackend, err)
 	}
 
 	err = j.terraformerExecutor.Execute(ctx)
 	if err != nil {
-		return fmt.Errorf("[run_job][error setting up terraformer executor][%w]", err)
+		return joberrors.Wrap("run_job", "error setting up terraformer executor", joberrors.CodeCloudScan, err)
+	}
+
+	// The terraformer executor leaves the job within current_cloud/.
+	err = os.Chdir(j.workingDirectory)
+	if err != nil {
+		return joberrors.Wrap("run_job", "error returning to working directory", joberrors.CodeCloudScan, err)
 	}
 
 	err = j.terraformImportMigrationGenerator.Execute(ctx)
 	if err != nil {
-		return fmt.Errorf("[run_job][error executing terraform import][%w]", err)
+		return joberrors.Wrap("run_job", "error executing terraform import", joberrors.CodeGeneration, err)
 	}
 
-	if !j.config.IsManagedDriftOnly {
+	if !j.config.isManagedDriftOnly() {
 		err = j.resourcesCalculator.Execute(ctx, workspaceToDirectory)
 		if err != nil {
 			if errors.Unwrap(errors.Unwrap(err)) != resourcesCalculator.ErrNoNewResources {
-				return fmt.Errorf("[run_job][error calculating resources][%w]", err)
+				return joberrors.Wrap("run_job", "error calculating resources", joberrors.CodeGeneration, err)
 			}
 
 			j.noNewResources = true
@@ -172,121 +204,205 @@ func (j *Job) Run(ctx context.Context) error {
 
 	driftedResourcesIdentified, err := j.driftDetector.Execute(ctx, workspaceToDirectory)
 	if err != nil {
-		return fmt.Errorf("[run_job][error detecting drifted resources]%w", err)
+		return joberrors.Wrap("run_job", "error detecting drifted resources", joberrors.CodeDriftDetection, err)
 	}
 
 	err = j.dragonDrop.InformCloudActorIdentification(ctx)
 	if err != nil {
-		return fmt.Errorf("[run_job][error posting cloud actor identification status]%w", err)
+		return joberrors.Wrap("run_job", "error posting cloud actor identification status", joberrors.CodeDragonDropAPI, err)
 	}
 
 	err = j.identifyCloudActors.Execute(ctx)
 	if err != nil {
-		return fmt.Errorf("[run_job][error identifying cloud actors]%w", err)
+		return joberrors.Wrap("run_job", "error identifying cloud actors", joberrors.CodeCloudActorIdentification, err)
+	}
+
+	if !j.noNewResources {
+		newResourcesRemain, err := j.resourcesCalculator.ApplyMinimumResourceAge(ctx)
+		if err != nil {
+			return joberrors.Wrap("run_job", "error applying the minimum resource age", joberrors.CodeGeneration, err)
+		}
+
+		if !newResourcesRemain {
+			j.noNewResources = true
+			log.Warnf("All new resources are younger than the minimum resource age, but scanning for drifted resources")
+		}
 	}
 
 	err = j.dragonDrop.InformCostEstimation(ctx)
 	if err != nil {
-		return fmt.Errorf("[run_job][error posting cost estimation status]%w", err)
+		return joberrors.Wrap("run_job", "error posting cost estimation status", joberrors.CodeDragonDropAPI, err)
 	}
 
 	err = j.costEstimator.Execute(ctx)
 	if err != nil {
-		return fmt.Errorf("[run_job][error estimating cost for identified resources]%w", err)
+		return joberrors.Wrap("run_job", "error estimating cost for identified resources", joberrors.CodeCostEstimation, err)
 	}
 
 	err = j.dragonDrop.InformSecurityScan(ctx)
 	if err != nil {
-		return fmt.Errorf("[run_job][error posting security scan status]%w", err)
+		return joberrors.Wrap("run_job", "error posting security scan status", joberrors.CodeDragonDropAPI, err)
 	}
 
-	err = j.terraformSecurity.ExecuteScan(ctx)
+	err = j.terraformSecurity.ExecuteScan(ctx, workspaceToDirectory)
 	if err != nil {
-		return fmt.Errorf("[run_job][error executing the tfsec command]%w", err)
+		return joberrors.Wrap("run_job", "error executing the security scan", joberrors.CodeSecurityScan, err)
+	}
+
+	err = j.inventoryExporter.Execute(ctx, workspaceToDirectory)
+	if err != nil {
+		return joberrors.Wrap("run_job", "error exporting resource inventory", joberrors.CodeGeneration, err)
+	}
+
+	err = j.policyEvaluator.Execute(ctx)
+	if err != nil {
+		return joberrors.Wrap("run_job", "error evaluating policies", joberrors.CodePolicyEvaluation, err)
 	}
 
 	createDummyFile := driftedResourcesIdentified && j.noNewResources
-	prURL, err := j.resourcesWriter.Execute(ctx, j.name, createDummyFile, workspaceToDirectory)
+	prURLs, err := j.resourcesWriter.Execute(ctx, j.name, createDummyFile, workspaceToDirectory)
+	if err != nil {
+		return joberrors.Wrap("run_job", "error writing resources on vcs", joberrors.CodeVCS, err)
+	}
+
+	err = j.dragonDrop.PutJobPullRequestURLs(ctx, prURLs)
 	if err != nil {
-		return fmt.Errorf("[run_job][error writing resources on vcs][%w]", err)
+		return joberrors.Wrap("run_job", "error putting job pull request URL", joberrors.CodeDragonDropAPI, err)
 	}
 
-	err = j.dragonDrop.PutJobPullRequestURL(ctx, prURL)
+	// Notifications are informational, so a failure to deliver them does not fail the job run.
+	err = j.notifier.Notify(ctx, prURLs)
 	if err != nil {
-		return fmt.Errorf("[run_job][error putting job pull request URL][%v]", err)
+		log.Warnf("[run_job][notifications not delivered]%s", err.Error())
 	}
 
 	err = j.dragonDrop.InformComplete(ctx)
 	if err != nil {
-		return fmt.Errorf("[run_job][error informing complete status][%w]", err)
+		return joberrors.Wrap("run_job", "error informing complete status", joberrors.CodeDragonDropAPI, err)
 	}
 
 	return nil
 }
 
+// InformFailure informs the dragondrop API that the job has failed, with the machine-readable code of err.
+func (j *Job) InformFailure(ctx context.Context, err error) {
+	informErr := j.dragonDrop.InformFailed(ctx, string(joberrors.CodeOf(err)))
+	if informErr != nil {
+		log.Errorf("[inform_failure]%s", informErr.Error())
+	}
+}
+
 // InitializeJobDependencies instantiates interface implementations for all needed interfaces
 // and configures by pulling in environment variables.
 func InitializeJobDependencies(ctx context.Context, env string) (*Job, error) {
-	var jobConfig JobConfig
-	err := envconfig.Process("CLOUDCONCIERGE", &jobConfig)
+	return InitializeJobDependenciesWithOverrides(ctx, env, nil)
+}
+
+// InitializeJobDependenciesWithOverrides instantiates interface implementations for all needed interfaces, configured
+// by the environment variables, the config file and the passed per-run overrides, e.g. of a scheduled trigger.
+func InitializeJobDependenciesWithOverrides(ctx context.Context, env string, overrides map[string]string) (*Job, error) {
+	jobConfig, err := loadJobConfig(overrides)
+	if err != nil {
+		return nil, joberrors.Wrap("initialize_job", "cannot create job config", joberrors.CodeConfiguration, err)
+	}
+
+	workingDirectory, err := enterWorkingDirectory(jobConfig)
+	if err != nil {
+		return nil, joberrors.Wrap("initialize_job", "cannot enter working directory", joberrors.CodeConfiguration, err)
+	}
+
+	err = refreshDivisionCredentials(ctx, &jobConfig)
 	if err != nil {
-		return nil, fmt.Errorf("[cannot create job config]%w", err)
+		return nil, joberrors.Wrap("initialize_job", "cannot refresh division credentials", joberrors.CodeAuthentication, err)
 	}
 
 	err = validateJobConfig(jobConfig)
 	if err != nil {
-		return nil, fmt.Errorf("[invalid job config]%w", err)
+		return nil, joberrors.Wrap("initialize_job", "invalid job config", joberrors.CodeConfiguration, err)
+	}
+
+	artifactStore := artifacts.NewMemoryStore(jobConfig.SpillArtifacts)
+
+	err = addAWSOrganizationDivisions(&jobConfig)
+	if err != nil {
+		return nil, joberrors.Wrap("initialize_job", "cannot discover aws organization accounts", joberrors.CodeCloudScan, err)
+	}
+
+	err = addGCPProjectDivisions(ctx, &jobConfig)
+	if err != nil {
+		return nil, joberrors.Wrap("initialize_job", "cannot enumerate gcp projects", joberrors.CodeCloudScan, err)
+	}
+
+	err = addAzureManagementGroupDivisions(ctx, &jobConfig)
+	if err != nil {
+		return nil, joberrors.Wrap("initialize_job", "cannot enumerate azure management group subscriptions", joberrors.CodeCloudScan, err)
 	}
 
 	inferredData, err := getInferredData(jobConfig)
 	if err != nil {
 		log.Errorf("[cannot create job config]%s", err.Error())
-		return nil, fmt.Errorf("[cannot create job config]%w", err)
+		return nil, joberrors.Wrap("initialize_job", "cannot create job config", joberrors.CodeConfiguration, err)
 	}
 
 	dragonDropInstance, err := (&dragonDrop.Factory{}).Instantiate(env, jobConfig.getDragonDropConfig())
 	if err != nil {
 		return nil, err
 	}
-	vcsInstance, err := (&vcs.Factory{}).Instantiate(ctx, env, dragonDropInstance, jobConfig.getVCSConfig())
+	vcsInstance, err := (&vcs.Factory{}).Instantiate(ctx, env, dragonDropInstance, jobConfig.getVCSConfig(), artifactStore)
 	if err != nil {
 		return nil, err
 	}
-	workspace, err := (&terraformWorkspace.Factory{}).Instantiate(ctx, env, dragonDropInstance, jobConfig.getTerraformWorkspaceConfig())
+	workspace, err := (&terraformWorkspace.Factory{}).Instantiate(ctx, env, dragonDropInstance, jobConfig.getTerraformWorkspaceConfig(), jobConfig.getContainerBackendConfig())
+	if err != nil {
+		return nil, err
+	}
+	store, err := (&runStateStore.Factory{}).Instantiate(env, jobConfig.getRunStateStoreConfig())
 	if err != nil {
 		return nil, err
 	}
 	executor, err := (&terraformerExecutor.Factory{}).Instantiate(ctx, env, dragonDropInstance, inferredData.DivisionToProvider,
-		jobConfig.getHCLCreateConfig(), jobConfig.getTerraformerConfig(), jobConfig.getTerraformerCLIConfig())
+		jobConfig.getHCLCreateConfig(), jobConfig.getTerraformerConfig(), jobConfig.getTerraformerCLIConfig(), store, artifactStore)
 	if err != nil {
 		return nil, err
 	}
 	instantiate, err := (&terraformImportMigrationGenerator.Factory{}).Instantiate(ctx, env, dragonDropInstance, inferredData.DivisionToProvider,
-		jobConfig.getTerraformImportMigrationGeneratorConfig())
+		jobConfig.getTerraformImportMigrationGeneratorConfig(), artifactStore)
+	if err != nil {
+		return nil, err
+	}
+	calculator, err := (&resourcesCalculator.Factory{}).Instantiate(ctx, env, dragonDropInstance, inferredData.DivisionToProvider, jobConfig.getResourcesCalculatorConfig(), artifactStore)
+	if err != nil {
+		return nil, err
+	}
+	costEstimator, err := (&costEstimation.Factory{}).Instantiate(env, inferredData.DivisionToProvider, jobConfig.getCostEstimationConfig(), artifactStore)
 	if err != nil {
 		return nil, err
 	}
-	calculator, err := (&resourcesCalculator.Factory{}).Instantiate(ctx, env, dragonDropInstance, inferredData.DivisionToProvider)
+	identifier, err := (&identifyCloudActors.Factory{}).Instantiate(ctx, env, dragonDropInstance, inferredData.DivisionToProvider, jobConfig.getIdentifyCloudActorsConfig(), artifactStore)
 	if err != nil {
 		return nil, err
 	}
-	costEstimator, err := (&costEstimation.Factory{}).Instantiate(env, inferredData.DivisionToProvider, jobConfig.getCostEstimationConfig())
+	tfSec, err := (&terraformSecurity.Factory{}).Instantiate(ctx, env, inferredData.DivisionToProvider, jobConfig.getTerraformSecurityConfig(), artifactStore)
 	if err != nil {
 		return nil, err
 	}
-	identifier, err := (&identifyCloudActors.Factory{}).Instantiate(ctx, env, dragonDropInstance, inferredData.DivisionToProvider, jobConfig.getIdentifyCloudActorsConfig())
+	writer, err := (&resourcesWriter.Factory{}).Instantiate(ctx, env, vcsInstance, dragonDropInstance, tfSec, inferredData.DivisionToProvider, jobConfig.getHCLCreateConfig(), jobConfig.getCodeValidationConfig(), jobConfig.MaxResourcesPerPullRequest, artifactStore)
 	if err != nil {
 		return nil, err
 	}
-	writer, err := (&resourcesWriter.Factory{}).Instantiate(ctx, env, vcsInstance, dragonDropInstance, inferredData.DivisionToProvider, jobConfig.getHCLCreateConfig())
+	driftDetector, err := (&terraformManagedResourcesDriftDetector.Factory{}).Instantiate(ctx, env, inferredData.DivisionToProvider, jobConfig.getDriftDetectorConfig(), artifactStore)
 	if err != nil {
 		return nil, err
 	}
-	driftDetector, err := (&terraformManagedResourcesDriftDetector.Factory{}).Instantiate(ctx, env, inferredData.DivisionToProvider)
+	inventory, err := (&inventoryExporter.Factory{}).Instantiate(env, inferredData.DivisionToProvider, jobConfig.getInventoryExporterConfig(), store, artifactStore)
 	if err != nil {
 		return nil, err
 	}
-	tfSec, err := (&terraformSecurity.Factory{}).Instantiate(ctx, env, inferredData.DivisionToProvider)
+	evaluator, err := (&policyEvaluator.Factory{}).Instantiate(env, inferredData.DivisionToProvider, jobConfig.getPolicyEvaluatorConfig(), artifactStore)
+	if err != nil {
+		return nil, err
+	}
+	jobNotifier, err := (&notifier.Factory{}).Instantiate(env, jobConfig.getNotifierConfig())
 	if err != nil {
 		return nil, err
 	}
@@ -303,15 +419,119 @@ func InitializeJobDependencies(ctx context.Context, env string) (*Job, error) {
 		identifyCloudActors:               identifier,
 		driftDetector:                     driftDetector,
 		config:                            jobConfig,
+		workingDirectory:                  workingDirectory,
 		terraformSecurity:                 tfSec,
+		inventoryExporter:                 inventory,
+		policyEvaluator:                   evaluator,
+		notifier:                          jobNotifier,
 	}, nil
 }
 
+// refreshDivisionCredentials replaces division credentials with those returned by the credential refresh hook, so
+// that short-lived credentials are fresh for each run.
+func refreshDivisionCredentials(ctx context.Context, config *JobConfig) error {
+	refreshConfig := config.getCredentialRefreshConfig()
+	if !refreshConfig.IsConfigured() {
+		return nil
+	}
+
+	credentials, err := credentialrefresh.Refresh(ctx, refreshConfig)
+	if err != nil {
+		return err
+	}
+
+	if config.DivisionCloudCredentials == nil {
+		config.DivisionCloudCredentials = terraformValueObjects.DivisionCloudCredentialDecoder{}
+	}
+	for division, credential := range credentials {
+		config.DivisionCloudCredentials[division] = credential
+	}
+	log.Infof("refreshed credentials for %v divisions", len(credentials))
+
+	return nil
+}
+
+// addAWSOrganizationDivisions adds a division for each member account of the AWS organization managed by
+// AWSOrganizationsManagementDivision. Divisions that are already configured are left untouched.
+func addAWSOrganizationDivisions(config *JobConfig) error {
+	if config.AWSOrganizationsManagementDivision == "" {
+		return nil
+	}
+
+	management := config.DivisionCloudCredentials[terraformValueObjects.Division(config.AWSOrganizationsManagementDivision)]
+	divisions, err := awscredentials.DiscoverOrganizationDivisions(management, config.AWSOrganizationsMemberRoleName, config.AWSOrganizationsExcludedAccounts)
+	if err != nil {
+		return err
+	}
+
+	for division, credential := range divisions {
+		if _, ok := config.DivisionCloudCredentials[division]; !ok {
+			config.DivisionCloudCredentials[division] = credential
+		}
+	}
+	log.Infof("discovered %v aws organization member accounts", len(divisions))
+
+	return nil
+}
+
+// addGCPProjectDivisions adds a division for each active project within GCPProjectsParent, accessed with the
+// credential of GCPProjectsDivision. Divisions that are already configured are left untouched.
+func addGCPProjectDivisions(ctx context.Context, config *JobConfig) error {
+	if config.GCPProjectsParent == "" {
+		return nil
+	}
+
+	credential := config.DivisionCloudCredentials[terraformValueObjects.Division(config.GCPProjectsDivision)]
+	divisions, err := gcpprojects.DiscoverProjectDivisions(ctx, credential, config.GCPProjectsParent, config.GCPExcludedProjects)
+	if err != nil {
+		return err
+	}
+
+	for division, credential := range divisions {
+		if _, ok := config.DivisionCloudCredentials[division]; !ok {
+			config.DivisionCloudCredentials[division] = credential
+		}
+	}
+	log.Infof("enumerated %v gcp projects within %v", len(divisions), config.GCPProjectsParent)
+
+	return nil
+}
+
+// addAzureManagementGroupDivisions adds a division for each resource group of every subscription beneath
+// AzureManagementGroupID, accessed with the credential of AzureManagementGroupDivision. Divisions that are already
+// configured are left untouched.
+func addAzureManagementGroupDivisions(ctx context.Context, config *JobConfig) error {
+	if config.AzureManagementGroupID == "" {
+		return nil
+	}
+
+	credential := config.DivisionCloudCredentials[terraformValueObjects.Division(config.AzureManagementGroupDivision)]
+	divisions, err := azuremanagementgroups.DiscoverResourceGroupDivisions(ctx, credential, config.AzureManagementGroupID, config.AzureExcludedSubscriptions)
+	if err != nil {
+		return err
+	}
+
+	for division, credential := range divisions {
+		if _, ok := config.DivisionCloudCredentials[division]; !ok {
+			config.DivisionCloudCredentials[division] = credential
+		}
+	}
+	log.Infof("enumerated %v azure resource groups beneath management group %v", len(divisions), config.AzureManagementGroupID)
+
+	return nil
+}
+
 func getInferredData(config JobConfig) (InferredData, error) {
 	divisionToProvider := make(map[terraformValueObjects.Division]terraformValueObjects.Provider)
 
 	for division, credential := range config.DivisionCloudCredentials {
-		provider, err := getProviderByCredential(credential)
+		var provider terraformValueObjects.Provider
+		var err error
+		if credential.IsAmbient() {
+			provider, err = getAmbientProvider(credential, config.Providers)
+		} else {
+			provider, err = getProviderByCredential(credential)
+		}
 		if err != nil {
 			return InferredData{}, fmt.Errorf("[error getting the inferred data][%w]", err)
 		}
@@ -324,6 +544,27 @@ func getInferredData(config JobConfig) (InferredData, error) {
 	}, nil
 }
 
+// getAmbientProvider determines the provider of an ambient credential, either from the provider it names or, for an
+// empty credential, from the single configured provider.
+func getAmbientProvider(credential terraformValueObjects.Credential, providers map[terraformValueObjects.Provider]string) (terraformValueObjects.Provider, error) {
+	provider := credential.AmbientProvider()
+	if provider == "" {
+		if len(providers) != 1 {
+			return "", fmt.Errorf("[an empty credential requires exactly one configured provider, otherwise specify `%v`]", terraformValueObjects.AmbientCredentialField)
+		}
+		for configuredProvider := range providers {
+			provider = configuredProvider
+		}
+	}
+
+	switch provider {
+	case "aws", "google", "azurerm":
+		return provider, nil
+	default:
+		return "", fmt.Errorf("[ambient credentials are not supported for provider %v]", provider)
+	}
+}
+
 func getProviderByCredential(credential terraformValueObjects.Credential) (terraformValueObjects.Provider, error) {
 	var credentialMapped map[string]string
 	err := json.Unmarshal([]byte(credential), &credentialMapped)
@@ -336,7 +577,7 @@ func getProviderByCredential(credential terraformValueObjects.Credential) (terra
 		return "azurerm", nil
 	}
 
-	if strings.Trim(credentialMapped["awsAccessKeyID"], "") != "" && strings.Trim(credentialMapped["awsSecretAccessKey"], "") != "" {
+	if awscredentials.IsAWSCredential(credentialMapped) {
 		return "aws", nil
 	}
 
@@ -348,5 +589,22 @@ func getProviderByCredential(credential terraformValueObjects.Credential) (terra
 		return "google", nil
 	}
 
+	if strings.Trim(credentialMapped["datadog_api_key"], "") != "" && strings.Trim(credentialMapped["datadog_app_key"], "") != "" {
+		return "datadog", nil
+	}
+
+	if strings.Trim(credentialMapped["tenancy_ocid"], "") != "" && strings.Trim(credentialMapped["user_ocid"], "") != "" &&
+		strings.Trim(credentialMapped["fingerprint"], "") != "" && strings.Trim(credentialMapped["private_key"], "") != "" {
+		return "oci", nil
+	}
+
+	if strings.Trim(credentialMapped["digitalocean_token"], "") != "" {
+		return "digitalocean", nil
+	}
+
+	if strings.Trim(credentialMapped["okta_org_name"], "") != "" && strings.Trim(credentialMapped["okta_api_token"], "") != "" {
+		return "okta", nil
+	}
+
 	return "", fmt.Errorf("provider not supported")
 }
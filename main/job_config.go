@@ -2,18 +2,33 @@ package main
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/awscredentials"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/codevalidation"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/credentialrefresh"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/hclcreate"
 
 	costEstimation "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/cost_estimation"
 	dragonDrop "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/dragon_drop"
 	identifyCloudActors "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/identify_cloud_actors"
+	inventoryExporter "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/inventory_exporter"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/notifier"
+	policyEvaluator "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/policy_evaluator"
+	resourcesCalculator "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/resources_calculator"
+	runStateStore "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/run_state_store"
 	terraformImportMigrationGenerator "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_import_migration_generator"
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+	terraformSecurity "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_security"
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
 	terraformWorkspace "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_workspace"
 	terraformerCli "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraformer_executor/terraformer_cli"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/vcs"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/nlpengine"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/ratelimit"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/schedule"
 )
 
 // JobConfig is the configuration for the Job that contains the variables to run successfully
@@ -21,12 +36,157 @@ type JobConfig struct {
 	// IsManagedDriftOnly represents the option for the user to only scan drifted resources and not new resources
 	IsManagedDriftOnly bool `default:"false"`
 
+	// ManagedDriftOnlyDivisions are the divisions for which only drifted resources are scanned, while new resources
+	// are codified for all other divisions.
+	ManagedDriftOnlyDivisions []string
+
 	// DivisionCloudCredentials is a map between a division and request cloud credentials to infer the division to provider.
 	DivisionCloudCredentials terraformValueObjects.DivisionCloudCredentialDecoder `required:"true"`
 
+	// CredentialRefreshCommand is a command run before each job run that prints refreshed division credentials, as a
+	// json object mapping each division to its credential, which replace the configured credentials.
+	CredentialRefreshCommand string
+
+	// CredentialRefreshURL is requested before each job run in place of CredentialRefreshCommand, and responds with
+	// refreshed division credentials.
+	CredentialRefreshURL string
+
+	// CredentialRefreshURLToken is the bearer token passed when requesting CredentialRefreshURL.
+	CredentialRefreshURLToken string
+
+	// CredentialRefreshTimeout is the maximum duration of the credential refresh hook.
+	CredentialRefreshTimeout time.Duration `default:"1m"`
+
+	// AWSOrganizationsManagementDivision is the division whose credential belongs to an AWS organization's management
+	// account. When set, every active member account of the organization is added as a division named by its account id.
+	AWSOrganizationsManagementDivision string
+
+	// AWSOrganizationsMemberRoleName is the role assumed within each discovered member account.
+	AWSOrganizationsMemberRoleName string `default:"OrganizationAccountAccessRole"`
+
+	// AWSOrganizationsExcludedAccounts are the ids of member accounts that are not to be scanned.
+	AWSOrganizationsExcludedAccounts []string
+
+	// GCPProjectsDivision is the division whose service account, with resourcemanager permissions, is used to enumerate
+	// the projects within GCPProjectsParent. Every active project is added as a division named by its project id.
+	GCPProjectsDivision string
+
+	// GCPProjectsParent is the organization (organizations/<id>) or folder (folders/<id>) whose projects are enumerated.
+	GCPProjectsParent string
+
+	// GCPExcludedProjects are the ids of enumerated projects that are not to be scanned.
+	GCPExcludedProjects []string
+
+	// AzureManagementGroupDivision is the division whose service principal or managed identity, with reader access to
+	// AzureManagementGroupID, is used to enumerate the resource groups of every subscription beneath the management
+	// group. Each resource group is added as a division named <subscription id>_<resource group>.
+	AzureManagementGroupDivision string
+
+	// AzureManagementGroupID is the id of the management group whose subscriptions are enumerated.
+	AzureManagementGroupID string
+
+	// AzureExcludedSubscriptions are the ids of subscriptions beneath the management group that are not to be scanned.
+	AzureExcludedSubscriptions []string
+
 	// InfracostAPIToken is the token for accessing Infracost's API.
 	InfracostAPIToken string `required:"true"`
 
+	// CostUsageFile is the path, relative to the root of the scanned repository, of an Infracost usage file
+	// estimating the consumption of usage based resources.
+	CostUsageFile string `default:"infracost-usage.yml"`
+
+	// CostCurrency is the ISO 4217 code of the currency in which costs are reported.
+	CostCurrency string `default:"USD"`
+
+	// CostExchangeRate is a static number of units of CostCurrency per USD. The ECB reference rate is used when unset.
+	CostExchangeRate float64 `default:"0"`
+
+	// WorkspaceMonthlyCostBudget is the monthly cost, in CostCurrency, of a workspace's resources outside of Terraform
+	// control at which the workspace's cost health score reaches 0.
+	WorkspaceMonthlyCostBudget float64 `default:"1000"`
+
+	// PriceCacheDirectory is the directory, typically a mounted volume, in which pricing data is cached between runs.
+	// Caching is disabled when empty.
+	PriceCacheDirectory string
+
+	// PriceCacheMaxAge is the maximum age of cached pricing data before it is downloaded again.
+	PriceCacheMaxAge time.Duration `default:"24h"`
+
+	// PricingConcurrency is the maximum number of concurrent pricing requests, and of divisions priced at once.
+	PricingConcurrency int `default:"8"`
+
+	// PricingMaxRetries is the number of times a failed pricing request is retried before resources are reported
+	// with an unknown cost.
+	PricingMaxRetries int `default:"3"`
+
+	// PricingRetryBackoff is the backoff before the first retry of a failed pricing request, doubled on each retry.
+	PricingRetryBackoff time.Duration `default:"1s"`
+
+	// OfflinePricing flags that resources are priced only from a pricing snapshot, for air-gapped deployments.
+	OfflinePricing bool `default:"false"`
+
+	// PricingSnapshotDirectory is the pricing snapshot used when pricing offline. Defaults to the snapshot bundled
+	// within the container image.
+	PricingSnapshotDirectory string
+
+	// InfracostPricingAPIEndpoint is the url of a self-hosted Infracost Cloud Pricing API, which also prices the
+	// resources absent from the pricing snapshot when pricing offline.
+	InfracostPricingAPIEndpoint string
+
+	// CostAllocationTags are the tag, or GCP label, keys by which resource costs are rolled up, e.g. "team" or "env".
+	CostAllocationTags []string
+
+	// SecurityScanner is the static security scanner run against the scanned cloud's Terraform representation,
+	// one of "trivy", "checkov" or the deprecated "tfsec".
+	SecurityScanner string `default:"trivy"`
+
+	// SecuritySeverityThreshold is the minimum severity, one of "LOW", "MEDIUM", "HIGH" or "CRITICAL", of security
+	// findings within newly generated resources that trip the security gate. The gate is disabled when empty.
+	SecuritySeverityThreshold string
+
+	// SecurityUnknownSeverity is the severity, one of "LOW", "MEDIUM", "HIGH" or "CRITICAL", that security findings
+	// without a known severity are gated as. Such findings never trip the security gate when empty.
+	SecurityUnknownSeverity string `default:"HIGH"`
+
+	// SecurityFailOnFindings is whether findings that trip the security gate fail the job, rather than being called
+	// out within the pull request.
+	SecurityFailOnFindings bool
+
+	// SecuritySARIFOutputPath is the path to which security findings are written as a SARIF file.
+	SecuritySARIFOutputPath string `default:"security/results.sarif"`
+
+	// SecurityUploadSARIF is whether the SARIF file of security findings is uploaded to the repository's GitHub code
+	// scanning, for the commit of the pull request.
+	SecurityUploadSARIF bool
+
+	// SecurityCustomChecksDirectory is a mounted directory of custom checks passed to the security scanner: rego
+	// checks for trivy and tfsec, or python and yaml checks for checkov.
+	SecurityCustomChecksDirectory string
+
+	// SecurityPolicyBundles are mounted directories of organization policy bundles passed to the security scanner
+	// alongside the custom checks.
+	SecurityPolicyBundles []string
+
+	// SecurityPolicyNamespaces are the rego namespaces, beyond trivy's default "user" namespace, of the custom checks
+	// and policy bundles evaluated by trivy.
+	SecurityPolicyNamespaces []string
+
+	// SecurityScanScopes are the scopes of the security scan, any of "generated" for the code generated by
+	// cloud-concierge, "changed" for the repository directories changed by the pull request, and "repository" for the
+	// entire repository.
+	SecurityScanScopes []string `default:"generated"`
+
+	// PolicyDirectories are mounted directories of Rego policies evaluated against the run output: new resources,
+	// drift, costs and security findings. Policies are not evaluated when empty.
+	PolicyDirectories []string
+
+	// PolicyQuery is the Rego query whose results are the policy violations.
+	PolicyQuery string `default:"data.cloudconcierge.deny"`
+
+	// PolicyFailOnViolations is whether policy violations fail the job, rather than being listed within the pull
+	// request.
+	PolicyFailOnViolations bool
+
 	// APIPath is the dragondrop api path to which requests are sent.
 	APIPath string `default:"https://api.dragondrop.cloud"`
 
@@ -46,9 +206,50 @@ type JobConfig struct {
 	// TerraformVersion is the version of Terraform used.
 	TerraformVersion string `required:"true"`
 
+	// VariableExtraction, when true, lifts environment-specific and sensitive values out of the resources generated
+	// by the job and into variables.
+	VariableExtraction bool `default:"false"`
+
+	// VariableExtractionPatterns are regular expressions, matched case-insensitively against attribute names, for
+	// attributes whose values are lifted into variables. When set, they replace the default account id, ARN and
+	// project, subscription and tenant id patterns.
+	VariableExtractionPatterns []string
+
+	// IgnoreChanges maps resource types to the attributes listed within the lifecycle ignore_changes of their
+	// generated resource blocks, e.g. {"aws_autoscaling_group": ["desired_capacity"]}.
+	IgnoreChanges hclcreate.IgnoreChangesDecoder
+
+	// ModuleWrapping, when true, generates the new resources of each workspace within a child module per service,
+	// called from the workspace's new-resources.tf, rather than as flat resource blocks.
+	ModuleWrapping bool `default:"false"`
+
+	// NewWorkspaceBackend is the template of the backend block written within the main.tf of each new workspace, as a
+	// json object with `type` and `config` fields, where {workspace} and {directory} are replaced within config values.
+	NewWorkspaceBackend hclcreate.BackendTemplateDecoder
+
+	// ImportOutput selects how new resources are imported, one of "auto", "import-blocks", "tfmigrate" or "script".
+	// auto writes import blocks for Terraform 1.5.0 or higher, and tfmigrate migrations otherwise.
+	ImportOutput string `default:"auto"`
+
+	// GeneratedDirectory is the subdirectory of each workspace within which supporting files, such as import blocks,
+	// migrations and child modules, are generated. Defaults to hclcreate.DefaultGeneratedDirectory when empty.
+	GeneratedDirectory string
+
+	// NewResourcesLayout selects how the new resources of each workspace are split into files, one of "single" for
+	// new-resources.tf, "service" for a file per service, "type" for a file per resource type or "division" for a
+	// file per provider division.
+	NewResourcesLayout string `default:"single"`
+
+	// GeneratedCodeValidators are the validators run against the generated code of each workspace before it is
+	// committed, any of "terraform" for terraform validate and "tflint". Validation is disabled when empty.
+	GeneratedCodeValidators []string `default:"terraform,tflint"`
+
 	// StateBackend is the name of the backend used for storing State.
 	StateBackend string `required:"true"`
 
+	// AWSRegion is the region of the S3 bucket used as the state backend.
+	AWSRegion string
+
 	// TerraformCloudOrganization is the name of the organization within Terraform Cloud
 	TerraformCloudOrganization string
 
@@ -82,6 +283,16 @@ type JobConfig struct {
 	// PullReviewers is the name of the pull request reviewer who will be tagged on the opened pull request.
 	PullReviewers []string `default:"NoReviewer"`
 
+	// ActorReviewersFile is the path of a mounted yaml file mapping cloud actors onto VCS users, who are requested to
+	// review the pull request alongside PullReviewers when they created unmanaged resources or caused drift.
+	ActorReviewersFile string
+
+	// MaxResourcesPerPullRequest, when positive, is the maximum number of new resources within each pull request.
+	// When a run identifies more, the changes of further workspaces are opened as separate pull requests, each based
+	// upon the base branch, so that review stays tractable. A workspace with more new resources than the maximum is
+	// split by the blocks of its new resources files, each pull request holding the import blocks of its share.
+	MaxResourcesPerPullRequest int
+
 	// ResourcesWhiteList represents the list of resource names that will be exclusively considered for inclusion in the import statement.
 	ResourcesWhiteList terraformValueObjects.ResourceNameList
 
@@ -90,6 +301,346 @@ type JobConfig struct {
 
 	// CloudRegions represents the list of cloud regions that will be considered for inclusion in the import statement.
 	CloudRegions terraformValueObjects.CloudRegionsDecoder
+
+	// ResourceTagInclusions are the tag selectors that a resource must match to be considered for inclusion in the import statement.
+	ResourceTagInclusions terraformValueObjects.ResourceTagSelectors
+
+	// ResourceTagExclusions are the tag selectors for which matching resources are excluded from consideration for inclusion in the import statement.
+	ResourceTagExclusions terraformValueObjects.ResourceTagSelectors
+
+	// ResourceIDExclusions are regular expressions for which resources with a matching cloud id are excluded from consideration
+	// for inclusion in the import statement.
+	ResourceIDExclusions terraformValueObjects.ResourcePatternList
+
+	// ResourceNameExclusions are regular expressions for which resources with a matching cloud name are excluded from consideration
+	// for inclusion in the import statement.
+	ResourceNameExclusions terraformValueObjects.ResourcePatternList
+
+	// SpillArtifacts flags that the artifacts passed between pipeline stages, such as the json mappings within
+	// mappings/, are written to disk as they are produced. Otherwise they are kept in memory and only written to disk
+	// before an external tool that reads them, such as opa or the state of cloud report, runs. Terraformer outputs and
+	// the cloned repository are always written to disk.
+	SpillArtifacts bool `default:"true"`
+
+	// WorkingDirectory is the directory within which the job clones the repository and writes its artifacts, such as
+	// mappings/, current_cloud/ and state_of_cloud/, e.g. a mounted volume. When empty, the job works within the
+	// directory from which it was started.
+	WorkingDirectory string
+
+	// UniqueRunDirectories flags that each job run works within its own subdirectory, runs/<job id>/, of the working
+	// directory, so that multiple jobs may run on one host.
+	UniqueRunDirectories bool `default:"false"`
+
+	// Schedule, when set, is a cron expression, e.g. "0 6 * * 1-5", on which the job runs as a long-running daemon.
+	// Each scheduled run works within its own subdirectory, runs/<scheduled time>/, of the working directory. When
+	// empty, the job runs once and exits.
+	Schedule string
+
+	// ScheduleTimeZone is the time zone, e.g. "America/New_York", within which the cron schedule is evaluated.
+	ScheduleTimeZone string `default:"UTC"`
+
+	// ScheduleRetainedRuns is the number of most recent scheduled run directories kept within runs/ of the working
+	// directory, older ones being removed after each run. All are kept when not positive.
+	ScheduleRetainedRuns int `default:"10"`
+
+	// TriggerListenAddress, when set, is the address, e.g. ":8080", on which the job runs as a long-running daemon
+	// accepting POST requests to /v1/runs that trigger a run, optionally with per-run config overrides as a json object
+	// body. Triggered runs are queued behind a run in progress, alongside any schedule.
+	TriggerListenAddress string
+
+	// TriggerToken is the bearer token required by the trigger endpoint.
+	TriggerToken string
+
+	// CommandTimeout is the maximum duration of a single terraformer or terraform invocation. Zero disables the limit.
+	CommandTimeout time.Duration `default:"0"`
+
+	// CommandMaxRetries is the number of times a failed terraformer or terraform invocation is retried.
+	CommandMaxRetries int `default:"0"`
+
+	// CommandRetryBackoff is the wait before the first retry of a failed invocation, doubled on each subsequent retry.
+	CommandRetryBackoff time.Duration `default:"10s"`
+
+	// ContinueOnPartialFailure flags that a division whose import fails is re-imported resource group by resource group,
+	// keeping the groups that succeed.
+	ContinueOnPartialFailure bool `default:"false"`
+
+	// ChunkedImport flags that each cloud service is imported by a separate terraformer invocation and the outputs
+	// merged, capping the memory used when scanning large divisions.
+	ChunkedImport bool `default:"false"`
+
+	// ImportConcurrency is the number of cloud services imported at once when importing service by service. Each
+	// concurrent import holds its own terraformer process in memory.
+	ImportConcurrency int `default:"1"`
+
+	// ResumeScans flags that the progress of each division's scan is persisted within the run state store, so that a
+	// division whose scan was interrupted or partially failed resumes from the cloud services not yet imported.
+	ResumeScans bool `default:"false"`
+
+	// ResumeMaxAge is the maximum age of persisted scan progress before a division is scanned from scratch.
+	ResumeMaxAge time.Duration `default:"24h"`
+
+	// PluginMirrorDirectory is a pre-populated terraform provider filesystem mirror. When set, providers are never
+	// downloaded from the network.
+	PluginMirrorDirectory string
+
+	// PluginCacheDirectory is the directory in which terraform caches installed providers.
+	PluginCacheDirectory string
+
+	// NetworkIsolated flags that the job runs without outbound registry access, installing providers from the plugin
+	// mirror, or the plugins bundled within the image, and using the pre-installed terraform binary.
+	NetworkIsolated bool `default:"false"`
+
+	// AWSNativeInventoryDivisions are the AWS divisions inventoried directly through the AWS Cloud Control API rather
+	// than with terraformer.
+	AWSNativeInventoryDivisions []string
+
+	// APIRequestsPerSecond limits the rate of terraformer invocations and actor identification queries made against
+	// cloud provider APIs. Rate limiting is disabled when zero.
+	APIRequestsPerSecond float64 `default:"0"`
+
+	// APIBurst is the number of calls that may be made at once before the rate limit applies.
+	APIBurst int `default:"1"`
+
+	// APIThrottleMaxRetries is the number of times a call rejected by the provider as throttled is retried.
+	APIThrottleMaxRetries int `default:"5"`
+
+	// APIThrottleMaxBackoff caps the wait between calls, which doubles each time the provider throttles a call.
+	APIThrottleMaxBackoff time.Duration `default:"5m"`
+
+	// ActorIdentityMappingFile is the path of a mounted yaml file mapping the principals recorded within audit logs,
+	// such as assumed-role sessions or impersonated service accounts, onto human identities.
+	ActorIdentityMappingFile string
+
+	// ActorExclusions are wildcard patterns for automation principals, such as CI roles, whose changes are not
+	// attributed to a cloud actor, in addition to the built-in patterns for cloud service principals and service
+	// accounts. Patterns prefixed by ! override the built-in patterns, e.g. "!*" to disable them all.
+	ActorExclusions []string
+
+	// ActorQueryBatching flags that the CloudTrail write events of each region are fetched once and indexed by
+	// resource, rather than looked up resource by resource.
+	ActorQueryBatching bool `default:"true"`
+
+	// ActorCacheDirectory is the directory, typically a mounted volume, in which batched CloudTrail events are cached
+	// between runs, so that each run only fetches the events since the previous one. Caching is disabled when empty.
+	ActorCacheDirectory string
+
+	// AWSEventArchive is the archive of CloudTrail events, either "cloudtrail-lake" or "athena", searched for the
+	// actors of AWS resources whose events are older than the 90-day window of CloudTrail LookupEvents. Disabled
+	// when empty.
+	AWSEventArchive string
+
+	// AWSEventArchiveLookbackDays is the number of days of events searched within the event archive, unbounded when 0.
+	AWSEventArchiveLookbackDays int `default:"2555"`
+
+	// AWSEventArchiveRegion is the region in which the event archive is queried, defaulting to each resource's region.
+	AWSEventArchiveRegion string
+
+	// CloudTrailLakeEventDataStore is the id or ARN of the CloudTrail Lake event data store searched for events.
+	CloudTrailLakeEventDataStore string
+
+	// AthenaCloudTrailDatabase is the Athena database containing the table over the S3 bucket of a CloudTrail trail.
+	AthenaCloudTrailDatabase string
+
+	// AthenaCloudTrailTable is the Athena table over the S3 bucket of a CloudTrail trail.
+	AthenaCloudTrailTable string
+
+	// AthenaWorkGroup is the Athena work group in which CloudTrail queries are run.
+	AthenaWorkGroup string `default:"primary"`
+
+	// AthenaOutputLocation is the S3 location to which Athena writes query results, optional when the work group
+	// defines one.
+	AthenaOutputLocation string
+
+	// GCPAuditLogScope is the resource name from which Google Cloud audit logs are listed when they are aggregated
+	// above the project level, either the log bucket view of an aggregated sink or the project into whose _Default
+	// log bucket the sink routes. Each project's own audit logs are queried when empty.
+	GCPAuditLogScope string
+
+	// GCPAuditLogBigQueryTable is the BigQuery table, of the form project.dataset.table, of a log sink into which
+	// admin activity audit logs are routed. When set, audit logs are queried from BigQuery rather than Cloud Logging.
+	GCPAuditLogBigQueryTable string
+
+	// GCPAuditLogBigQueryProject is the project in which BigQuery audit log queries are run, defaulting to the
+	// project of GCPAuditLogBigQueryTable.
+	GCPAuditLogBigQueryProject string
+
+	// GCPAuditLogLookbackDays is the number of days of audit logs queried from GCPAuditLogBigQueryTable.
+	GCPAuditLogLookbackDays int `default:"400"`
+
+	// DriftIgnoreRules are rules of the form "<kind>:<glob>" for expected drift that is not reported, where kind is
+	// one of "type", "address" or "attribute", e.g. attribute:aws_autoscaling_group.*.desired_capacity.
+	DriftIgnoreRules []string
+
+	// DriftDetectionConcurrency is the number of state files parsed, and resources compared, at once when detecting
+	// drift in managed resources.
+	DriftDetectionConcurrency int `default:"4"`
+
+	// ResourceIDMappings maps resource types to the expression of their unique id, either the name of an attribute or
+	// a template of {attribute} placeholders. Set as a json object or the path to a yaml or json file.
+	ResourceIDMappings driftDetector.ResourceIDMappings
+
+	// SensitiveAttributePatterns are regular expressions, matched case-insensitively against attribute paths, for
+	// attributes whose values are masked within the generated code, drift output, report, results, inventory and
+	// notifications, in addition to those marked sensitive by the provider schema and the shared patterns of common
+	// secret names such as password or private_key.
+	SensitiveAttributePatterns []string
+
+	// OtherIaCResources is the handling of new resources owned by CloudFormation, Pulumi, or the EKS and GKE
+	// controllers: "include" proposes them for import, "exclude" drops them, and "bucket" reports them separately.
+	OtherIaCResources string `default:"bucket"`
+
+	// DefaultResourceExclusions are rules of the form "<type>:<attribute>=<pattern>&..." for cloud default resources,
+	// in addition to the built-in rules for default VPCs, security groups and service-linked roles, that are not
+	// proposed for import, and only checked for drift once managed within a workspace. Rules prefixed by ! override the built-in rules, e.g. "!*".
+	DefaultResourceExclusions []string
+
+	// MinimumResourceAge is the minimum age of a new resource, from its creation time within its state or cloud actor
+	// identification, before it is proposed for import. Zero disables the filter.
+	MinimumResourceAge time.Duration `default:"0"`
+
+	// PlacementEngine is the engine placing new resources within workspaces: "tfidf" natively compares the documents
+	// of new resources with those of each workspace, "embeddings" compares their embeddings from an external
+	// embeddings API, while "python" runs the legacy python text classification model.
+	PlacementEngine string `default:"tfidf"`
+
+	// PlacementEmbeddingsEndpoint is the URL of the OpenAI-compatible embeddings API used by the "embeddings"
+	// placement engine, e.g. https://api.openai.com/v1/embeddings.
+	PlacementEmbeddingsEndpoint string
+
+	// PlacementEmbeddingsAPIKey is the bearer token passed to PlacementEmbeddingsEndpoint.
+	PlacementEmbeddingsAPIKey string
+
+	// PlacementEmbeddingsModel is the embeddings model requested from PlacementEmbeddingsEndpoint.
+	PlacementEmbeddingsModel string `default:"text-embedding-3-small"`
+
+	// PlacementRulesFile is the path of a mounted yaml file of deterministic rules placing new resources within
+	// workspaces by resource type, tag, region and division, which take precedence over the placement engine.
+	PlacementRulesFile string
+
+	// PlacementTagKeys are the keys of ownership tags or labels, in order of precedence. A new resource is placed within
+	// the single workspace whose managed resources carry the same value of an ownership tag, ahead of the placement
+	// engine.
+	PlacementTagKeys []string `default:"team,service,env"`
+
+	// PlacementTagWorkspaces maps "key=value" tags onto the workspaces within which new resources carrying them are
+	// placed, e.g. "team=payments:payments-prod".
+	PlacementTagWorkspaces map[string]string
+
+	// PlacementConfidenceThreshold is the minimum confidence, between 0 and 1, of a placement recommended by the tfidf
+	// placement engine, below which a new resource is listed for manual placement rather than codified within the
+	// recommended workspace. Zero disables the threshold.
+	PlacementConfidenceThreshold float64 `default:"0"`
+
+	// PlacementEmbeddingsConfidenceThreshold is the minimum confidence, between 0 and 1, of a placement recommended by
+	// the embeddings placement engine. Embedding similarities run higher than tfidf ones, so the engines have separate
+	// thresholds. Zero disables the threshold.
+	PlacementEmbeddingsConfidenceThreshold float64 `default:"0"`
+
+	// CatchAllWorkspaceDirectory is the directory of a catch-all workspace, e.g. "/unmanaged/", within which new
+	// resources fitting no workspace are placed rather than listed for manual placement. When the directory is not
+	// an existing workspace, it is proposed as a new workspace with its own main.tf file.
+	CatchAllWorkspaceDirectory string
+
+	// ComplianceBoundaries is an ordered list of compliance boundaries (e.g. PCI divisions or region residency) by which
+	// new resources are routed to dedicated workspace directories.
+	ComplianceBoundaries resourcesCalculator.ComplianceBoundariesDecoder
+
+	// RunStateStoreBackend is the backend in which state is persisted between job runs, one of "local", "azureblob",
+	// "s3" or "dynamodb".
+	RunStateStoreBackend string `default:"local"`
+
+	// RunStateStoreDirectory is the directory, typically a mounted volume, used by the local run state store.
+	RunStateStoreDirectory string `default:"/cloud-concierge-run-state/"`
+
+	// RunStateStoreAzureStorageAccountName is the storage account used by the azureblob run state store.
+	RunStateStoreAzureStorageAccountName string
+
+	// RunStateStoreAzureStorageAccountKey is the shared key of the storage account used by the azureblob run state store.
+	RunStateStoreAzureStorageAccountKey string
+
+	// RunStateStoreAzureContainerName is the blob container used by the azureblob run state store.
+	RunStateStoreAzureContainerName string
+
+	// RunStateStoreS3Bucket is the bucket used by the s3 run state store.
+	RunStateStoreS3Bucket string
+
+	// RunStateStoreDynamoDBTable is the table used by the dynamodb run state store.
+	RunStateStoreDynamoDBTable string
+
+	// RunStateStoreAWSRegion is the region of the bucket or table used by the s3 and dynamodb run state stores.
+	RunStateStoreAWSRegion string
+
+	// RunStateStorePrefix is prepended to every run state key, allowing several jobs to share a single store.
+	RunStateStorePrefix string
+
+	// InventoryOutputDirectory is the directory to which the resource inventory is written as json and csv.
+	InventoryOutputDirectory string `default:"inventory/"`
+
+	// ResultsOutputPath is the path to which the machine-readable results of the job run are written as json.
+	ResultsOutputPath string `default:"inventory/results.json"`
+
+	// InventoryWebhookURL, when set, receives the resource inventory as a json POST request.
+	InventoryWebhookURL string
+
+	// InventoryWebhookToken is the bearer token passed when posting to InventoryWebhookURL.
+	InventoryWebhookToken string
+
+	// ServiceNowInstanceURL, when set, is the ServiceNow instance whose CMDB the resource inventory is reconciled into.
+	ServiceNowInstanceURL string
+
+	// ServiceNowUsername is the user authenticating with the ServiceNow instance.
+	ServiceNowUsername string
+
+	// ServiceNowPassword is the password of ServiceNowUsername.
+	ServiceNowPassword string
+
+	// ServiceNowClassName is the CMDB class that inventory resources are reconciled as.
+	ServiceNowClassName string `default:"cmdb_ci_cloud_resource"`
+
+	// NotificationTeamsWebhookURL, when set, is the Microsoft Teams incoming webhook receiving the summary of each
+	// job run as an adaptive card.
+	NotificationTeamsWebhookURL string
+
+	// NotificationWebhookURL, when set, receives the summary of each job run as a json POST request.
+	NotificationWebhookURL string
+
+	// NotificationWebhookSecret, when set, signs the requests posted to NotificationWebhookURL with an HMAC-SHA256
+	// signature of their body.
+	NotificationWebhookSecret string
+
+	// NotificationSMTPHost, when set along with NotificationEmailRecipients, is the SMTP server through which the
+	// state of cloud report of each job run is emailed.
+	NotificationSMTPHost string
+
+	// NotificationSMTPPort is the port of NotificationSMTPHost. STARTTLS is used whenever the server supports it.
+	NotificationSMTPPort int `default:"587"`
+
+	// NotificationSMTPUsername and NotificationSMTPPassword, when set, authenticate against NotificationSMTPHost.
+	NotificationSMTPUsername string
+	NotificationSMTPPassword string
+
+	// NotificationEmailFrom is the sender address of the report emails.
+	NotificationEmailFrom string
+
+	// NotificationEmailRecipients are the addresses the report is emailed to.
+	NotificationEmailRecipients []string
+
+	// JiraURL, when set along with JiraProjectKey, is the Jira site within which an issue per division is opened, or
+	// updated while it remains unresolved, summarizing the division's drifted and unmanaged resources.
+	JiraURL string
+
+	// JiraUsername, when set, authenticates against Jira Cloud with basic auth alongside JiraAPIToken. Otherwise,
+	// JiraAPIToken is used as a bearer personal access token, as for Jira Data Center.
+	JiraUsername string
+
+	// JiraAPIToken is the Jira API token or personal access token.
+	JiraAPIToken string
+
+	// JiraProjectKey is the key of the Jira project issues are opened within.
+	JiraProjectKey string
+
+	// JiraIssueType is the name of the type of the Jira issues opened.
+	JiraIssueType string `default:"Task"`
 }
 
 // validateJobConfig validates the JobConfig struct with the values as expected.
@@ -102,9 +653,151 @@ func validateJobConfig(config JobConfig) error {
 			return fmt.Errorf("[terraform cloud token is required when using terraform cloud as state backend]")
 		}
 	}
+
+	if config.AWSOrganizationsManagementDivision != "" {
+		if _, ok := config.DivisionCloudCredentials[terraformValueObjects.Division(config.AWSOrganizationsManagementDivision)]; !ok {
+			return fmt.Errorf("[aws organizations management division %v does not have cloud credentials]", config.AWSOrganizationsManagementDivision)
+		}
+	}
+
+	if config.GCPProjectsDivision != "" || config.GCPProjectsParent != "" {
+		if _, ok := config.DivisionCloudCredentials[terraformValueObjects.Division(config.GCPProjectsDivision)]; !ok {
+			return fmt.Errorf("[gcp projects division %v does not have cloud credentials]", config.GCPProjectsDivision)
+		}
+		if config.GCPProjectsParent == "" {
+			return fmt.Errorf("[gcp projects parent is required when enumerating gcp projects]")
+		}
+	}
+
+	if config.AzureManagementGroupDivision != "" || config.AzureManagementGroupID != "" {
+		if _, ok := config.DivisionCloudCredentials[terraformValueObjects.Division(config.AzureManagementGroupDivision)]; !ok {
+			return fmt.Errorf("[azure management group division %v does not have cloud credentials]", config.AzureManagementGroupDivision)
+		}
+		if config.AzureManagementGroupID == "" {
+			return fmt.Errorf("[azure management group id is required when enumerating azure subscriptions]")
+		}
+	}
+
+	for _, division := range config.ManagedDriftOnlyDivisions {
+		if _, ok := config.DivisionCloudCredentials[terraformValueObjects.Division(division)]; !ok {
+			return fmt.Errorf("[managed drift only division %v does not have cloud credentials]", division)
+		}
+	}
+
+	if config.Schedule != "" {
+		if _, err := schedule.Parse(config.Schedule); err != nil {
+			return fmt.Errorf("[invalid schedule %q]%w", config.Schedule, err)
+		}
+		if _, err := time.LoadLocation(config.ScheduleTimeZone); err != nil {
+			return fmt.Errorf("[invalid schedule time zone %q]%w", config.ScheduleTimeZone, err)
+		}
+	}
+
+	if config.TriggerListenAddress != "" && config.TriggerToken == "" {
+		return fmt.Errorf("[trigger token is required when listening for triggered runs]")
+	}
 	return nil
 }
 
+// isManagedDriftOnly returns true if only drifted resources are to be scanned for every division.
+func (c JobConfig) isManagedDriftOnly() bool {
+	if c.IsManagedDriftOnly {
+		return true
+	}
+
+	if len(c.ManagedDriftOnlyDivisions) == 0 {
+		return false
+	}
+
+	driftOnlyDivisions := map[string]bool{}
+	for _, division := range c.ManagedDriftOnlyDivisions {
+		driftOnlyDivisions[division] = true
+	}
+
+	for division := range c.DivisionCloudCredentials {
+		if !driftOnlyDivisions[string(division)] {
+			return false
+		}
+	}
+	return true
+}
+
+// getCredentialRefreshConfig returns the configuration for the hook that refreshes division credentials.
+func (c JobConfig) getCredentialRefreshConfig() credentialrefresh.Config {
+	return credentialrefresh.Config{
+		Command:  c.CredentialRefreshCommand,
+		URL:      c.CredentialRefreshURL,
+		URLToken: c.CredentialRefreshURLToken,
+		Timeout:  c.CredentialRefreshTimeout,
+	}
+}
+
+// getRunStateStoreConfig returns the configuration for the store in which state is persisted between job runs.
+func (c JobConfig) getRunStateStoreConfig() runStateStore.Config {
+	return runStateStore.Config{
+		Backend:                 c.RunStateStoreBackend,
+		Directory:               c.RunStateStoreDirectory,
+		AzureStorageAccountName: c.RunStateStoreAzureStorageAccountName,
+		AzureStorageAccountKey:  c.RunStateStoreAzureStorageAccountKey,
+		AzureContainerName:      c.RunStateStoreAzureContainerName,
+		S3Bucket:                c.RunStateStoreS3Bucket,
+		DynamoDBTable:           c.RunStateStoreDynamoDBTable,
+		AWSRegion:               c.RunStateStoreAWSRegion,
+		Prefix:                  c.RunStateStorePrefix,
+	}
+}
+
+// getInventoryExporterConfig returns the configuration for exporting the resource inventory.
+func (c JobConfig) getInventoryExporterConfig() inventoryExporter.Config {
+	return inventoryExporter.Config{
+		OutputDirectory:            c.InventoryOutputDirectory,
+		ResultsPath:                c.ResultsOutputPath,
+		CostAllocationTags:         c.CostAllocationTags,
+		SensitiveAttributePatterns: c.SensitiveAttributePatterns,
+		WebhookURL:                 c.InventoryWebhookURL,
+		WebhookToken:               c.InventoryWebhookToken,
+		ServiceNowInstanceURL:      c.ServiceNowInstanceURL,
+		ServiceNowUsername:         c.ServiceNowUsername,
+		ServiceNowPassword:         c.ServiceNowPassword,
+		ServiceNowClassName:        c.ServiceNowClassName,
+	}
+}
+
+// getNotifierConfig returns the configuration for notifying external tooling of the results of each job run.
+func (c JobConfig) getNotifierConfig() notifier.Config {
+	return notifier.Config{
+		JobName:                    c.JobName,
+		ResultsPath:                c.ResultsOutputPath,
+		ReportPath:                 "state_of_cloud/report.md",
+		TeamsWebhookURL:            c.NotificationTeamsWebhookURL,
+		WebhookURL:                 c.NotificationWebhookURL,
+		WebhookSecret:              c.NotificationWebhookSecret,
+		SMTPHost:                   c.NotificationSMTPHost,
+		SMTPPort:                   c.NotificationSMTPPort,
+		SMTPUsername:               c.NotificationSMTPUsername,
+		SMTPPassword:               c.NotificationSMTPPassword,
+		EmailFrom:                  c.NotificationEmailFrom,
+		EmailRecipients:            c.NotificationEmailRecipients,
+		JiraURL:                    c.JiraURL,
+		JiraUsername:               c.JiraUsername,
+		JiraAPIToken:               c.JiraAPIToken,
+		JiraProjectKey:             c.JiraProjectKey,
+		JiraIssueType:              c.JiraIssueType,
+		Divisions:                  c.getDivisions(),
+		SensitiveAttributePatterns: c.SensitiveAttributePatterns,
+	}
+}
+
+// getDivisions returns the sorted divisions scanned by the job.
+func (c JobConfig) getDivisions() []string {
+	divisions := make([]string, 0, len(c.DivisionCloudCredentials))
+	for division := range c.DivisionCloudCredentials {
+		divisions = append(divisions, string(division))
+	}
+	sort.Strings(divisions)
+	return divisions
+}
+
 // getDragonDropConfig returns the configuration for the DragonDrop client.
 func (c JobConfig) getDragonDropConfig() dragonDrop.HTTPDragonDropClientConfig {
 	return dragonDrop.HTTPDragonDropClientConfig{
@@ -116,12 +809,15 @@ func (c JobConfig) getDragonDropConfig() dragonDrop.HTTPDragonDropClientConfig {
 
 func (c JobConfig) getVCSConfig() vcs.Config {
 	return vcs.Config{
-		VCSBaseBranch: c.VCSBaseBranch,
-		VCSRepo:       c.VCSRepo,
-		VCSToken:      c.VCSToken,
-		VCSUser:       c.VCSUser,
-		VCSSystem:     c.VCSSystem,
-		PullReviewers: c.PullReviewers,
+		VCSBaseBranch:      c.VCSBaseBranch,
+		VCSRepo:            c.VCSRepo,
+		VCSToken:           c.VCSToken,
+		VCSUser:            c.VCSUser,
+		VCSSystem:          c.VCSSystem,
+		PullReviewers:      c.PullReviewers,
+		ActorReviewersFile: c.ActorReviewersFile,
+		SARIFPath:          c.SecuritySARIFOutputPath,
+		UploadSARIF:        c.SecurityUploadSARIF,
 	}
 }
 
@@ -134,10 +830,51 @@ func (c JobConfig) getTerraformWorkspaceConfig() terraformWorkspace.TerraformClo
 	}
 }
 
+// getContainerBackendConfig returns the configuration for reading state from an S3, GCS or Azure Blob backend.
+func (c JobConfig) getContainerBackendConfig() terraformWorkspace.ContainerBackendConfig {
+	return terraformWorkspace.ContainerBackendConfig{
+		AWSRegion:                c.AWSRegion,
+		WorkspaceDirectories:     c.WorkspaceDirectories,
+		DivisionCloudCredentials: c.DivisionCloudCredentials,
+	}
+}
+
 func (c JobConfig) getHCLCreateConfig() hclcreate.Config {
 	return hclcreate.Config{
-		MigrationHistoryStorage: c.MigrationHistoryStorage,
-		TerraformVersion:        c.TerraformVersion,
+		MigrationHistoryStorage:    c.MigrationHistoryStorage,
+		TerraformVersion:           c.TerraformVersion,
+		VariableExtraction:         c.VariableExtraction,
+		VariableExtractionPatterns: c.VariableExtractionPatterns,
+		SensitiveAttributePatterns: c.SensitiveAttributePatterns,
+		IgnoreChanges:              c.IgnoreChanges,
+		ModuleWrapping:             c.ModuleWrapping,
+		NewWorkspaceBackend:        c.NewWorkspaceBackend,
+		ImportOutput:               c.ImportOutput,
+		GeneratedDirectory:         c.GeneratedDirectory,
+		NewResourcesLayout:         c.NewResourcesLayout,
+		DivisionRoleARNs:           c.getDivisionRoleARNs(),
+	}
+}
+
+// getDivisionRoleARNs returns the ARN of the role assumed to access each aws division, for divisions whose
+// credential assumes one.
+func (c JobConfig) getDivisionRoleARNs() map[string]string {
+	divisionRoleARNs := map[string]string{}
+	for division, credential := range c.DivisionCloudCredentials {
+		awsCredential, err := awscredentials.Parse(credential)
+		if err != nil || awsCredential.RoleARN == "" {
+			continue
+		}
+		divisionRoleARNs[string(division)] = awsCredential.RoleARN
+	}
+	return divisionRoleARNs
+}
+
+// getCodeValidationConfig returns the configuration for the validation of generated code.
+func (c JobConfig) getCodeValidationConfig() codevalidation.Config {
+	return codevalidation.Config{
+		Validators:         c.GeneratedCodeValidators,
+		GeneratedDirectory: c.GeneratedDirectory,
 	}
 }
 
@@ -147,13 +884,63 @@ func (c JobConfig) getTerraformerConfig() terraformerCli.TerraformerExecutorConf
 		Providers:                c.Providers,
 		TerraformVersion:         terraformValueObjects.Version(c.TerraformVersion),
 		CloudRegions:             c.CloudRegions,
+		PluginMirrorDirectory:    c.PluginMirrorDirectory,
+		PluginCacheDirectory:     c.PluginCacheDirectory,
+		NetworkIsolated:          c.NetworkIsolated,
+
+		AWSNativeInventoryDivisions: c.AWSNativeInventoryDivisions,
 	}
 }
 
 func (c JobConfig) getTerraformerCLIConfig() terraformerCli.Config {
 	return terraformerCli.Config{
-		ResourcesWhiteList: c.ResourcesWhiteList,
-		ResourcesBlackList: c.ResourcesBlackList,
+		ResourcesWhiteList:       c.ResourcesWhiteList,
+		ResourcesBlackList:       c.ResourcesBlackList,
+		ResourceTagInclusions:    c.ResourceTagInclusions,
+		ResourceTagExclusions:    c.ResourceTagExclusions,
+		ResourceIDExclusions:     c.ResourceIDExclusions,
+		ResourceNameExclusions:   c.ResourceNameExclusions,
+		CommandTimeout:           c.CommandTimeout,
+		CommandMaxRetries:        c.CommandMaxRetries,
+		CommandRetryBackoff:      c.CommandRetryBackoff,
+		ContinueOnPartialFailure: c.ContinueOnPartialFailure,
+		ChunkedImport:            c.ChunkedImport,
+		ImportConcurrency:        c.ImportConcurrency,
+		ResumeScans:              c.ResumeScans,
+		ResumeMaxAge:             c.ResumeMaxAge,
+		RateLimit:                c.getRateLimitConfig(),
+	}
+}
+
+func (c JobConfig) getRateLimitConfig() ratelimit.Config {
+	return ratelimit.Config{
+		RequestsPerSecond:  c.APIRequestsPerSecond,
+		Burst:              c.APIBurst,
+		MaxThrottleRetries: c.APIThrottleMaxRetries,
+		MaxBackoff:         c.APIThrottleMaxBackoff,
+	}
+}
+
+func (c JobConfig) getResourcesCalculatorConfig() resourcesCalculator.Config {
+	return resourcesCalculator.Config{
+		ComplianceBoundaries:      c.ComplianceBoundaries,
+		ManagedDriftOnlyDivisions: c.ManagedDriftOnlyDivisions,
+		ResourceIDMappings:        c.ResourceIDMappings,
+		OtherIaCResources:         c.OtherIaCResources,
+		DefaultResourceExclusions: c.DefaultResourceExclusions,
+		MinimumResourceAge:        c.MinimumResourceAge,
+		PlacementEngine:           c.PlacementEngine,
+		PlacementEmbeddings: nlpengine.EmbeddingsConfig{
+			Endpoint: c.PlacementEmbeddingsEndpoint,
+			APIKey:   c.PlacementEmbeddingsAPIKey,
+			Model:    c.PlacementEmbeddingsModel,
+		},
+		PlacementRulesFile:                     c.PlacementRulesFile,
+		PlacementTagKeys:                       c.PlacementTagKeys,
+		PlacementTagWorkspaces:                 c.PlacementTagWorkspaces,
+		PlacementConfidenceThreshold:           c.PlacementConfidenceThreshold,
+		PlacementEmbeddingsConfidenceThreshold: c.PlacementEmbeddingsConfidenceThreshold,
+		CatchAllWorkspaceDirectory:             c.CatchAllWorkspaceDirectory,
 	}
 }
 
@@ -167,11 +954,82 @@ func (c JobConfig) getCostEstimationConfig() costEstimation.CostEstimatorConfig
 	return costEstimation.CostEstimatorConfig{
 		InfracostAPIToken:        c.InfracostAPIToken,
 		DivisionCloudCredentials: c.DivisionCloudCredentials,
+		UsageFile:                c.CostUsageFile,
+		Currency:                 c.CostCurrency,
+		ExchangeRate:             c.CostExchangeRate,
+		PriceCacheDirectory:      c.PriceCacheDirectory,
+		WorkspaceMonthlyBudget:   c.WorkspaceMonthlyCostBudget,
+		PriceCacheMaxAge:         c.PriceCacheMaxAge,
+		PricingConcurrency:       c.PricingConcurrency,
+		PricingMaxRetries:        c.PricingMaxRetries,
+		PricingRetryBackoff:      c.PricingRetryBackoff,
+		OfflinePricing:           c.OfflinePricing,
+		PricingSnapshotDirectory: c.PricingSnapshotDirectory,
+
+		InfracostPricingAPIEndpoint: c.InfracostPricingAPIEndpoint,
+	}
+}
+
+// getTerraformSecurityConfig returns the configuration for the static security scan.
+func (c JobConfig) getTerraformSecurityConfig() terraformSecurity.Config {
+	return terraformSecurity.Config{
+		Scanner:               c.SecurityScanner,
+		SeverityThreshold:     c.SecuritySeverityThreshold,
+		UnknownSeverity:       c.SecurityUnknownSeverity,
+		FailOnFindings:        c.SecurityFailOnFindings,
+		SARIFOutputPath:       c.SecuritySARIFOutputPath,
+		CustomChecksDirectory: c.SecurityCustomChecksDirectory,
+		PolicyBundles:         c.SecurityPolicyBundles,
+		PolicyNamespaces:      c.SecurityPolicyNamespaces,
+		Scopes:                c.SecurityScanScopes,
+	}
+}
+
+// getPolicyEvaluatorConfig returns the configuration for evaluating the run output against Rego policies.
+func (c JobConfig) getPolicyEvaluatorConfig() policyEvaluator.Config {
+	return policyEvaluator.Config{
+		PolicyDirectories:          c.PolicyDirectories,
+		Query:                      c.PolicyQuery,
+		FailOnViolations:           c.PolicyFailOnViolations,
+		SensitiveAttributePatterns: c.SensitiveAttributePatterns,
+	}
+}
+
+func (c JobConfig) getDriftDetectorConfig() driftDetector.Config {
+	return driftDetector.Config{
+		IgnoreRules:                c.DriftIgnoreRules,
+		Concurrency:                c.DriftDetectionConcurrency,
+		ResourceIDMappings:         c.ResourceIDMappings,
+		SensitiveAttributePatterns: c.SensitiveAttributePatterns,
+		DefaultResourceExclusions:  c.DefaultResourceExclusions,
 	}
 }
 
 func (c JobConfig) getIdentifyCloudActorsConfig() identifyCloudActors.Config {
 	return identifyCloudActors.Config{
 		DivisionCloudCredentials: c.DivisionCloudCredentials,
+		RateLimit:                c.getRateLimitConfig(),
+		EventArchive: identifyCloudActors.EventArchiveConfig{
+			Backend:                      c.AWSEventArchive,
+			LookbackDays:                 c.AWSEventArchiveLookbackDays,
+			Region:                       c.AWSEventArchiveRegion,
+			CloudTrailLakeEventDataStore: c.CloudTrailLakeEventDataStore,
+			AthenaDatabase:               c.AthenaCloudTrailDatabase,
+			AthenaTable:                  c.AthenaCloudTrailTable,
+			AthenaWorkGroup:              c.AthenaWorkGroup,
+			AthenaOutputLocation:         c.AthenaOutputLocation,
+		},
+		GoogleAuditLogs: identifyCloudActors.GoogleAuditLogConfig{
+			Scope:           c.GCPAuditLogScope,
+			BigQueryTable:   c.GCPAuditLogBigQueryTable,
+			BigQueryProject: c.GCPAuditLogBigQueryProject,
+			LookbackDays:    c.GCPAuditLogLookbackDays,
+		},
+		IdentityMappingFile: c.ActorIdentityMappingFile,
+		ActorExclusions:     c.ActorExclusions,
+		EventBatching: identifyCloudActors.EventBatchingConfig{
+			Enabled:        c.ActorQueryBatching,
+			CacheDirectory: c.ActorCacheDirectory,
+		},
 	}
 }
@@ -1,35 +1,75 @@
 package main
 
 import (
+	"regexp"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/codevalidation"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/credentialrefresh"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/hclcreate"
 	costEstimation "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/cost_estimation"
 	dragonDrop "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/dragon_drop"
 	identifyCloudActors "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/identify_cloud_actors"
+	inventoryExporter "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/inventory_exporter"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/notifier"
+	policyEvaluator "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/policy_evaluator"
+	resourcesCalculator "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/resources_calculator"
+	runStateStore "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/run_state_store"
 	terraformImportMigrationGenerator "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_import_migration_generator"
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+	terraformSecurity "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_security"
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
 	terraformWorkspace "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_workspace"
 	terraformerCli "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraformer_executor/terraformer_cli"
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/vcs"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/nlpengine"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/ratelimit"
 )
 
 func validJobConfig() *JobConfig {
 	return &JobConfig{
-		IsManagedDriftOnly:         false,
-		DivisionCloudCredentials:   terraformValueObjects.DivisionCloudCredentialDecoder{ /* Valor necesario */ },
-		InfracostAPIToken:          "InfracostAPIToken",
-		APIPath:                    "https://api.dragondrop.cloud",
-		JobID:                      "JobID",
-		OrgToken:                   "OrgToken",
-		MigrationHistoryStorage:    hclcreate.MigrationHistory{ /* Valor necesario */ },
-		TerraformVersion:           "TerraformVersion",
-		StateBackend:               "StateBackend",
-		TerraformCloudOrganization: "TerraformCloudOrganization",
-		TerraformCloudToken:        "TerraformCloudToken",
-		WorkspaceDirectories:       terraformWorkspace.WorkspaceDirectoriesDecoder{ /* Valor necesario */ },
+		IsManagedDriftOnly:            false,
+		ManagedDriftOnlyDivisions:     []string{"prod"},
+		DivisionCloudCredentials:      terraformValueObjects.DivisionCloudCredentialDecoder{ /* Valor necesario */ },
+		InfracostAPIToken:             "InfracostAPIToken",
+		CostUsageFile:                 "costs/infracost-usage.yml",
+		CostCurrency:                  "EUR",
+		CostExchangeRate:              0.92,
+		WorkspaceMonthlyCostBudget:    500,
+		PriceCacheDirectory:           "/price-cache/",
+		PriceCacheMaxAge:              12 * time.Hour,
+		PricingConcurrency:            16,
+		PricingMaxRetries:             5,
+		PricingRetryBackoff:           2 * time.Second,
+		OfflinePricing:                true,
+		PricingSnapshotDirectory:      "/pricing-snapshot/",
+		CostAllocationTags:            []string{"team", "env"},
+		SecurityScanner:               "trivy",
+		SecuritySeverityThreshold:     "HIGH",
+		SecurityUnknownSeverity:       "HIGH",
+		SecurityFailOnFindings:        true,
+		SecuritySARIFOutputPath:       "security/results.sarif",
+		SecurityUploadSARIF:           true,
+		SecurityCustomChecksDirectory: "/custom-checks",
+		SecurityPolicyBundles:         []string{"/policy-bundles/org"},
+		SecurityPolicyNamespaces:      []string{"org"},
+		SecurityScanScopes:            []string{"generated", "changed"},
+		PolicyDirectories:             []string{"/policies/platform"},
+		PolicyQuery:                   "data.cloudconcierge.deny",
+		PolicyFailOnViolations:        true,
+		APIPath:                       "https://api.dragondrop.cloud",
+		JobID:                         "JobID",
+		OrgToken:                      "OrgToken",
+		MigrationHistoryStorage:       hclcreate.MigrationHistory{ /* Valor necesario */ },
+		TerraformVersion:              "TerraformVersion",
+		GeneratedCodeValidators:       []string{"terraform", "tflint"},
+		StateBackend:                  "StateBackend",
+		TerraformCloudOrganization:    "TerraformCloudOrganization",
+		TerraformCloudToken:           "TerraformCloudToken",
+		WorkspaceDirectories:          terraformWorkspace.WorkspaceDirectoriesDecoder{ /* Valor necesario */ },
 		Providers: map[terraformValueObjects.Provider]string{
 			"aws": "~>4.57.0",
 		},
@@ -41,7 +81,194 @@ func validJobConfig() *JobConfig {
 		PullReviewers:      []string{"PullReviewer1", "PullReviewer2"},
 		ResourcesWhiteList: terraformValueObjects.ResourceNameList{ /* Valor necesario */ },
 		ResourcesBlackList: terraformValueObjects.ResourceNameList{ /* Valor necesario */ },
+		ResourceTagInclusions: terraformValueObjects.ResourceTagSelectors{
+			"env": {"prod"},
+		},
+		ResourceTagExclusions: terraformValueObjects.ResourceTagSelectors{
+			"lifecycle": {"sandbox"},
+		},
+		ResourceIDExclusions:     terraformValueObjects.ResourcePatternList{regexp.MustCompile("^i-0packer")},
+		ResourceNameExclusions:   terraformValueObjects.ResourcePatternList{regexp.MustCompile("^packer-")},
+		PluginMirrorDirectory:    "/mirror/",
+		PluginCacheDirectory:     "/plugin-cache/",
+		NetworkIsolated:          true,
+		CommandTimeout:           30 * time.Minute,
+		CommandMaxRetries:        2,
+		CommandRetryBackoff:      10 * time.Second,
+		ContinueOnPartialFailure: true,
+		ChunkedImport:            true,
+		ImportConcurrency:        2,
+		ResumeScans:              true,
+		ResumeMaxAge:             24 * time.Hour,
+
+		AWSNativeInventoryDivisions:            []string{"my-aws-account"},
+		APIRequestsPerSecond:                   5,
+		APIBurst:                               2,
+		APIThrottleMaxRetries:                  5,
+		APIThrottleMaxBackoff:                  5 * time.Minute,
+		DriftIgnoreRules:                       []string{"attribute:aws_autoscaling_group.*.desired_capacity"},
+		DriftDetectionConcurrency:              4,
+		ResourceIDMappings:                     driftDetector.ResourceIDMappings{"aws_networkfirewall_rule_group": "arn"},
+		SensitiveAttributePatterns:             []string{"^user_data$"},
+		OtherIaCResources:                      "bucket",
+		DefaultResourceExclusions:              []string{"!aws_security_group"},
+		MinimumResourceAge:                     2 * time.Hour,
+		PlacementEngine:                        "embeddings",
+		PlacementEmbeddingsEndpoint:            "https://api.openai.com/v1/embeddings",
+		PlacementEmbeddingsAPIKey:              "sk-embeddings",
+		PlacementEmbeddingsModel:               "text-embedding-3-small",
+		PlacementRulesFile:                     "/placement-rules.yaml",
+		PlacementTagKeys:                       []string{"team", "service"},
+		PlacementTagWorkspaces:                 map[string]string{"team=payments": "payments-prod"},
+		PlacementConfidenceThreshold:           0.4,
+		PlacementEmbeddingsConfidenceThreshold: 0.75,
+		CatchAllWorkspaceDirectory:             "/unmanaged/",
+		IgnoreChanges:                          hclcreate.IgnoreChangesDecoder{"aws_autoscaling_group": {"desired_capacity"}},
+		ModuleWrapping:                         true,
+		ImportOutput:                           "script",
+		GeneratedDirectory:                     "terraform-import",
+		NewResourcesLayout:                     "service",
+		NewWorkspaceBackend: hclcreate.BackendTemplateDecoder{
+			Type:   "s3",
+			Config: map[string]interface{}{"bucket": "my-state", "key": "{directory}/terraform.tfstate"},
+		},
+		ComplianceBoundaries: resourcesCalculator.ComplianceBoundariesDecoder{
+			{Name: "pci", Divisions: []string{"payments"}, Directory: "/pci/", Isolated: true},
+		},
+		CredentialRefreshCommand:             "vault read -format=json aws/creds/scanner",
+		CredentialRefreshURL:                 "https://credentials.internal/refresh",
+		CredentialRefreshURLToken:            "my-token",
+		CredentialRefreshTimeout:             time.Minute,
+		RunStateStoreBackend:                 "azureblob",
+		RunStateStoreDirectory:               "/run-state/",
+		RunStateStoreAzureStorageAccountName: "myaccount",
+		RunStateStoreAzureStorageAccountKey:  "bXlrZXk=",
+		RunStateStoreAzureContainerName:      "cloud-concierge",
+		RunStateStoreS3Bucket:                "cloud-concierge-run-state",
+		RunStateStoreDynamoDBTable:           "cloud-concierge-run-state",
+		RunStateStoreAWSRegion:               "us-east-1",
+		RunStateStorePrefix:                  "my-job/",
+		InventoryOutputDirectory:             "inventory/",
+		ResultsOutputPath:                    "inventory/results.json",
+		InventoryWebhookURL:                  "https://cmdb.internal/inventory",
+		InventoryWebhookToken:                "my-token",
+		ServiceNowInstanceURL:                "https://my-instance.service-now.com",
+		ServiceNowUsername:                   "cloud-concierge",
+		ServiceNowPassword:                   "my-password",
+		ServiceNowClassName:                  "cmdb_ci_cloud_resource",
+		NotificationTeamsWebhookURL:          "https://my-org.webhook.office.com/webhookb2/my-webhook",
+		NotificationWebhookURL:               "https://tooling.internal/cloud-concierge",
+		NotificationWebhookSecret:            "my-secret",
+		NotificationSMTPHost:                 "smtp.example.com",
+		NotificationSMTPPort:                 587,
+		NotificationSMTPUsername:             "my-smtp-user",
+		NotificationSMTPPassword:             "my-smtp-password",
+		NotificationEmailFrom:                "cloud-concierge@example.com",
+		NotificationEmailRecipients:          []string{"platform@example.com", "finance@example.com"},
+		JiraURL:                              "https://my-org.atlassian.net",
+		JiraUsername:                         "platform@example.com",
+		JiraAPIToken:                         "my-jira-token",
+		JiraProjectKey:                       "OPS",
+		JiraIssueType:                        "Task",
+	}
+}
+
+func TestGetCredentialRefreshConfig(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+
+	// When
+	got := jobConfig.getCredentialRefreshConfig()
+
+	// Then
+	want := credentialrefresh.Config{
+		Command:  "vault read -format=json aws/creds/scanner",
+		URL:      "https://credentials.internal/refresh",
+		URLToken: "my-token",
+		Timeout:  time.Minute,
+	}
+
+	assert.Equal(t, want, got, "CredentialRefreshConfig should be equal")
+}
+
+func TestGetRunStateStoreConfig(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+
+	// When
+	got := jobConfig.getRunStateStoreConfig()
+
+	// Then
+	want := runStateStore.Config{
+		Backend:                 "azureblob",
+		Directory:               "/run-state/",
+		AzureStorageAccountName: "myaccount",
+		AzureStorageAccountKey:  "bXlrZXk=",
+		AzureContainerName:      "cloud-concierge",
+		S3Bucket:                "cloud-concierge-run-state",
+		DynamoDBTable:           "cloud-concierge-run-state",
+		AWSRegion:               "us-east-1",
+		Prefix:                  "my-job/",
+	}
+
+	assert.Equal(t, want, got, "RunStateStoreConfig should be equal")
+}
+
+func TestGetInventoryExporterConfig(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+
+	// When
+	got := jobConfig.getInventoryExporterConfig()
+
+	// Then
+	want := inventoryExporter.Config{
+		OutputDirectory:            "inventory/",
+		ResultsPath:                "inventory/results.json",
+		CostAllocationTags:         []string{"team", "env"},
+		SensitiveAttributePatterns: []string{"^user_data$"},
+		WebhookURL:                 "https://cmdb.internal/inventory",
+		WebhookToken:               "my-token",
+		ServiceNowInstanceURL:      "https://my-instance.service-now.com",
+		ServiceNowUsername:         "cloud-concierge",
+		ServiceNowPassword:         "my-password",
+		ServiceNowClassName:        "cmdb_ci_cloud_resource",
+	}
+
+	assert.Equal(t, want, got, "InventoryExporterConfig should be equal")
+}
+
+func TestGetNotifierConfig(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+
+	// When
+	got := jobConfig.getNotifierConfig()
+
+	// Then
+	want := notifier.Config{
+		JobName:                    jobConfig.JobName,
+		ResultsPath:                "inventory/results.json",
+		ReportPath:                 "state_of_cloud/report.md",
+		TeamsWebhookURL:            "https://my-org.webhook.office.com/webhookb2/my-webhook",
+		WebhookURL:                 "https://tooling.internal/cloud-concierge",
+		WebhookSecret:              "my-secret",
+		SMTPHost:                   "smtp.example.com",
+		SMTPPort:                   587,
+		SMTPUsername:               "my-smtp-user",
+		SMTPPassword:               "my-smtp-password",
+		EmailFrom:                  "cloud-concierge@example.com",
+		EmailRecipients:            []string{"platform@example.com", "finance@example.com"},
+		JiraURL:                    "https://my-org.atlassian.net",
+		JiraUsername:               "platform@example.com",
+		JiraAPIToken:               "my-jira-token",
+		JiraProjectKey:             "OPS",
+		JiraIssueType:              "Task",
+		Divisions:                  []string{},
+		SensitiveAttributePatterns: []string{"^user_data$"},
 	}
+
+	assert.Equal(t, want, got, "NotifierConfig should be equal")
 }
 
 func TestGetDragonDropConfig(t *testing.T) {
@@ -68,18 +295,22 @@ func TestGetDragonDropConfig(t *testing.T) {
 func TestGetVCSConfig(t *testing.T) {
 	// Given
 	jobConfig := validJobConfig()
+	jobConfig.ActorReviewersFile = "/reviewers.yaml"
 
 	// When
 	got := jobConfig.getVCSConfig()
 
 	// Then
 	want := vcs.Config{
-		VCSBaseBranch: jobConfig.VCSBaseBranch,
-		VCSRepo:       jobConfig.VCSRepo,
-		VCSToken:      jobConfig.VCSToken,
-		VCSUser:       jobConfig.VCSUser,
-		VCSSystem:     jobConfig.VCSSystem,
-		PullReviewers: jobConfig.PullReviewers,
+		VCSBaseBranch:      jobConfig.VCSBaseBranch,
+		VCSRepo:            jobConfig.VCSRepo,
+		VCSToken:           jobConfig.VCSToken,
+		VCSUser:            jobConfig.VCSUser,
+		VCSSystem:          jobConfig.VCSSystem,
+		PullReviewers:      jobConfig.PullReviewers,
+		ActorReviewersFile: "/reviewers.yaml",
+		SARIFPath:          "security/results.sarif",
+		UploadSARIF:        true,
 	}
 
 	assert.Equal(t, want, got, "VCS Config should be equal")
@@ -103,6 +334,24 @@ func TestGetTerraformWorkspaceConfig(t *testing.T) {
 	assert.Equal(t, want, got, "TerraformWorkspaceConfig should be equal")
 }
 
+func TestGetContainerBackendConfig(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+	jobConfig.AWSRegion = "us-east-1"
+
+	// When
+	got := jobConfig.getContainerBackendConfig()
+
+	// Then
+	want := terraformWorkspace.ContainerBackendConfig{
+		AWSRegion:                "us-east-1",
+		WorkspaceDirectories:     jobConfig.WorkspaceDirectories,
+		DivisionCloudCredentials: jobConfig.DivisionCloudCredentials,
+	}
+
+	assert.Equal(t, want, got, "ContainerBackendConfig should be equal")
+}
+
 func TestGetHCLCreateConfig(t *testing.T) {
 	// Given
 	jobConfig := validJobConfig()
@@ -112,13 +361,34 @@ func TestGetHCLCreateConfig(t *testing.T) {
 
 	// Then
 	want := hclcreate.Config{
-		MigrationHistoryStorage: jobConfig.MigrationHistoryStorage,
-		TerraformVersion:        jobConfig.TerraformVersion,
+		MigrationHistoryStorage:    jobConfig.MigrationHistoryStorage,
+		TerraformVersion:           jobConfig.TerraformVersion,
+		VariableExtraction:         jobConfig.VariableExtraction,
+		VariableExtractionPatterns: jobConfig.VariableExtractionPatterns,
+		SensitiveAttributePatterns: jobConfig.SensitiveAttributePatterns,
+		IgnoreChanges:              jobConfig.IgnoreChanges,
+		ModuleWrapping:             jobConfig.ModuleWrapping,
+		NewWorkspaceBackend:        jobConfig.NewWorkspaceBackend,
+		ImportOutput:               jobConfig.ImportOutput,
+		GeneratedDirectory:         jobConfig.GeneratedDirectory,
+		NewResourcesLayout:         jobConfig.NewResourcesLayout,
+		DivisionRoleARNs:           map[string]string{},
 	}
 
 	assert.Equal(t, want, got, "HCLCreateConfig should be equal")
 }
 
+func TestGetCodeValidationConfig(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+
+	// When
+	got := jobConfig.getCodeValidationConfig()
+
+	// Then
+	assert.Equal(t, codevalidation.Config{Validators: []string{"terraform", "tflint"}, GeneratedDirectory: "terraform-import"}, got)
+}
+
 func TestGetTerraformerConfig(t *testing.T) {
 	// Given
 	jobConfig := validJobConfig()
@@ -132,6 +402,11 @@ func TestGetTerraformerConfig(t *testing.T) {
 		Providers:                jobConfig.Providers,
 		TerraformVersion:         terraformValueObjects.Version(jobConfig.TerraformVersion),
 		CloudRegions:             jobConfig.CloudRegions,
+		PluginMirrorDirectory:    jobConfig.PluginMirrorDirectory,
+		PluginCacheDirectory:     jobConfig.PluginCacheDirectory,
+		NetworkIsolated:          jobConfig.NetworkIsolated,
+
+		AWSNativeInventoryDivisions: jobConfig.AWSNativeInventoryDivisions,
 	}
 
 	assert.Equal(t, want, got, "TerraformerExecutorConfig should be equal")
@@ -146,13 +421,86 @@ func TestGetTerraformerCLIConfig(t *testing.T) {
 
 	// Then
 	want := terraformerCli.Config{
-		ResourcesWhiteList: jobConfig.ResourcesWhiteList,
-		ResourcesBlackList: jobConfig.ResourcesBlackList,
+		ResourcesWhiteList:       jobConfig.ResourcesWhiteList,
+		ResourcesBlackList:       jobConfig.ResourcesBlackList,
+		ResourceTagInclusions:    jobConfig.ResourceTagInclusions,
+		ResourceTagExclusions:    jobConfig.ResourceTagExclusions,
+		ResourceIDExclusions:     jobConfig.ResourceIDExclusions,
+		ResourceNameExclusions:   jobConfig.ResourceNameExclusions,
+		CommandTimeout:           jobConfig.CommandTimeout,
+		CommandMaxRetries:        jobConfig.CommandMaxRetries,
+		CommandRetryBackoff:      jobConfig.CommandRetryBackoff,
+		ContinueOnPartialFailure: jobConfig.ContinueOnPartialFailure,
+		ChunkedImport:            jobConfig.ChunkedImport,
+		ImportConcurrency:        jobConfig.ImportConcurrency,
+		ResumeScans:              jobConfig.ResumeScans,
+		ResumeMaxAge:             jobConfig.ResumeMaxAge,
+		RateLimit: ratelimit.Config{
+			RequestsPerSecond:  5,
+			Burst:              2,
+			MaxThrottleRetries: 5,
+			MaxBackoff:         5 * time.Minute,
+		},
 	}
 
 	assert.Equal(t, want, got, "TerraformerCLIConfig should be equal")
 }
 
+func TestGetResourcesCalculatorConfig(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+
+	// When
+	got := jobConfig.getResourcesCalculatorConfig()
+
+	// Then
+	want := resourcesCalculator.Config{
+		ComplianceBoundaries:      jobConfig.ComplianceBoundaries,
+		ManagedDriftOnlyDivisions: jobConfig.ManagedDriftOnlyDivisions,
+		ResourceIDMappings:        driftDetector.ResourceIDMappings{"aws_networkfirewall_rule_group": "arn"},
+		OtherIaCResources:         "bucket",
+		DefaultResourceExclusions: []string{"!aws_security_group"},
+		MinimumResourceAge:        2 * time.Hour,
+		PlacementEngine:           "embeddings",
+		PlacementEmbeddings: nlpengine.EmbeddingsConfig{
+			Endpoint: "https://api.openai.com/v1/embeddings",
+			APIKey:   "sk-embeddings",
+			Model:    "text-embedding-3-small",
+		},
+		PlacementRulesFile:                     "/placement-rules.yaml",
+		PlacementTagKeys:                       []string{"team", "service"},
+		PlacementTagWorkspaces:                 map[string]string{"team=payments": "payments-prod"},
+		PlacementConfidenceThreshold:           0.4,
+		PlacementEmbeddingsConfidenceThreshold: 0.75,
+		CatchAllWorkspaceDirectory:             "/unmanaged/",
+	}
+
+	assert.Equal(t, want, got, "ResourcesCalculatorConfig should be equal")
+}
+
+func TestIsManagedDriftOnly(t *testing.T) {
+	// Given
+	jobConfig := JobConfig{
+		DivisionCloudCredentials: terraformValueObjects.DivisionCloudCredentialDecoder{
+			"prod":    "{}",
+			"sandbox": "{}",
+		},
+	}
+
+	// Then
+	assert.False(t, jobConfig.isManagedDriftOnly())
+
+	jobConfig.ManagedDriftOnlyDivisions = []string{"prod"}
+	assert.False(t, jobConfig.isManagedDriftOnly())
+
+	jobConfig.ManagedDriftOnlyDivisions = []string{"prod", "sandbox"}
+	assert.True(t, jobConfig.isManagedDriftOnly())
+
+	jobConfig.ManagedDriftOnlyDivisions = []string{}
+	jobConfig.IsManagedDriftOnly = true
+	assert.True(t, jobConfig.isManagedDriftOnly())
+}
+
 func TestGetTerraformImportMigrationGeneratorConfig(t *testing.T) {
 	// Given
 	jobConfig := validJobConfig()
@@ -179,14 +527,94 @@ func TestGetCostEstimationConfig(t *testing.T) {
 	want := costEstimation.CostEstimatorConfig{
 		InfracostAPIToken:        jobConfig.InfracostAPIToken,
 		DivisionCloudCredentials: jobConfig.DivisionCloudCredentials,
+		UsageFile:                jobConfig.CostUsageFile,
+		Currency:                 jobConfig.CostCurrency,
+		ExchangeRate:             jobConfig.CostExchangeRate,
+		PriceCacheDirectory:      jobConfig.PriceCacheDirectory,
+		WorkspaceMonthlyBudget:   jobConfig.WorkspaceMonthlyCostBudget,
+		PriceCacheMaxAge:         jobConfig.PriceCacheMaxAge,
+		PricingConcurrency:       16,
+		PricingMaxRetries:        5,
+		PricingRetryBackoff:      2 * time.Second,
+		OfflinePricing:           jobConfig.OfflinePricing,
+		PricingSnapshotDirectory: jobConfig.PricingSnapshotDirectory,
 	}
 
 	assert.Equal(t, want, got, "CostEstimationConfig should be equal")
 }
 
+func TestGetPolicyEvaluatorConfig(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+
+	// When
+	got := jobConfig.getPolicyEvaluatorConfig()
+
+	// Then
+	assert.Equal(t, policyEvaluator.Config{
+		PolicyDirectories:          []string{"/policies/platform"},
+		Query:                      "data.cloudconcierge.deny",
+		FailOnViolations:           true,
+		SensitiveAttributePatterns: []string{"^user_data$"},
+	}, got)
+}
+
+func TestGetTerraformSecurityConfig(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+
+	// When
+	got := jobConfig.getTerraformSecurityConfig()
+
+	// Then
+	assert.Equal(t, terraformSecurity.Config{
+		Scanner:               "trivy",
+		SeverityThreshold:     "HIGH",
+		UnknownSeverity:       "HIGH",
+		FailOnFindings:        true,
+		SARIFOutputPath:       "security/results.sarif",
+		CustomChecksDirectory: "/custom-checks",
+		PolicyBundles:         []string{"/policy-bundles/org"},
+		PolicyNamespaces:      []string{"org"},
+		Scopes:                []string{"generated", "changed"},
+	}, got)
+}
+
+func TestGetDriftDetectorConfig(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+
+	// When
+	got := jobConfig.getDriftDetectorConfig()
+
+	// Then
+	want := driftDetector.Config{
+		IgnoreRules:                []string{"attribute:aws_autoscaling_group.*.desired_capacity"},
+		Concurrency:                4,
+		ResourceIDMappings:         driftDetector.ResourceIDMappings{"aws_networkfirewall_rule_group": "arn"},
+		SensitiveAttributePatterns: []string{"^user_data$"},
+		DefaultResourceExclusions:  []string{"!aws_security_group"},
+	}
+
+	assert.Equal(t, want, got, "DriftDetectorConfig should be equal")
+}
+
 func TestGetIdentifyCloudActorsConfig(t *testing.T) {
 	// Given
 	jobConfig := validJobConfig()
+	jobConfig.AWSEventArchive = "athena"
+	jobConfig.AWSEventArchiveLookbackDays = 2555
+	jobConfig.AthenaCloudTrailDatabase = "default"
+	jobConfig.AthenaCloudTrailTable = "cloudtrail_logs"
+	jobConfig.AthenaWorkGroup = "primary"
+	jobConfig.AthenaOutputLocation = "s3://athena-results/"
+	jobConfig.ActorIdentityMappingFile = "/identities.yaml"
+	jobConfig.ActorQueryBatching = true
+	jobConfig.ActorCacheDirectory = "/cache/actors/"
+	jobConfig.ActorExclusions = []string{"arn:aws:sts::*:assumed-role/github-actions-deployer/*"}
+	jobConfig.GCPAuditLogBigQueryTable = "central-logging.org_audit_logs.cloudaudit_googleapis_com_activity_*"
+	jobConfig.GCPAuditLogBigQueryProject = "billing-project"
+	jobConfig.GCPAuditLogLookbackDays = 400
 
 	// When
 	got := jobConfig.getIdentifyCloudActorsConfig()
@@ -194,7 +622,158 @@ func TestGetIdentifyCloudActorsConfig(t *testing.T) {
 	// Then
 	want := identifyCloudActors.Config{
 		DivisionCloudCredentials: jobConfig.DivisionCloudCredentials,
+		RateLimit:                jobConfig.getRateLimitConfig(),
+		EventArchive: identifyCloudActors.EventArchiveConfig{
+			Backend:              "athena",
+			LookbackDays:         2555,
+			AthenaDatabase:       "default",
+			AthenaTable:          "cloudtrail_logs",
+			AthenaWorkGroup:      "primary",
+			AthenaOutputLocation: "s3://athena-results/",
+		},
+		GoogleAuditLogs: identifyCloudActors.GoogleAuditLogConfig{
+			BigQueryTable:   "central-logging.org_audit_logs.cloudaudit_googleapis_com_activity_*",
+			BigQueryProject: "billing-project",
+			LookbackDays:    400,
+		},
+		IdentityMappingFile: "/identities.yaml",
+		ActorExclusions:     []string{"arn:aws:sts::*:assumed-role/github-actions-deployer/*"},
+		EventBatching: identifyCloudActors.EventBatchingConfig{
+			Enabled:        true,
+			CacheDirectory: "/cache/actors/",
+		},
 	}
 
 	assert.Equal(t, want, got, "IdentifyCloudActorsConfig should be equal")
 }
+
+func TestValidateJobConfig_AWSOrganizationsManagementDivision(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+	jobConfig.DivisionCloudCredentials = terraformValueObjects.DivisionCloudCredentialDecoder{"prod": "{}"}
+	jobConfig.AWSOrganizationsManagementDivision = "missing-division"
+
+	// When
+	err := validateJobConfig(*jobConfig)
+
+	// Then
+	assert.Error(t, err)
+
+	// When
+	jobConfig.AWSOrganizationsManagementDivision = "prod"
+	err = validateJobConfig(*jobConfig)
+
+	// Then
+	assert.NoError(t, err)
+}
+
+func TestValidateJobConfig_GCPProjects(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+	jobConfig.DivisionCloudCredentials = terraformValueObjects.DivisionCloudCredentialDecoder{"prod": "{}"}
+	jobConfig.GCPProjectsDivision = "prod"
+
+	// When
+	err := validateJobConfig(*jobConfig)
+
+	// Then
+	assert.Error(t, err)
+
+	// When
+	jobConfig.GCPProjectsParent = "organizations/123"
+	err = validateJobConfig(*jobConfig)
+
+	// Then
+	assert.NoError(t, err)
+
+	// When
+	jobConfig.GCPProjectsDivision = "missing-division"
+	err = validateJobConfig(*jobConfig)
+
+	// Then
+	assert.Error(t, err)
+}
+
+func TestValidateJobConfig_AzureManagementGroup(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+	jobConfig.DivisionCloudCredentials = terraformValueObjects.DivisionCloudCredentialDecoder{"prod": "{}"}
+	jobConfig.AzureManagementGroupDivision = "prod"
+
+	// When
+	err := validateJobConfig(*jobConfig)
+
+	// Then
+	assert.Error(t, err)
+
+	// When
+	jobConfig.AzureManagementGroupID = "my-management-group"
+	err = validateJobConfig(*jobConfig)
+
+	// Then
+	assert.NoError(t, err)
+}
+
+func TestValidateJobConfig_Schedule(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+	jobConfig.DivisionCloudCredentials = terraformValueObjects.DivisionCloudCredentialDecoder{"prod": "{}"}
+	jobConfig.Schedule = "0 6 * * 1-5"
+
+	// When
+	err := validateJobConfig(*jobConfig)
+
+	// Then
+	assert.NoError(t, err)
+
+	// When
+	jobConfig.ScheduleTimeZone = "Not/AZone"
+	err = validateJobConfig(*jobConfig)
+
+	// Then
+	assert.Error(t, err)
+
+	// When
+	jobConfig.ScheduleTimeZone = "UTC"
+	jobConfig.Schedule = "0 25 * * *"
+	err = validateJobConfig(*jobConfig)
+
+	// Then
+	assert.Error(t, err)
+}
+
+func TestValidateJobConfig_TriggerToken(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+	jobConfig.DivisionCloudCredentials = terraformValueObjects.DivisionCloudCredentialDecoder{"prod": "{}"}
+	jobConfig.TriggerListenAddress = ":8080"
+
+	// When
+	err := validateJobConfig(*jobConfig)
+
+	// Then
+	assert.Error(t, err)
+
+	// When
+	jobConfig.TriggerToken = "secret"
+	err = validateJobConfig(*jobConfig)
+
+	// Then
+	assert.NoError(t, err)
+}
+
+func TestGetDivisionRoleARNs(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+	jobConfig.DivisionCloudCredentials = terraformValueObjects.DivisionCloudCredentialDecoder{
+		"prod":    `{"roleARN": "arn:aws:iam::123456789012:role/deployer"}`,
+		"dev":     `{"awsAccessKeyID": "key", "awsSecretAccessKey": "secret"}`,
+		"project": `{"type": "service_account"}`,
+	}
+
+	// When
+	got := jobConfig.getDivisionRoleARNs()
+
+	// Then
+	assert.Equal(t, map[string]string{"prod": "arn:aws:iam::123456789012:role/deployer"}, got)
+}
@@ -8,48 +8,93 @@ import (
 	"time"
 
 	log "github.com/sirupsen/logrus"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/joberrors"
 )
 
+// runSummaryPath is the path to which the machine-readable outcome of the run is written.
+const runSummaryPath = "run-summary.json"
+
+// runTimeout is the maximum duration of a single job run.
+const runTimeout = 15 * time.Minute
+
 func main() {
 	log.Info("Entrypoint on go binary")
-	ctx := context.Background()
-	ctx, cancel := context.WithTimeout(ctx, 15*time.Minute)
-	defer cancel()
+	startedAt := time.Now()
 
-	err := RemoveSubDirectories()
+	env := os.Getenv("CLOUDCONCIERGE_EXECUTION_ENVIRONMENT")
+	jobConfig, err := loadJobConfig(nil)
 	if err != nil {
-		log.Errorf("Error removing sub directories: %s", err.Error())
-		os.Exit(1)
+		log.Errorf("Error creating job config: %s", err.Error())
+		exitWithRunSummary(startedAt, joberrors.Wrap("initialize_job", "cannot create job config", joberrors.CodeConfiguration, err))
 	}
 
-	env := os.Getenv("CLOUDCONCIERGE_EXECUTION_ENVIRONMENT")
-	job, err := InitializeJobDependencies(ctx, env)
+	if jobConfig.Schedule != "" || jobConfig.TriggerListenAddress != "" {
+		err = runDaemon(env, jobConfig)
+		if err != nil {
+			log.Errorf("Error running scheduled jobs: %s", err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
+	exitWithRunSummary(startedAt, runJob(context.Background(), env, nil))
+}
+
+// runJob initializes, authorizes and runs a single job with the passed per-run config overrides, informing of its
+// failure.
+func runJob(ctx context.Context, env string, overrides map[string]string) error {
+	ctx, cancel := context.WithTimeout(ctx, runTimeout)
+	defer cancel()
+
+	job, err := InitializeJobDependenciesWithOverrides(ctx, env, overrides)
 	if err != nil {
 		log.Errorf("Error creating job: %s", err.Error())
-		os.Exit(1)
+		return err
 	}
 
 	err = job.Authorize(ctx)
 	if err != nil {
 		log.Errorf("Error authorizing job: %s", err.Error())
-		os.Exit(1)
+		job.InformFailure(ctx, err)
+		return err
 	}
 
 	err = job.Run(ctx)
 	if err != nil {
 		log.Errorf("Error running job: %s", err.Error())
+		job.InformFailure(ctx, err)
+		return err
+	}
+	return nil
+}
+
+// writeRunSummary writes the machine-readable outcome of the run to the current directory.
+func writeRunSummary(startedAt time.Time, err error) {
+	summaryErr := joberrors.NewRunSummary(startedAt, err).Write(runSummaryPath)
+	if summaryErr != nil {
+		log.Errorf("Error writing run summary: %s", summaryErr.Error())
+	}
+}
+
+// exitWithRunSummary writes the run summary, and exits with a non-zero status code if err is not nil.
+func exitWithRunSummary(startedAt time.Time, err error) {
+	writeRunSummary(startedAt, err)
+
+	if err != nil {
+		log.Errorf("Job failed with error code %s", joberrors.CodeOf(err))
 		os.Exit(1)
 	}
 
 	log.Info("Done executing go binary")
 }
 
-// RemoveSubDirectories removes all subdirectories within the container's volume prior to container startup
-func RemoveSubDirectories() error {
-	if _, err := os.Stat("/main/"); err == nil {
-		d, err := os.Open("/main/")
+// RemoveSubDirectories removes all subdirectories and files within directory, if it exists, prior to the job running.
+func RemoveSubDirectories(directory string) error {
+	if _, err := os.Stat(directory); err == nil {
+		d, err := os.Open(directory)
 		if err != nil {
-			return fmt.Errorf("[os.Open('/main/)]%v", err)
+			return fmt.Errorf("[os.Open(%v)]%v", directory, err)
 		}
 		defer d.Close()
 
@@ -60,9 +105,9 @@ func RemoveSubDirectories() error {
 		fmt.Printf("All sub directories identified:\n%v\n", names)
 
 		for _, name := range names {
-			err = os.RemoveAll(filepath.Join("/main/", name))
+			err = os.RemoveAll(filepath.Join(directory, name))
 			if err != nil {
-				return fmt.Errorf("[os.RemoveAll(/main/%v)]%v", name, err)
+				return fmt.Errorf("[os.RemoveAll(%v)]%v", filepath.Join(directory, name), err)
 			}
 		}
 	}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// triggerPath is the path of the endpoint through which a daemon job run is triggered.
+const triggerPath = "/v1/runs"
+
+// maxTriggerBodyBytes is the maximum size of the config overrides posted to the trigger endpoint.
+const maxTriggerBodyBytes = 1 << 20
+
+// overridableConfigKeys are the config variables which a triggered run may override: the filters of scanned and
+// reported resources, the divisions scanned and the thresholds of the job. Credentials, commands, tokens and output
+// destinations are never overridable, so that the trigger token grants no more than the choice of what is scanned.
+var overridableConfigKeys = []string{
+	"ISMANAGEDDRIFTONLY",
+	"MANAGEDDRIFTONLYDIVISIONS",
+	"AWSORGANIZATIONSEXCLUDEDACCOUNTS",
+	"GCPEXCLUDEDPROJECTS",
+	"AZUREEXCLUDEDSUBSCRIPTIONS",
+	"RESOURCESWHITELIST",
+	"RESOURCESBLACKLIST",
+	"CLOUDREGIONS",
+	"RESOURCETAGINCLUSIONS",
+	"RESOURCETAGEXCLUSIONS",
+	"RESOURCEIDEXCLUSIONS",
+	"RESOURCENAMEEXCLUSIONS",
+	"DRIFTIGNORERULES",
+	"DEFAULTRESOURCEEXCLUSIONS",
+	"MINIMUMRESOURCEAGE",
+	"MAXRESOURCESPERPULLREQUEST",
+	"PLACEMENTCONFIDENCETHRESHOLD",
+	"PLACEMENTEMBEDDINGSCONFIDENCETHRESHOLD",
+	"SECURITYSEVERITYTHRESHOLD",
+}
+
+// triggerHandler triggers a daemon job run for each authorized POST request, whose optional json object body holds
+// the per-run config overrides, e.g. {"MINIMUMRESOURCEAGE": "48h"}. Keys may omit the CLOUDCONCIERGE_ prefix, and
+// must be within overridableConfigKeys. Only a single run is queued; a request while one is already queued is
+// rejected.
+type triggerHandler struct {
+	// token is the bearer token which requests must present.
+	token string
+
+	// triggers receives the config overrides of each triggered run.
+	triggers chan<- map[string]string
+}
+
+// newTriggerServer returns the server of the trigger endpoint, listening on address.
+func newTriggerServer(address string, token string, triggers chan<- map[string]string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle(triggerPath, &triggerHandler{token: token, triggers: triggers})
+	return &http.Server{Addr: address, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+}
+
+// serveTriggers serves the trigger endpoint on address until the returned function closes it, calling stop if the
+// endpoint cannot be served.
+func serveTriggers(address string, token string, triggers chan<- map[string]string, stop context.CancelFunc) func() {
+	server := newTriggerServer(address, token, triggers)
+	go func() {
+		log.Infof("Accepting triggered job runs on %v%v", address, triggerPath)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Errorf("Error serving the trigger endpoint: %s", err.Error())
+			stop()
+		}
+	}()
+	return func() { _ = server.Close() }
+}
+
+// validateTriggerOverrides returns an error naming the passed config overrides which a triggered run may not
+// override.
+func validateTriggerOverrides(overrides map[string]string) error {
+	allowed := map[string]bool{}
+	for _, key := range overridableConfigKeys {
+		allowed[configVariableName(key)] = true
+	}
+
+	rejected := []string{}
+	for key := range overrides {
+		if !allowed[configVariableName(key)] {
+			rejected = append(rejected, key)
+		}
+	}
+	if len(rejected) > 0 {
+		sort.Strings(rejected)
+		return fmt.Errorf("config %v may not be overridden by a triggered run", rejected)
+	}
+	return nil
+}
+
+// ServeHTTP queues a job run with the posted config overrides.
+func (h *triggerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+h.token)) != 1 {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	overrides := map[string]string{}
+	if r.ContentLength != 0 {
+		err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxTriggerBodyBytes)).Decode(&overrides)
+		if err != nil {
+			http.Error(w, "body must be a json object of string config values", http.StatusBadRequest)
+			return
+		}
+	}
+
+	err := validateTriggerOverrides(overrides)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	select {
+	case h.triggers <- overrides:
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		http.Error(w, "a job run is already queued", http.StatusConflict)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTriggerHandler(t *testing.T) {
+	// Given
+	triggers := make(chan map[string]string, 1)
+	handler := &triggerHandler{token: "secret", triggers: triggers}
+	request := func(method string, token string, body string) int {
+		r := httptest.NewRequest(method, triggerPath, strings.NewReader(body))
+		r.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	// When
+	unauthorized := request(http.MethodPost, "wrong", "")
+	wrongMethod := request(http.MethodGet, "secret", "")
+	badBody := request(http.MethodPost, "secret", `{"MINIMUMRESOURCEAGE": 48}`)
+	notOverridable := request(http.MethodPost, "secret", `{"CREDENTIALREFRESHCOMMAND": "id", "MINIMUMRESOURCEAGE": "48h"}`)
+	accepted := request(http.MethodPost, "secret", `{"MINIMUMRESOURCEAGE": "48h"}`)
+	conflict := request(http.MethodPost, "secret", "")
+
+	// Then
+	assert.Equal(t, http.StatusUnauthorized, unauthorized)
+	assert.Equal(t, http.StatusMethodNotAllowed, wrongMethod)
+	assert.Equal(t, http.StatusBadRequest, badBody)
+	assert.Equal(t, http.StatusBadRequest, notOverridable)
+	assert.Equal(t, http.StatusAccepted, accepted)
+	assert.Equal(t, http.StatusConflict, conflict)
+	assert.Equal(t, map[string]string{"MINIMUMRESOURCEAGE": "48h"}, <-triggers)
+}
+
+func TestValidateTriggerOverrides(t *testing.T) {
+	// Then
+	assert.NoError(t, validateTriggerOverrides(map[string]string{"resourceswhitelist": "aws_s3_bucket", "CLOUDCONCIERGE_CLOUDREGIONS": "us-east-1"}))
+	assert.NoError(t, validateTriggerOverrides(map[string]string{}))
+
+	err := validateTriggerOverrides(map[string]string{"VCSTOKEN": "x", "DIVISIONCLOUDCREDENTIALS": "{}", "CLOUDREGIONS": "us-east-1"})
+	assert.EqualError(t, err, "config [DIVISIONCLOUDCREDENTIALS VCSTOKEN] may not be overridden by a triggered run")
+}
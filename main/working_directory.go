@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/clonedrepo"
+)
+
+// legacyVolumeDirectory is the container volume emptied before the job runs when no working directory is configured.
+const legacyVolumeDirectory = "/main/"
+
+// jobArtifactPaths are the directories and files, relative to the working directory, which the job writes, and which
+// are removed from a shared working directory before the job runs.
+var jobArtifactPaths = []string{
+	clonedrepo.Directory,
+	"mappings",
+	"current_cloud",
+	"state_of_cloud",
+	"state_files",
+	"inventory",
+	"security",
+	"credentials",
+	runSummaryPath,
+}
+
+// enterWorkingDirectory changes into the directory within which the job clones the repository and writes its
+// artifacts, such as mappings/, current_cloud/ and state_of_cloud/, returning its absolute path. Leftovers of a
+// previous run are removed from the directory beforehand: the whole of a unique run directory, but only the job's
+// own artifacts within a shared working directory, which may hold unrelated data.
+func enterWorkingDirectory(config JobConfig) (string, error) {
+	if config.WorkingDirectory == "" {
+		err := RemoveSubDirectories(legacyVolumeDirectory)
+		if err != nil {
+			return "", fmt.Errorf("[enter_working_directory]%w", err)
+		}
+
+		directory, err := os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("[enter_working_directory][os.Getwd]%w", err)
+		}
+		return directory, nil
+	}
+
+	directory, err := filepath.Abs(config.WorkingDirectory)
+	if err != nil {
+		return "", fmt.Errorf("[enter_working_directory][filepath.Abs %v]%w", config.WorkingDirectory, err)
+	}
+	if config.UniqueRunDirectories {
+		directory = filepath.Join(directory, "runs", runDirectoryName(config))
+	}
+
+	err = os.MkdirAll(directory, 0755)
+	if err != nil {
+		return "", fmt.Errorf("[enter_working_directory][os.MkdirAll %v]%w", directory, err)
+	}
+
+	if config.UniqueRunDirectories {
+		err = RemoveSubDirectories(directory)
+	} else {
+		err = removeJobArtifacts(directory)
+	}
+	if err != nil {
+		return "", fmt.Errorf("[enter_working_directory]%w", err)
+	}
+
+	err = os.Chdir(directory)
+	if err != nil {
+		return "", fmt.Errorf("[enter_working_directory][os.Chdir %v]%w", directory, err)
+	}
+	return directory, nil
+}
+
+// removeJobArtifacts removes the artifacts of a previous job run from directory.
+func removeJobArtifacts(directory string) error {
+	for _, path := range jobArtifactPaths {
+		err := os.RemoveAll(filepath.Join(directory, path))
+		if err != nil {
+			return fmt.Errorf("[remove_job_artifacts][os.RemoveAll %v]%w", path, err)
+		}
+	}
+	return nil
+}
+
+// runDirectoryName returns the name of the directory unique to the current job run, within which it works when
+// unique run directories are enabled.
+func runDirectoryName(config JobConfig) string {
+	if config.JobID != "empty" && config.JobID != "" {
+		return config.JobID
+	}
+	return fmt.Sprintf("%v-%d", time.Now().UTC().Format("20060102T150405"), os.Getpid())
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnterWorkingDirectory(t *testing.T) {
+	// Given
+	wd, _ := os.Getwd()
+	defer func() { _ = os.Chdir(wd) }()
+
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "mappings"), 0700))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "unrelated"), 0700))
+
+	// When
+	sharedDirectory, sharedErr := enterWorkingDirectory(JobConfig{WorkingDirectory: root})
+	runDirectory, runErr := enterWorkingDirectory(JobConfig{WorkingDirectory: root, UniqueRunDirectories: true, JobID: "job-2"})
+
+	// Then
+	require.NoError(t, sharedErr)
+	assert.Equal(t, root, sharedDirectory)
+	_, err = os.Stat(filepath.Join(root, "mappings"))
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(root, "unrelated"))
+	assert.NoError(t, err)
+
+	require.NoError(t, runErr)
+	assert.Equal(t, filepath.Join(root, "runs", "job-2"), runDirectory)
+	currentDirectory, _ := os.Getwd()
+	assert.Equal(t, runDirectory, currentDirectory)
+}
+
+func TestScheduledRunDirectory(t *testing.T) {
+	// Given
+	location := time.FixedZone("UTC-5", -5*60*60)
+	scheduledAt := time.Date(2023, time.March, 15, 6, 0, 0, 0, location)
+
+	// When
+	directory := scheduledRunDirectory("/data", scheduledAt)
+
+	// Then
+	assert.Equal(t, filepath.Join("/data", "runs", "20230315T110000"), directory)
+}
+
+func TestPruneScheduledRunDirectories(t *testing.T) {
+	// Given
+	root := t.TempDir()
+	names := []string{"20230313T060000", "20230314T060000", "20230315T060000", "job-2"}
+	for _, name := range names {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, "runs", name), 0700))
+	}
+
+	// When
+	err := pruneScheduledRunDirectories(root, 2)
+
+	// Then
+	require.NoError(t, err)
+	entries, err := os.ReadDir(filepath.Join(root, "runs"))
+	require.NoError(t, err)
+	remaining := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		remaining = append(remaining, entry.Name())
+	}
+	assert.Equal(t, []string{"20230314T060000", "20230315T060000", "job-2"}, remaining)
+}